@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: apikeys/v1/apikeys.proto
+
+package apikeysv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// APIKeyServiceName is the fully-qualified name of the APIKeyService service.
+	APIKeyServiceName = "apikeys.v1.APIKeyService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// APIKeyServiceCreateAPIKeyProcedure is the fully-qualified name of the APIKeyService's
+	// CreateAPIKey RPC.
+	APIKeyServiceCreateAPIKeyProcedure = "/apikeys.v1.APIKeyService/CreateAPIKey"
+	// APIKeyServiceRevokeAPIKeyProcedure is the fully-qualified name of the APIKeyService's
+	// RevokeAPIKey RPC.
+	APIKeyServiceRevokeAPIKeyProcedure = "/apikeys.v1.APIKeyService/RevokeAPIKey"
+	// APIKeyServiceListAPIKeysProcedure is the fully-qualified name of the APIKeyService's ListAPIKeys
+	// RPC.
+	APIKeyServiceListAPIKeysProcedure = "/apikeys.v1.APIKeyService/ListAPIKeys"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	aPIKeyServiceServiceDescriptor            = v1.File_apikeys_v1_apikeys_proto.Services().ByName("APIKeyService")
+	aPIKeyServiceCreateAPIKeyMethodDescriptor = aPIKeyServiceServiceDescriptor.Methods().ByName("CreateAPIKey")
+	aPIKeyServiceRevokeAPIKeyMethodDescriptor = aPIKeyServiceServiceDescriptor.Methods().ByName("RevokeAPIKey")
+	aPIKeyServiceListAPIKeysMethodDescriptor  = aPIKeyServiceServiceDescriptor.Methods().ByName("ListAPIKeys")
+)
+
+// APIKeyServiceClient is a client for the apikeys.v1.APIKeyService service.
+type APIKeyServiceClient interface {
+	CreateAPIKey(context.Context, *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error)
+	RevokeAPIKey(context.Context, *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error)
+	ListAPIKeys(context.Context, *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error)
+}
+
+// NewAPIKeyServiceClient constructs a client for the apikeys.v1.APIKeyService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAPIKeyServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) APIKeyServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &aPIKeyServiceClient{
+		createAPIKey: connect.NewClient[v1.CreateAPIKeyRequest, v1.CreateAPIKeyResponse](
+			httpClient,
+			baseURL+APIKeyServiceCreateAPIKeyProcedure,
+			connect.WithSchema(aPIKeyServiceCreateAPIKeyMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		revokeAPIKey: connect.NewClient[v1.RevokeAPIKeyRequest, v1.RevokeAPIKeyResponse](
+			httpClient,
+			baseURL+APIKeyServiceRevokeAPIKeyProcedure,
+			connect.WithSchema(aPIKeyServiceRevokeAPIKeyMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listAPIKeys: connect.NewClient[v1.ListAPIKeysRequest, v1.ListAPIKeysResponse](
+			httpClient,
+			baseURL+APIKeyServiceListAPIKeysProcedure,
+			connect.WithSchema(aPIKeyServiceListAPIKeysMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// aPIKeyServiceClient implements APIKeyServiceClient.
+type aPIKeyServiceClient struct {
+	createAPIKey *connect.Client[v1.CreateAPIKeyRequest, v1.CreateAPIKeyResponse]
+	revokeAPIKey *connect.Client[v1.RevokeAPIKeyRequest, v1.RevokeAPIKeyResponse]
+	listAPIKeys  *connect.Client[v1.ListAPIKeysRequest, v1.ListAPIKeysResponse]
+}
+
+// CreateAPIKey calls apikeys.v1.APIKeyService.CreateAPIKey.
+func (c *aPIKeyServiceClient) CreateAPIKey(ctx context.Context, req *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error) {
+	return c.createAPIKey.CallUnary(ctx, req)
+}
+
+// RevokeAPIKey calls apikeys.v1.APIKeyService.RevokeAPIKey.
+func (c *aPIKeyServiceClient) RevokeAPIKey(ctx context.Context, req *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error) {
+	return c.revokeAPIKey.CallUnary(ctx, req)
+}
+
+// ListAPIKeys calls apikeys.v1.APIKeyService.ListAPIKeys.
+func (c *aPIKeyServiceClient) ListAPIKeys(ctx context.Context, req *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error) {
+	return c.listAPIKeys.CallUnary(ctx, req)
+}
+
+// APIKeyServiceHandler is an implementation of the apikeys.v1.APIKeyService service.
+type APIKeyServiceHandler interface {
+	CreateAPIKey(context.Context, *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error)
+	RevokeAPIKey(context.Context, *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error)
+	ListAPIKeys(context.Context, *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error)
+}
+
+// NewAPIKeyServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAPIKeyServiceHandler(svc APIKeyServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	aPIKeyServiceCreateAPIKeyHandler := connect.NewUnaryHandler(
+		APIKeyServiceCreateAPIKeyProcedure,
+		svc.CreateAPIKey,
+		connect.WithSchema(aPIKeyServiceCreateAPIKeyMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	aPIKeyServiceRevokeAPIKeyHandler := connect.NewUnaryHandler(
+		APIKeyServiceRevokeAPIKeyProcedure,
+		svc.RevokeAPIKey,
+		connect.WithSchema(aPIKeyServiceRevokeAPIKeyMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	aPIKeyServiceListAPIKeysHandler := connect.NewUnaryHandler(
+		APIKeyServiceListAPIKeysProcedure,
+		svc.ListAPIKeys,
+		connect.WithSchema(aPIKeyServiceListAPIKeysMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/apikeys.v1.APIKeyService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIKeyServiceCreateAPIKeyProcedure:
+			aPIKeyServiceCreateAPIKeyHandler.ServeHTTP(w, r)
+		case APIKeyServiceRevokeAPIKeyProcedure:
+			aPIKeyServiceRevokeAPIKeyHandler.ServeHTTP(w, r)
+		case APIKeyServiceListAPIKeysProcedure:
+			aPIKeyServiceListAPIKeysHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAPIKeyServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAPIKeyServiceHandler struct{}
+
+func (UnimplementedAPIKeyServiceHandler) CreateAPIKey(context.Context, *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("apikeys.v1.APIKeyService.CreateAPIKey is not implemented"))
+}
+
+func (UnimplementedAPIKeyServiceHandler) RevokeAPIKey(context.Context, *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("apikeys.v1.APIKeyService.RevokeAPIKey is not implemented"))
+}
+
+func (UnimplementedAPIKeyServiceHandler) ListAPIKeys(context.Context, *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("apikeys.v1.APIKeyService.ListAPIKeys is not implemented"))
+}
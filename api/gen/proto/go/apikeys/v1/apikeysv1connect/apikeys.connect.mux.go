@@ -0,0 +1,37 @@
+// Code generated by protoc-gen-connect-go-mux. DO NOT EDIT.
+//
+// Source: apikeys/v1/apikeys.proto
+
+package apikeysv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	mux "github.com/gorilla/mux"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion0_1_0
+
+// RegisterAPIKeyServiceHandler register an HTTP handler to a mux.Router from the service
+// implementation.
+func RegisterAPIKeyServiceHandler(mux *mux.Router, svc APIKeyServiceHandler, opts ...connect.HandlerOption) {
+	mux.Handle("/apikeys.v1.APIKeyService/CreateAPIKey", connect.NewUnaryHandler(
+		"/apikeys.v1.APIKeyService/CreateAPIKey",
+		svc.CreateAPIKey,
+		opts...,
+	))
+	mux.Handle("/apikeys.v1.APIKeyService/RevokeAPIKey", connect.NewUnaryHandler(
+		"/apikeys.v1.APIKeyService/RevokeAPIKey",
+		svc.RevokeAPIKey,
+		opts...,
+	))
+	mux.Handle("/apikeys.v1.APIKeyService/ListAPIKeys", connect.NewUnaryHandler(
+		"/apikeys.v1.APIKeyService/ListAPIKeys",
+		svc.ListAPIKeys,
+		opts...,
+	))
+}
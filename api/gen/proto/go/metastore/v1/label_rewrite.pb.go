@@ -0,0 +1,986 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: metastore/v1/label_rewrite.proto
+
+package metastorev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateLabelRewriteJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unique name of the job.
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tenant string `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// Milliseconds since epoch. Blocks that overlap
+	// the time range are subject to the rewrite.
+	StartTime int64               `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   int64               `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Rules     []*LabelRewriteRule `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *CreateLabelRewriteJobRequest) Reset() {
+	*x = CreateLabelRewriteJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLabelRewriteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelRewriteJobRequest) ProtoMessage() {}
+
+func (x *CreateLabelRewriteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelRewriteJobRequest.ProtoReflect.Descriptor instead.
+func (*CreateLabelRewriteJobRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateLabelRewriteJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateLabelRewriteJobRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *CreateLabelRewriteJobRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *CreateLabelRewriteJobRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *CreateLabelRewriteJobRequest) GetRules() []*LabelRewriteRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type CreateLabelRewriteJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job *LabelRewriteJob `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *CreateLabelRewriteJobResponse) Reset() {
+	*x = CreateLabelRewriteJobResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLabelRewriteJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelRewriteJobResponse) ProtoMessage() {}
+
+func (x *CreateLabelRewriteJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelRewriteJobResponse.ProtoReflect.Descriptor instead.
+func (*CreateLabelRewriteJobResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateLabelRewriteJobResponse) GetJob() *LabelRewriteJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type GetLabelRewriteJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetLabelRewriteJobRequest) Reset() {
+	*x = GetLabelRewriteJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLabelRewriteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLabelRewriteJobRequest) ProtoMessage() {}
+
+func (x *GetLabelRewriteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLabelRewriteJobRequest.ProtoReflect.Descriptor instead.
+func (*GetLabelRewriteJobRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetLabelRewriteJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetLabelRewriteJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job *LabelRewriteJob `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *GetLabelRewriteJobResponse) Reset() {
+	*x = GetLabelRewriteJobResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLabelRewriteJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLabelRewriteJobResponse) ProtoMessage() {}
+
+func (x *GetLabelRewriteJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLabelRewriteJobResponse.ProtoReflect.Descriptor instead.
+func (*GetLabelRewriteJobResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetLabelRewriteJobResponse) GetJob() *LabelRewriteJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type ListLabelRewriteJobsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Optional. If set, only jobs of the tenant are listed.
+	Tenant string `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+}
+
+func (x *ListLabelRewriteJobsRequest) Reset() {
+	*x = ListLabelRewriteJobsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLabelRewriteJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelRewriteJobsRequest) ProtoMessage() {}
+
+func (x *ListLabelRewriteJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelRewriteJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListLabelRewriteJobsRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListLabelRewriteJobsRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type ListLabelRewriteJobsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Jobs []*LabelRewriteJob `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *ListLabelRewriteJobsResponse) Reset() {
+	*x = ListLabelRewriteJobsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLabelRewriteJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelRewriteJobsResponse) ProtoMessage() {}
+
+func (x *ListLabelRewriteJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelRewriteJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListLabelRewriteJobsResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLabelRewriteJobsResponse) GetJobs() []*LabelRewriteJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// LabelRewriteRule mirrors the Prometheus relabeling configuration;
+// empty fields take the Prometheus defaults. Only actions that do not
+// drop series are allowed, and service_name can only be derived from
+// itself, as it determines the dataset the series belong to.
+type LabelRewriteRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceLabels []string `protobuf:"bytes,1,rep,name=source_labels,json=sourceLabels,proto3" json:"source_labels,omitempty"`
+	Separator    string   `protobuf:"bytes,2,opt,name=separator,proto3" json:"separator,omitempty"`
+	Regex        string   `protobuf:"bytes,3,opt,name=regex,proto3" json:"regex,omitempty"`
+	TargetLabel  string   `protobuf:"bytes,4,opt,name=target_label,json=targetLabel,proto3" json:"target_label,omitempty"`
+	Replacement  string   `protobuf:"bytes,5,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	Action       string   `protobuf:"bytes,6,opt,name=action,proto3" json:"action,omitempty"`
+}
+
+func (x *LabelRewriteRule) Reset() {
+	*x = LabelRewriteRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelRewriteRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelRewriteRule) ProtoMessage() {}
+
+func (x *LabelRewriteRule) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelRewriteRule.ProtoReflect.Descriptor instead.
+func (*LabelRewriteRule) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LabelRewriteRule) GetSourceLabels() []string {
+	if x != nil {
+		return x.SourceLabels
+	}
+	return nil
+}
+
+func (x *LabelRewriteRule) GetSeparator() string {
+	if x != nil {
+		return x.Separator
+	}
+	return ""
+}
+
+func (x *LabelRewriteRule) GetRegex() string {
+	if x != nil {
+		return x.Regex
+	}
+	return ""
+}
+
+func (x *LabelRewriteRule) GetTargetLabel() string {
+	if x != nil {
+		return x.TargetLabel
+	}
+	return ""
+}
+
+func (x *LabelRewriteRule) GetReplacement() string {
+	if x != nil {
+		return x.Replacement
+	}
+	return ""
+}
+
+func (x *LabelRewriteRule) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+// LabelRewrite is attached to the compaction jobs
+// created on behalf of a label rewrite job.
+type LabelRewrite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job   string              `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	Rules []*LabelRewriteRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *LabelRewrite) Reset() {
+	*x = LabelRewrite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelRewrite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelRewrite) ProtoMessage() {}
+
+func (x *LabelRewrite) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelRewrite.ProtoReflect.Descriptor instead.
+func (*LabelRewrite) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LabelRewrite) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *LabelRewrite) GetRules() []*LabelRewriteRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type LabelRewriteJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tenant    string              `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	StartTime int64               `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   int64               `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Rules     []*LabelRewriteRule `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Milliseconds since epoch.
+	CreatedAt int64                    `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Progress  *LabelRewriteJobProgress `protobuf:"bytes,7,opt,name=progress,proto3" json:"progress,omitempty"`
+}
+
+func (x *LabelRewriteJob) Reset() {
+	*x = LabelRewriteJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelRewriteJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelRewriteJob) ProtoMessage() {}
+
+func (x *LabelRewriteJob) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelRewriteJob.ProtoReflect.Descriptor instead.
+func (*LabelRewriteJob) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LabelRewriteJob) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LabelRewriteJob) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *LabelRewriteJob) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *LabelRewriteJob) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *LabelRewriteJob) GetRules() []*LabelRewriteRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *LabelRewriteJob) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *LabelRewriteJob) GetProgress() *LabelRewriteJobProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type LabelRewriteJobProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Number of blocks that matched the job scope at creation.
+	BlocksTotal uint32 `protobuf:"varint,1,opt,name=blocks_total,json=blocksTotal,proto3" json:"blocks_total,omitempty"`
+	// Blocks waiting to be scheduled for rewriting.
+	BlocksPending uint32 `protobuf:"varint,2,opt,name=blocks_pending,json=blocksPending,proto3" json:"blocks_pending,omitempty"`
+	// Blocks being rewritten by compaction workers.
+	BlocksInProgress uint32 `protobuf:"varint,3,opt,name=blocks_in_progress,json=blocksInProgress,proto3" json:"blocks_in_progress,omitempty"`
+	// Blocks that have been rewritten.
+	BlocksDone uint32 `protobuf:"varint,4,opt,name=blocks_done,json=blocksDone,proto3" json:"blocks_done,omitempty"`
+	// Blocks that have been removed before they were rewritten.
+	BlocksSkipped uint32 `protobuf:"varint,5,opt,name=blocks_skipped,json=blocksSkipped,proto3" json:"blocks_skipped,omitempty"`
+}
+
+func (x *LabelRewriteJobProgress) Reset() {
+	*x = LabelRewriteJobProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_label_rewrite_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelRewriteJobProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelRewriteJobProgress) ProtoMessage() {}
+
+func (x *LabelRewriteJobProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_label_rewrite_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelRewriteJobProgress.ProtoReflect.Descriptor instead.
+func (*LabelRewriteJobProgress) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_label_rewrite_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LabelRewriteJobProgress) GetBlocksTotal() uint32 {
+	if x != nil {
+		return x.BlocksTotal
+	}
+	return 0
+}
+
+func (x *LabelRewriteJobProgress) GetBlocksPending() uint32 {
+	if x != nil {
+		return x.BlocksPending
+	}
+	return 0
+}
+
+func (x *LabelRewriteJobProgress) GetBlocksInProgress() uint32 {
+	if x != nil {
+		return x.BlocksInProgress
+	}
+	return 0
+}
+
+func (x *LabelRewriteJobProgress) GetBlocksDone() uint32 {
+	if x != nil {
+		return x.BlocksDone
+	}
+	return 0
+}
+
+func (x *LabelRewriteJobProgress) GetBlocksSkipped() uint32 {
+	if x != nil {
+		return x.BlocksSkipped
+	}
+	return 0
+}
+
+var File_metastore_v1_label_rewrite_proto protoreflect.FileDescriptor
+
+var file_metastore_v1_label_rewrite_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x5f, 0x72, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0c, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x22, 0xba, 0x01, 0x0a, 0x1c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08,
+	0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x50, 0x0a,
+	0x1d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f,
+	0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65,
+	0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x22,
+	0x2f, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x4d, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f,
+	0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65,
+	0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x22,
+	0x35, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x22, 0x51, 0x0a, 0x1c, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x4a, 0x6f, 0x62, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22, 0xc8, 0x01, 0x0a, 0x10, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x56, 0x0a, 0x0c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x34, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x8f, 0x02, 0x0a,
+	0x0f, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65,
+	0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65,
+	0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x41, 0x0a, 0x08, 0x70,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e,
+	0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x22, 0xd9,
+	0x01, 0x0a, 0x17, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a,
+	0x6f, 0x62, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x25, 0x0a,
+	0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x50, 0x65, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x0a, 0x12, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x69,
+	0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x10, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x64, 0x6f, 0x6e,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x44,
+	0x6f, 0x6e, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x73, 0x6b,
+	0x69, 0x70, 0x70, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x73, 0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x32, 0xe5, 0x02, 0x0a, 0x13, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x72, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x2a, 0x2e, 0x6d, 0x65,
+	0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x69, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x27, 0x2e, 0x6d,
+	0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x6f, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65,
+	0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x29, 0x2e, 0x6d, 0x65, 0x74, 0x61,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x42, 0xbe, 0x01, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x42, 0x11, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65,
+	0x77, 0x72, 0x69, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x46, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x61, 0x66, 0x61, 0x6e, 0x61,
+	0x2f, 0x70, 0x79, 0x72, 0x6f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67,
+	0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x61,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x4d, 0x58, 0x58, 0xaa, 0x02, 0x0c, 0x4d, 0x65, 0x74,
+	0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x0c, 0x4d, 0x65, 0x74, 0x61,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x18, 0x4d, 0x65, 0x74, 0x61, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0xea, 0x02, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x3a,
+	0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_metastore_v1_label_rewrite_proto_rawDescOnce sync.Once
+	file_metastore_v1_label_rewrite_proto_rawDescData = file_metastore_v1_label_rewrite_proto_rawDesc
+)
+
+func file_metastore_v1_label_rewrite_proto_rawDescGZIP() []byte {
+	file_metastore_v1_label_rewrite_proto_rawDescOnce.Do(func() {
+		file_metastore_v1_label_rewrite_proto_rawDescData = protoimpl.X.CompressGZIP(file_metastore_v1_label_rewrite_proto_rawDescData)
+	})
+	return file_metastore_v1_label_rewrite_proto_rawDescData
+}
+
+var file_metastore_v1_label_rewrite_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_metastore_v1_label_rewrite_proto_goTypes = []any{
+	(*CreateLabelRewriteJobRequest)(nil),  // 0: metastore.v1.CreateLabelRewriteJobRequest
+	(*CreateLabelRewriteJobResponse)(nil), // 1: metastore.v1.CreateLabelRewriteJobResponse
+	(*GetLabelRewriteJobRequest)(nil),     // 2: metastore.v1.GetLabelRewriteJobRequest
+	(*GetLabelRewriteJobResponse)(nil),    // 3: metastore.v1.GetLabelRewriteJobResponse
+	(*ListLabelRewriteJobsRequest)(nil),   // 4: metastore.v1.ListLabelRewriteJobsRequest
+	(*ListLabelRewriteJobsResponse)(nil),  // 5: metastore.v1.ListLabelRewriteJobsResponse
+	(*LabelRewriteRule)(nil),              // 6: metastore.v1.LabelRewriteRule
+	(*LabelRewrite)(nil),                  // 7: metastore.v1.LabelRewrite
+	(*LabelRewriteJob)(nil),               // 8: metastore.v1.LabelRewriteJob
+	(*LabelRewriteJobProgress)(nil),       // 9: metastore.v1.LabelRewriteJobProgress
+}
+var file_metastore_v1_label_rewrite_proto_depIdxs = []int32{
+	6,  // 0: metastore.v1.CreateLabelRewriteJobRequest.rules:type_name -> metastore.v1.LabelRewriteRule
+	8,  // 1: metastore.v1.CreateLabelRewriteJobResponse.job:type_name -> metastore.v1.LabelRewriteJob
+	8,  // 2: metastore.v1.GetLabelRewriteJobResponse.job:type_name -> metastore.v1.LabelRewriteJob
+	8,  // 3: metastore.v1.ListLabelRewriteJobsResponse.jobs:type_name -> metastore.v1.LabelRewriteJob
+	6,  // 4: metastore.v1.LabelRewrite.rules:type_name -> metastore.v1.LabelRewriteRule
+	6,  // 5: metastore.v1.LabelRewriteJob.rules:type_name -> metastore.v1.LabelRewriteRule
+	9,  // 6: metastore.v1.LabelRewriteJob.progress:type_name -> metastore.v1.LabelRewriteJobProgress
+	0,  // 7: metastore.v1.LabelRewriteService.CreateLabelRewriteJob:input_type -> metastore.v1.CreateLabelRewriteJobRequest
+	2,  // 8: metastore.v1.LabelRewriteService.GetLabelRewriteJob:input_type -> metastore.v1.GetLabelRewriteJobRequest
+	4,  // 9: metastore.v1.LabelRewriteService.ListLabelRewriteJobs:input_type -> metastore.v1.ListLabelRewriteJobsRequest
+	1,  // 10: metastore.v1.LabelRewriteService.CreateLabelRewriteJob:output_type -> metastore.v1.CreateLabelRewriteJobResponse
+	3,  // 11: metastore.v1.LabelRewriteService.GetLabelRewriteJob:output_type -> metastore.v1.GetLabelRewriteJobResponse
+	5,  // 12: metastore.v1.LabelRewriteService.ListLabelRewriteJobs:output_type -> metastore.v1.ListLabelRewriteJobsResponse
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_metastore_v1_label_rewrite_proto_init() }
+func file_metastore_v1_label_rewrite_proto_init() {
+	if File_metastore_v1_label_rewrite_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_metastore_v1_label_rewrite_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateLabelRewriteJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateLabelRewriteJobResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetLabelRewriteJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetLabelRewriteJobResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ListLabelRewriteJobsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ListLabelRewriteJobsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*LabelRewriteRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*LabelRewrite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*LabelRewriteJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_label_rewrite_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*LabelRewriteJobProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_metastore_v1_label_rewrite_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_metastore_v1_label_rewrite_proto_goTypes,
+		DependencyIndexes: file_metastore_v1_label_rewrite_proto_depIdxs,
+		MessageInfos:      file_metastore_v1_label_rewrite_proto_msgTypes,
+	}.Build()
+	File_metastore_v1_label_rewrite_proto = out.File
+	file_metastore_v1_label_rewrite_proto_rawDesc = nil
+	file_metastore_v1_label_rewrite_proto_goTypes = nil
+	file_metastore_v1_label_rewrite_proto_depIdxs = nil
+}
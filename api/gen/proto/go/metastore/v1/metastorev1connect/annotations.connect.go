@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: metastore/v1/annotations.proto
+
+package metastorev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AnnotationServiceName is the fully-qualified name of the AnnotationService service.
+	AnnotationServiceName = "metastore.v1.AnnotationService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AnnotationServiceAddAnnotationProcedure is the fully-qualified name of the AnnotationService's
+	// AddAnnotation RPC.
+	AnnotationServiceAddAnnotationProcedure = "/metastore.v1.AnnotationService/AddAnnotation"
+	// AnnotationServiceQueryAnnotationsProcedure is the fully-qualified name of the AnnotationService's
+	// QueryAnnotations RPC.
+	AnnotationServiceQueryAnnotationsProcedure = "/metastore.v1.AnnotationService/QueryAnnotations"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	annotationServiceServiceDescriptor                = v1.File_metastore_v1_annotations_proto.Services().ByName("AnnotationService")
+	annotationServiceAddAnnotationMethodDescriptor    = annotationServiceServiceDescriptor.Methods().ByName("AddAnnotation")
+	annotationServiceQueryAnnotationsMethodDescriptor = annotationServiceServiceDescriptor.Methods().ByName("QueryAnnotations")
+)
+
+// AnnotationServiceClient is a client for the metastore.v1.AnnotationService service.
+type AnnotationServiceClient interface {
+	AddAnnotation(context.Context, *connect.Request[v1.AddAnnotationRequest]) (*connect.Response[v1.AddAnnotationResponse], error)
+	QueryAnnotations(context.Context, *connect.Request[v1.QueryAnnotationsRequest]) (*connect.Response[v1.QueryAnnotationsResponse], error)
+}
+
+// NewAnnotationServiceClient constructs a client for the metastore.v1.AnnotationService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAnnotationServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AnnotationServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &annotationServiceClient{
+		addAnnotation: connect.NewClient[v1.AddAnnotationRequest, v1.AddAnnotationResponse](
+			httpClient,
+			baseURL+AnnotationServiceAddAnnotationProcedure,
+			connect.WithSchema(annotationServiceAddAnnotationMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		queryAnnotations: connect.NewClient[v1.QueryAnnotationsRequest, v1.QueryAnnotationsResponse](
+			httpClient,
+			baseURL+AnnotationServiceQueryAnnotationsProcedure,
+			connect.WithSchema(annotationServiceQueryAnnotationsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// annotationServiceClient implements AnnotationServiceClient.
+type annotationServiceClient struct {
+	addAnnotation    *connect.Client[v1.AddAnnotationRequest, v1.AddAnnotationResponse]
+	queryAnnotations *connect.Client[v1.QueryAnnotationsRequest, v1.QueryAnnotationsResponse]
+}
+
+// AddAnnotation calls metastore.v1.AnnotationService.AddAnnotation.
+func (c *annotationServiceClient) AddAnnotation(ctx context.Context, req *connect.Request[v1.AddAnnotationRequest]) (*connect.Response[v1.AddAnnotationResponse], error) {
+	return c.addAnnotation.CallUnary(ctx, req)
+}
+
+// QueryAnnotations calls metastore.v1.AnnotationService.QueryAnnotations.
+func (c *annotationServiceClient) QueryAnnotations(ctx context.Context, req *connect.Request[v1.QueryAnnotationsRequest]) (*connect.Response[v1.QueryAnnotationsResponse], error) {
+	return c.queryAnnotations.CallUnary(ctx, req)
+}
+
+// AnnotationServiceHandler is an implementation of the metastore.v1.AnnotationService service.
+type AnnotationServiceHandler interface {
+	AddAnnotation(context.Context, *connect.Request[v1.AddAnnotationRequest]) (*connect.Response[v1.AddAnnotationResponse], error)
+	QueryAnnotations(context.Context, *connect.Request[v1.QueryAnnotationsRequest]) (*connect.Response[v1.QueryAnnotationsResponse], error)
+}
+
+// NewAnnotationServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAnnotationServiceHandler(svc AnnotationServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	annotationServiceAddAnnotationHandler := connect.NewUnaryHandler(
+		AnnotationServiceAddAnnotationProcedure,
+		svc.AddAnnotation,
+		connect.WithSchema(annotationServiceAddAnnotationMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	annotationServiceQueryAnnotationsHandler := connect.NewUnaryHandler(
+		AnnotationServiceQueryAnnotationsProcedure,
+		svc.QueryAnnotations,
+		connect.WithSchema(annotationServiceQueryAnnotationsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/metastore.v1.AnnotationService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AnnotationServiceAddAnnotationProcedure:
+			annotationServiceAddAnnotationHandler.ServeHTTP(w, r)
+		case AnnotationServiceQueryAnnotationsProcedure:
+			annotationServiceQueryAnnotationsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAnnotationServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAnnotationServiceHandler struct{}
+
+func (UnimplementedAnnotationServiceHandler) AddAnnotation(context.Context, *connect.Request[v1.AddAnnotationRequest]) (*connect.Response[v1.AddAnnotationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.AnnotationService.AddAnnotation is not implemented"))
+}
+
+func (UnimplementedAnnotationServiceHandler) QueryAnnotations(context.Context, *connect.Request[v1.QueryAnnotationsRequest]) (*connect.Response[v1.QueryAnnotationsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.AnnotationService.QueryAnnotations is not implemented"))
+}
@@ -0,0 +1,113 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: metastore/v1/events.proto
+
+package metastorev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// EventServiceName is the fully-qualified name of the EventService service.
+	EventServiceName = "metastore.v1.EventService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// EventServiceQueryEventsProcedure is the fully-qualified name of the EventService's QueryEvents
+	// RPC.
+	EventServiceQueryEventsProcedure = "/metastore.v1.EventService/QueryEvents"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	eventServiceServiceDescriptor           = v1.File_metastore_v1_events_proto.Services().ByName("EventService")
+	eventServiceQueryEventsMethodDescriptor = eventServiceServiceDescriptor.Methods().ByName("QueryEvents")
+)
+
+// EventServiceClient is a client for the metastore.v1.EventService service.
+type EventServiceClient interface {
+	QueryEvents(context.Context, *connect.Request[v1.QueryEventsRequest]) (*connect.Response[v1.QueryEventsResponse], error)
+}
+
+// NewEventServiceClient constructs a client for the metastore.v1.EventService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewEventServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) EventServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &eventServiceClient{
+		queryEvents: connect.NewClient[v1.QueryEventsRequest, v1.QueryEventsResponse](
+			httpClient,
+			baseURL+EventServiceQueryEventsProcedure,
+			connect.WithSchema(eventServiceQueryEventsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// eventServiceClient implements EventServiceClient.
+type eventServiceClient struct {
+	queryEvents *connect.Client[v1.QueryEventsRequest, v1.QueryEventsResponse]
+}
+
+// QueryEvents calls metastore.v1.EventService.QueryEvents.
+func (c *eventServiceClient) QueryEvents(ctx context.Context, req *connect.Request[v1.QueryEventsRequest]) (*connect.Response[v1.QueryEventsResponse], error) {
+	return c.queryEvents.CallUnary(ctx, req)
+}
+
+// EventServiceHandler is an implementation of the metastore.v1.EventService service.
+type EventServiceHandler interface {
+	QueryEvents(context.Context, *connect.Request[v1.QueryEventsRequest]) (*connect.Response[v1.QueryEventsResponse], error)
+}
+
+// NewEventServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewEventServiceHandler(svc EventServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	eventServiceQueryEventsHandler := connect.NewUnaryHandler(
+		EventServiceQueryEventsProcedure,
+		svc.QueryEvents,
+		connect.WithSchema(eventServiceQueryEventsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/metastore.v1.EventService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EventServiceQueryEventsProcedure:
+			eventServiceQueryEventsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedEventServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedEventServiceHandler struct{}
+
+func (UnimplementedEventServiceHandler) QueryEvents(context.Context, *connect.Request[v1.QueryEventsRequest]) (*connect.Response[v1.QueryEventsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.EventService.QueryEvents is not implemented"))
+}
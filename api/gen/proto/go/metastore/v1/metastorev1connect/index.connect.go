@@ -35,22 +35,127 @@ const (
 const (
 	// IndexServiceAddBlockProcedure is the fully-qualified name of the IndexService's AddBlock RPC.
 	IndexServiceAddBlockProcedure = "/metastore.v1.IndexService/AddBlock"
+	// IndexServiceAddBlocksProcedure is the fully-qualified name of the IndexService's AddBlocks RPC.
+	IndexServiceAddBlocksProcedure = "/metastore.v1.IndexService/AddBlocks"
 	// IndexServiceGetBlockMetadataProcedure is the fully-qualified name of the IndexService's
 	// GetBlockMetadata RPC.
 	IndexServiceGetBlockMetadataProcedure = "/metastore.v1.IndexService/GetBlockMetadata"
+	// IndexServiceDescribeBlockProcedure is the fully-qualified name of the IndexService's
+	// DescribeBlock RPC.
+	IndexServiceDescribeBlockProcedure = "/metastore.v1.IndexService/DescribeBlock"
+	// IndexServiceQuarantineBlockProcedure is the fully-qualified name of the IndexService's
+	// QuarantineBlock RPC.
+	IndexServiceQuarantineBlockProcedure = "/metastore.v1.IndexService/QuarantineBlock"
+	// IndexServiceListQuarantinedBlocksProcedure is the fully-qualified name of the IndexService's
+	// ListQuarantinedBlocks RPC.
+	IndexServiceListQuarantinedBlocksProcedure = "/metastore.v1.IndexService/ListQuarantinedBlocks"
+	// IndexServiceQuarantineWriterProcedure is the fully-qualified name of the IndexService's
+	// QuarantineWriter RPC.
+	IndexServiceQuarantineWriterProcedure = "/metastore.v1.IndexService/QuarantineWriter"
+	// IndexServiceReleaseWriterProcedure is the fully-qualified name of the IndexService's
+	// ReleaseWriter RPC.
+	IndexServiceReleaseWriterProcedure = "/metastore.v1.IndexService/ReleaseWriter"
+	// IndexServiceListQuarantinedWritersProcedure is the fully-qualified name of the IndexService's
+	// ListQuarantinedWriters RPC.
+	IndexServiceListQuarantinedWritersProcedure = "/metastore.v1.IndexService/ListQuarantinedWriters"
+	// IndexServiceListBlocksProcedure is the fully-qualified name of the IndexService's ListBlocks RPC.
+	IndexServiceListBlocksProcedure = "/metastore.v1.IndexService/ListBlocks"
+	// IndexServiceRebuildIndexProcedure is the fully-qualified name of the IndexService's RebuildIndex
+	// RPC.
+	IndexServiceRebuildIndexProcedure = "/metastore.v1.IndexService/RebuildIndex"
+	// IndexServiceGetIndexRebuildStatusProcedure is the fully-qualified name of the IndexService's
+	// GetIndexRebuildStatus RPC.
+	IndexServiceGetIndexRebuildStatusProcedure = "/metastore.v1.IndexService/GetIndexRebuildStatus"
+	// IndexServiceWatchBlocksProcedure is the fully-qualified name of the IndexService's WatchBlocks
+	// RPC.
+	IndexServiceWatchBlocksProcedure = "/metastore.v1.IndexService/WatchBlocks"
+	// IndexServiceListPartitionsProcedure is the fully-qualified name of the IndexService's
+	// ListPartitions RPC.
+	IndexServiceListPartitionsProcedure = "/metastore.v1.IndexService/ListPartitions"
+	// IndexServiceCheckIndexProcedure is the fully-qualified name of the IndexService's CheckIndex RPC.
+	IndexServiceCheckIndexProcedure = "/metastore.v1.IndexService/CheckIndex"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	indexServiceServiceDescriptor                = v1.File_metastore_v1_index_proto.Services().ByName("IndexService")
-	indexServiceAddBlockMethodDescriptor         = indexServiceServiceDescriptor.Methods().ByName("AddBlock")
-	indexServiceGetBlockMetadataMethodDescriptor = indexServiceServiceDescriptor.Methods().ByName("GetBlockMetadata")
+	indexServiceServiceDescriptor                      = v1.File_metastore_v1_index_proto.Services().ByName("IndexService")
+	indexServiceAddBlockMethodDescriptor               = indexServiceServiceDescriptor.Methods().ByName("AddBlock")
+	indexServiceAddBlocksMethodDescriptor              = indexServiceServiceDescriptor.Methods().ByName("AddBlocks")
+	indexServiceGetBlockMetadataMethodDescriptor       = indexServiceServiceDescriptor.Methods().ByName("GetBlockMetadata")
+	indexServiceDescribeBlockMethodDescriptor          = indexServiceServiceDescriptor.Methods().ByName("DescribeBlock")
+	indexServiceQuarantineBlockMethodDescriptor        = indexServiceServiceDescriptor.Methods().ByName("QuarantineBlock")
+	indexServiceListQuarantinedBlocksMethodDescriptor  = indexServiceServiceDescriptor.Methods().ByName("ListQuarantinedBlocks")
+	indexServiceQuarantineWriterMethodDescriptor       = indexServiceServiceDescriptor.Methods().ByName("QuarantineWriter")
+	indexServiceReleaseWriterMethodDescriptor          = indexServiceServiceDescriptor.Methods().ByName("ReleaseWriter")
+	indexServiceListQuarantinedWritersMethodDescriptor = indexServiceServiceDescriptor.Methods().ByName("ListQuarantinedWriters")
+	indexServiceListBlocksMethodDescriptor             = indexServiceServiceDescriptor.Methods().ByName("ListBlocks")
+	indexServiceRebuildIndexMethodDescriptor           = indexServiceServiceDescriptor.Methods().ByName("RebuildIndex")
+	indexServiceGetIndexRebuildStatusMethodDescriptor  = indexServiceServiceDescriptor.Methods().ByName("GetIndexRebuildStatus")
+	indexServiceWatchBlocksMethodDescriptor            = indexServiceServiceDescriptor.Methods().ByName("WatchBlocks")
+	indexServiceListPartitionsMethodDescriptor         = indexServiceServiceDescriptor.Methods().ByName("ListPartitions")
+	indexServiceCheckIndexMethodDescriptor             = indexServiceServiceDescriptor.Methods().ByName("CheckIndex")
 )
 
 // IndexServiceClient is a client for the metastore.v1.IndexService service.
 type IndexServiceClient interface {
 	AddBlock(context.Context, *connect.Request[v1.AddBlockRequest]) (*connect.Response[v1.AddBlockResponse], error)
+	// AddBlocks adds the blocks with a single raft command, which is applied
+	// in a single transaction: writers that register many blocks at once may
+	// use it to reduce the overhead per block. The blocks are not added
+	// atomically: the result of each block is reported separately.
+	AddBlocks(context.Context, *connect.Request[v1.AddBlocksRequest]) (*connect.Response[v1.AddBlocksResponse], error)
 	GetBlockMetadata(context.Context, *connect.Request[v1.GetBlockMetadataRequest]) (*connect.Response[v1.GetBlockMetadataResponse], error)
+	// DescribeBlock returns the full metadata of a block, including the
+	// layout of its datasets. Intended for debugging tools and admin UI.
+	DescribeBlock(context.Context, *connect.Request[v1.DescribeBlockRequest]) (*connect.Response[v1.DescribeBlockResponse], error)
+	// QuarantineBlock excludes the block from query results and compaction
+	// inputs. The block is reported by the query backend if it repeatedly
+	// fails to read the block object, e.g., because the data is corrupted.
+	QuarantineBlock(context.Context, *connect.Request[v1.QuarantineBlockRequest]) (*connect.Response[v1.QuarantineBlockResponse], error)
+	// ListQuarantinedBlocks returns quarantined blocks of the tenants
+	// within the time range. Intended for admin UI and repair tooling.
+	ListQuarantinedBlocks(context.Context, *connect.Request[v1.ListQuarantinedBlocksRequest]) (*connect.Response[v1.ListQuarantinedBlocksResponse], error)
+	// QuarantineWriter makes the metastore refuse blocks registered
+	// by the writer instance until the quarantine is released. Intended
+	// for isolating misbehaving writers, e.g., flooding tiny blocks.
+	QuarantineWriter(context.Context, *connect.Request[v1.QuarantineWriterRequest]) (*connect.Response[v1.QuarantineWriterResponse], error)
+	// ReleaseWriter releases the quarantine of the writer instance.
+	ReleaseWriter(context.Context, *connect.Request[v1.ReleaseWriterRequest]) (*connect.Response[v1.ReleaseWriterResponse], error)
+	// ListQuarantinedWriters returns the quarantined writer instances.
+	ListQuarantinedWriters(context.Context, *connect.Request[v1.ListQuarantinedWritersRequest]) (*connect.Response[v1.ListQuarantinedWritersResponse], error)
+	// ListBlocks returns blocks of the tenant within the time range, page by
+	// page. Unlike QueryMetadata, the response size does not depend on the
+	// number of blocks matching the request, which makes it suitable for
+	// tools that need to scan large parts of the index, e.g., for auditing.
+	ListBlocks(context.Context, *connect.Request[v1.ListBlocksRequest]) (*connect.Response[v1.ListBlocksResponse], error)
+	// RebuildIndex starts registering the blocks found in the object storage,
+	// which restores the index if the metastore database has been lost. The
+	// metadata is read from the block objects; the blocks already present in
+	// the index are skipped. The rebuild runs in the background on the raft
+	// leader, and stops if the leadership is lost.
+	RebuildIndex(context.Context, *connect.Request[v1.RebuildIndexRequest]) (*connect.Response[v1.RebuildIndexResponse], error)
+	// GetIndexRebuildStatus returns the progress of the last index rebuild.
+	GetIndexRebuildStatus(context.Context, *connect.Request[v1.GetIndexRebuildStatusRequest]) (*connect.Response[v1.GetIndexRebuildStatusResponse], error)
+	// WatchBlocks streams the changes made to the blocks of the tenants:
+	// blocks added, replaced with the compacted ones, and deleted because
+	// of the retention policy. Each metastore replica keeps a limited number
+	// of the most recent changes in memory: the watch can be resumed from
+	// the cursor of the last response received, unless the changes after
+	// it are no longer available, e.g., because the replica has restarted.
+	// In that case the stream fails with the OUT_OF_RANGE code, and the
+	// blocks are to be listed again with ListBlocks.
+	WatchBlocks(context.Context, *connect.Request[v1.WatchBlocksRequest]) (*connect.ServerStreamForClient[v1.WatchBlocksResponse], error)
+	// ListPartitions returns the index partitions, and their state on the
+	// replica serving the request: whether the partition is loaded in memory,
+	// and when it was accessed last. Intended for debugging tools and admin UI.
+	ListPartitions(context.Context, *connect.Request[v1.ListPartitionsRequest]) (*connect.Response[v1.ListPartitionsResponse], error)
+	// CheckIndex cross-checks the index partitions in memory against the
+	// database, and optionally, the blocks against the object storage. The
+	// check runs on the replica serving the request. If the repair is
+	// requested, the check is run on all the replicas through the raft log,
+	// and the issues found are resolved according to the database. Missing
+	// block objects are reported, but never repaired.
+	CheckIndex(context.Context, *connect.Request[v1.CheckIndexRequest]) (*connect.Response[v1.CheckIndexResponse], error)
 }
 
 // NewIndexServiceClient constructs a client for the metastore.v1.IndexService service. By default,
@@ -69,19 +174,110 @@ func NewIndexServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			connect.WithSchema(indexServiceAddBlockMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		addBlocks: connect.NewClient[v1.AddBlocksRequest, v1.AddBlocksResponse](
+			httpClient,
+			baseURL+IndexServiceAddBlocksProcedure,
+			connect.WithSchema(indexServiceAddBlocksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 		getBlockMetadata: connect.NewClient[v1.GetBlockMetadataRequest, v1.GetBlockMetadataResponse](
 			httpClient,
 			baseURL+IndexServiceGetBlockMetadataProcedure,
 			connect.WithSchema(indexServiceGetBlockMetadataMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		describeBlock: connect.NewClient[v1.DescribeBlockRequest, v1.DescribeBlockResponse](
+			httpClient,
+			baseURL+IndexServiceDescribeBlockProcedure,
+			connect.WithSchema(indexServiceDescribeBlockMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		quarantineBlock: connect.NewClient[v1.QuarantineBlockRequest, v1.QuarantineBlockResponse](
+			httpClient,
+			baseURL+IndexServiceQuarantineBlockProcedure,
+			connect.WithSchema(indexServiceQuarantineBlockMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listQuarantinedBlocks: connect.NewClient[v1.ListQuarantinedBlocksRequest, v1.ListQuarantinedBlocksResponse](
+			httpClient,
+			baseURL+IndexServiceListQuarantinedBlocksProcedure,
+			connect.WithSchema(indexServiceListQuarantinedBlocksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		quarantineWriter: connect.NewClient[v1.QuarantineWriterRequest, v1.QuarantineWriterResponse](
+			httpClient,
+			baseURL+IndexServiceQuarantineWriterProcedure,
+			connect.WithSchema(indexServiceQuarantineWriterMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		releaseWriter: connect.NewClient[v1.ReleaseWriterRequest, v1.ReleaseWriterResponse](
+			httpClient,
+			baseURL+IndexServiceReleaseWriterProcedure,
+			connect.WithSchema(indexServiceReleaseWriterMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listQuarantinedWriters: connect.NewClient[v1.ListQuarantinedWritersRequest, v1.ListQuarantinedWritersResponse](
+			httpClient,
+			baseURL+IndexServiceListQuarantinedWritersProcedure,
+			connect.WithSchema(indexServiceListQuarantinedWritersMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listBlocks: connect.NewClient[v1.ListBlocksRequest, v1.ListBlocksResponse](
+			httpClient,
+			baseURL+IndexServiceListBlocksProcedure,
+			connect.WithSchema(indexServiceListBlocksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		rebuildIndex: connect.NewClient[v1.RebuildIndexRequest, v1.RebuildIndexResponse](
+			httpClient,
+			baseURL+IndexServiceRebuildIndexProcedure,
+			connect.WithSchema(indexServiceRebuildIndexMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getIndexRebuildStatus: connect.NewClient[v1.GetIndexRebuildStatusRequest, v1.GetIndexRebuildStatusResponse](
+			httpClient,
+			baseURL+IndexServiceGetIndexRebuildStatusProcedure,
+			connect.WithSchema(indexServiceGetIndexRebuildStatusMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		watchBlocks: connect.NewClient[v1.WatchBlocksRequest, v1.WatchBlocksResponse](
+			httpClient,
+			baseURL+IndexServiceWatchBlocksProcedure,
+			connect.WithSchema(indexServiceWatchBlocksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listPartitions: connect.NewClient[v1.ListPartitionsRequest, v1.ListPartitionsResponse](
+			httpClient,
+			baseURL+IndexServiceListPartitionsProcedure,
+			connect.WithSchema(indexServiceListPartitionsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		checkIndex: connect.NewClient[v1.CheckIndexRequest, v1.CheckIndexResponse](
+			httpClient,
+			baseURL+IndexServiceCheckIndexProcedure,
+			connect.WithSchema(indexServiceCheckIndexMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // indexServiceClient implements IndexServiceClient.
 type indexServiceClient struct {
-	addBlock         *connect.Client[v1.AddBlockRequest, v1.AddBlockResponse]
-	getBlockMetadata *connect.Client[v1.GetBlockMetadataRequest, v1.GetBlockMetadataResponse]
+	addBlock               *connect.Client[v1.AddBlockRequest, v1.AddBlockResponse]
+	addBlocks              *connect.Client[v1.AddBlocksRequest, v1.AddBlocksResponse]
+	getBlockMetadata       *connect.Client[v1.GetBlockMetadataRequest, v1.GetBlockMetadataResponse]
+	describeBlock          *connect.Client[v1.DescribeBlockRequest, v1.DescribeBlockResponse]
+	quarantineBlock        *connect.Client[v1.QuarantineBlockRequest, v1.QuarantineBlockResponse]
+	listQuarantinedBlocks  *connect.Client[v1.ListQuarantinedBlocksRequest, v1.ListQuarantinedBlocksResponse]
+	quarantineWriter       *connect.Client[v1.QuarantineWriterRequest, v1.QuarantineWriterResponse]
+	releaseWriter          *connect.Client[v1.ReleaseWriterRequest, v1.ReleaseWriterResponse]
+	listQuarantinedWriters *connect.Client[v1.ListQuarantinedWritersRequest, v1.ListQuarantinedWritersResponse]
+	listBlocks             *connect.Client[v1.ListBlocksRequest, v1.ListBlocksResponse]
+	rebuildIndex           *connect.Client[v1.RebuildIndexRequest, v1.RebuildIndexResponse]
+	getIndexRebuildStatus  *connect.Client[v1.GetIndexRebuildStatusRequest, v1.GetIndexRebuildStatusResponse]
+	watchBlocks            *connect.Client[v1.WatchBlocksRequest, v1.WatchBlocksResponse]
+	listPartitions         *connect.Client[v1.ListPartitionsRequest, v1.ListPartitionsResponse]
+	checkIndex             *connect.Client[v1.CheckIndexRequest, v1.CheckIndexResponse]
 }
 
 // AddBlock calls metastore.v1.IndexService.AddBlock.
@@ -89,15 +285,136 @@ func (c *indexServiceClient) AddBlock(ctx context.Context, req *connect.Request[
 	return c.addBlock.CallUnary(ctx, req)
 }
 
+// AddBlocks calls metastore.v1.IndexService.AddBlocks.
+func (c *indexServiceClient) AddBlocks(ctx context.Context, req *connect.Request[v1.AddBlocksRequest]) (*connect.Response[v1.AddBlocksResponse], error) {
+	return c.addBlocks.CallUnary(ctx, req)
+}
+
 // GetBlockMetadata calls metastore.v1.IndexService.GetBlockMetadata.
 func (c *indexServiceClient) GetBlockMetadata(ctx context.Context, req *connect.Request[v1.GetBlockMetadataRequest]) (*connect.Response[v1.GetBlockMetadataResponse], error) {
 	return c.getBlockMetadata.CallUnary(ctx, req)
 }
 
+// DescribeBlock calls metastore.v1.IndexService.DescribeBlock.
+func (c *indexServiceClient) DescribeBlock(ctx context.Context, req *connect.Request[v1.DescribeBlockRequest]) (*connect.Response[v1.DescribeBlockResponse], error) {
+	return c.describeBlock.CallUnary(ctx, req)
+}
+
+// QuarantineBlock calls metastore.v1.IndexService.QuarantineBlock.
+func (c *indexServiceClient) QuarantineBlock(ctx context.Context, req *connect.Request[v1.QuarantineBlockRequest]) (*connect.Response[v1.QuarantineBlockResponse], error) {
+	return c.quarantineBlock.CallUnary(ctx, req)
+}
+
+// ListQuarantinedBlocks calls metastore.v1.IndexService.ListQuarantinedBlocks.
+func (c *indexServiceClient) ListQuarantinedBlocks(ctx context.Context, req *connect.Request[v1.ListQuarantinedBlocksRequest]) (*connect.Response[v1.ListQuarantinedBlocksResponse], error) {
+	return c.listQuarantinedBlocks.CallUnary(ctx, req)
+}
+
+// QuarantineWriter calls metastore.v1.IndexService.QuarantineWriter.
+func (c *indexServiceClient) QuarantineWriter(ctx context.Context, req *connect.Request[v1.QuarantineWriterRequest]) (*connect.Response[v1.QuarantineWriterResponse], error) {
+	return c.quarantineWriter.CallUnary(ctx, req)
+}
+
+// ReleaseWriter calls metastore.v1.IndexService.ReleaseWriter.
+func (c *indexServiceClient) ReleaseWriter(ctx context.Context, req *connect.Request[v1.ReleaseWriterRequest]) (*connect.Response[v1.ReleaseWriterResponse], error) {
+	return c.releaseWriter.CallUnary(ctx, req)
+}
+
+// ListQuarantinedWriters calls metastore.v1.IndexService.ListQuarantinedWriters.
+func (c *indexServiceClient) ListQuarantinedWriters(ctx context.Context, req *connect.Request[v1.ListQuarantinedWritersRequest]) (*connect.Response[v1.ListQuarantinedWritersResponse], error) {
+	return c.listQuarantinedWriters.CallUnary(ctx, req)
+}
+
+// ListBlocks calls metastore.v1.IndexService.ListBlocks.
+func (c *indexServiceClient) ListBlocks(ctx context.Context, req *connect.Request[v1.ListBlocksRequest]) (*connect.Response[v1.ListBlocksResponse], error) {
+	return c.listBlocks.CallUnary(ctx, req)
+}
+
+// RebuildIndex calls metastore.v1.IndexService.RebuildIndex.
+func (c *indexServiceClient) RebuildIndex(ctx context.Context, req *connect.Request[v1.RebuildIndexRequest]) (*connect.Response[v1.RebuildIndexResponse], error) {
+	return c.rebuildIndex.CallUnary(ctx, req)
+}
+
+// GetIndexRebuildStatus calls metastore.v1.IndexService.GetIndexRebuildStatus.
+func (c *indexServiceClient) GetIndexRebuildStatus(ctx context.Context, req *connect.Request[v1.GetIndexRebuildStatusRequest]) (*connect.Response[v1.GetIndexRebuildStatusResponse], error) {
+	return c.getIndexRebuildStatus.CallUnary(ctx, req)
+}
+
+// WatchBlocks calls metastore.v1.IndexService.WatchBlocks.
+func (c *indexServiceClient) WatchBlocks(ctx context.Context, req *connect.Request[v1.WatchBlocksRequest]) (*connect.ServerStreamForClient[v1.WatchBlocksResponse], error) {
+	return c.watchBlocks.CallServerStream(ctx, req)
+}
+
+// ListPartitions calls metastore.v1.IndexService.ListPartitions.
+func (c *indexServiceClient) ListPartitions(ctx context.Context, req *connect.Request[v1.ListPartitionsRequest]) (*connect.Response[v1.ListPartitionsResponse], error) {
+	return c.listPartitions.CallUnary(ctx, req)
+}
+
+// CheckIndex calls metastore.v1.IndexService.CheckIndex.
+func (c *indexServiceClient) CheckIndex(ctx context.Context, req *connect.Request[v1.CheckIndexRequest]) (*connect.Response[v1.CheckIndexResponse], error) {
+	return c.checkIndex.CallUnary(ctx, req)
+}
+
 // IndexServiceHandler is an implementation of the metastore.v1.IndexService service.
 type IndexServiceHandler interface {
 	AddBlock(context.Context, *connect.Request[v1.AddBlockRequest]) (*connect.Response[v1.AddBlockResponse], error)
+	// AddBlocks adds the blocks with a single raft command, which is applied
+	// in a single transaction: writers that register many blocks at once may
+	// use it to reduce the overhead per block. The blocks are not added
+	// atomically: the result of each block is reported separately.
+	AddBlocks(context.Context, *connect.Request[v1.AddBlocksRequest]) (*connect.Response[v1.AddBlocksResponse], error)
 	GetBlockMetadata(context.Context, *connect.Request[v1.GetBlockMetadataRequest]) (*connect.Response[v1.GetBlockMetadataResponse], error)
+	// DescribeBlock returns the full metadata of a block, including the
+	// layout of its datasets. Intended for debugging tools and admin UI.
+	DescribeBlock(context.Context, *connect.Request[v1.DescribeBlockRequest]) (*connect.Response[v1.DescribeBlockResponse], error)
+	// QuarantineBlock excludes the block from query results and compaction
+	// inputs. The block is reported by the query backend if it repeatedly
+	// fails to read the block object, e.g., because the data is corrupted.
+	QuarantineBlock(context.Context, *connect.Request[v1.QuarantineBlockRequest]) (*connect.Response[v1.QuarantineBlockResponse], error)
+	// ListQuarantinedBlocks returns quarantined blocks of the tenants
+	// within the time range. Intended for admin UI and repair tooling.
+	ListQuarantinedBlocks(context.Context, *connect.Request[v1.ListQuarantinedBlocksRequest]) (*connect.Response[v1.ListQuarantinedBlocksResponse], error)
+	// QuarantineWriter makes the metastore refuse blocks registered
+	// by the writer instance until the quarantine is released. Intended
+	// for isolating misbehaving writers, e.g., flooding tiny blocks.
+	QuarantineWriter(context.Context, *connect.Request[v1.QuarantineWriterRequest]) (*connect.Response[v1.QuarantineWriterResponse], error)
+	// ReleaseWriter releases the quarantine of the writer instance.
+	ReleaseWriter(context.Context, *connect.Request[v1.ReleaseWriterRequest]) (*connect.Response[v1.ReleaseWriterResponse], error)
+	// ListQuarantinedWriters returns the quarantined writer instances.
+	ListQuarantinedWriters(context.Context, *connect.Request[v1.ListQuarantinedWritersRequest]) (*connect.Response[v1.ListQuarantinedWritersResponse], error)
+	// ListBlocks returns blocks of the tenant within the time range, page by
+	// page. Unlike QueryMetadata, the response size does not depend on the
+	// number of blocks matching the request, which makes it suitable for
+	// tools that need to scan large parts of the index, e.g., for auditing.
+	ListBlocks(context.Context, *connect.Request[v1.ListBlocksRequest]) (*connect.Response[v1.ListBlocksResponse], error)
+	// RebuildIndex starts registering the blocks found in the object storage,
+	// which restores the index if the metastore database has been lost. The
+	// metadata is read from the block objects; the blocks already present in
+	// the index are skipped. The rebuild runs in the background on the raft
+	// leader, and stops if the leadership is lost.
+	RebuildIndex(context.Context, *connect.Request[v1.RebuildIndexRequest]) (*connect.Response[v1.RebuildIndexResponse], error)
+	// GetIndexRebuildStatus returns the progress of the last index rebuild.
+	GetIndexRebuildStatus(context.Context, *connect.Request[v1.GetIndexRebuildStatusRequest]) (*connect.Response[v1.GetIndexRebuildStatusResponse], error)
+	// WatchBlocks streams the changes made to the blocks of the tenants:
+	// blocks added, replaced with the compacted ones, and deleted because
+	// of the retention policy. Each metastore replica keeps a limited number
+	// of the most recent changes in memory: the watch can be resumed from
+	// the cursor of the last response received, unless the changes after
+	// it are no longer available, e.g., because the replica has restarted.
+	// In that case the stream fails with the OUT_OF_RANGE code, and the
+	// blocks are to be listed again with ListBlocks.
+	WatchBlocks(context.Context, *connect.Request[v1.WatchBlocksRequest], *connect.ServerStream[v1.WatchBlocksResponse]) error
+	// ListPartitions returns the index partitions, and their state on the
+	// replica serving the request: whether the partition is loaded in memory,
+	// and when it was accessed last. Intended for debugging tools and admin UI.
+	ListPartitions(context.Context, *connect.Request[v1.ListPartitionsRequest]) (*connect.Response[v1.ListPartitionsResponse], error)
+	// CheckIndex cross-checks the index partitions in memory against the
+	// database, and optionally, the blocks against the object storage. The
+	// check runs on the replica serving the request. If the repair is
+	// requested, the check is run on all the replicas through the raft log,
+	// and the issues found are resolved according to the database. Missing
+	// block objects are reported, but never repaired.
+	CheckIndex(context.Context, *connect.Request[v1.CheckIndexRequest]) (*connect.Response[v1.CheckIndexResponse], error)
 }
 
 // NewIndexServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -112,18 +429,122 @@ func NewIndexServiceHandler(svc IndexServiceHandler, opts ...connect.HandlerOpti
 		connect.WithSchema(indexServiceAddBlockMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	indexServiceAddBlocksHandler := connect.NewUnaryHandler(
+		IndexServiceAddBlocksProcedure,
+		svc.AddBlocks,
+		connect.WithSchema(indexServiceAddBlocksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	indexServiceGetBlockMetadataHandler := connect.NewUnaryHandler(
 		IndexServiceGetBlockMetadataProcedure,
 		svc.GetBlockMetadata,
 		connect.WithSchema(indexServiceGetBlockMetadataMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	indexServiceDescribeBlockHandler := connect.NewUnaryHandler(
+		IndexServiceDescribeBlockProcedure,
+		svc.DescribeBlock,
+		connect.WithSchema(indexServiceDescribeBlockMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceQuarantineBlockHandler := connect.NewUnaryHandler(
+		IndexServiceQuarantineBlockProcedure,
+		svc.QuarantineBlock,
+		connect.WithSchema(indexServiceQuarantineBlockMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceListQuarantinedBlocksHandler := connect.NewUnaryHandler(
+		IndexServiceListQuarantinedBlocksProcedure,
+		svc.ListQuarantinedBlocks,
+		connect.WithSchema(indexServiceListQuarantinedBlocksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceQuarantineWriterHandler := connect.NewUnaryHandler(
+		IndexServiceQuarantineWriterProcedure,
+		svc.QuarantineWriter,
+		connect.WithSchema(indexServiceQuarantineWriterMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceReleaseWriterHandler := connect.NewUnaryHandler(
+		IndexServiceReleaseWriterProcedure,
+		svc.ReleaseWriter,
+		connect.WithSchema(indexServiceReleaseWriterMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceListQuarantinedWritersHandler := connect.NewUnaryHandler(
+		IndexServiceListQuarantinedWritersProcedure,
+		svc.ListQuarantinedWriters,
+		connect.WithSchema(indexServiceListQuarantinedWritersMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceListBlocksHandler := connect.NewUnaryHandler(
+		IndexServiceListBlocksProcedure,
+		svc.ListBlocks,
+		connect.WithSchema(indexServiceListBlocksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceRebuildIndexHandler := connect.NewUnaryHandler(
+		IndexServiceRebuildIndexProcedure,
+		svc.RebuildIndex,
+		connect.WithSchema(indexServiceRebuildIndexMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceGetIndexRebuildStatusHandler := connect.NewUnaryHandler(
+		IndexServiceGetIndexRebuildStatusProcedure,
+		svc.GetIndexRebuildStatus,
+		connect.WithSchema(indexServiceGetIndexRebuildStatusMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceWatchBlocksHandler := connect.NewServerStreamHandler(
+		IndexServiceWatchBlocksProcedure,
+		svc.WatchBlocks,
+		connect.WithSchema(indexServiceWatchBlocksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceListPartitionsHandler := connect.NewUnaryHandler(
+		IndexServiceListPartitionsProcedure,
+		svc.ListPartitions,
+		connect.WithSchema(indexServiceListPartitionsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	indexServiceCheckIndexHandler := connect.NewUnaryHandler(
+		IndexServiceCheckIndexProcedure,
+		svc.CheckIndex,
+		connect.WithSchema(indexServiceCheckIndexMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/metastore.v1.IndexService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case IndexServiceAddBlockProcedure:
 			indexServiceAddBlockHandler.ServeHTTP(w, r)
+		case IndexServiceAddBlocksProcedure:
+			indexServiceAddBlocksHandler.ServeHTTP(w, r)
 		case IndexServiceGetBlockMetadataProcedure:
 			indexServiceGetBlockMetadataHandler.ServeHTTP(w, r)
+		case IndexServiceDescribeBlockProcedure:
+			indexServiceDescribeBlockHandler.ServeHTTP(w, r)
+		case IndexServiceQuarantineBlockProcedure:
+			indexServiceQuarantineBlockHandler.ServeHTTP(w, r)
+		case IndexServiceListQuarantinedBlocksProcedure:
+			indexServiceListQuarantinedBlocksHandler.ServeHTTP(w, r)
+		case IndexServiceQuarantineWriterProcedure:
+			indexServiceQuarantineWriterHandler.ServeHTTP(w, r)
+		case IndexServiceReleaseWriterProcedure:
+			indexServiceReleaseWriterHandler.ServeHTTP(w, r)
+		case IndexServiceListQuarantinedWritersProcedure:
+			indexServiceListQuarantinedWritersHandler.ServeHTTP(w, r)
+		case IndexServiceListBlocksProcedure:
+			indexServiceListBlocksHandler.ServeHTTP(w, r)
+		case IndexServiceRebuildIndexProcedure:
+			indexServiceRebuildIndexHandler.ServeHTTP(w, r)
+		case IndexServiceGetIndexRebuildStatusProcedure:
+			indexServiceGetIndexRebuildStatusHandler.ServeHTTP(w, r)
+		case IndexServiceWatchBlocksProcedure:
+			indexServiceWatchBlocksHandler.ServeHTTP(w, r)
+		case IndexServiceListPartitionsProcedure:
+			indexServiceListPartitionsHandler.ServeHTTP(w, r)
+		case IndexServiceCheckIndexProcedure:
+			indexServiceCheckIndexHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -137,6 +558,58 @@ func (UnimplementedIndexServiceHandler) AddBlock(context.Context, *connect.Reque
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.AddBlock is not implemented"))
 }
 
+func (UnimplementedIndexServiceHandler) AddBlocks(context.Context, *connect.Request[v1.AddBlocksRequest]) (*connect.Response[v1.AddBlocksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.AddBlocks is not implemented"))
+}
+
 func (UnimplementedIndexServiceHandler) GetBlockMetadata(context.Context, *connect.Request[v1.GetBlockMetadataRequest]) (*connect.Response[v1.GetBlockMetadataResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.GetBlockMetadata is not implemented"))
 }
+
+func (UnimplementedIndexServiceHandler) DescribeBlock(context.Context, *connect.Request[v1.DescribeBlockRequest]) (*connect.Response[v1.DescribeBlockResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.DescribeBlock is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) QuarantineBlock(context.Context, *connect.Request[v1.QuarantineBlockRequest]) (*connect.Response[v1.QuarantineBlockResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.QuarantineBlock is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) ListQuarantinedBlocks(context.Context, *connect.Request[v1.ListQuarantinedBlocksRequest]) (*connect.Response[v1.ListQuarantinedBlocksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.ListQuarantinedBlocks is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) QuarantineWriter(context.Context, *connect.Request[v1.QuarantineWriterRequest]) (*connect.Response[v1.QuarantineWriterResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.QuarantineWriter is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) ReleaseWriter(context.Context, *connect.Request[v1.ReleaseWriterRequest]) (*connect.Response[v1.ReleaseWriterResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.ReleaseWriter is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) ListQuarantinedWriters(context.Context, *connect.Request[v1.ListQuarantinedWritersRequest]) (*connect.Response[v1.ListQuarantinedWritersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.ListQuarantinedWriters is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) ListBlocks(context.Context, *connect.Request[v1.ListBlocksRequest]) (*connect.Response[v1.ListBlocksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.ListBlocks is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) RebuildIndex(context.Context, *connect.Request[v1.RebuildIndexRequest]) (*connect.Response[v1.RebuildIndexResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.RebuildIndex is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) GetIndexRebuildStatus(context.Context, *connect.Request[v1.GetIndexRebuildStatusRequest]) (*connect.Response[v1.GetIndexRebuildStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.GetIndexRebuildStatus is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) WatchBlocks(context.Context, *connect.Request[v1.WatchBlocksRequest], *connect.ServerStream[v1.WatchBlocksResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.WatchBlocks is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) ListPartitions(context.Context, *connect.Request[v1.ListPartitionsRequest]) (*connect.Response[v1.ListPartitionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.ListPartitions is not implemented"))
+}
+
+func (UnimplementedIndexServiceHandler) CheckIndex(context.Context, *connect.Request[v1.CheckIndexRequest]) (*connect.Response[v1.CheckIndexResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.IndexService.CheckIndex is not implemented"))
+}
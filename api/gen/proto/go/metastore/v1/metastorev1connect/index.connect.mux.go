@@ -24,9 +24,74 @@ func RegisterIndexServiceHandler(mux *mux.Router, svc IndexServiceHandler, opts
 		svc.AddBlock,
 		opts...,
 	))
+	mux.Handle("/metastore.v1.IndexService/AddBlocks", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/AddBlocks",
+		svc.AddBlocks,
+		opts...,
+	))
 	mux.Handle("/metastore.v1.IndexService/GetBlockMetadata", connect.NewUnaryHandler(
 		"/metastore.v1.IndexService/GetBlockMetadata",
 		svc.GetBlockMetadata,
 		opts...,
 	))
+	mux.Handle("/metastore.v1.IndexService/DescribeBlock", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/DescribeBlock",
+		svc.DescribeBlock,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/QuarantineBlock", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/QuarantineBlock",
+		svc.QuarantineBlock,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/ListQuarantinedBlocks", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/ListQuarantinedBlocks",
+		svc.ListQuarantinedBlocks,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/QuarantineWriter", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/QuarantineWriter",
+		svc.QuarantineWriter,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/ReleaseWriter", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/ReleaseWriter",
+		svc.ReleaseWriter,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/ListQuarantinedWriters", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/ListQuarantinedWriters",
+		svc.ListQuarantinedWriters,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/ListBlocks", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/ListBlocks",
+		svc.ListBlocks,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/RebuildIndex", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/RebuildIndex",
+		svc.RebuildIndex,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/GetIndexRebuildStatus", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/GetIndexRebuildStatus",
+		svc.GetIndexRebuildStatus,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/WatchBlocks", connect.NewServerStreamHandler(
+		"/metastore.v1.IndexService/WatchBlocks",
+		svc.WatchBlocks,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/ListPartitions", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/ListPartitions",
+		svc.ListPartitions,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.IndexService/CheckIndex", connect.NewUnaryHandler(
+		"/metastore.v1.IndexService/CheckIndex",
+		svc.CheckIndex,
+		opts...,
+	))
 }
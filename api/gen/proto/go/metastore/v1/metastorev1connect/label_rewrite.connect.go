@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: metastore/v1/label_rewrite.proto
+
+package metastorev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// LabelRewriteServiceName is the fully-qualified name of the LabelRewriteService service.
+	LabelRewriteServiceName = "metastore.v1.LabelRewriteService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// LabelRewriteServiceCreateLabelRewriteJobProcedure is the fully-qualified name of the
+	// LabelRewriteService's CreateLabelRewriteJob RPC.
+	LabelRewriteServiceCreateLabelRewriteJobProcedure = "/metastore.v1.LabelRewriteService/CreateLabelRewriteJob"
+	// LabelRewriteServiceGetLabelRewriteJobProcedure is the fully-qualified name of the
+	// LabelRewriteService's GetLabelRewriteJob RPC.
+	LabelRewriteServiceGetLabelRewriteJobProcedure = "/metastore.v1.LabelRewriteService/GetLabelRewriteJob"
+	// LabelRewriteServiceListLabelRewriteJobsProcedure is the fully-qualified name of the
+	// LabelRewriteService's ListLabelRewriteJobs RPC.
+	LabelRewriteServiceListLabelRewriteJobsProcedure = "/metastore.v1.LabelRewriteService/ListLabelRewriteJobs"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	labelRewriteServiceServiceDescriptor                     = v1.File_metastore_v1_label_rewrite_proto.Services().ByName("LabelRewriteService")
+	labelRewriteServiceCreateLabelRewriteJobMethodDescriptor = labelRewriteServiceServiceDescriptor.Methods().ByName("CreateLabelRewriteJob")
+	labelRewriteServiceGetLabelRewriteJobMethodDescriptor    = labelRewriteServiceServiceDescriptor.Methods().ByName("GetLabelRewriteJob")
+	labelRewriteServiceListLabelRewriteJobsMethodDescriptor  = labelRewriteServiceServiceDescriptor.Methods().ByName("ListLabelRewriteJobs")
+)
+
+// LabelRewriteServiceClient is a client for the metastore.v1.LabelRewriteService service.
+type LabelRewriteServiceClient interface {
+	CreateLabelRewriteJob(context.Context, *connect.Request[v1.CreateLabelRewriteJobRequest]) (*connect.Response[v1.CreateLabelRewriteJobResponse], error)
+	GetLabelRewriteJob(context.Context, *connect.Request[v1.GetLabelRewriteJobRequest]) (*connect.Response[v1.GetLabelRewriteJobResponse], error)
+	ListLabelRewriteJobs(context.Context, *connect.Request[v1.ListLabelRewriteJobsRequest]) (*connect.Response[v1.ListLabelRewriteJobsResponse], error)
+}
+
+// NewLabelRewriteServiceClient constructs a client for the metastore.v1.LabelRewriteService
+// service. By default, it uses the Connect protocol with the binary Protobuf Codec, asks for
+// gzipped responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply
+// the connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewLabelRewriteServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) LabelRewriteServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &labelRewriteServiceClient{
+		createLabelRewriteJob: connect.NewClient[v1.CreateLabelRewriteJobRequest, v1.CreateLabelRewriteJobResponse](
+			httpClient,
+			baseURL+LabelRewriteServiceCreateLabelRewriteJobProcedure,
+			connect.WithSchema(labelRewriteServiceCreateLabelRewriteJobMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getLabelRewriteJob: connect.NewClient[v1.GetLabelRewriteJobRequest, v1.GetLabelRewriteJobResponse](
+			httpClient,
+			baseURL+LabelRewriteServiceGetLabelRewriteJobProcedure,
+			connect.WithSchema(labelRewriteServiceGetLabelRewriteJobMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listLabelRewriteJobs: connect.NewClient[v1.ListLabelRewriteJobsRequest, v1.ListLabelRewriteJobsResponse](
+			httpClient,
+			baseURL+LabelRewriteServiceListLabelRewriteJobsProcedure,
+			connect.WithSchema(labelRewriteServiceListLabelRewriteJobsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// labelRewriteServiceClient implements LabelRewriteServiceClient.
+type labelRewriteServiceClient struct {
+	createLabelRewriteJob *connect.Client[v1.CreateLabelRewriteJobRequest, v1.CreateLabelRewriteJobResponse]
+	getLabelRewriteJob    *connect.Client[v1.GetLabelRewriteJobRequest, v1.GetLabelRewriteJobResponse]
+	listLabelRewriteJobs  *connect.Client[v1.ListLabelRewriteJobsRequest, v1.ListLabelRewriteJobsResponse]
+}
+
+// CreateLabelRewriteJob calls metastore.v1.LabelRewriteService.CreateLabelRewriteJob.
+func (c *labelRewriteServiceClient) CreateLabelRewriteJob(ctx context.Context, req *connect.Request[v1.CreateLabelRewriteJobRequest]) (*connect.Response[v1.CreateLabelRewriteJobResponse], error) {
+	return c.createLabelRewriteJob.CallUnary(ctx, req)
+}
+
+// GetLabelRewriteJob calls metastore.v1.LabelRewriteService.GetLabelRewriteJob.
+func (c *labelRewriteServiceClient) GetLabelRewriteJob(ctx context.Context, req *connect.Request[v1.GetLabelRewriteJobRequest]) (*connect.Response[v1.GetLabelRewriteJobResponse], error) {
+	return c.getLabelRewriteJob.CallUnary(ctx, req)
+}
+
+// ListLabelRewriteJobs calls metastore.v1.LabelRewriteService.ListLabelRewriteJobs.
+func (c *labelRewriteServiceClient) ListLabelRewriteJobs(ctx context.Context, req *connect.Request[v1.ListLabelRewriteJobsRequest]) (*connect.Response[v1.ListLabelRewriteJobsResponse], error) {
+	return c.listLabelRewriteJobs.CallUnary(ctx, req)
+}
+
+// LabelRewriteServiceHandler is an implementation of the metastore.v1.LabelRewriteService service.
+type LabelRewriteServiceHandler interface {
+	CreateLabelRewriteJob(context.Context, *connect.Request[v1.CreateLabelRewriteJobRequest]) (*connect.Response[v1.CreateLabelRewriteJobResponse], error)
+	GetLabelRewriteJob(context.Context, *connect.Request[v1.GetLabelRewriteJobRequest]) (*connect.Response[v1.GetLabelRewriteJobResponse], error)
+	ListLabelRewriteJobs(context.Context, *connect.Request[v1.ListLabelRewriteJobsRequest]) (*connect.Response[v1.ListLabelRewriteJobsResponse], error)
+}
+
+// NewLabelRewriteServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewLabelRewriteServiceHandler(svc LabelRewriteServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	labelRewriteServiceCreateLabelRewriteJobHandler := connect.NewUnaryHandler(
+		LabelRewriteServiceCreateLabelRewriteJobProcedure,
+		svc.CreateLabelRewriteJob,
+		connect.WithSchema(labelRewriteServiceCreateLabelRewriteJobMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	labelRewriteServiceGetLabelRewriteJobHandler := connect.NewUnaryHandler(
+		LabelRewriteServiceGetLabelRewriteJobProcedure,
+		svc.GetLabelRewriteJob,
+		connect.WithSchema(labelRewriteServiceGetLabelRewriteJobMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	labelRewriteServiceListLabelRewriteJobsHandler := connect.NewUnaryHandler(
+		LabelRewriteServiceListLabelRewriteJobsProcedure,
+		svc.ListLabelRewriteJobs,
+		connect.WithSchema(labelRewriteServiceListLabelRewriteJobsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/metastore.v1.LabelRewriteService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LabelRewriteServiceCreateLabelRewriteJobProcedure:
+			labelRewriteServiceCreateLabelRewriteJobHandler.ServeHTTP(w, r)
+		case LabelRewriteServiceGetLabelRewriteJobProcedure:
+			labelRewriteServiceGetLabelRewriteJobHandler.ServeHTTP(w, r)
+		case LabelRewriteServiceListLabelRewriteJobsProcedure:
+			labelRewriteServiceListLabelRewriteJobsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedLabelRewriteServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedLabelRewriteServiceHandler struct{}
+
+func (UnimplementedLabelRewriteServiceHandler) CreateLabelRewriteJob(context.Context, *connect.Request[v1.CreateLabelRewriteJobRequest]) (*connect.Response[v1.CreateLabelRewriteJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.LabelRewriteService.CreateLabelRewriteJob is not implemented"))
+}
+
+func (UnimplementedLabelRewriteServiceHandler) GetLabelRewriteJob(context.Context, *connect.Request[v1.GetLabelRewriteJobRequest]) (*connect.Response[v1.GetLabelRewriteJobResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.LabelRewriteService.GetLabelRewriteJob is not implemented"))
+}
+
+func (UnimplementedLabelRewriteServiceHandler) ListLabelRewriteJobs(context.Context, *connect.Request[v1.ListLabelRewriteJobsRequest]) (*connect.Response[v1.ListLabelRewriteJobsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.LabelRewriteService.ListLabelRewriteJobs is not implemented"))
+}
@@ -0,0 +1,37 @@
+// Code generated by protoc-gen-connect-go-mux. DO NOT EDIT.
+//
+// Source: metastore/v1/label_rewrite.proto
+
+package metastorev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	mux "github.com/gorilla/mux"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion0_1_0
+
+// RegisterLabelRewriteServiceHandler register an HTTP handler to a mux.Router from the service
+// implementation.
+func RegisterLabelRewriteServiceHandler(mux *mux.Router, svc LabelRewriteServiceHandler, opts ...connect.HandlerOption) {
+	mux.Handle("/metastore.v1.LabelRewriteService/CreateLabelRewriteJob", connect.NewUnaryHandler(
+		"/metastore.v1.LabelRewriteService/CreateLabelRewriteJob",
+		svc.CreateLabelRewriteJob,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.LabelRewriteService/GetLabelRewriteJob", connect.NewUnaryHandler(
+		"/metastore.v1.LabelRewriteService/GetLabelRewriteJob",
+		svc.GetLabelRewriteJob,
+		opts...,
+	))
+	mux.Handle("/metastore.v1.LabelRewriteService/ListLabelRewriteJobs", connect.NewUnaryHandler(
+		"/metastore.v1.LabelRewriteService/ListLabelRewriteJobs",
+		svc.ListLabelRewriteJobs,
+		opts...,
+	))
+}
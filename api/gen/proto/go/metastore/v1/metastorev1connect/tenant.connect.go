@@ -38,19 +38,24 @@ const (
 	// TenantServiceDeleteTenantProcedure is the fully-qualified name of the TenantService's
 	// DeleteTenant RPC.
 	TenantServiceDeleteTenantProcedure = "/metastore.v1.TenantService/DeleteTenant"
+	// TenantServiceGetTenantBlockStatsProcedure is the fully-qualified name of the TenantService's
+	// GetTenantBlockStats RPC.
+	TenantServiceGetTenantBlockStatsProcedure = "/metastore.v1.TenantService/GetTenantBlockStats"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	tenantServiceServiceDescriptor            = v1.File_metastore_v1_tenant_proto.Services().ByName("TenantService")
-	tenantServiceGetTenantMethodDescriptor    = tenantServiceServiceDescriptor.Methods().ByName("GetTenant")
-	tenantServiceDeleteTenantMethodDescriptor = tenantServiceServiceDescriptor.Methods().ByName("DeleteTenant")
+	tenantServiceServiceDescriptor                   = v1.File_metastore_v1_tenant_proto.Services().ByName("TenantService")
+	tenantServiceGetTenantMethodDescriptor           = tenantServiceServiceDescriptor.Methods().ByName("GetTenant")
+	tenantServiceDeleteTenantMethodDescriptor        = tenantServiceServiceDescriptor.Methods().ByName("DeleteTenant")
+	tenantServiceGetTenantBlockStatsMethodDescriptor = tenantServiceServiceDescriptor.Methods().ByName("GetTenantBlockStats")
 )
 
 // TenantServiceClient is a client for the metastore.v1.TenantService service.
 type TenantServiceClient interface {
 	GetTenant(context.Context, *connect.Request[v1.GetTenantRequest]) (*connect.Response[v1.GetTenantResponse], error)
 	DeleteTenant(context.Context, *connect.Request[v1.DeleteTenantRequest]) (*connect.Response[v1.DeleteTenantResponse], error)
+	GetTenantBlockStats(context.Context, *connect.Request[v1.GetTenantBlockStatsRequest]) (*connect.Response[v1.GetTenantBlockStatsResponse], error)
 }
 
 // NewTenantServiceClient constructs a client for the metastore.v1.TenantService service. By
@@ -75,13 +80,20 @@ func NewTenantServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(tenantServiceDeleteTenantMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		getTenantBlockStats: connect.NewClient[v1.GetTenantBlockStatsRequest, v1.GetTenantBlockStatsResponse](
+			httpClient,
+			baseURL+TenantServiceGetTenantBlockStatsProcedure,
+			connect.WithSchema(tenantServiceGetTenantBlockStatsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // tenantServiceClient implements TenantServiceClient.
 type tenantServiceClient struct {
-	getTenant    *connect.Client[v1.GetTenantRequest, v1.GetTenantResponse]
-	deleteTenant *connect.Client[v1.DeleteTenantRequest, v1.DeleteTenantResponse]
+	getTenant           *connect.Client[v1.GetTenantRequest, v1.GetTenantResponse]
+	deleteTenant        *connect.Client[v1.DeleteTenantRequest, v1.DeleteTenantResponse]
+	getTenantBlockStats *connect.Client[v1.GetTenantBlockStatsRequest, v1.GetTenantBlockStatsResponse]
 }
 
 // GetTenant calls metastore.v1.TenantService.GetTenant.
@@ -94,10 +106,16 @@ func (c *tenantServiceClient) DeleteTenant(ctx context.Context, req *connect.Req
 	return c.deleteTenant.CallUnary(ctx, req)
 }
 
+// GetTenantBlockStats calls metastore.v1.TenantService.GetTenantBlockStats.
+func (c *tenantServiceClient) GetTenantBlockStats(ctx context.Context, req *connect.Request[v1.GetTenantBlockStatsRequest]) (*connect.Response[v1.GetTenantBlockStatsResponse], error) {
+	return c.getTenantBlockStats.CallUnary(ctx, req)
+}
+
 // TenantServiceHandler is an implementation of the metastore.v1.TenantService service.
 type TenantServiceHandler interface {
 	GetTenant(context.Context, *connect.Request[v1.GetTenantRequest]) (*connect.Response[v1.GetTenantResponse], error)
 	DeleteTenant(context.Context, *connect.Request[v1.DeleteTenantRequest]) (*connect.Response[v1.DeleteTenantResponse], error)
+	GetTenantBlockStats(context.Context, *connect.Request[v1.GetTenantBlockStatsRequest]) (*connect.Response[v1.GetTenantBlockStatsResponse], error)
 }
 
 // NewTenantServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -118,12 +136,20 @@ func NewTenantServiceHandler(svc TenantServiceHandler, opts ...connect.HandlerOp
 		connect.WithSchema(tenantServiceDeleteTenantMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	tenantServiceGetTenantBlockStatsHandler := connect.NewUnaryHandler(
+		TenantServiceGetTenantBlockStatsProcedure,
+		svc.GetTenantBlockStats,
+		connect.WithSchema(tenantServiceGetTenantBlockStatsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/metastore.v1.TenantService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case TenantServiceGetTenantProcedure:
 			tenantServiceGetTenantHandler.ServeHTTP(w, r)
 		case TenantServiceDeleteTenantProcedure:
 			tenantServiceDeleteTenantHandler.ServeHTTP(w, r)
+		case TenantServiceGetTenantBlockStatsProcedure:
+			tenantServiceGetTenantBlockStatsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -140,3 +166,7 @@ func (UnimplementedTenantServiceHandler) GetTenant(context.Context, *connect.Req
 func (UnimplementedTenantServiceHandler) DeleteTenant(context.Context, *connect.Request[v1.DeleteTenantRequest]) (*connect.Response[v1.DeleteTenantResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.TenantService.DeleteTenant is not implemented"))
 }
+
+func (UnimplementedTenantServiceHandler) GetTenantBlockStats(context.Context, *connect.Request[v1.GetTenantBlockStatsRequest]) (*connect.Response[v1.GetTenantBlockStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("metastore.v1.TenantService.GetTenantBlockStats is not implemented"))
+}
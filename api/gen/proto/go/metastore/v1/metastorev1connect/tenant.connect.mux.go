@@ -29,4 +29,9 @@ func RegisterTenantServiceHandler(mux *mux.Router, svc TenantServiceHandler, opt
 		svc.DeleteTenant,
 		opts...,
 	))
+	mux.Handle("/metastore.v1.TenantService/GetTenantBlockStats", connect.NewUnaryHandler(
+		"/metastore.v1.TenantService/GetTenantBlockStats",
+		svc.GetTenantBlockStats,
+		opts...,
+	))
 }
@@ -8,6 +8,7 @@ package raft_log
 
 import (
 	v1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	v11 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -28,21 +29,57 @@ const (
 	RaftCommand_RAFT_COMMAND_ADD_BLOCK_METADATA         RaftCommand = 1
 	RaftCommand_RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE RaftCommand = 2
 	RaftCommand_RAFT_COMMAND_UPDATE_COMPACTION_PLAN     RaftCommand = 3
+	RaftCommand_RAFT_COMMAND_CREATE_LABEL_REWRITE_JOB   RaftCommand = 4
+	RaftCommand_RAFT_COMMAND_ADD_ANNOTATION             RaftCommand = 5
+	RaftCommand_RAFT_COMMAND_QUARANTINE_BLOCK           RaftCommand = 6
+	RaftCommand_RAFT_COMMAND_ARCHIVE_PARTITION          RaftCommand = 7
+	RaftCommand_RAFT_COMMAND_MERGE_PARTITIONS           RaftCommand = 8
+	RaftCommand_RAFT_COMMAND_QUARANTINE_WRITER          RaftCommand = 9
+	RaftCommand_RAFT_COMMAND_RELEASE_WRITER             RaftCommand = 10
+	RaftCommand_RAFT_COMMAND_SWEEP_DELETED_BLOCKS       RaftCommand = 11
+	RaftCommand_RAFT_COMMAND_SPLIT_PARTITION            RaftCommand = 12
+	RaftCommand_RAFT_COMMAND_ADD_BLOCKS                 RaftCommand = 13
+	RaftCommand_RAFT_COMMAND_DELETE_PARTITIONS          RaftCommand = 14
+	RaftCommand_RAFT_COMMAND_REPAIR_INDEX               RaftCommand = 15
 )
 
 // Enum value maps for RaftCommand.
 var (
 	RaftCommand_name = map[int32]string{
-		0: "RAFT_COMMAND_UNKNOWN",
-		1: "RAFT_COMMAND_ADD_BLOCK_METADATA",
-		2: "RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE",
-		3: "RAFT_COMMAND_UPDATE_COMPACTION_PLAN",
+		0:  "RAFT_COMMAND_UNKNOWN",
+		1:  "RAFT_COMMAND_ADD_BLOCK_METADATA",
+		2:  "RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE",
+		3:  "RAFT_COMMAND_UPDATE_COMPACTION_PLAN",
+		4:  "RAFT_COMMAND_CREATE_LABEL_REWRITE_JOB",
+		5:  "RAFT_COMMAND_ADD_ANNOTATION",
+		6:  "RAFT_COMMAND_QUARANTINE_BLOCK",
+		7:  "RAFT_COMMAND_ARCHIVE_PARTITION",
+		8:  "RAFT_COMMAND_MERGE_PARTITIONS",
+		9:  "RAFT_COMMAND_QUARANTINE_WRITER",
+		10: "RAFT_COMMAND_RELEASE_WRITER",
+		11: "RAFT_COMMAND_SWEEP_DELETED_BLOCKS",
+		12: "RAFT_COMMAND_SPLIT_PARTITION",
+		13: "RAFT_COMMAND_ADD_BLOCKS",
+		14: "RAFT_COMMAND_DELETE_PARTITIONS",
+		15: "RAFT_COMMAND_REPAIR_INDEX",
 	}
 	RaftCommand_value = map[string]int32{
 		"RAFT_COMMAND_UNKNOWN":                    0,
 		"RAFT_COMMAND_ADD_BLOCK_METADATA":         1,
 		"RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE": 2,
 		"RAFT_COMMAND_UPDATE_COMPACTION_PLAN":     3,
+		"RAFT_COMMAND_CREATE_LABEL_REWRITE_JOB":   4,
+		"RAFT_COMMAND_ADD_ANNOTATION":             5,
+		"RAFT_COMMAND_QUARANTINE_BLOCK":           6,
+		"RAFT_COMMAND_ARCHIVE_PARTITION":          7,
+		"RAFT_COMMAND_MERGE_PARTITIONS":           8,
+		"RAFT_COMMAND_QUARANTINE_WRITER":          9,
+		"RAFT_COMMAND_RELEASE_WRITER":             10,
+		"RAFT_COMMAND_SWEEP_DELETED_BLOCKS":       11,
+		"RAFT_COMMAND_SPLIT_PARTITION":            12,
+		"RAFT_COMMAND_ADD_BLOCKS":                 13,
+		"RAFT_COMMAND_DELETE_PARTITIONS":          14,
+		"RAFT_COMMAND_REPAIR_INDEX":               15,
 	}
 )
 
@@ -735,6 +772,9 @@ type CompactionJobPlan struct {
 	SourceBlocks    []string `protobuf:"bytes,5,rep,name=source_blocks,json=sourceBlocks,proto3" json:"source_blocks,omitempty"`
 	// Objects to be deleted.
 	Tombstones []*v1.Tombstones `protobuf:"bytes,6,rep,name=tombstones,proto3" json:"tombstones,omitempty"`
+	// Rules to apply to the source blocks, if the job
+	// is created on behalf of a label rewrite job.
+	LabelRewrite *v1.LabelRewrite `protobuf:"bytes,7,opt,name=label_rewrite,json=labelRewrite,proto3" json:"label_rewrite,omitempty"`
 }
 
 func (x *CompactionJobPlan) Reset() {
@@ -811,6 +851,13 @@ func (x *CompactionJobPlan) GetTombstones() []*v1.Tombstones {
 	return nil
 }
 
+func (x *CompactionJobPlan) GetLabelRewrite() *v1.LabelRewrite {
+	if x != nil {
+		return x.LabelRewrite
+	}
+	return nil
+}
+
 // UpdateCompactionPlanRequest proposes compaction plan changes.
 type UpdateCompactionPlanRequest struct {
 	state         protoimpl.MessageState
@@ -843,303 +890,2389 @@ func (x *UpdateCompactionPlanRequest) ProtoReflect() protoreflect.Message {
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UpdateCompactionPlanRequest.ProtoReflect.Descriptor instead.
-func (*UpdateCompactionPlanRequest) Descriptor() ([]byte, []int) {
-	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *UpdateCompactionPlanRequest) GetTerm() uint64 {
-	if x != nil {
-		return x.Term
-	}
-	return 0
-}
-
-func (x *UpdateCompactionPlanRequest) GetPlanUpdate() *CompactionPlanUpdate {
-	if x != nil {
-		return x.PlanUpdate
-	}
-	return nil
-}
-
-type UpdateCompactionPlanResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	PlanUpdate *CompactionPlanUpdate `protobuf:"bytes,1,opt,name=plan_update,json=planUpdate,proto3" json:"plan_update,omitempty"`
-}
-
-func (x *UpdateCompactionPlanResponse) Reset() {
-	*x = UpdateCompactionPlanResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[13]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *UpdateCompactionPlanResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*UpdateCompactionPlanResponse) ProtoMessage() {}
-
-func (x *UpdateCompactionPlanResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[13]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCompactionPlanRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCompactionPlanRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateCompactionPlanRequest) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *UpdateCompactionPlanRequest) GetPlanUpdate() *CompactionPlanUpdate {
+	if x != nil {
+		return x.PlanUpdate
+	}
+	return nil
+}
+
+type UpdateCompactionPlanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlanUpdate *CompactionPlanUpdate `protobuf:"bytes,1,opt,name=plan_update,json=planUpdate,proto3" json:"plan_update,omitempty"`
+}
+
+func (x *UpdateCompactionPlanResponse) Reset() {
+	*x = UpdateCompactionPlanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateCompactionPlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCompactionPlanResponse) ProtoMessage() {}
+
+func (x *UpdateCompactionPlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCompactionPlanResponse.ProtoReflect.Descriptor instead.
+func (*UpdateCompactionPlanResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateCompactionPlanResponse) GetPlanUpdate() *CompactionPlanUpdate {
+	if x != nil {
+		return x.PlanUpdate
+	}
+	return nil
+}
+
+type CreateLabelRewriteJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job *v1.LabelRewriteJob `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *CreateLabelRewriteJobRequest) Reset() {
+	*x = CreateLabelRewriteJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLabelRewriteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelRewriteJobRequest) ProtoMessage() {}
+
+func (x *CreateLabelRewriteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelRewriteJobRequest.ProtoReflect.Descriptor instead.
+func (*CreateLabelRewriteJobRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateLabelRewriteJobRequest) GetJob() *v1.LabelRewriteJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type CreateLabelRewriteJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job *v1.LabelRewriteJob `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *CreateLabelRewriteJobResponse) Reset() {
+	*x = CreateLabelRewriteJobResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLabelRewriteJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelRewriteJobResponse) ProtoMessage() {}
+
+func (x *CreateLabelRewriteJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelRewriteJobResponse.ProtoReflect.Descriptor instead.
+func (*CreateLabelRewriteJobResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateLabelRewriteJobResponse) GetJob() *v1.LabelRewriteJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+// LabelRewriteJobState is the persisted state of a label rewrite job.
+type LabelRewriteJobState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job *v1.LabelRewriteJob `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	// Blocks that have not been scheduled for rewriting yet.
+	PendingBlocks []*LabelRewriteBlock `protobuf:"bytes,2,rep,name=pending_blocks,json=pendingBlocks,proto3" json:"pending_blocks,omitempty"`
+	// Blocks that are being rewritten.
+	ScheduledBlocks []*LabelRewriteBlock `protobuf:"bytes,3,rep,name=scheduled_blocks,json=scheduledBlocks,proto3" json:"scheduled_blocks,omitempty"`
+}
+
+func (x *LabelRewriteJobState) Reset() {
+	*x = LabelRewriteJobState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelRewriteJobState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelRewriteJobState) ProtoMessage() {}
+
+func (x *LabelRewriteJobState) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelRewriteJobState.ProtoReflect.Descriptor instead.
+func (*LabelRewriteJobState) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LabelRewriteJobState) GetJob() *v1.LabelRewriteJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *LabelRewriteJobState) GetPendingBlocks() []*LabelRewriteBlock {
+	if x != nil {
+		return x.PendingBlocks
+	}
+	return nil
+}
+
+func (x *LabelRewriteJobState) GetScheduledBlocks() []*LabelRewriteBlock {
+	if x != nil {
+		return x.ScheduledBlocks
+	}
+	return nil
+}
+
+type LabelRewriteBlock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Shard           uint32 `protobuf:"varint,2,opt,name=shard,proto3" json:"shard,omitempty"`
+	CompactionLevel uint32 `protobuf:"varint,3,opt,name=compaction_level,json=compactionLevel,proto3" json:"compaction_level,omitempty"`
+	// Name of the compaction job; only set for scheduled blocks.
+	CompactionJob string `protobuf:"bytes,4,opt,name=compaction_job,json=compactionJob,proto3" json:"compaction_job,omitempty"`
+}
+
+func (x *LabelRewriteBlock) Reset() {
+	*x = LabelRewriteBlock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelRewriteBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelRewriteBlock) ProtoMessage() {}
+
+func (x *LabelRewriteBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelRewriteBlock.ProtoReflect.Descriptor instead.
+func (*LabelRewriteBlock) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LabelRewriteBlock) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LabelRewriteBlock) GetShard() uint32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *LabelRewriteBlock) GetCompactionLevel() uint32 {
+	if x != nil {
+		return x.CompactionLevel
+	}
+	return 0
+}
+
+func (x *LabelRewriteBlock) GetCompactionJob() string {
+	if x != nil {
+		return x.CompactionJob
+	}
+	return ""
+}
+
+type AddAnnotationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tenant     string          `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Annotation *v11.Annotation `protobuf:"bytes,2,opt,name=annotation,proto3" json:"annotation,omitempty"`
+}
+
+func (x *AddAnnotationRequest) Reset() {
+	*x = AddAnnotationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddAnnotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAnnotationRequest) ProtoMessage() {}
+
+func (x *AddAnnotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAnnotationRequest.ProtoReflect.Descriptor instead.
+func (*AddAnnotationRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddAnnotationRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *AddAnnotationRequest) GetAnnotation() *v11.Annotation {
+	if x != nil {
+		return x.Annotation
+	}
+	return nil
+}
+
+type AddAnnotationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Annotation *v11.Annotation `protobuf:"bytes,1,opt,name=annotation,proto3" json:"annotation,omitempty"`
+}
+
+func (x *AddAnnotationResponse) Reset() {
+	*x = AddAnnotationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddAnnotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAnnotationResponse) ProtoMessage() {}
+
+func (x *AddAnnotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAnnotationResponse.ProtoReflect.Descriptor instead.
+func (*AddAnnotationResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddAnnotationResponse) GetAnnotation() *v11.Annotation {
+	if x != nil {
+		return x.Annotation
+	}
+	return nil
+}
+
+type QuarantineBlockRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockId    string `protobuf:"bytes,1,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+	Shard      uint32 `protobuf:"varint,2,opt,name=shard,proto3" json:"shard,omitempty"`
+	TenantId   string `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Reason     string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReportedBy string `protobuf:"bytes,5,opt,name=reported_by,json=reportedBy,proto3" json:"reported_by,omitempty"`
+}
+
+func (x *QuarantineBlockRequest) Reset() {
+	*x = QuarantineBlockRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuarantineBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantineBlockRequest) ProtoMessage() {}
+
+func (x *QuarantineBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantineBlockRequest.ProtoReflect.Descriptor instead.
+func (*QuarantineBlockRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *QuarantineBlockRequest) GetBlockId() string {
+	if x != nil {
+		return x.BlockId
+	}
+	return ""
+}
+
+func (x *QuarantineBlockRequest) GetShard() uint32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *QuarantineBlockRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *QuarantineBlockRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *QuarantineBlockRequest) GetReportedBy() string {
+	if x != nil {
+		return x.ReportedBy
+	}
+	return ""
+}
+
+type QuarantineBlockResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Nil if the block is not found.
+	Block *v1.BlockMeta `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (x *QuarantineBlockResponse) Reset() {
+	*x = QuarantineBlockResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuarantineBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantineBlockResponse) ProtoMessage() {}
+
+func (x *QuarantineBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantineBlockResponse.ProtoReflect.Descriptor instead.
+func (*QuarantineBlockResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *QuarantineBlockResponse) GetBlock() *v1.BlockMeta {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+// ArchivePartitionRequest replaces the index partition stored in the
+// database with its archive, uploaded to the object storage beforehand.
+type ArchivePartitionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Partition *ArchivedPartition `protobuf:"bytes,1,opt,name=partition,proto3" json:"partition,omitempty"`
+}
+
+func (x *ArchivePartitionRequest) Reset() {
+	*x = ArchivePartitionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchivePartitionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePartitionRequest) ProtoMessage() {}
+
+func (x *ArchivePartitionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePartitionRequest.ProtoReflect.Descriptor instead.
+func (*ArchivePartitionRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ArchivePartitionRequest) GetPartition() *ArchivedPartition {
+	if x != nil {
+		return x.Partition
+	}
+	return nil
+}
+
+type ArchivePartitionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// False if the partition has not been archived: e.g., if it has
+	// changed since the archive was created, or is already archived.
+	Archived bool `protobuf:"varint,1,opt,name=archived,proto3" json:"archived,omitempty"`
+}
+
+func (x *ArchivePartitionResponse) Reset() {
+	*x = ArchivePartitionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchivePartitionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePartitionResponse) ProtoMessage() {}
+
+func (x *ArchivePartitionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePartitionResponse.ProtoReflect.Descriptor instead.
+func (*ArchivePartitionResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ArchivePartitionResponse) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+// ArchivedPartition is the stub of an index partition
+// whose blocks have been moved to the object storage.
+type ArchivedPartition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Path of the archive object in the storage bucket.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Tenants of the partition blocks. Blocks that include
+	// data of multiple tenants are listed under empty tenant.
+	Tenants []string `protobuf:"bytes,3,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	Blocks  uint32   `protobuf:"varint,4,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	// Size and checksum (xxhash) of the archive object.
+	Size     uint64 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Checksum uint64 `protobuf:"varint,6,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// Time range of the data of the partition blocks, in milliseconds.
+	// Zero if the partition was archived before the range was recorded.
+	MinTime int64 `protobuf:"varint,7,opt,name=min_time,json=minTime,proto3" json:"min_time,omitempty"`
+	MaxTime int64 `protobuf:"varint,8,opt,name=max_time,json=maxTime,proto3" json:"max_time,omitempty"`
+	// Stats of the partition blocks by tenant, ordered by tenant.
+	TenantStats []*v1.TenantBlockStats `protobuf:"bytes,9,rep,name=tenant_stats,json=tenantStats,proto3" json:"tenant_stats,omitempty"`
+	// References of the partition blocks to the shared symbols
+	// dictionaries, released when the partition is deleted.
+	Dictionaries []*ArchivedDictionaryRefs `protobuf:"bytes,10,rep,name=dictionaries,proto3" json:"dictionaries,omitempty"`
+}
+
+func (x *ArchivedPartition) Reset() {
+	*x = ArchivedPartition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchivedPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivedPartition) ProtoMessage() {}
+
+func (x *ArchivedPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivedPartition.ProtoReflect.Descriptor instead.
+func (*ArchivedPartition) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ArchivedPartition) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ArchivedPartition) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ArchivedPartition) GetTenants() []string {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+func (x *ArchivedPartition) GetBlocks() uint32 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+func (x *ArchivedPartition) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ArchivedPartition) GetChecksum() uint64 {
+	if x != nil {
+		return x.Checksum
+	}
+	return 0
+}
+
+func (x *ArchivedPartition) GetMinTime() int64 {
+	if x != nil {
+		return x.MinTime
+	}
+	return 0
+}
+
+func (x *ArchivedPartition) GetMaxTime() int64 {
+	if x != nil {
+		return x.MaxTime
+	}
+	return 0
+}
+
+func (x *ArchivedPartition) GetTenantStats() []*v1.TenantBlockStats {
+	if x != nil {
+		return x.TenantStats
+	}
+	return nil
+}
+
+func (x *ArchivedPartition) GetDictionaries() []*ArchivedDictionaryRefs {
+	if x != nil {
+		return x.Dictionaries
+	}
+	return nil
+}
+
+type ArchivedDictionaryRefs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shard  uint32 `protobuf:"varint,1,opt,name=shard,proto3" json:"shard,omitempty"`
+	Tenant string `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Path   string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// Number of the partition blocks referring to the dictionary.
+	Blocks uint32 `protobuf:"varint,4,opt,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *ArchivedDictionaryRefs) Reset() {
+	*x = ArchivedDictionaryRefs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchivedDictionaryRefs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivedDictionaryRefs) ProtoMessage() {}
+
+func (x *ArchivedDictionaryRefs) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivedDictionaryRefs.ProtoReflect.Descriptor instead.
+func (*ArchivedDictionaryRefs) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ArchivedDictionaryRefs) GetShard() uint32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *ArchivedDictionaryRefs) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ArchivedDictionaryRefs) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ArchivedDictionaryRefs) GetBlocks() uint32 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+// PartitionArchive is the content of the archive object.
+type PartitionArchive struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Blocks ordered by shard, tenant, and identifier.
+	Blocks []*v1.BlockMeta `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *PartitionArchive) Reset() {
+	*x = PartitionArchive{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PartitionArchive) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartitionArchive) ProtoMessage() {}
+
+func (x *PartitionArchive) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartitionArchive.ProtoReflect.Descriptor instead.
+func (*PartitionArchive) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PartitionArchive) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PartitionArchive) GetBlocks() []*v1.BlockMeta {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+// MergePartitionsRequest moves the blocks of the source index partitions
+// to the target partition, which covers the time periods of all of them.
+// The source partitions are deleted.
+type MergePartitionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source []string `protobuf:"bytes,1,rep,name=source,proto3" json:"source,omitempty"`
+	Target string   `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+}
+
+func (x *MergePartitionsRequest) Reset() {
+	*x = MergePartitionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MergePartitionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePartitionsRequest) ProtoMessage() {}
+
+func (x *MergePartitionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePartitionsRequest.ProtoReflect.Descriptor instead.
+func (*MergePartitionsRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *MergePartitionsRequest) GetSource() []string {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *MergePartitionsRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type MergePartitionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Source partitions that can't be merged, e.g., those
+	// archived in the meantime, are skipped.
+	Merged []string `protobuf:"bytes,1,rep,name=merged,proto3" json:"merged,omitempty"`
+	Blocks uint32   `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *MergePartitionsResponse) Reset() {
+	*x = MergePartitionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MergePartitionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePartitionsResponse) ProtoMessage() {}
+
+func (x *MergePartitionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePartitionsResponse.ProtoReflect.Descriptor instead.
+func (*MergePartitionsResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *MergePartitionsResponse) GetMerged() []string {
+	if x != nil {
+		return x.Merged
+	}
+	return nil
+}
+
+func (x *MergePartitionsResponse) GetBlocks() uint32 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+// SplitPartitionRequest moves the blocks of the source index partition
+// to the partitions of the given duration the blocks belong to, which
+// cover the time period of the source partition. The source partition
+// is deleted.
+type SplitPartitionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// Duration of the target partitions, in milliseconds.
+	Duration int64 `protobuf:"varint,2,opt,name=duration,proto3" json:"duration,omitempty"`
+}
+
+func (x *SplitPartitionRequest) Reset() {
+	*x = SplitPartitionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SplitPartitionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitPartitionRequest) ProtoMessage() {}
+
+func (x *SplitPartitionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitPartitionRequest.ProtoReflect.Descriptor instead.
+func (*SplitPartitionRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SplitPartitionRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *SplitPartitionRequest) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+type SplitPartitionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Target partitions the blocks have been moved to. Empty if
+	// the partition can't be split, e.g., if it has been archived
+	// in the meantime.
+	Target []string `protobuf:"bytes,1,rep,name=target,proto3" json:"target,omitempty"`
+	Blocks uint32   `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *SplitPartitionResponse) Reset() {
+	*x = SplitPartitionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SplitPartitionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitPartitionResponse) ProtoMessage() {}
+
+func (x *SplitPartitionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitPartitionResponse.ProtoReflect.Descriptor instead.
+func (*SplitPartitionResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SplitPartitionResponse) GetTarget() []string {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *SplitPartitionResponse) GetBlocks() uint32 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+type QuarantineWriterRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WriterId   string `protobuf:"bytes,1,opt,name=writer_id,json=writerId,proto3" json:"writer_id,omitempty"`
+	Reason     string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReportedBy string `protobuf:"bytes,3,opt,name=reported_by,json=reportedBy,proto3" json:"reported_by,omitempty"`
+}
+
+func (x *QuarantineWriterRequest) Reset() {
+	*x = QuarantineWriterRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuarantineWriterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantineWriterRequest) ProtoMessage() {}
+
+func (x *QuarantineWriterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantineWriterRequest.ProtoReflect.Descriptor instead.
+func (*QuarantineWriterRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *QuarantineWriterRequest) GetWriterId() string {
+	if x != nil {
+		return x.WriterId
+	}
+	return ""
+}
+
+func (x *QuarantineWriterRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *QuarantineWriterRequest) GetReportedBy() string {
+	if x != nil {
+		return x.ReportedBy
+	}
+	return ""
+}
+
+type QuarantineWriterResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Quarantine *v1.WriterQuarantine `protobuf:"bytes,1,opt,name=quarantine,proto3" json:"quarantine,omitempty"`
+}
+
+func (x *QuarantineWriterResponse) Reset() {
+	*x = QuarantineWriterResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuarantineWriterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantineWriterResponse) ProtoMessage() {}
+
+func (x *QuarantineWriterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantineWriterResponse.ProtoReflect.Descriptor instead.
+func (*QuarantineWriterResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *QuarantineWriterResponse) GetQuarantine() *v1.WriterQuarantine {
+	if x != nil {
+		return x.Quarantine
+	}
+	return nil
+}
+
+type ReleaseWriterRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WriterId string `protobuf:"bytes,1,opt,name=writer_id,json=writerId,proto3" json:"writer_id,omitempty"`
+}
+
+func (x *ReleaseWriterRequest) Reset() {
+	*x = ReleaseWriterRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseWriterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseWriterRequest) ProtoMessage() {}
+
+func (x *ReleaseWriterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseWriterRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseWriterRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReleaseWriterRequest) GetWriterId() string {
+	if x != nil {
+		return x.WriterId
+	}
+	return ""
+}
+
+type ReleaseWriterResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// False if the writer is not quarantined.
+	Released bool `protobuf:"varint,1,opt,name=released,proto3" json:"released,omitempty"`
+}
+
+func (x *ReleaseWriterResponse) Reset() {
+	*x = ReleaseWriterResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseWriterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseWriterResponse) ProtoMessage() {}
+
+func (x *ReleaseWriterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseWriterResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseWriterResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ReleaseWriterResponse) GetReleased() bool {
+	if x != nil {
+		return x.Released
+	}
+	return false
+}
+
+// SweepDeletedBlocksRequest removes the blocks deleted from the index
+// before the given time. The time is decided by the leader, according
+// to the deletion grace period.
+type SweepDeletedBlocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Milliseconds since epoch.
+	DeletedBefore int64 `protobuf:"varint,1,opt,name=deleted_before,json=deletedBefore,proto3" json:"deleted_before,omitempty"`
+	// Maximum number of blocks to remove, which limits
+	// the size of the transaction. 0 means no limit.
+	MaxBlocks uint32 `protobuf:"varint,2,opt,name=max_blocks,json=maxBlocks,proto3" json:"max_blocks,omitempty"`
+}
+
+func (x *SweepDeletedBlocksRequest) Reset() {
+	*x = SweepDeletedBlocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SweepDeletedBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepDeletedBlocksRequest) ProtoMessage() {}
+
+func (x *SweepDeletedBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepDeletedBlocksRequest.ProtoReflect.Descriptor instead.
+func (*SweepDeletedBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SweepDeletedBlocksRequest) GetDeletedBefore() int64 {
+	if x != nil {
+		return x.DeletedBefore
+	}
+	return 0
+}
+
+func (x *SweepDeletedBlocksRequest) GetMaxBlocks() uint32 {
+	if x != nil {
+		return x.MaxBlocks
+	}
+	return 0
+}
+
+type SweepDeletedBlocksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Removed uint32 `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+}
+
+func (x *SweepDeletedBlocksResponse) Reset() {
+	*x = SweepDeletedBlocksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SweepDeletedBlocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepDeletedBlocksResponse) ProtoMessage() {}
+
+func (x *SweepDeletedBlocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepDeletedBlocksResponse.ProtoReflect.Descriptor instead.
+func (*SweepDeletedBlocksResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SweepDeletedBlocksResponse) GetRemoved() uint32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+// DeletePartitionsRequest removes the blocks of the tenant from the index
+// partitions that ended before the given time, as the tenant retention
+// period has passed. The block objects are deleted by the compaction
+// workers, as the blocks are tombstoned. Archived partitions are deleted
+// as a whole, along with the blocks including data of multiple tenants.
+type DeletePartitionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Empty if the blocks including data of multiple tenants, and the
+	// archived partitions, are deleted.
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Milliseconds since epoch.
+	Before int64 `protobuf:"varint,2,opt,name=before,proto3" json:"before,omitempty"`
+}
+
+func (x *DeletePartitionsRequest) Reset() {
+	*x = DeletePartitionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePartitionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePartitionsRequest) ProtoMessage() {}
+
+func (x *DeletePartitionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePartitionsRequest.ProtoReflect.Descriptor instead.
+func (*DeletePartitionsRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DeletePartitionsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DeletePartitionsRequest) GetBefore() int64 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+type DeletePartitionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Partitions deleted entirely: no blocks are left,
+	// or the archived partition has been deleted.
+	Deleted []string `protobuf:"bytes,1,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	// The number of blocks removed from the partitions.
+	Blocks uint32 `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *DeletePartitionsResponse) Reset() {
+	*x = DeletePartitionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePartitionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePartitionsResponse) ProtoMessage() {}
+
+func (x *DeletePartitionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePartitionsResponse.ProtoReflect.Descriptor instead.
+func (*DeletePartitionsResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeletePartitionsResponse) GetDeleted() []string {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+func (x *DeletePartitionsResponse) GetBlocks() uint32 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+// RepairIndexRequest checks the index partitions in memory against the
+// database, and resolves the issues found. The repair is deterministic:
+// each replica resolves the issues of its own state according to the
+// database, which is identical on all the replicas.
+type RepairIndexRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RepairIndexRequest) Reset() {
+	*x = RepairIndexRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RepairIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepairIndexRequest) ProtoMessage() {}
+
+func (x *RepairIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepairIndexRequest.ProtoReflect.Descriptor instead.
+func (*RepairIndexRequest) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{39}
+}
+
+type RepairIndexResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Issues found on the replica, before the repair.
+	Issues     []*v1.IndexIssue `protobuf:"bytes,1,rep,name=issues,proto3" json:"issues,omitempty"`
+	Partitions uint32           `protobuf:"varint,2,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	Blocks     uint64           `protobuf:"varint,3,opt,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *RepairIndexResponse) Reset() {
+	*x = RepairIndexResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RepairIndexResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepairIndexResponse) ProtoMessage() {}
+
+func (x *RepairIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metastore_v1_raft_log_raft_log_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepairIndexResponse.ProtoReflect.Descriptor instead.
+func (*RepairIndexResponse) Descriptor() ([]byte, []int) {
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RepairIndexResponse) GetIssues() []*v1.IndexIssue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+func (x *RepairIndexResponse) GetPartitions() uint32 {
+	if x != nil {
+		return x.Partitions
+	}
+	return 0
+}
+
+func (x *RepairIndexResponse) GetBlocks() uint64 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+var File_metastore_v1_raft_log_raft_log_proto protoreflect.FileDescriptor
+
+var file_metastore_v1_raft_log_raft_log_proto_rawDesc = []byte{
+	0x0a, 0x24, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x72,
+	0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2f, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67,
+	0x1a, 0x1c, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x63,
+	0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18,
+	0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f, 0x72, 0x65, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x6d, 0x65, 0x74, 0x61,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x14, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4e, 0x0a, 0x17, 0x41, 0x64, 0x64, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x1a, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x94, 0x01, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x4a, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x72,
+	0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x5f, 0x6a, 0x6f, 0x62, 0x73, 0x5f,
+	0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x61, 0x73, 0x73, 0x69, 0x67,
+	0x6e, 0x4a, 0x6f, 0x62, 0x73, 0x4d, 0x61, 0x78, 0x22, 0x80, 0x01, 0x0a, 0x19, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x12, 0x39, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x21, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x76, 0x0a, 0x1f, 0x47,
+	0x65, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x65,
+	0x72, 0x6d, 0x12, 0x3f, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c,
+	0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61,
+	0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x22, 0x9f, 0x02, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x35, 0x0a, 0x08,
+	0x6e, 0x65, 0x77, 0x5f, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x4e, 0x65, 0x77, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x4a,
+	0x6f, 0x62, 0x73, 0x12, 0x44, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f,
+	0x6a, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x72, 0x61, 0x66,
+	0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x6f,
+	0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x0c, 0x61, 0x73, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x41, 0x0a, 0x0c, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52,
+	0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x47, 0x0a, 0x0e,
+	0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e,
+	0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x4a, 0x6f, 0x62, 0x73, 0x22, 0x77, 0x0a, 0x10, 0x4e, 0x65, 0x77, 0x43, 0x6f, 0x6d, 0x70,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f,
+	0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f,
+	0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a,
+	0x04, 0x70, 0x6c, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x61,
+	0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4a, 0x6f, 0x62, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x22, 0x7c,
+	0x0a, 0x15, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f,
+	0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x70,
+	0x6c, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x61, 0x66, 0x74,
+	0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a,
+	0x6f, 0x62, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x22, 0x4a, 0x0a, 0x14,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43,
+	0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x96, 0x01, 0x0a, 0x16, 0x43, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f,
+	0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x48, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x61,
+	0x63, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x22, 0x85, 0x02, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10,
+	0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x39, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x28, 0x0a, 0x10, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73,
+	0x41, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x22, 0xa0, 0x02, 0x0a, 0x11, 0x43, 0x6f,
+	0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x50, 0x6c, 0x61, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x68, 0x61, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72,
+	0x64, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x63, 0x6f, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x23, 0x0a, 0x0d,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x12, 0x38, 0x0a, 0x0a, 0x74, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x73, 0x52,
+	0x0a, 0x74, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0d, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x5f, 0x72, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x52, 0x0c,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x22, 0x72, 0x0a, 0x1b,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x65, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x12,
+	0x3f, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e,
+	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x22, 0x5f, 0x0a, 0x1c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3f, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67,
+	0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x22, 0x4f, 0x0a, 0x1c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x2f, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x03, 0x6a,
+	0x6f, 0x62, 0x22, 0x50, 0x0a, 0x1d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52,
+	0x03, 0x6a, 0x6f, 0x62, 0x22, 0xd3, 0x01, 0x0a, 0x14, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65,
+	0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a,
+	0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x74,
+	0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52,
+	0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x42,
+	0x0a, 0x0e, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f,
+	0x67, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x52, 0x0d, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x12, 0x46, 0x0a, 0x10, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x5f,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72,
+	0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0f, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x8b, 0x01, 0x0a, 0x11, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6a, 0x6f, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x22, 0x64, 0x0a, 0x14, 0x41, 0x64, 0x64, 0x41,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x0a, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0a, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4d,
+	0x0a, 0x15, 0x41, 0x64, 0x64, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x0a, 0x61, 0x6e, 0x6e, 0x6f, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0a, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x9f, 0x01,
+	0x0a, 0x16, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65,
+	0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1f,
+	0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x22,
+	0x48, 0x0a, 0x17, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x65, 0x74, 0x61,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65,
+	0x74, 0x61, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x54, 0x0a, 0x17, 0x41, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c,
+	0x6f, 0x67, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x50, 0x61, 0x72, 0x74, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x36, 0x0a, 0x18, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61,
+	0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61,
+	0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x22, 0xda, 0x02, 0x0a, 0x11, 0x41, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x64, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x69, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x41, 0x0a, 0x0c, 0x74,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x0b, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x44,
+	0x0a, 0x0c, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e,
+	0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x44, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x72, 0x79, 0x52, 0x65, 0x66, 0x73, 0x52, 0x0c, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x72, 0x69, 0x65, 0x73, 0x22, 0x72, 0x0a, 0x16, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64,
+	0x44, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x72, 0x79, 0x52, 0x65, 0x66, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73,
+	0x68, 0x61, 0x72, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x16, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x55, 0x0a, 0x10, 0x50, 0x61, 0x72, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f,
+	0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22,
+	0x48, 0x0a, 0x16, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x49, 0x0a, 0x17, 0x4d, 0x65, 0x72,
+	0x67, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x73, 0x22, 0x4b, 0x0a, 0x15, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x50, 0x61, 0x72,
+	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x48, 0x0a, 0x16, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x6f, 0x0a, 0x17, 0x51,
+	0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x57, 0x72, 0x69, 0x74, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x22, 0x5a, 0x0a, 0x18,
+	0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x57, 0x72, 0x69, 0x74, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0a, 0x71, 0x75, 0x61, 0x72,
+	0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d,
+	0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x72, 0x69, 0x74,
+	0x65, 0x72, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x52, 0x0a, 0x71, 0x75,
+	0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x22, 0x33, 0x0a, 0x14, 0x52, 0x65, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x57, 0x72, 0x69, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1b, 0x0a, 0x09, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x49, 0x64, 0x22, 0x33, 0x0a,
+	0x15, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x57, 0x72, 0x69, 0x74, 0x65, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x64, 0x22, 0x61, 0x0a, 0x19, 0x53, 0x77, 0x65, 0x65, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x25, 0x0a, 0x0e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64,
+	0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x36, 0x0a, 0x1a, 0x53, 0x77, 0x65, 0x65, 0x70, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0x4e, 0x0a,
+	0x17, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x65, 0x6e, 0x61,
+	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x22, 0x4c, 0x0a,
+	0x18, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x14, 0x0a, 0x12, 0x52,
+	0x65, 0x70, 0x61, 0x69, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0x7f, 0x0a, 0x13, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x49, 0x73, 0x73,
+	0x75, 0x65, 0x52, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x2a, 0xc6, 0x04, 0x0a, 0x0b, 0x52, 0x61, 0x66, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41,
+	0x4e, 0x44, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x23, 0x0a, 0x1f,
+	0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x41, 0x44, 0x44,
+	0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x4d, 0x45, 0x54, 0x41, 0x44, 0x41, 0x54, 0x41, 0x10,
+	0x01, 0x12, 0x2b, 0x0a, 0x27, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e,
+	0x44, 0x5f, 0x47, 0x45, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x50, 0x4c, 0x41, 0x4e, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x02, 0x12, 0x27,
+	0x0a, 0x23, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x55,
+	0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x50, 0x4c, 0x41, 0x4e, 0x10, 0x03, 0x12, 0x29, 0x0a, 0x25, 0x52, 0x41, 0x46, 0x54, 0x5f,
+	0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x5f, 0x4c,
+	0x41, 0x42, 0x45, 0x4c, 0x5f, 0x52, 0x45, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x4a, 0x4f, 0x42,
+	0x10, 0x04, 0x12, 0x1f, 0x0a, 0x1b, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41,
+	0x4e, 0x44, 0x5f, 0x41, 0x44, 0x44, 0x5f, 0x41, 0x4e, 0x4e, 0x4f, 0x54, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x10, 0x05, 0x12, 0x21, 0x0a, 0x1d, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d,
+	0x41, 0x4e, 0x44, 0x5f, 0x51, 0x55, 0x41, 0x52, 0x41, 0x4e, 0x54, 0x49, 0x4e, 0x45, 0x5f, 0x42,
+	0x4c, 0x4f, 0x43, 0x4b, 0x10, 0x06, 0x12, 0x22, 0x0a, 0x1e, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43,
+	0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x41, 0x52, 0x43, 0x48, 0x49, 0x56, 0x45, 0x5f, 0x50,
+	0x41, 0x52, 0x54, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x07, 0x12, 0x21, 0x0a, 0x1d, 0x52, 0x41,
+	0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x4d, 0x45, 0x52, 0x47, 0x45,
+	0x5f, 0x50, 0x41, 0x52, 0x54, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x08, 0x12, 0x22, 0x0a,
+	0x1e, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x51, 0x55,
+	0x41, 0x52, 0x41, 0x4e, 0x54, 0x49, 0x4e, 0x45, 0x5f, 0x57, 0x52, 0x49, 0x54, 0x45, 0x52, 0x10,
+	0x09, 0x12, 0x1f, 0x0a, 0x1b, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e,
+	0x44, 0x5f, 0x52, 0x45, 0x4c, 0x45, 0x41, 0x53, 0x45, 0x5f, 0x57, 0x52, 0x49, 0x54, 0x45, 0x52,
+	0x10, 0x0a, 0x12, 0x25, 0x0a, 0x21, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41,
+	0x4e, 0x44, 0x5f, 0x53, 0x57, 0x45, 0x45, 0x50, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44,
+	0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x53, 0x10, 0x0b, 0x12, 0x20, 0x0a, 0x1c, 0x52, 0x41, 0x46,
+	0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x53, 0x50, 0x4c, 0x49, 0x54, 0x5f,
+	0x50, 0x41, 0x52, 0x54, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x0c, 0x12, 0x1b, 0x0a, 0x17, 0x52,
+	0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x41, 0x44, 0x44, 0x5f,
+	0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x53, 0x10, 0x0d, 0x12, 0x22, 0x0a, 0x1e, 0x52, 0x41, 0x46, 0x54,
+	0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x5f,
+	0x50, 0x41, 0x52, 0x54, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x0e, 0x12, 0x1d, 0x0a, 0x19,
+	0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x52, 0x45, 0x50,
+	0x41, 0x49, 0x52, 0x5f, 0x49, 0x4e, 0x44, 0x45, 0x58, 0x10, 0x0f, 0x42, 0x9d, 0x01, 0x0a, 0x0c,
+	0x63, 0x6f, 0x6d, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x42, 0x0c, 0x52, 0x61,
+	0x66, 0x74, 0x4c, 0x6f, 0x67, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x43, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x61, 0x66, 0x61, 0x6e, 0x61,
+	0x2f, 0x70, 0x79, 0x72, 0x6f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67,
+	0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x61,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f,
+	0x67, 0xa2, 0x02, 0x03, 0x52, 0x58, 0x58, 0xaa, 0x02, 0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f,
+	0x67, 0xca, 0x02, 0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0xe2, 0x02, 0x13, 0x52, 0x61,
+	0x66, 0x74, 0x4c, 0x6f, 0x67, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0xea, 0x02, 0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_metastore_v1_raft_log_raft_log_proto_rawDescOnce sync.Once
+	file_metastore_v1_raft_log_raft_log_proto_rawDescData = file_metastore_v1_raft_log_raft_log_proto_rawDesc
+)
+
+func file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP() []byte {
+	file_metastore_v1_raft_log_raft_log_proto_rawDescOnce.Do(func() {
+		file_metastore_v1_raft_log_raft_log_proto_rawDescData = protoimpl.X.CompressGZIP(file_metastore_v1_raft_log_raft_log_proto_rawDescData)
+	})
+	return file_metastore_v1_raft_log_raft_log_proto_rawDescData
+}
+
+var file_metastore_v1_raft_log_raft_log_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_metastore_v1_raft_log_raft_log_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
+var file_metastore_v1_raft_log_raft_log_proto_goTypes = []any{
+	(RaftCommand)(0),                        // 0: raft_log.RaftCommand
+	(*AddBlockMetadataRequest)(nil),         // 1: raft_log.AddBlockMetadataRequest
+	(*AddBlockMetadataResponse)(nil),        // 2: raft_log.AddBlockMetadataResponse
+	(*GetCompactionPlanUpdateRequest)(nil),  // 3: raft_log.GetCompactionPlanUpdateRequest
+	(*CompactionJobStatusUpdate)(nil),       // 4: raft_log.CompactionJobStatusUpdate
+	(*GetCompactionPlanUpdateResponse)(nil), // 5: raft_log.GetCompactionPlanUpdateResponse
+	(*CompactionPlanUpdate)(nil),            // 6: raft_log.CompactionPlanUpdate
+	(*NewCompactionJob)(nil),                // 7: raft_log.NewCompactionJob
+	(*AssignedCompactionJob)(nil),           // 8: raft_log.AssignedCompactionJob
+	(*UpdatedCompactionJob)(nil),            // 9: raft_log.UpdatedCompactionJob
+	(*CompletedCompactionJob)(nil),          // 10: raft_log.CompletedCompactionJob
+	(*CompactionJobState)(nil),              // 11: raft_log.CompactionJobState
+	(*CompactionJobPlan)(nil),               // 12: raft_log.CompactionJobPlan
+	(*UpdateCompactionPlanRequest)(nil),     // 13: raft_log.UpdateCompactionPlanRequest
+	(*UpdateCompactionPlanResponse)(nil),    // 14: raft_log.UpdateCompactionPlanResponse
+	(*CreateLabelRewriteJobRequest)(nil),    // 15: raft_log.CreateLabelRewriteJobRequest
+	(*CreateLabelRewriteJobResponse)(nil),   // 16: raft_log.CreateLabelRewriteJobResponse
+	(*LabelRewriteJobState)(nil),            // 17: raft_log.LabelRewriteJobState
+	(*LabelRewriteBlock)(nil),               // 18: raft_log.LabelRewriteBlock
+	(*AddAnnotationRequest)(nil),            // 19: raft_log.AddAnnotationRequest
+	(*AddAnnotationResponse)(nil),           // 20: raft_log.AddAnnotationResponse
+	(*QuarantineBlockRequest)(nil),          // 21: raft_log.QuarantineBlockRequest
+	(*QuarantineBlockResponse)(nil),         // 22: raft_log.QuarantineBlockResponse
+	(*ArchivePartitionRequest)(nil),         // 23: raft_log.ArchivePartitionRequest
+	(*ArchivePartitionResponse)(nil),        // 24: raft_log.ArchivePartitionResponse
+	(*ArchivedPartition)(nil),               // 25: raft_log.ArchivedPartition
+	(*ArchivedDictionaryRefs)(nil),          // 26: raft_log.ArchivedDictionaryRefs
+	(*PartitionArchive)(nil),                // 27: raft_log.PartitionArchive
+	(*MergePartitionsRequest)(nil),          // 28: raft_log.MergePartitionsRequest
+	(*MergePartitionsResponse)(nil),         // 29: raft_log.MergePartitionsResponse
+	(*SplitPartitionRequest)(nil),           // 30: raft_log.SplitPartitionRequest
+	(*SplitPartitionResponse)(nil),          // 31: raft_log.SplitPartitionResponse
+	(*QuarantineWriterRequest)(nil),         // 32: raft_log.QuarantineWriterRequest
+	(*QuarantineWriterResponse)(nil),        // 33: raft_log.QuarantineWriterResponse
+	(*ReleaseWriterRequest)(nil),            // 34: raft_log.ReleaseWriterRequest
+	(*ReleaseWriterResponse)(nil),           // 35: raft_log.ReleaseWriterResponse
+	(*SweepDeletedBlocksRequest)(nil),       // 36: raft_log.SweepDeletedBlocksRequest
+	(*SweepDeletedBlocksResponse)(nil),      // 37: raft_log.SweepDeletedBlocksResponse
+	(*DeletePartitionsRequest)(nil),         // 38: raft_log.DeletePartitionsRequest
+	(*DeletePartitionsResponse)(nil),        // 39: raft_log.DeletePartitionsResponse
+	(*RepairIndexRequest)(nil),              // 40: raft_log.RepairIndexRequest
+	(*RepairIndexResponse)(nil),             // 41: raft_log.RepairIndexResponse
+	(*v1.BlockMeta)(nil),                    // 42: metastore.v1.BlockMeta
+	(v1.CompactionJobStatus)(0),             // 43: metastore.v1.CompactionJobStatus
+	(*v1.CompactedBlocks)(nil),              // 44: metastore.v1.CompactedBlocks
+	(*v1.Tombstones)(nil),                   // 45: metastore.v1.Tombstones
+	(*v1.LabelRewrite)(nil),                 // 46: metastore.v1.LabelRewrite
+	(*v1.LabelRewriteJob)(nil),              // 47: metastore.v1.LabelRewriteJob
+	(*v11.Annotation)(nil),                  // 48: types.v1.Annotation
+	(*v1.TenantBlockStats)(nil),             // 49: metastore.v1.TenantBlockStats
+	(*v1.WriterQuarantine)(nil),             // 50: metastore.v1.WriterQuarantine
+	(*v1.IndexIssue)(nil),                   // 51: metastore.v1.IndexIssue
+}
+var file_metastore_v1_raft_log_raft_log_proto_depIdxs = []int32{
+	42, // 0: raft_log.AddBlockMetadataRequest.metadata:type_name -> metastore.v1.BlockMeta
+	4,  // 1: raft_log.GetCompactionPlanUpdateRequest.status_updates:type_name -> raft_log.CompactionJobStatusUpdate
+	43, // 2: raft_log.CompactionJobStatusUpdate.status:type_name -> metastore.v1.CompactionJobStatus
+	6,  // 3: raft_log.GetCompactionPlanUpdateResponse.plan_update:type_name -> raft_log.CompactionPlanUpdate
+	7,  // 4: raft_log.CompactionPlanUpdate.new_jobs:type_name -> raft_log.NewCompactionJob
+	8,  // 5: raft_log.CompactionPlanUpdate.assigned_jobs:type_name -> raft_log.AssignedCompactionJob
+	9,  // 6: raft_log.CompactionPlanUpdate.updated_jobs:type_name -> raft_log.UpdatedCompactionJob
+	10, // 7: raft_log.CompactionPlanUpdate.completed_jobs:type_name -> raft_log.CompletedCompactionJob
+	11, // 8: raft_log.NewCompactionJob.state:type_name -> raft_log.CompactionJobState
+	12, // 9: raft_log.NewCompactionJob.plan:type_name -> raft_log.CompactionJobPlan
+	11, // 10: raft_log.AssignedCompactionJob.state:type_name -> raft_log.CompactionJobState
+	12, // 11: raft_log.AssignedCompactionJob.plan:type_name -> raft_log.CompactionJobPlan
+	11, // 12: raft_log.UpdatedCompactionJob.state:type_name -> raft_log.CompactionJobState
+	11, // 13: raft_log.CompletedCompactionJob.state:type_name -> raft_log.CompactionJobState
+	44, // 14: raft_log.CompletedCompactionJob.compacted_blocks:type_name -> metastore.v1.CompactedBlocks
+	43, // 15: raft_log.CompactionJobState.status:type_name -> metastore.v1.CompactionJobStatus
+	45, // 16: raft_log.CompactionJobPlan.tombstones:type_name -> metastore.v1.Tombstones
+	46, // 17: raft_log.CompactionJobPlan.label_rewrite:type_name -> metastore.v1.LabelRewrite
+	6,  // 18: raft_log.UpdateCompactionPlanRequest.plan_update:type_name -> raft_log.CompactionPlanUpdate
+	6,  // 19: raft_log.UpdateCompactionPlanResponse.plan_update:type_name -> raft_log.CompactionPlanUpdate
+	47, // 20: raft_log.CreateLabelRewriteJobRequest.job:type_name -> metastore.v1.LabelRewriteJob
+	47, // 21: raft_log.CreateLabelRewriteJobResponse.job:type_name -> metastore.v1.LabelRewriteJob
+	47, // 22: raft_log.LabelRewriteJobState.job:type_name -> metastore.v1.LabelRewriteJob
+	18, // 23: raft_log.LabelRewriteJobState.pending_blocks:type_name -> raft_log.LabelRewriteBlock
+	18, // 24: raft_log.LabelRewriteJobState.scheduled_blocks:type_name -> raft_log.LabelRewriteBlock
+	48, // 25: raft_log.AddAnnotationRequest.annotation:type_name -> types.v1.Annotation
+	48, // 26: raft_log.AddAnnotationResponse.annotation:type_name -> types.v1.Annotation
+	42, // 27: raft_log.QuarantineBlockResponse.block:type_name -> metastore.v1.BlockMeta
+	25, // 28: raft_log.ArchivePartitionRequest.partition:type_name -> raft_log.ArchivedPartition
+	49, // 29: raft_log.ArchivedPartition.tenant_stats:type_name -> metastore.v1.TenantBlockStats
+	26, // 30: raft_log.ArchivedPartition.dictionaries:type_name -> raft_log.ArchivedDictionaryRefs
+	42, // 31: raft_log.PartitionArchive.blocks:type_name -> metastore.v1.BlockMeta
+	50, // 32: raft_log.QuarantineWriterResponse.quarantine:type_name -> metastore.v1.WriterQuarantine
+	51, // 33: raft_log.RepairIndexResponse.issues:type_name -> metastore.v1.IndexIssue
+	34, // [34:34] is the sub-list for method output_type
+	34, // [34:34] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
+}
+
+func init() { file_metastore_v1_raft_log_raft_log_proto_init() }
+func file_metastore_v1_raft_log_raft_log_proto_init() {
+	if File_metastore_v1_raft_log_raft_log_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*AddBlockMetadataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*AddBlockMetadataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetCompactionPlanUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*CompactionJobStatusUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GetCompactionPlanUpdateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*CompactionPlanUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*NewCompactionJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*AssignedCompactionJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdatedCompactionJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*CompletedCompactionJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*CompactionJobState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CompactionJobPlan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateCompactionPlanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateCompactionPlanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateLabelRewriteJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateLabelRewriteJobResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*LabelRewriteJobState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*LabelRewriteBlock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*AddAnnotationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*AddAnnotationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UpdateCompactionPlanResponse.ProtoReflect.Descriptor instead.
-func (*UpdateCompactionPlanResponse) Descriptor() ([]byte, []int) {
-	return file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP(), []int{13}
-}
-
-func (x *UpdateCompactionPlanResponse) GetPlanUpdate() *CompactionPlanUpdate {
-	if x != nil {
-		return x.PlanUpdate
-	}
-	return nil
-}
-
-var File_metastore_v1_raft_log_raft_log_proto protoreflect.FileDescriptor
-
-var file_metastore_v1_raft_log_raft_log_proto_rawDesc = []byte{
-	0x0a, 0x24, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x72,
-	0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2f, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67,
-	0x1a, 0x1c, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x63,
-	0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18,
-	0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70,
-	0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4e, 0x0a, 0x17, 0x41, 0x64, 0x64, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72,
-	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x08,
-	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x1a, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x94, 0x01, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x70,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4a, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x23, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x5f, 0x6a, 0x6f,
-	0x62, 0x73, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x61, 0x73,
-	0x73, 0x69, 0x67, 0x6e, 0x4a, 0x6f, 0x62, 0x73, 0x4d, 0x61, 0x78, 0x22, 0x80, 0x01, 0x0a, 0x19,
-	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x74, 0x6f,
-	0x6b, 0x65, 0x6e, 0x12, 0x39, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e,
-	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x76,
-	0x0a, 0x1f, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50,
-	0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x04, 0x74, 0x65, 0x72, 0x6d, 0x12, 0x3f, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x75, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x61, 0x66,
-	0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x6e,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x22, 0x9f, 0x02, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12,
-	0x35, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x4e, 0x65, 0x77,
-	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x07, 0x6e,
-	0x65, 0x77, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x44, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e,
-	0x65, 0x64, 0x5f, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
-	0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65,
-	0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x0c,
-	0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x41, 0x0a, 0x0c,
-	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a,
-	0x6f, 0x62, 0x52, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x4a, 0x6f, 0x62, 0x73, 0x12,
-	0x47, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x6a, 0x6f, 0x62,
-	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c,
-	0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x6c,
-	0x65, 0x74, 0x65, 0x64, 0x4a, 0x6f, 0x62, 0x73, 0x22, 0x77, 0x0a, 0x10, 0x4e, 0x65, 0x77, 0x43,
-	0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05,
-	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61,
-	0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x12, 0x2f, 0x0a, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
-	0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x70, 0x6c, 0x61,
-	0x6e, 0x22, 0x7c, 0x0a, 0x15, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x6f, 0x6d,
-	0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74,
-	0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61, 0x66, 0x74,
-	0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a,
-	0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2f,
-	0x0a, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72,
-	0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x22,
-	0x4a, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f,
-	0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x96, 0x01, 0x0a, 0x16,
-	0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x32, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67,
-	0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x48, 0x0a, 0x10, 0x63, 0x6f,
-	0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f, 0x72, 0x65,
-	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f,
-	0x63, 0x6b, 0x73, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x73, 0x22, 0x85, 0x02, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x65,
-	0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x39, 0x0a, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x74,
-	0x61, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x28, 0x0a, 0x10, 0x6c,
-	0x65, 0x61, 0x73, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69,
-	0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x61,
-	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64, 0x41, 0x74,
-	0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x22, 0xdf, 0x01, 0x0a,
-	0x11, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x50, 0x6c,
-	0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x14,
-	0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73,
-	0x68, 0x61, 0x72, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f,
-	0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12,
-	0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
-	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x73, 0x12, 0x38, 0x0a, 0x0a, 0x74, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e,
-	0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x73,
-	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e,
-	0x65, 0x73, 0x52, 0x0a, 0x74, 0x6f, 0x6d, 0x62, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x73, 0x22, 0x72,
-	0x0a, 0x1b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
-	0x04, 0x74, 0x65, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x65, 0x72,
-	0x6d, 0x12, 0x3f, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f,
-	0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x22, 0x5f, 0x0a, 0x1c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x70,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c,
-	0x6f, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x61,
-	0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x6e, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x2a, 0xa2, 0x01, 0x0a, 0x0b, 0x52, 0x61, 0x66, 0x74, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d,
-	0x41, 0x4e, 0x44, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x23, 0x0a,
-	0x1f, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f, 0x41, 0x44,
-	0x44, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x4d, 0x45, 0x54, 0x41, 0x44, 0x41, 0x54, 0x41,
-	0x10, 0x01, 0x12, 0x2b, 0x0a, 0x27, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41,
-	0x4e, 0x44, 0x5f, 0x47, 0x45, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x43, 0x54, 0x49, 0x4f,
-	0x4e, 0x5f, 0x50, 0x4c, 0x41, 0x4e, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x02, 0x12,
-	0x27, 0x0a, 0x23, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x5f,
-	0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x43, 0x54, 0x49, 0x4f,
-	0x4e, 0x5f, 0x50, 0x4c, 0x41, 0x4e, 0x10, 0x03, 0x42, 0x9d, 0x01, 0x0a, 0x0c, 0x63, 0x6f, 0x6d,
-	0x2e, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x42, 0x0c, 0x52, 0x61, 0x66, 0x74, 0x4c,
-	0x6f, 0x67, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x43, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x72, 0x61, 0x66, 0x61, 0x6e, 0x61, 0x2f, 0x70, 0x79,
-	0x72, 0x6f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x73, 0x74, 0x6f,
-	0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x61, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0xa2, 0x02,
-	0x03, 0x52, 0x58, 0x58, 0xaa, 0x02, 0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0xca, 0x02,
-	0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0xe2, 0x02, 0x13, 0x52, 0x61, 0x66, 0x74, 0x4c,
-	0x6f, 0x67, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02,
-	0x07, 0x52, 0x61, 0x66, 0x74, 0x4c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_metastore_v1_raft_log_raft_log_proto_rawDescOnce sync.Once
-	file_metastore_v1_raft_log_raft_log_proto_rawDescData = file_metastore_v1_raft_log_raft_log_proto_rawDesc
-)
-
-func file_metastore_v1_raft_log_raft_log_proto_rawDescGZIP() []byte {
-	file_metastore_v1_raft_log_raft_log_proto_rawDescOnce.Do(func() {
-		file_metastore_v1_raft_log_raft_log_proto_rawDescData = protoimpl.X.CompressGZIP(file_metastore_v1_raft_log_raft_log_proto_rawDescData)
-	})
-	return file_metastore_v1_raft_log_raft_log_proto_rawDescData
-}
-
-var file_metastore_v1_raft_log_raft_log_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_metastore_v1_raft_log_raft_log_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
-var file_metastore_v1_raft_log_raft_log_proto_goTypes = []any{
-	(RaftCommand)(0),                        // 0: raft_log.RaftCommand
-	(*AddBlockMetadataRequest)(nil),         // 1: raft_log.AddBlockMetadataRequest
-	(*AddBlockMetadataResponse)(nil),        // 2: raft_log.AddBlockMetadataResponse
-	(*GetCompactionPlanUpdateRequest)(nil),  // 3: raft_log.GetCompactionPlanUpdateRequest
-	(*CompactionJobStatusUpdate)(nil),       // 4: raft_log.CompactionJobStatusUpdate
-	(*GetCompactionPlanUpdateResponse)(nil), // 5: raft_log.GetCompactionPlanUpdateResponse
-	(*CompactionPlanUpdate)(nil),            // 6: raft_log.CompactionPlanUpdate
-	(*NewCompactionJob)(nil),                // 7: raft_log.NewCompactionJob
-	(*AssignedCompactionJob)(nil),           // 8: raft_log.AssignedCompactionJob
-	(*UpdatedCompactionJob)(nil),            // 9: raft_log.UpdatedCompactionJob
-	(*CompletedCompactionJob)(nil),          // 10: raft_log.CompletedCompactionJob
-	(*CompactionJobState)(nil),              // 11: raft_log.CompactionJobState
-	(*CompactionJobPlan)(nil),               // 12: raft_log.CompactionJobPlan
-	(*UpdateCompactionPlanRequest)(nil),     // 13: raft_log.UpdateCompactionPlanRequest
-	(*UpdateCompactionPlanResponse)(nil),    // 14: raft_log.UpdateCompactionPlanResponse
-	(*v1.BlockMeta)(nil),                    // 15: metastore.v1.BlockMeta
-	(v1.CompactionJobStatus)(0),             // 16: metastore.v1.CompactionJobStatus
-	(*v1.CompactedBlocks)(nil),              // 17: metastore.v1.CompactedBlocks
-	(*v1.Tombstones)(nil),                   // 18: metastore.v1.Tombstones
-}
-var file_metastore_v1_raft_log_raft_log_proto_depIdxs = []int32{
-	15, // 0: raft_log.AddBlockMetadataRequest.metadata:type_name -> metastore.v1.BlockMeta
-	4,  // 1: raft_log.GetCompactionPlanUpdateRequest.status_updates:type_name -> raft_log.CompactionJobStatusUpdate
-	16, // 2: raft_log.CompactionJobStatusUpdate.status:type_name -> metastore.v1.CompactionJobStatus
-	6,  // 3: raft_log.GetCompactionPlanUpdateResponse.plan_update:type_name -> raft_log.CompactionPlanUpdate
-	7,  // 4: raft_log.CompactionPlanUpdate.new_jobs:type_name -> raft_log.NewCompactionJob
-	8,  // 5: raft_log.CompactionPlanUpdate.assigned_jobs:type_name -> raft_log.AssignedCompactionJob
-	9,  // 6: raft_log.CompactionPlanUpdate.updated_jobs:type_name -> raft_log.UpdatedCompactionJob
-	10, // 7: raft_log.CompactionPlanUpdate.completed_jobs:type_name -> raft_log.CompletedCompactionJob
-	11, // 8: raft_log.NewCompactionJob.state:type_name -> raft_log.CompactionJobState
-	12, // 9: raft_log.NewCompactionJob.plan:type_name -> raft_log.CompactionJobPlan
-	11, // 10: raft_log.AssignedCompactionJob.state:type_name -> raft_log.CompactionJobState
-	12, // 11: raft_log.AssignedCompactionJob.plan:type_name -> raft_log.CompactionJobPlan
-	11, // 12: raft_log.UpdatedCompactionJob.state:type_name -> raft_log.CompactionJobState
-	11, // 13: raft_log.CompletedCompactionJob.state:type_name -> raft_log.CompactionJobState
-	17, // 14: raft_log.CompletedCompactionJob.compacted_blocks:type_name -> metastore.v1.CompactedBlocks
-	16, // 15: raft_log.CompactionJobState.status:type_name -> metastore.v1.CompactionJobStatus
-	18, // 16: raft_log.CompactionJobPlan.tombstones:type_name -> metastore.v1.Tombstones
-	6,  // 17: raft_log.UpdateCompactionPlanRequest.plan_update:type_name -> raft_log.CompactionPlanUpdate
-	6,  // 18: raft_log.UpdateCompactionPlanResponse.plan_update:type_name -> raft_log.CompactionPlanUpdate
-	19, // [19:19] is the sub-list for method output_type
-	19, // [19:19] is the sub-list for method input_type
-	19, // [19:19] is the sub-list for extension type_name
-	19, // [19:19] is the sub-list for extension extendee
-	0,  // [0:19] is the sub-list for field type_name
-}
-
-func init() { file_metastore_v1_raft_log_raft_log_proto_init() }
-func file_metastore_v1_raft_log_raft_log_proto_init() {
-	if File_metastore_v1_raft_log_raft_log_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*AddBlockMetadataRequest); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*QuarantineBlockRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1150,8 +3283,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*AddBlockMetadataResponse); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*QuarantineBlockResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1162,8 +3295,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*GetCompactionPlanUpdateRequest); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*ArchivePartitionRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1174,8 +3307,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*CompactionJobStatusUpdate); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*ArchivePartitionResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1186,8 +3319,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*GetCompactionPlanUpdateResponse); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*ArchivedPartition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1198,8 +3331,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*CompactionPlanUpdate); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*ArchivedDictionaryRefs); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1210,8 +3343,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*NewCompactionJob); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*PartitionArchive); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1222,8 +3355,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*AssignedCompactionJob); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*MergePartitionsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1234,8 +3367,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*UpdatedCompactionJob); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*MergePartitionsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1246,8 +3379,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[9].Exporter = func(v any, i int) any {
-			switch v := v.(*CompletedCompactionJob); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*SplitPartitionRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1258,8 +3391,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[10].Exporter = func(v any, i int) any {
-			switch v := v.(*CompactionJobState); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[30].Exporter = func(v any, i int) any {
+			switch v := v.(*SplitPartitionResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1270,8 +3403,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[11].Exporter = func(v any, i int) any {
-			switch v := v.(*CompactionJobPlan); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*QuarantineWriterRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1282,8 +3415,8 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[12].Exporter = func(v any, i int) any {
-			switch v := v.(*UpdateCompactionPlanRequest); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[32].Exporter = func(v any, i int) any {
+			switch v := v.(*QuarantineWriterResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1294,8 +3427,92 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 				return nil
 			}
 		}
-		file_metastore_v1_raft_log_raft_log_proto_msgTypes[13].Exporter = func(v any, i int) any {
-			switch v := v.(*UpdateCompactionPlanResponse); i {
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*ReleaseWriterRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*ReleaseWriterResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[35].Exporter = func(v any, i int) any {
+			switch v := v.(*SweepDeletedBlocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[36].Exporter = func(v any, i int) any {
+			switch v := v.(*SweepDeletedBlocksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[37].Exporter = func(v any, i int) any {
+			switch v := v.(*DeletePartitionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[38].Exporter = func(v any, i int) any {
+			switch v := v.(*DeletePartitionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[39].Exporter = func(v any, i int) any {
+			switch v := v.(*RepairIndexRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metastore_v1_raft_log_raft_log_proto_msgTypes[40].Exporter = func(v any, i int) any {
+			switch v := v.(*RepairIndexResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1313,7 +3530,7 @@ func file_metastore_v1_raft_log_raft_log_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_metastore_v1_raft_log_raft_log_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   14,
+			NumMessages:   41,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -66,22 +66,41 @@ const (
 	// QuerierServiceAnalyzeQueryProcedure is the fully-qualified name of the QuerierService's
 	// AnalyzeQuery RPC.
 	QuerierServiceAnalyzeQueryProcedure = "/querier.v1.QuerierService/AnalyzeQuery"
+	// QuerierServiceExplainProcedure is the fully-qualified name of the QuerierService's Explain RPC.
+	QuerierServiceExplainProcedure = "/querier.v1.QuerierService/Explain"
+	// QuerierServiceSelectAllocationSizesProcedure is the fully-qualified name of the QuerierService's
+	// SelectAllocationSizes RPC.
+	QuerierServiceSelectAllocationSizesProcedure = "/querier.v1.QuerierService/SelectAllocationSizes"
+	// QuerierServiceSelectServiceGraphProcedure is the fully-qualified name of the QuerierService's
+	// SelectServiceGraph RPC.
+	QuerierServiceSelectServiceGraphProcedure = "/querier.v1.QuerierService/SelectServiceGraph"
+	// QuerierServiceSelectProfileTypesSeriesProcedure is the fully-qualified name of the
+	// QuerierService's SelectProfileTypesSeries RPC.
+	QuerierServiceSelectProfileTypesSeriesProcedure = "/querier.v1.QuerierService/SelectProfileTypesSeries"
+	// QuerierServiceServiceHierarchyProcedure is the fully-qualified name of the QuerierService's
+	// ServiceHierarchy RPC.
+	QuerierServiceServiceHierarchyProcedure = "/querier.v1.QuerierService/ServiceHierarchy"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	querierServiceServiceDescriptor                      = v1.File_querier_v1_querier_proto.Services().ByName("QuerierService")
-	querierServiceProfileTypesMethodDescriptor           = querierServiceServiceDescriptor.Methods().ByName("ProfileTypes")
-	querierServiceLabelValuesMethodDescriptor            = querierServiceServiceDescriptor.Methods().ByName("LabelValues")
-	querierServiceLabelNamesMethodDescriptor             = querierServiceServiceDescriptor.Methods().ByName("LabelNames")
-	querierServiceSeriesMethodDescriptor                 = querierServiceServiceDescriptor.Methods().ByName("Series")
-	querierServiceSelectMergeStacktracesMethodDescriptor = querierServiceServiceDescriptor.Methods().ByName("SelectMergeStacktraces")
-	querierServiceSelectMergeSpanProfileMethodDescriptor = querierServiceServiceDescriptor.Methods().ByName("SelectMergeSpanProfile")
-	querierServiceSelectMergeProfileMethodDescriptor     = querierServiceServiceDescriptor.Methods().ByName("SelectMergeProfile")
-	querierServiceSelectSeriesMethodDescriptor           = querierServiceServiceDescriptor.Methods().ByName("SelectSeries")
-	querierServiceDiffMethodDescriptor                   = querierServiceServiceDescriptor.Methods().ByName("Diff")
-	querierServiceGetProfileStatsMethodDescriptor        = querierServiceServiceDescriptor.Methods().ByName("GetProfileStats")
-	querierServiceAnalyzeQueryMethodDescriptor           = querierServiceServiceDescriptor.Methods().ByName("AnalyzeQuery")
+	querierServiceServiceDescriptor                        = v1.File_querier_v1_querier_proto.Services().ByName("QuerierService")
+	querierServiceProfileTypesMethodDescriptor             = querierServiceServiceDescriptor.Methods().ByName("ProfileTypes")
+	querierServiceLabelValuesMethodDescriptor              = querierServiceServiceDescriptor.Methods().ByName("LabelValues")
+	querierServiceLabelNamesMethodDescriptor               = querierServiceServiceDescriptor.Methods().ByName("LabelNames")
+	querierServiceSeriesMethodDescriptor                   = querierServiceServiceDescriptor.Methods().ByName("Series")
+	querierServiceSelectMergeStacktracesMethodDescriptor   = querierServiceServiceDescriptor.Methods().ByName("SelectMergeStacktraces")
+	querierServiceSelectMergeSpanProfileMethodDescriptor   = querierServiceServiceDescriptor.Methods().ByName("SelectMergeSpanProfile")
+	querierServiceSelectMergeProfileMethodDescriptor       = querierServiceServiceDescriptor.Methods().ByName("SelectMergeProfile")
+	querierServiceSelectSeriesMethodDescriptor             = querierServiceServiceDescriptor.Methods().ByName("SelectSeries")
+	querierServiceDiffMethodDescriptor                     = querierServiceServiceDescriptor.Methods().ByName("Diff")
+	querierServiceGetProfileStatsMethodDescriptor          = querierServiceServiceDescriptor.Methods().ByName("GetProfileStats")
+	querierServiceAnalyzeQueryMethodDescriptor             = querierServiceServiceDescriptor.Methods().ByName("AnalyzeQuery")
+	querierServiceExplainMethodDescriptor                  = querierServiceServiceDescriptor.Methods().ByName("Explain")
+	querierServiceSelectAllocationSizesMethodDescriptor    = querierServiceServiceDescriptor.Methods().ByName("SelectAllocationSizes")
+	querierServiceSelectServiceGraphMethodDescriptor       = querierServiceServiceDescriptor.Methods().ByName("SelectServiceGraph")
+	querierServiceSelectProfileTypesSeriesMethodDescriptor = querierServiceServiceDescriptor.Methods().ByName("SelectProfileTypesSeries")
+	querierServiceServiceHierarchyMethodDescriptor         = querierServiceServiceDescriptor.Methods().ByName("ServiceHierarchy")
 )
 
 // QuerierServiceClient is a client for the querier.v1.QuerierService service.
@@ -107,6 +126,16 @@ type QuerierServiceClient interface {
 	// GetProfileStats returns profile stats for the current tenant.
 	GetProfileStats(context.Context, *connect.Request[v11.GetProfileStatsRequest]) (*connect.Response[v11.GetProfileStatsResponse], error)
 	AnalyzeQuery(context.Context, *connect.Request[v1.AnalyzeQueryRequest]) (*connect.Response[v1.AnalyzeQueryResponse], error)
+	// Explain returns the plan of a query without executing it.
+	Explain(context.Context, *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error)
+	// SelectAllocationSizes returns the distribution of allocation sizes of the requested heap profiles.
+	SelectAllocationSizes(context.Context, *connect.Request[v1.SelectAllocationSizesRequest]) (*connect.Response[v1.SelectAllocationSizesResponse], error)
+	// SelectServiceGraph attributes the profiled resources to the calling services, using span profiles.
+	SelectServiceGraph(context.Context, *connect.Request[v1.SelectServiceGraphRequest]) (*connect.Response[v1.SelectServiceGraphResponse], error)
+	// SelectProfileTypesSeries returns time series of several profile types for the same selection.
+	SelectProfileTypesSeries(context.Context, *connect.Request[v1.SelectProfileTypesSeriesRequest]) (*connect.Response[v1.SelectProfileTypesSeriesResponse], error)
+	// ServiceHierarchy returns the tree of namespaces, services, and profile types that have data in the time range.
+	ServiceHierarchy(context.Context, *connect.Request[v1.ServiceHierarchyRequest]) (*connect.Response[v1.ServiceHierarchyResponse], error)
 }
 
 // NewQuerierServiceClient constructs a client for the querier.v1.QuerierService service. By
@@ -185,22 +214,57 @@ func NewQuerierServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(querierServiceAnalyzeQueryMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		explain: connect.NewClient[v1.ExplainRequest, v1.ExplainResponse](
+			httpClient,
+			baseURL+QuerierServiceExplainProcedure,
+			connect.WithSchema(querierServiceExplainMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		selectAllocationSizes: connect.NewClient[v1.SelectAllocationSizesRequest, v1.SelectAllocationSizesResponse](
+			httpClient,
+			baseURL+QuerierServiceSelectAllocationSizesProcedure,
+			connect.WithSchema(querierServiceSelectAllocationSizesMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		selectServiceGraph: connect.NewClient[v1.SelectServiceGraphRequest, v1.SelectServiceGraphResponse](
+			httpClient,
+			baseURL+QuerierServiceSelectServiceGraphProcedure,
+			connect.WithSchema(querierServiceSelectServiceGraphMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		selectProfileTypesSeries: connect.NewClient[v1.SelectProfileTypesSeriesRequest, v1.SelectProfileTypesSeriesResponse](
+			httpClient,
+			baseURL+QuerierServiceSelectProfileTypesSeriesProcedure,
+			connect.WithSchema(querierServiceSelectProfileTypesSeriesMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		serviceHierarchy: connect.NewClient[v1.ServiceHierarchyRequest, v1.ServiceHierarchyResponse](
+			httpClient,
+			baseURL+QuerierServiceServiceHierarchyProcedure,
+			connect.WithSchema(querierServiceServiceHierarchyMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // querierServiceClient implements QuerierServiceClient.
 type querierServiceClient struct {
-	profileTypes           *connect.Client[v1.ProfileTypesRequest, v1.ProfileTypesResponse]
-	labelValues            *connect.Client[v11.LabelValuesRequest, v11.LabelValuesResponse]
-	labelNames             *connect.Client[v11.LabelNamesRequest, v11.LabelNamesResponse]
-	series                 *connect.Client[v1.SeriesRequest, v1.SeriesResponse]
-	selectMergeStacktraces *connect.Client[v1.SelectMergeStacktracesRequest, v1.SelectMergeStacktracesResponse]
-	selectMergeSpanProfile *connect.Client[v1.SelectMergeSpanProfileRequest, v1.SelectMergeSpanProfileResponse]
-	selectMergeProfile     *connect.Client[v1.SelectMergeProfileRequest, v12.Profile]
-	selectSeries           *connect.Client[v1.SelectSeriesRequest, v1.SelectSeriesResponse]
-	diff                   *connect.Client[v1.DiffRequest, v1.DiffResponse]
-	getProfileStats        *connect.Client[v11.GetProfileStatsRequest, v11.GetProfileStatsResponse]
-	analyzeQuery           *connect.Client[v1.AnalyzeQueryRequest, v1.AnalyzeQueryResponse]
+	profileTypes             *connect.Client[v1.ProfileTypesRequest, v1.ProfileTypesResponse]
+	labelValues              *connect.Client[v11.LabelValuesRequest, v11.LabelValuesResponse]
+	labelNames               *connect.Client[v11.LabelNamesRequest, v11.LabelNamesResponse]
+	series                   *connect.Client[v1.SeriesRequest, v1.SeriesResponse]
+	selectMergeStacktraces   *connect.Client[v1.SelectMergeStacktracesRequest, v1.SelectMergeStacktracesResponse]
+	selectMergeSpanProfile   *connect.Client[v1.SelectMergeSpanProfileRequest, v1.SelectMergeSpanProfileResponse]
+	selectMergeProfile       *connect.Client[v1.SelectMergeProfileRequest, v12.Profile]
+	selectSeries             *connect.Client[v1.SelectSeriesRequest, v1.SelectSeriesResponse]
+	diff                     *connect.Client[v1.DiffRequest, v1.DiffResponse]
+	getProfileStats          *connect.Client[v11.GetProfileStatsRequest, v11.GetProfileStatsResponse]
+	analyzeQuery             *connect.Client[v1.AnalyzeQueryRequest, v1.AnalyzeQueryResponse]
+	explain                  *connect.Client[v1.ExplainRequest, v1.ExplainResponse]
+	selectAllocationSizes    *connect.Client[v1.SelectAllocationSizesRequest, v1.SelectAllocationSizesResponse]
+	selectServiceGraph       *connect.Client[v1.SelectServiceGraphRequest, v1.SelectServiceGraphResponse]
+	selectProfileTypesSeries *connect.Client[v1.SelectProfileTypesSeriesRequest, v1.SelectProfileTypesSeriesResponse]
+	serviceHierarchy         *connect.Client[v1.ServiceHierarchyRequest, v1.ServiceHierarchyResponse]
 }
 
 // ProfileTypes calls querier.v1.QuerierService.ProfileTypes.
@@ -258,6 +322,31 @@ func (c *querierServiceClient) AnalyzeQuery(ctx context.Context, req *connect.Re
 	return c.analyzeQuery.CallUnary(ctx, req)
 }
 
+// Explain calls querier.v1.QuerierService.Explain.
+func (c *querierServiceClient) Explain(ctx context.Context, req *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error) {
+	return c.explain.CallUnary(ctx, req)
+}
+
+// SelectAllocationSizes calls querier.v1.QuerierService.SelectAllocationSizes.
+func (c *querierServiceClient) SelectAllocationSizes(ctx context.Context, req *connect.Request[v1.SelectAllocationSizesRequest]) (*connect.Response[v1.SelectAllocationSizesResponse], error) {
+	return c.selectAllocationSizes.CallUnary(ctx, req)
+}
+
+// SelectServiceGraph calls querier.v1.QuerierService.SelectServiceGraph.
+func (c *querierServiceClient) SelectServiceGraph(ctx context.Context, req *connect.Request[v1.SelectServiceGraphRequest]) (*connect.Response[v1.SelectServiceGraphResponse], error) {
+	return c.selectServiceGraph.CallUnary(ctx, req)
+}
+
+// SelectProfileTypesSeries calls querier.v1.QuerierService.SelectProfileTypesSeries.
+func (c *querierServiceClient) SelectProfileTypesSeries(ctx context.Context, req *connect.Request[v1.SelectProfileTypesSeriesRequest]) (*connect.Response[v1.SelectProfileTypesSeriesResponse], error) {
+	return c.selectProfileTypesSeries.CallUnary(ctx, req)
+}
+
+// ServiceHierarchy calls querier.v1.QuerierService.ServiceHierarchy.
+func (c *querierServiceClient) ServiceHierarchy(ctx context.Context, req *connect.Request[v1.ServiceHierarchyRequest]) (*connect.Response[v1.ServiceHierarchyResponse], error) {
+	return c.serviceHierarchy.CallUnary(ctx, req)
+}
+
 // QuerierServiceHandler is an implementation of the querier.v1.QuerierService service.
 type QuerierServiceHandler interface {
 	// ProfileType returns a list of the existing profile types.
@@ -281,6 +370,16 @@ type QuerierServiceHandler interface {
 	// GetProfileStats returns profile stats for the current tenant.
 	GetProfileStats(context.Context, *connect.Request[v11.GetProfileStatsRequest]) (*connect.Response[v11.GetProfileStatsResponse], error)
 	AnalyzeQuery(context.Context, *connect.Request[v1.AnalyzeQueryRequest]) (*connect.Response[v1.AnalyzeQueryResponse], error)
+	// Explain returns the plan of a query without executing it.
+	Explain(context.Context, *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error)
+	// SelectAllocationSizes returns the distribution of allocation sizes of the requested heap profiles.
+	SelectAllocationSizes(context.Context, *connect.Request[v1.SelectAllocationSizesRequest]) (*connect.Response[v1.SelectAllocationSizesResponse], error)
+	// SelectServiceGraph attributes the profiled resources to the calling services, using span profiles.
+	SelectServiceGraph(context.Context, *connect.Request[v1.SelectServiceGraphRequest]) (*connect.Response[v1.SelectServiceGraphResponse], error)
+	// SelectProfileTypesSeries returns time series of several profile types for the same selection.
+	SelectProfileTypesSeries(context.Context, *connect.Request[v1.SelectProfileTypesSeriesRequest]) (*connect.Response[v1.SelectProfileTypesSeriesResponse], error)
+	// ServiceHierarchy returns the tree of namespaces, services, and profile types that have data in the time range.
+	ServiceHierarchy(context.Context, *connect.Request[v1.ServiceHierarchyRequest]) (*connect.Response[v1.ServiceHierarchyResponse], error)
 }
 
 // NewQuerierServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -355,6 +454,36 @@ func NewQuerierServiceHandler(svc QuerierServiceHandler, opts ...connect.Handler
 		connect.WithSchema(querierServiceAnalyzeQueryMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	querierServiceExplainHandler := connect.NewUnaryHandler(
+		QuerierServiceExplainProcedure,
+		svc.Explain,
+		connect.WithSchema(querierServiceExplainMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	querierServiceSelectAllocationSizesHandler := connect.NewUnaryHandler(
+		QuerierServiceSelectAllocationSizesProcedure,
+		svc.SelectAllocationSizes,
+		connect.WithSchema(querierServiceSelectAllocationSizesMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	querierServiceSelectServiceGraphHandler := connect.NewUnaryHandler(
+		QuerierServiceSelectServiceGraphProcedure,
+		svc.SelectServiceGraph,
+		connect.WithSchema(querierServiceSelectServiceGraphMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	querierServiceSelectProfileTypesSeriesHandler := connect.NewUnaryHandler(
+		QuerierServiceSelectProfileTypesSeriesProcedure,
+		svc.SelectProfileTypesSeries,
+		connect.WithSchema(querierServiceSelectProfileTypesSeriesMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	querierServiceServiceHierarchyHandler := connect.NewUnaryHandler(
+		QuerierServiceServiceHierarchyProcedure,
+		svc.ServiceHierarchy,
+		connect.WithSchema(querierServiceServiceHierarchyMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/querier.v1.QuerierService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case QuerierServiceProfileTypesProcedure:
@@ -379,6 +508,16 @@ func NewQuerierServiceHandler(svc QuerierServiceHandler, opts ...connect.Handler
 			querierServiceGetProfileStatsHandler.ServeHTTP(w, r)
 		case QuerierServiceAnalyzeQueryProcedure:
 			querierServiceAnalyzeQueryHandler.ServeHTTP(w, r)
+		case QuerierServiceExplainProcedure:
+			querierServiceExplainHandler.ServeHTTP(w, r)
+		case QuerierServiceSelectAllocationSizesProcedure:
+			querierServiceSelectAllocationSizesHandler.ServeHTTP(w, r)
+		case QuerierServiceSelectServiceGraphProcedure:
+			querierServiceSelectServiceGraphHandler.ServeHTTP(w, r)
+		case QuerierServiceSelectProfileTypesSeriesProcedure:
+			querierServiceSelectProfileTypesSeriesHandler.ServeHTTP(w, r)
+		case QuerierServiceServiceHierarchyProcedure:
+			querierServiceServiceHierarchyHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -431,3 +570,23 @@ func (UnimplementedQuerierServiceHandler) GetProfileStats(context.Context, *conn
 func (UnimplementedQuerierServiceHandler) AnalyzeQuery(context.Context, *connect.Request[v1.AnalyzeQueryRequest]) (*connect.Response[v1.AnalyzeQueryResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("querier.v1.QuerierService.AnalyzeQuery is not implemented"))
 }
+
+func (UnimplementedQuerierServiceHandler) Explain(context.Context, *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("querier.v1.QuerierService.Explain is not implemented"))
+}
+
+func (UnimplementedQuerierServiceHandler) SelectAllocationSizes(context.Context, *connect.Request[v1.SelectAllocationSizesRequest]) (*connect.Response[v1.SelectAllocationSizesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("querier.v1.QuerierService.SelectAllocationSizes is not implemented"))
+}
+
+func (UnimplementedQuerierServiceHandler) SelectServiceGraph(context.Context, *connect.Request[v1.SelectServiceGraphRequest]) (*connect.Response[v1.SelectServiceGraphResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("querier.v1.QuerierService.SelectServiceGraph is not implemented"))
+}
+
+func (UnimplementedQuerierServiceHandler) SelectProfileTypesSeries(context.Context, *connect.Request[v1.SelectProfileTypesSeriesRequest]) (*connect.Response[v1.SelectProfileTypesSeriesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("querier.v1.QuerierService.SelectProfileTypesSeries is not implemented"))
+}
+
+func (UnimplementedQuerierServiceHandler) ServiceHierarchy(context.Context, *connect.Request[v1.ServiceHierarchyRequest]) (*connect.Response[v1.ServiceHierarchyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("querier.v1.QuerierService.ServiceHierarchy is not implemented"))
+}
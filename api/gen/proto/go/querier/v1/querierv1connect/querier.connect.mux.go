@@ -74,4 +74,29 @@ func RegisterQuerierServiceHandler(mux *mux.Router, svc QuerierServiceHandler, o
 		svc.AnalyzeQuery,
 		opts...,
 	))
+	mux.Handle("/querier.v1.QuerierService/Explain", connect.NewUnaryHandler(
+		"/querier.v1.QuerierService/Explain",
+		svc.Explain,
+		opts...,
+	))
+	mux.Handle("/querier.v1.QuerierService/SelectAllocationSizes", connect.NewUnaryHandler(
+		"/querier.v1.QuerierService/SelectAllocationSizes",
+		svc.SelectAllocationSizes,
+		opts...,
+	))
+	mux.Handle("/querier.v1.QuerierService/SelectServiceGraph", connect.NewUnaryHandler(
+		"/querier.v1.QuerierService/SelectServiceGraph",
+		svc.SelectServiceGraph,
+		opts...,
+	))
+	mux.Handle("/querier.v1.QuerierService/SelectProfileTypesSeries", connect.NewUnaryHandler(
+		"/querier.v1.QuerierService/SelectProfileTypesSeries",
+		svc.SelectProfileTypesSeries,
+		opts...,
+	))
+	mux.Handle("/querier.v1.QuerierService/ServiceHierarchy", connect.NewUnaryHandler(
+		"/querier.v1.QuerierService/ServiceHierarchy",
+		svc.ServiceHierarchy,
+		opts...,
+	))
 }
@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: settings/v1/collection_rules.proto
+
+package settingsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// CollectionRulesServiceName is the fully-qualified name of the CollectionRulesService service.
+	CollectionRulesServiceName = "settings.v1.CollectionRulesService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// CollectionRulesServiceGetCollectionRulesProcedure is the fully-qualified name of the
+	// CollectionRulesService's GetCollectionRules RPC.
+	CollectionRulesServiceGetCollectionRulesProcedure = "/settings.v1.CollectionRulesService/GetCollectionRules"
+	// CollectionRulesServiceUpsertCollectionRuleProcedure is the fully-qualified name of the
+	// CollectionRulesService's UpsertCollectionRule RPC.
+	CollectionRulesServiceUpsertCollectionRuleProcedure = "/settings.v1.CollectionRulesService/UpsertCollectionRule"
+	// CollectionRulesServiceDeleteCollectionRuleProcedure is the fully-qualified name of the
+	// CollectionRulesService's DeleteCollectionRule RPC.
+	CollectionRulesServiceDeleteCollectionRuleProcedure = "/settings.v1.CollectionRulesService/DeleteCollectionRule"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	collectionRulesServiceServiceDescriptor                    = v1.File_settings_v1_collection_rules_proto.Services().ByName("CollectionRulesService")
+	collectionRulesServiceGetCollectionRulesMethodDescriptor   = collectionRulesServiceServiceDescriptor.Methods().ByName("GetCollectionRules")
+	collectionRulesServiceUpsertCollectionRuleMethodDescriptor = collectionRulesServiceServiceDescriptor.Methods().ByName("UpsertCollectionRule")
+	collectionRulesServiceDeleteCollectionRuleMethodDescriptor = collectionRulesServiceServiceDescriptor.Methods().ByName("DeleteCollectionRule")
+)
+
+// CollectionRulesServiceClient is a client for the settings.v1.CollectionRulesService service.
+type CollectionRulesServiceClient interface {
+	GetCollectionRules(context.Context, *connect.Request[v1.GetCollectionRulesRequest]) (*connect.Response[v1.GetCollectionRulesResponse], error)
+	UpsertCollectionRule(context.Context, *connect.Request[v1.UpsertCollectionRuleRequest]) (*connect.Response[v1.UpsertCollectionRuleResponse], error)
+	DeleteCollectionRule(context.Context, *connect.Request[v1.DeleteCollectionRuleRequest]) (*connect.Response[v1.DeleteCollectionRuleResponse], error)
+}
+
+// NewCollectionRulesServiceClient constructs a client for the settings.v1.CollectionRulesService
+// service. By default, it uses the Connect protocol with the binary Protobuf Codec, asks for
+// gzipped responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply
+// the connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewCollectionRulesServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) CollectionRulesServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &collectionRulesServiceClient{
+		getCollectionRules: connect.NewClient[v1.GetCollectionRulesRequest, v1.GetCollectionRulesResponse](
+			httpClient,
+			baseURL+CollectionRulesServiceGetCollectionRulesProcedure,
+			connect.WithSchema(collectionRulesServiceGetCollectionRulesMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		upsertCollectionRule: connect.NewClient[v1.UpsertCollectionRuleRequest, v1.UpsertCollectionRuleResponse](
+			httpClient,
+			baseURL+CollectionRulesServiceUpsertCollectionRuleProcedure,
+			connect.WithSchema(collectionRulesServiceUpsertCollectionRuleMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteCollectionRule: connect.NewClient[v1.DeleteCollectionRuleRequest, v1.DeleteCollectionRuleResponse](
+			httpClient,
+			baseURL+CollectionRulesServiceDeleteCollectionRuleProcedure,
+			connect.WithSchema(collectionRulesServiceDeleteCollectionRuleMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// collectionRulesServiceClient implements CollectionRulesServiceClient.
+type collectionRulesServiceClient struct {
+	getCollectionRules   *connect.Client[v1.GetCollectionRulesRequest, v1.GetCollectionRulesResponse]
+	upsertCollectionRule *connect.Client[v1.UpsertCollectionRuleRequest, v1.UpsertCollectionRuleResponse]
+	deleteCollectionRule *connect.Client[v1.DeleteCollectionRuleRequest, v1.DeleteCollectionRuleResponse]
+}
+
+// GetCollectionRules calls settings.v1.CollectionRulesService.GetCollectionRules.
+func (c *collectionRulesServiceClient) GetCollectionRules(ctx context.Context, req *connect.Request[v1.GetCollectionRulesRequest]) (*connect.Response[v1.GetCollectionRulesResponse], error) {
+	return c.getCollectionRules.CallUnary(ctx, req)
+}
+
+// UpsertCollectionRule calls settings.v1.CollectionRulesService.UpsertCollectionRule.
+func (c *collectionRulesServiceClient) UpsertCollectionRule(ctx context.Context, req *connect.Request[v1.UpsertCollectionRuleRequest]) (*connect.Response[v1.UpsertCollectionRuleResponse], error) {
+	return c.upsertCollectionRule.CallUnary(ctx, req)
+}
+
+// DeleteCollectionRule calls settings.v1.CollectionRulesService.DeleteCollectionRule.
+func (c *collectionRulesServiceClient) DeleteCollectionRule(ctx context.Context, req *connect.Request[v1.DeleteCollectionRuleRequest]) (*connect.Response[v1.DeleteCollectionRuleResponse], error) {
+	return c.deleteCollectionRule.CallUnary(ctx, req)
+}
+
+// CollectionRulesServiceHandler is an implementation of the settings.v1.CollectionRulesService
+// service.
+type CollectionRulesServiceHandler interface {
+	GetCollectionRules(context.Context, *connect.Request[v1.GetCollectionRulesRequest]) (*connect.Response[v1.GetCollectionRulesResponse], error)
+	UpsertCollectionRule(context.Context, *connect.Request[v1.UpsertCollectionRuleRequest]) (*connect.Response[v1.UpsertCollectionRuleResponse], error)
+	DeleteCollectionRule(context.Context, *connect.Request[v1.DeleteCollectionRuleRequest]) (*connect.Response[v1.DeleteCollectionRuleResponse], error)
+}
+
+// NewCollectionRulesServiceHandler builds an HTTP handler from the service implementation. It
+// returns the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewCollectionRulesServiceHandler(svc CollectionRulesServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	collectionRulesServiceGetCollectionRulesHandler := connect.NewUnaryHandler(
+		CollectionRulesServiceGetCollectionRulesProcedure,
+		svc.GetCollectionRules,
+		connect.WithSchema(collectionRulesServiceGetCollectionRulesMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	collectionRulesServiceUpsertCollectionRuleHandler := connect.NewUnaryHandler(
+		CollectionRulesServiceUpsertCollectionRuleProcedure,
+		svc.UpsertCollectionRule,
+		connect.WithSchema(collectionRulesServiceUpsertCollectionRuleMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	collectionRulesServiceDeleteCollectionRuleHandler := connect.NewUnaryHandler(
+		CollectionRulesServiceDeleteCollectionRuleProcedure,
+		svc.DeleteCollectionRule,
+		connect.WithSchema(collectionRulesServiceDeleteCollectionRuleMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/settings.v1.CollectionRulesService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case CollectionRulesServiceGetCollectionRulesProcedure:
+			collectionRulesServiceGetCollectionRulesHandler.ServeHTTP(w, r)
+		case CollectionRulesServiceUpsertCollectionRuleProcedure:
+			collectionRulesServiceUpsertCollectionRuleHandler.ServeHTTP(w, r)
+		case CollectionRulesServiceDeleteCollectionRuleProcedure:
+			collectionRulesServiceDeleteCollectionRuleHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedCollectionRulesServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedCollectionRulesServiceHandler struct{}
+
+func (UnimplementedCollectionRulesServiceHandler) GetCollectionRules(context.Context, *connect.Request[v1.GetCollectionRulesRequest]) (*connect.Response[v1.GetCollectionRulesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.CollectionRulesService.GetCollectionRules is not implemented"))
+}
+
+func (UnimplementedCollectionRulesServiceHandler) UpsertCollectionRule(context.Context, *connect.Request[v1.UpsertCollectionRuleRequest]) (*connect.Response[v1.UpsertCollectionRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.CollectionRulesService.UpsertCollectionRule is not implemented"))
+}
+
+func (UnimplementedCollectionRulesServiceHandler) DeleteCollectionRule(context.Context, *connect.Request[v1.DeleteCollectionRuleRequest]) (*connect.Response[v1.DeleteCollectionRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.CollectionRulesService.DeleteCollectionRule is not implemented"))
+}
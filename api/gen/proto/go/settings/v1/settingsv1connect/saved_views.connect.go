@@ -0,0 +1,203 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: settings/v1/saved_views.proto
+
+package settingsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// SavedViewsServiceName is the fully-qualified name of the SavedViewsService service.
+	SavedViewsServiceName = "settings.v1.SavedViewsService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// SavedViewsServiceListSavedViewsProcedure is the fully-qualified name of the SavedViewsService's
+	// ListSavedViews RPC.
+	SavedViewsServiceListSavedViewsProcedure = "/settings.v1.SavedViewsService/ListSavedViews"
+	// SavedViewsServiceGetSavedViewProcedure is the fully-qualified name of the SavedViewsService's
+	// GetSavedView RPC.
+	SavedViewsServiceGetSavedViewProcedure = "/settings.v1.SavedViewsService/GetSavedView"
+	// SavedViewsServiceUpsertSavedViewProcedure is the fully-qualified name of the SavedViewsService's
+	// UpsertSavedView RPC.
+	SavedViewsServiceUpsertSavedViewProcedure = "/settings.v1.SavedViewsService/UpsertSavedView"
+	// SavedViewsServiceDeleteSavedViewProcedure is the fully-qualified name of the SavedViewsService's
+	// DeleteSavedView RPC.
+	SavedViewsServiceDeleteSavedViewProcedure = "/settings.v1.SavedViewsService/DeleteSavedView"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	savedViewsServiceServiceDescriptor               = v1.File_settings_v1_saved_views_proto.Services().ByName("SavedViewsService")
+	savedViewsServiceListSavedViewsMethodDescriptor  = savedViewsServiceServiceDescriptor.Methods().ByName("ListSavedViews")
+	savedViewsServiceGetSavedViewMethodDescriptor    = savedViewsServiceServiceDescriptor.Methods().ByName("GetSavedView")
+	savedViewsServiceUpsertSavedViewMethodDescriptor = savedViewsServiceServiceDescriptor.Methods().ByName("UpsertSavedView")
+	savedViewsServiceDeleteSavedViewMethodDescriptor = savedViewsServiceServiceDescriptor.Methods().ByName("DeleteSavedView")
+)
+
+// SavedViewsServiceClient is a client for the settings.v1.SavedViewsService service.
+type SavedViewsServiceClient interface {
+	ListSavedViews(context.Context, *connect.Request[v1.ListSavedViewsRequest]) (*connect.Response[v1.ListSavedViewsResponse], error)
+	GetSavedView(context.Context, *connect.Request[v1.GetSavedViewRequest]) (*connect.Response[v1.GetSavedViewResponse], error)
+	UpsertSavedView(context.Context, *connect.Request[v1.UpsertSavedViewRequest]) (*connect.Response[v1.UpsertSavedViewResponse], error)
+	DeleteSavedView(context.Context, *connect.Request[v1.DeleteSavedViewRequest]) (*connect.Response[v1.DeleteSavedViewResponse], error)
+}
+
+// NewSavedViewsServiceClient constructs a client for the settings.v1.SavedViewsService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewSavedViewsServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) SavedViewsServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &savedViewsServiceClient{
+		listSavedViews: connect.NewClient[v1.ListSavedViewsRequest, v1.ListSavedViewsResponse](
+			httpClient,
+			baseURL+SavedViewsServiceListSavedViewsProcedure,
+			connect.WithSchema(savedViewsServiceListSavedViewsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getSavedView: connect.NewClient[v1.GetSavedViewRequest, v1.GetSavedViewResponse](
+			httpClient,
+			baseURL+SavedViewsServiceGetSavedViewProcedure,
+			connect.WithSchema(savedViewsServiceGetSavedViewMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		upsertSavedView: connect.NewClient[v1.UpsertSavedViewRequest, v1.UpsertSavedViewResponse](
+			httpClient,
+			baseURL+SavedViewsServiceUpsertSavedViewProcedure,
+			connect.WithSchema(savedViewsServiceUpsertSavedViewMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteSavedView: connect.NewClient[v1.DeleteSavedViewRequest, v1.DeleteSavedViewResponse](
+			httpClient,
+			baseURL+SavedViewsServiceDeleteSavedViewProcedure,
+			connect.WithSchema(savedViewsServiceDeleteSavedViewMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// savedViewsServiceClient implements SavedViewsServiceClient.
+type savedViewsServiceClient struct {
+	listSavedViews  *connect.Client[v1.ListSavedViewsRequest, v1.ListSavedViewsResponse]
+	getSavedView    *connect.Client[v1.GetSavedViewRequest, v1.GetSavedViewResponse]
+	upsertSavedView *connect.Client[v1.UpsertSavedViewRequest, v1.UpsertSavedViewResponse]
+	deleteSavedView *connect.Client[v1.DeleteSavedViewRequest, v1.DeleteSavedViewResponse]
+}
+
+// ListSavedViews calls settings.v1.SavedViewsService.ListSavedViews.
+func (c *savedViewsServiceClient) ListSavedViews(ctx context.Context, req *connect.Request[v1.ListSavedViewsRequest]) (*connect.Response[v1.ListSavedViewsResponse], error) {
+	return c.listSavedViews.CallUnary(ctx, req)
+}
+
+// GetSavedView calls settings.v1.SavedViewsService.GetSavedView.
+func (c *savedViewsServiceClient) GetSavedView(ctx context.Context, req *connect.Request[v1.GetSavedViewRequest]) (*connect.Response[v1.GetSavedViewResponse], error) {
+	return c.getSavedView.CallUnary(ctx, req)
+}
+
+// UpsertSavedView calls settings.v1.SavedViewsService.UpsertSavedView.
+func (c *savedViewsServiceClient) UpsertSavedView(ctx context.Context, req *connect.Request[v1.UpsertSavedViewRequest]) (*connect.Response[v1.UpsertSavedViewResponse], error) {
+	return c.upsertSavedView.CallUnary(ctx, req)
+}
+
+// DeleteSavedView calls settings.v1.SavedViewsService.DeleteSavedView.
+func (c *savedViewsServiceClient) DeleteSavedView(ctx context.Context, req *connect.Request[v1.DeleteSavedViewRequest]) (*connect.Response[v1.DeleteSavedViewResponse], error) {
+	return c.deleteSavedView.CallUnary(ctx, req)
+}
+
+// SavedViewsServiceHandler is an implementation of the settings.v1.SavedViewsService service.
+type SavedViewsServiceHandler interface {
+	ListSavedViews(context.Context, *connect.Request[v1.ListSavedViewsRequest]) (*connect.Response[v1.ListSavedViewsResponse], error)
+	GetSavedView(context.Context, *connect.Request[v1.GetSavedViewRequest]) (*connect.Response[v1.GetSavedViewResponse], error)
+	UpsertSavedView(context.Context, *connect.Request[v1.UpsertSavedViewRequest]) (*connect.Response[v1.UpsertSavedViewResponse], error)
+	DeleteSavedView(context.Context, *connect.Request[v1.DeleteSavedViewRequest]) (*connect.Response[v1.DeleteSavedViewResponse], error)
+}
+
+// NewSavedViewsServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewSavedViewsServiceHandler(svc SavedViewsServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	savedViewsServiceListSavedViewsHandler := connect.NewUnaryHandler(
+		SavedViewsServiceListSavedViewsProcedure,
+		svc.ListSavedViews,
+		connect.WithSchema(savedViewsServiceListSavedViewsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	savedViewsServiceGetSavedViewHandler := connect.NewUnaryHandler(
+		SavedViewsServiceGetSavedViewProcedure,
+		svc.GetSavedView,
+		connect.WithSchema(savedViewsServiceGetSavedViewMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	savedViewsServiceUpsertSavedViewHandler := connect.NewUnaryHandler(
+		SavedViewsServiceUpsertSavedViewProcedure,
+		svc.UpsertSavedView,
+		connect.WithSchema(savedViewsServiceUpsertSavedViewMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	savedViewsServiceDeleteSavedViewHandler := connect.NewUnaryHandler(
+		SavedViewsServiceDeleteSavedViewProcedure,
+		svc.DeleteSavedView,
+		connect.WithSchema(savedViewsServiceDeleteSavedViewMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/settings.v1.SavedViewsService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case SavedViewsServiceListSavedViewsProcedure:
+			savedViewsServiceListSavedViewsHandler.ServeHTTP(w, r)
+		case SavedViewsServiceGetSavedViewProcedure:
+			savedViewsServiceGetSavedViewHandler.ServeHTTP(w, r)
+		case SavedViewsServiceUpsertSavedViewProcedure:
+			savedViewsServiceUpsertSavedViewHandler.ServeHTTP(w, r)
+		case SavedViewsServiceDeleteSavedViewProcedure:
+			savedViewsServiceDeleteSavedViewHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedSavedViewsServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedSavedViewsServiceHandler struct{}
+
+func (UnimplementedSavedViewsServiceHandler) ListSavedViews(context.Context, *connect.Request[v1.ListSavedViewsRequest]) (*connect.Response[v1.ListSavedViewsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SavedViewsService.ListSavedViews is not implemented"))
+}
+
+func (UnimplementedSavedViewsServiceHandler) GetSavedView(context.Context, *connect.Request[v1.GetSavedViewRequest]) (*connect.Response[v1.GetSavedViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SavedViewsService.GetSavedView is not implemented"))
+}
+
+func (UnimplementedSavedViewsServiceHandler) UpsertSavedView(context.Context, *connect.Request[v1.UpsertSavedViewRequest]) (*connect.Response[v1.UpsertSavedViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SavedViewsService.UpsertSavedView is not implemented"))
+}
+
+func (UnimplementedSavedViewsServiceHandler) DeleteSavedView(context.Context, *connect.Request[v1.DeleteSavedViewRequest]) (*connect.Response[v1.DeleteSavedViewResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SavedViewsService.DeleteSavedView is not implemented"))
+}
@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-connect-go-mux. DO NOT EDIT.
+//
+// Source: settings/v1/saved_views.proto
+
+package settingsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	mux "github.com/gorilla/mux"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion0_1_0
+
+// RegisterSavedViewsServiceHandler register an HTTP handler to a mux.Router from the service
+// implementation.
+func RegisterSavedViewsServiceHandler(mux *mux.Router, svc SavedViewsServiceHandler, opts ...connect.HandlerOption) {
+	mux.Handle("/settings.v1.SavedViewsService/ListSavedViews", connect.NewUnaryHandler(
+		"/settings.v1.SavedViewsService/ListSavedViews",
+		svc.ListSavedViews,
+		opts...,
+	))
+	mux.Handle("/settings.v1.SavedViewsService/GetSavedView", connect.NewUnaryHandler(
+		"/settings.v1.SavedViewsService/GetSavedView",
+		svc.GetSavedView,
+		opts...,
+	))
+	mux.Handle("/settings.v1.SavedViewsService/UpsertSavedView", connect.NewUnaryHandler(
+		"/settings.v1.SavedViewsService/UpsertSavedView",
+		svc.UpsertSavedView,
+		opts...,
+	))
+	mux.Handle("/settings.v1.SavedViewsService/DeleteSavedView", connect.NewUnaryHandler(
+		"/settings.v1.SavedViewsService/DeleteSavedView",
+		svc.DeleteSavedView,
+		opts...,
+	))
+}
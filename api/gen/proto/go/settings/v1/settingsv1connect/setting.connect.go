@@ -37,19 +37,43 @@ const (
 	SettingsServiceGetProcedure = "/settings.v1.SettingsService/Get"
 	// SettingsServiceSetProcedure is the fully-qualified name of the SettingsService's Set RPC.
 	SettingsServiceSetProcedure = "/settings.v1.SettingsService/Set"
+	// SettingsServiceGetViewConfigProcedure is the fully-qualified name of the SettingsService's
+	// GetViewConfig RPC.
+	SettingsServiceGetViewConfigProcedure = "/settings.v1.SettingsService/GetViewConfig"
+	// SettingsServiceGetUserSettingsProcedure is the fully-qualified name of the SettingsService's
+	// GetUserSettings RPC.
+	SettingsServiceGetUserSettingsProcedure = "/settings.v1.SettingsService/GetUserSettings"
+	// SettingsServiceSetUserSettingsProcedure is the fully-qualified name of the SettingsService's
+	// SetUserSettings RPC.
+	SettingsServiceSetUserSettingsProcedure = "/settings.v1.SettingsService/SetUserSettings"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	settingsServiceServiceDescriptor   = v1.File_settings_v1_setting_proto.Services().ByName("SettingsService")
-	settingsServiceGetMethodDescriptor = settingsServiceServiceDescriptor.Methods().ByName("Get")
-	settingsServiceSetMethodDescriptor = settingsServiceServiceDescriptor.Methods().ByName("Set")
+	settingsServiceServiceDescriptor               = v1.File_settings_v1_setting_proto.Services().ByName("SettingsService")
+	settingsServiceGetMethodDescriptor             = settingsServiceServiceDescriptor.Methods().ByName("Get")
+	settingsServiceSetMethodDescriptor             = settingsServiceServiceDescriptor.Methods().ByName("Set")
+	settingsServiceGetViewConfigMethodDescriptor   = settingsServiceServiceDescriptor.Methods().ByName("GetViewConfig")
+	settingsServiceGetUserSettingsMethodDescriptor = settingsServiceServiceDescriptor.Methods().ByName("GetUserSettings")
+	settingsServiceSetUserSettingsMethodDescriptor = settingsServiceServiceDescriptor.Methods().ByName("SetUserSettings")
 )
 
 // SettingsServiceClient is a client for the settings.v1.SettingsService service.
 type SettingsServiceClient interface {
 	Get(context.Context, *connect.Request[v1.GetSettingsRequest]) (*connect.Response[v1.GetSettingsResponse], error)
 	Set(context.Context, *connect.Request[v1.SetSettingsRequest]) (*connect.Response[v1.SetSettingsResponse], error)
+	// GetViewConfig returns the default view configuration of the tenant and
+	// its services. The configuration is stored as settings named
+	// "view.<key>" for the tenant defaults, and "view.service.<service>.<key>"
+	// for service overrides, where the key is one of "profile_type",
+	// "aggregation", and "color_scheme".
+	GetViewConfig(context.Context, *connect.Request[v1.GetViewConfigRequest]) (*connect.Response[v1.GetViewConfigResponse], error)
+	// GetUserSettings returns the settings of the user making the request,
+	// such as UI preferences. The user is identified by the X-Grafana-User
+	// header, which Grafana sets when proxying requests.
+	GetUserSettings(context.Context, *connect.Request[v1.GetUserSettingsRequest]) (*connect.Response[v1.GetUserSettingsResponse], error)
+	// SetUserSettings sets a setting of the user making the request.
+	SetUserSettings(context.Context, *connect.Request[v1.SetUserSettingsRequest]) (*connect.Response[v1.SetUserSettingsResponse], error)
 }
 
 // NewSettingsServiceClient constructs a client for the settings.v1.SettingsService service. By
@@ -74,13 +98,34 @@ func NewSettingsServiceClient(httpClient connect.HTTPClient, baseURL string, opt
 			connect.WithSchema(settingsServiceSetMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		getViewConfig: connect.NewClient[v1.GetViewConfigRequest, v1.GetViewConfigResponse](
+			httpClient,
+			baseURL+SettingsServiceGetViewConfigProcedure,
+			connect.WithSchema(settingsServiceGetViewConfigMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getUserSettings: connect.NewClient[v1.GetUserSettingsRequest, v1.GetUserSettingsResponse](
+			httpClient,
+			baseURL+SettingsServiceGetUserSettingsProcedure,
+			connect.WithSchema(settingsServiceGetUserSettingsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		setUserSettings: connect.NewClient[v1.SetUserSettingsRequest, v1.SetUserSettingsResponse](
+			httpClient,
+			baseURL+SettingsServiceSetUserSettingsProcedure,
+			connect.WithSchema(settingsServiceSetUserSettingsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // settingsServiceClient implements SettingsServiceClient.
 type settingsServiceClient struct {
-	get *connect.Client[v1.GetSettingsRequest, v1.GetSettingsResponse]
-	set *connect.Client[v1.SetSettingsRequest, v1.SetSettingsResponse]
+	get             *connect.Client[v1.GetSettingsRequest, v1.GetSettingsResponse]
+	set             *connect.Client[v1.SetSettingsRequest, v1.SetSettingsResponse]
+	getViewConfig   *connect.Client[v1.GetViewConfigRequest, v1.GetViewConfigResponse]
+	getUserSettings *connect.Client[v1.GetUserSettingsRequest, v1.GetUserSettingsResponse]
+	setUserSettings *connect.Client[v1.SetUserSettingsRequest, v1.SetUserSettingsResponse]
 }
 
 // Get calls settings.v1.SettingsService.Get.
@@ -93,10 +138,37 @@ func (c *settingsServiceClient) Set(ctx context.Context, req *connect.Request[v1
 	return c.set.CallUnary(ctx, req)
 }
 
+// GetViewConfig calls settings.v1.SettingsService.GetViewConfig.
+func (c *settingsServiceClient) GetViewConfig(ctx context.Context, req *connect.Request[v1.GetViewConfigRequest]) (*connect.Response[v1.GetViewConfigResponse], error) {
+	return c.getViewConfig.CallUnary(ctx, req)
+}
+
+// GetUserSettings calls settings.v1.SettingsService.GetUserSettings.
+func (c *settingsServiceClient) GetUserSettings(ctx context.Context, req *connect.Request[v1.GetUserSettingsRequest]) (*connect.Response[v1.GetUserSettingsResponse], error) {
+	return c.getUserSettings.CallUnary(ctx, req)
+}
+
+// SetUserSettings calls settings.v1.SettingsService.SetUserSettings.
+func (c *settingsServiceClient) SetUserSettings(ctx context.Context, req *connect.Request[v1.SetUserSettingsRequest]) (*connect.Response[v1.SetUserSettingsResponse], error) {
+	return c.setUserSettings.CallUnary(ctx, req)
+}
+
 // SettingsServiceHandler is an implementation of the settings.v1.SettingsService service.
 type SettingsServiceHandler interface {
 	Get(context.Context, *connect.Request[v1.GetSettingsRequest]) (*connect.Response[v1.GetSettingsResponse], error)
 	Set(context.Context, *connect.Request[v1.SetSettingsRequest]) (*connect.Response[v1.SetSettingsResponse], error)
+	// GetViewConfig returns the default view configuration of the tenant and
+	// its services. The configuration is stored as settings named
+	// "view.<key>" for the tenant defaults, and "view.service.<service>.<key>"
+	// for service overrides, where the key is one of "profile_type",
+	// "aggregation", and "color_scheme".
+	GetViewConfig(context.Context, *connect.Request[v1.GetViewConfigRequest]) (*connect.Response[v1.GetViewConfigResponse], error)
+	// GetUserSettings returns the settings of the user making the request,
+	// such as UI preferences. The user is identified by the X-Grafana-User
+	// header, which Grafana sets when proxying requests.
+	GetUserSettings(context.Context, *connect.Request[v1.GetUserSettingsRequest]) (*connect.Response[v1.GetUserSettingsResponse], error)
+	// SetUserSettings sets a setting of the user making the request.
+	SetUserSettings(context.Context, *connect.Request[v1.SetUserSettingsRequest]) (*connect.Response[v1.SetUserSettingsResponse], error)
 }
 
 // NewSettingsServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -117,12 +189,36 @@ func NewSettingsServiceHandler(svc SettingsServiceHandler, opts ...connect.Handl
 		connect.WithSchema(settingsServiceSetMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	settingsServiceGetViewConfigHandler := connect.NewUnaryHandler(
+		SettingsServiceGetViewConfigProcedure,
+		svc.GetViewConfig,
+		connect.WithSchema(settingsServiceGetViewConfigMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	settingsServiceGetUserSettingsHandler := connect.NewUnaryHandler(
+		SettingsServiceGetUserSettingsProcedure,
+		svc.GetUserSettings,
+		connect.WithSchema(settingsServiceGetUserSettingsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	settingsServiceSetUserSettingsHandler := connect.NewUnaryHandler(
+		SettingsServiceSetUserSettingsProcedure,
+		svc.SetUserSettings,
+		connect.WithSchema(settingsServiceSetUserSettingsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/settings.v1.SettingsService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case SettingsServiceGetProcedure:
 			settingsServiceGetHandler.ServeHTTP(w, r)
 		case SettingsServiceSetProcedure:
 			settingsServiceSetHandler.ServeHTTP(w, r)
+		case SettingsServiceGetViewConfigProcedure:
+			settingsServiceGetViewConfigHandler.ServeHTTP(w, r)
+		case SettingsServiceGetUserSettingsProcedure:
+			settingsServiceGetUserSettingsHandler.ServeHTTP(w, r)
+		case SettingsServiceSetUserSettingsProcedure:
+			settingsServiceSetUserSettingsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -139,3 +235,15 @@ func (UnimplementedSettingsServiceHandler) Get(context.Context, *connect.Request
 func (UnimplementedSettingsServiceHandler) Set(context.Context, *connect.Request[v1.SetSettingsRequest]) (*connect.Response[v1.SetSettingsResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SettingsService.Set is not implemented"))
 }
+
+func (UnimplementedSettingsServiceHandler) GetViewConfig(context.Context, *connect.Request[v1.GetViewConfigRequest]) (*connect.Response[v1.GetViewConfigResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SettingsService.GetViewConfig is not implemented"))
+}
+
+func (UnimplementedSettingsServiceHandler) GetUserSettings(context.Context, *connect.Request[v1.GetUserSettingsRequest]) (*connect.Response[v1.GetUserSettingsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SettingsService.GetUserSettings is not implemented"))
+}
+
+func (UnimplementedSettingsServiceHandler) SetUserSettings(context.Context, *connect.Request[v1.SetUserSettingsRequest]) (*connect.Response[v1.SetUserSettingsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("settings.v1.SettingsService.SetUserSettings is not implemented"))
+}
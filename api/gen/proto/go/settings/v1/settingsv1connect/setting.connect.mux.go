@@ -29,4 +29,19 @@ func RegisterSettingsServiceHandler(mux *mux.Router, svc SettingsServiceHandler,
 		svc.Set,
 		opts...,
 	))
+	mux.Handle("/settings.v1.SettingsService/GetViewConfig", connect.NewUnaryHandler(
+		"/settings.v1.SettingsService/GetViewConfig",
+		svc.GetViewConfig,
+		opts...,
+	))
+	mux.Handle("/settings.v1.SettingsService/GetUserSettings", connect.NewUnaryHandler(
+		"/settings.v1.SettingsService/GetUserSettings",
+		svc.GetUserSettings,
+		opts...,
+	))
+	mux.Handle("/settings.v1.SettingsService/SetUserSettings", connect.NewUnaryHandler(
+		"/settings.v1.SettingsService/SetUserSettings",
+		svc.SetUserSettings,
+		opts...,
+	))
 }
@@ -0,0 +1,178 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: snapshots/v1/snapshots.proto
+
+package snapshotsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/grafana/pyroscope/api/gen/proto/go/snapshots/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// SnapshotServiceName is the fully-qualified name of the SnapshotService service.
+	SnapshotServiceName = "snapshots.v1.SnapshotService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// SnapshotServiceCreateProcedure is the fully-qualified name of the SnapshotService's Create RPC.
+	SnapshotServiceCreateProcedure = "/snapshots.v1.SnapshotService/Create"
+	// SnapshotServiceGetProcedure is the fully-qualified name of the SnapshotService's Get RPC.
+	SnapshotServiceGetProcedure = "/snapshots.v1.SnapshotService/Get"
+	// SnapshotServiceDeleteProcedure is the fully-qualified name of the SnapshotService's Delete RPC.
+	SnapshotServiceDeleteProcedure = "/snapshots.v1.SnapshotService/Delete"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	snapshotServiceServiceDescriptor      = v1.File_snapshots_v1_snapshots_proto.Services().ByName("SnapshotService")
+	snapshotServiceCreateMethodDescriptor = snapshotServiceServiceDescriptor.Methods().ByName("Create")
+	snapshotServiceGetMethodDescriptor    = snapshotServiceServiceDescriptor.Methods().ByName("Get")
+	snapshotServiceDeleteMethodDescriptor = snapshotServiceServiceDescriptor.Methods().ByName("Delete")
+)
+
+// SnapshotServiceClient is a client for the snapshots.v1.SnapshotService service.
+type SnapshotServiceClient interface {
+	// Create runs the query and freezes the merged profile into an immutable
+	// snapshot. The response contains the token the snapshot can be accessed by.
+	Create(context.Context, *connect.Request[v1.CreateSnapshotRequest]) (*connect.Response[v1.CreateSnapshotResponse], error)
+	// Get retrieves a snapshot of the tenant by token.
+	Get(context.Context, *connect.Request[v1.GetSnapshotRequest]) (*connect.Response[v1.GetSnapshotResponse], error)
+	// Delete removes a snapshot of the tenant before it expires.
+	Delete(context.Context, *connect.Request[v1.DeleteSnapshotRequest]) (*connect.Response[v1.DeleteSnapshotResponse], error)
+}
+
+// NewSnapshotServiceClient constructs a client for the snapshots.v1.SnapshotService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewSnapshotServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) SnapshotServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &snapshotServiceClient{
+		create: connect.NewClient[v1.CreateSnapshotRequest, v1.CreateSnapshotResponse](
+			httpClient,
+			baseURL+SnapshotServiceCreateProcedure,
+			connect.WithSchema(snapshotServiceCreateMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		get: connect.NewClient[v1.GetSnapshotRequest, v1.GetSnapshotResponse](
+			httpClient,
+			baseURL+SnapshotServiceGetProcedure,
+			connect.WithSchema(snapshotServiceGetMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		delete: connect.NewClient[v1.DeleteSnapshotRequest, v1.DeleteSnapshotResponse](
+			httpClient,
+			baseURL+SnapshotServiceDeleteProcedure,
+			connect.WithSchema(snapshotServiceDeleteMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// snapshotServiceClient implements SnapshotServiceClient.
+type snapshotServiceClient struct {
+	create *connect.Client[v1.CreateSnapshotRequest, v1.CreateSnapshotResponse]
+	get    *connect.Client[v1.GetSnapshotRequest, v1.GetSnapshotResponse]
+	delete *connect.Client[v1.DeleteSnapshotRequest, v1.DeleteSnapshotResponse]
+}
+
+// Create calls snapshots.v1.SnapshotService.Create.
+func (c *snapshotServiceClient) Create(ctx context.Context, req *connect.Request[v1.CreateSnapshotRequest]) (*connect.Response[v1.CreateSnapshotResponse], error) {
+	return c.create.CallUnary(ctx, req)
+}
+
+// Get calls snapshots.v1.SnapshotService.Get.
+func (c *snapshotServiceClient) Get(ctx context.Context, req *connect.Request[v1.GetSnapshotRequest]) (*connect.Response[v1.GetSnapshotResponse], error) {
+	return c.get.CallUnary(ctx, req)
+}
+
+// Delete calls snapshots.v1.SnapshotService.Delete.
+func (c *snapshotServiceClient) Delete(ctx context.Context, req *connect.Request[v1.DeleteSnapshotRequest]) (*connect.Response[v1.DeleteSnapshotResponse], error) {
+	return c.delete.CallUnary(ctx, req)
+}
+
+// SnapshotServiceHandler is an implementation of the snapshots.v1.SnapshotService service.
+type SnapshotServiceHandler interface {
+	// Create runs the query and freezes the merged profile into an immutable
+	// snapshot. The response contains the token the snapshot can be accessed by.
+	Create(context.Context, *connect.Request[v1.CreateSnapshotRequest]) (*connect.Response[v1.CreateSnapshotResponse], error)
+	// Get retrieves a snapshot of the tenant by token.
+	Get(context.Context, *connect.Request[v1.GetSnapshotRequest]) (*connect.Response[v1.GetSnapshotResponse], error)
+	// Delete removes a snapshot of the tenant before it expires.
+	Delete(context.Context, *connect.Request[v1.DeleteSnapshotRequest]) (*connect.Response[v1.DeleteSnapshotResponse], error)
+}
+
+// NewSnapshotServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewSnapshotServiceHandler(svc SnapshotServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	snapshotServiceCreateHandler := connect.NewUnaryHandler(
+		SnapshotServiceCreateProcedure,
+		svc.Create,
+		connect.WithSchema(snapshotServiceCreateMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	snapshotServiceGetHandler := connect.NewUnaryHandler(
+		SnapshotServiceGetProcedure,
+		svc.Get,
+		connect.WithSchema(snapshotServiceGetMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	snapshotServiceDeleteHandler := connect.NewUnaryHandler(
+		SnapshotServiceDeleteProcedure,
+		svc.Delete,
+		connect.WithSchema(snapshotServiceDeleteMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/snapshots.v1.SnapshotService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case SnapshotServiceCreateProcedure:
+			snapshotServiceCreateHandler.ServeHTTP(w, r)
+		case SnapshotServiceGetProcedure:
+			snapshotServiceGetHandler.ServeHTTP(w, r)
+		case SnapshotServiceDeleteProcedure:
+			snapshotServiceDeleteHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedSnapshotServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedSnapshotServiceHandler struct{}
+
+func (UnimplementedSnapshotServiceHandler) Create(context.Context, *connect.Request[v1.CreateSnapshotRequest]) (*connect.Response[v1.CreateSnapshotResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("snapshots.v1.SnapshotService.Create is not implemented"))
+}
+
+func (UnimplementedSnapshotServiceHandler) Get(context.Context, *connect.Request[v1.GetSnapshotRequest]) (*connect.Response[v1.GetSnapshotResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("snapshots.v1.SnapshotService.Get is not implemented"))
+}
+
+func (UnimplementedSnapshotServiceHandler) Delete(context.Context, *connect.Request[v1.DeleteSnapshotRequest]) (*connect.Response[v1.DeleteSnapshotResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("snapshots.v1.SnapshotService.Delete is not implemented"))
+}
@@ -0,0 +1,37 @@
+// Code generated by protoc-gen-connect-go-mux. DO NOT EDIT.
+//
+// Source: snapshots/v1/snapshots.proto
+
+package snapshotsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	mux "github.com/gorilla/mux"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion0_1_0
+
+// RegisterSnapshotServiceHandler register an HTTP handler to a mux.Router from the service
+// implementation.
+func RegisterSnapshotServiceHandler(mux *mux.Router, svc SnapshotServiceHandler, opts ...connect.HandlerOption) {
+	mux.Handle("/snapshots.v1.SnapshotService/Create", connect.NewUnaryHandler(
+		"/snapshots.v1.SnapshotService/Create",
+		svc.Create,
+		opts...,
+	))
+	mux.Handle("/snapshots.v1.SnapshotService/Get", connect.NewUnaryHandler(
+		"/snapshots.v1.SnapshotService/Get",
+		svc.Get,
+		opts...,
+	))
+	mux.Handle("/snapshots.v1.SnapshotService/Delete", connect.NewUnaryHandler(
+		"/snapshots.v1.SnapshotService/Delete",
+		svc.Delete,
+		opts...,
+	))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	apikeysv1 "github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1/apikeysv1connect"
+	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
+)
+
+func (c *phlareClient) apiKeysClient() apikeysv1connect.APIKeyServiceClient {
+	return apikeysv1connect.NewAPIKeyServiceClient(
+		c.httpClient(),
+		c.URL,
+		append(
+			connectapi.DefaultClientOptions(),
+			c.protocolOption(),
+		)...,
+	)
+}
+
+type apiKeysCreateParams struct {
+	*phlareClient
+
+	Name        string
+	Permissions []string
+	ExpiresIn   time.Duration
+}
+
+func addAPIKeysCreateParams(cmd commander) *apiKeysCreateParams {
+	params := &apiKeysCreateParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	cmd.Arg("name", "Name of the API key.").Required().StringVar(&params.Name)
+	cmd.Flag("permission", "Permission granted by the API key: ingest, query, or admin. Can be specified multiple times.").Required().EnumsVar(&params.Permissions, "ingest", "query", "admin")
+	cmd.Flag("expires-in", "Duration after which the API key expires. The key never expires if not set.").Default("0").DurationVar(&params.ExpiresIn)
+
+	return params
+}
+
+func apiKeysCreate(ctx context.Context, params *apiKeysCreateParams) error {
+	req := &apikeysv1.CreateAPIKeyRequest{Name: params.Name}
+	for _, p := range params.Permissions {
+		req.Permissions = append(req.Permissions, apikeysv1.Permission(apikeysv1.Permission_value["PERMISSION_"+strings.ToUpper(p)]))
+	}
+	if params.ExpiresIn > 0 {
+		req.ExpiresAt = time.Now().Add(params.ExpiresIn).UnixMilli()
+	}
+
+	res, err := params.apiKeysClient().CreateAPIKey(ctx, connect.NewRequest(req))
+	if err != nil {
+		return err
+	}
+
+	return printAPIKeysJSON(res.Msg)
+}
+
+type apiKeysRevokeParams struct {
+	*phlareClient
+
+	ID string
+}
+
+func addAPIKeysRevokeParams(cmd commander) *apiKeysRevokeParams {
+	params := &apiKeysRevokeParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	cmd.Arg("id", "ID of the API key.").Required().StringVar(&params.ID)
+
+	return params
+}
+
+func apiKeysRevoke(ctx context.Context, params *apiKeysRevokeParams) error {
+	res, err := params.apiKeysClient().RevokeAPIKey(ctx, connect.NewRequest(&apikeysv1.RevokeAPIKeyRequest{
+		Id: params.ID,
+	}))
+	if err != nil {
+		return err
+	}
+
+	return printAPIKeysJSON(res.Msg)
+}
+
+type apiKeysListParams struct {
+	*phlareClient
+}
+
+func addAPIKeysListParams(cmd commander) *apiKeysListParams {
+	params := &apiKeysListParams{}
+	params.phlareClient = addPhlareClient(cmd)
+	return params
+}
+
+func apiKeysList(ctx context.Context, params *apiKeysListParams) error {
+	res, err := params.apiKeysClient().ListAPIKeys(ctx, connect.NewRequest(&apikeysv1.ListAPIKeysRequest{}))
+	if err != nil {
+		return err
+	}
+
+	return printAPIKeysJSON(res.Msg)
+}
+
+func printAPIKeysJSON(msg proto.Message) error {
+	opts := protojson.MarshalOptions{
+		Multiline: true,
+		Indent:    "  ",
+	}
+
+	b, err := opts.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
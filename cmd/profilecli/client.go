@@ -26,6 +26,7 @@ type phlareClient struct {
 		Username string
 		Password string
 	}
+	Token            string
 	defaultTransport http.RoundTripper
 	client           *http.Client
 	protocol         string
@@ -44,6 +45,9 @@ func (a *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		if c.BasicAuth.Username != "" || c.BasicAuth.Password != "" {
 			req.SetBasicAuth(c.BasicAuth.Username, c.BasicAuth.Password)
 		}
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
 	}
 
 	req.Header.Set("User-Agent", userAgentHeader)
@@ -88,6 +92,7 @@ func addPhlareClient(cmd commander) *phlareClient {
 	cmd.Flag("tenant-id", "The tenant ID to be used for the X-Scope-OrgID header.").Default("").Envar(envPrefix + "TENANT_ID").StringVar(&client.TenantID)
 	cmd.Flag("username", "The username to be used for basic auth.").Default("").Envar(envPrefix + "USERNAME").StringVar(&client.BasicAuth.Username)
 	cmd.Flag("password", "The password to be used for basic auth.").Default("").Envar(envPrefix + "PASSWORD").StringVar(&client.BasicAuth.Password)
+	cmd.Flag("token", "The API key to be used as the bearer token.").Default("").Envar(envPrefix + "TOKEN").StringVar(&client.Token)
 	cmd.Flag("protocol", "The protocol to be used for communicating with the server.").Default(protocolTypeConnect).EnumVar(&client.protocol,
 		protocolTypeConnect, protocolTypeGRPC, protocolTypeGRPCWeb)
 	return client
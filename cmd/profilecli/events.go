@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/metastorev1connect"
+	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
+	"github.com/grafana/pyroscope/pkg/operations"
+)
+
+func (c *phlareClient) eventClient() metastorev1connect.EventServiceClient {
+	return metastorev1connect.NewEventServiceClient(
+		c.httpClient(),
+		c.URL,
+		append(
+			connectapi.DefaultClientOptions(),
+			c.protocolOption(),
+		)...,
+	)
+}
+
+var eventTypes = []string{"block-added", "blocks-compacted", "blocks-deleted", "block-quarantined"}
+
+type queryEventsParams struct {
+	*phlareClient
+
+	Tenants  []string
+	Services []string
+	Types    []string
+	From     string
+	To       string
+	Limit    uint32
+}
+
+func addQueryEventsParams(cmd commander) *queryEventsParams {
+	params := &queryEventsParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	cmd.Flag("tenant", "Tenant to query the events of. Can be specified multiple times.").Required().StringsVar(&params.Tenants)
+	cmd.Flag("service", "Only query the events of the service. Can be specified multiple times.").StringsVar(&params.Services)
+	cmd.Flag("type", "Only query the events of the type: "+strings.Join(eventTypes, ", ")+". Can be specified multiple times.").EnumsVar(&params.Types, eventTypes...)
+	cmd.Flag("from", "Beginning of the query.").Default("now-24h").StringVar(&params.From)
+	cmd.Flag("to", "End of the query.").Default("now").StringVar(&params.To)
+	cmd.Flag("limit", "Maximum number of the most recent events to return. 0 for no limit.").Default("1000").Uint32Var(&params.Limit)
+
+	return params
+}
+
+func queryEvents(ctx context.Context, params *queryEventsParams) error {
+	from, err := operations.ParseTime(params.From)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse from")
+	}
+	to, err := operations.ParseTime(params.To)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse to")
+	}
+
+	req := &metastorev1.QueryEventsRequest{
+		Tenant:      params.Tenants,
+		StartTime:   from.UnixMilli(),
+		EndTime:     to.UnixMilli(),
+		ServiceName: params.Services,
+		Limit:       params.Limit,
+	}
+	for _, t := range params.Types {
+		name := "EVENT_TYPE_" + strings.ToUpper(strings.ReplaceAll(t, "-", "_"))
+		req.Type = append(req.Type, metastorev1.EventType(metastorev1.EventType_value[name]))
+	}
+
+	res, err := params.eventClient().QueryEvents(ctx, connect.NewRequest(req))
+	if err != nil {
+		return err
+	}
+
+	opts := protojson.MarshalOptions{
+		Multiline: true,
+		Indent:    "  ",
+	}
+	b, err := opts.Marshal(res.Msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/runutil"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/pyroscope/pkg/objstore"
+	objstoreclient "github.com/grafana/pyroscope/pkg/objstore/client"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/filesystem"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/gcs"
+	"github.com/grafana/pyroscope/pkg/operations"
+	"github.com/grafana/pyroscope/pkg/phlaredb"
+	"github.com/grafana/pyroscope/pkg/phlaredb/block"
+	"github.com/grafana/pyroscope/pkg/phlaredb/export"
+)
+
+type blocksExportParams struct {
+	*blocksQueryParams
+	From                string
+	To                  string
+	OutputDir           string
+	DestBucketName      string
+	DestObjectStoreType string
+	DestPrefix          string
+}
+
+func addBlocksExportParams(exportCmd commander) *blocksExportParams {
+	params := new(blocksExportParams)
+	params.blocksQueryParams = addBlocksQueryParams(exportCmd)
+	exportCmd.Flag("from", "Only export the profiles collected after the time. If not set, the profiles are exported from the beginning of the blocks.").StringVar(&params.From)
+	exportCmd.Flag("to", "Only export the profiles collected before the time. If not set, the profiles are exported up to the end of the blocks.").StringVar(&params.To)
+	exportCmd.Flag("output-dir", "The local directory the tables are written to, if no destination bucket is specified.").Default("./export").StringVar(&params.OutputDir)
+	exportCmd.Flag("dest-bucket-name", "The name of the object storage bucket the tables are uploaded to.").StringVar(&params.DestBucketName)
+	exportCmd.Flag("dest-object-store-type", "The type of the destination object storage (e.g., gcs).").Default("gcs").StringVar(&params.DestObjectStoreType)
+	exportCmd.Flag("dest-prefix", "The prefix of the tables in the destination bucket.").StringVar(&params.DestPrefix)
+	return params
+}
+
+func (p *blocksExportParams) parseFromTo() (from time.Time, to time.Time, err error) {
+	if p.From != "" {
+		if from, err = operations.ParseTime(p.From); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(err, "failed to parse from")
+		}
+	}
+	if p.To != "" {
+		if to, err = operations.ParseTime(p.To); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(err, "failed to parse to")
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("from cannot be after to")
+	}
+	return from, to, nil
+}
+
+func (p *blocksExportParams) destination(ctx context.Context) (objstore.Bucket, error) {
+	if p.DestBucketName == "" {
+		return filesystem.NewBucket(p.OutputDir)
+	}
+	return objstoreclient.NewBucket(ctx, objstoreclient.Config{
+		StorageBackendConfig: objstoreclient.StorageBackendConfig{
+			Backend: p.DestObjectStoreType,
+			GCS: gcs.Config{
+				BucketName: p.DestBucketName,
+			},
+		},
+		StoragePrefix: p.DestPrefix,
+	}, p.DestBucketName)
+}
+
+func blocksExport(ctx context.Context, params *blocksExportParams) (err error) {
+	level.Info(logger).Log("msg", "blocks export", "blockIds", fmt.Sprintf("%v", params.BlockIds), "path", cfg.blocks.path,
+		"bucketName", params.BucketName, "tenantId", params.TenantID, "query", params.Query, "from", params.From, "to", params.To)
+
+	matchers, err := parser.ParseMetricSelector(params.Query)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse query")
+	}
+	from, to, err := params.parseFromTo()
+	if err != nil {
+		return err
+	}
+
+	bucket, err := getBucket(ctx, params.blocksQueryParams)
+	if err != nil {
+		return err
+	}
+	dst, err := params.destination(ctx)
+	if err != nil {
+		return err
+	}
+	defer runutil.CloseWithErrCapture(&err, dst, "close destination bucket")
+
+	metas, err := selectBlocks(ctx, bucket, params.BlockIds, from, to)
+	if err != nil {
+		return err
+	}
+	if len(metas) == 0 {
+		return errors.New("no blocks to export")
+	}
+
+	table := tablewriter.NewWriter(output(ctx))
+	table.SetHeader([]string{"Block ID", "Profiles", "Samples", "Stacks", "Symbols"})
+	for _, meta := range metas {
+		level.Info(logger).Log("msg", "exporting block", "block", meta.ULID)
+		stats, err := exportBlock(ctx, bucket, dst, meta, export.Options{Matchers: matchers, Start: from, End: to})
+		if err != nil {
+			return errors.Wrapf(err, "failed to export block %s", meta.ULID)
+		}
+		table.Append([]string{
+			meta.ULID.String(),
+			fmt.Sprint(stats.Profiles),
+			fmt.Sprint(stats.Samples),
+			fmt.Sprint(stats.Stacks),
+			fmt.Sprint(stats.Symbols),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// selectBlocks returns the blocks specified, or all the blocks
+// overlapping the time range, if no block is specified.
+func selectBlocks(ctx context.Context, bucket objstore.Bucket, blockIDs []string, from, to time.Time) ([]*block.Meta, error) {
+	querier := phlaredb.NewBlockQuerier(ctx, bucket)
+	if len(blockIDs) > 0 {
+		metas := make([]*block.Meta, 0, len(blockIDs))
+		for _, id := range blockIDs {
+			meta, err := querier.BlockMeta(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			metas = append(metas, meta)
+		}
+		return metas, nil
+	}
+	all, err := querier.BlockMetas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metas := make([]*block.Meta, 0, len(all))
+	for _, meta := range all {
+		if !from.IsZero() && meta.MaxTime < model.TimeFromUnixNano(from.UnixNano()) {
+			continue
+		}
+		if !to.IsZero() && meta.MinTime > model.TimeFromUnixNano(to.UnixNano()) {
+			continue
+		}
+		metas = append(metas, meta)
+	}
+	return metas, nil
+}
+
+func exportBlock(ctx context.Context, bucket objstore.Bucket, dst objstore.Bucket, meta *block.Meta, opts export.Options) (export.Stats, error) {
+	b := phlaredb.NewSingleBlockQuerierFromMeta(ctx, bucket, meta)
+	if err := b.Open(ctx); err != nil {
+		return export.Stats{}, err
+	}
+	defer b.Close()
+	return export.Block(ctx, b, dst, opts)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/metastorev1connect"
+	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
+)
+
+func (c *phlareClient) indexClient() metastorev1connect.IndexServiceClient {
+	return metastorev1connect.NewIndexServiceClient(
+		c.httpClient(),
+		c.URL,
+		append(
+			connectapi.DefaultClientOptions(),
+			c.protocolOption(),
+		)...,
+	)
+}
+
+type checkIndexParams struct {
+	*phlareClient
+
+	CheckObjects bool
+	Repair       bool
+}
+
+func addCheckIndexParams(cmd commander) *checkIndexParams {
+	params := &checkIndexParams{}
+	params.phlareClient = addPhlareClient(cmd)
+
+	cmd.Flag("check-objects", "Check that the objects of the blocks exist in the object storage. A request is made per block.").BoolVar(&params.CheckObjects)
+	cmd.Flag("repair", "Resolve the issues of the index on all the metastore replicas. Missing block objects are not repaired.").BoolVar(&params.Repair)
+
+	return params
+}
+
+func checkIndex(ctx context.Context, params *checkIndexParams) error {
+	res, err := params.indexClient().CheckIndex(ctx, connect.NewRequest(&metastorev1.CheckIndexRequest{
+		CheckObjects: params.CheckObjects,
+		Repair:       params.Repair,
+	}))
+	if err != nil {
+		return err
+	}
+
+	opts := protojson.MarshalOptions{
+		Multiline: true,
+		Indent:    "  ",
+	}
+	b, err := opts.Marshal(res.Msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -61,6 +61,8 @@ func main() {
 	blocksQuerySeriesParams := addBlocksQuerySeriesParams(blocksQuerySeriesCmd)
 	blocksQueryProfileCmd := blocksQueryCmd.Command("profile", "Request merged profile on local/remote block.").Alias("merge")
 	blocksQueryProfileParams := addBlocksQueryProfileParams(blocksQueryProfileCmd)
+	blocksExportCmd := blocksCmd.Command("export", "Export the profiles of local/remote blocks as flat Parquet tables (samples, stacks, and symbols) for external analytics tools.")
+	blocksExportParams := addBlocksExportParams(blocksExportCmd)
 
 	parquetCmd := adminCmd.Command("parquet", "Operate on a Parquet file.")
 	parquetInspectCmd := parquetCmd.Command("inspect", "Inspect a parquet file's structure.")
@@ -81,6 +83,8 @@ func main() {
 	querySeriesParams := addQuerySeriesParams(querySeriesCmd)
 	queryLabelValuesCardinalityCmd := queryCmd.Command("label-values-cardinality", "Request label values cardinality.")
 	queryLabelValuesCardinalityParams := addQueryLabelValuesCardinalityParams(queryLabelValuesCardinalityCmd)
+	queryExplainCmd := queryCmd.Command("explain", "Show the query plan without executing the query.")
+	queryExplainParams := addQueryExplainParams(queryExplainCmd)
 
 	queryTracerCmd := app.Command("query-tracer", "Analyze query traces.")
 	queryTracerParams := addQueryTracerParams(queryTracerCmd)
@@ -102,6 +106,24 @@ func main() {
 	raftInfoCmd := raftCmd.Command("info", "Print info about a Raft node.")
 	raftInfoParams := addRaftInfoParams(raftInfoCmd)
 
+	apiKeysCmd := adminCmd.Command("api-keys", "Manage API keys of a tenant.")
+	apiKeysCreateCmd := apiKeysCmd.Command("create", "Create an API key. The token is only printed once.")
+	apiKeysCreateParams := addAPIKeysCreateParams(apiKeysCreateCmd)
+	apiKeysRevokeCmd := apiKeysCmd.Command("revoke", "Revoke an API key.")
+	apiKeysRevokeParams := addAPIKeysRevokeParams(apiKeysRevokeCmd)
+	apiKeysListCmd := apiKeysCmd.Command("list", "List API keys.")
+	apiKeysListParams := addAPIKeysListParams(apiKeysListCmd)
+
+	eventsCmd := adminCmd.Command("events", "Query the storage lifecycle events recorded by the metastore: blocks added, compacted, deleted, and quarantined.")
+	eventsParams := addQueryEventsParams(eventsCmd)
+
+	indexCmd := adminCmd.Command("index", "Operate on the metastore index.")
+	indexCheckCmd := indexCmd.Command("check", "Cross-check the metastore index in memory against the database, and optionally, against the object storage.")
+	indexCheckParams := addCheckIndexParams(indexCheckCmd)
+
+	generateMonitoringCmd := adminCmd.Command("generate-monitoring", "Generate Grafana dashboards and Prometheus alerting rules for the components of the new architecture.")
+	generateMonitoringParams := addGenerateMonitoringParams(generateMonitoringCmd)
+
 	// parse command line arguments
 	parsedCmd := kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -146,11 +168,19 @@ func main() {
 		if err := blocksQueryProfile(ctx, blocksQueryProfileParams); err != nil {
 			os.Exit(checkError(err))
 		}
+	case blocksExportCmd.FullCommand():
+		if err := blocksExport(ctx, blocksExportParams); err != nil {
+			os.Exit(checkError(err))
+		}
 
 	case queryLabelValuesCardinalityCmd.FullCommand():
 		if err := queryLabelValuesCardinality(ctx, queryLabelValuesCardinalityParams); err != nil {
 			os.Exit(checkError(err))
 		}
+	case queryExplainCmd.FullCommand():
+		if err := queryExplain(ctx, queryExplainParams); err != nil {
+			os.Exit(checkError(err))
+		}
 
 	case queryTracerCmd.FullCommand():
 		if err := queryTracer(ctx, queryTracerParams); err != nil {
@@ -181,6 +211,30 @@ func main() {
 		if err := raftInfo(ctx, raftInfoParams); err != nil {
 			os.Exit(checkError(err))
 		}
+	case apiKeysCreateCmd.FullCommand():
+		if err := apiKeysCreate(ctx, apiKeysCreateParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case apiKeysRevokeCmd.FullCommand():
+		if err := apiKeysRevoke(ctx, apiKeysRevokeParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case apiKeysListCmd.FullCommand():
+		if err := apiKeysList(ctx, apiKeysListParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case eventsCmd.FullCommand():
+		if err := queryEvents(ctx, eventsParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case indexCheckCmd.FullCommand():
+		if err := checkIndex(ctx, indexCheckParams); err != nil {
+			os.Exit(checkError(err))
+		}
+	case generateMonitoringCmd.FullCommand():
+		if err := generateMonitoring(ctx, generateMonitoringParams); err != nil {
+			os.Exit(checkError(err))
+		}
 	default:
 		level.Error(logger).Log("msg", "unknown command", "cmd", parsedCmd)
 	}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/pyroscope/pkg/monitoring"
+)
+
+type generateMonitoringParams struct {
+	Components []string
+	OutputDir  string
+}
+
+func addGenerateMonitoringParams(cmd commander) *generateMonitoringParams {
+	params := &generateMonitoringParams{}
+	cmd.Flag("component", "Component to generate the dashboard and alerting rules for. Can be specified multiple times. All components are included if not set.").EnumsVar(&params.Components, monitoring.ComponentNames()...)
+	cmd.Flag("output-dir", "Directory the dashboards and the alerting rules file are written to.").Default("./monitoring").StringVar(&params.OutputDir)
+	return params
+}
+
+func generateMonitoring(_ context.Context, params *generateMonitoringParams) error {
+	components, err := monitoring.LookupComponents(params.Components...)
+	if err != nil {
+		return err
+	}
+	dashboardsDir := filepath.Join(params.OutputDir, "dashboards")
+	if err = os.MkdirAll(dashboardsDir, 0o755); err != nil {
+		return err
+	}
+	for _, c := range components {
+		dashboard, err := monitoring.GenerateDashboard(c)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dashboardsDir, "pyroscope-"+c.Name+".json")
+		if err = os.WriteFile(path, dashboard, 0o644); err != nil {
+			return err
+		}
+		level.Info(logger).Log("msg", "dashboard written", "component", c.Name, "path", path)
+	}
+	rules, err := monitoring.GenerateRules(components)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(params.OutputDir, "alerts.yaml")
+	if err = os.WriteFile(path, rules, 0o644); err != nil {
+		return err
+	}
+	level.Info(logger).Log("msg", "alerting rules written", "path", path)
+	return nil
+}
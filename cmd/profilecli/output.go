@@ -15,8 +15,10 @@ import (
 	"github.com/klauspost/compress/gzip"
 	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	googlev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
 	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
 )
 
@@ -41,6 +43,15 @@ func outputSeries(result []*typesv1.Labels) error {
 	return nil
 }
 
+func outputExplain(result *querierv1.ExplainResponse) error {
+	b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(b))
+	return err
+}
+
 func outputMergeProfile(ctx context.Context, outputFlag string, profile *googlev1.Profile) error {
 	mypp := pp.New()
 	mypp.SetColoringEnabled(isatty.IsTerminal(os.Stdout.Fd()))
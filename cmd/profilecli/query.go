@@ -256,6 +256,44 @@ func querySeries(ctx context.Context, params *querySeriesParams) (err error) {
 	return err
 }
 
+type queryExplainParams struct {
+	*queryParams
+	ProfileType string
+	MaxNodes    int64
+}
+
+func addQueryExplainParams(queryCmd commander) *queryExplainParams {
+	params := new(queryExplainParams)
+	params.queryParams = addQueryParams(queryCmd)
+	queryCmd.Flag("profile-type", "Profile type to query.").Default("process_cpu:cpu:nanoseconds:cpu:nanoseconds").StringVar(&params.ProfileType)
+	queryCmd.Flag("max-nodes", "Maximum number of flame graph nodes. 0 to use the tenant default.").Default("0").Int64Var(&params.MaxNodes)
+	return params
+}
+
+func queryExplain(ctx context.Context, params *queryExplainParams) (err error) {
+	from, to, err := params.parseFromTo()
+	if err != nil {
+		return err
+	}
+	level.Info(logger).Log("msg", "explain query", "url", params.URL, "from", from, "to", to, "query", params.Query, "type", params.ProfileType)
+
+	req := &querierv1.ExplainRequest{
+		ProfileTypeID: params.ProfileType,
+		LabelSelector: params.Query,
+		Start:         from.UnixMilli(),
+		End:           to.UnixMilli(),
+	}
+	if params.MaxNodes > 0 {
+		req.MaxNodes = &params.MaxNodes
+	}
+	resp, err := params.phlareClient.queryClient().Explain(ctx, connect.NewRequest(req))
+	if err != nil {
+		return errors.Wrap(err, "failed to explain query")
+	}
+
+	return outputExplain(resp.Msg)
+}
+
 type queryLabelValuesCardinalityParams struct {
 	*queryParams
 	TopN uint64
@@ -0,0 +1,276 @@
+// Package convert converts profiles between the pprof format, used by the
+// Pyroscope push API, and the OpenTelemetry profiles data model.
+package convert
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	googlev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+)
+
+// DecodePprof decodes the pprof profile, which may be gzip-compressed.
+func DecodePprof(b []byte) (*googlev1.Profile, error) {
+	if len(b) >= 2 && b[0] == 0x1f && b[1] == 0x8b {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("decompressing profile: %w", err)
+		}
+		if b, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("decompressing profile: %w", err)
+		}
+	}
+	var p googlev1.Profile
+	if err := p.UnmarshalVT(b); err != nil {
+		return nil, fmt.Errorf("decoding profile: %w", err)
+	}
+	return &p, nil
+}
+
+// ToOTLP converts the pprof profile to the OpenTelemetry profile dst.
+// References by ID are replaced with indices into the profile tables.
+func ToOTLP(src *googlev1.Profile, dst pprofile.Profile) {
+	dst.StringTable().FromRaw(src.StringTable)
+	dst.Comment().FromRaw(src.Comment)
+	dst.SetDropFrames(src.DropFrames)
+	dst.SetKeepFrames(src.KeepFrames)
+	dst.SetStartTime(pcommon.Timestamp(src.TimeNanos))
+	dst.SetDuration(pcommon.Timestamp(src.DurationNanos))
+	dst.SetPeriod(src.Period)
+	dst.SetDefaultSampleType(src.DefaultSampleType)
+	dst.SampleType().EnsureCapacity(len(src.SampleType))
+	for _, st := range src.SampleType {
+		vt := dst.SampleType().AppendEmpty()
+		vt.SetType(st.Type)
+		vt.SetUnit(st.Unit)
+	}
+	if src.PeriodType != nil {
+		dst.PeriodType().SetType(src.PeriodType.Type)
+		dst.PeriodType().SetUnit(src.PeriodType.Unit)
+	}
+
+	mappings := make(map[uint64]uint64, len(src.Mapping))
+	dst.Mapping().EnsureCapacity(len(src.Mapping))
+	for i, m := range src.Mapping {
+		mappings[m.Id] = uint64(i)
+		dm := dst.Mapping().AppendEmpty()
+		dm.SetMemoryStart(m.MemoryStart)
+		dm.SetMemoryLimit(m.MemoryLimit)
+		dm.SetFileOffset(m.FileOffset)
+		dm.SetFilename(m.Filename)
+		dm.SetBuildID(m.BuildId)
+		dm.SetHasFunctions(m.HasFunctions)
+		dm.SetHasFilenames(m.HasFilenames)
+		dm.SetHasLineNumbers(m.HasLineNumbers)
+		dm.SetHasInlineFrames(m.HasInlineFrames)
+	}
+	functions := make(map[uint64]uint64, len(src.Function))
+	dst.Function().EnsureCapacity(len(src.Function))
+	for i, f := range src.Function {
+		functions[f.Id] = uint64(i)
+		df := dst.Function().AppendEmpty()
+		df.SetName(f.Name)
+		df.SetSystemName(f.SystemName)
+		df.SetFilename(f.Filename)
+		df.SetStartLine(f.StartLine)
+	}
+	locations := make(map[uint64]int64, len(src.Location))
+	dst.Location().EnsureCapacity(len(src.Location))
+	for i, l := range src.Location {
+		locations[l.Id] = int64(i)
+		dl := dst.Location().AppendEmpty()
+		dl.SetMappingIndex(mappings[l.MappingId])
+		dl.SetAddress(l.Address)
+		dl.SetIsFolded(l.IsFolded)
+		dl.Line().EnsureCapacity(len(l.Line))
+		for _, line := range l.Line {
+			dline := dl.Line().AppendEmpty()
+			dline.SetFunctionIndex(functions[line.FunctionId])
+			dline.SetLine(line.Line)
+		}
+	}
+
+	dst.Sample().EnsureCapacity(len(src.Sample))
+	for _, s := range src.Sample {
+		ds := dst.Sample().AppendEmpty()
+		ds.SetLocationsStartIndex(uint64(dst.LocationIndices().Len()))
+		ds.SetLocationsLength(uint64(len(s.LocationId)))
+		for _, id := range s.LocationId {
+			dst.LocationIndices().Append(locations[id])
+		}
+		ds.Value().FromRaw(s.Value)
+		for _, l := range s.Label {
+			dl := ds.Label().AppendEmpty()
+			dl.SetKey(l.Key)
+			dl.SetStr(l.Str)
+			dl.SetNum(l.Num)
+			dl.SetNumUnit(l.NumUnit)
+		}
+	}
+}
+
+// FromOTLP converts the OpenTelemetry profile to the pprof profile. String
+// and integer attributes of the samples are converted to pprof labels.
+func FromOTLP(src pprofile.Profile) *googlev1.Profile {
+	dst := &googlev1.Profile{
+		SampleType:        make([]*googlev1.ValueType, 0, src.SampleType().Len()),
+		Sample:            make([]*googlev1.Sample, 0, src.Sample().Len()),
+		Mapping:           make([]*googlev1.Mapping, 0, src.Mapping().Len()),
+		Location:          make([]*googlev1.Location, 0, src.Location().Len()),
+		Function:          make([]*googlev1.Function, 0, src.Function().Len()),
+		StringTable:       src.StringTable().AsRaw(),
+		DropFrames:        src.DropFrames(),
+		KeepFrames:        src.KeepFrames(),
+		TimeNanos:         int64(src.StartTime()),
+		DurationNanos:     int64(src.Duration()),
+		Period:            src.Period(),
+		Comment:           src.Comment().AsRaw(),
+		DefaultSampleType: src.DefaultSampleType(),
+	}
+	if len(dst.StringTable) == 0 {
+		dst.StringTable = []string{""}
+	}
+	for i := 0; i < src.SampleType().Len(); i++ {
+		st := src.SampleType().At(i)
+		dst.SampleType = append(dst.SampleType, &googlev1.ValueType{Type: st.Type(), Unit: st.Unit()})
+	}
+	if pt := src.PeriodType(); pt.Type() != 0 || pt.Unit() != 0 {
+		dst.PeriodType = &googlev1.ValueType{Type: pt.Type(), Unit: pt.Unit()}
+	}
+
+	for i := 0; i < src.Mapping().Len(); i++ {
+		m := src.Mapping().At(i)
+		dst.Mapping = append(dst.Mapping, &googlev1.Mapping{
+			Id:              uint64(i + 1),
+			MemoryStart:     m.MemoryStart(),
+			MemoryLimit:     m.MemoryLimit(),
+			FileOffset:      m.FileOffset(),
+			Filename:        m.Filename(),
+			BuildId:         m.BuildID(),
+			HasFunctions:    m.HasFunctions(),
+			HasFilenames:    m.HasFilenames(),
+			HasLineNumbers:  m.HasLineNumbers(),
+			HasInlineFrames: m.HasInlineFrames(),
+		})
+	}
+	for i := 0; i < src.Function().Len(); i++ {
+		f := src.Function().At(i)
+		dst.Function = append(dst.Function, &googlev1.Function{
+			Id:         uint64(i + 1),
+			Name:       f.Name(),
+			SystemName: f.SystemName(),
+			Filename:   f.Filename(),
+			StartLine:  f.StartLine(),
+		})
+	}
+	for i := 0; i < src.Location().Len(); i++ {
+		l := src.Location().At(i)
+		loc := &googlev1.Location{
+			Id:       uint64(i + 1),
+			Address:  l.Address(),
+			Line:     make([]*googlev1.Line, 0, l.Line().Len()),
+			IsFolded: l.IsFolded(),
+		}
+		if src.Mapping().Len() > 0 {
+			loc.MappingId = l.MappingIndex() + 1
+		}
+		for j := 0; j < l.Line().Len(); j++ {
+			line := l.Line().At(j)
+			loc.Line = append(loc.Line, &googlev1.Line{
+				FunctionId: line.FunctionIndex() + 1,
+				Line:       line.Line(),
+			})
+		}
+		dst.Location = append(dst.Location, loc)
+	}
+
+	table := newStringTable(dst)
+	attributes := attributeLabels(table, src.AttributeTable())
+	indices := src.LocationIndices().AsRaw()
+	for i := 0; i < src.Sample().Len(); i++ {
+		s := src.Sample().At(i)
+		sample := &googlev1.Sample{Value: s.Value().AsRaw()}
+		if s.LocationsLength() > 0 {
+			end := min(s.LocationsStartIndex()+s.LocationsLength(), uint64(len(indices)))
+			for _, idx := range indices[min(s.LocationsStartIndex(), end):end] {
+				sample.LocationId = append(sample.LocationId, uint64(idx+1))
+			}
+		} else {
+			// Deprecated location references.
+			for _, idx := range s.LocationIndex().AsRaw() {
+				sample.LocationId = append(sample.LocationId, idx+1)
+			}
+		}
+		if len(sample.Value) == 0 {
+			sample.Value = []int64{int64(s.TimestampsUnixNano().Len())}
+		}
+		for j := 0; j < s.Label().Len(); j++ {
+			l := s.Label().At(j)
+			sample.Label = append(sample.Label, &googlev1.Label{
+				Key:     l.Key(),
+				Str:     l.Str(),
+				Num:     l.Num(),
+				NumUnit: l.NumUnit(),
+			})
+		}
+		for _, idx := range s.Attributes().AsRaw() {
+			if idx < uint64(len(attributes)) && attributes[idx] != nil {
+				sample.Label = append(sample.Label, attributes[idx])
+			}
+		}
+		dst.Sample = append(dst.Sample, sample)
+	}
+	return dst
+}
+
+// attributeLabels converts the attribute table to pprof labels, in the
+// table order. Attributes of other types than string and integer are nil.
+func attributeLabels(table *stringTable, attrs pcommon.Map) []*googlev1.Label {
+	labels := make([]*googlev1.Label, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		var label *googlev1.Label
+		switch v.Type() {
+		case pcommon.ValueTypeStr:
+			label = &googlev1.Label{Key: table.index(k), Str: table.index(v.Str())}
+		case pcommon.ValueTypeInt:
+			label = &googlev1.Label{Key: table.index(k), Num: v.Int()}
+		}
+		labels = append(labels, label)
+		return true
+	})
+	return labels
+}
+
+// stringTable appends the strings missing in the profile string table.
+type stringTable struct {
+	profile *googlev1.Profile
+	indices map[string]int64
+}
+
+func newStringTable(p *googlev1.Profile) *stringTable {
+	t := &stringTable{
+		profile: p,
+		indices: make(map[string]int64, len(p.StringTable)),
+	}
+	for i, s := range p.StringTable {
+		if _, ok := t.indices[s]; !ok {
+			t.indices[s] = int64(i)
+		}
+	}
+	return t
+}
+
+func (t *stringTable) index(s string) int64 {
+	if i, ok := t.indices[s]; ok {
+		return i
+	}
+	i := int64(len(t.profile.StringTable))
+	t.profile.StringTable = append(t.profile.StringTable, s)
+	t.indices[s] = i
+	return i
+}
@@ -0,0 +1,101 @@
+package convert
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	googlev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+)
+
+func testProfile() *googlev1.Profile {
+	return &googlev1.Profile{
+		SampleType: []*googlev1.ValueType{{Type: 1, Unit: 2}},
+		Sample: []*googlev1.Sample{
+			{LocationId: []uint64{10, 20}, Value: []int64{3}, Label: []*googlev1.Label{{Key: 6, Str: 7}}},
+			{LocationId: []uint64{20}, Value: []int64{1}},
+		},
+		Mapping: []*googlev1.Mapping{{Id: 5, MemoryStart: 0x1000, MemoryLimit: 0x2000, Filename: 8, HasFunctions: true}},
+		Location: []*googlev1.Location{
+			{Id: 10, MappingId: 5, Address: 0x1010, Line: []*googlev1.Line{{FunctionId: 100, Line: 12}}},
+			{Id: 20, MappingId: 5, Address: 0x1020, Line: []*googlev1.Line{{FunctionId: 200, Line: 5}}},
+		},
+		Function: []*googlev1.Function{
+			{Id: 100, Name: 3, SystemName: 3, Filename: 9, StartLine: 10},
+			{Id: 200, Name: 4, SystemName: 4, Filename: 9, StartLine: 1},
+		},
+		StringTable:       []string{"", "cpu", "nanoseconds", "work", "main", "period", "thread", "1", "app", "main.go"},
+		TimeNanos:         1700000000000000000,
+		DurationNanos:     10000000000,
+		PeriodType:        &googlev1.ValueType{Type: 1, Unit: 2},
+		Period:            10000000,
+		DefaultSampleType: 1,
+	}
+}
+
+func Test_RoundTrip(t *testing.T) {
+	otlp := pprofile.NewProfile()
+	ToOTLP(testProfile(), otlp)
+	assert.Equal(t, []int64{0, 1, 1}, otlp.LocationIndices().AsRaw())
+	assert.Equal(t, uint64(1), otlp.Location().At(1).Line().At(0).FunctionIndex())
+
+	actual := FromOTLP(otlp)
+	expected := testProfile()
+	// IDs are reassigned to the positions in the tables.
+	expected.Mapping[0].Id = 1
+	expected.Location[0].Id, expected.Location[0].MappingId, expected.Location[0].Line[0].FunctionId = 1, 1, 1
+	expected.Location[1].Id, expected.Location[1].MappingId, expected.Location[1].Line[0].FunctionId = 2, 1, 2
+	expected.Function[0].Id, expected.Function[1].Id = 1, 2
+	expected.Sample[0].LocationId = []uint64{1, 2}
+	expected.Sample[1].LocationId = []uint64{2}
+	assert.Equal(t, expected.String(), actual.String())
+}
+
+func Test_FromOTLP_Attributes(t *testing.T) {
+	p := pprofile.NewProfile()
+	p.StringTable().FromRaw([]string{"", "samples", "count", "f"})
+	st := p.SampleType().AppendEmpty()
+	st.SetType(1)
+	st.SetUnit(2)
+	p.Function().AppendEmpty().SetName(3)
+	p.Location().AppendEmpty().Line().AppendEmpty().SetFunctionIndex(0)
+	p.AttributeTable().PutStr("thread.name", "f")
+	p.AttributeTable().PutInt("thread.id", 7)
+	p.AttributeTable().PutBool("flag", true)
+	s := p.Sample().AppendEmpty()
+	s.LocationIndex().FromRaw([]uint64{0})
+	s.Attributes().FromRaw([]uint64{0, 1, 2, 3})
+	s.TimestampsUnixNano().FromRaw([]uint64{1, 2})
+
+	actual := FromOTLP(p)
+	require.Len(t, actual.Sample, 1)
+	sample := actual.Sample[0]
+	assert.Equal(t, []uint64{1}, sample.LocationId)
+	assert.Equal(t, []int64{2}, sample.Value)
+	assert.Equal(t, []string{"", "samples", "count", "f", "thread.name", "thread.id"}, actual.StringTable)
+	assert.Equal(t, []*googlev1.Label{{Key: 4, Str: 3}, {Key: 5, Num: 7}}, sample.Label)
+	assert.Equal(t, uint64(1), actual.Location[0].Line[0].FunctionId)
+	assert.Equal(t, uint64(0), actual.Location[0].MappingId)
+}
+
+func Test_DecodePprof(t *testing.T) {
+	b, err := testProfile().MarshalVT()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	for _, data := range [][]byte{b, buf.Bytes()} {
+		p, err := DecodePprof(data)
+		require.NoError(t, err)
+		assert.Equal(t, testProfile().String(), p.String())
+	}
+	_, err = DecodePprof([]byte("not a profile"))
+	assert.Error(t, err)
+}
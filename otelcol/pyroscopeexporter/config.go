@@ -0,0 +1,33 @@
+package pyroscopeexporter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// Config defines the configuration of the Pyroscope exporter. The tenant
+// of the profiles is specified with the X-Scope-OrgID header.
+type Config struct {
+	confighttp.ClientConfig `mapstructure:",squash"`
+
+	QueueConfig   exporterhelper.QueueConfig `mapstructure:"sending_queue"`
+	RetryConfig   configretry.BackOffConfig  `mapstructure:"retry_on_failure"`
+	BatcherConfig exporterbatcher.Config     `mapstructure:"batcher"`
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint is required")
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("endpoint must be an absolute http(s) URL: %q", cfg.Endpoint)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package pyroscopeexporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/otelcol/internal/convert"
+)
+
+const (
+	labelNameProfileName    = "__name__"
+	labelNameServiceName    = "service_name"
+	attributeKeyServiceName = "service.name"
+
+	// Defaults used by the Pyroscope OTLP ingestion.
+	defaultProfileName = "process_cpu"
+	defaultServiceName = "unknown"
+)
+
+type pyroscopeExporter struct {
+	cfg      *Config
+	settings exporter.Settings
+	client   pushv1connect.PusherServiceClient
+}
+
+func newExporter(cfg *Config, set exporter.Settings) *pyroscopeExporter {
+	return &pyroscopeExporter{
+		cfg:      cfg,
+		settings: set,
+	}
+}
+
+func (e *pyroscopeExporter) start(ctx context.Context, host component.Host) error {
+	client, err := e.cfg.ToClient(ctx, host, e.settings.TelemetrySettings)
+	if err != nil {
+		return err
+	}
+	e.client = pushv1connect.NewPusherServiceClient(client, strings.TrimSuffix(e.cfg.Endpoint, "/"))
+	return nil
+}
+
+func (e *pyroscopeExporter) pushProfiles(ctx context.Context, pd pprofile.Profiles) error {
+	req, err := pushRequest(pd)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	if len(req.Series) == 0 {
+		return nil
+	}
+	if _, err = e.client.Push(ctx, connect.NewRequest(req)); err != nil {
+		if !retryable(connect.CodeOf(err)) {
+			return consumererror.NewPermanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// retryable reports whether the push failed because of
+// an error that may be resolved by retrying the request.
+func retryable(code connect.Code) bool {
+	switch code {
+	case connect.CodeInvalidArgument,
+		connect.CodeUnauthenticated,
+		connect.CodePermissionDenied,
+		connect.CodeNotFound,
+		connect.CodeUnimplemented,
+		connect.CodeFailedPrecondition,
+		connect.CodeOutOfRange:
+		return false
+	default:
+		return true
+	}
+}
+
+// pushRequest converts the profiles to the push request: each profile is
+// a series, labelled with the attributes of its resource, its scope, and
+// the profile itself.
+func pushRequest(pd pprofile.Profiles) (*pushv1.PushRequest, error) {
+	req := new(pushv1.PushRequest)
+	for i := 0; i < pd.ResourceProfiles().Len(); i++ {
+		rp := pd.ResourceProfiles().At(i)
+		for j := 0; j < rp.ScopeProfiles().Len(); j++ {
+			sp := rp.ScopeProfiles().At(j)
+			for k := 0; k < sp.Profiles().Len(); k++ {
+				p := sp.Profiles().At(k)
+				raw, err := convert.FromOTLP(p.Profile()).MarshalVT()
+				if err != nil {
+					return nil, fmt.Errorf("encoding profile: %w", err)
+				}
+				req.Series = append(req.Series, &pushv1.RawProfileSeries{
+					Labels: labels(rp.Resource().Attributes(), sp.Scope().Attributes(), p.Attributes()),
+					Samples: []*pushv1.RawSample{{
+						RawProfile: raw,
+						ID:         sampleID(p.ProfileID()),
+					}},
+				})
+			}
+		}
+	}
+	return req, nil
+}
+
+// labels converts the string attributes to the series labels. If an
+// attribute is defined at multiple levels, the first one is used.
+func labels(attrs ...pcommon.Map) []*typesv1.LabelPair {
+	seen := make(map[string]struct{})
+	var ls []*typesv1.LabelPair
+	for _, m := range attrs {
+		m.Range(func(k string, v pcommon.Value) bool {
+			name := k
+			if name == attributeKeyServiceName {
+				name = labelNameServiceName
+			}
+			if v.Type() != pcommon.ValueTypeStr || v.Str() == "" {
+				return true
+			}
+			if _, ok := seen[name]; ok {
+				return true
+			}
+			seen[name] = struct{}{}
+			ls = append(ls, &typesv1.LabelPair{Name: name, Value: v.Str()})
+			return true
+		})
+	}
+	if _, ok := seen[labelNameProfileName]; !ok {
+		ls = append(ls, &typesv1.LabelPair{Name: labelNameProfileName, Value: defaultProfileName})
+	}
+	if _, ok := seen[labelNameServiceName]; !ok {
+		ls = append(ls, &typesv1.LabelPair{Name: labelNameServiceName, Value: defaultServiceName})
+	}
+	return ls
+}
+
+func sampleID(id pprofile.ProfileID) string {
+	if u := uuid.UUID(id); u != uuid.Nil {
+		return u.String()
+	}
+	return uuid.NewString()
+}
@@ -0,0 +1,153 @@
+package pyroscopeexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	googlev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+)
+
+type fakePusher struct {
+	pushv1connect.UnimplementedPusherServiceHandler
+
+	mu       sync.Mutex
+	requests []*pushv1.PushRequest
+	err      error
+}
+
+func (f *fakePusher) Push(_ context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.requests = append(f.requests, req.Msg)
+	return connect.NewResponse(&pushv1.PushResponse{}), nil
+}
+
+func newTestExporter(t *testing.T, pusher *fakePusher) func(context.Context, pprofile.Profiles) error {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(pushv1connect.NewPusherServiceHandler(pusher))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.QueueConfig.Enabled = false
+	cfg.RetryConfig.Enabled = false
+	cfg.BatcherConfig.Enabled = false
+	require.NoError(t, cfg.Validate())
+	e, err := NewFactory().CreateProfiles(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, e.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, e.Shutdown(context.Background())) })
+	return e.ConsumeProfiles
+}
+
+func testProfiles(id uuid.UUID) pprofile.Profiles {
+	pd := pprofile.NewProfiles()
+	rp := pd.ResourceProfiles().AppendEmpty()
+	rp.Resource().Attributes().PutStr("service.name", "checkout")
+	rp.Resource().Attributes().PutStr("pod", "checkout-1")
+	rp.Resource().Attributes().PutInt("pid", 1)
+	sp := rp.ScopeProfiles().AppendEmpty()
+	sp.Scope().Attributes().PutStr("pod", "ignored")
+	container := sp.Profiles().AppendEmpty()
+	container.SetProfileID(pprofile.ProfileID(id))
+	container.Attributes().PutStr("__name__", "memory")
+	p := container.Profile()
+	p.StringTable().FromRaw([]string{"", "alloc_space", "bytes", "main"})
+	st := p.SampleType().AppendEmpty()
+	st.SetType(1)
+	st.SetUnit(2)
+	p.Function().AppendEmpty().SetName(3)
+	p.Location().AppendEmpty().Line().AppendEmpty().SetFunctionIndex(0)
+	p.LocationIndices().Append(0)
+	s := p.Sample().AppendEmpty()
+	s.SetLocationsLength(1)
+	s.Value().Append(42)
+	return pd
+}
+
+func Test_Exporter(t *testing.T) {
+	pusher := new(fakePusher)
+	consume := newTestExporter(t, pusher)
+	id := uuid.New()
+	require.NoError(t, consume(context.Background(), testProfiles(id)))
+
+	require.Len(t, pusher.requests, 1)
+	require.Len(t, pusher.requests[0].Series, 1)
+	series := pusher.requests[0].Series[0]
+	assert.Equal(t, []*typesv1.LabelPair{
+		{Name: "service_name", Value: "checkout"},
+		{Name: "pod", Value: "checkout-1"},
+		{Name: "__name__", Value: "memory"},
+	}, series.Labels)
+	require.Len(t, series.Samples, 1)
+	assert.Equal(t, id.String(), series.Samples[0].ID)
+
+	var p googlev1.Profile
+	require.NoError(t, p.UnmarshalVT(series.Samples[0].RawProfile))
+	assert.Equal(t, []string{"", "alloc_space", "bytes", "main"}, p.StringTable)
+	require.Len(t, p.Sample, 1)
+	assert.Equal(t, []uint64{1}, p.Sample[0].LocationId)
+	assert.Equal(t, []int64{42}, p.Sample[0].Value)
+}
+
+func Test_Exporter_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{name: "invalid argument", err: connect.NewError(connect.CodeInvalidArgument, nil), permanent: true},
+		{name: "unauthenticated", err: connect.NewError(connect.CodeUnauthenticated, nil), permanent: true},
+		{name: "unavailable", err: connect.NewError(connect.CodeUnavailable, nil)},
+		{name: "resource exhausted", err: connect.NewError(connect.CodeResourceExhausted, nil)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			consume := newTestExporter(t, &fakePusher{err: tc.err})
+			err := consume(context.Background(), testProfiles(uuid.New()))
+			require.Error(t, err)
+			assert.Equal(t, tc.permanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func Test_Labels_Defaults(t *testing.T) {
+	pd := pprofile.NewProfiles()
+	pd.ResourceProfiles().AppendEmpty().ScopeProfiles().AppendEmpty().Profiles().AppendEmpty()
+	req, err := pushRequest(pd)
+	require.NoError(t, err)
+	require.Len(t, req.Series, 1)
+	assert.Equal(t, []*typesv1.LabelPair{
+		{Name: "__name__", Value: "process_cpu"},
+		{Name: "service_name", Value: "unknown"},
+	}, req.Series[0].Labels)
+	assert.NotEmpty(t, req.Series[0].Samples[0].ID)
+}
+
+func Test_Config_Validate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Error(t, cfg.Validate())
+	cfg.Endpoint = "http://localhost:4040"
+	assert.NoError(t, cfg.Validate())
+	cfg.Endpoint = "localhost:4040"
+	assert.Error(t, cfg.Validate())
+}
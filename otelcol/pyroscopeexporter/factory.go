@@ -0,0 +1,56 @@
+// Package pyroscopeexporter implements an OpenTelemetry Collector exporter
+// writing profiles to Pyroscope with the Pyroscope push API.
+package pyroscopeexporter
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/exporterhelperprofiles"
+	"go.opentelemetry.io/collector/exporter/exporterprofiles"
+)
+
+const defaultTimeout = 30 * time.Second
+
+var componentType = component.MustNewType("pyroscope")
+
+// NewFactory creates a factory for the Pyroscope exporter.
+func NewFactory() exporterprofiles.Factory {
+	return exporterprofiles.NewFactory(
+		componentType,
+		createDefaultConfig,
+		exporterprofiles.WithProfiles(createProfilesExporter, component.StabilityLevelDevelopment),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	client := confighttp.NewDefaultClientConfig()
+	client.Timeout = defaultTimeout
+	return &Config{
+		ClientConfig:  client,
+		QueueConfig:   exporterhelper.NewDefaultQueueConfig(),
+		RetryConfig:   configretry.NewDefaultBackOffConfig(),
+		BatcherConfig: exporterbatcher.NewDefaultConfig(),
+	}
+}
+
+func createProfilesExporter(ctx context.Context, set exporter.Settings, cfg component.Config) (exporterprofiles.Profiles, error) {
+	c := cfg.(*Config)
+	e := newExporter(c, set)
+	return exporterhelperprofiles.NewProfilesExporter(ctx, set, cfg, e.pushProfiles,
+		exporterhelper.WithStart(e.start),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		// The timeout of the HTTP client applies.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{}),
+		exporterhelper.WithQueue(c.QueueConfig),
+		exporterhelper.WithRetry(c.RetryConfig),
+		exporterhelper.WithBatcher(c.BatcherConfig),
+	)
+}
@@ -0,0 +1,19 @@
+package pyroscopereceiver
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// Config defines the configuration of the Pyroscope receiver.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"`
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint is required")
+	}
+	return nil
+}
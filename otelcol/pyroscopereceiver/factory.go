@@ -0,0 +1,37 @@
+// Package pyroscopereceiver implements an OpenTelemetry Collector receiver
+// for the Pyroscope push API, which is used by the Pyroscope SDKs and by
+// Grafana Alloy to send profiles to Pyroscope.
+package pyroscopereceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverprofiles"
+)
+
+const defaultEndpoint = "localhost:4040"
+
+var componentType = component.MustNewType("pyroscope")
+
+// NewFactory creates a factory for the Pyroscope receiver.
+func NewFactory() receiverprofiles.Factory {
+	return receiverprofiles.NewFactory(
+		componentType,
+		createDefaultConfig,
+		receiverprofiles.WithProfiles(createProfilesReceiver, component.StabilityLevelDevelopment),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		ServerConfig: confighttp.ServerConfig{Endpoint: defaultEndpoint},
+	}
+}
+
+func createProfilesReceiver(_ context.Context, set receiver.Settings, cfg component.Config, next consumerprofiles.Profiles) (receiverprofiles.Profiles, error) {
+	return newReceiver(cfg.(*Config), set, next), nil
+}
@@ -0,0 +1,144 @@
+package pyroscopereceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/otelcol/internal/convert"
+)
+
+const (
+	labelNameServiceName     = "service_name"
+	attributeKeyServiceName  = "service.name"
+	instrumentationScopeName = "github.com/grafana/pyroscope/otelcol/pyroscopereceiver"
+)
+
+type pyroscopeReceiver struct {
+	cfg      *Config
+	settings receiver.Settings
+	next     consumerprofiles.Profiles
+
+	server     *http.Server
+	shutdownWG sync.WaitGroup
+}
+
+func newReceiver(cfg *Config, set receiver.Settings, next consumerprofiles.Profiles) *pyroscopeReceiver {
+	return &pyroscopeReceiver{
+		cfg:      cfg,
+		settings: set,
+		next:     next,
+	}
+}
+
+func (r *pyroscopeReceiver) Start(ctx context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.Handle(pushv1connect.NewPusherServiceHandler(r))
+	// The Pyroscope SDKs use the gRPC protocol over cleartext HTTP/2.
+	server, err := r.cfg.ToServer(ctx, host, r.settings.TelemetrySettings, h2c.NewHandler(mux, &http2.Server{}))
+	if err != nil {
+		return err
+	}
+	listener, err := r.cfg.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.cfg.Endpoint, err)
+	}
+	r.server = server
+	r.shutdownWG.Add(1)
+	go func() {
+		defer r.shutdownWG.Done()
+		if err := r.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.settings.Logger.Error("Pyroscope receiver server failed", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (r *pyroscopeReceiver) Shutdown(ctx context.Context) error {
+	var err error
+	if r.server != nil {
+		err = r.server.Shutdown(ctx)
+	}
+	r.shutdownWG.Wait()
+	return err
+}
+
+// Push implements the Pyroscope push API: each series of the request is
+// converted to a resource, and each of its samples to a profile.
+func (r *pyroscopeReceiver) Push(ctx context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+	profiles, err := r.profiles(req.Msg)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err = r.next.ConsumeProfiles(ctx, profiles); err != nil {
+		if consumererror.IsPermanent(err) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, err)
+	}
+	return connect.NewResponse(&pushv1.PushResponse{}), nil
+}
+
+func (r *pyroscopeReceiver) profiles(req *pushv1.PushRequest) (pprofile.Profiles, error) {
+	profiles := pprofile.NewProfiles()
+	profiles.ResourceProfiles().EnsureCapacity(len(req.Series))
+	for _, series := range req.Series {
+		rp := profiles.ResourceProfiles().AppendEmpty()
+		setAttributes(rp.Resource().Attributes(), series.Labels)
+		sp := rp.ScopeProfiles().AppendEmpty()
+		sp.Scope().SetName(instrumentationScopeName)
+		sp.Profiles().EnsureCapacity(len(series.Samples))
+		for _, sample := range series.Samples {
+			p, err := convert.DecodePprof(sample.RawProfile)
+			if err != nil {
+				return pprofile.Profiles{}, err
+			}
+			container := sp.Profiles().AppendEmpty()
+			container.SetProfileID(profileID(sample.ID))
+			container.SetStartTime(pcommon.Timestamp(p.TimeNanos))
+			container.SetEndTime(pcommon.Timestamp(p.TimeNanos + p.DurationNanos))
+			convert.ToOTLP(p, container.Profile())
+		}
+	}
+	return profiles, nil
+}
+
+// setAttributes converts the series labels to the resource attributes:
+// the service name label is converted to the semantic convention.
+func setAttributes(attrs pcommon.Map, labels []*typesv1.LabelPair) {
+	attrs.EnsureCapacity(len(labels))
+	for _, l := range labels {
+		key := l.Name
+		if key == labelNameServiceName {
+			key = attributeKeyServiceName
+		}
+		attrs.PutStr(key, l.Value)
+	}
+}
+
+// profileID returns the ID of the sample, if it is a UUID, as the
+// profile ID. Otherwise, a random profile ID is generated.
+func profileID(id string) pprofile.ProfileID {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		u = uuid.New()
+	}
+	return pprofile.ProfileID(u)
+}
@@ -0,0 +1,131 @@
+package pyroscopereceiver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	googlev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+)
+
+func testRawProfile(t *testing.T) []byte {
+	t.Helper()
+	p := &googlev1.Profile{
+		SampleType:  []*googlev1.ValueType{{Type: 1, Unit: 2}},
+		Sample:      []*googlev1.Sample{{LocationId: []uint64{1}, Value: []int64{5}}},
+		Location:    []*googlev1.Location{{Id: 1, Line: []*googlev1.Line{{FunctionId: 1}}}},
+		Function:    []*googlev1.Function{{Id: 1, Name: 3}},
+		StringTable: []string{"", "cpu", "nanoseconds", "main"},
+		TimeNanos:   1e9,
+		PeriodType:  &googlev1.ValueType{Type: 1, Unit: 2},
+	}
+	b, err := p.MarshalVT()
+	require.NoError(t, err)
+	return b
+}
+
+func freeEndpoint(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func Test_Receiver(t *testing.T) {
+	sink := new(consumertest.ProfilesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = freeEndpoint(t)
+	r, err := NewFactory().CreateProfiles(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	id := uuid.New()
+	raw := testRawProfile(t)
+	client := pushv1connect.NewPusherServiceClient(http.DefaultClient, "http://"+cfg.Endpoint)
+	_, err = client.Push(context.Background(), connect.NewRequest(&pushv1.PushRequest{
+		Series: []*pushv1.RawProfileSeries{{
+			Labels: []*typesv1.LabelPair{
+				{Name: "__name__", Value: "process_cpu"},
+				{Name: "service_name", Value: "checkout"},
+				{Name: "pod", Value: "checkout-1"},
+			},
+			Samples: []*pushv1.RawSample{{RawProfile: raw, ID: id.String()}},
+		}},
+	}))
+	require.NoError(t, err)
+
+	all := sink.AllProfiles()
+	require.Len(t, all, 1)
+	require.Equal(t, 1, all[0].ResourceProfiles().Len())
+	rp := all[0].ResourceProfiles().At(0)
+	assert.Equal(t, map[string]any{
+		"__name__":     "process_cpu",
+		"service.name": "checkout",
+		"pod":          "checkout-1",
+	}, rp.Resource().Attributes().AsRaw())
+
+	sp := rp.ScopeProfiles().At(0)
+	assert.Equal(t, instrumentationScopeName, sp.Scope().Name())
+	require.Equal(t, 1, sp.Profiles().Len())
+	container := sp.Profiles().At(0)
+	assert.Equal(t, [16]byte(id), [16]byte(container.ProfileID()))
+	assert.Equal(t, uint64(1e9), uint64(container.StartTime()))
+	profile := container.Profile()
+	assert.Equal(t, []string{"", "cpu", "nanoseconds", "main"}, profile.StringTable().AsRaw())
+	require.Equal(t, 1, profile.Sample().Len())
+	assert.Equal(t, []int64{5}, profile.Sample().At(0).Value().AsRaw())
+
+}
+
+func Test_Receiver_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		consumer error
+		raw      []byte
+		code     connect.Code
+	}{
+		{name: "invalid profile", raw: []byte("invalid"), code: connect.CodeInvalidArgument},
+		{name: "permanent error", consumer: consumererror.NewPermanent(errors.New("rejected")), code: connect.CodeInvalidArgument},
+		{name: "temporary error", consumer: errors.New("unavailable"), code: connect.CodeUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			r := newReceiver(cfg, receivertest.NewNopSettings(), consumertest.NewErr(tc.consumer))
+			raw := tc.raw
+			if raw == nil {
+				raw = testRawProfile(t)
+			}
+			_, err := r.Push(context.Background(), connect.NewRequest(&pushv1.PushRequest{
+				Series: []*pushv1.RawProfileSeries{{
+					Labels:  []*typesv1.LabelPair{{Name: "service_name", Value: "checkout"}},
+					Samples: []*pushv1.RawSample{{RawProfile: raw}},
+				}},
+			}))
+			require.Error(t, err)
+			assert.Equal(t, tc.code, connect.CodeOf(err))
+		})
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+	cfg.Endpoint = ""
+	assert.Error(t, cfg.Validate())
+}
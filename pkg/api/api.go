@@ -26,10 +26,12 @@ import (
 	"github.com/grafana/pyroscope/public"
 
 	"github.com/grafana/pyroscope/api/gen/proto/go/adhocprofiles/v1/adhocprofilesv1connect"
+	"github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1/apikeysv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/ingester/v1/ingesterv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/settings/v1/settingsv1connect"
+	"github.com/grafana/pyroscope/api/gen/proto/go/snapshots/v1/snapshotsv1connect"
 	statusv1 "github.com/grafana/pyroscope/api/gen/proto/go/status/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/storegateway/v1/storegatewayv1connect"
 	"github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1/vcsv1connect"
@@ -37,6 +39,7 @@ import (
 	"github.com/grafana/pyroscope/api/openapiv2"
 	"github.com/grafana/pyroscope/pkg/adhocprofiles"
 	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
+	"github.com/grafana/pyroscope/pkg/apikeys"
 	"github.com/grafana/pyroscope/pkg/compactor"
 	"github.com/grafana/pyroscope/pkg/distributor"
 	"github.com/grafana/pyroscope/pkg/frontend"
@@ -49,7 +52,10 @@ import (
 	"github.com/grafana/pyroscope/pkg/scheduler"
 	"github.com/grafana/pyroscope/pkg/scheduler/schedulerpb/schedulerpbconnect"
 	"github.com/grafana/pyroscope/pkg/settings"
+	"github.com/grafana/pyroscope/pkg/share"
+	"github.com/grafana/pyroscope/pkg/snapshots"
 	"github.com/grafana/pyroscope/pkg/storegateway"
+	"github.com/grafana/pyroscope/pkg/summary"
 	"github.com/grafana/pyroscope/pkg/util"
 	"github.com/grafana/pyroscope/pkg/util/gziphandler"
 	"github.com/grafana/pyroscope/pkg/validation/exporter"
@@ -57,18 +63,22 @@ import (
 
 type Config struct {
 	// The following configs are injected by the upstream caller.
-	HTTPAuthMiddleware middleware.Interface `yaml:"-"`
-	GrpcAuthMiddleware connect.Option       `yaml:"-"`
-	BaseURL            string               `yaml:"base-url"`
+	HTTPAuthMiddleware   middleware.Interface `yaml:"-"`
+	GrpcAuthMiddleware   connect.Option       `yaml:"-"`
+	HTTPAPIKeyMiddleware middleware.Interface `yaml:"-"`
+	GrpcAPIKeyMiddleware connect.Option       `yaml:"-"`
+	BaseURL              string               `yaml:"base-url"`
 }
 
 type API struct {
-	server             *server.Server
-	httpAuthMiddleware middleware.Interface
-	grpcGatewayMux     *grpcgw.ServeMux
-	grpcAuthMiddleware connect.Option
-	grpcLogMiddleware  connect.Option
-	recoveryMiddleware connect.Option
+	server               *server.Server
+	httpAuthMiddleware   middleware.Interface
+	httpAPIKeyMiddleware middleware.Interface
+	grpcGatewayMux       *grpcgw.ServeMux
+	grpcAuthMiddleware   connect.Option
+	grpcAPIKeyMiddleware connect.Option
+	grpcLogMiddleware    connect.Option
+	recoveryMiddleware   connect.Option
 
 	cfg       Config
 	logger    log.Logger
@@ -77,15 +87,17 @@ type API struct {
 
 func New(cfg Config, s *server.Server, grpcGatewayMux *grpcgw.ServeMux, logger log.Logger) (*API, error) {
 	api := &API{
-		cfg:                cfg,
-		httpAuthMiddleware: cfg.HTTPAuthMiddleware,
-		server:             s,
-		logger:             logger,
-		indexPage:          NewIndexPageContent(),
-		grpcGatewayMux:     grpcGatewayMux,
-		grpcAuthMiddleware: cfg.GrpcAuthMiddleware,
-		grpcLogMiddleware:  connect.WithInterceptors(util.NewLogInterceptor(logger)),
-		recoveryMiddleware: connect.WithInterceptors(util.RecoveryInterceptor),
+		cfg:                  cfg,
+		httpAuthMiddleware:   cfg.HTTPAuthMiddleware,
+		httpAPIKeyMiddleware: cfg.HTTPAPIKeyMiddleware,
+		server:               s,
+		logger:               logger,
+		indexPage:            NewIndexPageContent(),
+		grpcGatewayMux:       grpcGatewayMux,
+		grpcAuthMiddleware:   cfg.GrpcAuthMiddleware,
+		grpcAPIKeyMiddleware: cfg.GrpcAPIKeyMiddleware,
+		grpcLogMiddleware:    connect.WithInterceptors(util.NewLogInterceptor(logger)),
+		recoveryMiddleware:   connect.WithInterceptors(util.RecoveryInterceptor),
 	}
 
 	// If no authentication middleware is present in the config, use the default authentication middleware.
@@ -93,6 +105,14 @@ func New(cfg Config, s *server.Server, grpcGatewayMux *grpcgw.ServeMux, logger l
 		api.httpAuthMiddleware = middleware.AuthenticateUser
 	}
 
+	// API keys are only validated if the middleware is present in the config.
+	if cfg.HTTPAPIKeyMiddleware == nil {
+		api.httpAPIKeyMiddleware = middleware.Merge()
+	}
+	if cfg.GrpcAPIKeyMiddleware == nil {
+		api.grpcAPIKeyMiddleware = connect.WithInterceptors()
+	}
+
 	return api, nil
 }
 
@@ -113,6 +133,7 @@ func (a *API) RegisterRoutesWithPrefix(prefix string, handler http.Handler, auth
 func (a *API) newRoute(path string, handler http.Handler, isPrefix, auth, gzip bool, methods ...string) (route *mux.Route) {
 	if auth {
 		handler = a.httpAuthMiddleware.Wrap(handler)
+		handler = a.httpAPIKeyMiddleware.Wrap(handler)
 	}
 	if gzip {
 		handler = gziphandler.GzipHandler(handler)
@@ -205,7 +226,19 @@ func (a *API) RegisterRuntimeConfig(runtimeConfigHandler http.HandlerFunc, userL
 }
 
 func (a *API) RegisterTenantSettings(ts *settings.TenantSettings) {
-	settingsv1connect.RegisterSettingsServiceHandler(a.server.HTTP, ts, a.connectOptionsAuthRecovery()...)
+	settingsv1connect.RegisterSettingsServiceHandler(a.server.HTTP, ts, a.connectOptionsAPIKeyAuthRecovery()...)
+}
+
+func (a *API) RegisterAPIKeys(k *apikeys.APIKeys) {
+	apikeysv1connect.RegisterAPIKeyServiceHandler(a.server.HTTP, k, a.connectOptionsAPIKeyAuthRecovery()...)
+}
+
+func (a *API) RegisterCollectionRules(cr *settings.CollectionRules) {
+	settingsv1connect.RegisterCollectionRulesServiceHandler(a.server.HTTP, cr, a.connectOptionsAPIKeyAuthRecovery()...)
+}
+
+func (a *API) RegisterSavedViews(sv *settings.SavedViews) {
+	settingsv1connect.RegisterSavedViewsServiceHandler(a.server.HTTP, sv, a.connectOptionsAPIKeyAuthRecovery()...)
 }
 
 // RegisterOverridesExporter registers the endpoints associated with the overrides exporter.
@@ -223,7 +256,7 @@ func (a *API) RegisterDistributor(d *distributor.Distributor, multitenancyEnable
 
 	a.RegisterRoute("/ingest", pyroscopeHandler, true, true, "POST")
 	a.RegisterRoute("/pyroscope/ingest", pyroscopeHandler, true, true, "POST")
-	pushv1connect.RegisterPusherServiceHandler(a.server.HTTP, d, a.connectOptionsAuthRecovery()...)
+	pushv1connect.RegisterPusherServiceHandler(a.server.HTTP, d, a.connectOptionsAPIKeyAuthRecovery()...)
 	a.RegisterRoute("/distributor/ring", d, false, true, "GET", "POST")
 	a.indexPage.AddLinks(defaultWeight, "Distributor", []IndexPageLink{
 		{Desc: "Ring status", Path: "/distributor/ring"},
@@ -231,6 +264,10 @@ func (a *API) RegisterDistributor(d *distributor.Distributor, multitenancyEnable
 
 	a.RegisterRoute("/opentelemetry.proto.collector.profiles.v1experimental.ProfilesService/Export", otlpHandler, true, true, "POST")
 
+	if s := d.Symbolizer(); s != nil {
+		a.RegisterRoute("/pyroscope/debuginfo/upload", s, true, false, "POST")
+	}
+
 	// TODO(@petethepig): implement http/protobuf and http/json support
 	// a.RegisterRoute("/v1/profiles", otlpHandler, true, true, "POST")
 }
@@ -252,11 +289,11 @@ func (a *API) RegisterIngesterRing(r http.Handler) {
 }
 
 func (a *API) RegisterQuerierServiceHandler(svc querierv1connect.QuerierServiceHandler) {
-	querierv1connect.RegisterQuerierServiceHandler(a.server.HTTP, svc, a.connectOptionsAuthLogRecovery()...)
+	querierv1connect.RegisterQuerierServiceHandler(a.server.HTTP, svc, a.connectOptionsAPIKeyAuthLogRecovery()...)
 }
 
 func (a *API) RegisterVCSServiceHandler(svc vcsv1connect.VCSServiceHandler) {
-	vcsv1connect.RegisterVCSServiceHandler(a.server.HTTP, svc, a.connectOptionsAuthLogRecovery()...)
+	vcsv1connect.RegisterVCSServiceHandler(a.server.HTTP, svc, a.connectOptionsAPIKeyAuthLogRecovery()...)
 }
 
 func (a *API) RegisterPyroscopeHandlers(client querierv1connect.QuerierServiceClient) {
@@ -264,6 +301,50 @@ func (a *API) RegisterPyroscopeHandlers(client querierv1connect.QuerierServiceCl
 	a.RegisterRoute("/pyroscope/render", http.HandlerFunc(handlers.Render), true, true, "GET")
 	a.RegisterRoute("/pyroscope/render-diff", http.HandlerFunc(handlers.RenderDiff), true, true, "GET")
 	a.RegisterRoute("/pyroscope/label-values", http.HandlerFunc(handlers.LabelValues), true, true, "GET")
+	a.RegisterRoute("/pyroscope/export", http.HandlerFunc(handlers.Export), true, true, "GET")
+	// The remote read responses are snappy-compressed.
+	a.RegisterRoute("/prometheus/api/v1/read", http.HandlerFunc(handlers.RemoteRead), true, false, "POST")
+	a.RegisterRoute("/pyroscope/cost-attribution/metrics", http.HandlerFunc(handlers.CostAttribution), true, true, "GET")
+	a.RegisterRoute("/pyroscope/binaries", http.HandlerFunc(handlers.BinaryInventory), true, true, "GET")
+}
+
+// RegisterLiveStream registers the live profiling stream endpoint. The
+// response is a stream of server-sent events, therefore it is not gzipped.
+func (a *API) RegisterLiveStream(handler http.Handler) {
+	a.RegisterRoute("/pyroscope/live", handler, true, false, "GET")
+}
+
+// RegisterAnnotations registers the endpoint external systems, such as
+// CD pipelines, use to annotate services with events.
+func (a *API) RegisterAnnotations(handler http.Handler) {
+	a.RegisterRoute("/pyroscope/annotations", handler, true, true, "POST")
+}
+
+// RegisterSlowQueries registers the page listing the recent queries
+// that exceeded the slow query log thresholds.
+func (a *API) RegisterSlowQueries(handler http.Handler) {
+	a.RegisterRoute("/query-frontend/slow-queries", handler, false, true, "GET")
+	a.indexPage.AddLinks(defaultWeight, "Query Frontend", []IndexPageLink{
+		{Desc: "Slow queries", Path: "/query-frontend/slow-queries"},
+	})
+}
+
+// RegisterSnapshots registers the snapshot service. Public snapshots are
+// served without authentication: the snapshot token grants access.
+func (a *API) RegisterSnapshots(s *snapshots.Snapshots) {
+	snapshotsv1connect.RegisterSnapshotServiceHandler(a.server.HTTP, s, a.connectOptionsAPIKeyAuthRecovery()...)
+	a.RegisterRoute("/pyroscope/snapshots/{token}", http.HandlerFunc(s.PublicHandler), false, true, "GET")
+}
+
+// RegisterShare registers the endpoints sharing profiles with external services.
+func (a *API) RegisterShare(s *share.Service) {
+	a.RegisterRoute("/pyroscope/share/targets", http.HandlerFunc(s.TargetsHandler), true, true, "GET")
+	a.RegisterRoute("/pyroscope/share", http.HandlerFunc(s.ShareHandler), true, true, "POST")
+}
+
+// RegisterProfileSummary registers the endpoint summarizing profiles with a language model.
+func (a *API) RegisterProfileSummary(s *summary.Service) {
+	a.RegisterRoute("/pyroscope/summary", http.HandlerFunc(s.Handler), true, true, "POST")
 }
 
 // RegisterIngester registers the endpoints associated with the ingester.
@@ -323,7 +404,7 @@ func (a *API) RegisterAdmin(ad *operations.Admin) {
 }
 
 func (a *API) RegisterAdHocProfiles(ahp *adhocprofiles.AdHocProfiles) {
-	adhocprofilesv1connect.RegisterAdHocProfileServiceHandler(a.server.HTTP, ahp, a.connectOptionsAuthRecovery()...)
+	adhocprofilesv1connect.RegisterAdHocProfileServiceHandler(a.server.HTTP, ahp, a.connectOptionsAPIKeyAuthRecovery()...)
 }
 
 func (a *API) connectOptionsRecovery() []connect.HandlerOption {
@@ -337,3 +418,13 @@ func (a *API) connectOptionsAuthRecovery() []connect.HandlerOption {
 func (a *API) connectOptionsAuthLogRecovery() []connect.HandlerOption {
 	return append(connectapi.DefaultHandlerOptions(), []connect.HandlerOption{a.grpcAuthMiddleware, a.grpcLogMiddleware, a.recoveryMiddleware}...)
 }
+
+// connectOptionsAPIKeyAuthRecovery should be used for the public APIs: unlike
+// internal ones, they are subject to API key validation, if enabled.
+func (a *API) connectOptionsAPIKeyAuthRecovery() []connect.HandlerOption {
+	return append(connectapi.DefaultHandlerOptions(), []connect.HandlerOption{a.grpcAPIKeyMiddleware, a.grpcAuthMiddleware, a.recoveryMiddleware}...)
+}
+
+func (a *API) connectOptionsAPIKeyAuthLogRecovery() []connect.HandlerOption {
+	return append(connectapi.DefaultHandlerOptions(), []connect.HandlerOption{a.grpcAPIKeyMiddleware, a.grpcAuthMiddleware, a.grpcLogMiddleware, a.recoveryMiddleware}...)
+}
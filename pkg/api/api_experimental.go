@@ -6,6 +6,7 @@ import (
 	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
 	segmentwriterv1 "github.com/grafana/pyroscope/api/gen/proto/go/segmentwriter/v1"
 	segmentwriter "github.com/grafana/pyroscope/pkg/experiment/ingester"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore"
 	querybackend "github.com/grafana/pyroscope/pkg/experiment/query_backend"
 )
 
@@ -13,6 +14,10 @@ import (
 
 func (a *API) RegisterSegmentWriter(svc *segmentwriter.SegmentWriterService) {
 	segmentwriterv1.RegisterSegmentWriterServiceServer(a.server.GRPC, svc)
+	a.RegisterRoute("/segment-writer/segments", http.HandlerFunc(svc.SegmentsHandler), false, true, "GET")
+	a.indexPage.AddLinks(defaultWeight, "Segment Writer", []IndexPageLink{
+		{Desc: "Open segments", Path: "/segment-writer/segments"},
+	})
 }
 
 // RegisterSegmentWriterRing registers the ring UI page associated with the distributor for writes.
@@ -26,3 +31,12 @@ func (a *API) RegisterSegmentWriterRing(r http.Handler) {
 func (a *API) RegisterQueryBackend(svc *querybackend.QueryBackend) {
 	queryv1.RegisterQueryBackendServiceServer(a.server.GRPC, svc)
 }
+
+// RegisterMetastore registers the HTTP endpoints of the metastore.
+func (a *API) RegisterMetastore(m *metastore.Metastore) {
+	a.RegisterRoute("/metastore/external-blocks/notifications", m.ExternalBlocksNotificationHandler(), false, true, "POST")
+	a.RegisterRoute("/metastore/index/partitions", m.IndexPartitionsHandler(), false, true, "GET")
+	a.indexPage.AddLinks(defaultWeight, "Metastore", []IndexPageLink{
+		{Desc: "Index partitions", Path: "/metastore/index/partitions"},
+	})
+}
@@ -0,0 +1,184 @@
+package apikeys
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	apikeysv1 "github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1"
+)
+
+// APIKeys manages API keys stored in the object storage, and keeps the
+// authenticator up to date with the changes made by all the replicas.
+type APIKeys struct {
+	services.Service
+
+	cfg           Config
+	logger        log.Logger
+	store         *bucketStore
+	authenticator *Authenticator
+}
+
+func New(cfg Config, bucket objstore.Bucket, authenticator *Authenticator, logger log.Logger) *APIKeys {
+	k := &APIKeys{
+		cfg:           cfg,
+		logger:        logger,
+		store:         newBucketStore(bucket),
+		authenticator: authenticator,
+	}
+
+	k.Service = services.NewTimerService(cfg.RefreshInterval, k.starting, k.iteration, nil)
+
+	return k
+}
+
+func (k *APIKeys) starting(ctx context.Context) error {
+	return k.refresh(ctx)
+}
+
+func (k *APIKeys) iteration(ctx context.Context) error {
+	if err := k.refresh(ctx); err != nil {
+		level.Warn(k.logger).Log(
+			"msg", "failed to refresh API keys",
+			"err", err,
+		)
+	}
+	return nil
+}
+
+func (k *APIKeys) refresh(ctx context.Context) error {
+	keys, err := k.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	k.authenticator.update(keys)
+	return nil
+}
+
+func (k *APIKeys) CreateAPIKey(ctx context.Context, req *connect.Request[apikeysv1.CreateAPIKeyRequest]) (*connect.Response[apikeysv1.CreateAPIKeyResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	now := time.Now().UnixMilli()
+	if err = validateCreateRequest(req.Msg, now); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	id, secret, token, err := newToken()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	key := &apikeysv1.APIKey{
+		Id:          id,
+		Tenant:      tenantID,
+		Name:        req.Msg.Name,
+		Permissions: req.Msg.Permissions,
+		CreatedAt:   now,
+		ExpiresAt:   req.Msg.ExpiresAt,
+	}
+	err = k.store.Create(ctx, &storedKey{
+		Key:        key,
+		SecretHash: hashSecret(secret),
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	k.refreshAfterUpdate(ctx)
+	level.Info(k.logger).Log("msg", "API key created", "tenant", tenantID, "id", id, "name", key.Name)
+
+	return connect.NewResponse(&apikeysv1.CreateAPIKeyResponse{
+		Key:   key,
+		Token: token,
+	}), nil
+}
+
+func validateCreateRequest(req *apikeysv1.CreateAPIKeyRequest, now int64) error {
+	if req.Name == "" {
+		return fmt.Errorf("API key name is required")
+	}
+	if len(req.Permissions) == 0 {
+		return fmt.Errorf("at least one permission is required")
+	}
+	for _, p := range req.Permissions {
+		if _, ok := apikeysv1.Permission_name[int32(p)]; !ok || p == apikeysv1.Permission_PERMISSION_UNSPECIFIED {
+			return fmt.Errorf("invalid permission %d", p)
+		}
+	}
+	if req.ExpiresAt != 0 && req.ExpiresAt <= now {
+		return fmt.Errorf("expiration time must be in the future")
+	}
+	return nil
+}
+
+func (k *APIKeys) RevokeAPIKey(ctx context.Context, req *connect.Request[apikeysv1.RevokeAPIKeyRequest]) (*connect.Response[apikeysv1.RevokeAPIKeyResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	key, err := k.store.Revoke(ctx, tenantID, req.Msg.Id, time.Now().UnixMilli())
+	if err != nil {
+		if errors.Is(err, keyNotFoundErr) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	k.refreshAfterUpdate(ctx)
+	level.Info(k.logger).Log("msg", "API key revoked", "tenant", tenantID, "id", key.Id, "name", key.Name)
+
+	return connect.NewResponse(&apikeysv1.RevokeAPIKeyResponse{
+		Key: key,
+	}), nil
+}
+
+func (k *APIKeys) ListAPIKeys(ctx context.Context, _ *connect.Request[apikeysv1.ListAPIKeysRequest]) (*connect.Response[apikeysv1.ListAPIKeysResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	stored, err := k.store.List(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	keys := make([]*apikeysv1.APIKey, 0, len(stored))
+	for _, key := range stored {
+		if key.Key.Tenant == tenantID {
+			keys = append(keys, key.Key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b *apikeysv1.APIKey) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return connect.NewResponse(&apikeysv1.ListAPIKeysResponse{
+		Keys: keys,
+	}), nil
+}
+
+// refreshAfterUpdate makes the changes visible to the authenticator of
+// this replica immediately; other replicas pick them up on refresh.
+func (k *APIKeys) refreshAfterUpdate(ctx context.Context) {
+	if err := k.refresh(ctx); err != nil {
+		level.Warn(k.logger).Log(
+			"msg", "failed to refresh API keys",
+			"err", err,
+		)
+	}
+}
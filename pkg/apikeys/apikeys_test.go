@@ -0,0 +1,260 @@
+package apikeys
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	apikeysv1 "github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1"
+	phlarebucket "github.com/grafana/pyroscope/pkg/phlaredb/bucket"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func newTestAPIKeys(t *testing.T, cfg Config) (*APIKeys, *Authenticator) {
+	t.Helper()
+	authenticator := NewAuthenticator(cfg)
+	k := New(cfg, objstore.NewInMemBucket(), authenticator, log.NewNopLogger())
+	return k, authenticator
+}
+
+func createKey(t *testing.T, k *APIKeys, tenantID string, req *apikeysv1.CreateAPIKeyRequest) *apikeysv1.CreateAPIKeyResponse {
+	t.Helper()
+	ctx := tenant.InjectTenantID(context.Background(), tenantID)
+	res, err := k.CreateAPIKey(ctx, connect.NewRequest(req))
+	require.NoError(t, err)
+	return res.Msg
+}
+
+func TestAPIKeys_CreateListRevoke(t *testing.T) {
+	k, _ := newTestAPIKeys(t, Config{})
+	ctx := tenant.InjectTenantID(context.Background(), "tenant-a")
+
+	b := createKey(t, k, "tenant-a", &apikeysv1.CreateAPIKeyRequest{
+		Name:        "b",
+		Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_QUERY},
+	})
+	a := createKey(t, k, "tenant-a", &apikeysv1.CreateAPIKeyRequest{
+		Name:        "a",
+		Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_INGEST},
+	})
+	createKey(t, k, "tenant-b", &apikeysv1.CreateAPIKeyRequest{
+		Name:        "c",
+		Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_ADMIN},
+	})
+	require.NotEmpty(t, a.Token)
+	require.Equal(t, "tenant-a", a.Key.Tenant)
+
+	list, err := k.ListAPIKeys(ctx, connect.NewRequest(&apikeysv1.ListAPIKeysRequest{}))
+	require.NoError(t, err)
+	require.Len(t, list.Msg.Keys, 2)
+	require.Equal(t, "a", list.Msg.Keys[0].Name)
+	require.Equal(t, "b", list.Msg.Keys[1].Name)
+
+	revoked, err := k.RevokeAPIKey(ctx, connect.NewRequest(&apikeysv1.RevokeAPIKeyRequest{Id: b.Key.Id}))
+	require.NoError(t, err)
+	require.NotZero(t, revoked.Msg.Key.RevokedAt)
+
+	// Keys of other tenants can not be revoked.
+	ctxB := tenant.InjectTenantID(context.Background(), "tenant-b")
+	_, err = k.RevokeAPIKey(ctxB, connect.NewRequest(&apikeysv1.RevokeAPIKeyRequest{Id: a.Key.Id}))
+	require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestAPIKeys_CreateValidation(t *testing.T) {
+	k, _ := newTestAPIKeys(t, Config{})
+	ctx := tenant.InjectTenantID(context.Background(), "tenant-a")
+
+	for _, req := range []*apikeysv1.CreateAPIKeyRequest{
+		{Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_QUERY}},
+		{Name: "no-permissions"},
+		{Name: "unspecified", Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_UNSPECIFIED}},
+		{Name: "unknown", Permissions: []apikeysv1.Permission{42}},
+		{Name: "expired", Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_QUERY}, ExpiresAt: 1},
+	} {
+		_, err := k.CreateAPIKey(ctx, connect.NewRequest(req))
+		require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), req.Name)
+	}
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	k, a := newTestAPIKeys(t, Config{AdminToken: flagext.SecretWithValue("admin-secret")})
+	now := time.Now()
+
+	ingest := createKey(t, k, "tenant-a", &apikeysv1.CreateAPIKeyRequest{
+		Name:        "ingest",
+		Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_INGEST},
+		ExpiresAt:   now.Add(time.Hour).UnixMilli(),
+	})
+	admin := createKey(t, k, "tenant-a", &apikeysv1.CreateAPIKeyRequest{
+		Name:        "admin",
+		Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_ADMIN},
+	})
+
+	tenantID, err := a.Authenticate(ingest.Token, apikeysv1.Permission_PERMISSION_INGEST)
+	require.NoError(t, err)
+	require.Equal(t, "tenant-a", tenantID)
+
+	_, err = a.Authenticate(ingest.Token, apikeysv1.Permission_PERMISSION_QUERY)
+	require.ErrorIs(t, err, errPermissionDenied)
+
+	tenantID, err = a.Authenticate(admin.Token, apikeysv1.Permission_PERMISSION_QUERY)
+	require.NoError(t, err)
+	require.Equal(t, "tenant-a", tenantID)
+
+	tenantID, err = a.Authenticate("admin-secret", apikeysv1.Permission_PERMISSION_ADMIN)
+	require.NoError(t, err)
+	require.Empty(t, tenantID)
+
+	_, err = a.Authenticate("", apikeysv1.Permission_PERMISSION_QUERY)
+	require.ErrorIs(t, err, errMissingToken)
+	_, err = a.Authenticate("not-a-token", apikeysv1.Permission_PERMISSION_QUERY)
+	require.ErrorIs(t, err, errMalformedToken)
+	_, err = a.Authenticate(tokenPrefix+ingest.Key.Id+"_"+strings.Repeat("0", 2*secretLength), apikeysv1.Permission_PERMISSION_INGEST)
+	require.ErrorIs(t, err, errInvalidToken)
+
+	// Expired keys are rejected.
+	a.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = a.Authenticate(ingest.Token, apikeysv1.Permission_PERMISSION_INGEST)
+	require.ErrorIs(t, err, errInvalidToken)
+	a.now = time.Now
+
+	// Revoked keys are rejected.
+	ctx := tenant.InjectTenantID(context.Background(), "tenant-a")
+	_, err = k.RevokeAPIKey(ctx, connect.NewRequest(&apikeysv1.RevokeAPIKeyRequest{Id: admin.Key.Id}))
+	require.NoError(t, err)
+	_, err = a.Authenticate(admin.Token, apikeysv1.Permission_PERMISSION_QUERY)
+	require.ErrorIs(t, err, errInvalidToken)
+}
+
+func TestAuthenticator_HTTPMiddleware(t *testing.T) {
+	k, a := newTestAPIKeys(t, Config{})
+	key := createKey(t, k, "tenant-a", &apikeysv1.CreateAPIKeyRequest{
+		Name:        "ingest",
+		Permissions: []apikeysv1.Permission{apikeysv1.Permission_PERMISSION_INGEST},
+	})
+
+	var orgID string
+	handler := a.HTTPMiddleware().Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID = r.Header.Get(user.OrgIDHeaderName)
+	}))
+
+	for _, tc := range []struct {
+		name     string
+		path     string
+		token    string
+		orgID    string
+		expected int
+	}{
+		{name: "valid", path: "/ingest", token: key.Token, expected: http.StatusOK},
+		{name: "matching tenant", path: "/ingest", token: key.Token, orgID: "tenant-a", expected: http.StatusOK},
+		{name: "tenant mismatch", path: "/ingest", token: key.Token, orgID: "tenant-b", expected: http.StatusForbidden},
+		{name: "debuginfo upload", path: "/pyroscope/debuginfo/upload", token: key.Token, expected: http.StatusOK},
+		{name: "permission denied", path: "/querier.v1.QuerierService/SelectMergeStacktraces", token: key.Token, expected: http.StatusForbidden},
+		{name: "missing token", path: "/ingest", expected: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			orgID = ""
+			req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			if tc.orgID != "" {
+				req.Header.Set(user.OrgIDHeaderName, tc.orgID)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tc.expected, rec.Code)
+			if tc.expected == http.StatusOK {
+				require.Equal(t, "tenant-a", orgID)
+			}
+		})
+	}
+}
+
+func TestRequiredPermission(t *testing.T) {
+	for path, expected := range map[string]apikeysv1.Permission{
+		"/ingest":                      apikeysv1.Permission_PERMISSION_INGEST,
+		"/pyroscope/ingest":            apikeysv1.Permission_PERMISSION_INGEST,
+		"/pyroscope/debuginfo/upload":  apikeysv1.Permission_PERMISSION_INGEST,
+		"/push.v1.PusherService/Push":  apikeysv1.Permission_PERMISSION_INGEST,
+		"/apikeys.v1.APIKeyService/X":  apikeysv1.Permission_PERMISSION_ADMIN,
+		"/pyroscope/render":            apikeysv1.Permission_PERMISSION_QUERY,
+		"/querier.v1.QuerierService/X": apikeysv1.Permission_PERMISSION_QUERY,
+
+		"/settings.v1.SettingsService/Get":               apikeysv1.Permission_PERMISSION_QUERY,
+		"/settings.v1.SettingsService/SetUserSettings":   apikeysv1.Permission_PERMISSION_QUERY,
+		"/settings.v1.SettingsService/Set":               apikeysv1.Permission_PERMISSION_ADMIN,
+		"/settings.v1.SavedViewsService/GetSavedView":    apikeysv1.Permission_PERMISSION_QUERY,
+		"/settings.v1.SavedViewsService/UpsertSavedView": apikeysv1.Permission_PERMISSION_ADMIN,
+		"/settings.v1.SavedViewsService/DeleteSavedView": apikeysv1.Permission_PERMISSION_ADMIN,
+		"/snapshots.v1.SnapshotService/Get":              apikeysv1.Permission_PERMISSION_QUERY,
+		"/snapshots.v1.SnapshotService/Create":           apikeysv1.Permission_PERMISSION_ADMIN,
+		"/snapshots.v1.SnapshotService/Delete":           apikeysv1.Permission_PERMISSION_ADMIN,
+		"/adhocprofiles.v1.AdHocProfileService/List":     apikeysv1.Permission_PERMISSION_QUERY,
+		"/adhocprofiles.v1.AdHocProfileService/Upload":   apikeysv1.Permission_PERMISSION_ADMIN,
+		"/pyroscope/share/targets":                       apikeysv1.Permission_PERMISSION_QUERY,
+		"/pyroscope/share":                               apikeysv1.Permission_PERMISSION_ADMIN,
+		"/pyroscope/unknown":                             apikeysv1.Permission_PERMISSION_ADMIN,
+	} {
+		require.Equal(t, expected, requiredPermission(path), path)
+	}
+}
+
+func TestBucketStore_ConcurrentReplicas(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	a := newBucketStore(bucket)
+	b := newBucketStore(bucket)
+
+	newKey := func(id string) *storedKey {
+		return &storedKey{
+			Key:        &apikeysv1.APIKey{Id: id, Tenant: "tenant-a", Name: id},
+			SecretHash: hashSecret(id),
+		}
+	}
+	require.NoError(t, a.Create(ctx, newKey("0000000000000001")))
+
+	// Both replicas have seen the first key; one of them revokes it,
+	// while the other one creates a new key.
+	_, err := a.List(ctx)
+	require.NoError(t, err)
+	_, err = b.List(ctx)
+	require.NoError(t, err)
+
+	revoked, err := a.Revoke(ctx, "tenant-a", "0000000000000001", 100)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), revoked.RevokedAt)
+	require.NoError(t, b.Create(ctx, newKey("0000000000000002")))
+
+	for _, s := range []*bucketStore{a, b, newBucketStore(bucket)} {
+		keys, err := s.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, int64(100), keys["0000000000000001"].Key.RevokedAt)
+		require.Zero(t, keys["0000000000000002"].Key.RevokedAt)
+	}
+
+	// A repeated revocation keeps the original timestamp.
+	revoked, err = b.Revoke(ctx, "tenant-a", "0000000000000001", 200)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), revoked.RevokedAt)
+
+	_, err = b.Revoke(ctx, "tenant-a", "../0000000000000001", 200)
+	require.ErrorIs(t, err, keyNotFoundErr)
+	require.Error(t, b.Create(ctx, newKey("0000000000000002")))
+
+	// The keys are not mistaken for a tenant.
+	users, err := phlarebucket.ListUsers(ctx, bucket)
+	require.NoError(t, err)
+	require.Empty(t, users)
+}
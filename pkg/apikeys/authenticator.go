@@ -0,0 +1,201 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/user"
+
+	apikeysv1 "github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+var (
+	errMissingToken     = errors.New("missing API key")
+	errInvalidToken     = errors.New("invalid API key")
+	errPermissionDenied = errors.New("API key does not grant the permission required")
+	errTenantMismatch   = errors.New("API key does not belong to the tenant specified")
+)
+
+// permissionsByPrefix maps request paths (and connect procedures) to the
+// permission they require. The first matching prefix wins; requests that
+// do not match any prefix require the admin permission, therefore new
+// endpoints, and the endpoints that modify the tenant state, are only
+// allowed to admin keys unless listed explicitly.
+var permissionsByPrefix = []struct {
+	prefix     string
+	permission apikeysv1.Permission
+}{
+	{"/push.v1.PusherService/", apikeysv1.Permission_PERMISSION_INGEST},
+	{"/ingest", apikeysv1.Permission_PERMISSION_INGEST},
+	{"/pyroscope/annotations", apikeysv1.Permission_PERMISSION_INGEST},
+	{"/pyroscope/debuginfo/", apikeysv1.Permission_PERMISSION_INGEST},
+	{"/pyroscope/ingest", apikeysv1.Permission_PERMISSION_INGEST},
+	{"/opentelemetry.proto.collector.profiles.", apikeysv1.Permission_PERMISSION_INGEST},
+	{"/settings.v1.CollectionRulesService/GetCollectionRules", apikeysv1.Permission_PERMISSION_INGEST},
+	{"/querier.v1.QuerierService/", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/vcs.v1.VCSService/", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/settings.v1.SettingsService/Get", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/settings.v1.SettingsService/SetUserSettings", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/settings.v1.SavedViewsService/ListSavedViews", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/settings.v1.SavedViewsService/GetSavedView", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/snapshots.v1.SnapshotService/Get", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/adhocprofiles.v1.AdHocProfileService/Get", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/adhocprofiles.v1.AdHocProfileService/List", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/api/v1/tenant_limits", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/render", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/label-values", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/export", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/cost-attribution/", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/binaries", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/live", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/share/targets", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/pyroscope/summary", apikeysv1.Permission_PERMISSION_QUERY},
+	{"/prometheus/api/v1/read", apikeysv1.Permission_PERMISSION_QUERY},
+}
+
+func requiredPermission(path string) apikeysv1.Permission {
+	for _, p := range permissionsByPrefix {
+		if strings.HasPrefix(path, p.prefix) {
+			return p.permission
+		}
+	}
+	return apikeysv1.Permission_PERMISSION_ADMIN
+}
+
+// Authenticator validates the API keys of incoming requests. The set of
+// known keys is updated by the APIKeys service.
+type Authenticator struct {
+	adminToken string
+	keys       atomic.Pointer[map[string]*storedKey]
+	now        func() time.Time
+}
+
+func NewAuthenticator(cfg Config) *Authenticator {
+	a := &Authenticator{
+		adminToken: cfg.AdminToken.String(),
+		now:        time.Now,
+	}
+	a.update(make(map[string]*storedKey))
+	return a
+}
+
+func (a *Authenticator) update(keys map[string]*storedKey) {
+	a.keys.Store(&keys)
+}
+
+// Authenticate checks that the token is valid and grants the permission.
+// It returns the tenant the key belongs to; the tenant is empty for the
+// admin token, which is not bound to any tenant.
+func (a *Authenticator) Authenticate(token string, permission apikeysv1.Permission) (string, error) {
+	if token == "" {
+		return "", errMissingToken
+	}
+	if a.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) == 1 {
+		return "", nil
+	}
+	id, secret, err := parseToken(token)
+	if err != nil {
+		return "", err
+	}
+	key, ok := (*a.keys.Load())[id]
+	if !ok || subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.SecretHash)) != 1 {
+		return "", errInvalidToken
+	}
+	now := a.now().UnixMilli()
+	if key.Key.RevokedAt > 0 || (key.Key.ExpiresAt > 0 && key.Key.ExpiresAt <= now) {
+		return "", errInvalidToken
+	}
+	if !slices.Contains(key.Key.Permissions, permission) &&
+		!slices.Contains(key.Key.Permissions, apikeysv1.Permission_PERMISSION_ADMIN) {
+		return "", errPermissionDenied
+	}
+	return key.Key.Tenant, nil
+}
+
+// authenticate validates the request token and sets the tenant header
+// to the tenant of the key, so that it can be picked up by the tenant
+// authentication middleware.
+func (a *Authenticator) authenticate(path string, header http.Header) error {
+	token, _ := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	tenantID, err := a.Authenticate(token, requiredPermission(path))
+	if err != nil || tenantID == "" {
+		return err
+	}
+	if orgID := header.Get(user.OrgIDHeaderName); orgID != "" && orgID != tenantID {
+		return errTenantMismatch
+	}
+	header.Set(user.OrgIDHeaderName, tenantID)
+	return nil
+}
+
+// HTTPMiddleware validates the API keys of HTTP requests. It must
+// precede the tenant authentication middleware.
+func (a *Authenticator) HTTPMiddleware() middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := a.authenticate(r.URL.Path, r.Header); err != nil {
+				httputil.ErrorWithStatus(w, err, httpStatus(err))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+// Interceptor validates the API keys of connect requests. It must
+// precede the tenant authentication interceptor.
+func (a *Authenticator) Interceptor() connect.Interceptor {
+	return &interceptor{authenticator: a}
+}
+
+type interceptor struct {
+	authenticator *Authenticator
+}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.authenticator.authenticate(req.Spec().Procedure, req.Header()); err != nil {
+			return nil, connect.NewError(connectCode(err), err)
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.authenticator.authenticate(conn.Spec().Procedure, conn.RequestHeader()); err != nil {
+			return connect.NewError(connectCode(err), err)
+		}
+		return next(ctx, conn)
+	}
+}
+
+func connectCode(err error) connect.Code {
+	if errors.Is(err, errPermissionDenied) || errors.Is(err, errTenantMismatch) {
+		return connect.CodePermissionDenied
+	}
+	return connect.CodeUnauthenticated
+}
+
+func httpStatus(err error) int {
+	if errors.Is(err, errPermissionDenied) || errors.Is(err, errTenantMismatch) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
@@ -0,0 +1,20 @@
+package apikeys
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+type Config struct {
+	Enabled         bool           `yaml:"enabled" category:"experimental"`
+	AdminToken      flagext.Secret `yaml:"admin_token" category:"experimental"`
+	RefreshInterval time.Duration  `yaml:"refresh_interval" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "auth.api-keys.enabled", false, "When set to true, requests to the ingestion, query and settings APIs must carry a valid API key as the bearer token in the Authorization header. The key determines the tenant of the request.")
+	f.Var(&cfg.AdminToken, "auth.api-keys.admin-token", "Bearer token that grants admin permissions for any tenant, specified in the X-Scope-OrgID header. It is intended for bootstrapping: creating the first API keys.")
+	f.DurationVar(&cfg.RefreshInterval, "auth.api-keys.refresh-interval", 10*time.Second, "How often API keys are reloaded from the object storage. Keys revoked by other replicas are rejected after this interval at the latest.")
+}
@@ -0,0 +1,130 @@
+package apikeys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	apikeysv1 "github.com/grafana/pyroscope/api/gen/proto/go/apikeys/v1"
+	"github.com/grafana/pyroscope/pkg/phlaredb/bucket"
+)
+
+var keyNotFoundErr = errors.New("API key not found")
+
+// keysPrefix is the bucket prefix of the keys. Each key is stored in its
+// own object, so that concurrent writes of different replicas never
+// overwrite each other: a revocation can't be undone by a key created
+// at the same time elsewhere. The keys are cluster-wide objects, and
+// must not be mistaken for a tenant.
+const keysPrefix = bucket.PyroscopeInternalsPrefix + "/api_keys/"
+
+type storedKey struct {
+	Key        *apikeysv1.APIKey `json:"key"`
+	SecretHash string            `json:"secretHash"`
+}
+
+func newBucketStore(bucket objstore.Bucket) *bucketStore {
+	return &bucketStore{bucket: bucket}
+}
+
+type bucketStore struct {
+	// bucket is an object store bucket.
+	bucket objstore.Bucket
+}
+
+func keyPath(id string) string {
+	return path.Join(keysPrefix, id+".json")
+}
+
+// List returns all keys of all tenants, indexed by key id.
+func (s *bucketStore) List(ctx context.Context) (map[string]*storedKey, error) {
+	keys := make(map[string]*storedKey)
+	err := s.bucket.Iter(ctx, keysPrefix, func(name string) error {
+		if !strings.HasSuffix(name, ".json") {
+			return nil
+		}
+		key, err := s.get(ctx, name)
+		if err != nil {
+			if s.bucket.IsObjNotFoundErr(err) {
+				// Deleted after listing.
+				return nil
+			}
+			return err
+		}
+		keys[key.Key.Id] = key
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *bucketStore) Create(ctx context.Context, key *storedKey) error {
+	p := keyPath(key.Key.Id)
+	exists, err := s.bucket.Exists(ctx, p)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.Errorf("API key %s already exists", key.Key.Id)
+	}
+	return s.put(ctx, p, key)
+}
+
+// Revoke marks the key as revoked. Revoked keys are kept in the
+// store, so that they can be listed.
+func (s *bucketStore) Revoke(ctx context.Context, tenantID, id string, revokedAt int64) (*apikeysv1.APIKey, error) {
+	if id == "" || strings.ContainsAny(id, "/.") {
+		return nil, errors.Wrapf(keyNotFoundErr, "failed to revoke %s", id)
+	}
+	p := keyPath(id)
+	key, err := s.get(ctx, p)
+	if err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			return nil, errors.Wrapf(keyNotFoundErr, "failed to revoke %s", id)
+		}
+		return nil, err
+	}
+	if key.Key.Tenant != tenantID {
+		return nil, errors.Wrapf(keyNotFoundErr, "failed to revoke %s", id)
+	}
+	if key.Key.RevokedAt != 0 {
+		return key.Key, nil
+	}
+	key.Key.RevokedAt = revokedAt
+	if err = s.put(ctx, p, key); err != nil {
+		return nil, err
+	}
+	return key.Key, nil
+}
+
+func (s *bucketStore) get(ctx context.Context, name string) (*storedKey, error) {
+	reader, err := s.bucket.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var key storedKey
+	if err = json.NewDecoder(reader).Decode(&key); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s", name)
+	}
+	if key.Key == nil {
+		return nil, errors.Errorf("invalid API key object %s", name)
+	}
+	return &key, nil
+}
+
+func (s *bucketStore) put(ctx context.Context, name string, key *storedKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return s.bucket.Upload(ctx, name, bytes.NewReader(data))
+}
@@ -0,0 +1,46 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Tokens have the form "pyro_<id>_<secret>". Only the hash
+// of the secret is stored; the id is used to look up the key.
+const (
+	tokenPrefix  = "pyro_"
+	idLength     = 8
+	secretLength = 24
+)
+
+var errMalformedToken = errors.New("malformed API key")
+
+func newToken() (id, secret, token string, err error) {
+	b := make([]byte, idLength+secretLength)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	id = hex.EncodeToString(b[:idLength])
+	secret = hex.EncodeToString(b[idLength:])
+	return id, secret, tokenPrefix + id + "_" + secret, nil
+}
+
+func parseToken(token string) (id, secret string, err error) {
+	rest, ok := strings.CutPrefix(token, tokenPrefix)
+	if !ok {
+		return "", "", errMalformedToken
+	}
+	id, secret, ok = strings.Cut(rest, "_")
+	if !ok || len(id) != 2*idLength || len(secret) != 2*secretLength {
+		return "", "", errMalformedToken
+	}
+	return id, secret, nil
+}
+
+func hashSecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
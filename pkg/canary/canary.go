@@ -0,0 +1,291 @@
+// Package canary implements a component that continuously verifies the
+// write and read paths end-to-end: it writes a synthetic profile for
+// every configured tenant and queries it back, exporting the time it
+// takes the profile to become queryable, and whether the data read
+// matches the data written.
+package canary
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/go-cmp/cmp"
+	gprofile "github.com/google/pprof/profile"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
+	"github.com/grafana/pyroscope/pkg/pprof/testhelper"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+const (
+	// The profile is queried within the window starting at the profile
+	// time; the window must not include the profiles of other probes.
+	queryWindow = time.Second
+	minInterval = 2 * queryWindow
+	// How often the canary queries the profile until it is found.
+	pollInterval = 500 * time.Millisecond
+
+	serviceName   = "pyroscope-canary"
+	profileTypeID = "canary:probes:count:probes:count"
+)
+
+const (
+	resultSuccess    = "success"
+	resultWriteError = "write_error"
+	resultQueryError = "query_error"
+	resultTimeout    = "timeout"
+	resultMismatch   = "mismatch"
+)
+
+type Canary struct {
+	services.Service
+
+	config   Config
+	logger   log.Logger
+	metrics  *metrics
+	instance string
+
+	pusher  pushv1connect.PusherServiceClient
+	querier querierv1connect.QuerierServiceClient
+}
+
+func New(config Config, logger log.Logger, reg prometheus.Registerer) *Canary {
+	httpClient := &http.Client{Transport: &authRoundTripper{
+		token: config.BearerToken.String(),
+		next:  http.DefaultTransport,
+	}}
+	opts := append(connectapi.DefaultClientOptions(), connect.WithInterceptors(tenant.NewAuthInterceptor(true)))
+	return newCanary(config, logger, reg,
+		pushv1connect.NewPusherServiceClient(httpClient, config.URL, opts...),
+		querierv1connect.NewQuerierServiceClient(httpClient, config.URL, opts...),
+	)
+}
+
+func newCanary(
+	config Config,
+	logger log.Logger,
+	reg prometheus.Registerer,
+	pusher pushv1connect.PusherServiceClient,
+	querier querierv1connect.QuerierServiceClient,
+) *Canary {
+	if len(config.Tenants) == 0 {
+		config.Tenants = []string{tenant.DefaultTenantID}
+	}
+	c := &Canary{
+		config:   config,
+		logger:   logger,
+		metrics:  newMetrics(reg),
+		instance: "unknown",
+		pusher:   pusher,
+		querier:  querier,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		c.instance = hostname
+	}
+	c.Service = services.NewTimerService(config.Interval, nil, c.iteration, nil).WithName("canary")
+	return c
+}
+
+func (c *Canary) iteration(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, tenantID := range c.config.Tenants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.probe(ctx, tenantID)
+		}()
+	}
+	wg.Wait()
+	// Probe failures are reported with metrics,
+	// and must not stop the service.
+	return nil
+}
+
+func (c *Canary) probe(ctx context.Context, tenantID string) {
+	ctx, cancel := context.WithTimeout(tenant.InjectTenantID(ctx, tenantID), c.config.Timeout)
+	defer cancel()
+	logger := log.With(c.logger, "tenant", tenantID)
+	result := resultSuccess
+	defer func() {
+		c.metrics.probes.WithLabelValues(tenantID, result).Inc()
+	}()
+
+	start := time.Now()
+	expected, err := c.write(ctx, start)
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to write canary profile", "err", err)
+		result = resultWriteError
+		return
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		actual, err := c.read(ctx, start)
+		switch {
+		case ctx.Err() != nil:
+			level.Warn(logger).Log("msg", "canary profile is not queryable within the timeout", "timeout", c.config.Timeout)
+			result = resultTimeout
+			return
+
+		case err != nil:
+			level.Warn(logger).Log("msg", "failed to query canary profile", "err", err)
+			result = resultQueryError
+			return
+
+		case len(actual) > 0:
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				level.Warn(logger).Log("msg", "canary profile mismatch (-expected, +actual)", "diff", diff)
+				result = resultMismatch
+				c.metrics.correct.WithLabelValues(tenantID).Set(0)
+				return
+			}
+			c.metrics.correct.WithLabelValues(tenantID).Set(1)
+			c.metrics.freshnessLag.WithLabelValues(tenantID).Set(time.Since(start).Seconds())
+			c.metrics.lastSuccess.WithLabelValues(tenantID).SetToCurrentTime()
+			return
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// write writes a profile with random values, and returns the
+// expected values of the stack traces read back.
+func (c *Canary) write(ctx context.Context, t time.Time) (map[string]int64, error) {
+	n := rand.Int63n(1000) + 1
+	p := testhelper.NewProfileBuilderWithLabels(t.UnixNano(), nil)
+	p.CustomProfile("canary", "probes", "count", "probes", "count")
+	p.WithLabels(
+		"service_name", serviceName,
+		"instance", c.instance,
+	)
+	p.ForStacktraceString("probe", "canary").AddSamples(n)
+	p.ForStacktraceString("canary").AddSamples(2 * n)
+	data, err := p.Profile.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.pusher.Push(ctx, connect.NewRequest(&pushv1.PushRequest{
+		Series: []*pushv1.RawProfileSeries{{
+			Labels: p.Labels,
+			Samples: []*pushv1.RawSample{{
+				ID:         uuid.New().String(),
+				RawProfile: data,
+			}},
+		}},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int64{
+		"probe>canary": n,
+		"canary":       2 * n,
+	}, nil
+}
+
+// read returns the values of the stack traces of the
+// profile written at the given time, if it is found.
+func (c *Canary) read(ctx context.Context, t time.Time) (map[string]int64, error) {
+	resp, err := c.querier.SelectMergeProfile(ctx, connect.NewRequest(&querierv1.SelectMergeProfileRequest{
+		ProfileTypeID: profileTypeID,
+		LabelSelector: fmt.Sprintf(`{service_name=%q, instance=%q}`, serviceName, c.instance),
+		Start:         t.UnixMilli(),
+		End:           t.Add(queryWindow).UnixMilli(),
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Msg.Sample) == 0 {
+		return nil, nil
+	}
+	b, err := resp.Msg.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	p, err := gprofile.Parse(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]int64)
+	var sb strings.Builder
+	for _, s := range p.Sample {
+		sb.Reset()
+		for _, loc := range s.Location {
+			for _, line := range loc.Line {
+				if sb.Len() > 0 {
+					sb.WriteByte('>')
+				}
+				sb.WriteString(line.Function.Name)
+			}
+		}
+		values[sb.String()] += s.Value[0]
+	}
+	return values, nil
+}
+
+type authRoundTripper struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.token != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+rt.token)
+	}
+	return rt.next.RoundTrip(req)
+}
+
+type metrics struct {
+	probes       *prometheus.CounterVec
+	freshnessLag *prometheus.GaugeVec
+	correct      *prometheus.GaugeVec
+	lastSuccess  *prometheus.GaugeVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		probes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_canary_probes_total",
+			Help: "Total number of canary probes by result.",
+		}, []string{"tenant", "result"}),
+		freshnessLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "pyroscope_canary_freshness_lag_seconds",
+			Help: "Time it took the last canary profile to become queryable after it was written.",
+		}, []string{"tenant"}),
+		correct: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "pyroscope_canary_read_correct",
+			Help: "Whether the last canary profile read back matched the profile written: 1 if it did, 0 otherwise.",
+		}, []string{"tenant"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "pyroscope_canary_last_success_timestamp_seconds",
+			Help: "Time of the last successful canary probe.",
+		}, []string{"tenant"}),
+	}
+	m.probes = util.RegisterOrGet(reg, m.probes)
+	m.freshnessLag = util.RegisterOrGet(reg, m.freshnessLag)
+	m.correct = util.RegisterOrGet(reg, m.correct)
+	m.lastSuccess = util.RegisterOrGet(reg, m.lastSuccess)
+	return m
+}
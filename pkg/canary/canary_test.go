@@ -0,0 +1,147 @@
+package canary
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	googlev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+// fakeStore keeps the profiles written, and makes them
+// queryable after the given number of queries.
+type fakeStore struct {
+	querierv1connect.QuerierServiceClient
+
+	mu       sync.Mutex
+	delay    int
+	queries  int
+	tenants  []string
+	profiles map[string]*googlev1.Profile
+	writeErr error
+	mutate   func(*googlev1.Profile)
+}
+
+func (s *fakeStore) Push(ctx context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writeErr != nil {
+		return nil, s.writeErr
+	}
+	tenantID, err := tenant.ExtractTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.tenants = append(s.tenants, tenantID)
+	var p googlev1.Profile
+	if err = p.UnmarshalVT(req.Msg.Series[0].Samples[0].RawProfile); err != nil {
+		return nil, err
+	}
+	if s.profiles == nil {
+		s.profiles = make(map[string]*googlev1.Profile)
+	}
+	s.profiles[tenantID] = &p
+	return connect.NewResponse(new(pushv1.PushResponse)), nil
+}
+
+func (s *fakeStore) SelectMergeProfile(ctx context.Context, _ *connect.Request[querierv1.SelectMergeProfileRequest]) (*connect.Response[googlev1.Profile], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries++
+	if s.queries <= s.delay {
+		return connect.NewResponse(new(googlev1.Profile)), nil
+	}
+	tenantID, err := tenant.ExtractTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := s.profiles[tenantID].CloneVT()
+	if s.mutate != nil {
+		s.mutate(p)
+	}
+	return connect.NewResponse(p), nil
+}
+
+func newTestCanary(t *testing.T, s *fakeStore, tenants ...string) (*Canary, *prometheus.Registry) {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	c := newCanary(Config{
+		Tenants:  tenants,
+		Interval: time.Minute,
+		Timeout:  2 * time.Second,
+	}, log.NewNopLogger(), reg, s, s)
+	return c, reg
+}
+
+func TestCanary_Probe(t *testing.T) {
+	s := &fakeStore{delay: 2}
+	c, _ := newTestCanary(t, s, "tenant-a", "tenant-b")
+	require.NoError(t, c.iteration(context.Background()))
+
+	assert.ElementsMatch(t, []string{"tenant-a", "tenant-b"}, s.tenants)
+	for _, tenantID := range []string{"tenant-a", "tenant-b"} {
+		assert.Equal(t, float64(1), testutil.ToFloat64(c.metrics.probes.WithLabelValues(tenantID, resultSuccess)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(c.metrics.correct.WithLabelValues(tenantID)))
+		assert.Greater(t, testutil.ToFloat64(c.metrics.freshnessLag.WithLabelValues(tenantID)), float64(0))
+		assert.Greater(t, testutil.ToFloat64(c.metrics.lastSuccess.WithLabelValues(tenantID)), float64(0))
+	}
+}
+
+func TestCanary_DefaultTenant(t *testing.T) {
+	s := new(fakeStore)
+	c, _ := newTestCanary(t, s)
+	require.NoError(t, c.iteration(context.Background()))
+	assert.Equal(t, []string{tenant.DefaultTenantID}, s.tenants)
+}
+
+func TestCanary_Mismatch(t *testing.T) {
+	s := &fakeStore{mutate: func(p *googlev1.Profile) {
+		// The profile is returned twice, e.g., if it has been ingested twice.
+		for _, x := range p.Sample {
+			x.Value[0] *= 2
+		}
+	}}
+	c, _ := newTestCanary(t, s, "tenant")
+	require.NoError(t, c.iteration(context.Background()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.metrics.probes.WithLabelValues("tenant", resultMismatch)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(c.metrics.correct.WithLabelValues("tenant")))
+}
+
+func TestCanary_Failures(t *testing.T) {
+	s := &fakeStore{delay: 100}
+	c, reg := newTestCanary(t, s, "tenant")
+	c.config.Timeout = time.Second
+	require.NoError(t, c.iteration(context.Background()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.metrics.probes.WithLabelValues("tenant", resultTimeout)))
+
+	s.writeErr = errors.New("unavailable")
+	require.NoError(t, c.iteration(context.Background()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.metrics.probes.WithLabelValues("tenant", resultWriteError)))
+
+	// No successful probes.
+	n, err := testutil.GatherAndCount(reg, "pyroscope_canary_last_success_timestamp_seconds", "pyroscope_canary_read_correct")
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := Config{URL: "http://localhost:4040", Interval: 15 * time.Second}
+	assert.NoError(t, cfg.Validate())
+	cfg.Interval = time.Second
+	assert.Error(t, cfg.Validate())
+	cfg = Config{Interval: 15 * time.Second}
+	assert.Error(t, cfg.Validate())
+}
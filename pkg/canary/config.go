@@ -0,0 +1,37 @@
+package canary
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+type Config struct {
+	URL         string                 `yaml:"url" category:"experimental"`
+	Tenants     flagext.StringSliceCSV `yaml:"tenants" category:"experimental"`
+	BearerToken flagext.Secret         `yaml:"bearer_token" category:"experimental"`
+	Interval    time.Duration          `yaml:"interval" category:"experimental"`
+	Timeout     time.Duration          `yaml:"timeout" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	const prefix = "canary."
+	f.StringVar(&cfg.URL, prefix+"url", "http://localhost:4040", "URL of the Pyroscope API the canary writes the profiles to and queries them from. The canary only runs if the canary module is included in the -target list.")
+	f.Var(&cfg.Tenants, prefix+"tenants", "Comma-separated list of tenants the canary writes the profiles for. If empty, the anonymous tenant is used.")
+	f.Var(&cfg.BearerToken, prefix+"bearer-token", "Bearer token sent in the Authorization header, e.g., the API keys admin token.")
+	f.DurationVar(&cfg.Interval, prefix+"interval", 15*time.Second, "How often the canary writes a profile and queries it back.")
+	f.DurationVar(&cfg.Timeout, prefix+"timeout", time.Minute, "Maximum time to wait for the profile written to become queryable. The probe fails if the profile can not be queried within the timeout.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.URL == "" {
+		return errors.New("canary.url is required")
+	}
+	// The profiles written are told apart by their time.
+	if cfg.Interval < minInterval {
+		return errors.New("canary.interval must be at least " + minInterval.String())
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package distributor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+// activeSeriesWindow is the period of the active series generations:
+// a series is considered active if it has been seen within the last
+// one to two windows, which roughly matches the ingester timeout.
+const activeSeriesWindow = 5 * time.Minute
+
+// activeSeriesLimiter tracks active series of tenants and limits the
+// number of new series: the series that are already active are always
+// accepted.
+//
+// Series are tracked by the label set fingerprint in two generations,
+// which are rotated every window: the series that have not been seen
+// during the last two windows are evicted. The memory is bounded by the
+// limit: new series are not tracked once the limit is reached.
+//
+// Each distributor tracks the series independently: because requests are
+// distributed evenly, every active series is expected to be seen by every
+// distributor within the window, so the local count approximates the
+// global one.
+type activeSeriesLimiter struct {
+	service services.Service
+	window  time.Duration
+
+	mu      sync.RWMutex
+	tenants map[string]*activeSeries
+
+	activeSeries *prometheus.GaugeVec
+}
+
+func newActiveSeriesLimiter(reg prometheus.Registerer) *activeSeriesLimiter {
+	l := &activeSeriesLimiter{
+		window:  activeSeriesWindow,
+		tenants: make(map[string]*activeSeries),
+		activeSeries: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "pyroscope",
+			Name:      "distributor_active_series",
+			Help:      "The number of active series per tenant observed by the distributor.",
+		}, []string{"tenant"}),
+	}
+	l.activeSeries = util.RegisterOrGet(reg, l.activeSeries)
+	l.service = services.NewTimerService(l.window, nil, l.iteration, nil)
+	return l
+}
+
+func (l *activeSeriesLimiter) iteration(context.Context) error {
+	l.rotate(time.Now())
+	return nil
+}
+
+// allow reports whether the series is allowed to be ingested, and the
+// number of active series of the tenant.
+func (l *activeSeriesLimiter) allow(tenantID string, fp uint64, limit int) (bool, int) {
+	now := time.Now()
+	l.mu.RLock()
+	s, ok := l.tenants[tenantID]
+	l.mu.RUnlock()
+	if !ok {
+		l.mu.Lock()
+		if s, ok = l.tenants[tenantID]; !ok {
+			s = newActiveSeries(now)
+			l.tenants[tenantID] = s
+		}
+		l.mu.Unlock()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotate(now, l.window)
+	allowed := s.allow(fp, limit)
+	n := s.size()
+	l.activeSeries.WithLabelValues(tenantID).Set(float64(n))
+	return allowed, n
+}
+
+// rotate rotates the generations of all the tenants; the tenants that
+// have no active series are removed.
+func (l *activeSeriesLimiter) rotate(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for tenantID, s := range l.tenants {
+		s.mu.Lock()
+		s.rotate(now, l.window)
+		n := s.size()
+		s.mu.Unlock()
+		if n == 0 {
+			delete(l.tenants, tenantID)
+			l.activeSeries.DeleteLabelValues(tenantID)
+			continue
+		}
+		l.activeSeries.WithLabelValues(tenantID).Set(float64(n))
+	}
+}
+
+type activeSeries struct {
+	mu        sync.Mutex
+	current   map[uint64]struct{}
+	previous  map[uint64]struct{}
+	rotatedAt time.Time
+}
+
+func newActiveSeries(now time.Time) *activeSeries {
+	return &activeSeries{
+		current:   make(map[uint64]struct{}),
+		previous:  make(map[uint64]struct{}),
+		rotatedAt: now,
+	}
+}
+
+func (s *activeSeries) rotate(now time.Time, window time.Duration) {
+	elapsed := now.Sub(s.rotatedAt)
+	if elapsed < window {
+		return
+	}
+	if elapsed < 2*window {
+		s.previous = s.current
+	} else {
+		clear(s.previous)
+	}
+	s.current = make(map[uint64]struct{}, len(s.previous))
+	s.rotatedAt = now
+}
+
+// allow returns true if the series is active, or it can be added without
+// exceeding the limit. The series is promoted to the current generation.
+// The generations are disjoint, therefore the number of active series is
+// the sum of their sizes.
+func (s *activeSeries) allow(fp uint64, limit int) bool {
+	if _, ok := s.current[fp]; ok {
+		return true
+	}
+	if _, ok := s.previous[fp]; ok {
+		delete(s.previous, fp)
+		s.current[fp] = struct{}{}
+		return true
+	}
+	if limit > 0 && s.size() >= limit {
+		return false
+	}
+	s.current[fp] = struct{}{}
+	return true
+}
+
+func (s *activeSeries) size() int { return len(s.current) + len(s.previous) }
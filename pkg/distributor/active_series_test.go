@@ -0,0 +1,139 @@
+package distributor
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/ring/client"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	segmentwriterv1 "github.com/grafana/pyroscope/api/gen/proto/go/segmentwriter/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	writepath "github.com/grafana/pyroscope/pkg/distributor/write_path"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	pprof2 "github.com/grafana/pyroscope/pkg/pprof"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/testhelper"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+func Test_activeSeries(t *testing.T) {
+	const window = time.Minute
+	now := time.Unix(0, 0)
+	s := newActiveSeries(now)
+
+	assert.True(t, s.allow(1, 2))
+	assert.True(t, s.allow(2, 2))
+	assert.False(t, s.allow(3, 2))
+	assert.True(t, s.allow(1, 2), "active series must be allowed")
+	assert.Equal(t, 2, s.size())
+
+	// Series 2 is not seen during the next window,
+	// and it's evicted after the second rotation.
+	s.rotate(now.Add(window), window)
+	assert.True(t, s.allow(1, 2))
+	assert.False(t, s.allow(3, 2))
+	assert.Equal(t, 2, s.size())
+	s.rotate(now.Add(2*window), window)
+	assert.Equal(t, 1, s.size())
+	assert.True(t, s.allow(3, 2))
+	assert.False(t, s.allow(2, 2))
+
+	// All the series are evicted if none of them
+	// have been seen for two windows.
+	s.rotate(now.Add(5*window), window)
+	assert.Equal(t, 0, s.size())
+}
+
+type fakeSegmentWriter struct {
+	mu       sync.Mutex
+	requests []*segmentwriterv1.PushRequest
+}
+
+func (f *fakeSegmentWriter) Push(_ context.Context, req *segmentwriterv1.PushRequest) (*segmentwriterv1.PushResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	return &segmentwriterv1.PushResponse{}, nil
+}
+
+func TestPush_ActiveSeriesLimit(t *testing.T) {
+	const tenantID = "user-series-limit"
+	segwriter := new(fakeSegmentWriter)
+	d, err := New(
+		Config{DistributorRing: ringConfig},
+		testhelper.NewMockRing([]ring.InstanceDesc{{Addr: "foo"}}, 3),
+		&poolFactory{f: func(addr string) (client.PoolClient, error) { return newFakeIngester(t, false), nil }},
+		validation.MockOverrides(func(defaults *validation.Limits, tenantLimits map[string]*validation.Limits) {
+			l := validation.MockDefaultLimits()
+			l.MaxActiveSeriesPerTenant = 2
+			l.WritePathOverrides.WritePath = writepath.SegmentWriterPath
+			tenantLimits[tenantID] = l
+			// The ingester limits do not apply to the new write path.
+			l = validation.MockDefaultLimits()
+			l.MaxGlobalSeriesPerTenant = 1
+			l.WritePathOverrides.WritePath = writepath.SegmentWriterPath
+			tenantLimits["user-2"] = l
+		}),
+		nil, log.NewLogfmtLogger(os.Stdout), segwriter,
+	)
+	require.NoError(t, err)
+	ctx := tenant.InjectTenantID(context.Background(), tenantID)
+
+	series := func(names ...string) *distributormodel.PushRequest {
+		req := new(distributormodel.PushRequest)
+		for _, name := range names {
+			req.Series = append(req.Series, &distributormodel.ProfileSeries{
+				Labels: []*typesv1.LabelPair{
+					{Name: phlaremodel.LabelNameServiceName, Value: name},
+					{Name: "__name__", Value: "cpu"},
+				},
+				Samples: []*distributormodel.ProfileSample{{
+					Profile: &pprof2.Profile{Profile: testProfile(0)},
+				}},
+			})
+		}
+		return req
+	}
+
+	discarded := validation.DiscardedProfiles.WithLabelValues(string(validation.SeriesLimit), tenantID)
+	discardedBefore := testutil.ToFloat64(discarded)
+
+	_, err = d.PushParsed(ctx, series("svc-1", "svc-2"))
+	require.NoError(t, err)
+	// The new series is discarded, the active one is accepted.
+	_, err = d.PushParsed(ctx, series("svc-1", "svc-3"))
+	require.NoError(t, err)
+	// The request is rejected if all the series are discarded.
+	_, err = d.PushParsed(ctx, series("svc-4"))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	assert.Equal(t, validation.SeriesLimit, validation.ReasonOf(err))
+
+	var services []string
+	for _, r := range segwriter.requests {
+		services = append(services, phlaremodel.Labels(r.Labels).Get(phlaremodel.LabelNameServiceName))
+	}
+	assert.ElementsMatch(t, []string{"svc-1", "svc-2", "svc-1"}, services)
+	assert.Equal(t, float64(2), testutil.ToFloat64(discarded)-discardedBefore)
+	assert.Equal(t, float64(2), testutil.ToFloat64(
+		d.seriesLimiter.activeSeries.WithLabelValues(tenantID)))
+
+	// Other tenants are not affected.
+	for _, tenantID := range []string{"user-1", "user-2"} {
+		for i := 0; i < 3; i++ {
+			_, err = d.PushParsed(tenant.InjectTenantID(context.Background(), tenantID), series(strconv.Itoa(i)))
+			require.NoError(t, err)
+		}
+	}
+}
@@ -19,7 +19,6 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
 	"github.com/grafana/dskit/kv"
-	"github.com/grafana/dskit/limiter"
 	"github.com/grafana/dskit/ring"
 	ring_client "github.com/grafana/dskit/ring/client"
 	"github.com/grafana/dskit/services"
@@ -37,17 +36,21 @@ import (
 	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
 	"github.com/grafana/pyroscope/pkg/clientpool"
 	"github.com/grafana/pyroscope/pkg/distributor/aggregator"
+	"github.com/grafana/pyroscope/pkg/distributor/forwarder"
 	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
 	writepath "github.com/grafana/pyroscope/pkg/distributor/write_path"
 	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	pprofsplit "github.com/grafana/pyroscope/pkg/model/pprof_split"
 	"github.com/grafana/pyroscope/pkg/model/relabel"
+	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/pprof"
 	"github.com/grafana/pyroscope/pkg/slices"
+	"github.com/grafana/pyroscope/pkg/symbolizer"
 	"github.com/grafana/pyroscope/pkg/tenant"
 	"github.com/grafana/pyroscope/pkg/usagestats"
 	"github.com/grafana/pyroscope/pkg/util"
 	"github.com/grafana/pyroscope/pkg/validation"
+	"github.com/grafana/pyroscope/pkg/webhooks"
 )
 
 type PushClient interface {
@@ -72,6 +75,14 @@ type Config struct {
 
 	// Distributors ring
 	DistributorRing util.CommonRingConfig `yaml:"ring" doc:"hidden"`
+
+	Forwarding forwarder.Config  `yaml:"forwarding"`
+	Symbolizer symbolizer.Config `yaml:"symbolizer"`
+
+	// Notifier is injected by the upstream caller.
+	Notifier *webhooks.Notifier `yaml:"-"`
+	// Bucket is injected by the upstream caller.
+	Bucket objstore.Bucket `yaml:"-"`
 }
 
 // RegisterFlags registers distributor-related flags.
@@ -79,6 +90,12 @@ func (cfg *Config) RegisterFlags(fs *flag.FlagSet, logger log.Logger) {
 	cfg.PoolConfig.RegisterFlagsWithPrefix("distributor", fs)
 	fs.DurationVar(&cfg.PushTimeout, "distributor.push.timeout", 5*time.Second, "Timeout when pushing data to ingester.")
 	cfg.DistributorRing.RegisterFlags("distributor.ring.", "collectors/", "distributors", fs, logger)
+	cfg.Forwarding.RegisterFlagsWithPrefix("distributor.forwarding.", fs)
+	cfg.Symbolizer.RegisterFlagsWithPrefix("distributor.symbolizer.", fs)
+}
+
+func (cfg *Config) Validate() error {
+	return cfg.Forwarding.Validate()
 }
 
 // Distributor coordinates replicates and distribution of log streams.
@@ -93,12 +110,17 @@ type Distributor struct {
 
 	// The global rate limiter requires a distributors ring to count
 	// the number of healthy instances
-	distributorsLifecycler *ring.BasicLifecycler
-	distributorsRing       *ring.Ring
-	healthyInstancesCount  *atomic.Uint32
-	ingestionRateLimiter   *limiter.RateLimiter
-	aggregator             *aggregator.MultiTenantAggregator[*pprof.ProfileMerge]
-	asyncRequests          sync.WaitGroup
+	distributorsLifecycler  *ring.BasicLifecycler
+	distributorsRing        *ring.Ring
+	healthyInstancesCount   *atomic.Uint32
+	ingestionRateLimiter    *rateLimiter
+	profileTypeRateLimiter  *rateLimiter
+	aggregator              *aggregator.MultiTenantAggregator[*pprof.ProfileMerge]
+	seriesLimiter           *activeSeriesLimiter
+	labelCardinalityLimiter *labelCardinalityLimiter
+	forwarder               *forwarder.Forwarder
+	symbolizer              *symbolizer.Symbolizer
+	asyncRequests           sync.WaitGroup
 
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -117,6 +139,7 @@ type Distributor struct {
 type Limits interface {
 	IngestionRateBytes(tenantID string) float64
 	IngestionBurstSizeBytes(tenantID string) int
+	IngestionProfileTypeRateLimits(tenantID string) validation.ProfileTypeRateLimits
 	IngestionTenantShardSize(tenantID string) int
 	MaxLabelNameLength(tenantID string) int
 	MaxLabelValueLength(tenantID string) int
@@ -127,10 +150,15 @@ type Limits interface {
 	MaxProfileStacktraceDepth(tenantID string) int
 	MaxProfileSymbolValueLength(tenantID string) int
 	MaxSessionsPerSeries(tenantID string) int
+	MaxLabelValueCardinality(tenantID string) int
+	LabelCardinalityPlaceholder(tenantID string) string
+	MaxActiveSeriesPerTenant(tenantID string) int
+	ReservedLabelNames(tenantID string) []string
 	EnforceLabelsOrder(tenantID string) bool
 	IngestionRelabelingRules(tenantID string) []*relabel.Config
 	DistributorUsageGroups(tenantID string) *validation.UsageGroupConfig
 	validation.ProfileValidationLimits
+	forwarder.Overrides
 	aggregator.Limits
 	writepath.Overrides
 }
@@ -163,6 +191,9 @@ func New(
 		metrics:                 newMetrics(reg),
 		healthyInstancesCount:   atomic.NewUint32(0),
 		aggregator:              aggregator.NewMultiTenantAggregator[*pprof.ProfileMerge](limits, reg),
+		seriesLimiter:           newActiveSeriesLimiter(reg),
+		labelCardinalityLimiter: newLabelCardinalityLimiter(),
+		forwarder:               forwarder.New(config.Forwarding, limits, logger, reg),
 		limits:                  limits,
 		rfStats:                 usagestats.NewInt("distributor_replication_factor"),
 		bytesReceivedStats:      usagestats.NewStatistics("distributor_bytes_received"),
@@ -171,6 +202,13 @@ func New(
 		profileSizeStats:        usagestats.NewMultiStatistics("distributor_profile_sizes", "lang"),
 	}
 
+	if config.Symbolizer.Enabled {
+		if config.Bucket == nil {
+			return nil, errors.New("symbolizer requires the storage bucket")
+		}
+		d.symbolizer = symbolizer.New(config.Symbolizer, logger, reg, config.Bucket)
+	}
+
 	ingesterClient := writepath.IngesterFunc(d.sendRequestsToIngester)
 	d.router = writepath.NewRouter(
 		logger, reg, limits,
@@ -187,9 +225,25 @@ func New(
 		return nil, err
 	}
 
-	subservices = append(subservices, distributorsLifecycler, distributorsRing, d.aggregator)
-
-	d.ingestionRateLimiter = limiter.NewRateLimiter(newGlobalRateStrategy(newIngestionRateStrategy(limits), d), 10*time.Second)
+	subservices = append(subservices, distributorsLifecycler, distributorsRing, d.aggregator, d.seriesLimiter.service, d.labelCardinalityLimiter.service, d.forwarder.Service())
+
+	d.ingestionRateLimiter = newRateLimiter(newGlobalRateStrategy(newIngestionRateStrategy(limits), d), 10*time.Second)
+	d.profileTypeRateLimiter = newRateLimiter(newGlobalRateStrategy(newProfileTypeRateStrategy(limits), d), 10*time.Second)
+	if reg != nil {
+		reg.MustRegister(
+			newRateLimiterCollector(d.ingestionRateLimiter,
+				"ingestion_rate_limit", "ingestion rate limiter",
+				func(tenantID string) []string { return []string{tenantID} },
+				"tenant"),
+			newRateLimiterCollector(d.profileTypeRateLimiter,
+				"ingestion_profile_type_rate_limit", "profile type ingestion rate limiter",
+				func(key string) []string {
+					tenantID, profileType := splitProfileTypeRateLimiterKey(key)
+					return []string{tenantID, profileType}
+				},
+				"tenant", "type"),
+		)
+	}
 	d.distributorsLifecycler = distributorsLifecycler
 	d.distributorsRing = distributorsRing
 
@@ -206,6 +260,12 @@ func New(
 	return d, nil
 }
 
+// Symbolizer returns the symbolizer of the profiles of stripped
+// binaries, or nil if the symbolization is disabled.
+func (d *Distributor) Symbolizer() *symbolizer.Symbolizer {
+	return d.symbolizer
+}
+
 func (d *Distributor) starting(ctx context.Context) error {
 	return services.StartManagerAndAwaitHealthy(ctx, d.subservices)
 }
@@ -313,6 +373,15 @@ func (d *Distributor) PushParsed(ctx context.Context, req *distributormodel.Push
 		groups := usageGroups.GetUsageGroups(tenantID, phlaremodel.Labels(series.Labels))
 		profLanguage := d.GetProfileLanguage(series)
 
+		if err = d.validateLabels(tenantID, series.Labels); err != nil {
+			_ = level.Debug(d.logger).Log("msg", "invalid labels", "err", err)
+			reason := string(validation.ReasonOf(err))
+			validation.DiscardedProfiles.WithLabelValues(reason, tenantID).Add(float64(req.TotalProfiles))
+			validation.DiscardedBytes.WithLabelValues(reason, tenantID).Add(float64(req.TotalBytesUncompressed))
+			groups.CountDiscardedBytes(reason, req.TotalBytesUncompressed)
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+
 		for _, raw := range series.Samples {
 			usagestats.NewCounter(fmt.Sprintf("distributor_profile_type_%s_received", profName)).Inc(1)
 			d.profileReceivedStats.Inc(1, profLanguage)
@@ -347,11 +416,16 @@ func (d *Distributor) PushParsed(ctx context.Context, req *distributormodel.Push
 
 	// Normalisation is quite an expensive operation,
 	// therefore it should be done after the rate limit check.
+	maxStacktraceDepth := d.limits.MaxProfileStacktraceDepth(tenantID)
 	for _, series := range req.Series {
 		for _, sample := range series.Samples {
+			if d.symbolizer != nil {
+				d.symbolizer.SymbolizeProfile(ctx, tenantID, sample.Profile.Profile)
+			}
 			if series.Language == "go" {
 				sample.Profile.Profile = pprof.FixGoProfile(sample.Profile.Profile)
 			}
+			sample.Profile.TruncateStacktraces(maxStacktraceDepth)
 			sample.Profile.Normalize()
 		}
 	}
@@ -366,17 +440,35 @@ func (d *Distributor) PushParsed(ctx context.Context, req *distributormodel.Push
 		series.Labels = d.limitMaxSessionsPerSeries(maxSessionsPerSeries, series.Labels)
 	}
 
+	if err = d.limitLabelCardinality(req); err != nil {
+		return nil, err
+	}
+
+	if err = d.limitActiveSeries(req); err != nil {
+		return nil, err
+	}
+
+	// The profiles are only forwarded once they have been accepted,
+	// but the requests must be prepared before the profiles are
+	// aggregated or sent to the write path.
+	forwarded, err := d.forwarder.Prepare(req)
+	if err != nil {
+		_ = level.Warn(d.logger).Log("msg", "failed to prepare profiles for forwarding", "tenant", tenantID, "err", err)
+	}
+
 	aggregated, err := d.aggregate(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	if aggregated {
+		d.forwarder.Enqueue(forwarded)
 		return connect.NewResponse(&pushv1.PushResponse{}), nil
 	}
 
 	if err = d.router.Send(ctx, req); err != nil {
 		return nil, err
 	}
+	d.forwarder.Enqueue(forwarded)
 	return connect.NewResponse(&pushv1.PushResponse{}), nil
 }
 
@@ -449,12 +541,15 @@ func (d *Distributor) aggregate(ctx context.Context, req *distributormodel.PushR
 			if handleErr != nil {
 				return handleErr
 			}
+			merged := p.Profile()
 			aggregated := &distributormodel.PushRequest{
 				TenantID: req.TenantID,
 				Series: []*distributormodel.ProfileSeries{{
 					Labels:  labels,
-					Samples: []*distributormodel.ProfileSample{{Profile: pprof.RawFromProto(p.Profile())}},
+					Samples: []*distributormodel.ProfileSample{{Profile: pprof.RawFromProto(merged)}},
 				}},
+				TotalProfiles:          1,
+				TotalBytesUncompressed: int64(merged.SizeVT()),
 			}
 			return d.router.Send(localCtx, aggregated)
 		})()
@@ -708,6 +803,143 @@ func (d *Distributor) limitMaxSessionsPerSeries(maxSessionsPerSeries int, labels
 	return labels
 }
 
+// segmentWriterPath reports whether the tenant requests may be sent to
+// segment writers: in contrast to ingesters, segment writers expect the
+// requests to be validated by the distributor.
+func (d *Distributor) segmentWriterPath(tenantID string) bool {
+	switch d.limits.WritePathOverrides(tenantID).WritePath {
+	case writepath.SegmentWriterPath, writepath.CombinedPath, writepath.DualPath:
+		return true
+	}
+	return false
+}
+
+// validateLabels validates the series labels provided by the client. On the
+// old write path, the labels are validated in full after relabeling, before
+// they are sent to ingesters.
+func (d *Distributor) validateLabels(tenantID string, ls []*typesv1.LabelPair) error {
+	if err := validation.ValidateReservedLabelNames(d.limits.ReservedLabelNames(tenantID), ls); err != nil {
+		return err
+	}
+	if d.segmentWriterPath(tenantID) {
+		return validation.ValidateLabels(d.limits, tenantID, ls)
+	}
+	return nil
+}
+
+// limitActiveSeries enforces the active series limit on the new write path,
+// as segment writers do not limit series; ingesters enforce their own limits.
+// The series that exceed the limit are removed from the request, and the
+// request is only rejected if none of its series are allowed.
+func (d *Distributor) limitActiveSeries(req *distributormodel.PushRequest) error {
+	if !d.segmentWriterPath(req.TenantID) {
+		return nil
+	}
+	limit := d.limits.MaxActiveSeriesPerTenant(req.TenantID)
+	if limit <= 0 {
+		return nil
+	}
+	var active int
+	discarded := d.discardSeries(req, validation.SeriesLimit, func(series *distributormodel.ProfileSeries) bool {
+		var ok bool
+		ok, active = d.seriesLimiter.allow(req.TenantID, phlaremodel.Labels(series.Labels).Hash(), limit)
+		return !ok
+	})
+	if discarded == 0 {
+		return nil
+	}
+	err := validation.NewErrorf(validation.SeriesLimit, validation.SeriesLimitErrorMsg, active, limit)
+	if len(req.Series) > 0 {
+		_ = level.Debug(d.logger).Log("msg", "series discarded", "tenant", req.TenantID, "profiles", discarded, "err", err)
+		return nil
+	}
+	return connect.NewError(connect.CodeResourceExhausted, err)
+}
+
+// limitLabelCardinality limits the number of distinct values of labels:
+// series with new values of labels that reached the limit are discarded,
+// or the values are replaced with the placeholder, if it's configured.
+func (d *Distributor) limitLabelCardinality(req *distributormodel.PushRequest) error {
+	limit := d.limits.MaxLabelValueCardinality(req.TenantID)
+	if limit <= 0 {
+		return nil
+	}
+	placeholder := d.limits.LabelCardinalityPlaceholder(req.TenantID)
+	var limited string
+	discarded := d.discardSeries(req, validation.LabelCardinalityLimit, func(series *distributormodel.ProfileSeries) bool {
+		for i, l := range series.Labels {
+			if d.labelCardinalityLimiter.allow(req.TenantID, l.Name, l.Value, limit) {
+				continue
+			}
+			if placeholder == "" {
+				limited = l.Name
+				return true
+			}
+			// Label pairs may be shared by series.
+			series.Labels[i] = &typesv1.LabelPair{Name: l.Name, Value: placeholder}
+			d.metrics.rewrittenLabelValues.WithLabelValues(req.TenantID).Inc()
+		}
+		return false
+	})
+	if discarded == 0 {
+		return nil
+	}
+	err := validation.NewErrorf(validation.LabelCardinalityLimit, validation.LabelCardinalityLimitErrorMsg, limited, limit)
+	if len(req.Series) > 0 {
+		_ = level.Debug(d.logger).Log("msg", "series discarded", "tenant", req.TenantID, "profiles", discarded, "err", err)
+		return nil
+	}
+	return connect.NewError(connect.CodeResourceExhausted, err)
+}
+
+// discardSeries removes the series from the request, if the discard function
+// returns true, and accounts them as discarded for the given reason. The
+// function returns the number of discarded profiles.
+func (d *Distributor) discardSeries(
+	req *distributormodel.PushRequest,
+	reason validation.Reason,
+	discard func(*distributormodel.ProfileSeries) bool,
+) (discardedProfiles int64) {
+	var discardedBytes int64
+	usageGroups := d.limits.DistributorUsageGroups(req.TenantID)
+	kept := req.Series[:0]
+	for _, series := range req.Series {
+		if !discard(series) {
+			kept = append(kept, series)
+			continue
+		}
+		size := seriesSizeBytes(series)
+		profiles := int64(len(series.Samples))
+		profName := phlaremodel.Labels(series.Labels).Get(ProfileName)
+		d.metrics.discardedBytesByType.WithLabelValues(string(reason), profName, req.TenantID).Add(float64(size))
+		d.metrics.discardedProfilesByType.WithLabelValues(string(reason), profName, req.TenantID).Add(float64(profiles))
+		usageGroups.GetUsageGroups(req.TenantID, series.Labels).CountDiscardedBytes(string(reason), size)
+		discardedBytes += size
+		discardedProfiles += profiles
+	}
+	if len(kept) == len(req.Series) {
+		return 0
+	}
+	clear(req.Series[len(kept):])
+	req.Series = kept
+	req.TotalProfiles -= discardedProfiles
+	req.TotalBytesUncompressed -= discardedBytes
+	validation.DiscardedProfiles.WithLabelValues(string(reason), req.TenantID).Add(float64(discardedProfiles))
+	validation.DiscardedBytes.WithLabelValues(string(reason), req.TenantID).Add(float64(discardedBytes))
+	return discardedProfiles
+}
+
+// seriesSizeBytes returns the size of the series profiles, including the labels.
+func seriesSizeBytes(series *distributormodel.ProfileSeries) (size int64) {
+	for _, lbs := range series.Labels {
+		size += int64(len(lbs.Name) + len(lbs.Value))
+	}
+	for _, raw := range series.Samples {
+		size += int64(raw.Profile.SizeVT())
+	}
+	return size
+}
+
 func (d *Distributor) rateLimit(tenantID string, req *distributormodel.PushRequest) error {
 	for _, series := range req.Series {
 		// include the labels in the size calculation
@@ -721,14 +953,85 @@ func (d *Distributor) rateLimit(tenantID string, req *distributormodel.PushReque
 		}
 	}
 	// rate limit the request
-	if !d.ingestionRateLimiter.AllowN(time.Now(), tenantID, int(req.TotalBytesUncompressed)) {
+	now := time.Now()
+	if !d.ingestionRateLimiter.AllowN(now, tenantID, int(req.TotalBytesUncompressed)) {
 		validation.DiscardedProfiles.WithLabelValues(string(validation.RateLimited), tenantID).Add(float64(req.TotalProfiles))
 		validation.DiscardedBytes.WithLabelValues(string(validation.RateLimited), tenantID).Add(float64(req.TotalBytesUncompressed))
+		d.cfg.Notifier.Notify(webhooks.Event{
+			Type:   webhooks.EventQuotaExceeded,
+			Tenant: tenantID,
+			Attributes: map[string]string{
+				"reason": string(validation.RateLimited),
+				"limit":  humanize.IBytes(uint64(d.limits.IngestionRateBytes(tenantID))),
+			},
+		})
+		if burst := d.ingestionRateLimiter.Burst(now, tenantID); req.TotalBytesUncompressed > int64(burst) {
+			// The request can't be accepted regardless of the rate:
+			// the burst size must accommodate the largest request.
+			return connect.NewError(connect.CodeResourceExhausted,
+				fmt.Errorf("push request size %s exceeds the ingestion burst size (%s), which must be at least the size of the largest request (limit: ingestion_burst_size_mb)", humanize.IBytes(uint64(req.TotalBytesUncompressed)), humanize.IBytes(uint64(burst))),
+			)
+		}
 		return connect.NewError(connect.CodeResourceExhausted,
 			fmt.Errorf("push rate limit (%s) exceeded while adding %s", humanize.IBytes(uint64(d.limits.IngestionRateBytes(tenantID))), humanize.IBytes(uint64(req.TotalBytesUncompressed))),
 		)
 	}
-	return nil
+	return d.rateLimitProfileTypes(tenantID, req)
+}
+
+// rateLimitProfileTypes enforces the rate limits of individual profile types.
+// Series of the profile types that exceed the limit are removed from the
+// request, and the request is only rejected if none of its series are left.
+func (d *Distributor) rateLimitProfileTypes(tenantID string, req *distributormodel.PushRequest) error {
+	limits := d.limits.IngestionProfileTypeRateLimits(tenantID)
+	if len(limits) == 0 {
+		return nil
+	}
+	sizes := make(map[string]int64)
+	for _, series := range req.Series {
+		profName := phlaremodel.Labels(series.Labels).Get(ProfileName)
+		if _, ok := limits[profName]; ok {
+			sizes[profName] += seriesSizeBytes(series)
+		}
+	}
+	profileTypes := make([]string, 0, len(sizes))
+	for profName := range sizes {
+		profileTypes = append(profileTypes, profName)
+	}
+	sort.Strings(profileTypes)
+	now := time.Now()
+	var err error
+	limited := make(map[string]struct{})
+	for _, profName := range profileTypes {
+		size := sizes[profName]
+		if d.profileTypeRateLimiter.AllowN(now, profileTypeRateLimiterKey(tenantID, profName), int(size)) {
+			continue
+		}
+		limited[profName] = struct{}{}
+		limit := humanize.IBytes(uint64(limits[profName].IngestionRateBytes()))
+		d.cfg.Notifier.Notify(webhooks.Event{
+			Type:   webhooks.EventQuotaExceeded,
+			Tenant: tenantID,
+			Attributes: map[string]string{
+				"reason":       string(validation.ProfileTypeRateLimited),
+				"limit":        limit,
+				"profile_type": profName,
+			},
+		})
+		err = validation.NewErrorf(validation.ProfileTypeRateLimited, validation.ProfileTypeRateLimitedErrorMsg, limit, profName, humanize.IBytes(uint64(size)))
+	}
+	if len(limited) == 0 {
+		return nil
+	}
+	discarded := d.discardSeries(req, validation.ProfileTypeRateLimited, func(series *distributormodel.ProfileSeries) bool {
+		_, ok := limited[phlaremodel.Labels(series.Labels).Get(ProfileName)]
+		return ok
+	})
+	if len(req.Series) > 0 {
+		_ = level.Debug(d.logger).Log("msg", "series discarded", "tenant", tenantID, "profiles", discarded, "err", err)
+		return nil
+	}
+	return connect.NewError(connect.CodeResourceExhausted, err)
 }
 
 type profileTracker struct {
@@ -30,7 +30,9 @@ import (
 	testhelper2 "github.com/grafana/pyroscope/pkg/pprof/testhelper"
 
 	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	"github.com/grafana/pyroscope/pkg/distributor/forwarder"
 	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	writepath "github.com/grafana/pyroscope/pkg/distributor/write_path"
 	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	pprof2 "github.com/grafana/pyroscope/pkg/pprof"
 	"github.com/grafana/pyroscope/pkg/util"
@@ -301,6 +303,55 @@ func Test_Limits(t *testing.T) {
 			expectedCode:             connect.CodeInvalidArgument,
 			expectedValidationReason: validation.LabelNameTooLong,
 		},
+		{
+			description: "reserved_label_name",
+			pushReq: &pushv1.PushRequest{
+				Series: []*pushv1.RawProfileSeries{
+					{
+						Labels: []*typesv1.LabelPair{
+							{Name: phlaremodel.LabelNameProfileType, Value: "cpu"},
+							{Name: "__name__", Value: "cpu"},
+							{Name: phlaremodel.LabelNameServiceName, Value: "svc"},
+						},
+						Samples: []*pushv1.RawSample{
+							{
+								RawProfile: collectTestProfileBytes(t),
+							},
+						},
+					},
+				},
+			},
+			overrides:                validation.MockDefaultOverrides(),
+			expectedCode:             connect.CodeInvalidArgument,
+			expectedValidationReason: validation.ReservedLabelName,
+		},
+		{
+			description: "labels_limit_segment_writer",
+			pushReq: &pushv1.PushRequest{
+				Series: []*pushv1.RawProfileSeries{
+					{
+						Labels: []*typesv1.LabelPair{
+							{Name: "cluster", Value: "us-central1"},
+							{Name: "__name__", Value: "cpu"},
+							{Name: phlaremodel.LabelNameServiceName, Value: "svc"},
+						},
+						Samples: []*pushv1.RawSample{
+							{
+								RawProfile: collectTestProfileBytes(t),
+							},
+						},
+					},
+				},
+			},
+			overrides: validation.MockOverrides(func(defaults *validation.Limits, tenantLimits map[string]*validation.Limits) {
+				l := validation.MockDefaultLimits()
+				l.MaxLabelNamesPerSeries = 2
+				l.WritePathOverrides.WritePath = writepath.SegmentWriterPath
+				tenantLimits["user-1"] = l
+			}),
+			expectedCode:             connect.CodeInvalidArgument,
+			expectedValidationReason: validation.MaxLabelNamesPerSeries,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -338,6 +389,50 @@ func Test_Limits(t *testing.T) {
 	}
 }
 
+func Test_ProfileTypeRateLimit(t *testing.T) {
+	d, err := New(Config{DistributorRing: ringConfig},
+		testhelper.NewMockRing([]ring.InstanceDesc{{Addr: "foo"}}, 3),
+		&poolFactory{f: func(addr string) (client.PoolClient, error) { return newFakeIngester(t, false), nil }},
+		validation.MockOverrides(func(defaults *validation.Limits, tenantLimits map[string]*validation.Limits) {
+			l := validation.MockDefaultLimits()
+			l.IngestionProfileTypeRateLimits = validation.ProfileTypeRateLimits{
+				"memory": {IngestionRateMB: 0.0001, IngestionBurstSizeMB: 0.0001},
+			}
+			tenantLimits["user-1"] = l
+		}),
+		nil, log.NewLogfmtLogger(os.Stdout), nil)
+	require.NoError(t, err)
+	ctx := tenant.InjectTenantID(context.Background(), "user-1")
+
+	series := func(name string) *distributormodel.ProfileSeries {
+		return &distributormodel.ProfileSeries{
+			Labels: []*typesv1.LabelPair{
+				{Name: "__name__", Value: name},
+				{Name: phlaremodel.LabelNameServiceName, Value: "svc"},
+			},
+			Samples: []*distributormodel.ProfileSample{{
+				Profile: &pprof2.Profile{Profile: testProfile(0)},
+			}},
+		}
+	}
+
+	discarded := d.metrics.discardedProfilesByType.WithLabelValues(string(validation.ProfileTypeRateLimited), "memory", "user-1")
+	// The memory series is discarded, the cpu one is accepted.
+	req := &distributormodel.PushRequest{Series: []*distributormodel.ProfileSeries{series("process_cpu"), series("memory")}}
+	_, err = d.PushParsed(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, req.Series, 1)
+	assert.Equal(t, "process_cpu", phlaremodel.Labels(req.Series[0].Labels).Get(ProfileName))
+	assert.Equal(t, float64(1), testutil.ToFloat64(discarded))
+
+	// The request is rejected if all the series are discarded.
+	_, err = d.PushParsed(ctx, &distributormodel.PushRequest{Series: []*distributormodel.ProfileSeries{series("memory")}})
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	assert.Equal(t, validation.ProfileTypeRateLimited, validation.ReasonOf(err))
+	assert.Equal(t, float64(2), testutil.ToFloat64(discarded))
+}
+
 func Test_Sessions_Limit(t *testing.T) {
 	type testCase struct {
 		description    string
@@ -1273,6 +1368,63 @@ func TestPush_Aggregation(t *testing.T) {
 	assert.Equal(t, len(sessions), maxSessions)
 }
 
+func TestPush_Forwarding(t *testing.T) {
+	downstream := newFakeIngester(t, false)
+	mux := http.NewServeMux()
+	mux.Handle(pushv1connect.NewPusherServiceHandler(downstream, handlerOptions...))
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	ingesterClient := newFakeIngester(t, false)
+	d, err := New(
+		Config{
+			PoolConfig:      clientpool.PoolConfig{ClientCleanupPeriod: time.Second},
+			DistributorRing: ringConfig,
+			PushTimeout:     time.Second * 10,
+			Forwarding:      forwarder.Config{QueueSize: 10, Concurrency: 1, Timeout: time.Second * 10},
+		},
+		testhelper.NewMockRing([]ring.InstanceDesc{{Addr: "foo"}}, 3),
+		&poolFactory{f: func(addr string) (client.PoolClient, error) { return ingesterClient, nil }},
+		validation.MockOverrides(func(defaults *validation.Limits, tenantLimits map[string]*validation.Limits) {
+			l := validation.MockDefaultLimits()
+			l.MaxLabelValueLength = 16
+			l.ForwardingTargets = []forwarder.Target{{Name: "downstream", URL: s.URL, TenantID: "downstream"}}
+			tenantLimits["user-1"] = l
+		}),
+		nil, log.NewLogfmtLogger(os.Stdout), nil,
+	)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), d))
+	ctx := tenant.InjectTenantID(context.Background(), "user-1")
+
+	push := func(cluster string) error {
+		_, err := d.PushParsed(ctx, &distributormodel.PushRequest{
+			Series: []*distributormodel.ProfileSeries{{
+				Labels: []*typesv1.LabelPair{
+					{Name: "cluster", Value: cluster},
+					{Name: "__name__", Value: "cpu"},
+				},
+				Samples: []*distributormodel.ProfileSample{{
+					Profile: &pprof2.Profile{Profile: testProfile(0)},
+				}},
+			}},
+		})
+		return err
+	}
+	require.NoError(t, push("us-central1"))
+	// Rejected profiles are not forwarded.
+	require.Error(t, push("a-very-long-cluster-name"))
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), d))
+
+	require.Len(t, ingesterClient.requests, 1)
+	require.Len(t, downstream.requests, 1)
+	require.Len(t, downstream.requests[0].Series, 1)
+	assert.Equal(t,
+		`{__name__="cpu", cluster="us-central1", service_name="unspecified"}`,
+		phlaremodel.LabelPairsString(downstream.requests[0].Series[0].Labels),
+	)
+}
+
 func testProfile(t int64) *profilev1.Profile {
 	return &profilev1.Profile{
 		SampleType: []*profilev1.ValueType{
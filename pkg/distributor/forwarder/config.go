@@ -0,0 +1,110 @@
+package forwarder
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/prometheus/model/relabel"
+)
+
+// Config configures how the profiles are forwarded. The targets
+// themselves are configured per tenant, in the limits overrides.
+type Config struct {
+	QueueSize   int           `yaml:"queue_size" category:"experimental"`
+	Concurrency int           `yaml:"concurrency" category:"experimental"`
+	Timeout     time.Duration `yaml:"timeout" category:"experimental"`
+	MaxRetries  int           `yaml:"max_retries" category:"experimental"`
+	MinBackoff  time.Duration `yaml:"min_backoff" category:"experimental"`
+	MaxBackoff  time.Duration `yaml:"max_backoff" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.IntVar(&cfg.QueueSize, prefix+"queue-size", 1000, "Maximum number of push requests queued per tenant forwarding target. Requests exceeding the limit are dropped.")
+	f.IntVar(&cfg.Concurrency, prefix+"concurrency", 4, "Maximum number of concurrent push requests per tenant forwarding target.")
+	f.DurationVar(&cfg.Timeout, prefix+"timeout", 10*time.Second, "Timeout of a push request to a forwarding target.")
+	f.IntVar(&cfg.MaxRetries, prefix+"max-retries", 5, "Maximum number of times a failed push request is retried. Requests rejected by the target as invalid or unauthorized are not retried. 0 to disable retries.")
+	f.DurationVar(&cfg.MinBackoff, prefix+"min-backoff", 100*time.Millisecond, "Minimum delay before a failed push request is retried.")
+	f.DurationVar(&cfg.MaxBackoff, prefix+"max-backoff", 10*time.Second, "Maximum delay before a failed push request is retried.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.QueueSize < 1 {
+		return errors.New("forwarding queue size must be positive")
+	}
+	if cfg.Concurrency < 1 {
+		return errors.New("forwarding concurrency must be positive")
+	}
+	if cfg.MinBackoff > cfg.MaxBackoff {
+		return errors.New("forwarding min backoff must not exceed max backoff")
+	}
+	return nil
+}
+
+// Target is a Pyroscope instance the profiles of the tenant are
+// re-pushed to, once they have been accepted by the distributor.
+type Target struct {
+	// Name of the target, used in logs and metrics.
+	Name string `yaml:"name" json:"name"`
+	// Base URL of the Pyroscope instance, e.g., https://pyroscope.example.com.
+	URL string `yaml:"url" json:"url"`
+	// Tenant the profiles are pushed to in the X-Scope-OrgID header.
+	// If empty, the tenant the profiles have been pushed to is used.
+	TenantID          string `yaml:"tenant_id" json:"tenant_id"`
+	BasicAuthUsername string `yaml:"basic_auth_username" json:"basic_auth_username"`
+	BasicAuthPassword string `yaml:"basic_auth_password" json:"basic_auth_password"`
+	// Takes precedence over the basic authentication.
+	BearerToken string `yaml:"bearer_token" json:"bearer_token"`
+	// Applied to the series labels before the profiles are forwarded.
+	// Series dropped by the rules are not forwarded.
+	RelabelConfigs []*relabel.Config `yaml:"relabel_configs" json:"relabel_configs"`
+}
+
+type Targets []Target
+
+// ExampleDoc provides an example doc for this config, as the targets
+// are only configured in the overrides.
+func (Targets) ExampleDoc() (comment string, yaml interface{}) {
+	return `This example forwards the profiles to two targets: an aggregation cluster, and a cloud instance, with the 'cluster' label added to all profile series.`,
+		[]map[string]interface{}{
+			{"name": "aggregation", "url": "http://pyroscope-aggregation:4040", "tenant_id": "cluster-a"},
+			{
+				"name":                "cloud",
+				"url":                 "https://profiles.example.com",
+				"basic_auth_username": "123456",
+				"basic_auth_password": "<token>",
+				"relabel_configs": []map[string]interface{}{
+					{"action": "replace", "replacement": "cluster-a", "target_label": "cluster"},
+				},
+			},
+		}
+}
+
+// ValidateTargets validates the targets of a tenant.
+func ValidateTargets(targets Targets) error {
+	names := make(map[string]struct{}, len(targets))
+	for _, t := range targets {
+		if t.Name == "" {
+			return errors.New("forwarding target name is required")
+		}
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("duplicate forwarding target %q", t.Name)
+		}
+		names[t.Name] = struct{}{}
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			return fmt.Errorf("invalid URL of forwarding target %q: %w", t.Name, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid URL of forwarding target %q: absolute http(s) URL expected", t.Name)
+		}
+		for i, rule := range t.RelabelConfigs {
+			if err = rule.Validate(); err != nil {
+				return fmt.Errorf("invalid relabeling rule at pos %d of forwarding target %q: %w", i, t.Name, err)
+			}
+		}
+	}
+	return nil
+}
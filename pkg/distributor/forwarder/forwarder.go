@@ -0,0 +1,335 @@
+// Package forwarder implements forwarding of the profiles accepted by the
+// distributor to other Pyroscope instances: for example, to aggregate the
+// profiles of several clusters, or to write them both on-premises and to
+// a cloud instance.
+//
+// The profiles are forwarded asynchronously, on a best-effort basis:
+// failure to forward a profile does not affect the push request, and
+// requests exceeding the queue capacity are dropped.
+package forwarder
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	connectapi "github.com/grafana/pyroscope/pkg/api/connect"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/model/relabel"
+)
+
+const (
+	// Queues that have not been used for the period are removed,
+	// e.g., if the target has been removed from the overrides.
+	queueIdleTimeout = 10 * time.Minute
+	cleanupInterval  = time.Minute
+)
+
+type Overrides interface {
+	ForwardingTargets(tenantID string) []Target
+}
+
+type PushClient interface {
+	Push(context.Context, *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error)
+}
+
+type Forwarder struct {
+	service services.Service
+
+	config    Config
+	logger    log.Logger
+	overrides Overrides
+	metrics   *metrics
+	newClient func(url string) PushClient
+
+	// Cancelled on shutdown: the requests
+	// queued are sent without retries.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.RWMutex
+	stopped bool
+	queues  map[queueKey]*queue
+	workers sync.WaitGroup
+}
+
+func New(config Config, overrides Overrides, logger log.Logger, reg prometheus.Registerer) *Forwarder {
+	// The targets are external to the cluster: unlike the clients of the
+	// internal services, the client is not limited to h2c connections.
+	opts := append(connectapi.DefaultClientOptions(), connect.WithSendGzip())
+	return newForwarder(config, overrides, logger, reg, func(url string) PushClient {
+		return pushv1connect.NewPusherServiceClient(http.DefaultClient, url, opts...)
+	})
+}
+
+func newForwarder(
+	config Config,
+	overrides Overrides,
+	logger log.Logger,
+	reg prometheus.Registerer,
+	newClient func(url string) PushClient,
+) *Forwarder {
+	f := &Forwarder{
+		config:    config,
+		logger:    logger,
+		overrides: overrides,
+		metrics:   newMetrics(reg),
+		newClient: newClient,
+		queues:    make(map[queueKey]*queue),
+	}
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	f.service = services.NewBasicService(nil, f.running, f.stopping)
+	return f
+}
+
+func (f *Forwarder) Service() services.Service { return f.service }
+
+func (f *Forwarder) running(ctx context.Context) error {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			f.removeIdleQueues(time.Now().Add(-queueIdleTimeout))
+		}
+	}
+}
+
+func (f *Forwarder) stopping(_ error) error {
+	f.mu.Lock()
+	f.stopped = true
+	for k, q := range f.queues {
+		close(q.requests)
+		delete(f.queues, k)
+	}
+	f.mu.Unlock()
+	f.cancel()
+	f.workers.Wait()
+	return nil
+}
+
+// Request is a push request to a forwarding target.
+type Request struct {
+	tenantID string
+	target   Target
+	profiles int
+	request  *pushv1.PushRequest
+}
+
+// Prepare returns the requests forwarding the profiles to the targets
+// configured for the tenant. The requests must be prepared before the
+// profiles are sent to the write path, which may modify them in place.
+func (f *Forwarder) Prepare(req *distributormodel.PushRequest) ([]*Request, error) {
+	targets := f.overrides.ForwardingTargets(req.TenantID)
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	// The profiles are serialized once, and shared by the targets.
+	samples := make([][]*pushv1.RawSample, len(req.Series))
+	for i, series := range req.Series {
+		samples[i] = make([]*pushv1.RawSample, 0, len(series.Samples))
+		for _, s := range series.Samples {
+			b, err := s.Profile.MarshalVT()
+			if err != nil {
+				return nil, err
+			}
+			samples[i] = append(samples[i], &pushv1.RawSample{ID: s.ID, RawProfile: b})
+		}
+	}
+	requests := make([]*Request, 0, len(targets))
+	for _, target := range targets {
+		r := &Request{
+			tenantID: req.TenantID,
+			target:   target,
+			request:  &pushv1.PushRequest{Series: make([]*pushv1.RawProfileSeries, 0, len(req.Series))},
+		}
+		for i, series := range req.Series {
+			labels := phlaremodel.Labels(series.Labels)
+			if len(target.RelabelConfigs) > 0 {
+				var keep bool
+				if labels, keep = relabel.Process(labels.Clone(), target.RelabelConfigs...); !keep {
+					continue
+				}
+				sort.Sort(labels)
+			}
+			r.request.Series = append(r.request.Series, &pushv1.RawProfileSeries{
+				Labels:  labels,
+				Samples: samples[i],
+			})
+			r.profiles += len(samples[i])
+		}
+		if r.profiles > 0 {
+			requests = append(requests, r)
+		}
+	}
+	return requests, nil
+}
+
+// Enqueue queues the requests to be sent asynchronously.
+// Requests exceeding the queue capacity are dropped.
+func (f *Forwarder) Enqueue(requests []*Request) {
+	for _, r := range requests {
+		f.enqueue(r)
+	}
+}
+
+func (f *Forwarder) enqueue(r *Request) {
+	key := queueKey{tenantID: r.tenantID, target: r.target.Name, url: r.target.URL}
+	f.mu.RLock()
+	q, ok := f.queues[key]
+	if !ok {
+		f.mu.RUnlock()
+		if q = f.createQueue(key); q == nil {
+			f.metrics.profiles.WithLabelValues(r.tenantID, r.target.Name, resultDropped).Add(float64(r.profiles))
+			return
+		}
+		f.mu.RLock()
+	}
+	defer f.mu.RUnlock()
+	if f.stopped || q.closed {
+		f.metrics.profiles.WithLabelValues(r.tenantID, r.target.Name, resultDropped).Add(float64(r.profiles))
+		return
+	}
+	q.lastUsed.Store(time.Now().UnixNano())
+	select {
+	case q.requests <- r:
+		f.metrics.queueLength.WithLabelValues(r.tenantID, r.target.Name).Inc()
+	default:
+		level.Warn(f.logger).Log("msg", "forwarding queue is full, dropping profiles", "tenant", r.tenantID, "target", r.target.Name)
+		f.metrics.profiles.WithLabelValues(r.tenantID, r.target.Name, resultDropped).Add(float64(r.profiles))
+	}
+}
+
+func (f *Forwarder) createQueue(key queueKey) *queue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopped {
+		return nil
+	}
+	if q, ok := f.queues[key]; ok {
+		return q
+	}
+	q := &queue{
+		client:   f.newClient(key.url),
+		requests: make(chan *Request, f.config.QueueSize),
+	}
+	q.lastUsed.Store(time.Now().UnixNano())
+	f.queues[key] = q
+	for i := 0; i < f.config.Concurrency; i++ {
+		f.workers.Add(1)
+		go func() {
+			defer f.workers.Done()
+			for r := range q.requests {
+				f.metrics.queueLength.WithLabelValues(r.tenantID, r.target.Name).Dec()
+				f.send(q.client, r)
+			}
+		}()
+	}
+	return q
+}
+
+func (f *Forwarder) removeIdleQueues(before time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for k, q := range f.queues {
+		if q.lastUsed.Load() < before.UnixNano() && len(q.requests) == 0 {
+			q.closed = true
+			close(q.requests)
+			delete(f.queues, k)
+			f.metrics.queueLength.DeleteLabelValues(k.tenantID, k.target)
+		}
+	}
+}
+
+func (f *Forwarder) send(client PushClient, r *Request) {
+	// The first attempt is not counted as a retry.
+	b := backoff.New(f.ctx, backoff.Config{
+		MinBackoff: f.config.MinBackoff,
+		MaxBackoff: f.config.MaxBackoff,
+		MaxRetries: f.config.MaxRetries + 1,
+	})
+	for {
+		err := f.push(client, r)
+		if err == nil {
+			f.metrics.profiles.WithLabelValues(r.tenantID, r.target.Name, resultSuccess).Add(float64(r.profiles))
+			return
+		}
+		if isRetryable(err) {
+			if b.Wait(); b.Ongoing() {
+				f.metrics.retries.WithLabelValues(r.tenantID, r.target.Name).Inc()
+				continue
+			}
+		}
+		level.Warn(f.logger).Log("msg", "failed to forward profiles", "tenant", r.tenantID, "target", r.target.Name, "err", err)
+		f.metrics.profiles.WithLabelValues(r.tenantID, r.target.Name, resultFailed).Add(float64(r.profiles))
+		return
+	}
+}
+
+func (f *Forwarder) push(client PushClient, r *Request) error {
+	ctx, cancel := context.WithTimeout(context.Background(), f.config.Timeout)
+	defer cancel()
+	req := connect.NewRequest(r.request)
+	setHeaders(req.Header(), r.tenantID, r.target)
+	_, err := client.Push(ctx, req)
+	return err
+}
+
+func setHeaders(h http.Header, tenantID string, t Target) {
+	if t.TenantID != "" {
+		tenantID = t.TenantID
+	}
+	h.Set("X-Scope-OrgID", tenantID)
+	switch {
+	case t.BearerToken != "":
+		h.Set("Authorization", "Bearer "+t.BearerToken)
+	case t.BasicAuthUsername != "" || t.BasicAuthPassword != "":
+		auth := t.BasicAuthUsername + ":" + t.BasicAuthPassword
+		h.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+	}
+}
+
+// isRetryable reports whether the request may succeed if retried:
+// requests rejected by the target because of the request itself
+// or the credentials are not retried.
+func isRetryable(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeInvalidArgument,
+		connect.CodeUnauthenticated,
+		connect.CodePermissionDenied,
+		connect.CodeNotFound,
+		connect.CodeUnimplemented,
+		connect.CodeFailedPrecondition:
+		return false
+	}
+	return true
+}
+
+type queueKey struct {
+	tenantID string
+	target   string
+	url      string
+}
+
+type queue struct {
+	client   PushClient
+	requests chan *Request
+	lastUsed atomic.Int64
+	closed   bool
+}
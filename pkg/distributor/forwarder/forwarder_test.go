@@ -0,0 +1,274 @@
+package forwarder
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/pprof"
+	"github.com/grafana/pyroscope/pkg/pprof/testhelper"
+)
+
+type mockOverrides map[string][]Target
+
+func (m mockOverrides) ForwardingTargets(tenantID string) []Target { return m[tenantID] }
+
+type pushed struct {
+	url     string
+	header  map[string]string
+	request *pushv1.PushRequest
+}
+
+type mockClients struct {
+	mu       sync.Mutex
+	pushed   []pushed
+	attempts int
+	// Results of the consecutive attempts: nil means success.
+	errs []error
+	// Blocks the requests until closed.
+	wait chan struct{}
+}
+
+func (m *mockClients) client(url string) PushClient {
+	return pushFunc(func(ctx context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+		if m.wait != nil {
+			<-m.wait
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.attempts++
+		if len(m.errs) > 0 {
+			err := m.errs[0]
+			if m.errs = m.errs[1:]; err != nil {
+				return nil, err
+			}
+		}
+		m.pushed = append(m.pushed, pushed{
+			url: url,
+			header: map[string]string{
+				"X-Scope-OrgID": req.Header().Get("X-Scope-OrgID"),
+				"Authorization": req.Header().Get("Authorization"),
+			},
+			request: req.Msg,
+		})
+		return connect.NewResponse(new(pushv1.PushResponse)), nil
+	})
+}
+
+func (m *mockClients) requests() []pushed {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]pushed(nil), m.pushed...)
+}
+
+type pushFunc func(context.Context, *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error)
+
+func (f pushFunc) Push(ctx context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+	return f(ctx, req)
+}
+
+var testConfig = Config{
+	QueueSize:   10,
+	Concurrency: 1,
+	Timeout:     time.Second,
+	MaxRetries:  3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  time.Millisecond,
+}
+
+func newTestForwarder(t *testing.T, config Config, overrides Overrides, clients *mockClients) *Forwarder {
+	t.Helper()
+	f := newForwarder(config, overrides, log.NewNopLogger(), prometheus.NewRegistry(), clients.client)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), f.Service()))
+	return f
+}
+
+func newPushRequest(tenantID string, series ...[]*typesv1.LabelPair) *distributormodel.PushRequest {
+	req := &distributormodel.PushRequest{TenantID: tenantID}
+	for _, labels := range series {
+		p := testhelper.NewProfileBuilder(1).CPUProfile().ForStacktraceString("foo", "bar").AddSamples(1)
+		req.Series = append(req.Series, &distributormodel.ProfileSeries{
+			Labels:  labels,
+			Samples: []*distributormodel.ProfileSample{{Profile: pprof.RawFromProto(p.Profile), ID: "id"}},
+		})
+	}
+	return req
+}
+
+func Test_Forwarder(t *testing.T) {
+	overrides := mockOverrides{
+		"tenant-a": {
+			{
+				Name:              "cloud",
+				URL:               "https://cloud.example.com",
+				TenantID:          "downstream",
+				BasicAuthUsername: "user",
+				BasicAuthPassword: "password",
+				RelabelConfigs: []*relabel.Config{
+					{Action: relabel.Drop, SourceLabels: []model.LabelName{"env"}, Regex: relabel.MustNewRegexp("dev")},
+					{Action: relabel.Replace, TargetLabel: "cluster", Replacement: "eu-west", Regex: relabel.MustNewRegexp("(.*)")},
+				},
+			},
+			{
+				Name:        "aggregation",
+				URL:         "http://aggregation:4040",
+				BearerToken: "token",
+			},
+		},
+	}
+	clients := new(mockClients)
+	f := newTestForwarder(t, testConfig, overrides, clients)
+
+	req := newPushRequest("tenant-a",
+		phlaremodel.LabelsFromStrings("env", "prod", "service_name", "svc"),
+		phlaremodel.LabelsFromStrings("env", "dev", "service_name", "svc"),
+	)
+	requests, err := f.Prepare(req)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	f.Enqueue(requests)
+
+	// Requests of another tenant are not forwarded.
+	requests, err = f.Prepare(newPushRequest("tenant-b", phlaremodel.LabelsFromStrings("service_name", "svc")))
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), f.Service()))
+	requested := clients.requests()
+	require.Len(t, requested, 2)
+	byURL := make(map[string]pushed)
+	for _, p := range requested {
+		byURL[p.url] = p
+	}
+
+	cloud := byURL["https://cloud.example.com"]
+	assert.Equal(t, "downstream", cloud.header["X-Scope-OrgID"])
+	assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("user:password")), cloud.header["Authorization"])
+	require.Len(t, cloud.request.Series, 1)
+	assert.Equal(t, `{cluster="eu-west", env="prod", service_name="svc"}`, phlaremodel.LabelPairsString(cloud.request.Series[0].Labels))
+	require.Len(t, cloud.request.Series[0].Samples, 1)
+	p, err := pprof.RawFromBytes(cloud.request.Series[0].Samples[0].RawProfile)
+	require.NoError(t, err)
+	assert.Len(t, p.Sample, 1)
+
+	aggregation := byURL["http://aggregation:4040"]
+	assert.Equal(t, "tenant-a", aggregation.header["X-Scope-OrgID"])
+	assert.Equal(t, "Bearer token", aggregation.header["Authorization"])
+	assert.Len(t, aggregation.request.Series, 2)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(f.metrics.profiles.WithLabelValues("tenant-a", "cloud", resultSuccess)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(f.metrics.profiles.WithLabelValues("tenant-a", "aggregation", resultSuccess)))
+}
+
+func Test_Forwarder_Retries(t *testing.T) {
+	overrides := mockOverrides{"tenant": {{Name: "target", URL: "http://target"}}}
+	clients := &mockClients{errs: []error{
+		connect.NewError(connect.CodeUnavailable, errors.New("unavailable")),
+		connect.NewError(connect.CodeResourceExhausted, errors.New("rate limited")),
+		nil,
+		// Invalid requests are not retried.
+		connect.NewError(connect.CodeInvalidArgument, errors.New("invalid")),
+	}}
+	f := newTestForwarder(t, testConfig, overrides, clients)
+
+	forward := func() {
+		requests, err := f.Prepare(newPushRequest("tenant", phlaremodel.LabelsFromStrings("service_name", "svc")))
+		require.NoError(t, err)
+		f.Enqueue(requests)
+	}
+	forward()
+	require.Eventually(t, func() bool { return len(clients.requests()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	forward()
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), f.Service()))
+
+	assert.Equal(t, 4, clients.attempts)
+	assert.Equal(t, float64(1), testutil.ToFloat64(f.metrics.profiles.WithLabelValues("tenant", "target", resultSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(f.metrics.profiles.WithLabelValues("tenant", "target", resultFailed)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(f.metrics.retries.WithLabelValues("tenant", "target")))
+}
+
+func Test_Forwarder_QueueFull(t *testing.T) {
+	overrides := mockOverrides{"tenant": {{Name: "target", URL: "http://target"}}}
+	clients := &mockClients{wait: make(chan struct{})}
+	config := testConfig
+	config.QueueSize = 1
+	f := newTestForwarder(t, config, overrides, clients)
+
+	// The first request is handled by the worker,
+	// the second one is queued, and the rest are dropped.
+	for i := 0; i < 4; i++ {
+		requests, err := f.Prepare(newPushRequest("tenant", phlaremodel.LabelsFromStrings("service_name", "svc")))
+		require.NoError(t, err)
+		f.Enqueue(requests)
+		if i == 0 {
+			require.Eventually(t, func() bool { return len(f.queues[queueKey{"tenant", "target", "http://target"}].requests) == 0 }, 5*time.Second, 10*time.Millisecond)
+		}
+	}
+	close(clients.wait)
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), f.Service()))
+
+	assert.Len(t, clients.requests(), 2)
+	assert.Equal(t, float64(2), testutil.ToFloat64(f.metrics.profiles.WithLabelValues("tenant", "target", resultDropped)))
+}
+
+func Test_Forwarder_RemoveIdleQueues(t *testing.T) {
+	overrides := mockOverrides{"tenant": {{Name: "target", URL: "http://target"}}}
+	clients := new(mockClients)
+	f := newTestForwarder(t, testConfig, overrides, clients)
+	defer func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), f.Service()))
+	}()
+
+	requests, err := f.Prepare(newPushRequest("tenant", phlaremodel.LabelsFromStrings("service_name", "svc")))
+	require.NoError(t, err)
+	f.Enqueue(requests)
+	require.Eventually(t, func() bool { return len(clients.requests()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	f.removeIdleQueues(time.Now().Add(time.Minute))
+	assert.Empty(t, f.queues)
+	// The queue is recreated on demand.
+	f.Enqueue(requests)
+	require.Eventually(t, func() bool { return len(clients.requests()) == 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_ValidateTargets(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		targets []Target
+		err     bool
+	}{
+		{name: "no targets"},
+		{name: "valid", targets: []Target{{Name: "a", URL: "http://a"}, {Name: "b", URL: "https://b:4040/prefix"}}},
+		{name: "missing name", targets: []Target{{URL: "http://a"}}, err: true},
+		{name: "duplicate name", targets: []Target{{Name: "a", URL: "http://a"}, {Name: "a", URL: "http://b"}}, err: true},
+		{name: "missing url", targets: []Target{{Name: "a"}}, err: true},
+		{name: "relative url", targets: []Target{{Name: "a", URL: "/push"}}, err: true},
+		{name: "invalid relabeling rule", targets: []Target{{Name: "a", URL: "http://a", RelabelConfigs: []*relabel.Config{{Action: relabel.Replace}}}}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTargets(tc.targets)
+			if tc.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
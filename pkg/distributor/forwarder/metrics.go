@@ -0,0 +1,43 @@
+package forwarder
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailed  = "failed"
+	resultDropped = "dropped"
+)
+
+type metrics struct {
+	profiles    *prometheus.CounterVec
+	retries     *prometheus.CounterVec
+	queueLength *prometheus.GaugeVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		profiles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "distributor_forwarded_profiles_total",
+			Help:      "Total number of profiles forwarded to the tenant forwarding targets, by result.",
+		}, []string{"tenant", "target", "result"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pyroscope",
+			Name:      "distributor_forwarding_retries_total",
+			Help:      "Total number of push requests to the tenant forwarding targets retried.",
+		}, []string{"tenant", "target"}),
+		queueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "pyroscope",
+			Name:      "distributor_forwarding_queue_length",
+			Help:      "Number of push requests queued to be sent to the tenant forwarding targets.",
+		}, []string{"tenant", "target"}),
+	}
+	m.profiles = util.RegisterOrGet(reg, m.profiles)
+	m.retries = util.RegisterOrGet(reg, m.retries)
+	m.queueLength = util.RegisterOrGet(reg, m.queueLength)
+	return m
+}
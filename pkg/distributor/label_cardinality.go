@@ -0,0 +1,156 @@
+package distributor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/grafana/dskit/services"
+
+	"github.com/grafana/pyroscope/pkg/util/hyperloglog"
+)
+
+const (
+	// labelCardinalitySketchPrecision determines the memory footprint of
+	// the label value sketches (2KiB per sketch) and the estimate error
+	// (about 2%).
+	labelCardinalitySketchPrecision = 11
+	// maxTrackedLabelNames limits the number of label names tracked per
+	// tenant: values of other label names are not limited.
+	maxTrackedLabelNames = 1000
+)
+
+// labelCardinalityLimiter estimates the number of distinct values of label
+// names with HyperLogLog sketches, and limits the number of new values once
+// the estimate reaches the limit.
+//
+// A value is considered new if adding it to the sketch changes the sketch.
+// Values that don't change the sketch are always accepted: they may be new,
+// but they do not increase the estimate either.
+//
+// Similarly to the active series, the values are tracked in two generations
+// which are rotated every window, so that values that have not been seen
+// recently do not count towards the limit. Each distributor tracks the
+// values independently.
+type labelCardinalityLimiter struct {
+	service services.Service
+	window  time.Duration
+
+	mu      sync.RWMutex
+	tenants map[string]*labelCardinality
+}
+
+func newLabelCardinalityLimiter() *labelCardinalityLimiter {
+	l := &labelCardinalityLimiter{
+		window:  activeSeriesWindow,
+		tenants: make(map[string]*labelCardinality),
+	}
+	l.service = services.NewTimerService(l.window, nil, l.iteration, nil)
+	return l
+}
+
+func (l *labelCardinalityLimiter) iteration(context.Context) error {
+	l.rotate(time.Now())
+	return nil
+}
+
+// allow reports whether the label value is allowed to be ingested.
+// Labels with names starting with "__" are not limited.
+func (l *labelCardinalityLimiter) allow(tenantID, name, value string, limit int) bool {
+	if limit <= 0 || strings.HasPrefix(name, "__") {
+		return true
+	}
+	now := time.Now()
+	l.mu.RLock()
+	c, ok := l.tenants[tenantID]
+	l.mu.RUnlock()
+	if !ok {
+		l.mu.Lock()
+		if c, ok = l.tenants[tenantID]; !ok {
+			c = newLabelCardinality(now)
+			l.tenants[tenantID] = c
+		}
+		l.mu.Unlock()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rotate(now, l.window)
+	return c.allow(name, xxhash.Sum64String(value), limit)
+}
+
+// rotate rotates the generations of all the tenants; the tenants that
+// have no values tracked are removed.
+func (l *labelCardinalityLimiter) rotate(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for tenantID, c := range l.tenants {
+		c.mu.Lock()
+		c.rotate(now, l.window)
+		n := len(c.names)
+		c.mu.Unlock()
+		if n == 0 {
+			delete(l.tenants, tenantID)
+		}
+	}
+}
+
+type labelCardinality struct {
+	mu        sync.Mutex
+	names     map[string]*labelValues
+	rotatedAt time.Time
+}
+
+type labelValues struct {
+	current  *hyperloglog.Sketch
+	previous *hyperloglog.Sketch
+}
+
+func newLabelCardinality(now time.Time) *labelCardinality {
+	return &labelCardinality{
+		names:     make(map[string]*labelValues),
+		rotatedAt: now,
+	}
+}
+
+func (c *labelCardinality) rotate(now time.Time, window time.Duration) {
+	elapsed := now.Sub(c.rotatedAt)
+	if elapsed < window {
+		return
+	}
+	for name, v := range c.names {
+		if elapsed >= 2*window || v.current.Empty() {
+			// None of the values have been seen recently.
+			delete(c.names, name)
+			continue
+		}
+		// The sketch of the previous generation is reused.
+		v.previous, v.current = v.current, v.previous
+		v.current.Reset()
+	}
+	c.rotatedAt = now
+}
+
+func (c *labelCardinality) allow(name string, h uint64, limit int) bool {
+	v, ok := c.names[name]
+	if !ok {
+		if len(c.names) >= maxTrackedLabelNames {
+			return true
+		}
+		v = &labelValues{
+			current:  hyperloglog.New(labelCardinalitySketchPrecision),
+			previous: hyperloglog.New(labelCardinalitySketchPrecision),
+		}
+		c.names[name] = v
+	}
+	if v.current.Covers(h) {
+		return true
+	}
+	if !v.previous.Covers(h) && hyperloglog.Estimate(v.current, v.previous) >= uint64(limit) {
+		return false
+	}
+	// The value is promoted to the current generation.
+	v.current.Insert(h)
+	return true
+}
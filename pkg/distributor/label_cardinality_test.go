@@ -0,0 +1,140 @@
+package distributor
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/ring/client"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	pprof2 "github.com/grafana/pyroscope/pkg/pprof"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/testhelper"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+func Test_labelCardinality(t *testing.T) {
+	const (
+		window = time.Minute
+		limit  = 100
+	)
+	now := time.Unix(0, 0)
+	c := newLabelCardinality(now)
+	value := func(i int) uint64 { return xxhash.Sum64String(strconv.Itoa(i)) }
+
+	var allowed int
+	for i := 0; i < 2*limit; i++ {
+		if c.allow("pod", value(i), limit) {
+			allowed++
+		}
+	}
+	// Some of the values beyond the limit are accepted
+	// as they do not change the sketch.
+	assert.InDelta(t, limit, allowed, limit*0.1)
+	for i := 0; i < 10; i++ {
+		assert.True(t, c.allow("pod", value(i), limit), "known values must be allowed")
+	}
+	assert.True(t, c.allow("namespace", value(2*limit), limit), "label names must be limited independently")
+
+	// Values that have not been seen for two windows are evicted.
+	c.rotate(now.Add(window), window)
+	assert.True(t, c.allow("pod", value(0), limit))
+	assert.Len(t, c.names, 2)
+	c.rotate(now.Add(2*window), window)
+	assert.Len(t, c.names, 1)
+	c.rotate(now.Add(4*window), window)
+	assert.Empty(t, c.names)
+	for i := 2 * limit; i < 2*limit+10; i++ {
+		assert.True(t, c.allow("pod", value(i), limit))
+	}
+}
+
+func Test_labelCardinalityLimiter_ReservedLabels(t *testing.T) {
+	l := newLabelCardinalityLimiter()
+	for i := 0; i < 100; i++ {
+		assert.True(t, l.allow("tenant", phlaremodel.LabelNameSessionID, strconv.Itoa(i), 1))
+	}
+	assert.True(t, l.allow("tenant", "pod", "a", 1))
+	assert.False(t, l.allow("tenant", "pod", "b", 1))
+	assert.True(t, l.allow("tenant", "pod", "a", 1))
+	assert.True(t, l.allow("another-tenant", "pod", "b", 1))
+}
+
+func TestPush_LabelCardinalityLimit(t *testing.T) {
+	newDistributor := func(t *testing.T, tenantID, placeholder string) *Distributor {
+		d, err := New(
+			Config{DistributorRing: ringConfig},
+			testhelper.NewMockRing([]ring.InstanceDesc{{Addr: "foo"}}, 3),
+			&poolFactory{f: func(addr string) (client.PoolClient, error) { return newFakeIngester(t, false), nil }},
+			validation.MockOverrides(func(defaults *validation.Limits, tenantLimits map[string]*validation.Limits) {
+				l := validation.MockDefaultLimits()
+				l.MaxLabelValueCardinality = 1
+				l.LabelCardinalityPlaceholder = placeholder
+				tenantLimits[tenantID] = l
+			}),
+			nil, log.NewLogfmtLogger(os.Stdout), nil,
+		)
+		require.NoError(t, err)
+		return d
+	}
+
+	request := func(pods ...string) *distributormodel.PushRequest {
+		req := new(distributormodel.PushRequest)
+		for _, pod := range pods {
+			req.Series = append(req.Series, &distributormodel.ProfileSeries{
+				Labels: []*typesv1.LabelPair{
+					{Name: phlaremodel.LabelNameServiceName, Value: "svc"},
+					{Name: "__name__", Value: "cpu"},
+					{Name: "pod", Value: pod},
+				},
+				Samples: []*distributormodel.ProfileSample{{
+					Profile: &pprof2.Profile{Profile: testProfile(0)},
+				}},
+			})
+		}
+		return req
+	}
+
+	t.Run("discard", func(t *testing.T) {
+		const tenantID = "user-label-cardinality"
+		d := newDistributor(t, tenantID, "")
+		ctx := tenant.InjectTenantID(context.Background(), tenantID)
+
+		req := request("pod-1", "pod-2")
+		_, err := d.PushParsed(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, req.Series, 1)
+		assert.Equal(t, "pod-1", phlaremodel.Labels(req.Series[0].Labels).Get("pod"))
+
+		_, err = d.PushParsed(ctx, request("pod-3"))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+		assert.Equal(t, validation.LabelCardinalityLimit, validation.ReasonOf(err))
+	})
+
+	t.Run("placeholder", func(t *testing.T) {
+		const tenantID = "user-label-cardinality-placeholder"
+		d := newDistributor(t, tenantID, "other")
+		ctx := tenant.InjectTenantID(context.Background(), tenantID)
+
+		req := request("pod-1", "pod-2")
+		_, err := d.PushParsed(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, req.Series, 2)
+		assert.Equal(t, "pod-1", phlaremodel.Labels(req.Series[0].Labels).Get("pod"))
+		assert.Equal(t, "other", phlaremodel.Labels(req.Series[1].Labels).Get("pod"))
+		assert.Equal(t, float64(1), testutil.ToFloat64(d.metrics.rewrittenLabelValues.WithLabelValues(tenantID)))
+	})
+}
@@ -17,6 +17,9 @@ type metrics struct {
 	receivedSamplesBytes      *prometheus.HistogramVec
 	receivedSymbolsBytes      *prometheus.HistogramVec
 	replicationFactor         prometheus.Gauge
+	discardedBytesByType      *prometheus.CounterVec
+	discardedProfilesByType   *prometheus.CounterVec
+	rewrittenLabelValues      *prometheus.CounterVec
 }
 
 func newMetrics(reg prometheus.Registerer) *metrics {
@@ -71,6 +74,30 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			},
 			[]string{"type", "tenant"},
 		),
+		discardedBytesByType: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pyroscope",
+				Name:      "distributor_discarded_bytes_by_type_total",
+				Help:      "The total number of bytes discarded by the distributor, by profile type.",
+			},
+			[]string{"reason", "type", "tenant"},
+		),
+		discardedProfilesByType: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pyroscope",
+				Name:      "distributor_discarded_profiles_by_type_total",
+				Help:      "The total number of profiles discarded by the distributor, by profile type.",
+			},
+			[]string{"reason", "type", "tenant"},
+		),
+		rewrittenLabelValues: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pyroscope",
+				Name:      "distributor_rewritten_label_values_total",
+				Help:      "The total number of label values replaced with the placeholder because the label reached the cardinality limit.",
+			},
+			[]string{"tenant"},
+		),
 	}
 	if reg != nil {
 		reg.MustRegister(
@@ -80,6 +107,9 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			m.receivedSamplesBytes,
 			m.receivedSymbolsBytes,
 			m.replicationFactor,
+			m.discardedBytesByType,
+			m.discardedProfilesByType,
+			m.rewrittenLabelValues,
 		)
 	}
 	return m
@@ -10,6 +10,8 @@ type RawProfileType string
 
 const RawProfileTypePPROF = RawProfileType("pprof")
 const RawProfileTypeJFR = RawProfileType("jfr")
+const RawProfileTypeETW = RawProfileType("etw")
+const RawProfileTypeGPU = RawProfileType("gpu")
 
 type PushRequest struct {
 	TenantID       string
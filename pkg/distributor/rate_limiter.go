@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/grafana/dskit/blob/main/limiter/rate_limiter.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: Grafana Labs.
+
+package distributor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/limiter"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// rateLimiter is a multi-tenant local rate limiter based on the token
+// bucket algorithm. It's equivalent to the dskit limiter.RateLimiter,
+// except that it exposes the state of the token buckets, which allows
+// operators to see how close tenants are to their limits.
+//
+// The limiter keys are not necessarily tenant IDs: for example, profile
+// type limits are keyed by both tenant ID and profile type.
+type rateLimiter struct {
+	strategy      limiter.RateLimiterStrategy
+	recheckPeriod time.Duration
+
+	mu       sync.RWMutex
+	limiters map[string]*keyLimiter
+}
+
+type keyLimiter struct {
+	limiter   *rate.Limiter
+	recheckAt time.Time
+}
+
+// newRateLimiter creates a new rate limiter. The limit and burst of the
+// keys are obtained from the strategy and updated every recheckPeriod.
+func newRateLimiter(strategy limiter.RateLimiterStrategy, recheckPeriod time.Duration) *rateLimiter {
+	return &rateLimiter{
+		strategy:      strategy,
+		recheckPeriod: recheckPeriod,
+		limiters:      make(map[string]*keyLimiter),
+	}
+}
+
+// AllowN reports whether n tokens may be consumed at time now.
+func (l *rateLimiter) AllowN(now time.Time, key string, n int) bool {
+	return l.limiter(now, key).AllowN(now, n)
+}
+
+// Burst returns the currently configured maximum burst size.
+func (l *rateLimiter) Burst(now time.Time, key string) int {
+	return l.limiter(now, key).Burst()
+}
+
+func (l *rateLimiter) limiter(now time.Time, key string) *rate.Limiter {
+	l.mu.RLock()
+	e, ok := l.limiters[key]
+	l.mu.RUnlock()
+	if ok && now.Before(e.recheckAt) {
+		return e.limiter
+	}
+	limit := rate.Limit(l.strategy.Limit(key))
+	burst := l.strategy.Burst(key)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok = l.limiters[key]; !ok {
+		e = &keyLimiter{limiter: rate.NewLimiter(limit, burst)}
+		l.limiters[key] = e
+	} else if !now.Before(e.recheckAt) {
+		// The limit and burst may have been changed.
+		if e.limiter.Limit() != limit {
+			e.limiter.SetLimitAt(now, limit)
+		}
+		if e.limiter.Burst() != burst {
+			e.limiter.SetBurstAt(now, burst)
+		}
+	}
+	e.recheckAt = now.Add(l.recheckPeriod)
+	return e.limiter
+}
+
+type tokenBucket struct {
+	key    string
+	tokens float64
+	burst  int
+}
+
+// buckets returns the state of the token buckets at time now,
+// ordered by key. Keys without a limit are not included.
+func (l *rateLimiter) buckets(now time.Time) []tokenBucket {
+	l.mu.RLock()
+	buckets := make([]tokenBucket, 0, len(l.limiters))
+	for key, e := range l.limiters {
+		if e.limiter.Limit() == rate.Inf {
+			continue
+		}
+		buckets = append(buckets, tokenBucket{
+			key:    key,
+			tokens: e.limiter.TokensAt(now),
+			burst:  e.limiter.Burst(),
+		})
+	}
+	l.mu.RUnlock()
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].key < buckets[j].key
+	})
+	return buckets
+}
+
+// rateLimiterCollector exposes the fill level of the token buckets of
+// the rate limiter: the number of bytes that can be ingested without
+// exceeding the limit, and the capacity of the bucket (burst size).
+type rateLimiterCollector struct {
+	limiter *rateLimiter
+	// labels returns the label values of the limiter key.
+	labels func(key string) []string
+
+	available *prometheus.Desc
+	burst     *prometheus.Desc
+}
+
+func newRateLimiterCollector(
+	limiter *rateLimiter,
+	name string,
+	help string,
+	labels func(string) []string,
+	labelNames ...string,
+) *rateLimiterCollector {
+	return &rateLimiterCollector{
+		limiter: limiter,
+		labels:  labels,
+		available: prometheus.NewDesc(
+			prometheus.BuildFQName("pyroscope", "distributor", name+"_available_bytes"),
+			"The number of bytes available in the token bucket of the "+help+".",
+			labelNames, nil,
+		),
+		burst: prometheus.NewDesc(
+			prometheus.BuildFQName("pyroscope", "distributor", name+"_burst_bytes"),
+			"The capacity of the token bucket (burst size) of the "+help+".",
+			labelNames, nil,
+		),
+	}
+}
+
+func (c *rateLimiterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.available
+	ch <- c.burst
+}
+
+func (c *rateLimiterCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, b := range c.limiter.buckets(time.Now()) {
+		labels := c.labels(b.key)
+		// The bucket may be in debt after the burst size is reduced.
+		ch <- prometheus.MustNewConstMetric(c.available, prometheus.GaugeValue, max(b.tokens, 0), labels...)
+		ch <- prometheus.MustNewConstMetric(c.burst, prometheus.GaugeValue, float64(b.burst), labels...)
+	}
+}
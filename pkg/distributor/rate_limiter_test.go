@@ -0,0 +1,76 @@
+package distributor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+type staticRateStrategy map[string]struct {
+	limit float64
+	burst int
+}
+
+func (s staticRateStrategy) Limit(key string) float64 {
+	if l, ok := s[key]; ok {
+		return l.limit
+	}
+	return float64(rate.Inf)
+}
+
+func (s staticRateStrategy) Burst(key string) int { return s[key].burst }
+
+func Test_rateLimiter(t *testing.T) {
+	strategy := staticRateStrategy{"tenant-a": {limit: 10, burst: 100}}
+	l := newRateLimiter(strategy, time.Minute)
+	now := time.Unix(0, 0)
+
+	// The burst size is independent of the rate.
+	assert.True(t, l.AllowN(now, "tenant-a", 100))
+	assert.False(t, l.AllowN(now, "tenant-a", 1))
+	assert.False(t, l.AllowN(now.Add(time.Second), "tenant-a", 20))
+	assert.True(t, l.AllowN(now.Add(2*time.Second), "tenant-a", 20))
+	assert.True(t, l.AllowN(now, "tenant-b", 1<<30), "keys without a limit must not be limited")
+
+	// Requests larger than the burst size are never allowed.
+	assert.False(t, l.AllowN(now.Add(time.Hour), "tenant-a", 101))
+	assert.Equal(t, []tokenBucket{{key: "tenant-a", tokens: 100, burst: 100}}, l.buckets(now.Add(time.Hour)))
+
+	// Changes are applied after the recheck period.
+	strategy["tenant-a"] = struct {
+		limit float64
+		burst int
+	}{limit: 10, burst: 200}
+	assert.Equal(t, 100, l.Burst(now.Add(time.Hour), "tenant-a"))
+	assert.Equal(t, 200, l.Burst(now.Add(time.Hour+time.Minute), "tenant-a"))
+}
+
+func Test_rateLimiterCollector(t *testing.T) {
+	l := newRateLimiter(staticRateStrategy{"tenant-a/memory": {limit: 10, burst: 100}}, time.Minute)
+	require.True(t, l.AllowN(time.Now(), "tenant-a/memory", 100))
+	require.True(t, l.AllowN(time.Now(), "tenant-a/process_cpu", 100))
+
+	c := newRateLimiterCollector(l, "test_rate_limit", "test rate limiter",
+		func(key string) []string {
+			tenantID, profileType := splitProfileTypeRateLimiterKey(key)
+			return []string{tenantID, profileType}
+		},
+		"tenant", "type")
+
+	// Only the burst size is compared, as the bucket is being refilled.
+	const expected = `
+# HELP pyroscope_distributor_test_rate_limit_burst_bytes The capacity of the token bucket (burst size) of the test rate limiter.
+# TYPE pyroscope_distributor_test_rate_limit_burst_bytes gauge
+pyroscope_distributor_test_rate_limit_burst_bytes{tenant="tenant-a",type="memory"} 100
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"pyroscope_distributor_test_rate_limit_burst_bytes"))
+	assert.Equal(t, 2, testutil.CollectAndCount(c))
+	available := l.buckets(time.Now())[0].tokens
+	assert.True(t, available >= 0 && available < 100)
+}
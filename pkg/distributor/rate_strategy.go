@@ -6,9 +6,13 @@
 package distributor
 
 import (
+	"strings"
+
 	"golang.org/x/time/rate"
 
 	"github.com/grafana/dskit/limiter"
+
+	"github.com/grafana/pyroscope/pkg/validation"
 )
 
 // ReadLifecycler represents the read interface to the lifecycler.
@@ -70,6 +74,47 @@ func (s *ingestionRateStrategy) Burst(tenantID string) int {
 	return s.limits.IngestionBurstSizeBytes(tenantID)
 }
 
+// profileTypeRateStrategy limits the ingestion rate of profile types:
+// the rate limiter keys are composed of the tenant ID and profile name.
+type profileTypeRateStrategy struct {
+	limits Limits
+}
+
+func newProfileTypeRateStrategy(limits Limits) limiter.RateLimiterStrategy {
+	return &profileTypeRateStrategy{
+		limits: limits,
+	}
+}
+
+func profileTypeRateLimiterKey(tenantID, profileType string) string {
+	// Tenant IDs can't include slashes.
+	return tenantID + "/" + profileType
+}
+
+func splitProfileTypeRateLimiterKey(key string) (tenantID, profileType string) {
+	tenantID, profileType, _ = strings.Cut(key, "/")
+	return tenantID, profileType
+}
+
+func (s *profileTypeRateStrategy) limit(key string) (validation.ProfileTypeRateLimit, bool) {
+	tenantID, profileType := splitProfileTypeRateLimiterKey(key)
+	l, ok := s.limits.IngestionProfileTypeRateLimits(tenantID)[profileType]
+	return l, ok
+}
+
+func (s *profileTypeRateStrategy) Limit(key string) float64 {
+	l, ok := s.limit(key)
+	if !ok {
+		return float64(rate.Inf)
+	}
+	return l.IngestionRateBytes()
+}
+
+func (s *profileTypeRateStrategy) Burst(key string) int {
+	l, _ := s.limit(key)
+	return l.IngestionBurstSizeBytes()
+}
+
 type infiniteStrategy struct{}
 
 func newInfiniteRateStrategy() limiter.RateLimiterStrategy {
@@ -33,6 +33,23 @@ func TestIngestionRateStrategy(t *testing.T) {
 		assert.Equal(t, strategy.Burst("test"), 10000*1024*1024)
 	})
 
+	t.Run("profile type rate limiter should only limit the configured profile types", func(t *testing.T) {
+		overrides, err := validation.NewOverrides(validation.Limits{
+			IngestionProfileTypeRateLimits: validation.ProfileTypeRateLimits{
+				"memory": {IngestionRateMB: 10, IngestionBurstSizeMB: 20},
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		mockRing := newReadLifecyclerMock()
+		mockRing.On("HealthyInstancesCount").Return(2)
+
+		strategy := newGlobalRateStrategy(newProfileTypeRateStrategy(overrides), mockRing)
+		assert.Equal(t, float64(10*1024*1024/2), strategy.Limit(profileTypeRateLimiterKey("test", "memory")))
+		assert.Equal(t, 20*1024*1024, strategy.Burst(profileTypeRateLimiterKey("test", "memory")))
+		assert.Equal(t, float64(rate.Inf), strategy.Limit(profileTypeRateLimiterKey("test", "process_cpu")))
+	})
+
 	t.Run("infinite rate limiter should return unlimited settings", func(t *testing.T) {
 		strategy := newInfiniteRateStrategy()
 
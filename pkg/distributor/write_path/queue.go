@@ -0,0 +1,80 @@
+package writepath
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	minRetryAfter = time.Second
+	maxRetryAfter = 30 * time.Second
+
+	// latencyDecay is the weight of the latest observation
+	// in the moving average of the request duration.
+	latencyDecay = 0.2
+)
+
+// inflightQueue accounts for the requests sent to segment-writers that
+// have not completed yet. When segment-writers are saturated, requests
+// take longer to complete and pile up in the queue: once the number of
+// requests of the tenant reaches the limit, new requests are shed.
+type inflightQueue struct {
+	mu      sync.Mutex
+	tenants map[string]int
+	// Moving average of the request duration.
+	latency time.Duration
+}
+
+func newInflightQueue() *inflightQueue {
+	return &inflightQueue{tenants: make(map[string]int)}
+}
+
+// acquire adds the request to the queue, if the number of in-flight
+// requests of the tenant is below the limit. The queue depth observed
+// is returned. Non-positive limit means no limit.
+func (q *inflightQueue) acquire(tenantID string, limit int) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	depth := q.tenants[tenantID]
+	if limit > 0 && depth >= limit {
+		return depth, false
+	}
+	q.tenants[tenantID] = depth + 1
+	return depth + 1, true
+}
+
+// release removes the request from the queue and
+// updates the request duration moving average.
+func (q *inflightQueue) release(tenantID string, d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if depth := q.tenants[tenantID]; depth > 1 {
+		q.tenants[tenantID] = depth - 1
+	} else {
+		delete(q.tenants, tenantID)
+	}
+	if q.latency == 0 {
+		q.latency = d
+	} else {
+		q.latency += time.Duration(latencyDecay * float64(d-q.latency))
+	}
+}
+
+// retryAfter estimates when the client should retry the request: it's the
+// time needed to process the queued requests one by one, which is
+// intentionally pessimistic, as the queue only builds up when
+// segment-writers are slow to respond.
+func (q *inflightQueue) retryAfter(depth int) time.Duration {
+	q.mu.Lock()
+	d := time.Duration(depth) * q.latency
+	q.mu.Unlock()
+	return min(max(d, minRetryAfter), maxRetryAfter)
+}
+
+// retryAfterHeaderValue formats the duration as
+// the Retry-After header value: delay in seconds.
+func retryAfterHeaderValue(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -11,6 +11,7 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
 	"github.com/grafana/dskit/services"
 	"github.com/pkg/errors"
@@ -58,6 +59,7 @@ type Router struct {
 	logger    log.Logger
 	overrides Overrides
 	metrics   *metrics
+	queue     *inflightQueue
 
 	ingester  IngesterClient
 	segwriter SegmentWriterClient
@@ -74,6 +76,7 @@ func NewRouter(
 		logger:    logger,
 		overrides: overrides,
 		metrics:   newMetrics(registerer),
+		queue:     newInflightQueue(),
 		ingester:  ingester,
 		segwriter: segwriter,
 	}
@@ -100,9 +103,13 @@ func (m *Router) Send(ctx context.Context, req *distributormodel.PushRequest) er
 	config := m.overrides.WritePathOverrides(req.TenantID)
 	switch config.WritePath {
 	case SegmentWriterPath:
-		return m.send(m.segwriterRoute(true))(ctx, req)
+		return m.send(m.segwriterRoute(config, true))(ctx, req)
 	case CombinedPath:
 		return m.sendToBoth(ctx, req, config)
+	case DualPath:
+		config.IngesterWeight = 1
+		config.SegmentWriterWeight = 1
+		return m.sendToBoth(ctx, req, config)
 	default:
 		return m.send(m.ingesterRoute())(ctx, req)
 	}
@@ -119,11 +126,21 @@ func (m *Router) ingesterRoute() *route {
 	}
 }
 
-func (m *Router) segwriterRoute(primary bool) *route {
+func (m *Router) segwriterRoute(config Config, primary bool) *route {
 	return &route{
 		path:    SegmentWriterPath,
 		primary: primary,
 		send: func(ctx context.Context, req *distributormodel.PushRequest) error {
+			depth, ok := m.queue.acquire(req.TenantID, config.SegmentWriterMaxInflightRequests)
+			if !ok {
+				return m.shed(req.TenantID, depth)
+			}
+			m.metrics.inflightRequests.WithLabelValues(req.TenantID).Inc()
+			start := time.Now()
+			defer func() {
+				m.metrics.inflightRequests.WithLabelValues(req.TenantID).Dec()
+				m.queue.release(req.TenantID, time.Since(start))
+			}()
 			// Prepare the requests: we're trying to avoid allocating extra
 			// memory for serialized profiles by reusing the source request
 			// capacities, iff the request won't be sent to ingester.
@@ -152,7 +169,7 @@ func (m *Router) sendToBoth(ctx context.Context, req *distributormodel.PushReque
 		}
 	}
 	if shouldSegwriter {
-		segwriter = m.segwriterRoute(!shouldIngester)
+		segwriter = m.segwriterRoute(config, !shouldIngester)
 		if segwriter.primary {
 			// If the request is sent to segment-writer exclusively:
 			// the response returns to the client when the new write path
@@ -185,6 +202,19 @@ func (m *Router) sendToBoth(ctx context.Context, req *distributormodel.PushReque
 	}
 }
 
+// shed rejects the request because the tenant has too many requests
+// in-flight. The client is expected to retry the request after the
+// delay specified in the Retry-After header.
+func (m *Router) shed(tenantID string, depth int) error {
+	m.metrics.shedRequests.WithLabelValues(tenantID).Inc()
+	retryAfter := m.queue.retryAfter(depth)
+	err := connect.NewError(connect.CodeResourceExhausted, fmt.Errorf(
+		"too many in-flight requests (%d): segment-writers are overloaded, retry after %v (limit: write_path_segment_writer_max_inflight_requests)",
+		depth, retryAfter))
+	err.Meta().Set("Retry-After", retryAfterHeaderValue(retryAfter))
+	return err
+}
+
 type sendFunc func(context.Context, *distributormodel.PushRequest) error
 
 type route struct {
@@ -195,14 +225,34 @@ type route struct {
 
 func (m *Router) sendAsync(ctx context.Context, req *distributormodel.PushRequest, r *route) <-chan error {
 	c := make(chan error, 1)
+	cancel := context.CancelFunc(func() {})
+	if !r.primary {
+		// The secondary request must not be canceled when
+		// the primary one completes, but it is still subject
+		// to the deadline of the client request.
+		ctx, cancel = detachContext(ctx)
+	}
 	m.inflight.Add(1)
 	go func() {
 		defer m.inflight.Done()
-		c <- m.send(r)(ctx, req)
+		defer cancel()
+		err := m.send(r)(ctx, req)
+		if err != nil && !r.primary {
+			level.Warn(m.logger).Log("msg", "secondary write path request failed", "route", r.path, "tenant", req.TenantID, "err", err)
+		}
+		c <- err
 	}()
 	return c
 }
 
+func detachContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithoutCancel(ctx), func() {}
+	}
+	return context.WithDeadline(context.WithoutCancel(ctx), deadline)
+}
+
 func (m *Router) send(r *route) sendFunc {
 	return func(ctx context.Context, req *distributormodel.PushRequest) (err error) {
 		start := time.Now()
@@ -228,6 +278,9 @@ func (m *Router) send(r *route) sendFunc {
 			m.metrics.durationHistogram.
 				WithLabelValues(newDurationHistogramDims(r, code)...).
 				Observe(time.Since(start).Seconds())
+			if err == nil {
+				m.metrics.observeAccepted(r, req)
+			}
 		}()
 		return r.send(ctx, req)
 	}
@@ -4,10 +4,16 @@ import (
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
 )
 
 type metrics struct {
 	durationHistogram *prometheus.HistogramVec
+	inflightRequests  *prometheus.GaugeVec
+	shedRequests      *prometheus.CounterVec
+	acceptedProfiles  *prometheus.CounterVec
+	acceptedBytes     *prometheus.CounterVec
 }
 
 func newMetrics(reg prometheus.Registerer) *metrics {
@@ -17,13 +23,46 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			Buckets: prometheus.ExponentialBucketsRange(0.001, 10, 30),
 			Help:    "Duration of downstream requests made by the write path router.",
 		}, []string{"route", "primary", "status"}),
+		inflightRequests: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "pyroscope_write_path_segment_writer_inflight_requests",
+			Help: "Number of in-flight requests to segment-writers per tenant.",
+		}, []string{"tenant"}),
+		shedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_write_path_shed_requests_total",
+			Help: "Total number of requests rejected because the tenant has too many in-flight requests to segment-writers.",
+		}, []string{"tenant"}),
+		acceptedProfiles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_write_path_accepted_profiles_total",
+			Help: "Total number of profiles accepted by the downstream write path, per route.",
+		}, []string{"route", "primary", "tenant"}),
+		acceptedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_write_path_accepted_bytes_total",
+			Help: "Total uncompressed size of profiles accepted by the downstream write path, per route.",
+		}, []string{"route", "primary", "tenant"}),
 	}
 	if reg != nil {
-		reg.MustRegister(m.durationHistogram)
+		reg.MustRegister(
+			m.durationHistogram,
+			m.inflightRequests,
+			m.shedRequests,
+			m.acceptedProfiles,
+			m.acceptedBytes,
+		)
 	}
 	return m
 }
 
+// observeAccepted counts the profiles accepted by the route: in the dual
+// write mode, the counters of the routes are expected to match.
+func (m *metrics) observeAccepted(r *route, req *distributormodel.PushRequest) {
+	primary := "1"
+	if !r.primary {
+		primary = "0"
+	}
+	m.acceptedProfiles.WithLabelValues(string(r.path), primary, req.TenantID).Add(float64(req.TotalProfiles))
+	m.acceptedBytes.WithLabelValues(string(r.path), primary, req.TenantID).Add(float64(req.TotalBytesUncompressed))
+}
+
 func newDurationHistogramDims(r *route, code int) []string {
 	dims := []string{string(r.path), "1", strconv.Itoa(code)}
 	if !r.primary {
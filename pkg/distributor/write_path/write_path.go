@@ -27,6 +27,13 @@ const (
 	//    Failure of the new write is returned to the client.
 	//    Failure of the old write path is NOT returned to the client.
 	CombinedPath = "combined"
+	// DualPath specifies that each request is sent to both write paths,
+	// regardless of the weights, which is intended for the migration:
+	// ingester is the primary route, and segment-writer is the secondary
+	// one. Failures of the routes are handled independently, as in the
+	// combined mode, and profiles accepted by each of the routes are
+	// counted so that the write paths can be compared.
+	DualPath = "dual"
 )
 
 var ErrInvalidWritePath = errors.New("invalid write path")
@@ -35,9 +42,10 @@ var paths = []WritePath{
 	IngesterPath,
 	SegmentWriterPath,
 	CombinedPath,
+	DualPath,
 }
 
-const validOptionsString = "valid options: ingester, segment-writer, combined"
+const validOptionsString = "valid options: ingester, segment-writer, combined, dual"
 
 func (m *WritePath) Set(text string) error {
 	x := WritePath(text)
@@ -56,6 +64,8 @@ type Config struct {
 	WritePath           WritePath `yaml:"write_path" json:"write_path" doc:"hidden"`
 	IngesterWeight      float64   `yaml:"write_path_ingester_weight" json:"write_path_ingester_weight" doc:"hidden"`
 	SegmentWriterWeight float64   `yaml:"write_path_segment_writer_weight" json:"write_path_segment_writer_weight" doc:"hidden"`
+
+	SegmentWriterMaxInflightRequests int `yaml:"write_path_segment_writer_max_inflight_requests" json:"write_path_segment_writer_max_inflight_requests" doc:"hidden"`
 }
 
 func (o *Config) RegisterFlags(f *flag.FlagSet) {
@@ -65,4 +75,6 @@ func (o *Config) RegisterFlags(f *flag.FlagSet) {
 		"Specifies the fraction [0:1] that should be send to ingester in combined mode. 0 means no traffics is sent to ingester. 1 means 100% of requests are sent to ingester.")
 	f.Float64Var(&o.SegmentWriterWeight, "write-path.segment-writer-weight", 0,
 		"Specifies the fraction [0:1] that should be send to segment-writer in combined mode. 0 means no traffics is sent to segment-writer. 1 means 100% of requests are sent to segment-writer.")
+	f.IntVar(&o.SegmentWriterMaxInflightRequests, "write-path.segment-writer-max-inflight-requests", 0,
+		"Maximum number of in-flight requests of a tenant to segment-writers per distributor. Requests exceeding the limit are rejected with 429 and a Retry-After header. 0 means no limit.")
 }
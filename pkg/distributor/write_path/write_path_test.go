@@ -5,11 +5,14 @@ import (
 	"io"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/services"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
@@ -240,6 +243,50 @@ func (s *routerTestSuite) Test_CombinedPath_SegmentWriter_Exclusive_Error() {
 	s.Assert().Error(s.router.Send(context.Background(), s.request), context.Canceled)
 }
 
+func (s *routerTestSuite) Test_DualPath() {
+	s.overrides.On("WritePathOverrides", "tenant-a").Return(Config{
+		// Weights are ignored in the dual write mode.
+		WritePath: DualPath,
+	})
+	s.request.TotalProfiles = 1
+	s.request.TotalBytesUncompressed = 100
+
+	s.ingester.On("Push", mock.Anything, s.request).
+		Return(new(connect.Response[pushv1.PushResponse]), nil).
+		Twice()
+
+	// The secondary request is not canceled when the client request completes.
+	ctx, cancel := context.WithCancel(context.Background())
+	unblock := make(chan struct{})
+	s.segwriter.On("Push", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-unblock
+			s.Assert().NoError(args.Get(0).(context.Context).Err())
+		}).
+		Return(new(segmentwriterv1.PushResponse), nil).
+		Once()
+
+	s.Assert().NoError(s.router.Send(ctx, s.request))
+	cancel()
+	close(unblock)
+	s.router.inflight.Wait()
+
+	// Failure of the secondary route is not returned to the client.
+	s.segwriter.On("Push", mock.Anything, mock.Anything).
+		Return(new(segmentwriterv1.PushResponse), context.Canceled).
+		Once()
+	s.Assert().NoError(s.router.Send(context.Background(), s.request))
+	s.router.inflight.Wait()
+
+	accepted := func(c *prometheus.CounterVec, route WritePath, primary string) float64 {
+		return testutil.ToFloat64(c.WithLabelValues(string(route), primary, "tenant-a"))
+	}
+	s.Assert().Equal(float64(2), accepted(s.router.metrics.acceptedProfiles, IngesterPath, "1"))
+	s.Assert().Equal(float64(200), accepted(s.router.metrics.acceptedBytes, IngesterPath, "1"))
+	s.Assert().Equal(float64(1), accepted(s.router.metrics.acceptedProfiles, SegmentWriterPath, "0"))
+	s.Assert().Equal(float64(100), accepted(s.router.metrics.acceptedBytes, SegmentWriterPath, "0"))
+}
+
 func (s *routerTestSuite) Test_SegmentWriter_MultipleProfiles() {
 	s.overrides.On("WritePathOverrides", "tenant-a").Return(Config{
 		WritePath:           SegmentWriterPath,
@@ -256,3 +303,69 @@ func (s *routerTestSuite) Test_SegmentWriter_MultipleProfiles() {
 
 	s.Assert().NoError(s.router.Send(context.Background(), s.request))
 }
+
+func (s *routerTestSuite) Test_SegmentWriterPath_InflightLimit() {
+	s.overrides.On("WritePathOverrides", "tenant-a").Return(Config{
+		WritePath:                        SegmentWriterPath,
+		SegmentWriterMaxInflightRequests: 1,
+	})
+
+	blocked := make(chan struct{})
+	unblock := make(chan struct{})
+	s.segwriter.On("Push", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) {
+			close(blocked)
+			<-unblock
+		}).
+		Return(new(segmentwriterv1.PushResponse), nil).
+		Once()
+
+	done := make(chan error)
+	go func() { done <- s.router.Send(context.Background(), s.request) }()
+	<-blocked
+
+	// The in-flight request occupies the queue.
+	err := s.router.Send(context.Background(), s.request)
+	s.Require().Error(err)
+	s.Assert().Equal(connect.CodeResourceExhausted, connect.CodeOf(err))
+	var connectErr *connect.Error
+	s.Require().ErrorAs(err, &connectErr)
+	s.Assert().Equal("1", connectErr.Meta().Get("Retry-After"))
+	s.Assert().Equal(float64(1), testutil.ToFloat64(s.router.metrics.shedRequests.WithLabelValues("tenant-a")))
+	s.Assert().Equal(float64(1), testutil.ToFloat64(s.router.metrics.inflightRequests.WithLabelValues("tenant-a")))
+
+	close(unblock)
+	s.Require().NoError(<-done)
+	s.Assert().Equal(float64(0), testutil.ToFloat64(s.router.metrics.inflightRequests.WithLabelValues("tenant-a")))
+
+	// The queue is drained.
+	s.segwriter.On("Push", mock.Anything, mock.Anything).
+		Return(new(segmentwriterv1.PushResponse), nil).
+		Once()
+	s.Assert().NoError(s.router.Send(context.Background(), s.request))
+}
+
+func Test_inflightQueue_retryAfter(t *testing.T) {
+	q := newInflightQueue()
+	depth, ok := q.acquire("tenant-a", 2)
+	assert.True(t, ok)
+	assert.Equal(t, 1, depth)
+	depth, ok = q.acquire("tenant-a", 2)
+	assert.True(t, ok)
+	assert.Equal(t, 2, depth)
+	depth, ok = q.acquire("tenant-a", 2)
+	assert.False(t, ok)
+	assert.Equal(t, 2, depth)
+	_, ok = q.acquire("tenant-b", 2)
+	assert.True(t, ok, "tenants must not affect each other")
+
+	// No requests completed yet.
+	assert.Equal(t, minRetryAfter, q.retryAfter(depth))
+	q.release("tenant-a", 1500*time.Millisecond)
+	assert.Equal(t, 3*time.Second, q.retryAfter(depth))
+	assert.Equal(t, "3", retryAfterHeaderValue(q.retryAfter(depth)))
+	assert.Equal(t, maxRetryAfter, q.retryAfter(100))
+
+	_, ok = q.acquire("tenant-a", 2)
+	assert.True(t, ok)
+}
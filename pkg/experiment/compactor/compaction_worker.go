@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -24,6 +25,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/util"
@@ -49,11 +51,12 @@ type Worker struct {
 }
 
 type Config struct {
-	JobConcurrency  int           `yaml:"job_capacity"`
-	JobPollInterval time.Duration `yaml:"job_poll_interval"`
-	SmallObjectSize int           `yaml:"small_object_size_bytes"`
-	TempDir         string        `yaml:"temp_dir"`
-	RequestTimeout  time.Duration `yaml:"request_timeout"`
+	JobConcurrency       int           `yaml:"job_capacity"`
+	JobPollInterval      time.Duration `yaml:"job_poll_interval"`
+	SmallObjectSize      int           `yaml:"small_object_size_bytes"`
+	TempDir              string        `yaml:"temp_dir"`
+	RequestTimeout       time.Duration `yaml:"request_timeout"`
+	SharedSymbolsMaxSize int64         `yaml:"shared_symbols_max_size_bytes"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
@@ -64,6 +67,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.RequestTimeout, prefix+"request-timeout", 5*time.Second, "Job request timeout.")
 	f.IntVar(&cfg.SmallObjectSize, prefix+"small-object-size-bytes", 8<<20, "Size of the object that can be loaded in memory.")
 	f.StringVar(&cfg.TempDir, prefix+"temp-dir", tempdir, "Temporary directory for compaction jobs.")
+	f.Int64Var(&cfg.SharedSymbolsMaxSize, prefix+"shared-symbols-max-size-bytes", 0, "If set, the symbols of compacted blocks are stored in dictionaries shared by the blocks, deduplicated by stack trace fingerprints. A dictionary is not extended once its size exceeds the limit. 0 to disable.")
 }
 
 type compactionJob struct {
@@ -351,6 +355,18 @@ func (w *Worker) runCompaction(job *compactionJob) {
 				return nil
 			})
 		}
+		if d := t.GetDictionaries(); d != nil {
+			deleteGroup.Go(func() error {
+				w.deleteDictionaries(deleteCtx, logger, d)
+				return nil
+			})
+		}
+		if a := t.GetArchives(); a != nil {
+			deleteGroup.Go(func() error {
+				w.deleteArchive(deleteCtx, logger, a)
+				return nil
+			})
+		}
 	}
 
 	level.Info(logger).Log(
@@ -363,12 +379,30 @@ func (w *Worker) runCompaction(job *compactionJob) {
 
 	tempdir := filepath.Join(w.config.TempDir, job.Name)
 	sourcedir := filepath.Join(tempdir, "source")
-	compacted, err := block.Compact(ctx, job.blocks, w.storage,
+	options := []block.CompactionOption{
 		block.WithCompactionTempDir(tempdir),
 		block.WithCompactionObjectOptions(
 			block.WithObjectMaxSizeLoadInMemory(w.config.SmallObjectSize),
 			block.WithObjectDownload(sourcedir),
-		))
+		),
+	}
+	if rewrite := job.GetLabelRewrite(); rewrite != nil {
+		rules, err := block.LabelRewriteRules(rewrite.Rules)
+		if err != nil {
+			// The rules are validated when the rewrite job is created,
+			// therefore this is not expected to happen. The job will be
+			// reassigned once the lease expires.
+			level.Error(logger).Log("msg", "invalid label rewrite rules", "rewrite_job", rewrite.Job, "err", err)
+			return
+		}
+		level.Info(logger).Log("msg", "rewriting labels", "rewrite_job", rewrite.Job, "rules", len(rules))
+		options = append(options, block.WithCompactionLabelRewrite(rules))
+	}
+	if w.config.SharedSymbolsMaxSize > 0 {
+		options = append(options, block.WithCompactionSharedSymbols(w.config.SharedSymbolsMaxSize))
+	}
+
+	compacted, err := block.Compact(ctx, job.blocks, w.storage, options...)
 
 	switch {
 	case err == nil:
@@ -403,6 +437,7 @@ func (w *Worker) runCompaction(job *compactionJob) {
 
 		firstBlock := time.UnixMilli(int64(ulid.MustParse(job.blocks[0].Id).Time()))
 		w.metrics.timeToCompaction.WithLabelValues(labels...).Observe(time.Since(firstBlock).Seconds())
+		w.observeJobSize(job, compacted)
 
 	case errors.Is(err, context.Canceled):
 		level.Warn(logger).Log("msg", "job cancelled")
@@ -417,6 +452,22 @@ func (w *Worker) runCompaction(job *compactionJob) {
 	_ = deleteGroup.Wait()
 }
 
+func (w *Worker) observeJobSize(job *compactionJob, compacted []*metastorev1.BlockMeta) {
+	var input, output uint64
+	for _, b := range job.blocks {
+		input += b.Size
+	}
+	for _, b := range compacted {
+		output += b.Size
+		w.metrics.bytesWritten.
+			WithLabelValues(b.TenantId, strconv.Itoa(int(b.CompactionLevel))).
+			Add(float64(b.Size))
+	}
+	level := strconv.Itoa(int(job.CompactionLevel))
+	w.metrics.jobInputSize.WithLabelValues(job.Tenant, level).Observe(float64(input))
+	w.metrics.jobOutputSize.WithLabelValues(job.Tenant, level).Observe(float64(output))
+}
+
 func (w *Worker) getBlockMetadata(logger log.Logger, job *compactionJob) error {
 	ctx, cancel := context.WithTimeout(job.ctx, w.config.RequestTimeout)
 	defer cancel()
@@ -472,3 +523,58 @@ func (w *Worker) deleteBlocks(ctx context.Context, logger log.Logger, t *metasto
 		}
 	}
 }
+
+func (w *Worker) deleteDictionaries(ctx context.Context, logger log.Logger, t *metastorev1.DictionaryTombstones) {
+	level.Info(logger).Log(
+		"msg", "deleting symbols dictionaries",
+		"tenant", t.Tenant,
+		"shard", t.Shard,
+		"paths", strings.Join(t.Paths, " "),
+	)
+	for _, path := range t.Paths {
+		if err := w.storage.Delete(ctx, path); err != nil {
+			if objstore.IsNotExist(w.storage, err) {
+				level.Warn(logger).Log("msg", "dictionary not found", "path", path, "err", err)
+				continue
+			}
+			level.Warn(logger).Log("msg", "failed to delete dictionary", "path", path, "err", err)
+		}
+	}
+}
+
+// deleteArchive deletes the blocks of the archived partition,
+// and then the archive object that lists them.
+func (w *Worker) deleteArchive(ctx context.Context, logger log.Logger, t *metastorev1.ArchiveTombstones) {
+	level.Info(logger).Log("msg", "deleting archived partition", "partition", t.Partition, "path", t.Path)
+	data, err := w.storage.Get(ctx, t.Path)
+	if err != nil {
+		if objstore.IsNotExist(w.storage, err) {
+			level.Warn(logger).Log("msg", "partition archive not found", "path", t.Path, "err", err)
+			return
+		}
+		level.Warn(logger).Log("msg", "failed to fetch partition archive", "path", t.Path, "err", err)
+		return
+	}
+	buf, err := io.ReadAll(data)
+	_ = data.Close()
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to fetch partition archive", "path", t.Path, "err", err)
+		return
+	}
+	archive := new(raft_log.PartitionArchive)
+	if err = archive.UnmarshalVT(buf); err != nil {
+		level.Warn(logger).Log("msg", "invalid partition archive", "path", t.Path, "err", err)
+		return
+	}
+	for _, md := range archive.Blocks {
+		path := block.ObjectPath(md)
+		if err = w.storage.Delete(ctx, path); err != nil && !objstore.IsNotExist(w.storage, err) {
+			// The archive is kept: it is the only record of the blocks left.
+			level.Warn(logger).Log("msg", "failed to delete block", "path", path, "err", err)
+			return
+		}
+	}
+	if err = w.storage.Delete(ctx, t.Path); err != nil {
+		level.Warn(logger).Log("msg", "failed to delete partition archive", "path", t.Path, "err", err)
+	}
+}
@@ -13,6 +13,9 @@ type metrics struct {
 	jobsCompleted    *prometheus.CounterVec
 	jobDuration      *prometheus.HistogramVec
 	timeToCompaction *prometheus.HistogramVec
+	jobInputSize     *prometheus.HistogramVec
+	jobOutputSize    *prometheus.HistogramVec
+	bytesWritten     *prometheus.CounterVec
 }
 
 func newMetrics(r prometheus.Registerer) *metrics {
@@ -31,7 +34,7 @@ func newMetrics(r prometheus.Registerer) *metrics {
 			Name: "job_duration_seconds",
 			Help: "Duration of compaction job runs",
 
-			Buckets:                         prometheus.ExponentialBuckets(1, 300, 16),
+			Buckets:                         prometheus.ExponentialBucketsRange(1, 3600, 16),
 			NativeHistogramBucketFactor:     1.1,
 			NativeHistogramMaxBucketNumber:  16,
 			NativeHistogramMinResetDuration: time.Hour,
@@ -41,11 +44,36 @@ func newMetrics(r prometheus.Registerer) *metrics {
 			Name: "time_to_compaction_seconds",
 			Help: "The time elapsed since the oldest compacted block was created.",
 
-			Buckets:                         prometheus.ExponentialBuckets(1, 3600, 16),
+			Buckets:                         prometheus.ExponentialBucketsRange(1, 7*24*3600, 20),
 			NativeHistogramBucketFactor:     1.1,
 			NativeHistogramMaxBucketNumber:  16,
 			NativeHistogramMinResetDuration: time.Hour,
 		}, []string{"tenant", "level"}),
+
+		jobInputSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "job_input_size_bytes",
+			Help: "The total size of the source blocks of completed compaction jobs.",
+
+			Buckets:                         prometheus.ExponentialBucketsRange(64<<10, 64<<30, 20),
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  20,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, []string{"tenant", "level"}),
+
+		jobOutputSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "job_output_size_bytes",
+			Help: "The total size of the blocks produced by completed compaction jobs.",
+
+			Buckets:                         prometheus.ExponentialBucketsRange(64<<10, 64<<30, 20),
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  20,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, []string{"tenant", "level"}),
+
+		bytesWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "written_bytes_total",
+			Help: "Total number of bytes written to compacted blocks, by the compaction level of the blocks.",
+		}, []string{"tenant", "level"}),
 	}
 
 	util.Register(r,
@@ -53,7 +81,19 @@ func newMetrics(r prometheus.Registerer) *metrics {
 		m.jobsCompleted,
 		m.jobDuration,
 		m.timeToCompaction,
+		m.jobInputSize,
+		m.jobOutputSize,
+		m.bytesWritten,
 	)
 
 	return m
 }
+
+// MetricsPrefix is prepended to the names of the compaction worker
+// metrics by the registerer the worker is created with.
+const MetricsPrefix = "pyroscope_compaction_worker_"
+
+// RegisterMetrics registers the compaction worker metrics without
+// creating the worker. The metrics are used to generate monitoring
+// resources.
+func RegisterMetrics(reg prometheus.Registerer) { newMetrics(reg) }
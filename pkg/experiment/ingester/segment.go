@@ -10,6 +10,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -17,12 +18,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/oklog/ulid"
 	"github.com/thanos-io/objstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
 	"github.com/grafana/pyroscope/pkg/experiment/ingester/memdb"
 	segmentstorage "github.com/grafana/pyroscope/pkg/experiment/ingester/storage"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 	"github.com/grafana/pyroscope/pkg/model"
 	pprofsplit "github.com/grafana/pyroscope/pkg/model/pprof_split"
 	pprofmodel "github.com/grafana/pyroscope/pkg/pprof"
@@ -41,6 +45,11 @@ type segmentsWriter struct {
 	bucket    objstore.Bucket
 	metastore metastorev1.IndexServiceClient
 
+	// writerID and sequence make up the idempotency keys of the blocks:
+	// the writer ID is unique for every process.
+	writerID string
+	sequence atomic.Uint64
+
 	shards     map[shardKey]*shard
 	shardsLock sync.RWMutex
 
@@ -58,6 +67,8 @@ type shard struct {
 	sw        *segmentsWriter
 	mu        sync.RWMutex
 	segment   *segment
+	// Size of the profiles ingested into the last flushed segment.
+	lastSegmentBytes atomic.Int64
 }
 
 func (sh *shard) ingest(fn func(head segmentIngest)) segmentWaitFlushed {
@@ -126,6 +137,7 @@ func newSegmentWriter(l log.Logger, metrics *segmentMetrics, hm *memdb.HeadMetri
 		metastore:   metastoreClient,
 		cancel:      cancelFunc,
 		cancelCtx:   ctx,
+		writerID:    ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String(),
 	}
 
 	return sw
@@ -165,6 +177,25 @@ func (sw *segmentsWriter) Stop() error {
 	return nil
 }
 
+// shardSkew returns the ratio of the largest last flushed segment size
+// to the mean across the shards. The function returns false if no data
+// has been flushed.
+func (sw *segmentsWriter) shardSkew() (float64, bool) {
+	sw.shardsLock.RLock()
+	defer sw.shardsLock.RUnlock()
+	var total, largest int64
+	for _, sh := range sw.shards {
+		n := sh.lastSegmentBytes.Load()
+		total += n
+		largest = max(largest, n)
+	}
+	if total == 0 {
+		return 0, false
+	}
+	mean := float64(total) / float64(len(sw.shards))
+	return float64(largest) / mean, true
+}
+
 func (sw *segmentsWriter) newShard(sk shardKey) *shard {
 	sl := log.With(sw.logger, "shard", fmt.Sprintf("%d", sk))
 	sh := &shard{
@@ -208,6 +239,9 @@ func (s *segment) flush(ctx context.Context) (err error) {
 		}
 		close(s.doneChan)
 		s.sw.metrics.flushSegmentDuration.WithLabelValues(s.sshard).Observe(time.Since(t1).Seconds())
+		if s.sh != nil {
+			s.sh.lastSegmentBytes.Store(s.ingestedBytes.Load())
+		}
 	}()
 	pprof.Do(ctx, pprof.Labels("segment_op", "flush_heads"), func(ctx context.Context) {
 		heads = s.flushHeads(ctx)
@@ -221,20 +255,68 @@ func (s *segment) flush(ctx context.Context) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to flush block %s: %w", s.ulid.String(), err)
 	}
+	s.observeFlushPhase("build", t1)
+	var profiles uint64
+	for _, h := range heads {
+		profiles += h.head.Meta.NumProfiles
+	}
+	s.sw.metrics.segmentProfiles.WithLabelValues(s.sshard).Observe(float64(profiles))
+
+	// If enabled, the intent is recorded before the block is uploaded, and
+	// is removed once the metadata is registered in the metastore or stored
+	// in DLQ. If the commit is not completed, the intent is resolved by the
+	// metastore: see dlq.Recovery.
+	if err = s.sw.storeIntent(ctx, blockMeta, s); err != nil {
+		return fmt.Errorf("failed to store intent %s: %w", s.ulid.String(), err)
+	}
+	t2 := time.Now()
 	if err = s.sw.uploadBlock(ctx, blockData, blockMeta, s); err != nil {
+		s.sw.abortBlock(ctx, blockMeta, s)
 		return fmt.Errorf("failed to upload block %s: %w", s.ulid.String(), err)
 	}
-	if err = s.sw.storeMeta(ctx, blockMeta, s); err != nil {
-		level.Error(s.logger).Log("msg", "failed to store meta in metastore", "err", err)
-		if dlqErr := s.sw.storeMetaDLQ(ctx, blockMeta, s); dlqErr != nil {
-			level.Error(s.logger).Log("msg", "metastore fallback failed", "err", dlqErr)
-			return fmt.Errorf("failed to store meta %s: %w", s.ulid.String(), dlqErr)
-		}
+	s.observeFlushPhase("upload", t2)
+
+	t3 := time.Now()
+	err = s.commitMeta(ctx, blockMeta)
+	s.observeFlushPhase("metadata", t3)
+	if err != nil {
+		return err
 	}
+	s.sw.deleteIntent(ctx, blockMeta, s)
+	return nil
+}
 
+// commitMeta registers the block metadata in the metastore, or stores
+// it in DLQ, if the metastore is not available.
+func (s *segment) commitMeta(ctx context.Context, blockMeta *metastorev1.BlockMeta) error {
+	err := s.sw.storeMeta(ctx, blockMeta, s)
+	if err == nil {
+		return nil
+	}
+	level.Error(s.logger).Log("msg", "failed to store meta in metastore", "err", err)
+	switch status.Code(err) {
+	case codes.AlreadyExists:
+		// Another block with the same identifier is in the metastore:
+		// the object is referenced by the block and must be retained.
+		s.sw.deleteIntent(ctx, blockMeta, s)
+		return fmt.Errorf("failed to store meta %s: %w", s.ulid.String(), err)
+	case codes.InvalidArgument:
+		// The metadata is rejected and can't be added later.
+		s.sw.abortBlock(ctx, blockMeta, s)
+		return fmt.Errorf("failed to store meta %s: %w", s.ulid.String(), err)
+	}
+	if dlqErr := s.sw.storeMetaDLQ(ctx, blockMeta, s); dlqErr != nil {
+		// The intent, if any, is retained: the metastore will register the block.
+		level.Error(s.logger).Log("msg", "metastore fallback failed", "err", dlqErr)
+		return fmt.Errorf("failed to store meta %s: %w", s.ulid.String(), dlqErr)
+	}
 	return nil
 }
 
+func (s *segment) observeFlushPhase(phase string, start time.Time) {
+	s.sw.metrics.flushPhaseDuration.WithLabelValues(s.sshard, phase).Observe(time.Since(start).Seconds())
+}
+
 func (s *segment) flushBlock(heads []flushedServiceHead) ([]byte, *metastorev1.BlockMeta, error) {
 	t1 := time.Now()
 	hostname, _ := os.Hostname()
@@ -249,6 +331,10 @@ func (s *segment) flushBlock(heads []flushedServiceHead) ([]byte, *metastorev1.B
 		Datasets:        make([]*metastorev1.Dataset, 0, len(heads)),
 		Size:            0,
 		CreatedBy:       hostname,
+		IdempotencyKey: &metastorev1.IdempotencyKey{
+			WriterId: s.sw.writerID,
+			Sequence: s.sw.sequence.Add(1),
+		},
 	}
 
 	blockFile := bytes.NewBuffer(nil)
@@ -273,6 +359,11 @@ func (s *segment) flushBlock(heads []flushedServiceHead) ([]byte, *metastorev1.B
 	}
 
 	meta.Size = uint64(w.offset)
+	// The metadata is appended to the object, which allows
+	// the index to be rebuilt from the object storage.
+	if err := block.WriteMetadata(blockFile, meta); err != nil {
+		return nil, nil, err
+	}
 	s.debuginfo.flushBlockDuration = time.Since(t1)
 	return blockFile.Bytes(), meta, nil
 }
@@ -306,6 +397,7 @@ func concatSegmentHead(e flushedServiceHead, w *writerOffset) (*metastorev1.Data
 		//  - 2: symbols.symdb
 		TableOfContents: offsets,
 		ProfileTypes:    ptypes,
+		Series:          e.head.Meta.NumSeries,
 	}
 	return svc, nil
 }
@@ -410,6 +502,9 @@ type segment struct {
 	}
 	sh      *shard
 	counter int64
+
+	ingestedProfiles atomic.Int64
+	ingestedBytes    atomic.Int64
 }
 
 type segmentIngest interface {
@@ -447,6 +542,8 @@ func (s *segment) ingest(tenantID string, p *profilev1.Profile, id uuid.UUID, la
 	}
 	pprofsplit.VisitSampleSeries(p, labels, rules, appender)
 	size -= appender.discardedBytes
+	s.ingestedProfiles.Add(1)
+	s.ingestedBytes.Add(int64(size))
 	s.sw.metrics.segmentIngestBytes.WithLabelValues(s.sshard, tenantID).Observe(float64(size))
 	usage.CountDiscardedBytes(string(validation.DroppedByRelabelRules), int64(appender.discardedBytes))
 	// CountReceivedBytes is tracked in distributors.
@@ -521,6 +618,44 @@ func (sw *segmentsWriter) uploadBlock(ctx context.Context, blockData []byte, met
 	return nil
 }
 
+// storeIntent records the block intent, if enabled. The intent costs a
+// request to the object storage per block, and another one to delete it:
+// the latency is observed as the intent flush phases.
+func (sw *segmentsWriter) storeIntent(ctx context.Context, meta *metastorev1.BlockMeta, s *segment) error {
+	if !sw.config.BlockIntents {
+		return nil
+	}
+	defer s.observeFlushPhase("intent_store", time.Now())
+	metaBlob, err := meta.MarshalVT()
+	if err != nil {
+		return err
+	}
+	return sw.bucket.Upload(ctx, segmentstorage.PathForIntent(meta), bytes.NewReader(metaBlob))
+}
+
+// deleteIntent completes the block commit. The intent is resolved
+// by the metastore if it can't be deleted.
+func (sw *segmentsWriter) deleteIntent(ctx context.Context, meta *metastorev1.BlockMeta, s *segment) {
+	if !sw.config.BlockIntents {
+		return
+	}
+	defer s.observeFlushPhase("intent_delete", time.Now())
+	if err := sw.bucket.Delete(ctx, segmentstorage.PathForIntent(meta)); err != nil {
+		level.Warn(sw.logger).Log("msg", "failed to delete block intent", "block_id", meta.Id, "err", err)
+	}
+}
+
+// abortBlock deletes the block object that will not be registered
+// in the metastore. The intent is retained if the object can't be
+// deleted, so that the metastore resolves it.
+func (sw *segmentsWriter) abortBlock(ctx context.Context, meta *metastorev1.BlockMeta, s *segment) {
+	if err := sw.bucket.Delete(ctx, segmentstorage.PathForSegment(meta)); err != nil && !sw.bucket.IsObjNotFoundErr(err) {
+		level.Warn(sw.logger).Log("msg", "failed to delete block object", "block_id", meta.Id, "err", err)
+		return
+	}
+	sw.deleteIntent(ctx, meta, s)
+}
+
 func (sw *segmentsWriter) storeMeta(ctx context.Context, meta *metastorev1.BlockMeta, s *segment) error {
 	t1 := time.Now()
 	defer func() {
@@ -2,6 +2,8 @@ package ingester
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/experiment/ingester/memdb"
 )
 
 type segmentMetrics struct {
@@ -18,6 +20,8 @@ type segmentMetrics struct {
 	flushHeadsDuration       *prometheus.HistogramVec
 	flushServiceHeadDuration *prometheus.HistogramVec
 	flushServiceHeadError    *prometheus.CounterVec
+	flushPhaseDuration       *prometheus.HistogramVec
+	segmentProfiles          *prometheus.HistogramVec
 }
 
 var (
@@ -26,6 +30,16 @@ var (
 	segmentFlushWaitBuckets = []float64{.1, .2, .3, .4, .5, .6, .7, .8, .9, 1, 1.1, 1.2, 1.3, 1.4, 1.5, 1.6, 1.7, 1.8, 1.9, 2}
 )
 
+const headMetricsPrefix = "pyroscope_segment_writer"
+
+// RegisterMetrics registers the segment writer metrics without creating
+// the service. The metrics are used to generate monitoring resources.
+func RegisterMetrics(reg prometheus.Registerer) {
+	newSegmentMetrics(reg)
+	memdb.NewHeadMetricsWithPrefix(reg, headMetricsPrefix)
+	reg.MustRegister(newShardSkewCollector(nil))
+}
+
 func newSegmentMetrics(reg prometheus.Registerer) *segmentMetrics {
 
 	m := &segmentMetrics{
@@ -102,6 +116,19 @@ func newSegmentMetrics(reg prometheus.Registerer) *segmentMetrics {
 				Name:      "segment_head_size_bytes",
 				Buckets:   prometheus.ExponentialBucketsRange(10*1024, 100*1024*1024, 30),
 			}, []string{"shard", "tenant"}),
+		flushPhaseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pyroscope",
+			Name:      "segment_flush_phase_duration_seconds",
+			Help:      "Duration of the segment flush phases: build, upload, metadata, and, if enabled, intent_store and intent_delete.",
+			Buckets:   networkTimingBuckets,
+		}, []string{"shard", "phase"}),
+		segmentProfiles: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "pyroscope",
+				Name:      "segment_profiles",
+				Help:      "Number of profiles in flushed segments.",
+				Buckets:   prometheus.ExponentialBucketsRange(1, 1<<20, 21),
+			}, []string{"shard"}),
 	}
 
 	if reg != nil {
@@ -118,6 +145,38 @@ func newSegmentMetrics(reg prometheus.Registerer) *segmentMetrics {
 		reg.MustRegister(m.flushServiceHeadError)
 		reg.MustRegister(m.flushSegmentDuration)
 		reg.MustRegister(m.headSizeBytes)
+		reg.MustRegister(m.flushPhaseDuration)
+		reg.MustRegister(m.segmentProfiles)
 	}
 	return m
 }
+
+// shardSkewCollector reports the imbalance of the data ingested into
+// the shards owned by the segment writer: the ratio of the largest last
+// flushed segment to the mean. The ratio of 1 means that the shards
+// receive the same amount of data.
+type shardSkewCollector struct {
+	sw   *segmentsWriter
+	skew *prometheus.Desc
+}
+
+func newShardSkewCollector(sw *segmentsWriter) *shardSkewCollector {
+	return &shardSkewCollector{
+		sw: sw,
+		skew: prometheus.NewDesc(
+			"pyroscope_segment_shard_skew_ratio",
+			"Ratio of the largest last flushed segment size to the mean across the shards owned by the segment writer.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *shardSkewCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.skew
+}
+
+func (c *shardSkewCollector) Collect(ch chan<- prometheus.Metric) {
+	if skew, ok := c.sw.shardSkew(); ok {
+		ch <- prometheus.MustNewConstMetric(c.skew, prometheus.GaugeValue, skew)
+	}
+}
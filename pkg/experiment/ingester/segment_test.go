@@ -233,6 +233,9 @@ func TestBusyIngestLoop(t *testing.T) {
 func TestDLQFail(t *testing.T) {
 	l := testutil.NewLogger(t)
 	bucket := mockobjstore.NewMockBucket(t)
+	bucket.On("Upload", mock.Anything, mock.MatchedBy(func(name string) bool {
+		return segmentstorage.IsIntentPath(name)
+	}), mock.Anything).Return(nil)
 	bucket.On("Upload", mock.Anything, mock.MatchedBy(func(name string) bool {
 		return segmentstorage.IsSegmentPath(name)
 	}), mock.Anything).Return(nil)
@@ -249,6 +252,7 @@ func TestDLQFail(t *testing.T) {
 		memdb.NewHeadMetricsWithPrefix(nil, ""),
 		Config{
 			SegmentDuration: 100 * time.Millisecond,
+			BlockIntents:    true,
 		},
 		validation.MockDefaultOverrides(),
 		bucket,
@@ -278,6 +282,97 @@ func TestDLQFail(t *testing.T) {
 	require.Equal(t, err1, err3)
 }
 
+func TestBlockIntents(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprint(enabled), func(t *testing.T) {
+			bucket := mockobjstore.NewMockBucket(t)
+			bucket.On("Upload", mock.Anything, mock.MatchedBy(func(name string) bool {
+				return segmentstorage.IsSegmentPath(name)
+			}), mock.Anything).Return(nil).Once()
+			if enabled {
+				// The intent is removed once the block is registered.
+				bucket.On("Upload", mock.Anything, mock.MatchedBy(func(name string) bool {
+					return segmentstorage.IsIntentPath(name)
+				}), mock.Anything).Return(nil).Once()
+				bucket.On("Delete", mock.Anything, mock.MatchedBy(func(name string) bool {
+					return segmentstorage.IsIntentPath(name)
+				})).Return(nil).Once()
+			}
+			client := mockmetastorev1.NewMockIndexServiceClient(t)
+			client.On("AddBlock", mock.Anything, mock.Anything, mock.Anything).
+				Return(new(metastorev1.AddBlockResponse), nil).Once()
+
+			sw := newSegmentWriter(
+				testutil.NewLogger(t),
+				newSegmentMetrics(nil),
+				memdb.NewHeadMetricsWithPrefix(nil, ""),
+				Config{
+					SegmentDuration: 100 * time.Millisecond,
+					BlockIntents:    enabled,
+				},
+				validation.MockDefaultOverrides(),
+				bucket,
+				client,
+			)
+			defer sw.Stop()
+			awaiter := sw.ingest(0, func(head segmentIngest) {
+				p := cpuProfile(42, 420, "svc1", "foo", "bar")
+				head.ingest("t1", p.Profile, p.UUID, p.Labels)
+			})
+			require.NoError(t, awaiter.waitFlushed(context.Background()))
+		})
+	}
+}
+
+func TestOpenSegments(t *testing.T) {
+	client := mockmetastorev1.NewMockIndexServiceClient(t)
+	client.On("AddBlock", mock.Anything, mock.Anything, mock.Anything).
+		Return(new(metastorev1.AddBlockResponse), nil).Maybe()
+	res := newSegmentWriter(
+		testutil.NewLogger(t),
+		newSegmentMetrics(nil),
+		memdb.NewHeadMetricsWithPrefix(nil, ""),
+		Config{SegmentDuration: time.Hour},
+		validation.MockDefaultOverrides(),
+		memory.NewInMemBucket(),
+		client,
+	)
+	ing := func(svc string) func(head segmentIngest) {
+		return func(head segmentIngest) {
+			p := cpuProfile(42, 420, svc, "foo", "bar")
+			head.ingest("t1", p.Profile, p.UUID, p.Labels)
+		}
+	}
+	awaiters := []segmentWaitFlushed{
+		res.ingest(1, ing("svc2")),
+		res.ingest(1, ing("svc1")),
+		res.ingest(0, ing("svc1")),
+	}
+
+	segments := res.openSegments(time.Now())
+	require.Len(t, segments, 2)
+	assert.Equal(t, uint32(0), segments[0].Shard)
+	assert.Equal(t, int64(1), segments[0].Profiles)
+	assert.Equal(t, []string{"t1/svc1"}, segments[0].Datasets)
+	assert.Equal(t, uint32(1), segments[1].Shard)
+	assert.Equal(t, int64(2), segments[1].Profiles)
+	assert.Equal(t, []string{"t1/svc1", "t1/svc2"}, segments[1].Datasets)
+	assert.Positive(t, segments[1].Bytes)
+
+	_, ok := res.shardSkew()
+	assert.False(t, ok)
+	res.shards[0].lastSegmentBytes.Store(100)
+	res.shards[1].lastSegmentBytes.Store(300)
+	skew, ok := res.shardSkew()
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, skew)
+
+	require.NoError(t, res.Stop())
+	for _, a := range awaiters {
+		require.NoError(t, a.waitFlushed(context.Background()))
+	}
+}
+
 func TestDatasetMinMaxTime(t *testing.T) {
 	l := testutil.NewLogger(t)
 	bucket := memory.NewInMemBucket()
@@ -0,0 +1,76 @@
+package ingester
+
+import (
+	_ "embed" // Used to embed html template
+	"html/template"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+//go:embed segments.gohtml
+var segmentsPageHTML string
+var segmentsTemplate = template.Must(template.New("webpage").Parse(segmentsPageHTML))
+
+type segmentsPageContents struct {
+	Now      time.Time     `json:"now"`
+	Segments []segmentInfo `json:"segments"`
+}
+
+type segmentInfo struct {
+	Shard    uint32        `json:"shard"`
+	ID       string        `json:"id"`
+	Created  time.Time     `json:"created"`
+	Age      time.Duration `json:"age"`
+	Profiles int64         `json:"profiles"`
+	Bytes    int64         `json:"bytes"`
+	Datasets []string      `json:"datasets"`
+}
+
+// SegmentsHandler lists the segments that are currently open for writing,
+// one per shard owned by the segment writer.
+func (i *SegmentWriterService) SegmentsHandler(w http.ResponseWriter, req *http.Request) {
+	now := time.Now()
+	util.RenderHTTPResponse(w, segmentsPageContents{
+		Now:      now,
+		Segments: i.segmentWriter.openSegments(now),
+	}, segmentsTemplate, req)
+}
+
+func (sw *segmentsWriter) openSegments(now time.Time) []segmentInfo {
+	sw.shardsLock.RLock()
+	shards := make([]*shard, 0, len(sw.shards))
+	for _, sh := range sw.shards {
+		shards = append(shards, sh)
+	}
+	sw.shardsLock.RUnlock()
+
+	segments := make([]segmentInfo, 0, len(shards))
+	for _, sh := range shards {
+		sh.mu.RLock()
+		s := sh.segment
+		sh.mu.RUnlock()
+		created := time.UnixMilli(int64(s.ulid.Time()))
+		info := segmentInfo{
+			Shard:    uint32(s.shard),
+			ID:       s.ulid.String(),
+			Created:  created,
+			Age:      now.Sub(created),
+			Profiles: s.ingestedProfiles.Load(),
+			Bytes:    s.ingestedBytes.Load(),
+		}
+		s.headsLock.RLock()
+		for k := range s.heads {
+			info.Datasets = append(info.Datasets, k.tenant+"/"+k.service)
+		}
+		s.headsLock.RUnlock()
+		slices.Sort(info.Datasets)
+		segments = append(segments, info)
+	}
+	slices.SortFunc(segments, func(a, b segmentInfo) int {
+		return int(a.Shard) - int(b.Shard)
+	})
+	return segments
+}
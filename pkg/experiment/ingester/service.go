@@ -40,6 +40,7 @@ type Config struct {
 	GRPCClientConfig grpcclient.Config     `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate with the segment writer."`
 	LifecyclerConfig ring.LifecyclerConfig `yaml:"lifecycler,omitempty"`
 	SegmentDuration  time.Duration         `yaml:"segment_duration,omitempty"`
+	BlockIntents     bool                  `yaml:"block_intents,omitempty"`
 }
 
 // RegisterFlags registers the flags.
@@ -48,6 +49,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix(prefix, f)
 	cfg.LifecyclerConfig.RegisterFlagsWithPrefix(prefix+".", f, util.Logger)
 	f.DurationVar(&cfg.SegmentDuration, prefix+".segment-duration", 500*time.Millisecond, "Timeout when flushing segments to bucket.")
+	f.BoolVar(&cfg.BlockIntents, prefix+".block-intents", false, "Record an intent in the object storage before each segment block is uploaded, so that the metastore registers the blocks whose commit has been interrupted. Adds two object storage requests to each segment flush, which the ingestion waits for: see the intent phases of pyroscope_segment_flush_phase_duration_seconds.")
 }
 
 func (cfg *Config) Validate() error {
@@ -125,8 +127,11 @@ func New(
 		return nil, errors.New("metastore client is required for segment writer")
 	}
 	metrics := newSegmentMetrics(i.reg)
-	headMetrics := memdb.NewHeadMetricsWithPrefix(reg, "pyroscope_segment_writer")
+	headMetrics := memdb.NewHeadMetricsWithPrefix(reg, headMetricsPrefix)
 	i.segmentWriter = newSegmentWriter(i.logger, metrics, headMetrics, config, limits, storageBucket, metastoreClient)
+	if i.reg != nil {
+		i.reg.MustRegister(newShardSkewCollector(i.segmentWriter))
+	}
 	i.subservicesWatcher = services.NewFailureWatcher()
 	i.subservicesWatcher.WatchManager(i.subservices)
 	i.Service = services.NewBasicService(i.starting, i.running, i.stopping)
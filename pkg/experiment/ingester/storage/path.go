@@ -10,6 +10,11 @@ import (
 
 const PathDLQ = "dlq"
 
+// PathIntents is the location of the block metadata recorded by writers
+// before the block is uploaded. An intent is removed once the metadata is
+// registered in the metastore or handed over to DLQ.
+const PathIntents = "intents"
+
 const pathSegments = "segments"
 const pathAnon = tenant.DefaultTenantID
 const pathBlock = "block.bin"
@@ -19,6 +24,10 @@ func PathForDLQ(meta *metastorev1.BlockMeta) string {
 	return path.Join(PathDLQ, fmt.Sprintf("%d", meta.Shard), pathAnon, meta.Id, pathMetaPB)
 }
 
+func PathForIntent(meta *metastorev1.BlockMeta) string {
+	return path.Join(PathIntents, fmt.Sprintf("%d", meta.Shard), pathAnon, meta.Id, pathMetaPB)
+}
+
 func PathForSegment(meta *metastorev1.BlockMeta) string {
 	return path.Join(pathSegments, fmt.Sprintf("%d", meta.Shard), pathAnon, meta.Id, pathBlock)
 }
@@ -32,3 +41,8 @@ func IsSegmentPath(p string) bool {
 	fs := strings.Split(p, "/")
 	return len(fs) == 5 && fs[0] == pathSegments && fs[2] == pathAnon && fs[4] == pathBlock
 }
+
+func IsIntentPath(p string) bool {
+	fs := strings.Split(p, "/")
+	return len(fs) == 5 && fs[0] == PathIntents && fs[2] == pathAnon && fs[4] == pathMetaPB
+}
@@ -0,0 +1,107 @@
+package metastore
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+type AnnotationReader interface {
+	QueryAnnotations(*bbolt.Tx, *metastorev1.QueryAnnotationsRequest) ([]*typesv1.Annotation, error)
+}
+
+type AnnotationService struct {
+	metastorev1.AnnotationServiceServer
+
+	logger      log.Logger
+	raft        Raft
+	state       State
+	annotations AnnotationReader
+}
+
+func NewAnnotationService(
+	logger log.Logger,
+	raft Raft,
+	state State,
+	annotations AnnotationReader,
+) *AnnotationService {
+	return &AnnotationService{
+		logger:      logger,
+		raft:        raft,
+		state:       state,
+		annotations: annotations,
+	}
+}
+
+func (svc *AnnotationService) AddAnnotation(
+	_ context.Context,
+	req *metastorev1.AddAnnotationRequest,
+) (*metastorev1.AddAnnotationResponse, error) {
+	if err := validateAnnotation(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	annotation := req.Annotation.CloneVT()
+	annotation.Id = ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
+	if annotation.EndTime == 0 {
+		annotation.EndTime = annotation.StartTime
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ADD_ANNOTATION)
+	resp, err := svc.raft.Propose(cmd, &raft_log.AddAnnotationRequest{
+		Tenant:     req.Tenant,
+		Annotation: annotation,
+	})
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to add annotation", "tenant", req.Tenant, "err", err)
+		return nil, err
+	}
+	return &metastorev1.AddAnnotationResponse{
+		Annotation: resp.(*raft_log.AddAnnotationResponse).GetAnnotation(),
+	}, nil
+}
+
+func validateAnnotation(req *metastorev1.AddAnnotationRequest) error {
+	if req.Tenant == "" {
+		return fmt.Errorf("tenant is required")
+	}
+	if req.Annotation == nil {
+		return fmt.Errorf("annotation is required")
+	}
+	if req.Annotation.StartTime <= 0 {
+		return fmt.Errorf("start time is required")
+	}
+	if req.Annotation.EndTime != 0 && req.Annotation.EndTime < req.Annotation.StartTime {
+		return fmt.Errorf("invalid time range: end time must not be before start time")
+	}
+	return nil
+}
+
+func (svc *AnnotationService) QueryAnnotations(
+	ctx context.Context,
+	req *metastorev1.QueryAnnotationsRequest,
+) (resp *metastorev1.QueryAnnotationsResponse, err error) {
+	var annotations []*typesv1.Annotation
+	read := func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		annotations, err = svc.annotations.QueryAnnotations(tx, req)
+	}
+	if readErr := svc.state.ConsistentRead(ctx, read); readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &metastorev1.QueryAnnotationsResponse{Annotations: annotations}, nil
+}
@@ -0,0 +1,82 @@
+package annotations
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/hashicorp/raft"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/annotations/store"
+)
+
+type AnnotationStore interface {
+	StoreAnnotation(*bbolt.Tx, string, *typesv1.Annotation) error
+	ListAnnotations(*bbolt.Tx, string, int64) ([]*typesv1.Annotation, error)
+	CreateBuckets(*bbolt.Tx) error
+}
+
+// Annotations keeps annotations of tenant services. Annotations are
+// not kept in memory: they are only accessed when queried.
+type Annotations struct {
+	store AnnotationStore
+}
+
+func NewAnnotations(store AnnotationStore) *Annotations {
+	return &Annotations{store: store}
+}
+
+func NewStore() *store.AnnotationStore {
+	return store.NewAnnotationStore()
+}
+
+func (a *Annotations) AddAnnotation(
+	tx *bbolt.Tx, cmd *raft.Log, req *raft_log.AddAnnotationRequest,
+) (*raft_log.AddAnnotationResponse, error) {
+	if req.Annotation == nil {
+		return new(raft_log.AddAnnotationResponse), nil
+	}
+	annotation := req.Annotation.CloneVT()
+	annotation.CreatedAt = cmd.AppendedAt.UnixMilli()
+	if err := a.store.StoreAnnotation(tx, req.Tenant, annotation); err != nil {
+		return nil, err
+	}
+	return &raft_log.AddAnnotationResponse{Annotation: annotation}, nil
+}
+
+// QueryAnnotations returns the annotations that overlap the query time
+// range, ordered by start time. It only accesses the store and therefore
+// can be used for reads.
+func (a *Annotations) QueryAnnotations(tx *bbolt.Tx, req *metastorev1.QueryAnnotationsRequest) ([]*typesv1.Annotation, error) {
+	var result []*typesv1.Annotation
+	for _, tenant := range req.Tenant {
+		annotations, err := a.store.ListAnnotations(tx, tenant, req.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range annotations {
+			if x.EndTime < req.StartTime {
+				continue
+			}
+			if x.ServiceName != "" && len(req.ServiceName) > 0 && !slices.Contains(req.ServiceName, x.ServiceName) {
+				continue
+			}
+			result = append(result, x)
+		}
+	}
+	if len(req.Tenant) > 1 {
+		slices.SortStableFunc(result, func(a, b *typesv1.Annotation) int {
+			return cmp.Compare(a.StartTime, b.StartTime)
+		})
+	}
+	return result, nil
+}
+
+func (a *Annotations) Init(tx *bbolt.Tx) error {
+	return a.store.CreateBuckets(tx)
+}
+
+func (a *Annotations) Restore(*bbolt.Tx) error { return nil }
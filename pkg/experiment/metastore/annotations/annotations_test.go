@@ -0,0 +1,71 @@
+package annotations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestAnnotations_Query(t *testing.T) {
+	db := test.BoltDB(t)
+	a := NewAnnotations(NewStore())
+	tx, err := db.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, a.Init(tx))
+
+	cmd := &raft.Log{Index: 1, AppendedAt: time.UnixMilli(1000)}
+	for _, x := range []struct {
+		tenant string
+		*typesv1.Annotation
+	}{
+		{"tenant", &typesv1.Annotation{Id: "a", ServiceName: "foo", Kind: "deploy", StartTime: 10, EndTime: 10}},
+		{"tenant", &typesv1.Annotation{Id: "b", ServiceName: "bar", Kind: "deploy", StartTime: 20, EndTime: 20}},
+		{"tenant", &typesv1.Annotation{Id: "c", Kind: "incident", StartTime: 5, EndTime: 30}},
+		{"tenant", &typesv1.Annotation{Id: "d", ServiceName: "foo", Kind: "deploy", StartTime: 50, EndTime: 50}},
+		{"other", &typesv1.Annotation{Id: "e", ServiceName: "foo", Kind: "deploy", StartTime: 10, EndTime: 10}},
+	} {
+		resp, err := a.AddAnnotation(tx, cmd, &raft_log.AddAnnotationRequest{Tenant: x.tenant, Annotation: x.Annotation})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), resp.Annotation.CreatedAt)
+	}
+	require.NoError(t, tx.Commit())
+
+	ids := func(req *metastorev1.QueryAnnotationsRequest) []string {
+		tx, err := db.Begin(false)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Rollback())
+		}()
+		annotations, err := a.QueryAnnotations(tx, req)
+		require.NoError(t, err)
+		var ids []string
+		for _, x := range annotations {
+			ids = append(ids, x.Id)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"c", "a", "b"}, ids(&metastorev1.QueryAnnotationsRequest{
+		Tenant: []string{"tenant"}, StartTime: 0, EndTime: 40,
+	}))
+	assert.Equal(t, []string{"c", "b"}, ids(&metastorev1.QueryAnnotationsRequest{
+		Tenant: []string{"tenant"}, StartTime: 15, EndTime: 40,
+	}))
+	assert.Equal(t, []string{"c", "a"}, ids(&metastorev1.QueryAnnotationsRequest{
+		Tenant: []string{"tenant"}, StartTime: 0, EndTime: 40, ServiceName: []string{"foo"},
+	}))
+	assert.Equal(t, []string{"c", "a", "e"}, ids(&metastorev1.QueryAnnotationsRequest{
+		Tenant: []string{"tenant", "other"}, StartTime: 0, EndTime: 10, ServiceName: []string{"foo"},
+	}))
+	assert.Empty(t, ids(&metastorev1.QueryAnnotationsRequest{
+		Tenant: []string{"unknown"}, StartTime: 0, EndTime: 100,
+	}))
+}
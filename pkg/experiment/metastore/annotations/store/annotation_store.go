@@ -0,0 +1,70 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+)
+
+var annotationBucketName = []byte("annotation")
+
+var ErrInvalidAnnotation = errors.New("invalid annotation entry")
+
+// AnnotationStore keeps annotations in a bucket per tenant. Keys are
+// ordered by the annotation start time, which allows to stop iterating
+// at the end of the queried time range.
+type AnnotationStore struct{ bucketName []byte }
+
+func NewAnnotationStore() *AnnotationStore {
+	return &AnnotationStore{bucketName: annotationBucketName}
+}
+
+func (s AnnotationStore) CreateBuckets(tx *bbolt.Tx) error {
+	_, err := tx.CreateBucketIfNotExists(s.bucketName)
+	return err
+}
+
+func (s AnnotationStore) StoreAnnotation(tx *bbolt.Tx, tenant string, a *typesv1.Annotation) error {
+	bucket, err := tx.Bucket(s.bucketName).CreateBucketIfNotExists([]byte(tenant))
+	if err != nil {
+		return err
+	}
+	v, _ := a.MarshalVT()
+	return bucket.Put(annotationKey(a), v)
+}
+
+// ListAnnotations returns the annotations of the tenant that start
+// before the given time, in the order of their start time.
+func (s AnnotationStore) ListAnnotations(tx *bbolt.Tx, tenant string, before int64) ([]*typesv1.Annotation, error) {
+	bucket := tx.Bucket(s.bucketName).Bucket([]byte(tenant))
+	if bucket == nil {
+		return nil, nil
+	}
+	var annotations []*typesv1.Annotation
+	c := bucket.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if len(k) < 8 {
+			return nil, fmt.Errorf("%w: malformed key", ErrInvalidAnnotation)
+		}
+		if int64(binary.BigEndian.Uint64(k)) > before {
+			break
+		}
+		var a typesv1.Annotation
+		if err := a.UnmarshalVT(v); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAnnotation, err)
+		}
+		annotations = append(annotations, &a)
+	}
+	return annotations, nil
+}
+
+func annotationKey(a *typesv1.Annotation) []byte {
+	k := make([]byte, 8+len(a.Id))
+	binary.BigEndian.PutUint64(k, uint64(a.StartTime))
+	copy(k[8:], a.Id)
+	return k
+}
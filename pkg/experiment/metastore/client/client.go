@@ -37,6 +37,9 @@ type client struct {
 	metastorev1.CompactionServiceClient
 	metastorev1.MetadataQueryServiceClient
 	metastorev1.TenantServiceClient
+	metastorev1.LabelRewriteServiceClient
+	metastorev1.AnnotationServiceClient
+	metastorev1.EventServiceClient
 	raftnodepb.RaftNodeServiceClient
 
 	conn io.Closer
@@ -49,6 +52,9 @@ type instance interface {
 	metastorev1.MetadataQueryServiceClient
 	metastorev1.TenantServiceClient
 	metastorev1.CompactionServiceClient
+	metastorev1.LabelRewriteServiceClient
+	metastorev1.AnnotationServiceClient
+	metastorev1.EventServiceClient
 	raftnodepb.RaftNodeServiceClient
 }
 
@@ -151,6 +157,9 @@ func newClient(s discovery.Server, config grpcclient.Config, logger log.Logger)
 		CompactionServiceClient:    metastorev1.NewCompactionServiceClient(conn),
 		MetadataQueryServiceClient: metastorev1.NewMetadataQueryServiceClient(conn),
 		TenantServiceClient:        metastorev1.NewTenantServiceClient(conn),
+		LabelRewriteServiceClient:  metastorev1.NewLabelRewriteServiceClient(conn),
+		AnnotationServiceClient:    metastorev1.NewAnnotationServiceClient(conn),
+		EventServiceClient:         metastorev1.NewEventServiceClient(conn),
 		RaftNodeServiceClient:      raftnodepb.NewRaftNodeServiceClient(conn),
 		conn:                       conn,
 		srv:                        s,
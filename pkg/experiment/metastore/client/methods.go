@@ -82,6 +82,23 @@ func (c *Client) selectInstance() *client {
 	return it
 }
 
+// selectFollower returns a random instance other than the leader,
+// or nil, if there are no such instances.
+func (c *Client) selectFollower() *client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	followers := make([]*client, 0, len(c.servers))
+	for k, v := range c.servers {
+		if k != c.leader {
+			followers = append(followers, v)
+		}
+	}
+	if len(followers) == 0 {
+		return nil
+	}
+	return followers[rand.Intn(len(followers))]
+}
+
 // TODO(kolesnikovae): Interceptor.
 
 func (c *Client) AddBlock(ctx context.Context, in *metastorev1.AddBlockRequest, opts ...grpc.CallOption) (*metastorev1.AddBlockResponse, error) {
@@ -90,13 +107,119 @@ func (c *Client) AddBlock(ctx context.Context, in *metastorev1.AddBlockRequest,
 	})
 }
 
+func (c *Client) AddBlocks(ctx context.Context, in *metastorev1.AddBlocksRequest, opts ...grpc.CallOption) (*metastorev1.AddBlocksResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.AddBlocksResponse, error) {
+		return instance.AddBlocks(ctx, in, opts...)
+	})
+}
+
 func (c *Client) GetBlockMetadata(ctx context.Context, in *metastorev1.GetBlockMetadataRequest, opts ...grpc.CallOption) (*metastorev1.GetBlockMetadataResponse, error) {
 	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.GetBlockMetadataResponse, error) {
 		return instance.GetBlockMetadata(ctx, in, opts...)
 	})
 }
 
+func (c *Client) DescribeBlock(ctx context.Context, in *metastorev1.DescribeBlockRequest, opts ...grpc.CallOption) (*metastorev1.DescribeBlockResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.DescribeBlockResponse, error) {
+		return instance.DescribeBlock(ctx, in, opts...)
+	})
+}
+
+func (c *Client) QuarantineBlock(ctx context.Context, in *metastorev1.QuarantineBlockRequest, opts ...grpc.CallOption) (*metastorev1.QuarantineBlockResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.QuarantineBlockResponse, error) {
+		return instance.QuarantineBlock(ctx, in, opts...)
+	})
+}
+
+func (c *Client) ListQuarantinedBlocks(ctx context.Context, in *metastorev1.ListQuarantinedBlocksRequest, opts ...grpc.CallOption) (*metastorev1.ListQuarantinedBlocksResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.ListQuarantinedBlocksResponse, error) {
+		return instance.ListQuarantinedBlocks(ctx, in, opts...)
+	})
+}
+
+func (c *Client) QuarantineWriter(ctx context.Context, in *metastorev1.QuarantineWriterRequest, opts ...grpc.CallOption) (*metastorev1.QuarantineWriterResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.QuarantineWriterResponse, error) {
+		return instance.QuarantineWriter(ctx, in, opts...)
+	})
+}
+
+func (c *Client) ReleaseWriter(ctx context.Context, in *metastorev1.ReleaseWriterRequest, opts ...grpc.CallOption) (*metastorev1.ReleaseWriterResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.ReleaseWriterResponse, error) {
+		return instance.ReleaseWriter(ctx, in, opts...)
+	})
+}
+
+func (c *Client) ListQuarantinedWriters(ctx context.Context, in *metastorev1.ListQuarantinedWritersRequest, opts ...grpc.CallOption) (*metastorev1.ListQuarantinedWritersResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.ListQuarantinedWritersResponse, error) {
+		return instance.ListQuarantinedWriters(ctx, in, opts...)
+	})
+}
+
+func (c *Client) ListBlocks(ctx context.Context, in *metastorev1.ListBlocksRequest, opts ...grpc.CallOption) (*metastorev1.ListBlocksResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.ListBlocksResponse, error) {
+		return instance.ListBlocks(ctx, in, opts...)
+	})
+}
+
+func (c *Client) RebuildIndex(ctx context.Context, in *metastorev1.RebuildIndexRequest, opts ...grpc.CallOption) (*metastorev1.RebuildIndexResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.RebuildIndexResponse, error) {
+		return instance.RebuildIndex(ctx, in, opts...)
+	})
+}
+
+func (c *Client) GetIndexRebuildStatus(ctx context.Context, in *metastorev1.GetIndexRebuildStatusRequest, opts ...grpc.CallOption) (*metastorev1.GetIndexRebuildStatusResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.GetIndexRebuildStatusResponse, error) {
+		return instance.GetIndexRebuildStatus(ctx, in, opts...)
+	})
+}
+
+func (c *Client) ListPartitions(ctx context.Context, in *metastorev1.ListPartitionsRequest, opts ...grpc.CallOption) (*metastorev1.ListPartitionsResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.ListPartitionsResponse, error) {
+		return instance.ListPartitions(ctx, in, opts...)
+	})
+}
+
+func (c *Client) CheckIndex(ctx context.Context, in *metastorev1.CheckIndexRequest, opts ...grpc.CallOption) (*metastorev1.CheckIndexResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.CheckIndexResponse, error) {
+		return instance.CheckIndex(ctx, in, opts...)
+	})
+}
+
+// WatchBlocks opens the stream on the leader, if known, or on any other
+// instance: the changes are streamed by each replica. The stream is not
+// retried: the caller is expected to resume the watch from the cursor of
+// the last response received.
+func (c *Client) WatchBlocks(ctx context.Context, in *metastorev1.WatchBlocksRequest, opts ...grpc.CallOption) (metastorev1.IndexService_WatchBlocksClient, error) {
+	it := c.selectInstance()
+	if it == nil {
+		c.discovery.Rediscover()
+		return nil, fmt.Errorf("no metastore instances available")
+	}
+	return it.WatchBlocks(ctx, in, opts...)
+}
+
+// QueryMetadata sends stale reads to a follower, to divert the load away
+// from the leader. If the follower fails to serve the request, e.g., because
+// it lags behind, the query is performed by the leader as a consistent read.
 func (c *Client) QueryMetadata(ctx context.Context, in *metastorev1.QueryMetadataRequest, opts ...grpc.CallOption) (*metastorev1.QueryMetadataResponse, error) {
+	if in.AllowStaleRead && !in.ReadBarrier {
+		if it := c.selectFollower(); it != nil {
+			resp, err := it.QueryMetadata(ctx, in, opts...)
+			if err == nil {
+				return resp, nil
+			}
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			c.logger.Log(
+				"msg", "stale read failed, falling back to the leader",
+				"err", err,
+				"server_id", it.srv.Raft.ID,
+			)
+		}
+		in = in.CloneVT()
+		in.AllowStaleRead = false
+	}
 	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.QueryMetadataResponse, error) {
 		return instance.QueryMetadata(ctx, in, opts...)
 	})
@@ -114,12 +237,54 @@ func (c *Client) GetTenant(ctx context.Context, in *metastorev1.GetTenantRequest
 	})
 }
 
+func (c *Client) GetTenantBlockStats(ctx context.Context, in *metastorev1.GetTenantBlockStatsRequest, opts ...grpc.CallOption) (*metastorev1.GetTenantBlockStatsResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.GetTenantBlockStatsResponse, error) {
+		return instance.GetTenantBlockStats(ctx, in, opts...)
+	})
+}
+
 func (c *Client) DeleteTenant(ctx context.Context, in *metastorev1.DeleteTenantRequest, opts ...grpc.CallOption) (*metastorev1.DeleteTenantResponse, error) {
 	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.DeleteTenantResponse, error) {
 		return instance.DeleteTenant(ctx, in, opts...)
 	})
 }
 
+func (c *Client) CreateLabelRewriteJob(ctx context.Context, in *metastorev1.CreateLabelRewriteJobRequest, opts ...grpc.CallOption) (*metastorev1.CreateLabelRewriteJobResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.CreateLabelRewriteJobResponse, error) {
+		return instance.CreateLabelRewriteJob(ctx, in, opts...)
+	})
+}
+
+func (c *Client) GetLabelRewriteJob(ctx context.Context, in *metastorev1.GetLabelRewriteJobRequest, opts ...grpc.CallOption) (*metastorev1.GetLabelRewriteJobResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.GetLabelRewriteJobResponse, error) {
+		return instance.GetLabelRewriteJob(ctx, in, opts...)
+	})
+}
+
+func (c *Client) ListLabelRewriteJobs(ctx context.Context, in *metastorev1.ListLabelRewriteJobsRequest, opts ...grpc.CallOption) (*metastorev1.ListLabelRewriteJobsResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.ListLabelRewriteJobsResponse, error) {
+		return instance.ListLabelRewriteJobs(ctx, in, opts...)
+	})
+}
+
+func (c *Client) AddAnnotation(ctx context.Context, in *metastorev1.AddAnnotationRequest, opts ...grpc.CallOption) (*metastorev1.AddAnnotationResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.AddAnnotationResponse, error) {
+		return instance.AddAnnotation(ctx, in, opts...)
+	})
+}
+
+func (c *Client) QueryAnnotations(ctx context.Context, in *metastorev1.QueryAnnotationsRequest, opts ...grpc.CallOption) (*metastorev1.QueryAnnotationsResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.QueryAnnotationsResponse, error) {
+		return instance.QueryAnnotations(ctx, in, opts...)
+	})
+}
+
+func (c *Client) QueryEvents(ctx context.Context, in *metastorev1.QueryEventsRequest, opts ...grpc.CallOption) (*metastorev1.QueryEventsResponse, error) {
+	return invoke(ctx, c, func(ctx context.Context, instance instance) (*metastorev1.QueryEventsResponse, error) {
+		return instance.QueryEvents(ctx, in, opts...)
+	})
+}
+
 func (c *Client) ReadIndex(ctx context.Context, in *raftnodepb.ReadIndexRequest, opts ...grpc.CallOption) (*raftnodepb.ReadIndexResponse, error) {
 	return invoke(ctx, c, func(ctx context.Context, instance instance) (*raftnodepb.ReadIndexResponse, error) {
 		return instance.ReadIndex(ctx, in, opts...)
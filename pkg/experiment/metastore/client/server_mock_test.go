@@ -45,6 +45,10 @@ func (m *mockServer) GetTenant(ctx context.Context, request *metastorev1.GetTena
 	return m.tenant.GetTenant(ctx, request)
 }
 
+func (m *mockServer) GetTenantBlockStats(ctx context.Context, request *metastorev1.GetTenantBlockStatsRequest) (*metastorev1.GetTenantBlockStatsResponse, error) {
+	return m.tenant.GetTenantBlockStats(ctx, request)
+}
+
 func (m *mockServer) DeleteTenant(ctx context.Context, request *metastorev1.DeleteTenantRequest) (*metastorev1.DeleteTenantResponse, error) {
 	return m.tenant.DeleteTenant(ctx, request)
 }
@@ -57,10 +61,62 @@ func (m *mockServer) AddBlock(ctx context.Context, request *metastorev1.AddBlock
 	return m.metastore.AddBlock(ctx, request)
 }
 
+func (m *mockServer) AddBlocks(ctx context.Context, request *metastorev1.AddBlocksRequest) (*metastorev1.AddBlocksResponse, error) {
+	return m.metastore.AddBlocks(ctx, request)
+}
+
 func (m *mockServer) GetBlockMetadata(ctx context.Context, request *metastorev1.GetBlockMetadataRequest) (*metastorev1.GetBlockMetadataResponse, error) {
 	return m.metastore.GetBlockMetadata(ctx, request)
 }
 
+func (m *mockServer) DescribeBlock(ctx context.Context, request *metastorev1.DescribeBlockRequest) (*metastorev1.DescribeBlockResponse, error) {
+	return m.metastore.DescribeBlock(ctx, request)
+}
+
+func (m *mockServer) QuarantineBlock(ctx context.Context, request *metastorev1.QuarantineBlockRequest) (*metastorev1.QuarantineBlockResponse, error) {
+	return m.metastore.QuarantineBlock(ctx, request)
+}
+
+func (m *mockServer) ListQuarantinedBlocks(ctx context.Context, request *metastorev1.ListQuarantinedBlocksRequest) (*metastorev1.ListQuarantinedBlocksResponse, error) {
+	return m.metastore.ListQuarantinedBlocks(ctx, request)
+}
+
+func (m *mockServer) QuarantineWriter(ctx context.Context, request *metastorev1.QuarantineWriterRequest) (*metastorev1.QuarantineWriterResponse, error) {
+	return m.metastore.QuarantineWriter(ctx, request)
+}
+
+func (m *mockServer) ReleaseWriter(ctx context.Context, request *metastorev1.ReleaseWriterRequest) (*metastorev1.ReleaseWriterResponse, error) {
+	return m.metastore.ReleaseWriter(ctx, request)
+}
+
+func (m *mockServer) ListQuarantinedWriters(ctx context.Context, request *metastorev1.ListQuarantinedWritersRequest) (*metastorev1.ListQuarantinedWritersResponse, error) {
+	return m.metastore.ListQuarantinedWriters(ctx, request)
+}
+
+func (m *mockServer) ListBlocks(ctx context.Context, request *metastorev1.ListBlocksRequest) (*metastorev1.ListBlocksResponse, error) {
+	return m.metastore.ListBlocks(ctx, request)
+}
+
+func (m *mockServer) RebuildIndex(ctx context.Context, request *metastorev1.RebuildIndexRequest) (*metastorev1.RebuildIndexResponse, error) {
+	return m.metastore.RebuildIndex(ctx, request)
+}
+
+func (m *mockServer) GetIndexRebuildStatus(ctx context.Context, request *metastorev1.GetIndexRebuildStatusRequest) (*metastorev1.GetIndexRebuildStatusResponse, error) {
+	return m.metastore.GetIndexRebuildStatus(ctx, request)
+}
+
+func (m *mockServer) ListPartitions(ctx context.Context, request *metastorev1.ListPartitionsRequest) (*metastorev1.ListPartitionsResponse, error) {
+	return m.metastore.ListPartitions(ctx, request)
+}
+
+func (m *mockServer) CheckIndex(ctx context.Context, request *metastorev1.CheckIndexRequest) (*metastorev1.CheckIndexResponse, error) {
+	return m.metastore.CheckIndex(ctx, request)
+}
+
+func (m *mockServer) WatchBlocks(request *metastorev1.WatchBlocksRequest, stream metastorev1.IndexService_WatchBlocksServer) error {
+	return m.metastore.WatchBlocks(request, stream)
+}
+
 func (m *mockServer) QueryMetadata(ctx context.Context, request *metastorev1.QueryMetadataRequest) (*metastorev1.QueryMetadataResponse, error) {
 	return m.metadata.QueryMetadata(ctx, request)
 }
@@ -38,6 +38,11 @@ type Scheduler interface {
 	// UpdateSchedule adds new jobs and updates the state of existing ones.
 	// Implementation: This method must be idempotent.
 	UpdateSchedule(*bbolt.Tx, *raft.Log, *raft_log.CompactionPlanUpdate) error
+	// ValidateToken reports whether the job is still owned by the holder of
+	// the fencing token. The check must be performed before the outcome of
+	// the job is applied, as the job may have been reassigned or completed
+	// since the plan update was prepared.
+	ValidateToken(name string, token uint64) bool
 }
 
 // Schedule prepares changes to the compaction plan based on status updates
@@ -72,6 +72,9 @@ type queueStats struct {
 	batches  atomic.Int32
 	rejected atomic.Int32
 	missed   atomic.Int32
+	// Append time of the oldest block in the
+	// queue, in nanoseconds since the epoch.
+	oldest atomic.Int64
 }
 
 // blockRef points to the block in the batch.
@@ -81,8 +84,9 @@ type blockRef struct {
 }
 
 type blockEntry struct {
-	id    string // Block ID.
-	index uint64 // Index of the command in the raft log.
+	id       string // Block ID.
+	index    uint64 // Index of the command in the raft log.
+	appended int64  // Time the block was appended to the raft log.
 }
 
 type batch struct {
@@ -126,8 +130,9 @@ func (q *compactionQueue) push(e store.BlockEntry) bool {
 		level:  e.Level,
 	})
 	pushed := staged.push(blockEntry{
-		id:    e.ID,
-		index: e.Index,
+		id:       e.ID,
+		index:    e.Index,
+		appended: e.AppendedAt,
 	})
 	staged.updatedAt = e.AppendedAt
 	heap.Fix(level.updates, staged.heapIndex)
@@ -202,7 +207,9 @@ func (s *stagedBlocks) push(block blockEntry) bool {
 	s.refs[block.id] = blockRef{batch: s.batch, index: len(s.batch.blocks)}
 	s.batch.blocks = append(s.batch.blocks, block)
 	s.batch.size++
-	s.stats.blocks.Add(1)
+	if s.stats.blocks.Add(1) == 1 {
+		s.stats.oldest.Store(block.appended)
+	}
 	if s.queue.strategy.flush(s.batch) && !s.flush() {
 		// An attempt to flush the same batch twice.
 		// Should not be possible.
@@ -249,10 +256,46 @@ func (s *stagedBlocks) delete(block string) blockEntry {
 	delete(s.refs, block)
 	if len(s.refs) == 0 {
 		s.queue.removeStaged(s)
+	} else if e.appended == s.stats.oldest.Load() {
+		s.stats.oldest.Store(s.oldest())
 	}
 	return e
 }
 
+// oldest returns the append time of the oldest block in the queue.
+// Blocks are ordered by arrival, therefore the first non-empty entry
+// is the oldest one. Entries of deleted blocks are zeroed.
+func (s *stagedBlocks) oldest() int64 {
+	for b := s.head; b != nil; b = b.next {
+		for _, e := range b.blocks {
+			if e.id != "" {
+				return e.appended
+			}
+		}
+	}
+	for _, e := range s.batch.blocks {
+		if e.id != "" {
+			return e.appended
+		}
+	}
+	return 0
+}
+
+// newest returns the identifier of the most recent block in the queue.
+func (s *stagedBlocks) newest() string {
+	for _, b := range []*batch{s.batch, s.tail} {
+		if b == nil {
+			continue
+		}
+		for i := len(b.blocks) - 1; i >= 0; i-- {
+			if id := b.blocks[i].id; id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
 func (q *blockQueue) pushBatch(b *batch) {
 	if q.tail != nil {
 		q.tail.nextG = b
@@ -72,6 +72,24 @@ func TestBlockQueue_Remove(t *testing.T) {
 	require.Nil(t, q.tail)
 }
 
+func TestBlockQueue_OldestBlock(t *testing.T) {
+	q := newBlockQueue(Strategy{MaxBlocksDefault: 2}, nil)
+	key := compactionKey{tenant: "t", shard: 1}
+	for i := 1; i <= 3; i++ {
+		q.stagedBlocks(key).push(blockEntry{id: strconv.Itoa(i), appended: int64(i)})
+	}
+	stats := q.staged[key].stats
+	assert.Equal(t, int64(1), stats.oldest.Load())
+
+	remove(q, key, "2")
+	assert.Equal(t, int64(1), stats.oldest.Load())
+	remove(q, key, "1") // The first batch is removed.
+	assert.Equal(t, int64(3), stats.oldest.Load())
+	q.stagedBlocks(key).push(blockEntry{id: "4", appended: 4})
+	remove(q, key, "3")
+	assert.Equal(t, int64(4), stats.oldest.Load())
+}
+
 func TestBlockQueue_RemoveNotFound(t *testing.T) {
 	q := newBlockQueue(Strategy{MaxBlocksDefault: 3}, nil)
 	key := compactionKey{tenant: "t", shard: 1}
@@ -190,7 +208,7 @@ func TestBlockQueue_FlushByAge(t *testing.T) {
 		batches = append(batches, b.blocks...)
 	}
 
-	expected := []blockEntry{{"1", 1}, {"2", 2}}
+	expected := []blockEntry{{"1", 1, 5}, {"2", 2, 15}}
 	// "3" remains staged as we need another push to evict it.
 	assert.Equal(t, expected, batches)
 
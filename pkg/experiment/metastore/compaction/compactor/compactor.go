@@ -24,6 +24,13 @@ type Tombstones interface {
 	ListTombstones(before time.Time) iter.Iterator[*metastorev1.Tombstones]
 }
 
+// PartitionQuotas reports whether the index partition the block belongs
+// to exceeds its quotas: the blocks of such partitions are compacted
+// ahead of others, regardless of the order of arrival.
+type PartitionQuotas interface {
+	ExceedsQuota(shard uint32, tenant string, block string) bool
+}
+
 type BlockQueueStore interface {
 	StoreEntry(*bbolt.Tx, store.BlockEntry) error
 	DeleteEntry(tx *bbolt.Tx, index uint64, id string) error
@@ -45,12 +52,14 @@ type Compactor struct {
 	queue      *compactionQueue
 	store      BlockQueueStore
 	tombstones Tombstones
+	quotas     PartitionQuotas
 }
 
 func NewCompactor(
 	config Config,
 	store BlockQueueStore,
 	tombstones Tombstones,
+	quotas PartitionQuotas,
 	reg prometheus.Registerer,
 ) *Compactor {
 	queue := newCompactionQueue(config.Strategy, reg)
@@ -59,6 +68,7 @@ func NewCompactor(
 		queue:      queue,
 		store:      store,
 		tombstones: tombstones,
+		quotas:     quotas,
 	}
 }
 
@@ -101,6 +111,10 @@ func (c *Compactor) NewPlan(_ *bbolt.Tx, cmd *raft.Log) compaction.Plan {
 
 func (c *Compactor) UpdatePlan(tx *bbolt.Tx, _ *raft.Log, plan *raft_log.CompactionPlanUpdate) error {
 	for _, job := range plan.NewJobs {
+		if job.Plan.LabelRewrite != nil {
+			// Rewritten blocks are not in the compaction queue.
+			continue
+		}
 		// Delete source blocks from the compaction queue.
 		k := compactionKey{
 			tenant: job.Plan.Tenant,
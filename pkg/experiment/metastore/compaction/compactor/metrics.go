@@ -4,6 +4,8 @@ import (
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/util"
 )
 
 type queueStatsCollector struct {
@@ -13,6 +15,7 @@ type queueStatsCollector struct {
 	batches  *prometheus.Desc
 	rejected *prometheus.Desc
 	missed   *prometheus.Desc
+	oldest   *prometheus.Desc
 }
 
 const blockQueueMetricsPrefix = "compaction_block_queue_"
@@ -50,6 +53,12 @@ func newQueueStatsCollector(staged *stagedBlocks) *queueStatsCollector {
 			"The total number of blocks missed on delete.",
 			nil, constLabels,
 		),
+
+		oldest: prometheus.NewDesc(
+			blockQueueMetricsPrefix+"oldest_block_timestamp_seconds",
+			"The time the oldest block awaiting compaction was added to the queue, as a Unix timestamp.",
+			nil, constLabels,
+		),
 	}
 }
 
@@ -58,6 +67,7 @@ func (b *queueStatsCollector) Describe(c chan<- *prometheus.Desc) {
 	c <- b.batches
 	c <- b.rejected
 	c <- b.missed
+	c <- b.oldest
 }
 
 func (b *queueStatsCollector) Collect(m chan<- prometheus.Metric) {
@@ -65,4 +75,15 @@ func (b *queueStatsCollector) Collect(m chan<- prometheus.Metric) {
 	m <- prometheus.MustNewConstMetric(b.batches, prometheus.GaugeValue, float64(b.stats.batches.Load()))
 	m <- prometheus.MustNewConstMetric(b.rejected, prometheus.CounterValue, float64(b.stats.rejected.Load()))
 	m <- prometheus.MustNewConstMetric(b.missed, prometheus.CounterValue, float64(b.stats.missed.Load()))
+	if oldest := b.stats.oldest.Load(); oldest > 0 {
+		m <- prometheus.MustNewConstMetric(b.oldest, prometheus.GaugeValue, float64(oldest)/1e9)
+	}
+}
+
+// RegisterMetrics registers the block queue metrics. The metrics are
+// registered per compaction key, as the queues are created; for the
+// purpose of generating monitoring resources, a single placeholder
+// queue is registered.
+func RegisterMetrics(reg prometheus.Registerer) {
+	util.RegisterOrGet(reg, newQueueStatsCollector(&stagedBlocks{stats: new(queueStats)}))
 }
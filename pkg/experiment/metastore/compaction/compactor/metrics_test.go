@@ -18,7 +18,7 @@ func TestCollectorRegistration(t *testing.T) {
 			{Tenant: "A", Shard: 1, Level: 0},
 			{Tenant: "B", Shard: 0, Level: 0},
 		}
-		c := NewCompactor(testConfig, nil, nil, reg)
+		c := NewCompactor(testConfig, nil, nil, nil, reg)
 		for _, e := range entries {
 			c.enqueue(e)
 		}
@@ -1,6 +1,7 @@
 package compactor
 
 import (
+	"cmp"
 	"fmt"
 	"slices"
 	"strconv"
@@ -23,6 +24,10 @@ type plan struct {
 	compactor  *Compactor
 	batches    *batchIter
 	blocks     *blockIter
+	// Jobs for the blocks of partitions exceeding
+	// their quotas, planned ahead of the others.
+	priority        []*jobPlan
+	priorityPlanned bool
 }
 
 func (p *plan) CreateJob() (*raft_log.CompactionJobPlan, error) {
@@ -49,12 +54,25 @@ type jobPlan struct {
 }
 
 // Plan compaction of the queued blocks. The algorithm is simple:
+//   - Blocks of partitions exceeding their quotas are planned first,
+//     level by level, including the incomplete batches.
 //   - Iterate block queues from low levels to higher ones.
 //   - Find the oldest batch in the order of arrival and try to compact it.
 //   - A batch may not translate into a job (e.g., if some blocks have been
 //     removed). Therefore, we navigate to the next batch with the same
 //     compaction key in this case.
 func (p *plan) nextJob() *jobPlan {
+	if !p.priorityPlanned {
+		p.priorityPlanned = true
+		p.planPriorityJobs()
+	}
+	if len(p.priority) > 0 {
+		job := p.priority[0]
+		p.priority = p.priority[1:]
+		p.getTombstones(job)
+		return job
+	}
+
 	var job jobPlan
 	for p.level < uint32(len(p.compactor.queue.levels)) {
 		if p.batches == nil {
@@ -107,6 +125,64 @@ func (p *plan) nextJob() *jobPlan {
 	return nil
 }
 
+// planPriorityJobs plans jobs for the blocks of the compaction keys whose
+// most recent blocks belong to partitions exceeding their quotas. Keys are
+// ordered by the oldest block in the queue. The blocks are visited by the
+// plan block iterator, therefore they won't be included into other jobs.
+func (p *plan) planPriorityJobs() {
+	quotas := p.compactor.quotas
+	if quotas == nil {
+		return
+	}
+	for _, level := range p.compactor.queue.levels {
+		if level == nil {
+			continue
+		}
+		keys := make([]*stagedBlocks, 0)
+		for _, staged := range level.staged {
+			if newest := staged.newest(); newest != "" &&
+				quotas.ExceedsQuota(staged.key.shard, staged.key.tenant, newest) {
+				keys = append(keys, staged)
+			}
+		}
+		slices.SortFunc(keys, func(a, b *stagedBlocks) int {
+			return cmp.Or(
+				cmp.Compare(a.stats.oldest.Load(), b.stats.oldest.Load()),
+				strings.Compare(a.key.tenant, b.key.tenant),
+				cmp.Compare(a.key.shard, b.key.shard),
+			)
+		})
+		for _, staged := range keys {
+			p.planStagedJobs(staged)
+		}
+	}
+}
+
+func (p *plan) planStagedJobs(staged *stagedBlocks) {
+	blocks := make([]string, 0, staged.stats.blocks.Load())
+	for _, b := range []*batch{staged.head, staged.batch} {
+		p.blocks.setBatch(b)
+		for {
+			block, ok := p.blocks.next()
+			if !ok {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+	}
+	maxBlocks := max(int(p.compactor.config.maxBlocks(staged.key.level)), 2)
+	for len(blocks) >= 2 {
+		n := min(len(blocks), maxBlocks)
+		job := &jobPlan{
+			compactionKey: staged.key,
+			blocks:        blocks[:n:n],
+		}
+		nameJob(job)
+		p.priority = append(p.priority, job)
+		blocks = blocks[n:]
+	}
+}
+
 // Job name is a variable length string that should be globally unique
 // and is used as a tiebreaker in the compaction job queue ordering.
 func nameJob(plan *jobPlan) {
@@ -130,10 +206,10 @@ func nameJob(plan *jobPlan) {
 }
 
 func (p *plan) getTombstones(job *jobPlan) {
-	if int32(p.level) > p.compactor.config.CleanupJobMaxLevel {
+	if int32(job.level) > p.compactor.config.CleanupJobMaxLevel {
 		return
 	}
-	if int32(p.level) < p.compactor.config.CleanupJobMinLevel {
+	if int32(job.level) < p.compactor.config.CleanupJobMinLevel {
 		return
 	}
 	s := int(p.compactor.config.CleanupBatchSize)
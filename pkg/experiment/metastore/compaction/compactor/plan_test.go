@@ -19,7 +19,7 @@ var testConfig = Config{
 }
 
 func TestPlan_same_level(t *testing.T) {
-	c := NewCompactor(testConfig, nil, nil, nil)
+	c := NewCompactor(testConfig, nil, nil, nil, nil)
 
 	var i int // The index is used outside the loop.
 	for _, e := range []store.BlockEntry{
@@ -103,7 +103,7 @@ func TestPlan_same_level(t *testing.T) {
 }
 
 func TestPlan_level_priority(t *testing.T) {
-	c := NewCompactor(testConfig, nil, nil, nil)
+	c := NewCompactor(testConfig, nil, nil, nil, nil)
 
 	// Lower level job should be planned first despite the arrival order.
 	var i int
@@ -143,7 +143,7 @@ func TestPlan_level_priority(t *testing.T) {
 }
 
 func TestPlan_empty_queue(t *testing.T) {
-	c := NewCompactor(testConfig, nil, nil, nil)
+	c := NewCompactor(testConfig, nil, nil, nil, nil)
 
 	p := &plan{compactor: c, blocks: newBlockIter()}
 	assert.Nil(t, p.nextJob())
@@ -176,7 +176,7 @@ func TestPlan_empty_queue(t *testing.T) {
 }
 
 func TestPlan_deleted_blocks(t *testing.T) {
-	c := NewCompactor(testConfig, nil, nil, nil)
+	c := NewCompactor(testConfig, nil, nil, nil, nil)
 
 	var i int // The index is used outside the loop.
 	for _, e := range []store.BlockEntry{
@@ -251,7 +251,7 @@ func TestPlan_deleted_blocks(t *testing.T) {
 }
 
 func TestPlan_deleted_batch(t *testing.T) {
-	c := NewCompactor(testConfig, nil, nil, nil)
+	c := NewCompactor(testConfig, nil, nil, nil, nil)
 
 	for i, e := range []store.BlockEntry{{}, {}, {}} {
 		e.Index = uint64(i)
@@ -264,3 +264,56 @@ func TestPlan_deleted_batch(t *testing.T) {
 	p := &plan{compactor: c, blocks: newBlockIter()}
 	assert.Nil(t, p.nextJob())
 }
+
+type tenantQuotas map[string]bool
+
+func (q tenantQuotas) ExceedsQuota(_ uint32, tenant string, _ string) bool { return q[tenant] }
+
+func TestPlan_partition_quota(t *testing.T) {
+	c := NewCompactor(testConfig, nil, nil, tenantQuotas{"A": true}, nil)
+
+	for i, e := range []store.BlockEntry{
+		{Tenant: "A", Shard: 1, Level: 0},
+		{Tenant: "B", Shard: 2, Level: 0},
+		{Tenant: "A", Shard: 1, Level: 0},
+		{Tenant: "B", Shard: 2, Level: 0},
+		{Tenant: "B", Shard: 2, Level: 0}, // TB-S2-L0 is ready
+		{Tenant: "C", Shard: 1, Level: 1}, // Single block.
+	} {
+		e.Index = uint64(i)
+		e.ID = strconv.Itoa(i)
+		c.enqueue(e)
+	}
+
+	// The incomplete batch of the partition exceeding
+	// the quota is planned ahead of the complete one.
+	expected := []*jobPlan{
+		{
+			compactionKey: compactionKey{tenant: "A", shard: 1, level: 0},
+			name:          "76c1e21de9b9d231-TA-S1-L0",
+			blocks:        []string{"0", "2"},
+		},
+		{
+			compactionKey: compactionKey{tenant: "B", shard: 2, level: 0},
+			name:          "96944c8eda8151e0-TB-S2-L0",
+			blocks:        []string{"1", "3", "4"},
+		},
+	}
+
+	p := &plan{compactor: c, blocks: newBlockIter()}
+	planned := make([]*jobPlan, 0, len(expected))
+	for j := p.nextJob(); j != nil; j = p.nextJob() {
+		planned = append(planned, j)
+	}
+	assert.Equal(t, expected, planned)
+
+	// Blocks are not planned twice, and a single block
+	// is left in the queue even if it exceeds the quota.
+	c.quotas = tenantQuotas{"A": true, "B": true, "C": true}
+	p = &plan{compactor: c, blocks: newBlockIter()}
+	planned = planned[:0]
+	for j := p.nextJob(); j != nil; j = p.nextJob() {
+		planned = append(planned, j)
+	}
+	assert.Equal(t, expected, planned)
+}
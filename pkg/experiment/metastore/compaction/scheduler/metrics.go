@@ -4,6 +4,8 @@ import (
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/util"
 )
 
 type statsCollector struct {
@@ -117,3 +119,10 @@ func (c *statsCollector) collectMetrics() []prometheus.Metric {
 
 	return metrics
 }
+
+// RegisterMetrics registers the scheduler queue metrics without
+// creating the scheduler. The metrics are used to generate
+// monitoring resources.
+func RegisterMetrics(reg prometheus.Registerer) {
+	util.RegisterOrGet(reg, newStatsCollector(nil))
+}
@@ -133,6 +133,13 @@ func (sc *Scheduler) UpdateSchedule(tx *bbolt.Tx, _ *raft.Log, update *raft_log.
 	return nil
 }
 
+func (sc *Scheduler) ValidateToken(name string, token uint64) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	job, ok := sc.queue.jobs[name]
+	return ok && job.Token == token
+}
+
 func (sc *Scheduler) Init(tx *bbolt.Tx) error {
 	return sc.store.CreateBuckets(tx)
 }
@@ -87,3 +87,35 @@ func TestScheduler_Restore(t *testing.T) {
 
 	store.AssertExpectations(t)
 }
+
+func TestScheduler_ValidateToken(t *testing.T) {
+	store := new(mockscheduler.MockJobStore)
+	store.On("StoreJobState", mock.Anything, mock.Anything).Return(nil)
+	store.On("DeleteJobPlan", mock.Anything, "1").Return(nil).Once()
+	store.On("DeleteJobState", mock.Anything, "1").Return(nil).Once()
+
+	scheduler := NewScheduler(Config{}, store, nil)
+	scheduler.queue.put(&raft_log.CompactionJobState{Name: "1", Token: 1})
+	assert.True(t, scheduler.ValidateToken("1", 1))
+	assert.False(t, scheduler.ValidateToken("1", 2))
+	assert.False(t, scheduler.ValidateToken("2", 1))
+
+	// The job is reassigned to another worker.
+	require.NoError(t, scheduler.UpdateSchedule(nil, &raft.Log{Index: 3}, &raft_log.CompactionPlanUpdate{
+		AssignedJobs: []*raft_log.AssignedCompactionJob{{
+			State: &raft_log.CompactionJobState{Name: "1", Token: 3},
+		}},
+	}))
+	assert.False(t, scheduler.ValidateToken("1", 1))
+	assert.True(t, scheduler.ValidateToken("1", 3))
+
+	// The job is completed by the new owner.
+	require.NoError(t, scheduler.UpdateSchedule(nil, &raft.Log{Index: 4}, &raft_log.CompactionPlanUpdate{
+		CompletedJobs: []*raft_log.CompletedCompactionJob{{
+			State: &raft_log.CompactionJobState{Name: "1", Token: 3},
+		}},
+	}))
+	assert.False(t, scheduler.ValidateToken("1", 3))
+
+	store.AssertExpectations(t)
+}
@@ -1,6 +1,10 @@
 package metastore
 
 import (
+	"errors"
+	"slices"
+	"time"
+
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/hashicorp/raft"
@@ -9,15 +13,24 @@ import (
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 )
 
 type IndexReplacer interface {
-	ReplaceBlocks(*bbolt.Tx, *metastorev1.CompactedBlocks) error
+	FindBlocks(*bbolt.Tx, *metastorev1.BlockList) []*metastorev1.BlockMeta
+	ContainsBlock(tx *bbolt.Tx, shard uint32, tenant string, block string) bool
+	ReplaceBlocks(*bbolt.Tx, *metastorev1.CompactedBlocks, time.Time) error
 }
 
 type TombstoneDeleter interface {
 	DeleteTombstones(*bbolt.Tx, *raft.Log, ...*metastorev1.Tombstones) error
-	AddTombstones(*bbolt.Tx, *raft.Log, *metastorev1.Tombstones) error
+	AddTombstones(*bbolt.Tx, *raft.Log, *metastorev1.Tombstones, ...*metastorev1.BlockMeta) error
+}
+
+type CompactionEventRecorder interface {
+	BlocksCompacted(*bbolt.Tx, *raft.Log, *raft_log.CompletedCompactionJob, []*metastorev1.BlockMeta) error
+	BlocksDeleted(*bbolt.Tx, *raft.Log, ...*metastorev1.Tombstones) error
 }
 
 type CompactionCommandHandler struct {
@@ -27,6 +40,8 @@ type CompactionCommandHandler struct {
 	planner    compaction.Planner
 	scheduler  compaction.Scheduler
 	tombstones TombstoneDeleter
+	rewriter   compaction.Planner
+	events     CompactionEventRecorder
 }
 
 func NewCompactionCommandHandler(
@@ -36,6 +51,8 @@ func NewCompactionCommandHandler(
 	planner compaction.Planner,
 	scheduler compaction.Scheduler,
 	tombstones TombstoneDeleter,
+	rewriter compaction.Planner,
+	events CompactionEventRecorder,
 ) *CompactionCommandHandler {
 	return &CompactionCommandHandler{
 		logger:     logger,
@@ -44,6 +61,8 @@ func NewCompactionCommandHandler(
 		planner:    planner,
 		scheduler:  scheduler,
 		tombstones: tombstones,
+		rewriter:   rewriter,
+		events:     events,
 	}
 }
 
@@ -54,6 +73,7 @@ func (h *CompactionCommandHandler) GetCompactionPlanUpdate(
 	// report from the worker. The plan will be used to update the schedule
 	// after the Raft consensus is reached.
 	planner := h.planner.NewPlan(tx, cmd)
+	rewriter := h.rewriter.NewPlan(tx, cmd)
 	scheduler := h.scheduler.NewSchedule(tx, cmd)
 	p := new(raft_log.CompactionPlanUpdate)
 
@@ -107,12 +127,21 @@ func (h *CompactionCommandHandler) GetCompactionPlanUpdate(
 		p.AssignedJobs = append(p.AssignedJobs, job)
 	}
 
+	// Label rewrite jobs are only planned if there are no regular
+	// compaction jobs to create: the rewrite is a background task
+	// that should not delay compaction.
 	for created := 0; created < capacity; created++ {
 		plan, err := planner.CreateJob()
 		if err != nil {
 			level.Error(h.logger).Log("msg", "failed to create compaction job", "err", err)
 			return nil, err
 		}
+		if plan == nil {
+			if plan, err = rewriter.CreateJob(); err != nil {
+				level.Error(h.logger).Log("msg", "failed to create label rewrite job", "err", err)
+				return nil, err
+			}
+		}
 		if plan == nil {
 			// No more jobs to create.
 			break
@@ -144,6 +173,15 @@ func (h *CompactionCommandHandler) UpdateCompactionPlan(
 		return new(raft_log.UpdateCompactionPlanResponse), nil
 	}
 
+	// The job may have been reassigned to another worker (or even completed)
+	// after the plan update was prepared, e.g., if the worker lost its lease
+	// due to a long pause. The outcome of such a job must not be applied:
+	// otherwise, the compacted blocks of the same sources could be registered
+	// twice, and the sources deleted while still in use by the new owner.
+	if err := h.rejectStaleCompletedJobs(tx, cmd, req.PlanUpdate); err != nil {
+		return nil, err
+	}
+
 	if err := h.planner.UpdatePlan(tx, cmd, req.PlanUpdate); err != nil {
 		level.Error(h.logger).Log("msg", "failed to update compaction planner", "err", err)
 		return nil, err
@@ -154,11 +192,20 @@ func (h *CompactionCommandHandler) UpdateCompactionPlan(
 		return nil, err
 	}
 
+	if err := h.rewriter.UpdatePlan(tx, cmd, req.PlanUpdate); err != nil {
+		level.Error(h.logger).Log("msg", "failed to update label rewrite jobs", "err", err)
+		return nil, err
+	}
+
 	for _, job := range req.PlanUpdate.NewJobs {
 		if err := h.tombstones.DeleteTombstones(tx, cmd, job.Plan.Tombstones...); err != nil {
 			level.Error(h.logger).Log("msg", "failed to delete tombstones", "err", err)
 			return nil, err
 		}
+		if err := h.events.BlocksDeleted(tx, cmd, job.Plan.Tombstones...); err != nil {
+			level.Error(h.logger).Log("msg", "failed to record tombstone events", "err", err)
+			return nil, err
+		}
 	}
 
 	for _, job := range req.PlanUpdate.CompletedJobs {
@@ -167,14 +214,36 @@ func (h *CompactionCommandHandler) UpdateCompactionPlan(
 			level.Error(h.logger).Log("msg", "compacted blocks are missing", "job", job.State.Name)
 			continue
 		}
-		if err := h.index.ReplaceBlocks(tx, compacted); err != nil {
+		source := h.index.FindBlocks(tx, compacted.SourceBlocks)
+		for _, b := range compacted.NewBlocks {
+			stampBlock(b, cmd)
+		}
+		if err := h.index.ReplaceBlocks(tx, compacted, cmd.AppendedAt); err != nil {
+			if errors.Is(err, index.ErrInvalidBlock) {
+				// The index is not modified if the compacted blocks are
+				// invalid: the source blocks remain in place, e.g., if the
+				// partition has been archived while the job was running.
+				// The objects of the compacted blocks are deleted.
+				level.Error(h.logger).Log("msg", "rejecting invalid compacted blocks", "job", job.State.Name, "err", err)
+				for _, t := range blockTombstonesForRejectedBlocks(compacted.NewBlocks) {
+					if err = h.tombstones.AddTombstones(tx, cmd, t); err != nil {
+						level.Error(h.logger).Log("msg", "failed to add tombstones", "err", err)
+						return nil, err
+					}
+				}
+				continue
+			}
 			level.Error(h.logger).Log("msg", "failed to replace blocks", "err", err)
 			return nil, err
 		}
-		if err := h.tombstones.AddTombstones(tx, cmd, blockTombstonesForCompletedJob(job)); err != nil {
+		if err := h.tombstones.AddTombstones(tx, cmd, blockTombstonesForCompletedJob(job, source), source...); err != nil {
 			level.Error(h.logger).Log("msg", "failed to add tombstones", "err", err)
 			return nil, err
 		}
+		if err := h.events.BlocksCompacted(tx, cmd, job, source); err != nil {
+			level.Error(h.logger).Log("msg", "failed to record compaction events", "job", job.State.Name, "err", err)
+			return nil, err
+		}
 		for _, block := range compacted.NewBlocks {
 			if err := h.compactor.Compact(tx, cmd, block); err != nil {
 				level.Error(h.logger).Log("msg", "failed to compact block", "err", err)
@@ -186,15 +255,83 @@ func (h *CompactionCommandHandler) UpdateCompactionPlan(
 	return &raft_log.UpdateCompactionPlanResponse{PlanUpdate: req.PlanUpdate}, nil
 }
 
-func blockTombstonesForCompletedJob(job *raft_log.CompletedCompactionJob) *metastorev1.Tombstones {
+func (h *CompactionCommandHandler) rejectStaleCompletedJobs(tx *bbolt.Tx, cmd *raft.Log, update *raft_log.CompactionPlanUpdate) (err error) {
+	update.CompletedJobs = slices.DeleteFunc(update.CompletedJobs, func(job *raft_log.CompletedCompactionJob) bool {
+		if err != nil || h.scheduler.ValidateToken(job.State.Name, job.State.Token) {
+			return false
+		}
+		level.Warn(h.logger).Log(
+			"msg", "rejecting compaction job completion: fencing token mismatch",
+			"job", job.State.Name,
+			"token", job.State.Token,
+		)
+		// If the same completion report has already been applied, the
+		// compacted blocks are in the index, and deleting them would
+		// cause data loss: only the blocks not found are tombstoned.
+		var rejected []*metastorev1.BlockMeta
+		for _, b := range job.GetCompactedBlocks().GetNewBlocks() {
+			if !h.index.ContainsBlock(tx, b.Shard, b.TenantId, b.Id) {
+				rejected = append(rejected, b)
+			}
+		}
+		for _, t := range blockTombstonesForRejectedBlocks(rejected) {
+			if err = h.tombstones.AddTombstones(tx, cmd, t); err != nil {
+				level.Error(h.logger).Log("msg", "failed to add tombstones", "err", err)
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
+
+// blockTombstonesForRejectedBlocks lists the compacted blocks that have
+// not been added to the index. The block identifiers are unique, and the
+// blocks are never added later, therefore they name the tombstones.
+func blockTombstonesForRejectedBlocks(blocks []*metastorev1.BlockMeta) []*metastorev1.Tombstones {
+	tombstones := make([]*metastorev1.Tombstones, 0, len(blocks))
+	for _, b := range blocks {
+		tombstones = append(tombstones, &metastorev1.Tombstones{
+			Blocks: &metastorev1.BlockTombstones{
+				Name:            "rejected-" + b.Id,
+				Shard:           b.Shard,
+				Tenant:          b.TenantId,
+				CompactionLevel: b.CompactionLevel,
+				Blocks:          []string{block.ObjectID(b)},
+			},
+		})
+	}
+	return tombstones
+}
+
+// blockTombstonesForCompletedJob lists the source blocks of the job by the
+// identifiers their objects are stored under, which differ from the block
+// identifiers if the latter have been re-stamped.
+func blockTombstonesForCompletedJob(job *raft_log.CompletedCompactionJob, metas []*metastorev1.BlockMeta) *metastorev1.Tombstones {
 	source := job.CompactedBlocks.SourceBlocks
+	objects := make(map[string]string, len(metas))
+	for _, md := range metas {
+		if id := block.ObjectID(md); id != md.Id {
+			objects[md.Id] = id
+		}
+	}
+	blocks := source.Blocks
+	if len(objects) > 0 {
+		blocks = make([]string, len(source.Blocks))
+		for i, b := range source.Blocks {
+			if id, ok := objects[b]; ok {
+				b = id
+			}
+			blocks[i] = b
+		}
+	}
 	return &metastorev1.Tombstones{
 		Blocks: &metastorev1.BlockTombstones{
 			Name:            job.State.Name,
 			Shard:           source.Shard,
 			Tenant:          source.Tenant,
 			CompactionLevel: job.State.CompactionLevel,
-			Blocks:          source.Blocks,
+			Blocks:          blocks,
 		},
 	}
 }
@@ -0,0 +1,82 @@
+package metastore
+
+import (
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+type tokenScheduler struct {
+	compaction.Scheduler
+	tokens map[string]uint64
+}
+
+func (s *tokenScheduler) ValidateToken(name string, token uint64) bool {
+	return s.tokens[name] == token
+}
+
+type indexedBlocks struct {
+	IndexReplacer
+	blocks map[string]struct{}
+}
+
+func (x *indexedBlocks) ContainsBlock(_ *bbolt.Tx, _ uint32, _ string, block string) bool {
+	_, ok := x.blocks[block]
+	return ok
+}
+
+type tombstoneRecorder struct {
+	TombstoneDeleter
+	added []string
+}
+
+func (r *tombstoneRecorder) AddTombstones(_ *bbolt.Tx, _ *raft.Log, t *metastorev1.Tombstones, _ ...*metastorev1.BlockMeta) error {
+	r.added = append(r.added, t.Blocks.Blocks...)
+	return nil
+}
+
+func Test_CompactionCommandHandler_RejectStaleCompletedJobs(t *testing.T) {
+	applied := test.ULID("2024-09-23T08:00:00.000Z")
+	reassigned := test.ULID("2024-09-23T08:00:01.000Z")
+	current := test.ULID("2024-09-23T08:00:02.000Z")
+
+	tombstones := new(tombstoneRecorder)
+	h := NewCompactionCommandHandler(log.NewNopLogger(),
+		&indexedBlocks{blocks: map[string]struct{}{applied: {}}},
+		nil, nil,
+		&tokenScheduler{tokens: map[string]uint64{"job-2": 2, "job-3": 1}},
+		tombstones, nil, nil,
+	)
+
+	completed := func(name string, token uint64, block string) *raft_log.CompletedCompactionJob {
+		return &raft_log.CompletedCompactionJob{
+			State: &raft_log.CompactionJobState{Name: name, Token: token},
+			CompactedBlocks: &metastorev1.CompactedBlocks{
+				NewBlocks: []*metastorev1.BlockMeta{{Id: block, Shard: 1, TenantId: "tenant-1"}},
+			},
+		}
+	}
+	update := &raft_log.CompactionPlanUpdate{
+		CompletedJobs: []*raft_log.CompletedCompactionJob{
+			// The completion has already been applied, and the job removed.
+			completed("job-1", 1, applied),
+			// The job has been reassigned to another worker.
+			completed("job-2", 1, reassigned),
+			completed("job-3", 1, current),
+		},
+	}
+	require.NoError(t, h.rejectStaleCompletedJobs(nil, &raft.Log{Index: 1}, update))
+
+	require.Len(t, update.CompletedJobs, 1)
+	assert.Equal(t, "job-3", update.CompletedJobs[0].State.Name)
+	assert.Equal(t, []string{reassigned}, tombstones.added)
+}
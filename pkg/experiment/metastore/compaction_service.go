@@ -2,6 +2,7 @@ package metastore
 
 import (
 	"context"
+	"strconv"
 	"sync"
 
 	"github.com/go-kit/log"
@@ -12,23 +13,30 @@ import (
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/webhooks"
 )
 
 type CompactionService struct {
 	metastorev1.CompactionServiceServer
 
-	logger log.Logger
-	mu     sync.Mutex
-	raft   Raft
+	logger   log.Logger
+	mu       sync.Mutex
+	raft     Raft
+	notifier *webhooks.Notifier
+	maxLevel uint32
 }
 
 func NewCompactionService(
 	logger log.Logger,
 	raft Raft,
+	notifier *webhooks.Notifier,
+	maxLevel uint32,
 ) *CompactionService {
 	return &CompactionService{
-		logger: logger,
-		raft:   raft,
+		logger:   logger,
+		raft:     raft,
+		notifier: notifier,
+		maxLevel: maxLevel,
 	}
 }
 
@@ -113,6 +121,7 @@ func (svc *CompactionService) PollCompactionJobs(
 			CompactionLevel: job.CompactionLevel,
 			SourceBlocks:    job.SourceBlocks,
 			Tombstones:      job.Tombstones,
+			LabelRewrite:    job.LabelRewrite,
 		})
 		// Assigned jobs are not written to the raft log (only the assignments):
 		// from our perspective (scheduler and planner) these are just job updates.
@@ -150,7 +159,38 @@ func (svc *CompactionService) PollCompactionJobs(
 		return nil, status.Error(codes.FailedPrecondition, "failed to update compaction plan")
 	}
 
-	// As of now, accepted plan always matches the proposed one,
-	// so our prepared worker response is still valid.
+	// The accepted plan may only differ from the proposed one in the
+	// completed jobs rejected because of the fencing token mismatch,
+	// so our prepared worker response is still valid: the worker is
+	// done with the job either way.
+	svc.notifyCompacted(accepted)
 	return workerResp, nil
 }
+
+// notifyCompacted emits notifications for the blocks that have reached
+// the final compaction level. The notifications are only sent by the
+// node that handled the request, and not when the raft log is replayed.
+func (svc *CompactionService) notifyCompacted(update *raft_log.CompactionPlanUpdate) {
+	for _, job := range update.CompletedJobs {
+		if job.CompactedBlocks == nil {
+			continue
+		}
+		for _, b := range job.CompactedBlocks.NewBlocks {
+			if b.CompactionLevel < svc.maxLevel {
+				continue
+			}
+			svc.notifier.Notify(webhooks.Event{
+				Type:   webhooks.EventBlockCompacted,
+				Tenant: b.TenantId,
+				Attributes: map[string]string{
+					"block":            b.Id,
+					"shard":            strconv.FormatUint(uint64(b.Shard), 10),
+					"compaction_level": strconv.FormatUint(uint64(b.CompactionLevel), 10),
+					"min_time":         strconv.FormatInt(b.MinTime, 10),
+					"max_time":         strconv.FormatInt(b.MaxTime, 10),
+					"size":             strconv.FormatUint(b.Size, 10),
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package dlq
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	segmentstorage "github.com/grafana/pyroscope/pkg/experiment/ingester/storage"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+// Segment writers with block intents enabled commit blocks in two phases:
+//
+//  1. The block metadata is recorded as an intent, and the block
+//     object is uploaded.
+//  2. The block metadata is registered in the metastore, or stored
+//     in DLQ, if the metastore is not available. The intent is then
+//     removed.
+//
+// The metadata is never registered before the object is uploaded. An
+// intent left behind by a writer that failed to complete the commit is
+// resolved once the grace period expires:
+//
+//   - If the object does not exist, the writer failed to upload it, or the
+//     block has been registered, compacted, and deleted since: the intent
+//     is removed without registering the metadata.
+//   - If the object exists, the metadata is registered, unless the block is
+//     already in the metastore, which is told by the idempotency key. If the
+//     metadata is rejected, the orphaned object is deleted.
+func (r *Recovery) resolveIntent(ctx context.Context, intentPath string) error {
+	meta := r.readMeta(ctx, intentPath)
+	if meta == nil {
+		return nil
+	}
+	id, err := ulid.Parse(meta.Id)
+	if err != nil {
+		level.Error(r.logger).Log("msg", "invalid block id", "err", err, "path", intentPath)
+		return nil
+	}
+	if time.Since(ulid.Time(id.Time())) < r.config.IntentGracePeriod {
+		// The writer may still be committing the block.
+		return nil
+	}
+	objectPath := segmentstorage.PathForSegment(meta)
+	exists, err := r.bucket.Exists(ctx, objectPath)
+	if err != nil {
+		level.Error(r.logger).Log("msg", "failed to check block object", "err", err, "path", objectPath)
+		return nil
+	}
+	if !exists {
+		level.Warn(r.logger).Log("msg", "block object not found, discarding intent", "path", intentPath)
+		r.deleteObject(ctx, intentPath)
+		return nil
+	}
+	if _, err = r.metastore.AddRecoveredBlock(ctx, &metastorev1.AddBlockRequest{Block: meta}); err != nil {
+		if raftnode.IsRaftLeadershipError(err) {
+			return err
+		}
+		level.Error(r.logger).Log("msg", "failed to add block", "err", err, "path", intentPath)
+		switch status.Code(err) {
+		case codes.AlreadyExists:
+			// Another block with the same identifier is in the
+			// metastore: the object must not be deleted.
+		case codes.InvalidArgument:
+			r.deleteObject(ctx, objectPath)
+		default:
+			return nil
+		}
+	}
+	r.deleteObject(ctx, intentPath)
+	return nil
+}
+
+func (r *Recovery) deleteObject(ctx context.Context, path string) {
+	if err := r.bucket.Delete(ctx, path); err != nil {
+		level.Error(r.logger).Log("msg", "failed to delete object", "err", err, "path", path)
+	}
+}
@@ -0,0 +1,74 @@
+package dlq
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	segmentstorage "github.com/grafana/pyroscope/pkg/experiment/ingester/storage"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/memory"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockdlq"
+)
+
+func TestResolveIntents(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	newBlock := func(created time.Time) *metastorev1.BlockMeta {
+		return &metastorev1.BlockMeta{
+			Id:    ulid.MustNew(ulid.Timestamp(created), rand.Reader).String(),
+			Shard: 1,
+		}
+	}
+	var (
+		// The writer may still be committing the block.
+		inProgress = newBlock(time.Now())
+		// The writer failed to upload the block.
+		notUploaded = newBlock(past)
+		// The writer failed to register the block.
+		uploaded = newBlock(past)
+		// The metastore rejects the block.
+		rejected = newBlock(past)
+	)
+
+	bucket := memory.NewInMemBucket()
+	for _, meta := range []*metastorev1.BlockMeta{inProgress, notUploaded, uploaded, rejected} {
+		addIntent(bucket, meta)
+	}
+	for _, meta := range []*metastorev1.BlockMeta{inProgress, uploaded, rejected} {
+		bucket.Set(segmentstorage.PathForSegment(meta), []byte{1})
+	}
+
+	srv := mockdlq.NewMockLocalServer(t)
+	srv.On("AddRecoveredBlock", mock.Anything, mock.MatchedBy(func(r *metastorev1.AddBlockRequest) bool {
+		return r.Block.Id == uploaded.Id
+	})).Once().Return(&metastorev1.AddBlockResponse{}, nil)
+	srv.On("AddRecoveredBlock", mock.Anything, mock.MatchedBy(func(r *metastorev1.AddBlockRequest) bool {
+		return r.Block.Id == rejected.Id
+	})).Once().Return(nil, status.Error(codes.InvalidArgument, "invalid block"))
+
+	r := NewRecovery(testutil.NewLogger(t), RecoveryConfig{IntentGracePeriod: time.Minute}, srv, bucket)
+	r.recoverTick(context.Background())
+
+	objects := make([]string, 0, len(bucket.Objects()))
+	for path := range bucket.Objects() {
+		objects = append(objects, path)
+	}
+	assert.ElementsMatch(t, []string{
+		segmentstorage.PathForIntent(inProgress),
+		segmentstorage.PathForSegment(inProgress),
+		segmentstorage.PathForSegment(uploaded),
+	}, objects)
+}
+
+func addIntent(bucket *memory.InMemBucket, meta *metastorev1.BlockMeta) {
+	data, _ := meta.MarshalVT()
+	bucket.Set(segmentstorage.PathForIntent(meta), data)
+}
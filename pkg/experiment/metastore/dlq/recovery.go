@@ -13,6 +13,8 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/thanos-io/objstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	segmentstorage "github.com/grafana/pyroscope/pkg/experiment/ingester/storage"
@@ -20,11 +22,13 @@ import (
 )
 
 type RecoveryConfig struct {
-	Period time.Duration `yaml:"dlq_recovery_check_interval"`
+	Period            time.Duration `yaml:"dlq_recovery_check_interval"`
+	IntentGracePeriod time.Duration `yaml:"block_intent_grace_period"`
 }
 
 func (c *RecoveryConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.DurationVar(&c.Period, prefix+"dlq-recovery-check-interval", 15*time.Second, "Dead Letter Queue check interval.")
+	f.DurationVar(&c.IntentGracePeriod, prefix+"block-intent-grace-period", 5*time.Minute, "How long to wait for the writer to complete the block commit before the block intent is resolved by the metastore.")
 }
 
 type LocalServer interface {
@@ -100,9 +104,44 @@ func (r *Recovery) recoverTick(ctx context.Context) {
 	if err != nil {
 		level.Error(r.logger).Log("msg", "failed to iterate over dlq", "err", err)
 	}
+	err = r.bucket.Iter(ctx, segmentstorage.PathIntents, func(intentPath string) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return r.resolveIntent(ctx, intentPath)
+	}, objstore.WithRecursiveIter)
+	if err != nil {
+		level.Error(r.logger).Log("msg", "failed to iterate over block intents", "err", err)
+	}
 }
 
 func (r *Recovery) recover(ctx context.Context, metaPath string) error {
+	meta := r.readMeta(ctx, metaPath)
+	if meta == nil {
+		return nil
+	}
+	if _, err := r.metastore.AddRecoveredBlock(ctx, &metastorev1.AddBlockRequest{Block: meta}); err != nil {
+		if raftnode.IsRaftLeadershipError(err) {
+			return err
+		}
+		level.Error(r.logger).Log("msg", "failed to add block", "err", err, "path", metaPath)
+		switch status.Code(err) {
+		case codes.AlreadyExists, codes.InvalidArgument:
+			// The metadata conflicts with another block or is invalid, and
+			// will never be added: there's no point to keep it in DLQ.
+		default:
+			return nil
+		}
+	}
+	if err := r.bucket.Delete(ctx, metaPath); err != nil {
+		level.Error(r.logger).Log("msg", "failed to delete block meta", "err", err, "path", metaPath)
+	}
+	return nil
+}
+
+// readMeta returns the block metadata stored at the given path,
+// or nil if it can't be read or does not match the path.
+func (r *Recovery) readMeta(ctx context.Context, metaPath string) *metastorev1.BlockMeta {
 	fields := strings.Split(metaPath, "/")
 	if len(fields) != 5 {
 		r.logger.Log("msg", "unexpected path", "path", metaPath)
@@ -120,18 +159,7 @@ func (r *Recovery) recover(ctx context.Context, metaPath string) error {
 		level.Error(r.logger).Log("msg", "unexpected block meta", "path", metaPath, "meta", fmt.Sprintf("%+v", meta))
 		return nil
 	}
-	if _, err = r.metastore.AddRecoveredBlock(ctx, &metastorev1.AddBlockRequest{Block: meta}); err != nil {
-		if raftnode.IsRaftLeadershipError(err) {
-			return err
-		}
-		level.Error(r.logger).Log("msg", "failed to add block", "err", err, "path", metaPath)
-		return nil
-	}
-	err = r.bucket.Delete(ctx, metaPath)
-	if err != nil {
-		level.Error(r.logger).Log("msg", "failed to delete block meta", "err", err, "path", metaPath)
-	}
-	return nil
+	return meta
 }
 
 func (r *Recovery) get(ctx context.Context, metaPath string) (*metastorev1.BlockMeta, error) {
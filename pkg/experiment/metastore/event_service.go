@@ -0,0 +1,60 @@
+package metastore
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+type EventReader interface {
+	QueryEvents(*bbolt.Tx, *metastorev1.QueryEventsRequest) ([]*metastorev1.Event, error)
+}
+
+type EventService struct {
+	metastorev1.EventServiceServer
+
+	logger log.Logger
+	state  State
+	events EventReader
+}
+
+func NewEventService(
+	logger log.Logger,
+	state State,
+	events EventReader,
+) *EventService {
+	return &EventService{
+		logger: logger,
+		state:  state,
+		events: events,
+	}
+}
+
+func (svc *EventService) QueryEvents(
+	ctx context.Context,
+	req *metastorev1.QueryEventsRequest,
+) (resp *metastorev1.QueryEventsResponse, err error) {
+	if len(req.Tenant) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "tenant is required")
+	}
+	if req.EndTime < req.StartTime {
+		return nil, status.Error(codes.InvalidArgument, "invalid time range: end time must not be before start time")
+	}
+	var events []*metastorev1.Event
+	read := func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		events, err = svc.events.QueryEvents(tx, req)
+	}
+	if readErr := svc.state.ConsistentRead(ctx, read); readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &metastorev1.QueryEventsResponse{Events: events}, nil
+}
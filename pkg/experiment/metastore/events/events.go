@@ -0,0 +1,290 @@
+package events
+
+import (
+	"cmp"
+	"flag"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/events/store"
+)
+
+type Config struct {
+	RetentionPeriod time.Duration `yaml:"event_log_retention_period"`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.RetentionPeriod, prefix+"event-log-retention-period", 0, "How long to keep the storage lifecycle events: blocks added, compacted, deleted, and quarantined. Every segment added makes an event, therefore the log may take a considerable amount of space. Should be the same on all the replicas. 0 to disable the event log.")
+}
+
+// Old events are deleted at most once per truncationInterval.
+const truncationInterval = time.Hour
+
+type EventStore interface {
+	StoreEvent(tx *bbolt.Tx, index uint64, ordinal uint32, e *metastorev1.Event) error
+	ListEvents(tx *bbolt.Tx, tenant string, start, end int64) ([]*metastorev1.Event, error)
+	DeleteEvents(tx *bbolt.Tx, before int64) error
+	TruncatedAt(*bbolt.Tx) int64
+	SetTruncatedAt(*bbolt.Tx, int64) error
+	CreateBuckets(*bbolt.Tx) error
+}
+
+// Events keeps the log of the storage lifecycle events. The events are
+// recorded by the FSM command handlers, and are not kept in memory: they
+// are only accessed when queried.
+type Events struct {
+	config Config
+	store  EventStore
+
+	// Events recorded within the same command are told
+	// apart by their ordinal number.
+	index   uint64
+	ordinal uint32
+}
+
+func NewEvents(config Config, store EventStore) *Events {
+	return &Events{config: config, store: store}
+}
+
+func NewStore() *store.EventStore {
+	return store.NewEventStore()
+}
+
+// BlockAdded records that the block has been added to the index.
+func (e *Events) BlockAdded(tx *bbolt.Tx, cmd *raft.Log, block *metastorev1.BlockMeta) error {
+	return e.record(tx, cmd, blockEvents(metastorev1.EventType_EVENT_TYPE_BLOCK_ADDED, block)...)
+}
+
+// BlockQuarantined records that the block has been quarantined.
+func (e *Events) BlockQuarantined(tx *bbolt.Tx, cmd *raft.Log, block *metastorev1.BlockMeta) error {
+	events := blockEvents(metastorev1.EventType_EVENT_TYPE_BLOCK_QUARANTINED, block)
+	for _, x := range events {
+		x.Reason = block.Quarantine.GetReason()
+		x.ReportedBy = block.Quarantine.GetReportedBy()
+	}
+	return e.record(tx, cmd, events...)
+}
+
+// BlocksCompacted records that the source blocks of the job have been
+// replaced with the compacted ones. Level 0 blocks include data of many
+// tenants: an event is recorded for every tenant of the compacted blocks,
+// and only lists the source blocks that include the tenant data, if the
+// metadata of the source blocks is provided.
+func (e *Events) BlocksCompacted(
+	tx *bbolt.Tx,
+	cmd *raft.Log,
+	job *raft_log.CompletedCompactionJob,
+	source []*metastorev1.BlockMeta,
+) error {
+	compacted := job.GetCompactedBlocks()
+	if compacted == nil || compacted.SourceBlocks == nil {
+		return nil
+	}
+	newEvent := func(tenant string) *metastorev1.Event {
+		return &metastorev1.Event{
+			Type:            metastorev1.EventType_EVENT_TYPE_BLOCKS_COMPACTED,
+			Tenant:          tenant,
+			Shard:           compacted.SourceBlocks.Shard,
+			CompactionLevel: job.State.GetCompactionLevel(),
+			CompactionJob:   job.State.GetName(),
+		}
+	}
+	var events []*metastorev1.Event
+	byTenant := make(map[string]*metastorev1.Event)
+	for _, b := range compacted.NewBlocks {
+		for _, ds := range b.Datasets {
+			x, ok := byTenant[ds.TenantId]
+			if !ok {
+				x = newEvent(ds.TenantId)
+				byTenant[ds.TenantId] = x
+				events = append(events, x)
+			}
+			if !slices.Contains(x.CompactedBlocks, b.Id) {
+				x.CompactedBlocks = append(x.CompactedBlocks, b.Id)
+			}
+			if !slices.Contains(x.Services, ds.Name) {
+				x.Services = append(x.Services, ds.Name)
+			}
+		}
+	}
+	if len(events) == 0 {
+		// All the data has been removed, e.g., by a label rewrite job.
+		events = append(events, newEvent(compacted.SourceBlocks.Tenant))
+	}
+	for _, x := range events {
+		x.Blocks = sourceBlocks(x.Tenant, compacted.SourceBlocks.Blocks, source)
+	}
+	return e.record(tx, cmd, events...)
+}
+
+func sourceBlocks(tenant string, blocks []string, source []*metastorev1.BlockMeta) []string {
+	if len(source) == 0 {
+		return blocks
+	}
+	var tenantBlocks []string
+	for _, b := range source {
+		if b.TenantId == tenant || slices.ContainsFunc(b.Datasets, func(ds *metastorev1.Dataset) bool {
+			return ds.TenantId == tenant
+		}) {
+			tenantBlocks = append(tenantBlocks, b.Id)
+		}
+	}
+	return tenantBlocks
+}
+
+// BlocksDeleted records that the blocks are to be deleted from the
+// object storage. The tombstones of level 0 blocks are not attributed
+// to a tenant: the events can be linked with the compaction events by
+// the job name.
+func (e *Events) BlocksDeleted(tx *bbolt.Tx, cmd *raft.Log, tombstones ...*metastorev1.Tombstones) error {
+	events := make([]*metastorev1.Event, 0, len(tombstones))
+	for _, t := range tombstones {
+		if t.Blocks == nil {
+			continue
+		}
+		events = append(events, &metastorev1.Event{
+			Type:            metastorev1.EventType_EVENT_TYPE_BLOCKS_DELETED,
+			Tenant:          t.Blocks.Tenant,
+			Shard:           t.Blocks.Shard,
+			CompactionLevel: t.Blocks.CompactionLevel,
+			Blocks:          t.Blocks.Blocks,
+			CompactionJob:   t.Blocks.Name,
+		})
+	}
+	return e.record(tx, cmd, events...)
+}
+
+// RetentionApplied records that the blocks and the archived index
+// partitions have been deleted because of the retention policy. An
+// event is recorded for every tenant of an archived partition: the
+// blocks are listed in the partition archive.
+func (e *Events) RetentionApplied(
+	tx *bbolt.Tx,
+	cmd *raft.Log,
+	tombstones []*metastorev1.Tombstones,
+	archived []*raft_log.ArchivedPartition,
+) error {
+	events := make([]*metastorev1.Event, 0, len(tombstones))
+	for _, t := range tombstones {
+		if t.Blocks == nil {
+			continue
+		}
+		events = append(events, &metastorev1.Event{
+			Type:            metastorev1.EventType_EVENT_TYPE_RETENTION_APPLIED,
+			Tenant:          t.Blocks.Tenant,
+			Shard:           t.Blocks.Shard,
+			CompactionLevel: t.Blocks.CompactionLevel,
+			Blocks:          t.Blocks.Blocks,
+		})
+	}
+	for _, p := range archived {
+		for _, tenant := range p.Tenants {
+			events = append(events, &metastorev1.Event{
+				Type:      metastorev1.EventType_EVENT_TYPE_RETENTION_APPLIED,
+				Tenant:    tenant,
+				Partition: p.Key,
+			})
+		}
+	}
+	return e.record(tx, cmd, events...)
+}
+
+func blockEvents(typ metastorev1.EventType, block *metastorev1.BlockMeta) []*metastorev1.Event {
+	var events []*metastorev1.Event
+	for _, ds := range block.Datasets {
+		i := slices.IndexFunc(events, func(x *metastorev1.Event) bool { return x.Tenant == ds.TenantId })
+		if i < 0 {
+			i = len(events)
+			events = append(events, &metastorev1.Event{
+				Type:            typ,
+				Tenant:          ds.TenantId,
+				Shard:           block.Shard,
+				CompactionLevel: block.CompactionLevel,
+				Blocks:          []string{block.Id},
+			})
+		}
+		if !slices.Contains(events[i].Services, ds.Name) {
+			events[i].Services = append(events[i].Services, ds.Name)
+		}
+	}
+	if len(events) == 0 {
+		events = append(events, &metastorev1.Event{
+			Type:            typ,
+			Tenant:          block.TenantId,
+			Shard:           block.Shard,
+			CompactionLevel: block.CompactionLevel,
+			Blocks:          []string{block.Id},
+		})
+	}
+	return events
+}
+
+func (e *Events) record(tx *bbolt.Tx, cmd *raft.Log, events ...*metastorev1.Event) error {
+	if e.config.RetentionPeriod <= 0 || len(events) == 0 {
+		return nil
+	}
+	if cmd.Index != e.index {
+		e.index = cmd.Index
+		e.ordinal = 0
+	}
+	now := cmd.AppendedAt.UnixMilli()
+	for _, x := range events {
+		x.Timestamp = now
+		if err := e.store.StoreEvent(tx, cmd.Index, e.ordinal, x); err != nil {
+			return err
+		}
+		e.ordinal++
+	}
+	// The time of the last truncation is stored along with the events,
+	// so that the replicas delete the events at the same commands.
+	if now-e.store.TruncatedAt(tx) < truncationInterval.Milliseconds() {
+		return nil
+	}
+	if err := e.store.DeleteEvents(tx, now-e.config.RetentionPeriod.Milliseconds()); err != nil {
+		return err
+	}
+	return e.store.SetTruncatedAt(tx, now)
+}
+
+// QueryEvents returns the events that match the query, ordered by time.
+// It only accesses the store and therefore can be used for reads.
+func (e *Events) QueryEvents(tx *bbolt.Tx, req *metastorev1.QueryEventsRequest) ([]*metastorev1.Event, error) {
+	var result []*metastorev1.Event
+	for _, tenant := range req.Tenant {
+		events, err := e.store.ListEvents(tx, tenant, max(req.StartTime, 0), req.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range events {
+			if len(req.Type) > 0 && !slices.Contains(req.Type, x.Type) {
+				continue
+			}
+			if len(x.Services) > 0 && len(req.ServiceName) > 0 && !slices.ContainsFunc(x.Services, func(s string) bool {
+				return slices.Contains(req.ServiceName, s)
+			}) {
+				continue
+			}
+			result = append(result, x)
+		}
+	}
+	if len(req.Tenant) > 1 {
+		slices.SortStableFunc(result, func(a, b *metastorev1.Event) int {
+			return cmp.Compare(a.Timestamp, b.Timestamp)
+		})
+	}
+	if req.Limit > 0 && len(result) > int(req.Limit) {
+		result = result[len(result)-int(req.Limit):]
+	}
+	return result, nil
+}
+
+func (e *Events) Init(tx *bbolt.Tx) error {
+	return e.store.CreateBuckets(tx)
+}
+
+func (e *Events) Restore(*bbolt.Tx) error { return nil }
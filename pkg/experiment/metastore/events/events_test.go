@@ -0,0 +1,164 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func segment(id string, datasets ...*metastorev1.Dataset) *metastorev1.BlockMeta {
+	return &metastorev1.BlockMeta{Id: id, Shard: 1, Datasets: datasets}
+}
+
+func dataset(tenant, service string) *metastorev1.Dataset {
+	return &metastorev1.Dataset{TenantId: tenant, Name: service}
+}
+
+func TestEvents(t *testing.T) {
+	db := test.BoltDB(t)
+	e := NewEvents(Config{RetentionPeriod: 24 * time.Hour}, NewStore())
+	update := func(fn func(*bbolt.Tx) error) {
+		tx, err := db.Begin(true)
+		require.NoError(t, err)
+		require.NoError(t, fn(tx))
+		require.NoError(t, tx.Commit())
+	}
+	query := func(req *metastorev1.QueryEventsRequest) []*metastorev1.Event {
+		tx, err := db.Begin(false)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Rollback())
+		}()
+		events, err := e.QueryEvents(tx, req)
+		require.NoError(t, err)
+		return events
+	}
+
+	a := segment("a", dataset("tenant-1", "foo"), dataset("tenant-1", "bar"), dataset("tenant-2", "foo"))
+	b := segment("b", dataset("tenant-1", "bar"))
+	c := &metastorev1.BlockMeta{Id: "c", Shard: 1, TenantId: "tenant-1", CompactionLevel: 1, Datasets: []*metastorev1.Dataset{
+		dataset("tenant-1", "foo"), dataset("tenant-1", "bar"),
+	}}
+	d := &metastorev1.BlockMeta{Id: "d", Shard: 1, TenantId: "tenant-2", CompactionLevel: 1, Datasets: []*metastorev1.Dataset{
+		dataset("tenant-2", "foo"),
+	}}
+
+	update(e.Init)
+	update(func(tx *bbolt.Tx) error {
+		cmd := &raft.Log{Index: 1, AppendedAt: time.UnixMilli(1000)}
+		require.NoError(t, e.BlockAdded(tx, cmd, a))
+		return e.BlockAdded(tx, cmd, b)
+	})
+	update(func(tx *bbolt.Tx) error {
+		cmd := &raft.Log{Index: 2, AppendedAt: time.UnixMilli(2000)}
+		b.Quarantine = &metastorev1.BlockQuarantine{Reason: "corrupted", ReportedBy: "query-backend"}
+		return e.BlockQuarantined(tx, cmd, b)
+	})
+	update(func(tx *bbolt.Tx) error {
+		cmd := &raft.Log{Index: 3, AppendedAt: time.UnixMilli(3000)}
+		return e.BlocksCompacted(tx, cmd, &raft_log.CompletedCompactionJob{
+			State: &raft_log.CompactionJobState{Name: "job", CompactionLevel: 0},
+			CompactedBlocks: &metastorev1.CompactedBlocks{
+				SourceBlocks: &metastorev1.BlockList{Shard: 1, Blocks: []string{"a", "b"}},
+				NewBlocks:    []*metastorev1.BlockMeta{c, d},
+			},
+		}, []*metastorev1.BlockMeta{a, b})
+	})
+	update(func(tx *bbolt.Tx) error {
+		cmd := &raft.Log{Index: 4, AppendedAt: time.UnixMilli(4000)}
+		return e.BlocksDeleted(tx, cmd, &metastorev1.Tombstones{Blocks: &metastorev1.BlockTombstones{
+			Name: "job", Shard: 1, Blocks: []string{"a", "b"},
+		}})
+	})
+
+	expected := []*metastorev1.Event{
+		{Timestamp: 1000, Type: metastorev1.EventType_EVENT_TYPE_BLOCK_ADDED, Tenant: "tenant-1", Shard: 1, Blocks: []string{"a"}, Services: []string{"foo", "bar"}},
+		{Timestamp: 1000, Type: metastorev1.EventType_EVENT_TYPE_BLOCK_ADDED, Tenant: "tenant-1", Shard: 1, Blocks: []string{"b"}, Services: []string{"bar"}},
+		{
+			Timestamp: 2000, Type: metastorev1.EventType_EVENT_TYPE_BLOCK_QUARANTINED, Tenant: "tenant-1", Shard: 1, Blocks: []string{"b"},
+			Services: []string{"bar"}, Reason: "corrupted", ReportedBy: "query-backend",
+		},
+		{
+			Timestamp: 3000, Type: metastorev1.EventType_EVENT_TYPE_BLOCKS_COMPACTED, Tenant: "tenant-1", Shard: 1, Blocks: []string{"a", "b"},
+			CompactedBlocks: []string{"c"}, Services: []string{"foo", "bar"}, CompactionJob: "job",
+		},
+	}
+	assert.Equal(t, expected, query(&metastorev1.QueryEventsRequest{Tenant: []string{"tenant-1"}, EndTime: 5000}))
+	assert.Equal(t, expected[2:3], query(&metastorev1.QueryEventsRequest{
+		Tenant: []string{"tenant-1"}, EndTime: 5000, Type: []metastorev1.EventType{metastorev1.EventType_EVENT_TYPE_BLOCK_QUARANTINED},
+	}))
+	assert.Equal(t, []*metastorev1.Event{expected[0], expected[3]}, query(&metastorev1.QueryEventsRequest{
+		Tenant: []string{"tenant-1"}, EndTime: 5000, ServiceName: []string{"foo"},
+	}))
+	assert.Equal(t, expected[3:], query(&metastorev1.QueryEventsRequest{Tenant: []string{"tenant-1"}, EndTime: 5000, Limit: 1}))
+	assert.Equal(t, expected[2:3], query(&metastorev1.QueryEventsRequest{Tenant: []string{"tenant-1"}, StartTime: 1500, EndTime: 2500}))
+
+	tenant2 := query(&metastorev1.QueryEventsRequest{Tenant: []string{"tenant-2"}, EndTime: 5000})
+	require.Len(t, tenant2, 2)
+	assert.Equal(t, []string{"a"}, tenant2[1].Blocks)
+	assert.Equal(t, []string{"d"}, tenant2[1].CompactedBlocks)
+
+	// Deletion of segments is not attributed to tenants.
+	deleted := query(&metastorev1.QueryEventsRequest{Tenant: []string{""}, EndTime: 5000})
+	require.Len(t, deleted, 1)
+	assert.Equal(t, metastorev1.EventType_EVENT_TYPE_BLOCKS_DELETED, deleted[0].Type)
+	assert.Equal(t, "job", deleted[0].CompactionJob)
+
+	// Events older than the retention period are deleted.
+	update(func(tx *bbolt.Tx) error {
+		cmd := &raft.Log{Index: 5, AppendedAt: time.UnixMilli(3500).Add(24 * time.Hour)}
+		return e.BlockAdded(tx, cmd, segment("e", dataset("tenant-2", "foo")))
+	})
+	assert.Empty(t, query(&metastorev1.QueryEventsRequest{Tenant: []string{"tenant-1"}, EndTime: 5000}))
+	assert.Len(t, query(&metastorev1.QueryEventsRequest{Tenant: []string{""}, EndTime: 5000}), 1)
+	assert.Len(t, query(&metastorev1.QueryEventsRequest{Tenant: []string{"tenant-2"}, EndTime: time.Now().UnixMilli()}), 1)
+}
+
+func TestEvents_RetentionApplied(t *testing.T) {
+	db := test.BoltDB(t)
+	e := NewEvents(Config{RetentionPeriod: 24 * time.Hour}, NewStore())
+	require.NoError(t, db.Update(e.Init))
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		cmd := &raft.Log{Index: 1, AppendedAt: time.UnixMilli(1000)}
+		return e.RetentionApplied(tx, cmd,
+			[]*metastorev1.Tombstones{{Blocks: &metastorev1.BlockTombstones{
+				Name: "retention-1-1-tenant-1-1", Shard: 1, Tenant: "tenant-1", CompactionLevel: 1, Blocks: []string{"a", "b"},
+			}}},
+			[]*raft_log.ArchivedPartition{{Key: "20240923T08.1h", Tenants: []string{"", "tenant-1"}}},
+		)
+	}))
+
+	var events []*metastorev1.Event
+	require.NoError(t, db.View(func(tx *bbolt.Tx) (err error) {
+		events, err = e.QueryEvents(tx, &metastorev1.QueryEventsRequest{Tenant: []string{"", "tenant-1"}, EndTime: 2000})
+		return err
+	}))
+	typ := metastorev1.EventType_EVENT_TYPE_RETENTION_APPLIED
+	assert.ElementsMatch(t, []*metastorev1.Event{
+		{Timestamp: 1000, Type: typ, Tenant: "tenant-1", Shard: 1, CompactionLevel: 1, Blocks: []string{"a", "b"}},
+		{Timestamp: 1000, Type: typ, Partition: "20240923T08.1h"},
+		{Timestamp: 1000, Type: typ, Tenant: "tenant-1", Partition: "20240923T08.1h"},
+	}, events)
+}
+
+func TestEvents_Disabled(t *testing.T) {
+	db := test.BoltDB(t)
+	e := NewEvents(Config{}, NewStore())
+	tx, err := db.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, e.Init(tx))
+	cmd := &raft.Log{Index: 1, AppendedAt: time.UnixMilli(1000)}
+	require.NoError(t, e.BlockAdded(tx, cmd, segment("a", dataset("tenant", "foo"))))
+	events, err := e.QueryEvents(tx, &metastorev1.QueryEventsRequest{Tenant: []string{"tenant"}, EndTime: 5000})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	require.NoError(t, tx.Rollback())
+}
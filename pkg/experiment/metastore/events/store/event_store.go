@@ -0,0 +1,151 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+var (
+	eventBucketName     = []byte("event")
+	eventMetaBucketName = []byte("event_meta")
+	truncatedAtKey      = []byte("truncated_at")
+)
+
+var ErrInvalidEvent = errors.New("invalid event entry")
+
+// EventStore keeps events in a bucket per tenant. Keys are ordered by
+// the event time, followed by the raft log index and the ordinal number
+// of the event within the command, which makes them unique.
+type EventStore struct {
+	bucketName     []byte
+	metaBucketName []byte
+}
+
+func NewEventStore() *EventStore {
+	return &EventStore{
+		bucketName:     eventBucketName,
+		metaBucketName: eventMetaBucketName,
+	}
+}
+
+func (s EventStore) CreateBuckets(tx *bbolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(s.bucketName); err != nil {
+		return err
+	}
+	_, err := tx.CreateBucketIfNotExists(s.metaBucketName)
+	return err
+}
+
+func (s EventStore) StoreEvent(tx *bbolt.Tx, index uint64, ordinal uint32, e *metastorev1.Event) error {
+	bucket, err := tx.Bucket(s.bucketName).CreateBucketIfNotExists(tenantBucketName(e.Tenant))
+	if err != nil {
+		return err
+	}
+	v, _ := e.MarshalVT()
+	return bucket.Put(eventKey(e.Timestamp, index, ordinal), v)
+}
+
+// ListEvents returns the events of the tenant that occurred
+// within the time range (inclusive), in the order of their time.
+func (s EventStore) ListEvents(tx *bbolt.Tx, tenant string, start, end int64) ([]*metastorev1.Event, error) {
+	bucket := tx.Bucket(s.bucketName).Bucket(tenantBucketName(tenant))
+	if bucket == nil {
+		return nil, nil
+	}
+	var events []*metastorev1.Event
+	c := bucket.Cursor()
+	for k, v := c.Seek(eventKey(start, 0, 0)); k != nil; k, v = c.Next() {
+		ts, err := eventTime(k)
+		if err != nil {
+			return nil, err
+		}
+		if ts > end {
+			break
+		}
+		var e metastorev1.Event
+		if err = e.UnmarshalVT(v); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		events = append(events, &e)
+	}
+	return events, nil
+}
+
+// DeleteEvents deletes the events of all tenants that occurred before
+// the given time. Buckets of tenants with no events left are removed.
+func (s EventStore) DeleteEvents(tx *bbolt.Tx, before int64) error {
+	root := tx.Bucket(s.bucketName)
+	var empty [][]byte
+	err := root.ForEachBucket(func(tenant []byte) error {
+		bucket := root.Bucket(tenant)
+		c := bucket.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.First() {
+			ts, err := eventTime(k)
+			if err != nil {
+				return err
+			}
+			if ts >= before {
+				return nil
+			}
+			if err = c.Delete(); err != nil {
+				return err
+			}
+		}
+		empty = append(empty, tenant)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, tenant := range empty {
+		if err = root.DeleteBucket(tenant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TruncatedAt returns the time of the last DeleteEvents call
+// recorded with SetTruncatedAt, or zero, if there were none.
+func (s EventStore) TruncatedAt(tx *bbolt.Tx) int64 {
+	v := tx.Bucket(s.metaBucketName).Get(truncatedAtKey)
+	if len(v) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(v))
+}
+
+func (s EventStore) SetTruncatedAt(tx *bbolt.Tx, t int64) error {
+	return tx.Bucket(s.metaBucketName).Put(truncatedAtKey, binary.BigEndian.AppendUint64(nil, uint64(t)))
+}
+
+// Events of level 0 blocks may not be attributed to a tenant,
+// while bucket names can not be empty.
+var noTenantBucketName = []byte{0}
+
+func tenantBucketName(tenant string) []byte {
+	if tenant == "" {
+		return noTenantBucketName
+	}
+	return []byte(tenant)
+}
+
+func eventKey(ts int64, index uint64, ordinal uint32) []byte {
+	k := make([]byte, 20)
+	binary.BigEndian.PutUint64(k[0:8], uint64(ts))
+	binary.BigEndian.PutUint64(k[8:16], index)
+	binary.BigEndian.PutUint32(k[16:20], ordinal)
+	return k
+}
+
+func eventTime(k []byte) (int64, error) {
+	if len(k) != 20 {
+		return 0, fmt.Errorf("%w: malformed key", ErrInvalidEvent)
+	}
+	return int64(binary.BigEndian.Uint64(k)), nil
+}
@@ -0,0 +1,312 @@
+// Package external implements registration of blocks produced out-of-band
+// by external pipelines, e.g., batch jobs converting profiles collected by
+// other means.
+//
+// The pipeline uploads the block object to its regular location in the
+// storage bucket, and then the block metadata, in the JSON representation
+// of metastorev1.BlockMeta, to the watched prefix:
+//
+//	<prefix><tenant>/<block_id>.json
+//
+// The metadata is validated against the block object and registered with
+// the metastore. The metadata object is deleted once the block has been
+// registered, or if the block can't be registered at all. The watched
+// prefix is checked periodically; the check can also be triggered by the
+// bucket event notifications pushed to the notification handler.
+package external
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+const metadataFileExtension = ".json"
+
+type Config struct {
+	Prefix        string        `yaml:"external_blocks_prefix"`
+	CheckInterval time.Duration `yaml:"external_blocks_check_interval"`
+}
+
+func (c *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&c.Prefix, prefix+"external-blocks-prefix", "", "Prefix in the storage bucket watched for the metadata of the blocks produced by external pipelines. The metadata is uploaded as <prefix><tenant>/<block_id>.json, once the block object is uploaded to its location. Empty to disable the registration of external blocks.")
+	f.DurationVar(&c.CheckInterval, prefix+"external-blocks-check-interval", time.Minute, "How often the external blocks prefix is checked for new block metadata.")
+}
+
+func (c *Config) Validate() error {
+	if c.Prefix != "" && !strings.HasSuffix(c.Prefix, "/") {
+		return errors.New("external blocks prefix must end with '/'")
+	}
+	if c.Prefix != "" && c.CheckInterval <= 0 {
+		return errors.New("external blocks check interval must be positive")
+	}
+	return nil
+}
+
+type LocalServer interface {
+	AddExternalBlock(context.Context, *metastorev1.AddBlockRequest) (*metastorev1.AddBlockResponse, error)
+}
+
+const (
+	resultRegistered = "registered"
+	resultRejected   = "rejected"
+	resultFailed     = "failed"
+)
+
+// Watcher registers the external blocks. It only runs on the raft leader.
+type Watcher struct {
+	config    Config
+	logger    log.Logger
+	metastore LocalServer
+	bucket    objstore.Bucket
+	blocks    *prometheus.CounterVec
+
+	m       sync.Mutex
+	started bool
+	ctx     context.Context
+	cancel  func()
+	// Registrations are serialized: the
+	// same block may be notified repeatedly.
+	register sync.Mutex
+}
+
+func NewWatcher(logger log.Logger, config Config, metastore LocalServer, bucket objstore.Bucket, reg prometheus.Registerer) *Watcher {
+	return &Watcher{
+		config:    config,
+		logger:    logger,
+		metastore: metastore,
+		bucket:    bucket,
+		blocks:    newBlocksCounter(reg),
+	}
+}
+
+func RegisterMetrics(reg prometheus.Registerer) { newBlocksCounter(reg) }
+
+func newBlocksCounter(reg prometheus.Registerer) *prometheus.CounterVec {
+	return util.RegisterOrGet(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "external_blocks_total",
+		Help: "The total number of external blocks processed, by result: registered, rejected, or failed (to be retried).",
+	}, []string{"result"}))
+}
+
+func (w *Watcher) Start() {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.config.Prefix == "" || w.started {
+		return
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.started = true
+	go w.watch(w.ctx)
+	level.Info(w.logger).Log("msg", "external blocks watcher started", "prefix", w.config.Prefix)
+}
+
+func (w *Watcher) Stop() {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if !w.started {
+		return
+	}
+	w.cancel()
+	w.started = false
+	level.Info(w.logger).Log("msg", "external blocks watcher stopped")
+}
+
+// context returns the context of the watcher, if it is running.
+func (w *Watcher) context() (context.Context, bool) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return w.ctx, w.started
+}
+
+func (w *Watcher) watch(ctx context.Context) {
+	ticker := time.NewTicker(w.config.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx)
+		}
+	}
+}
+
+func (w *Watcher) check(ctx context.Context) {
+	err := w.bucket.Iter(ctx, w.config.Prefix, func(path string) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !strings.HasSuffix(path, metadataFileExtension) {
+			return nil
+		}
+		return w.registerBlock(ctx, path)
+	}, objstore.WithRecursiveIter)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		level.Error(w.logger).Log("msg", "failed to iterate over external blocks", "err", err)
+	}
+}
+
+// registerBlock registers the block with the metadata stored at the given
+// path. Only the raft leadership errors are returned: other failures are
+// retried on the next check.
+func (w *Watcher) registerBlock(ctx context.Context, path string) error {
+	w.register.Lock()
+	defer w.register.Unlock()
+	logger := log.With(w.logger, "path", path)
+	meta, err := w.readMeta(ctx, path)
+	switch {
+	case err == nil:
+	case w.bucket.IsObjNotFoundErr(err):
+		// Already processed.
+		return nil
+	case errors.Is(err, errInvalidMetadata):
+		level.Warn(logger).Log("msg", "rejecting external block", "err", err)
+		w.blocks.WithLabelValues(resultRejected).Inc()
+		w.delete(ctx, logger, path)
+		return nil
+	default:
+		level.Error(logger).Log("msg", "failed to read external block metadata", "err", err)
+		w.blocks.WithLabelValues(resultFailed).Inc()
+		return nil
+	}
+
+	if _, err = w.metastore.AddExternalBlock(ctx, &metastorev1.AddBlockRequest{Block: meta}); err != nil {
+		if raftnode.IsRaftLeadershipError(err) {
+			return err
+		}
+		switch status.Code(err) {
+		case codes.AlreadyExists, codes.InvalidArgument:
+			// The block will never be added.
+			level.Warn(logger).Log("msg", "rejecting external block", "block_id", meta.Id, "err", err)
+			w.blocks.WithLabelValues(resultRejected).Inc()
+			w.delete(ctx, logger, path)
+		default:
+			level.Error(logger).Log("msg", "failed to add external block", "block_id", meta.Id, "err", err)
+			w.blocks.WithLabelValues(resultFailed).Inc()
+		}
+		return nil
+	}
+
+	level.Info(logger).Log("msg", "external block registered", "block_id", meta.Id, "tenant", meta.TenantId)
+	w.blocks.WithLabelValues(resultRegistered).Inc()
+	w.delete(ctx, logger, path)
+	return nil
+}
+
+func (w *Watcher) delete(ctx context.Context, logger log.Logger, path string) {
+	if err := w.bucket.Delete(ctx, path); err != nil && !w.bucket.IsObjNotFoundErr(err) {
+		level.Error(logger).Log("msg", "failed to delete external block metadata", "err", err)
+	}
+}
+
+var errInvalidMetadata = errors.New("invalid external block metadata")
+
+func invalidMetadata(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", errInvalidMetadata, fmt.Sprintf(format, args...))
+}
+
+func (w *Watcher) readMeta(ctx context.Context, path string) (*metastorev1.BlockMeta, error) {
+	tenant, blockID, ok := parsePath(w.config.Prefix, path)
+	if !ok {
+		return nil, invalidMetadata("unexpected path, expected %s<tenant>/<block_id>%s", w.config.Prefix, metadataFileExtension)
+	}
+	r, err := w.bucket.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	meta := new(metastorev1.BlockMeta)
+	if err = protojson.Unmarshal(b, meta); err != nil {
+		return nil, invalidMetadata("%v", err)
+	}
+	if err = validateMeta(meta, tenant, blockID); err != nil {
+		return nil, err
+	}
+	attrs, err := w.bucket.Attributes(ctx, block.ObjectPath(meta))
+	if err != nil {
+		if w.bucket.IsObjNotFoundErr(err) {
+			return nil, invalidMetadata("block object %s not found", block.ObjectPath(meta))
+		}
+		return nil, err
+	}
+	if uint64(attrs.Size) != meta.Size {
+		return nil, invalidMetadata("block object size %d does not match the metadata: %d", attrs.Size, meta.Size)
+	}
+	return meta, nil
+}
+
+func parsePath(prefix, path string) (tenant, blockID string, ok bool) {
+	name, ok := strings.CutPrefix(path, prefix)
+	if !ok {
+		return "", "", false
+	}
+	name, ok = strings.CutSuffix(name, metadataFileExtension)
+	if !ok {
+		return "", "", false
+	}
+	tenant, blockID, ok = strings.Cut(name, "/")
+	if !ok || tenant == "" || strings.Contains(blockID, "/") {
+		return "", "", false
+	}
+	return tenant, blockID, true
+}
+
+func validateMeta(meta *metastorev1.BlockMeta, tenant, blockID string) error {
+	if _, err := ulid.Parse(meta.Id); err != nil {
+		return invalidMetadata("invalid block id %q: %v", meta.Id, err)
+	}
+	if meta.Id != blockID {
+		return invalidMetadata("block id %s does not match the path", meta.Id)
+	}
+	if meta.TenantId != tenant {
+		return invalidMetadata("tenant %q does not match the path", meta.TenantId)
+	}
+	// Segments (level 0) are only produced by the segment writers.
+	if meta.CompactionLevel == 0 {
+		return invalidMetadata("compaction level must be positive")
+	}
+	if meta.MinTime > meta.MaxTime {
+		return invalidMetadata("min time is after max time")
+	}
+	if len(meta.Datasets) == 0 {
+		return invalidMetadata("block has no datasets")
+	}
+	for _, ds := range meta.Datasets {
+		if ds.TenantId != meta.TenantId {
+			return invalidMetadata("dataset %q belongs to another tenant: %q", ds.Name, ds.TenantId)
+		}
+		if ds.MinTime < meta.MinTime || ds.MaxTime > meta.MaxTime {
+			return invalidMetadata("time range of dataset %q exceeds the block time range", ds.Name)
+		}
+		for _, offset := range ds.TableOfContents {
+			if offset >= meta.Size {
+				return invalidMetadata("section of dataset %q is out of the block object bounds", ds.Name)
+			}
+		}
+	}
+	return nil
+}
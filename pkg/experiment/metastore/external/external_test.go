@@ -0,0 +1,203 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/memory"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockexternal"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+const testPrefix = "external/"
+
+func newBlockMeta(tenant string) *metastorev1.BlockMeta {
+	return &metastorev1.BlockMeta{
+		FormatVersion:   1,
+		Id:              ulid.MustNew(ulid.Now(), rand.Reader).String(),
+		TenantId:        tenant,
+		Shard:           1,
+		CompactionLevel: 1,
+		MinTime:         10,
+		MaxTime:         20,
+		Size:            100,
+		Datasets: []*metastorev1.Dataset{{
+			TenantId:        tenant,
+			Name:            "service",
+			MinTime:         10,
+			MaxTime:         20,
+			TableOfContents: []uint64{0, 40, 80},
+			Size:            100,
+		}},
+	}
+}
+
+func addBlock(t *testing.T, bucket *memory.InMemBucket, meta *metastorev1.BlockMeta, objectSize int) string {
+	t.Helper()
+	bucket.Set(block.ObjectPath(meta), make([]byte, objectSize))
+	path := testPrefix + meta.TenantId + "/" + meta.Id + ".json"
+	bucket.Set(path, mustMarshal(t, meta))
+	return path
+}
+
+func newTestWatcher(srv LocalServer, bucket *memory.InMemBucket) *Watcher {
+	return NewWatcher(util.Logger, Config{Prefix: testPrefix}, srv, bucket, prometheus.NewRegistry())
+}
+
+func Test_Watcher_check(t *testing.T) {
+	bucket := memory.NewInMemBucket()
+	valid := newBlockMeta("tenant-a")
+	addBlock(t, bucket, valid, 100)
+
+	sizeMismatch := newBlockMeta("tenant-a")
+	addBlock(t, bucket, sizeMismatch, 10)
+
+	segment := newBlockMeta("tenant-a")
+	segment.CompactionLevel = 0
+	addBlock(t, bucket, segment, 100)
+
+	wrongTenant := newBlockMeta("tenant-b")
+	bucket.Set(testPrefix+"tenant-a/"+wrongTenant.Id+".json", mustMarshal(t, wrongTenant))
+
+	missingObject := newBlockMeta("tenant-a")
+	bucket.Set(testPrefix+"tenant-a/"+missingObject.Id+".json", mustMarshal(t, missingObject))
+
+	bucket.Set(testPrefix+"tenant-a/malformed.json", []byte("{"))
+	// Objects without the metadata extension are ignored.
+	bucket.Set(testPrefix+"tenant-a/README", []byte("hello"))
+
+	srv := mockexternal.NewMockLocalServer(t)
+	srv.On("AddExternalBlock", mock.Anything, mock.Anything).
+		Once().
+		Run(func(args mock.Arguments) {
+			assert.Equal(t, valid.Id, args.Get(1).(*metastorev1.AddBlockRequest).Block.Id)
+		}).
+		Return(&metastorev1.AddBlockResponse{}, nil)
+
+	w := newTestWatcher(srv, bucket)
+	w.check(context.Background())
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(w.blocks.WithLabelValues(resultRegistered)))
+	assert.Equal(t, float64(5), testutil.ToFloat64(w.blocks.WithLabelValues(resultRejected)))
+	// All the metadata objects have been processed.
+	for path := range bucket.Objects() {
+		assert.NotContains(t, path, ".json")
+	}
+	assert.Contains(t, bucket.Objects(), testPrefix+"tenant-a/README")
+	assert.Contains(t, bucket.Objects(), block.ObjectPath(valid))
+}
+
+func Test_Watcher_retry(t *testing.T) {
+	bucket := memory.NewInMemBucket()
+	meta := newBlockMeta("tenant")
+	path := addBlock(t, bucket, meta, 100)
+
+	srv := mockexternal.NewMockLocalServer(t)
+	srv.On("AddExternalBlock", mock.Anything, mock.Anything).
+		Once().
+		Return(nil, status.Error(codes.Unavailable, "unavailable"))
+	srv.On("AddExternalBlock", mock.Anything, mock.Anything).
+		Once().
+		Return(nil, status.Error(codes.AlreadyExists, "exists"))
+
+	w := newTestWatcher(srv, bucket)
+	w.check(context.Background())
+	assert.Contains(t, bucket.Objects(), path)
+	assert.Equal(t, float64(1), testutil.ToFloat64(w.blocks.WithLabelValues(resultFailed)))
+
+	w.check(context.Background())
+	assert.NotContains(t, bucket.Objects(), path)
+	assert.Equal(t, float64(1), testutil.ToFloat64(w.blocks.WithLabelValues(resultRejected)))
+}
+
+func Test_Watcher_NotificationHandler(t *testing.T) {
+	bucket := memory.NewInMemBucket()
+	meta := newBlockMeta("tenant")
+	path := addBlock(t, bucket, meta, 100)
+
+	srv := mockexternal.NewMockLocalServer(t)
+	srv.On("AddExternalBlock", mock.Anything, mock.Anything).
+		Once().
+		Return(&metastorev1.AddBlockResponse{}, nil)
+
+	w := newTestWatcher(srv, bucket)
+	notify := func(body string) int {
+		rec := httptest.NewRecorder()
+		w.NotificationHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)))
+		return rec.Code
+	}
+	notification := `{"message":{"attributes":{"eventType":"OBJECT_FINALIZE","objectId":"` + path + `"}}}`
+
+	// Followers don't handle notifications.
+	assert.Equal(t, http.StatusServiceUnavailable, notify(notification))
+	assert.Contains(t, bucket.Objects(), path)
+
+	// The watcher is started on the leader. The prefix
+	// is not checked in the background during the test.
+	w.config.CheckInterval = time.Hour
+	w.Start()
+	defer w.Stop()
+	assert.Equal(t, http.StatusBadRequest, notify("{"))
+	assert.Equal(t, http.StatusNoContent, notify(notification))
+	assert.NotContains(t, bucket.Objects(), path)
+	// Repeated notifications are ignored.
+	assert.Equal(t, http.StatusNoContent, notify(notification))
+}
+
+func Test_parseNotification(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+		keys []string
+	}{
+		{
+			name: "gcs",
+			body: `{"message":{"attributes":{"eventType":"OBJECT_FINALIZE","objectId":"external/t/b.json"},"data":"e30="},"subscription":"s"}`,
+			keys: []string{"external/t/b.json"},
+		},
+		{
+			name: "gcs object deleted",
+			body: `{"message":{"attributes":{"eventType":"OBJECT_DELETE","objectId":"external/t/b.json"}}}`,
+		},
+		{
+			name: "s3",
+			body: `{"Records":[{"eventName":"ObjectCreated:Put","s3":{"object":{"key":"external/t/a%3Db.json"}}},{"eventName":"ObjectRemoved:Delete","s3":{"object":{"key":"external/t/c.json"}}}]}`,
+			keys: []string{"external/t/a=b.json"},
+		},
+		{
+			name: "sns",
+			body: `{"Type":"Notification","Message":"{\"Records\":[{\"eventName\":\"ObjectCreated:Put\",\"s3\":{\"object\":{\"key\":\"external/t/b.json\"}}}]}"}`,
+			keys: []string{"external/t/b.json"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := parseNotification([]byte(tc.body))
+			require.NoError(t, err)
+			assert.Equal(t, tc.keys, keys)
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, meta *metastorev1.BlockMeta) []byte {
+	t.Helper()
+	data, err := protojson.Marshal(meta)
+	require.NoError(t, err)
+	return data
+}
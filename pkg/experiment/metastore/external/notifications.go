@@ -0,0 +1,109 @@
+package external
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/log/level"
+)
+
+const maxNotificationSize = 1 << 20
+
+// NotificationHandler handles the bucket event notifications: the
+// metadata objects created under the watched prefix are registered
+// immediately, without waiting for the next check. The following
+// notification formats are supported:
+//   - Google Cloud Storage notifications, delivered by a Pub/Sub push subscription.
+//   - Amazon S3 event notifications, either delivered directly (e.g., by
+//     an EventBridge API destination), or wrapped in an SNS notification.
+//     SNS subscriptions are to be confirmed out of band.
+//
+// Notifications can only be handled by the raft leader: other replicas
+// respond with 503, letting the notification service retry the delivery.
+func (w *Watcher) NotificationHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx, ok := w.context()
+		if !ok {
+			http.Error(rw, "external blocks watcher is not running on the instance", http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxNotificationSize))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		keys, err := parseNotification(body)
+		if err != nil {
+			http.Error(rw, "invalid notification: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, key := range keys {
+			if !strings.HasPrefix(key, w.config.Prefix) || !strings.HasSuffix(key, metadataFileExtension) {
+				continue
+			}
+			if err = w.registerBlock(ctx, key); err != nil {
+				level.Warn(w.logger).Log("msg", "failed to handle external block notification", "path", key, "err", err)
+				http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
+
+type notification struct {
+	// Pub/Sub push message.
+	Message *struct {
+		Attributes struct {
+			EventType string `json:"eventType"`
+			ObjectID  string `json:"objectId"`
+		} `json:"attributes"`
+	} `json:"message"`
+
+	// S3 event notification.
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Object struct {
+				Key string `json:"key"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+
+	// SNS notification envelope.
+	Type       string `json:"Type"`
+	SNSMessage string `json:"Message"`
+}
+
+// parseNotification returns the keys of the objects created.
+func parseNotification(body []byte) ([]string, error) {
+	var n notification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, err
+	}
+	var keys []string
+	switch {
+	case n.Message != nil:
+		if n.Message.Attributes.EventType == "OBJECT_FINALIZE" {
+			keys = append(keys, n.Message.Attributes.ObjectID)
+		}
+	case n.Type == "Notification":
+		return parseNotification([]byte(n.SNSMessage))
+	default:
+		for _, r := range n.Records {
+			if !strings.HasPrefix(r.EventName, "ObjectCreated:") {
+				continue
+			}
+			// S3 object keys are URL-encoded in the notifications.
+			key, err := url.QueryUnescape(r.S3.Object.Key)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
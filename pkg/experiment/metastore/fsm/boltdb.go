@@ -16,7 +16,10 @@ import (
 const (
 	boltDBFileName        = "metastore.boltdb"
 	boltDBSnapshotName    = "metastore_snapshot.boltdb"
+	boltDBDefragName      = "metastore_defrag.boltdb"
+	boltDBBackupName      = "metastore_backup.boltdb"
 	boltDBInitialMmapSize = 1 << 30
+	boltDBCompactTxSize   = 64 << 20
 )
 
 type boltdb struct {
@@ -61,8 +64,20 @@ func (db *boltdb) open(readOnly bool) (err error) {
 		db.path = filepath.Join(db.dir, boltDBFileName)
 	}
 
-	opts := *bbolt.DefaultOptions
 	// open is called with readOnly=true to verify the snapshot integrity.
+	opts := options(readOnly)
+	if db.boltdb, err = bbolt.Open(db.path, 0644, opts); err != nil {
+		return fmt.Errorf("failed to open db: %w", err)
+	}
+	if !readOnly {
+		db.stats()
+	}
+
+	return nil
+}
+
+func options(readOnly bool) *bbolt.Options {
+	opts := *bbolt.DefaultOptions
 	opts.ReadOnly = readOnly
 	opts.PreLoadFreelist = !readOnly
 	opts.InitialMmapSize = boltDBInitialMmapSize
@@ -72,11 +87,26 @@ func (db *boltdb) open(readOnly bool) (err error) {
 	opts.NoGrowSync = true
 	opts.NoFreelistSync = true
 	opts.FreelistType = bbolt.FreelistMapType
-	if db.boltdb, err = bbolt.Open(db.path, 0644, &opts); err != nil {
-		return fmt.Errorf("failed to open db: %w", err)
-	}
+	return &opts
+}
 
-	return nil
+// stats returns the size of the database and the size of its free
+// pages, in bytes, and updates the corresponding metrics.
+func (db *boltdb) stats() (size, free int64) {
+	_ = db.boltdb.View(func(tx *bbolt.Tx) error {
+		size = tx.Size()
+		return nil
+	})
+	free = int64(db.boltdb.Stats().FreeAlloc)
+	db.observeSize(size, free)
+	return size, free
+}
+
+func (db *boltdb) observeSize(size, free int64) {
+	db.metrics.boltDBSize.Set(float64(size))
+	if size > 0 {
+		db.metrics.boltDBFreePagesRatio.Set(float64(free) / float64(size))
+	}
 }
 
 func (db *boltdb) shutdown() {
@@ -99,19 +129,12 @@ func (db *boltdb) restore(snapshot io.Reader) error {
 	// it on disk and use it instead of the current database.
 	path, err := db.copySnapshot(snapshot)
 	if err == nil {
-		// First check the snapshot.
-		restored := *db
-		restored.path = path
-		err = restored.open(true)
-		// Also check applied index.
-		restored.shutdown()
+		err = db.replace(path)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to restore snapshot: %w", err)
 	}
-	// Note that we do not keep the previous database: in case if the
-	// snapshot is corrupted, we should try another one.
-	return db.openSnapshot(path)
+	return nil
 }
 
 func (db *boltdb) copySnapshot(snapshot io.Reader) (path string, err error) {
@@ -127,15 +150,82 @@ func (db *boltdb) copySnapshot(snapshot io.Reader) (path string, err error) {
 	return path, err
 }
 
-func (db *boltdb) openSnapshot(path string) (err error) {
-	db.shutdown()
-	if err = os.Rename(path, db.path); err != nil {
+// defragment copies the database into a new file, omitting the free
+// pages, and replaces the database with it. The caller must ensure
+// that there are no transactions in progress.
+func (db *boltdb) defragment() (err error) {
+	start := time.Now()
+	path := filepath.Join(db.dir, boltDBDefragName)
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	compacted, err := bbolt.Open(path, 0644, options(false))
+	if err != nil {
+		return fmt.Errorf("failed to create compacted db: %w", err)
+	}
+	if err = bbolt.Compact(compacted, db.boltdb, boltDBCompactTxSize); err == nil {
+		err = compacted.Sync()
+	}
+	if closeErr := compacted.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return fmt.Errorf("failed to compact db: %w", err)
+	}
+	if err = db.replace(path); err != nil {
 		return err
 	}
-	if err = syncPath(db.path); err != nil {
+	db.metrics.boltDBDefragDuration.Observe(time.Since(start).Seconds())
+	db.metrics.boltDBDefragTime.SetToCurrentTime()
+	return nil
+}
+
+// replace closes the database and replaces it with the file at path.
+//
+// The file is opened in read-only mode first, to verify its integrity.
+// The current database is kept until the new one is opened: if it can
+// not be opened, the current database is restored and reopened.
+func (db *boltdb) replace(path string) (err error) {
+	candidate := *db
+	candidate.path = path
+	candidate.boltdb = nil
+	if err = candidate.open(true); err != nil {
+		_ = os.Remove(path)
 		return err
 	}
-	return db.open(false)
+	candidate.shutdown()
+
+	backup := filepath.Join(db.dir, boltDBBackupName)
+	db.shutdown()
+	if err = os.Rename(db.path, backup); err != nil {
+		return db.reopen(err)
+	}
+	if err = os.Rename(path, db.path); err == nil {
+		if err = syncPath(db.path); err == nil {
+			err = db.open(false)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		if renameErr := os.Rename(backup, db.path); renameErr != nil {
+			return fmt.Errorf("%w; failed to restore the database: %w", err, renameErr)
+		}
+		return db.reopen(err)
+	}
+	if err = os.Remove(backup); err != nil {
+		_ = level.Warn(db.logger).Log("msg", "failed to remove database backup", "err", err)
+	}
+	return nil
+}
+
+// reopen opens the current database after a failed replace,
+// and returns the replace error.
+func (db *boltdb) reopen(cause error) error {
+	if err := db.open(false); err != nil {
+		return fmt.Errorf("%w; failed to reopen the database: %w", cause, err)
+	}
+	return cause
 }
 
 func syncPath(path string) (err error) {
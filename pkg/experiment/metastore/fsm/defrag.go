@@ -0,0 +1,89 @@
+package fsm
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+// The database file never shrinks: pages freed by deletions are reused,
+// but never returned to the file system. After heavy churn (retention,
+// tenant deletion) most of the file may consist of free pages, which
+// makes the file and snapshots unnecessarily large. The defragmentation
+// copies the database into a new compact file, and replaces the current
+// one with it.
+
+type Config struct {
+	DefragCheckInterval  time.Duration `yaml:"boltdb_defrag_check_interval"`
+	DefragFreePagesRatio float64       `yaml:"boltdb_defrag_free_pages_ratio"`
+	DefragMinSize        uint64        `yaml:"boltdb_defrag_min_size"`
+	DefragMaxApplyRate   float64       `yaml:"boltdb_defrag_max_apply_rate"`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.DefragCheckInterval, prefix+"boltdb-defrag-check-interval", 0, "How often to check whether the FSM database should be defragmented. The FSM does not apply commands and does not serve reads while the database is copied, which may take a while for a large database: defragmentation is only started while the apply rate is below the configured maximum. 0 to disable defragmentation.")
+	f.Float64Var(&cfg.DefragFreePagesRatio, prefix+"boltdb-defrag-free-pages-ratio", 0.5, "Ratio of the free pages in the FSM database at which it is defragmented.")
+	f.Uint64Var(&cfg.DefragMinSize, prefix+"boltdb-defrag-min-size", 64<<20, "Minimum size of the FSM database, in bytes, at which it is defragmented.")
+	f.Float64Var(&cfg.DefragMaxApplyRate, prefix+"boltdb-defrag-max-apply-rate", 10, "Defragmentation is postponed while the FSM applies more commands per second than this, on average since the previous check. The FSM does not apply commands and does not serve reads until defragmentation completes. Must be positive if defragmentation is enabled.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.DefragFreePagesRatio < 0 || cfg.DefragFreePagesRatio > 1 {
+		return errors.New("boltdb defragmentation free pages ratio must be between 0 and 1")
+	}
+	if cfg.DefragMaxApplyRate < 0 {
+		return errors.New("boltdb defragmentation max apply rate must not be negative")
+	}
+	if cfg.DefragCheckInterval > 0 && cfg.DefragMaxApplyRate == 0 {
+		return errors.New("boltdb defragmentation max apply rate must be positive if defragmentation is enabled")
+	}
+	return nil
+}
+
+// Defragment defragments the database if the ratio of the free pages
+// exceeds the configured threshold, and the FSM is not busy: commands
+// are applied at a low rate, and no snapshot is being persisted.
+//
+// The FSM does not apply commands and does not serve reads while the
+// database is being defragmented.
+func (fsm *FSM) Defragment() {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	now := time.Now()
+	applied := fsm.appliedIndex - fsm.defragCheckIndex
+	elapsed := now.Sub(fsm.defragCheckTime)
+	fsm.defragCheckIndex, fsm.defragCheckTime = fsm.appliedIndex, now
+
+	size, free := fsm.db.stats()
+	if size < int64(fsm.config.DefragMinSize) || size == 0 {
+		return
+	}
+	if ratio := float64(free) / float64(size); ratio < fsm.config.DefragFreePagesRatio {
+		return
+	}
+	if elapsed > 0 {
+		if rate := float64(applied) / elapsed.Seconds(); rate > fsm.config.DefragMaxApplyRate {
+			level.Debug(fsm.logger).Log("msg", "postponing database defragmentation", "apply_rate", rate)
+			return
+		}
+	}
+	// Snapshots hold a read transaction until persisted: the database
+	// can not be closed until they are released.
+	if n := fsm.snapshots.Load(); n > 0 {
+		level.Debug(fsm.logger).Log("msg", "postponing database defragmentation", "snapshots_in_progress", n)
+		return
+	}
+
+	fsm.txns.Wait()
+	level.Info(fsm.logger).Log("msg", "defragmenting database", "size", size, "free", free)
+	if err := fsm.db.defragment(); err != nil {
+		level.Error(fsm.logger).Log("msg", "failed to defragment database", "err", err)
+		return
+	}
+	newSize, _ := fsm.db.stats()
+	level.Info(fsm.logger).Log("msg", "database defragmented",
+		"duration", time.Since(now), "size", newSize, "reclaimed", size-newSize)
+}
@@ -0,0 +1,109 @@
+package fsm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func Test_Defragment(t *testing.T) {
+	dir := t.TempDir()
+	fsm, err := New(log.NewNopLogger(), nil, dir, Config{DefragFreePagesRatio: 0.5, DefragMaxApplyRate: 10})
+	require.NoError(t, err)
+	defer fsm.Shutdown()
+	require.NoError(t, fsm.Init())
+
+	bucket := []byte("test")
+	value := make([]byte, 1<<10)
+	require.NoError(t, fsm.db.boltdb.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucket(bucket)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 10000; i++ {
+			if err = b.Put([]byte(fmt.Sprint(i)), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	require.NoError(t, fsm.db.boltdb.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucket)
+		for i := 10; i < 10000; i++ {
+			if err := b.Delete([]byte(fmt.Sprint(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	size, free := fsm.db.stats()
+	require.Greater(t, float64(free)/float64(size), 0.5)
+
+	// Defragmentation is postponed while a snapshot is in progress.
+	s, err := fsm.Snapshot()
+	require.NoError(t, err)
+	fsm.Defragment()
+	sizeWithSnapshot, _ := fsm.db.stats()
+	assert.Equal(t, size, sizeWithSnapshot)
+	s.Release()
+
+	fsm.Defragment()
+	compacted, free := fsm.db.stats()
+	assert.Less(t, compacted, size/4)
+	assert.Less(t, float64(free)/float64(compacted), 0.5)
+	_, err = os.Stat(filepath.Join(dir, boltDBDefragName))
+	assert.True(t, os.IsNotExist(err))
+
+	// The state and the applied index are preserved.
+	require.NoError(t, fsm.Read(func(tx *bbolt.Tx) {
+		b := tx.Bucket(bucket)
+		require.NotNil(t, b)
+		assert.Equal(t, 10, b.Stats().KeyN)
+		assert.Equal(t, value, b.Get([]byte("9")))
+	}))
+	require.NoError(t, fsm.db.boltdb.View(fsm.loadAppliedIndex))
+}
+
+func TestConfig_Validate_Defrag(t *testing.T) {
+	assert.NoError(t, (&Config{}).Validate())
+	// Defragmentation stalls the FSM: it must be limited to low traffic.
+	assert.Error(t, (&Config{DefragCheckInterval: time.Minute}).Validate())
+	assert.NoError(t, (&Config{DefragCheckInterval: time.Minute, DefragMaxApplyRate: 10}).Validate())
+}
+
+func Test_Replace_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	fsm, err := New(log.NewNopLogger(), nil, dir, Config{})
+	require.NoError(t, err)
+	defer fsm.Shutdown()
+	require.NoError(t, fsm.Init())
+
+	bucket := []byte("test")
+	require.NoError(t, fsm.db.boltdb.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucket(bucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("k"), []byte("v"))
+	}))
+
+	path := filepath.Join(dir, boltDBDefragName)
+	require.NoError(t, os.WriteFile(path, []byte("not a database"), 0644))
+	require.Error(t, fsm.db.replace(path))
+
+	// The current database is kept open.
+	require.NoError(t, fsm.Read(func(tx *bbolt.Tx) {
+		assert.Equal(t, []byte("v"), tx.Bucket(bucket).Get([]byte("k")))
+	}))
+	for _, name := range []string{boltDBDefragName, boltDBBackupName} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		assert.True(t, os.IsNotExist(err))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -36,29 +37,44 @@ type StateRestorer interface {
 	Restore(*bbolt.Tx) error
 }
 
+// Flusher writes the state buffered in memory to the database. The FSM
+// flushes the state periodically, before a snapshot is taken, and at
+// shutdown.
+type Flusher interface {
+	Flush(*bbolt.Tx) error
+}
+
 // FSM implements the raft.FSM interface.
 type FSM struct {
 	logger  log.Logger
+	config  Config
 	metrics *metrics
 
-	mu   sync.RWMutex
-	txns sync.WaitGroup
-	db   *boltdb
+	mu        sync.RWMutex
+	txns      sync.WaitGroup
+	snapshots atomic.Int64
+	db        *boltdb
 
 	handlers  map[RaftLogEntryType]handler
 	restorers []StateRestorer
+	flushers  []Flusher
 
 	appliedTerm  uint64
 	appliedIndex uint64
+
+	defragCheckIndex uint64
+	defragCheckTime  time.Time
 }
 
 type handler func(tx *bbolt.Tx, cmd *raft.Log, raw []byte) (proto.Message, error)
 
-func New(logger log.Logger, reg prometheus.Registerer, dir string) (*FSM, error) {
+func New(logger log.Logger, reg prometheus.Registerer, dir string, config Config) (*FSM, error) {
 	fsm := FSM{
-		logger:   logger,
-		metrics:  newMetrics(reg),
-		handlers: make(map[RaftLogEntryType]handler),
+		logger:          logger,
+		config:          config,
+		metrics:         newMetrics(reg),
+		handlers:        make(map[RaftLogEntryType]handler),
+		defragCheckTime: time.Now(),
 	}
 	db := newDB(logger, fsm.metrics, dir)
 	if err := db.open(false); err != nil {
@@ -72,6 +88,10 @@ func (fsm *FSM) RegisterRestorer(r ...StateRestorer) {
 	fsm.restorers = append(fsm.restorers, r...)
 }
 
+func (fsm *FSM) RegisterFlusher(f ...Flusher) {
+	fsm.flushers = append(fsm.flushers, f...)
+}
+
 func RegisterRaftCommandHandler[Req, Resp proto.Message](fsm *FSM, t RaftLogEntryType, handler RaftHandler[Req, Resp]) {
 	fsm.handlers[t] = func(tx *bbolt.Tx, cmd *raft.Log, raw []byte) (proto.Message, error) {
 		req, err := unmarshal[Req](raw)
@@ -219,8 +239,10 @@ func (fsm *FSM) applyCommand(cmd *raft.Log) any {
 		return errResponse(cmd, fmt.Errorf("unknown command type: %d", e.Type))
 	}
 
-	// Apply is never called concurrently with Restore, so we don't need
-	// to lock the FSM: db.boltdb is guaranteed to be in a consistent state.
+	// Apply is never called concurrently with Restore, however, the
+	// database may be replaced at defragmentation.
+	fsm.mu.RLock()
+	defer fsm.mu.RUnlock()
 	tx, err := fsm.db.boltdb.Begin(true)
 	if err != nil {
 		panic(fmt.Sprint("failed to begin write transaction:", err))
@@ -237,12 +259,14 @@ func (fsm *FSM) applyCommand(cmd *raft.Log) any {
 	if err = fsm.storeAppliedIndex(tx, cmd.Term, cmd.Index); err != nil {
 		panic(fmt.Sprint("failed to store applied index: %w", err))
 	}
+	size := tx.Size()
 
 	// We can't do anything about the failure at the database level, so we
 	// panic here in a hope that other instances will handle the command.
 	if err = tx.Commit(); err != nil {
 		panic(fmt.Sprint("failed to commit transaction:", err))
 	}
+	fsm.db.observeSize(size, int64(fsm.db.boltdb.Stats().FreeAlloc))
 
 	return Response{Data: data, Err: err}
 }
@@ -267,21 +291,54 @@ func (fsm *FSM) Read(fn func(*bbolt.Tx)) error {
 func (fsm *FSM) Snapshot() (raft.FSMSnapshot, error) {
 	// Snapshot should only capture a pointer to the state, and any
 	// expensive IO should happen as part of FSMSnapshot.Persist.
-	s := snapshot{logger: fsm.logger, metrics: fsm.metrics}
+	s := snapshot{logger: fsm.logger, metrics: fsm.metrics, inProgress: &fsm.snapshots}
+	fsm.mu.RLock()
+	defer fsm.mu.RUnlock()
+	// The snapshot must include the state buffered in memory. Snapshot
+	// is never called concurrently with Apply: the state is consistent
+	// with the applied index.
+	if err := fsm.flush(); err != nil {
+		return nil, fmt.Errorf("failed to flush state for snapshot: %w", err)
+	}
 	tx, err := fsm.db.boltdb.Begin(false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open a transaction for snapshot: %w", err)
 	}
 	s.tx = tx
+	fsm.snapshots.Add(1)
 	return &s, nil
 }
 
 func (fsm *FSM) Shutdown() {
 	if fsm.db.boltdb != nil {
+		if err := fsm.flush(); err != nil {
+			level.Error(fsm.logger).Log("msg", "failed to flush state at shutdown", "err", err)
+		}
 		fsm.db.shutdown()
 	}
 }
 
+// Flush writes the state buffered in memory to the database.
+func (fsm *FSM) Flush() error {
+	fsm.mu.RLock()
+	defer fsm.mu.RUnlock()
+	return fsm.flush()
+}
+
+func (fsm *FSM) flush() error {
+	if len(fsm.flushers) == 0 {
+		return nil
+	}
+	return fsm.db.boltdb.Update(func(tx *bbolt.Tx) error {
+		for _, f := range fsm.flushers {
+			if err := f.Flush(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 var (
 	raftBucketName  = []byte("raft")
 	appliedIndexKey = []byte("term.applied_index")
@@ -315,16 +372,21 @@ func (fsm *FSM) storeAppliedIndex(tx *bbolt.Tx, term, index uint64) error {
 
 var errAppliedIndexInvalid = fmt.Errorf("invalid applied index")
 
-func (fsm *FSM) loadAppliedIndex(tx *bbolt.Tx) error {
+func (fsm *FSM) loadAppliedIndex(tx *bbolt.Tx) (err error) {
+	fsm.appliedTerm, fsm.appliedIndex, err = AppliedIndex(tx)
+	return err
+}
+
+// AppliedIndex returns the term and the index of the last command
+// applied to the state, as observed by the transaction.
+func AppliedIndex(tx *bbolt.Tx) (term, index uint64, err error) {
 	b := tx.Bucket(raftBucketName)
 	if b == nil {
-		return bbolt.ErrBucketNotFound
+		return 0, 0, bbolt.ErrBucketNotFound
 	}
 	v := b.Get(appliedIndexKey)
 	if len(v) < 16 {
-		return errAppliedIndexInvalid
+		return 0, 0, errAppliedIndexInvalid
 	}
-	fsm.appliedTerm = binary.BigEndian.Uint64(v[0:8])
-	fsm.appliedIndex = binary.BigEndian.Uint64(v[8:16])
-	return nil
+	return binary.BigEndian.Uint64(v[0:8]), binary.BigEndian.Uint64(v[8:16]), nil
 }
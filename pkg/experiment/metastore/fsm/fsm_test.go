@@ -0,0 +1,59 @@
+package fsm
+
+import (
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+type testFlusher struct{ pending, flushed int }
+
+func (f *testFlusher) Flush(tx *bbolt.Tx) error {
+	b, err := tx.CreateBucketIfNotExists([]byte("test"))
+	if err != nil {
+		return err
+	}
+	for ; f.pending > 0; f.pending-- {
+		f.flushed++
+		if err = b.Put([]byte{byte(f.flushed)}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Test_Flush(t *testing.T) {
+	fsm, err := New(log.NewNopLogger(), nil, t.TempDir(), Config{})
+	require.NoError(t, err)
+	require.NoError(t, fsm.Init())
+	f := new(testFlusher)
+	fsm.RegisterFlusher(f)
+
+	stored := func() (n int) {
+		require.NoError(t, fsm.Read(func(tx *bbolt.Tx) {
+			if b := tx.Bucket([]byte("test")); b != nil {
+				n = b.Stats().KeyN
+			}
+		}))
+		return n
+	}
+
+	f.pending = 2
+	require.NoError(t, fsm.Flush())
+	assert.Equal(t, 2, stored())
+
+	// The snapshot includes the state flushed.
+	f.pending = 1
+	s, err := fsm.Snapshot()
+	require.NoError(t, err)
+	s.Release()
+	assert.Equal(t, 3, stored())
+
+	f.pending = 1
+	fsm.Shutdown()
+	assert.Equal(t, 0, f.pending)
+	assert.Equal(t, 4, f.flushed)
+}
@@ -11,7 +11,12 @@ import (
 type metrics struct {
 	boltDBPersistSnapshotDuration prometheus.Histogram
 	boltDBPersistSnapshotSize     prometheus.Histogram
+	boltDBPersistSnapshotTime     prometheus.Gauge
 	boltDBRestoreSnapshotDuration prometheus.Histogram
+	boltDBSize                    prometheus.Gauge
+	boltDBFreePagesRatio          prometheus.Gauge
+	boltDBDefragDuration          prometheus.Histogram
+	boltDBDefragTime              prometheus.Gauge
 	fsmRestoreSnapshotDuration    prometheus.Histogram
 	fsmApplyCommandSize           *prometheus.HistogramVec
 	fsmApplyCommandDuration       *prometheus.HistogramVec
@@ -35,6 +40,11 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			NativeHistogramMinResetDuration: time.Hour,
 		}),
 
+		boltDBPersistSnapshotTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "boltdb_persist_snapshot_last_success_timestamp_seconds",
+			Help: "Time of the last successfully persisted snapshot, as a Unix timestamp.",
+		}),
+
 		boltDBRestoreSnapshotDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name:                            "boltdb_restore_snapshot_duration_seconds",
 			Buckets:                         dataTimingBuckets,
@@ -43,6 +53,29 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			NativeHistogramMinResetDuration: time.Hour,
 		}),
 
+		boltDBSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "boltdb_size_bytes",
+			Help: "Size of the FSM database, in bytes.",
+		}),
+
+		boltDBFreePagesRatio: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "boltdb_free_pages_ratio",
+			Help: "Ratio of the FSM database size taken by free pages.",
+		}),
+
+		boltDBDefragDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:                            "boltdb_defrag_duration_seconds",
+			Buckets:                         dataTimingBuckets,
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: time.Hour,
+		}),
+
+		boltDBDefragTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "boltdb_defrag_last_success_timestamp_seconds",
+			Help: "Time of the last successful FSM database defragmentation, as a Unix timestamp.",
+		}),
+
 		fsmRestoreSnapshotDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name:                            "fsm_restore_snapshot_duration_seconds",
 			Buckets:                         dataTimingBuckets,
@@ -70,10 +103,19 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 	if reg != nil {
 		util.RegisterOrGet(reg, m.boltDBPersistSnapshotSize)
 		util.RegisterOrGet(reg, m.boltDBPersistSnapshotDuration)
+		util.RegisterOrGet(reg, m.boltDBPersistSnapshotTime)
 		util.RegisterOrGet(reg, m.boltDBRestoreSnapshotDuration)
+		util.RegisterOrGet(reg, m.boltDBSize)
+		util.RegisterOrGet(reg, m.boltDBFreePagesRatio)
+		util.RegisterOrGet(reg, m.boltDBDefragDuration)
+		util.RegisterOrGet(reg, m.boltDBDefragTime)
 		util.RegisterOrGet(reg, m.fsmRestoreSnapshotDuration)
 		util.RegisterOrGet(reg, m.fsmApplyCommandSize)
 		util.RegisterOrGet(reg, m.fsmApplyCommandDuration)
 	}
 	return m
 }
+
+// RegisterMetrics registers the FSM metrics without opening the
+// database. The metrics are used to generate monitoring resources.
+func RegisterMetrics(reg prometheus.Registerer) { newMetrics(reg) }
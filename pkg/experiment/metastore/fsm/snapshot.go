@@ -3,6 +3,7 @@ package fsm
 import (
 	"context"
 	"runtime/pprof"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -12,9 +13,10 @@ import (
 )
 
 type snapshot struct {
-	logger  log.Logger
-	tx      *bbolt.Tx
-	metrics *metrics
+	logger     log.Logger
+	tx         *bbolt.Tx
+	metrics    *metrics
+	inProgress *atomic.Int64
 }
 
 func (s *snapshot) Persist(sink raft.SnapshotSink) (err error) {
@@ -30,7 +32,9 @@ func (s *snapshot) Persist(sink raft.SnapshotSink) (err error) {
 			level.Info(s.logger).Log("msg", "persisted snapshot", "sink_id", sink.ID(), "duration", time.Since(start))
 			if err = sink.Close(); err != nil {
 				level.Error(s.logger).Log("msg", "failed to close sink", "err", err)
+				return
 			}
+			s.metrics.boltDBPersistSnapshotTime.SetToCurrentTime()
 			return
 		}
 		level.Error(s.logger).Log("msg", "failed to persist snapshot", "err", err)
@@ -53,5 +57,7 @@ func (s *snapshot) Release() {
 	if s.tx != nil {
 		// This is an in-memory rollback, no error expected.
 		_ = s.tx.Rollback()
+		s.tx = nil
+		s.inProgress.Add(-1)
 	}
 }
@@ -0,0 +1,249 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log/level"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+	"github.com/grafana/pyroscope/pkg/phlaredb/bucket"
+)
+
+// Archives are stored under the partition key and the checksum of the
+// content: an archive of the same partition state may be uploaded more
+// than once, e.g., by a new leader, while archives of different states
+// never overwrite each other. The stub refers to the archive by its path,
+// therefore the archives uploaded under a different directory are found.
+const archiveDir = bucket.PyroscopeInternalsPrefix + "/metastore/index/"
+
+// archiveLoadTimeout limits the time the index waits
+// for an archived partition to be fetched from the bucket.
+const archiveLoadTimeout = time.Minute
+
+func archivePath(key store.PartitionKey, checksum uint64) string {
+	return path.Join(archiveDir, string(key), strconv.FormatUint(checksum, 16)+".binpb")
+}
+
+func archivedPartitionMeta(archived *raft_log.ArchivedPartition) *PartitionMeta {
+	key := store.PartitionKey(archived.Key)
+	ts, duration, _ := key.Parse()
+	meta := &PartitionMeta{
+		Key:        key,
+		Ts:         ts,
+		Duration:   duration,
+		Tenants:    make([]string, 0, len(archived.Tenants)),
+		BlockCount: int(archived.Blocks),
+		MinTime:    archived.MinTime,
+		MaxTime:    archived.MaxTime,
+		tenantMap:  make(map[string]struct{}, len(archived.Tenants)),
+		archive:    archived,
+	}
+	for _, t := range archived.Tenants {
+		meta.AddTenant(t)
+	}
+	if len(archived.TenantStats) > 0 {
+		meta.tenantStats = make(map[string]*metastorev1.TenantBlockStats, len(archived.TenantStats))
+		for _, s := range archived.TenantStats {
+			meta.tenantStats[s.TenantId] = s.CloneVT()
+		}
+	}
+	return meta
+}
+
+// PartitionsToArchive returns the keys of the partitions that
+// ended before the given time and have not been archived yet.
+func (i *Index) PartitionsToArchive(before time.Time) []store.PartitionKey {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	keys := make([]store.PartitionKey, 0)
+	for _, meta := range i.partitions.all() {
+		if meta.archive == nil && !meta.EndTime().After(before) {
+			keys = append(keys, meta.Key)
+		}
+	}
+	return keys
+}
+
+// PartitionArchive creates the archive of the partition from its state in
+// the database. The archive content is to be uploaded to the path of the
+// returned stub before the partition is archived with ArchivePartition.
+func (i *Index) PartitionArchive(tx *bbolt.Tx, key store.PartitionKey) ([]byte, *raft_log.ArchivedPartition, error) {
+	archive := &raft_log.PartitionArchive{Key: string(key)}
+	stub := &raft_log.ArchivedPartition{Key: string(key)}
+	seen := make(map[string]struct{})
+	for _, s := range i.store.ListShards(tx, key) {
+		for _, t := range i.store.ListTenants(tx, key, s) {
+			if _, ok := seen[t]; !ok {
+				seen[t] = struct{}{}
+				stub.Tenants = append(stub.Tenants, t)
+			}
+			archive.Blocks = append(archive.Blocks, i.store.ListBlocks(tx, key, s, t)...)
+		}
+	}
+	data, err := archive.MarshalVT()
+	if err != nil {
+		return nil, nil, err
+	}
+	stub.Blocks = uint32(len(archive.Blocks))
+	stats := new(PartitionMeta)
+	for _, b := range archive.Blocks {
+		stats.addBlock(b)
+	}
+	stub.MinTime, stub.MaxTime = stats.MinTime, stats.MaxTime
+	stub.TenantStats = stats.TenantStats()
+	stub.Dictionaries = archivedDictionaryRefs(archive.Blocks)
+	stub.Size = uint64(len(data))
+	stub.Checksum = xxhash.Sum64(data)
+	stub.Path = archivePath(key, stub.Checksum)
+	return data, stub, nil
+}
+
+// archivedDictionaryRefs counts the references of the blocks to the
+// symbols dictionaries: the references are kept while the partition
+// is archived, and released when the partition is deleted.
+func archivedDictionaryRefs(blocks []*metastorev1.BlockMeta) []*raft_log.ArchivedDictionaryRefs {
+	var refs []*raft_log.ArchivedDictionaryRefs
+	byPath := make(map[string]*raft_log.ArchivedDictionaryRefs)
+	for _, b := range blocks {
+		for _, path := range blockDictionaries(b) {
+			r, ok := byPath[path]
+			if !ok {
+				r = &raft_log.ArchivedDictionaryRefs{Shard: b.Shard, Tenant: b.TenantId, Path: path}
+				byPath[path] = r
+				refs = append(refs, r)
+			}
+			r.Blocks++
+		}
+	}
+	return refs
+}
+
+// ArchivePartition deletes the partition blocks from the database, leaving
+// the stub in their place. The partition is only archived if its state has
+// not changed since the archive was created: false is returned otherwise.
+//
+// The partition blocks loaded in memory are kept: they are identical to the
+// archived ones, and archived partitions can not be modified.
+func (i *Index) ArchivePartition(tx *bbolt.Tx, archived *raft_log.ArchivedPartition) (bool, error) {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	if err := i.flushPendingBlocks(tx); err != nil {
+		return false, err
+	}
+	key := store.PartitionKey(archived.Key)
+	meta := i.findPartitionMeta(key)
+	if meta == nil || meta.archive != nil {
+		return false, nil
+	}
+	_, stub, err := i.PartitionArchive(tx, key)
+	if err != nil {
+		return false, err
+	}
+	if !stub.EqualVT(archived) {
+		level.Warn(i.logger).Log("msg", "partition has changed since the archive was created", "partition", key)
+		return false, nil
+	}
+	if err = i.store.ArchivePartition(tx, stub); err != nil {
+		return false, err
+	}
+	meta.archive = stub
+	i.metrics.archivedPartitions.Inc()
+	return true, nil
+}
+
+// loadArchivedPartition fetches the partition archive from the bucket and
+// caches the blocks of all the tenants in memory, unless they are already
+// cached. The partition of the given tenant is returned, or nil, if the
+// archive can't be loaded. Archives are only loaded for reads: see
+// getOrLoadPartition.
+func (i *Index) loadArchivedPartition(meta *PartitionMeta, tenant string) *indexPartition {
+	archive, err := i.fetchArchive(meta.archive)
+	if err != nil {
+		level.Error(i.logger).Log("msg", "failed to load archived partition", "partition", meta.Key, "path", meta.archive.Path, "err", err)
+		i.metrics.archiveLoads.WithLabelValues("failure").Inc()
+		return nil
+	}
+	i.metrics.archiveLoads.WithLabelValues("success").Inc()
+	now := time.Now().UTC()
+	partitions := make(map[string]*indexPartition)
+	partition := func(t string) *indexPartition {
+		p, ok := partitions[t]
+		if !ok {
+			p = newIndexPartition(meta, now)
+			partitions[t] = p
+		}
+		return p
+	}
+	partition(tenant)
+	for _, b := range archive.Blocks {
+		store.InternBlockStrings(b)
+		bp := partition(b.TenantId)
+		i.putBlock(bp, i.getOrCreateShard(bp, b.Shard), b)
+	}
+	i.cacheMu.Lock()
+	defer i.cacheMu.Unlock()
+	for t, p := range partitions {
+		partitions[t] = i.cacheLoadedPartition(cacheKey{partitionKey: meta.Key, tenant: t}, p)
+	}
+	return partitions[tenant]
+}
+
+func (i *Index) fetchArchive(stub *raft_log.ArchivedPartition) (*raft_log.PartitionArchive, error) {
+	if i.config.Bucket == nil {
+		return nil, fmt.Errorf("bucket is not configured")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), archiveLoadTimeout)
+	defer cancel()
+	r, err := i.config.Bucket.Get(ctx, stub.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if xxhash.Sum64(data) != stub.Checksum {
+		return nil, fmt.Errorf("archive checksum mismatch")
+	}
+	archive := new(raft_log.PartitionArchive)
+	if err = archive.UnmarshalVT(data); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// checkNotArchived returns an *InvalidBlockError if the block
+// belongs to an archived partition. It is the caller's
+// responsibility to enforce safe concurrent access.
+func (i *Index) checkNotArchived(block string, key store.PartitionKey) error {
+	if meta := i.findPartitionMeta(key); meta != nil && meta.archive != nil {
+		return &InvalidBlockError{Block: block, Reason: ArchivedPartition}
+	}
+	return nil
+}
+
+func (i *Index) checkCompactedBlocksNotArchived(compacted *metastorev1.CompactedBlocks) error {
+	for _, b := range compacted.NewBlocks {
+		if err := i.checkNotArchived(b.Id, i.partitionKey(b.Id, b.Shard, b.TenantId)); err != nil {
+			return err
+		}
+	}
+	for k, list := range i.partitionBlockList(compacted.SourceBlocks) {
+		if err := i.checkNotArchived(list.Blocks[0], k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package index
+
+import (
+	"github.com/cespare/xxhash/v2"
+)
+
+// blockFilterHashes is the number of bits set for each block: the false
+// positive rate is about 1% if the filter has 10 bits per block.
+const blockFilterHashes = 7
+
+// blockFilter is a bloom filter of the identifiers of the partition blocks.
+// It is used to skip the partitions that can't include a block without
+// loading them in memory. Blocks are never removed from the filter.
+//
+// The filter is not stored: it is built when the partition metadata is
+// loaded from the store, which involves reading all the partition blocks.
+type blockFilter struct {
+	bits []uint64
+}
+
+// newBlockFilter returns a filter of the given size in bytes,
+// or nil, if the size is not positive.
+func newBlockFilter(size int) *blockFilter {
+	if size <= 0 {
+		return nil
+	}
+	return &blockFilter{bits: make([]uint64, (size+7)/8)}
+}
+
+func (f *blockFilter) add(id string) {
+	h1, h2, n := f.hash(id)
+	for j := uint64(0); j < blockFilterHashes; j++ {
+		b := (h1 + j*h2) % n
+		f.bits[b/64] |= 1 << (b % 64)
+	}
+}
+
+// mayContain reports whether the block might have been added to the filter.
+func (f *blockFilter) mayContain(id string) bool {
+	h1, h2, n := f.hash(id)
+	for j := uint64(0); j < blockFilterHashes; j++ {
+		b := (h1 + j*h2) % n
+		if f.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *blockFilter) hash(id string) (h1, h2, n uint64) {
+	h := xxhash.Sum64String(id)
+	return h & 0xffffffff, h>>32 | 1, uint64(len(f.bits)) * 64
+}
+
+// merge adds the blocks of the other filter to the filter. Only filters
+// of the same size can be merged: false is returned otherwise.
+func (f *blockFilter) merge(other *blockFilter) bool {
+	if len(f.bits) != len(other.bits) {
+		return false
+	}
+	for j := range f.bits {
+		f.bits[j] |= other.bits[j]
+	}
+	return true
+}
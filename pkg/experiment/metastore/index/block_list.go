@@ -0,0 +1,165 @@
+package index
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+	"github.com/grafana/pyroscope/pkg/iter"
+	"github.com/grafana/pyroscope/pkg/util/loser"
+)
+
+// BlockCursor is the position of a block in the listing of ListBlocks:
+// blocks are ordered by partition key, shard, and block identifier.
+type BlockCursor struct {
+	Partition store.PartitionKey
+	Shard     uint32
+	Block     string
+}
+
+const blockCursorSeparator = "/"
+
+func (c BlockCursor) String() string {
+	return strings.Join([]string{
+		string(c.Partition),
+		strconv.FormatUint(uint64(c.Shard), 10),
+		c.Block,
+	}, blockCursorSeparator)
+}
+
+// ParseBlockCursor parses the cursor returned by BlockCursor.String.
+func ParseBlockCursor(s string) (BlockCursor, error) {
+	parts := strings.Split(s, blockCursorSeparator)
+	if len(parts) != 3 {
+		return BlockCursor{}, fmt.Errorf("invalid block cursor: %q", s)
+	}
+	shard, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return BlockCursor{}, fmt.Errorf("invalid block cursor: %q", s)
+	}
+	return BlockCursor{
+		Partition: store.PartitionKey(parts[0]),
+		Shard:     uint32(shard),
+		Block:     parts[2],
+	}, nil
+}
+
+// ListBlocks returns up to limit blocks of the tenant that might contain
+// data for the given time range, starting after the cursor, if it is not
+// nil. The empty tenant refers to the blocks that include data of multiple
+// tenants. If the listing might continue, the cursor of the last block
+// returned is returned as well.
+//
+// Unlike FindBlocksInRange, the blocks are read from the store one at a time,
+// and the partitions are not loaded in memory, so the blocks of a partition
+// can be listed in pages of any size. The listing is not isolated from the
+// changes made to the index between the pages: e.g., the blocks of a
+// partition merged in the meantime may be skipped or listed twice.
+//
+// Quarantined, deleted, and archived blocks are not included.
+func (i *Index) ListBlocks(tx *bbolt.Tx, tenant string, start, end int64, after *BlockCursor, limit int) ([]*metastorev1.BlockMeta, *BlockCursor, error) {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+
+	partitions := make([]*PartitionMeta, 0)
+	for _, meta := range i.partitionsInRange(start, end) {
+		if meta.archive != nil {
+			continue
+		}
+		if tenant != "" && !meta.HasTenant(tenant) {
+			continue
+		}
+		if after != nil && meta.Key < after.Partition {
+			continue
+		}
+		partitions = append(partitions, meta)
+	}
+	slices.SortFunc(partitions, func(a, b *PartitionMeta) int {
+		return strings.Compare(string(a.Key), string(b.Key))
+	})
+
+	blocks := make([]*metastorev1.BlockMeta, 0, limit)
+	for _, meta := range partitions {
+		for _, shard := range i.listShards(tx, meta.Key) {
+			var from string
+			if after != nil && meta.Key == after.Partition {
+				if shard < after.Shard {
+					continue
+				}
+				if shard == after.Shard {
+					from = after.Block
+				}
+			}
+			it := i.iterateBlocks(tx, meta.Key, shard, tenant, from)
+			for it.Next() {
+				b := it.At()
+				if b.Quarantine != nil || b.DeletedAt != 0 || start >= b.MaxTime || end < b.MinTime {
+					continue
+				}
+				blocks = append(blocks, b)
+				if len(blocks) == limit {
+					_ = it.Close()
+					return blocks, &BlockCursor{Partition: meta.Key, Shard: shard, Block: b.Id}, nil
+				}
+			}
+			err := it.Err()
+			_ = it.Close()
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return blocks, nil, nil
+}
+
+// listShards returns the shards of the partition in ascending order,
+// including the shards of the blocks pending in the write-behind mode.
+func (i *Index) listShards(tx *bbolt.Tx, key store.PartitionKey) []uint32 {
+	shards := i.store.ListShards(tx, key)
+	for _, p := range i.pending {
+		if p.key == key && !slices.Contains(shards, p.block.Shard) {
+			shards = append(shards, p.block.Shard)
+		}
+	}
+	slices.Sort(shards)
+	return shards
+}
+
+// iterateBlocks returns an iterator over the stored blocks of the partition
+// shard and tenant, merged with the blocks pending in the write-behind mode.
+func (i *Index) iterateBlocks(tx *bbolt.Tx, key store.PartitionKey, shard uint32, tenant string, after string) iter.Iterator[*metastorev1.BlockMeta] {
+	stored := i.store.IterateBlocks(tx, key, shard, tenant, after)
+	var pending []*metastorev1.BlockMeta
+	for _, p := range i.pending {
+		if p.key == key && p.block.Shard == shard && p.block.TenantId == tenant && p.block.Id > after {
+			pending = append(pending, p.block)
+		}
+	}
+	if len(pending) == 0 {
+		return stored
+	}
+	slices.SortFunc(pending, func(a, b *metastorev1.BlockMeta) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	return iter.NewTreeIterator(loser.New(
+		[]iter.Iterator[*metastorev1.BlockMeta]{stored, iter.NewSliceIterator(pending)},
+		nil,
+		func(it iter.Iterator[*metastorev1.BlockMeta]) *metastorev1.BlockMeta { return it.At() },
+		func(a, b *metastorev1.BlockMeta) bool {
+			// The nil value is greater than any block.
+			if a == nil {
+				return false
+			}
+			if b == nil {
+				return true
+			}
+			return a.Id < b.Id
+		},
+		func(it iter.Iterator[*metastorev1.BlockMeta]) { _ = it.Close() },
+	))
+}
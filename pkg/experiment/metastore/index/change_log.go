@@ -0,0 +1,200 @@
+package index
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// ErrChangesUnavailable is returned by BlockChanges if the changes after
+// the cursor are no longer kept in the change log, or the cursor refers
+// to the change log of another index instance.
+var ErrChangesUnavailable = errors.New("block changes are no longer available")
+
+// ErrChangeLogDisabled is returned by BlockChanges if the change log
+// size is not configured.
+var ErrChangeLogDisabled = errors.New("block change log is disabled")
+
+// changeLog keeps the most recent changes of the index blocks in memory,
+// in the order they are applied, so that they can be streamed to watchers.
+//
+// The changes are numbered sequentially, and the cursor of a change is its
+// sequence number prefixed with the change log identifier: the log is not
+// persisted, and is started anew each time the index is restored, therefore
+// the numbers are only meaningful within the log they were assigned in.
+type changeLog struct {
+	mu      sync.Mutex
+	id      string
+	changes []*metastorev1.BlockChange // Ring buffer.
+	next    uint64                     // Sequence number of the next change.
+	// notify is closed when a change is appended.
+	notify chan struct{}
+}
+
+const changeCursorSeparator = "/"
+
+func newChangeLogID() string {
+	return ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
+}
+
+func newChangeLog(size int) *changeLog {
+	return &changeLog{
+		id:      newChangeLogID(),
+		changes: make([]*metastorev1.BlockChange, size),
+		notify:  make(chan struct{}),
+	}
+}
+
+func (l *changeLog) enabled() bool { return len(l.changes) > 0 }
+
+func (l *changeLog) append(changes ...*metastorev1.BlockChange) {
+	if !l.enabled() || len(changes) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, c := range changes {
+		l.changes[l.next%uint64(len(l.changes))] = c
+		l.next++
+	}
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// reset discards the changes: the cursors issued before are invalidated.
+func (l *changeLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.id = newChangeLogID()
+	clear(l.changes)
+	l.next = 0
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+func (l *changeLog) cursor(seq uint64) string {
+	return l.id + changeCursorSeparator + strconv.FormatUint(seq, 10)
+}
+
+// read returns up to limit changes of the tenants after the cursor, the
+// cursor of the position the read has stopped at, and the channel closed
+// once there are changes after it. The empty cursor refers to the end of
+// the log.
+func (l *changeLog) read(cursor string, tenants []string, limit int) ([]*metastorev1.BlockChange, string, <-chan struct{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	seq := l.next
+	if cursor != "" {
+		id, s, ok := strings.Cut(cursor, changeCursorSeparator)
+		if !ok {
+			return nil, "", nil, fmt.Errorf("invalid change cursor: %q", cursor)
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("invalid change cursor: %q", cursor)
+		}
+		if id != l.id || n > l.next || l.next-n > uint64(len(l.changes)) {
+			return nil, "", nil, ErrChangesUnavailable
+		}
+		seq = n
+	}
+	var changes []*metastorev1.BlockChange
+	for ; seq < l.next && len(changes) < limit; seq++ {
+		c := l.changes[seq%uint64(len(l.changes))]
+		if len(tenants) == 0 || slices.Contains(tenants, c.TenantId) {
+			changes = append(changes, c)
+		}
+	}
+	notify := l.notify
+	if seq < l.next {
+		// More changes are available.
+		notify = closedChan
+	}
+	return changes, l.cursor(seq), notify, nil
+}
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// BlockChanges returns up to limit changes of the blocks of the tenants
+// made after the cursor, and the cursor to continue with. The returned
+// channel is closed once there are changes after the returned cursor. The
+// empty cursor refers to the most recent change; no tenants refers to all
+// the blocks.
+//
+// The changes are shared and must not be modified.
+func (i *Index) BlockChanges(cursor string, tenants []string, limit int) ([]*metastorev1.BlockChange, string, <-chan struct{}, error) {
+	if !i.changes.enabled() {
+		return nil, "", nil, ErrChangeLogDisabled
+	}
+	return i.changes.read(cursor, tenants, limit)
+}
+
+func blocksAddedChange(b *metastorev1.BlockMeta) *metastorev1.BlockChange {
+	return &metastorev1.BlockChange{
+		Type:     metastorev1.BlockChangeType_BLOCK_CHANGE_TYPE_ADDED,
+		TenantId: b.TenantId,
+		Shard:    b.Shard,
+		Added:    []*metastorev1.BlockMeta{b.CloneVT()},
+	}
+}
+
+// blocksReplacedChanges returns a change for each tenant of the source and
+// compacted blocks: level 0 blocks, which include data of many tenants, are
+// compacted into blocks of individual tenants.
+func blocksReplacedChanges(compacted *metastorev1.CompactedBlocks) []*metastorev1.BlockChange {
+	source := compacted.SourceBlocks
+	changes := []*metastorev1.BlockChange{{
+		Type:     metastorev1.BlockChangeType_BLOCK_CHANGE_TYPE_REPLACED,
+		TenantId: source.Tenant,
+		Shard:    source.Shard,
+		Removed:  slices.Clone(source.Blocks),
+	}}
+	for _, b := range compacted.NewBlocks {
+		k := slices.IndexFunc(changes, func(c *metastorev1.BlockChange) bool {
+			return c.TenantId == b.TenantId && c.Shard == b.Shard
+		})
+		if k < 0 {
+			k = len(changes)
+			changes = append(changes, &metastorev1.BlockChange{
+				Type:     metastorev1.BlockChangeType_BLOCK_CHANGE_TYPE_REPLACED,
+				TenantId: b.TenantId,
+				Shard:    b.Shard,
+			})
+		}
+		changes[k].Added = append(changes[k].Added, b.CloneVT())
+	}
+	return changes
+}
+
+// blocksDeletedChanges groups the deleted blocks by tenant and shard.
+func blocksDeletedChanges(blocks []*metastorev1.BlockMeta) []*metastorev1.BlockChange {
+	var changes []*metastorev1.BlockChange
+	for _, b := range blocks {
+		k := slices.IndexFunc(changes, func(c *metastorev1.BlockChange) bool {
+			return c.TenantId == b.TenantId && c.Shard == b.Shard
+		})
+		if k < 0 {
+			k = len(changes)
+			changes = append(changes, &metastorev1.BlockChange{
+				Type:     metastorev1.BlockChangeType_BLOCK_CHANGE_TYPE_DELETED,
+				TenantId: b.TenantId,
+				Shard:    b.Shard,
+			})
+		}
+		changes[k].Removed = append(changes[k].Removed, b.Id)
+	}
+	return changes
+}
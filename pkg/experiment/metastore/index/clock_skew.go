@@ -0,0 +1,60 @@
+package index
+
+import (
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// CheckBlockClockSkew verifies that the time of the block identifier does
+// not deviate from the given time by more than the configured maximum clock
+// skew. The block identifier determines the partition the block belongs to:
+// blocks created on hosts with skewed clocks land in partitions that are not
+// accessed by queries.
+//
+// If re-stamping is enabled, the timestamp of the block identifier is replaced
+// with the block max time, and the original timestamp is recorded in the block
+// metadata; the block is modified in place. Otherwise, an *InvalidBlockError
+// is returned. The time is expected to be deterministic, e.g., the time the
+// Raft log entry was appended at.
+func (i *Index) CheckBlockClockSkew(b *metastorev1.BlockMeta, now time.Time) error {
+	if i.config.MaxBlockClockSkew <= 0 {
+		return nil
+	}
+	id, err := ulid.Parse(b.Id)
+	if err != nil {
+		// Invalid identifiers are rejected by the validation.
+		return nil
+	}
+	created := time.UnixMilli(int64(id.Time()))
+	skew := created.Sub(now).Abs()
+	if skew <= i.config.MaxBlockClockSkew {
+		return nil
+	}
+	if !i.config.RestampSkewedBlocks || !validTimeRange(b.MinTime, b.MaxTime) {
+		err = &InvalidBlockError{Block: b.Id, Reason: ClockSkew}
+		i.metrics.observeValidation(err)
+		return err
+	}
+	// The block data time is used instead of the current time, so that
+	// retries of adding the block are re-stamped identically.
+	if err = id.SetTime(uint64(b.MaxTime)); err != nil {
+		err = &InvalidBlockError{Block: b.Id, Reason: ClockSkew}
+		i.metrics.observeValidation(err)
+		return err
+	}
+	level.Warn(i.logger).Log(
+		"msg", "re-stamping block identifier",
+		"block_id", b.Id,
+		"new_block_id", id.String(),
+		"created_at", created,
+		"skew", skew,
+	)
+	b.OriginalCreatedAt = created.UnixMilli()
+	b.Id = id.String()
+	i.metrics.restampedBlocks.Inc()
+	return nil
+}
@@ -0,0 +1,87 @@
+package index
+
+import (
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+// Blocks are deleted from the index in two phases. First, a block replaced
+// by compaction is marked as deleted: the block is excluded from queries and
+// compaction, but can still be found by its identifier, and a tombstone is
+// stored. Then, once the grace period has passed, the leader proposes the
+// sweep of the tombstones, and the blocks are removed from the index.
+//
+// The time of the deletion is carried by the raft command that deletes the
+// block, therefore all the replicas make the same decisions.
+
+// SweepDeletedBlocks removes the blocks deleted before the given time.
+// Blocks of archived partitions are not removed, but their tombstones are.
+// The symbols dictionaries no longer referenced by the blocks are released
+// at the time of the sweep, and returned.
+func (i *Index) SweepDeletedBlocks(tx *bbolt.Tx, req *raft_log.SweepDeletedBlocksRequest, sweptAt time.Time) (*raft_log.SweepDeletedBlocksResponse, []ReleasedDictionary, error) {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	if err := i.flushPendingBlocks(tx); err != nil {
+		return nil, nil, err
+	}
+	tombstones, err := i.store.ListDeletedBlocks(tx, req.DeletedBefore, int(req.MaxBlocks))
+	if err != nil {
+		return nil, nil, err
+	}
+	resp := new(raft_log.SweepDeletedBlocksResponse)
+	var released []ReleasedDictionary
+	for _, t := range tombstones {
+		b, err := i.sweepDeletedBlock(tx, t)
+		if err != nil {
+			return nil, nil, err
+		}
+		if b == nil {
+			continue
+		}
+		resp.Removed++
+		r, err := i.releaseDictionaryRefs(tx, b, sweptAt.UnixMilli())
+		if err != nil {
+			return nil, nil, err
+		}
+		released = append(released, r...)
+	}
+	if err = i.store.DeleteDeletedBlocks(tx, tombstones); err != nil {
+		return nil, nil, err
+	}
+	forgetBefore := sweptAt.Add(-releasedDictionaryRetention).UnixMilli()
+	if err = i.store.DeleteReleasedDictionaries(tx, forgetBefore); err != nil {
+		return nil, nil, err
+	}
+	i.metrics.sweptBlocks.Add(float64(resp.Removed))
+	return resp, released, nil
+}
+
+// sweepDeletedBlock removes the block, and returns its metadata,
+// or nil, if the block has not been removed.
+func (i *Index) sweepDeletedBlock(tx *bbolt.Tx, t store.DeletedBlock) (*metastorev1.BlockMeta, error) {
+	key, s := i.findBlockShard(tx, t.Shard, t.Tenant, t.Block, true)
+	if s == nil {
+		return nil, nil
+	}
+	if meta := i.findPartitionMeta(key); meta == nil || meta.archive != nil {
+		// Archived partitions can not be modified.
+		return nil, nil
+	}
+	b := s.blocks[t.Block]
+	if b.DeletedAt == 0 {
+		// The block has been inserted again.
+		return nil, nil
+	}
+	list := &metastorev1.BlockList{Shard: t.Shard, Tenant: t.Tenant, Blocks: []string{t.Block}}
+	if err := i.store.DeleteBlockList(tx, key, list); err != nil {
+		return nil, err
+	}
+	// The shard belongs to the partition loaded for the tenant.
+	i.deleteBlockEntry(i.loadedPartitions[cacheKey{partitionKey: key, tenant: t.Tenant}], s, t.Block)
+	return b, nil
+}
@@ -0,0 +1,85 @@
+package index
+
+import (
+	"slices"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// The index counts the blocks that refer to each of the shared symbols
+// dictionaries. A reference is added when a block is inserted for the
+// first time, and released when the block is removed from the index,
+// either swept after compaction, or deleted due to retention. Blocks
+// moved between partitions and archived keep their references.
+//
+// A released dictionary is returned to the caller, which tombstones it.
+// A compaction job that started before the release may still refer to the
+// dictionary: such blocks are rejected until the release is forgotten.
+
+// releasedDictionaryRetention is how long a release is remembered: well
+// beyond the cleanup delay of the tombstones, after which the dictionary
+// object is deleted and can no longer be picked by compaction jobs.
+const releasedDictionaryRetention = 24 * time.Hour
+
+// ReleasedDictionary is a shared symbols dictionary
+// that is no longer referenced by the index blocks.
+type ReleasedDictionary struct {
+	Shard  uint32
+	Tenant string
+	Path   string
+}
+
+// blockDictionaries returns the paths of the dictionaries the block refers to.
+func blockDictionaries(b *metastorev1.BlockMeta) []string {
+	var paths []string
+	for _, ds := range b.Datasets {
+		d := ds.SymbolsDictionary
+		if d == nil || d.Path == "" {
+			continue
+		}
+		if !slices.Contains(paths, d.Path) {
+			paths = append(paths, d.Path)
+		}
+	}
+	return paths
+}
+
+func (i *Index) addDictionaryRefs(tx *bbolt.Tx, b *metastorev1.BlockMeta) error {
+	paths := blockDictionaries(b)
+	if len(paths) == 0 {
+		return nil
+	}
+	return i.store.AddDictionaryRefs(tx, paths)
+}
+
+func (i *Index) releaseDictionaryRefs(tx *bbolt.Tx, b *metastorev1.BlockMeta, releasedAt int64) ([]ReleasedDictionary, error) {
+	paths := blockDictionaries(b)
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	released, err := i.store.ReleaseDictionaryRefs(tx, paths, releasedAt)
+	if err != nil || len(released) == 0 {
+		return nil, err
+	}
+	dictionaries := make([]ReleasedDictionary, len(released))
+	for j, p := range released {
+		dictionaries[j] = ReleasedDictionary{Shard: b.Shard, Tenant: b.TenantId, Path: p}
+	}
+	return dictionaries, nil
+}
+
+// checkDictionariesNotReleased rejects the compacted blocks
+// that refer to the dictionaries that have been released.
+func (i *Index) checkDictionariesNotReleased(tx *bbolt.Tx, compacted *metastorev1.CompactedBlocks) error {
+	for _, b := range compacted.NewBlocks {
+		for _, ds := range b.Datasets {
+			if d := ds.SymbolsDictionary; d != nil && i.store.DictionaryReleased(tx, d.Path) {
+				return &InvalidBlockError{Block: b.Id, Reason: DictionaryReleased, Dataset: ds.Name}
+			}
+		}
+	}
+	return nil
+}
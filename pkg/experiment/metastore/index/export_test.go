@@ -0,0 +1,8 @@
+package index
+
+// Exported for testing.
+var (
+	PartitionEntrySize = uint64(partitionEntrySize)
+	ShardEntrySize     = uint64(shardEntrySize)
+	BlockEntrySize     = blockEntrySize
+)
@@ -2,6 +2,7 @@ package index
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"slices"
@@ -12,63 +13,243 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
 	"go.etcd.io/bbolt"
 	"golang.org/x/sync/errgroup"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+	"github.com/grafana/pyroscope/pkg/iter"
 )
 
 var ErrBlockExists = fmt.Errorf("block already exists")
 
+// BlockExistsError is returned when the block is already in the index.
+// It matches ErrBlockExists.
+type BlockExistsError struct {
+	// Block is the metadata of the block in the index.
+	Block *metastorev1.BlockMeta
+}
+
+func (e *BlockExistsError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrBlockExists, e.Block.Id)
+}
+
+func (e *BlockExistsError) Unwrap() error { return ErrBlockExists }
+
 type Store interface {
 	CreateBuckets(*bbolt.Tx) error
 	StoreBlock(*bbolt.Tx, store.PartitionKey, *metastorev1.BlockMeta) error
 	DeleteBlockList(*bbolt.Tx, store.PartitionKey, *metastorev1.BlockList) error
+	MovePartition(tx *bbolt.Tx, source, target store.PartitionKey) (int, error)
+	SplitPartition(tx *bbolt.Tx, source store.PartitionKey, d time.Duration) ([]store.PartitionKey, int, error)
+	DeleteTenantBlocks(tx *bbolt.Tx, p store.PartitionKey, tenant string) ([]*metastorev1.BlockMeta, bool, error)
 
 	ListPartitions(*bbolt.Tx) []store.PartitionKey
 	ListShards(*bbolt.Tx, store.PartitionKey) []uint32
 	ListTenants(tx *bbolt.Tx, p store.PartitionKey, shard uint32) []string
 	ListBlocks(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string) []*metastorev1.BlockMeta
+	IterateBlocks(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string, after string) iter.Iterator[*metastorev1.BlockMeta]
+
+	CheckPartitions(*bbolt.Tx) []store.PartitionIssue
+	RepairPartitions(*bbolt.Tx, []store.PartitionIssue, func(string) time.Duration) error
+
+	ArchivePartition(*bbolt.Tx, *raft_log.ArchivedPartition) error
+	DeleteArchivedPartition(*bbolt.Tx, store.PartitionKey) error
+	ListArchivedPartitions(*bbolt.Tx) []*raft_log.ArchivedPartition
+
+	PartitionScheme(*bbolt.Tx) string
+	StorePartitionScheme(*bbolt.Tx, string) error
+	RecompressBlocks(*bbolt.Tx) (int, error)
+
+	StoreDeletedBlocks(*bbolt.Tx, []store.DeletedBlock) error
+	DeleteDeletedBlocks(*bbolt.Tx, []store.DeletedBlock) error
+	ListDeletedBlocks(tx *bbolt.Tx, before int64, limit int) ([]store.DeletedBlock, error)
+
+	StorePendingBlock(*bbolt.Tx, store.PartitionKey, *metastorev1.BlockMeta) error
+	FlushPendingBlocks(*bbolt.Tx) (int, error)
+
+	AddDictionaryRefs(tx *bbolt.Tx, paths []string) error
+	ReleaseDictionaryRefs(tx *bbolt.Tx, paths []string, releasedAt int64) ([]string, error)
+	DictionaryReleased(tx *bbolt.Tx, path string) bool
+	DeleteReleasedDictionaries(tx *bbolt.Tx, before int64) error
 }
 
 type Index struct {
 	config *Config
 
-	partitionMu      sync.Mutex
+	// partitionMu guards the partition metadata and the contents of the
+	// loaded partitions: the queries share the lock, while the changes
+	// to the index take it exclusively.
+	partitionMu sync.RWMutex
+	partitions  partitionList
+
+	// cacheMu guards the partition cache, which is also modified by the
+	// readers holding partitionMu shared, as the partitions are loaded on
+	// demand. Holding partitionMu exclusively is sufficient to access the
+	// cache without cacheMu. cacheMu is never held while acquiring
+	// partitionMu.
+	cacheMu          sync.Mutex
 	loadedPartitions map[cacheKey]*indexPartition
-	allPartitions    []*PartitionMeta
-
-	store  Store
-	logger log.Logger
+	// loadedBytes approximates the memory footprint of the loaded
+	// partitions: the block metadata and the shard maps.
+	loadedBytes uint64
+	// The cache limits may be changed at runtime.
+	cacheSize  int
+	cacheBytes uint64
+
+	// pending blocks are not stored yet, in the write-behind mode.
+	pending []pendingBlock
+	// changes made to the blocks, streamed to the watchers.
+	changes *changeLog
+
+	store   Store
+	scheme  store.PartitionScheme
+	logger  log.Logger
+	metrics *metrics
 }
 
 type Config struct {
-	PartitionDuration     time.Duration `yaml:"partition_duration"`
-	PartitionCacheSize    int           `yaml:"partition_cache_size"`
-	QueryLookaroundPeriod time.Duration `yaml:"query_lookaround_period"`
+	PartitionDuration          time.Duration `yaml:"partition_duration"`
+	PartitionScheme            string        `yaml:"partition_scheme"`
+	PartitionCacheSize         int           `yaml:"partition_cache_size"`
+	PartitionCacheBytes        uint64        `yaml:"partition_cache_bytes"`
+	PartitionCacheRetainPeriod time.Duration `yaml:"partition_cache_retain_period"`
+	QueryLookaroundPeriod      time.Duration `yaml:"query_lookaround_period"`
+	RepairPartitions           bool          `yaml:"repair_partitions"`
+	MaxBlockClockSkew          time.Duration `yaml:"max_block_clock_skew"`
+	RestampSkewedBlocks        bool          `yaml:"restamp_skewed_blocks"`
+
+	PartitionArchiveAfter         time.Duration `yaml:"partition_archive_after"`
+	PartitionArchiveCheckInterval time.Duration `yaml:"partition_archive_check_interval"`
+	PartitionMergeInterval        time.Duration `yaml:"partition_merge_interval"`
+	PartitionMaxBlocks            int           `yaml:"partition_max_blocks"`
+	PartitionMaxSize              uint64        `yaml:"partition_max_size"`
+	PartitionBlockFilterSize      int           `yaml:"partition_block_filter_size"`
+	PartitionRetentionInterval    time.Duration `yaml:"partition_retention_interval"`
+
+	BlockWriteBehindInterval  time.Duration `yaml:"block_write_behind_interval"`
+	BlockWriteBehindQueueSize int           `yaml:"block_write_behind_queue_size"`
+
+	BlockDeletionGracePeriod   time.Duration `yaml:"block_deletion_grace_period"`
+	BlockDeletionSweepInterval time.Duration `yaml:"block_deletion_sweep_interval"`
+
+	BlockCompression string `yaml:"block_compression"`
+
+	RebuildConcurrency int `yaml:"rebuild_concurrency"`
+	ChangeLogSize      int `yaml:"change_log_size"`
+
+	// Bucket is injected by the upstream caller: archived
+	// partitions are loaded from the bucket on demand.
+	Bucket objstore.BucketReader `yaml:"-"`
+	// Limits are injected by the upstream caller, optionally.
+	Limits Limits `yaml:"-"`
+}
+
+// RuntimeConfig is the index configuration that can be changed at runtime,
+// without restarting the metastore. Unset values fall back to the static
+// configuration.
+type RuntimeConfig struct {
+	PartitionCacheSize  *int    `yaml:"partition_cache_size"`
+	PartitionCacheBytes *uint64 `yaml:"partition_cache_bytes"`
+}
+
+func (c *RuntimeConfig) Validate() error {
+	if c.PartitionCacheSize != nil && *c.PartitionCacheSize < 0 {
+		return errors.New("partition cache size must not be negative")
+	}
+	return nil
+}
+
+// Limits are the tenant-specific settings of the index. The overrides must
+// be identical on all the replicas: blocks are partitioned by each of them.
+type Limits interface {
+	// MetastoreIndexPartitionDuration returns the duration of the partitions
+	// of the tenant blocks, or 0, if the configured duration applies.
+	MetastoreIndexPartitionDuration(tenant string) time.Duration
+	// MetastoreIndexRetentionPeriod returns how long the blocks of
+	// the tenant are kept, or 0, if they are kept indefinitely.
+	MetastoreIndexRetentionPeriod(tenant string) time.Duration
 }
 
 func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.DurationVar(&cfg.PartitionDuration, prefix+"partition-duration", DefaultConfig.PartitionDuration, "")
+	f.StringVar(&cfg.PartitionScheme, prefix+"partition-scheme", DefaultConfig.PartitionScheme, "Partition scheme of new index stores: 'time', 'tenant-hash:<n>' to spread the blocks of each tenant over n partitions per period, or 'shard'. The scheme is recorded in the store when it is created, and can't be changed afterwards.")
 	f.IntVar(&cfg.PartitionCacheSize, prefix+"partition-cache-size", DefaultConfig.PartitionCacheSize, "How many partitions to keep loaded in memory.")
-	f.DurationVar(&cfg.QueryLookaroundPeriod, prefix+"query-lookaround-period", DefaultConfig.QueryLookaroundPeriod, "")
+	f.DurationVar(&cfg.PartitionCacheRetainPeriod, prefix+"partition-cache-retain-period", DefaultConfig.PartitionCacheRetainPeriod, "If set, the partitions covering this period until now are kept loaded in memory, and the older partitions are unloaded once they have been used, regardless of the partition cache size. The partition cache bytes limit still applies. 0 to disable.")
+	f.Uint64Var(&cfg.PartitionCacheBytes, prefix+"partition-cache-bytes", DefaultConfig.PartitionCacheBytes, "Approximate memory footprint of the partitions loaded in memory, including the block metadata and the shard maps, in bytes, at which the least recently accessed partitions are unloaded before other partitions are loaded. 0 to disable.")
+	f.DurationVar(&cfg.QueryLookaroundPeriod, prefix+"query-lookaround-period", DefaultConfig.QueryLookaroundPeriod, "How far the data of archived index partitions is assumed to extend beyond the partition time range, if the partitions were archived before the time range of their data was recorded.")
+	f.BoolVar(&cfg.RepairPartitions, prefix+"repair-partitions", DefaultConfig.RepairPartitions, "Repair inconsistent index partitions at startup: empty and invalid entries are removed, and blocks of partitions with invalid keys are moved to the partitions they belong to. Should be enabled on all the replicas.")
+	f.DurationVar(&cfg.MaxBlockClockSkew, prefix+"max-block-clock-skew", DefaultConfig.MaxBlockClockSkew, "Maximum difference between the time of a new block identifier and the time the block is added to the metastore. Blocks exceeding the limit are rejected, unless re-stamping is enabled. 0 to disable.")
+	f.BoolVar(&cfg.RestampSkewedBlocks, prefix+"restamp-skewed-blocks", DefaultConfig.RestampSkewedBlocks, "Re-stamp identifiers of blocks exceeding the maximum clock skew with the time of the block data instead of rejecting them.")
+	f.DurationVar(&cfg.PartitionArchiveAfter, prefix+"partition-archive-after", DefaultConfig.PartitionArchiveAfter, "Index partitions older than this are moved to the object storage, and only loaded on demand. Archived partitions can not be modified: blocks that belong to them are rejected. 0 to disable.")
+	f.DurationVar(&cfg.PartitionArchiveCheckInterval, prefix+"partition-archive-check-interval", DefaultConfig.PartitionArchiveCheckInterval, "How often the leader checks for index partitions to archive.")
+	f.DurationVar(&cfg.PartitionMergeInterval, prefix+"partition-merge-interval", DefaultConfig.PartitionMergeInterval, "How often the leader migrates index partitions created before the partition duration was changed: shorter partitions are merged into, and longer partitions are split into partitions of the partition duration. 0 to disable.")
+	f.IntVar(&cfg.PartitionMaxBlocks, prefix+"partition-max-blocks", DefaultConfig.PartitionMaxBlocks, "Number of blocks in an index partition above which the blocks of the partition are compacted ahead of others. 0 to disable.")
+	f.Uint64Var(&cfg.PartitionMaxSize, prefix+"partition-max-size", DefaultConfig.PartitionMaxSize, "Total size of blocks in an index partition, in bytes, above which the blocks of the partition are compacted ahead of others. 0 to disable.")
+	f.DurationVar(&cfg.PartitionRetentionInterval, prefix+"partition-retention-interval", DefaultConfig.PartitionRetentionInterval, "How often the leader deletes the index partitions older than the retention period of their tenants, along with the block objects. Archived partitions are not deleted. 0 to disable.")
+	f.IntVar(&cfg.PartitionBlockFilterSize, prefix+"partition-block-filter-size", DefaultConfig.PartitionBlockFilterSize, "Size of the bloom filter of block identifiers kept in memory for each index partition, in bytes. Block lookups skip the partitions that can't include the block without loading them. The filter is effective for partitions of up to about size*0.8 blocks. 0 to disable.")
+	f.DurationVar(&cfg.BlockWriteBehindInterval, prefix+"block-write-behind-interval", DefaultConfig.BlockWriteBehindInterval, "How often the blocks added to the index are stored in the database, in batches. The blocks are added to the index in memory immediately, and are stored with the snapshots regardless of the interval. 0 to store the blocks synchronously, as they are added.")
+	f.DurationVar(&cfg.BlockDeletionGracePeriod, prefix+"block-deletion-grace-period", DefaultConfig.BlockDeletionGracePeriod, "How long the metadata of the blocks deleted from the index, e.g., compacted, is kept before it is removed. Deleted blocks are excluded from queries and compaction immediately, but can still be looked up by identifier.")
+	f.DurationVar(&cfg.BlockDeletionSweepInterval, prefix+"block-deletion-sweep-interval", DefaultConfig.BlockDeletionSweepInterval, "How often the leader removes the blocks deleted from the index before the grace period. 0 to disable.")
+	f.IntVar(&cfg.BlockWriteBehindQueueSize, prefix+"block-write-behind-queue-size", DefaultConfig.BlockWriteBehindQueueSize, "Maximum number of blocks pending to be stored in the write-behind mode. When the limit is reached, the pending blocks are stored synchronously with the block added.")
+	f.StringVar(&cfg.BlockCompression, prefix+"block-compression", DefaultConfig.BlockCompression, "Compression of the block metadata stored in the database: 'none', 'snappy', or 'zstd'. Blocks stored with another compression are re-encoded at startup. The setting only affects the database of the replica: the replicas may use different compressions.")
+	f.IntVar(&cfg.ChangeLogSize, prefix+"change-log-size", DefaultConfig.ChangeLogSize, "Number of the most recent block changes kept in memory for the watchers of the index: blocks added, replaced, and deleted. Watchers that fall behind by more changes have to list the blocks again. 0 to disable.")
+	f.IntVar(&cfg.RebuildConcurrency, prefix+"rebuild-concurrency", DefaultConfig.RebuildConcurrency, "Number of block objects read concurrently when the index is rebuilt from the object storage, unless specified in the request.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.BlockWriteBehindInterval > 0 && cfg.BlockWriteBehindQueueSize <= 0 {
+		return errors.New("block write-behind queue size must be positive")
+	}
+	if cfg.ChangeLogSize < 0 {
+		return errors.New("change log size must not be negative")
+	}
+	if cfg.BlockCompression != "" {
+		if err := store.ValidateBlockCompression(cfg.BlockCompression); err != nil {
+			return err
+		}
+	}
+	if cfg.PartitionScheme == "" {
+		return nil
+	}
+	_, err := store.ParsePartitionScheme(cfg.PartitionScheme)
+	return err
 }
 
 var DefaultConfig = Config{
 	PartitionDuration:     24 * time.Hour,
+	PartitionScheme:       store.TimePartitionSchemeName,
 	PartitionCacheSize:    7,
 	QueryLookaroundPeriod: time.Hour,
+
+	PartitionArchiveCheckInterval: time.Hour,
+	PartitionRetentionInterval:    time.Hour,
+	BlockWriteBehindQueueSize:     1024,
+	BlockDeletionGracePeriod:      10 * time.Minute,
+	BlockDeletionSweepInterval:    time.Minute,
+	BlockCompression:              store.BlockCompressionNone,
+	RebuildConcurrency:            16,
+	ChangeLogSize:                 4096,
 }
 
 type indexPartition struct {
 	meta       *PartitionMeta
 	accessedAt time.Time
 	shards     map[uint32]*indexShard
+	// size is the approximate memory footprint of the partition, and
+	// cached indicates whether the partition is loaded in memory.
+	size   uint64
+	cached bool
 }
 
 type indexShard struct {
 	blocks map[string]*metastorev1.BlockMeta
+	// datasets is the secondary index of the shard blocks by the
+	// names of the datasets they include: dataset -> block ID -> block.
+	datasets map[string]map[string]*metastorev1.BlockMeta
 }
 
 type cacheKey struct {
@@ -83,22 +264,26 @@ type cacheKey struct {
 // between 2024-09-23T16:00:00.000Z and 2024-09-23T16:59:59.999Z.
 //
 // Partitions are mostly transparent for the end user, though PartitionMeta is at times used externally. Partition
-// durations are configurable (at application level).
+// durations are configurable (at application level). The partition scheme may add a prefix to the identifiers, e.g.,
+// "t3-20240923T16.1h" (see store.PartitionScheme); the scheme in use is recorded in the store.
 //
 // The index requires a backing Store for loading data in memory. Data is loaded directly via LoadPartitions() or when
 // looking up blocks with FindBlock() or FindBlocksInRange().
-func NewIndex(logger log.Logger, store Store, cfg *Config) *Index {
+func NewIndex(logger log.Logger, store Store, cfg *Config, reg prometheus.Registerer) *Index {
 	// A fixed cache size gives us bounded memory footprint, however changes to the partition duration could reduce
 	// the cache effectiveness.
-	// TODO (aleks-p):
-	//  - resize the cache at runtime when the config changes
-	//  - consider auto-calculating the cache size to ensure we hold data for e.g., the last 24 hours
+	// Alternatively, the partitions of a time period are kept, see PartitionCacheRetainPeriod.
 	return &Index{
 		loadedPartitions: make(map[cacheKey]*indexPartition, cfg.PartitionCacheSize),
-		allPartitions:    make([]*PartitionMeta, 0),
+		cacheSize:        cfg.PartitionCacheSize,
+		cacheBytes:       cfg.PartitionCacheBytes,
+		partitions:       newPartitionList(),
+		changes:          newChangeLog(cfg.ChangeLogSize),
 		store:            store,
+		scheme:           configuredPartitionScheme(cfg),
 		logger:           logger,
 		config:           cfg,
+		metrics:          newMetrics(reg),
 	}
 }
 
@@ -106,13 +291,24 @@ func NewStore() *store.IndexStore {
 	return store.NewIndexStore()
 }
 
+// NewStoreWithConfig creates the index store with the block
+// compression configured.
+func NewStoreWithConfig(cfg *Config) *store.IndexStore {
+	s := store.NewIndexStore()
+	s.BlockCompression = cfg.BlockCompression
+	return s
+}
+
 // LoadPartitions reads all partitions from the backing store and loads the recent ones in memory.
 func (i *Index) LoadPartitions(tx *bbolt.Tx) {
 	i.partitionMu.Lock()
 	defer i.partitionMu.Unlock()
 
-	i.allPartitions = i.allPartitions[:0]
-	clear(i.loadedPartitions)
+	i.clearPartitions()
+	// The state is loaded from the database: the blocks pending, if
+	// any, have been stored in their partitions at initialization.
+	i.dropPendingBlocks()
+	partitions := make([]*PartitionMeta, 0)
 	for _, key := range i.store.ListPartitions(tx) {
 		pMeta := i.loadPartitionMeta(tx, key)
 		level.Info(i.logger).Log(
@@ -121,16 +317,19 @@ func (i *Index) LoadPartitions(tx *bbolt.Tx) {
 			"ts", pMeta.Ts.Format(time.RFC3339),
 			"duration", pMeta.Duration,
 			"tenants", strings.Join(pMeta.Tenants, ","))
-		i.allPartitions = append(i.allPartitions, pMeta)
+		partitions = append(partitions, pMeta)
 
-		// load the currently active partition
-		if pMeta.contains(time.Now().UTC().UnixMilli()) {
+		// load the currently active partition, or the partitions
+		// of the retain period
+		if i.retainPartition(pMeta, time.Now().UTC().UnixMilli()) {
 			i.loadEntirePartition(tx, pMeta)
 		}
 	}
-	level.Info(i.logger).Log("msg", "loaded metastore index partitions", "count", len(i.allPartitions))
-
-	i.sortPartitions()
+	for _, archived := range i.store.ListArchivedPartitions(tx) {
+		partitions = append(partitions, archivedPartitionMeta(archived))
+	}
+	i.partitions.set(partitions)
+	level.Info(i.logger).Log("msg", "loaded metastore index partitions", "count", len(partitions))
 }
 
 func (i *Index) loadPartitionMeta(tx *bbolt.Tx, key store.PartitionKey) *PartitionMeta {
@@ -141,10 +340,25 @@ func (i *Index) loadPartitionMeta(tx *bbolt.Tx, key store.PartitionKey) *Partiti
 		Duration:  dur,
 		Tenants:   make([]string, 0),
 		tenantMap: make(map[string]struct{}),
+		filter:    newBlockFilter(i.config.PartitionBlockFilterSize),
 	}
 	for _, s := range i.store.ListShards(tx, key) {
 		for _, t := range i.store.ListTenants(tx, key, s) {
 			pMeta.AddTenant(t)
+			for _, b := range i.store.ListBlocks(tx, key, s, t) {
+				pMeta.addBlock(b)
+				if t != "" {
+					continue
+				}
+				// Blocks that include data of multiple tenants are stored
+				// with an empty tenant: the tenants are added the same way
+				// they are when the blocks are inserted, as the partition
+				// may not include blocks of the tenants otherwise, which
+				// is typical for partitions of the tenant-hash scheme.
+				for _, ds := range b.Datasets {
+					pMeta.AddTenant(ds.TenantId)
+				}
+			}
 		}
 	}
 	return pMeta
@@ -153,11 +367,11 @@ func (i *Index) loadPartitionMeta(tx *bbolt.Tx, key store.PartitionKey) *Partiti
 // ForEachPartition executes the given function concurrently for each partition. It will be called for all partitions,
 // regardless if they are fully loaded in memory or not.
 func (i *Index) ForEachPartition(ctx context.Context, fn func(meta *PartitionMeta) error) error {
-	i.partitionMu.Lock()
-	defer i.partitionMu.Unlock()
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
 
 	g, ctx := errgroup.WithContext(ctx)
-	for _, meta := range i.allPartitions {
+	for _, meta := range i.partitions.all() {
 		g.Go(func() error {
 			return fn(meta)
 		})
@@ -179,102 +393,158 @@ func (i *Index) loadEntirePartition(tx *bbolt.Tx, meta *PartitionMeta) {
 			}
 			p, ok := i.loadedPartitions[cKey]
 			if !ok {
-				p = &indexPartition{
-					meta:       meta,
-					accessedAt: time.Now(),
-					shards:     make(map[uint32]*indexShard),
-				}
-				i.loadedPartitions[cKey] = p
-			}
-			sh, ok := p.shards[s]
-			if !ok {
-				sh = &indexShard{
-					blocks: make(map[string]*metastorev1.BlockMeta),
-				}
-				p.shards[s] = sh
+				i.evictPartitions()
+				p = newIndexPartition(meta, time.Now())
+				i.cachePartition(cKey, p)
 			}
+			sh := i.getOrCreateShard(p, s)
 			for _, b := range i.store.ListBlocks(tx, meta.Key, s, t) {
-				sh.blocks[b.Id] = b
+				i.putBlock(p, sh, b)
 			}
 		}
 	}
 }
 
 func (i *Index) getOrLoadPartition(tx *bbolt.Tx, meta *PartitionMeta, tenant string) *indexPartition {
+	if meta.archive != nil && tx != nil && tx.Writable() {
+		// The raft commands must not depend on the bucket, nor on whether
+		// the replica has the archive cached: the blocks of archived
+		// partitions are not visible to the commands modifying the index.
+		return newIndexPartition(meta, time.Time{})
+	}
 	cKey := cacheKey{
 		partitionKey: meta.Key,
 		tenant:       tenant,
 	}
+	i.cacheMu.Lock()
 	p, ok := i.loadedPartitions[cKey]
+	i.cacheMu.Unlock()
 	if !ok {
-		p = &indexPartition{
-			meta:   meta,
-			shards: make(map[uint32]*indexShard),
+		// The partition is loaded without holding the cache lock, so that
+		// the concurrent queries are not blocked. If the same partition is
+		// loaded concurrently, the first one cached is used.
+		if meta.archive != nil {
+			p = i.loadArchivedPartition(meta, tenant)
+		} else {
+			p = i.loadPartition(tx, meta, tenant)
 		}
-		for _, s := range i.store.ListShards(tx, meta.Key) {
-			sh := &indexShard{
-				blocks: make(map[string]*metastorev1.BlockMeta),
-			}
-			p.shards[s] = sh
-			for _, b := range i.store.ListBlocks(tx, meta.Key, s, tenant) {
-				sh.blocks[b.Id] = b
-			}
+		if p == nil {
+			// The partition is not cached, so that
+			// the next attempt to load it is made.
+			return newIndexPartition(meta, time.Time{})
 		}
-		i.loadedPartitions[cKey] = p
+	}
+	i.cacheMu.Lock()
+	defer i.cacheMu.Unlock()
+	if !ok {
+		p = i.cacheLoadedPartition(cKey, p)
 	}
 	p.accessedAt = time.Now().UTC()
 	i.unloadPartitions()
 	return p
 }
 
-// findPartitionMeta retrieves the partition meta for the given key.
-func (i *Index) findPartitionMeta(key store.PartitionKey) *PartitionMeta {
-	for _, p := range i.allPartitions {
-		if p.Key == key {
-			return p
+func (i *Index) loadPartition(tx *bbolt.Tx, meta *PartitionMeta, tenant string) *indexPartition {
+	p := newIndexPartition(meta, time.Time{})
+	for _, s := range i.store.ListShards(tx, meta.Key) {
+		sh := i.getOrCreateShard(p, s)
+		for _, b := range i.store.ListBlocks(tx, meta.Key, s, tenant) {
+			i.putBlock(p, sh, b)
 		}
 	}
-	return nil
+	i.putPendingBlocks(p, tenant)
+	return p
+}
+
+func (i *Index) findPartitionMeta(key store.PartitionKey) *PartitionMeta {
+	return i.partitions.get(key)
 }
 
+// InsertBlock validates the block metadata and adds it to the index. If the
+// metadata is invalid, an *InvalidBlockError is returned. If the block is
+// already in the index, a *BlockExistsError is returned.
 func (i *Index) InsertBlock(tx *bbolt.Tx, b *metastorev1.BlockMeta) error {
+	if err := ValidateBlock(b); err != nil {
+		i.metrics.observeValidation(err)
+		return err
+	}
 	i.partitionMu.Lock()
 	defer i.partitionMu.Unlock()
-	if x := i.findBlock(tx, b.Shard, b.TenantId, b.Id); x != nil {
-		return ErrBlockExists
+	return i.insertValidBlock(tx, b)
+}
+
+// InsertBlocks is like InsertBlock, but the blocks are inserted at once.
+// The errors are returned in the order of the blocks; the error is nil
+// if the block has been inserted. Unlike the validation errors, any other
+// error indicates that the transaction is to be aborted.
+func (i *Index) InsertBlocks(tx *bbolt.Tx, blocks []*metastorev1.BlockMeta) []error {
+	errs := make([]error, len(blocks))
+	for j, b := range blocks {
+		if errs[j] = ValidateBlock(b); errs[j] != nil {
+			i.metrics.observeValidation(errs[j])
+		}
+	}
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	for j, b := range blocks {
+		if errs[j] == nil {
+			errs[j] = i.insertValidBlock(tx, b)
+		}
 	}
-	i.insertBlock(tx, b)
-	pk := store.CreatePartitionKey(b.Id, i.config.PartitionDuration)
-	return i.store.StoreBlock(tx, pk, b)
+	return errs
+}
+
+func (i *Index) insertValidBlock(tx *bbolt.Tx, b *metastorev1.BlockMeta) error {
+	pk := i.partitionKey(b.Id, b.Shard, b.TenantId)
+	if err := i.checkNotArchived(b.Id, pk); err != nil {
+		i.metrics.observeValidation(err)
+		return err
+	}
+	// A deleted block is not inserted again until it is swept.
+	if x := i.findBlock(tx, b.Shard, b.TenantId, b.Id, true); x != nil {
+		return &BlockExistsError{Block: x}
+	}
+	if meta, added := i.insertBlock(tx, b); added {
+		i.addPartitionBlock(meta, b)
+	}
+	i.changes.append(blocksAddedChange(b))
+	var err error
+	if i.writeBehind() {
+		err = i.storeBlockBehind(tx, pk, b)
+	} else {
+		err = i.store.StoreBlock(tx, pk, b)
+	}
+	if err != nil {
+		return err
+	}
+	return i.addDictionaryRefs(tx, b)
 }
 
 func (i *Index) InsertBlockNoCheckNoPersist(tx *bbolt.Tx, b *metastorev1.BlockMeta) error {
 	i.partitionMu.Lock()
 	defer i.partitionMu.Unlock()
-	i.insertBlock(tx, b)
+	if meta, added := i.insertBlock(tx, b); added {
+		i.addPartitionBlock(meta, b)
+	}
 	return nil
 }
 
 // insertBlock is the underlying implementation for inserting blocks. It is the caller's responsibility to enforce safe
-// concurrent access. The method will create a new partition if needed.
-func (i *Index) insertBlock(tx *bbolt.Tx, b *metastorev1.BlockMeta) {
+// concurrent access. The method will create a new partition if needed. The partition the block is inserted to is
+// returned, and whether the block was not there before. The partition block stats are to be updated by the caller.
+func (i *Index) insertBlock(tx *bbolt.Tx, b *metastorev1.BlockMeta) (*PartitionMeta, bool) {
 	meta := i.getOrCreatePartitionMeta(b)
 	p := i.getOrLoadPartition(tx, meta, b.TenantId)
-	s, ok := p.shards[b.Shard]
+	s := i.getOrCreateShard(p, b.Shard)
+	_, ok := s.blocks[b.Id]
 	if !ok {
-		s = &indexShard{
-			blocks: make(map[string]*metastorev1.BlockMeta),
-		}
-		p.shards[b.Shard] = s
-	}
-	_, ok = s.blocks[b.Id]
-	if !ok {
-		s.blocks[b.Id] = b
+		i.putBlock(p, s, b)
 	}
+	return meta, !ok
 }
 
 func (i *Index) getOrCreatePartitionMeta(b *metastorev1.BlockMeta) *PartitionMeta {
-	key := store.CreatePartitionKey(b.Id, i.config.PartitionDuration)
+	key := i.partitionKey(b.Id, b.Shard, b.TenantId)
 	meta := i.findPartitionMeta(key)
 
 	if meta == nil {
@@ -285,9 +555,9 @@ func (i *Index) getOrCreatePartitionMeta(b *metastorev1.BlockMeta) *PartitionMet
 			Duration:  duration,
 			Tenants:   make([]string, 0),
 			tenantMap: make(map[string]struct{}),
+			filter:    newBlockFilter(i.config.PartitionBlockFilterSize),
 		}
-		i.allPartitions = append(i.allPartitions, meta)
-		i.sortPartitions()
+		i.partitions.add(meta)
 	}
 
 	if b.TenantId != "" {
@@ -311,9 +581,9 @@ func (i *Index) getOrCreatePartitionMetaForCacheKey(k cacheKey) *PartitionMeta {
 			Duration:  duration,
 			Tenants:   make([]string, 0),
 			tenantMap: make(map[string]struct{}),
+			filter:    newBlockFilter(i.config.PartitionBlockFilterSize),
 		}
-		i.allPartitions = append(i.allPartitions, meta)
-		i.sortPartitions()
+		i.partitions.add(meta)
 	}
 	return meta
 }
@@ -321,19 +591,86 @@ func (i *Index) getOrCreatePartitionMetaForCacheKey(k cacheKey) *PartitionMeta {
 // FindBlock tries to retrieve an existing block from the index. It will load the corresponding partition if it is not
 // already loaded. Returns nil if the block cannot be found.
 func (i *Index) FindBlock(tx *bbolt.Tx, shardNum uint32, tenant string, blockId string) *metastorev1.BlockMeta {
-	i.partitionMu.Lock()
-	defer i.partitionMu.Unlock()
-	return i.findBlock(tx, shardNum, tenant, blockId)
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	return i.findBlock(tx, shardNum, tenant, blockId, false)
+}
+
+// ContainsBlock reports whether the block may be present in the index,
+// including the blocks marked as deleted. The blocks of archived partitions
+// are not visible to the raft commands, therefore such blocks are assumed
+// to be present.
+func (i *Index) ContainsBlock(tx *bbolt.Tx, shardNum uint32, tenant string, blockId string) bool {
+	id, err := ulid.Parse(blockId)
+	if err != nil {
+		return false
+	}
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	if _, s := i.findBlockShard(tx, shardNum, tenant, blockId, true); s != nil {
+		return true
+	}
+	t := ulid.Time(id.Time()).UTC().UnixMilli()
+	for _, p := range i.partitions.candidates(t, t) {
+		if p.archive != nil && p.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindBlockByID retrieves a block regardless of the shard and tenant it belongs to, and the partition it is stored in.
+// It will load the corresponding partitions if they are not already loaded. Returns nil if the block cannot be found.
+func (i *Index) FindBlockByID(tx *bbolt.Tx, blockId string) (*metastorev1.BlockMeta, *PartitionMeta) {
+	id, err := ulid.Parse(blockId)
+	if err != nil {
+		return nil, nil
+	}
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+
+	// The mapped partition is checked first. The shard and tenant are not
+	// known, therefore the block is mapped as one that includes data of
+	// multiple tenants; other partitions covering the time are checked next.
+	key := i.partitionKey(blockId, 0, "")
+	candidates := make([]*PartitionMeta, 0, 2)
+	if meta := i.findPartitionMeta(key); meta != nil {
+		candidates = append(candidates, meta)
+	}
+	t := ulid.Time(id.Time()).UTC().UnixMilli()
+	for _, p := range i.partitions.candidates(t, t) {
+		if p.Key != key && p.contains(t) {
+			candidates = append(candidates, p)
+		}
+	}
+
+	for _, meta := range candidates {
+		if !meta.mayContainBlock(blockId) {
+			continue
+		}
+		// Blocks that include data of multiple tenants are stored with an empty tenant.
+		tenants := append([]string{""}, meta.Tenants...)
+		for _, tenant := range tenants {
+			p := i.getOrLoadPartition(tx, meta, tenant)
+			for _, s := range p.shards {
+				if b := s.blocks[blockId]; b != nil {
+					return b, meta
+				}
+			}
+		}
+	}
+
+	return nil, nil
 }
 
 func (i *Index) FindBlocks(tx *bbolt.Tx, list *metastorev1.BlockList) []*metastorev1.BlockMeta {
-	i.partitionMu.Lock()
-	defer i.partitionMu.Unlock()
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
 
 	pk := make(map[store.PartitionKey]struct{})
 	left := make(map[string]struct{})
 	for _, block := range list.Blocks {
-		pk[store.CreatePartitionKey(block, i.config.PartitionDuration)] = struct{}{}
+		pk[i.partitionKey(block, list.Shard, list.Tenant)] = struct{}{}
 		left[block] = struct{}{}
 	}
 
@@ -349,7 +686,7 @@ func (i *Index) FindBlocks(tx *bbolt.Tx, list *metastorev1.BlockList) []*metasto
 			continue
 		}
 		for b := range left {
-			if block := s.blocks[b]; block != nil {
+			if block := s.blocks[b]; block != nil && block.DeletedAt == 0 {
 				found = append(found, block)
 				delete(left, b)
 			}
@@ -359,31 +696,48 @@ func (i *Index) FindBlocks(tx *bbolt.Tx, list *metastorev1.BlockList) []*metasto
 	return found
 }
 
-func (i *Index) findBlock(tx *bbolt.Tx, shardNum uint32, tenant string, blockId string) *metastorev1.BlockMeta {
-	key := store.CreatePartitionKey(blockId, i.config.PartitionDuration)
+func (i *Index) findBlock(tx *bbolt.Tx, shardNum uint32, tenant string, blockId string, withDeleted bool) *metastorev1.BlockMeta {
+	_, s := i.findBlockShard(tx, shardNum, tenant, blockId, withDeleted)
+	if s == nil {
+		return nil
+	}
+	return s.blocks[blockId]
+}
+
+// findBlockShard returns the shard that includes the block, and the key
+// of the partition the shard belongs to. Deleted blocks are only found if
+// withDeleted is true.
+func (i *Index) findBlockShard(tx *bbolt.Tx, shardNum uint32, tenant string, blockId string, withDeleted bool) (store.PartitionKey, *indexShard) {
+	key := i.partitionKey(blockId, shardNum, tenant)
 
 	// first try the currently mapped partition
-	b := i.findBlockInPartition(tx, key, shardNum, tenant, blockId)
-	if b != nil {
-		return b
+	if s := i.findBlockShardInPartition(tx, key, shardNum, tenant, blockId, withDeleted); s != nil {
+		return key, s
 	}
 
 	// try other partitions that could contain the block
 	t := ulid.Time(ulid.MustParse(blockId).Time()).UTC().UnixMilli()
-	for _, p := range i.allPartitions {
+	for _, p := range i.partitions.candidates(t, t) {
 		if p.contains(t) {
-			b := i.findBlockInPartition(tx, p.Key, shardNum, tenant, blockId)
-			if b != nil {
-				return b
+			if s := i.findBlockShardInPartition(tx, p.Key, shardNum, tenant, blockId, withDeleted); s != nil {
+				return p.Key, s
 			}
 		}
 	}
-	return nil
+	return "", nil
 }
 
-func (i *Index) findBlockInPartition(tx *bbolt.Tx, key store.PartitionKey, shard uint32, tenant string, blockId string) *metastorev1.BlockMeta {
+func (i *Index) findBlockInPartition(tx *bbolt.Tx, key store.PartitionKey, shard uint32, tenant string, blockId string, withDeleted bool) *metastorev1.BlockMeta {
+	s := i.findBlockShardInPartition(tx, key, shard, tenant, blockId, withDeleted)
+	if s == nil {
+		return nil
+	}
+	return s.blocks[blockId]
+}
+
+func (i *Index) findBlockShardInPartition(tx *bbolt.Tx, key store.PartitionKey, shard uint32, tenant string, blockId string, withDeleted bool) *indexShard {
 	meta := i.findPartitionMeta(key)
-	if meta == nil {
+	if meta == nil || !meta.mayContainBlock(blockId) {
 		return nil
 	}
 
@@ -394,9 +748,11 @@ func (i *Index) findBlockInPartition(tx *bbolt.Tx, key store.PartitionKey, shard
 		return nil
 	}
 
-	b, _ := s.blocks[blockId]
+	if b, ok := s.blocks[blockId]; !ok || (b.DeletedAt != 0 && !withDeleted) {
+		return nil
+	}
 
-	return b
+	return s
 }
 
 // FindBlocksInRange retrieves all blocks that might contain data for the given time range and tenants.
@@ -404,70 +760,285 @@ func (i *Index) findBlockInPartition(tx *bbolt.Tx, key store.PartitionKey, shard
 // It is not enough to scan for partition keys that fall in the given time interval. Partitions are built on top of
 // block identifiers which refer to the moment a block was created and not to the timestamps of the profiles contained
 // within the block (min_time, max_time). This method works around this by including blocks from adjacent partitions.
-func (i *Index) FindBlocksInRange(tx *bbolt.Tx, start, end int64, tenants map[string]struct{}) []*metastorev1.BlockMeta {
-	i.partitionMu.Lock()
-	defer i.partitionMu.Unlock()
-	startWithLookaround := start - i.config.QueryLookaroundPeriod.Milliseconds()
-	endWithLookaround := end + i.config.QueryLookaroundPeriod.Milliseconds()
+//
+// If profile types are specified, only blocks that might contain data of any of the profile types are included:
+// blocks with datasets that do not list their profile types are always included.
+//
+// If services are specified, only blocks that include a dataset of any of the services are included. The blocks
+// are looked up in the dataset index of the partition shards, rather than scanned.
+//
+// Quarantined blocks are not included.
+//
+// The blocks are not copied: they are shared with the index and must not be modified. The index never modifies
+// the block metadata entries in place either, but replaces them with modified copies, therefore the blocks remain
+// valid after the call, although they may become stale.
+func (i *Index) FindBlocksInRange(tx *bbolt.Tx, start, end int64, tenants, profileTypes, services map[string]struct{}) []*metastorev1.BlockMeta {
+	return i.findBlocksInRange(tx, start, end, tenants, profileTypes, services, false)
+}
+
+// FindQuarantinedBlocksInRange is like FindBlocksInRange, but only quarantined blocks are included.
+func (i *Index) FindQuarantinedBlocksInRange(tx *bbolt.Tx, start, end int64, tenants map[string]struct{}) []*metastorev1.BlockMeta {
+	return i.findBlocksInRange(tx, start, end, tenants, nil, nil, true)
+}
+
+func (i *Index) findBlocksInRange(tx *bbolt.Tx, start, end int64, tenants, profileTypes, services map[string]struct{}, quarantined bool) []*metastorev1.BlockMeta {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
 
 	blocks := make([]*metastorev1.BlockMeta, 0)
 
-	for _, meta := range i.allPartitions { // TODO aleks-p: consider using binary search to find a good starting point
-		if meta.overlaps(startWithLookaround, endWithLookaround) {
-			for t := range tenants {
-				if !meta.HasTenant(t) {
-					continue
-				}
-				p := i.getOrLoadPartition(tx, meta, t)
-				tenantBlocks := i.collectTenantBlocks(p, start, end)
-				blocks = append(blocks, tenantBlocks...)
-
-				// return mixed blocks as well, we rely on the caller to filter out the data per tenant / service
-				p = i.getOrLoadPartition(tx, meta, "")
-				tenantBlocks = i.collectTenantBlocks(p, start, end)
-				blocks = append(blocks, tenantBlocks...)
+	for _, meta := range i.partitionsInRange(start, end) {
+		for t := range tenants {
+			if !meta.HasTenant(t) {
+				continue
 			}
+			p := i.getOrLoadPartition(tx, meta, t)
+			tenantBlocks := i.collectTenantBlocks(p, start, end, profileTypes, services, quarantined)
+			blocks = append(blocks, tenantBlocks...)
+
+			// return mixed blocks as well, we rely on the caller to filter out the data per tenant / service
+			p = i.getOrLoadPartition(tx, meta, "")
+			tenantBlocks = i.collectTenantBlocks(p, start, end, profileTypes, services, quarantined)
+			blocks = append(blocks, tenantBlocks...)
 		}
 	}
 
 	return blocks
 }
 
-func (i *Index) sortPartitions() {
-	slices.SortFunc(i.allPartitions, func(a, b *PartitionMeta) int {
-		return a.compare(b)
-	})
+// partitionsInRange returns the partitions that might include data of the
+// time range, based on the time range of the partition blocks. The lookaround
+// period only applies to the partitions the data time range is not known for.
+func (i *Index) partitionsInRange(start, end int64) []*PartitionMeta {
+	lookaround := i.config.QueryLookaroundPeriod.Milliseconds()
+	partitions := make([]*PartitionMeta, 0)
+	for _, meta := range i.partitions.dataCandidates(start-lookaround, end+lookaround) {
+		if meta.mayIncludeData(start, end, lookaround) {
+			partitions = append(partitions, meta)
+		}
+	}
+	return partitions
 }
 
-func (i *Index) collectTenantBlocks(p *indexPartition, start, end int64) []*metastorev1.BlockMeta {
+// FindPartitionsInRange returns the partitions FindBlocksInRange would visit for the given time range and tenants,
+// without loading them. A partition is reported as cached if it is loaded in memory for all the tenants requested.
+func (i *Index) FindPartitionsInRange(start, end int64, tenants map[string]struct{}) []*metastorev1.PartitionInfo {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+
+	partitions := make([]*metastorev1.PartitionInfo, 0)
+	for _, meta := range i.partitionsInRange(start, end) {
+		cached := true
+		matches := false
+		for t := range tenants {
+			if !meta.HasTenant(t) {
+				continue
+			}
+			matches = true
+			for _, k := range []cacheKey{{partitionKey: meta.Key, tenant: t}, {partitionKey: meta.Key}} {
+				if !i.isCached(k) {
+					cached = false
+				}
+			}
+		}
+		if !matches {
+			continue
+		}
+		partitions = append(partitions, &metastorev1.PartitionInfo{
+			Key:       string(meta.Key),
+			StartTime: meta.StartTime().UnixMilli(),
+			EndTime:   meta.EndTime().UnixMilli(),
+			Cached:    cached,
+		})
+	}
+
+	return partitions
+}
+
+// ListPartitions returns the details of the partitions that include blocks
+// of the tenants, and overlap the time range. No tenants refers to all the
+// partitions; the zero time range refers to any time. The partitions are not
+// loaded: the loaded state reflects the partition cache of this replica.
+func (i *Index) ListPartitions(tx *bbolt.Tx, tenants []string, start, end int64) []*metastorev1.PartitionDetails {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+
+	candidates := i.partitions.all()
+	if start != 0 || end != 0 {
+		candidates = i.partitions.candidates(start, end)
+	}
+	partitions := make([]*metastorev1.PartitionDetails, 0)
+	for _, meta := range candidates {
+		if (start != 0 || end != 0) && !meta.overlaps(start, end) {
+			continue
+		}
+		if len(tenants) > 0 && !slices.ContainsFunc(tenants, meta.HasTenant) {
+			continue
+		}
+		p := &metastorev1.PartitionDetails{
+			Key:        string(meta.Key),
+			StartTime:  meta.StartTime().UnixMilli(),
+			EndTime:    meta.EndTime().UnixMilli(),
+			MinTime:    meta.MinTime,
+			MaxTime:    meta.MaxTime,
+			Tenants:    slices.Clone(meta.Tenants),
+			BlockCount: uint64(meta.BlockCount),
+			BlockSize:  meta.BlockSize,
+			Archived:   meta.Archived(),
+		}
+		if !p.Archived {
+			p.Shards = i.listShards(tx, meta.Key)
+		}
+		partitions = append(partitions, p)
+	}
+
+	loaded := make(map[string][]*metastorev1.LoadedPartition)
+	i.cacheMu.Lock()
+	for k, p := range i.loadedPartitions {
+		loaded[string(k.partitionKey)] = append(loaded[string(k.partitionKey)], &metastorev1.LoadedPartition{
+			TenantId:   k.tenant,
+			AccessedAt: p.accessedAt.UnixMilli(),
+			Size:       p.size,
+		})
+	}
+	i.cacheMu.Unlock()
+	for _, p := range partitions {
+		p.Loaded = loaded[p.Key]
+		slices.SortFunc(p.Loaded, func(a, b *metastorev1.LoadedPartition) int {
+			return strings.Compare(a.TenantId, b.TenantId)
+		})
+	}
+	return partitions
+}
+
+func (i *Index) collectTenantBlocks(p *indexPartition, start, end int64, profileTypes, services map[string]struct{}, quarantined bool) []*metastorev1.BlockMeta {
 	blocks := make([]*metastorev1.BlockMeta, 0)
 	for _, s := range p.shards {
-		for _, block := range s.blocks {
+		s.forEachBlock(services, func(block *metastorev1.BlockMeta) {
+			if (block.Quarantine != nil) != quarantined || block.DeletedAt != 0 {
+				return
+			}
+			if !HasProfileType(block, profileTypes) {
+				return
+			}
 			if start < block.MaxTime && end >= block.MinTime {
-				clone := block.CloneVT()
-				blocks = append(blocks, clone)
+				blocks = append(blocks, block)
 			}
-		}
+		})
 	}
 	return blocks
 }
 
+// HasProfileType reports whether the block might contain data of any of
+// the profile types. Datasets that do not list their profile types are
+// considered to contain data of any profile type. If no profile types
+// are specified, the function returns true.
+func HasProfileType(block *metastorev1.BlockMeta, profileTypes map[string]struct{}) bool {
+	if len(profileTypes) == 0 {
+		return true
+	}
+	for _, ds := range block.Datasets {
+		if len(ds.ProfileTypes) == 0 {
+			return true
+		}
+		for _, pt := range ds.ProfileTypes {
+			if _, ok := profileTypes[pt]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// QuarantineBlock marks the block as quarantined: the block is excluded from FindBlocksInRange results, but still
+// can be found by its identifier. The quarantine of a block that is already quarantined is not changed. Returns nil
+// if the block is not found.
+func (i *Index) QuarantineBlock(tx *bbolt.Tx, shard uint32, tenant string, blockId string, q *metastorev1.BlockQuarantine) (*metastorev1.BlockMeta, error) {
+	if _, err := ulid.Parse(blockId); err != nil {
+		return nil, nil
+	}
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	if err := i.flushPendingBlocks(tx); err != nil {
+		return nil, err
+	}
+	key, s := i.findBlockShard(tx, shard, tenant, blockId, false)
+	if s == nil {
+		return nil, nil
+	}
+	if meta := i.findPartitionMeta(key); meta != nil && meta.archive != nil {
+		// Archived partitions can not be modified.
+		return nil, nil
+	}
+	b := s.blocks[blockId]
+	if b.Quarantine != nil {
+		return b, nil
+	}
+	// The metadata entry is replaced rather than modified in place:
+	// the previous one might be still in use by readers, see
+	// FindBlocksInRange.
+	b = b.CloneVT()
+	b.Quarantine = q
+	if err := i.store.StoreBlock(tx, key, b); err != nil {
+		return nil, err
+	}
+	// The shard belongs to the partition loaded for the tenant.
+	i.putBlock(i.loadedPartitions[cacheKey{partitionKey: key, tenant: tenant}], s, b)
+	i.metrics.quarantinedBlocks.Inc()
+	return b, nil
+}
+
 // ReplaceBlocks removes source blocks from the index and inserts replacement blocks into the index. The intended usage
 // is for block compaction. The replacement blocks could be added to the same or a different partition.
-func (i *Index) ReplaceBlocks(tx *bbolt.Tx, compacted *metastorev1.CompactedBlocks) error {
+//
+// The source blocks are not removed immediately: they are marked as deleted at the given time, and are excluded
+// from queries and compaction, but can still be found by identifier until they are removed by SweepDeletedBlocks.
+//
+// The replacement blocks are validated before any changes are made: if any of them is invalid, the index is not
+// modified and an *InvalidBlockError is returned. The store is updated before the in-memory state: if the store
+// fails, the changes made to the store in the transaction are reverted, and the in-memory state is left intact.
+func (i *Index) ReplaceBlocks(tx *bbolt.Tx, compacted *metastorev1.CompactedBlocks, deletedAt time.Time) error {
+	if err := validateCompactedBlocks(compacted); err != nil {
+		i.metrics.observeValidation(err)
+		return err
+	}
 	i.partitionMu.Lock()
 	defer i.partitionMu.Unlock()
-	if err := i.insertBlocks(tx, compacted.NewBlocks); err != nil {
+	if err := i.checkCompactedBlocksNotArchived(compacted); err != nil {
+		i.metrics.observeValidation(err)
+		return err
+	}
+	if err := i.checkDictionariesNotReleased(tx, compacted); err != nil {
+		i.metrics.observeValidation(err)
+		return err
+	}
+	if err := i.flushPendingBlocks(tx); err != nil {
 		return err
 	}
-	return i.deleteBlockList(tx, compacted.SourceBlocks)
+	mutations, err := i.replaceStoredBlocks(tx, compacted, deletedAt.UnixMilli())
+	if err != nil {
+		return err
+	}
+	for _, b := range compacted.NewBlocks {
+		i.insertBlock(tx, b)
+	}
+	// The stats are updated according to the changes made to the store:
+	// the blocks might not be loaded in memory.
+	for _, m := range mutations {
+		i.updatePartitionStats(m)
+	}
+	i.markLoadedBlocksDeleted(mutations)
+	i.changes.append(blocksReplacedChanges(compacted)...)
+	return nil
 }
 
 func (i *Index) ReplaceBlocksNoCheckNoPersist(tx *bbolt.Tx, compacted *metastorev1.CompactedBlocks) error {
 	i.partitionMu.Lock()
 	defer i.partitionMu.Unlock()
 	for _, b := range compacted.NewBlocks {
-		i.insertBlock(tx, b)
+		if meta, added := i.insertBlock(tx, b); added {
+			i.addPartitionBlock(meta, b)
+		}
 	}
 	source := compacted.SourceBlocks
 	for _, b := range source.Blocks {
@@ -476,21 +1047,156 @@ func (i *Index) ReplaceBlocksNoCheckNoPersist(tx *bbolt.Tx, compacted *metastore
 	return nil
 }
 
-func (i *Index) insertBlocks(tx *bbolt.Tx, blocks []*metastorev1.BlockMeta) error {
-	for _, b := range blocks {
-		k := store.CreatePartitionKey(b.Id, i.config.PartitionDuration)
-		i.insertBlock(tx, b)
-		if err := i.store.StoreBlock(tx, k, b); err != nil {
+// storeMutation is a change of a block entry in the store.
+// The previous value is used to revert the change.
+type storeMutation struct {
+	key      store.PartitionKey
+	shard    uint32
+	tenant   string
+	block    string
+	previous *metastorev1.BlockMeta // nil if the block did not exist.
+	current  *metastorev1.BlockMeta // nil if the block is removed.
+}
+
+// updatePartitionStats updates the block stats of the partition
+// according to the change made to the store.
+func (i *Index) updatePartitionStats(m storeMutation) {
+	meta := i.findPartitionMeta(m.key)
+	if meta == nil {
+		return
+	}
+	if m.previous != nil {
+		meta.removeBlock(m.previous)
+	}
+	if m.current != nil {
+		i.addPartitionBlock(meta, m.current)
+	}
+}
+
+// addPartitionBlock updates the block stats and the data time range of the partition.
+func (i *Index) addPartitionBlock(meta *PartitionMeta, b *metastorev1.BlockMeta) {
+	meta.addBlock(b)
+	i.partitions.extend(meta)
+}
+
+// replaceStoredBlocks updates the store: either all the changes are applied, or none of them.
+// The source blocks are stored marked as deleted, and their tombstones are added. The references
+// of the new blocks to the symbols dictionaries are added last, as they are not reverted. The
+// changes made are returned.
+func (i *Index) replaceStoredBlocks(tx *bbolt.Tx, compacted *metastorev1.CompactedBlocks, deletedAt int64) (_ []storeMutation, err error) {
+	// The current state must be captured before the store is modified:
+	// the lookup may load partitions in memory, and they must not include
+	// changes that could be reverted.
+	stored := make([]storeMutation, len(compacted.NewBlocks))
+	for j, b := range compacted.NewBlocks {
+		k := i.partitionKey(b.Id, b.Shard, b.TenantId)
+		stored[j] = storeMutation{
+			key:      k,
+			shard:    b.Shard,
+			tenant:   b.TenantId,
+			block:    b.Id,
+			previous: i.findBlockInPartition(tx, k, b.Shard, b.TenantId, b.Id, true),
+			current:  b,
+		}
+	}
+	source := compacted.SourceBlocks
+	partitions := i.partitionBlockList(source)
+	deleted := make([]storeMutation, 0, len(source.GetBlocks()))
+	tombstones := make([]store.DeletedBlock, 0, len(source.GetBlocks()))
+	for k, list := range partitions {
+		for _, b := range list.Blocks {
+			previous := i.findBlockInPartition(tx, k, list.Shard, list.Tenant, b, false)
+			if previous == nil {
+				// The block does not exist or has already been deleted.
+				continue
+			}
+			current := previous.CloneVT()
+			current.DeletedAt = deletedAt
+			deleted = append(deleted, storeMutation{
+				key:      k,
+				shard:    list.Shard,
+				tenant:   list.Tenant,
+				block:    b,
+				previous: previous,
+				current:  current,
+			})
+			tombstones = append(tombstones, store.DeletedBlock{
+				DeletedAt: deletedAt,
+				Block:     b,
+				Shard:     list.Shard,
+				Tenant:    list.Tenant,
+			})
+		}
+	}
+
+	// Mutations are considered applied once attempted: reverting
+	// a change that has not been made is a no-op.
+	applied := make([]storeMutation, 0, len(stored)+len(deleted))
+	defer func() {
+		if err == nil {
+			return
+		}
+		if revertErr := i.revertStoreMutations(tx, applied); revertErr != nil {
+			level.Error(i.logger).Log("msg", "failed to revert metastore index changes", "err", revertErr)
+			err = fmt.Errorf("%w; failed to revert changes: %w", err, revertErr)
+		}
+	}()
+	for j, b := range compacted.NewBlocks {
+		applied = append(applied, stored[j])
+		if err = i.store.StoreBlock(tx, stored[j].key, b); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range deleted {
+		applied = append(applied, m)
+		if err = i.store.StoreBlock(tx, m.key, m.current); err != nil {
+			return nil, err
+		}
+	}
+	// Tombstones are not reverted: a tombstone of a block that
+	// is not marked as deleted is ignored by the sweep.
+	if err = i.store.StoreDeletedBlocks(tx, tombstones); err != nil {
+		return nil, err
+	}
+	for j, b := range compacted.NewBlocks {
+		if stored[j].previous != nil {
+			continue
+		}
+		if err = i.addDictionaryRefs(tx, b); err != nil {
+			return nil, err
+		}
+	}
+	return applied, nil
+}
+
+func (i *Index) revertStoreMutations(tx *bbolt.Tx, mutations []storeMutation) error {
+	for j := len(mutations) - 1; j >= 0; j-- {
+		m := mutations[j]
+		var err error
+		if m.previous != nil {
+			err = i.store.StoreBlock(tx, m.key, m.previous)
+		} else {
+			err = i.store.DeleteBlockList(tx, m.key, &metastorev1.BlockList{
+				Shard:  m.shard,
+				Tenant: m.tenant,
+				Blocks: []string{m.block},
+			})
+		}
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (i *Index) deleteBlockList(tx *bbolt.Tx, list *metastorev1.BlockList) error {
+// partitionBlockList splits the block list by partitions the blocks belong to.
+func (i *Index) partitionBlockList(list *metastorev1.BlockList) map[store.PartitionKey]*metastorev1.BlockList {
 	partitions := make(map[store.PartitionKey]*metastorev1.BlockList)
+	if list == nil {
+		return partitions
+	}
 	for _, block := range list.Blocks {
-		k := store.CreatePartitionKey(block, i.config.PartitionDuration)
+		k := i.partitionKey(block, list.Shard, list.Tenant)
 		v := partitions[k]
 		if v == nil {
 			v = &metastorev1.BlockList{
@@ -502,30 +1208,30 @@ func (i *Index) deleteBlockList(tx *bbolt.Tx, list *metastorev1.BlockList) error
 		}
 		v.Blocks = append(v.Blocks, block)
 	}
-	for k, partitioned := range partitions {
-		if err := i.store.DeleteBlockList(tx, k, partitioned); err != nil {
-			return err
-		}
-		ck := cacheKey{partitionKey: k, tenant: list.Tenant}
-		loaded := i.loadedPartitions[ck]
-		if loaded == nil {
+	return partitions
+}
+
+// markLoadedBlocksDeleted replaces the source blocks in the partitions
+// loaded in memory with the blocks marked as deleted.
+func (i *Index) markLoadedBlocksDeleted(mutations []storeMutation) {
+	for _, m := range mutations {
+		if m.current == nil || m.current.DeletedAt == 0 {
 			continue
 		}
-		shard := loaded.shards[partitioned.Shard]
-		if shard == nil {
+		loaded := i.loadedPartitions[cacheKey{partitionKey: m.key, tenant: m.tenant}]
+		if loaded == nil {
 			continue
 		}
-		for _, b := range partitioned.Blocks {
-			delete(shard.blocks, b)
+		if shard := loaded.shards[m.shard]; shard != nil {
+			i.putBlock(loaded, shard, m.current)
 		}
 	}
-	return nil
 }
 
 // deleteBlock deletes a block from the index. It is the caller's responsibility to enforce safe concurrent access.
 func (i *Index) deleteBlock(shard uint32, tenant string, blockId string) {
 	// first try the currently mapped partition
-	key := store.CreatePartitionKey(blockId, i.config.PartitionDuration)
+	key := i.partitionKey(blockId, shard, tenant)
 	if ok := i.tryDelete(key, shard, tenant, blockId); ok {
 		return
 	}
@@ -533,7 +1239,7 @@ func (i *Index) deleteBlock(shard uint32, tenant string, blockId string) {
 	// now try all other possible partitions
 	t := ulid.Time(ulid.MustParse(blockId).Time()).UTC().UnixMilli()
 
-	for _, p := range i.allPartitions {
+	for _, p := range i.partitions.candidates(t, t) {
 		if p.contains(t) {
 			if ok := i.tryDelete(p.Key, shard, tenant, blockId); ok {
 				return
@@ -562,8 +1268,9 @@ func (i *Index) tryDelete(key store.PartitionKey, shard uint32, tenant string, b
 		return false
 	}
 
-	if s.blocks[blockId] != nil {
-		delete(s.blocks, blockId)
+	if b := s.blocks[blockId]; b != nil {
+		i.deleteBlockEntry(p, s, blockId)
+		meta.removeBlock(b)
 		return true
 	}
 
@@ -571,12 +1278,12 @@ func (i *Index) tryDelete(key store.PartitionKey, shard uint32, tenant string, b
 }
 
 func (i *Index) FindPartitionMetas(blockId string) []*PartitionMeta {
-	i.partitionMu.Lock()
-	defer i.partitionMu.Unlock()
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
 	ts := ulid.Time(ulid.MustParse(blockId).Time()).UTC().UnixMilli()
 
 	metas := make([]*PartitionMeta, 0)
-	for _, p := range i.allPartitions {
+	for _, p := range i.partitions.candidates(ts, ts) {
 		if p.contains(ts) {
 			metas = append(metas, p)
 		}
@@ -584,12 +1291,26 @@ func (i *Index) FindPartitionMetas(blockId string) []*PartitionMeta {
 	return metas
 }
 
+// retainPartition reports whether the partition is kept loaded in memory
+// regardless of the cache size: the currently active partition, and the
+// partitions of the retain period, if configured.
+func (i *Index) retainPartition(meta *PartitionMeta, now int64) bool {
+	if i.config.PartitionCacheRetainPeriod > 0 {
+		return meta.EndTime().UnixMilli() > now-i.config.PartitionCacheRetainPeriod.Milliseconds()
+	}
+	return meta.contains(now)
+}
+
 func (i *Index) unloadPartitions() {
+	if i.config.PartitionCacheRetainPeriod > 0 {
+		i.unloadPartitionsBeforeRetainPeriod()
+		return
+	}
 	tenantPartitions := make(map[string][]*indexPartition)
 	excessPerTenant := make(map[string]int)
 	for k, p := range i.loadedPartitions {
 		tenantPartitions[k.tenant] = append(tenantPartitions[k.tenant], p)
-		if len(tenantPartitions[k.tenant]) > i.config.PartitionCacheSize {
+		if len(tenantPartitions[k.tenant]) > i.cacheSize {
 			excessPerTenant[k.tenant]++
 		}
 	}
@@ -604,7 +1325,7 @@ func (i *Index) unloadPartitions() {
 		})
 		level.Debug(i.logger).Log("msg", "unloading metastore index partitions", "tenant", t, "to_remove", len(partitions))
 		for _, p := range partitions {
-			if p.meta.contains(time.Now().UTC().UnixMilli()) {
+			if i.retainPartition(p.meta, time.Now().UTC().UnixMilli()) {
 				continue
 			}
 			level.Debug(i.logger).Log("unloading metastore index partition", "key", p.meta.Key, "accessed_at", p.accessedAt.Format(time.RFC3339))
@@ -612,7 +1333,7 @@ func (i *Index) unloadPartitions() {
 				partitionKey: p.meta.Key,
 				tenant:       t,
 			}
-			delete(i.loadedPartitions, cKey)
+			i.uncachePartition(cKey)
 			toRemove--
 			if toRemove == 0 {
 				break
@@ -621,11 +1342,159 @@ func (i *Index) unloadPartitions() {
 	}
 }
 
+// unloadPartitionsBeforeRetainPeriod unloads the partitions that end
+// before the retain period.
+func (i *Index) unloadPartitionsBeforeRetainPeriod() {
+	now := time.Now().UTC().UnixMilli()
+	for k, p := range i.loadedPartitions {
+		if !i.retainPartition(p.meta, now) {
+			level.Debug(i.logger).Log("msg", "unloading metastore index partition", "key", p.meta.Key, "tenant", k.tenant)
+			i.uncachePartition(k)
+		}
+	}
+}
+
 func (i *Index) Init(tx *bbolt.Tx) error {
-	return i.store.CreateBuckets(tx)
+	if err := i.store.CreateBuckets(tx); err != nil {
+		return err
+	}
+	if err := i.initPartitionScheme(tx); err != nil {
+		return err
+	}
+	if n, err := i.store.RecompressBlocks(tx); err != nil {
+		return fmt.Errorf("failed to re-encode blocks: %w", err)
+	} else if n > 0 {
+		level.Info(i.logger).Log("msg", "re-encoded metastore index blocks", "blocks", n, "compression", i.config.BlockCompression)
+	}
+	// The blocks journaled in the write-behind mode, if any, were pending
+	// at shutdown or crash; they are stored regardless of the mode.
+	if n, err := i.store.FlushPendingBlocks(tx); err != nil {
+		return fmt.Errorf("failed to store pending blocks: %w", err)
+	} else if n > 0 {
+		level.Info(i.logger).Log("msg", "stored pending metastore index blocks", "blocks", n)
+	}
+	if !i.config.RepairPartitions {
+		return nil
+	}
+	issues := i.store.CheckPartitions(tx)
+	if len(issues) == 0 {
+		return nil
+	}
+	i.reportPartitionIssues(issues, true)
+	return i.store.RepairPartitions(tx, issues, i.partitionDuration)
+}
+
+// initPartitionScheme loads the partition scheme recorded in the store. If
+// no scheme is recorded, the configured one is recorded. The configuration
+// is ignored afterwards: blocks must be partitioned identically by all the
+// replicas, regardless of the configuration of each.
+func (i *Index) initPartitionScheme(tx *bbolt.Tx) error {
+	recorded := i.store.PartitionScheme(tx)
+	if recorded == "" {
+		i.scheme = configuredPartitionScheme(i.config)
+		return i.store.StorePartitionScheme(tx, i.scheme.String())
+	}
+	scheme, err := store.ParsePartitionScheme(recorded)
+	if err != nil {
+		return err
+	}
+	if c := configuredPartitionScheme(i.config); c.String() != scheme.String() {
+		level.Warn(i.logger).Log(
+			"msg", "configured partition scheme differs from the one recorded in the store and is ignored",
+			"configured", c,
+			"recorded", scheme,
+		)
+	}
+	i.scheme = scheme
+	return nil
+}
+
+func configuredPartitionScheme(cfg *Config) store.PartitionScheme {
+	if cfg.PartitionScheme == "" {
+		return store.TimePartitionScheme{}
+	}
+	scheme, err := store.ParsePartitionScheme(cfg.PartitionScheme)
+	if err != nil {
+		// The configuration is validated at startup.
+		return store.TimePartitionScheme{}
+	}
+	return scheme
+}
+
+// partitionKey returns the key of the partition the new block belongs to.
+func (i *Index) partitionKey(blockId string, shard uint32, tenant string) store.PartitionKey {
+	return i.scheme.PartitionKey(blockId, shard, tenant, i.partitionDuration(tenant))
+}
+
+// partitionDuration returns the duration of the partitions of the tenant
+// blocks. Blocks that include data of multiple tenants are stored in the
+// partitions of the configured duration.
+func (i *Index) partitionDuration(tenant string) time.Duration {
+	if tenant != "" && i.config.Limits != nil {
+		if d := i.config.Limits.MetastoreIndexPartitionDuration(tenant); d > 0 {
+			return d
+		}
+	}
+	return i.config.PartitionDuration
+}
+
+// ExceedsQuota reports whether the partition the block belongs to
+// exceeds the configured block count or size limits.
+func (i *Index) ExceedsQuota(shard uint32, tenant string, block string) bool {
+	if i.config.PartitionMaxBlocks <= 0 && i.config.PartitionMaxSize == 0 {
+		return false
+	}
+	if _, err := ulid.Parse(block); err != nil {
+		return false
+	}
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	meta := i.findPartitionMeta(i.partitionKey(block, shard, tenant))
+	if meta == nil {
+		return false
+	}
+	if i.config.PartitionMaxBlocks > 0 && meta.BlockCount > i.config.PartitionMaxBlocks {
+		return true
+	}
+	return i.config.PartitionMaxSize > 0 && meta.BlockSize > i.config.PartitionMaxSize
 }
 
 func (i *Index) Restore(tx *bbolt.Tx) error {
+	// If the repair is enabled, the issues have already been resolved at
+	// Init, and the check is expected to find nothing.
+	if issues := i.store.CheckPartitions(tx); len(issues) > 0 {
+		i.reportPartitionIssues(issues, false)
+	}
 	i.LoadPartitions(tx)
+	// The changes made before the restore are not
+	// relevant to the state restored.
+	i.changes.reset()
 	return nil
 }
+
+func (i *Index) reportPartitionIssues(issues []store.PartitionIssue, repair bool) {
+	counts := make(map[store.PartitionIssueType]int)
+	for _, issue := range issues {
+		counts[issue.Type]++
+		level.Warn(i.logger).Log(
+			"msg", "inconsistent metastore index partition",
+			"issue", issue.Type,
+			"partition", issue.Partition,
+			"shard", issue.Shard,
+			"tenant", issue.Tenant,
+			"block", issue.Block,
+			"repair", repair,
+		)
+	}
+	keyvals := []interface{}{"msg", "metastore index partition check completed", "issues", len(issues), "repair", repair}
+	for _, t := range []store.PartitionIssueType{
+		store.IssueInvalidPartitionKey,
+		store.IssueEmptyPartition,
+		store.IssueEmptyShard,
+		store.IssueEmptyTenant,
+		store.IssueInvalidBlock,
+	} {
+		keyvals = append(keyvals, string(t), counts[t])
+	}
+	level.Warn(i.logger).Log(keyvals...)
+}
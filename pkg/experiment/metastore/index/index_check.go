@@ -0,0 +1,414 @@
+package index
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-kit/log/level"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+// The index consistency check cross-checks the partitions in memory against
+// the database: the state in memory is expected to be derived from the
+// database entirely, except for the blocks pending in the write-behind mode.
+// The repair therefore makes the database consistent first, and then reloads
+// the partitions affected from the database: the outcome does not depend on
+// the state in memory, and is identical on all the replicas.
+
+type blockLocation struct {
+	partition store.PartitionKey
+	shard     uint32
+	tenant    string
+}
+
+type duplicateBlock struct {
+	// The location the block belongs to, according to the partition
+	// scheme: the entries in other locations are removed on repair.
+	expected  blockLocation
+	locations []blockLocation
+}
+
+type indexCheck struct {
+	response *metastorev1.CheckIndexResponse
+	// Issues of the database entries.
+	storeIssues []store.PartitionIssue
+	// Blocks stored in more than one location, by block identifier.
+	duplicates map[string]*duplicateBlock
+	// Partitions to be reloaded from the database on repair.
+	reload map[store.PartitionKey]struct{}
+	// Partitions with entries that can't be decoded: these
+	// can't be loaded, and therefore are never reloaded.
+	unreadable map[store.PartitionKey]struct{}
+}
+
+func (c *indexCheck) report(issue *metastorev1.IndexIssue, reload bool) {
+	c.response.Issues = append(c.response.Issues, issue)
+	if reload {
+		c.reload[store.PartitionKey(issue.Partition)] = struct{}{}
+	}
+}
+
+// Check cross-checks the index partitions in memory against the database,
+// and reports the issues found. The function, if not nil, is called for
+// each block stored in the database; the block must not be modified.
+//
+// The blocks of the partitions that are not loaded in memory can only be
+// checked against the database: such partitions are loaded on demand.
+func (i *Index) Check(tx *bbolt.Tx, fn func(store.PartitionKey, *metastorev1.BlockMeta)) *metastorev1.CheckIndexResponse {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	c := i.check(tx, fn)
+	i.reportIndexIssues(c.response.Issues, false)
+	return c.response
+}
+
+// Repair checks the index, as Check does, and resolves the issues found:
+// malformed entries and duplicate blocks are removed from the database,
+// and the partitions affected are reloaded from the database. Missing
+// tenants of the partitions are resolved the same way. The changes are
+// not streamed to the block watchers. The issues found are returned.
+func (i *Index) Repair(tx *bbolt.Tx) (*metastorev1.CheckIndexResponse, error) {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	if err := i.flushPendingBlocks(tx); err != nil {
+		return nil, err
+	}
+	c := i.check(tx, nil)
+	i.reportIndexIssues(c.response.Issues, true)
+	if len(c.response.Issues) == 0 {
+		return c.response, nil
+	}
+	if err := i.repair(tx, c); err != nil {
+		return nil, err
+	}
+	c.response.Repaired = true
+	return c.response, nil
+}
+
+func (i *Index) check(tx *bbolt.Tx, fn func(store.PartitionKey, *metastorev1.BlockMeta)) *indexCheck {
+	c := &indexCheck{
+		response:    new(metastorev1.CheckIndexResponse),
+		storeIssues: i.store.CheckPartitions(tx),
+		duplicates:  make(map[string]*duplicateBlock),
+		reload:      make(map[store.PartitionKey]struct{}),
+		unreadable:  make(map[store.PartitionKey]struct{}),
+	}
+	for _, issue := range c.storeIssues {
+		c.report(&metastorev1.IndexIssue{
+			Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_INVALID_ENTRY,
+			Partition: string(issue.Partition),
+			Shard:     issue.Shard,
+			TenantId:  issue.Tenant,
+			BlockId:   issue.Block,
+			Details:   string(issue.Type),
+		}, true)
+	}
+
+	// The readers may load partitions concurrently.
+	i.cacheMu.Lock()
+	loaded := maps.Clone(i.loadedPartitions)
+	i.cacheMu.Unlock()
+
+	pendingBlocks := make(map[string]struct{}, len(i.pending))
+	pendingTenants := make(map[cacheKey]struct{})
+	for _, p := range i.pending {
+		pendingBlocks[p.block.Id] = struct{}{}
+		pendingTenants[cacheKey{partitionKey: p.key, tenant: p.block.TenantId}] = struct{}{}
+		if p.block.TenantId == "" {
+			for _, ds := range p.block.Datasets {
+				pendingTenants[cacheKey{partitionKey: p.key, tenant: ds.TenantId}] = struct{}{}
+			}
+		}
+	}
+
+	// The first location of each block stored.
+	stored := make(map[string]blockLocation)
+	storedIn := func(id string, loc blockLocation) bool {
+		if first, ok := stored[id]; ok && first == loc {
+			return true
+		}
+		if d := c.duplicates[id]; d != nil {
+			for _, l := range d.locations {
+				if l == loc {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	partitions := i.store.ListPartitions(tx)
+	inStore := make(map[store.PartitionKey]struct{}, len(partitions))
+	for _, key := range partitions {
+		inStore[key] = struct{}{}
+		c.response.Partitions++
+		meta := i.findPartitionMeta(key)
+		switch {
+		case meta == nil:
+			c.report(&metastorev1.IndexIssue{
+				Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_ORPHANED_PARTITION,
+				Partition: string(key),
+			}, true)
+		case meta.Archived():
+			// The partition can't be reloaded from the database:
+			// the blocks are to be inspected manually.
+			c.report(&metastorev1.IndexIssue{
+				Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_ORPHANED_PARTITION,
+				Partition: string(key),
+				Details:   "the partition is archived",
+			}, false)
+		}
+		tenants := make(map[string]struct{})
+		for _, shard := range i.store.ListShards(tx, key) {
+			for _, tenant := range i.store.ListTenants(tx, key, shard) {
+				tenants[tenant] = struct{}{}
+				var blocks map[string]*metastorev1.BlockMeta
+				p, isLoaded := loaded[cacheKey{partitionKey: key, tenant: tenant}]
+				if isLoaded {
+					if s := p.shards[shard]; s != nil {
+						blocks = s.blocks
+					}
+				}
+				loc := blockLocation{partition: key, shard: shard, tenant: tenant}
+				it := i.store.IterateBlocks(tx, key, shard, tenant, "")
+				for it.Next() {
+					b := it.At()
+					c.response.Blocks++
+					if tenant == "" {
+						for _, ds := range b.Datasets {
+							tenants[ds.TenantId] = struct{}{}
+						}
+					}
+					c.checkDuplicate(i, stored, b, loc)
+					if isLoaded && blocks[b.Id] == nil {
+						c.report(&metastorev1.IndexIssue{
+							Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_ORPHANED_BLOCK,
+							Partition: string(key),
+							Shard:     shard,
+							TenantId:  tenant,
+							BlockId:   b.Id,
+						}, true)
+					}
+					if fn != nil {
+						fn(key, b)
+					}
+				}
+				if err := it.Err(); err != nil {
+					// Undecodable entries are not removed: the
+					// blocks are to be inspected manually.
+					c.unreadable[key] = struct{}{}
+					c.report(&metastorev1.IndexIssue{
+						Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_INVALID_ENTRY,
+						Partition: string(key),
+						Shard:     shard,
+						TenantId:  tenant,
+						Details:   err.Error(),
+					}, false)
+				}
+			}
+		}
+		if meta == nil || meta.Archived() {
+			continue
+		}
+		for t := range tenants {
+			if !meta.HasTenant(t) {
+				c.report(&metastorev1.IndexIssue{
+					Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_UNLISTED_TENANT,
+					Partition: string(key),
+					TenantId:  t,
+				}, true)
+			}
+		}
+		c.checkMissingTenants(meta, tenants, pendingTenants)
+	}
+
+	// Partitions without any blocks stored.
+	for _, meta := range i.partitions.all() {
+		if _, ok := inStore[meta.Key]; ok || meta.Archived() {
+			continue
+		}
+		c.checkMissingTenants(meta, nil, pendingTenants)
+	}
+
+	for k, p := range loaded {
+		for shard, s := range p.shards {
+			loc := blockLocation{partition: k.partitionKey, shard: shard, tenant: k.tenant}
+			for id := range s.blocks {
+				if _, ok := pendingBlocks[id]; ok || storedIn(id, loc) {
+					continue
+				}
+				c.report(&metastorev1.IndexIssue{
+					Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_BLOCK_NOT_STORED,
+					Partition: string(k.partitionKey),
+					Shard:     shard,
+					TenantId:  k.tenant,
+					BlockId:   id,
+				}, true)
+			}
+		}
+	}
+
+	for id, d := range c.duplicates {
+		for _, loc := range d.locations {
+			c.report(&metastorev1.IndexIssue{
+				Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_DUPLICATE_BLOCK,
+				Partition: string(loc.partition),
+				Shard:     loc.shard,
+				TenantId:  loc.tenant,
+				BlockId:   id,
+				Details:   fmt.Sprintf("the block is stored in %d locations", len(d.locations)),
+			}, true)
+		}
+	}
+
+	slices.SortStableFunc(c.response.Issues, compareIndexIssues)
+	return c
+}
+
+func compareIndexIssues(a, b *metastorev1.IndexIssue) int {
+	return cmp.Or(
+		strings.Compare(a.Partition, b.Partition),
+		cmp.Compare(a.Shard, b.Shard),
+		strings.Compare(a.TenantId, b.TenantId),
+		strings.Compare(a.BlockId, b.BlockId),
+		cmp.Compare(a.Type, b.Type),
+	)
+}
+
+func (c *indexCheck) checkDuplicate(i *Index, stored map[string]blockLocation, b *metastorev1.BlockMeta, loc blockLocation) {
+	first, ok := stored[b.Id]
+	if !ok {
+		stored[b.Id] = loc
+		return
+	}
+	d := c.duplicates[b.Id]
+	if d == nil {
+		d = &duplicateBlock{
+			expected: blockLocation{
+				partition: i.partitionKey(b.Id, b.Shard, b.TenantId),
+				shard:     b.Shard,
+				tenant:    b.TenantId,
+			},
+			locations: []blockLocation{first},
+		}
+		c.duplicates[b.Id] = d
+	}
+	d.locations = append(d.locations, loc)
+}
+
+// checkMissingTenants reports the tenants the partition references,
+// but has no blocks of, either stored or pending.
+func (c *indexCheck) checkMissingTenants(meta *PartitionMeta, stored map[string]struct{}, pending map[cacheKey]struct{}) {
+	for _, t := range meta.Tenants {
+		if _, ok := stored[t]; ok {
+			continue
+		}
+		if _, ok := pending[cacheKey{partitionKey: meta.Key, tenant: t}]; ok {
+			continue
+		}
+		c.report(&metastorev1.IndexIssue{
+			Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_MISSING_TENANT,
+			Partition: string(meta.Key),
+			TenantId:  t,
+		}, true)
+	}
+}
+
+func (i *Index) repair(tx *bbolt.Tx, c *indexCheck) error {
+	for id, d := range c.duplicates {
+		keep := d.locations[0]
+		for _, loc := range d.locations {
+			if loc == d.expected {
+				keep = loc
+				break
+			}
+		}
+		for _, loc := range d.locations {
+			if loc == keep {
+				continue
+			}
+			list := &metastorev1.BlockList{Tenant: loc.tenant, Shard: loc.shard, Blocks: []string{id}}
+			if err := i.store.DeleteBlockList(tx, loc.partition, list); err != nil {
+				return err
+			}
+		}
+	}
+	// The removal of the duplicates may leave empty entries behind,
+	// therefore the database is checked again.
+	if err := i.store.RepairPartitions(tx, i.store.CheckPartitions(tx), i.partitionDuration); err != nil {
+		return err
+	}
+	// Partitions with invalid keys are rebuilt under
+	// other keys, which are not known in memory.
+	for _, key := range i.store.ListPartitions(tx) {
+		if i.findPartitionMeta(key) == nil {
+			c.reload[key] = struct{}{}
+		}
+	}
+	for key := range c.reload {
+		if _, ok := c.unreadable[key]; ok {
+			continue
+		}
+		i.reloadPartition(tx, key)
+	}
+	return nil
+}
+
+// reloadPartition replaces the partition metadata with the one loaded from
+// the database, and unloads the partition: it will be loaded on demand. The
+// partition is removed if it has no blocks stored. Archived partitions are
+// not affected.
+func (i *Index) reloadPartition(tx *bbolt.Tx, key store.PartitionKey) {
+	if meta := i.findPartitionMeta(key); meta != nil {
+		if meta.Archived() {
+			return
+		}
+		i.partitions.remove(func(p *PartitionMeta) bool {
+			return p == meta
+		})
+	}
+	if len(i.store.ListShards(tx, key)) > 0 {
+		i.partitions.add(i.loadPartitionMeta(tx, key))
+	}
+	for k := range i.loadedPartitions {
+		if k.partitionKey == key {
+			i.uncachePartition(k)
+		}
+	}
+}
+
+func (i *Index) reportIndexIssues(issues []*metastorev1.IndexIssue, repair bool) {
+	counts := make(map[metastorev1.IndexIssueType]int)
+	for _, issue := range issues {
+		counts[issue.Type]++
+		if !repair {
+			continue
+		}
+		level.Warn(i.logger).Log(
+			"msg", "inconsistent metastore index",
+			"issue", issue.Type,
+			"partition", issue.Partition,
+			"shard", issue.Shard,
+			"tenant", issue.TenantId,
+			"block", issue.BlockId,
+			"details", issue.Details,
+		)
+	}
+	keyvals := []interface{}{"msg", "metastore index check completed", "issues", len(issues), "repair", repair}
+	for t := metastorev1.IndexIssueType(1); int(t) < len(metastorev1.IndexIssueType_name); t++ {
+		if n := counts[t]; n > 0 {
+			keyvals = append(keyvals, t.String(), n)
+		}
+	}
+	lvl := level.Info
+	if len(issues) > 0 {
+		lvl = level.Warn
+	}
+	lvl(i.logger).Log(keyvals...)
+}
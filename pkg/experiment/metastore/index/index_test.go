@@ -1,21 +1,32 @@
 package index_test
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/bbolt"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/memory"
 	"github.com/grafana/pyroscope/pkg/test"
 	"github.com/grafana/pyroscope/pkg/test/mocks/mockindex"
 	"github.com/grafana/pyroscope/pkg/util"
@@ -62,17 +73,17 @@ func TestIndex_FindBlocksInRange(t *testing.T) {
 				createBlock("20240923T07.1h", -1*time.Hour), // in range
 				createBlock("20240923T07.1h", -2*time.Hour), // in range
 				createBlock("20240923T07.1h", -3*time.Hour), // too old
-				createBlock("20240923T08.1h", -3*time.Hour), // // technically in range but we will not look here
+				createBlock("20240923T08.1h", -3*time.Hour), // in range
 				createBlock("20240923T10.1h", 0),
 			},
 			queryStart: test.Time("2024-09-23T05:00:00.000Z"),
 			queryEnd:   test.Time("2024-09-23T06:00:00.000Z"),
-			want:       3,
+			want:       4,
 		},
 		{
 			name: "out of order ingestion (ahead of time)",
 			blocks: []*metastorev1.BlockMeta{
-				createBlock("20240923T06.1h", 2*time.Hour), // technically in range but we will not look here
+				createBlock("20240923T06.1h", 2*time.Hour), // in range
 				createBlock("20240923T07.1h", 1*time.Hour), // in range
 				createBlock("20240923T07.1h", 3*time.Hour), // too new
 				createBlock("20240923T08.1h", 0),           // in range
@@ -81,6 +92,18 @@ func TestIndex_FindBlocksInRange(t *testing.T) {
 			},
 			queryStart: test.Time("2024-09-23T08:00:00.000Z"),
 			queryEnd:   test.Time("2024-09-23T09:00:00.000Z"),
+			want:       4,
+		},
+		{
+			name: "out of order ingestion (beyond the lookaround period)",
+			blocks: []*metastorev1.BlockMeta{
+				createBlock("20240923T06.1h", 0),
+				createBlock("20240923T10.1h", -5*time.Hour), // in range
+				createBlock("20240923T10.1h", 0),
+				createBlock("20240923T12.1h", -6*time.Hour), // in range
+			},
+			queryStart: test.Time("2024-09-23T05:00:00.000Z"),
+			queryEnd:   test.Time("2024-09-23T06:00:00.000Z"),
 			want:       3,
 		},
 	}
@@ -93,12 +116,12 @@ func TestIndex_FindBlocksInRange(t *testing.T) {
 				PartitionDuration:     time.Hour,
 				PartitionCacheSize:    24,
 				QueryLookaroundPeriod: time.Hour,
-			})
+			}, nil)
 			for _, b := range tt.blocks {
 				i.InsertBlockNoCheckNoPersist(nil, b)
 			}
 			tenantMap := map[string]struct{}{"tenant-1": {}}
-			found := i.FindBlocksInRange(nil, tt.queryStart, tt.queryEnd, tenantMap)
+			found := i.FindBlocksInRange(nil, tt.queryStart, tt.queryEnd, tenantMap, nil, nil)
 			require.Equal(t, tt.want, len(found))
 			for _, b := range found {
 				require.Truef(
@@ -110,6 +133,51 @@ func TestIndex_FindBlocksInRange(t *testing.T) {
 	}
 }
 
+func TestIndex_FindPartitionsInRange(t *testing.T) {
+	mockStore := mockindex.NewMockStore(t)
+	mockStore.On("ListShards", mock.Anything, mock.Anything).Return([]uint32{})
+	i := index.NewIndex(util.Logger, mockStore, &index.Config{
+		PartitionDuration:     time.Hour,
+		PartitionCacheSize:    24,
+		QueryLookaroundPeriod: time.Hour,
+	}, nil)
+	for _, b := range []*metastorev1.BlockMeta{
+		createBlock("20240923T06.1h", 0),
+		createBlock("20240923T07.1h", 0),
+		createBlock("20240923T08.1h", 0),
+		createBlock("20240923T10.1h", 0),
+	} {
+		i.InsertBlockNoCheckNoPersist(nil, b)
+	}
+	start := test.Time("2024-09-23T08:00:00.000Z")
+	end := test.Time("2024-09-23T08:30:00.000Z")
+	tenantMap := map[string]struct{}{"tenant-1": {}}
+
+	keys := func(partitions []*metastorev1.PartitionInfo) []string {
+		k := make([]string, len(partitions))
+		for j, p := range partitions {
+			k[j] = p.Key
+		}
+		return k
+	}
+
+	// The blocks of the partition 20240923T07.1h do not include data of
+	// the time range, and the partition is not visited.
+	partitions := i.FindPartitionsInRange(start, end, tenantMap)
+	assert.Equal(t, []string{"20240923T08.1h"}, keys(partitions))
+	for _, p := range partitions {
+		// The partitions of blocks without tenant are not loaded yet.
+		assert.False(t, p.Cached)
+	}
+
+	i.FindBlocksInRange(nil, start, end, tenantMap, nil, nil)
+	for _, p := range i.FindPartitionsInRange(start, end, tenantMap) {
+		assert.True(t, p.Cached)
+	}
+
+	assert.Empty(t, i.FindPartitionsInRange(start, end, map[string]struct{}{"tenant-2": {}}))
+}
+
 func TestIndex_FindBlocks(t *testing.T) {
 	mockStore := mockindex.NewMockStore(t)
 	mockStore.On("ListShards", mock.Anything, mock.Anything).Return([]uint32{})
@@ -117,7 +185,7 @@ func TestIndex_FindBlocks(t *testing.T) {
 		PartitionDuration:     time.Hour,
 		PartitionCacheSize:    24,
 		QueryLookaroundPeriod: time.Hour,
-	})
+	}, nil)
 	a := test.ULID("2024-09-21T08:00:00.123Z")
 	b := test.ULID("2024-09-22T08:00:00.123Z")
 	c := test.ULID("2024-09-23T08:00:00.123Z")
@@ -126,6 +194,39 @@ func TestIndex_FindBlocks(t *testing.T) {
 	assert.Len(t, i.FindBlocks(nil, &metastorev1.BlockList{Blocks: []string{a, b, c}}), 2)
 }
 
+func TestIndex_FindBlockByID(t *testing.T) {
+	mockStore := mockindex.NewMockStore(t)
+	mockStore.On("ListShards", mock.Anything, mock.Anything).Return([]uint32{})
+	i := index.NewIndex(util.Logger, mockStore, &index.Config{
+		PartitionDuration:     time.Hour,
+		PartitionCacheSize:    24,
+		QueryLookaroundPeriod: time.Hour,
+	}, nil)
+	a := test.ULID("2024-09-21T08:00:00.123Z")
+	b := test.ULID("2024-09-21T08:10:00.123Z")
+	c := test.ULID("2024-09-23T08:00:00.123Z")
+	i.InsertBlockNoCheckNoPersist(nil, &metastorev1.BlockMeta{Id: a, Shard: 1})
+	i.InsertBlockNoCheckNoPersist(nil, &metastorev1.BlockMeta{Id: b, Shard: 2, TenantId: "tenant-1"})
+
+	md, p := i.FindBlockByID(nil, a)
+	require.NotNil(t, md)
+	assert.Equal(t, a, md.Id)
+	assert.Equal(t, store.PartitionKey("20240921T08.1h"), p.Key)
+
+	md, p = i.FindBlockByID(nil, b)
+	require.NotNil(t, md)
+	assert.Equal(t, "tenant-1", md.TenantId)
+	assert.Equal(t, uint32(2), md.Shard)
+	assert.Equal(t, store.PartitionKey("20240921T08.1h"), p.Key)
+
+	md, p = i.FindBlockByID(nil, c)
+	assert.Nil(t, md)
+	assert.Nil(t, p)
+
+	md, _ = i.FindBlockByID(nil, "invalid")
+	assert.Nil(t, md)
+}
+
 func mockPartition(store *mockindex.MockStore, key store.PartitionKey, blocks []*metastorev1.BlockMeta) {
 	store.On("ListShards", mock.Anything, key).Return([]uint32{0}).Maybe()
 	store.On("ListTenants", mock.Anything, key, uint32(0)).Return([]string{""}).Maybe()
@@ -134,7 +235,7 @@ func mockPartition(store *mockindex.MockStore, key store.PartitionKey, blocks []
 
 func TestIndex_ForEachPartition(t *testing.T) {
 	mockStore := mockindex.NewMockStore(t)
-	i := index.NewIndex(util.Logger, mockStore, &index.Config{PartitionDuration: time.Hour})
+	i := index.NewIndex(util.Logger, mockStore, &index.Config{PartitionDuration: time.Hour}, nil)
 
 	keys := []store.PartitionKey{
 		"20240923T06.1h",
@@ -144,6 +245,7 @@ func TestIndex_ForEachPartition(t *testing.T) {
 		"20240923T10.1h",
 	}
 	mockStore.On("ListPartitions", mock.Anything).Return(keys)
+	mockStore.On("ListArchivedPartitions", mock.Anything).Return(nil)
 	for _, key := range keys {
 		mockPartition(mockStore, key, nil)
 	}
@@ -165,7 +267,7 @@ func TestIndex_ForEachPartition(t *testing.T) {
 func TestIndex_InsertBlock(t *testing.T) {
 	mockStore := mockindex.NewMockStore(t)
 	mockStore.On("ListShards", mock.Anything, mock.Anything).Return([]uint32{})
-	i := index.NewIndex(util.Logger, mockStore, &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 1})
+	i := index.NewIndex(util.Logger, mockStore, &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 1}, nil)
 	block := &metastorev1.BlockMeta{
 		Id:       test.ULID("2024-09-23T08:00:00.123Z"),
 		TenantId: "tenant-1",
@@ -175,13 +277,13 @@ func TestIndex_InsertBlock(t *testing.T) {
 
 	i.InsertBlockNoCheckNoPersist(nil, block)
 	require.NotNil(t, i.FindBlock(nil, 0, "tenant-1", block.Id))
-	blocks := i.FindBlocksInRange(nil, test.Time("2024-09-23T07:00:00.000Z"), test.Time("2024-09-23T09:00:00.000Z"), map[string]struct{}{"tenant-1": {}})
+	blocks := i.FindBlocksInRange(nil, test.Time("2024-09-23T07:00:00.000Z"), test.Time("2024-09-23T09:00:00.000Z"), map[string]struct{}{"tenant-1": {}}, nil, nil)
 	require.Len(t, blocks, 1)
 	require.Equal(t, block, blocks[0])
 
 	// inserting the block again is a noop
 	i.InsertBlockNoCheckNoPersist(nil, block)
-	blocks = i.FindBlocksInRange(nil, test.Time("2024-09-23T07:00:00.000Z"), test.Time("2024-09-23T09:00:00.000Z"), map[string]struct{}{"tenant-1": {}})
+	blocks = i.FindBlocksInRange(nil, test.Time("2024-09-23T07:00:00.000Z"), test.Time("2024-09-23T09:00:00.000Z"), map[string]struct{}{"tenant-1": {}}, nil, nil)
 	require.Len(t, blocks, 1)
 	require.Equal(t, block, blocks[0])
 }
@@ -189,7 +291,7 @@ func TestIndex_InsertBlock(t *testing.T) {
 func TestIndex_LoadPartitions(t *testing.T) {
 	mockStore := mockindex.NewMockStore(t)
 	config := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 1}
-	i := index.NewIndex(util.Logger, mockStore, config)
+	i := index.NewIndex(util.Logger, mockStore, config, nil)
 
 	blocks := make([]*metastorev1.BlockMeta, 0, 420)
 	for i := 0; i < 420; i++ {
@@ -202,6 +304,7 @@ func TestIndex_LoadPartitions(t *testing.T) {
 
 	partitionKey := store.CreatePartitionKey(blocks[0].Id, config.PartitionDuration)
 	mockStore.On("ListPartitions", mock.Anything).Return([]store.PartitionKey{partitionKey})
+	mockStore.On("ListArchivedPartitions", mock.Anything).Return(nil)
 	mockStore.On("ListShards", mock.Anything, mock.Anything).Return([]uint32{0})
 	mockStore.On("ListTenants", mock.Anything, mock.Anything, mock.Anything).Return([]string{""})
 	mockStore.On("ListBlocks", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(blocks)
@@ -217,7 +320,7 @@ func TestIndex_LoadPartitions(t *testing.T) {
 func TestIndex_ReplaceBlocks(t *testing.T) {
 	mockStore := mockindex.NewMockStore(t)
 	mockStore.On("ListShards", mock.Anything, mock.Anything).Return([]uint32{})
-	i := index.NewIndex(util.Logger, mockStore, &index.DefaultConfig)
+	i := index.NewIndex(util.Logger, mockStore, &index.DefaultConfig, nil)
 	b1 := &metastorev1.BlockMeta{
 		Id: test.ULID("2024-09-23T08:00:00.123Z"),
 	}
@@ -252,7 +355,7 @@ func TestIndex_DurationChange(t *testing.T) {
 	mockStore := mockindex.NewMockStore(t)
 	mockStore.On("ListShards", mock.Anything, mock.Anything).Return([]uint32{})
 	config := &index.Config{PartitionDuration: 24 * time.Hour, PartitionCacheSize: 1}
-	i := index.NewIndex(util.Logger, mockStore, config)
+	i := index.NewIndex(util.Logger, mockStore, config, nil)
 	b := &metastorev1.BlockMeta{
 		Id: test.ULID("2024-09-23T08:00:00.123Z"),
 	}
@@ -265,7 +368,7 @@ func TestIndex_DurationChange(t *testing.T) {
 
 func TestIndex_UnloadPartitions(t *testing.T) {
 	mockStore := mockindex.NewMockStore(t)
-	i := index.NewIndex(util.Logger, mockStore, &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 3})
+	i := index.NewIndex(util.Logger, mockStore, &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 3}, nil)
 
 	keys := []store.PartitionKey{
 		"20240923T06.1h",
@@ -275,6 +378,7 @@ func TestIndex_UnloadPartitions(t *testing.T) {
 		"20240923T10.1h",
 	}
 	mockStore.On("ListPartitions", mock.Anything).Return(keys)
+	mockStore.On("ListArchivedPartitions", mock.Anything).Return(nil)
 	for _, key := range keys {
 		mockPartition(mockStore, key, nil)
 	}
@@ -284,25 +388,108 @@ func TestIndex_UnloadPartitions(t *testing.T) {
 	for _, key := range keys {
 		start, _, _ := key.Parse()
 		for c := 0; c < 10; c++ {
-			i.FindBlocksInRange(nil, start.UnixMilli(), start.Add(5*time.Minute).UnixMilli(), map[string]struct{}{"": {}})
+			i.FindBlocksInRange(nil, start.UnixMilli(), start.Add(5*time.Minute).UnixMilli(), map[string]struct{}{"": {}}, nil, nil)
 		}
 	}
 	// multiple reads cause a single store access
 	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 10))
 
 	for c := 0; c < 10; c++ {
-		i.FindBlocksInRange(nil, test.Time("2024-09-23T08:00:00.000Z"), test.Time("2024-09-23T08:05:00.000Z"), map[string]struct{}{"": {}})
+		i.FindBlocksInRange(nil, test.Time("2024-09-23T08:00:00.000Z"), test.Time("2024-09-23T08:05:00.000Z"), map[string]struct{}{"": {}}, nil, nil)
 	}
 	// this partition is still loaded in memory
 	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 10))
 
 	for c := 0; c < 10; c++ {
-		i.FindBlocksInRange(nil, test.Time("2024-09-23T06:00:00.000Z"), test.Time("2024-09-23T06:05:00.000Z"), map[string]struct{}{"": {}})
+		i.FindBlocksInRange(nil, test.Time("2024-09-23T06:00:00.000Z"), test.Time("2024-09-23T06:05:00.000Z"), map[string]struct{}{"": {}}, nil, nil)
 	}
 	// this partition was unloaded
 	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 11))
 }
 
+func TestIndex_ApplyRuntimeConfig(t *testing.T) {
+	mockStore := mockindex.NewMockStore(t)
+	i := index.NewIndex(util.Logger, mockStore, &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 5}, nil)
+
+	keys := []store.PartitionKey{
+		"20240923T06.1h",
+		"20240923T07.1h",
+		"20240923T08.1h",
+		"20240923T09.1h",
+		"20240923T10.1h",
+	}
+	mockStore.On("ListPartitions", mock.Anything).Return(keys)
+	mockStore.On("ListArchivedPartitions", mock.Anything).Return(nil)
+	for _, key := range keys {
+		mockPartition(mockStore, key, nil)
+	}
+	i.LoadPartitions(nil)
+
+	query := func(keys ...store.PartitionKey) {
+		for _, key := range keys {
+			start, _, _ := key.Parse()
+			i.FindBlocksInRange(nil, start.UnixMilli(), start.Add(5*time.Minute).UnixMilli(), map[string]struct{}{"": {}}, nil, nil)
+		}
+	}
+	query(keys...)
+	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 10))
+
+	// The least recently accessed partitions are unloaded immediately.
+	size := 2
+	i.ApplyRuntimeConfig(index.RuntimeConfig{PartitionCacheSize: &size})
+	query(keys[3:]...)
+	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 10))
+	query(keys[:3]...)
+	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 13))
+
+	// The configured size applies once the runtime value is unset.
+	i.ApplyRuntimeConfig(index.RuntimeConfig{})
+	query(keys...)
+	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 16))
+	query(keys...)
+	require.True(t, mockStore.AssertNumberOfCalls(t, "ListShards", 16))
+}
+
+func TestIndex_PartitionCacheRetainPeriod(t *testing.T) {
+	mockStore := mockindex.NewMockStore(t)
+	i := index.NewIndex(util.Logger, mockStore, &index.Config{
+		PartitionDuration:          time.Hour,
+		PartitionCacheSize:         1,
+		PartitionCacheRetainPeriod: 3 * time.Hour,
+	}, nil)
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	keys := make([]store.PartitionKey, 6)
+	for j := range keys {
+		keys[j] = store.CreatePartitionKey(test.ULID(now.Add(time.Duration(j-5)*time.Hour).Format(time.RFC3339)), time.Hour)
+	}
+	mockStore.On("ListPartitions", mock.Anything).Return(keys)
+	mockStore.On("ListArchivedPartitions", mock.Anything).Return(nil)
+	for _, key := range keys {
+		mockPartition(mockStore, key, nil)
+	}
+	i.LoadPartitions(nil)
+	loaded := len(mockStore.Calls)
+
+	query := func(keys ...store.PartitionKey) int {
+		for _, key := range keys {
+			start, _, _ := key.Parse()
+			i.FindBlocksInRange(nil, start.UnixMilli(), start.Add(5*time.Minute).UnixMilli(), map[string]struct{}{"": {}}, nil, nil)
+		}
+		n := len(mockStore.Calls) - loaded
+		loaded = len(mockStore.Calls)
+		return n
+	}
+	// The partitions of the retain period are loaded
+	// at startup and kept regardless of the cache size.
+	assert.Zero(t, query(keys[3:]...))
+	assert.Zero(t, query(keys[3:]...))
+	// Older partitions are unloaded once they have been used.
+	assert.NotZero(t, query(keys[0]))
+	assert.NotZero(t, query(keys[0]))
+	assert.Zero(t, query(keys[3:]...))
+}
+
 func createBlock(key string, offset time.Duration) *metastorev1.BlockMeta {
 	pKey := store.PartitionKey(key)
 	ts, _, _ := pKey.Parse()
@@ -321,32 +508,32 @@ func TestReplaceBlocks_Persistence(t *testing.T) {
 		PartitionCacheSize:    7,
 		QueryLookaroundPeriod: time.Hour,
 	}
-	md1 := &metastorev1.BlockMeta{
+	md1 := withDataset(&metastorev1.BlockMeta{
 		Id:              test.ULID("2024-09-22T08:00:00.123Z"),
 		Shard:           3,
 		CompactionLevel: 0,
 		TenantId:        "",
-	}
-	md2 := &metastorev1.BlockMeta{
+	}, "x1")
+	md2 := withDataset(&metastorev1.BlockMeta{
 		Id:              test.ULID("2024-09-22T08:01:00.123Z"),
 		Shard:           3,
 		CompactionLevel: 0,
 		TenantId:        "",
-	}
-	md3 := &metastorev1.BlockMeta{
+	}, "x2")
+	md3 := withDataset(&metastorev1.BlockMeta{
 		Id:              test.ULID("2024-09-25T09:00:00.123Z"),
 		Shard:           3,
 		CompactionLevel: 1,
 		TenantId:        "x1",
-	}
-	md4 := &metastorev1.BlockMeta{
+	}, "x1")
+	md4 := withDataset(&metastorev1.BlockMeta{
 		Id:              test.ULID("2024-09-25T09:01:00.123Z"),
 		Shard:           3,
 		CompactionLevel: 1,
 		TenantId:        "x2",
-	}
+	}, "x2")
 
-	x := index.NewIndex(util.Logger, index.NewStore(), c)
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
 	require.NoError(t, db.Update(x.Init))
 	require.NoError(t, db.View(x.Restore))
 
@@ -365,10 +552,10 @@ func TestReplaceBlocks_Persistence(t *testing.T) {
 				Shard:  md1.Shard,
 				Blocks: []string{md1.Id, md2.Id},
 			},
-		})
+		}, time.Now())
 	}))
 
-	x = index.NewIndex(util.Logger, index.NewStore(), c)
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
 	require.NoError(t, db.Update(x.Init))
 	require.NoError(t, db.View(x.Restore))
 	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
@@ -379,3 +566,2116 @@ func TestReplaceBlocks_Persistence(t *testing.T) {
 		return nil
 	}))
 }
+
+func TestReplaceBlocks_DeleteAndSweep(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, QueryLookaroundPeriod: time.Hour}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	source := withDataset(&metastorev1.BlockMeta{
+		Id:       test.ULID("2024-09-23T08:00:00.123Z"),
+		Shard:    1,
+		TenantId: "tenant-1",
+		Size:     100,
+	}, "tenant-1")
+	compacted := withDataset(&metastorev1.BlockMeta{
+		Id:              test.ULID("2024-09-23T08:10:00.123Z"),
+		Shard:           1,
+		TenantId:        "tenant-1",
+		CompactionLevel: 1,
+		Size:            50,
+	}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, source.CloneVT())
+	}))
+
+	deletedAt := test.Time("2024-09-23T09:00:00.000Z")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			NewBlocks:    []*metastorev1.BlockMeta{compacted},
+			SourceBlocks: &metastorev1.BlockList{Tenant: "tenant-1", Shard: 1, Blocks: []string{source.Id}},
+		}, time.UnixMilli(deletedAt))
+	}))
+
+	assertDeleted := func(x *index.Index) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			assert.Nil(t, x.FindBlock(tx, source.Shard, source.TenantId, source.Id))
+			found := x.FindBlocksInRange(tx, source.MinTime, compacted.MaxTime, map[string]struct{}{"tenant-1": {}}, nil, nil)
+			require.Len(t, found, 1)
+			assert.Equal(t, compacted.Id, found[0].Id)
+			// The deleted block can still be found by identifier.
+			b, p := x.FindBlockByID(tx, source.Id)
+			require.NotNil(t, b)
+			assert.Equal(t, deletedAt, b.DeletedAt)
+			assert.Equal(t, 1, p.BlockCount)
+			assert.Equal(t, uint64(50), p.BlockSize)
+			return nil
+		}))
+		// The deleted block can not be added again.
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			var exists *index.BlockExistsError
+			assert.ErrorAs(t, x.InsertBlock(tx, source.CloneVT()), &exists)
+			return nil
+		}))
+	}
+	assertDeleted(x)
+	restored := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(restored.Init))
+	require.NoError(t, db.View(restored.Restore))
+	assertDeleted(restored)
+
+	sweep := func(before int64) (removed uint32) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			resp, _, err := x.SweepDeletedBlocks(tx, &raft_log.SweepDeletedBlocksRequest{DeletedBefore: before}, time.Now())
+			if err == nil {
+				removed = resp.Removed
+			}
+			return err
+		}))
+		return removed
+	}
+	assert.Equal(t, uint32(0), sweep(deletedAt-1))
+	assert.Equal(t, uint32(1), sweep(deletedAt))
+	// The tombstones are removed.
+	assert.Equal(t, uint32(0), sweep(deletedAt))
+
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		b, _ := x.FindBlockByID(tx, source.Id)
+		assert.Nil(t, b)
+		assert.NotNil(t, x.FindBlock(tx, compacted.Shard, compacted.TenantId, compacted.Id))
+		return nil
+	}))
+}
+
+func TestReplaceBlocks_Rollback(t *testing.T) {
+	errStore := errors.New("store failure")
+	for _, tc := range []struct {
+		name  string
+		fault func(*faultyStore)
+	}{
+		{"store new block", func(s *faultyStore) { s.failStoreBlock = s.storeBlockCalls + 2 }},
+		{"mark source blocks deleted", func(s *faultyStore) { s.failStoreBlock = s.storeBlockCalls + 4 }},
+		{"store tombstones", func(s *faultyStore) { s.failStoreDeletedBlocks = true }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := test.BoltDB(t)
+			c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+			s := &faultyStore{Store: index.NewStore(), err: errStore}
+			x := index.NewIndex(util.Logger, s, c, nil)
+			require.NoError(t, db.Update(x.Init))
+
+			// Source and compacted blocks span multiple partitions.
+			source := []*metastorev1.BlockMeta{
+				withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1}, "tenant-1"),
+				withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 1}, "tenant-1"),
+			}
+			compacted := []*metastorev1.BlockMeta{
+				withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T10:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+				withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T11:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+			}
+			for _, b := range source {
+				require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+					return x.InsertBlock(tx, b)
+				}))
+			}
+
+			tc.fault(s)
+			// The transaction is committed regardless of the error:
+			// the changes must be reverted by the index itself.
+			var err error
+			require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+				err = x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+					NewBlocks: compacted,
+					SourceBlocks: &metastorev1.BlockList{
+						Shard:  1,
+						Blocks: []string{source[0].Id, source[1].Id},
+					},
+				}, time.Now())
+				return nil
+			}))
+			require.ErrorIs(t, err, errStore)
+
+			assertUnchanged := func(x *index.Index) {
+				require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+					for _, b := range source {
+						found := x.FindBlock(tx, b.Shard, b.TenantId, b.Id)
+						require.NotNil(t, found)
+						assert.True(t, b.EqualVT(found))
+					}
+					for _, b := range compacted {
+						assert.Nil(t, x.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+					}
+					return nil
+				}))
+			}
+			assertUnchanged(x)
+
+			x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+			require.NoError(t, db.Update(x.Init))
+			require.NoError(t, db.View(x.Restore))
+			assertUnchanged(x)
+		})
+	}
+}
+
+// faultyStore fails the n-th call of the method, if set.
+type faultyStore struct {
+	index.Store
+	err error
+
+	failStoreBlock         int
+	storeBlockCalls        int
+	failStoreDeletedBlocks bool
+}
+
+func (s *faultyStore) StoreBlock(tx *bbolt.Tx, k store.PartitionKey, b *metastorev1.BlockMeta) error {
+	if s.storeBlockCalls++; s.storeBlockCalls == s.failStoreBlock {
+		return s.err
+	}
+	return s.Store.StoreBlock(tx, k, b)
+}
+
+func (s *faultyStore) StoreDeletedBlocks(tx *bbolt.Tx, blocks []store.DeletedBlock) error {
+	if s.failStoreDeletedBlocks {
+		return s.err
+	}
+	return s.Store.StoreDeletedBlocks(tx, blocks)
+}
+
+func TestIndex_InsertBlock_Exists(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 1}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	block := withDataset(&metastorev1.BlockMeta{
+		Id:             test.ULID("2024-09-23T08:00:00.123Z"),
+		TenantId:       "tenant-1",
+		IdempotencyKey: &metastorev1.IdempotencyKey{WriterId: "writer-1", Sequence: 1},
+	}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, block)
+	}))
+
+	duplicate := block.CloneVT()
+	duplicate.IdempotencyKey.Sequence = 2
+	err := db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, duplicate)
+	})
+	require.ErrorIs(t, err, index.ErrBlockExists)
+	var exists *index.BlockExistsError
+	require.ErrorAs(t, err, &exists)
+	require.True(t, exists.Block.EqualVT(block))
+}
+
+func TestIndex_InsertBlock_Invalid(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 1}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	valid := func() *metastorev1.BlockMeta {
+		return withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z")}, "tenant-1")
+	}
+	for _, tc := range []struct {
+		reason index.InvalidBlockReason
+		modify func(*metastorev1.BlockMeta)
+	}{
+		{index.InvalidBlockID, func(b *metastorev1.BlockMeta) { b.Id = "invalid" }},
+		{index.InvalidTimeRange, func(b *metastorev1.BlockMeta) { b.MinTime = 0 }},
+		{index.InvalidTimeRange, func(b *metastorev1.BlockMeta) { b.MinTime, b.MaxTime = b.MaxTime, b.MinTime }},
+		{index.InvalidTimeRange, func(b *metastorev1.BlockMeta) { b.Datasets[0].MaxTime = b.MaxTime + 1 }},
+		{index.MissingDatasets, func(b *metastorev1.BlockMeta) { b.Datasets = nil }},
+		{index.MissingTenant, func(b *metastorev1.BlockMeta) { b.Datasets[0].TenantId = "" }},
+		{index.TenantMismatch, func(b *metastorev1.BlockMeta) { b.TenantId = "tenant-2" }},
+	} {
+		t.Run(string(tc.reason), func(t *testing.T) {
+			b := valid()
+			tc.modify(b)
+			err := db.Update(func(tx *bbolt.Tx) error {
+				return x.InsertBlock(tx, b)
+			})
+			require.ErrorIs(t, err, index.ErrInvalidBlock)
+			var invalid *index.InvalidBlockError
+			require.ErrorAs(t, err, &invalid)
+			assert.Equal(t, tc.reason, invalid.Reason)
+		})
+	}
+
+	source := valid()
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, source)
+	}))
+	compacted := withDataset(&metastorev1.BlockMeta{
+		Id:              test.ULID("2024-09-23T08:01:00.123Z"),
+		Shard:           1,
+		CompactionLevel: 1,
+		TenantId:        "tenant-1",
+	}, "tenant-1")
+	err := db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			NewBlocks:    []*metastorev1.BlockMeta{compacted},
+			SourceBlocks: &metastorev1.BlockList{Shard: source.Shard, Blocks: []string{source.Id}},
+		}, time.Now())
+	})
+	var invalid *index.InvalidBlockError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, index.ShardMismatch, invalid.Reason)
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.NotNil(t, x.FindBlock(tx, source.Shard, source.TenantId, source.Id))
+		assert.Nil(t, x.FindBlock(tx, compacted.Shard, compacted.TenantId, compacted.Id))
+		return nil
+	}))
+}
+
+func TestIndex_CheckBlockClockSkew(t *testing.T) {
+	now := time.Date(2024, 9, 23, 8, 0, 0, 0, time.UTC)
+	newBlock := func(created time.Time) *metastorev1.BlockMeta {
+		b := withDataset(&metastorev1.BlockMeta{Id: test.ULID(created.Format(time.RFC3339))}, "tenant-1")
+		// The block data is not affected by the writer clock skew.
+		b.MinTime = now.Add(-time.Minute).UnixMilli()
+		b.MaxTime = now.UnixMilli()
+		b.Datasets[0].MinTime = b.MinTime
+		b.Datasets[0].MaxTime = b.MaxTime
+		return b
+	}
+
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 1}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	skewed := newBlock(now.Add(3 * time.Hour))
+	require.NoError(t, x.CheckBlockClockSkew(skewed, now), "check is disabled")
+
+	c.MaxBlockClockSkew = time.Hour
+	for _, b := range []*metastorev1.BlockMeta{
+		newBlock(now.Add(30 * time.Minute)),
+		newBlock(now.Add(-30 * time.Minute)),
+	} {
+		id := b.Id
+		require.NoError(t, x.CheckBlockClockSkew(b, now))
+		assert.Equal(t, id, b.Id)
+		assert.Zero(t, b.OriginalCreatedAt)
+	}
+	for _, b := range []*metastorev1.BlockMeta{
+		newBlock(now.Add(3 * time.Hour)),
+		newBlock(now.Add(-3 * time.Hour)),
+	} {
+		id := b.Id
+		var invalid *index.InvalidBlockError
+		require.ErrorAs(t, x.CheckBlockClockSkew(b, now), &invalid)
+		assert.Equal(t, index.ClockSkew, invalid.Reason)
+		assert.Equal(t, id, b.Id)
+	}
+
+	c.RestampSkewedBlocks = true
+	b := newBlock(now.Add(3 * time.Hour))
+	original := b.Id
+	require.NoError(t, x.CheckBlockClockSkew(b, now))
+	assert.Equal(t, now.UnixMilli(), int64(ulid.MustParse(b.Id).Time()))
+	assert.Equal(t, now.Add(3*time.Hour).UnixMilli(), b.OriginalCreatedAt)
+	assert.Equal(t, original, block.ObjectID(b))
+	assert.Equal(t, store.PartitionKey("20240923T08.1h"), store.CreatePartitionKey(b.Id, time.Hour))
+
+	// Retries are re-stamped identically.
+	retry := newBlock(now.Add(3 * time.Hour))
+	retry.Id = original
+	require.NoError(t, x.CheckBlockClockSkew(retry, now.Add(time.Minute)))
+	assert.Equal(t, b.Id, retry.Id)
+}
+
+func TestIndex_QuarantineBlock(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:01.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	quarantine := &metastorev1.BlockQuarantine{QuarantinedAt: 1, Reason: "invalid CRC", ReportedBy: "query-backend-1"}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		md, err := x.QuarantineBlock(tx, 1, "tenant-1", blocks[0].Id, quarantine)
+		require.NoError(t, err)
+		require.NotNil(t, md)
+		assert.True(t, quarantine.EqualVT(md.Quarantine))
+		// The quarantine of a quarantined block is not changed.
+		md, err = x.QuarantineBlock(tx, 1, "tenant-1", blocks[0].Id, &metastorev1.BlockQuarantine{QuarantinedAt: 2})
+		require.NoError(t, err)
+		assert.True(t, quarantine.EqualVT(md.Quarantine))
+		// Blocks that are not found are ignored.
+		md, err = x.QuarantineBlock(tx, 2, "tenant-1", blocks[1].Id, quarantine)
+		require.NoError(t, err)
+		assert.Nil(t, md)
+		md, err = x.QuarantineBlock(tx, 1, "tenant-1", "not-a-block", quarantine)
+		require.NoError(t, err)
+		assert.Nil(t, md)
+		return nil
+	}))
+
+	assertQuarantined := func(x *index.Index) {
+		start := test.Time("2024-09-23T08:00:00.000Z")
+		end := test.Time("2024-09-23T09:00:00.000Z")
+		tenants := map[string]struct{}{"tenant-1": {}}
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			found := x.FindBlocksInRange(tx, start, end, tenants, nil, nil)
+			require.Len(t, found, 1)
+			assert.Equal(t, blocks[1].Id, found[0].Id)
+			found = x.FindQuarantinedBlocksInRange(tx, start, end, tenants)
+			require.Len(t, found, 1)
+			assert.Equal(t, blocks[0].Id, found[0].Id)
+			md := x.FindBlock(tx, 1, "tenant-1", blocks[0].Id)
+			require.NotNil(t, md)
+			assert.True(t, quarantine.EqualVT(md.Quarantine))
+			return nil
+		}))
+	}
+	assertQuarantined(x)
+
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	assertQuarantined(x)
+}
+
+func TestIndex_FindBlocksInRange_SharedBlocks(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:01.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, err := range x.InsertBlocks(tx, blocks) {
+			require.NoError(t, err)
+		}
+		return nil
+	}))
+
+	start := test.Time("2024-09-23T08:00:00.000Z")
+	end := test.Time("2024-09-23T09:00:00.000Z")
+	tenants := map[string]struct{}{"tenant-1": {}}
+	find := func() (found []*metastorev1.BlockMeta) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			found = x.FindBlocksInRange(tx, start, end, tenants, nil, nil)
+			return nil
+		}))
+		slices.SortFunc(found, func(a, b *metastorev1.BlockMeta) int {
+			return strings.Compare(a.Id, b.Id)
+		})
+		return found
+	}
+
+	// The blocks are not copied.
+	found := find()
+	require.Len(t, found, 2)
+	again := find()
+	require.Len(t, again, 2)
+	assert.Same(t, found[0], again[0])
+	assert.Same(t, found[1], again[1])
+
+	// The blocks found are not modified: the index replaces them.
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		_, err := x.QuarantineBlock(tx, 1, "tenant-1", blocks[0].Id, &metastorev1.BlockQuarantine{QuarantinedAt: 1})
+		return err
+	}))
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			SourceBlocks: &metastorev1.BlockList{
+				Tenant: "tenant-1",
+				Shard:  1,
+				Blocks: []string{blocks[1].Id},
+			},
+		}, time.Now())
+	}))
+	assert.Nil(t, found[0].Quarantine)
+	assert.Zero(t, found[1].DeletedAt)
+	assert.Empty(t, find())
+}
+
+func TestIndex_ArchivePartition(t *testing.T) {
+	db := test.BoltDB(t)
+	bucket := memory.NewInMemBucket()
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, Bucket: bucket}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:01.123Z"), Shard: 2, TenantId: "tenant-2"}, "tenant-2"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	key := store.PartitionKey("20240923T08.1h")
+	assert.Equal(t, []store.PartitionKey{key}, x.PartitionsToArchive(time.UnixMilli(test.Time("2024-09-23T09:30:00.000Z"))))
+
+	var data []byte
+	var stub *raft_log.ArchivedPartition
+	require.NoError(t, db.View(func(tx *bbolt.Tx) (err error) {
+		data, stub, err = x.PartitionArchive(tx, key)
+		return err
+	}))
+	assert.Equal(t, uint32(2), stub.Blocks)
+	assert.ElementsMatch(t, []string{"tenant-1", "tenant-2"}, stub.Tenants)
+	assert.Equal(t, blocks[0].MinTime, stub.MinTime)
+	assert.Equal(t, blocks[1].MaxTime, stub.MaxTime)
+	require.Len(t, stub.TenantStats, 2)
+	assert.Equal(t, "tenant-1", stub.TenantStats[0].TenantId)
+	assert.Equal(t, uint64(1), stub.TenantStats[0].BlockCount)
+	assert.True(t, strings.HasPrefix(stub.Path, "__pyroscope_cluster/metastore/index/"), stub.Path)
+	require.NoError(t, bucket.Upload(context.Background(), stub.Path, bytes.NewReader(data)))
+
+	// The stub must match the partition state.
+	changed := stub.CloneVT()
+	changed.Checksum++
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		archived, err := x.ArchivePartition(tx, changed)
+		assert.False(t, archived)
+		return err
+	}))
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		archived, err := x.ArchivePartition(tx, stub)
+		assert.True(t, archived)
+		return err
+	}))
+	assert.Empty(t, x.PartitionsToArchive(time.UnixMilli(test.Time("2024-09-23T09:30:00.000Z"))))
+
+	// Archived partitions can not be modified.
+	late := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:30:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1")
+	err := db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, late)
+	})
+	var invalid *index.InvalidBlockError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, index.ArchivedPartition, invalid.Reason)
+
+	// The compacted blocks of archived partitions are rejected.
+	err = db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			SourceBlocks: &metastorev1.BlockList{Tenant: "tenant-1", Shard: 1, Blocks: []string{blocks[0].Id}},
+			NewBlocks:    []*metastorev1.BlockMeta{late},
+		}, time.Now())
+	})
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, index.ArchivedPartition, invalid.Reason)
+
+	// The blocks of archived partitions are not visible to the commands
+	// modifying the index, even if the archive is cached by the replica.
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		assert.Nil(t, x.FindBlock(tx, 1, "tenant-1", blocks[0].Id))
+		// Such blocks are assumed to be present.
+		assert.True(t, x.ContainsBlock(tx, 1, "tenant-1", blocks[0].Id))
+		assert.False(t, x.ContainsBlock(tx, 1, "tenant-1", test.ULID("2024-09-25T08:00:00.000Z")))
+		return nil
+	}))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.NotNil(t, x.FindBlock(tx, 1, "tenant-1", blocks[0].Id))
+		return nil
+	}))
+
+	// The archived blocks are fetched from the bucket after restore.
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	// The tenant stats of archived partitions are kept in the stub.
+	stats := x.TenantBlockStats([]string{"tenant-1"})
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(2), stats[0].BlockCount)
+	start := test.Time("2024-09-23T08:00:00.000Z")
+	end := test.Time("2024-09-23T10:00:00.000Z")
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		found := x.FindBlocksInRange(tx, start, end, map[string]struct{}{"tenant-1": {}, "tenant-2": {}}, nil, nil)
+		require.Len(t, found, 3)
+		assert.NotNil(t, x.FindBlock(tx, 2, "tenant-2", blocks[1].Id))
+		return nil
+	}))
+
+	// Archives that can not be fetched are not cached.
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	require.NoError(t, bucket.Delete(context.Background(), stub.Path))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Nil(t, x.FindBlock(tx, 1, "tenant-1", blocks[0].Id))
+		return nil
+	}))
+	require.NoError(t, bucket.Upload(context.Background(), stub.Path, bytes.NewReader(data)))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.NotNil(t, x.FindBlock(tx, 1, "tenant-1", blocks[0].Id))
+		return nil
+	}))
+}
+
+func TestIndex_DeleteArchivedPartition(t *testing.T) {
+	db := test.BoltDB(t)
+	bucket := memory.NewInMemBucket()
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, Bucket: bucket}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	const dictionary = "blocks/1/tenant-1/dictionaries/1/01J8F6JBQQZ8CNGRF4A1K9WHE6/symbols.symdb"
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1", CompactionLevel: 1}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:01.123Z"), Shard: 1, TenantId: "tenant-1", CompactionLevel: 1}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:02.123Z"), Shard: 2, TenantId: "tenant-2"}, "tenant-2"),
+	}
+	blocks[0].Datasets[0].SymbolsDictionary = &metastorev1.SymbolsDictionary{Path: dictionary}
+	blocks[1].Datasets[0].SymbolsDictionary = &metastorev1.SymbolsDictionary{Path: dictionary}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	key := store.PartitionKey("20240923T08.1h")
+	var stub *raft_log.ArchivedPartition
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		data, s, err := x.PartitionArchive(tx, key)
+		if err != nil {
+			return err
+		}
+		stub = s
+		if err = bucket.Upload(context.Background(), stub.Path, bytes.NewReader(data)); err != nil {
+			return err
+		}
+		_, err = x.ArchivePartition(tx, stub)
+		return err
+	}))
+	assert.Equal(t, []*raft_log.ArchivedDictionaryRefs{
+		{Shard: 1, Tenant: "tenant-1", Path: dictionary, Blocks: 2},
+	}, stub.Dictionaries)
+
+	before := time.UnixMilli(test.Time("2024-09-23T10:00:00.000Z"))
+	deletePartitions := func(tenant string) (d *index.DeletedPartitions) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) (err error) {
+			d, err = x.DeletePartitionsBefore(tx, before, tenant, before)
+			return err
+		}))
+		return d
+	}
+
+	// Archived partitions are not modified for a single tenant.
+	d := deletePartitions("tenant-1")
+	assert.Empty(t, d.Partitions)
+	assert.Empty(t, d.Archives)
+
+	d = deletePartitions("")
+	assert.Equal(t, []store.PartitionKey{key}, d.Partitions)
+	assert.Empty(t, d.Blocks)
+	require.Len(t, d.Archives, 1)
+	assert.Equal(t, stub.Path, d.Archives[0].Path)
+	assert.Equal(t, []index.ReleasedDictionary{{Shard: 1, Tenant: "tenant-1", Path: dictionary}}, d.Dictionaries)
+
+	// The archive is not restored.
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	assert.Empty(t, x.TenantBlockStats([]string{"tenant-1"}))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Nil(t, x.FindBlock(tx, 1, "tenant-1", blocks[0].Id))
+		return nil
+	}))
+}
+
+func TestIndex_PartitionScheme(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, PartitionScheme: "tenant-hash:8"}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	mixed := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1}, "tenant-1")
+	blocks := []*metastorev1.BlockMeta{
+		mixed,
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:01.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:02.123Z"), Shard: 1, TenantId: "tenant-3"}, "tenant-3"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	assertPartitions := func(x *index.Index) {
+		var keys []string
+		for _, p := range x.FindPartitionsInRange(test.Time("2024-09-23T08:00:00.000Z"), test.Time("2024-09-23T08:30:00.000Z"), map[string]struct{}{"tenant-1": {}, "tenant-3": {}}) {
+			keys = append(keys, p.Key)
+		}
+		assert.ElementsMatch(t, []string{"20240923T08.1h", "t4-20240923T08.1h", "t6-20240923T08.1h"}, keys)
+	}
+	assertPartitions(x)
+
+	// The recorded scheme is used regardless of the configuration.
+	c = &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, PartitionScheme: "shard"}
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	assertPartitions(x)
+
+	start := test.Time("2024-09-23T08:00:00.000Z")
+	end := test.Time("2024-09-23T09:00:00.000Z")
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		found := x.FindBlocksInRange(tx, start, end, map[string]struct{}{"tenant-1": {}}, nil, nil)
+		require.Len(t, found, 2)
+		for _, b := range blocks {
+			assert.NotNil(t, x.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+			byID, p := x.FindBlockByID(tx, b.Id)
+			require.NotNil(t, byID)
+			assert.Equal(t, b.TenantId, byID.TenantId)
+			assert.NotNil(t, p)
+		}
+		return nil
+	}))
+
+	compacted := withDataset(&metastorev1.BlockMeta{
+		Id:              test.ULID("2024-09-23T08:01:00.123Z"),
+		Shard:           1,
+		CompactionLevel: 1,
+		TenantId:        "tenant-1",
+	}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			NewBlocks:    []*metastorev1.BlockMeta{compacted},
+			SourceBlocks: &metastorev1.BlockList{Shard: 1, Tenant: "tenant-1", Blocks: []string{blocks[1].Id}},
+		}, time.Now())
+	}))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Nil(t, x.FindBlock(tx, 1, "tenant-1", blocks[1].Id))
+		assert.NotNil(t, x.FindBlock(tx, 1, "tenant-1", compacted.Id))
+		return nil
+	}))
+	assertPartitions(x)
+}
+
+func TestIndex_MergePartitions(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T10:00:00.123Z"), Shard: 2, TenantId: "tenant-2"}, "tenant-2"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-24T10:00:00.123Z"), Shard: 2, TenantId: "tenant-2"}, "tenant-2"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+	assert.Empty(t, x.PartitionsToMerge())
+
+	start := test.Time("2024-09-23T00:00:00.000Z")
+	end := test.Time("2024-09-25T00:00:00.000Z")
+	tenants := map[string]struct{}{"tenant-1": {}, "tenant-2": {}}
+	partitions := func(x *index.Index) []string {
+		var keys []string
+		for _, p := range x.FindPartitionsInRange(start, end, tenants) {
+			keys = append(keys, p.Key)
+		}
+		return keys
+	}
+
+	c = &index.Config{PartitionDuration: 24 * time.Hour, PartitionCacheSize: 7}
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	merges := x.PartitionsToMerge()
+	require.Len(t, merges, 1, "single partitions are not merged into new ones")
+	assert.Equal(t, "20240923.1d", merges[0].Target)
+	assert.Equal(t, []string{"20240923T08.1h", "20240923T09.1h", "20240923T10.1h"}, merges[0].Source)
+
+	// Partitions that don't belong to the target are skipped.
+	merges[0].Source = append(merges[0].Source, "20240924T10.1h", "20240923T10.1h")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		resp, err := x.MergePartitions(tx, merges[0])
+		require.NoError(t, err)
+		assert.Equal(t, []string{"20240923T08.1h", "20240923T09.1h", "20240923T10.1h"}, resp.Merged)
+		assert.Equal(t, uint32(3), resp.Blocks)
+		return nil
+	}))
+
+	assertMerged := func(x *index.Index) {
+		assert.ElementsMatch(t, []string{"20240923.1d", "20240924T10.1h"}, partitions(x))
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			assert.Len(t, x.FindBlocksInRange(tx, start, end, tenants, nil, nil), 4)
+			for _, b := range blocks {
+				assert.NotNil(t, x.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+			}
+			return nil
+		}))
+	}
+	assertMerged(x)
+
+	// The remaining partition is merged once the target exists.
+	late := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-24T11:00:00.123Z"), Shard: 2, TenantId: "tenant-2"}, "tenant-2")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, late)
+	}))
+	merges = x.PartitionsToMerge()
+	require.Len(t, merges, 1)
+	assert.Equal(t, "20240924.1d", merges[0].Target)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		_, err := x.MergePartitions(tx, merges[0])
+		return err
+	}))
+	blocks = append(blocks, late)
+
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	assert.ElementsMatch(t, []string{"20240923.1d", "20240924.1d"}, partitions(x))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Len(t, x.FindBlocksInRange(tx, start, end, tenants, nil, nil), 5)
+		for _, b := range blocks {
+			assert.NotNil(t, x.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+		}
+		return nil
+	}))
+	assert.Empty(t, x.PartitionsToMerge())
+}
+
+func TestIndex_SplitPartition(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: 24 * time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T14:00:00.123Z"), Shard: 2, TenantId: "tenant-2"}, "tenant-2"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-24T10:00:00.123Z"), Shard: 2, TenantId: "tenant-2"}, "tenant-2"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+	assert.Empty(t, x.PartitionsToSplit())
+
+	start := test.Time("2024-09-23T00:00:00.000Z")
+	end := test.Time("2024-09-25T00:00:00.000Z")
+	tenants := map[string]struct{}{"tenant-1": {}, "tenant-2": {}}
+	partitions := func(x *index.Index) []string {
+		var keys []string
+		for _, p := range x.FindPartitionsInRange(start, end, tenants) {
+			keys = append(keys, p.Key)
+		}
+		return keys
+	}
+
+	c = &index.Config{PartitionDuration: 6 * time.Hour, PartitionCacheSize: 7}
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+
+	// The blocks are moved to the existing target partitions.
+	late := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T07:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, late)
+	}))
+	blocks = append(blocks, late)
+
+	splits := x.PartitionsToSplit()
+	require.Len(t, splits, 2)
+	assert.Equal(t, "20240923.1d", splits[0].Source)
+	assert.Equal(t, "20240924.1d", splits[1].Source)
+	assert.Equal(t, (6 * time.Hour).Milliseconds(), splits[0].Duration)
+	assert.Empty(t, x.PartitionsToMerge())
+
+	// Partitions that can't be split into partitions
+	// of the requested duration are left intact.
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		resp, err := x.SplitPartition(tx, &raft_log.SplitPartitionRequest{
+			Source:   "20240923.1d",
+			Duration: (5 * time.Hour).Milliseconds(),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Target)
+		return nil
+	}))
+
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		resp, err := x.SplitPartition(tx, splits[0])
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"20240923T06.6h", "20240923T12.6h"}, resp.Target)
+		assert.Equal(t, uint32(3), resp.Blocks)
+		return nil
+	}))
+
+	assertSplit := func(x *index.Index, expected []string) {
+		assert.ElementsMatch(t, expected, partitions(x))
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			assert.Len(t, x.FindBlocksInRange(tx, start, end, tenants, nil, nil), len(blocks))
+			for _, b := range blocks {
+				assert.NotNil(t, x.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+			}
+			return nil
+		}))
+	}
+	assertSplit(x, []string{"20240923T06.6h", "20240923T12.6h", "20240924.1d"})
+	require.NoError(t, x.ForEachPartition(context.Background(), func(p *index.PartitionMeta) error {
+		if p.Key == "20240923T06.6h" {
+			assert.Equal(t, 3, p.BlockCount)
+			assert.Equal(t, []string{"tenant-1"}, p.Tenants)
+		}
+		return nil
+	}))
+
+	// The migration is resumed after a restart.
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	splits = x.PartitionsToSplit()
+	require.Len(t, splits, 1)
+	assert.Equal(t, "20240924.1d", splits[0].Source)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		_, err := x.SplitPartition(tx, splits[0])
+		return err
+	}))
+	assertSplit(x, []string{"20240923T06.6h", "20240923T12.6h", "20240924T06.6h"})
+	assert.Empty(t, x.PartitionsToSplit())
+	assert.Empty(t, x.PartitionsToMerge())
+
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	assertSplit(x, []string{"20240923T06.6h", "20240923T12.6h", "20240924T06.6h"})
+}
+
+type partitionDurations map[string]time.Duration
+
+func (d partitionDurations) MetastoreIndexPartitionDuration(tenant string) time.Duration {
+	return d[tenant]
+}
+
+func (partitionDurations) MetastoreIndexRetentionPeriod(string) time.Duration { return 0 }
+
+func TestIndex_TenantPartitionDuration(t *testing.T) {
+	db := test.BoltDB(t)
+	limits := partitionDurations{"tenant-1": time.Hour}
+	c := &index.Config{PartitionDuration: 24 * time.Hour, PartitionCacheSize: 7, Limits: limits}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:30:00.123Z"), Shard: 1, TenantId: "tenant-2"}, "tenant-2"),
+		// Blocks that include data of multiple tenants use the configured duration.
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:10:00.123Z"), Shard: 1}, "tenant-1"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	start := test.Time("2024-09-23T00:00:00.000Z")
+	end := test.Time("2024-09-24T00:00:00.000Z")
+	tenants := map[string]struct{}{"tenant-1": {}, "tenant-2": {}}
+	assertBlocks := func(x *index.Index, partitions ...string) {
+		var keys []string
+		for _, p := range x.FindPartitionsInRange(start, end, tenants) {
+			keys = append(keys, p.Key)
+		}
+		assert.ElementsMatch(t, partitions, keys)
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			found := make(map[string]struct{})
+			for _, b := range x.FindBlocksInRange(tx, start, end, tenants, nil, nil) {
+				found[b.Id] = struct{}{}
+			}
+			assert.Len(t, found, len(blocks))
+			for _, b := range blocks {
+				assert.NotNil(t, x.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+				byID, _ := x.FindBlockByID(tx, b.Id)
+				assert.NotNil(t, byID)
+			}
+			return nil
+		}))
+	}
+	assertBlocks(x, "20240923T08.1h", "20240923T09.1h", "20240923.1d")
+	// Partitions of the tenant duration are not merged.
+	assert.Empty(t, x.PartitionsToMerge())
+
+	// Partitions of different durations are restored from the store.
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	assertBlocks(x, "20240923T08.1h", "20240923T09.1h", "20240923.1d")
+
+	// Once the override is removed, the partitions are merged.
+	delete(limits, "tenant-1")
+	merges := x.PartitionsToMerge()
+	require.Len(t, merges, 1)
+	assert.Equal(t, "20240923.1d", merges[0].Target)
+	assert.Equal(t, []string{"20240923T08.1h", "20240923T09.1h"}, merges[0].Source)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		_, err := x.MergePartitions(tx, merges[0])
+		return err
+	}))
+	assertBlocks(x, "20240923.1d")
+}
+
+func TestIndex_PartitionQuota(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, PartitionMaxBlocks: 2}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1", Size: 10}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:01:00.123Z"), Shard: 1, TenantId: "tenant-1", Size: 10}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:02:00.123Z"), Shard: 1, TenantId: "tenant-1", Size: 10}, "tenant-1"),
+	}
+	stats := func(x *index.Index) (int, uint64) {
+		metas := x.FindPartitionMetas(blocks[0].Id)
+		require.Len(t, metas, 1)
+		return metas[0].BlockCount, metas[0].BlockSize
+	}
+	for j, b := range blocks {
+		assert.False(t, x.ExceedsQuota(b.Shard, b.TenantId, b.Id), j)
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+	n, size := stats(x)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, uint64(30), size)
+	assert.True(t, x.ExceedsQuota(1, "tenant-1", blocks[0].Id))
+	assert.False(t, x.ExceedsQuota(2, "tenant-1", test.ULID("2024-09-23T09:00:00.123Z")))
+
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	n, size = stats(x)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, uint64(30), size)
+
+	compacted := withDataset(&metastorev1.BlockMeta{
+		Id:              test.ULID("2024-09-23T08:03:00.123Z"),
+		Shard:           1,
+		TenantId:        "tenant-1",
+		CompactionLevel: 1,
+		Size:            25,
+	}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			NewBlocks: []*metastorev1.BlockMeta{compacted},
+			SourceBlocks: &metastorev1.BlockList{
+				Tenant: "tenant-1",
+				Shard:  1,
+				Blocks: []string{blocks[0].Id, blocks[1].Id, blocks[2].Id},
+			},
+		}, time.Now())
+	}))
+	n, size = stats(x)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, uint64(25), size)
+	assert.False(t, x.ExceedsQuota(1, "tenant-1", compacted.Id))
+
+	c.PartitionMaxSize = 20
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	n, size = stats(x)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, uint64(25), size)
+	assert.True(t, x.ExceedsQuota(1, "tenant-1", compacted.Id))
+}
+
+func TestIndex_FindBlocksInRange_ProfileTypes(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	const (
+		cpu    = "process_cpu:cpu:nanoseconds:cpu:nanoseconds"
+		memory = "memory:inuse_space:bytes:space:bytes"
+	)
+	withProfileTypes := func(b *metastorev1.BlockMeta, profileTypes ...string) *metastorev1.BlockMeta {
+		b = withDataset(b, "tenant-1")
+		b.Datasets[0].ProfileTypes = profileTypes
+		return b
+	}
+	blocks := []*metastorev1.BlockMeta{
+		withProfileTypes(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.001Z"), TenantId: "tenant-1"}, cpu),
+		withProfileTypes(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.002Z"), TenantId: "tenant-1"}, cpu, memory),
+		withProfileTypes(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.003Z"), TenantId: "tenant-1"}),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	start := test.Time("2024-09-23T08:00:00.000Z")
+	end := test.Time("2024-09-23T09:00:00.000Z")
+	tenants := map[string]struct{}{"tenant-1": {}}
+	find := func(profileTypes map[string]struct{}) []string {
+		var ids []string
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			for _, b := range x.FindBlocksInRange(tx, start, end, tenants, profileTypes, nil) {
+				ids = append(ids, b.Id)
+			}
+			return nil
+		}))
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{blocks[0].Id, blocks[1].Id, blocks[2].Id}, find(nil))
+	assert.ElementsMatch(t, []string{blocks[0].Id, blocks[1].Id, blocks[2].Id}, find(map[string]struct{}{cpu: {}}))
+	// Blocks that do not list the profile types are always included.
+	assert.ElementsMatch(t, []string{blocks[1].Id, blocks[2].Id}, find(map[string]struct{}{memory: {}}))
+	assert.ElementsMatch(t, []string{blocks[2].Id}, find(map[string]struct{}{"unknown": {}}))
+}
+
+func TestIndex_FindBlocksInRange_Services(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	withServices := func(b *metastorev1.BlockMeta, services ...string) *metastorev1.BlockMeta {
+		b = withDataset(b, "tenant-1")
+		ds := b.Datasets[0]
+		b.Datasets = b.Datasets[:0]
+		for _, s := range services {
+			d := ds.CloneVT()
+			d.Name = s
+			b.Datasets = append(b.Datasets, d)
+		}
+		return b
+	}
+	blocks := []*metastorev1.BlockMeta{
+		withServices(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.001Z"), TenantId: "tenant-1"}, "service-a"),
+		withServices(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.002Z"), TenantId: "tenant-1"}, "service-a", "service-b"),
+		withServices(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.003Z"), TenantId: "tenant-1", Shard: 1}, "service-c"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	start := test.Time("2024-09-23T08:00:00.000Z")
+	end := test.Time("2024-09-23T09:00:00.000Z")
+	tenants := map[string]struct{}{"tenant-1": {}}
+	find := func(x *index.Index, services ...string) []string {
+		var s map[string]struct{}
+		if len(services) > 0 {
+			s = make(map[string]struct{})
+			for _, name := range services {
+				s[name] = struct{}{}
+			}
+		}
+		var ids []string
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			for _, b := range x.FindBlocksInRange(tx, start, end, tenants, nil, s) {
+				ids = append(ids, b.Id)
+			}
+			return nil
+		}))
+		return ids
+	}
+
+	assertFound := func(x *index.Index) {
+		assert.ElementsMatch(t, []string{blocks[0].Id, blocks[1].Id, blocks[2].Id}, find(x))
+		assert.ElementsMatch(t, []string{blocks[0].Id, blocks[1].Id}, find(x, "service-a"))
+		assert.ElementsMatch(t, []string{blocks[1].Id}, find(x, "service-b"))
+		assert.ElementsMatch(t, []string{blocks[0].Id, blocks[1].Id, blocks[2].Id}, find(x, "service-a", "service-b", "service-c"))
+		assert.Empty(t, find(x, "unknown"))
+	}
+	assertFound(x)
+
+	// The dataset index is rebuilt as the partitions are loaded.
+	restored := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(restored.Init))
+	require.NoError(t, db.View(restored.Restore))
+	assertFound(restored)
+
+	// Blocks deleted from the index are not found by services either.
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			SourceBlocks: &metastorev1.BlockList{
+				Tenant: "tenant-1",
+				Blocks: []string{blocks[1].Id},
+			},
+		}, time.Now())
+	}))
+	assert.ElementsMatch(t, []string{blocks[0].Id}, find(x, "service-a"))
+	assert.Empty(t, find(x, "service-b"))
+}
+
+func TestIndex_PartitionBlockFilter(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.001Z"), TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:10:00.001Z"), TenantId: "tenant-1"}, "tenant-1"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	start := test.Time("2024-09-23T08:00:00.000Z")
+	end := test.Time("2024-09-23T08:59:59.999Z")
+	tenants := map[string]struct{}{"tenant-1": {}}
+	cached := func(x *index.Index) bool {
+		partitions := x.FindPartitionsInRange(start, end, tenants)
+		require.Len(t, partitions, 1)
+		return partitions[0].Cached
+	}
+	find := func(x *index.Index, id string) (b *metastorev1.BlockMeta) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			b, _ = x.FindBlockByID(tx, id)
+			return nil
+		}))
+		return b
+	}
+	missing := test.ULID("2024-09-23T08:30:00.001Z")
+
+	for _, size := range []int{0, 1 << 10} {
+		t.Run(fmt.Sprintf("filter size %d", size), func(t *testing.T) {
+			c.PartitionBlockFilterSize = size
+			x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+			require.NoError(t, db.Update(x.Init))
+			require.NoError(t, db.View(x.Restore))
+			require.False(t, cached(x))
+
+			// The partition is only loaded if it might include the block.
+			assert.Nil(t, find(x, missing))
+			assert.Equal(t, size == 0, cached(x))
+			for _, b := range blocks {
+				assert.NotNil(t, find(x, b.Id))
+			}
+			assert.True(t, cached(x))
+		})
+	}
+
+	// Blocks added to the partition are added to the filter.
+	c.PartitionBlockFilterSize = 1 << 10
+	c.PartitionCacheSize = 0
+	x = index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+	late := withDataset(&metastorev1.BlockMeta{Id: missing, TenantId: "tenant-1"}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, late)
+	}))
+	assert.NotNil(t, find(x, late.Id))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.NotNil(t, x.FindBlock(tx, 0, "tenant-1", late.Id))
+		return nil
+	}))
+}
+
+func TestIndex_PartitionCacheBytes(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T10:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+	}
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+	}
+
+	// Each partition holds a single shard with a single
+	// block: the cache is limited to the size of two partitions.
+	size := func(blocks ...*metastorev1.BlockMeta) (n uint64) {
+		for _, b := range blocks {
+			n += index.PartitionEntrySize + index.ShardEntrySize + index.BlockEntrySize(b)
+		}
+		return n
+	}
+	c.PartitionCacheBytes = size(blocks[0], blocks[1])
+	reg := prometheus.NewRegistry()
+	x = index.NewIndex(util.Logger, index.NewStore(), c, reg)
+	require.NoError(t, db.Update(x.Init))
+	require.NoError(t, db.View(x.Restore))
+
+	find := func(b *metastorev1.BlockMeta) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			found := x.FindBlock(tx, b.Shard, b.TenantId, b.Id)
+			require.NotNil(t, found)
+			assert.Equal(t, b.Id, found.Id)
+			return nil
+		}))
+	}
+	expect := func(loaded uint64, evicted int) {
+		expected := fmt.Sprintf(`
+# HELP metastore_index_evicted_partitions_total The total number of index partitions unloaded from memory because the partition cache size limit was reached.
+# TYPE metastore_index_evicted_partitions_total counter
+metastore_index_evicted_partitions_total %d
+# HELP metastore_index_loaded_partitions_bytes Approximate memory footprint of the index partitions loaded in memory.
+# TYPE metastore_index_loaded_partitions_bytes gauge
+metastore_index_loaded_partitions_bytes %d
+`, evicted, loaded)
+		require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+			"metastore_index_evicted_partitions_total",
+			"metastore_index_loaded_partitions_bytes",
+		))
+	}
+
+	expect(0, 0)
+	find(blocks[0])
+	find(blocks[1])
+	expect(c.PartitionCacheBytes, 0)
+
+	// The least recently accessed partition is unloaded before the next one is loaded.
+	find(blocks[2])
+	expect(size(blocks[1], blocks[2]), 1)
+	find(blocks[0])
+	expect(size(blocks[2], blocks[0]), 2)
+
+	// Blocks of the partitions loaded are accounted for.
+	// The block is added to the shard of a loaded partition.
+	b := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:30:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, b)
+	}))
+	expect(size(blocks[2], blocks[0])+index.BlockEntrySize(b), 2)
+	deletedAt := time.UnixMilli(1000)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			SourceBlocks: &metastorev1.BlockList{Tenant: "tenant-1", Shard: 1, Blocks: []string{b.Id}},
+		}, deletedAt)
+	}))
+	// The block is kept marked as deleted until it is swept.
+	deleted := b.CloneVT()
+	deleted.DeletedAt = deletedAt.UnixMilli()
+	expect(size(blocks[2], blocks[0])+index.BlockEntrySize(deleted), 2)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		_, _, err := x.SweepDeletedBlocks(tx, &raft_log.SweepDeletedBlocksRequest{DeletedBefore: deletedAt.UnixMilli()}, time.Now())
+		return err
+	}))
+	expect(size(blocks[2], blocks[0]), 2)
+}
+
+func TestIndex_WriteBehind(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{
+		PartitionDuration:         time.Hour,
+		PartitionCacheSize:        1,
+		BlockWriteBehindInterval:  time.Minute,
+		BlockWriteBehindQueueSize: 3,
+	}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	insert := func(ts string) *metastorev1.BlockMeta {
+		b := withDataset(&metastorev1.BlockMeta{Id: test.ULID(ts), Shard: 1, TenantId: "tenant-1"}, "tenant-1")
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, b)
+		}))
+		return b
+	}
+	stored := func() int {
+		restored := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+		var n int
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			require.NoError(t, restored.Restore(tx))
+			n = len(restored.FindBlocksInRange(tx, 0, time.Now().UnixMilli(), map[string]struct{}{"tenant-1": {}}, nil, nil))
+			return nil
+		}))
+		return n
+	}
+	find := func(b *metastorev1.BlockMeta) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			assert.NotNil(t, x.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+			return nil
+		}))
+	}
+
+	a := insert("2024-09-23T08:00:00.000Z")
+	b := insert("2024-09-23T09:00:00.000Z")
+	assert.Equal(t, 0, stored())
+
+	// The pending blocks are found after the partitions
+	// are unloaded from memory and loaded from the store.
+	find(a)
+	find(b)
+	find(a)
+
+	// The queue is full: the blocks are stored with the last one.
+	insert("2024-09-23T10:00:00.000Z")
+	assert.Equal(t, 3, stored())
+
+	d := insert("2024-09-23T11:00:00.000Z")
+	assert.Equal(t, 3, stored())
+	require.NoError(t, db.Update(x.Flush))
+	assert.Equal(t, 4, stored())
+	find(d)
+
+	// Pending blocks are stored before the stored blocks are modified.
+	e := insert("2024-09-23T11:30:00.000Z")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			SourceBlocks: &metastorev1.BlockList{Tenant: "tenant-1", Shard: 1, Blocks: []string{d.Id}},
+		}, time.Now())
+	}))
+	assert.Equal(t, 4, stored())
+	find(e)
+}
+
+func TestIndex_InsertBlocks(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	a := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.001Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1")
+	b := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.001Z"), Shard: 2, TenantId: "tenant-1"}, "tenant-1")
+	invalid := &metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.002Z"), Shard: 1, TenantId: "tenant-1"}
+
+	var errs []error
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		errs = x.InsertBlocks(tx, []*metastorev1.BlockMeta{a, invalid, b, a})
+		return nil
+	}))
+	require.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], index.ErrInvalidBlock)
+	assert.NoError(t, errs[2])
+	var exists *index.BlockExistsError
+	require.ErrorAs(t, errs[3], &exists)
+	assert.Equal(t, a.Id, exists.Block.Id)
+
+	// The blocks are stored.
+	restored := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		require.NoError(t, restored.Restore(tx))
+		assert.NotNil(t, restored.FindBlock(tx, a.Shard, a.TenantId, a.Id))
+		assert.NotNil(t, restored.FindBlock(tx, b.Shard, b.TenantId, b.Id))
+		assert.Nil(t, restored.FindBlock(tx, invalid.Shard, invalid.TenantId, invalid.Id))
+		return nil
+	}))
+}
+
+func TestIndex_ListBlocks(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{
+		PartitionDuration:         time.Hour,
+		PartitionCacheSize:        1,
+		BlockWriteBehindInterval:  time.Minute,
+		BlockWriteBehindQueueSize: 4,
+	}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		{Id: test.ULID("2024-09-23T08:00:00.002Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-09-23T08:00:00.001Z"), Shard: 2, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-09-23T08:00:00.003Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-09-23T09:00:00.001Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-09-23T09:00:00.002Z"), Shard: 1, TenantId: "tenant-2"},
+		{Id: test.ULID("2024-09-23T08:00:00.004Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-09-23T08:00:00.005Z"), Shard: 1, TenantId: "tenant-1"},
+	}
+	// The last blocks are pending in the write-behind queue.
+	for _, b := range blocks {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return x.InsertBlock(tx, withDataset(b, b.TenantId))
+		}))
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		_, err := x.QuarantineBlock(tx, 1, "tenant-1", blocks[2].Id, &metastorev1.BlockQuarantine{Reason: "corrupted"})
+		return err
+	}))
+
+	list := func(tenant string, start, end string, limit int) (pages [][]string) {
+		var after *index.BlockCursor
+		for {
+			var page []string
+			require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+				found, next, err := x.ListBlocks(tx, tenant, test.Time(start), test.Time(end), after, limit)
+				for _, b := range found {
+					page = append(page, b.Id)
+				}
+				after = next
+				return err
+			}))
+			pages = append(pages, page)
+			if after == nil {
+				return pages
+			}
+			// The cursor is passed to the client as an opaque token.
+			c, err := index.ParseBlockCursor(after.String())
+			require.NoError(t, err)
+			after = &c
+		}
+	}
+
+	// Blocks are listed by partition, shard, and identifier.
+	assert.Equal(t, [][]string{
+		{blocks[0].Id, blocks[5].Id},
+		{blocks[6].Id, blocks[1].Id},
+		{blocks[3].Id},
+	}, list("tenant-1", "2024-09-23T08:00:00.000Z", "2024-09-23T10:00:00.000Z", 2))
+	assert.Equal(t, [][]string{
+		{blocks[0].Id, blocks[5].Id, blocks[6].Id, blocks[1].Id},
+		nil,
+	}, list("tenant-1", "2024-09-23T08:00:00.000Z", "2024-09-23T08:30:00.000Z", 4))
+	assert.Equal(t, [][]string{{blocks[4].Id}}, list("tenant-2", "2024-09-23T08:00:00.000Z", "2024-09-23T10:00:00.000Z", 10))
+	assert.Equal(t, [][]string{nil}, list("tenant-3", "2024-09-23T08:00:00.000Z", "2024-09-23T10:00:00.000Z", 10))
+
+	_, err := index.ParseBlockCursor("invalid")
+	assert.Error(t, err)
+}
+
+type retentionPeriods map[string]time.Duration
+
+func (retentionPeriods) MetastoreIndexPartitionDuration(string) time.Duration { return 0 }
+
+func (r retentionPeriods) MetastoreIndexRetentionPeriod(tenant string) time.Duration {
+	return r[tenant]
+}
+
+func TestIndex_DeletePartitionsBefore(t *testing.T) {
+	db := test.BoltDB(t)
+	limits := retentionPeriods{"tenant-1": 2 * time.Hour}
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, Limits: limits}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	shared := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:20:00.123Z"), Shard: 1}, "tenant-1")
+	shared = withDataset(shared, "tenant-2")
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:10:00.123Z"), Shard: 1, TenantId: "tenant-2"}, "tenant-2"),
+		shared,
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 2, TenantId: "tenant-1"}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T10:00:00.123Z"), Shard: 2, TenantId: "tenant-1"}, "tenant-1"),
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, err := range x.InsertBlocks(tx, blocks) {
+			require.NoError(t, err)
+		}
+		return nil
+	}))
+
+	start := test.Time("2024-09-23T00:00:00.000Z")
+	end := test.Time("2024-09-24T00:00:00.000Z")
+	tenants := map[string]struct{}{"tenant-1": {}, "tenant-2": {}}
+	partitions := func(x *index.Index) []string {
+		var keys []string
+		for _, p := range x.FindPartitionsInRange(start, end, tenants) {
+			keys = append(keys, p.Key)
+		}
+		return keys
+	}
+	found := func(x *index.Index) (ids []string) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			for _, b := range blocks {
+				if x.FindBlock(tx, b.Shard, b.TenantId, b.Id) != nil {
+					ids = append(ids, b.Id)
+				}
+			}
+			return nil
+		}))
+		return ids
+	}
+	apply := func(requests []*raft_log.DeletePartitionsRequest) (deleted []store.PartitionKey, removed []*metastorev1.BlockMeta) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			for _, req := range requests {
+				d, err := x.DeletePartitionsBefore(tx, time.UnixMilli(req.Before), req.TenantId, time.Now())
+				require.NoError(t, err)
+				deleted = append(deleted, d.Partitions...)
+				removed = append(removed, d.Blocks...)
+			}
+			return nil
+		}))
+		return deleted, removed
+	}
+
+	toDelete := func(now time.Time) (requests []*raft_log.DeletePartitionsRequest) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			requests = x.PartitionsToDelete(tx, now)
+			return nil
+		}))
+		return requests
+	}
+
+	// The blocks of tenant-2 are kept indefinitely, and so are the shared blocks.
+	now := time.UnixMilli(test.Time("2024-09-23T12:00:00.000Z"))
+	requests := toDelete(now)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "tenant-1", requests[0].TenantId)
+	assert.Equal(t, now.Add(-2*time.Hour).UnixMilli(), requests[0].Before)
+
+	deleted, removed := apply(requests)
+	assert.Equal(t, []store.PartitionKey{"20240923T09.1h"}, deleted)
+	require.Len(t, removed, 2)
+	assert.Equal(t, blocks[0].Id, removed[0].Id)
+	assert.Equal(t, blocks[3].Id, removed[1].Id)
+	assert.Empty(t, toDelete(now))
+
+	assertDeleted := func(x *index.Index) {
+		assert.ElementsMatch(t, []string{"20240923T08.1h", "20240923T10.1h"}, partitions(x))
+		assert.Equal(t, []string{blocks[1].Id, blocks[2].Id, blocks[4].Id}, found(x))
+	}
+	assertDeleted(x)
+	restored := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(restored.Init))
+	require.NoError(t, db.View(restored.Restore))
+	assertDeleted(restored)
+
+	// The shared blocks are deleted once the retention
+	// period of all the tenants has passed.
+	limits["tenant-2"] = 3 * time.Hour
+	requests = toDelete(now)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "tenant-2", requests[0].TenantId)
+	assert.Equal(t, "", requests[1].TenantId)
+	assert.Equal(t, now.Add(-3*time.Hour).UnixMilli(), requests[1].Before)
+
+	deleted, removed = apply(requests)
+	assert.Equal(t, []store.PartitionKey{"20240923T08.1h"}, deleted)
+	assert.Len(t, removed, 2)
+	assert.Equal(t, []string{"20240923T10.1h"}, partitions(x))
+	assert.Equal(t, []string{blocks[4].Id}, found(x))
+	assert.Empty(t, toDelete(now))
+}
+
+func TestIndex_SymbolsDictionaries(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	const d1 = "blocks/1/tenant-1/dictionaries/1/01J8F6JBQQZ8CNGRF4A1K9WHE6/symbols.symdb"
+	const d2 = "blocks/1/tenant-1/dictionaries/1/01J8F6JBQQZ8CNGRF4A1K9WHE7/symbols.symdb"
+	block := func(ts string, level uint32, dictionary string) *metastorev1.BlockMeta {
+		b := withDataset(&metastorev1.BlockMeta{
+			Id:              test.ULID(ts),
+			Shard:           1,
+			TenantId:        "tenant-1",
+			CompactionLevel: level,
+		}, "tenant-1")
+		if dictionary != "" {
+			b.Datasets[0].SymbolsDictionary = &metastorev1.SymbolsDictionary{Path: dictionary}
+		}
+		return b
+	}
+	replace := func(source []*metastorev1.BlockMeta, compacted *metastorev1.BlockMeta, deletedAt time.Time) error {
+		list := &metastorev1.BlockList{Tenant: "tenant-1", Shard: 1}
+		for _, b := range source {
+			list.Blocks = append(list.Blocks, b.Id)
+		}
+		return db.Update(func(tx *bbolt.Tx) error {
+			return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+				NewBlocks:    []*metastorev1.BlockMeta{compacted},
+				SourceBlocks: list,
+			}, deletedAt)
+		})
+	}
+	sweep := func(before time.Time) (released []index.ReleasedDictionary) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) (err error) {
+			req := &raft_log.SweepDeletedBlocksRequest{DeletedBefore: before.UnixMilli()}
+			_, released, err = x.SweepDeletedBlocks(tx, req, before)
+			return err
+		}))
+		return released
+	}
+
+	segments := []*metastorev1.BlockMeta{
+		block("2024-09-23T08:00:00.000Z", 0, ""),
+		block("2024-09-23T08:01:00.000Z", 0, ""),
+		block("2024-09-23T08:02:00.000Z", 0, ""),
+		block("2024-09-23T08:03:00.000Z", 0, ""),
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, err := range x.InsertBlocks(tx, segments) {
+			require.NoError(t, err)
+		}
+		return nil
+	}))
+	// Two compacted blocks refer to the same dictionary.
+	b1 := block("2024-09-23T08:04:00.000Z", 1, d1)
+	b2 := block("2024-09-23T08:05:00.000Z", 1, d1)
+	t1 := test.Time("2024-09-23T09:00:00.000Z")
+	require.NoError(t, replace(segments[:2], b1, time.UnixMilli(t1)))
+	require.NoError(t, replace(segments[2:], b2, time.UnixMilli(t1)))
+	assert.Empty(t, sweep(time.UnixMilli(t1)))
+
+	// The dictionary is released once no block refers to it.
+	b3 := block("2024-09-23T08:06:00.000Z", 2, d2)
+	t2 := test.Time("2024-09-23T10:00:00.000Z")
+	require.NoError(t, replace([]*metastorev1.BlockMeta{b1, b2}, b3, time.UnixMilli(t2)))
+	released := sweep(time.UnixMilli(t2))
+	assert.Equal(t, []index.ReleasedDictionary{{Shard: 1, Tenant: "tenant-1", Path: d1}}, released)
+
+	// The released dictionary can not be referenced again.
+	b4 := block("2024-09-23T08:07:00.000Z", 3, d1)
+	var invalid *index.InvalidBlockError
+	require.ErrorAs(t, replace([]*metastorev1.BlockMeta{b3}, b4, time.UnixMilli(t2)), &invalid)
+	assert.Equal(t, index.DictionaryReleased, invalid.Reason)
+	assert.Empty(t, sweep(time.UnixMilli(t2).Add(time.Hour)))
+	require.Error(t, replace([]*metastorev1.BlockMeta{b3}, b4, time.UnixMilli(t2).Add(time.Hour)))
+	// The release is forgotten once the dictionary object is deleted.
+	t3 := time.UnixMilli(t2).Add(25 * time.Hour)
+	assert.Empty(t, sweep(t3))
+	b4.Datasets[0].SymbolsDictionary.Path = d2
+	require.NoError(t, replace([]*metastorev1.BlockMeta{b3}, b4, t3))
+
+	// The dictionaries of the blocks deleted due to retention are released,
+	// including these of the blocks marked as deleted, but not swept yet.
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		d, err := x.DeletePartitionsBefore(tx, time.UnixMilli(t2), "tenant-1", time.UnixMilli(t2))
+		require.NoError(t, err)
+		assert.Len(t, d.Blocks, 1)
+		assert.Equal(t, []index.ReleasedDictionary{{Shard: 1, Tenant: "tenant-1", Path: d2}}, d.Dictionaries)
+		return nil
+	}))
+}
+
+func TestIndex_BlockChanges(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7, ChangeLogSize: 3}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	_, cursor, notify, err := x.BlockChanges("", nil, 10)
+	require.NoError(t, err)
+
+	shared := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1}, "tenant-1")
+	compacted := withDataset(&metastorev1.BlockMeta{
+		Id:              test.ULID("2024-09-23T08:10:00.123Z"),
+		Shard:           1,
+		TenantId:        "tenant-1",
+		CompactionLevel: 1,
+	}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.InsertBlock(tx, shared.CloneVT())
+	}))
+	select {
+	case <-notify:
+	default:
+		t.Fatal("watchers are not notified of the change")
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			NewBlocks:    []*metastorev1.BlockMeta{compacted},
+			SourceBlocks: &metastorev1.BlockList{Shard: 1, Blocks: []string{shared.Id}},
+		}, time.UnixMilli(test.Time("2024-09-23T09:00:00.000Z")))
+	}))
+
+	changes, next, _, err := x.BlockChanges(cursor, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, metastorev1.BlockChangeType_BLOCK_CHANGE_TYPE_ADDED, changes[0].Type)
+	assert.Equal(t, "", changes[0].TenantId)
+	assert.True(t, shared.EqualVT(changes[0].Added[0]))
+	assert.Equal(t, metastorev1.BlockChangeType_BLOCK_CHANGE_TYPE_REPLACED, changes[1].Type)
+	assert.Equal(t, "", changes[1].TenantId)
+	assert.Equal(t, []string{shared.Id}, changes[1].Removed)
+	assert.Equal(t, metastorev1.BlockChangeType_BLOCK_CHANGE_TYPE_REPLACED, changes[2].Type)
+	assert.Equal(t, "tenant-1", changes[2].TenantId)
+	assert.True(t, compacted.EqualVT(changes[2].Added[0]))
+
+	// The changes are filtered by tenant, and read in pages.
+	changes, page, notify, err := x.BlockChanges(cursor, []string{"tenant-1"}, 1)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "tenant-1", changes[0].TenantId)
+	assert.Equal(t, next, page)
+	select {
+	case <-notify:
+		t.Fatal("no changes are expected after the cursor")
+	default:
+	}
+	changes, page, notify, err = x.BlockChanges(cursor, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.NotEqual(t, next, page)
+	select {
+	case <-notify:
+	default:
+		t.Fatal("more changes are expected after the cursor")
+	}
+
+	// Only the most recent changes are kept.
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		b := withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:20:00.123Z"), Shard: 2}, "tenant-2")
+		return x.InsertBlock(tx, b)
+	}))
+	_, _, _, err = x.BlockChanges(cursor, nil, 10)
+	assert.ErrorIs(t, err, index.ErrChangesUnavailable)
+	changes, _, _, err = x.BlockChanges(next, nil, 10)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	// The cursors are invalidated when the index is restored.
+	require.NoError(t, db.View(x.Restore))
+	_, _, _, err = x.BlockChanges(next, nil, 10)
+	assert.ErrorIs(t, err, index.ErrChangesUnavailable)
+	_, _, _, err = x.BlockChanges("invalid", nil, 10)
+	assert.Error(t, err)
+}
+
+func TestIndex_ListPartitions(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	blocks := []*metastorev1.BlockMeta{
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-1", Size: 10}, "tenant-1"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:10:00.123Z"), Shard: 2, TenantId: "tenant-2", Size: 20}, "tenant-2"),
+		withDataset(&metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 1, TenantId: "tenant-2", Size: 30}, "tenant-2"),
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, err := range x.InsertBlocks(tx, blocks) {
+			require.NoError(t, err)
+		}
+		return nil
+	}))
+
+	list := func(x *index.Index, tenants []string, start, end int64) (partitions []*metastorev1.PartitionDetails) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			partitions = x.ListPartitions(tx, tenants, start, end)
+			return nil
+		}))
+		return partitions
+	}
+
+	partitions := list(x, nil, 0, 0)
+	require.Len(t, partitions, 2)
+	p := partitions[0]
+	assert.Equal(t, "20240923T08.1h", p.Key)
+	assert.Equal(t, test.Time("2024-09-23T08:00:00.000Z"), p.StartTime)
+	assert.Equal(t, test.Time("2024-09-23T09:00:00.000Z"), p.EndTime)
+	assert.Equal(t, blocks[0].MinTime, p.MinTime)
+	assert.Equal(t, blocks[1].MaxTime, p.MaxTime)
+	assert.ElementsMatch(t, []string{"tenant-1", "tenant-2"}, p.Tenants)
+	assert.Equal(t, []uint32{1, 2}, p.Shards)
+	assert.Equal(t, uint64(2), p.BlockCount)
+	assert.Equal(t, uint64(30), p.BlockSize)
+	assert.False(t, p.Archived)
+	// The partitions are loaded for each tenant the blocks are inserted for.
+	require.Len(t, p.Loaded, 2)
+	assert.Equal(t, "tenant-1", p.Loaded[0].TenantId)
+	assert.Equal(t, "tenant-2", p.Loaded[1].TenantId)
+	assert.NotZero(t, p.Loaded[0].AccessedAt)
+	assert.NotZero(t, p.Loaded[0].Size)
+
+	partitions = list(x, []string{"tenant-1"}, 0, 0)
+	require.Len(t, partitions, 1)
+	assert.Equal(t, "20240923T08.1h", partitions[0].Key)
+
+	partitions = list(x, nil, test.Time("2024-09-23T09:00:00.000Z"), test.Time("2024-09-23T10:00:00.000Z"))
+	require.Len(t, partitions, 1)
+	assert.Equal(t, "20240923T09.1h", partitions[0].Key)
+
+	// The partitions are not loaded by the listing.
+	restored := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(restored.Init))
+	require.NoError(t, db.View(restored.Restore))
+	partitions = list(restored, nil, 0, 0)
+	require.Len(t, partitions, 2)
+	assert.Empty(t, partitions[0].Loaded)
+	assert.Equal(t, []uint32{1, 2}, partitions[0].Shards)
+}
+
+func TestIndex_ConcurrentQueriesAndInserts(t *testing.T) {
+	db := test.BoltDB(t)
+	// The cache is small, so that the partitions are
+	// evicted and loaded again by concurrent queries.
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 2}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	const (
+		hours   = 6
+		inserts = 50
+		readers = 4
+	)
+	start := time.Date(2024, 9, 23, 8, 0, 0, 0, time.UTC)
+	tenants := map[string]struct{}{"tenant-1": {}}
+	var inserted sync.WaitGroup
+	inserted.Add(1)
+	go func() {
+		defer inserted.Done()
+		for n := 0; n < inserts; n++ {
+			ts := start.Add(time.Duration(n%hours)*time.Hour + time.Duration(n)*time.Second)
+			b := withDataset(&metastorev1.BlockMeta{Id: test.ULID(ts.Format(time.RFC3339)), Shard: 1, TenantId: "tenant-1"}, "tenant-1")
+			assert.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+				return x.InsertBlock(tx, b)
+			}))
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < inserts; n++ {
+				from := start.Add(time.Duration((n+r)%hours) * time.Hour)
+				assert.NoError(t, db.View(func(tx *bbolt.Tx) error {
+					x.FindBlocksInRange(tx, from.UnixMilli(), from.Add(time.Hour).UnixMilli(), tenants, nil, nil)
+					x.FindPartitionsInRange(from.UnixMilli(), from.Add(time.Hour).UnixMilli(), tenants)
+					return nil
+				}))
+			}
+		}()
+	}
+	inserted.Wait()
+	wg.Wait()
+
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		found := x.FindBlocksInRange(tx, start.UnixMilli(), start.Add(hours*time.Hour).UnixMilli(), tenants, nil, nil)
+		assert.Len(t, found, inserts)
+		return nil
+	}))
+}
+
+// withDataset sets the time range of the block to the first minute
+// after its creation and adds a dataset of the tenant.
+func TestIndex_TenantBlockStats(t *testing.T) {
+	db := test.BoltDB(t)
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	// Level 0 blocks include data of multiple tenants:
+	// the stats are attributed by dataset.
+	l0 := withDataset(withDataset(&metastorev1.BlockMeta{
+		Id:    test.ULID("2024-09-23T08:00:00.123Z"),
+		Shard: 1,
+		Size:  30,
+	}, "tenant-1"), "tenant-2")
+	l0.Datasets[0].Size = 10
+	l0.Datasets[1].Size = 20
+	l1 := withDataset(&metastorev1.BlockMeta{
+		Id:              test.ULID("2024-09-23T09:00:00.123Z"),
+		Shard:           1,
+		TenantId:        "tenant-1",
+		CompactionLevel: 1,
+		Size:            50,
+	}, "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, err := range x.InsertBlocks(tx, []*metastorev1.BlockMeta{l0, l1}) {
+			require.NoError(t, err)
+		}
+		return nil
+	}))
+
+	expected := []*metastorev1.TenantBlockStats{
+		{
+			TenantId:      "tenant-1",
+			BlockCount:    2,
+			BlockSize:     60,
+			BlocksByLevel: []uint64{1, 1},
+			MinTime:       l0.MinTime,
+			MaxTime:       l1.MaxTime,
+		},
+		{
+			TenantId:      "tenant-2",
+			BlockCount:    1,
+			BlockSize:     20,
+			BlocksByLevel: []uint64{1},
+			MinTime:       l0.MinTime,
+			MaxTime:       l0.MaxTime,
+		},
+	}
+	assert.Equal(t, expected, x.TenantBlockStats(nil))
+	assert.Equal(t, expected[1:], x.TenantBlockStats([]string{"tenant-2"}))
+
+	compacted := withDataset(&metastorev1.BlockMeta{
+		Id:              test.ULID("2024-09-23T08:10:00.123Z"),
+		Shard:           1,
+		TenantId:        "tenant-2",
+		CompactionLevel: 1,
+		Size:            15,
+	}, "tenant-2")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return x.ReplaceBlocks(tx, &metastorev1.CompactedBlocks{
+			NewBlocks: []*metastorev1.BlockMeta{compacted},
+			SourceBlocks: &metastorev1.BlockList{
+				Shard:  l0.Shard,
+				Blocks: []string{l0.Id},
+			},
+		}, time.Now())
+	}))
+
+	// The time range is not narrowed until the partition
+	// includes no blocks of the tenant.
+	expected = []*metastorev1.TenantBlockStats{
+		{
+			TenantId:      "tenant-1",
+			BlockCount:    1,
+			BlockSize:     50,
+			BlocksByLevel: []uint64{0, 1},
+			MinTime:       l1.MinTime,
+			MaxTime:       l1.MaxTime,
+		},
+		{
+			TenantId:      "tenant-2",
+			BlockCount:    1,
+			BlockSize:     15,
+			BlocksByLevel: []uint64{0, 1},
+			MinTime:       l0.MinTime,
+			MaxTime:       compacted.MaxTime,
+		},
+	}
+	assert.Equal(t, expected, x.TenantBlockStats(nil))
+
+	// The stats are rebuilt from the database when the index is restored.
+	restored := index.NewIndex(util.Logger, index.NewStore(), c, nil)
+	require.NoError(t, db.Update(restored.Init))
+	require.NoError(t, db.View(restored.Restore))
+	stats := restored.TenantBlockStats(nil)
+	require.Len(t, stats, 2)
+	assert.Equal(t, uint64(1), stats[0].BlockCount)
+	assert.Equal(t, uint64(50), stats[0].BlockSize)
+	assert.Equal(t, []uint64{0, 1}, stats[0].BlocksByLevel)
+	assert.Equal(t, uint64(15), stats[1].BlockSize)
+	assert.Equal(t, []uint64{0, 1}, stats[1].BlocksByLevel)
+}
+
+func withDataset(b *metastorev1.BlockMeta, tenant string) *metastorev1.BlockMeta {
+	t := int64(ulid.MustParse(b.Id).Time())
+	b.MinTime = t
+	b.MaxTime = t + time.Minute.Milliseconds()
+	b.Datasets = append(b.Datasets, &metastorev1.Dataset{
+		TenantId: tenant,
+		Name:     "service",
+		MinTime:  b.MinTime,
+		MaxTime:  b.MaxTime,
+	})
+	return b
+}
+
+func TestIndex_CheckAndRepair(t *testing.T) {
+	db := test.BoltDB(t)
+	s := index.NewStore()
+	c := &index.Config{PartitionDuration: time.Hour, PartitionCacheSize: 7}
+	x := index.NewIndex(util.Logger, s, c, nil)
+	require.NoError(t, db.Update(x.Init))
+
+	block := func(ts, tenant string) *metastorev1.BlockMeta {
+		return withDataset(&metastorev1.BlockMeta{
+			Id:              test.ULID(ts),
+			Shard:           1,
+			TenantId:        tenant,
+			CompactionLevel: 1,
+		}, tenant)
+	}
+	b1 := block("2024-09-23T08:00:00.123Z", "tenant-1")
+	b2 := block("2024-09-23T09:00:00.123Z", "tenant-1")
+	b3 := block("2024-09-23T08:30:00.123Z", "tenant-2")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, err := range x.InsertBlocks(tx, []*metastorev1.BlockMeta{b1, b2, b3}) {
+			require.NoError(t, err)
+		}
+		return nil
+	}))
+
+	check := func() (resp *metastorev1.CheckIndexResponse) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			resp = x.Check(tx, nil)
+			return nil
+		}))
+		return resp
+	}
+	resp := check()
+	assert.Empty(t, resp.Issues)
+	assert.Equal(t, uint32(2), resp.Partitions)
+	assert.Equal(t, uint64(3), resp.Blocks)
+
+	// The entries are modified behind the index.
+	k08 := store.CreatePartitionKey(b1.Id, time.Hour)
+	k12 := store.CreatePartitionKey(test.ULID("2024-09-23T12:00:00.123Z"), time.Hour)
+	orphan := block("2024-09-23T08:40:00.123Z", "tenant-1")
+	unknown := block("2024-09-23T12:00:00.123Z", "tenant-1")
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		require.NoError(t, s.StoreBlock(tx, k08, orphan))
+		require.NoError(t, s.StoreBlock(tx, k08, b2))
+		require.NoError(t, s.StoreBlock(tx, k12, unknown))
+		return s.DeleteBlockList(tx, k08, &metastorev1.BlockList{Tenant: "tenant-2", Shard: 1, Blocks: []string{b3.Id}})
+	}))
+
+	type issue struct {
+		typ       metastorev1.IndexIssueType
+		partition store.PartitionKey
+		tenant    string
+		block     string
+	}
+	issues := func(resp *metastorev1.CheckIndexResponse) []issue {
+		var x []issue
+		for _, i := range resp.Issues {
+			x = append(x, issue{i.Type, store.PartitionKey(i.Partition), i.TenantId, i.BlockId})
+		}
+		return x
+	}
+	k09 := store.CreatePartitionKey(b2.Id, time.Hour)
+	expected := []issue{
+		{metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_ORPHANED_BLOCK, k08, "tenant-1", orphan.Id},
+		{metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_ORPHANED_BLOCK, k08, "tenant-1", b2.Id},
+		{metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_DUPLICATE_BLOCK, k08, "tenant-1", b2.Id},
+		{metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_INVALID_ENTRY, k08, "tenant-2", ""},
+		{metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_BLOCK_NOT_STORED, k08, "tenant-2", b3.Id},
+		{metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_DUPLICATE_BLOCK, k09, "tenant-1", b2.Id},
+		{metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_ORPHANED_PARTITION, k12, "", ""},
+	}
+	resp = check()
+	assert.Equal(t, expected, issues(resp))
+	assert.False(t, resp.Repaired)
+
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) (err error) {
+		resp, err = x.Repair(tx)
+		return err
+	}))
+	assert.Equal(t, expected, issues(resp))
+	assert.True(t, resp.Repaired)
+
+	resp = check()
+	assert.Empty(t, resp.Issues)
+	assert.Equal(t, uint32(3), resp.Partitions)
+	assert.Equal(t, uint64(4), resp.Blocks)
+
+	// The state in memory follows the database.
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		var ids []string
+		for _, b := range x.FindBlocksInRange(tx, 0, math.MaxInt64, map[string]struct{}{"tenant-1": {}, "tenant-2": {}}, nil, nil) {
+			ids = append(ids, b.Id)
+		}
+		slices.Sort(ids)
+		assert.Equal(t, []string{b1.Id, orphan.Id, b2.Id, unknown.Id}, ids)
+		for _, p := range x.ListPartitions(tx, nil, 0, 0) {
+			if p.Key == string(k08) {
+				assert.Equal(t, []string{"tenant-1"}, p.Tenants)
+			}
+		}
+		return nil
+	}))
+}
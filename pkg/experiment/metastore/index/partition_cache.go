@@ -0,0 +1,239 @@
+package index
+
+import (
+	"slices"
+	"time"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// The approximate memory footprint of the index entries, excluding the
+// block metadata: the partition, the shard, the block map entries, and the
+// dataset index entries of the block, including the structures they refer
+// to. The size of the block metadata is approximated with the size of the
+// serialized block metadata.
+const (
+	partitionEntrySize   = 128
+	shardEntrySize       = 96
+	blockEntryOverhead   = 64
+	datasetEntryOverhead = 48
+)
+
+func blockEntrySize(b *metastorev1.BlockMeta) uint64 {
+	if b == nil {
+		return 0
+	}
+	return blockEntryOverhead + uint64(len(b.Id)+b.SizeVT()+datasetEntryOverhead*len(b.Datasets))
+}
+
+func newIndexPartition(meta *PartitionMeta, accessedAt time.Time) *indexPartition {
+	return &indexPartition{
+		meta:       meta,
+		accessedAt: accessedAt,
+		shards:     make(map[uint32]*indexShard),
+		size:       partitionEntrySize,
+	}
+}
+
+// getOrCreateShard returns the partition shard, creating it if needed.
+func (i *Index) getOrCreateShard(p *indexPartition, shard uint32) *indexShard {
+	s, ok := p.shards[shard]
+	if !ok {
+		s = &indexShard{
+			blocks:   make(map[string]*metastorev1.BlockMeta),
+			datasets: make(map[string]map[string]*metastorev1.BlockMeta),
+		}
+		p.shards[shard] = s
+		i.resizePartition(p, shardEntrySize, 0)
+	}
+	return s
+}
+
+// cachePartition adds the partition to the partitions loaded in memory.
+func (i *Index) cachePartition(k cacheKey, p *indexPartition) {
+	i.uncachePartition(k)
+	i.loadedPartitions[k] = p
+	p.cached = true
+	i.loadedBytes += p.size
+	i.metrics.loadedPartitionsBytes.Set(float64(i.loadedBytes))
+}
+
+// cacheLoadedPartition caches the partition loaded from the store, unless
+// the partition has been cached concurrently: the cached one is returned.
+// The caller must hold cacheMu.
+func (i *Index) cacheLoadedPartition(k cacheKey, p *indexPartition) *indexPartition {
+	if cached, ok := i.loadedPartitions[k]; ok {
+		return cached
+	}
+	i.evictPartitions()
+	i.cachePartition(k, p)
+	return p
+}
+
+func (i *Index) isCached(k cacheKey) bool {
+	i.cacheMu.Lock()
+	defer i.cacheMu.Unlock()
+	_, ok := i.loadedPartitions[k]
+	return ok
+}
+
+// uncachePartition removes the partition from the partitions loaded in
+// memory. The partition is still valid, and can be used by the caller.
+func (i *Index) uncachePartition(k cacheKey) {
+	p, ok := i.loadedPartitions[k]
+	if !ok {
+		return
+	}
+	delete(i.loadedPartitions, k)
+	p.cached = false
+	i.loadedBytes -= p.size
+	i.metrics.loadedPartitionsBytes.Set(float64(i.loadedBytes))
+}
+
+func (i *Index) clearPartitions() {
+	for _, p := range i.loadedPartitions {
+		p.cached = false
+	}
+	clear(i.loadedPartitions)
+	i.loadedBytes = 0
+	i.metrics.loadedPartitionsBytes.Set(0)
+}
+
+// putBlock adds the block to the partition shard, replacing the entry with
+// the same identifier, if any. The partition may be nil, if the shard does
+// not belong to a partition loaded in memory.
+func (i *Index) putBlock(p *indexPartition, s *indexShard, b *metastorev1.BlockMeta) {
+	prev := s.blocks[b.Id]
+	s.blocks[b.Id] = b
+	s.unindexDatasets(prev)
+	s.indexDatasets(b)
+	if p != nil {
+		i.resizePartition(p, blockEntrySize(b), blockEntrySize(prev))
+	}
+}
+
+// deleteBlockEntry removes the block from the partition shard.
+// The partition may be nil, as in putBlock.
+func (i *Index) deleteBlockEntry(p *indexPartition, s *indexShard, blockId string) {
+	b, ok := s.blocks[blockId]
+	if !ok {
+		return
+	}
+	delete(s.blocks, blockId)
+	s.unindexDatasets(b)
+	if p != nil {
+		i.resizePartition(p, 0, blockEntrySize(b))
+	}
+}
+
+// indexDatasets adds the block to the dataset index of the shard.
+func (s *indexShard) indexDatasets(b *metastorev1.BlockMeta) {
+	for _, ds := range b.Datasets {
+		blocks, ok := s.datasets[ds.Name]
+		if !ok {
+			blocks = make(map[string]*metastorev1.BlockMeta)
+			s.datasets[ds.Name] = blocks
+		}
+		blocks[b.Id] = b
+	}
+}
+
+// unindexDatasets removes the block from the dataset index of the shard.
+func (s *indexShard) unindexDatasets(b *metastorev1.BlockMeta) {
+	if b == nil {
+		return
+	}
+	for _, ds := range b.Datasets {
+		blocks, ok := s.datasets[ds.Name]
+		if !ok {
+			continue
+		}
+		delete(blocks, b.Id)
+		if len(blocks) == 0 {
+			delete(s.datasets, ds.Name)
+		}
+	}
+}
+
+// forEachBlock calls the function for each block of the shard that includes
+// a dataset of any of the services. If no services are specified, the
+// function is called for all the blocks. Each block is visited once.
+func (s *indexShard) forEachBlock(services map[string]struct{}, fn func(*metastorev1.BlockMeta)) {
+	if len(services) == 0 {
+		for _, b := range s.blocks {
+			fn(b)
+		}
+		return
+	}
+	if len(services) == 1 {
+		for name := range services {
+			for _, b := range s.datasets[name] {
+				fn(b)
+			}
+		}
+		return
+	}
+	visited := make(map[string]struct{})
+	for name := range services {
+		for id, b := range s.datasets[name] {
+			if _, ok := visited[id]; ok {
+				continue
+			}
+			visited[id] = struct{}{}
+			fn(b)
+		}
+	}
+}
+
+func (i *Index) resizePartition(p *indexPartition, added, removed uint64) {
+	p.size = p.size + added - removed
+	if p.cached {
+		i.loadedBytes = i.loadedBytes + added - removed
+		i.metrics.loadedPartitionsBytes.Set(float64(i.loadedBytes))
+	}
+}
+
+// evictPartitions unloads the least recently accessed partitions until the
+// size of the partitions loaded in memory is below the configured limit.
+// The method is called before a partition is cached: the limit is exceeded
+// at most by the size of the partitions loaded at once. This bounds the
+// memory footprint of the index regardless of the query pattern, at the
+// cost of loading the evicted partitions from the store again.
+func (i *Index) evictPartitions() {
+	if i.cacheBytes == 0 || i.loadedBytes < i.cacheBytes {
+		return
+	}
+	keys := make([]cacheKey, 0, len(i.loadedPartitions))
+	for k := range i.loadedPartitions {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b cacheKey) int {
+		return i.loadedPartitions[a].accessedAt.Compare(i.loadedPartitions[b].accessedAt)
+	})
+	var evicted int
+	for _, k := range keys {
+		if i.loadedBytes < i.cacheBytes {
+			break
+		}
+		i.uncachePartition(k)
+		evicted++
+	}
+	i.metrics.evictedPartitions.Add(float64(evicted))
+}
+
+// ApplyRuntimeConfig changes the partition cache limits. If the limits
+// are decreased, the partitions exceeding them are unloaded immediately.
+func (i *Index) ApplyRuntimeConfig(c RuntimeConfig) {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	i.cacheSize = i.config.PartitionCacheSize
+	if c.PartitionCacheSize != nil {
+		i.cacheSize = *c.PartitionCacheSize
+	}
+	i.cacheBytes = i.config.PartitionCacheBytes
+	if c.PartitionCacheBytes != nil {
+		i.cacheBytes = *c.PartitionCacheBytes
+	}
+	i.unloadPartitions()
+	i.evictPartitions()
+}
@@ -0,0 +1,109 @@
+package index
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+// partitionList holds the metadata of all the index partitions, sorted by
+// the partition start time, and indexed by the partition key: the lookups
+// by key are constant time, and the partitions overlapping a time range are
+// found with a binary search, instead of scanning all the partitions.
+type partitionList struct {
+	sorted []*PartitionMeta
+	byKey  map[store.PartitionKey]*PartitionMeta
+	// The longest partition duration. Partitions of different durations
+	// may coexist: a partition overlapping a time range starts at most
+	// maxDuration before the range. The value is not decreased when the
+	// partitions are removed, which only makes the search less selective.
+	maxDuration time.Duration
+	// How far the data of the partitions extends before the partition
+	// start time and after the partition end time, in milliseconds.
+	// Like maxDuration, the values are never decreased.
+	dataBefore int64
+	dataAfter  int64
+}
+
+func newPartitionList() partitionList {
+	return partitionList{byKey: make(map[store.PartitionKey]*PartitionMeta)}
+}
+
+// set replaces the partitions of the list.
+func (l *partitionList) set(partitions []*PartitionMeta) {
+	l.sorted = slices.Clone(partitions)
+	l.byKey = make(map[store.PartitionKey]*PartitionMeta, len(partitions))
+	l.maxDuration = 0
+	l.dataBefore, l.dataAfter = 0, 0
+	for _, p := range partitions {
+		l.byKey[p.Key] = p
+		l.maxDuration = max(l.maxDuration, p.Duration)
+		l.extend(p)
+	}
+	slices.SortStableFunc(l.sorted, func(a, b *PartitionMeta) int {
+		return a.compare(b)
+	})
+}
+
+// add inserts the partition, keeping the list sorted.
+func (l *partitionList) add(p *PartitionMeta) {
+	n := sort.Search(len(l.sorted), func(i int) bool {
+		return l.sorted[i].Ts.After(p.Ts)
+	})
+	l.sorted = slices.Insert(l.sorted, n, p)
+	l.byKey[p.Key] = p
+	l.maxDuration = max(l.maxDuration, p.Duration)
+	l.extend(p)
+}
+
+// extend is to be called when the time range of the partition data changes.
+func (l *partitionList) extend(p *PartitionMeta) {
+	if p.MaxTime == 0 {
+		return
+	}
+	l.dataBefore = max(l.dataBefore, p.StartTime().UnixMilli()-p.MinTime)
+	l.dataAfter = max(l.dataAfter, p.MaxTime-p.EndTime().UnixMilli())
+}
+
+// remove deletes the partitions for which the function returns true.
+func (l *partitionList) remove(fn func(*PartitionMeta) bool) {
+	l.sorted = slices.DeleteFunc(l.sorted, func(p *PartitionMeta) bool {
+		if fn(p) {
+			delete(l.byKey, p.Key)
+			return true
+		}
+		return false
+	})
+}
+
+func (l *partitionList) get(key store.PartitionKey) *PartitionMeta {
+	return l.byKey[key]
+}
+
+func (l *partitionList) all() []*PartitionMeta {
+	return l.sorted
+}
+
+// candidates returns the partitions that may overlap the time range
+// [start, end]; the caller is expected to check the partitions with
+// PartitionMeta.overlaps or PartitionMeta.contains. The slice must not
+// be modified.
+func (l *partitionList) candidates(start, end int64) []*PartitionMeta {
+	from := start - l.maxDuration.Milliseconds()
+	lo := sort.Search(len(l.sorted), func(i int) bool {
+		return l.sorted[i].Ts.UnixMilli() > from
+	})
+	hi := lo + sort.Search(len(l.sorted)-lo, func(i int) bool {
+		return l.sorted[lo+i].Ts.UnixMilli() > end
+	})
+	return l.sorted[lo:hi]
+}
+
+// dataCandidates returns the partitions that may include data of the time
+// range [start, end]; the caller is expected to check the partitions with
+// PartitionMeta.mayIncludeData. The slice must not be modified.
+func (l *partitionList) dataCandidates(start, end int64) []*PartitionMeta {
+	return l.candidates(start-l.dataAfter, end+l.dataBefore)
+}
@@ -0,0 +1,72 @@
+package index
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+func TestPartitionList_candidates(t *testing.T) {
+	base := time.Date(2024, 9, 23, 0, 0, 0, 0, time.UTC)
+	rnd := rand.New(rand.NewSource(1))
+	durations := []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+	partitions := make([]*PartitionMeta, 0, 500)
+	for i := 0; i < 500; i++ {
+		d := durations[rnd.Intn(len(durations))]
+		ts := base.Add(time.Duration(rnd.Intn(24*60)) * time.Hour).Truncate(d)
+		partitions = append(partitions, &PartitionMeta{
+			Key:      store.PartitionKey(fmt.Sprintf("p%d-%s", i, ts.Format(time.RFC3339))),
+			Ts:       ts,
+			Duration: d,
+		})
+	}
+	l := newPartitionList()
+	l.set(partitions[:250])
+	for _, p := range partitions[250:] {
+		l.add(p)
+	}
+	require.Len(t, l.all(), len(partitions))
+	for i := 1; i < len(l.all()); i++ {
+		require.False(t, l.all()[i].Ts.Before(l.all()[i-1].Ts))
+	}
+
+	removed := make(map[*PartitionMeta]bool)
+	l.remove(func(p *PartitionMeta) bool {
+		if rnd.Intn(10) == 0 {
+			removed[p] = true
+		}
+		return removed[p]
+	})
+	for _, p := range partitions {
+		if removed[p] {
+			assert.Nil(t, l.get(p.Key))
+		} else {
+			assert.Equal(t, p, l.get(p.Key))
+		}
+	}
+
+	overlapping := func(partitions []*PartitionMeta, start, end int64) []*PartitionMeta {
+		var result []*PartitionMeta
+		for _, p := range partitions {
+			if !removed[p] && p.overlaps(start, end) {
+				result = append(result, p)
+			}
+		}
+		return result
+	}
+	for i := 0; i < 1000; i++ {
+		start := base.Add(time.Duration(rnd.Intn(26*60)-60) * time.Minute * 60).UnixMilli()
+		end := start + time.Duration(rnd.Intn(48)*int(time.Hour)).Milliseconds()
+		if i%2 == 0 {
+			end = start
+		}
+		assert.ElementsMatch(t, overlapping(partitions, start, end), overlapping(l.candidates(start, end), start, end))
+	}
+}
@@ -0,0 +1,161 @@
+package index
+
+import (
+	"slices"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+// PartitionsToMerge returns the partitions shorter than the configured
+// partition duration, grouped by the partition of the configured duration
+// they belong to. A group is only returned if it reduces the number of
+// partitions. Archived partitions are not merged. Partitions that include
+// blocks of tenants with shorter partition durations are only merged into
+// the partitions of the shortest of them.
+func (i *Index) PartitionsToMerge() []*raft_log.MergePartitionsRequest {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	groups := make(map[store.PartitionKey]*raft_log.MergePartitionsRequest)
+	merges := make([]*raft_log.MergePartitionsRequest, 0)
+	for _, meta := range i.partitions.all() {
+		if meta.archive != nil {
+			continue
+		}
+		target, ok := mergeTarget(meta, i.mergeDuration(meta))
+		if !ok {
+			continue
+		}
+		if t := i.findPartitionMeta(target); t != nil && t.archive != nil {
+			continue
+		}
+		g, ok := groups[target]
+		if !ok {
+			g = &raft_log.MergePartitionsRequest{Target: string(target)}
+			groups[target] = g
+			merges = append(merges, g)
+		}
+		g.Source = append(g.Source, string(meta.Key))
+	}
+	merges = slices.DeleteFunc(merges, func(m *raft_log.MergePartitionsRequest) bool {
+		return len(m.Source) < 2 && i.findPartitionMeta(store.PartitionKey(m.Target)) == nil
+	})
+	var n int
+	for _, m := range merges {
+		n += len(m.Source)
+	}
+	i.metrics.partitionsToMerge.Set(float64(n))
+	return merges
+}
+
+// mergeDuration returns the duration of the partition the partition can be
+// merged into: the shortest partition duration of the partition tenants.
+func (i *Index) mergeDuration(meta *PartitionMeta) time.Duration {
+	d := i.config.PartitionDuration
+	for j, t := range meta.Tenants {
+		if td := i.partitionDuration(t); j == 0 || td < d {
+			d = td
+		}
+	}
+	return d
+}
+
+// mergeTarget returns the key of the partition of the given duration
+// the partition can be merged into: the target partition must cover
+// the time period of the partition, and have the same key prefix.
+func mergeTarget(meta *PartitionMeta, d time.Duration) (store.PartitionKey, bool) {
+	if meta.Duration >= d {
+		return "", false
+	}
+	target, err := meta.Key.Resize(d)
+	if err != nil || target == meta.Key {
+		return "", false
+	}
+	ts, _, err := target.Parse()
+	if err != nil {
+		return "", false
+	}
+	if meta.Ts.Before(ts) || meta.EndTime().After(ts.Add(d)) {
+		return "", false
+	}
+	return target, true
+}
+
+// MergePartitions moves the blocks of the source partitions to the target
+// partition. The command is validated against the partition keys only, not
+// the configuration, which may differ between the replicas: the partitions
+// that can't be merged are skipped. The store is updated before the state
+// in memory: if the store fails, the state in memory is left intact.
+func (i *Index) MergePartitions(tx *bbolt.Tx, req *raft_log.MergePartitionsRequest) (*raft_log.MergePartitionsResponse, error) {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	if err := i.flushPendingBlocks(tx); err != nil {
+		return nil, err
+	}
+	resp := new(raft_log.MergePartitionsResponse)
+	target := store.PartitionKey(req.Target)
+	_, d, err := target.Parse()
+	if err != nil {
+		level.Warn(i.logger).Log("msg", "invalid merge target partition", "partition", target, "err", err)
+		return resp, nil
+	}
+	targetMeta := i.findPartitionMeta(target)
+	if targetMeta != nil && targetMeta.archive != nil {
+		return resp, nil
+	}
+
+	merged := make([]*PartitionMeta, 0, len(req.Source))
+	for _, s := range req.Source {
+		meta := i.findPartitionMeta(store.PartitionKey(s))
+		if meta == nil || meta.archive != nil || slices.Contains(merged, meta) {
+			continue
+		}
+		if k, ok := mergeTarget(meta, d); !ok || k != target {
+			continue
+		}
+		n, err := i.store.MovePartition(tx, meta.Key, target)
+		if err != nil {
+			return nil, err
+		}
+		resp.Merged = append(resp.Merged, s)
+		resp.Blocks += uint32(n)
+		merged = append(merged, meta)
+	}
+	if len(merged) == 0 {
+		return resp, nil
+	}
+
+	if targetMeta == nil {
+		targetMeta = i.getOrCreatePartitionMetaForCacheKey(cacheKey{partitionKey: target})
+	}
+	for _, meta := range merged {
+		for _, t := range meta.Tenants {
+			targetMeta.AddTenant(t)
+		}
+		targetMeta.BlockCount += meta.BlockCount
+		targetMeta.BlockSize += meta.BlockSize
+		targetMeta.extendTimeRange(meta.MinTime, meta.MaxTime)
+		i.partitions.extend(targetMeta)
+		if targetMeta.filter != nil && (meta.filter == nil || !targetMeta.filter.merge(meta.filter)) {
+			// The target might include any block.
+			targetMeta.filter = nil
+		}
+	}
+	i.partitions.remove(func(p *PartitionMeta) bool {
+		return slices.Contains(merged, p)
+	})
+	// The partitions are loaded from the store on demand.
+	for k := range i.loadedPartitions {
+		if k.partitionKey == target || slices.ContainsFunc(merged, func(p *PartitionMeta) bool {
+			return p.Key == k.partitionKey
+		}) {
+			i.uncachePartition(k)
+		}
+	}
+	i.metrics.mergedPartitions.Add(float64(len(merged)))
+	return resp, nil
+}
@@ -1,8 +1,12 @@
 package index
 
 import (
+	"slices"
+	"strings"
 	"time"
 
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
 )
 
@@ -11,12 +15,39 @@ type PartitionMeta struct {
 	Ts       time.Time
 	Duration time.Duration
 	Tenants  []string
+	// The number of blocks in the partition and their total size.
+	// Archived partitions only report the number of blocks.
+	BlockCount int
+	BlockSize  uint64
+	// Time range of the data of the partition blocks, in milliseconds:
+	// blocks may include data outside the partition time range, e.g.,
+	// late-arriving profiles. The range is not narrowed when blocks
+	// are removed. Zero if the range is not known.
+	MinTime int64
+	MaxTime int64
 
 	tenantMap map[string]struct{}
+	// Stats of the partition blocks by tenant. Tenants without
+	// blocks are removed. Guarded by the index partition mutex.
+	tenantStats map[string]*metastorev1.TenantBlockStats
+	// Set if the partition blocks have been moved to the object storage.
+	archive *raft_log.ArchivedPartition
+	// Set if the block filter is enabled, and the partition is not archived.
+	filter *blockFilter
 }
 
+// Archived reports whether the partition blocks have been moved
+// to the object storage: the partition is loaded on demand.
+func (m *PartitionMeta) Archived() bool {
+	return m.archive != nil
+}
+
+// HasTenant does not modify the partition metadata,
+// and is safe to call concurrently with other readers.
 func (m *PartitionMeta) HasTenant(tenant string) bool {
-	m.loadTenants()
+	if len(m.tenantMap) == 0 {
+		return slices.Contains(m.Tenants, tenant)
+	}
 	_, ok := m.tenantMap[tenant]
 	return ok
 }
@@ -46,6 +77,132 @@ func (m *PartitionMeta) AddTenant(tenant string) {
 	}
 }
 
+// Blocks marked as deleted are not included in the stats, but are
+// added to the block filter: they can still be found by identifier.
+func (m *PartitionMeta) addBlock(b *metastorev1.BlockMeta) {
+	if m.filter != nil {
+		m.filter.add(b.Id)
+	}
+	if b.DeletedAt != 0 {
+		return
+	}
+	m.BlockCount++
+	m.BlockSize += b.Size
+	m.extendTimeRange(b.MinTime, b.MaxTime)
+	for _, d := range blockTenantData(b) {
+		s, ok := m.tenantStats[d.tenant]
+		if !ok {
+			if m.tenantStats == nil {
+				m.tenantStats = make(map[string]*metastorev1.TenantBlockStats)
+			}
+			s = &metastorev1.TenantBlockStats{TenantId: d.tenant}
+			m.tenantStats[d.tenant] = s
+		}
+		s.BlockCount++
+		s.BlockSize += d.size
+		if n := int(b.CompactionLevel) + 1; len(s.BlocksByLevel) < n {
+			s.BlocksByLevel = append(s.BlocksByLevel, make([]uint64, n-len(s.BlocksByLevel))...)
+		}
+		s.BlocksByLevel[b.CompactionLevel]++
+		extendTenantTimeRange(s, d.minTime, d.maxTime)
+	}
+}
+
+func (m *PartitionMeta) extendTimeRange(minTime, maxTime int64) {
+	if maxTime == 0 {
+		return
+	}
+	if m.MaxTime == 0 {
+		m.MinTime, m.MaxTime = minTime, maxTime
+		return
+	}
+	m.MinTime = min(m.MinTime, minTime)
+	m.MaxTime = max(m.MaxTime, maxTime)
+}
+
+func (m *PartitionMeta) removeBlock(b *metastorev1.BlockMeta) {
+	if b.DeletedAt != 0 {
+		return
+	}
+	if m.BlockCount > 0 {
+		m.BlockCount--
+	}
+	m.BlockSize -= min(m.BlockSize, b.Size)
+	for _, d := range blockTenantData(b) {
+		s, ok := m.tenantStats[d.tenant]
+		if !ok {
+			continue
+		}
+		s.BlockCount -= min(s.BlockCount, 1)
+		s.BlockSize -= min(s.BlockSize, d.size)
+		if int(b.CompactionLevel) < len(s.BlocksByLevel) && s.BlocksByLevel[b.CompactionLevel] > 0 {
+			s.BlocksByLevel[b.CompactionLevel]--
+		}
+		if s.BlockCount == 0 {
+			delete(m.tenantStats, d.tenant)
+		}
+	}
+}
+
+// tenantData is the share of the block data of a tenant.
+type tenantData struct {
+	tenant  string
+	size    uint64
+	minTime int64
+	maxTime int64
+}
+
+// blockTenantData returns the size and the time range of the block data by
+// tenant. Blocks that include data of multiple tenants are split by dataset.
+func blockTenantData(b *metastorev1.BlockMeta) []tenantData {
+	if b.TenantId != "" || len(b.Datasets) == 0 {
+		return []tenantData{{tenant: b.TenantId, size: b.Size, minTime: b.MinTime, maxTime: b.MaxTime}}
+	}
+	data := make([]tenantData, 0, 1)
+	for _, ds := range b.Datasets {
+		k := slices.IndexFunc(data, func(d tenantData) bool { return d.tenant == ds.TenantId })
+		if k < 0 {
+			k = len(data)
+			data = append(data, tenantData{tenant: ds.TenantId, minTime: ds.MinTime, maxTime: ds.MaxTime})
+		}
+		data[k].size += ds.Size
+		data[k].minTime = min(data[k].minTime, ds.MinTime)
+		data[k].maxTime = max(data[k].maxTime, ds.MaxTime)
+	}
+	return data
+}
+
+func extendTenantTimeRange(s *metastorev1.TenantBlockStats, minTime, maxTime int64) {
+	if maxTime == 0 {
+		return
+	}
+	if s.MaxTime == 0 {
+		s.MinTime, s.MaxTime = minTime, maxTime
+		return
+	}
+	s.MinTime = min(s.MinTime, minTime)
+	s.MaxTime = max(s.MaxTime, maxTime)
+}
+
+// TenantStats returns the stats of the partition blocks by tenant,
+// ordered by tenant. The stats are shared and must not be modified.
+func (m *PartitionMeta) TenantStats() []*metastorev1.TenantBlockStats {
+	stats := make([]*metastorev1.TenantBlockStats, 0, len(m.tenantStats))
+	for _, s := range m.tenantStats {
+		stats = append(stats, s)
+	}
+	slices.SortFunc(stats, func(a, b *metastorev1.TenantBlockStats) int {
+		return strings.Compare(a.TenantId, b.TenantId)
+	})
+	return stats
+}
+
+// mayContainBlock reports whether the partition might include the block.
+// Partitions without a block filter might include any block.
+func (m *PartitionMeta) mayContainBlock(id string) bool {
+	return m.filter == nil || m.filter.mayContain(id)
+}
+
 func (m *PartitionMeta) compare(other *PartitionMeta) int {
 	if m == other {
 		return 0
@@ -53,6 +210,17 @@ func (m *PartitionMeta) compare(other *PartitionMeta) int {
 	return m.Ts.Compare(other.Ts)
 }
 
+// mayIncludeData reports whether the partition blocks might include data
+// of the time range [start, end]. If the time range of the partition data
+// is not known, it is assumed to extend beyond the partition time range
+// by the lookaround period, in milliseconds.
+func (m *PartitionMeta) mayIncludeData(start, end, lookaround int64) bool {
+	if m.MaxTime == 0 {
+		return m.overlaps(start-lookaround, end+lookaround)
+	}
+	return start < m.MaxTime && end >= m.MinTime
+}
+
 // [ m.StartTime(), m.EndTime() )
 func (m *PartitionMeta) overlaps(start, end int64) bool {
 	return start < m.EndTime().UnixMilli() && end >= m.StartTime().UnixMilli()
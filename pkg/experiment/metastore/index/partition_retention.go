@@ -0,0 +1,216 @@
+package index
+
+import (
+	"slices"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+// PartitionsToDelete returns the requests to delete the blocks of the
+// tenants whose retention period has passed. The blocks that include data
+// of multiple tenants are only deleted once the retention period of all
+// the tenants has passed: if any of the tenants has no retention period,
+// the blocks are kept indefinitely.
+func (i *Index) PartitionsToDelete(tx *bbolt.Tx, now time.Time) []*raft_log.DeletePartitionsRequest {
+	if i.config.Limits == nil {
+		return nil
+	}
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	retention := make(map[string]time.Duration)
+	tenants := make([]string, 0)
+	for _, meta := range i.partitions.all() {
+		for _, t := range meta.Tenants {
+			if _, ok := retention[t]; !ok && t != "" {
+				retention[t] = i.config.Limits.MetastoreIndexRetentionPeriod(t)
+				tenants = append(tenants, t)
+			}
+		}
+	}
+	slices.Sort(tenants)
+
+	requests := make([]*raft_log.DeletePartitionsRequest, 0)
+	var shared time.Duration
+	for _, t := range tenants {
+		r := retention[t]
+		if r <= 0 {
+			shared = -1
+			continue
+		}
+		if shared >= 0 {
+			shared = max(shared, r)
+		}
+		if before := now.Add(-r); i.hasExpiredBlocks(tx, before, t) {
+			requests = append(requests, &raft_log.DeletePartitionsRequest{TenantId: t, Before: before.UnixMilli()})
+		}
+	}
+	if shared > 0 {
+		if before := now.Add(-shared); i.hasExpiredBlocks(tx, before, "") {
+			requests = append(requests, &raft_log.DeletePartitionsRequest{Before: before.UnixMilli()})
+		}
+	}
+	return requests
+}
+
+// hasExpiredBlocks reports whether any of the expired partitions includes
+// blocks of the tenant: the partition tenants also include the tenants of
+// the blocks that include data of multiple tenants. Archived partitions
+// are only deleted as a whole, with the blocks of the empty tenant.
+func (i *Index) hasExpiredBlocks(tx *bbolt.Tx, before time.Time, tenant string) bool {
+	for _, meta := range i.expiredPartitions(before, tenant) {
+		if meta.archive != nil {
+			if tenant == "" {
+				return true
+			}
+			continue
+		}
+		for _, s := range i.store.ListShards(tx, meta.Key) {
+			if slices.Contains(i.store.ListTenants(tx, meta.Key, s), tenant) {
+				return true
+			}
+		}
+		for _, p := range i.pending {
+			if p.key == meta.Key && p.block.TenantId == tenant {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expiredPartitions returns the partitions that ended before the given
+// time, and the blocks of which do not include data after that time.
+func (i *Index) expiredPartitions(before time.Time, tenant string) []*PartitionMeta {
+	t := before.UnixMilli()
+	expired := make([]*PartitionMeta, 0)
+	for _, meta := range i.partitions.all() {
+		if !meta.Ts.Before(before) {
+			break
+		}
+		if meta.EndTime().After(before) || meta.MaxTime > t {
+			continue
+		}
+		if tenant == "" || meta.HasTenant(tenant) {
+			expired = append(expired, meta)
+		}
+	}
+	return expired
+}
+
+// DeletedPartitions describes the changes made by DeletePartitionsBefore.
+type DeletedPartitions struct {
+	// Partitions left without blocks, and the archived partitions deleted.
+	Partitions []store.PartitionKey
+	// Blocks removed, except for those already marked as deleted.
+	Blocks []*metastorev1.BlockMeta
+	// Archived partitions deleted: their blocks are listed in the archives.
+	Archives []*raft_log.ArchivedPartition
+	// Symbols dictionaries no longer referenced by the blocks.
+	Dictionaries []ReleasedDictionary
+}
+
+// DeletePartitionsBefore removes the blocks of the tenant from the partitions
+// that ended before the given time, provided that the partition blocks do
+// not include data after that time. The empty tenant refers to the blocks
+// that include data of multiple tenants. The partitions left without blocks
+// are deleted.
+//
+// Archived partitions can not be modified: they are deleted as a whole with
+// the blocks of the empty tenant, which are only deleted once the retention
+// period of all the tenants has passed.
+//
+// The caller is responsible for deleting the objects of the removed blocks,
+// of the archived partitions, and of the symbols dictionaries released at
+// the given time.
+func (i *Index) DeletePartitionsBefore(tx *bbolt.Tx, before time.Time, tenant string, deletedAt time.Time) (*DeletedPartitions, error) {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	if err := i.flushPendingBlocks(tx); err != nil {
+		return nil, err
+	}
+	d := new(DeletedPartitions)
+	for _, meta := range i.expiredPartitions(before, tenant) {
+		if meta.archive != nil {
+			if tenant != "" {
+				continue
+			}
+			if err := i.deleteArchivedPartition(tx, meta, deletedAt, d); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		blocks, empty, err := i.store.DeleteTenantBlocks(tx, meta.Key, tenant)
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) == 0 && !empty {
+			continue
+		}
+		for _, b := range blocks {
+			if b.DeletedAt == 0 {
+				d.Blocks = append(d.Blocks, b)
+			}
+			// The blocks marked as deleted are not swept:
+			// their references are released here.
+			r, err := i.releaseDictionaryRefs(tx, b, deletedAt.UnixMilli())
+			if err != nil {
+				return nil, err
+			}
+			d.Dictionaries = append(d.Dictionaries, r...)
+		}
+		i.partitions.remove(func(p *PartitionMeta) bool {
+			return p == meta
+		})
+		if empty {
+			d.Partitions = append(d.Partitions, meta.Key)
+		} else {
+			i.partitions.add(i.loadPartitionMeta(tx, meta.Key))
+		}
+		i.uncacheAllTenants(meta.Key)
+	}
+	i.changes.append(blocksDeletedChanges(d.Blocks)...)
+	i.metrics.deletedPartitions.Add(float64(len(d.Partitions)))
+	i.metrics.expiredBlocks.Add(float64(len(d.Blocks)))
+	return d, nil
+}
+
+func (i *Index) deleteArchivedPartition(tx *bbolt.Tx, meta *PartitionMeta, deletedAt time.Time, d *DeletedPartitions) error {
+	if err := i.store.DeleteArchivedPartition(tx, meta.Key); err != nil {
+		return err
+	}
+	for _, ref := range meta.archive.Dictionaries {
+		paths := make([]string, ref.Blocks)
+		for j := range paths {
+			paths[j] = ref.Path
+		}
+		released, err := i.store.ReleaseDictionaryRefs(tx, paths, deletedAt.UnixMilli())
+		if err != nil {
+			return err
+		}
+		for _, p := range released {
+			d.Dictionaries = append(d.Dictionaries, ReleasedDictionary{Shard: ref.Shard, Tenant: ref.Tenant, Path: p})
+		}
+	}
+	i.partitions.remove(func(p *PartitionMeta) bool {
+		return p == meta
+	})
+	i.uncacheAllTenants(meta.Key)
+	d.Partitions = append(d.Partitions, meta.Key)
+	d.Archives = append(d.Archives, meta.archive)
+	return nil
+}
+
+// uncacheAllTenants removes the partition from the cache:
+// the partitions are loaded from the store on demand.
+func (i *Index) uncacheAllTenants(key store.PartitionKey) {
+	for k := range i.loadedPartitions {
+		if k.partitionKey == key {
+			i.uncachePartition(k)
+		}
+	}
+}
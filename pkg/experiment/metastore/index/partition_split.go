@@ -0,0 +1,130 @@
+package index
+
+import (
+	"slices"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+// PartitionsToSplit returns the partitions longer than the partition
+// duration of their tenants, e.g., created before the duration was
+// decreased. Archived partitions are not split. Partitions that include
+// blocks of tenants with different partition durations are only split
+// into partitions of the longest of them, which are not merged again.
+func (i *Index) PartitionsToSplit() []*raft_log.SplitPartitionRequest {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	splits := make([]*raft_log.SplitPartitionRequest, 0)
+	for _, meta := range i.partitions.all() {
+		if meta.archive != nil {
+			continue
+		}
+		d := i.splitDuration(meta)
+		targets, ok := splitTargets(meta, d)
+		if !ok || slices.ContainsFunc(targets, i.archived) {
+			continue
+		}
+		splits = append(splits, &raft_log.SplitPartitionRequest{
+			Source:   string(meta.Key),
+			Duration: d.Milliseconds(),
+		})
+	}
+	i.metrics.partitionsToSplit.Set(float64(len(splits)))
+	return splits
+}
+
+// splitDuration returns the duration of the partitions the partition can be
+// split into: the longest partition duration of the partition tenants.
+func (i *Index) splitDuration(meta *PartitionMeta) time.Duration {
+	d := i.config.PartitionDuration
+	for j, t := range meta.Tenants {
+		if td := i.partitionDuration(t); j == 0 || td > d {
+			d = td
+		}
+	}
+	return d
+}
+
+func (i *Index) archived(k store.PartitionKey) bool {
+	meta := i.findPartitionMeta(k)
+	return meta != nil && meta.archive != nil
+}
+
+// splitTargets returns the keys of the partitions of the given duration the
+// partition can be split into: the target partitions must cover the time
+// period of the partition exactly, and have the same key prefix.
+func splitTargets(meta *PartitionMeta, d time.Duration) ([]store.PartitionKey, bool) {
+	if d <= 0 || d%time.Hour != 0 || meta.Duration <= d {
+		return nil, false
+	}
+	var targets []store.PartitionKey
+	for ts := meta.Ts; ts.Before(meta.EndTime()); ts = ts.Add(d) {
+		target, err := meta.Key.ResizeAt(ts, d)
+		if err != nil {
+			return nil, false
+		}
+		if t, _, err := target.Parse(); err != nil || !t.Equal(ts) {
+			return nil, false
+		}
+		targets = append(targets, target)
+	}
+	if !meta.Ts.Add(time.Duration(len(targets)) * d).Equal(meta.EndTime()) {
+		return nil, false
+	}
+	return targets, true
+}
+
+// SplitPartition moves the blocks of the source partition to the partitions
+// of the requested duration. Like MergePartitions, the command is validated
+// against the partition keys only: if the partition can't be split, it is
+// left intact. The metadata of the target partitions is reloaded from the
+// store, as the blocks are distributed by the time of their identifiers.
+func (i *Index) SplitPartition(tx *bbolt.Tx, req *raft_log.SplitPartitionRequest) (*raft_log.SplitPartitionResponse, error) {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	if err := i.flushPendingBlocks(tx); err != nil {
+		return nil, err
+	}
+	resp := new(raft_log.SplitPartitionResponse)
+	meta := i.findPartitionMeta(store.PartitionKey(req.Source))
+	if meta == nil || meta.archive != nil {
+		return resp, nil
+	}
+	d := time.Duration(req.Duration) * time.Millisecond
+	targets, ok := splitTargets(meta, d)
+	if !ok {
+		level.Warn(i.logger).Log("msg", "invalid partition split", "partition", req.Source, "duration", d)
+		return resp, nil
+	}
+	if slices.ContainsFunc(targets, i.archived) {
+		return resp, nil
+	}
+	written, n, err := i.store.SplitPartition(tx, meta.Key, d)
+	if err != nil {
+		return nil, err
+	}
+	resp.Blocks = uint32(n)
+	for _, k := range written {
+		resp.Target = append(resp.Target, string(k))
+	}
+
+	i.partitions.remove(func(p *PartitionMeta) bool {
+		return p == meta || slices.Contains(written, p.Key)
+	})
+	for _, k := range written {
+		i.partitions.add(i.loadPartitionMeta(tx, k))
+	}
+	// The partitions are loaded from the store on demand.
+	for k := range i.loadedPartitions {
+		if k.partitionKey == meta.Key || slices.Contains(written, k.partitionKey) {
+			i.uncachePartition(k)
+		}
+	}
+	i.metrics.splitPartitions.Inc()
+	return resp, nil
+}
@@ -0,0 +1,201 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// Compression of the block metadata entries.
+const (
+	BlockCompressionNone   = "none"
+	BlockCompressionSnappy = "snappy"
+	BlockCompressionZstd   = "zstd"
+)
+
+// BlockCompressions lists the supported block metadata compressions.
+var BlockCompressions = []string{
+	BlockCompressionNone,
+	BlockCompressionSnappy,
+	BlockCompressionZstd,
+}
+
+const blockCompressionKey = "block_compression"
+
+var blockCompressionKeyBytes = []byte(blockCompressionKey)
+
+// Compressed block metadata entries are prefixed with a header: the marker
+// byte, which is never the first byte of serialized block metadata, as zero
+// is not a valid protobuf field tag, and the codec. Entries without the
+// header are not compressed, which includes all the entries stored before
+// the compression was introduced: these are read as is.
+const (
+	blockHeaderMarker byte = 0
+	blockHeaderSize        = 2
+
+	blockCodecSnappy byte = 1
+	blockCodecZstd   byte = 2
+)
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// ValidateBlockCompression reports whether the compression is supported.
+func ValidateBlockCompression(compression string) error {
+	switch compression {
+	case BlockCompressionNone, BlockCompressionSnappy, BlockCompressionZstd:
+		return nil
+	}
+	return fmt.Errorf("unsupported block compression %q, supported: %v", compression, BlockCompressions)
+}
+
+func blockCodec(compression string) (byte, bool) {
+	switch compression {
+	case BlockCompressionSnappy:
+		return blockCodecSnappy, true
+	case BlockCompressionZstd:
+		return blockCodecZstd, true
+	}
+	return 0, false
+}
+
+// entryCodec returns the codec the block entry is compressed with,
+// and false if the entry is not compressed.
+func entryCodec(v []byte) (byte, bool) {
+	if len(v) < blockHeaderSize || v[0] != blockHeaderMarker {
+		return 0, false
+	}
+	return v[1], true
+}
+
+func encodeBlock(b *metastorev1.BlockMeta, compression string) ([]byte, error) {
+	value, err := b.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	return compressBlockEntry(value, compression), nil
+}
+
+// decodeBlock decodes the block metadata entry. The strings repeated
+// across the blocks are interned, see InternBlockStrings.
+func decodeBlock(v []byte, md *metastorev1.BlockMeta) error {
+	raw, err := decompressBlockEntry(v)
+	if err != nil {
+		return err
+	}
+	if err = md.UnmarshalVT(raw); err != nil {
+		return err
+	}
+	InternBlockStrings(md)
+	return nil
+}
+
+func compressBlockEntry(raw []byte, compression string) []byte {
+	codec, ok := blockCodec(compression)
+	if !ok {
+		return raw
+	}
+	header := []byte{blockHeaderMarker, codec}
+	switch codec {
+	case blockCodecSnappy:
+		return append(header, snappy.Encode(nil, raw)...)
+	default:
+		return zstdEncoder.EncodeAll(raw, header)
+	}
+}
+
+func decompressBlockEntry(v []byte) ([]byte, error) {
+	codec, ok := entryCodec(v)
+	if !ok {
+		return v, nil
+	}
+	switch codec {
+	case blockCodecSnappy:
+		return snappy.Decode(nil, v[blockHeaderSize:])
+	case blockCodecZstd:
+		return zstdDecoder.DecodeAll(v[blockHeaderSize:], nil)
+	}
+	return nil, fmt.Errorf("unknown block codec: %d", codec)
+}
+
+// RecompressBlocks re-encodes the block metadata entries stored with a
+// compression other than the configured one. The compression of the entries
+// is recorded in the store, therefore the entries are only visited after the
+// configuration has changed. The number of entries re-encoded is returned.
+//
+// The entries are readable regardless of their compression: the migration
+// only affects the size of the store.
+func (m *IndexStore) RecompressBlocks(tx *bbolt.Tx) (int, error) {
+	compression := m.blockCompression()
+	meta := tx.Bucket(indexMetaBucketNameBytes)
+	recorded := string(meta.Get(blockCompressionKeyBytes))
+	if recorded == "" {
+		recorded = BlockCompressionNone
+	}
+	if recorded == compression {
+		return 0, nil
+	}
+	codec, compressed := blockCodec(compression)
+	var n int
+	err := getPartitionBucket(tx).ForEachBucket(func(name []byte) error {
+		partition := getPartitionBucket(tx).Bucket(name)
+		return partition.ForEachBucket(func(shardName []byte) error {
+			shard := partition.Bucket(shardName)
+			return shard.ForEachBucket(func(tenantName []byte) error {
+				c, err := recompressBucket(shard.Bucket(tenantName), compression, codec, compressed)
+				n += c
+				return err
+			})
+		})
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, meta.Put(blockCompressionKeyBytes, []byte(compression))
+}
+
+func recompressBucket(bucket *bbolt.Bucket, compression string, codec byte, compressed bool) (int, error) {
+	// The bucket can't be modified while it is iterated.
+	type entry struct{ key, value []byte }
+	var entries []entry
+	err := bucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+		if c, ok := entryCodec(v); ok == compressed && c == codec {
+			return nil
+		}
+		raw, err := decompressBlockEntry(v)
+		if err != nil {
+			return fmt.Errorf("failed to decompress block %q: %w", string(k), err)
+		}
+		entries = append(entries, entry{
+			key:   bytes.Clone(k),
+			value: compressBlockEntry(raw, compression),
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		if err = bucket.Put(e.key, e.value); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+func (m *IndexStore) blockCompression() string {
+	if m.BlockCompression == "" {
+		return BlockCompressionNone
+	}
+	return m.BlockCompression
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestIndexStore_BlockCompression(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+	require.NoError(t, db.Update(s.CreateBuckets))
+
+	const key = PartitionKey("20240715.1d")
+	blocks := []*metastorev1.BlockMeta{
+		{Id: test.ULID("2024-07-15T15:00:00.000Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-07-15T16:00:00.000Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-07-15T17:00:00.000Z"), Shard: 1, TenantId: "tenant-1"},
+	}
+	for _, b := range blocks {
+		b.Datasets = []*metastorev1.Dataset{{TenantId: b.TenantId, Name: "service", Size: 100}}
+	}
+	store := func(b *metastorev1.BlockMeta) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+			return s.StoreBlock(tx, key, b)
+		}))
+	}
+	recompress := func() (n int) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) (err error) {
+			n, err = s.RecompressBlocks(tx)
+			return err
+		}))
+		return n
+	}
+	codecs := func() (codecs []byte) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			bucket := getShardBucket(getPartitionBucket(tx), key, 1).Bucket(tenantBucketName("tenant-1"))
+			return bucket.ForEach(func(_, v []byte) error {
+				c, _ := entryCodec(v)
+				codecs = append(codecs, c)
+				return nil
+			})
+		}))
+		return codecs
+	}
+	assertBlocks := func() {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			stored := s.ListBlocks(tx, key, 1, "tenant-1")
+			require.Len(t, stored, len(blocks))
+			for i := range blocks {
+				assert.True(t, blocks[i].EqualVT(stored[i]))
+			}
+			return nil
+		}))
+	}
+
+	// Entries of different compressions are read transparently.
+	store(blocks[0])
+	s.BlockCompression = BlockCompressionSnappy
+	store(blocks[1])
+	s.BlockCompression = BlockCompressionZstd
+	store(blocks[2])
+	assert.Equal(t, []byte{0, blockCodecSnappy, blockCodecZstd}, codecs())
+	assertBlocks()
+
+	// Entries of other compressions are re-encoded once.
+	assert.Equal(t, 2, recompress())
+	assert.Equal(t, []byte{blockCodecZstd, blockCodecZstd, blockCodecZstd}, codecs())
+	assertBlocks()
+	assert.Zero(t, recompress())
+
+	s.BlockCompression = BlockCompressionNone
+	assert.Equal(t, 3, recompress())
+	assert.Equal(t, []byte{0, 0, 0}, codecs())
+	assertBlocks()
+	assert.Zero(t, recompress())
+}
+
+func TestValidateBlockCompression(t *testing.T) {
+	for _, c := range BlockCompressions {
+		assert.NoError(t, ValidateBlockCompression(c))
+	}
+	assert.Error(t, ValidateBlockCompression("lz4"))
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/iter"
+)
+
+// IterateBlocks returns an iterator over the blocks of the partition shard
+// and tenant, ordered by block identifier, starting after the given block
+// identifier, or from the first block, if it is empty. Unlike ListBlocks,
+// the block metadata is decoded on demand, one block at a time. The
+// iterator is only valid within the transaction.
+func (m *IndexStore) IterateBlocks(tx *bbolt.Tx, key PartitionKey, shard uint32, tenant string, after string) iter.Iterator[*metastorev1.BlockMeta] {
+	bucket := getShardBucket(getPartitionBucket(tx), key, shard)
+	if bucket != nil {
+		bucket = bucket.Bucket(tenantBucketName(tenant))
+	}
+	if bucket == nil {
+		return iter.NewEmptyIterator[*metastorev1.BlockMeta]()
+	}
+	return &blockIterator{cursor: bucket.Cursor(), after: []byte(after)}
+}
+
+type blockIterator struct {
+	cursor *bbolt.Cursor
+	after  []byte
+	seek   bool
+	cur    *metastorev1.BlockMeta
+	err    error
+}
+
+func (x *blockIterator) Next() bool {
+	if x.err != nil {
+		return false
+	}
+	var k, v []byte
+	if !x.seek {
+		x.seek = true
+		k, v = x.cursor.Seek(x.after)
+		if k != nil && len(x.after) > 0 && bytes.Equal(k, x.after) {
+			k, v = x.cursor.Next()
+		}
+	} else {
+		k, v = x.cursor.Next()
+	}
+	// Nested buckets are not expected but are harmless.
+	for k != nil && v == nil {
+		k, v = x.cursor.Next()
+	}
+	if k == nil {
+		return false
+	}
+	var md metastorev1.BlockMeta
+	if err := decodeBlock(v, &md); err != nil {
+		x.err = fmt.Errorf("failed to unmarshal block %q: %w", string(k), err)
+		return false
+	}
+	x.cur = &md
+	return true
+}
+
+func (x *blockIterator) At() *metastorev1.BlockMeta { return x.cur }
+
+func (x *blockIterator) Err() error { return x.err }
+
+func (x *blockIterator) Close() error { return nil }
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestIndexStore_IterateBlocks(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+	require.NoError(t, db.Update(s.CreateBuckets))
+
+	const key = PartitionKey("20240715.1d")
+	blocks := []*metastorev1.BlockMeta{
+		{Id: test.ULID("2024-07-15T16:00:00.000Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-07-15T17:00:00.000Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-07-15T15:00:00.000Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-07-15T15:00:00.000Z"), Shard: 2, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-07-15T18:00:00.000Z"), Shard: 1},
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, b := range blocks {
+			if err := s.StoreBlock(tx, key, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	list := func(shard uint32, tenant, after string) (ids []string) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			it := s.IterateBlocks(tx, key, shard, tenant, after)
+			defer func() {
+				require.NoError(t, it.Close())
+			}()
+			for it.Next() {
+				ids = append(ids, it.At().Id)
+			}
+			return it.Err()
+		}))
+		return ids
+	}
+
+	// Blocks are listed in the order of identifiers.
+	assert.Equal(t, []string{blocks[2].Id, blocks[0].Id, blocks[1].Id}, list(1, "tenant-1", ""))
+	assert.Equal(t, []string{blocks[0].Id, blocks[1].Id}, list(1, "tenant-1", blocks[2].Id))
+	assert.Equal(t, []string{blocks[1].Id}, list(1, "tenant-1", test.ULID("2024-07-15T16:30:00.000Z")))
+	assert.Empty(t, list(1, "tenant-1", blocks[1].Id))
+	assert.Equal(t, []string{blocks[3].Id}, list(2, "tenant-1", ""))
+	assert.Equal(t, []string{blocks[4].Id}, list(1, "", ""))
+	assert.Empty(t, list(3, "tenant-1", ""))
+	assert.Empty(t, list(1, "tenant-2", ""))
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"go.etcd.io/bbolt"
+)
+
+// DeletedBlock is the tombstone of a block deleted from the index: the
+// block metadata is kept in the partition until the tombstone is swept.
+//
+// Tombstones are ordered by the deletion time. The key is the deletion
+// time (8 bytes, big-endian) followed by the block identifier; the value
+// is the shard (4 bytes, big-endian) followed by the tenant.
+type DeletedBlock struct {
+	DeletedAt int64
+	Block     string
+	Shard     uint32
+	Tenant    string
+}
+
+var errInvalidDeletedBlock = errors.New("invalid deleted block entry")
+
+func (d DeletedBlock) key() []byte {
+	k := make([]byte, 8+len(d.Block))
+	binary.BigEndian.PutUint64(k, uint64(d.DeletedAt))
+	copy(k[8:], d.Block)
+	return k
+}
+
+func (d DeletedBlock) value() []byte {
+	v := make([]byte, 4+len(d.Tenant))
+	binary.BigEndian.PutUint32(v, d.Shard)
+	copy(v[4:], d.Tenant)
+	return v
+}
+
+func (d *DeletedBlock) unmarshal(k, v []byte) error {
+	if len(k) < 8 || len(v) < 4 {
+		return errInvalidDeletedBlock
+	}
+	d.DeletedAt = int64(binary.BigEndian.Uint64(k))
+	d.Block = string(k[8:])
+	d.Shard = binary.BigEndian.Uint32(v)
+	d.Tenant = string(v[4:])
+	return nil
+}
+
+func (m *IndexStore) StoreDeletedBlocks(tx *bbolt.Tx, deleted []DeletedBlock) error {
+	bucket := tx.Bucket(deletedBlockBucketNameBytes)
+	if bucket == nil {
+		return bbolt.ErrBucketNotFound
+	}
+	for _, d := range deleted {
+		if err := bucket.Put(d.key(), d.value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *IndexStore) DeleteDeletedBlocks(tx *bbolt.Tx, deleted []DeletedBlock) error {
+	bucket := tx.Bucket(deletedBlockBucketNameBytes)
+	if bucket == nil {
+		return bbolt.ErrBucketNotFound
+	}
+	for _, d := range deleted {
+		if err := bucket.Delete(d.key()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListDeletedBlocks returns the tombstones of the blocks deleted before
+// the given time (inclusive), in the order of deletion. If the limit is
+// positive, at most limit tombstones are returned.
+func (m *IndexStore) ListDeletedBlocks(tx *bbolt.Tx, before int64, limit int) ([]DeletedBlock, error) {
+	bucket := tx.Bucket(deletedBlockBucketNameBytes)
+	if bucket == nil {
+		return nil, nil
+	}
+	deleted := make([]DeletedBlock, 0)
+	c := bucket.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		var d DeletedBlock
+		if err := d.unmarshal(k, v); err != nil {
+			return nil, err
+		}
+		if d.DeletedAt > before || (limit > 0 && len(deleted) == limit) {
+			break
+		}
+		deleted = append(deleted, d)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestIndexStore_DeletedBlocks(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+	require.NoError(t, db.Update(s.CreateBuckets))
+
+	blocks := []DeletedBlock{
+		{DeletedAt: 2, Block: test.ULID("2024-07-15T16:00:00.000Z"), Shard: 1, Tenant: "tenant-1"},
+		{DeletedAt: 1, Block: test.ULID("2024-07-15T17:00:00.000Z"), Shard: 2, Tenant: ""},
+		{DeletedAt: 3, Block: test.ULID("2024-07-15T15:00:00.000Z"), Shard: 1, Tenant: "tenant-2"},
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return s.StoreDeletedBlocks(tx, blocks)
+	}))
+
+	list := func(before int64, limit int) (deleted []DeletedBlock) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) (err error) {
+			deleted, err = s.ListDeletedBlocks(tx, before, limit)
+			return err
+		}))
+		return deleted
+	}
+	// Blocks are listed in the order they were deleted.
+	assert.Equal(t, []DeletedBlock{blocks[1], blocks[0], blocks[2]}, list(3, 0))
+	assert.Equal(t, []DeletedBlock{blocks[1], blocks[0]}, list(2, 0))
+	assert.Equal(t, []DeletedBlock{blocks[1]}, list(3, 1))
+	assert.Empty(t, list(0, 0))
+
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return s.DeleteDeletedBlocks(tx, blocks[:2])
+	}))
+	assert.Equal(t, []DeletedBlock{blocks[2]}, list(3, 0))
+}
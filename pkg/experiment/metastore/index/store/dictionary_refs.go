@@ -0,0 +1,115 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"go.etcd.io/bbolt"
+)
+
+// The shared symbols dictionaries are referenced by the compacted blocks.
+// The references are counted, so that a dictionary can be deleted once no
+// block refers to it. The key is the dictionary object path; the value is
+// the number of the blocks in the index that refer to it (4 bytes,
+// big-endian).
+//
+// Once the last reference is released, the counter is removed, and the
+// dictionary is recorded as released: the key is the path; the value is
+// the release time (8 bytes, big-endian). A released dictionary must not
+// be referenced again, as its object is to be deleted.
+
+var errInvalidDictionaryRef = errors.New("invalid dictionary reference entry")
+
+// AddDictionaryRefs increments the reference counters of the dictionaries.
+func (m *IndexStore) AddDictionaryRefs(tx *bbolt.Tx, paths []string) error {
+	bucket := tx.Bucket(dictionaryRefBucketNameBytes)
+	if bucket == nil {
+		return bbolt.ErrBucketNotFound
+	}
+	for _, p := range paths {
+		var n uint32
+		if v := bucket.Get([]byte(p)); v != nil {
+			if len(v) != 4 {
+				return errInvalidDictionaryRef
+			}
+			n = binary.BigEndian.Uint32(v)
+		}
+		v := make([]byte, 4)
+		binary.BigEndian.PutUint32(v, n+1)
+		if err := bucket.Put([]byte(p), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseDictionaryRefs decrements the reference counters of the
+// dictionaries, and returns the paths of the dictionaries no longer
+// referenced. The dictionaries not tracked are ignored.
+func (m *IndexStore) ReleaseDictionaryRefs(tx *bbolt.Tx, paths []string, releasedAt int64) ([]string, error) {
+	refs := tx.Bucket(dictionaryRefBucketNameBytes)
+	released := tx.Bucket(releasedDictionaryBucketNameBytes)
+	if refs == nil || released == nil {
+		return nil, bbolt.ErrBucketNotFound
+	}
+	var unreferenced []string
+	for _, p := range paths {
+		k := []byte(p)
+		v := refs.Get(k)
+		if v == nil {
+			continue
+		}
+		if len(v) != 4 {
+			return nil, errInvalidDictionaryRef
+		}
+		if n := binary.BigEndian.Uint32(v); n > 1 {
+			v = make([]byte, 4)
+			binary.BigEndian.PutUint32(v, n-1)
+			if err := refs.Put(k, v); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := refs.Delete(k); err != nil {
+			return nil, err
+		}
+		t := make([]byte, 8)
+		binary.BigEndian.PutUint64(t, uint64(releasedAt))
+		if err := released.Put(k, t); err != nil {
+			return nil, err
+		}
+		unreferenced = append(unreferenced, p)
+	}
+	return unreferenced, nil
+}
+
+// DictionaryReleased reports whether the dictionary has been released.
+func (m *IndexStore) DictionaryReleased(tx *bbolt.Tx, path string) bool {
+	bucket := tx.Bucket(releasedDictionaryBucketNameBytes)
+	return bucket != nil && bucket.Get([]byte(path)) != nil
+}
+
+// DeleteReleasedDictionaries forgets the dictionaries released
+// before the given time: their objects are expected to be deleted.
+func (m *IndexStore) DeleteReleasedDictionaries(tx *bbolt.Tx, before int64) error {
+	bucket := tx.Bucket(releasedDictionaryBucketNameBytes)
+	if bucket == nil {
+		return bbolt.ErrBucketNotFound
+	}
+	var expired [][]byte
+	c := bucket.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if len(v) != 8 {
+			return errInvalidDictionaryRef
+		}
+		if int64(binary.BigEndian.Uint64(v)) < before {
+			expired = append(expired, k)
+		}
+	}
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
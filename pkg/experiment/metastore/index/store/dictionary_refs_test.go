@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestIndexStore_DictionaryRefs(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+	require.NoError(t, db.Update(s.CreateBuckets))
+
+	release := func(releasedAt int64, paths ...string) (released []string) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) (err error) {
+			released, err = s.ReleaseDictionaryRefs(tx, paths, releasedAt)
+			return err
+		}))
+		return released
+	}
+	isReleased := func(path string) (released bool) {
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			released = s.DictionaryReleased(tx, path)
+			return nil
+		}))
+		return released
+	}
+
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		if err := s.AddDictionaryRefs(tx, []string{"a", "b"}); err != nil {
+			return err
+		}
+		return s.AddDictionaryRefs(tx, []string{"a"})
+	}))
+	assert.Equal(t, []string{"b"}, release(10, "a", "b"))
+	assert.True(t, isReleased("b"))
+	assert.False(t, isReleased("a"))
+	// The dictionaries not tracked are ignored.
+	assert.Equal(t, []string{"a"}, release(20, "a", "c"))
+	assert.Empty(t, release(30, "a"))
+
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return s.DeleteReleasedDictionaries(tx, 20)
+	}))
+	assert.False(t, isReleased("b"))
+	assert.True(t, isReleased("a"))
+}
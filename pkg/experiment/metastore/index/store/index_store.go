@@ -4,23 +4,47 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/oklog/ulid"
 	"go.etcd.io/bbolt"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 )
 
 const (
-	partitionBucketName   = "partition"
-	emptyTenantBucketName = "-"
+	partitionBucketName          = "partition"
+	archivedPartitionBucketName  = "partition_archive"
+	indexMetaBucketName          = "index_meta"
+	deletedBlockBucketName       = "deleted_block"
+	pendingBlockBucketName       = "pending_block"
+	dictionaryRefBucketName      = "dictionary_ref"
+	releasedDictionaryBucketName = "released_dictionary"
+	emptyTenantBucketName        = "-"
+
+	partitionSchemeKey = "partition_scheme"
 )
 
 var (
-	partitionBucketNameBytes   = []byte(partitionBucketName)
-	emptyTenantBucketNameBytes = []byte(emptyTenantBucketName)
+	partitionBucketNameBytes          = []byte(partitionBucketName)
+	archivedPartitionBucketNameBytes  = []byte(archivedPartitionBucketName)
+	indexMetaBucketNameBytes          = []byte(indexMetaBucketName)
+	deletedBlockBucketNameBytes       = []byte(deletedBlockBucketName)
+	pendingBlockBucketNameBytes       = []byte(pendingBlockBucketName)
+	dictionaryRefBucketNameBytes      = []byte(dictionaryRefBucketName)
+	releasedDictionaryBucketNameBytes = []byte(releasedDictionaryBucketName)
+	partitionSchemeKeyBytes           = []byte(partitionSchemeKey)
+	emptyTenantBucketNameBytes        = []byte(emptyTenantBucketName)
 )
 
-type IndexStore struct{}
+type IndexStore struct {
+	// BlockCompression is the compression of the block metadata entries
+	// written to the store, see BlockCompressions. The entries are read
+	// regardless of the compression they are written with.
+	BlockCompression string
+}
 
 func NewIndexStore() *IndexStore {
 	return &IndexStore{}
@@ -31,13 +55,60 @@ func getPartitionBucket(tx *bbolt.Tx) *bbolt.Bucket {
 }
 
 func (m *IndexStore) CreateBuckets(tx *bbolt.Tx) error {
-	_, err := tx.CreateBucketIfNotExists(partitionBucketNameBytes)
-	return err
+	partitions, err := tx.CreateBucketIfNotExists(partitionBucketNameBytes)
+	if err != nil {
+		return err
+	}
+	archived, err := tx.CreateBucketIfNotExists(archivedPartitionBucketNameBytes)
+	if err != nil {
+		return err
+	}
+	meta, err := tx.CreateBucketIfNotExists(indexMetaBucketNameBytes)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.CreateBucketIfNotExists(deletedBlockBucketNameBytes); err != nil {
+		return err
+	}
+	if _, err = tx.CreateBucketIfNotExists(pendingBlockBucketNameBytes); err != nil {
+		return err
+	}
+	if _, err = tx.CreateBucketIfNotExists(dictionaryRefBucketNameBytes); err != nil {
+		return err
+	}
+	if _, err = tx.CreateBucketIfNotExists(releasedDictionaryBucketNameBytes); err != nil {
+		return err
+	}
+	if meta.Get(partitionSchemeKeyBytes) != nil {
+		return nil
+	}
+	// Partitions created before the scheme was recorded
+	// in the store are partitioned by time.
+	k, _ := partitions.Cursor().First()
+	a, _ := archived.Cursor().First()
+	if k != nil || a != nil {
+		return meta.Put(partitionSchemeKeyBytes, []byte(TimePartitionSchemeName))
+	}
+	return nil
+}
+
+// PartitionScheme returns the definition of the partition scheme recorded
+// in the store, or an empty string, if no scheme has been recorded yet.
+func (m *IndexStore) PartitionScheme(tx *bbolt.Tx) string {
+	meta := tx.Bucket(indexMetaBucketNameBytes)
+	if meta == nil {
+		return ""
+	}
+	return string(meta.Get(partitionSchemeKeyBytes))
+}
+
+func (m *IndexStore) StorePartitionScheme(tx *bbolt.Tx, scheme string) error {
+	return tx.Bucket(indexMetaBucketNameBytes).Put(partitionSchemeKeyBytes, []byte(scheme))
 }
 
 func (m *IndexStore) StoreBlock(tx *bbolt.Tx, pk PartitionKey, b *metastorev1.BlockMeta) error {
 	key := []byte(b.Id)
-	value, err := b.MarshalVT()
+	value, err := encodeBlock(b, m.blockCompression())
 	if err != nil {
 		return err
 	}
@@ -163,7 +234,7 @@ func (m *IndexStore) ListBlocks(tx *bbolt.Tx, key PartitionKey, shard uint32, te
 	}
 	_ = tenantBkt.ForEach(func(k, v []byte) error {
 		var md metastorev1.BlockMeta
-		if err := md.UnmarshalVT(v); err != nil {
+		if err := decodeBlock(v, &md); err != nil {
 			panic(fmt.Sprintf("failed to unmarshal block %q: %v", string(k), err))
 		}
 		blocks = append(blocks, &md)
@@ -172,6 +243,141 @@ func (m *IndexStore) ListBlocks(tx *bbolt.Tx, key PartitionKey, shard uint32, te
 	return blocks
 }
 
+// ArchivePartition deletes the partition blocks and stores
+// the stub of the partition archive in their place.
+func (m *IndexStore) ArchivePartition(tx *bbolt.Tx, p *raft_log.ArchivedPartition) error {
+	partitions := getPartitionBucket(tx)
+	if partitions.Bucket([]byte(p.Key)) != nil {
+		if err := partitions.DeleteBucket([]byte(p.Key)); err != nil {
+			return err
+		}
+	}
+	value, err := p.MarshalVT()
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(archivedPartitionBucketNameBytes).Put([]byte(p.Key), value)
+}
+
+// DeleteArchivedPartition deletes the stub of the partition archive.
+func (m *IndexStore) DeleteArchivedPartition(tx *bbolt.Tx, key PartitionKey) error {
+	return tx.Bucket(archivedPartitionBucketNameBytes).Delete([]byte(key))
+}
+
+func (m *IndexStore) ListArchivedPartitions(tx *bbolt.Tx) []*raft_log.ArchivedPartition {
+	archived := make([]*raft_log.ArchivedPartition, 0)
+	_ = tx.Bucket(archivedPartitionBucketNameBytes).ForEach(func(k, v []byte) error {
+		var p raft_log.ArchivedPartition
+		if err := p.UnmarshalVT(v); err != nil {
+			panic(fmt.Sprintf("failed to unmarshal archived partition %q: %v", string(k), err))
+		}
+		archived = append(archived, &p)
+		return nil
+	})
+	return archived
+}
+
+// MovePartition moves the blocks of the source partition to the
+// target one, and deletes the source partition. The number of
+// blocks moved is returned.
+func (m *IndexStore) MovePartition(tx *bbolt.Tx, source, target PartitionKey) (int, error) {
+	partitions := getPartitionBucket(tx)
+	partition := partitions.Bucket([]byte(source))
+	if partition == nil {
+		return 0, nil
+	}
+	entries := collectBlockEntries(partition)
+	if err := partitions.DeleteBucket([]byte(source)); err != nil {
+		return 0, err
+	}
+	var moved int
+	for _, e := range entries {
+		if len(e.shard) != 4 {
+			continue
+		}
+		if err := putBlockEntry(partitions, target, e); err != nil {
+			return 0, fmt.Errorf("error moving block %s from partition %s to %s: %w", e.key, source, target, err)
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// SplitPartition moves the blocks of the source partition to the partitions
+// of the given duration the blocks belong to, according to the time of their
+// identifiers, and deletes the source partition. The key prefix is preserved.
+// The target partitions and the number of blocks moved are returned.
+func (m *IndexStore) SplitPartition(tx *bbolt.Tx, source PartitionKey, d time.Duration) ([]PartitionKey, int, error) {
+	partitions := getPartitionBucket(tx)
+	partition := partitions.Bucket([]byte(source))
+	if partition == nil {
+		return nil, 0, nil
+	}
+	entries := collectBlockEntries(partition)
+	if err := partitions.DeleteBucket([]byte(source)); err != nil {
+		return nil, 0, err
+	}
+	var targets []PartitionKey
+	var moved int
+	for _, e := range entries {
+		if len(e.shard) != 4 {
+			continue
+		}
+		t := ulid.Time(ulid.MustParse(string(e.key)).Time())
+		target, err := source.ResizeAt(t, d)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err = putBlockEntry(partitions, target, e); err != nil {
+			return nil, 0, fmt.Errorf("error moving block %s from partition %s to %s: %w", e.key, source, target, err)
+		}
+		if !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+		moved++
+	}
+	return targets, moved, nil
+}
+
+// DeleteTenantBlocks deletes the blocks of the tenant from all the shards
+// of the partition. The shards left empty are deleted, as well as the
+// partition itself, in which case true is returned. The deleted blocks
+// are returned.
+func (m *IndexStore) DeleteTenantBlocks(tx *bbolt.Tx, key PartitionKey, tenant string) ([]*metastorev1.BlockMeta, bool, error) {
+	partitions := getPartitionBucket(tx)
+	partition := partitions.Bucket([]byte(key))
+	if partition == nil {
+		return nil, false, nil
+	}
+	var deleted []*metastorev1.BlockMeta
+	for _, s := range m.ListShards(tx, key) {
+		shard := partition.Bucket(shardBucketName(s))
+		if shard.Bucket(tenantBucketName(tenant)) != nil {
+			deleted = append(deleted, m.ListBlocks(tx, key, s, tenant)...)
+			if err := shard.DeleteBucket(tenantBucketName(tenant)); err != nil {
+				return nil, false, err
+			}
+		}
+		if isEmptyBucket(shard) {
+			if err := partition.DeleteBucket(shardBucketName(s)); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	if !isEmptyBucket(partition) {
+		return deleted, false, nil
+	}
+	if err := partitions.DeleteBucket([]byte(key)); err != nil {
+		return nil, false, err
+	}
+	return deleted, true, nil
+}
+
+func isEmptyBucket(b *bbolt.Bucket) bool {
+	k, _ := b.Cursor().First()
+	return k == nil
+}
+
 func getOrCreateSubBucket(parent *bbolt.Bucket, name []byte) (*bbolt.Bucket, error) {
 	bucket := parent.Bucket(name)
 	if bucket == nil {
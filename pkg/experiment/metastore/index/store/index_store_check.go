@@ -0,0 +1,267 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid"
+	"go.etcd.io/bbolt"
+)
+
+type PartitionIssueType string
+
+const (
+	// IssueInvalidPartitionKey indicates that the partition key can't be
+	// parsed, and thus the blocks of the partition can't be found by time.
+	IssueInvalidPartitionKey PartitionIssueType = "invalid_partition_key"
+	// IssueEmptyPartition indicates that the partition has no blocks.
+	IssueEmptyPartition PartitionIssueType = "empty_partition"
+	// IssueEmptyShard indicates that the shard of the partition has no blocks.
+	IssueEmptyShard PartitionIssueType = "empty_shard"
+	// IssueEmptyTenant indicates that the tenant of the partition shard has no blocks.
+	IssueEmptyTenant PartitionIssueType = "empty_tenant"
+	// IssueInvalidBlock indicates that the block entry key is not a valid
+	// block identifier or the block metadata is missing.
+	IssueInvalidBlock PartitionIssueType = "invalid_block"
+)
+
+// PartitionIssue describes an inconsistency found in the index partitions,
+// which is typically caused by an interrupted write.
+type PartitionIssue struct {
+	Type      PartitionIssueType
+	Partition PartitionKey
+	Shard     uint32
+	Tenant    string
+	Block     string
+}
+
+// CheckPartitions inspects the index partitions and reports the issues found.
+// Issues of nested entries precede the issues of the entries they belong to:
+// e.g., invalid blocks are reported before the tenant they belong to, if the
+// latter has no valid blocks, so the issues can be resolved in order.
+//
+// The block metadata is not decoded: only the structure of the index is
+// checked, therefore the check is relatively cheap.
+func (m *IndexStore) CheckPartitions(tx *bbolt.Tx) []PartitionIssue {
+	partitions := getPartitionBucket(tx)
+	if partitions == nil {
+		return nil
+	}
+	var issues []PartitionIssue
+	_ = partitions.ForEachBucket(func(name []byte) error {
+		key := PartitionKey(name)
+		if _, _, err := key.Parse(); err != nil {
+			// The partition is rebuilt as a whole.
+			issues = append(issues, PartitionIssue{Type: IssueInvalidPartitionKey, Partition: key})
+			return nil
+		}
+		issues = checkPartition(issues, partitions.Bucket(name), key)
+		return nil
+	})
+	return issues
+}
+
+func checkPartition(issues []PartitionIssue, partition *bbolt.Bucket, key PartitionKey) []PartitionIssue {
+	var shards int
+	_ = partition.ForEachBucket(func(shardName []byte) error {
+		if len(shardName) != 4 {
+			return nil
+		}
+		shard := binary.BigEndian.Uint32(shardName)
+		shardBkt := partition.Bucket(shardName)
+		var tenants int
+		_ = shardBkt.ForEachBucket(func(tenantName []byte) error {
+			tenant := string(tenantName)
+			if bytes.Equal(tenantName, emptyTenantBucketNameBytes) {
+				tenant = ""
+			}
+			var blocks int
+			_ = shardBkt.Bucket(tenantName).ForEach(func(k, v []byte) error {
+				if v == nil {
+					// Nested buckets are not expected but are harmless.
+					return nil
+				}
+				if _, err := ulid.Parse(string(k)); err != nil || len(v) == 0 {
+					issues = append(issues, PartitionIssue{
+						Type:      IssueInvalidBlock,
+						Partition: key,
+						Shard:     shard,
+						Tenant:    tenant,
+						Block:     string(k),
+					})
+					return nil
+				}
+				blocks++
+				return nil
+			})
+			if blocks == 0 {
+				issues = append(issues, PartitionIssue{
+					Type:      IssueEmptyTenant,
+					Partition: key,
+					Shard:     shard,
+					Tenant:    tenant,
+				})
+				return nil
+			}
+			tenants++
+			return nil
+		})
+		if tenants == 0 {
+			issues = append(issues, PartitionIssue{
+				Type:      IssueEmptyShard,
+				Partition: key,
+				Shard:     shard,
+			})
+			return nil
+		}
+		shards++
+		return nil
+	})
+	if shards == 0 {
+		issues = append(issues, PartitionIssue{Type: IssueEmptyPartition, Partition: key})
+	}
+	return issues
+}
+
+// RepairPartitions resolves the issues reported by CheckPartitions. Invalid
+// blocks and empty entries are removed. Blocks of partitions with invalid keys
+// are moved to the partitions the blocks belong to, according to the given
+// partition duration of the block tenant and the partition scheme recorded in
+// the store. The transaction must be writable.
+func (m *IndexStore) RepairPartitions(tx *bbolt.Tx, issues []PartitionIssue, partitionDuration func(tenant string) time.Duration) error {
+	partitions := getPartitionBucket(tx)
+	if partitions == nil {
+		return nil
+	}
+	var scheme PartitionScheme = TimePartitionScheme{}
+	if s := m.PartitionScheme(tx); s != "" {
+		var err error
+		if scheme, err = ParsePartitionScheme(s); err != nil {
+			return err
+		}
+	}
+	for _, issue := range issues {
+		var err error
+		switch issue.Type {
+		case IssueInvalidPartitionKey:
+			err = rebuildPartition(partitions, issue.Partition, scheme, partitionDuration)
+		case IssueEmptyPartition:
+			err = partitions.DeleteBucket([]byte(issue.Partition))
+		case IssueEmptyShard:
+			if p := partitions.Bucket([]byte(issue.Partition)); p != nil {
+				err = p.DeleteBucket(shardBucketName(issue.Shard))
+			}
+		case IssueEmptyTenant:
+			if s := getShardBucket(partitions, issue.Partition, issue.Shard); s != nil {
+				err = s.DeleteBucket(tenantBucketName(issue.Tenant))
+			}
+		case IssueInvalidBlock:
+			if s := getShardBucket(partitions, issue.Partition, issue.Shard); s != nil {
+				if t := s.Bucket(tenantBucketName(issue.Tenant)); t != nil {
+					err = t.Delete([]byte(issue.Block))
+				}
+			}
+		}
+		if err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+			return fmt.Errorf("failed to repair partition %s (%s): %w", issue.Partition, issue.Type, err)
+		}
+	}
+	return nil
+}
+
+type blockEntry struct {
+	shard  []byte
+	tenant []byte
+	key    []byte
+	value  []byte
+}
+
+func rebuildPartition(partitions *bbolt.Bucket, key PartitionKey, scheme PartitionScheme, partitionDuration func(string) time.Duration) error {
+	partition := partitions.Bucket([]byte(key))
+	if partition == nil {
+		return nil
+	}
+	// The entries are collected first, as the buckets
+	// can't be modified while they are being iterated.
+	entries := collectBlockEntries(partition)
+	if err := partitions.DeleteBucket([]byte(key)); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if len(e.shard) != 4 {
+			continue
+		}
+		tenant := string(e.tenant)
+		if bytes.Equal(e.tenant, emptyTenantBucketNameBytes) {
+			tenant = ""
+		}
+		pk := scheme.PartitionKey(string(e.key), binary.BigEndian.Uint32(e.shard), tenant, partitionDuration(tenant))
+		if err := putBlockEntry(partitions, pk, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectBlockEntries returns the valid block entries of the partition.
+func collectBlockEntries(partition *bbolt.Bucket) []blockEntry {
+	var entries []blockEntry
+	_ = partition.ForEachBucket(func(shardName []byte) error {
+		shard := partition.Bucket(shardName)
+		return shard.ForEachBucket(func(tenantName []byte) error {
+			return shard.Bucket(tenantName).ForEach(func(k, v []byte) error {
+				if _, err := ulid.Parse(string(k)); err != nil || len(v) == 0 {
+					return nil
+				}
+				entries = append(entries, blockEntry{
+					shard:  bytes.Clone(shardName),
+					tenant: bytes.Clone(tenantName),
+					key:    bytes.Clone(k),
+					value:  bytes.Clone(v),
+				})
+				return nil
+			})
+		})
+	})
+	return entries
+}
+
+func putBlockEntry(partitions *bbolt.Bucket, pk PartitionKey, e blockEntry) error {
+	partBkt, err := getOrCreateSubBucket(partitions, []byte(pk))
+	if err != nil {
+		return err
+	}
+	shardBkt, err := getOrCreateSubBucket(partBkt, e.shard)
+	if err != nil {
+		return err
+	}
+	tenantBkt, err := getOrCreateSubBucket(shardBkt, e.tenant)
+	if err != nil {
+		return err
+	}
+	return tenantBkt.Put(e.key, e.value)
+}
+
+func getShardBucket(partitions *bbolt.Bucket, key PartitionKey, shard uint32) *bbolt.Bucket {
+	partition := partitions.Bucket([]byte(key))
+	if partition == nil {
+		return nil
+	}
+	return partition.Bucket(shardBucketName(shard))
+}
+
+func shardBucketName(shard uint32) []byte {
+	name := make([]byte, 4)
+	binary.BigEndian.PutUint32(name, shard)
+	return name
+}
+
+func tenantBucketName(tenant string) []byte {
+	if tenant == "" {
+		return emptyTenantBucketNameBytes
+	}
+	return []byte(tenant)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestIndexStore_CheckPartitions(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+
+	const d = time.Hour
+	valid := &metastorev1.BlockMeta{Id: test.ULID("2024-09-23T08:00:00.123Z"), Shard: 1, TenantId: "tenant-a"}
+	misplaced := &metastorev1.BlockMeta{Id: test.ULID("2024-09-23T09:00:00.123Z"), Shard: 2, TenantId: "tenant-b"}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		require.NoError(t, s.CreateBuckets(tx))
+		require.NoError(t, s.StoreBlock(tx, CreatePartitionKey(valid.Id, d), valid))
+		require.NoError(t, s.StoreBlock(tx, "invalid", misplaced))
+		partitions := getPartitionBucket(tx)
+		// A partition without shards.
+		_, err := partitions.CreateBucket([]byte("20240923T10.1h"))
+		require.NoError(t, err)
+		// A shard without tenants and a tenant without blocks.
+		partition := partitions.Bucket([]byte(CreatePartitionKey(valid.Id, d)))
+		_, err = partition.CreateBucket(shardBucketName(3))
+		require.NoError(t, err)
+		shard, err := partition.CreateBucket(shardBucketName(4))
+		require.NoError(t, err)
+		tenant, err := shard.CreateBucket(tenantBucketName(""))
+		require.NoError(t, err)
+		// An invalid block.
+		return tenant.Put([]byte("not-a-block"), []byte{1})
+	}))
+
+	expected := []PartitionIssue{
+		{Type: IssueEmptyShard, Partition: "20240923T08.1h", Shard: 3},
+		{Type: IssueInvalidBlock, Partition: "20240923T08.1h", Shard: 4, Block: "not-a-block"},
+		{Type: IssueEmptyTenant, Partition: "20240923T08.1h", Shard: 4},
+		{Type: IssueEmptyShard, Partition: "20240923T08.1h", Shard: 4},
+		{Type: IssueEmptyPartition, Partition: "20240923T10.1h"},
+		{Type: IssueInvalidPartitionKey, Partition: "invalid"},
+	}
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Equal(t, expected, s.CheckPartitions(tx))
+		return nil
+	}))
+
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return s.RepairPartitions(tx, s.CheckPartitions(tx), func(string) time.Duration { return d })
+	}))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Empty(t, s.CheckPartitions(tx))
+		assert.Equal(t, []PartitionKey{"20240923T08.1h", "20240923T09.1h"}, s.ListPartitions(tx))
+		assert.Equal(t, []uint32{1}, s.ListShards(tx, "20240923T08.1h"))
+		blocks := s.ListBlocks(tx, "20240923T09.1h", misplaced.Shard, misplaced.TenantId)
+		require.Len(t, blocks, 1)
+		assert.True(t, misplaced.EqualVT(blocks[0]))
+		return nil
+	}))
+}
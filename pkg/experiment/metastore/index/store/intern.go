@@ -0,0 +1,76 @@
+package store
+
+import (
+	"sync"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// maxInternedStrings limits the number of strings kept by the interner:
+// once the limit is reached, the interner is reset. The strings interned
+// before remain shared by the blocks decoded, but are not reused anymore.
+const maxInternedStrings = 1 << 20
+
+// blockStrings is shared by all the stores: the strings of the blocks are
+// alike regardless of the store they are decoded from.
+var blockStrings = newStringInterner(maxInternedStrings)
+
+// stringInterner deduplicates the strings repeated across the block
+// metadata: tenants, datasets, profile types, and labels. Strings that
+// are unique to each block, such as identifiers, are not interned.
+type stringInterner struct {
+	mu      sync.Mutex
+	strings map[string]string
+	limit   int
+}
+
+func newStringInterner(limit int) *stringInterner {
+	return &stringInterner{
+		strings: make(map[string]string),
+		limit:   limit,
+	}
+}
+
+func (x *stringInterner) intern(s string) string {
+	if s == "" {
+		return s
+	}
+	if v, ok := x.strings[s]; ok {
+		return v
+	}
+	if len(x.strings) >= x.limit {
+		clear(x.strings)
+	}
+	x.strings[s] = s
+	return s
+}
+
+func (x *stringInterner) internBlock(b *metastorev1.BlockMeta) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	b.TenantId = x.intern(b.TenantId)
+	b.CreatedBy = x.intern(b.CreatedBy)
+	if b.IdempotencyKey != nil {
+		b.IdempotencyKey.WriterId = x.intern(b.IdempotencyKey.WriterId)
+	}
+	for _, ds := range b.Datasets {
+		ds.TenantId = x.intern(ds.TenantId)
+		ds.Name = x.intern(ds.Name)
+		for i, pt := range ds.ProfileTypes {
+			ds.ProfileTypes[i] = x.intern(pt)
+		}
+		for _, ls := range ds.Labels {
+			for _, l := range ls.Labels {
+				l.Name = x.intern(l.Name)
+				l.Value = x.intern(l.Value)
+			}
+		}
+	}
+}
+
+// InternBlockStrings replaces the strings repeated across the block
+// metadata with their shared copies, to reduce the memory footprint
+// of the blocks kept in memory. The block must not be in use.
+func InternBlockStrings(b *metastorev1.BlockMeta) {
+	blockStrings.internBlock(b)
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestIndexStore_ListBlocks_InternedStrings(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+	require.NoError(t, db.Update(s.CreateBuckets))
+
+	const key = PartitionKey("20240715.1d")
+	block := func(id string) *metastorev1.BlockMeta {
+		return &metastorev1.BlockMeta{
+			Id:       id,
+			Shard:    1,
+			TenantId: "tenant-1",
+			Datasets: []*metastorev1.Dataset{{
+				TenantId:     "tenant-1",
+				Name:         "service",
+				ProfileTypes: []string{"cpu"},
+				Labels: []*typesv1.Labels{{
+					Labels: []*typesv1.LabelPair{{Name: "service_name", Value: "service"}},
+				}},
+			}},
+		}
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, b := range []*metastorev1.BlockMeta{
+			block(test.ULID("2024-07-15T15:00:00.000Z")),
+			block(test.ULID("2024-07-15T16:00:00.000Z")),
+		} {
+			if err := s.StoreBlock(tx, key, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	var blocks []*metastorev1.BlockMeta
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		blocks = s.ListBlocks(tx, key, 1, "tenant-1")
+		return nil
+	}))
+	require.Len(t, blocks, 2)
+	a, b := blocks[0], blocks[1]
+	assertShared := func(x, y string) {
+		assert.Equal(t, x, y)
+		assert.Equal(t, unsafe.StringData(x), unsafe.StringData(y))
+	}
+	assertShared(a.TenantId, b.TenantId)
+	assertShared(a.TenantId, a.Datasets[0].TenantId)
+	assertShared(a.Datasets[0].Name, b.Datasets[0].Name)
+	assertShared(a.Datasets[0].ProfileTypes[0], b.Datasets[0].ProfileTypes[0])
+	assertShared(a.Datasets[0].Labels[0].Labels[0].Name, b.Datasets[0].Labels[0].Labels[0].Name)
+	assertShared(a.Datasets[0].Labels[0].Labels[0].Value, b.Datasets[0].Labels[0].Labels[0].Value)
+	assertShared(a.Datasets[0].Name, b.Datasets[0].Labels[0].Labels[0].Value)
+	// Identifiers are not interned.
+	assert.NotEqual(t, a.Id, b.Id)
+}
+
+func Test_stringInterner_Limit(t *testing.T) {
+	x := newStringInterner(4)
+	for i := 0; i < 10; i++ {
+		x.intern(strconv.Itoa(i))
+		assert.LessOrEqual(t, len(x.strings), 4)
+	}
+	assert.Empty(t, x.intern(""))
+}
@@ -31,14 +31,16 @@ type PartitionKey string
 // verify that the returned partition actually contains the block.
 func CreatePartitionKey(blockId string, dur time.Duration) PartitionKey {
 	t := ulid.Time(ulid.MustParse(blockId).Time()).UTC()
+	return PartitionKey(partitionPeriod(t, dur))
+}
 
+func partitionPeriod(t time.Time, partitionDuration time.Duration) string {
 	var b strings.Builder
 	b.Grow(16)
 
 	year, month, day := t.Date()
 	b.WriteString(fmt.Sprintf("%04d%02d%02d", year, month, day))
 
-	partitionDuration := dur
 	if partitionDuration < 24*time.Hour {
 		hour := (t.Hour() / int(partitionDuration.Hours())) * int(partitionDuration.Hours())
 		b.WriteString(fmt.Sprintf("T%02d", hour))
@@ -48,11 +50,41 @@ func CreatePartitionKey(blockId string, dur time.Duration) PartitionKey {
 	b.WriteString(".")
 	b.WriteString(mDuration.String())
 
-	return PartitionKey(b.String())
+	return b.String()
+}
+
+// Resize returns the key of the partition of the given duration that
+// includes the start of the partition. The key prefix is preserved.
+func (k PartitionKey) Resize(d time.Duration) (PartitionKey, error) {
+	t, _, err := k.Parse()
+	if err != nil {
+		return "", err
+	}
+	return k.ResizeAt(t, d)
+}
+
+// ResizeAt returns the key of the partition of the given duration that
+// includes the given time. The key prefix is preserved.
+func (k PartitionKey) ResizeAt(t time.Time, d time.Duration) (PartitionKey, error) {
+	prefix, _, err := splitPartitionKey(k)
+	if err != nil {
+		return "", err
+	}
+	period := partitionPeriod(t.UTC(), d)
+	if prefix == "" {
+		return PartitionKey(period), nil
+	}
+	return PartitionKey(prefix + partitionKeyPrefixSeparator + period), nil
 }
 
+// Parse returns the time period of the partition. Keys
+// created by any PartitionScheme are supported.
 func (k PartitionKey) Parse() (t time.Time, d time.Duration, err error) {
-	parts := strings.Split(string(k), ".")
+	_, period, err := splitPartitionKey(k)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	parts := strings.Split(period, ".")
 	if len(parts) != 2 {
 		return time.Time{}, 0, fmt.Errorf("invalid partition key: %s", k)
 	}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// PartitionScheme determines the partition a block belongs to.
+//
+// Partition keys of all the schemes are self-describing: the time period of
+// a partition is always the last component of the key, optionally preceded
+// by a scheme-specific prefix, e.g., "t3-20240923T16.1h". Therefore, keys
+// created by any scheme can be parsed with PartitionKey.Parse.
+type PartitionScheme interface {
+	// PartitionKey returns the key of the partition for a new block.
+	PartitionKey(blockId string, shard uint32, tenant string, d time.Duration) PartitionKey
+	// String returns the scheme definition, as accepted by ParsePartitionScheme.
+	String() string
+}
+
+const (
+	TimePartitionSchemeName       = "time"
+	TenantHashPartitionSchemeName = "tenant-hash"
+	ShardPartitionSchemeName      = "shard"
+
+	partitionKeyPrefixSeparator = "-"
+	tenantHashPrefix            = 't'
+	shardPrefix                 = 's'
+)
+
+// ParsePartitionScheme parses the scheme definition:
+//   - "time": blocks are partitioned by time only.
+//   - "tenant-hash:<n>": blocks of each tenant are partitioned by time
+//     and by the tenant name hash into one of n groups, which spreads
+//     the blocks of large tenants over smaller partitions. Blocks that
+//     include data of multiple tenants are partitioned by time only.
+//   - "shard": blocks are partitioned by shard and time.
+func ParsePartitionScheme(s string) (PartitionScheme, error) {
+	name, arg, hasArg := strings.Cut(s, ":")
+	switch name {
+	case TimePartitionSchemeName:
+		if !hasArg {
+			return TimePartitionScheme{}, nil
+		}
+	case ShardPartitionSchemeName:
+		if !hasArg {
+			return ShardPartitionScheme{}, nil
+		}
+	case TenantHashPartitionSchemeName:
+		n, err := strconv.ParseUint(arg, 10, 32)
+		if err == nil && n > 0 {
+			return TenantHashPartitionScheme{Groups: uint32(n)}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid partition scheme: %q", s)
+}
+
+// TimePartitionScheme partitions blocks by the time of their creation.
+type TimePartitionScheme struct{}
+
+func (TimePartitionScheme) PartitionKey(blockId string, _ uint32, _ string, d time.Duration) PartitionKey {
+	return CreatePartitionKey(blockId, d)
+}
+
+func (TimePartitionScheme) String() string { return TimePartitionSchemeName }
+
+// TenantHashPartitionScheme partitions blocks by the time of
+// their creation and by the tenant name hash.
+type TenantHashPartitionScheme struct {
+	Groups uint32
+}
+
+func (s TenantHashPartitionScheme) PartitionKey(blockId string, _ uint32, tenant string, d time.Duration) PartitionKey {
+	k := CreatePartitionKey(blockId, d)
+	if tenant == "" {
+		return k
+	}
+	g := xxhash.Sum64String(tenant) % uint64(s.Groups)
+	return prefixPartitionKey(tenantHashPrefix, g, k)
+}
+
+func (s TenantHashPartitionScheme) String() string {
+	return TenantHashPartitionSchemeName + ":" + strconv.FormatUint(uint64(s.Groups), 10)
+}
+
+// ShardPartitionScheme partitions blocks by shard
+// and by the time of their creation.
+type ShardPartitionScheme struct{}
+
+func (ShardPartitionScheme) PartitionKey(blockId string, shard uint32, _ string, d time.Duration) PartitionKey {
+	return prefixPartitionKey(shardPrefix, uint64(shard), CreatePartitionKey(blockId, d))
+}
+
+func (ShardPartitionScheme) String() string { return ShardPartitionSchemeName }
+
+func prefixPartitionKey(prefix byte, n uint64, k PartitionKey) PartitionKey {
+	var b strings.Builder
+	b.Grow(len(k) + 8)
+	b.WriteByte(prefix)
+	b.WriteString(strconv.FormatUint(n, 10))
+	b.WriteString(partitionKeyPrefixSeparator)
+	b.WriteString(string(k))
+	return PartitionKey(b.String())
+}
+
+// splitPartitionKey returns the prefix and the time period of the key.
+func splitPartitionKey(k PartitionKey) (prefix, period string, err error) {
+	prefix, period, ok := strings.Cut(string(k), partitionKeyPrefixSeparator)
+	if !ok {
+		return "", string(k), nil
+	}
+	if len(prefix) < 2 || (prefix[0] != tenantHashPrefix && prefix[0] != shardPrefix) {
+		return "", "", fmt.Errorf("invalid partition key prefix: %s", k)
+	}
+	if _, err = strconv.ParseUint(prefix[1:], 10, 32); err != nil {
+		return "", "", fmt.Errorf("invalid partition key prefix: %s", k)
+	}
+	return prefix, period, nil
+}
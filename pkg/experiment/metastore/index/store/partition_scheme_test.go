@@ -0,0 +1,86 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestPartitionScheme_PartitionKey(t *testing.T) {
+	blockId := test.ULID("2024-07-15T16:13:43.245Z")
+	for _, tc := range []struct {
+		scheme string
+		shard  uint32
+		tenant string
+		want   PartitionKey
+	}{
+		{scheme: "time", shard: 1, tenant: "tenant-1", want: "20240715T16.1h"},
+		{scheme: "shard", shard: 1, tenant: "tenant-1", want: "s1-20240715T16.1h"},
+		{scheme: "shard", shard: 0, tenant: "", want: "s0-20240715T16.1h"},
+		{scheme: "tenant-hash:8", shard: 1, tenant: "tenant-1", want: "t6-20240715T16.1h"},
+		{scheme: "tenant-hash:8", shard: 2, tenant: "tenant-1", want: "t6-20240715T16.1h"},
+		{scheme: "tenant-hash:8", shard: 1, tenant: "", want: "20240715T16.1h"},
+	} {
+		t.Run(tc.scheme, func(t *testing.T) {
+			s, err := ParsePartitionScheme(tc.scheme)
+			require.NoError(t, err)
+			assert.Equal(t, tc.scheme, s.String())
+			k := s.PartitionKey(blockId, tc.shard, tc.tenant, time.Hour)
+			assert.Equal(t, tc.want, k)
+			ts, d, err := k.Parse()
+			require.NoError(t, err)
+			assert.Equal(t, test.Time("2024-07-15T16:00:00.000Z"), ts.UnixMilli())
+			assert.Equal(t, time.Hour, d)
+		})
+	}
+}
+
+func TestParsePartitionScheme_Invalid(t *testing.T) {
+	for _, s := range []string{"", "day", "time:1", "shard:2", "tenant-hash", "tenant-hash:0", "tenant-hash:x"} {
+		_, err := ParsePartitionScheme(s)
+		assert.Error(t, err, s)
+	}
+	for _, k := range []PartitionKey{"x1-20240715T16.1h", "t-20240715T16.1h", "sx-20240715T16.1h", "s1-invalid"} {
+		_, _, err := k.Parse()
+		assert.Error(t, err, k)
+	}
+}
+
+func TestIndexStore_PartitionScheme(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+	require.NoError(t, db.Update(s.CreateBuckets))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Empty(t, s.PartitionScheme(tx))
+		return nil
+	}))
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return s.StorePartitionScheme(tx, "shard")
+	}))
+	require.NoError(t, db.Update(s.CreateBuckets))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Equal(t, "shard", s.PartitionScheme(tx))
+		return nil
+	}))
+
+	// Stores created before the scheme was recorded are partitioned by time.
+	db = test.BoltDB(t)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		if _, err := tx.CreateBucket(partitionBucketNameBytes); err != nil {
+			return err
+		}
+		block := &metastorev1.BlockMeta{Id: test.ULID("2024-07-15T16:13:43.245Z"), TenantId: "tenant-1"}
+		return s.StoreBlock(tx, "20240715T16.1h", block)
+	}))
+	require.NoError(t, db.Update(s.CreateBuckets))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Equal(t, "time", s.PartitionScheme(tx))
+		return nil
+	}))
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// The blocks pending in the write-behind mode are journaled in the
+// transaction they are added in, so that they survive a crash. The journal
+// is append-only: the key is the sequence number of the entry (8 bytes,
+// big-endian); the value is the partition key length (2 bytes, big-endian),
+// the partition key, and the block metadata entry.
+
+var errInvalidPendingBlock = errors.New("invalid pending block entry")
+
+// StorePendingBlock appends the block to the journal.
+func (m *IndexStore) StorePendingBlock(tx *bbolt.Tx, pk PartitionKey, b *metastorev1.BlockMeta) error {
+	bucket := tx.Bucket(pendingBlockBucketNameBytes)
+	if bucket == nil {
+		return bbolt.ErrBucketNotFound
+	}
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	entry, err := encodeBlock(b, m.blockCompression())
+	if err != nil {
+		return err
+	}
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, seq)
+	v := make([]byte, 2+len(pk)+len(entry))
+	binary.BigEndian.PutUint16(v, uint16(len(pk)))
+	copy(v[2:], pk)
+	copy(v[2+len(pk):], entry)
+	return bucket.Put(k, v)
+}
+
+// FlushPendingBlocks stores the journaled blocks in their partitions,
+// in the order they were added, and clears the journal. It returns the
+// number of blocks stored.
+func (m *IndexStore) FlushPendingBlocks(tx *bbolt.Tx) (int, error) {
+	bucket := tx.Bucket(pendingBlockBucketNameBytes)
+	if bucket == nil {
+		return 0, bbolt.ErrBucketNotFound
+	}
+	var n int
+	c := bucket.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if len(v) < 2 || len(v) < 2+int(binary.BigEndian.Uint16(v)) {
+			return n, errInvalidPendingBlock
+		}
+		size := 2 + int(binary.BigEndian.Uint16(v))
+		var md metastorev1.BlockMeta
+		if err := decodeBlock(v[size:], &md); err != nil {
+			return n, fmt.Errorf("failed to decode pending block: %w", err)
+		}
+		if err := m.StoreBlock(tx, PartitionKey(v[2:size]), &md); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	// The bucket is recreated rather than cleared key by key: the
+	// sequence is reset, and the pages are released at once.
+	if err := tx.DeleteBucket(pendingBlockBucketNameBytes); err != nil {
+		return n, err
+	}
+	_, err := tx.CreateBucket(pendingBlockBucketNameBytes)
+	return n, err
+}
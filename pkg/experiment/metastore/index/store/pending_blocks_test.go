@@ -0,0 +1,61 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestIndexStore_PendingBlocks(t *testing.T) {
+	db := test.BoltDB(t)
+	s := NewIndexStore()
+	require.NoError(t, db.Update(s.CreateBuckets))
+
+	p1 := PartitionKey("20240715T16.1h")
+	p2 := PartitionKey("20240715T17.1h")
+	blocks := []*metastorev1.BlockMeta{
+		{Id: test.ULID("2024-07-15T16:00:00.000Z"), Shard: 1, TenantId: "tenant-1"},
+		{Id: test.ULID("2024-07-15T17:00:00.000Z"), Shard: 2},
+		{Id: test.ULID("2024-07-15T16:30:00.000Z"), Shard: 1, TenantId: "tenant-2"},
+	}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		for _, b := range blocks {
+			p := p1
+			if b.Shard == 2 {
+				p = p2
+			}
+			if err := s.StorePendingBlock(tx, p, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	flush := func() (n int) {
+		require.NoError(t, db.Update(func(tx *bbolt.Tx) (err error) {
+			n, err = s.FlushPendingBlocks(tx)
+			return err
+		}))
+		return n
+	}
+	// Journaled blocks are not in the partitions until flushed.
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.Empty(t, s.ListPartitions(tx))
+		return nil
+	}))
+	assert.Equal(t, 3, flush())
+	assert.Zero(t, flush())
+
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		assert.ElementsMatch(t, []PartitionKey{p1, p2}, s.ListPartitions(tx))
+		assert.Equal(t, blocks[:1], s.ListBlocks(tx, p1, 1, "tenant-1"))
+		assert.Equal(t, blocks[2:], s.ListBlocks(tx, p1, 1, "tenant-2"))
+		assert.Equal(t, blocks[1:2], s.ListBlocks(tx, p2, 2, ""))
+		return nil
+	}))
+}
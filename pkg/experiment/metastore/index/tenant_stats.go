@@ -0,0 +1,117 @@
+package index
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// TenantBlockStats returns the stats of the blocks of the tenants, ordered
+// by tenant; no tenants refers to all the tenants. The stats are maintained
+// by the partitions as blocks are added and removed, and are only aggregated
+// here: neither the database nor the partition blocks are read.
+func (i *Index) TenantBlockStats(tenants []string) []*metastorev1.TenantBlockStats {
+	i.partitionMu.RLock()
+	defer i.partitionMu.RUnlock()
+	stats := make(map[string]*metastorev1.TenantBlockStats)
+	for _, meta := range i.partitions.all() {
+		for tenant, s := range meta.tenantStats {
+			// Blocks without tenant datasets can't be attributed.
+			if tenant == "" || (len(tenants) > 0 && !slices.Contains(tenants, tenant)) {
+				continue
+			}
+			t, ok := stats[tenant]
+			if !ok {
+				t = &metastorev1.TenantBlockStats{TenantId: tenant}
+				stats[tenant] = t
+			}
+			mergeTenantBlockStats(t, s)
+		}
+	}
+	result := make([]*metastorev1.TenantBlockStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b *metastorev1.TenantBlockStats) int {
+		return strings.Compare(a.TenantId, b.TenantId)
+	})
+	return result
+}
+
+func mergeTenantBlockStats(dst, src *metastorev1.TenantBlockStats) {
+	dst.BlockCount += src.BlockCount
+	dst.BlockSize += src.BlockSize
+	if len(dst.BlocksByLevel) < len(src.BlocksByLevel) {
+		dst.BlocksByLevel = append(dst.BlocksByLevel, make([]uint64, len(src.BlocksByLevel)-len(dst.BlocksByLevel))...)
+	}
+	for level, n := range src.BlocksByLevel {
+		dst.BlocksByLevel[level] += n
+	}
+	extendTenantTimeRange(dst, src.MinTime, src.MaxTime)
+}
+
+// TenantStatsCollector exports the tenant block stats of the index.
+type TenantStatsCollector struct {
+	index *Index
+
+	blocks     *prometheus.Desc
+	blockBytes *prometheus.Desc
+	minTime    *prometheus.Desc
+	maxTime    *prometheus.Desc
+}
+
+// NewTenantStatsCollector creates a collector of the index tenant stats.
+// The collector does not report any metrics if the index is nil.
+func NewTenantStatsCollector(index *Index) *TenantStatsCollector {
+	return &TenantStatsCollector{
+		index: index,
+
+		blocks: prometheus.NewDesc(
+			"metastore_index_tenant_blocks",
+			"The number of blocks of the tenant in the index, by compaction level.",
+			[]string{"tenant", "level"}, nil,
+		),
+		blockBytes: prometheus.NewDesc(
+			"metastore_index_tenant_block_bytes",
+			"The total compressed size of the tenant data in the index blocks.",
+			[]string{"tenant"}, nil,
+		),
+		minTime: prometheus.NewDesc(
+			"metastore_index_tenant_oldest_data_timestamp_seconds",
+			"The time of the oldest tenant data in the index blocks.",
+			[]string{"tenant"}, nil,
+		),
+		maxTime: prometheus.NewDesc(
+			"metastore_index_tenant_newest_data_timestamp_seconds",
+			"The time of the newest tenant data in the index blocks.",
+			[]string{"tenant"}, nil,
+		),
+	}
+}
+
+func (c *TenantStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.blocks
+	ch <- c.blockBytes
+	ch <- c.minTime
+	ch <- c.maxTime
+}
+
+func (c *TenantStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.index == nil {
+		return
+	}
+	for _, s := range c.index.TenantBlockStats(nil) {
+		for level, n := range s.BlocksByLevel {
+			ch <- prometheus.MustNewConstMetric(c.blocks, prometheus.GaugeValue, float64(n), s.TenantId, strconv.Itoa(level))
+		}
+		ch <- prometheus.MustNewConstMetric(c.blockBytes, prometheus.GaugeValue, float64(s.BlockSize), s.TenantId)
+		if s.MaxTime != 0 {
+			ch <- prometheus.MustNewConstMetric(c.minTime, prometheus.GaugeValue, float64(s.MinTime)/1e3, s.TenantId)
+			ch <- prometheus.MustNewConstMetric(c.maxTime, prometheus.GaugeValue, float64(s.MaxTime)/1e3, s.TenantId)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+var ErrInvalidBlock = errors.New("invalid block metadata")
+
+type InvalidBlockReason string
+
+const (
+	InvalidBlockID   InvalidBlockReason = "invalid_id"
+	InvalidTimeRange InvalidBlockReason = "invalid_time_range"
+	MissingDatasets  InvalidBlockReason = "missing_datasets"
+	MissingTenant    InvalidBlockReason = "missing_tenant"
+	TenantMismatch   InvalidBlockReason = "tenant_mismatch"
+	ShardMismatch    InvalidBlockReason = "shard_mismatch"
+	ClockSkew        InvalidBlockReason = "clock_skew"
+	// ArchivedPartition is reported if the block belongs
+	// to a partition that has been archived: archived
+	// partitions can not be modified.
+	ArchivedPartition InvalidBlockReason = "archived_partition"
+	// DictionaryReleased is reported if the compacted block refers
+	// to a symbols dictionary that is no longer referenced by other
+	// blocks: the dictionary object is to be deleted.
+	DictionaryReleased InvalidBlockReason = "dictionary_released"
+)
+
+// InvalidBlockError is returned when the block metadata is rejected
+// by the index. It matches ErrInvalidBlock.
+type InvalidBlockError struct {
+	Block  string
+	Reason InvalidBlockReason
+	// Dataset is set if the reason refers to a dataset of the block.
+	Dataset string
+}
+
+func (e *InvalidBlockError) Error() string {
+	if e.Dataset != "" {
+		return fmt.Sprintf("%v: block %s, dataset %s: %s", ErrInvalidBlock, e.Block, e.Dataset, e.Reason)
+	}
+	return fmt.Sprintf("%v: block %s: %s", ErrInvalidBlock, e.Block, e.Reason)
+}
+
+func (e *InvalidBlockError) Unwrap() error { return ErrInvalidBlock }
+
+// ValidateBlock verifies that the block metadata can be used for query
+// pruning: the block identifier determines the partition, and the time
+// range and tenants of the block and its datasets are used to find the
+// blocks relevant to the query.
+func ValidateBlock(b *metastorev1.BlockMeta) error {
+	invalid := func(reason InvalidBlockReason, dataset string) error {
+		return &InvalidBlockError{Block: b.Id, Reason: reason, Dataset: dataset}
+	}
+	if _, err := ulid.Parse(b.Id); err != nil {
+		return invalid(InvalidBlockID, "")
+	}
+	if !validTimeRange(b.MinTime, b.MaxTime) {
+		return invalid(InvalidTimeRange, "")
+	}
+	if len(b.Datasets) == 0 {
+		return invalid(MissingDatasets, "")
+	}
+	for _, ds := range b.Datasets {
+		switch {
+		case ds.TenantId == "":
+			return invalid(MissingTenant, ds.Name)
+		case b.TenantId != "" && ds.TenantId != b.TenantId:
+			return invalid(TenantMismatch, ds.Name)
+		case !validTimeRange(ds.MinTime, ds.MaxTime) || ds.MinTime < b.MinTime || ds.MaxTime > b.MaxTime:
+			return invalid(InvalidTimeRange, ds.Name)
+		}
+	}
+	return nil
+}
+
+func validTimeRange(minTime, maxTime int64) bool {
+	return minTime > 0 && maxTime > 0 && minTime <= maxTime
+}
+
+// validateCompactedBlocks verifies that the compacted blocks
+// belong to the same shard as the source blocks.
+func validateCompactedBlocks(compacted *metastorev1.CompactedBlocks) error {
+	for _, b := range compacted.NewBlocks {
+		if err := ValidateBlock(b); err != nil {
+			return err
+		}
+		if compacted.SourceBlocks != nil && b.Shard != compacted.SourceBlocks.Shard {
+			return &InvalidBlockError{Block: b.Id, Reason: ShardMismatch}
+		}
+	}
+	return nil
+}
+
+type metrics struct {
+	rejectedBlocks     *prometheus.CounterVec
+	restampedBlocks    prometheus.Counter
+	quarantinedBlocks  prometheus.Counter
+	archivedPartitions prometheus.Counter
+	archiveLoads       *prometheus.CounterVec
+	mergedPartitions   prometheus.Counter
+	splitPartitions    prometheus.Counter
+	sweptBlocks        prometheus.Counter
+	deletedPartitions  prometheus.Counter
+	expiredBlocks      prometheus.Counter
+
+	partitionsToMerge prometheus.Gauge
+	partitionsToSplit prometheus.Gauge
+
+	loadedPartitionsBytes prometheus.Gauge
+	evictedPartitions     prometheus.Counter
+	pendingBlocks         prometheus.Gauge
+}
+
+// RegisterMetrics registers the index metrics without creating
+// the index. The metrics are used to generate monitoring resources.
+func RegisterMetrics(reg prometheus.Registerer) {
+	newMetrics(reg)
+	reg.MustRegister(NewTenantStatsCollector(nil))
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		rejectedBlocks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "metastore_index_rejected_blocks_total",
+			Help: "The total number of block metadata entries rejected by the index.",
+		}, []string{"reason"}),
+		restampedBlocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_restamped_blocks_total",
+			Help: "The total number of blocks with identifiers re-stamped because of the writer clock skew.",
+		}),
+		quarantinedBlocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_quarantined_blocks_total",
+			Help: "The total number of blocks quarantined because they can't be read.",
+		}),
+		archivedPartitions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_archived_partitions_total",
+			Help: "The total number of index partitions moved to the object storage.",
+		}),
+		archiveLoads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "metastore_index_archive_loads_total",
+			Help: "The total number of archived index partitions loaded from the object storage, by result.",
+		}, []string{"result"}),
+		mergedPartitions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_merged_partitions_total",
+			Help: "The total number of index partitions merged into partitions of the configured duration.",
+		}),
+		splitPartitions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_split_partitions_total",
+			Help: "The total number of index partitions split into partitions of the configured duration.",
+		}),
+		partitionsToMerge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "metastore_index_partitions_to_merge",
+			Help: "The number of index partitions shorter than the configured duration, which are yet to be merged. Only reported by the leader.",
+		}),
+		partitionsToSplit: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "metastore_index_partitions_to_split",
+			Help: "The number of index partitions longer than the configured duration, which are yet to be split. Only reported by the leader.",
+		}),
+		deletedPartitions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_deleted_partitions_total",
+			Help: "The total number of index partitions deleted, as the retention period of their tenants has passed.",
+		}),
+		expiredBlocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_expired_blocks_total",
+			Help: "The total number of blocks removed from the index, as the retention period of their tenants has passed.",
+		}),
+		sweptBlocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_swept_blocks_total",
+			Help: "The total number of deleted blocks removed from the index after the grace period.",
+		}),
+		loadedPartitionsBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "metastore_index_loaded_partitions_bytes",
+			Help: "Approximate memory footprint of the index partitions loaded in memory.",
+		}),
+		evictedPartitions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "metastore_index_evicted_partitions_total",
+			Help: "The total number of index partitions unloaded from memory because the partition cache size limit was reached.",
+		}),
+		pendingBlocks: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "metastore_index_pending_blocks",
+			Help: "The number of blocks added to the index in memory, but not stored in their partitions yet, in the write-behind mode.",
+		}),
+	}
+	m.rejectedBlocks = util.RegisterOrGet(reg, m.rejectedBlocks)
+	m.restampedBlocks = util.RegisterOrGet(reg, m.restampedBlocks)
+	m.quarantinedBlocks = util.RegisterOrGet(reg, m.quarantinedBlocks)
+	m.archivedPartitions = util.RegisterOrGet(reg, m.archivedPartitions)
+	m.archiveLoads = util.RegisterOrGet(reg, m.archiveLoads)
+	m.mergedPartitions = util.RegisterOrGet(reg, m.mergedPartitions)
+	m.splitPartitions = util.RegisterOrGet(reg, m.splitPartitions)
+	m.partitionsToMerge = util.RegisterOrGet(reg, m.partitionsToMerge)
+	m.partitionsToSplit = util.RegisterOrGet(reg, m.partitionsToSplit)
+	m.sweptBlocks = util.RegisterOrGet(reg, m.sweptBlocks)
+	m.deletedPartitions = util.RegisterOrGet(reg, m.deletedPartitions)
+	m.expiredBlocks = util.RegisterOrGet(reg, m.expiredBlocks)
+	m.loadedPartitionsBytes = util.RegisterOrGet(reg, m.loadedPartitionsBytes)
+	m.evictedPartitions = util.RegisterOrGet(reg, m.evictedPartitions)
+	m.pendingBlocks = util.RegisterOrGet(reg, m.pendingBlocks)
+	return m
+}
+
+func (m *metrics) observeValidation(err error) {
+	var invalid *InvalidBlockError
+	if errors.As(err, &invalid) {
+		m.rejectedBlocks.WithLabelValues(string(invalid.Reason)).Inc()
+	}
+}
@@ -0,0 +1,84 @@
+package index
+
+import (
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+)
+
+// In the write-behind mode, the blocks inserted are added to the index in
+// memory immediately, but stored in their partitions later, in batches:
+// either when Flush is called, which the FSM does periodically, before a
+// snapshot is taken, and at shutdown, or when the queue of the pending
+// blocks is full, in the transaction of the insert.
+//
+// A pending block is journaled in the transaction that adds it, which is
+// cheaper than storing it in the partition: the journal is a single bucket
+// the entries are appended to, whereas a block is stored in nested buckets
+// of the partition, shard, and tenant. The applied index is therefore
+// stored with every command, and the raft log is never replayed over the
+// state already committed. The blocks journaled at a crash are stored in
+// their partitions when the index is initialized at restart.
+//
+// Operations that read or modify the stored blocks, other than the loading
+// of a partition, flush the pending blocks first.
+
+type pendingBlock struct {
+	key   store.PartitionKey
+	block *metastorev1.BlockMeta
+}
+
+func (i *Index) writeBehind() bool {
+	return i.config.BlockWriteBehindInterval > 0
+}
+
+// storeBlockBehind journals the block and queues it to be stored. If the
+// queue is full, the pending blocks are stored in the transaction.
+func (i *Index) storeBlockBehind(tx *bbolt.Tx, key store.PartitionKey, b *metastorev1.BlockMeta) error {
+	if err := i.store.StorePendingBlock(tx, key, b); err != nil {
+		return err
+	}
+	i.pending = append(i.pending, pendingBlock{key: key, block: b})
+	i.metrics.pendingBlocks.Set(float64(len(i.pending)))
+	if len(i.pending) < i.config.BlockWriteBehindQueueSize {
+		return nil
+	}
+	return i.flushPendingBlocks(tx)
+}
+
+// Flush stores the blocks pending in the write-behind mode.
+func (i *Index) Flush(tx *bbolt.Tx) error {
+	i.partitionMu.Lock()
+	defer i.partitionMu.Unlock()
+	return i.flushPendingBlocks(tx)
+}
+
+// flushPendingBlocks stores the blocks journaled in their partitions.
+// The blocks queued in memory are the ones journaled.
+func (i *Index) flushPendingBlocks(tx *bbolt.Tx) error {
+	if len(i.pending) == 0 {
+		return nil
+	}
+	if _, err := i.store.FlushPendingBlocks(tx); err != nil {
+		return err
+	}
+	i.dropPendingBlocks()
+	return nil
+}
+
+func (i *Index) dropPendingBlocks() {
+	clear(i.pending)
+	i.pending = i.pending[:0]
+	i.metrics.pendingBlocks.Set(0)
+}
+
+// putPendingBlocks adds the pending blocks of the tenant partition
+// to the partition loaded from the database.
+func (i *Index) putPendingBlocks(p *indexPartition, tenant string) {
+	for _, x := range i.pending {
+		if x.key == p.meta.Key && x.block.TenantId == tenant {
+			i.putBlock(p, i.getOrCreateShard(p, x.block.Shard), x.block)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/thanos-io/objstore"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+type PartitionArchiveSource interface {
+	PartitionsToArchive(before time.Time) []store.PartitionKey
+	PartitionArchive(*bbolt.Tx, store.PartitionKey) ([]byte, *raft_log.ArchivedPartition, error)
+}
+
+// PartitionArchiver moves the index partitions older than the configured
+// threshold to the object storage. It only runs on the raft leader: the
+// archive is uploaded to the bucket, and then the partition is replaced
+// with its archive through the raft log, which keeps the replicas in sync.
+type PartitionArchiver struct {
+	config index.Config
+	logger log.Logger
+	raft   Raft
+	state  State
+	index  PartitionArchiveSource
+	bucket objstore.Bucket
+
+	m       sync.Mutex
+	started bool
+	cancel  func()
+}
+
+func NewPartitionArchiver(
+	logger log.Logger,
+	config index.Config,
+	raft Raft,
+	state State,
+	index PartitionArchiveSource,
+	bucket objstore.Bucket,
+) *PartitionArchiver {
+	return &PartitionArchiver{
+		config: config,
+		logger: logger,
+		raft:   raft,
+		state:  state,
+		index:  index,
+		bucket: bucket,
+	}
+}
+
+func (a *PartitionArchiver) Start() {
+	a.m.Lock()
+	defer a.m.Unlock()
+	if a.config.PartitionArchiveAfter <= 0 || a.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.started = true
+	go a.loop(ctx)
+	level.Info(a.logger).Log("msg", "partition archiver started")
+}
+
+func (a *PartitionArchiver) Stop() {
+	a.m.Lock()
+	defer a.m.Unlock()
+	if !a.started {
+		return
+	}
+	a.cancel()
+	a.started = false
+	level.Info(a.logger).Log("msg", "partition archiver stopped")
+}
+
+func (a *PartitionArchiver) loop(ctx context.Context) {
+	ticker := time.NewTicker(a.config.PartitionArchiveCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.archive(ctx)
+		}
+	}
+}
+
+func (a *PartitionArchiver) archive(ctx context.Context) {
+	before := time.Now().Add(-a.config.PartitionArchiveAfter)
+	for _, key := range a.index.PartitionsToArchive(before) {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := a.archivePartition(ctx, key); err != nil {
+			if raftnode.IsRaftLeadershipError(err) {
+				return
+			}
+			level.Error(a.logger).Log("msg", "failed to archive partition", "partition", key, "err", err)
+		}
+	}
+}
+
+func (a *PartitionArchiver) archivePartition(ctx context.Context, key store.PartitionKey) error {
+	var data []byte
+	var stub *raft_log.ArchivedPartition
+	var err error
+	readErr := a.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		data, stub, err = a.index.PartitionArchive(tx, key)
+	})
+	if readErr != nil {
+		return readErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = a.bucket.Upload(ctx, stub.Path, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ARCHIVE_PARTITION)
+	resp, err := a.raft.Propose(cmd, &raft_log.ArchivePartitionRequest{Partition: stub})
+	if err != nil {
+		return err
+	}
+	if !resp.(*raft_log.ArchivePartitionResponse).Archived {
+		// The partition has changed in the meantime: the
+		// next attempt is made at the next check.
+		level.Warn(a.logger).Log("msg", "partition not archived", "partition", key)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package metastore
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/thanos-io/objstore"
+	"go.etcd.io/bbolt"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+)
+
+// checkObjectsConcurrency limits the number of concurrent
+// requests to the object storage made by the index check.
+const checkObjectsConcurrency = 16
+
+type IndexCheck interface {
+	Check(context.Context, *metastorev1.CheckIndexRequest) (*metastorev1.CheckIndexResponse, error)
+}
+
+type CheckedIndex interface {
+	Check(*bbolt.Tx, func(store.PartitionKey, *metastorev1.BlockMeta)) *metastorev1.CheckIndexResponse
+}
+
+// IndexChecker cross-checks the index in memory against the database, and
+// optionally, the blocks against the object storage. The check is read-only
+// and runs on the replica serving the request. The repair is proposed to the
+// raft leader, and applied on all the replicas: each replica resolves the
+// issues of its own state. The objects are checked after the repair.
+type IndexChecker struct {
+	logger log.Logger
+	raft   Raft
+	state  State
+	index  CheckedIndex
+	bucket objstore.BucketReader
+}
+
+func NewIndexChecker(
+	logger log.Logger,
+	raft Raft,
+	state State,
+	index CheckedIndex,
+	bucket objstore.BucketReader,
+) *IndexChecker {
+	return &IndexChecker{
+		logger: logger,
+		raft:   raft,
+		state:  state,
+		index:  index,
+		bucket: bucket,
+	}
+}
+
+type blockObject struct {
+	partition store.PartitionKey
+	shard     uint32
+	tenant    string
+	block     string
+	path      string
+}
+
+func (c *IndexChecker) Check(ctx context.Context, req *metastorev1.CheckIndexRequest) (*metastorev1.CheckIndexResponse, error) {
+	var resp *metastorev1.CheckIndexResponse
+	if req.Repair {
+		r, err := c.raft.Propose(fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_REPAIR_INDEX), &raft_log.RepairIndexRequest{})
+		if err != nil {
+			level.Error(c.logger).Log("msg", "failed to repair index", "err", err)
+			return nil, err
+		}
+		repaired := r.(*raft_log.RepairIndexResponse)
+		resp = &metastorev1.CheckIndexResponse{
+			Issues:     repaired.Issues,
+			Partitions: repaired.Partitions,
+			Blocks:     repaired.Blocks,
+			Repaired:   len(repaired.Issues) > 0,
+		}
+		if !req.CheckObjects {
+			return resp, nil
+		}
+	}
+
+	var objects []blockObject
+	var visit func(store.PartitionKey, *metastorev1.BlockMeta)
+	if req.CheckObjects {
+		visit = func(k store.PartitionKey, b *metastorev1.BlockMeta) {
+			if b.DeletedAt != 0 {
+				// The object may have been deleted already.
+				return
+			}
+			objects = append(objects, blockObject{
+				partition: k,
+				shard:     b.Shard,
+				tenant:    b.TenantId,
+				block:     b.Id,
+				path:      block.ObjectPath(b),
+			})
+		}
+	}
+	readErr := c.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		r := c.index.Check(tx, visit)
+		if resp == nil {
+			resp = r
+		}
+	})
+	if readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	if req.CheckObjects {
+		missing, err := c.checkObjects(ctx, objects)
+		if err != nil {
+			return nil, err
+		}
+		resp.Issues = append(resp.Issues, missing...)
+	}
+	return resp, nil
+}
+
+func (c *IndexChecker) checkObjects(ctx context.Context, objects []blockObject) ([]*metastorev1.IndexIssue, error) {
+	missing := make([]bool, len(objects))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(checkObjectsConcurrency)
+	for j := range objects {
+		g.Go(func() error {
+			exists, err := c.bucket.Exists(ctx, objects[j].path)
+			missing[j] = !exists
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		level.Error(c.logger).Log("msg", "failed to check block objects", "err", err)
+		return nil, err
+	}
+	var issues []*metastorev1.IndexIssue
+	for j, o := range objects {
+		if !missing[j] {
+			continue
+		}
+		issues = append(issues, &metastorev1.IndexIssue{
+			Type:      metastorev1.IndexIssueType_INDEX_ISSUE_TYPE_MISSING_OBJECT,
+			Partition: string(o.partition),
+			Shard:     o.shard,
+			TenantId:  o.tenant,
+			BlockId:   o.block,
+			Details:   o.path,
+		})
+	}
+	level.Info(c.logger).Log("msg", "block objects checked", "objects", len(objects), "missing", len(issues))
+	return issues, nil
+}
@@ -0,0 +1,83 @@
+package metastore
+
+import (
+	_ "embed" // Used to embed html template
+	"html/template"
+	"net/http"
+	"time"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+//go:embed index_partitions.gohtml
+var partitionsPageHTML string
+var partitionsTemplate = template.Must(template.New("webpage").Parse(partitionsPageHTML))
+
+type partitionsPageContents struct {
+	Now        time.Time       `json:"now"`
+	Tenants    []string        `json:"tenants,omitempty"`
+	Partitions []partitionInfo `json:"partitions"`
+}
+
+type partitionInfo struct {
+	Key        string                `json:"key"`
+	Start      time.Time             `json:"start"`
+	End        time.Time             `json:"end"`
+	MinTime    time.Time             `json:"min_time"`
+	MaxTime    time.Time             `json:"max_time"`
+	Tenants    []string              `json:"tenants"`
+	Shards     []uint32              `json:"shards"`
+	BlockCount uint64                `json:"block_count"`
+	BlockSize  uint64                `json:"block_size"`
+	Archived   bool                  `json:"archived"`
+	Loaded     []loadedPartitionInfo `json:"loaded"`
+}
+
+type loadedPartitionInfo struct {
+	Tenant     string    `json:"tenant"`
+	AccessedAt time.Time `json:"accessed_at"`
+	Size       uint64    `json:"size"`
+}
+
+// PartitionsHandler lists the index partitions, and their state on this
+// replica. The partitions can be filtered by the "tenant" parameter, which
+// may be specified multiple times.
+func (svc *IndexService) PartitionsHandler(w http.ResponseWriter, req *http.Request) {
+	tenants := req.URL.Query()["tenant"]
+	resp, err := svc.ListPartitions(req.Context(), &metastorev1.ListPartitionsRequest{TenantId: tenants})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	contents := partitionsPageContents{
+		Now:        time.Now().UTC(),
+		Tenants:    tenants,
+		Partitions: make([]partitionInfo, 0, len(resp.Partitions)),
+	}
+	for _, p := range resp.Partitions {
+		info := partitionInfo{
+			Key:        p.Key,
+			Start:      time.UnixMilli(p.StartTime).UTC(),
+			End:        time.UnixMilli(p.EndTime).UTC(),
+			Tenants:    p.Tenants,
+			Shards:     p.Shards,
+			BlockCount: p.BlockCount,
+			BlockSize:  p.BlockSize,
+			Archived:   p.Archived,
+		}
+		if p.MaxTime > 0 {
+			info.MinTime = time.UnixMilli(p.MinTime).UTC()
+			info.MaxTime = time.UnixMilli(p.MaxTime).UTC()
+		}
+		for _, l := range p.Loaded {
+			info.Loaded = append(info.Loaded, loadedPartitionInfo{
+				Tenant:     l.TenantId,
+				AccessedAt: time.UnixMilli(l.AccessedAt).UTC(),
+				Size:       l.Size,
+			})
+		}
+		contents.Partitions = append(contents.Partitions, info)
+	}
+	util.RenderHTTPResponse(w, contents, partitionsTemplate, req)
+}
@@ -0,0 +1,124 @@
+package metastore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+type PartitionMergeSource interface {
+	PartitionsToMerge() []*raft_log.MergePartitionsRequest
+	PartitionsToSplit() []*raft_log.SplitPartitionRequest
+}
+
+// PartitionMerger migrates the index partitions to the configured partition
+// duration, once it has changed: shorter partitions are merged into, and
+// longer partitions are split into partitions of the configured duration.
+// It only runs on the raft leader: each merge and split is applied through
+// the raft log, so the partitions are migrated atomically and identically
+// on all replicas. The remaining partitions are determined from the index
+// state on every run, therefore the migration resumes where it stopped
+// after a restart or a leadership change.
+type PartitionMerger struct {
+	config index.Config
+	logger log.Logger
+	raft   Raft
+	index  PartitionMergeSource
+
+	m       sync.Mutex
+	started bool
+	cancel  func()
+}
+
+func NewPartitionMerger(
+	logger log.Logger,
+	config index.Config,
+	raft Raft,
+	index PartitionMergeSource,
+) *PartitionMerger {
+	return &PartitionMerger{
+		config: config,
+		logger: logger,
+		raft:   raft,
+		index:  index,
+	}
+}
+
+func (m *PartitionMerger) Start() {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if m.config.PartitionMergeInterval <= 0 || m.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.started = true
+	go m.loop(ctx)
+	level.Info(m.logger).Log("msg", "partition merger started")
+}
+
+func (m *PartitionMerger) Stop() {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if !m.started {
+		return
+	}
+	m.cancel()
+	m.started = false
+	level.Info(m.logger).Log("msg", "partition merger stopped")
+}
+
+func (m *PartitionMerger) loop(ctx context.Context) {
+	ticker := time.NewTicker(m.config.PartitionMergeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.merge(ctx)
+		}
+	}
+}
+
+func (m *PartitionMerger) merge(ctx context.Context) {
+	merges := m.index.PartitionsToMerge()
+	splits := m.index.PartitionsToSplit()
+	if len(merges) > 0 || len(splits) > 0 {
+		level.Info(m.logger).Log("msg", "migrating index partitions", "merges", len(merges), "splits", len(splits))
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_MERGE_PARTITIONS)
+	// Partitions are merged one group at a time,
+	// which limits the size of the transaction.
+	for _, req := range merges {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := m.raft.Propose(cmd, req); err != nil {
+			if raftnode.IsRaftLeadershipError(err) {
+				return
+			}
+			level.Error(m.logger).Log("msg", "failed to merge partitions", "partition", req.Target, "err", err)
+		}
+	}
+	cmd = fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_SPLIT_PARTITION)
+	for _, req := range splits {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := m.raft.Propose(cmd, req); err != nil {
+			if raftnode.IsRaftLeadershipError(err) {
+				return
+			}
+			level.Error(m.logger).Log("msg", "failed to split partition", "partition", req.Source, "err", err)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package metastore
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/hashicorp/raft"
@@ -8,58 +12,448 @@ import (
 	"go.etcd.io/bbolt"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 )
 
 type Index interface {
+	CheckBlockClockSkew(*metastorev1.BlockMeta, time.Time) error
 	InsertBlock(*bbolt.Tx, *metastorev1.BlockMeta) error
+	InsertBlocks(*bbolt.Tx, []*metastorev1.BlockMeta) []error
+	QuarantineBlock(tx *bbolt.Tx, shard uint32, tenant string, block string, q *metastorev1.BlockQuarantine) (*metastorev1.BlockMeta, error)
+	ArchivePartition(*bbolt.Tx, *raft_log.ArchivedPartition) (bool, error)
+	MergePartitions(*bbolt.Tx, *raft_log.MergePartitionsRequest) (*raft_log.MergePartitionsResponse, error)
+	SplitPartition(*bbolt.Tx, *raft_log.SplitPartitionRequest) (*raft_log.SplitPartitionResponse, error)
+	SweepDeletedBlocks(*bbolt.Tx, *raft_log.SweepDeletedBlocksRequest, time.Time) (*raft_log.SweepDeletedBlocksResponse, []index.ReleasedDictionary, error)
+	DeletePartitionsBefore(tx *bbolt.Tx, before time.Time, tenant string, deletedAt time.Time) (*index.DeletedPartitions, error)
+	Repair(*bbolt.Tx) (*metastorev1.CheckIndexResponse, error)
 }
 
 type Tombstones interface {
 	Exists(*metastorev1.BlockMeta) bool
+	AddTombstones(*bbolt.Tx, *raft.Log, *metastorev1.Tombstones, ...*metastorev1.BlockMeta) error
+}
+
+type Writers interface {
+	Quarantined(writer string) *metastorev1.WriterQuarantine
 }
 
 type Compactor interface {
 	Compact(*bbolt.Tx, *raft.Log, *metastorev1.BlockMeta) error
 }
 
+type BlockEventRecorder interface {
+	BlockAdded(*bbolt.Tx, *raft.Log, *metastorev1.BlockMeta) error
+	BlockQuarantined(*bbolt.Tx, *raft.Log, *metastorev1.BlockMeta) error
+	RetentionApplied(*bbolt.Tx, *raft.Log, []*metastorev1.Tombstones, []*raft_log.ArchivedPartition) error
+}
+
 type IndexCommandHandler struct {
-	logger     log.Logger
-	index      Index
-	tombstones Tombstones
-	compactor  Compactor
+	logger      log.Logger
+	index       Index
+	tombstones  Tombstones
+	writers     Writers
+	compactor   Compactor
+	events      BlockEventRecorder
+	cardinality *tenantCardinality
 }
 
 func NewIndexCommandHandler(
 	logger log.Logger,
 	index Index,
 	tombstones Tombstones,
+	writers Writers,
 	compactor Compactor,
+	events BlockEventRecorder,
+	cardinality *tenantCardinality,
 ) *IndexCommandHandler {
 	return &IndexCommandHandler{
-		logger:     logger,
-		index:      index,
-		tombstones: tombstones,
-		compactor:  compactor,
+		logger:      logger,
+		index:       index,
+		tombstones:  tombstones,
+		writers:     writers,
+		compactor:   compactor,
+		events:      events,
+		cardinality: cardinality,
 	}
 }
 
 func (m *IndexCommandHandler) AddBlock(tx *bbolt.Tx, cmd *raft.Log, req *metastorev1.AddBlockRequest) (*metastorev1.AddBlockResponse, error) {
-	if m.tombstones.Exists(req.Block) {
-		level.Warn(m.logger).Log("msg", "block already added and compacted", "block_id", req.Block.Id)
-		return new(metastorev1.AddBlockResponse), nil
-	}
-	if err := m.index.InsertBlock(tx, req.Block); err != nil {
-		if errors.Is(err, index.ErrBlockExists) {
-			level.Warn(m.logger).Log("msg", "block already added", "block_id", req.Block.Id)
-			return new(metastorev1.AddBlockResponse), nil
+	if resp := m.checkBlock(cmd, req.Block); resp != nil {
+		return resp, nil
+	}
+	stampBlock(req.Block, cmd)
+	return m.blockInserted(tx, cmd, req.Block, m.index.InsertBlock(tx, req.Block))
+}
+
+// AddBlocks handles each of the blocks the same way AddBlock does, except
+// that the blocks are inserted into the index at once. The results are
+// reported in the order of the request blocks.
+func (m *IndexCommandHandler) AddBlocks(tx *bbolt.Tx, cmd *raft.Log, req *metastorev1.AddBlocksRequest) (*metastorev1.AddBlocksResponse, error) {
+	resp := &metastorev1.AddBlocksResponse{Results: make([]*metastorev1.AddBlockResponse, len(req.Blocks))}
+	blocks := make([]*metastorev1.BlockMeta, 0, len(req.Blocks))
+	positions := make([]int, 0, len(req.Blocks))
+	for j, b := range req.Blocks {
+		if resp.Results[j] = m.checkBlock(cmd, b); resp.Results[j] == nil {
+			stampBlock(b, cmd)
+			blocks = append(blocks, b)
+			positions = append(positions, j)
+		}
+	}
+	for k, err := range m.index.InsertBlocks(tx, blocks) {
+		r, err := m.blockInserted(tx, cmd, blocks[k], err)
+		if err != nil {
+			return nil, err
+		}
+		resp.Results[positions[k]] = r
+	}
+	return resp, nil
+}
+
+// checkBlock returns the response to the block that is not to be
+// inserted into the index, or nil, if the block may be inserted.
+func (m *IndexCommandHandler) checkBlock(cmd *raft.Log, block *metastorev1.BlockMeta) *metastorev1.AddBlockResponse {
+	if q := m.writers.Quarantined(block.CreatedBy); q != nil {
+		level.Warn(m.logger).Log("msg", "block refused: writer is quarantined", "block_id", block.Id, "writer", q.WriterId)
+		return writerQuarantinedResponse(q)
+	}
+	if m.tombstones.Exists(block) {
+		level.Warn(m.logger).Log("msg", "block already added and compacted", "block_id", block.Id)
+		return &metastorev1.AddBlockResponse{Result: metastorev1.AddBlockResult_ADD_BLOCK_RESULT_COMPACTED}
+	}
+	if err := m.index.CheckBlockClockSkew(block, cmd.AppendedAt); err != nil {
+		return m.blockInvalid(block, err)
+	}
+	return nil
+}
+
+// blockInserted completes the addition of the block, given
+// the result of the block insertion into the index.
+func (m *IndexCommandHandler) blockInserted(tx *bbolt.Tx, cmd *raft.Log, block *metastorev1.BlockMeta, err error) (*metastorev1.AddBlockResponse, error) {
+	if err != nil {
+		var exists *index.BlockExistsError
+		if errors.As(err, &exists) {
+			return m.blockExists(block, exists.Block), nil
+		}
+		if errors.Is(err, index.ErrInvalidBlock) {
+			return m.blockInvalid(block, err), nil
+		}
+		level.Error(m.logger).Log("msg", "failed to add block to index", "block_id", block.Id)
+		return nil, err
+	}
+	if err = m.compactor.Compact(tx, cmd, block); err != nil {
+		level.Error(m.logger).Log("msg", "failed to add block to compaction", "block", block.Id, "err", err)
+		return nil, err
+	}
+	if err = m.events.BlockAdded(tx, cmd, block); err != nil {
+		level.Error(m.logger).Log("msg", "failed to record block event", "block", block.Id, "err", err)
+		return nil, err
+	}
+	m.cardinality.observe(block, cmd.AppendedAt)
+	return &metastorev1.AddBlockResponse{Result: metastorev1.AddBlockResult_ADD_BLOCK_RESULT_ADDED}, nil
+}
+
+// QuarantineBlock excludes the block from query results and compaction
+// inputs. A nil block in the response indicates that the block is not
+// found: e.g., it might have been compacted since it was reported.
+func (m *IndexCommandHandler) QuarantineBlock(tx *bbolt.Tx, cmd *raft.Log, req *raft_log.QuarantineBlockRequest) (*raft_log.QuarantineBlockResponse, error) {
+	block, err := m.index.QuarantineBlock(tx, req.Shard, req.TenantId, req.BlockId, &metastorev1.BlockQuarantine{
+		QuarantinedAt: cmd.AppendedAt.UnixMilli(),
+		Reason:        req.Reason,
+		ReportedBy:    req.ReportedBy,
+	})
+	if err != nil {
+		level.Error(m.logger).Log("msg", "failed to quarantine block", "block_id", req.BlockId, "err", err)
+		return nil, err
+	}
+	if block == nil {
+		level.Warn(m.logger).Log("msg", "block to quarantine not found", "block_id", req.BlockId, "shard", req.Shard, "tenant", req.TenantId)
+		return new(raft_log.QuarantineBlockResponse), nil
+	}
+	if err = m.events.BlockQuarantined(tx, cmd, block); err != nil {
+		level.Error(m.logger).Log("msg", "failed to record block event", "block_id", req.BlockId, "err", err)
+		return nil, err
+	}
+	level.Warn(m.logger).Log(
+		"msg", "block quarantined",
+		"block_id", req.BlockId,
+		"shard", req.Shard,
+		"tenant", req.TenantId,
+		"reason", block.Quarantine.Reason,
+		"reported_by", block.Quarantine.ReportedBy,
+	)
+	return &raft_log.QuarantineBlockResponse{Block: block.CloneVT()}, nil
+}
+
+// ArchivePartition replaces the index partition with its archive, which
+// has been uploaded to the object storage by the leader. The partition is
+// not archived if it has changed since the archive was created.
+func (m *IndexCommandHandler) ArchivePartition(tx *bbolt.Tx, _ *raft.Log, req *raft_log.ArchivePartitionRequest) (*raft_log.ArchivePartitionResponse, error) {
+	archived, err := m.index.ArchivePartition(tx, req.Partition)
+	if err != nil {
+		level.Error(m.logger).Log("msg", "failed to archive partition", "partition", req.Partition.GetKey(), "err", err)
+		return nil, err
+	}
+	if archived {
+		level.Info(m.logger).Log(
+			"msg", "index partition archived",
+			"partition", req.Partition.Key,
+			"path", req.Partition.Path,
+			"blocks", req.Partition.Blocks,
+		)
+	}
+	return &raft_log.ArchivePartitionResponse{Archived: archived}, nil
+}
+
+// MergePartitions moves the blocks of the small index partitions to
+// the partition that covers their time periods.
+func (m *IndexCommandHandler) MergePartitions(tx *bbolt.Tx, _ *raft.Log, req *raft_log.MergePartitionsRequest) (*raft_log.MergePartitionsResponse, error) {
+	resp, err := m.index.MergePartitions(tx, req)
+	if err != nil {
+		level.Error(m.logger).Log("msg", "failed to merge partitions", "partition", req.Target, "err", err)
+		return nil, err
+	}
+	if len(resp.Merged) > 0 {
+		level.Info(m.logger).Log(
+			"msg", "index partitions merged",
+			"partition", req.Target,
+			"merged", strings.Join(resp.Merged, ","),
+			"blocks", resp.Blocks,
+		)
+	}
+	return resp, nil
+}
+
+// SplitPartition moves the blocks of the long index partition
+// to the partitions of the requested duration.
+func (m *IndexCommandHandler) SplitPartition(tx *bbolt.Tx, _ *raft.Log, req *raft_log.SplitPartitionRequest) (*raft_log.SplitPartitionResponse, error) {
+	resp, err := m.index.SplitPartition(tx, req)
+	if err != nil {
+		level.Error(m.logger).Log("msg", "failed to split partition", "partition", req.Source, "err", err)
+		return nil, err
+	}
+	if len(resp.Target) > 0 {
+		level.Info(m.logger).Log(
+			"msg", "index partition split",
+			"partition", req.Source,
+			"target", strings.Join(resp.Target, ","),
+			"blocks", resp.Blocks,
+		)
+	}
+	return resp, nil
+}
+
+// SweepDeletedBlocks removes the blocks marked as deleted
+// from the index once the grace period has passed, and adds
+// the tombstones of the symbols dictionaries released.
+func (m *IndexCommandHandler) SweepDeletedBlocks(tx *bbolt.Tx, cmd *raft.Log, req *raft_log.SweepDeletedBlocksRequest) (*raft_log.SweepDeletedBlocksResponse, error) {
+	resp, released, err := m.index.SweepDeletedBlocks(tx, req, cmd.AppendedAt)
+	if err != nil {
+		level.Error(m.logger).Log("msg", "failed to sweep deleted blocks", "err", err)
+		return nil, err
+	}
+	if err = m.addDictionaryTombstones(tx, cmd, released); err != nil {
+		return nil, err
+	}
+	if resp.Removed > 0 {
+		level.Debug(m.logger).Log("msg", "deleted blocks removed from index", "blocks", resp.Removed)
+	}
+	return resp, nil
+}
+
+// DeletePartitions removes the blocks of the tenant from the index
+// partitions older than the retention period, and adds the tombstones
+// of the removed blocks, of the archived partitions deleted, and of the
+// symbols dictionaries released: their objects are deleted by the
+// compaction workers, the same way as the objects of compacted blocks.
+func (m *IndexCommandHandler) DeletePartitions(tx *bbolt.Tx, cmd *raft.Log, req *raft_log.DeletePartitionsRequest) (*raft_log.DeletePartitionsResponse, error) {
+	deleted, err := m.index.DeletePartitionsBefore(tx, time.UnixMilli(req.Before), req.TenantId, cmd.AppendedAt)
+	if err != nil {
+		level.Error(m.logger).Log("msg", "failed to delete partitions", "tenant", req.TenantId, "err", err)
+		return nil, err
+	}
+	resp := &raft_log.DeletePartitionsResponse{
+		Deleted: make([]string, len(deleted.Partitions)),
+		Blocks:  uint32(len(deleted.Blocks)),
+	}
+	tombstones := make([]*metastorev1.Tombstones, 0)
+	for _, g := range retentionTombstones(cmd, deleted.Blocks) {
+		if err = m.tombstones.AddTombstones(tx, cmd, g.tombstones, g.blocks...); err != nil {
+			level.Error(m.logger).Log("msg", "failed to add tombstones", "err", err)
+			return nil, err
 		}
-		level.Error(m.logger).Log("msg", "failed to add block to index", "block_id", req.Block.Id)
+		tombstones = append(tombstones, g.tombstones)
+	}
+	for _, a := range deleted.Archives {
+		t := &metastorev1.Tombstones{Archives: &metastorev1.ArchiveTombstones{
+			Name:      fmt.Sprintf("retention-%d-%s", cmd.Index, a.Key),
+			Partition: a.Key,
+			Path:      a.Path,
+		}}
+		if err = m.tombstones.AddTombstones(tx, cmd, t); err != nil {
+			level.Error(m.logger).Log("msg", "failed to add tombstones", "err", err)
+			return nil, err
+		}
+	}
+	if err = m.addDictionaryTombstones(tx, cmd, deleted.Dictionaries); err != nil {
+		return nil, err
+	}
+	if err = m.events.RetentionApplied(tx, cmd, tombstones, deleted.Archives); err != nil {
+		level.Error(m.logger).Log("msg", "failed to record retention events", "err", err)
 		return nil, err
 	}
-	if err := m.compactor.Compact(tx, cmd, req.Block); err != nil {
-		level.Error(m.logger).Log("msg", "failed to add block to compaction", "block", req.Block.Id, "err", err)
+	for j, k := range deleted.Partitions {
+		resp.Deleted[j] = string(k)
+	}
+	if resp.Blocks > 0 || len(resp.Deleted) > 0 {
+		level.Info(m.logger).Log(
+			"msg", "expired index partitions deleted",
+			"tenant", req.TenantId,
+			"before", time.UnixMilli(req.Before).UTC().Format(time.RFC3339),
+			"deleted", strings.Join(resp.Deleted, ","),
+			"blocks", resp.Blocks,
+		)
+	}
+	return resp, nil
+}
+
+// RepairIndex checks the index against the database, and resolves the
+// issues found on the replica. The issues found are returned.
+func (m *IndexCommandHandler) RepairIndex(tx *bbolt.Tx, _ *raft.Log, _ *raft_log.RepairIndexRequest) (*raft_log.RepairIndexResponse, error) {
+	report, err := m.index.Repair(tx)
+	if err != nil {
+		level.Error(m.logger).Log("msg", "failed to repair index", "err", err)
 		return nil, err
 	}
-	return &metastorev1.AddBlockResponse{}, nil
+	return &raft_log.RepairIndexResponse{
+		Issues:     report.Issues,
+		Partitions: report.Partitions,
+		Blocks:     report.Blocks,
+	}, nil
+}
+
+type retentionGroup struct {
+	tombstones *metastorev1.Tombstones
+	blocks     []*metastorev1.BlockMeta
+}
+
+// retentionTombstones groups the removed blocks by shard, tenant,
+// and compaction level, which determine the block object paths.
+func retentionTombstones(cmd *raft.Log, blocks []*metastorev1.BlockMeta) []*retentionGroup {
+	type groupKey struct {
+		shard  uint32
+		tenant string
+		level  uint32
+	}
+	byKey := make(map[groupKey]*retentionGroup)
+	groups := make([]*retentionGroup, 0)
+	for _, md := range blocks {
+		k := groupKey{shard: md.Shard, tenant: md.TenantId, level: md.CompactionLevel}
+		g, ok := byKey[k]
+		if !ok {
+			g = &retentionGroup{tombstones: &metastorev1.Tombstones{
+				Blocks: &metastorev1.BlockTombstones{
+					Name:            fmt.Sprintf("retention-%d-%d-%s-%d", cmd.Index, k.shard, k.tenant, k.level),
+					Shard:           k.shard,
+					Tenant:          k.tenant,
+					CompactionLevel: k.level,
+				},
+			}}
+			byKey[k] = g
+			groups = append(groups, g)
+		}
+		g.tombstones.Blocks.Blocks = append(g.tombstones.Blocks.Blocks, block.ObjectID(md))
+		g.blocks = append(g.blocks, md)
+	}
+	return groups
+}
+
+func (m *IndexCommandHandler) addDictionaryTombstones(tx *bbolt.Tx, cmd *raft.Log, released []index.ReleasedDictionary) error {
+	for _, t := range dictionaryTombstones(cmd, released) {
+		if err := m.tombstones.AddTombstones(tx, cmd, t); err != nil {
+			level.Error(m.logger).Log("msg", "failed to add tombstones", "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// dictionaryTombstones groups the released dictionaries by shard and tenant.
+func dictionaryTombstones(cmd *raft.Log, released []index.ReleasedDictionary) []*metastorev1.Tombstones {
+	type groupKey struct {
+		shard  uint32
+		tenant string
+	}
+	byKey := make(map[groupKey]*metastorev1.DictionaryTombstones)
+	tombstones := make([]*metastorev1.Tombstones, 0)
+	for _, d := range released {
+		k := groupKey{shard: d.Shard, tenant: d.Tenant}
+		t, ok := byKey[k]
+		if !ok {
+			t = &metastorev1.DictionaryTombstones{
+				Name:   fmt.Sprintf("dictionary-%d-%d-%s", cmd.Index, k.shard, k.tenant),
+				Shard:  k.shard,
+				Tenant: k.tenant,
+			}
+			byKey[k] = t
+			tombstones = append(tombstones, &metastorev1.Tombstones{Dictionaries: t})
+		}
+		t.Paths = append(t.Paths, d.Path)
+	}
+	return tombstones
+}
+
+// blockInvalid rejects the block metadata. The command must
+// not fail: the state is left intact.
+func (m *IndexCommandHandler) blockInvalid(block *metastorev1.BlockMeta, err error) *metastorev1.AddBlockResponse {
+	level.Warn(m.logger).Log("msg", "rejecting invalid block metadata", "block_id", block.Id, "err", err)
+	return &metastorev1.AddBlockResponse{
+		Result:        metastorev1.AddBlockResult_ADD_BLOCK_RESULT_INVALID,
+		InvalidReason: err.Error(),
+	}
+}
+
+// blockExists tells a retry from a duplicate by the idempotency key.
+// Note that the command must not fail: the caller decides whether
+// the duplicate is an error.
+func (m *IndexCommandHandler) blockExists(block, existing *metastorev1.BlockMeta) *metastorev1.AddBlockResponse {
+	if block.IdempotencyKey != nil && block.IdempotencyKey.EqualVT(existing.IdempotencyKey) {
+		level.Debug(m.logger).Log(
+			"msg", "block already added by a previous attempt",
+			"block_id", block.Id,
+			"writer_id", block.IdempotencyKey.WriterId,
+			"sequence", block.IdempotencyKey.Sequence,
+		)
+		return &metastorev1.AddBlockResponse{Result: metastorev1.AddBlockResult_ADD_BLOCK_RESULT_RETRY}
+	}
+	level.Warn(m.logger).Log(
+		"msg", "block already added",
+		"block_id", block.Id,
+		"shard", block.Shard,
+		"tenant", block.TenantId,
+		"compaction_level", block.CompactionLevel,
+		"created_by", block.CreatedBy,
+		"idempotency_key", formatIdempotencyKey(block.IdempotencyKey),
+		"existing_created_by", existing.CreatedBy,
+		"existing_idempotency_key", formatIdempotencyKey(existing.IdempotencyKey),
+	)
+	return &metastorev1.AddBlockResponse{
+		Result:        metastorev1.AddBlockResult_ADD_BLOCK_RESULT_DUPLICATE,
+		ExistingBlock: existing.CloneVT(),
+	}
+}
+
+// stampBlock records the raft log entry that adds the block to the index:
+// as-of queries exclude the blocks added after the state queried.
+func stampBlock(b *metastorev1.BlockMeta, cmd *raft.Log) {
+	b.AddedAtIndex = cmd.Index
+	b.AddedAt = cmd.AppendedAt.UnixMilli()
+}
+
+func formatIdempotencyKey(k *metastorev1.IdempotencyKey) string {
+	if k == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%s/%d", k.WriterId, k.Sequence)
 }
@@ -0,0 +1,326 @@
+package metastore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/thanos-io/objstore"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+)
+
+// rebuildBatchSize limits the number of blocks registered in a raft command.
+const rebuildBatchSize = 100
+
+// maxRebuildConcurrency caps the concurrency requested by the caller.
+const maxRebuildConcurrency = 256
+
+var ErrIndexRebuildInProgress = errors.New("index rebuild is already in progress")
+
+type LeaderRaft interface {
+	Raft
+	VerifyLeader() error
+}
+
+// IndexRebuilder registers the blocks found in the object storage, which
+// restores the index if the metastore database has been lost. The block
+// metadata is read from the block objects: the objects created before the
+// metadata was stored in them can't be registered.
+//
+// The rebuild is started on demand, and only runs on the raft leader. The
+// blocks are registered through the raft log, the same way as they are
+// added by writers; the blocks already present in the index are skipped,
+// therefore an interrupted rebuild can be started over. The clock skew
+// check, if enabled, applies to the blocks too.
+//
+// The tombstones are lost along with the database: the objects of the
+// compaction source blocks may not have been deleted yet. The compacted
+// blocks list their sources, therefore the blocks found are registered
+// once the scan completes, except for those compacted into another block
+// found. Note that only the direct sources are known: a block is still
+// registered if the block it has been compacted into has been compacted
+// and deleted already.
+type IndexRebuilder struct {
+	config index.Config
+	logger log.Logger
+	raft   LeaderRaft
+	bucket objstore.Bucket
+
+	m       sync.Mutex
+	started bool
+	cancel  func()
+	status  *metastorev1.IndexRebuildStatus
+}
+
+func NewIndexRebuilder(
+	logger log.Logger,
+	config index.Config,
+	raft LeaderRaft,
+	bucket objstore.Bucket,
+) *IndexRebuilder {
+	return &IndexRebuilder{
+		config: config,
+		logger: logger,
+		raft:   raft,
+		bucket: bucket,
+		status: new(metastorev1.IndexRebuildStatus),
+	}
+}
+
+func (r *IndexRebuilder) Start() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.started = true
+}
+
+func (r *IndexRebuilder) Stop() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.started = false
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// Rebuild starts the index rebuild in the background.
+func (r *IndexRebuilder) Rebuild(concurrency int) (*metastorev1.IndexRebuildStatus, error) {
+	if err := r.raft.VerifyLeader(); err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 {
+		concurrency = r.config.RebuildConcurrency
+	}
+	concurrency = max(1, min(concurrency, maxRebuildConcurrency))
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.started {
+		return nil, status.Error(codes.Unavailable, "index rebuilder is not running")
+	}
+	if r.status.Running {
+		return nil, ErrIndexRebuildInProgress
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.status = &metastorev1.IndexRebuildStatus{
+		Running:   true,
+		StartedAt: time.Now().UnixMilli(),
+	}
+	go r.rebuild(ctx, concurrency)
+	level.Info(r.logger).Log("msg", "index rebuild started", "concurrency", concurrency)
+	return r.status.CloneVT(), nil
+}
+
+// Status returns the progress of the last index rebuild.
+func (r *IndexRebuilder) Status() (*metastorev1.IndexRebuildStatus, error) {
+	if err := r.raft.VerifyLeader(); err != nil {
+		return nil, err
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.status.CloneVT(), nil
+}
+
+func (r *IndexRebuilder) update(fn func(*metastorev1.IndexRebuildStatus)) {
+	r.m.Lock()
+	fn(r.status)
+	r.m.Unlock()
+}
+
+func (r *IndexRebuilder) rebuild(ctx context.Context, concurrency int) {
+	g, scanCtx := errgroup.WithContext(ctx)
+	paths := make(chan string)
+	g.Go(func() error {
+		defer close(paths)
+		return r.listObjects(scanCtx, paths)
+	})
+
+	blocks := make(chan *metastorev1.BlockMeta)
+	var readers sync.WaitGroup
+	readers.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		g.Go(func() error {
+			defer readers.Done()
+			for path := range paths {
+				md := r.readMetadata(scanCtx, path)
+				if md == nil {
+					continue
+				}
+				select {
+				case blocks <- md:
+				case <-scanCtx.Done():
+					return scanCtx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	go func() {
+		readers.Wait()
+		close(blocks)
+	}()
+
+	var found []*metastorev1.BlockMeta
+	g.Go(func() error {
+		for md := range blocks {
+			found = append(found, md)
+		}
+		return nil
+	})
+
+	err := g.Wait()
+	if err == nil {
+		err = r.registerBlocks(ctx, found)
+	}
+	r.update(func(s *metastorev1.IndexRebuildStatus) {
+		s.Running = false
+		s.FinishedAt = time.Now().UnixMilli()
+		if err != nil {
+			s.Error = err.Error()
+		}
+	})
+	if err != nil {
+		level.Error(r.logger).Log("msg", "index rebuild failed", "err", err)
+		return
+	}
+	level.Info(r.logger).Log("msg", "index rebuild completed")
+}
+
+// listObjects sends the paths of the block objects found in the bucket.
+func (r *IndexRebuilder) listObjects(ctx context.Context, paths chan<- string) error {
+	for _, dir := range []string{block.DirPathSegment, block.DirPathBlock} {
+		err := r.bucket.Iter(ctx, dir, func(path string) error {
+			if !strings.HasSuffix(path, "/"+block.FileNameDataObject) {
+				return nil
+			}
+			r.update(func(s *metastorev1.IndexRebuildStatus) { s.ObjectsScanned++ })
+			select {
+			case paths <- path:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, objstore.WithRecursiveIter)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMetadata returns the metadata of the block object at the given
+// path, or nil, if the block can't be registered.
+func (r *IndexRebuilder) readMetadata(ctx context.Context, path string) *metastorev1.BlockMeta {
+	md, err := block.ReadMetadata(ctx, r.bucket, path)
+	switch {
+	case err == nil:
+	case errors.Is(err, block.ErrNoMetadata):
+		level.Debug(r.logger).Log("msg", "block object has no metadata", "path", path, "err", err)
+		r.update(func(s *metastorev1.IndexRebuildStatus) { s.ObjectsWithoutMetadata++ })
+		return nil
+	case r.bucket.IsObjNotFoundErr(err):
+		// The object has been deleted since it was listed, e.g., compacted.
+		r.update(func(s *metastorev1.IndexRebuildStatus) { s.BlocksSkipped++ })
+		return nil
+	default:
+		if ctx.Err() == nil {
+			level.Warn(r.logger).Log("msg", "failed to read block metadata", "path", path, "err", err)
+			r.update(func(s *metastorev1.IndexRebuildStatus) { s.BlocksFailed++ })
+		}
+		return nil
+	}
+	if block.ObjectPath(md) != path {
+		level.Warn(r.logger).Log("msg", "block metadata does not match the object path", "path", path, "block_id", md.Id)
+		r.update(func(s *metastorev1.IndexRebuildStatus) { s.BlocksFailed++ })
+		return nil
+	}
+	return md
+}
+
+// registerBlocks adds the blocks to the index in batches, skipping
+// the blocks compacted into any of the blocks found.
+func (r *IndexRebuilder) registerBlocks(ctx context.Context, blocks []*metastorev1.BlockMeta) error {
+	compacted := make(map[string]struct{})
+	for _, md := range blocks {
+		for _, id := range md.SourceBlocks {
+			compacted[id] = struct{}{}
+		}
+	}
+	batch := make([]*metastorev1.BlockMeta, 0, rebuildBatchSize)
+	for _, md := range blocks {
+		if _, ok := compacted[md.Id]; ok {
+			level.Debug(r.logger).Log("msg", "skipping compacted block", "block_id", md.Id)
+			r.update(func(s *metastorev1.IndexRebuildStatus) { s.BlocksSkipped++ })
+			continue
+		}
+		if batch = append(batch, md); len(batch) < rebuildBatchSize {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.register(batch); err != nil {
+			return err
+		}
+		batch = make([]*metastorev1.BlockMeta, 0, rebuildBatchSize)
+	}
+	return r.register(batch)
+}
+
+// register adds the blocks to the index. Only the raft leadership
+// errors are returned: the rebuild can't continue without it.
+func (r *IndexRebuilder) register(blocks []*metastorev1.BlockMeta) error {
+	valid := make([]*metastorev1.BlockMeta, 0, len(blocks))
+	for _, md := range blocks {
+		if err := SanitizeMetadata(md); err != nil {
+			level.Warn(r.logger).Log("msg", "invalid block metadata", "block_id", md.Id, "err", err)
+			r.update(func(s *metastorev1.IndexRebuildStatus) { s.BlocksFailed++ })
+			continue
+		}
+		valid = append(valid, md)
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+	results, err := proposeAddBlocks(r.raft, valid)
+	if err != nil {
+		if raftnode.IsRaftLeadershipError(err) {
+			return err
+		}
+		level.Error(r.logger).Log("msg", "failed to add blocks", "blocks", len(valid), "err", err)
+		r.update(func(s *metastorev1.IndexRebuildStatus) { s.BlocksFailed += uint64(len(valid)) })
+		return nil
+	}
+	r.update(func(s *metastorev1.IndexRebuildStatus) {
+		for k, resp := range results {
+			switch resp.Result {
+			case metastorev1.AddBlockResult_ADD_BLOCK_RESULT_ADDED:
+				s.BlocksAdded++
+			case metastorev1.AddBlockResult_ADD_BLOCK_RESULT_RETRY,
+				metastorev1.AddBlockResult_ADD_BLOCK_RESULT_DUPLICATE,
+				metastorev1.AddBlockResult_ADD_BLOCK_RESULT_COMPACTED:
+				s.BlocksSkipped++
+			default:
+				level.Warn(r.logger).Log(
+					"msg", "block not registered",
+					"block_id", valid[k].Id,
+					"result", resp.Result,
+					"reason", resp.InvalidReason+resp.RejectedReason,
+				)
+				s.BlocksFailed++
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,55 @@
+package metastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+type addBlocksRaft struct {
+	LeaderRaft
+	added []string
+}
+
+func (r *addBlocksRaft) Propose(_ fsm.RaftLogEntryType, req proto.Message) (proto.Message, error) {
+	resp := new(metastorev1.AddBlocksResponse)
+	for _, b := range req.(*metastorev1.AddBlocksRequest).Blocks {
+		r.added = append(r.added, b.Id)
+		resp.Results = append(resp.Results, &metastorev1.AddBlockResponse{
+			Result: metastorev1.AddBlockResult_ADD_BLOCK_RESULT_ADDED,
+		})
+	}
+	return resp, nil
+}
+
+func Test_IndexRebuilder_SkipsCompactedBlocks(t *testing.T) {
+	raft := new(addBlocksRaft)
+	r := NewIndexRebuilder(log.NewNopLogger(), index.DefaultConfig, raft, nil)
+
+	source := []string{
+		test.ULID("2024-09-23T08:00:00.000Z"),
+		test.ULID("2024-09-23T08:00:01.000Z"),
+	}
+	compacted := test.ULID("2024-09-23T08:00:02.000Z")
+	other := test.ULID("2024-09-23T08:00:03.000Z")
+	blocks := []*metastorev1.BlockMeta{
+		{Id: source[0]},
+		{Id: compacted, CompactionLevel: 1, SourceBlocks: source},
+		{Id: source[1]},
+		{Id: other},
+	}
+	require.NoError(t, r.registerBlocks(context.Background(), blocks))
+
+	assert.Equal(t, []string{compacted, other}, raft.added)
+	assert.Equal(t, uint64(2), r.status.BlocksAdded)
+	assert.Equal(t, uint64(2), r.status.BlocksSkipped)
+}
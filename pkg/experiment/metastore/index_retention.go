@@ -0,0 +1,117 @@
+package metastore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+type PartitionRetentionSource interface {
+	PartitionsToDelete(tx *bbolt.Tx, now time.Time) []*raft_log.DeletePartitionsRequest
+}
+
+// PartitionRetention deletes the index partitions older than the retention
+// period of their tenants. It only runs on the raft leader: the retention
+// threshold is carried in the command, so all the replicas remove the same
+// blocks. The block objects are deleted by the compaction workers, once
+// the tombstones of the removed blocks are added.
+type PartitionRetention struct {
+	config index.Config
+	logger log.Logger
+	raft   Raft
+	state  State
+	index  PartitionRetentionSource
+
+	m       sync.Mutex
+	started bool
+	cancel  func()
+}
+
+func NewPartitionRetention(
+	logger log.Logger,
+	config index.Config,
+	raft Raft,
+	state State,
+	index PartitionRetentionSource,
+) *PartitionRetention {
+	return &PartitionRetention{
+		config: config,
+		logger: logger,
+		raft:   raft,
+		state:  state,
+		index:  index,
+	}
+}
+
+func (r *PartitionRetention) Start() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.config.PartitionRetentionInterval <= 0 || r.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.started = true
+	go r.loop(ctx)
+	level.Info(r.logger).Log("msg", "partition retention started")
+}
+
+func (r *PartitionRetention) Stop() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.started {
+		return
+	}
+	r.cancel()
+	r.started = false
+	level.Info(r.logger).Log("msg", "partition retention stopped")
+}
+
+func (r *PartitionRetention) loop(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PartitionRetentionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.enforce(ctx)
+		}
+	}
+}
+
+func (r *PartitionRetention) enforce(ctx context.Context) {
+	var requests []*raft_log.DeletePartitionsRequest
+	err := r.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		requests = r.index.PartitionsToDelete(tx, time.Now())
+	})
+	if err != nil {
+		if !raftnode.IsRaftLeadershipError(err) {
+			level.Error(r.logger).Log("msg", "failed to find expired partitions", "err", err)
+		}
+		return
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_DELETE_PARTITIONS)
+	// The partitions are deleted one tenant at a time,
+	// which limits the size of the transaction.
+	for _, req := range requests {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := r.raft.Propose(cmd, req); err != nil {
+			if raftnode.IsRaftLeadershipError(err) {
+				return
+			}
+			level.Error(r.logger).Log("msg", "failed to delete expired partitions", "tenant", req.TenantId, "err", err)
+		}
+	}
+}
@@ -2,17 +2,26 @@ package metastore
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/oklog/ulid"
 	"go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	placement "github.com/grafana/pyroscope/pkg/experiment/distributor/placement/adaptive_placement"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 	"github.com/grafana/pyroscope/pkg/iter"
 )
 
@@ -20,36 +29,68 @@ type PlacementStats interface {
 	RecordStats(iter.Iterator[placement.Sample])
 }
 
+type IndexRebuild interface {
+	Rebuild(concurrency int) (*metastorev1.IndexRebuildStatus, error)
+	Status() (*metastorev1.IndexRebuildStatus, error)
+}
+
+type WriterQuarantine interface {
+	Quarantined(writer string) *metastorev1.WriterQuarantine
+	ListQuarantinedWriters(*bbolt.Tx) ([]*metastorev1.WriterQuarantine, error)
+}
+
 func NewIndexService(
 	logger log.Logger,
+	config WriterRateLimitConfig,
 	raft Raft,
 	state State,
 	index IndexQuerier,
+	writers WriterQuarantine,
 	stats PlacementStats,
+	rebuild IndexRebuild,
+	check IndexCheck,
 ) *IndexService {
 	return &IndexService{
-		logger: logger,
-		raft:   raft,
-		state:  state,
-		index:  index,
-		stats:  stats,
+		logger:  logger,
+		raft:    raft,
+		state:   state,
+		index:   index,
+		writers: writers,
+		limiter: newWriterRateLimiter(config),
+		stats:   stats,
+		rebuild: rebuild,
+		check:   check,
 	}
 }
 
 type IndexService struct {
 	metastorev1.IndexServiceServer
 
-	logger log.Logger
-	raft   Raft
-	state  State
-	index  IndexQuerier
-	stats  PlacementStats
+	logger  log.Logger
+	raft    Raft
+	state   State
+	index   IndexQuerier
+	writers WriterQuarantine
+	limiter *writerRateLimiter
+	stats   PlacementStats
+	rebuild IndexRebuild
+	check   IndexCheck
 }
 
 func (svc *IndexService) AddBlock(
 	ctx context.Context,
 	req *metastorev1.AddBlockRequest,
 ) (rsp *metastorev1.AddBlockResponse, err error) {
+	// Only blocks registered by writers directly are rate limited:
+	// the recovered and external blocks are added at our own pace.
+	if ok, retryAfter := svc.limiter.allow(req.Block.GetCreatedBy(), time.Now()); !ok {
+		level.Debug(svc.logger).Log("msg", "block rate limit exceeded", "block_id", req.Block.GetId(), "writer", req.Block.GetCreatedBy())
+		return nil, blockRejectedError(codes.ResourceExhausted, &metastorev1.AddBlockResponse{
+			Result:         metastorev1.AddBlockResult_ADD_BLOCK_RESULT_RATE_LIMITED,
+			RejectedReason: fmt.Sprintf("writer %s exceeded the block rate limit", req.Block.GetCreatedBy()),
+			RetryAfter:     retryAfter.Milliseconds(),
+		})
+	}
 	defer func() {
 		if err == nil {
 			svc.stats.RecordStats(statsFromMetadata(req.Block))
@@ -58,6 +99,67 @@ func (svc *IndexService) AddBlock(
 	return svc.addBlockMetadata(ctx, req)
 }
 
+// AddBlocks registers the blocks the same way AddBlock does, with a single
+// raft command. The blocks refused or rejected are reported in the results,
+// instead of failing the request.
+func (svc *IndexService) AddBlocks(
+	_ context.Context,
+	req *metastorev1.AddBlocksRequest,
+) (*metastorev1.AddBlocksResponse, error) {
+	if slices.Contains(req.Blocks, nil) {
+		return nil, status.Error(codes.InvalidArgument, "block metadata is required")
+	}
+	resp := &metastorev1.AddBlocksResponse{Results: make([]*metastorev1.AddBlockResponse, len(req.Blocks))}
+	blocks := make([]*metastorev1.BlockMeta, 0, len(req.Blocks))
+	positions := make([]int, 0, len(req.Blocks))
+	now := time.Now()
+	for j, b := range req.Blocks {
+		if resp.Results[j] = svc.checkBlock(b, now); resp.Results[j] == nil {
+			blocks = append(blocks, b)
+			positions = append(positions, j)
+		}
+	}
+	if len(blocks) == 0 {
+		return resp, nil
+	}
+	results, err := proposeAddBlocks(svc.raft, blocks)
+	if err != nil {
+		_ = level.Error(svc.logger).Log("msg", "failed to add blocks", "blocks", len(blocks), "err", err)
+		return nil, err
+	}
+	for k, r := range results {
+		resp.Results[positions[k]] = r
+		switch r.Result {
+		case metastorev1.AddBlockResult_ADD_BLOCK_RESULT_ADDED,
+			metastorev1.AddBlockResult_ADD_BLOCK_RESULT_RETRY:
+			svc.stats.RecordStats(statsFromMetadata(blocks[k]))
+		}
+	}
+	return resp, nil
+}
+
+// checkBlock returns the response to the block that is not to be
+// proposed, or nil, if the block may be added.
+func (svc *IndexService) checkBlock(md *metastorev1.BlockMeta, now time.Time) *metastorev1.AddBlockResponse {
+	if ok, retryAfter := svc.limiter.allow(md.CreatedBy, now); !ok {
+		return &metastorev1.AddBlockResponse{
+			Result:         metastorev1.AddBlockResult_ADD_BLOCK_RESULT_RATE_LIMITED,
+			RejectedReason: fmt.Sprintf("writer %s exceeded the block rate limit", md.CreatedBy),
+			RetryAfter:     retryAfter.Milliseconds(),
+		}
+	}
+	if err := SanitizeMetadata(md); err != nil {
+		return &metastorev1.AddBlockResponse{
+			Result:        metastorev1.AddBlockResult_ADD_BLOCK_RESULT_INVALID,
+			InvalidReason: err.Error(),
+		}
+	}
+	if q := svc.writers.Quarantined(md.CreatedBy); q != nil {
+		return writerQuarantinedResponse(q)
+	}
+	return nil
+}
+
 func (svc *IndexService) AddRecoveredBlock(
 	ctx context.Context,
 	req *metastorev1.AddBlockRequest,
@@ -65,27 +167,113 @@ func (svc *IndexService) AddRecoveredBlock(
 	return svc.addBlockMetadata(ctx, req)
 }
 
+// AddExternalBlock adds a block produced by an external pipeline.
+func (svc *IndexService) AddExternalBlock(
+	ctx context.Context,
+	req *metastorev1.AddBlockRequest,
+) (*metastorev1.AddBlockResponse, error) {
+	return svc.addBlockMetadata(ctx, req)
+}
+
 func (svc *IndexService) addBlockMetadata(
 	_ context.Context,
 	req *metastorev1.AddBlockRequest,
 ) (*metastorev1.AddBlockResponse, error) {
 	if err := SanitizeMetadata(req.Block); err != nil {
 		_ = level.Warn(svc.logger).Log("invalid metadata", "block_id", req.Block.Id, "err", err)
-		return nil, err
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// The quarantine is checked once again when the block is added:
+	// the check here only prevents the blocks from being proposed.
+	if q := svc.writers.Quarantined(req.Block.CreatedBy); q != nil {
+		return nil, blockRejectedError(codes.FailedPrecondition, writerQuarantinedResponse(q))
 	}
-	if err := proposeAddBlockMetadata(svc.raft, req.Block); err != nil {
+	resp, err := proposeAddBlockMetadata(svc.raft, req.Block)
+	if err != nil {
 		_ = level.Error(svc.logger).Log("msg", "failed to add block", "block_id", req.Block.Id, "err", err)
 		return nil, err
 	}
-	return new(metastorev1.AddBlockResponse), nil
+	switch resp.Result {
+	case metastorev1.AddBlockResult_ADD_BLOCK_RESULT_INVALID:
+		return nil, status.Error(codes.InvalidArgument, resp.InvalidReason)
+	case metastorev1.AddBlockResult_ADD_BLOCK_RESULT_WRITER_QUARANTINED:
+		return nil, blockRejectedError(codes.FailedPrecondition, resp)
+	}
+	// Without the idempotency key, a retry can't be told apart from
+	// a duplicate, therefore duplicates are only reported to writers
+	// that provide the key.
+	if resp.Result == metastorev1.AddBlockResult_ADD_BLOCK_RESULT_DUPLICATE && req.Block.IdempotencyKey != nil {
+		return nil, status.Errorf(codes.AlreadyExists,
+			"block %s already exists: added by %s with idempotency key %s, conflicts with the block of %s with idempotency key %s",
+			req.Block.Id,
+			resp.ExistingBlock.GetCreatedBy(), formatIdempotencyKey(resp.ExistingBlock.GetIdempotencyKey()),
+			req.Block.CreatedBy, formatIdempotencyKey(req.Block.IdempotencyKey))
+	}
+	return resp, nil
+}
+
+func writerQuarantinedResponse(q *metastorev1.WriterQuarantine) *metastorev1.AddBlockResponse {
+	return &metastorev1.AddBlockResponse{
+		Result:         metastorev1.AddBlockResult_ADD_BLOCK_RESULT_WRITER_QUARANTINED,
+		RejectedReason: fmt.Sprintf("writer %s is quarantined: %s", q.WriterId, q.Reason),
+	}
+}
+
+// blockRejectedError returns the status error with the response attached
+// as the details: the writer may inspect the reason and when to retry.
+// The block is not to be registered by the writer once it is refused:
+// the writer falls back to the dead letter queue, and the block is
+// registered by the metastore when it is allowed.
+func blockRejectedError(code codes.Code, resp *metastorev1.AddBlockResponse) error {
+	s := status.New(code, resp.RejectedReason)
+	if d, err := s.WithDetails(resp); err == nil {
+		s = d
+	}
+	return s.Err()
 }
 
-func proposeAddBlockMetadata(raft Raft, md *metastorev1.BlockMeta) error {
-	_, err := raft.Propose(
+// BlockRejectionFromStatusDetails returns the response attached to the
+// error returned by AddBlock, if the block has been refused.
+func BlockRejectionFromStatusDetails(err error) (*metastorev1.AddBlockResponse, bool) {
+	s, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+	for _, d := range s.Details() {
+		if r, ok := d.(*metastorev1.AddBlockResponse); ok {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+func proposeAddBlocks(raft Raft, blocks []*metastorev1.BlockMeta) ([]*metastorev1.AddBlockResponse, error) {
+	resp, err := raft.Propose(
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ADD_BLOCKS),
+		&metastorev1.AddBlocksRequest{Blocks: blocks},
+	)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := resp.(*metastorev1.AddBlocksResponse)
+	if !ok || len(r.Results) != len(blocks) {
+		return nil, fmt.Errorf("unexpected response to %d blocks added", len(blocks))
+	}
+	return r.Results, nil
+}
+
+func proposeAddBlockMetadata(raft Raft, md *metastorev1.BlockMeta) (*metastorev1.AddBlockResponse, error) {
+	resp, err := raft.Propose(
 		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ADD_BLOCK_METADATA),
 		&raft_log.AddBlockMetadataRequest{Metadata: md},
 	)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := resp.(*metastorev1.AddBlockResponse); ok {
+		return r, nil
+	}
+	return new(metastorev1.AddBlockResponse), nil
 }
 
 func (svc *IndexService) GetBlockMetadata(
@@ -99,9 +287,314 @@ func (svc *IndexService) GetBlockMetadata(
 	if err != nil {
 		return nil, err
 	}
+	// Quarantined blocks are not returned: compaction workers only
+	// compact the blocks found, and the quarantined ones stay in place.
+	found = slices.DeleteFunc(found, func(md *metastorev1.BlockMeta) bool {
+		return md.Quarantine != nil
+	})
 	return &metastorev1.GetBlockMetadataResponse{Blocks: found}, nil
 }
 
+func (svc *IndexService) DescribeBlock(
+	ctx context.Context,
+	req *metastorev1.DescribeBlockRequest,
+) (*metastorev1.DescribeBlockResponse, error) {
+	id, err := ulid.Parse(req.BlockId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block id %q: %v", req.BlockId, err)
+	}
+	var resp *metastorev1.DescribeBlockResponse
+	err = svc.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		if md, p := svc.index.FindBlockByID(tx, req.BlockId); md != nil {
+			resp = &metastorev1.DescribeBlockResponse{
+				Block:   md.CloneVT(),
+				Details: describeBlock(md, p),
+			}
+			for _, s := range resp.Details.SourceBlocks {
+				source, _ := svc.index.FindBlockByID(tx, s.Id)
+				s.Present = source != nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if resp == nil {
+		return nil, status.Errorf(codes.NotFound, "block %s not found", id)
+	}
+	return resp, nil
+}
+
+func (svc *IndexService) QuarantineBlock(
+	_ context.Context,
+	req *metastorev1.QuarantineBlockRequest,
+) (*metastorev1.QuarantineBlockResponse, error) {
+	if _, err := ulid.Parse(req.BlockId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block id %q: %v", req.BlockId, err)
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_QUARANTINE_BLOCK)
+	resp, err := svc.raft.Propose(cmd, &raft_log.QuarantineBlockRequest{
+		BlockId:    req.BlockId,
+		Shard:      req.Shard,
+		TenantId:   req.TenantId,
+		Reason:     req.Reason,
+		ReportedBy: req.ReportedBy,
+	})
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to quarantine block", "block_id", req.BlockId, "err", err)
+		return nil, err
+	}
+	md := resp.(*raft_log.QuarantineBlockResponse).GetBlock()
+	if md == nil {
+		return nil, status.Errorf(codes.NotFound, "block %s not found", req.BlockId)
+	}
+	return &metastorev1.QuarantineBlockResponse{Block: md}, nil
+}
+
+func (svc *IndexService) ListQuarantinedBlocks(
+	ctx context.Context,
+	req *metastorev1.ListQuarantinedBlocksRequest,
+) (*metastorev1.ListQuarantinedBlocksResponse, error) {
+	if len(req.TenantId) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+	if req.StartTime > req.EndTime {
+		return nil, status.Error(codes.InvalidArgument, "invalid time range")
+	}
+	tenants := make(map[string]struct{}, len(req.TenantId))
+	for _, t := range req.TenantId {
+		tenants[t] = struct{}{}
+	}
+	var blocks []*metastorev1.BlockMeta
+	err := svc.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		blocks = svc.index.FindQuarantinedBlocksInRange(tx, req.StartTime, req.EndTime, tenants)
+	})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	// Blocks of multiple tenants may be found more than once.
+	slices.SortFunc(blocks, func(a, b *metastorev1.BlockMeta) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	blocks = slices.CompactFunc(blocks, func(a, b *metastorev1.BlockMeta) bool {
+		return a.Id == b.Id
+	})
+	return &metastorev1.ListQuarantinedBlocksResponse{Blocks: blocks}, nil
+}
+
+func (svc *IndexService) QuarantineWriter(
+	_ context.Context,
+	req *metastorev1.QuarantineWriterRequest,
+) (*metastorev1.QuarantineWriterResponse, error) {
+	if req.WriterId == "" {
+		return nil, status.Error(codes.InvalidArgument, "writer_id is required")
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_QUARANTINE_WRITER)
+	resp, err := svc.raft.Propose(cmd, &raft_log.QuarantineWriterRequest{
+		WriterId:   req.WriterId,
+		Reason:     req.Reason,
+		ReportedBy: req.ReportedBy,
+	})
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to quarantine writer", "writer", req.WriterId, "err", err)
+		return nil, err
+	}
+	q := resp.(*raft_log.QuarantineWriterResponse).GetQuarantine()
+	return &metastorev1.QuarantineWriterResponse{Quarantine: q}, nil
+}
+
+func (svc *IndexService) ReleaseWriter(
+	_ context.Context,
+	req *metastorev1.ReleaseWriterRequest,
+) (*metastorev1.ReleaseWriterResponse, error) {
+	if req.WriterId == "" {
+		return nil, status.Error(codes.InvalidArgument, "writer_id is required")
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_RELEASE_WRITER)
+	resp, err := svc.raft.Propose(cmd, &raft_log.ReleaseWriterRequest{WriterId: req.WriterId})
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to release writer", "writer", req.WriterId, "err", err)
+		return nil, err
+	}
+	released := resp.(*raft_log.ReleaseWriterResponse).GetReleased()
+	return &metastorev1.ReleaseWriterResponse{Released: released}, nil
+}
+
+func (svc *IndexService) ListQuarantinedWriters(
+	ctx context.Context,
+	_ *metastorev1.ListQuarantinedWritersRequest,
+) (*metastorev1.ListQuarantinedWritersResponse, error) {
+	var writers []*metastorev1.WriterQuarantine
+	var err error
+	readErr := svc.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		writers, err = svc.writers.ListQuarantinedWriters(tx)
+	})
+	if readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to list quarantined writers", "err", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &metastorev1.ListQuarantinedWritersResponse{Writers: writers}, nil
+}
+
+// Page size of ListBlocks, if not specified, and its upper limit.
+const (
+	defaultListBlocksPageSize = 100
+	maxListBlocksPageSize     = 1000
+)
+
+func (svc *IndexService) ListBlocks(
+	ctx context.Context,
+	req *metastorev1.ListBlocksRequest,
+) (*metastorev1.ListBlocksResponse, error) {
+	if req.StartTime > req.EndTime {
+		return nil, status.Error(codes.InvalidArgument, "invalid time range")
+	}
+	var after *index.BlockCursor
+	if req.PageToken != "" {
+		c, err := index.ParseBlockCursor(req.PageToken)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		after = &c
+	}
+	limit := int(req.PageSize)
+	if limit == 0 {
+		limit = defaultListBlocksPageSize
+	}
+	limit = min(limit, maxListBlocksPageSize)
+	var blocks []*metastorev1.BlockMeta
+	var next *index.BlockCursor
+	var err error
+	readErr := svc.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		blocks, next, err = svc.index.ListBlocks(tx, req.TenantId, req.StartTime, req.EndTime, after, limit)
+	})
+	if readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to list blocks", "tenant", req.TenantId, "err", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &metastorev1.ListBlocksResponse{Blocks: blocks}
+	if next != nil {
+		resp.NextPageToken = next.String()
+	}
+	return resp, nil
+}
+
+func (svc *IndexService) RebuildIndex(
+	_ context.Context,
+	req *metastorev1.RebuildIndexRequest,
+) (*metastorev1.RebuildIndexResponse, error) {
+	s, err := svc.rebuild.Rebuild(int(req.Concurrency))
+	if err != nil {
+		if errors.Is(err, ErrIndexRebuildInProgress) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	return &metastorev1.RebuildIndexResponse{Status: s}, nil
+}
+
+func (svc *IndexService) GetIndexRebuildStatus(
+	context.Context,
+	*metastorev1.GetIndexRebuildStatusRequest,
+) (*metastorev1.GetIndexRebuildStatusResponse, error) {
+	s, err := svc.rebuild.Status()
+	if err != nil {
+		return nil, err
+	}
+	return &metastorev1.GetIndexRebuildStatusResponse{Status: s}, nil
+}
+
+func (svc *IndexService) ListPartitions(
+	ctx context.Context,
+	req *metastorev1.ListPartitionsRequest,
+) (*metastorev1.ListPartitionsResponse, error) {
+	if req.StartTime > req.EndTime {
+		return nil, status.Error(codes.InvalidArgument, "invalid time range")
+	}
+	var partitions []*metastorev1.PartitionDetails
+	readErr := svc.state.ConsistentRead(ctx, func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		partitions = svc.index.ListPartitions(tx, req.TenantId, req.StartTime, req.EndTime)
+	})
+	if readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	return &metastorev1.ListPartitionsResponse{Partitions: partitions}, nil
+}
+
+func (svc *IndexService) CheckIndex(
+	ctx context.Context,
+	req *metastorev1.CheckIndexRequest,
+) (*metastorev1.CheckIndexResponse, error) {
+	return svc.check.Check(ctx, req)
+}
+
+// The maximum number of changes sent in a WatchBlocks response.
+const maxWatchBlocksChanges = 100
+
+// WatchBlocks streams the changes of the blocks kept in the change log of
+// the index. The changes are not read through raft: the watch can be served
+// by any replica, and the changes of a follower may lag behind the leader.
+func (svc *IndexService) WatchBlocks(
+	req *metastorev1.WatchBlocksRequest,
+	stream metastorev1.IndexService_WatchBlocksServer,
+) error {
+	ctx := stream.Context()
+	cursor := req.Cursor
+	for first := true; ; first = false {
+		changes, next, notify, err := svc.index.BlockChanges(cursor, req.TenantId, maxWatchBlocksChanges)
+		switch {
+		case err == nil:
+		case errors.Is(err, index.ErrChangeLogDisabled):
+			return status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, index.ErrChangesUnavailable):
+			return status.Error(codes.OutOfRange, err.Error())
+		default:
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if first || len(changes) > 0 {
+			resp := &metastorev1.WatchBlocksResponse{Changes: changes, Cursor: next}
+			if err = stream.Send(resp); err != nil {
+				return err
+			}
+		}
+		cursor = next
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func describeBlock(md *metastorev1.BlockMeta, p *index.PartitionMeta) *metastorev1.BlockDetails {
+	d := &metastorev1.BlockDetails{
+		PartitionKey: string(p.Key),
+		CreatedAt:    int64(ulid.MustParse(md.Id).Time()),
+		Datasets:     make([]*metastorev1.DatasetDetails, 0, len(md.Datasets)),
+	}
+	for _, ds := range md.Datasets {
+		d.Datasets = append(d.Datasets, &metastorev1.DatasetDetails{
+			TenantId: ds.TenantId,
+			Name:     ds.Name,
+			Sections: block.DescribeDatasetSections(md, ds),
+		})
+	}
+	for _, id := range md.SourceBlocks {
+		s := &metastorev1.SourceBlockDetails{Id: id}
+		if u, err := ulid.Parse(id); err == nil {
+			s.CreatedAt = int64(u.Time())
+		}
+		d.SourceBlocks = append(d.SourceBlocks, s)
+	}
+	return d
+}
+
 func statsFromMetadata(md *metastorev1.BlockMeta) iter.Iterator[placement.Sample] {
 	return &sampleIterator{md: md}
 }
@@ -136,6 +629,17 @@ func (s *sampleIterator) At() placement.Sample {
 // TODO(kolesnikovae): Implement and refactor to the block package.
 
 func SanitizeMetadata(md *metastorev1.BlockMeta) error {
-	_, err := ulid.Parse(md.Id)
-	return err
+	if _, err := ulid.Parse(md.Id); err != nil {
+		return err
+	}
+	if md.OriginalCreatedAt != 0 {
+		return fmt.Errorf("original creation time of block %s must not be set by the writer", md.Id)
+	}
+	if md.Quarantine != nil {
+		return fmt.Errorf("block %s must not be quarantined by the writer", md.Id)
+	}
+	if md.DeletedAt != 0 {
+		return fmt.Errorf("block %s must not be deleted by the writer", md.Id)
+	}
+	return nil
 }
@@ -0,0 +1,24 @@
+package metastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func Test_SanitizeMetadata(t *testing.T) {
+	id := test.ULID("2024-09-23T08:00:00.000Z")
+	assert.NoError(t, SanitizeMetadata(&metastorev1.BlockMeta{Id: id}))
+	// The fields managed by the metastore must not be set by the writer.
+	for _, md := range []*metastorev1.BlockMeta{
+		{Id: "invalid"},
+		{Id: id, OriginalCreatedAt: 1},
+		{Id: id, Quarantine: &metastorev1.BlockQuarantine{}},
+		{Id: id, DeletedAt: 1},
+	} {
+		assert.Error(t, SanitizeMetadata(md))
+	}
+}
@@ -0,0 +1,98 @@
+package metastore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+// sweepBatchSize limits the number of blocks removed in a transaction.
+const sweepBatchSize = 1000
+
+// BlockSweeper removes the blocks deleted from the index once the grace
+// period has passed. It only runs on the raft leader: the time the blocks
+// were deleted before is carried in the command, so all the replicas
+// remove the same blocks.
+type BlockSweeper struct {
+	config index.Config
+	logger log.Logger
+	raft   Raft
+
+	m       sync.Mutex
+	started bool
+	cancel  func()
+}
+
+func NewBlockSweeper(logger log.Logger, config index.Config, raft Raft) *BlockSweeper {
+	return &BlockSweeper{
+		config: config,
+		logger: logger,
+		raft:   raft,
+	}
+}
+
+func (s *BlockSweeper) Start() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.config.BlockDeletionSweepInterval <= 0 || s.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.started = true
+	go s.loop(ctx)
+	level.Info(s.logger).Log("msg", "block sweeper started")
+}
+
+func (s *BlockSweeper) Stop() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.started = false
+	level.Info(s.logger).Log("msg", "block sweeper stopped")
+}
+
+func (s *BlockSweeper) loop(ctx context.Context) {
+	ticker := time.NewTicker(s.config.BlockDeletionSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *BlockSweeper) sweep(ctx context.Context) {
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_SWEEP_DELETED_BLOCKS)
+	req := &raft_log.SweepDeletedBlocksRequest{
+		DeletedBefore: time.Now().Add(-s.config.BlockDeletionGracePeriod).UnixMilli(),
+		MaxBlocks:     sweepBatchSize,
+	}
+	// The blocks are removed in batches, until a batch is not full.
+	for ctx.Err() == nil {
+		resp, err := s.raft.Propose(cmd, req)
+		if err != nil {
+			if !raftnode.IsRaftLeadershipError(err) {
+				level.Error(s.logger).Log("msg", "failed to sweep deleted blocks", "err", err)
+			}
+			return
+		}
+		if r, ok := resp.(*raft_log.SweepDeletedBlocksResponse); !ok || r.Removed < sweepBatchSize {
+			return
+		}
+	}
+}
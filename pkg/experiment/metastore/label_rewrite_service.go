@@ -0,0 +1,134 @@
+package metastore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+)
+
+type LabelRewriteJobReader interface {
+	GetJob(*bbolt.Tx, string) (*metastorev1.LabelRewriteJob, error)
+	ListJobs(*bbolt.Tx, string) ([]*metastorev1.LabelRewriteJob, error)
+}
+
+type LabelRewriteService struct {
+	metastorev1.LabelRewriteServiceServer
+
+	logger log.Logger
+	raft   Raft
+	state  State
+	jobs   LabelRewriteJobReader
+}
+
+func NewLabelRewriteService(
+	logger log.Logger,
+	raft Raft,
+	state State,
+	jobs LabelRewriteJobReader,
+) *LabelRewriteService {
+	return &LabelRewriteService{
+		logger: logger,
+		raft:   raft,
+		state:  state,
+		jobs:   jobs,
+	}
+}
+
+func (svc *LabelRewriteService) CreateLabelRewriteJob(
+	_ context.Context,
+	req *metastorev1.CreateLabelRewriteJobRequest,
+) (*metastorev1.CreateLabelRewriteJobResponse, error) {
+	if err := validateLabelRewriteJob(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	cmd := fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_CREATE_LABEL_REWRITE_JOB)
+	resp, err := svc.raft.Propose(cmd, &raft_log.CreateLabelRewriteJobRequest{
+		Job: &metastorev1.LabelRewriteJob{
+			Name:      req.Name,
+			Tenant:    req.Tenant,
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+			Rules:     req.Rules,
+		},
+	})
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to create label rewrite job", "job", req.Name, "err", err)
+		return nil, err
+	}
+	job := resp.(*raft_log.CreateLabelRewriteJobResponse).GetJob()
+	if job == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "label rewrite job %q already exists", req.Name)
+	}
+	level.Info(svc.logger).Log(
+		"msg", "label rewrite job created",
+		"job", job.Name,
+		"tenant", job.Tenant,
+		"blocks", job.Progress.GetBlocksTotal(),
+	)
+	return &metastorev1.CreateLabelRewriteJobResponse{Job: job}, nil
+}
+
+func validateLabelRewriteJob(req *metastorev1.CreateLabelRewriteJobRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+	if req.Tenant == "" {
+		return fmt.Errorf("tenant is required")
+	}
+	if req.StartTime > req.EndTime {
+		return fmt.Errorf("invalid time range: start time must not be after end time")
+	}
+	if len(req.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	_, err := block.LabelRewriteRules(req.Rules)
+	return err
+}
+
+func (svc *LabelRewriteService) GetLabelRewriteJob(
+	ctx context.Context,
+	req *metastorev1.GetLabelRewriteJobRequest,
+) (resp *metastorev1.GetLabelRewriteJobResponse, err error) {
+	var job *metastorev1.LabelRewriteJob
+	read := func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		job, err = svc.jobs.GetJob(tx, req.Name)
+	}
+	if readErr := svc.state.ConsistentRead(ctx, read); readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "label rewrite job %q not found", req.Name)
+	}
+	return &metastorev1.GetLabelRewriteJobResponse{Job: job}, nil
+}
+
+func (svc *LabelRewriteService) ListLabelRewriteJobs(
+	ctx context.Context,
+	req *metastorev1.ListLabelRewriteJobsRequest,
+) (resp *metastorev1.ListLabelRewriteJobsResponse, err error) {
+	var jobs []*metastorev1.LabelRewriteJob
+	read := func(tx *bbolt.Tx, _ raftnode.ReadIndex) {
+		jobs, err = svc.jobs.ListJobs(tx, req.Tenant)
+	}
+	if readErr := svc.state.ConsistentRead(ctx, read); readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &metastorev1.ListLabelRewriteJobsResponse{Jobs: jobs}, nil
+}
@@ -0,0 +1,297 @@
+package labelrewrite
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/raft"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/labelrewrite/store"
+	kvstore "github.com/grafana/pyroscope/pkg/experiment/metastore/store"
+	"github.com/grafana/pyroscope/pkg/iter"
+)
+
+var _ compaction.Planner = (*Rewriter)(nil)
+
+type JobStore interface {
+	StoreJob(*bbolt.Tx, *raft_log.LabelRewriteJobState) error
+	GetJob(*bbolt.Tx, string) (*raft_log.LabelRewriteJobState, error)
+	ListEntries(*bbolt.Tx) iter.Iterator[*raft_log.LabelRewriteJobState]
+	CreateBuckets(*bbolt.Tx) error
+}
+
+type Index interface {
+	FindBlock(tx *bbolt.Tx, shard uint32, tenant string, block string) *metastorev1.BlockMeta
+	FindBlocksInRange(tx *bbolt.Tx, start, end int64, tenants, profileTypes, services map[string]struct{}) []*metastorev1.BlockMeta
+}
+
+// Rewriter tracks label rewrite jobs and plans the compaction jobs that
+// rewrite the blocks in scope, one block per compaction job.
+//
+// Only blocks that have reached the compaction level that is not subject
+// to further compaction are rewritten: blocks at lower levels are still
+// in the compaction queue and would be compacted without the rules.
+type Rewriter struct {
+	store    JobStore
+	index    Index
+	minLevel uint32
+
+	jobs map[string]*raft_log.LabelRewriteJobState
+	// Jobs in the order of creation.
+	order []string
+	// Compaction job name => label rewrite job name.
+	scheduled map[string]string
+}
+
+func NewRewriter(store JobStore, index Index, minLevel uint32) *Rewriter {
+	return &Rewriter{
+		store:     store,
+		index:     index,
+		minLevel:  minLevel,
+		jobs:      make(map[string]*raft_log.LabelRewriteJobState),
+		scheduled: make(map[string]string),
+	}
+}
+
+func NewStore() *store.JobStore {
+	return store.NewJobStore()
+}
+
+// CreateJob creates a new label rewrite job. The response does not
+// include the job, if a job with the same name already exists.
+func (r *Rewriter) CreateJob(
+	tx *bbolt.Tx, cmd *raft.Log, req *raft_log.CreateLabelRewriteJobRequest,
+) (*raft_log.CreateLabelRewriteJobResponse, error) {
+	if req.Job == nil {
+		return new(raft_log.CreateLabelRewriteJobResponse), nil
+	}
+	if _, exists := r.jobs[req.Job.Name]; exists {
+		return new(raft_log.CreateLabelRewriteJobResponse), nil
+	}
+
+	job := req.Job.CloneVT()
+	job.CreatedAt = cmd.AppendedAt.UnixMilli()
+	state := &raft_log.LabelRewriteJobState{Job: job}
+	tenants := map[string]struct{}{job.Tenant: {}}
+	for _, b := range r.index.FindBlocksInRange(tx, job.StartTime, job.EndTime, tenants, nil, nil) {
+		if b.TenantId != job.Tenant || b.CompactionLevel < r.minLevel {
+			continue
+		}
+		state.PendingBlocks = append(state.PendingBlocks, &raft_log.LabelRewriteBlock{
+			Id:              b.Id,
+			Shard:           b.Shard,
+			CompactionLevel: b.CompactionLevel,
+		})
+	}
+	// The index does not guarantee any particular order.
+	slices.SortFunc(state.PendingBlocks, func(a, b *raft_log.LabelRewriteBlock) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	job.Progress = &metastorev1.LabelRewriteJobProgress{
+		BlocksTotal: uint32(len(state.PendingBlocks)),
+	}
+
+	updateProgress(state)
+	if err := r.store.StoreJob(tx, state); err != nil {
+		return nil, err
+	}
+	r.put(state)
+	return &raft_log.CreateLabelRewriteJobResponse{Job: job.CloneVT()}, nil
+}
+
+// GetJob returns the job with the given name, or nil if it does not exist.
+// It only accesses the store and therefore can be used for reads.
+func (r *Rewriter) GetJob(tx *bbolt.Tx, name string) (*metastorev1.LabelRewriteJob, error) {
+	state, err := r.store.GetJob(tx, name)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrorNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return state.Job, nil
+}
+
+// ListJobs returns the jobs of the tenant, or all jobs if the tenant is
+// not specified. It only accesses the store and therefore can be used for
+// reads.
+func (r *Rewriter) ListJobs(tx *bbolt.Tx, tenant string) ([]*metastorev1.LabelRewriteJob, error) {
+	entries := r.store.ListEntries(tx)
+	defer func() {
+		_ = entries.Close()
+	}()
+	var jobs []*metastorev1.LabelRewriteJob
+	for entries.Next() {
+		if job := entries.At().Job; tenant == "" || job.Tenant == tenant {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, entries.Err()
+}
+
+func (r *Rewriter) NewPlan(tx *bbolt.Tx, _ *raft.Log) compaction.Plan {
+	return &plan{tx: tx, rewriter: r}
+}
+
+func (r *Rewriter) UpdatePlan(tx *bbolt.Tx, _ *raft.Log, update *raft_log.CompactionPlanUpdate) error {
+	updated := make(map[string]*raft_log.LabelRewriteJobState)
+
+	for _, job := range update.NewJobs {
+		rewrite := job.Plan.GetLabelRewrite()
+		if rewrite == nil {
+			continue
+		}
+		state, ok := r.jobs[rewrite.Job]
+		if !ok {
+			continue
+		}
+		for _, id := range job.Plan.SourceBlocks {
+			i := slices.IndexFunc(state.PendingBlocks, func(b *raft_log.LabelRewriteBlock) bool {
+				return b.Id == id
+			})
+			if i < 0 {
+				continue
+			}
+			b := state.PendingBlocks[i]
+			state.PendingBlocks = slices.Delete(state.PendingBlocks, i, i+1)
+			b.CompactionJob = job.Plan.Name
+			state.ScheduledBlocks = append(state.ScheduledBlocks, b)
+			r.scheduled[job.Plan.Name] = rewrite.Job
+			updated[rewrite.Job] = state
+		}
+	}
+
+	for _, job := range update.CompletedJobs {
+		name, ok := r.scheduled[job.State.Name]
+		if !ok {
+			continue
+		}
+		delete(r.scheduled, job.State.Name)
+		state := r.jobs[name]
+		n := len(state.ScheduledBlocks)
+		state.ScheduledBlocks = slices.DeleteFunc(state.ScheduledBlocks, func(b *raft_log.LabelRewriteBlock) bool {
+			return b.CompactionJob == job.State.Name
+		})
+		state.Job.Progress.BlocksDone += uint32(n - len(state.ScheduledBlocks))
+		updated[name] = state
+	}
+
+	// Blocks may be removed from the index before they are rewritten,
+	// e.g., due to retention policies. The planner skips such blocks;
+	// here we remove them from the job, once they reach the head.
+	for _, name := range r.order {
+		state := r.jobs[name]
+		for len(state.PendingBlocks) > 0 {
+			b := state.PendingBlocks[0]
+			if r.index.FindBlock(tx, b.Shard, state.Job.Tenant, b.Id) != nil {
+				break
+			}
+			state.PendingBlocks = state.PendingBlocks[1:]
+			state.Job.Progress.BlocksSkipped++
+			updated[name] = state
+		}
+	}
+
+	for _, name := range r.order {
+		if state, ok := updated[name]; ok {
+			updateProgress(state)
+			if err := r.store.StoreJob(tx, state); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Rewriter) put(state *raft_log.LabelRewriteJobState) {
+	r.jobs[state.Job.Name] = state
+	r.order = append(r.order, state.Job.Name)
+	for _, b := range state.ScheduledBlocks {
+		r.scheduled[b.CompactionJob] = state.Job.Name
+	}
+}
+
+func updateProgress(state *raft_log.LabelRewriteJobState) {
+	state.Job.Progress.BlocksPending = uint32(len(state.PendingBlocks))
+	state.Job.Progress.BlocksInProgress = uint32(len(state.ScheduledBlocks))
+}
+
+func (r *Rewriter) Init(tx *bbolt.Tx) error {
+	return r.store.CreateBuckets(tx)
+}
+
+func (r *Rewriter) Restore(tx *bbolt.Tx) error {
+	clear(r.jobs)
+	clear(r.scheduled)
+	r.order = r.order[:0]
+	entries := r.store.ListEntries(tx)
+	defer func() {
+		_ = entries.Close()
+	}()
+	var states []*raft_log.LabelRewriteJobState
+	for entries.Next() {
+		states = append(states, entries.At())
+	}
+	if err := entries.Err(); err != nil {
+		return err
+	}
+	slices.SortFunc(states, func(a, b *raft_log.LabelRewriteJobState) int {
+		if c := cmp.Compare(a.Job.CreatedAt, b.Job.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Job.Name, b.Job.Name)
+	})
+	for _, state := range states {
+		r.put(state)
+	}
+	return nil
+}
+
+// plan should be used to prepare the compaction plan update.
+// The implementation must have no side effects or alter the
+// Rewriter in any way.
+type plan struct {
+	tx       *bbolt.Tx
+	rewriter *Rewriter
+	job      int
+	block    int
+}
+
+func (p *plan) CreateJob() (*raft_log.CompactionJobPlan, error) {
+	for p.job < len(p.rewriter.order) {
+		state := p.rewriter.jobs[p.rewriter.order[p.job]]
+		for p.block < len(state.PendingBlocks) {
+			b := state.PendingBlocks[p.block]
+			p.block++
+			if p.rewriter.index.FindBlock(p.tx, b.Shard, state.Job.Tenant, b.Id) == nil {
+				continue
+			}
+			return &raft_log.CompactionJobPlan{
+				Name:            jobName(state.Job.Name, b.Id),
+				Tenant:          state.Job.Tenant,
+				Shard:           b.Shard,
+				CompactionLevel: b.CompactionLevel,
+				SourceBlocks:    []string{b.Id},
+				LabelRewrite: &metastorev1.LabelRewrite{
+					Job:   state.Job.Name,
+					Rules: state.Job.Rules,
+				},
+			}, nil
+		}
+		p.job++
+		p.block = 0
+	}
+	return nil, nil
+}
+
+func jobName(job, block string) string {
+	return fmt.Sprintf("rewrite-%s-%s", job, block)
+}
@@ -0,0 +1,125 @@
+package labelrewrite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+type mockIndex struct {
+	blocks map[string]*metastorev1.BlockMeta
+}
+
+func (m *mockIndex) FindBlock(_ *bbolt.Tx, shard uint32, tenant string, block string) *metastorev1.BlockMeta {
+	if b, ok := m.blocks[block]; ok && b.Shard == shard && b.TenantId == tenant {
+		return b
+	}
+	return nil
+}
+
+func (m *mockIndex) FindBlocksInRange(_ *bbolt.Tx, start, end int64, tenants, _, _ map[string]struct{}) []*metastorev1.BlockMeta {
+	var blocks []*metastorev1.BlockMeta
+	for _, b := range m.blocks {
+		if _, ok := tenants[b.TenantId]; ok && b.MinTime <= end && b.MaxTime >= start {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+func TestRewriter_JobLifecycle(t *testing.T) {
+	index := &mockIndex{blocks: map[string]*metastorev1.BlockMeta{
+		"a": {Id: "a", TenantId: "tenant", Shard: 1, CompactionLevel: 3, MinTime: 10, MaxTime: 20},
+		"b": {Id: "b", TenantId: "tenant", Shard: 2, CompactionLevel: 3, MinTime: 20, MaxTime: 30},
+		"c": {Id: "c", TenantId: "tenant", Shard: 1, CompactionLevel: 3, MinTime: 30, MaxTime: 40},
+		"d": {Id: "d", TenantId: "tenant", Shard: 1, CompactionLevel: 1, MinTime: 10, MaxTime: 20},
+		"e": {Id: "e", TenantId: "other", Shard: 1, CompactionLevel: 3, MinTime: 10, MaxTime: 20},
+		"f": {Id: "f", TenantId: "tenant", Shard: 1, CompactionLevel: 3, MinTime: 50, MaxTime: 60},
+	}}
+
+	db := test.BoltDB(t)
+	r := NewRewriter(NewStore(), index, 3)
+	tx, err := db.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, r.Init(tx))
+
+	cmd := &raft.Log{Index: 1, AppendedAt: time.UnixMilli(1000)}
+	rules := []*metastorev1.LabelRewriteRule{{SourceLabels: []string{"env"}, TargetLabel: "environment"}}
+	resp, err := r.CreateJob(tx, cmd, &raft_log.CreateLabelRewriteJobRequest{
+		Job: &metastorev1.LabelRewriteJob{Name: "job", Tenant: "tenant", StartTime: 0, EndTime: 45, Rules: rules},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Job)
+	assert.Equal(t, int64(1000), resp.Job.CreatedAt)
+	assert.Equal(t, &metastorev1.LabelRewriteJobProgress{BlocksTotal: 3, BlocksPending: 3}, resp.Job.Progress)
+
+	// A job with the same name is not created.
+	resp, err = r.CreateJob(tx, cmd, &raft_log.CreateLabelRewriteJobRequest{
+		Job: &metastorev1.LabelRewriteJob{Name: "job", Tenant: "other"},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp.Job)
+
+	// Plan is read-only.
+	p := r.NewPlan(tx, cmd)
+	job, err := p.CreateJob()
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, []string{"a"}, job.SourceBlocks)
+	assert.Equal(t, uint32(3), job.CompactionLevel)
+	assert.Equal(t, uint32(1), job.Shard)
+	assert.Equal(t, "job", job.LabelRewrite.Job)
+	assert.Equal(t, rules, job.LabelRewrite.Rules)
+	job2, err := p.CreateJob()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, job2.SourceBlocks)
+	assert.Len(t, r.jobs["job"].PendingBlocks, 3)
+
+	require.NoError(t, r.UpdatePlan(tx, cmd, &raft_log.CompactionPlanUpdate{
+		NewJobs: []*raft_log.NewCompactionJob{{Plan: job}, {Plan: job2}},
+	}))
+	progress := r.jobs["job"].Job.Progress
+	assert.Equal(t, &metastorev1.LabelRewriteJobProgress{BlocksTotal: 3, BlocksPending: 1, BlocksInProgress: 2}, progress)
+
+	// Block "c" is removed before it is rewritten.
+	delete(index.blocks, "c")
+	p = r.NewPlan(tx, cmd)
+	job3, err := p.CreateJob()
+	require.NoError(t, err)
+	require.Nil(t, job3)
+
+	require.NoError(t, r.UpdatePlan(tx, cmd, &raft_log.CompactionPlanUpdate{
+		CompletedJobs: []*raft_log.CompletedCompactionJob{{State: &raft_log.CompactionJobState{Name: job.Name}}},
+	}))
+	progress = r.jobs["job"].Job.Progress
+	assert.Equal(t, &metastorev1.LabelRewriteJobProgress{BlocksTotal: 3, BlocksInProgress: 1, BlocksDone: 1, BlocksSkipped: 1}, progress)
+	require.NoError(t, tx.Commit())
+
+	// The state is restored from the store.
+	r = NewRewriter(NewStore(), index, 3)
+	tx, err = db.Begin(false)
+	require.NoError(t, err)
+	require.NoError(t, r.Restore(tx))
+	assert.Equal(t, "job", r.scheduled[job2.Name])
+	stored, err := r.GetJob(tx, "job")
+	require.NoError(t, err)
+	assert.Equal(t, progress, stored.Progress)
+	listed, err := r.ListJobs(tx, "other")
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+	listed, err = r.ListJobs(tx, "")
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+	missing, err := r.GetJob(tx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+	require.NoError(t, tx.Rollback())
+}
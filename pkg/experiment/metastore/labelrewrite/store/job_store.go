@@ -0,0 +1,84 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/store"
+	"github.com/grafana/pyroscope/pkg/iter"
+)
+
+var jobBucketName = []byte("label_rewrite_job")
+
+var ErrInvalidJobState = errors.New("invalid label rewrite job state entry")
+
+type JobStore struct{ bucketName []byte }
+
+func NewJobStore() *JobStore {
+	return &JobStore{bucketName: jobBucketName}
+}
+
+func (s JobStore) CreateBuckets(tx *bbolt.Tx) error {
+	_, err := tx.CreateBucketIfNotExists(s.bucketName)
+	return err
+}
+
+func (s JobStore) GetJob(tx *bbolt.Tx, name string) (*raft_log.LabelRewriteJobState, error) {
+	b := tx.Bucket(s.bucketName).Get([]byte(name))
+	if b == nil {
+		return nil, fmt.Errorf("loading label rewrite job %s: %w", name, store.ErrorNotFound)
+	}
+	var v raft_log.LabelRewriteJobState
+	if err := v.UnmarshalVT(b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobState, err)
+	}
+	return &v, nil
+}
+
+func (s JobStore) StoreJob(tx *bbolt.Tx, state *raft_log.LabelRewriteJobState) error {
+	v, _ := state.MarshalVT()
+	return tx.Bucket(s.bucketName).Put([]byte(state.Job.Name), v)
+}
+
+func (s JobStore) ListEntries(tx *bbolt.Tx) iter.Iterator[*raft_log.LabelRewriteJobState] {
+	return newJobEntriesIterator(tx.Bucket(s.bucketName))
+}
+
+type jobEntriesIterator struct {
+	iter *store.CursorIterator
+	cur  *raft_log.LabelRewriteJobState
+	err  error
+}
+
+func newJobEntriesIterator(bucket *bbolt.Bucket) *jobEntriesIterator {
+	return &jobEntriesIterator{iter: store.NewCursorIter(nil, bucket.Cursor())}
+}
+
+func (x *jobEntriesIterator) Next() bool {
+	if x.err != nil || !x.iter.Next() {
+		return false
+	}
+	e := x.iter.At()
+	var s raft_log.LabelRewriteJobState
+	x.err = s.UnmarshalVT(e.Value)
+	if x.err != nil {
+		x.err = fmt.Errorf("%w: %v", ErrInvalidJobState, x.err)
+		return false
+	}
+	x.cur = &s
+	return true
+}
+
+func (x *jobEntriesIterator) At() *raft_log.LabelRewriteJobState { return x.cur }
+
+func (x *jobEntriesIterator) Close() error { return x.iter.Close() }
+
+func (x *jobEntriesIterator) Err() error {
+	if err := x.iter.Err(); err != nil {
+		return err
+	}
+	return x.err
+}
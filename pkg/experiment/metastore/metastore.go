@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
@@ -19,27 +20,40 @@ import (
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
 	placement "github.com/grafana/pyroscope/pkg/experiment/distributor/placement/adaptive_placement"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/annotations"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction/compactor"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction/scheduler"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/dlq"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/events"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/external"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/labelrewrite"
 	raft "github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode/raftnodepb"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/tombstones"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/writers"
 	"github.com/grafana/pyroscope/pkg/util/health"
+	"github.com/grafana/pyroscope/pkg/webhooks"
 )
 
 type Config struct {
-	Address          string             `yaml:"address"`
-	GRPCClientConfig grpcclient.Config  `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate with the metastore."`
-	DataDir          string             `yaml:"data_dir"`
-	MinReadyDuration time.Duration      `yaml:"min_ready_duration" category:"advanced"`
-	Raft             raft.Config        `yaml:"raft"`
-	Index            index.Config       `yaml:",inline" category:"advanced"`
-	DLQRecovery      dlq.RecoveryConfig `yaml:",inline" category:"advanced"`
-	Compactor        compactor.Config   `yaml:",inline" category:"advanced"`
-	Scheduler        scheduler.Config   `yaml:",inline" category:"advanced"`
+	Address          string                `yaml:"address"`
+	GRPCClientConfig grpcclient.Config     `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate with the metastore."`
+	DataDir          string                `yaml:"data_dir"`
+	MinReadyDuration time.Duration         `yaml:"min_ready_duration" category:"advanced"`
+	Raft             raft.Config           `yaml:"raft"`
+	Index            index.Config          `yaml:",inline" category:"advanced"`
+	DLQRecovery      dlq.RecoveryConfig    `yaml:",inline" category:"advanced"`
+	ExternalBlocks   external.Config       `yaml:",inline" category:"experimental"`
+	Compactor        compactor.Config      `yaml:",inline" category:"advanced"`
+	Scheduler        scheduler.Config      `yaml:",inline" category:"advanced"`
+	Events           events.Config         `yaml:",inline" category:"advanced"`
+	FSM              fsm.Config            `yaml:",inline" category:"advanced"`
+	WriterRateLimit  WriterRateLimitConfig `yaml:",inline" category:"advanced"`
+
+	// Notifier is injected by the upstream caller.
+	Notifier *webhooks.Notifier `yaml:"-"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
@@ -53,6 +67,10 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Scheduler.RegisterFlagsWithPrefix(prefix, f)
 	cfg.Index.RegisterFlagsWithPrefix(prefix, f)
 	cfg.DLQRecovery.RegisterFlagsWithPrefix(prefix, f)
+	cfg.ExternalBlocks.RegisterFlagsWithPrefix(prefix, f)
+	cfg.Events.RegisterFlagsWithPrefix(prefix, f)
+	cfg.FSM.RegisterFlagsWithPrefix(prefix, f)
+	cfg.WriterRateLimit.RegisterFlagsWithPrefix(prefix, f)
 }
 
 func (cfg *Config) Validate() error {
@@ -62,6 +80,15 @@ func (cfg *Config) Validate() error {
 	if err := cfg.GRPCClientConfig.Validate(); err != nil {
 		return err
 	}
+	if err := cfg.ExternalBlocks.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Index.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.FSM.Validate(); err != nil {
+		return err
+	}
 	return cfg.Raft.Validate()
 }
 
@@ -79,10 +106,18 @@ type Metastore struct {
 	bucket      objstore.Bucket
 	placement   *placement.Manager
 	dlqRecovery *dlq.Recovery
+	external    *external.Watcher
 
 	index        *index.Index
 	indexHandler *IndexCommandHandler
 	indexService *IndexService
+	archiver     *PartitionArchiver
+	merger       *PartitionMerger
+	sweeper      *BlockSweeper
+	retention    *PartitionRetention
+	rebuilder    *IndexRebuilder
+	checker      *IndexChecker
+	writers      *writers.Quarantine
 
 	tombstones        *tombstones.Tombstones
 	compactor         *compactor.Compactor
@@ -90,6 +125,15 @@ type Metastore struct {
 	compactionHandler *CompactionCommandHandler
 	compactionService *CompactionService
 
+	labelRewriter       *labelrewrite.Rewriter
+	labelRewriteService *LabelRewriteService
+
+	annotations       *annotations.Annotations
+	annotationService *AnnotationService
+
+	events       *events.Events
+	eventService *EventService
+
 	followerRead    *raft.StateReader[*bbolt.Tx]
 	tenantService   *TenantService
 	metadataService *MetadataQueryService
@@ -106,7 +150,10 @@ func New(
 	client raftnodepb.RaftNodeServiceClient,
 	bucket objstore.Bucket,
 	placementMgr *placement.Manager,
+	limits index.Limits,
 ) (*Metastore, error) {
+	config.Index.Bucket = bucket
+	config.Index.Limits = limits
 	m := &Metastore{
 		config:    config,
 		logger:    logger,
@@ -117,35 +164,88 @@ func New(
 	}
 
 	var err error
-	m.fsm, err = fsm.New(m.logger, m.reg, m.config.DataDir)
+	m.fsm, err = fsm.New(m.logger, m.reg, m.config.DataDir, m.config.FSM)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize store: %w", err)
 	}
 
 	// Initialization of the base components.
-	m.index = index.NewIndex(m.logger, index.NewStore(), &config.Index)
+	m.index = index.NewIndex(m.logger, index.NewStoreWithConfig(&config.Index), &config.Index, m.reg)
 	m.tombstones = tombstones.NewTombstones(tombstones.NewStore())
-	m.compactor = compactor.NewCompactor(config.Compactor, compactor.NewStore(), m.tombstones, m.reg)
+	m.compactor = compactor.NewCompactor(config.Compactor, compactor.NewStore(), m.tombstones, m.index, m.reg)
 	m.scheduler = scheduler.NewScheduler(config.Scheduler, scheduler.NewStore(), m.reg)
+	// Blocks are only rewritten once they reach the top compaction level.
+	m.labelRewriter = labelrewrite.NewRewriter(labelrewrite.NewStore(), m.index, uint32(config.Compactor.MaxLevel))
+	m.annotations = annotations.NewAnnotations(annotations.NewStore())
+	m.events = events.NewEvents(config.Events, events.NewStore())
+	m.writers = writers.NewQuarantine(writers.NewStore())
 
 	// FSM handlers that utilize the components.
-	m.indexHandler = NewIndexCommandHandler(m.logger, m.index, m.tombstones, m.compactor)
+	cardinality := newTenantCardinality()
+	if m.reg != nil {
+		m.reg.MustRegister(newTenantCardinalityCollector(cardinality))
+		m.reg.MustRegister(index.NewTenantStatsCollector(m.index))
+	}
+	m.indexHandler = NewIndexCommandHandler(m.logger, m.index, m.tombstones, m.writers, m.compactor, m.events, cardinality)
 	fsm.RegisterRaftCommandHandler(m.fsm,
 		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ADD_BLOCK_METADATA),
 		m.indexHandler.AddBlock)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ADD_BLOCKS),
+		m.indexHandler.AddBlocks)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_QUARANTINE_BLOCK),
+		m.indexHandler.QuarantineBlock)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ARCHIVE_PARTITION),
+		m.indexHandler.ArchivePartition)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_MERGE_PARTITIONS),
+		m.indexHandler.MergePartitions)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_SPLIT_PARTITION),
+		m.indexHandler.SplitPartition)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_SWEEP_DELETED_BLOCKS),
+		m.indexHandler.SweepDeletedBlocks)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_DELETE_PARTITIONS),
+		m.indexHandler.DeletePartitions)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_REPAIR_INDEX),
+		m.indexHandler.RepairIndex)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_QUARANTINE_WRITER),
+		m.writers.QuarantineWriter)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_RELEASE_WRITER),
+		m.writers.ReleaseWriter)
 
-	m.compactionHandler = NewCompactionCommandHandler(m.logger, m.index, m.compactor, m.compactor, m.scheduler, m.tombstones)
+	m.compactionHandler = NewCompactionCommandHandler(m.logger, m.index, m.compactor, m.compactor, m.scheduler, m.tombstones, m.labelRewriter, m.events)
 	fsm.RegisterRaftCommandHandler(m.fsm,
 		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE),
 		m.compactionHandler.GetCompactionPlanUpdate)
 	fsm.RegisterRaftCommandHandler(m.fsm,
 		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_UPDATE_COMPACTION_PLAN),
 		m.compactionHandler.UpdateCompactionPlan)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_CREATE_LABEL_REWRITE_JOB),
+		m.labelRewriter.CreateJob)
+	fsm.RegisterRaftCommandHandler(m.fsm,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ADD_ANNOTATION),
+		m.annotations.AddAnnotation)
 
 	m.fsm.RegisterRestorer(m.tombstones)
 	m.fsm.RegisterRestorer(m.compactor)
 	m.fsm.RegisterRestorer(m.scheduler)
 	m.fsm.RegisterRestorer(m.index)
+	m.fsm.RegisterRestorer(m.labelRewriter)
+	m.fsm.RegisterRestorer(m.annotations)
+	m.fsm.RegisterRestorer(m.events)
+	m.fsm.RegisterRestorer(m.writers)
+	if config.Index.BlockWriteBehindInterval > 0 {
+		m.fsm.RegisterFlusher(m.index)
+	}
 
 	// We are ready to start raft as our FSM is fully configured.
 	if err = m.buildRaftNode(); err != nil {
@@ -160,17 +260,33 @@ func New(
 
 	// Services should be registered after FSM and Raft have been initialized.
 	// Services provide an interface to interact with the metastore.
-	m.compactionService = NewCompactionService(m.logger, m.raft)
-	m.indexService = NewIndexService(m.logger, m.raft, m.followerRead, m.index, m.placement)
+	m.compactionService = NewCompactionService(m.logger, m.raft, config.Notifier, uint32(config.Compactor.MaxLevel))
+	m.rebuilder = NewIndexRebuilder(logger, config.Index, m.raft, bucket)
+	m.checker = NewIndexChecker(logger, m.raft, m.followerRead, m.index, bucket)
+	m.indexService = NewIndexService(m.logger, config.WriterRateLimit, m.raft, m.followerRead, m.index, m.writers, m.placement, m.rebuilder, m.checker)
 	m.tenantService = NewTenantService(m.logger, m.followerRead, m.index)
-	m.metadataService = NewMetadataQueryService(m.logger, m.followerRead, m.index)
+	m.metadataService = NewMetadataQueryService(m.logger, m.followerRead, m.index, m.tombstones)
+	m.labelRewriteService = NewLabelRewriteService(m.logger, m.raft, m.followerRead, m.labelRewriter)
+	m.annotationService = NewAnnotationService(m.logger, m.raft, m.followerRead, m.annotations)
+	m.eventService = NewEventService(m.logger, m.followerRead, m.events)
 	m.dlqRecovery = dlq.NewRecovery(logger, config.DLQRecovery, m.indexService, bucket)
+	m.external = external.NewWatcher(logger, config.ExternalBlocks, m.indexService, bucket, m.reg)
+	m.archiver = NewPartitionArchiver(logger, config.Index, m.raft, m.followerRead, m.index, bucket)
+	m.merger = NewPartitionMerger(logger, config.Index, m.raft, m.index)
+	m.sweeper = NewBlockSweeper(logger, config.Index, m.raft)
+	m.retention = NewPartitionRetention(logger, config.Index, m.raft, m.followerRead, m.index)
 
 	// These are the services that only run on the raft leader.
 	// Keep in mind that the node may not be the leader at the moment the
 	// service is starting, so it should be able to handle conflicts.
 	m.raft.RunOnLeader(m.dlqRecovery)
+	m.raft.RunOnLeader(m.external)
 	m.raft.RunOnLeader(m.placement)
+	m.raft.RunOnLeader(m.archiver)
+	m.raft.RunOnLeader(m.merger)
+	m.raft.RunOnLeader(m.sweeper)
+	m.raft.RunOnLeader(m.retention)
+	m.raft.RunOnLeader(m.rebuilder)
 
 	m.service = services.NewBasicService(m.starting, m.running, m.stopping)
 	return m, nil
@@ -222,11 +338,32 @@ func (m *Metastore) Register(server *grpc.Server) {
 	metastorev1.RegisterCompactionServiceServer(server, m.compactionService)
 	metastorev1.RegisterMetadataQueryServiceServer(server, m.metadataService)
 	metastorev1.RegisterTenantServiceServer(server, m.tenantService)
+	metastorev1.RegisterLabelRewriteServiceServer(server, m.labelRewriteService)
+	metastorev1.RegisterAnnotationServiceServer(server, m.annotationService)
+	metastorev1.RegisterEventServiceServer(server, m.eventService)
 	m.raft.Register(server)
 }
 
 func (m *Metastore) Service() services.Service { return m.service }
 
+// ExternalBlocksNotificationHandler handles the bucket event notifications
+// about the external blocks. See external.Watcher.NotificationHandler.
+func (m *Metastore) ExternalBlocksNotificationHandler() http.Handler {
+	return m.external.NotificationHandler()
+}
+
+// IndexPartitionsHandler lists the index partitions and their state.
+// See IndexService.PartitionsHandler.
+func (m *Metastore) IndexPartitionsHandler() http.Handler {
+	return http.HandlerFunc(m.indexService.PartitionsHandler)
+}
+
+// ApplyIndexRuntimeConfig applies the index configuration
+// changed at runtime, e.g., the partition cache limits.
+func (m *Metastore) ApplyIndexRuntimeConfig(c index.RuntimeConfig) {
+	m.index.ApplyRuntimeConfig(c)
+}
+
 func (m *Metastore) starting(context.Context) error { return nil }
 
 func (m *Metastore) stopping(_ error) error {
@@ -257,8 +394,32 @@ func (m *Metastore) stopping(_ error) error {
 
 func (m *Metastore) running(ctx context.Context) error {
 	m.health.SetServing()
-	<-ctx.Done()
-	return nil
+	// The database is defragmented, and the blocks pending in the
+	// write-behind mode are flushed, locally on each replica,
+	// independently of the raft log.
+	var defrag, flush <-chan time.Time
+	if m.config.FSM.DefragCheckInterval > 0 {
+		ticker := time.NewTicker(m.config.FSM.DefragCheckInterval)
+		defer ticker.Stop()
+		defrag = ticker.C
+	}
+	if m.config.Index.BlockWriteBehindInterval > 0 {
+		ticker := time.NewTicker(m.config.Index.BlockWriteBehindInterval)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-defrag:
+			m.fsm.Defragment()
+		case <-flush:
+			if err := m.fsm.Flush(); err != nil {
+				level.Error(m.logger).Log("msg", "failed to flush pending blocks", "err", err)
+			}
+		}
+	}
 }
 
 // CheckReady verifies if the metastore is ready to serve requests by
@@ -22,6 +22,8 @@ type Raft interface {
 // The write interface is provided through the FSM raft command handlers.
 type State interface {
 	ConsistentRead(context.Context, func(*bbolt.Tx, raftnode.ReadIndex)) error
+	BarrierRead(context.Context, func(*bbolt.Tx, raftnode.ReadIndex)) error
+	StaleRead(context.Context, func(*bbolt.Tx, raftnode.ReadIndex)) error
 }
 
 // newFollowerReader creates a new follower reader – implementation of the
@@ -36,9 +38,11 @@ func (m *Metastore) newFollowerReader(
 		// NOTE(kolesnikovae): replace the client with the local
 		// raft node to implement Leader Read pattern.
 		&leaderNode{client: client, timeout: m.config.Raft.ApplyTimeout},
+		node,
 		&localNode{node: node, fsm: fsm},
 		m.config.Raft.LogIndexCheckInterval,
 		m.config.Raft.ReadIndexMaxDistance,
+		m.config.Raft.StaleReadMaxLag,
 	)
 }
 
@@ -50,10 +54,18 @@ type leaderNode struct {
 	timeout time.Duration
 }
 
-func (l *leaderNode) ReadIndex() (read raftnode.ReadIndex, err error) {
+func (l *leaderNode) ReadIndex() (raftnode.ReadIndex, error) {
+	return l.readIndex(new(raftnodepb.ReadIndexRequest))
+}
+
+func (l *leaderNode) Barrier() (raftnode.ReadIndex, error) {
+	return l.readIndex(&raftnodepb.ReadIndexRequest{Barrier: true})
+}
+
+func (l *leaderNode) readIndex(req *raftnodepb.ReadIndexRequest) (read raftnode.ReadIndex, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
 	defer cancel()
-	resp, err := l.client.ReadIndex(ctx, new(raftnodepb.ReadIndexRequest))
+	resp, err := l.client.ReadIndex(ctx, req)
 	if err != nil {
 		return read, err
 	}
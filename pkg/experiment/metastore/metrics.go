@@ -0,0 +1,29 @@
+package metastore
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction/compactor"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction/scheduler"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/external"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	raft "github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+)
+
+// MetricsPrefix is prepended to the names of the metastore metrics
+// by the registerer the metastore is created with.
+const MetricsPrefix = "pyroscope_metastore_"
+
+// RegisterMetrics registers the metrics of the metastore components
+// without creating them. The metrics are used to generate monitoring
+// resources, such as dashboards and alerting rules.
+func RegisterMetrics(reg prometheus.Registerer) {
+	fsm.RegisterMetrics(reg)
+	raft.RegisterMetrics(reg)
+	index.RegisterMetrics(reg)
+	compactor.RegisterMetrics(reg)
+	scheduler.RegisterMetrics(reg)
+	external.RegisterMetrics(reg)
+	reg.MustRegister(newTenantCardinalityCollector(nil))
+}
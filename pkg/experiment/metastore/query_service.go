@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -15,35 +16,54 @@ import (
 	"google.golang.org/grpc/status"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
 	"github.com/grafana/pyroscope/pkg/experiment/metastore/raftnode"
+	tombstones "github.com/grafana/pyroscope/pkg/experiment/metastore/tombstones/store"
 	"github.com/grafana/pyroscope/pkg/model"
 )
 
 type IndexQuerier interface {
 	FindBlocks(tx *bbolt.Tx, list *metastorev1.BlockList) []*metastorev1.BlockMeta
-	FindBlocksInRange(tx *bbolt.Tx, start, end int64, tenants map[string]struct{}) []*metastorev1.BlockMeta
+	FindBlockByID(tx *bbolt.Tx, blockId string) (*metastorev1.BlockMeta, *index.PartitionMeta)
+	FindBlocksInRange(tx *bbolt.Tx, start, end int64, tenants, profileTypes, services map[string]struct{}) []*metastorev1.BlockMeta
+	FindQuarantinedBlocksInRange(tx *bbolt.Tx, start, end int64, tenants map[string]struct{}) []*metastorev1.BlockMeta
+	FindPartitionsInRange(start, end int64, tenants map[string]struct{}) []*metastorev1.PartitionInfo
+	ListBlocks(tx *bbolt.Tx, tenant string, start, end int64, after *index.BlockCursor, limit int) ([]*metastorev1.BlockMeta, *index.BlockCursor, error)
+	BlockChanges(cursor string, tenants []string, limit int) ([]*metastorev1.BlockChange, string, <-chan struct{}, error)
+	ListPartitions(tx *bbolt.Tx, tenants []string, start, end int64) []*metastorev1.PartitionDetails
+	TenantBlockStats(tenants []string) []*metastorev1.TenantBlockStats
 	ForEachPartition(ctx context.Context, f func(*index.PartitionMeta) error) error
 }
 
+// RemovedBlockQuerier provides the metadata of the blocks removed
+// from the index, retained along with their tombstones.
+type RemovedBlockQuerier interface {
+	ListRemovedBlocks(tx *bbolt.Tx, after uint64) ([]tombstones.RemovedBlock, error)
+	RetainedSince(tx *bbolt.Tx) (index uint64, appendedAt int64)
+}
+
 func NewMetadataQueryService(
 	logger log.Logger,
 	state State,
 	index IndexQuerier,
+	removed RemovedBlockQuerier,
 ) *MetadataQueryService {
 	return &MetadataQueryService{
-		logger: logger,
-		state:  state,
-		index:  index,
+		logger:  logger,
+		state:   state,
+		index:   index,
+		removed: removed,
 	}
 }
 
 type MetadataQueryService struct {
 	metastorev1.MetadataQueryServiceServer
 
-	logger log.Logger
-	state  State
-	index  IndexQuerier
+	logger  log.Logger
+	state   State
+	index   IndexQuerier
+	removed RemovedBlockQuerier
 }
 
 func (svc *MetadataQueryService) QueryMetadata(
@@ -58,7 +78,14 @@ func (svc *MetadataQueryService) QueryMetadata(
 		// do not access the state beyond the read index.
 		resp, err = svc.listBlocksForQuery(ctx, tx, req)
 	}
-	if readErr := svc.state.ConsistentRead(ctx, read); readErr != nil {
+	consistentRead := svc.state.ConsistentRead
+	switch {
+	case req.ReadBarrier:
+		consistentRead = svc.state.BarrierRead
+	case req.AllowStaleRead:
+		consistentRead = svc.state.StaleRead
+	}
+	if readErr := consistentRead(ctx, read); readErr != nil {
 		return nil, status.Error(codes.Unavailable, readErr.Error())
 	}
 	return resp, err
@@ -73,15 +100,37 @@ func (svc *MetadataQueryService) listBlocksForQuery(
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	_, applied, err := fsm.AppliedIndex(tx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
 	var resp metastorev1.QueryMetadataResponse
+	switch {
+	case q.asOf.index > 0:
+		resp.Index = q.asOf.index
+	case q.asOf.time == 0:
+		resp.Index = applied
+	}
+	removed, err := svc.listRemovedBlocks(tx, q, applied)
+	if err != nil {
+		return nil, err
+	}
 	md := make(map[string]*metastorev1.BlockMeta, 32)
 
-	blocks := svc.index.FindBlocksInRange(tx, q.startTime, q.endTime, q.tenants)
+	if req.Explain {
+		// Partitions must be collected before the blocks are
+		// looked up, as the lookup loads them in memory.
+		resp.Partitions = svc.index.FindPartitionsInRange(q.startTime, q.endTime, q.tenants)
+	}
+	blocks := svc.index.FindBlocksInRange(tx, q.startTime, q.endTime, q.tenants, q.profileTypes, q.services)
 	if err != nil {
 		level.Error(svc.logger).Log("msg", "failed to list metastore blocks", "query", q, "err", err)
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	for _, block := range blocks {
+	for _, block := range slices.Concat(blocks, removed) {
+		if q.asOf.after(block.AddedAtIndex, block.AddedAt) {
+			continue
+		}
 		var clone *metastorev1.BlockMeta
 		for _, svc := range block.Datasets {
 			if q.matchService(svc) {
@@ -104,15 +153,82 @@ func (svc *MetadataQueryService) listBlocksForQuery(
 	return &resp, nil
 }
 
+// listRemovedBlocks returns the blocks that have been removed from the
+// index after the state the query is resolved against, if any.
+func (svc *MetadataQueryService) listRemovedBlocks(
+	tx *bbolt.Tx,
+	q *metadataQuery,
+	applied uint64,
+) ([]*metastorev1.BlockMeta, error) {
+	if q.asOf.index == 0 && q.asOf.time == 0 {
+		return nil, nil
+	}
+	if q.asOf.index > applied {
+		// The replica lags behind the state requested.
+		return nil, status.Errorf(codes.Unavailable, "state at index %d has not been applied yet; applied index: %d", q.asOf.index, applied)
+	}
+	index, appendedAt := svc.removed.RetainedSince(tx)
+	if q.asOf.after(index, time.Unix(0, appendedAt).UnixMilli()) {
+		return nil, status.Errorf(codes.FailedPrecondition, "blocks removed before index %d are not retained", index)
+	}
+	list, err := svc.removed.ListRemovedBlocks(tx, q.asOf.index)
+	if err != nil {
+		level.Error(svc.logger).Log("msg", "failed to list removed blocks", "query", q, "err", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	blocks := make([]*metastorev1.BlockMeta, 0, len(list))
+	for _, b := range list {
+		if !q.asOf.after(b.Index, time.Unix(0, b.AppendedAt).UnixMilli()) {
+			continue
+		}
+		if b.Quarantine != nil || !inRange(b.MinTime, b.MaxTime, q.startTime, q.endTime) {
+			continue
+		}
+		blocks = append(blocks, b.BlockMeta)
+	}
+	return blocks, nil
+}
+
 type metadataQuery struct {
 	startTime      int64
 	endTime        int64
 	tenants        map[string]struct{}
 	serviceMatcher *labels.Matcher
+	// Services the query is restricted to, if the query selects
+	// them with the equality matcher: the blocks are looked up
+	// in the dataset index.
+	services map[string]struct{}
+	// Profile types the query is restricted to, if the query selects
+	// them with the equality matcher. Otherwise, the matcher is only
+	// applied to datasets.
+	profileTypeMatcher *labels.Matcher
+	profileTypes       map[string]struct{}
+	asOf               asOf
+}
+
+// asOf identifies the state of the metastore the query is resolved
+// against, by either the raft log index or the time. Zero value
+// stands for the current state.
+type asOf struct {
+	index uint64
+	time  int64 // Milliseconds since epoch.
+}
+
+// after reports whether the change made by the raft log entry
+// with the given index and time is not visible at the state.
+func (a asOf) after(index uint64, t int64) bool {
+	switch {
+	case a.index > 0:
+		return index > a.index
+	case a.time > 0:
+		return t > a.time
+	}
+	return false
 }
 
 func (q *metadataQuery) String() string {
-	return fmt.Sprintf("start: %d, end: %d, tenants: %v, serviceMatcher: %v", q.startTime, q.endTime, q.tenants, q.serviceMatcher)
+	return fmt.Sprintf("start: %d, end: %d, tenants: %v, serviceMatcher: %v, profileTypeMatcher: %v, asOf: %+v",
+		q.startTime, q.endTime, q.tenants, q.serviceMatcher, q.profileTypeMatcher, q.asOf)
 }
 
 func newMetadataQuery(request *metastorev1.QueryMetadataRequest) (*metadataQuery, error) {
@@ -123,6 +239,13 @@ func newMetadataQuery(request *metastorev1.QueryMetadataRequest) (*metadataQuery
 		startTime: request.StartTime,
 		endTime:   request.EndTime,
 		tenants:   make(map[string]struct{}, len(request.TenantId)),
+		asOf: asOf{
+			index: request.AsOfIndex,
+			time:  request.AsOfTime,
+		},
+	}
+	if q.asOf.index > 0 {
+		q.asOf.time = 0
 	}
 	for _, tenant := range request.TenantId {
 		q.tenants[tenant] = struct{}{}
@@ -132,14 +255,23 @@ func newMetadataQuery(request *metastorev1.QueryMetadataRequest) (*metadataQuery
 		return nil, fmt.Errorf("failed to parse label selectors: %w", err)
 	}
 	for _, m := range selectors {
-		if m.Name == model.LabelNameServiceName {
-			q.serviceMatcher = m
-			break
+		switch m.Name {
+		case model.LabelNameServiceName:
+			if q.serviceMatcher == nil {
+				q.serviceMatcher = m
+			}
+		case model.LabelNameProfileType:
+			if q.profileTypeMatcher == nil {
+				q.profileTypeMatcher = m
+			}
 		}
 	}
-	// We could also validate that the service has the profile type
-	// queried, but that's not really necessary: querying an irrelevant
-	// profile type is rather a rare/invalid case.
+	if m := q.serviceMatcher; m != nil && m.Type == labels.MatchEqual {
+		q.services = map[string]struct{}{m.Value: {}}
+	}
+	if m := q.profileTypeMatcher; m != nil && m.Type == labels.MatchEqual {
+		q.profileTypes = map[string]struct{}{m.Value: {}}
+	}
 	return q, nil
 }
 
@@ -151,21 +283,49 @@ func (q *metadataQuery) matchService(s *metastorev1.Dataset) bool {
 	if !inRange(s.MinTime, s.MaxTime, q.startTime, q.endTime) {
 		return false
 	}
-	if q.serviceMatcher != nil {
-		return q.serviceMatcher.Matches(s.Name)
+	if q.serviceMatcher != nil && !q.serviceMatcher.Matches(s.Name) {
+		return false
 	}
-	return true
+	return q.matchProfileType(s)
+}
+
+// matchProfileType reports whether the dataset might contain data of
+// the profile types queried. Datasets that do not list their profile
+// types are always matched.
+func (q *metadataQuery) matchProfileType(s *metastorev1.Dataset) bool {
+	if q.profileTypeMatcher == nil || len(s.ProfileTypes) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.ProfileTypes, q.profileTypeMatcher.Matches)
 }
 
 func inRange(blockStart, blockEnd, queryStart, queryEnd int64) bool {
 	return blockStart <= queryEnd && blockEnd >= queryStart
 }
 
+// cloneBlockForQuery copies the block metadata without the datasets.
+// The block is shared with the index and must not be modified, even
+// temporarily: it might be accessed by other readers concurrently.
+// The nested messages are not modified in the response, and are
+// therefore not copied.
 func cloneBlockForQuery(b *metastorev1.BlockMeta) *metastorev1.BlockMeta {
-	datasets := b.Datasets
-	b.Datasets = nil
-	c := b.CloneVT()
-	b.Datasets = datasets
-	c.Datasets = make([]*metastorev1.Dataset, 0, len(b.Datasets))
-	return c
+	return &metastorev1.BlockMeta{
+		FormatVersion:     b.FormatVersion,
+		Id:                b.Id,
+		MinTime:           b.MinTime,
+		MaxTime:           b.MaxTime,
+		Shard:             b.Shard,
+		CompactionLevel:   b.CompactionLevel,
+		TenantId:          b.TenantId,
+		Size:              b.Size,
+		CreatedBy:         b.CreatedBy,
+		IdempotencyKey:    b.IdempotencyKey,
+		OriginalCreatedAt: b.OriginalCreatedAt,
+		Quarantine:        b.Quarantine,
+		AddedAtIndex:      b.AddedAtIndex,
+		AddedAt:           b.AddedAt,
+		DeletedAt:         b.DeletedAt,
+		SourceBlocks:      b.SourceBlocks,
+		Datasets:          make([]*metastorev1.Dataset, 0, len(b.Datasets)),
+	}
 }
@@ -0,0 +1,47 @@
+package metastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+func Test_cloneBlockForQuery(t *testing.T) {
+	b := &metastorev1.BlockMeta{
+		FormatVersion:     1,
+		Id:                "block",
+		MinTime:           2,
+		MaxTime:           3,
+		Shard:             4,
+		CompactionLevel:   5,
+		TenantId:          "tenant",
+		Size:              6,
+		CreatedBy:         "writer",
+		IdempotencyKey:    &metastorev1.IdempotencyKey{WriterId: "writer", Sequence: 8},
+		OriginalCreatedAt: 9,
+		Quarantine:        &metastorev1.BlockQuarantine{QuarantinedAt: 10, Reason: "reason"},
+		AddedAtIndex:      11,
+		AddedAt:           12,
+		DeletedAt:         13,
+		SourceBlocks:      []string{"source"},
+		Datasets:          []*metastorev1.Dataset{{TenantId: "tenant", Name: "service"}},
+	}
+	// All the fields must be set: new fields must be copied as well.
+	var n int
+	b.ProtoReflect().Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		n++
+		return true
+	})
+	require.Equal(t, b.ProtoReflect().Descriptor().Fields().Len(), n)
+
+	original := b.CloneVT()
+	c := cloneBlockForQuery(b)
+	assert.True(t, original.EqualVT(b))
+	assert.Empty(t, c.Datasets)
+	c.Datasets = b.Datasets
+	assert.True(t, original.EqualVT(c))
+}
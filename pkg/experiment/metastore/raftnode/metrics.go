@@ -0,0 +1,54 @@
+package raftnode
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+type metrics struct {
+	proposeDuration   *prometheus.HistogramVec
+	proposalsInflight prometheus.Gauge
+	leaderRedirects   *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		// The duration includes the time the proposal spends in the raft
+		// queue, replication, and the FSM apply call on the leader.
+		proposeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:                            "raft_propose_duration_seconds",
+			Help:                            "Time it takes to commit and apply a proposed command.",
+			Buckets:                         prometheus.ExponentialBucketsRange(0.001, 10, 30),
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  50,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, []string{"command"}),
+
+		proposalsInflight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "raft_proposals_inflight",
+			Help: "Number of proposals waiting to be committed and applied.",
+		}),
+
+		leaderRedirects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "raft_leader_redirects_total",
+			Help: "Number of requests rejected because the node is not the leader. " +
+				"The command label is set to the raft command type, or to the read operation.",
+		}, []string{"command"}),
+	}
+	if reg != nil {
+		util.RegisterOrGet(reg, m.proposeDuration)
+		util.RegisterOrGet(reg, m.proposalsInflight)
+		util.RegisterOrGet(reg, m.leaderRedirects)
+	}
+	return m
+}
+
+// RegisterMetrics registers the raft node metrics without creating
+// the node. The metrics are used to generate monitoring resources.
+func RegisterMetrics(reg prometheus.Registerer) {
+	newMetrics(reg)
+	newObserverMetrics(reg)
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,6 +38,9 @@ type Config struct {
 	LogIndexCheckInterval time.Duration `yaml:"log_index_check_interval" doc:"hidden"`
 	ReadIndexMaxDistance  uint64        `yaml:"read_index_max_distance" doc:"hidden"`
 
+	StaleReadMaxLag         uint64        `yaml:"stale_read_max_lag" doc:"hidden"`
+	StaleReadMaxLastContact time.Duration `yaml:"stale_read_max_last_contact" doc:"hidden"`
+
 	WALCacheEntries       uint64        `yaml:"wal_cache_entries" doc:"hidden"`
 	TrailingLogs          uint64        `yaml:"trailing_logs" doc:"hidden"`
 	SnapshotsRetain       uint64        `yaml:"snapshots_retain" doc:"hidden"`
@@ -70,6 +74,9 @@ func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.DurationVar(&cfg.LogIndexCheckInterval, prefix+"log-index-check-interval", 14*time.Millisecond, "")
 	f.Uint64Var(&cfg.ReadIndexMaxDistance, prefix+"read-index-max-distance", 10<<10, "")
 
+	f.Uint64Var(&cfg.StaleReadMaxLag, prefix+"stale-read-max-lag", 256, "Maximum number of committed entries the replica may not have applied yet to serve stale reads.")
+	f.DurationVar(&cfg.StaleReadMaxLastContact, prefix+"stale-read-max-last-contact", 5*time.Second, "Maximum time since the follower last heard from the leader to serve stale reads.")
+
 	f.Uint64Var(&cfg.WALCacheEntries, prefix+"wal-cache-entries", defaultWALCacheEntries, "")
 	f.Uint64Var(&cfg.TrailingLogs, prefix+"trailing-logs", defaultTrailingLogs, "")
 	f.Uint64Var(&cfg.SnapshotsRetain, prefix+"snapshots-retain", defaultSnapshotsRetain, "")
@@ -101,6 +108,7 @@ type Node struct {
 
 	observer *Observer
 	service  *RaftNodeService
+	metrics  *metrics
 }
 
 func NewNode(
@@ -110,10 +118,11 @@ func NewNode(
 	fsm raft.FSM,
 ) (_ *Node, err error) {
 	n := Node{
-		logger: logger,
-		config: config,
-		reg:    reg,
-		fsm:    fsm,
+		logger:  logger,
+		config:  config,
+		reg:     reg,
+		fsm:     fsm,
+		metrics: newMetrics(reg),
 	}
 
 	defer func() {
@@ -269,16 +278,31 @@ func (n *Node) TransferLeadership() (err error) {
 	return err
 }
 
+// VerifyLeader returns an error if the node is not the leader. The error
+// includes the current leader, so the client may redirect the request.
+func (n *Node) VerifyLeader() error {
+	return WithRaftLeaderStatusDetails(n.raft.VerifyLeader().Error(), n.raft)
+}
+
 // Propose makes an attempt to apply the given command to the FSM.
 // The function returns an error if node is not the leader.
 func (n *Node) Propose(t fsm.RaftLogEntryType, m proto.Message) (resp proto.Message, err error) {
-	// TODO: logs, stats?
 	raw, err := fsm.MarshalEntry(t, m)
 	if err != nil {
 		return nil, err
 	}
+	cmdType := strconv.FormatUint(uint64(t), 10)
+	start := time.Now()
+	n.metrics.proposalsInflight.Inc()
+	defer func() {
+		n.metrics.proposalsInflight.Dec()
+		n.metrics.proposeDuration.WithLabelValues(cmdType).Observe(time.Since(start).Seconds())
+	}()
 	future := n.raft.Apply(raw, n.config.ApplyTimeout)
 	if err = future.Error(); err != nil {
+		if IsRaftLeadershipError(err) {
+			n.metrics.leaderRedirects.WithLabelValues(cmdType).Inc()
+		}
 		return nil, WithRaftLeaderStatusDetails(err, n.raft)
 	}
 	r := future.Response().(fsm.Response)
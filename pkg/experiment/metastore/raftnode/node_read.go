@@ -10,9 +10,11 @@ import (
 )
 
 var (
-	ErrConsistentRead = errors.New("consistent read failed")
-	ErrLagBehind      = errors.New("replica has fallen too far behind")
-	ErrAborted        = errors.New("aborted")
+	ErrConsistentRead  = errors.New("consistent read failed")
+	ErrStaleRead       = errors.New("stale read failed")
+	ErrLagBehind       = errors.New("replica has fallen too far behind")
+	ErrNoLeaderContact = errors.New("replica has not heard from the leader")
+	ErrAborted         = errors.New("aborted")
 )
 
 // ReadIndex is the lower bound for the state any query must operate against.
@@ -31,6 +33,13 @@ type ReadIndex struct {
 
 type Leader interface {
 	ReadIndex() (ReadIndex, error)
+	Barrier() (ReadIndex, error)
+}
+
+// Replica provides the read index known to the local node,
+// which may lag behind the leader's one.
+type Replica interface {
+	LocalReadIndex() (ReadIndex, error)
 }
 
 type FSM[Tx any] interface {
@@ -43,9 +52,11 @@ type FSM[Tx any] interface {
 // state machines.
 type StateReader[Tx any] struct {
 	leader        Leader
+	replica       Replica
 	fsm           FSM[Tx]
 	checkInterval time.Duration
 	maxDistance   uint64
+	maxStaleLag   uint64
 }
 
 // NewStateReader creates a new interface to query the replicated state.
@@ -76,17 +87,25 @@ type StateReader[Tx any] struct {
 // between the read index and the applied index exceeds the configured
 // threshold, the operation fails with ErrLagBehind. Any error returned by
 // the reader is wrapped with ErrConsistentRead.
+//
+// Stale reads are served by the local replica without contacting the
+// leader, if the replica has applied all but maxStaleLag entries of the
+// commit index known to it.
 func NewStateReader[Tx any](
 	leader Leader,
+	replica Replica,
 	fsm FSM[Tx],
 	checkInterval time.Duration,
 	maxDistance uint64,
+	maxStaleLag uint64,
 ) *StateReader[Tx] {
 	return &StateReader[Tx]{
 		leader:        leader,
+		replica:       replica,
 		fsm:           fsm,
 		checkInterval: checkInterval,
 		maxDistance:   maxDistance,
+		maxStaleLag:   maxStaleLag,
 	}
 }
 
@@ -109,14 +128,73 @@ func NewStateReader[Tx any](
 // It's caller's responsibility to handle errors encountered while using the
 // provided transaction, such as I/O errors or logical inconsistencies.
 func (r *StateReader[Tx]) ConsistentRead(ctx context.Context, read func(tx Tx, index ReadIndex)) error {
-	if err := r.consistentRead(ctx, read); err != nil {
+	if err := r.consistentRead(ctx, r.leader.ReadIndex, read); err != nil {
+		return fmt.Errorf("%w: %w", ErrConsistentRead, err)
+	}
+	return nil
+}
+
+// BarrierRead is similar to ConsistentRead, but instead of the leader's
+// commit index, the read index is obtained with a raft barrier: the leader
+// appends a new entry to the log and responds once the entry and all the
+// entries preceding it have been applied to its state. This is more costly,
+// as it incurs a disk write on the quorum, but the read is guaranteed to
+// observe all the changes that have been acknowledged before the request.
+func (r *StateReader[Tx]) BarrierRead(ctx context.Context, read func(tx Tx, index ReadIndex)) error {
+	if err := r.consistentRead(ctx, r.leader.Barrier, read); err != nil {
 		return fmt.Errorf("%w: %w", ErrConsistentRead, err)
 	}
 	return nil
 }
 
-func (r *StateReader[Tx]) consistentRead(ctx context.Context, read func(tx Tx, index ReadIndex)) error {
-	readIndex, err := r.WaitLeaderCommitIndexApplied(ctx)
+// StaleRead performs a read-only operation on the local state machine
+// without contacting the leader. The state observed may not include the
+// most recent updates: the staleness is bounded by the lag allowed, and
+// by the time since the follower last heard from the leader.
+//
+// If the replica is too far behind, the read fails with an error wrapping
+// ErrStaleRead, and it's guaranteed that the state has not been accessed.
+// Such reads should be retried with the leader.
+func (r *StateReader[Tx]) StaleRead(_ context.Context, read func(tx Tx, index ReadIndex)) error {
+	if err := r.staleRead(read); err != nil {
+		return fmt.Errorf("%w: %w", ErrStaleRead, err)
+	}
+	return nil
+}
+
+func (r *StateReader[Tx]) staleRead(read func(tx Tx, index ReadIndex)) error {
+	readIndex, err := r.replica.LocalReadIndex()
+	if err != nil {
+		return err
+	}
+	lagBehind := func() bool {
+		return r.fsm.AppliedIndex()+r.maxStaleLag < readIndex.CommitIndex
+	}
+	if lagBehind() {
+		return ErrLagBehind
+	}
+	var readErr error
+	fn := func(tx Tx) {
+		// The state might have been restored from a snapshot
+		// after the check and before the transaction began.
+		if lagBehind() {
+			readErr = ErrAborted
+			return
+		}
+		read(tx, readIndex)
+	}
+	if err = r.fsm.Read(fn); err != nil {
+		return err
+	}
+	return readErr
+}
+
+func (r *StateReader[Tx]) consistentRead(
+	ctx context.Context,
+	leaderReadIndex func() (ReadIndex, error),
+	read func(tx Tx, index ReadIndex),
+) error {
+	readIndex, err := r.waitReadIndexApplied(ctx, leaderReadIndex)
 	if err != nil {
 		return err
 	}
@@ -149,7 +227,11 @@ func (r *StateReader[Tx]) consistentRead(ctx context.Context, read func(tx Tx, i
 // WaitLeaderCommitIndexApplied blocks until the local
 // applied index reaches the leader read index
 func (r *StateReader[tx]) WaitLeaderCommitIndexApplied(ctx context.Context) (ReadIndex, error) {
-	readIndex, err := r.leader.ReadIndex()
+	return r.waitReadIndexApplied(ctx, r.leader.ReadIndex)
+}
+
+func (r *StateReader[tx]) waitReadIndexApplied(ctx context.Context, leaderReadIndex func() (ReadIndex, error)) (ReadIndex, error) {
+	readIndex, err := leaderReadIndex()
 	if err != nil {
 		return ReadIndex{}, err
 	}
@@ -163,11 +245,40 @@ func (r *StateReader[tx]) WaitLeaderCommitIndexApplied(ctx context.Context) (Rea
 
 func (n *Node) ReadIndex() (ReadIndex, error) {
 	v, err := n.readIndex()
+	if IsRaftLeadershipError(err) {
+		n.metrics.leaderRedirects.WithLabelValues("read_index").Inc()
+	}
+	return v, WithRaftLeaderStatusDetails(err, n.raft)
+}
+
+// Barrier returns the read index that covers all the entries
+// present in the leader's log at the time of the call.
+func (n *Node) Barrier() (ReadIndex, error) {
+	v, err := n.barrier()
+	if IsRaftLeadershipError(err) {
+		n.metrics.leaderRedirects.WithLabelValues("barrier").Inc()
+	}
 	return v, WithRaftLeaderStatusDetails(err, n.raft)
 }
 
 func (n *Node) AppliedIndex() uint64 { return n.raft.AppliedIndex() }
 
+// LocalReadIndex returns the commit index known to the local node. Unlike
+// ReadIndex, it does not involve the leader: a follower learns the commit
+// index from the leader's messages, therefore it may be behind the actual
+// one. If the follower has not heard from the leader for too long, the
+// call fails with ErrNoLeaderContact.
+func (n *Node) LocalReadIndex() (ReadIndex, error) {
+	term := n.raft.CurrentTerm()
+	if n.raft.State() != raft.Leader {
+		since := time.Since(n.raft.LastContact())
+		if since > n.config.StaleReadMaxLastContact {
+			return ReadIndex{}, fmt.Errorf("%w for %v", ErrNoLeaderContact, since.Truncate(time.Millisecond))
+		}
+	}
+	return ReadIndex{CommitIndex: n.raft.CommitIndex(), Term: term}, nil
+}
+
 func (n *Node) readIndex() (ReadIndex, error) {
 	// > If the leader has not yet marked an entry from its current term
 	// > committed, it waits until it has done so. The Leader Completeness
@@ -217,6 +328,22 @@ func (n *Node) readIndex() (ReadIndex, error) {
 	return ReadIndex{CommitIndex: commitIndex, Term: term}, nil
 }
 
+func (n *Node) barrier() (ReadIndex, error) {
+	term := n.raft.CurrentTerm()
+	// The barrier entry can only be committed by the leader of the current
+	// term, once it is replicated to the quorum. Therefore, there's no need
+	// to verify the leadership explicitly, as it's done in readIndex. When
+	// the future resolves, all the preceding entries have been applied to
+	// the leader's state machine.
+	if err := n.raft.Barrier(n.config.ApplyTimeout).Error(); err != nil {
+		return ReadIndex{}, err
+	}
+	if n.raft.CurrentTerm() != term {
+		return ReadIndex{}, raft.ErrLeadershipLost
+	}
+	return ReadIndex{CommitIndex: n.raft.AppliedIndex(), Term: term}, nil
+}
+
 func (n *Node) waitLastIndexCommitted() error {
 	ctx, cancel := context.WithTimeout(context.Background(), n.config.ApplyTimeout)
 	defer cancel()
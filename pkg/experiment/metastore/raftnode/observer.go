@@ -18,34 +18,49 @@ type Observer struct {
 	raft     *raft.Raft
 	observer *raft.Observer
 	state    *prometheus.GaugeVec
+	leader   prometheus.Counter
 	handlers []StateHandler
 	c        chan raft.Observation
 	stop     chan struct{}
 	done     chan struct{}
 }
 
-func NewRaftStateObserver(logger log.Logger, r *raft.Raft, reg prometheus.Registerer) *Observer {
-	o := &Observer{
-		logger: logger,
-		raft:   r,
-		c:      make(chan raft.Observation, 1),
-		stop:   make(chan struct{}),
-		done:   make(chan struct{}),
-	}
-	o.state = prometheus.NewGaugeVec(
+func newObserverMetrics(reg prometheus.Registerer) (*prometheus.GaugeVec, prometheus.Counter) {
+	state := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "raft_state",
 			Help: "Current Raft state",
 		},
 		[]string{"state"},
 	)
+	leader := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "raft_leader_changes_total",
+			Help: "Number of observed Raft leader changes",
+		},
+	)
 	if reg != nil {
-		reg.MustRegister(o.state)
+		reg.MustRegister(state, leader)
 	}
+	return state, leader
+}
+
+func NewRaftStateObserver(logger log.Logger, r *raft.Raft, reg prometheus.Registerer) *Observer {
+	o := &Observer{
+		logger: logger,
+		raft:   r,
+		c:      make(chan raft.Observation, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	o.state, o.leader = newObserverMetrics(reg)
 	_ = level.Debug(o.logger).Log("msg", "registering raft state observer")
 	o.observer = raft.NewObserver(o.c, true, func(o *raft.Observation) bool {
-		_, ok := o.Data.(raft.RaftState)
-		return ok
+		switch o.Data.(type) {
+		case raft.RaftState, raft.LeaderObservation:
+			return true
+		}
+		return false
 	})
 	r.RegisterObserver(o.observer)
 	o.updateRaftState()
@@ -75,8 +90,13 @@ func (o *Observer) run() {
 	}()
 	for {
 		select {
-		case <-o.c:
-			o.updateRaftState()
+		case obs := <-o.c:
+			switch obs.Data.(type) {
+			case raft.RaftState:
+				o.updateRaftState()
+			case raft.LeaderObservation:
+				o.leader.Inc()
+			}
 		case <-o.stop:
 			return
 		}
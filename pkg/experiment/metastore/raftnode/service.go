@@ -8,6 +8,7 @@ import (
 
 type RaftNode interface {
 	ReadIndex() (ReadIndex, error)
+	Barrier() (ReadIndex, error)
 	NodeInfo() (*raftnodepb.NodeInfo, error)
 }
 
@@ -21,11 +22,17 @@ func NewRaftNodeService(node RaftNode) *RaftNodeService {
 }
 
 // ReadIndex returns the current commit index and verifies leadership.
+// If the barrier is requested, the index returned is the applied index
+// of the leader after the barrier.
 func (svc *RaftNodeService) ReadIndex(
-	context.Context,
-	*raftnodepb.ReadIndexRequest,
+	_ context.Context,
+	req *raftnodepb.ReadIndexRequest,
 ) (*raftnodepb.ReadIndexResponse, error) {
-	read, err := svc.node.ReadIndex()
+	readIndex := svc.node.ReadIndex
+	if req.Barrier {
+		readIndex = svc.node.Barrier
+	}
+	read, err := readIndex()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,247 @@
+package metastore
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/util/hyperloglog"
+)
+
+const (
+	tenantCardinalityWindow = 15 * time.Minute
+	// Tenants beyond the top-k (by the number of series) are reported
+	// in aggregate, under the tenantCardinalityOther label value.
+	tenantCardinalityTopK  = 20
+	tenantCardinalityOther = "__other__"
+	// Precision of the services and profile types sketches:
+	// 256B per sketch with the estimate error of about 6.5%.
+	tenantCardinalitySketchPrecision = 8
+	// maxTrackedDatasets limits the number of (service, shard) pairs
+	// tracked per tenant for the series estimate.
+	maxTrackedDatasets = 10 << 10
+)
+
+// tenantCardinality tracks the cardinality of the tenant data based on the
+// metadata of the recently added segments (blocks of the compaction level 0):
+// the number of distinct services and profile types, and the estimated
+// number of series.
+//
+// A series is written to exactly one shard, and the datasets report the
+// number of series they include. The number of series of the tenant is
+// estimated as the sum of the largest dataset series count observed for
+// every (service, shard) pair. The estimate is a lower bound, as series
+// may not be present in every segment.
+//
+// The data is tracked in two generations rotated every window, therefore
+// the estimates cover the data added within the last one to two windows.
+type tenantCardinality struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	tenants map[string]*tenantDatasets
+}
+
+type tenantDatasets struct {
+	rotatedAt time.Time
+	current   *datasetGeneration
+	previous  *datasetGeneration
+}
+
+type datasetGeneration struct {
+	services     *hyperloglog.Sketch
+	profileTypes *hyperloglog.Sketch
+	series       map[datasetShard]uint64
+}
+
+type datasetShard struct {
+	service string
+	shard   uint32
+}
+
+type tenantCardinalityStats struct {
+	tenant       string
+	services     uint64
+	profileTypes uint64
+	series       uint64
+}
+
+func newTenantCardinality() *tenantCardinality {
+	return &tenantCardinality{
+		window:  tenantCardinalityWindow,
+		tenants: make(map[string]*tenantDatasets),
+	}
+}
+
+func newDatasetGeneration() *datasetGeneration {
+	return &datasetGeneration{
+		services:     hyperloglog.New(tenantCardinalitySketchPrecision),
+		profileTypes: hyperloglog.New(tenantCardinalitySketchPrecision),
+		series:       make(map[datasetShard]uint64),
+	}
+}
+
+func (g *datasetGeneration) reset() {
+	g.services.Reset()
+	g.profileTypes.Reset()
+	clear(g.series)
+}
+
+func (g *datasetGeneration) empty() bool { return g.services.Empty() }
+
+func (c *tenantCardinality) observe(b *metastorev1.BlockMeta, now time.Time) {
+	if b.CompactionLevel != 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ds := range b.Datasets {
+		t, ok := c.tenants[ds.TenantId]
+		if !ok {
+			t = &tenantDatasets{
+				rotatedAt: now,
+				current:   newDatasetGeneration(),
+				previous:  newDatasetGeneration(),
+			}
+			c.tenants[ds.TenantId] = t
+		}
+		t.rotate(now, c.window)
+		g := t.current
+		g.services.Insert(xxhash.Sum64String(ds.Name))
+		for _, pt := range ds.ProfileTypes {
+			g.profileTypes.Insert(xxhash.Sum64String(pt))
+		}
+		k := datasetShard{service: ds.Name, shard: b.Shard}
+		if n, found := g.series[k]; found || len(g.series) < maxTrackedDatasets {
+			g.series[k] = max(n, ds.Series)
+		}
+	}
+}
+
+func (t *tenantDatasets) rotate(now time.Time, window time.Duration) {
+	elapsed := now.Sub(t.rotatedAt)
+	if elapsed < window {
+		return
+	}
+	t.previous, t.current = t.current, t.previous
+	t.current.reset()
+	if elapsed >= 2*window {
+		t.previous.reset()
+	}
+	t.rotatedAt = now
+}
+
+// stats returns the cardinality estimates of the tenants, ordered by the
+// number of series, descending. Tenants that have not been observed
+// within the last two windows are removed.
+func (c *tenantCardinality) stats(now time.Time) []tenantCardinalityStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := make([]tenantCardinalityStats, 0, len(c.tenants))
+	for tenant, t := range c.tenants {
+		t.rotate(now, c.window)
+		if t.current.empty() && t.previous.empty() {
+			delete(c.tenants, tenant)
+			continue
+		}
+		s := tenantCardinalityStats{
+			tenant:       tenant,
+			services:     hyperloglog.Estimate(t.current.services, t.previous.services),
+			profileTypes: hyperloglog.Estimate(t.current.profileTypes, t.previous.profileTypes),
+		}
+		for k, n := range t.previous.series {
+			if _, found := t.current.series[k]; !found {
+				s.series += n
+			}
+		}
+		for k, n := range t.current.series {
+			s.series += max(n, t.previous.series[k])
+		}
+		stats = append(stats, s)
+	}
+	slices.SortFunc(stats, func(a, b tenantCardinalityStats) int {
+		if a.series != b.series {
+			if a.series > b.series {
+				return -1
+			}
+			return 1
+		}
+		if a.tenant < b.tenant {
+			return -1
+		}
+		return 1
+	})
+	return stats
+}
+
+type tenantCardinalityCollector struct {
+	cardinality *tenantCardinality
+	topK        int
+
+	tenants      *prometheus.Desc
+	services     *prometheus.Desc
+	profileTypes *prometheus.Desc
+	series       *prometheus.Desc
+}
+
+func newTenantCardinalityCollector(c *tenantCardinality) *tenantCardinalityCollector {
+	variableLabels := []string{"tenant"}
+	return &tenantCardinalityCollector{
+		cardinality: c,
+		topK:        tenantCardinalityTopK,
+
+		tenants: prometheus.NewDesc(
+			"active_tenants",
+			"The number of tenants with data added recently.",
+			nil, nil,
+		),
+		services: prometheus.NewDesc(
+			"tenant_active_services",
+			"The estimated number of services with data added recently. "+
+				"Tenants beyond the top-k by series are aggregated.",
+			variableLabels, nil,
+		),
+		profileTypes: prometheus.NewDesc(
+			"tenant_active_profile_types",
+			"The estimated number of profile types with data added recently. "+
+				"Tenants beyond the top-k by series are aggregated.",
+			variableLabels, nil,
+		),
+		series: prometheus.NewDesc(
+			"tenant_estimated_series",
+			"The estimated number of series with data added recently. "+
+				"Tenants beyond the top-k by series are aggregated.",
+			variableLabels, nil,
+		),
+	}
+}
+
+func (c *tenantCardinalityCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tenants
+	ch <- c.services
+	ch <- c.profileTypes
+	ch <- c.series
+}
+
+func (c *tenantCardinalityCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.cardinality.stats(time.Now())
+	ch <- prometheus.MustNewConstMetric(c.tenants, prometheus.GaugeValue, float64(len(stats)))
+	if len(stats) > c.topK {
+		other := tenantCardinalityStats{tenant: tenantCardinalityOther}
+		for _, s := range stats[c.topK:] {
+			other.services += s.services
+			other.profileTypes += s.profileTypes
+			other.series += s.series
+		}
+		stats = append(stats[:c.topK], other)
+	}
+	for _, s := range stats {
+		ch <- prometheus.MustNewConstMetric(c.services, prometheus.GaugeValue, float64(s.services), s.tenant)
+		ch <- prometheus.MustNewConstMetric(c.profileTypes, prometheus.GaugeValue, float64(s.profileTypes), s.tenant)
+		ch <- prometheus.MustNewConstMetric(c.series, prometheus.GaugeValue, float64(s.series), s.tenant)
+	}
+}
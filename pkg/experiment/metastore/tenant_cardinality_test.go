@@ -0,0 +1,85 @@
+package metastore
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+func Test_tenantCardinality(t *testing.T) {
+	c := newTenantCardinality()
+	now := time.Unix(0, 0)
+	block := func(shard uint32, level uint32, datasets ...*metastorev1.Dataset) *metastorev1.BlockMeta {
+		return &metastorev1.BlockMeta{Shard: shard, CompactionLevel: level, Datasets: datasets}
+	}
+	dataset := func(tenant, service string, series uint64, profileTypes ...string) *metastorev1.Dataset {
+		return &metastorev1.Dataset{TenantId: tenant, Name: service, Series: series, ProfileTypes: profileTypes}
+	}
+
+	c.observe(block(1, 0,
+		dataset("a", "svc-1", 10, "cpu", "memory"),
+		dataset("b", "svc-1", 5, "cpu"),
+	), now)
+	c.observe(block(1, 0, dataset("a", "svc-1", 7, "cpu")), now)
+	c.observe(block(2, 0, dataset("a", "svc-1", 3, "cpu")), now)
+	c.observe(block(1, 0, dataset("a", "svc-2", 20, "wall")), now)
+	// Compacted blocks are ignored.
+	c.observe(block(1, 1, dataset("a", "svc-3", 100, "cpu")), now)
+
+	expected := []tenantCardinalityStats{
+		{tenant: "a", services: 2, profileTypes: 3, series: 33},
+		{tenant: "b", services: 1, profileTypes: 1, series: 5},
+	}
+	assert.Equal(t, expected, c.stats(now))
+
+	// The previous generation is still accounted.
+	now = now.Add(c.window)
+	c.observe(block(1, 0, dataset("a", "svc-1", 15, "cpu")), now)
+	expected[0].series = 38
+	assert.Equal(t, expected, c.stats(now))
+
+	now = now.Add(c.window)
+	expected = []tenantCardinalityStats{{tenant: "a", services: 1, profileTypes: 1, series: 15}}
+	assert.Equal(t, expected, c.stats(now))
+
+	now = now.Add(c.window)
+	assert.Empty(t, c.stats(now))
+	assert.Empty(t, c.tenants)
+}
+
+func Test_tenantCardinalityCollector(t *testing.T) {
+	c := newTenantCardinality()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		c.observe(&metastorev1.BlockMeta{
+			Datasets: []*metastorev1.Dataset{{
+				TenantId:     "tenant-" + strconv.Itoa(i),
+				Name:         "svc",
+				Series:       uint64(i + 1),
+				ProfileTypes: []string{"cpu"},
+			}},
+		}, now)
+	}
+	collector := newTenantCardinalityCollector(c)
+	collector.topK = 2
+
+	expected := `
+# HELP active_tenants The number of tenants with data added recently.
+# TYPE active_tenants gauge
+active_tenants 5
+# HELP tenant_estimated_series The estimated number of series with data added recently. Tenants beyond the top-k by series are aggregated.
+# TYPE tenant_estimated_series gauge
+tenant_estimated_series{tenant="__other__"} 6
+tenant_estimated_series{tenant="tenant-3"} 4
+tenant_estimated_series{tenant="tenant-4"} 5
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"active_tenants", "tenant_estimated_series"))
+}
@@ -81,6 +81,24 @@ func (svc *TenantService) getTenantStats(tenant string, ctx context.Context) (*m
 	return &metastorev1.GetTenantResponse{Stats: stats}, nil
 }
 
+// GetTenantBlockStats returns the stats of the tenant blocks in the index.
+// The stats are maintained as blocks are added and removed, therefore the
+// request does not involve reading the blocks.
+func (svc *TenantService) GetTenantBlockStats(
+	ctx context.Context,
+	req *metastorev1.GetTenantBlockStatsRequest,
+) (resp *metastorev1.GetTenantBlockStatsResponse, err error) {
+	read := func(*bbolt.Tx, raftnode.ReadIndex) {
+		resp = &metastorev1.GetTenantBlockStatsResponse{
+			Stats: svc.index.TenantBlockStats(req.TenantId),
+		}
+	}
+	if readErr := svc.state.ConsistentRead(ctx, read); readErr != nil {
+		return nil, status.Error(codes.Unavailable, readErr.Error())
+	}
+	return resp, nil
+}
+
 func (svc *TenantService) DeleteTenant(
 	context.Context,
 	*metastorev1.DeleteTenantRequest,
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/oklog/ulid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/memory"
+)
+
+func TestAsOfQueryMetadata(t *testing.T) {
+	cfg := new(metastore.Config)
+	flagext.DefaultValues(cfg)
+
+	ms := NewMetastoreSet(t, cfg, 3, memory.NewInMemBucket())
+	defer ms.Close()
+
+	now := time.Now()
+	newBlock := func() *metastorev1.BlockMeta {
+		return &metastorev1.BlockMeta{
+			Id:      ulid.MustNew(ulid.Timestamp(now), rand.Reader).String(),
+			MinTime: now.Add(-time.Minute).UnixMilli(),
+			MaxTime: now.UnixMilli(),
+			Datasets: []*metastorev1.Dataset{{
+				TenantId: "tenant-a",
+				Name:     "service-a",
+				MinTime:  now.Add(-time.Minute).UnixMilli(),
+				MaxTime:  now.UnixMilli(),
+			}},
+		}
+	}
+	request := func(asOf uint64) *metastorev1.QueryMetadataRequest {
+		return &metastorev1.QueryMetadataRequest{
+			TenantId:  []string{"tenant-a"},
+			StartTime: now.Add(-time.Hour).UnixMilli(),
+			EndTime:   now.Add(time.Hour).UnixMilli(),
+			Query:     "{}",
+			AsOfIndex: asOf,
+		}
+	}
+	query := func(asOf uint64) (*metastorev1.QueryMetadataResponse, error) {
+		return ms.Client.QueryMetadata(context.Background(), request(asOf))
+	}
+
+	a := newBlock()
+	_, err := ms.Client.AddBlock(context.Background(), &metastorev1.AddBlockRequest{Block: a})
+	require.NoError(t, err)
+	resp, err := query(0)
+	require.NoError(t, err)
+	require.Len(t, resp.Blocks, 1)
+	assert.Equal(t, a.Id, resp.Blocks[0].Id)
+	assert.NotZero(t, resp.Blocks[0].AddedAtIndex)
+	assert.NotZero(t, resp.Blocks[0].AddedAt)
+	snapshot := resp.Index
+	require.NotZero(t, snapshot)
+
+	b := newBlock()
+	_, err = ms.Client.AddBlock(context.Background(), &metastorev1.AddBlockRequest{Block: b})
+	require.NoError(t, err)
+	resp, err = query(0)
+	require.NoError(t, err)
+	assert.Len(t, resp.Blocks, 2)
+	assert.Greater(t, resp.Index, snapshot)
+
+	// The block added after the state queried is not included.
+	resp, err = query(snapshot)
+	require.NoError(t, err)
+	require.Len(t, resp.Blocks, 1)
+	assert.Equal(t, a.Id, resp.Blocks[0].Id)
+	assert.Equal(t, snapshot, resp.Index)
+
+	// The state must be known to the replica.
+	_, err = ms.Instances[0].QueryMetadata(context.Background(), request(snapshot+1e6))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
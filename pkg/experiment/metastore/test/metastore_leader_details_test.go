@@ -24,7 +24,10 @@ func TestRaftDetailsAddBlock(t *testing.T) {
 
 	errors := 0
 	m := &metastorev1.BlockMeta{
-		Id: ulid.MustNew(1, rand.Reader).String(),
+		Id:       ulid.MustNew(1, rand.Reader).String(),
+		MinTime:  1,
+		MaxTime:  1,
+		Datasets: []*metastorev1.Dataset{{TenantId: "tenant", MinTime: 1, MaxTime: 1}},
 	}
 	for _, it := range ms.Instances {
 		_, err := it.IndexServiceClient.AddBlock(context.Background(), &metastorev1.AddBlockRequest{
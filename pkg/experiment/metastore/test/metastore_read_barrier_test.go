@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/oklog/ulid"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/memory"
+)
+
+func TestReadBarrierQueryMetadata(t *testing.T) {
+	cfg := new(metastore.Config)
+	flagext.DefaultValues(cfg)
+
+	ms := NewMetastoreSet(t, cfg, 3, memory.NewInMemBucket())
+	defer ms.Close()
+
+	now := time.Now()
+	m := &metastorev1.BlockMeta{
+		Id:      ulid.MustNew(ulid.Timestamp(now), rand.Reader).String(),
+		MinTime: now.Add(-time.Minute).UnixMilli(),
+		MaxTime: now.UnixMilli(),
+		Datasets: []*metastorev1.Dataset{{
+			TenantId: "tenant-a",
+			Name:     "service-a",
+			MinTime:  now.Add(-time.Minute).UnixMilli(),
+			MaxTime:  now.UnixMilli(),
+		}},
+	}
+	_, err := ms.Client.AddBlock(context.Background(), &metastorev1.AddBlockRequest{Block: m})
+	require.NoError(t, err)
+
+	// Any replica must observe the block once it has been added.
+	for _, it := range ms.Instances {
+		resp, err := it.MetadataQueryServiceClient.QueryMetadata(context.Background(), &metastorev1.QueryMetadataRequest{
+			TenantId:    []string{"tenant-a"},
+			StartTime:   now.Add(-time.Hour).UnixMilli(),
+			EndTime:     now.Add(time.Hour).UnixMilli(),
+			Query:       "{}",
+			ReadBarrier: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Blocks, 1)
+		require.Equal(t, m.Id, resp.Blocks[0].Id)
+	}
+}
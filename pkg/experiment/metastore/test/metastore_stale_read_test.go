@@ -0,0 +1,61 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/oklog/ulid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/memory"
+)
+
+func TestStaleReadQueryMetadata(t *testing.T) {
+	cfg := new(metastore.Config)
+	flagext.DefaultValues(cfg)
+
+	ms := NewMetastoreSet(t, cfg, 3, memory.NewInMemBucket())
+	defer ms.Close()
+
+	now := time.Now()
+	m := &metastorev1.BlockMeta{
+		Id:      ulid.MustNew(ulid.Timestamp(now), rand.Reader).String(),
+		MinTime: now.Add(-time.Minute).UnixMilli(),
+		MaxTime: now.UnixMilli(),
+		Datasets: []*metastorev1.Dataset{{
+			TenantId: "tenant-a",
+			Name:     "service-a",
+			MinTime:  now.Add(-time.Minute).UnixMilli(),
+			MaxTime:  now.UnixMilli(),
+		}},
+	}
+	_, err := ms.Client.AddBlock(context.Background(), &metastorev1.AddBlockRequest{Block: m})
+	require.NoError(t, err)
+
+	req := &metastorev1.QueryMetadataRequest{
+		TenantId:       []string{"tenant-a"},
+		StartTime:      now.Add(-time.Hour).UnixMilli(),
+		EndTime:        now.Add(time.Hour).UnixMilli(),
+		Query:          "{}",
+		AllowStaleRead: true,
+	}
+	// Every replica serves the query from its local state, which
+	// eventually includes the block.
+	for _, it := range ms.Instances {
+		require.Eventually(t, func() bool {
+			resp, err := it.MetadataQueryServiceClient.QueryMetadata(context.Background(), req)
+			return err == nil && len(resp.Blocks) == 1 && resp.Blocks[0].Id == m.Id
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	resp, err := ms.Client.QueryMetadata(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Blocks, 1)
+	assert.Equal(t, m.Id, resp.Blocks[0].Id)
+}
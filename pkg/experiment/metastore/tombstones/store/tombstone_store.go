@@ -1,6 +1,7 @@
 package store
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -14,7 +15,12 @@ import (
 
 var ErrInvalidTombstoneEntry = errors.New("invalid tombstone entry")
 
-var tombstoneBucketName = []byte("tombstones")
+var (
+	tombstoneBucketName      = []byte("tombstones")
+	tombstoneBlockBucketName = []byte("tombstone_blocks")
+	tombstoneMetaBucketName  = []byte("tombstone_meta")
+	retainedSinceKey         = []byte("retained_since")
+)
 
 type TombstoneEntry struct {
 	Index      uint64
@@ -22,15 +28,53 @@ type TombstoneEntry struct {
 	*metastorev1.Tombstones
 }
 
-type TombstoneStore struct{ bucketName []byte }
+// RemovedBlock is the metadata of a block removed from the
+// index, retained along with the tombstones of the block.
+type RemovedBlock struct {
+	Index      uint64
+	AppendedAt int64
+	*metastorev1.BlockMeta
+}
+
+// TombstoneStore keeps the tombstone entries, ordered by the raft log
+// index. The metadata of the removed blocks is kept in a separate bucket
+// under the key of the entry followed by the block identifier, so that
+// it is not loaded with the entries.
+type TombstoneStore struct {
+	bucketName      []byte
+	blockBucketName []byte
+	metaBucketName  []byte
+}
 
 func NewTombstoneStore() *TombstoneStore {
-	return &TombstoneStore{bucketName: tombstoneBucketName}
+	return &TombstoneStore{
+		bucketName:      tombstoneBucketName,
+		blockBucketName: tombstoneBlockBucketName,
+		metaBucketName:  tombstoneMetaBucketName,
+	}
 }
 
 func (s *TombstoneStore) CreateBuckets(tx *bbolt.Tx) error {
-	_, err := tx.CreateBucketIfNotExists(s.bucketName)
-	return err
+	entries, err := tx.CreateBucketIfNotExists(s.bucketName)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.CreateBucketIfNotExists(s.blockBucketName); err != nil {
+		return err
+	}
+	if tx.Bucket(s.metaBucketName) != nil {
+		return nil
+	}
+	meta, err := tx.CreateBucket(s.metaBucketName)
+	if err != nil {
+		return err
+	}
+	// The removed blocks of the entries created before
+	// the metadata was retained are not known.
+	if k, _ := entries.Cursor().Last(); len(k) >= 16 {
+		return meta.Put(retainedSinceKey, k[:16])
+	}
+	return nil
 }
 
 func (s *TombstoneStore) StoreTombstones(tx *bbolt.Tx, entry TombstoneEntry) error {
@@ -38,8 +82,70 @@ func (s *TombstoneStore) StoreTombstones(tx *bbolt.Tx, entry TombstoneEntry) err
 	return tx.Bucket(s.bucketName).Put(kv.Key, kv.Value)
 }
 
+// StoreRemovedBlocks retains the metadata of the blocks removed with the
+// tombstones entry, until the entry is deleted.
+func (s *TombstoneStore) StoreRemovedBlocks(tx *bbolt.Tx, entry TombstoneEntry, blocks ...*metastorev1.BlockMeta) error {
+	bucket := tx.Bucket(s.blockBucketName)
+	k := marshalTombstoneEntryKey(entry)
+	for _, b := range blocks {
+		v, _ := b.MarshalVT()
+		if err := bucket.Put(append(k[:16:16], b.Id...), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTombstones deletes the entry and the metadata of the removed
+// blocks. Once the entry is deleted, the blocks removed at or before
+// the entry index may not be listed.
 func (s *TombstoneStore) DeleteTombstones(tx *bbolt.Tx, entry TombstoneEntry) error {
-	return tx.Bucket(s.bucketName).Delete(marshalTombstoneEntryKey(entry))
+	k := marshalTombstoneEntryKey(entry)
+	if err := tx.Bucket(s.bucketName).Delete(k); err != nil {
+		return err
+	}
+	c := tx.Bucket(s.blockBucketName).Cursor()
+	for bk, _ := c.Seek(k); bk != nil && bytes.HasPrefix(bk, k); bk, _ = c.Seek(k) {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	if index, _ := s.RetainedSince(tx); index >= entry.Index {
+		return nil
+	}
+	return tx.Bucket(s.metaBucketName).Put(retainedSinceKey, k)
+}
+
+// RetainedSince returns the index and time of the last deleted
+// entry: the removed blocks are only known after that point.
+func (s *TombstoneStore) RetainedSince(tx *bbolt.Tx) (index uint64, appendedAt int64) {
+	v := tx.Bucket(s.metaBucketName).Get(retainedSinceKey)
+	if len(v) < 16 {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint64(v[0:8]), int64(binary.BigEndian.Uint64(v[8:16]))
+}
+
+// ListRemovedBlocks returns the retained metadata of the
+// blocks removed after the given index, in the order of removal.
+func (s *TombstoneStore) ListRemovedBlocks(tx *bbolt.Tx, after uint64) ([]RemovedBlock, error) {
+	var blocks []RemovedBlock
+	c := tx.Bucket(s.blockBucketName).Cursor()
+	for k, v := c.Seek(binary.BigEndian.AppendUint64(nil, after+1)); k != nil; k, v = c.Next() {
+		if len(k) < 16 {
+			return nil, ErrInvalidTombstoneEntry
+		}
+		b := RemovedBlock{
+			Index:      binary.BigEndian.Uint64(k[0:8]),
+			AppendedAt: int64(binary.BigEndian.Uint64(k[8:16])),
+			BlockMeta:  new(metastorev1.BlockMeta),
+		}
+		if err := b.UnmarshalVT(v); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTombstoneEntry, err)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
 }
 
 func (s *TombstoneStore) ListEntries(tx *bbolt.Tx) iter.Iterator[TombstoneEntry] {
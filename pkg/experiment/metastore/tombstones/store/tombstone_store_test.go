@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -99,3 +100,79 @@ func TestTombstoneStore_DeleteQueuedEntries(t *testing.T) {
 	assert.Nil(t, iter.Close())
 	require.NoError(t, tx.Rollback())
 }
+
+func TestTombstoneStore_RemovedBlocks(t *testing.T) {
+	db := test.BoltDB(t)
+
+	s := NewTombstoneStore()
+	tx, err := db.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, s.CreateBuckets(tx))
+
+	entries := make([]TombstoneEntry, 3)
+	for i := range entries {
+		entries[i] = TombstoneEntry{
+			Index:      uint64(i + 1),
+			AppendedAt: int64(i + 1),
+			Tombstones: &metastorev1.Tombstones{
+				Blocks: &metastorev1.BlockTombstones{Name: fmt.Sprint(i)},
+			},
+		}
+		require.NoError(t, s.StoreTombstones(tx, entries[i]))
+		require.NoError(t, s.StoreRemovedBlocks(tx, entries[i],
+			&metastorev1.BlockMeta{Id: fmt.Sprintf("block-%d-a", i)},
+			&metastorev1.BlockMeta{Id: fmt.Sprintf("block-%d-b", i)},
+		))
+	}
+	require.NoError(t, tx.Commit())
+
+	tx, err = db.Begin(true)
+	require.NoError(t, err)
+	index, appendedAt := s.RetainedSince(tx)
+	assert.Zero(t, index)
+	assert.Zero(t, appendedAt)
+
+	blocks, err := s.ListRemovedBlocks(tx, 1)
+	require.NoError(t, err)
+	require.Len(t, blocks, 4)
+	assert.Equal(t, uint64(2), blocks[0].Index)
+	assert.Equal(t, int64(2), blocks[0].AppendedAt)
+	assert.Equal(t, "block-1-a", blocks[0].Id)
+	assert.Equal(t, "block-2-b", blocks[3].Id)
+
+	require.NoError(t, s.DeleteTombstones(tx, entries[1]))
+	require.NoError(t, s.DeleteTombstones(tx, entries[0]))
+	index, appendedAt = s.RetainedSince(tx)
+	assert.Equal(t, uint64(2), index)
+	assert.Equal(t, int64(2), appendedAt)
+
+	blocks, err = s.ListRemovedBlocks(tx, 0)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "block-2-a", blocks[0].Id)
+	assert.Equal(t, "block-2-b", blocks[1].Id)
+	require.NoError(t, tx.Commit())
+}
+
+func TestTombstoneStore_RetainedSince_ExistingEntries(t *testing.T) {
+	db := test.BoltDB(t)
+
+	// Entries stored before the removed blocks are retained.
+	tx, err := db.Begin(true)
+	require.NoError(t, err)
+	_, err = tx.CreateBucket(tombstoneBucketName)
+	require.NoError(t, err)
+	s := &TombstoneStore{bucketName: tombstoneBucketName}
+	require.NoError(t, s.StoreTombstones(tx, TombstoneEntry{
+		Index:      10,
+		AppendedAt: 20,
+		Tombstones: &metastorev1.Tombstones{Blocks: &metastorev1.BlockTombstones{Name: "a"}},
+	}))
+
+	s = NewTombstoneStore()
+	require.NoError(t, s.CreateBuckets(tx))
+	index, appendedAt := s.RetainedSince(tx)
+	assert.Equal(t, uint64(10), index)
+	assert.Equal(t, int64(20), appendedAt)
+	require.NoError(t, tx.Commit())
+}
@@ -12,8 +12,13 @@ type tombstoneQueue struct{ head, tail *tombstones }
 type tombstoneKey string
 
 func (k *tombstoneKey) set(t *metastorev1.Tombstones) bool {
-	if t.Blocks != nil {
+	switch {
+	case t.Blocks != nil:
 		*k = tombstoneKey(t.Blocks.Name)
+	case t.Dictionaries != nil:
+		*k = tombstoneKey(t.Dictionaries.Name)
+	case t.Archives != nil:
+		*k = tombstoneKey(t.Archives.Name)
 	}
 	return len(*k) > 0
 }
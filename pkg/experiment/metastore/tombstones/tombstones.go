@@ -13,7 +13,10 @@ import (
 
 type TombstoneStore interface {
 	StoreTombstones(*bbolt.Tx, store.TombstoneEntry) error
+	StoreRemovedBlocks(*bbolt.Tx, store.TombstoneEntry, ...*metastorev1.BlockMeta) error
 	DeleteTombstones(*bbolt.Tx, store.TombstoneEntry) error
+	RetainedSince(*bbolt.Tx) (uint64, int64)
+	ListRemovedBlocks(*bbolt.Tx, uint64) ([]store.RemovedBlock, error)
 	ListEntries(*bbolt.Tx) iter.Iterator[store.TombstoneEntry]
 	CreateBuckets(*bbolt.Tx) error
 }
@@ -62,7 +65,9 @@ func (x *Tombstones) ListTombstones(before time.Time) iter.Iterator[*metastorev1
 	}
 }
 
-func (x *Tombstones) AddTombstones(tx *bbolt.Tx, cmd *raft.Log, t *metastorev1.Tombstones) error {
+// AddTombstones adds the tombstones. The metadata of the removed
+// blocks, if provided, is retained until the tombstones are deleted.
+func (x *Tombstones) AddTombstones(tx *bbolt.Tx, cmd *raft.Log, t *metastorev1.Tombstones, removed ...*metastorev1.BlockMeta) error {
 	var k tombstoneKey
 	if !k.set(t) {
 		return nil
@@ -75,7 +80,23 @@ func (x *Tombstones) AddTombstones(tx *bbolt.Tx, cmd *raft.Log, t *metastorev1.T
 	if !x.put(k, v) {
 		return nil
 	}
-	return x.store.StoreTombstones(tx, v)
+	if err := x.store.StoreTombstones(tx, v); err != nil {
+		return err
+	}
+	return x.store.StoreRemovedBlocks(tx, v, removed...)
+}
+
+// ListRemovedBlocks returns the metadata of the blocks removed after
+// the given index, provided that their tombstones are still retained.
+func (x *Tombstones) ListRemovedBlocks(tx *bbolt.Tx, after uint64) ([]store.RemovedBlock, error) {
+	return x.store.ListRemovedBlocks(tx, after)
+}
+
+// RetainedSince returns the index and the time (in nanoseconds) after
+// which the removed blocks are known: the tombstones of the blocks
+// removed earlier might have been deleted.
+func (x *Tombstones) RetainedSince(tx *bbolt.Tx) (index uint64, appendedAt int64) {
+	return x.store.RetainedSince(tx)
 }
 
 func (x *Tombstones) DeleteTombstones(tx *bbolt.Tx, cmd *raft.Log, tombstones ...*metastorev1.Tombstones) error {
@@ -105,13 +126,13 @@ func (x *Tombstones) put(k tombstoneKey, v store.TombstoneEntry) bool {
 	}
 	e := &tombstones{TombstoneEntry: v}
 	x.tombstones[k] = e
+	if !x.queue.push(e) {
+		return false
+	}
 	if v.Tombstones.Blocks != nil {
-		if x.queue.push(e) {
-			x.putBlockTombstones(v.Tombstones.Blocks)
-			return true
-		}
+		x.putBlockTombstones(v.Tombstones.Blocks)
 	}
-	return false
+	return true
 }
 
 func (x *Tombstones) delete(k tombstoneKey) (t *tombstones) {
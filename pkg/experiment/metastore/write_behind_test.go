@@ -0,0 +1,174 @@
+package metastore
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/hashicorp/raft"
+	"github.com/oklog/ulid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction/compactor"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/compaction/scheduler"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/events"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/fsm"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/labelrewrite"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/tombstones"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/writers"
+)
+
+type writeBehindFSM struct {
+	*fsm.FSM
+	index *index.Index
+}
+
+// newWriteBehindFSM creates the FSM with the index in the write-behind
+// mode and the handlers of the blocks and the compaction plan.
+func newWriteBehindFSM(t *testing.T, dir string) *writeBehindFSM {
+	logger := log.NewNopLogger()
+	f, err := fsm.New(logger, nil, dir, fsm.Config{})
+	require.NoError(t, err)
+
+	indexConfig := index.DefaultConfig
+	indexConfig.BlockWriteBehindInterval = time.Minute
+	indexConfig.BlockWriteBehindQueueSize = 10
+	strategy := compactor.DefaultStrategy()
+	strategy.MaxBlocksPerLevel = []uint{2}
+
+	idx := index.NewIndex(logger, index.NewStoreWithConfig(&indexConfig), &indexConfig, nil)
+	ts := tombstones.NewTombstones(tombstones.NewStore())
+	c := compactor.NewCompactor(compactor.Config{Strategy: strategy}, compactor.NewStore(), ts, idx, nil)
+	s := scheduler.NewScheduler(scheduler.Config{MaxFailures: 3, LeaseDuration: time.Minute, MaxQueueSize: 10}, scheduler.NewStore(), nil)
+	rewriter := labelrewrite.NewRewriter(labelrewrite.NewStore(), idx, uint32(strategy.MaxLevel))
+	e := events.NewEvents(events.Config{RetentionPeriod: time.Hour}, events.NewStore())
+	w := writers.NewQuarantine(writers.NewStore())
+
+	indexHandler := NewIndexCommandHandler(logger, idx, ts, w, c, e, newTenantCardinality())
+	fsm.RegisterRaftCommandHandler(f,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_ADD_BLOCK_METADATA),
+		indexHandler.AddBlock)
+	fsm.RegisterRaftCommandHandler(f,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_QUARANTINE_WRITER),
+		w.QuarantineWriter)
+	compactionHandler := NewCompactionCommandHandler(logger, idx, c, c, s, ts, rewriter, e)
+	fsm.RegisterRaftCommandHandler(f,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE),
+		compactionHandler.GetCompactionPlanUpdate)
+	fsm.RegisterRaftCommandHandler(f,
+		fsm.RaftLogEntryType(raft_log.RaftCommand_RAFT_COMMAND_UPDATE_COMPACTION_PLAN),
+		compactionHandler.UpdateCompactionPlan)
+
+	f.RegisterRestorer(ts, c, s, idx, rewriter, e, w)
+	f.RegisterFlusher(idx)
+	require.NoError(t, f.Init())
+	return &writeBehindFSM{FSM: f, index: idx}
+}
+
+func (f *writeBehindFSM) apply(t *testing.T, cmd *raft.Log) proto.Message {
+	resp, ok := f.Apply(cmd).(fsm.Response)
+	require.True(t, ok)
+	require.NoError(t, resp.Err)
+	return resp.Data
+}
+
+func (f *writeBehindFSM) findBlock(t *testing.T, id string) *metastorev1.BlockMeta {
+	var b *metastorev1.BlockMeta
+	require.NoError(t, f.Read(func(tx *bbolt.Tx) {
+		b = f.index.FindBlock(tx, 1, "tenant", id)
+	}))
+	return b
+}
+
+func Test_WriteBehind_CrashReplay(t *testing.T) {
+	now := time.Now()
+	var commands []*raft.Log
+	command := func(typ raft_log.RaftCommand, req proto.Message) *raft.Log {
+		data, err := fsm.MarshalEntry(fsm.RaftLogEntryType(typ), req)
+		require.NoError(t, err)
+		cmd := &raft.Log{
+			Type:       raft.LogCommand,
+			Term:       1,
+			Index:      uint64(len(commands) + 1),
+			Data:       data,
+			AppendedAt: now,
+		}
+		commands = append(commands, cmd)
+		return cmd
+	}
+	addBlock := func(id string) *raft.Log {
+		return command(raft_log.RaftCommand_RAFT_COMMAND_ADD_BLOCK_METADATA, &metastorev1.AddBlockRequest{
+			Block: &metastorev1.BlockMeta{
+				FormatVersion: 1,
+				Id:            id,
+				TenantId:      "tenant",
+				Shard:         1,
+				CreatedBy:     "writer",
+				MinTime:       now.UnixMilli(),
+				MaxTime:       now.UnixMilli(),
+				Datasets: []*metastorev1.Dataset{{
+					TenantId: "tenant",
+					Name:     "service",
+					MinTime:  now.UnixMilli(),
+					MaxTime:  now.UnixMilli(),
+				}},
+			},
+		})
+	}
+	ids := make([]string, 3)
+	for j := range ids {
+		ids[j] = ulid.MustNew(ulid.Timestamp(now), rand.Reader).String()
+	}
+
+	dir := t.TempDir()
+	f := newWriteBehindFSM(t, dir)
+	t.Cleanup(f.Shutdown)
+	f.apply(t, addBlock(ids[0]))
+	f.apply(t, addBlock(ids[1]))
+	// The first two blocks make a compaction job.
+	update := f.apply(t, command(raft_log.RaftCommand_RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE,
+		&raft_log.GetCompactionPlanUpdateRequest{AssignJobsMax: 1},
+	)).(*raft_log.GetCompactionPlanUpdateResponse)
+	require.Len(t, update.PlanUpdate.NewJobs, 1)
+	f.apply(t, command(raft_log.RaftCommand_RAFT_COMMAND_UPDATE_COMPACTION_PLAN,
+		&raft_log.UpdateCompactionPlanRequest{Term: update.Term, PlanUpdate: update.PlanUpdate},
+	))
+	f.apply(t, addBlock(ids[2]))
+	// The blocks added by the writer before it is
+	// quarantined must not be refused at replay.
+	f.apply(t, command(raft_log.RaftCommand_RAFT_COMMAND_QUARANTINE_WRITER,
+		&raft_log.QuarantineWriterRequest{WriterId: "writer"},
+	))
+
+	// The node crashes before the pending blocks are flushed: the
+	// database is copied as is, and the state in memory is lost.
+	crashed := t.TempDir()
+	data, err := os.ReadFile(filepath.Join(dir, "metastore.boltdb"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(crashed, "metastore.boltdb"), data, 0o644))
+
+	f = newWriteBehindFSM(t, crashed)
+	t.Cleanup(f.Shutdown)
+	// The raft log is replayed at restart: the commands
+	// applied before the crash must not be applied again.
+	for _, cmd := range commands {
+		f.apply(t, cmd)
+	}
+	for _, id := range ids {
+		assert.NotNil(t, f.findBlock(t, id), id)
+	}
+	update = f.apply(t, command(raft_log.RaftCommand_RAFT_COMMAND_GET_COMPACTION_PLAN_UPDATE,
+		&raft_log.GetCompactionPlanUpdateRequest{AssignJobsMax: 1},
+	)).(*raft_log.GetCompactionPlanUpdateResponse)
+	// No job is created for the blocks compacted already.
+	assert.Empty(t, update.PlanUpdate.NewJobs)
+}
@@ -0,0 +1,83 @@
+package metastore
+
+import (
+	"flag"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type WriterRateLimitConfig struct {
+	WriterBlockRateLimit float64 `yaml:"writer_block_rate_limit"`
+	WriterBlockBurst     int     `yaml:"writer_block_burst"`
+}
+
+func (cfg *WriterRateLimitConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.Float64Var(&cfg.WriterBlockRateLimit, prefix+"writer-block-rate-limit", 0, "Maximum number of blocks per second a single writer instance can register. Blocks exceeding the limit are refused, and the writer falls back to the dead letter queue. 0 to disable.")
+	f.IntVar(&cfg.WriterBlockBurst, prefix+"writer-block-burst", 10, "Maximum number of blocks a single writer instance can register at once, above the rate limit.")
+}
+
+const writerLimiterIdleTimeout = 10 * time.Minute
+
+// writerRateLimiter limits the rate of block registration per writer
+// instance. The limits are local to the metastore instance: only the
+// raft leader accepts new blocks, therefore the limits are reset when
+// the leadership changes, which is acceptable.
+type writerRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	writers  map[string]*writerLimiter
+	prunedAt time.Time
+}
+
+type writerLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newWriterRateLimiter(config WriterRateLimitConfig) *writerRateLimiter {
+	return &writerRateLimiter{
+		limit:   rate.Limit(config.WriterBlockRateLimit),
+		burst:   max(config.WriterBlockBurst, 1),
+		writers: make(map[string]*writerLimiter),
+	}
+}
+
+// allow reports whether the writer can register a block now. If the
+// limit is exceeded, the time the writer should wait is returned.
+func (l *writerRateLimiter) allow(writer string, now time.Time) (bool, time.Duration) {
+	if l.limit <= 0 {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(now)
+	w, ok := l.writers[writer]
+	if !ok {
+		w = &writerLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.writers[writer] = w
+	}
+	w.lastSeen = now
+	if w.limiter.AllowN(now, 1) {
+		return true, 0
+	}
+	missing := 1 - w.limiter.TokensAt(now)
+	return false, time.Duration(missing / float64(l.limit) * float64(time.Second))
+}
+
+// prune removes limiters of the writers that have not registered
+// blocks for a while: writer instances come and go.
+func (l *writerRateLimiter) prune(now time.Time) {
+	if now.Sub(l.prunedAt) < writerLimiterIdleTimeout {
+		return
+	}
+	l.prunedAt = now
+	for id, w := range l.writers {
+		if now.Sub(w.lastSeen) > writerLimiterIdleTimeout {
+			delete(l.writers, id)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package metastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+func TestWriterRateLimiter(t *testing.T) {
+	l := newWriterRateLimiter(WriterRateLimitConfig{WriterBlockRateLimit: 2, WriterBlockBurst: 2})
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 2; i++ {
+		ok, _ := l.allow("writer-1", now)
+		assert.True(t, ok)
+	}
+	ok, retryAfter := l.allow("writer-1", now)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Writers are limited independently.
+	ok, _ = l.allow("writer-2", now)
+	assert.True(t, ok)
+
+	ok, _ = l.allow("writer-1", now.Add(500*time.Millisecond))
+	assert.True(t, ok)
+
+	// Idle writers are removed.
+	ok, _ = l.allow("writer-2", now.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Len(t, l.writers, 1)
+
+	l = newWriterRateLimiter(WriterRateLimitConfig{})
+	for i := 0; i < 100; i++ {
+		ok, _ = l.allow("writer-1", now)
+		assert.True(t, ok)
+	}
+}
+
+func TestBlockRejectedError(t *testing.T) {
+	err := blockRejectedError(codes.ResourceExhausted, &metastorev1.AddBlockResponse{
+		Result:         metastorev1.AddBlockResult_ADD_BLOCK_RESULT_RATE_LIMITED,
+		RejectedReason: "rate limited",
+		RetryAfter:     500,
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	resp, ok := BlockRejectionFromStatusDetails(err)
+	require.True(t, ok)
+	assert.Equal(t, metastorev1.AddBlockResult_ADD_BLOCK_RESULT_RATE_LIMITED, resp.Result)
+	assert.Equal(t, int64(500), resp.RetryAfter)
+
+	_, ok = BlockRejectionFromStatusDetails(status.Error(codes.Internal, "error"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,123 @@
+package writers
+
+import (
+	"sync"
+
+	"github.com/hashicorp/raft"
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/writers/store"
+)
+
+type QuarantineStore interface {
+	StoreQuarantine(*bbolt.Tx, *metastorev1.WriterQuarantine) error
+	DeleteQuarantine(*bbolt.Tx, string) error
+	ListQuarantines(*bbolt.Tx) ([]*metastorev1.WriterQuarantine, error)
+	CreateBuckets(*bbolt.Tx) error
+}
+
+// Quarantine keeps the writer instances whose blocks are refused by the
+// metastore, e.g., because they flood the metastore with tiny blocks.
+// A writer is identified by the BlockMeta.CreatedBy field.
+//
+// Quarantined writers are kept in memory, so that the blocks can be
+// refused before they are proposed to the raft log. The state is only
+// modified through the raft log.
+type Quarantine struct {
+	mu      sync.RWMutex
+	writers map[string]*metastorev1.WriterQuarantine
+	store   QuarantineStore
+}
+
+func NewQuarantine(store QuarantineStore) *Quarantine {
+	return &Quarantine{
+		writers: make(map[string]*metastorev1.WriterQuarantine),
+		store:   store,
+	}
+}
+
+func NewStore() *store.QuarantineStore {
+	return store.NewQuarantineStore()
+}
+
+// QuarantineWriter quarantines the writer. The quarantine of a writer
+// that is already quarantined is not changed. Requests with an empty
+// writer identifier are ignored.
+func (q *Quarantine) QuarantineWriter(
+	tx *bbolt.Tx, cmd *raft.Log, req *raft_log.QuarantineWriterRequest,
+) (*raft_log.QuarantineWriterResponse, error) {
+	if req.WriterId == "" {
+		return new(raft_log.QuarantineWriterResponse), nil
+	}
+	if x := q.Quarantined(req.WriterId); x != nil {
+		return &raft_log.QuarantineWriterResponse{Quarantine: x}, nil
+	}
+	x := &metastorev1.WriterQuarantine{
+		WriterId:      req.WriterId,
+		QuarantinedAt: cmd.AppendedAt.UnixMilli(),
+		Reason:        req.Reason,
+		ReportedBy:    req.ReportedBy,
+	}
+	if err := q.store.StoreQuarantine(tx, x); err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	q.writers[x.WriterId] = x
+	q.mu.Unlock()
+	return &raft_log.QuarantineWriterResponse{Quarantine: x}, nil
+}
+
+// ReleaseWriter releases the quarantine of the writer.
+func (q *Quarantine) ReleaseWriter(
+	tx *bbolt.Tx, _ *raft.Log, req *raft_log.ReleaseWriterRequest,
+) (*raft_log.ReleaseWriterResponse, error) {
+	if q.Quarantined(req.WriterId) == nil {
+		return new(raft_log.ReleaseWriterResponse), nil
+	}
+	if err := q.store.DeleteQuarantine(tx, req.WriterId); err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	delete(q.writers, req.WriterId)
+	q.mu.Unlock()
+	return &raft_log.ReleaseWriterResponse{Released: true}, nil
+}
+
+// Quarantined returns the quarantine of the writer,
+// or nil if the writer is not quarantined.
+func (q *Quarantine) Quarantined(writer string) *metastorev1.WriterQuarantine {
+	if writer == "" {
+		return nil
+	}
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.writers[writer]
+}
+
+// ListQuarantinedWriters returns the quarantined writers in the order
+// of their identifiers. It only accesses the store and therefore can
+// be used for reads.
+func (q *Quarantine) ListQuarantinedWriters(tx *bbolt.Tx) ([]*metastorev1.WriterQuarantine, error) {
+	return q.store.ListQuarantines(tx)
+}
+
+func (q *Quarantine) Init(tx *bbolt.Tx) error {
+	return q.store.CreateBuckets(tx)
+}
+
+func (q *Quarantine) Restore(tx *bbolt.Tx) error {
+	list, err := q.store.ListQuarantines(tx)
+	if err != nil {
+		return err
+	}
+	writers := make(map[string]*metastorev1.WriterQuarantine, len(list))
+	for _, x := range list {
+		writers[x.WriterId] = x
+	}
+	q.mu.Lock()
+	q.writers = writers
+	q.mu.Unlock()
+	return nil
+}
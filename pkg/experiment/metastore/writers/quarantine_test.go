@@ -0,0 +1,70 @@
+package writers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+	"github.com/grafana/pyroscope/pkg/test"
+)
+
+func TestQuarantine(t *testing.T) {
+	db := test.BoltDB(t)
+	q := NewQuarantine(NewStore())
+	require.NoError(t, db.Update(q.Init))
+
+	cmd := &raft.Log{Index: 1, AppendedAt: time.UnixMilli(1000)}
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		resp, err := q.QuarantineWriter(tx, cmd, &raft_log.QuarantineWriterRequest{WriterId: "writer-1", Reason: "tiny blocks"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), resp.Quarantine.QuarantinedAt)
+
+		// The quarantine is not changed.
+		cmd := &raft.Log{Index: 2, AppendedAt: time.UnixMilli(2000)}
+		resp, err = q.QuarantineWriter(tx, cmd, &raft_log.QuarantineWriterRequest{WriterId: "writer-1", Reason: "other"})
+		require.NoError(t, err)
+		assert.Equal(t, "tiny blocks", resp.Quarantine.Reason)
+
+		_, err = q.QuarantineWriter(tx, cmd, &raft_log.QuarantineWriterRequest{WriterId: "writer-2"})
+		require.NoError(t, err)
+		resp, err = q.QuarantineWriter(tx, cmd, &raft_log.QuarantineWriterRequest{})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Quarantine)
+		return nil
+	}))
+
+	assert.NotNil(t, q.Quarantined("writer-1"))
+	assert.NotNil(t, q.Quarantined("writer-2"))
+	assert.Nil(t, q.Quarantined("writer-3"))
+	assert.Nil(t, q.Quarantined(""))
+
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		resp, err := q.ReleaseWriter(tx, cmd, &raft_log.ReleaseWriterRequest{WriterId: "writer-2"})
+		require.NoError(t, err)
+		assert.True(t, resp.Released)
+		resp, err = q.ReleaseWriter(tx, cmd, &raft_log.ReleaseWriterRequest{WriterId: "writer-3"})
+		require.NoError(t, err)
+		assert.False(t, resp.Released)
+		return nil
+	}))
+	assert.Nil(t, q.Quarantined("writer-2"))
+
+	q = NewQuarantine(NewStore())
+	require.NoError(t, db.Update(q.Init))
+	require.NoError(t, db.View(q.Restore))
+	assert.NotNil(t, q.Quarantined("writer-1"))
+	assert.Nil(t, q.Quarantined("writer-2"))
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		list, err := q.ListQuarantinedWriters(tx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "writer-1", list[0].WriterId)
+		assert.Equal(t, "tiny blocks", list[0].Reason)
+		return nil
+	}))
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+var quarantineBucketName = []byte("writer_quarantine")
+
+var ErrInvalidQuarantine = errors.New("invalid writer quarantine entry")
+
+// QuarantineStore keeps quarantined writer instances,
+// keyed by the writer identifier.
+type QuarantineStore struct{ bucketName []byte }
+
+func NewQuarantineStore() *QuarantineStore {
+	return &QuarantineStore{bucketName: quarantineBucketName}
+}
+
+func (s QuarantineStore) CreateBuckets(tx *bbolt.Tx) error {
+	_, err := tx.CreateBucketIfNotExists(s.bucketName)
+	return err
+}
+
+func (s QuarantineStore) StoreQuarantine(tx *bbolt.Tx, q *metastorev1.WriterQuarantine) error {
+	v, _ := q.MarshalVT()
+	return tx.Bucket(s.bucketName).Put([]byte(q.WriterId), v)
+}
+
+func (s QuarantineStore) DeleteQuarantine(tx *bbolt.Tx, writer string) error {
+	return tx.Bucket(s.bucketName).Delete([]byte(writer))
+}
+
+// ListQuarantines returns the quarantined writers
+// in the order of their identifiers.
+func (s QuarantineStore) ListQuarantines(tx *bbolt.Tx) ([]*metastorev1.WriterQuarantine, error) {
+	var list []*metastorev1.WriterQuarantine
+	err := tx.Bucket(s.bucketName).ForEach(func(k, v []byte) error {
+		var q metastorev1.WriterQuarantine
+		if err := q.UnmarshalVT(v); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidQuarantine, err)
+		}
+		list = append(list, &q)
+		return nil
+	})
+	return list, err
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/grpcclient"
@@ -18,13 +20,15 @@ import (
 )
 
 type Config struct {
-	Address          string            `yaml:"address"`
-	GRPCClientConfig grpcclient.Config `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate between the query-frontends and the query-schedulers."`
+	Address                  string            `yaml:"address"`
+	GRPCClientConfig         grpcclient.Config `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to communicate between the query-frontends and the query-schedulers."`
+	BlockQuarantineThreshold int               `yaml:"block_quarantine_threshold"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.StringVar(&cfg.Address, "query-backend.address", "localhost:9095", "")
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("query-backend.grpc-client-config", f)
+	f.IntVar(&cfg.BlockQuarantineThreshold, "query-backend.block-quarantine-threshold", 3, "Number of requests that fail to read a block because of data corruption, after which the block is quarantined: excluded from query results and compaction. 0 to disable.")
 }
 
 func (cfg *Config) Validate() error {
@@ -96,6 +100,7 @@ func (q *QueryBackend) merge(
 ) (*queryv1.InvokeResponse, error) {
 	request.QueryPlan = nil
 	m := newAggregator(request)
+	var d mergeDiagnostics
 	g, ctx := errgroup.WithContext(ctx)
 	for _, child := range children {
 		req := request.CloneVT()
@@ -104,13 +109,28 @@ func (q *QueryBackend) merge(
 		}
 		g.Go(util.RecoverPanic(func() error {
 			// TODO: Speculative retry.
-			return m.aggregateResponse(q.backendClient.Invoke(ctx, req))
+			resp, err := q.backendClient.Invoke(ctx, req)
+			if err != nil {
+				return err
+			}
+			d.observe(resp.Diagnostics)
+			start := time.Now()
+			err = m.aggregateResponse(resp, nil)
+			d.addMerge(time.Since(start))
+			return err
 		}))
 	}
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
-	return m.response()
+	start := time.Now()
+	resp, err := m.response()
+	if err != nil {
+		return nil, err
+	}
+	d.addMerge(time.Since(start))
+	resp.Diagnostics = d.diagnostics()
+	return resp, nil
 }
 
 func (q *QueryBackend) read(
@@ -123,5 +143,47 @@ func (q *QueryBackend) read(
 			Blocks: blocks,
 		},
 	}
-	return q.blockReader.Invoke(ctx, request)
+	start := time.Now()
+	resp, err := q.blockReader.Invoke(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	resp.Diagnostics = &queryv1.Diagnostics{ReadDurationNanos: int64(time.Since(start))}
+	return resp, nil
+}
+
+// mergeDiagnostics aggregates the diagnostics of the query plan nodes.
+// The children are executed concurrently, therefore the longest read
+// and merge durations are taken; the time spent merging the children
+// reports is added to the merge duration.
+type mergeDiagnostics struct {
+	mu    sync.Mutex
+	read  int64
+	merge int64
+	own   int64
+}
+
+func (d *mergeDiagnostics) observe(x *queryv1.Diagnostics) {
+	if x == nil {
+		return
+	}
+	d.mu.Lock()
+	d.read = max(d.read, x.ReadDurationNanos)
+	d.merge = max(d.merge, x.MergeDurationNanos)
+	d.mu.Unlock()
+}
+
+func (d *mergeDiagnostics) addMerge(t time.Duration) {
+	d.mu.Lock()
+	d.own += int64(t)
+	d.mu.Unlock()
+}
+
+func (d *mergeDiagnostics) diagnostics() *queryv1.Diagnostics {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &queryv1.Diagnostics{
+		ReadDurationNanos:  d.read,
+		MergeDurationNanos: d.merge + d.own,
+	}
 }
@@ -1,6 +1,7 @@
 package block
 
 import (
+	"cmp"
 	"context"
 	"crypto/rand"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"github.com/oklog/ulid"
 	"github.com/parquet-go/parquet-go"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/storage"
 	"golang.org/x/sync/errgroup"
 
@@ -22,6 +24,7 @@ import (
 	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/phlaredb/block"
+	schemav1 "github.com/grafana/pyroscope/pkg/phlaredb/schemas/v1"
 	"github.com/grafana/pyroscope/pkg/phlaredb/symdb"
 	"github.com/grafana/pyroscope/pkg/phlaredb/tsdb/index"
 	"github.com/grafana/pyroscope/pkg/util"
@@ -52,11 +55,35 @@ func WithCompactionDestination(storage objstore.Bucket) CompactionOption {
 	}
 }
 
+// WithCompactionLabelRewrite makes the compaction rewrite series labels
+// of the source blocks with the given rules. The compacted blocks retain
+// the compaction level of the source blocks. The rules are expected to be
+// obtained with LabelRewriteRules.
+func WithCompactionLabelRewrite(rules []*relabel.Config) CompactionOption {
+	return func(p *compactionConfig) {
+		p.labelRewrite = rules
+	}
+}
+
+// WithCompactionSharedSymbols makes the compaction store the symbols of
+// the compacted datasets in dictionaries shared by the blocks, instead of
+// the blocks themselves. The newest dictionary of a dataset is reused if
+// it includes all the stack traces of the compacted blocks; otherwise, a
+// new dictionary is created, extending the newest one, unless its size
+// exceeds maxSize.
+func WithCompactionSharedSymbols(maxSize int64) CompactionOption {
+	return func(p *compactionConfig) {
+		p.dictionaryMaxSize = maxSize
+	}
+}
+
 type compactionConfig struct {
-	objectOptions []ObjectOption
-	tempdir       string
-	source        objstore.BucketReader
-	destination   objstore.Bucket
+	objectOptions     []ObjectOption
+	tempdir           string
+	source            objstore.BucketReader
+	destination       objstore.Bucket
+	labelRewrite      []*relabel.Config
+	dictionaryMaxSize int64
 }
 
 func Compact(
@@ -75,7 +102,7 @@ func Compact(
 	}
 
 	objects := ObjectsFromMetas(storage, blocks, c.objectOptions...)
-	plan, err := PlanCompaction(objects)
+	plan, err := planCompaction(objects, c)
 	if err != nil {
 		return nil, err
 	}
@@ -100,6 +127,10 @@ func Compact(
 }
 
 func PlanCompaction(objects Objects) ([]*CompactionPlan, error) {
+	return planCompaction(objects, new(compactionConfig))
+}
+
+func planCompaction(objects Objects, c *compactionConfig) ([]*CompactionPlan, error) {
 	if len(objects) == 0 {
 		// Even if there's just a single object, we still need to rewrite it.
 		return nil, ErrNoBlocksToMerge
@@ -113,7 +144,10 @@ func PlanCompaction(objects Objects) ([]*CompactionPlan, error) {
 		}
 		level = max(level, obj.meta.CompactionLevel)
 	}
-	level++
+	if len(c.labelRewrite) == 0 {
+		// Rewritten blocks are not promoted to the next level.
+		level++
+	}
 
 	// Assuming that the first block in the job is the oldest one.
 	timestamp := ulid.MustParse(r.meta.Id).Time()
@@ -123,11 +157,19 @@ func PlanCompaction(objects Objects) ([]*CompactionPlan, error) {
 			tm, ok := m[s.TenantId]
 			if !ok {
 				tm = newBlockCompaction(timestamp, s.TenantId, r.meta.Shard, level)
+				tm.labelRewrite = c.labelRewrite
+				tm.dictionaryMaxSize = c.dictionaryMaxSize
 				m[s.TenantId] = tm
 			}
-			sm := tm.addDataset(s)
+			sm, err := tm.addDataset(s)
+			if err != nil {
+				return nil, err
+			}
 			// Bind objects to datasets.
 			sm.append(NewDataset(s, obj))
+			if !slices.Contains(tm.meta.SourceBlocks, obj.meta.Id) {
+				tm.meta.SourceBlocks = append(tm.meta.SourceBlocks, obj.meta.Id)
+			}
 		}
 	}
 
@@ -146,10 +188,12 @@ func PlanCompaction(objects Objects) ([]*CompactionPlan, error) {
 }
 
 type CompactionPlan struct {
-	tenantID   string
-	datasetMap map[string]*datasetCompaction
-	datasets   []*datasetCompaction
-	meta       *metastorev1.BlockMeta
+	tenantID          string
+	datasetMap        map[string]*datasetCompaction
+	datasets          []*datasetCompaction
+	meta              *metastorev1.BlockMeta
+	labelRewrite      []*relabel.Config
+	dictionaryMaxSize int64
 }
 
 func newBlockCompaction(unixMilli uint64, tenantID string, shard uint32, compactionLevel uint32) *CompactionPlan {
@@ -178,23 +222,35 @@ func (b *CompactionPlan) Compact(ctx context.Context, dst objstore.Bucket, tmpdi
 	}()
 	// Datasets are compacted in a strict order.
 	for _, s := range b.datasets {
+		if b.dictionaryMaxSize > 0 {
+			s.dictionary = newSharedSymbols(dst, b.dictionaryMaxSize, b.tenantID, b.meta.Shard, s.meta.Name)
+		}
 		if err = s.compact(ctx, w); err != nil {
 			return nil, fmt.Errorf("compacting block: %w", err)
 		}
 		b.meta.Datasets = append(b.meta.Datasets, s.meta)
 	}
+	b.meta.Size = w.Offset()
+	if err = w.WriteMetadata(b.meta); err != nil {
+		return nil, fmt.Errorf("writing block metadata: %w", err)
+	}
 	if err = w.Flush(ctx); err != nil {
 		return nil, fmt.Errorf("flushing block writer: %w", err)
 	}
-	b.meta.Size = w.Offset()
 	return b.meta, nil
 }
 
-func (b *CompactionPlan) addDataset(s *metastorev1.Dataset) *datasetCompaction {
-	sm, ok := b.datasetMap[s.Name]
+func (b *CompactionPlan) addDataset(s *metastorev1.Dataset) (*datasetCompaction, error) {
+	// Datasets may be merged, if the label rewrite renames the service.
+	name, err := rewriteServiceName(s.Name, b.labelRewrite)
+	if err != nil {
+		return nil, err
+	}
+	sm, ok := b.datasetMap[name]
 	if !ok {
-		sm = newDatasetCompaction(s.TenantId, s.Name)
-		b.datasetMap[s.Name] = sm
+		sm = newDatasetCompaction(s.TenantId, name)
+		sm.labelRewrite = b.labelRewrite
+		b.datasetMap[name] = sm
 		b.datasets = append(b.datasets, sm)
 	}
 	if b.meta.MinTime == 0 || s.MinTime < b.meta.MinTime {
@@ -203,7 +259,7 @@ func (b *CompactionPlan) addDataset(s *metastorev1.Dataset) *datasetCompaction {
 	if s.MaxTime > b.meta.MaxTime {
 		b.meta.MaxTime = s.MaxTime
 	}
-	return sm
+	return sm, nil
 }
 
 type datasetCompaction struct {
@@ -211,8 +267,12 @@ type datasetCompaction struct {
 	ptypes map[string]struct{}
 	path   string // Set at open.
 
-	datasets []*Dataset
+	datasets     []*Dataset
+	labelRewrite []*relabel.Config
+	// Optional. Set if the symbols are stored in a shared dictionary.
+	dictionary *sharedSymbols
 
+	seriesRewriter  *seriesRewriter
 	indexRewriter   *indexRewriter
 	symbolsRewriter *symbolsRewriter
 	profilesWriter  *profilesWriter
@@ -264,7 +324,7 @@ func (m *datasetCompaction) compact(ctx context.Context, w *Writer) (err error)
 	if err = m.mergeAndClose(ctx); err != nil {
 		return fmt.Errorf("failed to merge profiles: %w", err)
 	}
-	if err = m.writeTo(w); err != nil {
+	if err = m.writeTo(ctx, w); err != nil {
 		return fmt.Errorf("failed to write sections: %w", err)
 	}
 	return nil
@@ -292,14 +352,17 @@ func (m *datasetCompaction) open(ctx context.Context, path string) (err error) {
 		return err
 	}
 
+	if len(m.labelRewrite) > 0 {
+		m.seriesRewriter = newSeriesRewriter(m.labelRewrite)
+	}
 	m.indexRewriter = newIndexRewriter(m.path)
 	m.symbolsRewriter = newSymbolsRewriter(m.path)
 
-	g, ctx := errgroup.WithContext(ctx)
+	g, gctx := errgroup.WithContext(ctx)
 	for _, s := range m.datasets {
 		s := s
 		g.Go(util.RecoverPanic(func() error {
-			if openErr := s.Open(ctx, allSections...); openErr != nil {
+			if openErr := s.Open(gctx, allSections...); openErr != nil {
 				return fmt.Errorf("opening tenant dataset (block %s): %w", s.obj.path, openErr)
 			}
 			return nil
@@ -313,6 +376,33 @@ func (m *datasetCompaction) open(ctx context.Context, path string) (err error) {
 		return merr.Err()
 	}
 
+	if m.dictionary != nil {
+		if err = m.openDictionary(ctx); err != nil {
+			merr := multierror.New(fmt.Errorf("opening symbols dictionary: %w", err))
+			for _, s := range m.datasets {
+				merr.Add(s.Close())
+			}
+			return merr.Err()
+		}
+	}
+
+	return nil
+}
+
+func (m *datasetCompaction) openDictionary(ctx context.Context) error {
+	if err := m.dictionary.open(ctx, m.datasets); err != nil {
+		return err
+	}
+	if m.dictionary.covered() {
+		// The stack traces are mapped to these of the dictionary.
+		m.symbolsRewriter.mapping = m.dictionary.mapping
+		return nil
+	}
+	if m.dictionary.reader != nil {
+		// The new dictionary must include all the stack
+		// traces of the dictionary it replaces.
+		return m.symbolsRewriter.include(ctx, m.dictionary.reader)
+	}
 	return nil
 }
 
@@ -342,14 +432,34 @@ func (m *datasetCompaction) merge(ctx context.Context) (err error) {
 			return err
 		}
 	}
-	return rows.Err()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if m.seriesRewriter == nil {
+		return nil
+	}
+	for _, r := range m.seriesRewriter.sortedRows() {
+		if err = m.writeSeriesRow(r); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *datasetCompaction) writeRow(r ProfileEntry) (err error) {
-	if err = m.indexRewriter.rewriteRow(r); err != nil {
+	if err = m.symbolsRewriter.rewriteRow(r); err != nil {
 		return err
 	}
-	if err = m.symbolsRewriter.rewriteRow(r); err != nil {
+	if m.seriesRewriter != nil {
+		// The rows are written once all of them are rewritten.
+		m.seriesRewriter.rewriteRow(r)
+		return nil
+	}
+	return m.writeSeriesRow(r)
+}
+
+func (m *datasetCompaction) writeSeriesRow(r ProfileEntry) error {
+	if err := m.indexRewriter.rewriteRow(r); err != nil {
 		return err
 	}
 	return m.profilesWriter.writeRow(r)
@@ -365,6 +475,7 @@ func (m *datasetCompaction) close() (err error) {
 		m.series = m.indexRewriter.NumSeries()
 		m.profiles = m.profilesWriter.profiles
 		m.symbolsRewriter = nil
+		m.seriesRewriter = nil
 		m.indexRewriter = nil
 		m.profilesWriter = nil
 		// Note that m.datasets are closed by merge
@@ -377,16 +488,27 @@ func (m *datasetCompaction) close() (err error) {
 	return err
 }
 
-func (m *datasetCompaction) writeTo(w *Writer) (err error) {
+func (m *datasetCompaction) writeTo(ctx context.Context, w *Writer) (err error) {
 	off := w.Offset()
-	m.meta.TableOfContents, err = w.ReadFromFiles(
+	files := []string{
 		FileNameProfilesParquet,
 		block.IndexFilename,
 		symdb.DefaultFileName,
-	)
+	}
+	if m.dictionary != nil {
+		if m.meta.SymbolsDictionary, err = m.dictionary.flush(ctx, m.path); err != nil {
+			return err
+		}
+		// The symbols section is empty.
+		files = files[:len(files)-1]
+	}
+	m.meta.TableOfContents, err = w.ReadFromFiles(files...)
 	if err != nil {
 		return err
 	}
+	if m.dictionary != nil {
+		m.meta.TableOfContents = append(m.meta.TableOfContents, w.Offset())
+	}
 	m.meta.Size = w.Offset() - off
 	m.meta.ProfileTypes = make([]string, 0, len(m.ptypes))
 	for pt := range m.ptypes {
@@ -397,7 +519,55 @@ func (m *datasetCompaction) writeTo(w *Writer) (err error) {
 }
 
 func (m *datasetCompaction) cleanup() error {
-	return os.RemoveAll(m.path)
+	merr := multierror.New()
+	if m.dictionary != nil {
+		merr.Add(m.dictionary.Close())
+	}
+	merr.Add(os.RemoveAll(m.path))
+	return merr.Err()
+}
+
+// seriesRewriter applies the label rewrite rules to the series. The rules
+// may map distinct series to the same labels, and change the order of the
+// series; besides, a dataset may include series of multiple services, if
+// they are renamed to the same one. Therefore, the rewritten rows are kept
+// in memory and sorted by the new series labels and timestamp before they
+// are written, so that the index has exactly one entry per series.
+type seriesRewriter struct {
+	rules  []*relabel.Config
+	series map[model.Fingerprint]seriesLabels // By source fingerprint.
+	rows   []ProfileEntry
+}
+
+func newSeriesRewriter(rules []*relabel.Config) *seriesRewriter {
+	return &seriesRewriter{
+		rules:  rules,
+		series: make(map[model.Fingerprint]seriesLabels),
+	}
+}
+
+func (rw *seriesRewriter) rewriteRow(e ProfileEntry) {
+	s, ok := rw.series[e.Fingerprint]
+	if !ok {
+		s.labels, s.fingerprint = rewriteLabels(e.Labels.Clone(), rw.rules)
+		rw.series[e.Fingerprint] = s
+	}
+	// The row buffers are reused by the source iterators.
+	e.Row = schemav1.ProfileRow(parquet.Row(e.Row).Clone())
+	e.Labels = s.labels
+	e.Fingerprint = s.fingerprint
+	e.Dataset = nil
+	rw.rows = append(rw.rows, e)
+}
+
+func (rw *seriesRewriter) sortedRows() []ProfileEntry {
+	slices.SortStableFunc(rw.rows, func(a, b ProfileEntry) int {
+		if c := phlaremodel.CompareLabelPairs(a.Labels, b.Labels); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+	return rw.rows
 }
 
 func newIndexRewriter(path string) *indexRewriter {
@@ -407,6 +577,7 @@ func newIndexRewriter(path string) *indexRewriter {
 	}
 }
 
+// indexRewriter expects the rows to be ordered by series.
 type indexRewriter struct {
 	series     []seriesLabels
 	symbols    map[string]struct{}
@@ -484,6 +655,9 @@ type symbolsRewriter struct {
 	w       *symdb.SymDB
 	rw      map[*Dataset]*symdb.Rewriter
 	samples uint64
+	// Optional. If set, the stack trace identifiers are mapped to
+	// these of a shared dictionary, and no symbols are written.
+	mapping map[*Dataset]map[uint64][]uint32
 
 	stacktraces []uint32
 }
@@ -498,6 +672,9 @@ func newSymbolsRewriter(path string) *symbolsRewriter {
 }
 
 func (s *symbolsRewriter) rewriteRow(e ProfileEntry) (err error) {
+	if s.mapping != nil {
+		return s.mapRow(e)
+	}
 	rw := s.rewriterFor(e.Dataset)
 	e.Row.ForStacktraceIDsValues(func(values []parquet.Value) {
 		s.loadStacktraceIDs(values)
@@ -512,6 +689,55 @@ func (s *symbolsRewriter) rewriteRow(e ProfileEntry) (err error) {
 	return err
 }
 
+func (s *symbolsRewriter) mapRow(e ProfileEntry) (err error) {
+	m, ok := s.mapping[e.Dataset]
+	if !ok {
+		// The dataset refers to the dictionary.
+		e.Row.ForStacktraceIDsValues(func(values []parquet.Value) {
+			s.samples += uint64(len(values))
+		})
+		return nil
+	}
+	ids := m[e.Row.StacktracePartitionID()]
+	e.Row.ForStacktraceIDsValues(func(values []parquet.Value) {
+		s.samples += uint64(len(values))
+		for i, v := range values {
+			id := v.Uint32()
+			if int(id) >= len(ids) {
+				err = fmt.Errorf("stack trace %d not found in partition %x", id, e.Row.StacktracePartitionID())
+				return
+			}
+			values[i] = parquet.Int64Value(int64(ids[id])).Level(v.RepetitionLevel(), v.DefinitionLevel(), v.Column())
+		}
+	})
+	return err
+}
+
+// include writes all the stack traces of the database.
+func (s *symbolsRewriter) include(ctx context.Context, r *symdb.Reader) error {
+	rw := symdb.NewRewriter(s.w, r)
+	for _, p := range r.Partitions() {
+		pr, err := r.Partition(ctx, p)
+		if err != nil {
+			return err
+		}
+		var stats symdb.PartitionStats
+		pr.WriteStats(&stats)
+		pr.Release()
+		if stats.MaxStacktraceID < 2 {
+			continue
+		}
+		s.stacktraces = slices.Grow(s.stacktraces[:0], stats.MaxStacktraceID-1)[:stats.MaxStacktraceID-1]
+		for i := range s.stacktraces {
+			s.stacktraces[i] = uint32(i + 1)
+		}
+		if err = rw.Rewrite(p, s.stacktraces); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *symbolsRewriter) rewriterFor(x *Dataset) *symdb.Rewriter {
 	rw, ok := s.rw[x]
 	if !ok {
@@ -528,4 +754,9 @@ func (s *symbolsRewriter) loadStacktraceIDs(values []parquet.Value) {
 	}
 }
 
-func (s *symbolsRewriter) Flush() error { return s.w.Flush() }
+func (s *symbolsRewriter) Flush() error {
+	if s.mapping != nil {
+		return nil
+	}
+	return s.w.Flush()
+}
@@ -6,11 +6,14 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/objstore/testutil"
+	"github.com/grafana/pyroscope/pkg/phlaredb/symdb"
 )
 
 func Test_CompactBlocks(t *testing.T) {
@@ -34,5 +37,251 @@ func Test_CompactBlocks(t *testing.T) {
 
 	require.NoError(t, err)
 	require.Len(t, compactedBlocks, 1)
+	md, err := ReadMetadata(ctx, dst, ObjectPath(compactedBlocks[0]))
+	require.NoError(t, err)
+	require.True(t, md.EqualVT(compactedBlocks[0]))
+	sources := make([]string, len(resp.Blocks))
+	for i, b := range resp.Blocks {
+		sources[i] = b.Id
+	}
+	assert.Equal(t, sources, md.SourceBlocks)
 	// TODO: Assertions.
 }
+
+func Test_CompactBlocks_LabelRewrite(t *testing.T) {
+	ctx := context.Background()
+	bucket, _ := testutil.NewFilesystemBucket(t, ctx, "testdata")
+
+	var resp metastorev1.GetBlockMetadataResponse
+	raw, err := os.ReadFile("testdata/block-metas.json")
+	require.NoError(t, err)
+	err = protojson.Unmarshal(raw, &resp)
+	require.NoError(t, err)
+
+	rules, err := LabelRewriteRules([]*metastorev1.LabelRewriteRule{
+		{
+			SourceLabels: []string{"service_name"},
+			Regex:        "pyroscope-test/(.*)",
+			TargetLabel:  "component",
+		},
+		{
+			SourceLabels: []string{"service_name"},
+			Regex:        "pyroscope-test/(.*)",
+			TargetLabel:  "service_name",
+			Replacement:  "test/$1",
+		},
+	})
+	require.NoError(t, err)
+
+	dst, tempdir := testutil.NewFilesystemBucket(t, ctx, t.TempDir())
+	compactedBlocks, err := Compact(ctx, resp.Blocks, bucket,
+		WithCompactionDestination(dst),
+		WithCompactionTempDir(tempdir),
+		WithCompactionLabelRewrite(rules),
+	)
+	require.NoError(t, err)
+	require.Len(t, compactedBlocks, 1)
+
+	compacted := compactedBlocks[0]
+	require.Equal(t, uint32(0), compacted.CompactionLevel)
+	names := make([]string, 0, len(compacted.Datasets))
+	for _, ds := range compacted.Datasets {
+		names = append(names, ds.Name)
+	}
+	require.Equal(t, []string{"test/alloy", "test/ingester", "test/query-frontend"}, names)
+
+	obj := NewObject(dst, compacted)
+	require.NoError(t, obj.Open(ctx))
+	defer func() {
+		require.NoError(t, obj.Close())
+	}()
+	ds := NewDataset(compacted.Datasets[1], obj)
+	require.NoError(t, ds.Open(ctx, SectionTSDB))
+	defer func() {
+		require.NoError(t, ds.Close())
+	}()
+	values, err := ds.Index().SortedLabelValues("service_name")
+	require.NoError(t, err)
+	require.Equal(t, []string{"test/ingester"}, values)
+	values, err = ds.Index().SortedLabelValues("component")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ingester"}, values)
+}
+
+func Test_CompactBlocks_LabelRewrite_MergeSeries(t *testing.T) {
+	ctx := context.Background()
+	bucket, _ := testutil.NewFilesystemBucket(t, ctx, "testdata")
+
+	var resp metastorev1.GetBlockMetadataResponse
+	raw, err := os.ReadFile("testdata/block-metas.json")
+	require.NoError(t, err)
+	err = protojson.Unmarshal(raw, &resp)
+	require.NoError(t, err)
+
+	// All the services are renamed to the same one, and the series
+	// of all the pods and services collapse into one per profile type.
+	rules, err := LabelRewriteRules([]*metastorev1.LabelRewriteRule{
+		{
+			SourceLabels: []string{"service_name"},
+			Regex:        "pyroscope-test/.*",
+			TargetLabel:  "service_name",
+			Replacement:  "pyroscope-test",
+		},
+		{
+			Action: "labelkeep",
+			Regex:  "service_name|__profile_type__",
+		},
+	})
+	require.NoError(t, err)
+
+	dst, tempdir := testutil.NewFilesystemBucket(t, ctx, t.TempDir())
+	compactedBlocks, err := Compact(ctx, resp.Blocks, bucket,
+		WithCompactionDestination(dst),
+		WithCompactionTempDir(tempdir),
+		WithCompactionLabelRewrite(rules),
+	)
+	require.NoError(t, err)
+	require.Len(t, compactedBlocks, 1)
+	compacted := compactedBlocks[0]
+	require.Len(t, compacted.Datasets, 1)
+	require.Equal(t, "pyroscope-test", compacted.Datasets[0].Name)
+
+	obj := NewObject(dst, compacted)
+	require.NoError(t, obj.Open(ctx))
+	ds := NewDataset(compacted.Datasets[0], obj)
+	require.NoError(t, ds.Open(ctx, SectionTSDB, SectionProfiles))
+	profileTypes, err := ds.Index().SortedLabelValues("__profile_type__")
+	require.NoError(t, err)
+	require.Len(t, profileTypes, 7)
+
+	rows, err := NewProfileRowIterator(ds)
+	require.NoError(t, err)
+	var profiles int
+	series := make([]string, 0, len(profileTypes))
+	var lastSeries uint32
+	var lastTimestamp int64
+	for rows.Next() {
+		e := rows.At()
+		profiles++
+		s := e.Row.SeriesIndex()
+		if int(s) == len(series) {
+			series = append(series, e.Labels.Get("__profile_type__"))
+		} else {
+			require.Equal(t, lastSeries, s, "rows are not ordered by series")
+			require.LessOrEqual(t, lastTimestamp, e.Timestamp, "rows are not ordered by time")
+		}
+		lastSeries = s
+		lastTimestamp = e.Timestamp
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	require.NoError(t, obj.Close())
+
+	// Each profile type is a single series.
+	assert.Equal(t, profileTypes, series)
+	assert.NotZero(t, profiles)
+}
+
+func Test_CompactBlocks_SharedSymbols(t *testing.T) {
+	ctx := context.Background()
+	bucket, _ := testutil.NewFilesystemBucket(t, ctx, "testdata")
+
+	var resp metastorev1.GetBlockMetadataResponse
+	raw, err := os.ReadFile("testdata/block-metas.json")
+	require.NoError(t, err)
+	err = protojson.Unmarshal(raw, &resp)
+	require.NoError(t, err)
+
+	dst, tempdir := testutil.NewFilesystemBucket(t, ctx, t.TempDir())
+	compact := func(blocks []*metastorev1.BlockMeta, src objstore.Bucket) *metastorev1.BlockMeta {
+		compacted, err := Compact(ctx, blocks, src,
+			WithCompactionDestination(dst),
+			WithCompactionTempDir(tempdir),
+			WithCompactionSharedSymbols(64<<20),
+		)
+		require.NoError(t, err)
+		require.Len(t, compacted, 1)
+		return compacted[0]
+	}
+
+	dictionaries := func(b *metastorev1.BlockMeta) map[string]string {
+		m := make(map[string]string, len(b.Datasets))
+		for _, ds := range b.Datasets {
+			require.NotNil(t, ds.SymbolsDictionary)
+			m[ds.Name] = ds.SymbolsDictionary.Path
+		}
+		return m
+	}
+
+	first := compact(resp.Blocks, bucket)
+	expected := dictionaries(first)
+	// The dictionaries include all the stack traces of the
+	// source blocks, therefore they are reused as is.
+	require.Equal(t, expected, dictionaries(compact(resp.Blocks, bucket)))
+	// Compaction of the blocks referring to the dictionaries.
+	next := compact([]*metastorev1.BlockMeta{first}, dst)
+	require.Equal(t, expected, dictionaries(next))
+
+	obj := NewObject(dst, next)
+	require.NoError(t, obj.Open(ctx))
+	defer func() {
+		require.NoError(t, obj.Close())
+	}()
+	for _, meta := range next.Datasets {
+		ds := NewDataset(meta, obj)
+		require.NoError(t, ds.Open(ctx, SectionSymbols))
+		var stats symdb.PartitionStats
+		for _, p := range ds.symbols.Partitions() {
+			pr, err := ds.symbols.Partition(ctx, p)
+			require.NoError(t, err)
+			pr.WriteStats(&stats)
+			pr.Release()
+			require.NotZero(t, stats.StacktracesTotal)
+		}
+		require.NoError(t, ds.Close())
+	}
+}
+
+func Test_LabelRewriteRules_Validation(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		rule  *metastorev1.LabelRewriteRule
+		valid bool
+	}{
+		{
+			name:  "rename label value",
+			rule:  &metastorev1.LabelRewriteRule{SourceLabels: []string{"env"}, Regex: "prod", TargetLabel: "env", Replacement: "production"},
+			valid: true,
+		},
+		{
+			name:  "service name derived from itself",
+			rule:  &metastorev1.LabelRewriteRule{SourceLabels: []string{"service_name"}, TargetLabel: "service_name", Action: "lowercase"},
+			valid: true,
+		},
+		{
+			name: "service name derived from other labels",
+			rule: &metastorev1.LabelRewriteRule{SourceLabels: []string{"namespace", "service_name"}, TargetLabel: "service_name"},
+		},
+		{
+			name: "service name dropped",
+			rule: &metastorev1.LabelRewriteRule{Regex: "service_.*", Action: "labeldrop"},
+		},
+		{
+			name: "series dropped",
+			rule: &metastorev1.LabelRewriteRule{SourceLabels: []string{"env"}, Regex: "dev", Action: "drop"},
+		},
+		{
+			name: "invalid regex",
+			rule: &metastorev1.LabelRewriteRule{SourceLabels: []string{"env"}, Regex: "(", TargetLabel: "env"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LabelRewriteRules([]*metastorev1.LabelRewriteRule{tc.rule})
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidLabelRewriteRule)
+			}
+		})
+	}
+}
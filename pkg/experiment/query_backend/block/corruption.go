@@ -0,0 +1,29 @@
+package block
+
+import (
+	"errors"
+
+	"github.com/parquet-go/parquet-go"
+	tsdb_enc "github.com/prometheus/prometheus/tsdb/encoding"
+
+	"github.com/grafana/pyroscope/pkg/phlaredb/symdb"
+)
+
+// IsCorrupted reports whether the error indicates that the data of the
+// block object is corrupted: e.g., a checksum mismatch, or a malformed
+// section. Unlike transient errors, such as object storage unavailability,
+// these are not resolved by retries.
+func IsCorrupted(err error) bool {
+	var formatErr *symdb.FormatError
+	switch {
+	case errors.As(err, &formatErr):
+		return true
+	case errors.Is(err, tsdb_enc.ErrInvalidChecksum),
+		errors.Is(err, tsdb_enc.ErrInvalidSize):
+		return true
+	case errors.Is(err, parquet.ErrCorrupted),
+		errors.Is(err, parquet.ErrMissingPageHeader):
+		return true
+	}
+	return false
+}
@@ -136,6 +136,8 @@ func (s *Dataset) closeErr(err error) error {
 
 func (s *Dataset) Meta() *metastorev1.Dataset { return s.meta }
 
+func (s *Dataset) Object() *Object { return s.obj }
+
 func (s *Dataset) Profiles() *ParquetFile { return s.profiles }
 
 func (s *Dataset) ProfileRowReader() parquet.RowReader { return s.profiles.RowReader() }
@@ -144,6 +146,25 @@ func (s *Dataset) Symbols() symdb.SymbolsReader { return s.symbols }
 
 func (s *Dataset) Index() phlaredb.IndexReader { return s.tsdb.index }
 
+// DescribeDatasetSections returns the sections of the dataset of the
+// given block, in the order of the table of contents.
+func DescribeDatasetSections(md *metastorev1.BlockMeta, ds *metastorev1.Dataset) []*metastorev1.DatasetSection {
+	if len(ds.TableOfContents) < len(allSections) {
+		// Invalid or unknown dataset layout.
+		return nil
+	}
+	s := &Dataset{meta: ds, obj: &Object{meta: md}}
+	sections := make([]*metastorev1.DatasetSection, 0, len(allSections))
+	for _, sc := range allSections {
+		sections = append(sections, &metastorev1.DatasetSection{
+			Name:   s.sectionName(sc),
+			Offset: uint64(s.sectionOffset(sc)),
+			Size:   uint64(s.sectionSize(sc)),
+		})
+	}
+	return sections
+}
+
 // Offset of the tenant dataset section within the object.
 func (s *Dataset) offset() uint64 { return s.meta.TableOfContents[0] }
 
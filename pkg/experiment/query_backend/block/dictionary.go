@@ -0,0 +1,236 @@
+package block
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/oklog/ulid"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/objstore"
+	"github.com/grafana/pyroscope/pkg/phlaredb/symdb"
+)
+
+// Shared symbols dictionaries.
+//
+// The symbols of a dataset may be stored in a dictionary object shared
+// by the blocks, instead of the symbols section of the dataset. Workloads
+// with a stable set of stack traces store nearly identical symbols in all
+// the blocks: a dictionary is only extended when a block has stack traces
+// that the dictionary does not include.
+//
+// The dictionaries of a dataset are stored in the directory of the shard
+// and the tenant the compacted blocks are stored in, under the prefix
+// specific to the dataset; they are identified by ULIDs, so that the
+// newest dictionary is the last one listed. A dictionary is immutable:
+// extending a dictionary creates a new one, which includes all the stack
+// traces of the original dictionary, and these of the compacted blocks.
+//
+// The metastore counts the blocks referring to a dictionary: once the last
+// of them is removed from the index, the dictionary is tombstoned, and its
+// object is deleted by the compaction workers, along with the blocks.
+
+// DirNameDictionary is the directory of the dictionaries
+// in the directory of the compacted blocks of the tenant.
+const DirNameDictionary = "dictionaries/"
+
+func dictionaryPrefix(tenant string, shard uint32, dataset string) string {
+	var b strings.Builder
+	b.WriteString(DirPathBlock)
+	b.WriteString(strconv.Itoa(int(shard)))
+	b.WriteByte('/')
+	b.WriteString(tenant)
+	b.WriteByte('/')
+	b.WriteString(DirNameDictionary)
+	// Dataset names are not guaranteed to be valid path segments.
+	b.WriteString(strconv.FormatUint(xxhash.Sum64String(dataset), 16))
+	b.WriteByte('/')
+	return b.String()
+}
+
+// latestDictionary returns the newest dictionary under the prefix, if any.
+func latestDictionary(ctx context.Context, storage objstore.BucketReader, prefix string) (*metastorev1.SymbolsDictionary, error) {
+	var latest string
+	err := storage.Iter(ctx, prefix, func(name string) error {
+		if id := strings.TrimSuffix(strings.TrimPrefix(name, prefix), "/"); id > latest {
+			if _, err := ulid.Parse(id); err == nil {
+				latest = id
+			}
+		}
+		return nil
+	})
+	if err != nil || latest == "" {
+		return nil, err
+	}
+	path := prefix + latest + "/" + symdb.DefaultFileName
+	attrs, err := storage.Attributes(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading dictionary attributes %s: %w", path, err)
+	}
+	return &metastorev1.SymbolsDictionary{Path: path, Size: uint64(attrs.Size)}, nil
+}
+
+// sharedSymbols determines the dictionary the compacted dataset refers to.
+//
+// The newest dictionary of the dataset is reused as is, if it includes all
+// the stack traces of the source datasets: the stack trace identifiers are
+// then mapped to these of the dictionary by their fingerprints. Otherwise,
+// the symbols of the compacted dataset are written to a new dictionary.
+type sharedSymbols struct {
+	storage objstore.Bucket
+	maxSize int64
+	prefix  string
+
+	base   *metastorev1.SymbolsDictionary
+	reader *symdb.Reader
+	// Identifiers of the stack traces of the source datasets in the base
+	// dictionary, by partition; the datasets that refer to the base
+	// dictionary are not included. Nil, if the dictionary does not
+	// include all the stack traces of the source datasets.
+	mapping map[*Dataset]map[uint64][]uint32
+}
+
+func newSharedSymbols(storage objstore.Bucket, maxSize int64, tenant string, shard uint32, dataset string) *sharedSymbols {
+	return &sharedSymbols{
+		storage: storage,
+		maxSize: maxSize,
+		prefix:  dictionaryPrefix(tenant, shard, dataset),
+	}
+}
+
+// open locates the base dictionary and checks if it includes the stack
+// traces of the source datasets. The datasets must be open.
+func (d *sharedSymbols) open(ctx context.Context, datasets []*Dataset) (err error) {
+	if d.base, err = latestDictionary(ctx, d.storage, d.prefix); err != nil || d.base == nil {
+		return err
+	}
+	if int64(d.base.Size) >= d.maxSize {
+		// The dictionary is not to be extended:
+		// the new one is started from scratch.
+		d.base = nil
+		return nil
+	}
+	d.reader, err = symdb.OpenObject(ctx, d.storage, d.base.Path, 0, int64(d.base.Size),
+		symdb.WithPrefetchSize(symbolsPrefetchSize))
+	if err != nil {
+		return fmt.Errorf("opening symbols dictionary %s: %w", d.base.Path, err)
+	}
+	index, err := fingerprintIndex(ctx, d.reader)
+	if err != nil {
+		return err
+	}
+	mapping := make(map[*Dataset]map[uint64][]uint32, len(datasets))
+	for _, ds := range datasets {
+		if ref := ds.meta.SymbolsDictionary; ref != nil && ref.Path == d.base.Path {
+			continue
+		}
+		m, err := mapStacktraces(ctx, ds.symbols, index)
+		if err != nil || m == nil {
+			return err
+		}
+		mapping[ds] = m
+	}
+	d.mapping = mapping
+	return nil
+}
+
+// covered reports whether the base dictionary includes
+// all the stack traces of the source datasets.
+func (d *sharedSymbols) covered() bool { return d.mapping != nil }
+
+// flush returns the dictionary the compacted dataset refers to: either
+// the base dictionary, or the new one, created from the symbols written
+// to the directory.
+func (d *sharedSymbols) flush(ctx context.Context, dir string) (*metastorev1.SymbolsDictionary, error) {
+	if d.covered() {
+		return d.base, nil
+	}
+	f, err := os.Open(filepath.Join(dir, symdb.DefaultFileName))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	id := ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
+	path := d.prefix + id + "/" + symdb.DefaultFileName
+	if err = d.storage.Upload(ctx, path, f); err != nil {
+		return nil, fmt.Errorf("uploading symbols dictionary %s: %w", path, err)
+	}
+	return &metastorev1.SymbolsDictionary{Path: path, Size: uint64(stat.Size())}, nil
+}
+
+func (d *sharedSymbols) Close() error {
+	if d.reader != nil {
+		return d.reader.Close()
+	}
+	return nil
+}
+
+// fingerprintIndex returns the identifiers of the stack traces
+// of the database by their fingerprints, by partition.
+func fingerprintIndex(ctx context.Context, r *symdb.Reader) (map[uint64]map[uint64]uint32, error) {
+	index := make(map[uint64]map[uint64]uint32)
+	for _, p := range r.Partitions() {
+		fingerprints, err := partitionFingerprints(ctx, r, p)
+		if err != nil {
+			return nil, err
+		}
+		ids := make(map[uint64]uint32, len(fingerprints))
+		for id, fp := range fingerprints {
+			if _, found := ids[fp]; fp != 0 && !found {
+				ids[fp] = uint32(id)
+			}
+		}
+		index[p] = ids
+	}
+	return index, nil
+}
+
+// mapStacktraces returns the identifiers of the stack traces of the
+// database in the index, by partition. Nil is returned, if any of the
+// stack traces is not present in the index.
+func mapStacktraces(ctx context.Context, r *symdb.Reader, index map[uint64]map[uint64]uint32) (map[uint64][]uint32, error) {
+	mapping := make(map[uint64][]uint32)
+	for _, p := range r.Partitions() {
+		ids, ok := index[p]
+		if !ok {
+			return nil, nil
+		}
+		fingerprints, err := partitionFingerprints(ctx, r, p)
+		if err != nil {
+			return nil, err
+		}
+		m := make([]uint32, len(fingerprints))
+		for i, fp := range fingerprints {
+			if fp == 0 {
+				continue
+			}
+			if m[i], ok = ids[fp]; !ok {
+				return nil, nil
+			}
+		}
+		mapping[p] = m
+	}
+	return mapping, nil
+}
+
+func partitionFingerprints(ctx context.Context, r *symdb.Reader, partition uint64) ([]uint64, error) {
+	p, err := r.Partition(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Release()
+	return symdb.StacktraceFingerprints(p), nil
+}
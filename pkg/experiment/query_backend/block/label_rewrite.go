@@ -0,0 +1,106 @@
+package block
+
+import (
+	"fmt"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	phlarerelabel "github.com/grafana/pyroscope/pkg/model/relabel"
+)
+
+var ErrInvalidLabelRewriteRule = fmt.Errorf("invalid label rewrite rule")
+
+// LabelRewriteRules converts label rewrite rules to the relabeling
+// configuration. Empty fields take the Prometheus defaults.
+//
+// Rules that may drop series are not allowed. Because the service name
+// determines the dataset a series belongs to, rules must not change it
+// based on other labels, or remove it.
+func LabelRewriteRules(rules []*metastorev1.LabelRewriteRule) ([]*relabel.Config, error) {
+	configs := make([]*relabel.Config, 0, len(rules))
+	for i, r := range rules {
+		c, err := labelRewriteRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %w", ErrInvalidLabelRewriteRule, i, err)
+		}
+		configs = append(configs, c)
+	}
+	return configs, nil
+}
+
+func labelRewriteRule(r *metastorev1.LabelRewriteRule) (*relabel.Config, error) {
+	c := relabel.DefaultRelabelConfig
+	// Rules that act on label names must not have source labels,
+	// which is validated as nil, and not empty.
+	for _, l := range r.SourceLabels {
+		c.SourceLabels = append(c.SourceLabels, model.LabelName(l))
+	}
+	c.TargetLabel = r.TargetLabel
+	if r.Separator != "" {
+		c.Separator = r.Separator
+	}
+	if r.Replacement != "" {
+		c.Replacement = r.Replacement
+	}
+	if r.Action != "" {
+		c.Action = relabel.Action(r.Action)
+	}
+	if r.Regex != "" {
+		regex, err := relabel.NewRegexp(r.Regex)
+		if err != nil {
+			return nil, err
+		}
+		c.Regex = regex
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch c.Action {
+	case relabel.Replace, relabel.Lowercase, relabel.Uppercase:
+		if c.TargetLabel != phlaremodel.LabelNameServiceName {
+			break
+		}
+		if len(c.SourceLabels) != 1 || c.SourceLabels[0] != phlaremodel.LabelNameServiceName {
+			return nil, fmt.Errorf("%s can only be derived from itself", phlaremodel.LabelNameServiceName)
+		}
+	case relabel.LabelDrop:
+		if c.Regex.MatchString(phlaremodel.LabelNameServiceName) {
+			return nil, fmt.Errorf("%s can not be dropped", phlaremodel.LabelNameServiceName)
+		}
+	case relabel.LabelKeep:
+		if !c.Regex.MatchString(phlaremodel.LabelNameServiceName) {
+			return nil, fmt.Errorf("%s can not be dropped", phlaremodel.LabelNameServiceName)
+		}
+	default:
+		return nil, fmt.Errorf("action %q is not supported", c.Action)
+	}
+
+	return &c, nil
+}
+
+// rewriteServiceName returns the dataset name after the rules are applied.
+func rewriteServiceName(name string, rules []*relabel.Config) (string, error) {
+	if len(rules) == 0 {
+		return name, nil
+	}
+	lbls, _ := phlarerelabel.Process(phlaremodel.Labels{{
+		Name:  phlaremodel.LabelNameServiceName,
+		Value: name,
+	}}, rules...)
+	if renamed := lbls.Get(phlaremodel.LabelNameServiceName); renamed != "" {
+		return renamed, nil
+	}
+	return "", fmt.Errorf("%w: %s %q is removed", ErrInvalidLabelRewriteRule, phlaremodel.LabelNameServiceName, name)
+}
+
+// rewriteLabels applies the rules to the series labels. The service name
+// is guaranteed to match the one of the dataset the series belongs to.
+func rewriteLabels(lbls phlaremodel.Labels, rules []*relabel.Config) (phlaremodel.Labels, model.Fingerprint) {
+	// Rules that drop series are rejected at validation.
+	lbls, _ = phlarerelabel.Process(lbls, rules...)
+	return lbls, model.Fingerprint(lbls.Hash())
+}
@@ -0,0 +1,93 @@
+package block
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/thanos-io/objstore"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+// The block metadata is appended to the block object, which allows to
+// rebuild the metastore index from the object storage. The metadata is
+// stored in the protobuf encoding, followed by the trailer: the size of
+// the encoded metadata and the magic number, both 4-byte big-endian.
+//
+// The datasets are located by their offsets, therefore the metadata is
+// not visible to the readers. The block size does not include it.
+const (
+	metadataMagic       = 0x50424d31 // PBM1
+	metadataTrailerSize = 8
+)
+
+// ErrNoMetadata is returned by ReadMetadata if the block object has no
+// metadata appended, e.g., if it was created before the metadata was.
+var ErrNoMetadata = errors.New("block object has no metadata")
+
+// WriteMetadata appends the metadata to the block object written to w.
+// The block size must be set before the metadata is written.
+func WriteMetadata(w io.Writer, md *metastorev1.BlockMeta) error {
+	b, err := md.MarshalVT()
+	if err != nil {
+		return err
+	}
+	b = binary.BigEndian.AppendUint32(b, uint32(len(b)))
+	b = binary.BigEndian.AppendUint32(b, metadataMagic)
+	_, err = w.Write(b)
+	return err
+}
+
+// ReadMetadata reads the metadata appended to the block object
+// at the given path.
+func ReadMetadata(ctx context.Context, bucket objstore.BucketReader, path string) (*metastorev1.BlockMeta, error) {
+	attrs, err := bucket.Attributes(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if attrs.Size < metadataTrailerSize {
+		return nil, ErrNoMetadata
+	}
+	trailer, err := readRange(ctx, bucket, path, attrs.Size-metadataTrailerSize, metadataTrailerSize)
+	if err != nil {
+		return nil, err
+	}
+	if binary.BigEndian.Uint32(trailer[4:]) != metadataMagic {
+		return nil, ErrNoMetadata
+	}
+	size := int64(binary.BigEndian.Uint32(trailer[:4]))
+	offset := attrs.Size - metadataTrailerSize - size
+	if offset < 0 {
+		return nil, ErrNoMetadata
+	}
+	b, err := readRange(ctx, bucket, path, offset, size)
+	if err != nil {
+		return nil, err
+	}
+	md := new(metastorev1.BlockMeta)
+	if err = md.UnmarshalVT(b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoMetadata, err)
+	}
+	if md.Size != uint64(offset) {
+		return nil, fmt.Errorf("%w: block size %d does not match the metadata offset %d", ErrNoMetadata, md.Size, offset)
+	}
+	return md, nil
+}
+
+func readRange(ctx context.Context, bucket objstore.BucketReader, path string, offset, size int64) ([]byte, error) {
+	r, err := bucket.GetRange(ctx, path, offset, size)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	b := make([]byte, size)
+	if _, err = io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
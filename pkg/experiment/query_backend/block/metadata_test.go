@@ -0,0 +1,56 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	"github.com/grafana/pyroscope/pkg/objstore/providers/memory"
+)
+
+func TestReadMetadata(t *testing.T) {
+	ctx := context.Background()
+	bucket := memory.NewInMemBucket()
+	md := &metastorev1.BlockMeta{
+		Id:              "01J8CV4XZX6KQ3F0DQ5ZTG4A5B",
+		TenantId:        "tenant-a",
+		Shard:           1,
+		CompactionLevel: 1,
+		Size:            4,
+		Datasets:        []*metastorev1.Dataset{{TenantId: "tenant-a", Name: "service-a", Size: 4}},
+	}
+	upload := func(path string, data []byte, md *metastorev1.BlockMeta) {
+		var buf bytes.Buffer
+		buf.Write(data)
+		if md != nil {
+			require.NoError(t, WriteMetadata(&buf, md))
+		}
+		require.NoError(t, bucket.Upload(ctx, path, &buf))
+	}
+
+	path := ObjectPath(md)
+	upload(path, []byte("data"), md)
+	actual, err := ReadMetadata(ctx, bucket, path)
+	require.NoError(t, err)
+	assert.True(t, md.EqualVT(actual))
+
+	upload("no-metadata", []byte("data"), nil)
+	_, err = ReadMetadata(ctx, bucket, "no-metadata")
+	assert.ErrorIs(t, err, ErrNoMetadata)
+
+	upload("empty", nil, nil)
+	_, err = ReadMetadata(ctx, bucket, "empty")
+	assert.ErrorIs(t, err, ErrNoMetadata)
+
+	// The block size must match the metadata offset.
+	upload("size-mismatch", []byte("more data"), md)
+	_, err = ReadMetadata(ctx, bucket, "size-mismatch")
+	assert.ErrorIs(t, err, ErrNoMetadata)
+
+	_, err = ReadMetadata(ctx, bucket, "not-found")
+	assert.True(t, bucket.IsObjNotFoundErr(err))
+}
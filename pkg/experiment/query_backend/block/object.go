@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/grafana/dskit/multierror"
+	"github.com/oklog/ulid"
 	"golang.org/x/sync/errgroup"
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
@@ -66,6 +67,10 @@ type Object struct {
 	meta    *metastorev1.BlockMeta
 	storage objstore.BucketReader
 	local   *objstore.ReadOnlyFile
+	// The storage the object is stored in. Unlike storage, it never
+	// refers to the local copy of the object: the shared dictionaries
+	// the datasets refer to are read from the bucket.
+	bucket objstore.BucketReader
 
 	refs refctr.Counter
 	buf  *bufferpool.Buffer
@@ -98,6 +103,7 @@ func WithObjectDownload(dir string) ObjectOption {
 func NewObject(storage objstore.Bucket, meta *metastorev1.BlockMeta, opts ...ObjectOption) *Object {
 	o := &Object{
 		storage: storage,
+		bucket:  storage,
 		meta:    meta,
 		path:    ObjectPath(meta),
 		memSize: defaultObjectSizeLoadInMemory,
@@ -109,7 +115,24 @@ func NewObject(storage objstore.Bucket, meta *metastorev1.BlockMeta, opts ...Obj
 }
 
 func ObjectPath(md *metastorev1.BlockMeta) string {
-	return BuildObjectPath(md.TenantId, md.Shard, md.CompactionLevel, md.Id)
+	return BuildObjectPath(md.TenantId, md.Shard, md.CompactionLevel, ObjectID(md))
+}
+
+// ObjectID returns the identifier the block object is stored under. It
+// differs from the block identifier if the latter has been re-stamped by
+// the metastore: the original identifier is restored from its timestamp.
+func ObjectID(md *metastorev1.BlockMeta) string {
+	if md.OriginalCreatedAt == 0 {
+		return md.Id
+	}
+	id, err := ulid.Parse(md.Id)
+	if err != nil {
+		return md.Id
+	}
+	if err = id.SetTime(uint64(md.OriginalCreatedAt)); err != nil {
+		return md.Id
+	}
+	return id.String()
 }
 
 func BuildObjectPath(tenant string, shard uint32, level uint32, block string) string {
@@ -8,6 +8,16 @@ import (
 )
 
 func openSymbols(ctx context.Context, s *Dataset) (err error) {
+	if d := s.meta.SymbolsDictionary; d != nil {
+		// The stack traces of the dataset refer to the shared
+		// dictionary: the symbols section is empty.
+		s.symbols, err = symdb.OpenObject(ctx, s.obj.bucket, d.Path, 0, int64(d.Size),
+			symdb.WithPrefetchSize(symbolsPrefetchSize))
+		if err != nil {
+			return fmt.Errorf("opening symbols dictionary %s: %w", d.Path, err)
+		}
+		return nil
+	}
 	offset := s.sectionOffset(SectionSymbols)
 	size := s.sectionSize(SectionSymbols)
 	if buf := s.inMemoryBuffer(); buf != nil {
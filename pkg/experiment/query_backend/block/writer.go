@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	"github.com/grafana/pyroscope/pkg/objstore"
 	"github.com/grafana/pyroscope/pkg/util/bufferpool"
 )
@@ -83,6 +84,17 @@ func (b *Writer) ReadFromFile(file string) (err error) {
 
 func (b *Writer) Offset() uint64 { return b.off }
 
+// WriteMetadata appends the block metadata to the object. The offset
+// is not advanced: the metadata is not included in the block size.
+func (b *Writer) WriteMetadata(md *metastorev1.BlockMeta) (err error) {
+	if b.w == nil {
+		if b.w, err = os.Create(b.local); err != nil {
+			return err
+		}
+	}
+	return WriteMetadata(b.w, md)
+}
+
 func (b *Writer) Flush(ctx context.Context) error {
 	if err := b.w.Close(); err != nil {
 		return err
@@ -0,0 +1,120 @@
+package query_backend
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"google.golang.org/grpc"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+)
+
+type BlockQuarantineClient interface {
+	QuarantineBlock(context.Context, *metastorev1.QuarantineBlockRequest, ...grpc.CallOption) (*metastorev1.QuarantineBlockResponse, error)
+}
+
+const (
+	// The number of blocks with read failures tracked at a time.
+	// Failures of other blocks are ignored until the tracked
+	// blocks are reported.
+	maxTrackedBlocks = 1 << 10
+
+	quarantineRequestTimeout = 10 * time.Second
+)
+
+// BlockQuarantine counts read failures of blocks caused by data corruption,
+// and reports the block to the metastore once the number of failures reaches
+// the threshold. Failures are counted once per request. The metastore marks
+// the block quarantined, and the block is not queried anymore.
+type BlockQuarantine struct {
+	logger    log.Logger
+	client    BlockQuarantineClient
+	threshold int
+	hostname  string
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// NewBlockQuarantine creates a new BlockQuarantine. Blocks are never
+// reported if the threshold is not positive.
+func NewBlockQuarantine(logger log.Logger, client BlockQuarantineClient, threshold int) *BlockQuarantine {
+	hostname, _ := os.Hostname()
+	return &BlockQuarantine{
+		logger:    logger,
+		client:    client,
+		threshold: threshold,
+		hostname:  hostname,
+		failures:  make(map[string]int),
+	}
+}
+
+// blockFailures collects the blocks that failed to be read within a request.
+type blockFailures struct {
+	mu     sync.Mutex
+	blocks map[string]*blockFailure
+}
+
+type blockFailure struct {
+	md  *metastorev1.BlockMeta
+	err error
+}
+
+func (f *blockFailures) add(md *metastorev1.BlockMeta, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.blocks == nil {
+		f.blocks = make(map[string]*blockFailure)
+	}
+	if _, ok := f.blocks[md.Id]; !ok {
+		f.blocks[md.Id] = &blockFailure{md: md, err: err}
+	}
+}
+
+func (q *BlockQuarantine) observe(ctx context.Context, failures *blockFailures) {
+	if q == nil || q.threshold <= 0 || len(failures.blocks) == 0 {
+		return
+	}
+	var report []*blockFailure
+	q.mu.Lock()
+	for id, f := range failures.blocks {
+		n, ok := q.failures[id]
+		if !ok && len(q.failures) >= maxTrackedBlocks {
+			continue
+		}
+		if n++; n < q.threshold {
+			q.failures[id] = n
+			continue
+		}
+		// If the report fails, the failures are counted anew.
+		delete(q.failures, id)
+		report = append(report, f)
+	}
+	q.mu.Unlock()
+	// The request context might be canceled once the failure
+	// is returned to the caller, but the report should be sent.
+	ctx = context.WithoutCancel(ctx)
+	for _, f := range report {
+		q.report(ctx, f)
+	}
+}
+
+func (q *BlockQuarantine) report(ctx context.Context, f *blockFailure) {
+	ctx, cancel := context.WithTimeout(ctx, quarantineRequestTimeout)
+	defer cancel()
+	level.Warn(q.logger).Log("msg", "quarantining block", "block_id", f.md.Id, "err", f.err)
+	_, err := q.client.QuarantineBlock(ctx, &metastorev1.QuarantineBlockRequest{
+		BlockId:    f.md.Id,
+		Shard:      f.md.Shard,
+		TenantId:   f.md.TenantId,
+		Reason:     f.err.Error(),
+		ReportedBy: q.hostname,
+	})
+	if err != nil {
+		level.Error(q.logger).Log("msg", "failed to quarantine block", "block_id", f.md.Id, "err", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql/parser"
@@ -14,6 +15,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
 	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
 	"github.com/grafana/pyroscope/pkg/objstore"
@@ -45,8 +47,10 @@ import (
 //
 
 type BlockReader struct {
-	log     log.Logger
-	storage objstore.Bucket
+	log        log.Logger
+	storage    objstore.Bucket
+	quarantine *BlockQuarantine
+	metrics    *blockReaderMetrics
 
 	// TODO:
 	//  - Use a worker pool instead of the errgroup.
@@ -56,10 +60,19 @@ type BlockReader struct {
 	//    Instead, they should share the processing pipeline, if possible.
 }
 
-func NewBlockReader(logger log.Logger, storage objstore.Bucket) *BlockReader {
+// NewBlockReader creates a new block reader. Blocks that can't be read
+// because of data corruption are reported to the quarantine, if provided.
+func NewBlockReader(
+	logger log.Logger,
+	storage objstore.Bucket,
+	quarantine *BlockQuarantine,
+	reg prometheus.Registerer,
+) *BlockReader {
 	return &BlockReader{
-		log:     logger,
-		storage: storage,
+		log:        logger,
+		storage:    storage,
+		quarantine: quarantine,
+		metrics:    newBlockReaderMetrics(reg),
 	}
 }
 
@@ -79,15 +92,19 @@ func (b *BlockReader) Invoke(
 	agg := newAggregator(req)
 
 	qcs := make([]*queryContext, 0, len(req.Query)*len(req.QueryPlan.Root.Blocks))
+	mds := make([]*metastorev1.BlockMeta, 0, cap(qcs))
 	for _, md := range req.QueryPlan.Root.Blocks {
 		object := block.NewObject(b.storage, md)
 		for _, ds := range md.Datasets {
 			dataset := block.NewDataset(ds, object)
-			qcs = append(qcs, newQueryContext(ctx, b.log, r, agg, dataset))
+			qcs = append(qcs, newQueryContext(ctx, b.log, b.metrics, r, agg, dataset))
+			mds = append(mds, md)
 		}
 	}
 
-	for _, c := range qcs {
+	var failures blockFailures
+	for i, c := range qcs {
+		md := mds[i]
 		for _, query := range req.Query {
 			q := query
 			g.Go(util.RecoverPanic(func() error {
@@ -96,12 +113,17 @@ func (b *BlockReader) Invoke(
 					level.Warn(b.log).Log("msg", "object not found", "err", execErr)
 					return nil
 				}
+				if execErr != nil && block.IsCorrupted(execErr) {
+					failures.add(md, execErr)
+				}
 				return execErr
 			}))
 		}
 	}
 
-	if err = g.Wait(); err != nil {
+	err = g.Wait()
+	b.quarantine.observe(ctx, &failures)
+	if err != nil {
 		return nil, err
 	}
 	return agg.response()
@@ -0,0 +1,68 @@
+package query_backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/dskit/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+// Stages of a query execution on a block dataset.
+const (
+	// Fetching the dataset ranges and resolving the section metadata
+	// (TSDB index, symbols, and profile table footer).
+	blockReadStageOpen = "open"
+	// Reading and decoding the data, and building the query report.
+	blockReadStageRead = "read"
+	// Merging the report into the response aggregate.
+	blockReadStageMerge = "merge"
+)
+
+type blockReaderMetrics struct {
+	blockReadDuration *prometheus.HistogramVec
+}
+
+func newBlockReaderMetrics(reg prometheus.Registerer) *blockReaderMetrics {
+	m := &blockReaderMetrics{
+		blockReadDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pyroscope",
+			Subsystem: "query_backend",
+			Name:      "block_read_duration_seconds",
+			Help:      "Time spent executing a query on a block dataset, by stage.",
+
+			Buckets:                         prometheus.ExponentialBucketsRange(0.001, 60, 24),
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  50,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, []string{"stage", "query_type"}),
+	}
+	if reg != nil {
+		util.RegisterOrGet(reg, m.blockReadDuration)
+	}
+	return m
+}
+
+// observeBlockRead records the duration of the stage. If the context
+// carries a sampled trace, the trace and block identifiers are attached
+// to the observation as an exemplar.
+func (m *blockReaderMetrics) observeBlockRead(
+	ctx context.Context,
+	stage string,
+	queryType string,
+	blockID string,
+	start time.Time,
+) {
+	o := m.blockReadDuration.WithLabelValues(stage, queryType)
+	d := time.Since(start).Seconds()
+	if traceID, ok := tracing.ExtractSampledTraceID(ctx); ok {
+		o.(prometheus.ExemplarObserver).ObserveWithExemplar(d, prometheus.Labels{
+			"traceID":  traceID,
+			"block_id": blockID,
+		})
+		return
+	}
+	o.Observe(d)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/iancoleman/strcase"
@@ -71,27 +72,30 @@ func registerQueryType(
 }
 
 type queryContext struct {
-	ctx context.Context
-	log log.Logger
-	req *request
-	agg *reportAggregator
-	ds  *block.Dataset
-	err error
+	ctx     context.Context
+	log     log.Logger
+	metrics *blockReaderMetrics
+	req     *request
+	agg     *reportAggregator
+	ds      *block.Dataset
+	err     error
 }
 
 func newQueryContext(
 	ctx context.Context,
 	log log.Logger,
+	metrics *blockReaderMetrics,
 	req *request,
 	agg *reportAggregator,
 	ds *block.Dataset,
 ) *queryContext {
 	return &queryContext{
-		ctx: ctx,
-		log: log,
-		req: req,
-		agg: agg,
-		ds:  ds,
+		ctx:     ctx,
+		log:     log,
+		metrics: metrics,
+		req:     req,
+		agg:     agg,
+		ds:      ds,
 	}
 }
 
@@ -99,23 +103,33 @@ func executeQuery(q *queryContext, query *queryv1.Query) error {
 	var span opentracing.Span
 	span, q.ctx = opentracing.StartSpanFromContext(q.ctx, "executeQuery."+strcase.ToCamel(query.QueryType.String()))
 	defer span.Finish()
+	blockID := q.ds.Object().Meta().Id
+	span.SetTag("block_id", blockID)
 	handle, err := getQueryHandler(query.QueryType)
 	if err != nil {
 		return err
 	}
+	queryType := query.QueryType.String()
+	start := time.Now()
 	if err = q.open(); err != nil {
 		return fmt.Errorf("failed to initialize query context: %w", err)
 	}
 	defer func() {
 		_ = q.close(err)
 	}()
+	q.metrics.observeBlockRead(q.ctx, blockReadStageOpen, queryType, blockID, start)
+	start = time.Now()
 	r, err := handle(q, query)
 	if err != nil {
 		return err
 	}
+	q.metrics.observeBlockRead(q.ctx, blockReadStageRead, queryType, blockID, start)
 	if r != nil {
 		r.ReportType = QueryReportType(query.QueryType)
-		return q.agg.aggregateReport(r)
+		start = time.Now()
+		err = q.agg.aggregateReport(r)
+		q.metrics.observeBlockRead(q.ctx, blockReadStageMerge, queryType, blockID, start)
+		return err
 	}
 	return nil
 }
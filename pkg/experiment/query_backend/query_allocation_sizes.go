@@ -0,0 +1,174 @@
+package query_backend
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/grafana/dskit/runutil"
+
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	parquetquery "github.com/grafana/pyroscope/pkg/phlaredb/query"
+	v1 "github.com/grafana/pyroscope/pkg/phlaredb/schemas/v1"
+	"github.com/grafana/pyroscope/pkg/phlaredb/symdb"
+)
+
+func init() {
+	registerQueryType(
+		queryv1.QueryType_QUERY_ALLOCATION_SIZES,
+		queryv1.ReportType_REPORT_ALLOCATION_SIZES,
+		queryAllocationSizes,
+		newAllocationSizesAggregator,
+		[]block.Section{
+			block.SectionTSDB,
+			block.SectionProfiles,
+			block.SectionSymbols,
+		}...,
+	)
+}
+
+type allocations struct {
+	objects int64
+	bytes   int64
+}
+
+// queryAllocationSizes joins the allocated space and the allocated objects
+// samples by stack trace: stack trace identifiers are shared by all the
+// profile types within a partition.
+func queryAllocationSizes(q *queryContext, query *queryv1.Query) (*queryv1.Report, error) {
+	entries, err := profileEntryIterator(q, phlaremodel.LabelNameProfileType)
+	if err != nil {
+		return nil, err
+	}
+	defer runutil.CloseWithErrCapture(&err, entries, "failed to close profile entry iterator")
+
+	var columns v1.SampleColumns
+	if err = columns.Resolve(q.ds.Profiles().Schema()); err != nil {
+		return nil, err
+	}
+
+	profiles := parquetquery.NewRepeatedRowIterator(q.ctx, entries, q.ds.Profiles().RowGroups(),
+		columns.StacktraceID.ColumnIndex,
+		columns.Value.ColumnIndex)
+	defer runutil.CloseWithErrCapture(&err, profiles, "failed to close profile stream")
+
+	partitions := make(map[uint64]map[uint32]*allocations)
+	for profiles.Next() {
+		p := profiles.At()
+		var objects bool
+		switch p.Row.Labels.Get(phlaremodel.LabelNameProfileType) {
+		case query.AllocationSizes.ObjectsProfileType:
+			objects = true
+		case query.AllocationSizes.SpaceProfileType:
+		default:
+			continue
+		}
+		stacks, ok := partitions[p.Row.Partition]
+		if !ok {
+			stacks = make(map[uint32]*allocations)
+			partitions[p.Row.Partition] = stacks
+		}
+		for i, sid := range p.Values[0] {
+			a, ok := stacks[sid.Uint32()]
+			if !ok {
+				a = new(allocations)
+				stacks[sid.Uint32()] = a
+			}
+			if objects {
+				a.objects += p.Values[1][i].Int64()
+			} else {
+				a.bytes += p.Values[1][i].Int64()
+			}
+		}
+	}
+	if err = profiles.Err(); err != nil {
+		return nil, err
+	}
+
+	histogram := phlaremodel.NewAllocationSizeHistogram()
+	for partition, stacks := range partitions {
+		if query.AllocationSizes.FunctionName != "" {
+			if err = filterAllocationsByLeafFunction(q, partition, stacks, query.AllocationSizes.FunctionName); err != nil {
+				return nil, err
+			}
+		}
+		for _, a := range stacks {
+			histogram.Add(a.objects, a.bytes)
+		}
+	}
+
+	resp := &queryv1.Report{
+		AllocationSizes: &queryv1.AllocationSizesReport{
+			Query:   query.AllocationSizes.CloneVT(),
+			Buckets: histogram.Buckets(),
+		},
+	}
+	return resp, nil
+}
+
+// filterAllocationsByLeafFunction removes stack traces
+// that do not have the function as the leaf frame.
+func filterAllocationsByLeafFunction(q *queryContext, partition uint64, stacks map[uint32]*allocations, name string) error {
+	p, err := q.ds.Symbols().Partition(q.ctx, partition)
+	if err != nil {
+		return err
+	}
+	defer p.Release()
+
+	stacktraces := make([]uint32, 0, len(stacks))
+	for sid := range stacks {
+		stacktraces = append(stacktraces, sid)
+	}
+	slices.Sort(stacktraces)
+	f := &leafFunctionFilter{symbols: p.Symbols(), name: name, stacks: stacks}
+	return f.symbols.Stacktraces.ResolveStacktraceLocations(q.ctx, f, stacktraces)
+}
+
+type leafFunctionFilter struct {
+	symbols *symdb.Symbols
+	name    string
+	stacks  map[uint32]*allocations
+}
+
+func (f *leafFunctionFilter) InsertStacktrace(stacktraceID uint32, locations []int32) {
+	if len(locations) > 0 {
+		// The leaf is at locations[0], and the innermost
+		// inlined function is at the first line.
+		if lines := f.symbols.Locations[locations[0]].Line; len(lines) > 0 {
+			fn := f.symbols.Functions[lines[0].FunctionId]
+			if f.symbols.Strings[fn.Name] == f.name {
+				return
+			}
+		}
+	}
+	delete(f.stacks, stacktraceID)
+}
+
+type allocationSizesAggregator struct {
+	init      sync.Once
+	query     *queryv1.AllocationSizesQuery
+	histogram *phlaremodel.AllocationSizeHistogram
+}
+
+func newAllocationSizesAggregator(*queryv1.InvokeRequest) aggregator {
+	return &allocationSizesAggregator{histogram: phlaremodel.NewAllocationSizeHistogram()}
+}
+
+func (a *allocationSizesAggregator) aggregate(report *queryv1.Report) error {
+	r := report.AllocationSizes
+	a.init.Do(func() {
+		a.query = r.Query.CloneVT()
+	})
+	a.histogram.Merge(r.Buckets)
+	return nil
+}
+
+func (a *allocationSizesAggregator) build() *queryv1.Report {
+	return &queryv1.Report{
+		AllocationSizes: &queryv1.AllocationSizesReport{
+			Query:   a.query,
+			Buckets: a.histogram.Buckets(),
+		},
+	}
+}
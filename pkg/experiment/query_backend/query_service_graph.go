@@ -0,0 +1,111 @@
+package query_backend
+
+import (
+	"sync"
+
+	"github.com/grafana/dskit/runutil"
+
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	"github.com/grafana/pyroscope/pkg/experiment/query_backend/block"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	parquetquery "github.com/grafana/pyroscope/pkg/phlaredb/query"
+	v1 "github.com/grafana/pyroscope/pkg/phlaredb/schemas/v1"
+)
+
+func init() {
+	registerQueryType(
+		queryv1.QueryType_QUERY_SERVICE_GRAPH,
+		queryv1.ReportType_REPORT_SERVICE_GRAPH,
+		queryServiceGraph,
+		newServiceGraphAggregator,
+		[]block.Section{
+			block.SectionTSDB,
+			block.SectionProfiles,
+		}...,
+	)
+}
+
+// queryServiceGraph attributes samples to the calling services by the span
+// identifiers: a sample that belongs to a span of a caller adds its value
+// to the edge from the caller to the service the profile belongs to.
+func queryServiceGraph(q *queryContext, query *queryv1.Query) (*queryv1.Report, error) {
+	// A span may be caused by requests of several callers,
+	// e.g. if a request is shared by them.
+	callers := make(map[uint64][]string)
+	for _, c := range query.ServiceGraph.Callers {
+		spans, err := phlaremodel.NewSpanSelector(c.SpanSelector)
+		if err != nil {
+			return nil, err
+		}
+		for span := range spans {
+			callers[span] = append(callers[span], c.ServiceName)
+		}
+	}
+
+	targetLabel := query.ServiceGraph.TargetLabel
+	entries, err := profileEntryIterator(q, targetLabel)
+	if err != nil {
+		return nil, err
+	}
+	defer runutil.CloseWithErrCapture(&err, entries, "failed to close profile entry iterator")
+
+	var columns v1.SampleColumns
+	if err = columns.Resolve(q.ds.Profiles().Schema()); err != nil {
+		return nil, err
+	}
+
+	profiles := parquetquery.NewRepeatedRowIterator(q.ctx, entries, q.ds.Profiles().RowGroups(),
+		columns.Value.ColumnIndex,
+		columns.SpanID.ColumnIndex)
+	defer runutil.CloseWithErrCapture(&err, profiles, "failed to close profile stream")
+
+	graph := phlaremodel.NewServiceGraph()
+	for profiles.Next() {
+		p := profiles.At()
+		target := p.Row.Labels.Get(targetLabel)
+		for i, span := range p.Values[1] {
+			for _, source := range callers[span.Uint64()] {
+				graph.Add(source, target, p.Values[0][i].Int64())
+			}
+		}
+	}
+	if err = profiles.Err(); err != nil {
+		return nil, err
+	}
+
+	resp := &queryv1.Report{
+		ServiceGraph: &queryv1.ServiceGraphReport{
+			Query: query.ServiceGraph.CloneVT(),
+			Edges: graph.Edges(),
+		},
+	}
+	return resp, nil
+}
+
+type serviceGraphAggregator struct {
+	init  sync.Once
+	query *queryv1.ServiceGraphQuery
+	graph *phlaremodel.ServiceGraph
+}
+
+func newServiceGraphAggregator(*queryv1.InvokeRequest) aggregator {
+	return &serviceGraphAggregator{graph: phlaremodel.NewServiceGraph()}
+}
+
+func (a *serviceGraphAggregator) aggregate(report *queryv1.Report) error {
+	r := report.ServiceGraph
+	a.init.Do(func() {
+		a.query = r.Query.CloneVT()
+	})
+	a.graph.Merge(r.Edges)
+	return nil
+}
+
+func (a *serviceGraphAggregator) build() *queryv1.Report {
+	return &queryv1.Report{
+		ServiceGraph: &queryv1.ServiceGraphReport{
+			Query: a.query,
+			Edges: a.graph.Edges(),
+		},
+	}
+}
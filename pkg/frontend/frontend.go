@@ -31,6 +31,7 @@ import (
 
 	"github.com/grafana/pyroscope/api/gen/proto/go/vcs/v1/vcsv1connect"
 	"github.com/grafana/pyroscope/pkg/frontend/frontendpb"
+	readpath "github.com/grafana/pyroscope/pkg/frontend/read_path"
 	"github.com/grafana/pyroscope/pkg/frontend/vcs"
 	"github.com/grafana/pyroscope/pkg/querier/stats"
 	"github.com/grafana/pyroscope/pkg/scheduler/schedulerdiscovery"
@@ -105,6 +106,7 @@ type Limits interface {
 	MaxQueryLength(tenantID string) time.Duration
 	MaxQueryLookback(tenantID string) time.Duration
 	QueryAnalysisEnabled(string) bool
+	ReadPathOverrides(tenantID string) readpath.Config
 	validation.FlameGraphLimits
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	readpath "github.com/grafana/pyroscope/pkg/frontend/read_path"
 	"github.com/grafana/pyroscope/pkg/model"
 	"github.com/grafana/pyroscope/pkg/util/connectgrpc"
 	"github.com/grafana/pyroscope/pkg/util/httpgrpc"
@@ -39,6 +40,10 @@ func (m *mockLimits) QueryAnalysisEnabled(_ string) bool {
 	return true
 }
 
+func (m *mockLimits) ReadPathOverrides(_ string) readpath.Config {
+	return readpath.Config{}
+}
+
 func (m *mockLimits) MaxFlameGraphNodesDefault(_ string) int {
 	return 10_000
 }
@@ -0,0 +1,82 @@
+package frontend
+
+import (
+	"context"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/common/model"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	validationutil "github.com/grafana/pyroscope/pkg/util/validation"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+// Explain reports the time range and the limits the frontend would apply
+// to the query. Blocks are selected by queriers at execution time, therefore
+// the response does not include them.
+func (f *Frontend) Explain(
+	ctx context.Context,
+	c *connect.Request[querierv1.ExplainRequest],
+) (*connect.Response[querierv1.ExplainResponse], error) {
+	opentracing.SpanFromContext(ctx).
+		SetTag("start", model.Time(c.Msg.Start).Time().String()).
+		SetTag("end", model.Time(c.Msg.End).Time().String()).
+		SetTag("selector", c.Msg.LabelSelector).
+		SetTag("profile_type", c.Msg.ProfileTypeID)
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	validated, err := validation.ValidateRangeRequest(f.limits, tenantIDs, model.Interval{Start: model.Time(c.Msg.Start), End: model.Time(c.Msg.End)}, model.Now())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	resp := new(querierv1.ExplainResponse)
+	if d := validationutil.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, f.limits.MaxQueryLookback); d > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:    "max_query_lookback",
+			Value:   model.Duration(d).String(),
+			Applied: validated.IsEmpty || int64(validated.Start) != c.Msg.Start,
+		})
+	}
+	if d := validationutil.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, f.limits.MaxQueryLength); d > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:  "max_query_length",
+			Value: model.Duration(d).String(),
+		})
+	}
+	if d := validationutil.MaxDurationOrZeroPerTenant(tenantIDs, f.limits.QuerySplitDuration); d > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:    "split_queries_by_interval",
+			Value:   model.Duration(d).String(),
+			Applied: validated.End.Sub(validated.Start) > d,
+		})
+	}
+	if n := validationutil.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.MaxQueryParallelism); n > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:  "max_query_parallelism",
+			Value: strconv.Itoa(n),
+		})
+	}
+	maxNodes, err := validation.ValidateMaxNodes(f.limits, tenantIDs, c.Msg.GetMaxNodes())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if maxNodes > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:    "max_flamegraph_nodes",
+			Value:   strconv.FormatInt(maxNodes, 10),
+			Applied: c.Msg.GetMaxNodes() == 0,
+		})
+	}
+	if !validated.IsEmpty {
+		resp.Start = int64(validated.Start)
+		resp.End = int64(validated.End)
+	}
+	return connect.NewResponse(resp), nil
+}
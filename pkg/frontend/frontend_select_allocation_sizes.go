@@ -0,0 +1,18 @@
+package frontend
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+)
+
+// SelectAllocationSizes returns no buckets: allocation sizes are only
+// computed by the query backend, for data in the v2 storage.
+func (f *Frontend) SelectAllocationSizes(
+	context.Context,
+	*connect.Request[querierv1.SelectAllocationSizesRequest],
+) (*connect.Response[querierv1.SelectAllocationSizesResponse], error) {
+	return connect.NewResponse(&querierv1.SelectAllocationSizesResponse{}), nil
+}
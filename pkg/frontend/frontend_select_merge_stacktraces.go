@@ -26,10 +26,17 @@ func (f *Frontend) SelectMergeStacktraces(
 	if err != nil {
 		return nil, err
 	}
+	t.Subtree(c.Msg.SubtreePath...)
+	if d := c.Msg.GetMaxDepth(); d > 0 {
+		t.TruncateDepth(len(c.Msg.SubtreePath) + int(d))
+	}
 	var resp querierv1.SelectMergeStacktracesResponse
 	switch c.Msg.Format {
 	default:
 		resp.Flamegraph = phlaremodel.NewFlameGraph(t, c.Msg.GetMaxNodes())
+		if c.Msg.ClassifyFrames {
+			phlaremodel.ClassifyFlameGraphFrames(resp.Flamegraph)
+		}
 	case querierv1.ProfileFormat_PROFILE_FORMAT_TREE:
 		resp.Tree = t.Bytes(c.Msg.GetMaxNodes())
 	}
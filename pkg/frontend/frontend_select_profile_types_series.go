@@ -0,0 +1,47 @@
+package frontend
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"golang.org/x/sync/errgroup"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+)
+
+// SelectProfileTypesSeries queries the series of each profile type
+// separately: the v1 read path can't share block reads between them.
+func (f *Frontend) SelectProfileTypesSeries(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectProfileTypesSeriesRequest],
+) (*connect.Response[querierv1.SelectProfileTypesSeriesResponse], error) {
+	resp := &querierv1.SelectProfileTypesSeriesResponse{
+		Results: make([]*querierv1.ProfileTypeSeries, len(c.Msg.ProfileTypeIDs)),
+	}
+	g, ctx := errgroup.WithContext(ctx)
+	for i, profileTypeID := range c.Msg.ProfileTypeIDs {
+		g.Go(func() error {
+			series, err := f.SelectSeries(ctx, connect.NewRequest(&querierv1.SelectSeriesRequest{
+				ProfileTypeID: profileTypeID,
+				LabelSelector: c.Msg.LabelSelector,
+				Start:         c.Msg.Start,
+				End:           c.Msg.End,
+				GroupBy:       c.Msg.GroupBy,
+				Step:          c.Msg.Step,
+				Limit:         c.Msg.Limit,
+			}))
+			if err != nil {
+				return err
+			}
+			resp.Results[i] = &querierv1.ProfileTypeSeries{
+				ProfileTypeID: profileTypeID,
+				Series:        series.Msg.Series,
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
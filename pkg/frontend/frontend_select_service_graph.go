@@ -0,0 +1,18 @@
+package frontend
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+)
+
+// SelectServiceGraph returns no edges: the service graph is only
+// computed by the query backend, for data in the v2 storage.
+func (f *Frontend) SelectServiceGraph(
+	context.Context,
+	*connect.Request[querierv1.SelectServiceGraphRequest],
+) (*connect.Response[querierv1.SelectServiceGraphResponse], error) {
+	return connect.NewResponse(&querierv1.SelectServiceGraphResponse{}), nil
+}
@@ -12,6 +12,7 @@ import (
 
 	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
 	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	"github.com/grafana/pyroscope/pkg/util/connectgrpc"
 	validationutil "github.com/grafana/pyroscope/pkg/util/validation"
@@ -84,6 +85,11 @@ func (f *Frontend) SelectSeries(
 		return nil, err
 	}
 
-	series := m.Top(int(c.Msg.GetLimit()))
+	var series []*typesv1.Series
+	if c.Msg.IncludeOther {
+		series = m.TopWithOther(int(c.Msg.GetLimit()), c.Msg.GroupBy)
+	} else {
+		series = m.Top(int(c.Msg.GetLimit()))
+	}
 	return connect.NewResponse(&querierv1.SelectSeriesResponse{Series: series}), nil
 }
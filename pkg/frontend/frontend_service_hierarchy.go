@@ -0,0 +1,18 @@
+package frontend
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+)
+
+// ServiceHierarchy returns no namespaces: the hierarchy is derived
+// from the metastore index, which only covers the v2 storage.
+func (f *Frontend) ServiceHierarchy(
+	context.Context,
+	*connect.Request[querierv1.ServiceHierarchyRequest],
+) (*connect.Response[querierv1.ServiceHierarchyResponse], error) {
+	return connect.NewResponse(&querierv1.ServiceHierarchyResponse{}), nil
+}
@@ -0,0 +1,218 @@
+package query_frontend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	querybackend "github.com/grafana/pyroscope/pkg/experiment/query_backend"
+	queryplan "github.com/grafana/pyroscope/pkg/experiment/query_backend/query_plan"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/querier"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+var (
+	// livePollInterval is how often the metastore is asked for new segments.
+	livePollInterval = 2 * time.Second
+	// liveMaxDuration limits the lifetime of a stream: clients are
+	// expected to reconnect, if they need to continue.
+	liveMaxDuration = 30 * time.Minute
+)
+
+// LiveStream streams the merged profile of the selection as new segments
+// arrive, using server-sent events. Only segments (blocks of the compaction
+// level 0) created after the stream has started are taken into account:
+// each segment is queried once, and the result is merged into the profile
+// accumulated since the stream start. An "update" event with the profile in
+// the flamebearer format is sent every time new data is merged.
+//
+// Segments that are compacted before they are observed are not included
+// in the stream. The poll interval is short enough compared to the
+// compaction delay for this to be rare.
+func (q *QueryFrontend) LiveStream(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	selector, profileType, err := querier.ParseQuery("query", r)
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	tenants, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	var maxNodes int64
+	if v, err := strconv.ParseInt(r.Form.Get("maxNodes"), 10, 64); err == nil {
+		maxNodes = v
+	}
+	if maxNodes, err = validation.ValidateMaxNodes(q.limits, tenants, maxNodes); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	labelSelector, err := buildLabelSelectorWithProfileType(selector, profileType.ID)
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+
+	// The stream outlives the server write timeout.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Now().Add(liveMaxDuration + livePollInterval))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err = rc.Flush(); err != nil {
+		level.Warn(q.logger).Log("msg", "streaming is not supported", "err", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), liveMaxDuration)
+	defer cancel()
+
+	s := &liveStream{
+		q:             q,
+		tenants:       tenants,
+		labelSelector: labelSelector,
+		profileType:   profileType,
+		maxNodes:      maxNodes,
+		start:         time.Now().UnixMilli(),
+		seen:          make(map[string]struct{}),
+		tree:          new(phlaremodel.Tree),
+	}
+
+	ticker := time.NewTicker(livePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		updated, err := s.poll(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				level.Warn(q.logger).Log("msg", "live stream failed", "err", err)
+				_ = writeEvent(w, "error", map[string]string{"error": err.Error()})
+				_ = rc.Flush()
+			}
+			return
+		}
+		if !updated {
+			// Comments are ignored by clients but keep the
+			// connection alive through proxies.
+			_, _ = fmt.Fprint(w, ": keep-alive\n\n")
+		} else if err = writeEvent(w, "update", s.flamebearer()); err != nil {
+			return
+		}
+		if err = rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+type liveStream struct {
+	q             *QueryFrontend
+	tenants       []string
+	labelSelector string
+	profileType   *typesv1.ProfileType
+	maxNodes      int64
+	start         int64
+
+	// seen holds the segments that have already been merged.
+	seen map[string]struct{}
+	tree *phlaremodel.Tree
+}
+
+// poll queries the segments that have not been seen yet, and merges the
+// result into the stream profile. It reports whether the profile has changed.
+func (s *liveStream) poll(ctx context.Context) (bool, error) {
+	end := time.Now().UnixMilli()
+	md, err := s.q.metadataQueryClient.QueryMetadata(ctx, &metastorev1.QueryMetadataRequest{
+		TenantId:  s.tenants,
+		StartTime: s.start,
+		EndTime:   end,
+		Query:     s.labelSelector,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// Only the segments returned by the latest query are retained: once
+	// a segment is compacted, it won't be returned again.
+	seen := make(map[string]struct{}, len(md.Blocks))
+	blocks := make([]*metastorev1.BlockMeta, 0, len(md.Blocks))
+	for _, b := range md.Blocks {
+		if b.CompactionLevel != 0 {
+			continue
+		}
+		seen[b.Id] = struct{}{}
+		if _, ok := s.seen[b.Id]; !ok {
+			blocks = append(blocks, b)
+		}
+	}
+	s.seen = seen
+	if len(blocks) == 0 {
+		return false, nil
+	}
+
+	resp, err := s.q.querybackendClient.Invoke(ctx, &queryv1.InvokeRequest{
+		Tenant:        s.tenants,
+		StartTime:     s.start,
+		EndTime:       end,
+		LabelSelector: s.labelSelector,
+		Options:       &queryv1.InvokeOptions{},
+		QueryPlan:     queryplan.Build(blocks, queryPlanMaxReads, queryPlanMaxMerges),
+		Query: []*queryv1.Query{{
+			QueryType: queryv1.QueryType_QUERY_TREE,
+			Tree:      &queryv1.TreeQuery{MaxNodes: s.maxNodes},
+		}},
+	})
+	if err != nil {
+		return false, err
+	}
+	report := findReport(querybackend.QueryReportType(queryv1.QueryType_QUERY_TREE), resp.Reports)
+	if report == nil {
+		return false, nil
+	}
+	delta, err := phlaremodel.UnmarshalTree(report.Tree.Tree)
+	if err != nil {
+		return false, err
+	}
+	if delta.Total() == 0 {
+		return false, nil
+	}
+	s.tree.Merge(delta)
+	return true, nil
+}
+
+func (s *liveStream) flamebearer() any {
+	fg := phlaremodel.NewFlameGraph(s.tree, s.maxNodes)
+	return phlaremodel.ExportToFlamebearer(fg, s.profileType)
+}
+
+func writeEvent(w http.ResponseWriter, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
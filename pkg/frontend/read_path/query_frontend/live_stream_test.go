@@ -0,0 +1,219 @@
+package query_frontend
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockmetastorev1"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+const liveStreamQuery = `process_cpu:cpu:nanoseconds:cpu:nanoseconds{service_name="svc"}`
+
+// fakeQueryBackend returns a tree with a single sample per block.
+type fakeQueryBackend struct {
+	mu     sync.Mutex
+	blocks [][]string
+}
+
+func (b *fakeQueryBackend) Invoke(_ context.Context, req *queryv1.InvokeRequest) (*queryv1.InvokeResponse, error) {
+	var ids []string
+	var visit func(*queryv1.QueryNode)
+	visit = func(n *queryv1.QueryNode) {
+		for _, m := range n.Blocks {
+			ids = append(ids, m.Id)
+		}
+		for _, c := range n.Children {
+			visit(c)
+		}
+	}
+	visit(req.QueryPlan.Root)
+	b.mu.Lock()
+	b.blocks = append(b.blocks, ids)
+	b.mu.Unlock()
+
+	tree := new(phlaremodel.Tree)
+	for range ids {
+		tree.InsertStack(1, "main", "foo")
+	}
+	return &queryv1.InvokeResponse{Reports: []*queryv1.Report{{
+		ReportType: queryv1.ReportType_REPORT_TREE,
+		Tree:       &queryv1.TreeReport{Tree: tree.Bytes(-1)},
+	}}}, nil
+}
+
+func (b *fakeQueryBackend) invoked() [][]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.blocks
+}
+
+func setLivePollInterval(t *testing.T, d time.Duration) {
+	prev := livePollInterval
+	livePollInterval = d
+	t.Cleanup(func() { livePollInterval = prev })
+}
+
+// newLiveStreamServer serves the live stream of the tenant. The returned
+// channel is closed when the handler returns.
+func newLiveStreamServer(t *testing.T, f *QueryFrontend) (*httptest.Server, chan struct{}) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		f.LiveStream(w, r.WithContext(tenant.InjectTenantID(r.Context(), "tenant")))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, done
+}
+
+type liveEvent struct {
+	event string
+	data  string
+}
+
+// readEvent reads the next event; keep-alive comments are
+// returned as events with the empty name.
+func readEvent(t *testing.T, r *bufio.Reader) liveEvent {
+	t.Helper()
+	var e liveEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return e
+		case strings.HasPrefix(line, "event: "):
+			e.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			e.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func numTicks(t *testing.T, e liveEvent) int {
+	t.Helper()
+	require.Equal(t, "update", e.event)
+	var fb struct {
+		Flamebearer struct {
+			NumTicks int `json:"numTicks"`
+		} `json:"flamebearer"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(e.data), &fb))
+	return fb.Flamebearer.NumTicks
+}
+
+func TestQueryFrontend_LiveStream(t *testing.T) {
+	setLivePollInterval(t, 10*time.Millisecond)
+	segment := func(id string, level uint32) *metastorev1.BlockMeta {
+		return &metastorev1.BlockMeta{Id: id, CompactionLevel: level}
+	}
+	responses := [][]*metastorev1.BlockMeta{
+		{segment("a", 0)},
+		// Compacted blocks are ignored.
+		{segment("a", 0), segment("b", 0), segment("c", 1)},
+		// Segment a has been compacted: nothing new.
+		{segment("b", 0)},
+	}
+
+	metaClient := mockmetastorev1.NewMockMetadataQueryServiceClient(t)
+	var requests []*metastorev1.QueryMetadataRequest
+	metaClient.On("QueryMetadata", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, req *metastorev1.QueryMetadataRequest, _ ...grpc.CallOption) (*metastorev1.QueryMetadataResponse, error) {
+			requests = append(requests, req)
+			if len(requests) > len(responses) {
+				return nil, errors.New("metastore unavailable")
+			}
+			return &metastorev1.QueryMetadataResponse{Blocks: responses[len(requests)-1]}, nil
+		})
+
+	backend := new(fakeQueryBackend)
+	f := NewQueryFrontend(log.NewNopLogger(), validation.MockLimits{}, metaClient, nil, nil, backend)
+	srv, done := newLiveStreamServer(t, f)
+
+	resp, err := http.Get(srv.URL + "?query=" + url.QueryEscape(liveStreamQuery))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+	assert.Equal(t, 1, numTicks(t, readEvent(t, r)))
+	assert.Equal(t, 2, numTicks(t, readEvent(t, r)))
+	assert.Equal(t, liveEvent{}, readEvent(t, r))
+
+	e := readEvent(t, r)
+	assert.Equal(t, "error", e.event)
+	assert.Contains(t, e.data, "metastore unavailable")
+	// The stream ends after the error.
+	_, err = r.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+	<-done
+
+	// Each segment is queried once.
+	assert.Equal(t, [][]string{{"a"}, {"b"}}, backend.invoked())
+	require.Len(t, requests, 4)
+	for _, req := range requests {
+		assert.Equal(t, []string{"tenant"}, req.TenantId)
+		assert.Contains(t, req.Query, `service_name="svc"`)
+		assert.Contains(t, req.Query, `__profile_type__="process_cpu:cpu:nanoseconds:cpu:nanoseconds"`)
+	}
+}
+
+func TestQueryFrontend_LiveStream_Cancel(t *testing.T) {
+	setLivePollInterval(t, 10*time.Millisecond)
+	metaClient := mockmetastorev1.NewMockMetadataQueryServiceClient(t)
+	metaClient.On("QueryMetadata", mock.Anything, mock.Anything).
+		Return(new(metastorev1.QueryMetadataResponse), nil)
+
+	backend := new(fakeQueryBackend)
+	f := NewQueryFrontend(log.NewNopLogger(), validation.MockLimits{}, metaClient, nil, nil, backend)
+	srv, done := newLiveStreamServer(t, f)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?query="+url.QueryEscape(liveStreamQuery), nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, liveEvent{}, readEvent(t, bufio.NewReader(resp.Body)))
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("live stream is not stopped")
+	}
+	assert.Empty(t, backend.invoked())
+}
+
+func TestQueryFrontend_LiveStream_InvalidQuery(t *testing.T) {
+	f := NewQueryFrontend(log.NewNopLogger(), validation.MockLimits{}, nil, nil, nil, nil)
+	srv, _ := newLiveStreamServer(t, f)
+
+	resp, err := http.Get(srv.URL + "?query=" + url.QueryEscape(`{service_name="svc"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,108 @@
+package query_frontend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+func (q *QueryFrontend) SelectAllocationSizes(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectAllocationSizesRequest],
+) (*connect.Response[querierv1.SelectAllocationSizesResponse], error) {
+	opentracing.SpanFromContext(ctx).
+		SetTag("start", model.Time(c.Msg.Start).Time().String()).
+		SetTag("end", model.Time(c.Msg.End).Time().String()).
+		SetTag("selector", c.Msg.LabelSelector).
+		SetTag("function_name", c.Msg.GetFunctionName()).
+		SetTag("profile_type", c.Msg.ProfileTypeID)
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	empty, err := validation.SanitizeTimeRange(q.limits, tenantIDs, &c.Msg.Start, &c.Msg.End)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if empty {
+		return connect.NewResponse(&querierv1.SelectAllocationSizesResponse{}), nil
+	}
+
+	space, err := phlaremodel.ParseProfileTypeSelector(c.Msg.ProfileTypeID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	objects, err := allocationObjectsProfileType(space)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	// The profile type ID may have the optional delta suffix.
+	space.ID = phlaremodel.SelectorFromProfileType(space).Value
+	matchers, err := parser.ParseMetricSelector(c.Msg.LabelSelector)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	// Both profile types are selected: the query backend
+	// tells them apart by the profile type label.
+	matchers = append(matchers, &labels.Matcher{
+		Type:  labels.MatchRegexp,
+		Name:  phlaremodel.LabelNameProfileType,
+		Value: space.ID + "|" + objects.ID,
+	})
+
+	report, err := q.querySingle(ctx, &queryv1.QueryRequest{
+		StartTime:     c.Msg.Start,
+		EndTime:       c.Msg.End,
+		LabelSelector: matchersToLabelSelector(matchers),
+		Query: []*queryv1.Query{{
+			QueryType: queryv1.QueryType_QUERY_ALLOCATION_SIZES,
+			AllocationSizes: &queryv1.AllocationSizesQuery{
+				SpaceProfileType:   space.ID,
+				ObjectsProfileType: objects.ID,
+				FunctionName:       c.Msg.GetFunctionName(),
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return connect.NewResponse(&querierv1.SelectAllocationSizesResponse{}), nil
+	}
+	return connect.NewResponse(&querierv1.SelectAllocationSizesResponse{
+		Buckets: report.AllocationSizes.Buckets,
+	}), nil
+}
+
+// allocationObjectsProfileType returns the profile type of the allocated
+// objects that corresponds to the profile type of the allocated space:
+// e.g., memory:alloc_objects:count:space:bytes for
+// memory:alloc_space:bytes:space:bytes.
+func allocationObjectsProfileType(space *typesv1.ProfileType) (*typesv1.ProfileType, error) {
+	prefix, ok := strings.CutSuffix(space.SampleType, "_space")
+	if !ok {
+		prefix, ok = strings.CutSuffix(space.SampleType, "_bytes")
+	}
+	if !ok || space.SampleUnit != "bytes" {
+		return nil, fmt.Errorf("%s is not an allocated space profile type", space.ID)
+	}
+	objects := space.CloneVT()
+	objects.SampleType = prefix + "_objects"
+	objects.SampleUnit = "count"
+	objects.ID = phlaremodel.SelectorFromProfileType(objects).Value
+	return objects, nil
+}
@@ -0,0 +1,32 @@
+package query_frontend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+)
+
+func Test_allocationObjectsProfileType(t *testing.T) {
+	for space, expected := range map[string]string{
+		"memory:alloc_space:bytes:space:bytes":                 "memory:alloc_objects:count:space:bytes",
+		"memory:inuse_space:bytes:space:bytes":                 "memory:inuse_objects:count:space:bytes",
+		"memory:alloc_in_new_tlab_bytes:bytes::":               "memory:alloc_in_new_tlab_objects:count::",
+		"memory:alloc_space:bytes:space:bytes:delta":           "memory:alloc_objects:count:space:bytes",
+		"process_cpu:cpu:nanoseconds:cpu:nanoseconds":          "",
+		"memory:alloc_objects:count:space:bytes":               "",
+		"memory:alloc_in_new_tlab_bytes:nanoseconds:cpu:bytes": "",
+	} {
+		pt, err := phlaremodel.ParseProfileTypeSelector(space)
+		require.NoError(t, err)
+		objects, err := allocationObjectsProfileType(pt)
+		if expected == "" {
+			assert.Error(t, err, space)
+			continue
+		}
+		require.NoError(t, err, space)
+		assert.Equal(t, expected, objects.ID)
+	}
+}
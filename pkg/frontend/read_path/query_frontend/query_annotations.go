@@ -0,0 +1,114 @@
+package query_frontend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+// queryAnnotations returns annotations of the services matched by the
+// label selector. Annotations are auxiliary to the query results, therefore
+// errors are logged and not returned.
+func (q *QueryFrontend) queryAnnotations(
+	ctx context.Context,
+	tenants []string,
+	labelSelector string,
+	start, end int64,
+) []*typesv1.Annotation {
+	req := &metastorev1.QueryAnnotationsRequest{
+		Tenant:    tenants,
+		StartTime: start,
+		EndTime:   end,
+	}
+	// Only equality matchers narrow down the services; otherwise,
+	// annotations of all the services are returned.
+	if matchers, err := parser.ParseMetricSelector(labelSelector); err == nil {
+		for _, m := range matchers {
+			if m.Name == phlaremodel.LabelNameServiceName && m.Type == labels.MatchEqual {
+				req.ServiceName = []string{m.Value}
+			}
+		}
+	}
+	resp, err := q.annotationClient.QueryAnnotations(ctx, req)
+	if err != nil {
+		level.Warn(q.logger).Log("msg", "failed to query annotations", "err", err)
+		return nil
+	}
+	return resp.Annotations
+}
+
+type annotationRequest struct {
+	ServiceName string            `json:"serviceName"`
+	Kind        string            `json:"kind"`
+	Text        string            `json:"text"`
+	Labels      map[string]string `json:"labels"`
+	// Milliseconds since epoch. Defaults to the current time.
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+}
+
+// AddAnnotation handles annotations posted by external systems, such as
+// CD pipelines marking deployments of a service.
+func (q *QueryFrontend) AddAnnotation(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	var req annotationRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid annotation: %w", err)))
+		return
+	}
+	if req.Kind == "" {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("annotation kind is required")))
+		return
+	}
+	if req.StartTime == 0 {
+		req.StartTime = time.Now().UnixMilli()
+	}
+
+	annotation := &typesv1.Annotation{
+		ServiceName: req.ServiceName,
+		Kind:        req.Kind,
+		Text:        req.Text,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Labels:      make([]*typesv1.LabelPair, 0, len(req.Labels)),
+	}
+	for name, value := range req.Labels {
+		annotation.Labels = append(annotation.Labels, &typesv1.LabelPair{Name: name, Value: value})
+	}
+	sort.Sort(phlaremodel.Labels(annotation.Labels))
+
+	resp, err := q.annotationClient.AddAnnotation(r.Context(), &metastorev1.AddAnnotationRequest{
+		Tenant:     tenantID,
+		Annotation: annotation,
+	})
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	b, err := protojson.Marshal(resp.Annotation)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,74 @@
+package query_frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+type annotationClient struct {
+	added   *metastorev1.AddAnnotationRequest
+	queried *metastorev1.QueryAnnotationsRequest
+}
+
+func (c *annotationClient) AddAnnotation(_ context.Context, req *metastorev1.AddAnnotationRequest, _ ...grpc.CallOption) (*metastorev1.AddAnnotationResponse, error) {
+	c.added = req
+	annotation := req.Annotation.CloneVT()
+	annotation.Id = "id"
+	return &metastorev1.AddAnnotationResponse{Annotation: annotation}, nil
+}
+
+func (c *annotationClient) QueryAnnotations(_ context.Context, req *metastorev1.QueryAnnotationsRequest, _ ...grpc.CallOption) (*metastorev1.QueryAnnotationsResponse, error) {
+	c.queried = req
+	return &metastorev1.QueryAnnotationsResponse{Annotations: []*typesv1.Annotation{{Id: "id"}}}, nil
+}
+
+func TestQueryFrontend_AddAnnotation(t *testing.T) {
+	client := new(annotationClient)
+	f := NewQueryFrontend(log.NewNopLogger(), nil, nil, nil, client, nil)
+
+	body := `{"serviceName":"checkout","kind":"deploy","text":"v1.2.3","labels":{"version":"1.2.3","env":"prod"},"startTime":1000}`
+	r := httptest.NewRequest(http.MethodPost, "/pyroscope/annotations", strings.NewReader(body))
+	r = r.WithContext(tenant.InjectTenantID(r.Context(), "tenant"))
+	w := httptest.NewRecorder()
+	f.AddAnnotation(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"id":"id","serviceName":"checkout","kind":"deploy","text":"v1.2.3","labels":[{"name":"env","value":"prod"},{"name":"version","value":"1.2.3"}],"startTime":"1000"}`, w.Body.String())
+	assert.Equal(t, "tenant", client.added.Tenant)
+
+	r = httptest.NewRequest(http.MethodPost, "/pyroscope/annotations", strings.NewReader(`{"text":"no kind"}`))
+	r = r.WithContext(tenant.InjectTenantID(r.Context(), "tenant"))
+	w = httptest.NewRecorder()
+	f.AddAnnotation(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestQueryFrontend_QueryAnnotations(t *testing.T) {
+	client := new(annotationClient)
+	f := NewQueryFrontend(log.NewNopLogger(), nil, nil, nil, client, nil)
+	ctx := context.Background()
+
+	annotations := f.queryAnnotations(ctx, []string{"tenant"}, `{service_name="checkout", env="prod"}`, 10, 20)
+	assert.Len(t, annotations, 1)
+	assert.Equal(t, &metastorev1.QueryAnnotationsRequest{
+		Tenant:      []string{"tenant"},
+		StartTime:   10,
+		EndTime:     20,
+		ServiceName: []string{"checkout"},
+	}, client.queried)
+
+	f.queryAnnotations(ctx, []string{"tenant"}, `{service_name=~"checkout|cart"}`, 10, 20)
+	assert.Empty(t, client.queried.ServiceName)
+}
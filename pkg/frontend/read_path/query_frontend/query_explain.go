@@ -0,0 +1,119 @@
+package query_frontend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	validationutil "github.com/grafana/pyroscope/pkg/util/validation"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+const metastoreIndexCacheName = "metastore_index_partitions"
+
+// Explain resolves the query plan the same way Query does, but
+// stops before the query backend is invoked.
+func (q *QueryFrontend) Explain(
+	ctx context.Context,
+	c *connect.Request[querierv1.ExplainRequest],
+) (*connect.Response[querierv1.ExplainResponse], error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	resp := new(querierv1.ExplainResponse)
+	start, end := c.Msg.Start, c.Msg.End
+	empty, err := validation.SanitizeTimeRange(q.limits, tenantIDs, &c.Msg.Start, &c.Msg.End)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if d := validationutil.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, q.limits.MaxQueryLookback); d > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:    "max_query_lookback",
+			Value:   model.Duration(d).String(),
+			Applied: empty || c.Msg.Start != start || c.Msg.End != end,
+		})
+	}
+	if d := validationutil.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, q.limits.MaxQueryLength); d > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:  "max_query_length",
+			Value: model.Duration(d).String(),
+		})
+	}
+	maxNodes, err := validation.ValidateMaxNodes(q.limits, tenantIDs, c.Msg.GetMaxNodes())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if maxNodes > 0 {
+		resp.Limits = append(resp.Limits, &querierv1.ExplainLimit{
+			Name:    "max_flamegraph_nodes",
+			Value:   strconv.FormatInt(maxNodes, 10),
+			Applied: c.Msg.GetMaxNodes() == 0,
+		})
+	}
+	if empty {
+		return connect.NewResponse(resp), nil
+	}
+	resp.Start, resp.End = c.Msg.Start, c.Msg.End
+
+	labelSelector, err := buildLabelSelectorWithProfileType(c.Msg.LabelSelector, c.Msg.ProfileTypeID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	md, err := q.metadataQueryClient.QueryMetadata(ctx, &metastorev1.QueryMetadataRequest{
+		TenantId:  tenantIDs,
+		StartTime: c.Msg.Start,
+		EndTime:   c.Msg.End,
+		Query:     labelSelector,
+		Explain:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var cached int
+	for _, p := range md.Partitions {
+		resp.Partitions = append(resp.Partitions, &querierv1.ExplainPartition{
+			Key:    p.Key,
+			Start:  p.StartTime,
+			End:    p.EndTime,
+			Cached: p.Cached,
+		})
+		if p.Cached {
+			cached++
+		}
+	}
+	if len(md.Partitions) > 0 {
+		resp.Caches = append(resp.Caches, &querierv1.ExplainCache{
+			Name:        metastoreIndexCacheName,
+			ExpectedHit: cached == len(md.Partitions),
+			Details:     fmt.Sprintf("%d of %d partitions loaded in memory", cached, len(md.Partitions)),
+		})
+	}
+
+	for _, b := range md.Blocks {
+		block := &querierv1.ExplainBlock{
+			Id:              b.Id,
+			Shard:           b.Shard,
+			CompactionLevel: b.CompactionLevel,
+			MinTime:         b.MinTime,
+			MaxTime:         b.MaxTime,
+			Datasets:        make([]string, 0, len(b.Datasets)),
+		}
+		for _, ds := range b.Datasets {
+			block.Datasets = append(block.Datasets, ds.Name)
+			block.Size += ds.Size
+		}
+		resp.EstimatedBytes += block.Size
+		resp.Blocks = append(resp.Blocks, block)
+	}
+
+	return connect.NewResponse(resp), nil
+}
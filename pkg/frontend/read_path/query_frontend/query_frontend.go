@@ -3,6 +3,7 @@ package query_frontend
 import (
 	"context"
 	"math/rand"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
@@ -12,20 +13,34 @@ import (
 	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
 	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
 	querybackend "github.com/grafana/pyroscope/pkg/experiment/query_backend"
-	querybackendclient "github.com/grafana/pyroscope/pkg/experiment/query_backend/client"
 	queryplan "github.com/grafana/pyroscope/pkg/experiment/query_backend/query_plan"
 	"github.com/grafana/pyroscope/pkg/frontend"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+	"github.com/grafana/pyroscope/pkg/validation"
 )
 
 var _ querierv1connect.QuerierServiceClient = (*QueryFrontend)(nil)
 
+type QueryBackend interface {
+	Invoke(ctx context.Context, req *queryv1.InvokeRequest) (*queryv1.InvokeResponse, error)
+}
+
+// Parameters of the query plan: the maximum number of
+// blocks per read node, and of inputs per merge node.
+const (
+	queryPlanMaxReads  = 4
+	queryPlanMaxMerges = 20
+)
+
 type QueryFrontend struct {
 	logger log.Logger
 	limits frontend.Limits
 
 	metadataQueryClient metastorev1.MetadataQueryServiceClient
 	tenantServiceClient metastorev1.TenantServiceClient
-	querybackendClient  *querybackendclient.Client
+	annotationClient    metastorev1.AnnotationServiceClient
+	querybackendClient  QueryBackend
+	slowQueries         *slowQueryLog
 }
 
 func NewQueryFrontend(
@@ -33,22 +48,44 @@ func NewQueryFrontend(
 	limits frontend.Limits,
 	metadataQueryClient metastorev1.MetadataQueryServiceClient,
 	tenantServiceClient metastorev1.TenantServiceClient,
-	querybackendClient *querybackendclient.Client,
+	annotationClient metastorev1.AnnotationServiceClient,
+	querybackendClient QueryBackend,
 ) *QueryFrontend {
 	return &QueryFrontend{
 		logger:              logger,
 		limits:              limits,
 		metadataQueryClient: metadataQueryClient,
 		tenantServiceClient: tenantServiceClient,
+		annotationClient:    annotationClient,
 		querybackendClient:  querybackendClient,
+		slowQueries:         newSlowQueryLog(log.With(logger, "log", "slow_queries"), limits),
 	}
 }
 
 var xrand = rand.New(rand.NewSource(4349676827832284783))
 
+// readAfterWritePollInterval is how often the metadata is queried while
+// waiting for the most recent data to become available.
+var readAfterWritePollInterval = 100 * time.Millisecond
+
 func (q *QueryFrontend) Query(
 	ctx context.Context,
 	req *queryv1.QueryRequest,
+) (*queryv1.QueryResponse, error) {
+	var stats queryStats
+	start := time.Now()
+	resp, err := q.query(ctx, req, &stats)
+	q.slowQueries.observe(ctx, req, stats, time.Since(start), err)
+	// The rest of the request handling is considered rendering:
+	// conversion of the reports and serialization of the response.
+	httputil.ServerTimingFromContext(ctx).Start("render")
+	return resp, err
+}
+
+func (q *QueryFrontend) query(
+	ctx context.Context,
+	req *queryv1.QueryRequest,
+	stats *queryStats,
 ) (*queryv1.QueryResponse, error) {
 	// TODO(kolesnikovae):
 	// This method is supposed to be the entry point of the read path
@@ -58,24 +95,39 @@ func (q *QueryFrontend) Query(
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	md, err := q.metadataQueryClient.QueryMetadata(ctx, &metastorev1.QueryMetadataRequest{
-		TenantId:  tenants,
-		StartTime: req.StartTime,
-		EndTime:   req.EndTime,
-		Query:     req.LabelSelector,
-	})
+	// The time range limits are enforced before the metadata is queried:
+	// a query beyond the lookback period must not cause a bucket scan,
+	// regardless of the API it was issued with.
+	empty, err := validation.SanitizeTimeRange(q.limits, tenants, &req.StartTime, &req.EndTime)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if empty {
+		return new(queryv1.QueryResponse), nil
+	}
+	timing := httputil.ServerTimingFromContext(ctx)
+	resolveStart := time.Now()
+	md, err := q.queryMetadata(ctx, tenants, req)
+	timing.Add("metastore-resolve", time.Since(resolveStart))
 	if err != nil {
 		return nil, err
 	}
 	if len(md.Blocks) == 0 {
 		return new(queryv1.QueryResponse), nil
 	}
+	stats.Blocks = len(md.Blocks)
+	for _, b := range md.Blocks {
+		stats.Datasets += len(b.Datasets)
+		for _, ds := range b.Datasets {
+			stats.Bytes += ds.Size
+		}
+	}
 
 	// Randomize the order of blocks to avoid hotspots.
 	xrand.Shuffle(len(md.Blocks), func(i, j int) {
 		md.Blocks[i], md.Blocks[j] = md.Blocks[j], md.Blocks[i]
 	})
-	p := queryplan.Build(md.Blocks, 4, 20)
+	p := queryplan.Build(md.Blocks, queryPlanMaxReads, queryPlanMaxMerges)
 
 	resp, err := q.querybackendClient.Invoke(ctx, &queryv1.InvokeRequest{
 		Tenant:        tenants,
@@ -89,6 +141,10 @@ func (q *QueryFrontend) Query(
 	if err != nil {
 		return nil, err
 	}
+	if d := resp.Diagnostics; d != nil {
+		timing.Add("bucket-fetch", time.Duration(d.ReadDurationNanos))
+		timing.Add("merge", time.Duration(d.MergeDurationNanos))
+	}
 	resp.Diagnostics = &queryv1.Diagnostics{
 		QueryPlan: p,
 		// TODO(kolesnikovae): Extend diagnostics
@@ -96,6 +152,85 @@ func (q *QueryFrontend) Query(
 	return &queryv1.QueryResponse{Reports: resp.Reports}, nil
 }
 
+// allowStaleRead reports whether the query metadata may be resolved by
+// a metastore follower replica: this must be allowed for all the tenants.
+func (q *QueryFrontend) allowStaleRead(tenants []string) bool {
+	for _, t := range tenants {
+		if !q.limits.ReadPathOverrides(t).MetastoreStaleReads {
+			return false
+		}
+	}
+	return len(tenants) > 0
+}
+
+// queryMetadata resolves the blocks of the query. The most recent data may
+// not be available yet: segments are registered in the metastore after they
+// are flushed. If the query covers the recent past, the metadata is polled
+// until a block with data at or after the query end (or the current time,
+// if it is earlier) appears, or the read-after-write delay expires.
+func (q *QueryFrontend) queryMetadata(
+	ctx context.Context,
+	tenants []string,
+	req *queryv1.QueryRequest,
+) (*metastorev1.QueryMetadataResponse, error) {
+	now := time.Now()
+	delay := q.readAfterWriteDelay(tenants, req.EndTime)
+	mdReq := &metastorev1.QueryMetadataRequest{
+		TenantId:       tenants,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Query:          req.LabelSelector,
+		ReadBarrier:    delay > 0,
+		AllowStaleRead: q.allowStaleRead(tenants),
+	}
+	if delay <= 0 {
+		return q.metadataQueryClient.QueryMetadata(ctx, mdReq)
+	}
+	target := min(req.EndTime, now.UnixMilli())
+	deadline := now.Add(delay)
+	for {
+		md, err := q.metadataQueryClient.QueryMetadata(ctx, mdReq)
+		if err != nil || hasDataAfter(md.Blocks, target) {
+			return md, err
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return md, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(min(wait, readAfterWritePollInterval)):
+		}
+	}
+}
+
+func hasDataAfter(blocks []*metastorev1.BlockMeta, t int64) bool {
+	for _, b := range blocks {
+		if b.MaxTime >= t {
+			return true
+		}
+	}
+	return false
+}
+
+// readAfterWriteDelay returns how long the query may wait for the data
+// ingested before the query end to become available. The delay does not
+// exceed the configured one: data ingested after the query is received is
+// not waited for.
+func (q *QueryFrontend) readAfterWriteDelay(tenants []string, end int64) time.Duration {
+	var delay time.Duration
+	for _, t := range tenants {
+		delay = max(delay, q.limits.ReadPathOverrides(t).ReadAfterWriteDelay)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	now := time.Now()
+	available := time.UnixMilli(min(end, now.UnixMilli())).Add(delay)
+	return available.Sub(now)
+}
+
 // querySingle is a helper method that expects a single report
 // of the appropriate type in the response; this method should
 // be used to implement adapter to the old query API.
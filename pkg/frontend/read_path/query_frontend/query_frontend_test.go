@@ -0,0 +1,201 @@
+package query_frontend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	readpath "github.com/grafana/pyroscope/pkg/frontend/read_path"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockmetastorev1"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+func TestQueryFrontend_ReadAfterWriteDelay(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	limits := validation.MockLimits{
+		ReadPathOverridesValue: readpath.Config{
+			ReadAfterWriteDelay: delay,
+			MetastoreStaleReads: true,
+		},
+	}
+	metaClient := mockmetastorev1.NewMockMetadataQueryServiceClient(t)
+	var requests []*metastorev1.QueryMetadataRequest
+	metaClient.On("QueryMetadata", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			requests = append(requests, args.Get(1).(*metastorev1.QueryMetadataRequest))
+		}).
+		Return(new(metastorev1.QueryMetadataResponse), nil)
+
+	f := NewQueryFrontend(log.NewNopLogger(), limits, metaClient, nil, nil, nil)
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+	now := time.UnixMilli(time.Now().UnixMilli())
+
+	// The query covers the recent past, but the data never arrives:
+	// the metadata is polled until the delay expires.
+	_, err := f.Query(ctx, &queryv1.QueryRequest{
+		StartTime: now.Add(-5 * time.Minute).UnixMilli(),
+		EndTime:   now.UnixMilli(),
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(now), delay)
+	require.Greater(t, len(requests), 1)
+	for _, r := range requests {
+		assert.True(t, r.ReadBarrier)
+	}
+
+	// The query does not cover the recent past.
+	requests = requests[:0]
+	start := time.Now()
+	_, err = f.Query(ctx, &queryv1.QueryRequest{
+		StartTime: now.Add(-2 * time.Hour).UnixMilli(),
+		EndTime:   now.Add(-time.Hour).UnixMilli(),
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), delay)
+	require.Len(t, requests, 1)
+	assert.False(t, requests[0].ReadBarrier)
+	// Queries that do not need the most recent metadata
+	// can be served by a metastore follower.
+	assert.True(t, requests[0].AllowStaleRead)
+
+	// The query is canceled while waiting.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = f.Query(ctx, &queryv1.QueryRequest{
+		StartTime: now.UnixMilli(),
+		EndTime:   now.Add(time.Minute).UnixMilli(),
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestQueryFrontend_queryMetadata_DataAvailable(t *testing.T) {
+	const delay = 10 * time.Second
+	limits := validation.MockLimits{
+		ReadPathOverridesValue: readpath.Config{ReadAfterWriteDelay: delay},
+	}
+	now := time.Now()
+	metaClient := mockmetastorev1.NewMockMetadataQueryServiceClient(t)
+	// The block covering the query end only appears on the third attempt.
+	var attempts int
+	metaClient.On("QueryMetadata", mock.Anything, mock.Anything).
+		Return(func(context.Context, *metastorev1.QueryMetadataRequest, ...grpc.CallOption) (*metastorev1.QueryMetadataResponse, error) {
+			attempts++
+			blocks := []*metastorev1.BlockMeta{{Id: "old", MaxTime: now.Add(-time.Minute).UnixMilli()}}
+			if attempts >= 3 {
+				blocks = append(blocks, &metastorev1.BlockMeta{Id: "new", MaxTime: now.UnixMilli()})
+			}
+			return &metastorev1.QueryMetadataResponse{Blocks: blocks}, nil
+		})
+
+	f := NewQueryFrontend(log.NewNopLogger(), limits, metaClient, nil, nil, nil)
+	start := time.Now()
+	md, err := f.queryMetadata(context.Background(), []string{"tenant"}, &queryv1.QueryRequest{
+		StartTime: now.Add(-5 * time.Minute).UnixMilli(),
+		EndTime:   now.UnixMilli(),
+	})
+	require.NoError(t, err)
+	// The query does not wait for the delay to expire.
+	assert.Less(t, time.Since(start), delay)
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, md.Blocks, 2)
+}
+
+func TestQueryFrontend_TimeRangeLimits(t *testing.T) {
+	limits := validation.MockLimits{
+		MaxQueryLookbackValue: 24 * time.Hour,
+		MaxQueryLengthValue:   6 * time.Hour,
+	}
+	metaClient := mockmetastorev1.NewMockMetadataQueryServiceClient(t)
+	var requests []*metastorev1.QueryMetadataRequest
+	metaClient.On("QueryMetadata", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			requests = append(requests, args.Get(1).(*metastorev1.QueryMetadataRequest))
+		}).
+		Return(new(metastorev1.QueryMetadataResponse), nil)
+
+	f := NewQueryFrontend(log.NewNopLogger(), limits, metaClient, nil, nil, nil)
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+	now := time.Now()
+
+	// The query is fully outside the lookback period:
+	// the metadata is not queried.
+	_, err := f.Query(ctx, &queryv1.QueryRequest{
+		StartTime: now.Add(-72 * time.Hour).UnixMilli(),
+		EndTime:   now.Add(-48 * time.Hour).UnixMilli(),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	// The query is too long.
+	_, err = f.Query(ctx, &queryv1.QueryRequest{
+		StartTime: now.Add(-12 * time.Hour).UnixMilli(),
+		EndTime:   now.UnixMilli(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	assert.Empty(t, requests)
+
+	// The start time is adjusted to the lookback period.
+	_, err = f.Query(ctx, &queryv1.QueryRequest{
+		StartTime: now.Add(-30 * time.Hour).UnixMilli(),
+		EndTime:   now.Add(-20 * time.Hour).UnixMilli(),
+	})
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.GreaterOrEqual(t, requests[0].StartTime, now.Add(-24*time.Hour).UnixMilli())
+	assert.Equal(t, now.Add(-20*time.Hour).UnixMilli(), requests[0].EndTime)
+}
+
+func TestQueryFrontend_SlowQueryLog(t *testing.T) {
+	limits := validation.MockLimits{
+		ReadPathOverridesValue: readpath.Config{SlowQueryLogDuration: time.Nanosecond},
+	}
+	metaClient := mockmetastorev1.NewMockMetadataQueryServiceClient(t)
+	metaClient.On("QueryMetadata", mock.Anything, mock.Anything).
+		Return(new(metastorev1.QueryMetadataResponse), nil)
+
+	f := NewQueryFrontend(log.NewNopLogger(), limits, metaClient, nil, nil, nil)
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+	now := time.Now()
+	_, err := f.Query(ctx, &queryv1.QueryRequest{
+		StartTime:     now.Add(-time.Hour).UnixMilli(),
+		EndTime:       now.UnixMilli(),
+		LabelSelector: `{service_name="svc"}`,
+		Query:         []*queryv1.Query{{QueryType: queryv1.QueryType_QUERY_TREE}},
+	})
+	require.NoError(t, err)
+
+	queries := f.slowQueries.recent()
+	require.Len(t, queries, 1)
+	assert.Equal(t, []string{"tenant"}, queries[0].Tenants)
+	assert.Equal(t, `{service_name="svc"}`, queries[0].LabelSelector)
+	assert.Equal(t, []string{"QUERY_TREE"}, queries[0].QueryTypes)
+	assert.Equal(t, time.Hour, queries[0].EndTime.Sub(queries[0].StartTime))
+}
+
+func Test_slowQueryLog(t *testing.T) {
+	limits := validation.MockLimits{
+		ReadPathOverridesValue: readpath.Config{SlowQueryLogBytes: 100},
+	}
+	l := newSlowQueryLog(log.NewNopLogger(), limits)
+	ctx := tenant.InjectTenantID(context.Background(), "tenant")
+	for i := 0; i < slowQueryLogSize+10; i++ {
+		l.observe(ctx, &queryv1.QueryRequest{}, queryStats{Bytes: uint64(100 + i)}, time.Second, nil)
+	}
+	l.observe(ctx, &queryv1.QueryRequest{}, queryStats{Bytes: 99}, time.Second, nil)
+
+	queries := l.recent()
+	require.Len(t, queries, slowQueryLogSize)
+	assert.Equal(t, uint64(100+slowQueryLogSize+9), queries[0].Stats.Bytes)
+	assert.Equal(t, uint64(110), queries[slowQueryLogSize-1].Stats.Bytes)
+}
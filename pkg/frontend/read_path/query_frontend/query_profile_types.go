@@ -32,10 +32,11 @@ func (q *QueryFrontend) ProfileTypes(
 	}
 
 	md, err := q.metadataQueryClient.QueryMetadata(ctx, &metastorev1.QueryMetadataRequest{
-		TenantId:  tenants,
-		StartTime: req.Msg.Start,
-		EndTime:   req.Msg.End,
-		Query:     "{}",
+		TenantId:       tenants,
+		StartTime:      req.Msg.Start,
+		EndTime:        req.Msg.End,
+		Query:          "{}",
+		AllowStaleRead: q.allowStaleRead(tenants),
 	})
 
 	if err != nil {
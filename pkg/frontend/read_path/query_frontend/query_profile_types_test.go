@@ -12,6 +12,7 @@ import (
 
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	readpath "github.com/grafana/pyroscope/pkg/frontend/read_path"
 	"github.com/grafana/pyroscope/pkg/tenant"
 	"github.com/grafana/pyroscope/pkg/test/mocks/mockfrontend"
 	"github.com/grafana/pyroscope/pkg/test/mocks/mockmetastorev1"
@@ -20,11 +21,12 @@ import (
 func TestQueryFrontend_ProfileTypes(t *testing.T) {
 	metaClient := mockmetastorev1.NewMockMetadataQueryServiceClient(t)
 	limits := mockfrontend.NewMockLimits(t)
-	f := NewQueryFrontend(log.NewNopLogger(), limits, metaClient, nil, nil)
+	f := NewQueryFrontend(log.NewNopLogger(), limits, metaClient, nil, nil, nil)
 	require.NotNil(t, f)
 
 	limits.On("MaxQueryLookback", mock.Anything).Return(24 * time.Hour)
 	limits.On("MaxQueryLength", mock.Anything).Return(2 * time.Hour)
+	limits.On("ReadPathOverrides", mock.Anything).Return(readpath.Config{})
 	metaClient.On("QueryMetadata", mock.Anything, mock.Anything).Maybe().Return(&metastorev1.QueryMetadataResponse{
 		Blocks: []*metastorev1.BlockMeta{
 			{
@@ -23,6 +23,13 @@ func (q *QueryFrontend) SelectMergeStacktraces(
 		return nil, err
 	}
 	var resp querierv1.SelectMergeStacktracesResponse
+	if b != nil {
+		tenantIDs, err := tenant.TenantIDs(ctx)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		resp.Annotations = q.queryAnnotations(ctx, tenantIDs, c.Msg.LabelSelector, c.Msg.Start, c.Msg.End)
+	}
 	switch c.Msg.Format {
 	case querierv1.ProfileFormat_PROFILE_FORMAT_TREE:
 		resp.Tree = b
@@ -32,6 +39,9 @@ func (q *QueryFrontend) SelectMergeStacktraces(
 			return nil, err
 		}
 		resp.Flamegraph = phlaremodel.NewFlameGraph(t, c.Msg.GetMaxNodes())
+		if c.Msg.ClassifyFrames {
+			phlaremodel.ClassifyFlameGraphFrames(resp.Flamegraph)
+		}
 	}
 	return connect.NewResponse(&resp), nil
 }
@@ -0,0 +1,137 @@
+package query_frontend
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+// SelectProfileTypesSeries queries all the profile types at once:
+// blocks are resolved and read only once, and the series are
+// grouped by the profile type in addition to the requested labels.
+func (q *QueryFrontend) SelectProfileTypesSeries(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectProfileTypesSeriesRequest],
+) (*connect.Response[querierv1.SelectProfileTypesSeriesResponse], error) {
+	opentracing.SpanFromContext(ctx).
+		SetTag("start", model.Time(c.Msg.Start).Time().String()).
+		SetTag("end", model.Time(c.Msg.End).Time().String()).
+		SetTag("selector", c.Msg.LabelSelector).
+		SetTag("step", c.Msg.Step).
+		SetTag("by", c.Msg.GroupBy).
+		SetTag("profile_types", c.Msg.ProfileTypeIDs)
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	profileTypes := make([]string, len(c.Msg.ProfileTypeIDs))
+	for i, id := range c.Msg.ProfileTypeIDs {
+		pt, err := phlaremodel.ParseProfileTypeSelector(id)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		// The profile type ID may have the optional delta suffix.
+		profileTypes[i] = phlaremodel.SelectorFromProfileType(pt).Value
+	}
+	resp := newProfileTypesSeriesResponse(c.Msg.ProfileTypeIDs)
+	empty, err := validation.SanitizeTimeRange(q.limits, tenantIDs, &c.Msg.Start, &c.Msg.End)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if empty || len(profileTypes) == 0 {
+		return connect.NewResponse(resp), nil
+	}
+
+	matchers, err := parser.ParseMetricSelector(c.Msg.LabelSelector)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	quoted := make([]string, len(profileTypes))
+	for i, id := range profileTypes {
+		quoted[i] = regexp.QuoteMeta(id)
+	}
+	matchers = append(matchers, &labels.Matcher{
+		Type:  labels.MatchRegexp,
+		Name:  phlaremodel.LabelNameProfileType,
+		Value: strings.Join(quoted, "|"),
+	})
+	groupBy := c.Msg.GroupBy
+	if !slices.Contains(groupBy, phlaremodel.LabelNameProfileType) {
+		groupBy = append([]string{phlaremodel.LabelNameProfileType}, groupBy...)
+	}
+
+	report, err := q.querySingle(ctx, &queryv1.QueryRequest{
+		StartTime:     c.Msg.Start,
+		EndTime:       c.Msg.End,
+		LabelSelector: matchersToLabelSelector(matchers),
+		Query: []*queryv1.Query{{
+			QueryType: queryv1.QueryType_QUERY_TIME_SERIES,
+			TimeSeries: &queryv1.TimeSeriesQuery{
+				Step:    c.Msg.GetStep(),
+				GroupBy: groupBy,
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return connect.NewResponse(resp), nil
+	}
+	splitSeriesByProfileType(resp, profileTypes, report.TimeSeries.TimeSeries,
+		!slices.Contains(c.Msg.GroupBy, phlaremodel.LabelNameProfileType))
+	for _, r := range resp.Results {
+		r.Series = phlaremodel.TopSeries(r.Series, int(c.Msg.GetLimit()))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func newProfileTypesSeriesResponse(profileTypeIDs []string) *querierv1.SelectProfileTypesSeriesResponse {
+	resp := &querierv1.SelectProfileTypesSeriesResponse{
+		Results: make([]*querierv1.ProfileTypeSeries, len(profileTypeIDs)),
+	}
+	for i, id := range profileTypeIDs {
+		resp.Results[i] = &querierv1.ProfileTypeSeries{ProfileTypeID: id}
+	}
+	return resp
+}
+
+// splitSeriesByProfileType distributes the series among the results by
+// the profile type label; profileTypes are aligned with the results.
+// The label is removed from the series, unless it was requested.
+func splitSeriesByProfileType(
+	resp *querierv1.SelectProfileTypesSeriesResponse,
+	profileTypes []string,
+	series []*typesv1.Series,
+	removeLabel bool,
+) {
+	for _, s := range series {
+		profileType := phlaremodel.Labels(s.Labels).Get(phlaremodel.LabelNameProfileType)
+		if removeLabel {
+			s.Labels = slices.DeleteFunc(s.Labels, func(l *typesv1.LabelPair) bool {
+				return l.Name == phlaremodel.LabelNameProfileType
+			})
+		}
+		// The same profile type may be requested more than once.
+		for i, id := range profileTypes {
+			if id == profileType {
+				resp.Results[i].Series = append(resp.Results[i].Series, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package query_frontend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/testhelper"
+)
+
+func Test_splitSeriesByProfileType(t *testing.T) {
+	const (
+		cpu   = "process_cpu:cpu:nanoseconds:cpu:nanoseconds"
+		alloc = "memory:alloc_space:bytes:space:bytes"
+	)
+	series := func(profileType, service string, v float64) *typesv1.Series {
+		return &typesv1.Series{
+			Labels: phlaremodel.LabelsFromStrings(
+				phlaremodel.LabelNameProfileType, profileType,
+				phlaremodel.LabelNameServiceName, service,
+			),
+			Points: []*typesv1.Point{{Timestamp: 1000, Value: v}},
+		}
+	}
+
+	resp := newProfileTypesSeriesResponse([]string{cpu + ":delta", alloc, cpu})
+	splitSeriesByProfileType(resp, []string{cpu, alloc, cpu}, []*typesv1.Series{
+		series(cpu, "a", 1),
+		series(alloc, "a", 2),
+		series(cpu, "b", 3),
+	}, true)
+
+	cpuSeries := []*typesv1.Series{
+		{Labels: phlaremodel.LabelsFromStrings(phlaremodel.LabelNameServiceName, "a"), Points: []*typesv1.Point{{Timestamp: 1000, Value: 1}}},
+		{Labels: phlaremodel.LabelsFromStrings(phlaremodel.LabelNameServiceName, "b"), Points: []*typesv1.Point{{Timestamp: 1000, Value: 3}}},
+	}
+	testhelper.EqualProto(t, cpuSeries, resp.Results[0].Series)
+	testhelper.EqualProto(t, []*typesv1.Series{
+		{Labels: phlaremodel.LabelsFromStrings(phlaremodel.LabelNameServiceName, "a"), Points: []*typesv1.Point{{Timestamp: 1000, Value: 2}}},
+	}, resp.Results[1].Series)
+	testhelper.EqualProto(t, cpuSeries, resp.Results[2].Series)
+	assert.Equal(t, []string{cpu + ":delta", alloc, cpu},
+		[]string{resp.Results[0].ProfileTypeID, resp.Results[1].ProfileTypeID, resp.Results[2].ProfileTypeID})
+}
@@ -10,6 +10,7 @@ import (
 
 	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
 	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
 	phlaremodel "github.com/grafana/pyroscope/pkg/model"
 	"github.com/grafana/pyroscope/pkg/validation"
 )
@@ -42,6 +43,11 @@ func (q *QueryFrontend) SelectSeries(
 	if err != nil {
 		return nil, err
 	}
+	var limit int64
+	if !c.Msg.IncludeOther {
+		// Series beyond the limit are needed to build the "other" series.
+		limit = c.Msg.GetLimit()
+	}
 	report, err := q.querySingle(ctx, &queryv1.QueryRequest{
 		StartTime:     c.Msg.Start,
 		EndTime:       c.Msg.End,
@@ -51,7 +57,7 @@ func (q *QueryFrontend) SelectSeries(
 			TimeSeries: &queryv1.TimeSeriesQuery{
 				Step:    c.Msg.GetStep(),
 				GroupBy: c.Msg.GetGroupBy(),
-				Limit:   c.Msg.GetLimit(),
+				Limit:   limit,
 			},
 		}},
 	})
@@ -61,6 +67,14 @@ func (q *QueryFrontend) SelectSeries(
 	if report == nil {
 		return connect.NewResponse(&querierv1.SelectSeriesResponse{}), nil
 	}
-	series := phlaremodel.TopSeries(report.TimeSeries.TimeSeries, int(c.Msg.GetLimit()))
-	return connect.NewResponse(&querierv1.SelectSeriesResponse{Series: series}), nil
+	var series []*typesv1.Series
+	if c.Msg.IncludeOther {
+		series = phlaremodel.TopSeriesWithOther(report.TimeSeries.TimeSeries, int(c.Msg.GetLimit()), c.Msg.GroupBy)
+	} else {
+		series = phlaremodel.TopSeries(report.TimeSeries.TimeSeries, int(c.Msg.GetLimit()))
+	}
+	return connect.NewResponse(&querierv1.SelectSeriesResponse{
+		Series:      series,
+		Annotations: q.queryAnnotations(ctx, tenantIDs, c.Msg.LabelSelector, c.Msg.Start, c.Msg.End),
+	}), nil
 }
@@ -0,0 +1,75 @@
+package query_frontend
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/common/model"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+func (q *QueryFrontend) SelectServiceGraph(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectServiceGraphRequest],
+) (*connect.Response[querierv1.SelectServiceGraphResponse], error) {
+	opentracing.SpanFromContext(ctx).
+		SetTag("start", model.Time(c.Msg.Start).Time().String()).
+		SetTag("end", model.Time(c.Msg.End).Time().String()).
+		SetTag("selector", c.Msg.LabelSelector).
+		SetTag("callers", len(c.Msg.Callers)).
+		SetTag("target_label", c.Msg.GetTargetLabel()).
+		SetTag("profile_type", c.Msg.ProfileTypeID)
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	for _, caller := range c.Msg.Callers {
+		if _, err = phlaremodel.NewSpanSelector(caller.SpanSelector); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	empty, err := validation.SanitizeTimeRange(q.limits, tenantIDs, &c.Msg.Start, &c.Msg.End)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if empty || len(c.Msg.Callers) == 0 {
+		return connect.NewResponse(&querierv1.SelectServiceGraphResponse{}), nil
+	}
+
+	labelSelector, err := buildLabelSelectorWithProfileType(c.Msg.LabelSelector, c.Msg.ProfileTypeID)
+	if err != nil {
+		return nil, err
+	}
+	targetLabel := c.Msg.GetTargetLabel()
+	if targetLabel == "" {
+		targetLabel = phlaremodel.LabelNameServiceName
+	}
+	report, err := q.querySingle(ctx, &queryv1.QueryRequest{
+		StartTime:     c.Msg.Start,
+		EndTime:       c.Msg.End,
+		LabelSelector: labelSelector,
+		Query: []*queryv1.Query{{
+			QueryType: queryv1.QueryType_QUERY_SERVICE_GRAPH,
+			ServiceGraph: &queryv1.ServiceGraphQuery{
+				Callers:     c.Msg.Callers,
+				TargetLabel: targetLabel,
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return connect.NewResponse(&querierv1.SelectServiceGraphResponse{}), nil
+	}
+	return connect.NewResponse(&querierv1.SelectServiceGraphResponse{
+		Edges: report.ServiceGraph.Edges,
+	}), nil
+}
@@ -0,0 +1,54 @@
+package query_frontend
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+
+	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/validation"
+)
+
+// ServiceHierarchy is derived from the metadata of the datasets,
+// without querying the blocks: a dataset holds the data of a
+// single service and lists the profile types it has.
+func (q *QueryFrontend) ServiceHierarchy(
+	ctx context.Context,
+	req *connect.Request[querierv1.ServiceHierarchyRequest],
+) (*connect.Response[querierv1.ServiceHierarchyResponse], error) {
+	tenants, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	empty, err := validation.SanitizeTimeRange(q.limits, tenants, &req.Msg.Start, &req.Msg.End)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if empty {
+		return connect.NewResponse(&querierv1.ServiceHierarchyResponse{}), nil
+	}
+
+	md, err := q.metadataQueryClient.QueryMetadata(ctx, &metastorev1.QueryMetadataRequest{
+		TenantId:       tenants,
+		StartTime:      req.Msg.Start,
+		EndTime:        req.Msg.End,
+		Query:          "{}",
+		AllowStaleRead: q.allowStaleRead(tenants),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h := phlaremodel.NewServiceHierarchy()
+	for _, b := range md.Blocks {
+		for _, d := range b.Datasets {
+			h.Add(d.Name, d.ProfileTypes, d.MinTime, d.MaxTime)
+		}
+	}
+	return connect.NewResponse(&querierv1.ServiceHierarchyResponse{
+		Namespaces: h.Namespaces(),
+	}), nil
+}
@@ -0,0 +1,160 @@
+package query_frontend
+
+import (
+	"context"
+	_ "embed" // Used to embed html template
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/tracing"
+
+	queryv1 "github.com/grafana/pyroscope/api/gen/proto/go/query/v1"
+	"github.com/grafana/pyroscope/pkg/frontend"
+	"github.com/grafana/pyroscope/pkg/util"
+)
+
+// slowQueryLogSize is the number of the most recent slow
+// queries that are kept in memory for the admin page.
+const slowQueryLogSize = 100
+
+//go:embed slow_queries.gohtml
+var slowQueriesPageHTML string
+var slowQueriesTemplate = template.Must(template.New("webpage").Parse(slowQueriesPageHTML))
+
+type slowQueriesPageContents struct {
+	Now     time.Time   `json:"now"`
+	Queries []slowQuery `json:"queries"`
+}
+
+type slowQuery struct {
+	Time          time.Time     `json:"time"`
+	Tenants       []string      `json:"tenants"`
+	LabelSelector string        `json:"label_selector"`
+	QueryTypes    []string      `json:"query_types"`
+	StartTime     time.Time     `json:"start_time"`
+	EndTime       time.Time     `json:"end_time"`
+	Duration      time.Duration `json:"duration"`
+	Stats         queryStats    `json:"stats"`
+	TraceID       string        `json:"trace_id,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// queryStats describes the data a query has been executed on.
+// The number of bytes is the total size of the datasets queried.
+type queryStats struct {
+	Blocks   int    `json:"blocks"`
+	Datasets int    `json:"datasets"`
+	Bytes    uint64 `json:"bytes"`
+}
+
+// slowQueryLog logs queries that exceed the per-tenant duration or
+// bytes thresholds, and keeps the most recent of them in memory.
+type slowQueryLog struct {
+	logger log.Logger
+	limits frontend.Limits
+
+	mu      sync.Mutex
+	queries []slowQuery
+	next    int
+}
+
+func newSlowQueryLog(logger log.Logger, limits frontend.Limits) *slowQueryLog {
+	return &slowQueryLog{
+		logger:  logger,
+		limits:  limits,
+		queries: make([]slowQuery, 0, slowQueryLogSize),
+	}
+}
+
+func (l *slowQueryLog) observe(
+	ctx context.Context,
+	req *queryv1.QueryRequest,
+	stats queryStats,
+	duration time.Duration,
+	err error,
+) {
+	tenants, tenantErr := tenant.TenantIDs(ctx)
+	if tenantErr != nil || !l.isSlow(tenants, duration, stats.Bytes) {
+		return
+	}
+	q := slowQuery{
+		Time:          time.Now(),
+		Tenants:       tenants,
+		LabelSelector: req.LabelSelector,
+		QueryTypes:    make([]string, 0, len(req.Query)),
+		StartTime:     time.UnixMilli(req.StartTime),
+		EndTime:       time.UnixMilli(req.EndTime),
+		Duration:      duration,
+		Stats:         stats,
+	}
+	for _, x := range req.Query {
+		q.QueryTypes = append(q.QueryTypes, x.QueryType.String())
+	}
+	q.TraceID, _ = tracing.ExtractTraceID(ctx)
+	if err != nil {
+		q.Error = err.Error()
+	}
+	level.Info(l.logger).Log(
+		"msg", "slow query",
+		"tenant", strings.Join(q.Tenants, "|"),
+		"selector", q.LabelSelector,
+		"query_types", strings.Join(q.QueryTypes, ","),
+		"start", q.StartTime,
+		"end", q.EndTime,
+		"range", q.EndTime.Sub(q.StartTime),
+		"duration", q.Duration,
+		"blocks", q.Stats.Blocks,
+		"datasets", q.Stats.Datasets,
+		"bytes", q.Stats.Bytes,
+		"trace_id", q.TraceID,
+		"err", q.Error,
+	)
+	l.mu.Lock()
+	if len(l.queries) < slowQueryLogSize {
+		l.queries = append(l.queries, q)
+	} else {
+		l.queries[l.next] = q
+	}
+	l.next = (l.next + 1) % slowQueryLogSize
+	l.mu.Unlock()
+}
+
+// isSlow reports whether any of the tenant thresholds is exceeded.
+func (l *slowQueryLog) isSlow(tenants []string, duration time.Duration, bytes uint64) bool {
+	for _, t := range tenants {
+		o := l.limits.ReadPathOverrides(t)
+		if o.SlowQueryLogDuration > 0 && duration >= o.SlowQueryLogDuration {
+			return true
+		}
+		if o.SlowQueryLogBytes > 0 && bytes >= o.SlowQueryLogBytes {
+			return true
+		}
+	}
+	return false
+}
+
+// recent returns the recent slow queries, most recent first.
+func (l *slowQueryLog) recent() []slowQuery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	queries := make([]slowQuery, 0, len(l.queries))
+	for i := 1; i <= len(l.queries); i++ {
+		queries = append(queries, l.queries[(l.next-i+len(l.queries))%len(l.queries)])
+	}
+	return queries
+}
+
+// SlowQueriesHandler lists the recent queries that exceeded
+// the slow query log thresholds.
+func (q *QueryFrontend) SlowQueriesHandler(w http.ResponseWriter, req *http.Request) {
+	util.RenderHTTPResponse(w, slowQueriesPageContents{
+		Now:     time.Now(),
+		Queries: q.slowQueries.recent(),
+	}, slowQueriesTemplate, req)
+}
@@ -64,6 +64,9 @@ func (r *Router) SelectMergeStacktraces(
 	// return it in the format requested by the client.
 	f := c.Msg.Format
 	c.Msg.Format = querierv1.ProfileFormat_PROFILE_FORMAT_TREE
+	// The level of detail is applied to the merged tree.
+	path, maxDepth := c.Msg.SubtreePath, c.Msg.GetMaxDepth()
+	c.Msg.SubtreePath, c.Msg.MaxDepth = nil, nil
 	resp, err := Query[querierv1.SelectMergeStacktracesRequest, querierv1.SelectMergeStacktracesResponse](ctx, r, c,
 		func(a, b *querierv1.SelectMergeStacktracesResponse) (*querierv1.SelectMergeStacktracesResponse, error) {
 			m := phlaremodel.NewTreeMerger()
@@ -74,15 +77,29 @@ func (r *Router) SelectMergeStacktraces(
 				return nil, err
 			}
 			tree := m.Tree().Bytes(c.Msg.GetMaxNodes())
-			return &querierv1.SelectMergeStacktracesResponse{Tree: tree}, nil
+			return &querierv1.SelectMergeStacktracesResponse{
+				Tree:        tree,
+				Annotations: append(a.Annotations, b.Annotations...),
+			}, nil
 		},
 	)
-	if err == nil && f != c.Msg.Format {
-		resp.Msg.Flamegraph = phlaremodel.NewFlameGraph(
-			phlaremodel.MustUnmarshalTree(resp.Msg.Tree),
-			c.Msg.GetMaxNodes())
+	if err != nil || (len(path) == 0 && maxDepth <= 0 && f == c.Msg.Format) {
+		return resp, err
 	}
-	return resp, err
+	tree := phlaremodel.MustUnmarshalTree(resp.Msg.Tree)
+	tree.Subtree(path...)
+	if maxDepth > 0 {
+		tree.TruncateDepth(len(path) + int(maxDepth))
+	}
+	if f != c.Msg.Format {
+		resp.Msg.Flamegraph = phlaremodel.NewFlameGraph(tree, c.Msg.GetMaxNodes())
+		if c.Msg.ClassifyFrames {
+			phlaremodel.ClassifyFlameGraphFrames(resp.Msg.Flamegraph)
+		}
+	} else {
+		resp.Msg.Tree = tree.Bytes(c.Msg.GetMaxNodes())
+	}
+	return resp, nil
 }
 
 func (r *Router) SelectMergeSpanProfile(
@@ -135,18 +152,56 @@ func (r *Router) SelectSeries(
 	ctx context.Context,
 	c *connect.Request[querierv1.SelectSeriesRequest],
 ) (*connect.Response[querierv1.SelectSeriesResponse], error) {
-	var limit int
-	if limit = int(c.Msg.GetLimit()); limit > 0 {
-		// Limit must be applied after merging.
-		c.Msg.Limit = nil
-	}
-	return Query[querierv1.SelectSeriesRequest, querierv1.SelectSeriesResponse](ctx, r, c,
+	// Limit must be applied after merging: the request is routed
+	// to a single read path, or split between both of them.
+	limit, includeOther := int(c.Msg.GetLimit()), c.Msg.IncludeOther
+	c.Msg.Limit, c.Msg.IncludeOther = nil, false
+	resp, err := Query[querierv1.SelectSeriesRequest, querierv1.SelectSeriesResponse](ctx, r, c,
 		func(a, b *querierv1.SelectSeriesResponse) (*querierv1.SelectSeriesResponse, error) {
 			m := phlaremodel.NewTimeSeriesMerger(true)
 			m.MergeTimeSeries(a.Series)
 			m.MergeTimeSeries(b.Series)
-			return &querierv1.SelectSeriesResponse{Series: m.Top(limit)}, nil
+			return &querierv1.SelectSeriesResponse{
+				Series:      m.Top(0),
+				Annotations: append(a.Annotations, b.Annotations...),
+			}, nil
 		})
+	if err != nil || limit <= 0 {
+		return resp, err
+	}
+	if includeOther {
+		resp.Msg.Series = phlaremodel.TopSeriesWithOther(resp.Msg.Series, limit, c.Msg.GroupBy)
+	} else {
+		resp.Msg.Series = phlaremodel.TopSeries(resp.Msg.Series, limit)
+	}
+	return resp, nil
+}
+
+func (r *Router) SelectProfileTypesSeries(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectProfileTypesSeriesRequest],
+) (*connect.Response[querierv1.SelectProfileTypesSeriesResponse], error) {
+	// Limit must be applied after merging, as in SelectSeries.
+	limit := int(c.Msg.GetLimit())
+	c.Msg.Limit = nil
+	resp, err := Query[querierv1.SelectProfileTypesSeriesRequest, querierv1.SelectProfileTypesSeriesResponse](ctx, r, c,
+		func(a, b *querierv1.SelectProfileTypesSeriesResponse) (*querierv1.SelectProfileTypesSeriesResponse, error) {
+			// Both responses have results in the order of the request.
+			for i, x := range a.Results {
+				m := phlaremodel.NewTimeSeriesMerger(true)
+				m.MergeTimeSeries(x.Series)
+				m.MergeTimeSeries(b.Results[i].Series)
+				x.Series = m.Top(0)
+			}
+			return a, nil
+		})
+	if err != nil || limit <= 0 {
+		return resp, err
+	}
+	for _, x := range resp.Msg.Results {
+		x.Series = phlaremodel.TopSeries(x.Series, limit)
+	}
+	return resp, nil
 }
 
 func (r *Router) Diff(
@@ -184,6 +239,81 @@ func (r *Router) Diff(
 	return connect.NewResponse(&querierv1.DiffResponse{Flamegraph: diff}), nil
 }
 
+func (r *Router) Explain(
+	ctx context.Context,
+	c *connect.Request[querierv1.ExplainRequest],
+) (*connect.Response[querierv1.ExplainResponse], error) {
+	return Query[querierv1.ExplainRequest, querierv1.ExplainResponse](ctx, r, c,
+		func(a, b *querierv1.ExplainResponse) (*querierv1.ExplainResponse, error) {
+			resp := &querierv1.ExplainResponse{
+				Start:          min(a.Start, b.Start),
+				End:            max(a.End, b.End),
+				Partitions:     append(a.Partitions, b.Partitions...),
+				Blocks:         append(a.Blocks, b.Blocks...),
+				EstimatedBytes: a.EstimatedBytes + b.EstimatedBytes,
+				Caches:         append(a.Caches, b.Caches...),
+				Limits:         a.Limits,
+			}
+			if a.Start == 0 {
+				resp.Start = b.Start
+			}
+			if b.Start == 0 {
+				resp.Start = a.Start
+			}
+			// Both read paths report the same tenant limits.
+			for _, limit := range b.Limits {
+				i := slices.IndexFunc(resp.Limits, func(x *querierv1.ExplainLimit) bool {
+					return x.Name == limit.Name
+				})
+				if i < 0 {
+					resp.Limits = append(resp.Limits, limit)
+					continue
+				}
+				resp.Limits[i].Applied = resp.Limits[i].Applied || limit.Applied
+			}
+			return resp, nil
+		})
+}
+
+func (r *Router) SelectAllocationSizes(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectAllocationSizesRequest],
+) (*connect.Response[querierv1.SelectAllocationSizesResponse], error) {
+	return Query[querierv1.SelectAllocationSizesRequest, querierv1.SelectAllocationSizesResponse](ctx, r, c,
+		func(a, b *querierv1.SelectAllocationSizesResponse) (*querierv1.SelectAllocationSizesResponse, error) {
+			h := phlaremodel.NewAllocationSizeHistogram()
+			h.Merge(a.Buckets)
+			h.Merge(b.Buckets)
+			return &querierv1.SelectAllocationSizesResponse{Buckets: h.Buckets()}, nil
+		})
+}
+
+func (r *Router) SelectServiceGraph(
+	ctx context.Context,
+	c *connect.Request[querierv1.SelectServiceGraphRequest],
+) (*connect.Response[querierv1.SelectServiceGraphResponse], error) {
+	return Query[querierv1.SelectServiceGraphRequest, querierv1.SelectServiceGraphResponse](ctx, r, c,
+		func(a, b *querierv1.SelectServiceGraphResponse) (*querierv1.SelectServiceGraphResponse, error) {
+			g := phlaremodel.NewServiceGraph()
+			g.Merge(a.Edges)
+			g.Merge(b.Edges)
+			return &querierv1.SelectServiceGraphResponse{Edges: g.Edges()}, nil
+		})
+}
+
+func (r *Router) ServiceHierarchy(
+	ctx context.Context,
+	c *connect.Request[querierv1.ServiceHierarchyRequest],
+) (*connect.Response[querierv1.ServiceHierarchyResponse], error) {
+	return Query[querierv1.ServiceHierarchyRequest, querierv1.ServiceHierarchyResponse](ctx, r, c,
+		func(a, b *querierv1.ServiceHierarchyResponse) (*querierv1.ServiceHierarchyResponse, error) {
+			h := phlaremodel.NewServiceHierarchy()
+			h.Merge(a.Namespaces)
+			h.Merge(b.Namespaces)
+			return &querierv1.ServiceHierarchyResponse{Namespaces: h.Namespaces()}, nil
+		})
+}
+
 // Stubs: these methods are not supposed to be implemented
 // and only needed to satisfy interfaces.
 
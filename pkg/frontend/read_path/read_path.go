@@ -8,8 +8,14 @@ import (
 )
 
 type Config struct {
-	EnableQueryBackend     bool      `yaml:"enable_query_backend" json:"enable_query_backend" doc:"hidden"`
-	EnableQueryBackendFrom time.Time `yaml:"enable_query_backend_from" json:"enable_query_backend_from" doc:"hidden"`
+	EnableQueryBackend     bool          `yaml:"enable_query_backend" json:"enable_query_backend" doc:"hidden"`
+	EnableQueryBackendFrom time.Time     `yaml:"enable_query_backend_from" json:"enable_query_backend_from" doc:"hidden"`
+	ReadAfterWriteDelay    time.Duration `yaml:"read_after_write_delay" json:"read_after_write_delay" doc:"hidden"`
+	SlowQueryLogDuration   time.Duration `yaml:"slow_query_log_duration" json:"slow_query_log_duration" doc:"hidden"`
+	SlowQueryLogBytes      uint64        `yaml:"slow_query_log_bytes" json:"slow_query_log_bytes" doc:"hidden"`
+	MetastoreStaleReads    bool          `yaml:"metastore_stale_reads" json:"metastore_stale_reads" doc:"hidden"`
+	ShadowQueryFraction    float64       `yaml:"shadow_query_fraction" json:"shadow_query_fraction" doc:"hidden"`
+	ShadowQueryTolerance   float64       `yaml:"shadow_query_tolerance" json:"shadow_query_tolerance" doc:"hidden"`
 }
 
 func (o *Config) RegisterFlags(f *flag.FlagSet) {
@@ -17,4 +23,19 @@ func (o *Config) RegisterFlags(f *flag.FlagSet) {
 		"This parameter specifies whether the new query backend is enabled.")
 	f.Var((*flagext.Time)(&o.EnableQueryBackendFrom), "enable-query-backend-from",
 		"This parameter specifies the point in time from which data is queried from the new query backend.")
+	f.DurationVar(&o.ReadAfterWriteDelay, "read-after-write-delay", 0,
+		"This parameter specifies the time it takes for the ingested data to become available in the new query backend. "+
+			"Queries that cover this period of the recent past read the most recent metadata, and wait up to this long for the data to become available. 0 to disable.")
+	f.DurationVar(&o.SlowQueryLogDuration, "slow-query-log-duration", 0,
+		"Queries to the new query backend that take longer than this are logged to the slow query log. 0 to disable.")
+	f.Uint64Var(&o.SlowQueryLogBytes, "slow-query-log-bytes", 0,
+		"Queries to the new query backend that scan more than this number of bytes are logged to the slow query log. 0 to disable.")
+	f.BoolVar(&o.MetastoreStaleReads, "metastore-stale-reads", false,
+		"This parameter specifies whether the query metadata may be resolved by a metastore follower replica, if it does not lag behind the leader too much. "+
+			"Otherwise, the metadata is resolved by the leader. Queries that read the most recent metadata are always resolved by the leader.")
+	f.Float64Var(&o.ShadowQueryFraction, "shadow-query-fraction", 0,
+		"Specifies the fraction [0:1] of queries served by the old read path that are also executed against the new query backend in the background. "+
+			"The results of the read paths are compared, and mismatches are logged and counted in metrics. The new query backend is not required to be enabled. 0 to disable.")
+	f.Float64Var(&o.ShadowQueryTolerance, "shadow-query-tolerance", 0.01,
+		"Maximum relative difference of the totals and the number of nodes of the query results at which the results of the read paths are considered matching.")
 }
@@ -10,6 +10,7 @@ import (
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
@@ -49,6 +50,7 @@ func (s *routerTestSuite) SetupTest() {
 	s.backend = new(mockquerierv1connect.MockQuerierServiceClient)
 	s.router = NewRouter(
 		s.logger,
+		s.registry,
 		s.overrides,
 		s.frontend,
 		s.backend,
@@ -264,3 +266,195 @@ func (s *routerTestSuite) Test_TimeSeries_NoLimit() {
 	s.Require().NoError(err)
 	s.Assert().Equal(expected, resp)
 }
+
+func (s *routerTestSuite) Test_TimeSeries_LimitWithOther() {
+	s.overrides.On("ReadPathOverrides", "tenant-a").Return(Config{
+		EnableQueryBackend:     true,
+		EnableQueryBackendFrom: time.Unix(5, 0),
+	})
+
+	one := int64(1)
+	req := connect.NewRequest(&querierv1.SelectSeriesRequest{
+		Start:        10,
+		End:          10000,
+		GroupBy:      []string{"foo"},
+		Limit:        &one,
+		IncludeOther: true,
+	})
+	expected := connect.NewResponse(&querierv1.SelectSeriesResponse{
+		Series: []*typesv1.Series{
+			{Labels: model.LabelsFromStrings("foo", "baz"), Points: []*typesv1.Point{{Timestamp: 1, Value: 3}}},
+			{Labels: model.LabelsFromStrings("foo", model.OtherSeriesLabelValue), Points: []*typesv1.Point{{Timestamp: 1, Value: 2}}},
+		},
+	})
+
+	// The limit is not propagated: the series beyond the
+	// limit are merged into the "other" series.
+	s.frontend.On("SelectSeries",
+		mock.Anything, connect.NewRequest(&querierv1.SelectSeriesRequest{Start: 10, End: 4999, GroupBy: []string{"foo"}})).
+		Return(connect.NewResponse(&querierv1.SelectSeriesResponse{
+			Series: []*typesv1.Series{
+				{Labels: model.LabelsFromStrings("foo", "bar"), Points: []*typesv1.Point{{Timestamp: 1, Value: 1}}},
+				{Labels: model.LabelsFromStrings("foo", "baz"), Points: []*typesv1.Point{{Timestamp: 1, Value: 2}}},
+			}}), nil).Once()
+
+	s.backend.On("SelectSeries",
+		mock.Anything, connect.NewRequest(&querierv1.SelectSeriesRequest{Start: 5000, End: 10000, GroupBy: []string{"foo"}})).
+		Return(connect.NewResponse(&querierv1.SelectSeriesResponse{
+			Series: []*typesv1.Series{
+				{Labels: model.LabelsFromStrings("foo", "bar"), Points: []*typesv1.Point{{Timestamp: 1, Value: 1}}},
+				{Labels: model.LabelsFromStrings("foo", "baz"), Points: []*typesv1.Point{{Timestamp: 1, Value: 1}}},
+			}}), nil).Once()
+
+	resp, err := s.router.SelectSeries(s.ctx, req)
+	s.Require().NoError(err)
+	s.Assert().Equal(expected, resp)
+}
+
+func (s *routerTestSuite) Test_Explain() {
+	s.overrides.On("ReadPathOverrides", "tenant-a").Return(Config{
+		EnableQueryBackend:     true,
+		EnableQueryBackendFrom: time.Unix(5, 0),
+	})
+
+	req := connect.NewRequest(&querierv1.ExplainRequest{Start: 10, End: 10000})
+	s.frontend.On("Explain", mock.Anything, mock.Anything).Return(connect.NewResponse(&querierv1.ExplainResponse{
+		Start: 10,
+		End:   4999,
+		Limits: []*querierv1.ExplainLimit{
+			{Name: "max_query_lookback", Value: "7d", Applied: true},
+			{Name: "max_query_parallelism", Value: "32"},
+		},
+	}), nil).Once()
+	s.backend.On("Explain", mock.Anything, mock.Anything).Return(connect.NewResponse(&querierv1.ExplainResponse{
+		Start:          5000,
+		End:            10000,
+		Blocks:         []*querierv1.ExplainBlock{{Id: "block-1", Size: 100}},
+		EstimatedBytes: 100,
+		Limits: []*querierv1.ExplainLimit{
+			{Name: "max_query_lookback", Value: "7d"},
+		},
+	}), nil).Once()
+
+	expected := &querierv1.ExplainResponse{
+		Start:          10,
+		End:            10000,
+		Blocks:         []*querierv1.ExplainBlock{{Id: "block-1", Size: 100}},
+		EstimatedBytes: 100,
+		Limits: []*querierv1.ExplainLimit{
+			{Name: "max_query_lookback", Value: "7d", Applied: true},
+			{Name: "max_query_parallelism", Value: "32"},
+		},
+	}
+
+	resp, err := s.router.Explain(s.ctx, req)
+	s.Require().NoError(err)
+	s.Assert().Equal(expected.String(), resp.Msg.String())
+}
+
+func (s *routerTestSuite) Test_ShadowQuery() {
+	s.overrides.On("ReadPathOverrides", "tenant-a").Return(Config{
+		ShadowQueryFraction:  1,
+		ShadowQueryTolerance: 0.1,
+	})
+
+	series := func(values ...float64) *connect.Response[querierv1.SelectSeriesResponse] {
+		points := make([]*typesv1.Point, 0, len(values))
+		for i, v := range values {
+			points = append(points, &typesv1.Point{Timestamp: int64(i), Value: v})
+		}
+		return connect.NewResponse(&querierv1.SelectSeriesResponse{
+			Series: []*typesv1.Series{{Labels: model.LabelsFromStrings("foo", "bar"), Points: points}},
+		})
+	}
+
+	// The response of the old read path is returned.
+	expected := series(10, 10)
+	s.frontend.On("SelectSeries", mock.Anything, mock.Anything).Return(expected, nil).Times(3)
+	s.backend.On("SelectSeries", mock.Anything, mock.Anything).Return(series(10, 9.5), nil).Once()
+	s.backend.On("SelectSeries", mock.Anything, mock.Anything).Return(series(10), nil).Once()
+	s.backend.On("SelectSeries", mock.Anything, mock.Anything).Return(nil, connect.NewError(connect.CodeInternal, nil)).Once()
+	for i := 0; i < 3; i++ {
+		resp, err := s.router.SelectSeries(s.ctx, connect.NewRequest(&querierv1.SelectSeriesRequest{Start: 10, End: 10000}))
+		s.Require().NoError(err)
+		s.Assert().Equal(expected, resp)
+		s.router.inflight.Wait()
+	}
+
+	const queryType = "querier.v1.SelectSeriesRequest"
+	s.Assert().Equal(float64(1), testutil.ToFloat64(s.router.metrics.shadowQueries.WithLabelValues(queryType, shadowResultMatch)))
+	s.Assert().Equal(float64(1), testutil.ToFloat64(s.router.metrics.shadowQueries.WithLabelValues(queryType, shadowResultMismatch)))
+	s.Assert().Equal(float64(1), testutil.ToFloat64(s.router.metrics.shadowQueries.WithLabelValues(queryType, shadowResultError)))
+}
+
+func (s *routerTestSuite) Test_ShadowQuery_Unsupported() {
+	s.overrides.On("ReadPathOverrides", "tenant-a").Return(Config{ShadowQueryFraction: 1})
+
+	expected := connect.NewResponse(&typesv1.LabelNamesResponse{Names: []string{"foo", "bar"}})
+	s.frontend.On("LabelNames", mock.Anything, mock.Anything).Return(expected, nil).Once()
+
+	resp, err := s.router.LabelNames(s.ctx, connect.NewRequest(&typesv1.LabelNamesRequest{}))
+	s.Require().NoError(err)
+	s.Assert().Equal(expected, resp)
+}
+
+func (s *routerTestSuite) Test_SelectMergeStacktraces_LevelOfDetail() {
+	s.overrides.On("ReadPathOverrides", "tenant-a").Return(Config{})
+
+	tree := new(model.Tree)
+	tree.InsertStack(1, "a", "b", "c", "d")
+	tree.InsertStack(2, "a", "b", "e")
+	tree.InsertStack(3, "a", "f")
+
+	// The level of detail is not propagated to the read path.
+	s.frontend.On("SelectMergeStacktraces", mock.Anything, connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+		Start:  10,
+		End:    10000,
+		Format: querierv1.ProfileFormat_PROFILE_FORMAT_TREE,
+	})).Return(connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{Tree: tree.Bytes(-1)}), nil).Once()
+
+	maxDepth := int64(1)
+	resp, err := s.router.SelectMergeStacktraces(s.ctx, connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+		Start:       10,
+		End:         10000,
+		Format:      querierv1.ProfileFormat_PROFILE_FORMAT_TREE,
+		SubtreePath: []string{"a", "b"},
+		MaxDepth:    &maxDepth,
+	}))
+	s.Require().NoError(err)
+
+	expected := new(model.Tree)
+	expected.InsertStack(1, "a", "b", "c")
+	expected.InsertStack(2, "a", "b", "e")
+	s.Assert().Equal(expected.String(), model.MustUnmarshalTree(resp.Msg.Tree).String())
+}
+
+func (s *routerTestSuite) Test_SelectMergeStacktraces_ClassifyFrames() {
+	s.overrides.On("ReadPathOverrides", "tenant-a").Return(Config{})
+
+	tree := new(model.Tree)
+	tree.InsertStack(1, "main.main", "fmt.Println", "runtime.mallocgc")
+
+	s.frontend.On("SelectMergeStacktraces", mock.Anything, connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+		Start:          10,
+		End:            10000,
+		Format:         querierv1.ProfileFormat_PROFILE_FORMAT_TREE,
+		ClassifyFrames: true,
+	})).Return(connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{Tree: tree.Bytes(-1)}), nil).Once()
+
+	resp, err := s.router.SelectMergeStacktraces(s.ctx, connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+		Start:          10,
+		End:            10000,
+		ClassifyFrames: true,
+	}))
+	s.Require().NoError(err)
+
+	fg := resp.Msg.Flamegraph
+	s.Require().Equal([]string{"total", "main.main", "fmt.Println", "runtime.mallocgc"}, fg.Names)
+	s.Assert().Equal([]querierv1.FrameClass{
+		querierv1.FrameClass_FRAME_CLASS_UNSPECIFIED,
+		querierv1.FrameClass_FRAME_CLASS_APPLICATION,
+		querierv1.FrameClass_FRAME_CLASS_STDLIB,
+		querierv1.FrameClass_FRAME_CLASS_RUNTIME,
+	}, fg.NameClasses)
+}
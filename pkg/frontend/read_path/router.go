@@ -2,12 +2,14 @@ package read_path
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"golang.org/x/sync/errgroup"
 
@@ -27,6 +29,8 @@ type Overrides interface {
 type Router struct {
 	logger    log.Logger
 	overrides Overrides
+	metrics   *metrics
+	inflight  sync.WaitGroup
 
 	frontend querierv1connect.QuerierServiceClient
 	backend  querierv1connect.QuerierServiceClient
@@ -34,6 +38,7 @@ type Router struct {
 
 func NewRouter(
 	logger log.Logger,
+	reg prometheus.Registerer,
 	overrides Overrides,
 	frontend querierv1connect.QuerierServiceClient,
 	backend querierv1connect.QuerierServiceClient,
@@ -41,6 +46,7 @@ func NewRouter(
 	return &Router{
 		logger:    logger,
 		overrides: overrides,
+		metrics:   newMetrics(reg),
 		frontend:  frontend,
 		backend:   backend,
 	}
@@ -65,6 +71,9 @@ func Query[Req, Resp any](
 	// are delegated to the callee.
 	overrides := router.overrides.ReadPathOverrides(tenantID)
 	if !overrides.EnableQueryBackend {
+		if shouldShadow[Resp](overrides) {
+			return shadowQuery[Req, Resp](ctx, router, req, overrides.ShadowQueryTolerance)
+		}
 		return query[Req, Resp](ctx, router.frontend, req)
 	}
 	// Note: the old read path includes both start and end: [start, end].
@@ -128,6 +137,16 @@ func query[Req, Resp any](
 		resp, err = svc.GetProfileStats(ctx, r)
 	case *connect.Request[querierv1.AnalyzeQueryRequest]:
 		resp, err = svc.AnalyzeQuery(ctx, r)
+	case *connect.Request[querierv1.ExplainRequest]:
+		resp, err = svc.Explain(ctx, r)
+	case *connect.Request[querierv1.SelectAllocationSizesRequest]:
+		resp, err = svc.SelectAllocationSizes(ctx, r)
+	case *connect.Request[querierv1.SelectProfileTypesSeriesRequest]:
+		resp, err = svc.SelectProfileTypesSeries(ctx, r)
+	case *connect.Request[querierv1.SelectServiceGraphRequest]:
+		resp, err = svc.SelectServiceGraph(ctx, r)
+	case *connect.Request[querierv1.ServiceHierarchyRequest]:
+		resp, err = svc.ServiceHierarchy(ctx, r)
 
 	case *connect.Request[typesv1.LabelNamesRequest]:
 		resp, err = svc.LabelNames(ctx, r)
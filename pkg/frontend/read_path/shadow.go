@@ -0,0 +1,178 @@
+package read_path
+
+import (
+	"context"
+	"math"
+	"math/rand"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+)
+
+const (
+	shadowResultMatch    = "match"
+	shadowResultMismatch = "mismatch"
+	shadowResultError    = "error"
+)
+
+type metrics struct {
+	shadowQueries *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		shadowQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_read_path_shadow_queries_total",
+			Help: "Total number of queries executed against both the old and new read paths, by query type and comparison result.",
+		}, []string{"type", "result"}),
+	}
+	if reg != nil {
+		reg.MustRegister(m.shadowQueries)
+	}
+	return m
+}
+
+// shadowQuery executes the query against both the old and new read paths:
+// the response of the old read path is returned to the client, while the
+// response of the new read path is only compared to it in the background.
+// Neither the latency nor the failure of the shadow query affect the client.
+func shadowQuery[Req, Resp any](
+	ctx context.Context,
+	router *Router,
+	req *connect.Request[Req],
+	tolerance float64,
+) (*connect.Response[Resp], error) {
+	c, ok := (any)(req.Msg).(interface{ CloneVT() *Req })
+	if !ok {
+		return query[Req, Resp](ctx, router.frontend, req)
+	}
+	shadowReq := connect.NewRequest(c.CloneVT())
+	// The primary response is summarized before it is returned:
+	// the response may be modified by the caller afterwards.
+	primary := make(chan *querySummary, 1)
+	shadowCtx, cancel := detachContext(ctx)
+	router.inflight.Add(1)
+	go func() {
+		defer router.inflight.Done()
+		defer cancel()
+		var queryType string
+		if m, ok := (any)(shadowReq.Msg).(proto.Message); ok {
+			queryType = string(proto.MessageName(m))
+		}
+		shadow, err := query[Req, Resp](shadowCtx, router.backend, shadowReq)
+		a := <-primary
+		if a == nil {
+			// The primary query failed: nothing to compare.
+			return
+		}
+		if err != nil {
+			router.metrics.shadowQueries.WithLabelValues(queryType, shadowResultError).Inc()
+			level.Warn(router.logger).Log("msg", "shadow query failed", "type", queryType, "err", err)
+			return
+		}
+		b, _ := summarize(shadow.Msg)
+		if a.matches(b, tolerance) {
+			router.metrics.shadowQueries.WithLabelValues(queryType, shadowResultMatch).Inc()
+			return
+		}
+		router.metrics.shadowQueries.WithLabelValues(queryType, shadowResultMismatch).Inc()
+		level.Warn(router.logger).Log(
+			"msg", "shadow query result mismatch",
+			"type", queryType,
+			"expected_total", a.total,
+			"expected_nodes", a.nodes,
+			"total", b.total,
+			"nodes", b.nodes,
+		)
+	}()
+
+	resp, err := query[Req, Resp](ctx, router.frontend, req)
+	if err != nil || resp == nil {
+		primary <- nil
+		return resp, err
+	}
+	summary, _ := summarize(resp.Msg)
+	primary <- &summary
+	return resp, nil
+}
+
+// shouldShadow reports whether the query is to be executed against
+// both the read paths. Only the queries with the results that can
+// be summarized are eligible.
+func shouldShadow[Resp any](config Config) bool {
+	if config.ShadowQueryFraction <= 0 || rand.Float64() >= config.ShadowQueryFraction {
+		return false
+	}
+	// A nil response of the supported type is summarized as empty.
+	_, ok := summarize((*Resp)(nil))
+	return ok
+}
+
+// querySummary is the basis for the comparison of the query results
+// of the read paths: the results are not expected to be identical,
+// e.g., because of the truncation and the data ingestion timing.
+type querySummary struct {
+	// total is the sum of the values of the result.
+	total float64
+	// nodes is the number of the result elements:
+	// tree nodes, samples, or series points.
+	nodes int64
+}
+
+func (s querySummary) matches(x querySummary, tolerance float64) bool {
+	return withinTolerance(s.total, x.total, tolerance) &&
+		withinTolerance(float64(s.nodes), float64(x.nodes), tolerance)
+}
+
+func withinTolerance(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func summarize(resp any) (s querySummary, ok bool) {
+	switch r := resp.(type) {
+	case *querierv1.SelectMergeStacktracesResponse:
+		return summarizeTree(r.GetTree())
+	case *querierv1.SelectMergeSpanProfileResponse:
+		return summarizeTree(r.GetTree())
+	case *profilev1.Profile:
+		for _, sample := range r.GetSample() {
+			for _, v := range sample.Value {
+				s.total += float64(v)
+			}
+		}
+		s.nodes = int64(len(r.GetSample()))
+		return s, true
+	case *querierv1.SelectSeriesResponse:
+		for _, series := range r.GetSeries() {
+			for _, p := range series.Points {
+				s.total += p.Value
+			}
+			s.nodes += int64(len(series.Points))
+		}
+		return s, true
+	}
+	return s, false
+}
+
+func summarizeTree(b []byte) (querySummary, bool) {
+	tree, err := phlaremodel.UnmarshalTree(b)
+	if err != nil {
+		// A malformed tree is summarized as empty.
+		return querySummary{}, true
+	}
+	return querySummary{total: float64(tree.Total()), nodes: tree.Size()}, true
+}
+
+func detachContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithoutCancel(ctx), func() {}
+	}
+	return context.WithDeadline(context.WithoutCancel(ctx), deadline)
+}
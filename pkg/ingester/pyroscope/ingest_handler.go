@@ -18,6 +18,8 @@ import (
 	"github.com/go-kit/log/level"
 
 	"github.com/grafana/pyroscope/pkg/og/agent/types"
+	"github.com/grafana/pyroscope/pkg/og/convert/etw"
+	"github.com/grafana/pyroscope/pkg/og/convert/gpu"
 	"github.com/grafana/pyroscope/pkg/og/convert/jfr"
 	"github.com/grafana/pyroscope/pkg/og/convert/pprof"
 	"github.com/grafana/pyroscope/pkg/og/convert/profile"
@@ -159,6 +161,18 @@ func (h ingestHandler) ingestInputFromRequest(r *http.Request) (*ingestion.Inges
 			RawData: b,
 		}
 
+	case format == "etw":
+		input.Format = ingestion.FormatETW
+		input.Profile = &etw.RawProfile{
+			RawData: b,
+		}
+
+	case format == "gpu":
+		input.Format = ingestion.FormatGPU
+		input.Profile = &gpu.RawProfile{
+			RawData: b,
+		}
+
 	case strings.Contains(contentType, "multipart/form-data"):
 		input.Profile = &pprof.RawProfile{
 			FormDataContentType: contentType,
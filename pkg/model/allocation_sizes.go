@@ -0,0 +1,77 @@
+package model
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+	"sync"
+
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+)
+
+// AllocationSizeHistogram distributes allocations into buckets by size.
+// Bucket upper bounds are powers of two: the first bucket holds
+// allocations of up to 1 byte, the next one of up to 2 bytes, etc.
+type AllocationSizeHistogram struct {
+	mu      sync.Mutex
+	buckets map[int64]*typesv1.AllocationSizeBucket
+}
+
+func NewAllocationSizeHistogram() *AllocationSizeHistogram {
+	return &AllocationSizeHistogram{buckets: make(map[int64]*typesv1.AllocationSizeBucket)}
+}
+
+// Add accounts allocations of a call stack. As the exact size of each
+// allocation is not known, the average one is used.
+func (h *AllocationSizeHistogram) Add(objects, bytes int64) {
+	if objects <= 0 || bytes <= 0 {
+		return
+	}
+	size := (bytes + objects - 1) / objects
+	h.add(&typesv1.AllocationSizeBucket{
+		UpperBound: allocationSizeUpperBound(size),
+		Objects:    objects,
+		Bytes:      bytes,
+	})
+}
+
+func (h *AllocationSizeHistogram) Merge(buckets []*typesv1.AllocationSizeBucket) {
+	for _, b := range buckets {
+		h.add(b)
+	}
+}
+
+func (h *AllocationSizeHistogram) add(b *typesv1.AllocationSizeBucket) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	x, ok := h.buckets[b.UpperBound]
+	if !ok {
+		h.buckets[b.UpperBound] = b.CloneVT()
+		return
+	}
+	x.Objects += b.Objects
+	x.Bytes += b.Bytes
+}
+
+// Buckets returns non-empty buckets in the ascending order of upper bounds.
+func (h *AllocationSizeHistogram) Buckets() []*typesv1.AllocationSizeBucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets := make([]*typesv1.AllocationSizeBucket, 0, len(h.buckets))
+	for _, b := range h.buckets {
+		buckets = append(buckets, b)
+	}
+	slices.SortFunc(buckets, func(a, b *typesv1.AllocationSizeBucket) int {
+		return cmp.Compare(a.UpperBound, b.UpperBound)
+	})
+	return buckets
+}
+
+// allocationSizeUpperBound returns the smallest power of two
+// that is greater than or equal to the size.
+func allocationSizeUpperBound(size int64) int64 {
+	if size <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(uint64(size-1))
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/testhelper"
+)
+
+func Test_allocationSizeUpperBound(t *testing.T) {
+	for size, expected := range map[int64]int64{
+		0:       1,
+		1:       1,
+		2:       2,
+		3:       4,
+		4:       4,
+		5:       8,
+		1000:    1024,
+		1 << 20: 1 << 20,
+	} {
+		assert.Equal(t, expected, allocationSizeUpperBound(size), size)
+	}
+}
+
+func Test_AllocationSizeHistogram(t *testing.T) {
+	h := NewAllocationSizeHistogram()
+	h.Add(10, 80)       // 8 bytes each.
+	h.Add(2, 10)        // 5 bytes on average.
+	h.Add(1, 1<<20)     // 1MiB.
+	h.Add(0, 100)       // Ignored.
+	h.Add(4, 4*(1<<20)) // 1MiB each.
+	h.Merge([]*typesv1.AllocationSizeBucket{
+		{UpperBound: 8, Objects: 1, Bytes: 7},
+		{UpperBound: 16, Objects: 1, Bytes: 16},
+	})
+
+	testhelper.EqualProto(t, []*typesv1.AllocationSizeBucket{
+		{UpperBound: 8, Objects: 13, Bytes: 97},
+		{UpperBound: 16, Objects: 1, Bytes: 16},
+		{UpperBound: 1 << 20, Objects: 5, Bytes: 5 * (1 << 20)},
+	}, h.Buckets())
+}
@@ -128,7 +128,7 @@ func ExportToFlamebearer(fg *querierv1.FlameGraph, profileType *typesv1.ProfileT
 	sampleRate := uint32(100)
 
 	switch profileType.SampleType {
-	case "inuse_objects", "alloc_objects", "goroutine", "samples":
+	case "inuse_objects", "alloc_objects", "goroutine", "samples", "launches":
 		unit = metadata.ObjectsUnits
 	case "cpu":
 		unit = metadata.SamplesUnits
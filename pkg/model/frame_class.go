@@ -0,0 +1,225 @@
+package model
+
+import (
+	"strings"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/pkg/model/wasm"
+)
+
+// frameClassifier classifies the frames of a language, identified by the
+// shape of the frame name. The classifier reports false if the frame does
+// not belong to the language.
+type frameClassifier func(name string) (querierv1.FrameClass, bool)
+
+// frameClassifiers are tried in order: the first one that recognizes the
+// frame determines the class. The more specific naming conventions go first.
+var frameClassifiers = []frameClassifier{
+	classifyWasmFrame,
+	classifyPythonFrame,
+	classifyRubyFrame,
+	classifyNodeJSFrame,
+	classifyJavaFrame,
+	classifyDotNetFrame,
+	classifyRustFrame,
+	classifyGoFrame,
+}
+
+// ClassifyFrame returns the class of the frame with the given function
+// name, as determined by the heuristics of the language of the frame.
+// Frames of unknown languages are not classified.
+func ClassifyFrame(name string) querierv1.FrameClass {
+	for _, c := range frameClassifiers {
+		if class, ok := c(name); ok {
+			return class
+		}
+	}
+	return querierv1.FrameClass_FRAME_CLASS_UNSPECIFIED
+}
+
+// ClassifyFlameGraphFrames sets the class of each of the flame graph names.
+func ClassifyFlameGraphFrames(fg *querierv1.FlameGraph) {
+	if fg == nil {
+		return
+	}
+	fg.NameClasses = make([]querierv1.FrameClass, len(fg.Names))
+	for i, name := range fg.Names {
+		fg.NameClasses[i] = ClassifyFrame(name)
+	}
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, x := range substrs {
+		if strings.Contains(s, x) {
+			return true
+		}
+	}
+	return false
+}
+
+func classifyWasmFrame(name string) (querierv1.FrameClass, bool) {
+	switch wasm.Classify(name) {
+	case wasm.FrameHost:
+		return querierv1.FrameClass_FRAME_CLASS_RUNTIME, true
+	case wasm.FrameGuest:
+		return querierv1.FrameClass_FRAME_CLASS_APPLICATION, true
+	}
+	return 0, false
+}
+
+// classifyPythonFrame handles the frames reported by py-spy and the
+// eBPF profiler: "function (path/to/file.py)" or "path/to/file.py function".
+func classifyPythonFrame(name string) (querierv1.FrameClass, bool) {
+	switch {
+	case strings.Contains(name, "<frozen "):
+		return querierv1.FrameClass_FRAME_CLASS_RUNTIME, true
+	case !containsAny(name, ".py)", ".py ", ".py:") && !strings.HasSuffix(name, ".py"):
+		return 0, false
+	case containsAny(name, "/site-packages/", "/dist-packages/"):
+		return querierv1.FrameClass_FRAME_CLASS_DEPENDENCY, true
+	case containsAny(name, "/lib/python2", "/lib/python3", "/lib64/python3"):
+		return querierv1.FrameClass_FRAME_CLASS_STDLIB, true
+	}
+	return querierv1.FrameClass_FRAME_CLASS_APPLICATION, true
+}
+
+// classifyRubyFrame handles the frames reported by rbspy:
+// "method - path/to/file.rb:line".
+func classifyRubyFrame(name string) (querierv1.FrameClass, bool) {
+	switch {
+	case strings.Contains(name, "<internal:"):
+		return querierv1.FrameClass_FRAME_CLASS_RUNTIME, true
+	case !strings.Contains(name, ".rb:") && !strings.HasSuffix(name, ".rb"):
+		return 0, false
+	case containsAny(name, "/gems/", "/bundler/"):
+		return querierv1.FrameClass_FRAME_CLASS_DEPENDENCY, true
+	case strings.Contains(name, "/lib/ruby/"):
+		return querierv1.FrameClass_FRAME_CLASS_STDLIB, true
+	}
+	return querierv1.FrameClass_FRAME_CLASS_APPLICATION, true
+}
+
+// classifyNodeJSFrame handles the frames reported by the Node.js
+// profiler: "path/to/file.js:function:line:column".
+func classifyNodeJSFrame(name string) (querierv1.FrameClass, bool) {
+	switch {
+	case !strings.Contains(name, ":"):
+		return 0, false
+	case hasAnyPrefix(name, "node:internal/", "internal/"):
+		return querierv1.FrameClass_FRAME_CLASS_RUNTIME, true
+	case strings.HasPrefix(name, "node:"):
+		return querierv1.FrameClass_FRAME_CLASS_STDLIB, true
+	case strings.Contains(name, "node_modules/"):
+		return querierv1.FrameClass_FRAME_CLASS_DEPENDENCY, true
+	case containsAny(name, ".js:", ".mjs:", ".cjs:", ".ts:"):
+		return querierv1.FrameClass_FRAME_CLASS_APPLICATION, true
+	}
+	return 0, false
+}
+
+// classifyJavaFrame handles the frames reported by async-profiler,
+// named after the class in the internal form: "java/lang/String.equals".
+func classifyJavaFrame(name string) (querierv1.FrameClass, bool) {
+	switch {
+	case hasAnyPrefix(name, "jdk/internal/", "sun/", "com/sun/", "java/lang/invoke/"):
+		return querierv1.FrameClass_FRAME_CLASS_RUNTIME, true
+	case hasAnyPrefix(name, "java/", "javax/", "jdk/"):
+		return querierv1.FrameClass_FRAME_CLASS_STDLIB, true
+	case hasAnyPrefix(name,
+		"kotlin/", "kotlinx/", "scala/",
+		"org/springframework/", "org/apache/", "org/eclipse/", "org/hibernate/",
+		"io/netty/", "io/grpc/", "io/micrometer/", "io/opentelemetry/",
+		"com/google/", "com/fasterxml/", "reactor/", "okhttp3/", "ch/qos/"):
+		return querierv1.FrameClass_FRAME_CLASS_DEPENDENCY, true
+	}
+	// Packages are separated by slashes, and the class names are
+	// capitalized, unlike the package names of Go functions.
+	i := strings.LastIndexByte(name, '/')
+	if i <= 0 || i == len(name)-1 || name[i+1] < 'A' || name[i+1] > 'Z' {
+		return 0, false
+	}
+	if strings.ContainsAny(name[:i], ".: ") || !strings.Contains(name[i:], ".") {
+		return 0, false
+	}
+	return querierv1.FrameClass_FRAME_CLASS_APPLICATION, true
+}
+
+func classifyDotNetFrame(name string) (querierv1.FrameClass, bool) {
+	switch {
+	case hasAnyPrefix(name, "System.Runtime.", "Internal.Runtime."):
+		return querierv1.FrameClass_FRAME_CLASS_RUNTIME, true
+	case hasAnyPrefix(name, "System.", "Microsoft."):
+		return querierv1.FrameClass_FRAME_CLASS_STDLIB, true
+	}
+	return 0, false
+}
+
+func classifyRustFrame(name string) (querierv1.FrameClass, bool) {
+	switch {
+	case hasAnyPrefix(name, "std::", "core::", "alloc::", "<std::", "<core::", "<alloc::"):
+		return querierv1.FrameClass_FRAME_CLASS_STDLIB, true
+	case hasAnyPrefix(name, "tokio::", "<tokio::", "futures::", "hyper::", "serde::", "tracing::"):
+		return querierv1.FrameClass_FRAME_CLASS_DEPENDENCY, true
+	}
+	return 0, false
+}
+
+// classifyGoFrame handles the names of Go functions: the import path of
+// the package, followed by the function name.
+func classifyGoFrame(name string) (querierv1.FrameClass, bool) {
+	if strings.ContainsAny(name, " :") {
+		return 0, false
+	}
+	pkg := name
+	if i := strings.LastIndexByte(pkg, '/'); i >= 0 {
+		if j := strings.IndexByte(pkg[i:], '.'); j > 0 {
+			pkg = pkg[:i+j]
+		}
+	} else if j := strings.IndexByte(pkg, '.'); j > 0 {
+		pkg = pkg[:j]
+	} else {
+		return 0, false
+	}
+	switch {
+	case pkg == "runtime" || hasAnyPrefix(pkg, "runtime/", "internal/runtime/"):
+		return querierv1.FrameClass_FRAME_CLASS_RUNTIME, true
+	case pkg == "main":
+		return querierv1.FrameClass_FRAME_CLASS_APPLICATION, true
+	case strings.Contains(pkg, "/vendor/") ||
+		hasAnyPrefix(pkg, "golang.org/x/", "google.golang.org/", "gopkg.in/", "go.opentelemetry.io/", "go.uber.org/"):
+		return querierv1.FrameClass_FRAME_CLASS_DEPENDENCY, true
+	}
+	// The standard library packages have no domain name in the path.
+	first, _, _ := strings.Cut(pkg, "/")
+	if !strings.Contains(first, ".") {
+		if strings.Contains(pkg, "/") || isGoStdPackage(first) {
+			return querierv1.FrameClass_FRAME_CLASS_STDLIB, true
+		}
+		return 0, false
+	}
+	return querierv1.FrameClass_FRAME_CLASS_APPLICATION, true
+}
+
+// isGoStdPackage reports whether the top-level standard library package
+// has the name: the single-element names are too ambiguous otherwise.
+func isGoStdPackage(name string) bool {
+	switch name {
+	case "bufio", "bytes", "cmp", "context", "errors", "expvar", "flag", "fmt",
+		"hash", "io", "iter", "log", "maps", "math", "mime", "net", "os", "path",
+		"reflect", "regexp", "slices", "sort", "strconv", "strings", "sync",
+		"syscall", "testing", "time", "unicode", "unique", "unsafe",
+		"archive", "compress", "container", "crypto", "database", "debug",
+		"embed", "encoding", "go", "html", "image", "index", "internal", "plugin", "text":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+)
+
+func Test_ClassifyFrame(t *testing.T) {
+	const (
+		unknown     = querierv1.FrameClass_FRAME_CLASS_UNSPECIFIED
+		runtime     = querierv1.FrameClass_FRAME_CLASS_RUNTIME
+		stdlib      = querierv1.FrameClass_FRAME_CLASS_STDLIB
+		dependency  = querierv1.FrameClass_FRAME_CLASS_DEPENDENCY
+		application = querierv1.FrameClass_FRAME_CLASS_APPLICATION
+	)
+	for _, tc := range []struct {
+		name string
+		want querierv1.FrameClass
+	}{
+		// Go.
+		{"runtime.mallocgc", runtime},
+		{"runtime/internal/syscall.Syscall6", runtime},
+		{"internal/runtime/maps.(*Map).getWithKey", runtime},
+		{"fmt.Println", stdlib},
+		{"net/http.(*conn).serve", stdlib},
+		{"internal/poll.(*FD).Read", stdlib},
+		{"golang.org/x/net/http2.(*Framer).ReadFrame", dependency},
+		{"google.golang.org/grpc.(*Server).Serve", dependency},
+		{"gopkg.in/yaml.v3.(*decoder).unmarshal", dependency},
+		{"github.com/acme/app/vendor/github.com/pkg/errors.New", dependency},
+		{"main.main", application},
+		{"main.main.func1", application},
+		{"github.com/grafana/pyroscope/pkg/model.NewFlameGraph", application},
+		// Java.
+		{"java/lang/String.equals", stdlib},
+		{"jdk/internal/misc/Unsafe.park", runtime},
+		{"sun/nio/ch/EPoll.wait", runtime},
+		{"org/springframework/web/servlet/DispatcherServlet.doDispatch", dependency},
+		{"com/example/App.main", application},
+		// Python.
+		{"<module> (/usr/lib/python3.11/threading.py)", stdlib},
+		{"get (/usr/local/lib/python3.11/site-packages/requests/api.py)", dependency},
+		{"compute (app/main.py)", application},
+		{"_find_and_load (<frozen importlib._bootstrap>)", runtime},
+		// Ruby.
+		{"each - /usr/lib/ruby/3.2.0/set.rb:511", stdlib},
+		{"call - /app/vendor/bundle/ruby/3.2.0/gems/rack-3.0.8/lib/rack/builder.rb:277", dependency},
+		{"work - app/models/user.rb:12", application},
+		{"<internal:kernel>:90 - loop", runtime},
+		// Node.js.
+		{"node:internal/process/task_queues:processTicksAndRejections:95:5", runtime},
+		{"node:fs:readFileSync:441:20", stdlib},
+		{"./node_modules/express/lib/router/index.js:handle:284:7", dependency},
+		{"./src/app.js:fib:10:3", application},
+		// .NET.
+		{"System.Runtime.CompilerServices.AsyncMethodBuilderCore.Start", runtime},
+		{"System.Collections.Generic.List.Add", stdlib},
+		// Rust.
+		{"std::thread::local::LocalKey<T>::with", stdlib},
+		{"tokio::runtime::park::CachedParkThread::block_on", dependency},
+		// Wasm.
+		{"wasmtime::func::Func::call", runtime},
+		{"wasm[0]::function[42]", application},
+		// Unknown.
+		{"total", unknown},
+		{"other", unknown},
+		{"__libc_start_main", unknown},
+		{"entry_SYSCALL_64_after_hwframe", unknown},
+		{"[libc.so.6]", unknown},
+		{"app::handler", unknown},
+	} {
+		assert.Equal(t, tc.want.String(), ClassifyFrame(tc.name).String(), tc.name)
+	}
+}
+
+func Test_ClassifyFlameGraphFrames(t *testing.T) {
+	tree := new(Tree)
+	tree.InsertStack(1, "main.main", "runtime.mallocgc")
+	fg := NewFlameGraph(tree, -1)
+	ClassifyFlameGraphFrames(fg)
+	assert.Equal(t, []querierv1.FrameClass{
+		querierv1.FrameClass_FRAME_CLASS_UNSPECIFIED,
+		querierv1.FrameClass_FRAME_CLASS_APPLICATION,
+		querierv1.FrameClass_FRAME_CLASS_RUNTIME,
+	}, fg.NameClasses)
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+)
+
+// FunctionTotal is the value of the samples of a function (self), and
+// of the samples of the function and its callees (total).
+type FunctionTotal struct {
+	Name  string
+	Self  int64
+	Total int64
+}
+
+// FunctionTotals returns the self and total values of the functions of the
+// tree, sorted by the self value, then by the total value, in descending
+// order. Recursive calls are accounted once in the total value.
+func (t *Tree) FunctionTotals() []FunctionTotal {
+	totals := make(map[string]*FunctionTotal)
+	seen := make(map[string]struct{})
+	t.IterateStacks(func(_ string, self int64, stack []string) {
+		clear(seen)
+		for i, fn := range stack {
+			if fn == "" {
+				// The root node of an unmarshalled tree.
+				continue
+			}
+			ft, ok := totals[fn]
+			if !ok {
+				ft = &FunctionTotal{Name: fn}
+				totals[fn] = ft
+			}
+			if i == 0 {
+				ft.Self += self
+			}
+			if _, ok = seen[fn]; !ok {
+				seen[fn] = struct{}{}
+				ft.Total += self
+			}
+		}
+	})
+	functions := make([]FunctionTotal, 0, len(totals))
+	for _, ft := range totals {
+		functions = append(functions, *ft)
+	}
+	slices.SortFunc(functions, func(a, b FunctionTotal) int {
+		if c := cmp.Compare(b.Self, a.Self); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return functions
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_FunctionTotals(t *testing.T) {
+	tree := new(Tree)
+	tree.InsertStack(3, "main", "a", "b")
+	tree.InsertStack(2, "main", "b")
+	tree.InsertStack(1, "main", "a", "a")
+
+	expected := []FunctionTotal{
+		{Name: "b", Self: 5, Total: 5},
+		{Name: "a", Self: 1, Total: 4},
+		{Name: "main", Self: 0, Total: 6},
+	}
+	assert.Equal(t, expected, tree.FunctionTotals())
+	assert.Equal(t, expected, MustUnmarshalTree(tree.Bytes(-1)).FunctionTotals())
+}
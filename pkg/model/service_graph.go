@@ -0,0 +1,69 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+)
+
+// ServiceGraph accumulates the values of service-to-service edges.
+type ServiceGraph struct {
+	mu    sync.Mutex
+	edges map[serviceGraphEdgeKey]*typesv1.ServiceGraphEdge
+}
+
+type serviceGraphEdgeKey struct {
+	source string
+	target string
+}
+
+func NewServiceGraph() *ServiceGraph {
+	return &ServiceGraph{edges: make(map[serviceGraphEdgeKey]*typesv1.ServiceGraphEdge)}
+}
+
+func (g *ServiceGraph) Add(source, target string, value int64) {
+	g.add(&typesv1.ServiceGraphEdge{Source: source, Target: target, Value: value})
+}
+
+func (g *ServiceGraph) Merge(edges []*typesv1.ServiceGraphEdge) {
+	for _, e := range edges {
+		g.add(e)
+	}
+}
+
+func (g *ServiceGraph) add(e *typesv1.ServiceGraphEdge) {
+	if e.Value == 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	k := serviceGraphEdgeKey{source: e.Source, target: e.Target}
+	x, ok := g.edges[k]
+	if !ok {
+		g.edges[k] = e.CloneVT()
+		return
+	}
+	x.Value += e.Value
+}
+
+// Edges returns edges in the descending order of the value.
+func (g *ServiceGraph) Edges() []*typesv1.ServiceGraphEdge {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	edges := make([]*typesv1.ServiceGraphEdge, 0, len(g.edges))
+	for _, e := range g.edges {
+		edges = append(edges, e)
+	}
+	slices.SortFunc(edges, func(a, b *typesv1.ServiceGraphEdge) int {
+		if c := cmp.Compare(b.Value, a.Value); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Target, b.Target)
+	})
+	return edges
+}
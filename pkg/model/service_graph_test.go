@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/testhelper"
+)
+
+func Test_ServiceGraph(t *testing.T) {
+	g := NewServiceGraph()
+	g.Add("frontend", "auth", 10)
+	g.Add("frontend", "cart", 30)
+	g.Add("checkout", "cart", 5)
+	g.Add("checkout", "auth", 0) // Ignored.
+	g.Merge([]*typesv1.ServiceGraphEdge{
+		{Source: "frontend", Target: "auth", Value: 20},
+		{Source: "checkout", Target: "auth", Value: 5},
+	})
+
+	testhelper.EqualProto(t, []*typesv1.ServiceGraphEdge{
+		{Source: "frontend", Target: "auth", Value: 30},
+		{Source: "frontend", Target: "cart", Value: 30},
+		{Source: "checkout", Target: "auth", Value: 5},
+		{Source: "checkout", Target: "cart", Value: 5},
+	}, g.Edges())
+}
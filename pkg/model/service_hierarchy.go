@@ -0,0 +1,121 @@
+package model
+
+import (
+	"slices"
+	"strings"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+)
+
+// ServiceHierarchy builds the tree of namespaces, services,
+// and profile types, along with the time bounds of their data.
+type ServiceHierarchy struct {
+	namespaces map[string]*namespaceNode
+}
+
+type namespaceNode struct {
+	*querierv1.NamespaceNode
+	services map[string]*serviceNode
+}
+
+type serviceNode struct {
+	*querierv1.ServiceNode
+	profileTypes map[string]*querierv1.ProfileTypeNode
+}
+
+func NewServiceHierarchy() *ServiceHierarchy {
+	return &ServiceHierarchy{namespaces: make(map[string]*namespaceNode)}
+}
+
+// ServiceNamespace returns the namespace of the service: the
+// prefix of the name before the first slash, if any.
+func ServiceNamespace(serviceName string) string {
+	namespace, _, ok := strings.Cut(serviceName, "/")
+	if !ok {
+		return ""
+	}
+	return namespace
+}
+
+func (h *ServiceHierarchy) Add(serviceName string, profileTypes []string, minTime, maxTime int64) {
+	namespaceName := ServiceNamespace(serviceName)
+	ns, ok := h.namespaces[namespaceName]
+	if !ok {
+		ns = &namespaceNode{
+			NamespaceNode: &querierv1.NamespaceNode{Name: namespaceName, MinTime: minTime, MaxTime: maxTime},
+			services:      make(map[string]*serviceNode),
+		}
+		h.namespaces[namespaceName] = ns
+	}
+	extendTimeBounds(&ns.MinTime, &ns.MaxTime, minTime, maxTime)
+
+	svc, ok := ns.services[serviceName]
+	if !ok {
+		svc = &serviceNode{
+			ServiceNode:  &querierv1.ServiceNode{Name: serviceName, MinTime: minTime, MaxTime: maxTime},
+			profileTypes: make(map[string]*querierv1.ProfileTypeNode),
+		}
+		ns.services[serviceName] = svc
+	}
+	extendTimeBounds(&svc.MinTime, &svc.MaxTime, minTime, maxTime)
+
+	for _, profileType := range profileTypes {
+		pt, ok := svc.profileTypes[profileType]
+		if !ok {
+			svc.profileTypes[profileType] = &querierv1.ProfileTypeNode{
+				ProfileTypeID: profileType,
+				MinTime:       minTime,
+				MaxTime:       maxTime,
+			}
+			continue
+		}
+		extendTimeBounds(&pt.MinTime, &pt.MaxTime, minTime, maxTime)
+	}
+}
+
+func (h *ServiceHierarchy) Merge(namespaces []*querierv1.NamespaceNode) {
+	for _, ns := range namespaces {
+		for _, svc := range ns.Services {
+			for _, pt := range svc.ProfileTypes {
+				h.Add(svc.Name, []string{pt.ProfileTypeID}, pt.MinTime, pt.MaxTime)
+			}
+			// Services may have no profile types, e.g. if the
+			// metadata has not been populated.
+			h.Add(svc.Name, nil, svc.MinTime, svc.MaxTime)
+		}
+	}
+}
+
+// Namespaces returns the tree; all the nodes are
+// sorted by name in the lexicographical order.
+func (h *ServiceHierarchy) Namespaces() []*querierv1.NamespaceNode {
+	namespaces := make([]*querierv1.NamespaceNode, 0, len(h.namespaces))
+	for _, name := range sortedKeys(h.namespaces) {
+		ns := h.namespaces[name]
+		ns.Services = make([]*querierv1.ServiceNode, 0, len(ns.services))
+		for _, name := range sortedKeys(ns.services) {
+			svc := ns.services[name]
+			svc.ProfileTypes = make([]*querierv1.ProfileTypeNode, 0, len(svc.profileTypes))
+			for _, name := range sortedKeys(svc.profileTypes) {
+				svc.ProfileTypes = append(svc.ProfileTypes, svc.profileTypes[name])
+			}
+			ns.Services = append(ns.Services, svc.ServiceNode)
+		}
+		namespaces = append(namespaces, ns.NamespaceNode)
+	}
+	return namespaces
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func extendTimeBounds(minTime, maxTime *int64, start, end int64) {
+	*minTime = min(*minTime, start)
+	*maxTime = max(*maxTime, end)
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/pkg/testhelper"
+)
+
+func Test_ServiceHierarchy(t *testing.T) {
+	const (
+		cpu   = "process_cpu:cpu:nanoseconds:cpu:nanoseconds"
+		alloc = "memory:alloc_space:bytes:space:bytes"
+	)
+	h := NewServiceHierarchy()
+	h.Add("prod/checkout", []string{cpu, alloc}, 10, 20)
+	h.Add("prod/checkout", []string{cpu}, 30, 40)
+	h.Add("prod/cart", []string{cpu}, 5, 15)
+	h.Add("standalone", []string{alloc}, 1, 2)
+	h.Merge([]*querierv1.NamespaceNode{{
+		Name: "dev", MinTime: 100, MaxTime: 200,
+		Services: []*querierv1.ServiceNode{{
+			Name: "dev/checkout", MinTime: 100, MaxTime: 200,
+			ProfileTypes: []*querierv1.ProfileTypeNode{{ProfileTypeID: cpu, MinTime: 100, MaxTime: 200}},
+		}},
+	}})
+
+	testhelper.EqualProto(t, []*querierv1.NamespaceNode{
+		{
+			Name: "", MinTime: 1, MaxTime: 2,
+			Services: []*querierv1.ServiceNode{{
+				Name: "standalone", MinTime: 1, MaxTime: 2,
+				ProfileTypes: []*querierv1.ProfileTypeNode{{ProfileTypeID: alloc, MinTime: 1, MaxTime: 2}},
+			}},
+		},
+		{
+			Name: "dev", MinTime: 100, MaxTime: 200,
+			Services: []*querierv1.ServiceNode{{
+				Name: "dev/checkout", MinTime: 100, MaxTime: 200,
+				ProfileTypes: []*querierv1.ProfileTypeNode{{ProfileTypeID: cpu, MinTime: 100, MaxTime: 200}},
+			}},
+		},
+		{
+			Name: "prod", MinTime: 5, MaxTime: 40,
+			Services: []*querierv1.ServiceNode{
+				{
+					Name: "prod/cart", MinTime: 5, MaxTime: 15,
+					ProfileTypes: []*querierv1.ProfileTypeNode{{ProfileTypeID: cpu, MinTime: 5, MaxTime: 15}},
+				},
+				{
+					Name: "prod/checkout", MinTime: 10, MaxTime: 40,
+					ProfileTypes: []*querierv1.ProfileTypeNode{
+						{ProfileTypeID: alloc, MinTime: 10, MaxTime: 20},
+						{ProfileTypeID: cpu, MinTime: 10, MaxTime: 40},
+					},
+				},
+			},
+		},
+	}, h.Namespaces())
+}
@@ -56,6 +56,32 @@ func TopSeries(s []*typesv1.Series, k int) []*typesv1.Series {
 	return s
 }
 
+// OtherSeriesLabelValue is the value of the group by labels
+// of the series that aggregates the series beyond the top k.
+const OtherSeriesLabelValue = "__other__"
+
+// TopSeriesWithOther returns the top k series by sum of values, like
+// TopSeries does. Unlike TopSeries, the remaining series are not dropped
+// but summed up into a single series that is appended to the result.
+// All the groupBy labels of the series are set to OtherSeriesLabelValue.
+func TopSeriesWithOther(s []*typesv1.Series, k int, groupBy []string) []*typesv1.Series {
+	s = TopSeries(s, 0)
+	if k <= 0 || len(s) <= k {
+		return s
+	}
+	other := &typesv1.Series{Labels: make([]*typesv1.LabelPair, 0, len(groupBy))}
+	for _, name := range groupBy {
+		other.Labels = append(other.Labels, &typesv1.LabelPair{Name: name, Value: OtherSeriesLabelValue})
+	}
+	sort.Sort(Labels(other.Labels))
+	for _, x := range s[k:] {
+		other.Points = append(other.Points, x.Points...)
+	}
+	m := NewTimeSeriesMerger(true)
+	other.Points = other.Points[:m.mergePoints(other.Points)]
+	return append(s[:k:k], other)
+}
+
 type TimeSeriesMerger struct {
 	mu     sync.Mutex
 	series map[uint64]*typesv1.Series
@@ -115,6 +141,10 @@ func (m *TimeSeriesMerger) Top(n int) []*typesv1.Series {
 	return TopSeries(m.mergeTimeSeries(), n)
 }
 
+func (m *TimeSeriesMerger) TopWithOther(n int, groupBy []string) []*typesv1.Series {
+	return TopSeriesWithOther(m.mergeTimeSeries(), n, groupBy)
+}
+
 func (m *TimeSeriesMerger) mergePoints(points []*typesv1.Point) int {
 	l := len(points)
 	if l < 2 {
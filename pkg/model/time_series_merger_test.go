@@ -193,3 +193,60 @@ func Test_SeriesMerger_Top(t *testing.T) {
 		})
 	}
 }
+
+func Test_SeriesMerger_TopWithOther(t *testing.T) {
+	in := []*typesv1.Series{
+		{Labels: LabelsFromStrings("foo", "a", "pod", "a"), Points: []*typesv1.Point{{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 1}}},
+		{Labels: LabelsFromStrings("foo", "b", "pod", "b"), Points: []*typesv1.Point{{Timestamp: 2, Value: 1}, {Timestamp: 3, Value: 5}}},
+		{Labels: LabelsFromStrings("foo", "c", "pod", "c"), Points: []*typesv1.Point{{Timestamp: 1, Value: 2}, {Timestamp: 3, Value: 1}}},
+		{Labels: LabelsFromStrings("foo", "d", "pod", "d"), Points: []*typesv1.Point{{Timestamp: 3, Value: 1}}},
+	}
+	clone := func(s []*typesv1.Series) []*typesv1.Series {
+		c := make([]*typesv1.Series, len(s))
+		for i, x := range s {
+			c[i] = x.CloneVT()
+		}
+		return c
+	}
+
+	for _, tc := range []struct {
+		name string
+		top  int
+		out  []*typesv1.Series
+	}{
+		{
+			name: "top < len",
+			top:  1,
+			out: []*typesv1.Series{
+				{Labels: LabelsFromStrings("foo", "b", "pod", "b"), Points: []*typesv1.Point{{Timestamp: 2, Value: 1}, {Timestamp: 3, Value: 5}}},
+				{Labels: LabelsFromStrings("foo", OtherSeriesLabelValue, "pod", OtherSeriesLabelValue), Points: []*typesv1.Point{{Timestamp: 1, Value: 3}, {Timestamp: 2, Value: 1}, {Timestamp: 3, Value: 2}}},
+			},
+		},
+		{
+			name: "top == len",
+			top:  4,
+			out: []*typesv1.Series{
+				{Labels: LabelsFromStrings("foo", "b", "pod", "b"), Points: []*typesv1.Point{{Timestamp: 2, Value: 1}, {Timestamp: 3, Value: 5}}},
+				{Labels: LabelsFromStrings("foo", "c", "pod", "c"), Points: []*typesv1.Point{{Timestamp: 1, Value: 2}, {Timestamp: 3, Value: 1}}},
+				{Labels: LabelsFromStrings("foo", "a", "pod", "a"), Points: []*typesv1.Point{{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 1}}},
+				{Labels: LabelsFromStrings("foo", "d", "pod", "d"), Points: []*typesv1.Point{{Timestamp: 3, Value: 1}}},
+			},
+		},
+		{
+			name: "k == 0",
+			top:  0,
+			out: []*typesv1.Series{
+				{Labels: LabelsFromStrings("foo", "b", "pod", "b"), Points: []*typesv1.Point{{Timestamp: 2, Value: 1}, {Timestamp: 3, Value: 5}}},
+				{Labels: LabelsFromStrings("foo", "c", "pod", "c"), Points: []*typesv1.Point{{Timestamp: 1, Value: 2}, {Timestamp: 3, Value: 1}}},
+				{Labels: LabelsFromStrings("foo", "a", "pod", "a"), Points: []*typesv1.Point{{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 1}}},
+				{Labels: LabelsFromStrings("foo", "d", "pod", "d"), Points: []*typesv1.Point{{Timestamp: 3, Value: 1}}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewTimeSeriesMerger(true)
+			m.MergeTimeSeries(clone(in))
+			testhelper.EqualProto(t, tc.out, m.TopWithOther(tc.top, []string{"pod", "foo"}))
+		})
+	}
+}
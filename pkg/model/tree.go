@@ -57,6 +57,9 @@ func (t *Tree) Total() (v int64) {
 	return v
 }
 
+// Size reports the number of nodes the tree consists of.
+func (t *Tree) Size() int64 { return t.size(nil) }
+
 func (t *Tree) InsertStack(v int64, stack ...string) {
 	if v <= 0 {
 		return
@@ -164,6 +167,63 @@ func (t *Tree) Merge(src *Tree) {
 	t.root = dstRoot.children
 }
 
+// Subtree removes the stack traces that do not start with the given
+// frames, from the root. The totals of the path nodes are set to the
+// total of the subtree. The function modifies the tree in place.
+func (t *Tree) Subtree(path ...string) {
+	if len(path) == 0 {
+		return
+	}
+	r := &node{children: t.root}
+	nodes := make([]*node, 0, len(path))
+	p := r
+	for _, name := range path {
+		n := p.find(name)
+		if n == nil {
+			t.root = nil
+			return
+		}
+		p.children = []*node{n}
+		nodes = append(nodes, n)
+		p = n
+	}
+	for _, n := range nodes[:len(nodes)-1] {
+		n.self = 0
+		n.total = p.total
+	}
+	t.root = r.children
+}
+
+// TruncateDepth removes the nodes deeper than the given number of
+// levels: their values are added to the ancestors at the depth limit,
+// so the totals of the remaining nodes do not change. The function
+// modifies the tree in place.
+func (t *Tree) TruncateDepth(depth int) {
+	if depth < 1 {
+		return
+	}
+	type entry struct {
+		n     *node
+		depth int
+	}
+	nodes := make([]entry, 0, defaultDFSSize)
+	for _, n := range t.root {
+		nodes = append(nodes, entry{n: n, depth: 1})
+	}
+	var e entry
+	for len(nodes) > 0 {
+		e, nodes = nodes[len(nodes)-1], nodes[:len(nodes)-1]
+		if e.depth == depth {
+			e.n.self = e.n.total
+			e.n.children = nil
+			continue
+		}
+		for _, c := range e.n.children {
+			nodes = append(nodes, entry{n: c, depth: e.depth + 1})
+		}
+	}
+}
+
 func (t *Tree) FormatNodeNames(fn func(string) string) {
 	nodes := make([]*node, 0, defaultDFSSize)
 	nodes = append(nodes, &node{children: t.root})
@@ -233,6 +293,16 @@ func (n *node) String() string {
 	return fmt.Sprintf("{%s: self %d total %d}", n.name, n.self, n.total)
 }
 
+func (n *node) find(name string) *node {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].name >= name
+	})
+	if i < len(n.children) && n.children[i].name == name {
+		return n.children[i]
+	}
+	return nil
+}
+
 func (n *node) insert(name string) *node {
 	i := sort.Search(len(n.children), func(i int) bool {
 		return n.children[i].name >= name
@@ -303,6 +303,58 @@ func Test_FormatNames(t *testing.T) {
 	require.Equal(t, expected.String(), x.String())
 }
 
+func Test_Tree_Subtree(t *testing.T) {
+	stacks := []stacktraces{
+		{locations: []string{"c0", "b0", "a0"}, value: 3},
+		{locations: []string{"c1", "b0", "a0"}, value: 3},
+		{locations: []string{"b0", "a0"}, value: 1},
+		{locations: []string{"d0", "b1", "a0"}, value: 2},
+		{locations: []string{"e1", "c1", "a1"}, value: 4},
+	}
+
+	x := newTree(stacks)
+	x.Subtree("a0", "b0")
+	expected := newTree([]stacktraces{
+		{locations: []string{"c0", "b0", "a0"}, value: 3},
+		{locations: []string{"c1", "b0", "a0"}, value: 3},
+		{locations: []string{"b0", "a0"}, value: 1},
+	})
+	require.Equal(t, expected.String(), x.String())
+	require.Equal(t, int64(7), x.Total())
+	require.Equal(t, int64(4), x.Size())
+
+	x = newTree(stacks)
+	x.Subtree("a0", "b2")
+	require.Equal(t, int64(0), x.Total())
+
+	x = newTree(stacks)
+	x.Subtree()
+	require.Equal(t, newTree(stacks).String(), x.String())
+}
+
+func Test_Tree_TruncateDepth(t *testing.T) {
+	x := newTree([]stacktraces{
+		{locations: []string{"c0", "b0", "a0"}, value: 3},
+		{locations: []string{"c1", "b0", "a0"}, value: 3},
+		{locations: []string{"b0", "a0"}, value: 1},
+		{locations: []string{"d0", "b1", "a0"}, value: 2},
+		{locations: []string{"a1"}, value: 4},
+	})
+	x.TruncateDepth(2)
+	expected := newTree([]stacktraces{
+		{locations: []string{"b0", "a0"}, value: 7},
+		{locations: []string{"b1", "a0"}, value: 2},
+		{locations: []string{"a1"}, value: 4},
+	})
+	require.Equal(t, expected.String(), x.String())
+
+	// The tree survives the serialization.
+	b := x.Bytes(-1)
+	y, err := UnmarshalTree(b)
+	require.NoError(t, err)
+	require.Equal(t, expected.String(), y.String())
+}
+
 func emptyTree() *Tree {
 	return &Tree{}
 }
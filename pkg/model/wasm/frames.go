@@ -0,0 +1,106 @@
+// Package wasm classifies the stack frames of the profiles collected
+// from the WebAssembly runtimes embedded in native processes.
+package wasm
+
+import (
+	"strconv"
+	"strings"
+)
+
+type FrameKind int
+
+const (
+	// FrameNative is a frame of the native code, unrelated to Wasm.
+	FrameNative FrameKind = iota
+	// FrameHost is a frame of the Wasm runtime: the compiler, the
+	// trampolines, the host functions called by the guest, etc.
+	FrameHost
+	// FrameGuest is a frame of a function of the guest Wasm module.
+	FrameGuest
+)
+
+func (k FrameKind) String() string {
+	switch k {
+	case FrameHost:
+		return "host"
+	case FrameGuest:
+		return "guest"
+	default:
+		return "native"
+	}
+}
+
+// hostPrefixes are the prefixes of the function names of the runtimes.
+var hostPrefixes = []string{
+	"wasmtime::",
+	"wasmtime_",
+	"cranelift_",
+	"wasmer::",
+	"wasmer_",
+	"<wasmtime",
+	"<wasmer",
+}
+
+// Classify returns the kind of the frame with the given function name.
+func Classify(name string) FrameKind {
+	if _, ok := ParseGuestFrame(name); ok {
+		return FrameGuest
+	}
+	for _, p := range hostPrefixes {
+		if strings.HasPrefix(name, p) {
+			return FrameHost
+		}
+	}
+	return FrameNative
+}
+
+// GuestFrame is a frame of a guest module function, as named by the runtime.
+type GuestFrame struct {
+	// Index of the module in the runtime; -1 if not known.
+	Module int
+	// Index of the function in the module function index space.
+	Function uint32
+	// Name of the function, if the runtime has resolved it.
+	Name string
+}
+
+// ParseGuestFrame parses the name of a guest function frame. The names
+// are expected in one of the formats the runtimes use in the perf maps
+// and the symbol tables of the compiled code:
+//
+//	wasm[<module>]::function[<index>]
+//	wasm[<module>]::function[<index>]::<name>
+//	wasm-function[<index>]
+func ParseGuestFrame(name string) (GuestFrame, bool) {
+	f := GuestFrame{Module: -1}
+	rest, ok := strings.CutPrefix(name, "wasm-function[")
+	if !ok {
+		if rest, ok = strings.CutPrefix(name, "wasm["); !ok {
+			return f, false
+		}
+		var module string
+		if module, rest, ok = strings.Cut(rest, "]::function["); !ok {
+			return f, false
+		}
+		m, err := strconv.Atoi(module)
+		if err != nil || m < 0 {
+			return f, false
+		}
+		f.Module = m
+	}
+	index, rest, ok := strings.Cut(rest, "]")
+	if !ok {
+		return f, false
+	}
+	i, err := strconv.ParseUint(index, 10, 32)
+	if err != nil {
+		return f, false
+	}
+	f.Function = uint32(i)
+	if rest != "" {
+		if f.Name, ok = strings.CutPrefix(rest, "::"); !ok || f.Module < 0 {
+			return f, false
+		}
+	}
+	return f, true
+}
@@ -0,0 +1,40 @@
+package wasm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Classify(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		want FrameKind
+	}{
+		{name: "wasm[0]::function[42]", want: FrameGuest},
+		{name: "wasm[1]::function[7]::my_func", want: FrameGuest},
+		{name: "wasm-function[3]", want: FrameGuest},
+		{name: "wasmtime::func::Func::call", want: FrameHost},
+		{name: "wasmtime_runtime::traphandlers::catch_traps", want: FrameHost},
+		{name: "cranelift_codegen::machinst::compile", want: FrameHost},
+		{name: "wasmer_vm::trap::traphandlers::catch_traps", want: FrameHost},
+		{name: "<wasmtime::runtime::Store as Drop>::drop", want: FrameHost},
+		{name: "main.main", want: FrameNative},
+		{name: "wasm[x]::function[1]", want: FrameNative},
+		{name: "wasm-function[1]::name", want: FrameNative},
+		{name: "wasm[0]::function[1]x", want: FrameNative},
+		{name: "wasm[0]::function[-1]", want: FrameNative},
+	} {
+		assert.Equal(t, tc.want, Classify(tc.name), tc.name)
+	}
+}
+
+func Test_ParseGuestFrame(t *testing.T) {
+	f, ok := ParseGuestFrame("wasm[1]::function[7]::my_func")
+	assert.True(t, ok)
+	assert.Equal(t, GuestFrame{Module: 1, Function: 7, Name: "my_func"}, f)
+
+	f, ok = ParseGuestFrame("wasm-function[3]")
+	assert.True(t, ok)
+	assert.Equal(t, GuestFrame{Module: -1, Function: 3}, f)
+}
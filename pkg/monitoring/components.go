@@ -0,0 +1,84 @@
+// Package monitoring generates Grafana dashboards and Prometheus alerting
+// rules for the Pyroscope components. The resources are generated from the
+// metrics the components register, therefore the queries always refer to
+// the existing metrics.
+package monitoring
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	compactionworker "github.com/grafana/pyroscope/pkg/experiment/compactor"
+	segmentwriter "github.com/grafana/pyroscope/pkg/experiment/ingester"
+	"github.com/grafana/pyroscope/pkg/experiment/metastore"
+)
+
+// Component describes the metrics of a component.
+type Component struct {
+	// Name of the component, as in the -target option.
+	Name  string
+	Title string
+	// Prefix is prepended to the names of the metrics
+	// by the registerer the component is created with.
+	Prefix   string
+	Register func(prometheus.Registerer)
+	Rules    []AlertingRule
+}
+
+var Components = []Component{
+	{
+		Name:     "metastore",
+		Title:    "Metastore",
+		Prefix:   metastore.MetricsPrefix,
+		Register: metastore.RegisterMetrics,
+		Rules:    metastoreRules,
+	},
+	{
+		Name:     "segment-writer",
+		Title:    "Segment writer",
+		Register: segmentwriter.RegisterMetrics,
+		Rules:    segmentWriterRules,
+	},
+	{
+		Name:     "compaction-worker",
+		Title:    "Compaction worker",
+		Prefix:   compactionworker.MetricsPrefix,
+		Register: compactionworker.RegisterMetrics,
+		Rules:    compactionWorkerRules,
+	},
+}
+
+// ComponentNames returns the names of the known components.
+func ComponentNames() []string {
+	names := make([]string, len(Components))
+	for i, c := range Components {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// LookupComponents returns the components with the given names.
+// All the components are returned, if no names are specified.
+func LookupComponents(names ...string) ([]Component, error) {
+	if len(names) == 0 {
+		return Components, nil
+	}
+	components := make([]Component, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(Components, func(c Component) bool { return c.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown component %q, expected one of %v", name, ComponentNames())
+		}
+		components = append(components, Components[i])
+	}
+	return components, nil
+}
+
+// Metrics returns the metrics registered by the component.
+func (c Component) Metrics() []Metric {
+	r := NewRecorder()
+	c.Register(r.WithPrefix(c.Prefix))
+	return r.Metrics()
+}
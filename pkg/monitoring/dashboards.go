@@ -0,0 +1,210 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Labels that are not used to group the series in the dashboard
+// panels, as their cardinality is not bound.
+var highCardinalityLabels = []string{"tenant", "shard", "service", "service_name", "profile_name"}
+
+const dashboardSelector = `cluster=~"$cluster", namespace=~"$namespace"`
+
+type dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Editable      bool       `json:"editable"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label,omitempty"`
+	Type       string      `json:"type"`
+	Query      string      `json:"query"`
+	Datasource *datasource `json:"datasource,omitempty"`
+	Multi      bool        `json:"multi,omitempty"`
+	IncludeAll bool        `json:"includeAll,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type panel struct {
+	ID          int          `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Type        string       `json:"type"`
+	Datasource  *datasource  `json:"datasource,omitempty"`
+	GridPos     gridPos      `json:"gridPos"`
+	FieldConfig *fieldConfig `json:"fieldConfig,omitempty"`
+	Targets     []target     `json:"targets,omitempty"`
+}
+
+type gridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type fieldConfig struct {
+	Defaults fieldDefaults `json:"defaults"`
+}
+
+type fieldDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+type target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+var prometheusDatasource = &datasource{Type: "prometheus", UID: "$datasource"}
+
+// GenerateDashboard generates a Grafana dashboard with a panel
+// for every metric registered by the component.
+func GenerateDashboard(c Component) ([]byte, error) {
+	d := dashboard{
+		UID:           "pyroscope-" + c.Name,
+		Title:         "Pyroscope / " + c.Title,
+		Tags:          []string{"pyroscope"},
+		Editable:      true,
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          timeRange{From: "now-6h", To: "now"},
+		Templating: templating{List: []variable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+			{
+				Name: "cluster", Type: "query", Datasource: prometheusDatasource,
+				Query: "label_values(cluster)", Multi: true, IncludeAll: true, Refresh: 2,
+			},
+			{
+				Name: "namespace", Type: "query", Datasource: prometheusDatasource,
+				Query: `label_values({cluster=~"$cluster"}, namespace)`, Multi: true, IncludeAll: true, Refresh: 2,
+			},
+		}},
+	}
+	metrics := c.Metrics()
+	for i, m := range metrics {
+		p := metricPanel(m)
+		p.ID = i + 1
+		p.GridPos = gridPos{X: (i % 2) * 12, Y: (i / 2) * 8, W: 12, H: 8}
+		for _, t := range p.Targets {
+			if err := validateExpr(t.Expr, metrics); err != nil {
+				return nil, fmt.Errorf("%s: panel %s: %w", c.Name, p.Title, err)
+			}
+		}
+		d.Panels = append(d.Panels, p)
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func metricPanel(m Metric) panel {
+	p := panel{
+		Title:       m.Name,
+		Description: m.Help,
+		Type:        "timeseries",
+		Datasource:  prometheusDatasource,
+	}
+	var groupBy []string
+	for _, l := range m.Labels {
+		if !slices.Contains(highCardinalityLabels, l) {
+			groupBy = append(groupBy, l)
+		}
+	}
+	by := strings.Join(groupBy, ", ")
+	legend := legendFormat(groupBy)
+	switch {
+	case m.Type == MetricTypeCounter:
+		p.Targets = []target{{
+			Expr:         fmt.Sprintf("sum by (%s) (rate(%s{%s}[$__rate_interval]))", by, m.Name, dashboardSelector),
+			LegendFormat: legend,
+		}}
+
+	case m.Type == MetricTypeHistogram:
+		byLe := strings.Join(append([]string{"le"}, groupBy...), ", ")
+		for _, q := range []struct{ quantile, name string }{{"0.99", "p99"}, {"0.5", "p50"}} {
+			p.Targets = append(p.Targets, target{
+				Expr: fmt.Sprintf("histogram_quantile(%s, sum by (%s) (rate(%s_bucket{%s}[$__rate_interval])))",
+					q.quantile, byLe, m.Name, dashboardSelector),
+				LegendFormat: strings.TrimSpace(q.name + " " + legend),
+			})
+		}
+
+	case m.Type == MetricTypeSummary:
+		p.Targets = []target{{
+			Expr: fmt.Sprintf("sum by (%[1]s) (rate(%[2]s_sum{%[3]s}[$__rate_interval])) / sum by (%[1]s) (rate(%[2]s_count{%[3]s}[$__rate_interval]))",
+				by, m.Name, dashboardSelector),
+			LegendFormat: legend,
+		}}
+
+	case strings.HasSuffix(m.Name, "_timestamp_seconds"):
+		// The age of the oldest timestamp is more useful than the value.
+		p.Title = strings.TrimSuffix(m.Name, "_timestamp_seconds") + " age"
+		p.Targets = []target{{
+			RefID:        "A",
+			Expr:         fmt.Sprintf("time() - min by (%s) (%s{%s})", by, m.Name, dashboardSelector),
+			LegendFormat: legend,
+		}}
+		p.FieldConfig = &fieldConfig{Defaults: fieldDefaults{Unit: "s"}}
+		return p
+
+	default:
+		p.Targets = []target{{
+			Expr:         fmt.Sprintf("sum by (%s) (%s{%s})", by, m.Name, dashboardSelector),
+			LegendFormat: legend,
+		}}
+	}
+	for i := range p.Targets {
+		p.Targets[i].RefID = string(rune('A' + i))
+	}
+	if unit := metricUnit(m.Name); unit != "" {
+		p.FieldConfig = &fieldConfig{Defaults: fieldDefaults{Unit: unit}}
+	}
+	return p
+}
+
+func metricUnit(name string) string {
+	name = strings.TrimSuffix(name, "_total")
+	switch {
+	case strings.HasSuffix(name, "_seconds"):
+		return "s"
+	case strings.HasSuffix(name, "_bytes"):
+		return "bytes"
+	}
+	return ""
+}
+
+func legendFormat(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = "{{" + l + "}}"
+	}
+	return strings.Join(parts, " ")
+}
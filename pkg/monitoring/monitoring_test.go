@@ -0,0 +1,89 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	p := r.WithPrefix("prefix_")
+	p.MustRegister(
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests", Help: "Requests."}, []string{"method"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "inflight", Help: "In-flight \"requests\"."}),
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "duration_seconds"}, []string{"method", "status"}),
+	)
+	r.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "custom_total",
+		ConstLabels: prometheus.Labels{"level": "0"},
+	}, func() float64 { return 0 }))
+	require.Error(t, p.Register(prometheus.NewCounter(prometheus.CounterOpts{Name: "requests"})))
+
+	expected := []Metric{
+		{Name: "custom_total", Type: MetricTypeCounter, Labels: []string{"level"}},
+		{Name: "prefix_duration_seconds", Type: MetricTypeHistogram, Labels: []string{"method", "status"}},
+		{Name: "prefix_inflight", Help: `In-flight "requests".`, Type: MetricTypeGauge},
+		{Name: "prefix_requests", Help: "Requests.", Type: MetricTypeCounter, Labels: []string{"method"}},
+	}
+	assert.Equal(t, expected, r.Metrics())
+}
+
+func Test_validateExpr(t *testing.T) {
+	metrics := []Metric{
+		{Name: "requests_total", Type: MetricTypeCounter, Labels: []string{"method"}},
+		{Name: "duration_seconds", Type: MetricTypeHistogram},
+	}
+	for _, expr := range []string{
+		`sum by (method) (rate(requests_total{method="GET", cluster="x"}[$__rate_interval]))`,
+		`histogram_quantile(0.99, sum by (le) (rate(duration_seconds_bucket{le="1"}[5m])))`,
+		`rate(duration_seconds_sum[5m]) / rate(duration_seconds_count[5m])`,
+	} {
+		assert.NoError(t, validateExpr(expr, metrics), expr)
+	}
+	for _, expr := range []string{
+		`rate(requests[5m])`,
+		`rate(requests_total{status="500"}[5m])`,
+		`rate(requests_total_bucket[5m])`,
+		`sum(`,
+	} {
+		assert.Error(t, validateExpr(expr, metrics), expr)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, c := range Components {
+		t.Run(c.Name, func(t *testing.T) {
+			require.NotEmpty(t, c.Metrics())
+			b, err := GenerateDashboard(c)
+			require.NoError(t, err)
+			var d dashboard
+			require.NoError(t, json.Unmarshal(b, &d))
+			assert.Len(t, d.Panels, len(c.Metrics()))
+		})
+	}
+
+	b, err := GenerateRules(Components)
+	require.NoError(t, err)
+	groups, errs := rulefmt.Parse(b)
+	require.Empty(t, errs)
+	require.Len(t, groups.Groups, len(Components))
+}
+
+func TestLookupComponents(t *testing.T) {
+	c, err := LookupComponents()
+	require.NoError(t, err)
+	assert.Equal(t, Components, c)
+
+	c, err = LookupComponents("metastore")
+	require.NoError(t, err)
+	require.Len(t, c, 1)
+	assert.Equal(t, "metastore", c[0].Name)
+
+	_, err = LookupComponents("unknown")
+	require.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package monitoring
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type MetricType string
+
+const (
+	MetricTypeCounter   MetricType = "counter"
+	MetricTypeGauge     MetricType = "gauge"
+	MetricTypeHistogram MetricType = "histogram"
+	MetricTypeSummary   MetricType = "summary"
+)
+
+// Metric describes a metric registered by a component.
+type Metric struct {
+	Name string
+	Help string
+	Type MetricType
+	// Labels include both the variable and the constant labels
+	// of the metric, in the order of declaration.
+	Labels []string
+}
+
+// Recorder is a prometheus.Registerer that records the descriptions of
+// the registered collectors, instead of collecting them. Unlike the
+// prometheus.WrapRegistererWithPrefix, a prefixed recorder preserves the
+// collectors, which is required to determine the metric type.
+type Recorder struct {
+	prefix string
+	shared *recorded
+}
+
+type recorded struct {
+	mu      sync.Mutex
+	metrics map[string]Metric
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{shared: &recorded{metrics: make(map[string]Metric)}}
+}
+
+// WithPrefix returns a recorder that prepends the prefix to the names
+// of the metrics registered with it. The metrics are recorded to the
+// parent recorder.
+func (r *Recorder) WithPrefix(prefix string) *Recorder {
+	return &Recorder{prefix: r.prefix + prefix, shared: r.shared}
+}
+
+func (r *Recorder) Register(c prometheus.Collector) error {
+	descs := make(chan *prometheus.Desc)
+	go func() {
+		c.Describe(descs)
+		close(descs)
+	}()
+	var metrics []Metric
+	var err error
+	for desc := range descs {
+		m, parseErr := parseDesc(desc.String())
+		if parseErr != nil {
+			err = parseErr
+			continue
+		}
+		m.Name = r.prefix + m.Name
+		m.Type = collectorType(c, m.Name)
+		metrics = append(metrics, m)
+	}
+	if err != nil {
+		return err
+	}
+	r.shared.mu.Lock()
+	defer r.shared.mu.Unlock()
+	for _, m := range metrics {
+		if _, ok := r.shared.metrics[m.Name]; ok {
+			return fmt.Errorf("duplicate metric %q", m.Name)
+		}
+	}
+	for _, m := range metrics {
+		r.shared.metrics[m.Name] = m
+	}
+	return nil
+}
+
+func (r *Recorder) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (r *Recorder) Unregister(prometheus.Collector) bool { return false }
+
+// Metrics returns the recorded metrics, ordered by name.
+func (r *Recorder) Metrics() []Metric {
+	r.shared.mu.Lock()
+	defer r.shared.mu.Unlock()
+	metrics := make([]Metric, 0, len(r.shared.metrics))
+	for _, m := range r.shared.metrics {
+		metrics = append(metrics, m)
+	}
+	slices.SortFunc(metrics, func(a, b Metric) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return metrics
+}
+
+// collectorType determines the type of the metric by the collector type.
+// Custom collectors do not expose the type of the metrics they collect:
+// the metric is considered to be a counter if its name follows the
+// counter naming convention, and a gauge otherwise.
+func collectorType(c prometheus.Collector, name string) MetricType {
+	switch c.(type) {
+	case *prometheus.CounterVec:
+		return MetricTypeCounter
+	case *prometheus.GaugeVec:
+		return MetricTypeGauge
+	case *prometheus.HistogramVec:
+		return MetricTypeHistogram
+	case *prometheus.SummaryVec:
+		return MetricTypeSummary
+	// Gauge is a superset of Counter, therefore it goes first.
+	case prometheus.Gauge:
+		return MetricTypeGauge
+	case prometheus.Counter:
+		return MetricTypeCounter
+	case prometheus.Histogram:
+		return MetricTypeHistogram
+	}
+	if strings.HasSuffix(name, "_total") {
+		return MetricTypeCounter
+	}
+	return MetricTypeGauge
+}
+
+// The description is only available in the string form; the format
+// is defined by the prometheus.Desc String method.
+var (
+	descRegexp = regexp.MustCompile(
+		`^Desc\{fqName: ("(?:[^"\\]|\\.)*"), help: ("(?:[^"\\]|\\.)*"), constLabels: \{(.*)\}, variableLabels: \{(.*)\}\}$`)
+	constLabelRegexp = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)="(?:[^"\\]|\\.)*"`)
+)
+
+func parseDesc(s string) (m Metric, err error) {
+	match := descRegexp.FindStringSubmatch(s)
+	if match == nil {
+		return m, fmt.Errorf("invalid metric description: %s", s)
+	}
+	if m.Name, err = strconv.Unquote(match[1]); err != nil {
+		return m, fmt.Errorf("invalid metric name: %s: %w", s, err)
+	}
+	if m.Help, err = strconv.Unquote(match[2]); err != nil {
+		return m, fmt.Errorf("invalid metric help: %s: %w", s, err)
+	}
+	if match[4] != "" {
+		for _, l := range strings.Split(match[4], ",") {
+			// Constrained labels are formatted as c(name).
+			l = strings.TrimSuffix(strings.TrimPrefix(l, "c("), ")")
+			m.Labels = append(m.Labels, l)
+		}
+	}
+	for _, l := range constLabelRegexp.FindAllStringSubmatch(match[3], -1) {
+		m.Labels = append(m.Labels, l[1])
+	}
+	return m, nil
+}
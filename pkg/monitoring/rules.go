@@ -0,0 +1,209 @@
+package monitoring
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// AlertingRule is a Prometheus alerting rule. The metrics the expression
+// refers to must be registered by the component the rule belongs to.
+type AlertingRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type ruleGroups struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name  string         `yaml:"name"`
+	Rules []AlertingRule `yaml:"rules"`
+}
+
+// targetLabels are attached to the metrics at scrape time, and
+// therefore can be referred to in the queries of any metric.
+var targetLabels = []string{"cluster", "namespace", "job", "pod", "instance"}
+
+type alert struct {
+	severity    string
+	summary     string
+	description string
+}
+
+func warning(summary, description string) alert {
+	return alert{severity: "warning", summary: summary, description: description}
+}
+
+func critical(summary, description string) alert {
+	return alert{severity: "critical", summary: summary, description: description}
+}
+
+func rule(name, expr, forDuration string, a alert) AlertingRule {
+	return AlertingRule{
+		Alert:       name,
+		Expr:        expr,
+		For:         forDuration,
+		Labels:      map[string]string{"severity": a.severity},
+		Annotations: map[string]string{"summary": a.summary, "description": a.description},
+	}
+}
+
+var metastoreRules = []AlertingRule{
+	rule("PyroscopeMetastoreNoLeader",
+		`max by (cluster, namespace) (pyroscope_metastore_raft_state{state="Leader"}) < 1`,
+		"5m", critical(
+			"The metastore has no leader.",
+			"No metastore replica in {{ $labels.cluster }}/{{ $labels.namespace }} is the raft leader: "+
+				"block metadata can not be added, and queries and compaction are stalled.",
+		)),
+	rule("PyroscopeMetastoreFrequentLeaderChanges",
+		`sum by (cluster, namespace) (increase(pyroscope_metastore_raft_leader_changes_total[1h])) > 5`,
+		"", warning(
+			"The metastore leader changes frequently.",
+			"The metastore leader in {{ $labels.cluster }}/{{ $labels.namespace }} changed {{ $value }} times in the last hour.",
+		)),
+	rule("PyroscopeMetastoreSlowProposals",
+		`histogram_quantile(0.99, sum by (cluster, namespace, le) (rate(pyroscope_metastore_raft_propose_duration_seconds_bucket[5m]))) > 1`,
+		"15m", warning(
+			"Metastore raft proposals are slow.",
+			"The 99th percentile of the metastore raft proposal latency in {{ $labels.cluster }}/{{ $labels.namespace }} is {{ $value }}s.",
+		)),
+	rule("PyroscopeMetastoreRejectedBlocks",
+		`sum by (cluster, namespace, reason) (rate(pyroscope_metastore_metastore_index_rejected_blocks_total[5m])) > 0`,
+		"15m", warning(
+			"The metastore rejects blocks.",
+			"The metastore in {{ $labels.cluster }}/{{ $labels.namespace }} rejects block metadata entries: {{ $labels.reason }}.",
+		)),
+	rule("PyroscopeCompactionLagging",
+		`time() - min by (cluster, namespace) (pyroscope_metastore_compaction_block_queue_oldest_block_timestamp_seconds) > 3600`,
+		"15m", warning(
+			"Blocks are awaiting compaction for too long.",
+			"The oldest block in the compaction queue in {{ $labels.cluster }}/{{ $labels.namespace }} was added {{ $value | humanizeDuration }} ago.",
+		)),
+}
+
+var segmentWriterRules = []AlertingRule{
+	rule("PyroscopeSegmentWriterMetadataErrors",
+		`sum by (cluster, namespace, pod) (rate(pyroscope_segment_store_meta_errors[5m])) > 0`,
+		"15m", warning(
+			"The segment writer fails to store block metadata.",
+			"The segment writer {{ $labels.pod }} in {{ $labels.cluster }}/{{ $labels.namespace }} fails to add segments to the metastore.",
+		)),
+	rule("PyroscopeSegmentWriterFlushTimeouts",
+		`sum by (cluster, namespace, pod) (rate(pyroscope_segment_ingester_wait_timeouts[5m])) > 0`,
+		"15m", warning(
+			"Segment flushes time out.",
+			"Write requests to the segment writer {{ $labels.pod }} in {{ $labels.cluster }}/{{ $labels.namespace }} time out waiting for the segment flush.",
+		)),
+	rule("PyroscopeSegmentWriterSlowFlush",
+		`histogram_quantile(0.99, sum by (cluster, namespace, le) (rate(pyroscope_segment_flush_segment_duration_seconds_bucket[5m]))) > 5`,
+		"15m", warning(
+			"Segment flushes are slow.",
+			"The 99th percentile of the segment flush duration in {{ $labels.cluster }}/{{ $labels.namespace }} is {{ $value }}s.",
+		)),
+}
+
+var compactionWorkerRules = []AlertingRule{
+	rule("PyroscopeCompactionJobsFailing",
+		`sum by (cluster, namespace) (rate(pyroscope_compaction_worker_jobs_completed_total{status="failure"}[15m]))
+  / sum by (cluster, namespace) (rate(pyroscope_compaction_worker_jobs_completed_total[15m])) > 0.1`,
+		"30m", warning(
+			"Compaction jobs fail.",
+			"{{ $value | humanizePercentage }} of the compaction jobs in {{ $labels.cluster }}/{{ $labels.namespace }} fail.",
+		)),
+	rule("PyroscopeCompactionSlow",
+		`histogram_quantile(0.99, sum by (cluster, namespace, le) (rate(pyroscope_compaction_worker_time_to_compaction_seconds_bucket[30m]))) > 21600`,
+		"30m", warning(
+			"Blocks are compacted too late.",
+			"The 99th percentile of the time to compaction in {{ $labels.cluster }}/{{ $labels.namespace }} is {{ $value | humanizeDuration }}.",
+		)),
+}
+
+// GenerateRules generates the Prometheus rules file with the alerting
+// rules of the components.
+func GenerateRules(components []Component) ([]byte, error) {
+	var groups ruleGroups
+	for _, c := range components {
+		metrics := c.Metrics()
+		for _, r := range c.Rules {
+			if err := validateExpr(r.Expr, metrics); err != nil {
+				return nil, fmt.Errorf("%s: alert %s: %w", c.Name, r.Alert, err)
+			}
+		}
+		if len(c.Rules) > 0 {
+			groups.Groups = append(groups.Groups, ruleGroup{
+				Name:  "pyroscope-" + c.Name,
+				Rules: c.Rules,
+			})
+		}
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(groups); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// validateExpr checks that the metrics and labels the expression
+// refers to are registered.
+func validateExpr(expr string, metrics []Metric) error {
+	// Grafana variables are not valid PromQL.
+	expr = strings.ReplaceAll(expr, "$__rate_interval", "5m")
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return fmt.Errorf("invalid expression %q: %w", expr, err)
+	}
+	return parser.Walk(selectorValidator(metrics), e, nil)
+}
+
+type selectorValidator []Metric
+
+func (v selectorValidator) Visit(node parser.Node, _ []parser.Node) (parser.Visitor, error) {
+	vs, ok := node.(*parser.VectorSelector)
+	if !ok {
+		return v, nil
+	}
+	m, ok := v.lookup(vs.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q", vs.Name)
+	}
+	for _, matcher := range vs.LabelMatchers {
+		if matcher.Name == labels.MetricName {
+			continue
+		}
+		if matcher.Name == "le" && m.Type == MetricTypeHistogram {
+			continue
+		}
+		if !slices.Contains(m.Labels, matcher.Name) && !slices.Contains(targetLabels, matcher.Name) {
+			return nil, fmt.Errorf("metric %q does not have label %q", m.Name, matcher.Name)
+		}
+	}
+	return v, nil
+}
+
+func (v selectorValidator) lookup(name string) (Metric, bool) {
+	for _, m := range v {
+		observer := m.Type == MetricTypeHistogram || m.Type == MetricTypeSummary
+		switch {
+		case m.Name == name:
+			return m, true
+		case m.Type == MetricTypeHistogram && name == m.Name+"_bucket":
+			return m, true
+		case observer && (name == m.Name+"_sum" || name == m.Name+"_count"):
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
@@ -0,0 +1,356 @@
+package etw
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The trace is expected in the text format of the xperf dumper action
+// (xperf -i trace.etl -a dumper), which can be produced from the traces
+// recorded with WPR or PerfView. Each line is an event: the event name,
+// followed by comma separated fields. The fields of each event type are
+// described in the header of the output; the default layouts below are
+// used if the header is missing.
+const (
+	eventSampledProfile = "SampledProfile"
+	eventStack          = "Stack"
+	eventDbgIDRSDS      = "DbgID_RSDS"
+
+	headerBegin = "BeginHeader"
+	headerEnd   = "EndHeader"
+
+	fieldTimestamp   = "TimeStamp"
+	fieldProcess     = "Process Name ( PID)"
+	fieldThreadID    = "ThreadID"
+	fieldProgramCtr  = "PrgrmCtr"
+	fieldCount       = "Count"
+	fieldFrame       = "No."
+	fieldAddress     = "Address"
+	fieldFunction    = "Image!Function"
+	fieldImageBase   = "ImageBase"
+	fieldImageSize   = "ImageSize"
+	fieldFileName    = "FileName"
+	fieldGUID        = "GUID"
+	fieldAge         = "Age"
+	fieldPDBFileName = "PDB File Name"
+)
+
+// Image load events: loaded during the trace, and loaded before
+// or after the trace (reported at the beginning and the end).
+var imageEvents = []string{"I-Start", "I-End", "I-DCStart", "I-DCEnd"}
+
+var defaultLayouts = map[string][]string{
+	eventSampledProfile: {fieldTimestamp, fieldProcess, fieldThreadID, fieldProgramCtr, "CPU", "ThreadStartImage!Function", "Image!Function", fieldCount, "SampledProfile type"},
+	eventStack:          {fieldTimestamp, fieldThreadID, fieldFrame, fieldAddress, fieldFunction},
+	eventDbgIDRSDS:      {fieldTimestamp, fieldProcess, fieldImageBase, fieldGUID, fieldAge, fieldPDBFileName},
+	"I-DCStart":         {fieldTimestamp, fieldProcess, fieldImageBase, fieldImageSize, "ImageChecksum", "TimeDateStamp", "DefaultBase", fieldFileName},
+}
+
+func init() {
+	for _, e := range imageEvents {
+		defaultLayouts[e] = defaultLayouts["I-DCStart"]
+	}
+}
+
+var reProcess = regexp.MustCompile(`^(.*?)\s*\(\s*(\d+)\s*\)$`)
+
+type trace struct {
+	samples []*sample
+	// Images by process ID.
+	images map[uint64][]*image
+}
+
+type sample struct {
+	process string
+	pid     uint64
+	count   int64
+	frames  []frame
+}
+
+// frame is a stack frame, as resolved by the tool that produced
+// the trace: the function may be missing, if the module symbols
+// were not available.
+type frame struct {
+	address  uint64
+	module   string
+	function string
+}
+
+type image struct {
+	base     uint64
+	size     uint64
+	fileName string
+	buildID  string
+}
+
+type layout map[string]int
+
+func newLayout(fields []string) layout {
+	l := make(layout, len(fields))
+	for i, f := range fields {
+		l[f] = i
+	}
+	return l
+}
+
+type row struct {
+	fields []string
+	layout layout
+}
+
+func (r row) get(name string) (string, bool) {
+	i, ok := r.layout[name]
+	if !ok || i >= len(r.fields) {
+		return "", false
+	}
+	return r.fields[i], true
+}
+
+// rest returns the field along with the ones that follow it:
+// the last field of an event may include commas.
+func (r row) rest(name string) string {
+	i, ok := r.layout[name]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.Join(r.fields[i:], ",")
+}
+
+func (r row) uint(name string) (uint64, error) {
+	s, ok := r.get(name)
+	if !ok {
+		return 0, fmt.Errorf("missing field %q", name)
+	}
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid field %q: %w", name, err)
+	}
+	return v, nil
+}
+
+func (r row) process() (string, uint64, error) {
+	s, _ := r.get(fieldProcess)
+	m := reProcess.FindStringSubmatch(s)
+	if m == nil {
+		return "", 0, fmt.Errorf("invalid process %q", s)
+	}
+	pid, err := strconv.ParseUint(m[2], 10, 64)
+	return m[1], pid, err
+}
+
+type parser struct {
+	layouts map[string]layout
+	trace   *trace
+	// Build IDs of the PDB files by process ID and image base.
+	pdbs map[[2]uint64]string
+
+	pending   *sample
+	timestamp string
+	threadID  string
+}
+
+func parseTrace(data []byte) (*trace, error) {
+	p := &parser{
+		layouts: make(map[string]layout, len(defaultLayouts)),
+		trace:   &trace{images: make(map[uint64][]*image)},
+		pdbs:    make(map[[2]uint64]string),
+	}
+	for e, fields := range defaultLayouts {
+		p.layouts[e] = newLayout(fields)
+	}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	var header bool
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "":
+			continue
+		case line == headerBegin:
+			header = true
+			continue
+		case line == headerEnd:
+			header = false
+			continue
+		}
+		fields := strings.Split(line, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if header {
+			p.layouts[fields[0]] = newLayout(fields[1:])
+			continue
+		}
+		if err := p.parseEvent(fields[0], fields[1:]); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	p.flush()
+	for pid, images := range p.trace.images {
+		for _, img := range images {
+			img.buildID = p.pdbs[[2]uint64{pid, img.base}]
+		}
+	}
+	return p.trace, nil
+}
+
+func (p *parser) parseEvent(event string, fields []string) error {
+	l, ok := p.layouts[event]
+	if !ok {
+		return nil
+	}
+	r := row{fields: fields, layout: l}
+	if event == eventStack {
+		return p.parseStack(r)
+	}
+	p.flush()
+	switch event {
+	case eventSampledProfile:
+		return p.parseSample(r)
+	case eventDbgIDRSDS:
+		return p.parseDbgID(r)
+	default:
+		for _, e := range imageEvents {
+			if event == e {
+				return p.parseImage(r)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) flush() {
+	if p.pending != nil {
+		p.trace.samples = append(p.trace.samples, p.pending)
+		p.pending = nil
+	}
+}
+
+func (p *parser) parseSample(r row) error {
+	name, pid, err := r.process()
+	if err != nil {
+		return err
+	}
+	s := &sample{process: name, pid: pid, count: 1}
+	if c, ok := r.get(fieldCount); ok {
+		if s.count, err = strconv.ParseInt(c, 10, 64); err != nil {
+			return fmt.Errorf("invalid field %q: %w", fieldCount, err)
+		}
+	}
+	pc, err := r.uint(fieldProgramCtr)
+	if err != nil {
+		return err
+	}
+	// The program counter is only used
+	// if the stack has not been collected.
+	s.frames = []frame{{address: pc}}
+	p.pending = s
+	p.timestamp, _ = r.get(fieldTimestamp)
+	p.threadID, _ = r.get(fieldThreadID)
+	return nil
+}
+
+// parseStack adds the frame to the sample it belongs to: the stack
+// events follow the sample event, with the same timestamp and thread.
+// The frames are numbered from the innermost one.
+func (p *parser) parseStack(r row) error {
+	if p.pending == nil {
+		return nil
+	}
+	ts, _ := r.get(fieldTimestamp)
+	tid, _ := r.get(fieldThreadID)
+	if ts != p.timestamp || tid != p.threadID {
+		return nil
+	}
+	n, err := r.uint(fieldFrame)
+	if err != nil {
+		return err
+	}
+	addr, err := r.uint(fieldAddress)
+	if err != nil {
+		return err
+	}
+	f := frame{address: addr}
+	f.module, f.function = splitFunction(r.rest(fieldFunction))
+	if n == 1 {
+		p.pending.frames = p.pending.frames[:0]
+	}
+	p.pending.frames = append(p.pending.frames, f)
+	return nil
+}
+
+func (p *parser) parseImage(r row) error {
+	_, pid, err := r.process()
+	if err != nil {
+		return err
+	}
+	base, err := r.uint(fieldImageBase)
+	if err != nil {
+		return err
+	}
+	size, err := r.uint(fieldImageSize)
+	if err != nil {
+		return err
+	}
+	fileName := r.rest(fieldFileName)
+	for _, img := range p.trace.images[pid] {
+		if img.base == base {
+			return nil
+		}
+	}
+	p.trace.images[pid] = append(p.trace.images[pid], &image{
+		base:     base,
+		size:     size,
+		fileName: strings.Trim(fileName, `"`),
+	})
+	return nil
+}
+
+func (p *parser) parseDbgID(r row) error {
+	_, pid, err := r.process()
+	if err != nil {
+		return err
+	}
+	base, err := r.uint(fieldImageBase)
+	if err != nil {
+		return err
+	}
+	guid, _ := r.get(fieldGUID)
+	age, err := r.uint(fieldAge)
+	if err != nil {
+		return err
+	}
+	p.pdbs[[2]uint64{pid, base}] = pdbBuildID(guid, uint32(age))
+	return nil
+}
+
+// pdbBuildID returns the identifier of the PDB file the symbol servers
+// use: the GUID of the PDB file, as hexadecimal digits without separators,
+// followed by the age of the PDB file. The PDB files are to be uploaded
+// to the symbolizer with the identifier as the build ID.
+func pdbBuildID(guid string, age uint32) string {
+	id := strings.NewReplacer("{", "", "}", "", "-", "").Replace(guid)
+	return fmt.Sprintf("%s%X", strings.ToUpper(id), age)
+}
+
+func splitFunction(s string) (module, function string) {
+	module, function, ok := strings.Cut(s, "!")
+	if !ok {
+		return "", ""
+	}
+	if module == "?" {
+		module = ""
+	}
+	switch f := strings.ToLower(function); {
+	case f == "?", f == "<unknown>", f == "unknown", strings.HasPrefix(f, "0x"):
+		function = ""
+	}
+	return module, function
+}
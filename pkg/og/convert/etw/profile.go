@@ -0,0 +1,283 @@
+package etw
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/og/agent/types"
+	"github.com/grafana/pyroscope/pkg/og/ingestion"
+	"github.com/grafana/pyroscope/pkg/og/storage"
+	"github.com/grafana/pyroscope/pkg/pprof"
+)
+
+const (
+	// The default sampling rate of the ETW profile source.
+	defaultSampleRate = 1000
+
+	labelProcessName = "process_name"
+)
+
+// RawProfile implements ingestion.RawProfile for the CPU sampling
+// traces collected with Event Tracing for Windows (ETW).
+type RawProfile struct {
+	RawData []byte
+}
+
+func (p *RawProfile) Bytes() ([]byte, error) { return p.RawData, nil }
+
+func (*RawProfile) ContentType() string { return "text/plain" }
+
+func (p *RawProfile) Parse(context.Context, storage.Putter, storage.MetricsExporter, ingestion.Metadata) error {
+	return fmt.Errorf("parsing ETW traces to tree/storage.Putter is not supported")
+}
+
+// ParseToPprof converts the samples of each process in the trace to a
+// CPU profile. The frames without symbols are kept as addresses of the
+// module mappings identified by the PDB build ID, if known, so that the
+// symbolizer can resolve them once the PDB files have been uploaded.
+func (p *RawProfile) ParseToPprof(_ context.Context, md ingestion.Metadata) (*distributormodel.PushRequest, error) {
+	t, err := parseTrace(p.RawData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ETW trace: %w", err)
+	}
+	sampleRate := md.SampleRate
+	if sampleRate == 0 || sampleRate == types.DefaultSampleRate {
+		sampleRate = defaultSampleRate
+	}
+	period := time.Second.Nanoseconds() / int64(sampleRate)
+
+	builders := make(map[string]*profileBuilder)
+	for _, s := range t.samples {
+		// The idle process only indicates idle CPU time.
+		if s.pid == 0 {
+			continue
+		}
+		b, ok := builders[s.process]
+		if !ok {
+			b = newProfileBuilder(md, period)
+			builders[s.process] = b
+		}
+		b.addSample(t, s)
+	}
+	processes := make([]string, 0, len(builders))
+	for name := range builders {
+		processes = append(processes, name)
+	}
+	sort.Strings(processes)
+
+	res := &distributormodel.PushRequest{
+		RawProfileSize: len(p.RawData),
+		RawProfileType: distributormodel.RawProfileTypeETW,
+	}
+	for _, name := range processes {
+		res.Series = append(res.Series, &distributormodel.ProfileSeries{
+			Labels: createLabels(md, name),
+			Samples: []*distributormodel.ProfileSample{{
+				Profile: pprof.RawFromProto(builders[name].profile),
+			}},
+		})
+	}
+	return res, nil
+}
+
+func createLabels(md ingestion.Metadata, process string) []*typesv1.LabelPair {
+	ls := make([]*typesv1.LabelPair, 0, len(md.Key.Labels())+5)
+	ls = append(ls, &typesv1.LabelPair{
+		Name:  labels.MetricName,
+		Value: "process_cpu",
+	}, &typesv1.LabelPair{
+		Name:  phlaremodel.LabelNameDelta,
+		Value: "false",
+	}, &typesv1.LabelPair{
+		Name:  "service_name",
+		Value: md.Key.AppName(),
+	}, &typesv1.LabelPair{
+		Name:  phlaremodel.LabelNamePyroscopeSpy,
+		Value: md.SpyName,
+	}, &typesv1.LabelPair{
+		Name:  labelProcessName,
+		Value: process,
+	})
+	for k, v := range md.Key.Labels() {
+		if !phlaremodel.IsLabelAllowedForIngestion(k) || k == labelProcessName {
+			continue
+		}
+		ls = append(ls, &typesv1.LabelPair{
+			Name:  k,
+			Value: v,
+		})
+	}
+	return ls
+}
+
+type locationKey struct {
+	mapping  uint64
+	address  uint64
+	function string
+}
+
+type profileBuilder struct {
+	profile   *profilev1.Profile
+	period    int64
+	strings   map[string]int64
+	functions map[string]uint64
+	locations map[locationKey]uint64
+	mappings  map[*image]*profilev1.Mapping
+	samples   map[string]*profilev1.Sample
+}
+
+func newProfileBuilder(md ingestion.Metadata, period int64) *profileBuilder {
+	b := &profileBuilder{
+		profile:   &profilev1.Profile{StringTable: []string{""}},
+		period:    period,
+		strings:   map[string]int64{"": 0},
+		functions: make(map[string]uint64),
+		locations: make(map[locationKey]uint64),
+		mappings:  make(map[*image]*profilev1.Mapping),
+		samples:   make(map[string]*profilev1.Sample),
+	}
+	b.profile.SampleType = []*profilev1.ValueType{
+		{Type: b.string("samples"), Unit: b.string("count")},
+		{Type: b.string("cpu"), Unit: b.string("nanoseconds")},
+	}
+	b.profile.PeriodType = &profilev1.ValueType{Type: b.string("cpu"), Unit: b.string("nanoseconds")}
+	b.profile.Period = period
+	b.profile.TimeNanos = md.StartTime.UnixNano()
+	b.profile.DurationNanos = md.EndTime.Sub(md.StartTime).Nanoseconds()
+	return b
+}
+
+func (b *profileBuilder) addSample(t *trace, s *sample) {
+	locations := make([]uint64, 0, len(s.frames))
+	var key []byte
+	for _, f := range s.frames {
+		id := b.location(t.findImage(s.pid, f.address), f)
+		locations = append(locations, id)
+		key = fmt.Appendf(key, "%d;", id)
+	}
+	if x, ok := b.samples[string(key)]; ok {
+		x.Value[0] += s.count
+		x.Value[1] += s.count * b.period
+		return
+	}
+	x := &profilev1.Sample{
+		LocationId: locations,
+		Value:      []int64{s.count, s.count * b.period},
+	}
+	b.samples[string(key)] = x
+	b.profile.Sample = append(b.profile.Sample, x)
+}
+
+func (b *profileBuilder) location(img *image, f frame) uint64 {
+	var m *profilev1.Mapping
+	if img != nil {
+		m = b.mapping(img)
+	}
+	name := f.function
+	if name == "" && (m == nil || img.buildID == "") {
+		// The frame can't be symbolized later: the module is
+		// unknown, or so is the PDB file of the module.
+		switch {
+		case img != nil:
+			name = fmt.Sprintf("%s+0x%x", moduleName(img.fileName), f.address-img.base)
+		case f.module != "":
+			name = fmt.Sprintf("%s+0x%x", f.module, f.address)
+		default:
+			name = fmt.Sprintf("0x%x", f.address)
+		}
+	}
+	k := locationKey{address: f.address, function: name}
+	if m != nil {
+		k.mapping = m.Id
+	}
+	if id, ok := b.locations[k]; ok {
+		return id
+	}
+	loc := &profilev1.Location{
+		Id:        uint64(len(b.profile.Location) + 1),
+		MappingId: k.mapping,
+		Address:   f.address,
+	}
+	if name != "" {
+		loc.Line = []*profilev1.Line{{FunctionId: b.function(name)}}
+	} else {
+		m.HasFunctions = false
+	}
+	b.profile.Location = append(b.profile.Location, loc)
+	b.locations[k] = loc.Id
+	return loc.Id
+}
+
+func (b *profileBuilder) mapping(img *image) *profilev1.Mapping {
+	if m, ok := b.mappings[img]; ok {
+		return m
+	}
+	m := &profilev1.Mapping{
+		Id:           uint64(len(b.profile.Mapping) + 1),
+		MemoryStart:  img.base,
+		MemoryLimit:  img.base + img.size,
+		Filename:     b.string(img.fileName),
+		BuildId:      b.string(img.buildID),
+		HasFunctions: true,
+	}
+	b.profile.Mapping = append(b.profile.Mapping, m)
+	b.mappings[img] = m
+	return m
+}
+
+func (b *profileBuilder) function(name string) uint64 {
+	if id, ok := b.functions[name]; ok {
+		return id
+	}
+	fn := &profilev1.Function{
+		Id:         uint64(len(b.profile.Function) + 1),
+		Name:       b.string(name),
+		SystemName: b.string(name),
+	}
+	b.profile.Function = append(b.profile.Function, fn)
+	b.functions[name] = fn.Id
+	return fn.Id
+}
+
+func (b *profileBuilder) string(s string) int64 {
+	if i, ok := b.strings[s]; ok {
+		return i
+	}
+	i := int64(len(b.profile.StringTable))
+	b.profile.StringTable = append(b.profile.StringTable, s)
+	b.strings[s] = i
+	return i
+}
+
+// findImage returns the module the address belongs to. The kernel
+// modules are reported for the system process only, and shared by
+// all the processes.
+func (t *trace) findImage(pid, addr uint64) *image {
+	for _, p := range []uint64{pid, systemPID, 0} {
+		for _, img := range t.images[p] {
+			if addr >= img.base && addr < img.base+img.size {
+				return img
+			}
+		}
+	}
+	return nil
+}
+
+const systemPID = 4
+
+func moduleName(fileName string) string {
+	for i := len(fileName) - 1; i >= 0; i-- {
+		if fileName[i] == '\\' || fileName[i] == '/' {
+			return fileName[i+1:]
+		}
+	}
+	return fileName
+}
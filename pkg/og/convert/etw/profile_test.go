@@ -0,0 +1,103 @@
+package etw
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/og/agent/types"
+	"github.com/grafana/pyroscope/pkg/og/ingestion"
+	"github.com/grafana/pyroscope/pkg/og/storage/segment"
+)
+
+// stacks returns the stacks of the profile, from the root frame,
+// with the value of the second sample type. The frames without
+// functions are shown with the build ID of the mapping.
+func stacks(p *profilev1.Profile) map[string]int64 {
+	functions := make(map[uint64]string)
+	for _, fn := range p.Function {
+		functions[fn.Id] = p.StringTable[fn.Name]
+	}
+	locations := make(map[uint64]string)
+	for _, loc := range p.Location {
+		if len(loc.Line) > 0 {
+			locations[loc.Id] = functions[loc.Line[0].FunctionId]
+			continue
+		}
+		m := p.Mapping[loc.MappingId-1]
+		locations[loc.Id] = p.StringTable[m.BuildId] + "@" + p.StringTable[m.Filename]
+	}
+	s := make(map[string]int64)
+	for _, x := range p.Sample {
+		names := make([]string, len(x.LocationId))
+		for i, id := range x.LocationId {
+			names[len(names)-1-i] = locations[id]
+		}
+		s[strings.Join(names, ";")] += x.Value[1]
+	}
+	return s
+}
+
+func Test_ParseToPprof(t *testing.T) {
+	data, err := os.ReadFile("testdata/cpu.txt")
+	require.NoError(t, err)
+	key, err := segment.ParseKey("my-service{env=test}")
+	require.NoError(t, err)
+	md := ingestion.Metadata{
+		Key:        key,
+		SpyName:    "etw",
+		SampleRate: types.DefaultSampleRate,
+		StartTime:  time.Unix(1, 0),
+		EndTime:    time.Unix(11, 0),
+	}
+
+	req, err := (&RawProfile{RawData: data}).ParseToPprof(context.Background(), md)
+	require.NoError(t, err)
+	require.Len(t, req.Series, 2)
+
+	app := req.Series[0]
+	assert.Equal(t, "app.exe", phlaremodel.Labels(app.Labels).Get(labelProcessName))
+	assert.Equal(t, "my-service", phlaremodel.Labels(app.Labels).Get("service_name"))
+	assert.Equal(t, "test", phlaremodel.Labels(app.Labels).Get("env"))
+	assert.Equal(t, "process_cpu", phlaremodel.Labels(app.Labels).Get("__name__"))
+	p := app.Samples[0].Profile.Profile
+	assert.Equal(t, int64(time.Millisecond), p.Period)
+	assert.Equal(t, int64(10*time.Second), p.DurationNanos)
+	assert.Equal(t, map[string]int64{
+		"RtlUserThreadStart;main;6F4C1A2B3C4D5E6F7A8B9C0D1E2F3A4B2@C:\\Program Files\\App\\app.exe": 2 * int64(time.Millisecond),
+		"std::map<int,int>::find;ntdll.dll+0x200;KiSwapContext":                                     int64(time.Millisecond),
+	}, stacks(p))
+	for _, m := range p.Mapping {
+		// Only the module with the unresolved frames
+		// and the known PDB file is to be symbolized.
+		assert.Equal(t, p.StringTable[m.BuildId] == "", m.HasFunctions, p.StringTable[m.Filename])
+	}
+
+	worker := req.Series[1]
+	assert.Equal(t, "worker.exe", phlaremodel.Labels(worker.Labels).Get(labelProcessName))
+	assert.Equal(t, map[string]int64{"0x401000": 3 * int64(time.Millisecond)}, stacks(worker.Samples[0].Profile.Profile))
+}
+
+func Test_ParseToPprof_Invalid(t *testing.T) {
+	key, err := segment.ParseKey("my-service")
+	require.NoError(t, err)
+	for _, data := range []string{
+		"SampledProfile, 1000, app.exe, 1, 0x1000, 0, ?!?, ?!?, 1, Unbatched",
+		"SampledProfile, 1000, app.exe (1), 1, 0x1000, 0, ?!?, ?!?, x, Unbatched",
+		"I-DCStart, 1000, app.exe (1), base, 0x1000, 0, 0, 0, app.exe",
+	} {
+		_, err = (&RawProfile{RawData: []byte(data)}).ParseToPprof(context.Background(), ingestion.Metadata{Key: key})
+		assert.Error(t, err, data)
+	}
+}
+
+func Test_pdbBuildID(t *testing.T) {
+	assert.Equal(t, "6F4C1A2B3C4D5E6F7A8B9C0D1E2F3A4B1A", pdbBuildID("{6f4c1a2b-3c4d-5e6f-7a8b-9c0d1e2f3a4b}", 26))
+}
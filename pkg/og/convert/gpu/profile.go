@@ -0,0 +1,270 @@
+package gpu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	distributormodel "github.com/grafana/pyroscope/pkg/distributor/model"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/og/ingestion"
+	"github.com/grafana/pyroscope/pkg/og/storage"
+	"github.com/grafana/pyroscope/pkg/og/storage/metadata"
+	"github.com/grafana/pyroscope/pkg/pprof"
+)
+
+const (
+	// MetricName is the name of the GPU kernel profiles.
+	MetricName = "gpu"
+
+	// SampleTypeLaunches is the number of kernel launches.
+	SampleTypeLaunches = "launches"
+	// SampleTypeTime is the kernel execution time on the device.
+	SampleTypeTime = "gpu_time"
+	// SampleTypeOccupancy is the kernel execution time weighted by the
+	// achieved occupancy: the ratio to SampleTypeTime is the average
+	// occupancy of the kernels of the stack.
+	SampleTypeOccupancy = "occupancy"
+
+	labelDevice = "gpu_device"
+)
+
+// RawProfile implements ingestion.RawProfile for the GPU kernel reports
+// produced by the converters of the NVIDIA profiling tools output (Nsight
+// Systems, CUPTI). A report lists the kernels executed on a device, each
+// with the host call stack that launched the kernel:
+//
+//	{
+//	  "device": "NVIDIA A100-SXM4-80GB",
+//	  "kernels": [
+//	    {
+//	      "name": "ampere_sgemm_128x64_nn",
+//	      "stack": ["main", "train_step", "at::native::addmm"],
+//	      "launches": 4,
+//	      "duration_ns": 480000,
+//	      "occupancy": 0.62
+//	    }
+//	  ]
+//	}
+//
+// The stack is listed from the root frame; the kernel name is the leaf
+// frame of the profile stack. Reports may also be sent as a JSON array,
+// one report per device.
+type RawProfile struct {
+	RawData []byte
+}
+
+type report struct {
+	Device  string   `json:"device"`
+	Kernels []kernel `json:"kernels"`
+}
+
+type kernel struct {
+	Name       string   `json:"name"`
+	Stack      []string `json:"stack"`
+	Launches   int64    `json:"launches"`
+	DurationNs int64    `json:"duration_ns"`
+	Occupancy  float64  `json:"occupancy"`
+}
+
+func (p *RawProfile) Bytes() ([]byte, error) { return p.RawData, nil }
+
+func (*RawProfile) ContentType() string { return "application/json" }
+
+func (p *RawProfile) Parse(context.Context, storage.Putter, storage.MetricsExporter, ingestion.Metadata) error {
+	return fmt.Errorf("parsing GPU kernel reports to tree/storage.Putter is not supported")
+}
+
+// ParseToPprof converts the kernels of each device in the report to a
+// profile with the launches, the execution time, and the occupancy of
+// the kernels.
+func (p *RawProfile) ParseToPprof(_ context.Context, md ingestion.Metadata) (*distributormodel.PushRequest, error) {
+	reports, err := parseReports(p.RawData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GPU kernel report: %w", err)
+	}
+	builders := make(map[string]*profileBuilder)
+	for _, r := range reports {
+		b, ok := builders[r.Device]
+		if !ok {
+			b = newProfileBuilder(md)
+			builders[r.Device] = b
+		}
+		for _, k := range r.Kernels {
+			b.addKernel(k)
+		}
+	}
+	devices := make([]string, 0, len(builders))
+	for device := range builders {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+
+	res := &distributormodel.PushRequest{
+		RawProfileSize: len(p.RawData),
+		RawProfileType: distributormodel.RawProfileTypeGPU,
+	}
+	for _, device := range devices {
+		res.Series = append(res.Series, &distributormodel.ProfileSeries{
+			Labels: createLabels(md, device),
+			Samples: []*distributormodel.ProfileSample{{
+				Profile: pprof.RawFromProto(builders[device].profile),
+			}},
+		})
+	}
+	return res, nil
+}
+
+func parseReports(data []byte) ([]report, error) {
+	var reports []report
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &reports); err != nil {
+			return nil, err
+		}
+	} else {
+		var r report
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	for _, r := range reports {
+		for i, k := range r.Kernels {
+			switch {
+			case k.Name == "":
+				return nil, fmt.Errorf("kernel %d: name is missing", i)
+			case k.Launches < 0:
+				return nil, fmt.Errorf("kernel %q: invalid number of launches %d", k.Name, k.Launches)
+			case k.DurationNs < 0:
+				return nil, fmt.Errorf("kernel %q: invalid duration %d", k.Name, k.DurationNs)
+			case k.Occupancy < 0 || k.Occupancy > 1:
+				return nil, fmt.Errorf("kernel %q: occupancy %v is out of range [0, 1]", k.Name, k.Occupancy)
+			}
+		}
+	}
+	return reports, nil
+}
+
+func createLabels(md ingestion.Metadata, device string) []*typesv1.LabelPair {
+	ls := make([]*typesv1.LabelPair, 0, len(md.Key.Labels())+5)
+	ls = append(ls, &typesv1.LabelPair{
+		Name:  labels.MetricName,
+		Value: MetricName,
+	}, &typesv1.LabelPair{
+		Name:  phlaremodel.LabelNameDelta,
+		Value: "false",
+	}, &typesv1.LabelPair{
+		Name:  "service_name",
+		Value: md.Key.AppName(),
+	}, &typesv1.LabelPair{
+		Name:  phlaremodel.LabelNamePyroscopeSpy,
+		Value: md.SpyName,
+	})
+	if device != "" {
+		ls = append(ls, &typesv1.LabelPair{
+			Name:  labelDevice,
+			Value: device,
+		})
+	}
+	for k, v := range md.Key.Labels() {
+		if !phlaremodel.IsLabelAllowedForIngestion(k) || k == labelDevice {
+			continue
+		}
+		ls = append(ls, &typesv1.LabelPair{
+			Name:  k,
+			Value: v,
+		})
+	}
+	return ls
+}
+
+type profileBuilder struct {
+	profile   *profilev1.Profile
+	strings   map[string]int64
+	locations map[string]uint64
+	samples   map[string]*profilev1.Sample
+}
+
+func newProfileBuilder(md ingestion.Metadata) *profileBuilder {
+	b := &profileBuilder{
+		profile:   &profilev1.Profile{StringTable: []string{""}},
+		strings:   map[string]int64{"": 0},
+		locations: make(map[string]uint64),
+		samples:   make(map[string]*profilev1.Sample),
+	}
+	b.profile.SampleType = []*profilev1.ValueType{
+		{Type: b.string(SampleTypeLaunches), Unit: b.string("count")},
+		{Type: b.string(SampleTypeTime), Unit: b.string(metadata.NanosecondsUnits.String())},
+		{Type: b.string(SampleTypeOccupancy), Unit: b.string(metadata.NanosecondsUnits.String())},
+	}
+	b.profile.PeriodType = &profilev1.ValueType{Type: b.string(SampleTypeTime), Unit: b.string(metadata.NanosecondsUnits.String())}
+	b.profile.Period = 1
+	b.profile.TimeNanos = md.StartTime.UnixNano()
+	b.profile.DurationNanos = md.EndTime.Sub(md.StartTime).Nanoseconds()
+	return b
+}
+
+func (b *profileBuilder) addKernel(k kernel) {
+	launches := k.Launches
+	if launches == 0 {
+		launches = 1
+	}
+	occupancy := int64(float64(k.DurationNs) * k.Occupancy)
+	// Locations are listed from the leaf frame: the kernel.
+	locations := make([]uint64, 0, len(k.Stack)+1)
+	locations = append(locations, b.location(k.Name))
+	for i := len(k.Stack) - 1; i >= 0; i-- {
+		locations = append(locations, b.location(k.Stack[i]))
+	}
+	var key []byte
+	for _, id := range locations {
+		key = fmt.Appendf(key, "%d;", id)
+	}
+	if x, ok := b.samples[string(key)]; ok {
+		x.Value[0] += launches
+		x.Value[1] += k.DurationNs
+		x.Value[2] += occupancy
+		return
+	}
+	x := &profilev1.Sample{
+		LocationId: locations,
+		Value:      []int64{launches, k.DurationNs, occupancy},
+	}
+	b.samples[string(key)] = x
+	b.profile.Sample = append(b.profile.Sample, x)
+}
+
+func (b *profileBuilder) location(name string) uint64 {
+	if id, ok := b.locations[name]; ok {
+		return id
+	}
+	fn := &profilev1.Function{
+		Id:         uint64(len(b.profile.Function) + 1),
+		Name:       b.string(name),
+		SystemName: b.string(name),
+	}
+	b.profile.Function = append(b.profile.Function, fn)
+	loc := &profilev1.Location{
+		Id:   uint64(len(b.profile.Location) + 1),
+		Line: []*profilev1.Line{{FunctionId: fn.Id}},
+	}
+	b.profile.Location = append(b.profile.Location, loc)
+	b.locations[name] = loc.Id
+	return loc.Id
+}
+
+func (b *profileBuilder) string(s string) int64 {
+	if i, ok := b.strings[s]; ok {
+		return i
+	}
+	i := int64(len(b.profile.StringTable))
+	b.profile.StringTable = append(b.profile.StringTable, s)
+	b.strings[s] = i
+	return i
+}
@@ -0,0 +1,106 @@
+package gpu
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/og/ingestion"
+	"github.com/grafana/pyroscope/pkg/og/storage/segment"
+)
+
+// stacks returns the stacks of the profile, from the root frame,
+// with the values of all the sample types.
+func stacks(p *profilev1.Profile) map[string][]int64 {
+	locations := make(map[uint64]string)
+	for _, loc := range p.Location {
+		locations[loc.Id] = p.StringTable[p.Function[loc.Line[0].FunctionId-1].Name]
+	}
+	s := make(map[string][]int64)
+	for _, x := range p.Sample {
+		names := make([]string, len(x.LocationId))
+		for i, id := range x.LocationId {
+			names[len(names)-1-i] = locations[id]
+		}
+		s[strings.Join(names, ";")] = x.Value
+	}
+	return s
+}
+
+func Test_ParseToPprof(t *testing.T) {
+	data, err := os.ReadFile("testdata/kernels.json")
+	require.NoError(t, err)
+	key, err := segment.ParseKey("trainer{env=test}")
+	require.NoError(t, err)
+	md := ingestion.Metadata{
+		Key:       key,
+		SpyName:   "nsys",
+		StartTime: time.Unix(1, 0),
+		EndTime:   time.Unix(11, 0),
+	}
+
+	req, err := (&RawProfile{RawData: data}).ParseToPprof(context.Background(), md)
+	require.NoError(t, err)
+	require.Len(t, req.Series, 2)
+
+	a100 := req.Series[0]
+	ls := phlaremodel.Labels(a100.Labels)
+	assert.Equal(t, "gpu", ls.Get("__name__"))
+	assert.Equal(t, "NVIDIA A100-SXM4-80GB", ls.Get(labelDevice))
+	assert.Equal(t, "trainer", ls.Get("service_name"))
+	assert.Equal(t, "test", ls.Get("env"))
+
+	p := a100.Samples[0].Profile.Profile
+	sampleTypes := make([]string, 0, len(p.SampleType))
+	for _, st := range p.SampleType {
+		sampleTypes = append(sampleTypes, p.StringTable[st.Type]+":"+p.StringTable[st.Unit])
+	}
+	assert.Equal(t, []string{"launches:count", "gpu_time:nanoseconds", "occupancy:nanoseconds"}, sampleTypes)
+	assert.Equal(t, int64(10*time.Second), p.DurationNanos)
+	assert.Equal(t, map[string][]int64{
+		"main;train_step;at::native::addmm;ampere_sgemm_128x64_nn":       {6, 600000, 270000},
+		"main;train_step;at::native::relu;vectorized_elementwise_kernel": {1, 20000, 20000},
+	}, stacks(p))
+
+	h100 := req.Series[1]
+	assert.Equal(t, "NVIDIA H100 80GB HBM3", phlaremodel.Labels(h100.Labels).Get(labelDevice))
+	assert.Equal(t, map[string][]int64{
+		"main;attention;flash_fwd_kernel": {8, 64000, 48000},
+	}, stacks(h100.Samples[0].Profile.Profile))
+}
+
+func Test_ParseToPprof_SingleReport(t *testing.T) {
+	key, err := segment.ParseKey("trainer")
+	require.NoError(t, err)
+	data := `{"kernels": [{"name": "reduce_kernel", "duration_ns": 100}]}`
+	req, err := (&RawProfile{RawData: []byte(data)}).ParseToPprof(context.Background(), ingestion.Metadata{Key: key})
+	require.NoError(t, err)
+	require.Len(t, req.Series, 1)
+	assert.Empty(t, phlaremodel.Labels(req.Series[0].Labels).Get(labelDevice))
+	assert.Equal(t, map[string][]int64{
+		"reduce_kernel": {1, 100, 0},
+	}, stacks(req.Series[0].Samples[0].Profile.Profile))
+}
+
+func Test_ParseToPprof_Invalid(t *testing.T) {
+	key, err := segment.ParseKey("trainer")
+	require.NoError(t, err)
+	for _, data := range []string{
+		`{"kernels": [{"duration_ns": 100}]}`,
+		`{"kernels": [{"name": "k", "duration_ns": -1}]}`,
+		`{"kernels": [{"name": "k", "launches": -1}]}`,
+		`{"kernels": [{"name": "k", "occupancy": 1.5}]}`,
+		`[{"kernels": {}}]`,
+		`not json`,
+	} {
+		_, err = (&RawProfile{RawData: []byte(data)}).ParseToPprof(context.Background(), ingestion.Metadata{Key: key})
+		assert.Error(t, err, data)
+	}
+}
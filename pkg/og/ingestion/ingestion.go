@@ -32,6 +32,8 @@ const (
   FormatLines      Format = "lines"
   FormatGroups     Format = "groups"
   FormatSpeedscope Format = "speedscope"
+  FormatETW        Format = "etw"
+  FormatGPU        Format = "gpu"
 )
 
 type RawProfile interface {
@@ -13,6 +13,7 @@ const (
 	BytesUnits           Units = "bytes"
 	LockNanosecondsUnits Units = "lock_nanoseconds"
 	LockSamplesUnits     Units = "lock_samples"
+	NanosecondsUnits     Units = "nanoseconds"
 )
 
 type AggregationType string
@@ -26,6 +26,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/collectors/version"
+	"github.com/thanos-io/objstore"
 	objstoretracing "github.com/thanos-io/objstore/tracing/opentracing"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -34,9 +35,12 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
 	statusv1 "github.com/grafana/pyroscope/api/gen/proto/go/status/v1"
 	"github.com/grafana/pyroscope/pkg/adhocprofiles"
 	apiversion "github.com/grafana/pyroscope/pkg/api/version"
+	"github.com/grafana/pyroscope/pkg/apikeys"
+	"github.com/grafana/pyroscope/pkg/canary"
 	"github.com/grafana/pyroscope/pkg/compactor"
 	"github.com/grafana/pyroscope/pkg/distributor"
 	"github.com/grafana/pyroscope/pkg/embedded/grafana"
@@ -54,13 +58,17 @@ import (
 	"github.com/grafana/pyroscope/pkg/querier/worker"
 	"github.com/grafana/pyroscope/pkg/scheduler"
 	"github.com/grafana/pyroscope/pkg/settings"
+	"github.com/grafana/pyroscope/pkg/share"
+	"github.com/grafana/pyroscope/pkg/snapshots"
 	"github.com/grafana/pyroscope/pkg/storegateway"
+	"github.com/grafana/pyroscope/pkg/summary"
 	"github.com/grafana/pyroscope/pkg/usagestats"
 	"github.com/grafana/pyroscope/pkg/util"
 	"github.com/grafana/pyroscope/pkg/util/build"
 	httputil "github.com/grafana/pyroscope/pkg/util/http"
 	"github.com/grafana/pyroscope/pkg/validation"
 	"github.com/grafana/pyroscope/pkg/validation/exporter"
+	"github.com/grafana/pyroscope/pkg/webhooks"
 )
 
 // The various modules that make up Pyroscope.
@@ -87,7 +95,11 @@ const (
 	Admin             string = "admin"
 	TenantSettings    string = "tenant-settings"
 	AdHocProfiles     string = "ad-hoc-profiles"
+	APIKeys           string = "api-keys"
+	Webhooks          string = "webhooks"
+	SnapshotsSweeper  string = "snapshots-sweeper"
 	EmbeddedGrafana   string = "embedded-grafana"
+	Canary            string = "canary"
 
 	// Experimental modules
 
@@ -130,6 +142,9 @@ func (f *Phlare) initQueryFrontend() (services.Service, error) {
 		f.API.RegisterQuerierServiceHandler(frontendSvc)
 		f.API.RegisterPyroscopeHandlers(frontendSvc)
 		f.API.RegisterVCSServiceHandler(frontendSvc)
+		f.registerSnapshots(frontendSvc)
+		f.API.RegisterShare(share.New(f.Overrides, log.With(f.logger, "component", "share")))
+		f.registerProfileSummary(frontendSvc)
 	} else {
 		f.initReadPathRouter()
 	}
@@ -148,11 +163,13 @@ func (f *Phlare) initReadPathRouter() {
 		f.Overrides,
 		f.metastoreClient,
 		f.metastoreClient,
+		f.metastoreClient,
 		f.queryBackendClient,
 	)
 
 	router := readpath.NewRouter(
 		log.With(f.logger, "component", "read-path-router"),
+		f.reg,
 		f.Overrides,
 		f.frontend,
 		newFrontend,
@@ -160,7 +177,30 @@ func (f *Phlare) initReadPathRouter() {
 
 	f.API.RegisterQuerierServiceHandler(router)
 	f.API.RegisterPyroscopeHandlers(router)
+	f.API.RegisterLiveStream(http.HandlerFunc(newFrontend.LiveStream))
+	f.API.RegisterAnnotations(http.HandlerFunc(newFrontend.AddAnnotation))
+	f.API.RegisterSlowQueries(http.HandlerFunc(newFrontend.SlowQueriesHandler))
 	f.API.RegisterVCSServiceHandler(vcsService)
+	f.registerSnapshots(router)
+	f.API.RegisterShare(share.New(f.Overrides, log.With(f.logger, "component", "share")))
+	f.registerProfileSummary(router)
+}
+
+func (f *Phlare) registerProfileSummary(client querierv1connect.QuerierServiceClient) {
+	if f.Cfg.ProfileSummary.Endpoint == "" {
+		return
+	}
+	s := summary.New(f.Cfg.ProfileSummary, f.Overrides, client, log.With(f.logger, "component", "profile-summary"))
+	f.API.RegisterProfileSummary(s)
+}
+
+func (f *Phlare) registerSnapshots(client querierv1connect.QuerierServiceClient) {
+	if f.storageBucket == nil {
+		level.Warn(f.logger).Log("msg", "no storage bucket configured, snapshots will not be available")
+		return
+	}
+	s := snapshots.New(f.storageBucket, client, log.With(f.logger, "component", "snapshots"))
+	f.API.RegisterSnapshots(s)
 }
 
 func (f *Phlare) initRuntimeConfig() (services.Service, error) {
@@ -192,28 +232,87 @@ func (f *Phlare) initRuntimeConfig() (services.Service, error) {
 
 func (f *Phlare) initTenantSettings() (services.Service, error) {
 	var store settings.Store
+	var rulesStore settings.CollectionRulesStore
+	var viewsStore settings.SavedViewsStore
+	var userStore settings.UserSettingsStore
 	var err error
 
 	switch {
 	case f.storageBucket != nil:
 		store, err = settings.NewBucketStore(f.storageBucket)
+		if err == nil {
+			rulesStore, err = settings.NewBucketCollectionRulesStore(f.storageBucket)
+		}
+		if err == nil {
+			viewsStore, err = settings.NewBucketSavedViewsStore(f.storageBucket)
+		}
+		if err == nil {
+			userStore, err = settings.NewBucketUserSettingsStore(f.storageBucket)
+		}
 	default:
 		store, err = settings.NewMemoryStore()
+		if err == nil {
+			rulesStore, err = settings.NewMemoryCollectionRulesStore()
+		}
+		if err == nil {
+			viewsStore, err = settings.NewMemorySavedViewsStore()
+		}
+		if err == nil {
+			userStore, err = settings.NewMemoryUserSettingsStore()
+		}
 		level.Warn(f.logger).Log("msg", "using in-memory settings store, changes will be lost after shutdown")
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init settings store")
 	}
 
-	settings, err := settings.New(store, log.With(f.logger, "component", TenantSettings))
+	collectionRules := settings.NewCollectionRules(rulesStore, log.With(f.logger, "component", TenantSettings))
+	savedViews := settings.NewSavedViews(viewsStore, log.With(f.logger, "component", TenantSettings))
+	settings, err := settings.New(store, userStore, log.With(f.logger, "component", TenantSettings))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init settings service")
 	}
 
 	f.API.RegisterTenantSettings(settings)
+	f.API.RegisterCollectionRules(collectionRules)
+	f.API.RegisterSavedViews(savedViews)
 	return settings, nil
 }
 
+func (f *Phlare) initAPIKeys() (services.Service, error) {
+	if !f.Cfg.APIKeys.Enabled {
+		return nil, nil
+	}
+
+	var bucket objstore.Bucket = f.storageBucket
+	if bucket == nil {
+		bucket = objstore.NewInMemBucket()
+		level.Warn(f.logger).Log("msg", "using in-memory API keys store, keys will be lost after shutdown")
+	}
+
+	k := apikeys.New(f.Cfg.APIKeys, bucket, f.apiKeysAuthenticator, log.With(f.logger, "component", APIKeys))
+	f.API.RegisterAPIKeys(k)
+	return k, nil
+}
+
+func (f *Phlare) initWebhooks() (services.Service, error) {
+	f.webhooks = webhooks.New(f.Cfg.Webhooks, log.With(f.logger, "component", Webhooks), f.reg)
+	if f.webhooks == nil {
+		return nil, nil
+	}
+	f.Cfg.Distributor.Notifier = f.webhooks
+	f.Cfg.Compactor.Notifier = f.webhooks
+	f.Cfg.Metastore.Notifier = f.webhooks
+	return f.webhooks, nil
+}
+
+func (f *Phlare) initSnapshotsSweeper() (services.Service, error) {
+	if f.storageBucket == nil {
+		return nil, nil
+	}
+	return snapshots.NewSweeper(f.storageBucket, log.With(f.logger, "component", SnapshotsSweeper)), nil
+}
+
 func (f *Phlare) initAdHocProfiles() (services.Service, error) {
 	if f.storageBucket == nil {
 		level.Warn(f.logger).Log("msg", "no storage bucket configured, ad hoc profiles will not be loaded")
@@ -373,6 +472,7 @@ func (f *Phlare) initGRPCGateway() (services.Service, error) {
 
 func (f *Phlare) initDistributor() (services.Service, error) {
 	f.Cfg.Distributor.DistributorRing.ListenPort = f.Cfg.Server.HTTPListenPort
+	f.Cfg.Distributor.Bucket = f.storageBucket
 	logger := log.With(f.logger, "component", "distributor")
 	d, err := distributor.New(f.Cfg.Distributor, f.ingesterRing, nil, f.Overrides, f.reg, logger, f.segmentWriterClient, f.auth)
 	if err != nil {
@@ -557,6 +657,7 @@ func (f *Phlare) initServer() (services.Service, error) {
 		httpMetric,
 		objstoreTracerMiddleware,
 		httputil.K6Middleware(),
+		httputil.ServerTimingMiddleware(),
 	}
 	if f.Cfg.SelfProfiling.UseK6Middleware {
 		defaultHTTPMiddleware = append(defaultHTTPMiddleware, httputil.K6Middleware())
@@ -630,6 +731,10 @@ func (f *Phlare) initEmbeddedGrafana() (services.Service, error) {
 	return grafana.New(f.Cfg.EmbeddedGrafana, f.logger)
 }
 
+func (f *Phlare) initCanary() (services.Service, error) {
+	return canary.New(f.Cfg.Canary, log.With(f.logger, "component", "canary"), f.reg), nil
+}
+
 type statusService struct {
 	statusv1.UnimplementedStatusServiceServer
 	defaultConfig *Config
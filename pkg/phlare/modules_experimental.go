@@ -26,6 +26,7 @@ import (
 	querybackendclient "github.com/grafana/pyroscope/pkg/experiment/query_backend/client"
 	"github.com/grafana/pyroscope/pkg/util"
 	"github.com/grafana/pyroscope/pkg/util/health"
+	"github.com/grafana/pyroscope/pkg/validation"
 )
 
 func (f *Phlare) initSegmentWriterRing() (_ services.Service, err error) {
@@ -94,7 +95,7 @@ func (f *Phlare) initSegmentWriterClient() (_ services.Service, err error) {
 
 func (f *Phlare) initCompactionWorker() (svc services.Service, err error) {
 	logger := log.With(f.logger, "component", "compaction-worker")
-	registerer := prometheus.WrapRegistererWithPrefix("pyroscope_compaction_worker_", f.reg)
+	registerer := prometheus.WrapRegistererWithPrefix(compactionworker.MetricsPrefix, f.reg)
 	w, err := compactionworker.New(
 		logger,
 		f.Cfg.CompactionWorker,
@@ -116,7 +117,7 @@ func (f *Phlare) initMetastore() (services.Service, error) {
 
 	logger := log.With(f.logger, "component", "metastore")
 	healthService := health.NewGRPCHealthService(f.healthServer, logger, "pyroscope.metastore")
-	registerer := prometheus.WrapRegistererWithPrefix("pyroscope_metastore_", f.reg)
+	registerer := prometheus.WrapRegistererWithPrefix(metastore.MetricsPrefix, f.reg)
 	m, err := metastore.New(
 		f.Cfg.Metastore,
 		logger,
@@ -125,12 +126,20 @@ func (f *Phlare) initMetastore() (services.Service, error) {
 		f.metastoreClient,
 		f.storageBucket,
 		f.placementManager,
+		f.Overrides,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if f.RuntimeConfig != nil {
+		watchRuntimeConfig(f.RuntimeConfig, func(c *validation.RuntimeConfigValues) {
+			m.ApplyIndexRuntimeConfig(c.MetastoreIndex)
+		})
+	}
+
 	m.Register(f.Server.GRPC)
+	f.API.RegisterMetastore(m)
 	f.metastore = m
 	return m.Service(), nil
 }
@@ -164,7 +173,9 @@ func (f *Phlare) initQueryBackend() (services.Service, error) {
 		logger,
 		f.reg,
 		f.queryBackendClient,
-		querybackend.NewBlockReader(f.logger, f.storageBucket),
+		querybackend.NewBlockReader(f.logger, f.storageBucket,
+			querybackend.NewBlockQuarantine(logger, f.metastoreClient, f.Cfg.QueryBackend.BlockQuarantineThreshold),
+			f.reg),
 	)
 	if err != nil {
 		return nil, err
@@ -41,6 +41,8 @@ import (
 
 	"github.com/grafana/pyroscope/pkg/api"
 	apiversion "github.com/grafana/pyroscope/pkg/api/version"
+	"github.com/grafana/pyroscope/pkg/apikeys"
+	"github.com/grafana/pyroscope/pkg/canary"
 	"github.com/grafana/pyroscope/pkg/cfg"
 	"github.com/grafana/pyroscope/pkg/compactor"
 	"github.com/grafana/pyroscope/pkg/distributor"
@@ -65,6 +67,7 @@ import (
 	"github.com/grafana/pyroscope/pkg/scheduler"
 	"github.com/grafana/pyroscope/pkg/scheduler/schedulerdiscovery"
 	"github.com/grafana/pyroscope/pkg/storegateway"
+	"github.com/grafana/pyroscope/pkg/summary"
 	"github.com/grafana/pyroscope/pkg/tenant"
 	"github.com/grafana/pyroscope/pkg/tracing"
 	"github.com/grafana/pyroscope/pkg/usagestats"
@@ -72,6 +75,7 @@ import (
 	"github.com/grafana/pyroscope/pkg/util/cli"
 	"github.com/grafana/pyroscope/pkg/validation"
 	"github.com/grafana/pyroscope/pkg/validation/exporter"
+	"github.com/grafana/pyroscope/pkg/webhooks"
 )
 
 type Config struct {
@@ -97,10 +101,14 @@ type Config struct {
 	SelfProfiling SelfProfilingConfig `yaml:"self_profiling,omitempty"`
 
 	MultitenancyEnabled bool              `yaml:"multitenancy_enabled,omitempty"`
+	APIKeys             apikeys.Config    `yaml:"api_keys"`
+	Webhooks            webhooks.Config   `yaml:"webhooks"`
 	Analytics           usagestats.Config `yaml:"analytics"`
 	ShowBanner          bool              `yaml:"show_banner,omitempty"`
 
 	EmbeddedGrafana grafana.Config `yaml:"embedded_grafana,omitempty"`
+	Canary          canary.Config  `yaml:"canary,omitempty"`
+	ProfileSummary  summary.Config `yaml:"profile_summary"`
 
 	ConfigFile      string `yaml:"-"`
 	ConfigExpandEnv bool   `yaml:"-"`
@@ -172,7 +180,11 @@ func (c *Config) RegisterFlagsWithContext(ctx context.Context, f *flag.FlagSet)
 	c.LimitsConfig.RegisterFlags(f)
 	c.Compactor.RegisterFlags(f, log.NewLogfmtLogger(os.Stderr))
 	c.API.RegisterFlags(f)
+	c.APIKeys.RegisterFlags(f)
+	c.Webhooks.RegisterFlags(f)
 	c.EmbeddedGrafana.RegisterFlags(f)
+	c.Canary.RegisterFlags(f)
+	c.ProfileSummary.RegisterFlags(f)
 }
 
 // registerServerFlagsWithChangedDefaultValues registers *Config.Server flags, but overrides some defaults set by the dskit package.
@@ -236,6 +248,18 @@ func (c *Config) Validate() error {
 	if err := c.Compactor.Validate(c.PhlareDB.MaxBlockDuration); err != nil {
 		return err
 	}
+	if err := c.Webhooks.Validate(); err != nil {
+		return err
+	}
+	if err := c.Canary.Validate(); err != nil {
+		return err
+	}
+	if err := c.ProfileSummary.Validate(); err != nil {
+		return err
+	}
+	if err := c.Distributor.Validate(); err != nil {
+		return err
+	}
 	return c.Ingester.Validate()
 }
 
@@ -288,6 +312,9 @@ type Phlare struct {
 
 	storageBucket phlareobj.Bucket
 
+	apiKeysAuthenticator *apikeys.Authenticator
+	webhooks             *webhooks.Notifier
+
 	grpcGatewayMux *grpcgw.ServeMux
 
 	auth     connect.Option
@@ -346,6 +373,11 @@ func New(cfg Config) (*Phlare, error) {
 	phlare.auth = connect.WithInterceptors(tenant.NewAuthInterceptor(cfg.MultitenancyEnabled))
 	phlare.Cfg.API.HTTPAuthMiddleware = util.AuthenticateUser(cfg.MultitenancyEnabled)
 	phlare.Cfg.API.GrpcAuthMiddleware = phlare.auth
+	if cfg.APIKeys.Enabled {
+		phlare.apiKeysAuthenticator = apikeys.NewAuthenticator(cfg.APIKeys)
+		phlare.Cfg.API.HTTPAPIKeyMiddleware = phlare.apiKeysAuthenticator.HTTPMiddleware()
+		phlare.Cfg.API.GrpcAPIKeyMiddleware = connect.WithInterceptors(phlare.apiKeysAuthenticator.Interceptor())
+	}
 
 	return phlare, nil
 }
@@ -374,8 +406,12 @@ func (f *Phlare) setupModuleManager() error {
 	mm.RegisterModule(Admin, f.initAdmin)
 	mm.RegisterModule(All, nil)
 	mm.RegisterModule(TenantSettings, f.initTenantSettings)
+	mm.RegisterModule(APIKeys, f.initAPIKeys, modules.UserInvisibleModule)
+	mm.RegisterModule(Webhooks, f.initWebhooks, modules.UserInvisibleModule)
+	mm.RegisterModule(SnapshotsSweeper, f.initSnapshotsSweeper, modules.UserInvisibleModule)
 	mm.RegisterModule(AdHocProfiles, f.initAdHocProfiles)
 	mm.RegisterModule(EmbeddedGrafana, f.initEmbeddedGrafana)
+	mm.RegisterModule(Canary, f.initCanary)
 
 	// Add dependencies
 	deps := map[string][]string{
@@ -383,13 +419,13 @@ func (f *Phlare) setupModuleManager() error {
 
 		Server:            {GRPCGateway},
 		API:               {Server},
-		Distributor:       {Overrides, IngesterRing, API, UsageReport},
-		Querier:           {Overrides, API, MemberlistKV, IngesterRing, UsageReport, Version},
-		QueryFrontend:     {OverridesExporter, API, MemberlistKV, UsageReport, Version},
+		Distributor:       {Overrides, IngesterRing, API, Storage, UsageReport, APIKeys, Webhooks},
+		Querier:           {Overrides, API, MemberlistKV, IngesterRing, UsageReport, Version, APIKeys},
+		QueryFrontend:     {OverridesExporter, API, MemberlistKV, Storage, UsageReport, Version, APIKeys, SnapshotsSweeper},
 		QueryScheduler:    {Overrides, API, MemberlistKV, UsageReport},
 		Ingester:          {Overrides, API, MemberlistKV, Storage, UsageReport, Version},
 		StoreGateway:      {API, Storage, Overrides, MemberlistKV, UsageReport, Admin, Version},
-		Compactor:         {API, Storage, Overrides, MemberlistKV, UsageReport},
+		Compactor:         {API, Storage, Overrides, MemberlistKV, UsageReport, Webhooks},
 		UsageReport:       {Storage, MemberlistKV},
 		Overrides:         {RuntimeConfig},
 		OverridesExporter: {Overrides, MemberlistKV},
@@ -398,18 +434,22 @@ func (f *Phlare) setupModuleManager() error {
 		MemberlistKV:      {API},
 		Admin:             {API, Storage},
 		Version:           {API, MemberlistKV},
-		TenantSettings:    {API, Storage},
-		AdHocProfiles:     {API, Overrides, Storage},
+		TenantSettings:    {API, Storage, APIKeys},
+		AdHocProfiles:     {API, Overrides, Storage, APIKeys},
+		APIKeys:           {API, Storage},
+		Webhooks:          {API},
+		SnapshotsSweeper:  {Storage},
 		EmbeddedGrafana:   {API},
+		Canary:            {API},
 	}
 
 	// Experimental modules.
 	if f.Cfg.v2Experiment {
 		experimentalModules := map[string][]string{
 			SegmentWriter:       {Overrides, API, MemberlistKV, Storage, UsageReport, MetastoreClient},
-			Metastore:           {Overrides, API, MetastoreClient, Storage, PlacementManager},
+			Metastore:           {Overrides, API, MetastoreClient, Storage, PlacementManager, Webhooks},
 			CompactionWorker:    {Overrides, API, Storage, MetastoreClient},
-			QueryBackend:        {Overrides, API, Storage, QueryBackendClient},
+			QueryBackend:        {Overrides, API, Storage, QueryBackendClient, MetastoreClient},
 			SegmentWriterRing:   {Overrides, API, MemberlistKV},
 			SegmentWriterClient: {Overrides, API, SegmentWriterRing, PlacementAgent},
 			PlacementAgent:      {Overrides, API, Storage},
@@ -40,6 +40,22 @@ func newTenantLimits(c *runtimeconfig.Manager) validation.TenantLimits {
 	return &tenantLimitsFromRuntimeConfig{c: c}
 }
 
+// watchRuntimeConfig calls the function with the runtime config every time
+// it is loaded, until the runtime config manager is stopped.
+func watchRuntimeConfig(c *runtimeconfig.Manager, fn func(*validation.RuntimeConfigValues)) {
+	ch := c.CreateListenerChannel(1)
+	if cfg, ok := c.GetConfig().(*validation.RuntimeConfigValues); ok && cfg != nil {
+		fn(cfg)
+	}
+	go func() {
+		for v := range ch {
+			if cfg, ok := v.(*validation.RuntimeConfigValues); ok && cfg != nil {
+				fn(cfg)
+			}
+		}
+	}()
+}
+
 func runtimeConfigHandler(runtimeCfgManager *runtimeconfig.Manager, defaultLimits validation.Limits) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cfg, ok := runtimeCfgManager.GetConfig().(*validation.RuntimeConfigValues)
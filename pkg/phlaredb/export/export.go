@@ -0,0 +1,376 @@
+// Package export converts blocks into flat Parquet tables that can be
+// loaded into external analytics tools (e.g., BigQuery, Spark, DuckDB)
+// without knowledge of the block format: samples, stack traces, and
+// symbols are written to separate tables, one set of files per block.
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/thanos-io/objstore"
+
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	phlareparquet "github.com/grafana/pyroscope/pkg/parquet"
+	"github.com/grafana/pyroscope/pkg/phlaredb"
+	schemav1 "github.com/grafana/pyroscope/pkg/phlaredb/schemas/v1"
+	"github.com/grafana/pyroscope/pkg/phlaredb/tsdb/index"
+)
+
+const writeBatchSize = 1 << 10
+
+type Options struct {
+	// Only the series matching all the matchers are exported.
+	// If empty, all the series are exported.
+	Matchers []*labels.Matcher
+	// Only the profiles within the time range are exported.
+	// Zero values mean no lower or upper bound.
+	Start, End time.Time
+}
+
+type Stats struct {
+	Profiles int
+	Samples  int
+	Stacks   int
+	Symbols  int
+}
+
+// Block exports the profiles of the block selected by the options to the
+// destination bucket. The tables are uploaded to the directory named after
+// the block ID. The block must be open.
+func Block(ctx context.Context, b phlaredb.BlockReader, dst objstore.Bucket, opts Options) (Stats, error) {
+	tmp, err := os.MkdirTemp("", "pyroscope-export-")
+	if err != nil {
+		return Stats{}, err
+	}
+	defer os.RemoveAll(tmp)
+
+	e := &exporter{
+		reader:  b,
+		blockID: b.Meta().ULID.String(),
+		opts:    opts,
+		dir:     tmp,
+		stacks:  make(map[uint64]map[uint32]struct{}),
+	}
+	if err = e.export(ctx); err != nil {
+		return e.stats, err
+	}
+	for _, name := range []string{SamplesFileName, StacksFileName, SymbolsFileName} {
+		if err = upload(ctx, dst, filepath.Join(tmp, name), e.blockID+"/"+name); err != nil {
+			return e.stats, err
+		}
+	}
+	return e.stats, nil
+}
+
+type exporter struct {
+	reader  phlaredb.BlockReader
+	blockID string
+	opts    Options
+	dir     string
+	stats   Stats
+
+	// Stack traces referenced by the samples exported, by partition.
+	stacks map[uint64]map[uint32]struct{}
+}
+
+func (e *exporter) export(ctx context.Context) error {
+	series, err := e.series()
+	if err != nil {
+		return fmt.Errorf("reading series: %w", err)
+	}
+	if err = e.exportSamples(series); err != nil {
+		return fmt.Errorf("exporting samples: %w", err)
+	}
+	if err = e.exportSymbols(ctx); err != nil {
+		return fmt.Errorf("exporting symbols: %w", err)
+	}
+	return nil
+}
+
+// series returns the labels of the series selected, by series index.
+func (e *exporter) series() (map[uint32]phlaremodel.Labels, error) {
+	idx := e.reader.Index()
+	var postings index.Postings
+	var err error
+	if len(e.opts.Matchers) > 0 {
+		postings, err = phlaredb.PostingsForMatchers(idx, nil, e.opts.Matchers...)
+	} else {
+		k, v := index.AllPostingsKey()
+		postings, err = idx.Postings(k, nil, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	series := make(map[uint32]phlaremodel.Labels)
+	var lbls phlaremodel.Labels
+	chks := make([]index.ChunkMeta, 1)
+	for postings.Next() {
+		if _, err = idx.Series(postings.At(), &lbls, &chks); err != nil {
+			return nil, err
+		}
+		if len(chks) > 0 {
+			series[chks[0].SeriesIndex] = lbls.Clone()
+		}
+	}
+	return series, postings.Err()
+}
+
+func (e *exporter) inRange(t int64) bool {
+	if !e.opts.Start.IsZero() && t < e.opts.Start.UnixNano() {
+		return false
+	}
+	if !e.opts.End.IsZero() && t > e.opts.End.UnixNano() {
+		return false
+	}
+	return true
+}
+
+func (e *exporter) exportSamples(series map[uint32]phlaremodel.Labels) error {
+	w, err := newTableWriter[Sample](filepath.Join(e.dir, SamplesFileName))
+	if err != nil {
+		return err
+	}
+	defer w.close()
+
+	reader := parquet.NewReader(e.reader.Profiles(), schemav1.ProfilesSchema)
+	defer reader.Close()
+	rows := phlareparquet.NewBufferedRowReaderIterator(reader, 32)
+	defer rows.Close()
+
+	var persister schemav1.ProfilePersister
+	for rows.Next() {
+		row := schemav1.ProfileRow(rows.At())
+		lbls, ok := series[row.SeriesIndex()]
+		if !ok || !e.inRange(row.TimeNanos()) {
+			continue
+		}
+		p, err := persister.Reconstruct(rows.At())
+		if err != nil {
+			return err
+		}
+		e.stats.Profiles++
+		stacks, ok := e.stacks[p.StacktracePartition]
+		if !ok {
+			stacks = make(map[uint32]struct{})
+			e.stacks[p.StacktracePartition] = stacks
+		}
+		profileID := p.ID.String()
+		sampleLabels := exportedLabels(lbls)
+		for _, s := range p.Samples {
+			if s.Value == 0 {
+				continue
+			}
+			stacks[uint32(s.StacktraceID)] = struct{}{}
+			if err = w.write(Sample{
+				BlockID:      e.blockID,
+				ProfileID:    profileID,
+				Timestamp:    p.TimeNanos,
+				ServiceName:  lbls.Get(phlaremodel.LabelNameServiceName),
+				ProfileType:  lbls.Get(phlaremodel.LabelNameProfileType),
+				Partition:    int64(p.StacktracePartition),
+				StacktraceID: int64(s.StacktraceID),
+				Value:        s.Value,
+				Labels:       sampleLabels,
+			}); err != nil {
+				return err
+			}
+			e.stats.Samples++
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	return w.close()
+}
+
+func exportedLabels(lbls phlaremodel.Labels) map[string]string {
+	m := make(map[string]string, len(lbls))
+	for _, l := range lbls {
+		if !strings.HasPrefix(l.Name, "__") {
+			m[l.Name] = l.Value
+		}
+	}
+	return m
+}
+
+func (e *exporter) exportSymbols(ctx context.Context) error {
+	stacks, err := newTableWriter[Stack](filepath.Join(e.dir, StacksFileName))
+	if err != nil {
+		return err
+	}
+	defer stacks.close()
+	symbols, err := newTableWriter[Symbol](filepath.Join(e.dir, SymbolsFileName))
+	if err != nil {
+		return err
+	}
+	defer symbols.close()
+
+	partitions := make([]uint64, 0, len(e.stacks))
+	for p := range e.stacks {
+		partitions = append(partitions, p)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	for _, p := range partitions {
+		if err = e.exportPartition(ctx, p, stacks, symbols); err != nil {
+			return fmt.Errorf("partition %d: %w", p, err)
+		}
+	}
+	if err = stacks.close(); err != nil {
+		return err
+	}
+	return symbols.close()
+}
+
+func (e *exporter) exportPartition(ctx context.Context, partition uint64, stacks *tableWriter[Stack], symbols *tableWriter[Symbol]) error {
+	pr, err := e.reader.Symbols().Partition(ctx, partition)
+	if err != nil {
+		return err
+	}
+	defer pr.Release()
+	s := pr.Symbols()
+
+	ids := make([]uint32, 0, len(e.stacks[partition]))
+	for id := range e.stacks[partition] {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	resolved := &stacktraceCollector{stacktraces: make(map[uint32][]int32, len(ids))}
+	if err = s.Stacktraces.ResolveStacktraceLocations(ctx, resolved, ids); err != nil {
+		return err
+	}
+
+	locations := make(map[int32]struct{})
+	for _, id := range ids {
+		locs := resolved.stacktraces[id]
+		row := Stack{
+			BlockID:      e.blockID,
+			Partition:    int64(partition),
+			StacktraceID: int64(id),
+			LocationIDs:  make([]int64, 0, len(locs)),
+			Frames:       make([]string, 0, len(locs)),
+		}
+		for _, loc := range locs {
+			locations[loc] = struct{}{}
+			row.LocationIDs = append(row.LocationIDs, int64(loc))
+			for _, line := range s.Locations[loc].Line {
+				row.Frames = append(row.Frames, s.Strings[s.Functions[line.FunctionId].Name])
+			}
+		}
+		if err = stacks.write(row); err != nil {
+			return err
+		}
+		e.stats.Stacks++
+	}
+
+	locIDs := make([]int32, 0, len(locations))
+	for loc := range locations {
+		locIDs = append(locIDs, loc)
+	}
+	sort.Slice(locIDs, func(i, j int) bool { return locIDs[i] < locIDs[j] })
+	for _, id := range locIDs {
+		loc := s.Locations[id]
+		m := s.Mappings[loc.MappingId]
+		for i, line := range loc.Line {
+			fn := s.Functions[line.FunctionId]
+			if err = symbols.write(Symbol{
+				BlockID:         e.blockID,
+				Partition:       int64(partition),
+				LocationID:      int64(id),
+				LineIndex:       int64(i),
+				Address:         int64(loc.Address),
+				FunctionName:    s.Strings[fn.Name],
+				SystemName:      s.Strings[fn.SystemName],
+				Filename:        s.Strings[fn.Filename],
+				Line:            int64(line.Line),
+				StartLine:       int64(fn.StartLine),
+				MappingFilename: s.Strings[m.Filename],
+				MappingBuildID:  s.Strings[m.BuildId],
+			}); err != nil {
+				return err
+			}
+			e.stats.Symbols++
+		}
+	}
+	return nil
+}
+
+type stacktraceCollector struct {
+	stacktraces map[uint32][]int32
+}
+
+func (c *stacktraceCollector) InsertStacktrace(id uint32, locations []int32) {
+	// The slice is reused by the resolver.
+	c.stacktraces[id] = append([]int32(nil), locations...)
+}
+
+type tableWriter[T any] struct {
+	file   *os.File
+	writer *parquet.GenericWriter[T]
+	buf    []T
+	closed bool
+}
+
+func newTableWriter[T any](path string) (*tableWriter[T], error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &tableWriter[T]{
+		file:   f,
+		writer: parquet.NewGenericWriter[T](f, parquet.Compression(&parquet.Zstd)),
+		buf:    make([]T, 0, writeBatchSize),
+	}, nil
+}
+
+func (w *tableWriter[T]) write(row T) error {
+	if w.buf = append(w.buf, row); len(w.buf) < writeBatchSize {
+		return nil
+	}
+	return w.flush()
+}
+
+func (w *tableWriter[T]) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.writer.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// close flushes the rows buffered and closes the file.
+// It is safe to call close multiple times.
+func (w *tableWriter[T]) close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.flush()
+	if closeErr := w.writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func upload(ctx context.Context, dst objstore.Bucket, src, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err = dst.Upload(ctx, name, f); err != nil {
+		return fmt.Errorf("uploading %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package export
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/pyroscope/pkg/objstore/providers/filesystem"
+	"github.com/grafana/pyroscope/pkg/phlaredb"
+	block_testutil "github.com/grafana/pyroscope/pkg/phlaredb/block/testutil"
+	"github.com/grafana/pyroscope/pkg/pprof/testhelper"
+)
+
+func Test_Block(t *testing.T) {
+	meta, dir := block_testutil.CreateBlock(t, func() []*testhelper.ProfileBuilder {
+		return []*testhelper.ProfileBuilder{
+			testhelper.NewProfileBuilder(int64(time.Second)).
+				CPUProfile().
+				WithLabels("service_name", "svc-a", "env", "prod").
+				ForStacktraceString("foo", "bar").AddSamples(1).
+				ForStacktraceString("baz", "bar").AddSamples(2),
+			testhelper.NewProfileBuilder(int64(2*time.Second)).
+				CPUProfile().
+				WithLabels("service_name", "svc-a", "env", "prod").
+				ForStacktraceString("foo", "bar").AddSamples(3),
+			testhelper.NewProfileBuilder(int64(time.Second)).
+				CPUProfile().
+				WithLabels("service_name", "svc-b").
+				ForStacktraceString("qux").AddSamples(4),
+		}
+	})
+	ctx := context.Background()
+	bucket, err := filesystem.NewBucket(dir)
+	require.NoError(t, err)
+	b := phlaredb.NewSingleBlockQuerierFromMeta(ctx, bucket, &meta)
+	require.NoError(t, b.Open(ctx))
+	defer b.Close()
+
+	dstDir := t.TempDir()
+	dst, err := filesystem.NewBucket(dstDir)
+	require.NoError(t, err)
+
+	stats, err := Block(ctx, b, dst, Options{
+		Matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "service_name", "svc-a")},
+		End:      time.Unix(1, 0),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Profiles: 1, Samples: 2, Stacks: 2, Symbols: 3}, stats)
+
+	blockDir := filepath.Join(dstDir, meta.ULID.String())
+	samples := readTable[Sample](t, filepath.Join(blockDir, SamplesFileName))
+	require.Len(t, samples, 2)
+	var total int64
+	for _, s := range samples {
+		assert.Equal(t, meta.ULID.String(), s.BlockID)
+		assert.Equal(t, int64(time.Second), s.Timestamp)
+		assert.Equal(t, "svc-a", s.ServiceName)
+		assert.Equal(t, "process_cpu:cpu:nanoseconds:cpu:nanoseconds", s.ProfileType)
+		assert.Equal(t, "prod", s.Labels["env"])
+		assert.NotContains(t, s.Labels, "__name__")
+		total += s.Value
+	}
+	assert.Equal(t, int64(3), total)
+
+	frames := make(map[int64][]string)
+	for _, s := range readTable[Stack](t, filepath.Join(blockDir, StacksFileName)) {
+		frames[s.StacktraceID] = s.Frames
+		assert.Len(t, s.LocationIDs, len(s.Frames))
+	}
+	for _, s := range samples {
+		switch s.Value {
+		case 1:
+			assert.Equal(t, []string{"foo", "bar"}, frames[s.StacktraceID])
+		case 2:
+			assert.Equal(t, []string{"baz", "bar"}, frames[s.StacktraceID])
+		}
+	}
+
+	functions := make(map[string]struct{})
+	for _, s := range readTable[Symbol](t, filepath.Join(blockDir, SymbolsFileName)) {
+		functions[s.FunctionName] = struct{}{}
+	}
+	assert.Equal(t, map[string]struct{}{"foo": {}, "bar": {}, "baz": {}}, functions)
+}
+
+func readTable[T any](t *testing.T, path string) []T {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	stat, err := f.Stat()
+	require.NoError(t, err)
+	rows, err := parquet.Read[T](f, stat.Size())
+	require.NoError(t, err)
+	return rows
+}
@@ -0,0 +1,59 @@
+package export
+
+// The tables are deliberately flat and use signed integers only, so that
+// they can be loaded as is into tools like BigQuery or Spark.
+//
+// Stack trace and location identifiers are only unique within the
+// symbols partition of a block: the tables are to be joined on the
+// block_id, partition, and the identifier.
+
+const (
+	SamplesFileName = "samples.parquet"
+	StacksFileName  = "stacks.parquet"
+	SymbolsFileName = "symbols.parquet"
+)
+
+// Sample is a row of the samples table: the value
+// of a stack trace in a profile.
+type Sample struct {
+	BlockID      string `parquet:"block_id,dict"`
+	ProfileID    string `parquet:"profile_id"`
+	Timestamp    int64  `parquet:"timestamp,timestamp(nanosecond)"`
+	ServiceName  string `parquet:"service_name,dict"`
+	ProfileType  string `parquet:"profile_type,dict"`
+	Partition    int64  `parquet:"partition"`
+	StacktraceID int64  `parquet:"stacktrace_id"`
+	Value        int64  `parquet:"value"`
+	// Series labels, except for the internal ones.
+	Labels map[string]string `parquet:"labels"`
+}
+
+// Stack is a row of the stacks table: the locations
+// of a stack trace, starting from the leaf.
+type Stack struct {
+	BlockID      string  `parquet:"block_id,dict"`
+	Partition    int64   `parquet:"partition"`
+	StacktraceID int64   `parquet:"stacktrace_id"`
+	LocationIDs  []int64 `parquet:"location_ids,list"`
+	// Names of the functions of the locations, including the
+	// inlined ones, starting from the leaf.
+	Frames []string `parquet:"frames,list"`
+}
+
+// Symbol is a row of the symbols table: a line of a location. A
+// location includes multiple lines if functions have been inlined,
+// starting from the innermost one (line_index 0).
+type Symbol struct {
+	BlockID         string `parquet:"block_id,dict"`
+	Partition       int64  `parquet:"partition"`
+	LocationID      int64  `parquet:"location_id"`
+	LineIndex       int64  `parquet:"line_index"`
+	Address         int64  `parquet:"address"`
+	FunctionName    string `parquet:"function_name,dict"`
+	SystemName      string `parquet:"system_name,dict"`
+	Filename        string `parquet:"filename,dict"`
+	Line            int64  `parquet:"line"`
+	StartLine       int64  `parquet:"start_line"`
+	MappingFilename string `parquet:"mapping_filename,dict"`
+	MappingBuildID  string `parquet:"mapping_build_id,dict"`
+}
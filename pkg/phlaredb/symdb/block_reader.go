@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 
 	"github.com/grafana/dskit/multierror"
@@ -296,6 +297,16 @@ func (r *Reader) Partition(ctx context.Context, partition uint64) (PartitionRead
 	return p, nil
 }
 
+// Partitions returns the identifiers of the partitions, in ascending order.
+func (r *Reader) Partitions() []uint64 {
+	partitions := make([]uint64, 0, len(r.partitionsMap))
+	for p := range r.partitionsMap {
+		partitions = append(partitions, p)
+	}
+	slices.Sort(partitions)
+	return partitions
+}
+
 func (r *Reader) partition(ctx context.Context, partition uint64) (*partition, error) {
 	p, ok := r.partitionsMap[partition]
 	if !ok {
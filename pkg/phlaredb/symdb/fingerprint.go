@@ -0,0 +1,101 @@
+package symdb
+
+import (
+	"encoding/binary"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// StacktraceFingerprints returns the fingerprints of the stack traces of
+// the partition, indexed by the stack trace identifier.
+//
+// The fingerprints are content-addressed: a fingerprint only depends on
+// the symbols of the stack trace frames, therefore the same stack trace
+// has the same fingerprint in any database, regardless of its identifier.
+// Zero fingerprint denotes an identifier that does not refer to a stack
+// trace.
+func StacktraceFingerprints(p PartitionReader) []uint64 {
+	var stats PartitionStats
+	p.WriteStats(&stats)
+	f := newFingerprinter(p.Symbols())
+	fingerprints := make([]uint64, stats.MaxStacktraceID)
+	var locations []uint64
+	for id := 1; id < len(fingerprints); id++ {
+		locations = f.symbols.Stacktraces.LookupLocations(locations, uint32(id))
+		fingerprints[id] = f.stacktrace(locations)
+	}
+	return fingerprints
+}
+
+type fingerprinter struct {
+	symbols   *Symbols
+	locations []uint64 // Fingerprints of the locations; zero if not computed.
+	buf       []byte
+}
+
+func newFingerprinter(s *Symbols) *fingerprinter {
+	return &fingerprinter{
+		symbols:   s,
+		locations: make([]uint64, len(s.Locations)),
+	}
+}
+
+// stacktrace returns the fingerprint of the stack trace; the
+// locations are expected in the leaf-first order.
+func (f *fingerprinter) stacktrace(locations []uint64) uint64 {
+	if len(locations) == 0 {
+		return 0
+	}
+	f.buf = f.buf[:0]
+	for i := len(locations) - 1; i >= 0; i-- {
+		f.buf = binary.LittleEndian.AppendUint64(f.buf, f.location(locations[i]))
+	}
+	return nonZero(xxhash.Sum64(f.buf))
+}
+
+func (f *fingerprinter) location(id uint64) uint64 {
+	if id >= uint64(len(f.locations)) {
+		// The location is missing: the stack trace can't be
+		// resolved, but it's still identified by the reference.
+		return id
+	}
+	if fp := f.locations[id]; fp != 0 {
+		return fp
+	}
+	loc := f.symbols.Locations[id]
+	b := binary.LittleEndian.AppendUint64(nil, loc.Address)
+	if int(loc.MappingId) < len(f.symbols.Mappings) {
+		m := f.symbols.Mappings[loc.MappingId]
+		b = f.appendString(b, m.Filename)
+		b = f.appendString(b, m.BuildId)
+	}
+	for _, line := range loc.Line {
+		if int(line.FunctionId) < len(f.symbols.Functions) {
+			fn := f.symbols.Functions[line.FunctionId]
+			b = f.appendString(b, fn.Name)
+			b = f.appendString(b, fn.SystemName)
+			b = f.appendString(b, fn.Filename)
+			b = binary.LittleEndian.AppendUint32(b, fn.StartLine)
+		}
+		b = binary.LittleEndian.AppendUint32(b, uint32(line.Line))
+	}
+	fp := nonZero(xxhash.Sum64(b))
+	f.locations[id] = fp
+	return fp
+}
+
+func (f *fingerprinter) appendString(b []byte, id uint32) []byte {
+	var s string
+	if int(id) < len(f.symbols.Strings) {
+		s = f.symbols.Strings[id]
+	}
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func nonZero(h uint64) uint64 {
+	if h == 0 {
+		return 1
+	}
+	return h
+}
@@ -0,0 +1,41 @@
+package symdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StacktraceFingerprints(t *testing.T) {
+	a := newBlockSuite(t, [][]string{{"testdata/profile.pb.gz"}})
+	defer a.teardown()
+	// The same profile is written after another one:
+	// the stack trace identifiers differ.
+	b := newMemSuite(t, [][]string{{"testdata/big-profile.pb.gz", "testdata/profile.pb.gz"}})
+
+	pa, err := a.reader.Partition(context.Background(), 0)
+	require.NoError(t, err)
+	pb, err := b.db.Partition(context.Background(), 0)
+	require.NoError(t, err)
+	fa := StacktraceFingerprints(pa)
+	fb := StacktraceFingerprints(pb)
+	assert.Zero(t, fa[0])
+
+	sa := a.indexed[0][0].Samples.StacktraceIDs
+	sb := b.indexed[0][0].Samples.StacktraceIDs
+	require.Equal(t, len(sa), len(sb))
+	var differ bool
+	ids := make(map[uint64]uint32)
+	for i := range sa {
+		differ = differ || sa[i] != sb[i]
+		require.NotZero(t, fa[sa[i]])
+		require.Equal(t, fa[sa[i]], fb[sb[i]])
+		if id, ok := ids[fa[sa[i]]]; ok {
+			require.Equal(t, id, sa[i], "fingerprint collision")
+		}
+		ids[fa[sa[i]]] = sa[i]
+	}
+	assert.True(t, differ)
+}
@@ -404,6 +404,61 @@ func (p *Profile) Normalize() {
 	p.clearAddresses()
 }
 
+// TruncatedFrameName is the name of the synthetic frame that replaces
+// the frames of a stack trace beyond the depth limit.
+const TruncatedFrameName = "[truncated]"
+
+// TruncateStacktraces limits the depth of the stack traces: the leaf frames
+// beyond the limit are collapsed into a synthetic TruncatedFrameName frame,
+// so that the stack trace does not exceed maxDepth frames including the
+// synthetic one. Locations and functions that are no longer referenced are
+// removed. The call should precede Normalize, so that the samples with the
+// same truncated stack trace are merged.
+func (p *Profile) TruncateStacktraces(maxDepth int) {
+	if maxDepth <= 0 {
+		return
+	}
+	var (
+		truncated *profilev1.Sample
+		location  uint64
+	)
+	for _, s := range p.Sample {
+		if len(s.LocationId) <= maxDepth {
+			continue
+		}
+		if truncated == nil {
+			truncated = new(profilev1.Sample)
+			location = p.addTruncatedLocation()
+		}
+		// s.LocationId[0] is the leaf: n frames are
+		// replaced with the synthetic one.
+		n := len(s.LocationId) - maxDepth + 1
+		truncated.LocationId = append(truncated.LocationId, s.LocationId[:n]...)
+		s.LocationId = s.LocationId[n-1:]
+		s.LocationId[0] = location
+	}
+	if truncated != nil {
+		p.clearSampleReferences([]*profilev1.Sample{truncated})
+	}
+}
+
+func (p *Profile) addTruncatedLocation() uint64 {
+	var locationID, functionID uint64
+	for _, l := range p.Location {
+		locationID = max(locationID, l.Id)
+	}
+	for _, f := range p.Function {
+		functionID = max(functionID, f.Id)
+	}
+	name := int64(len(p.StringTable))
+	p.StringTable = append(p.StringTable, TruncatedFrameName)
+	fn := &profilev1.Function{Id: functionID + 1, Name: name, SystemName: name}
+	p.Function = append(p.Function, fn)
+	loc := &profilev1.Location{Id: locationID + 1, Line: []*profilev1.Line{{FunctionId: fn.Id}}}
+	p.Location = append(p.Location, loc)
+	return loc.Id
+}
+
 // Removes addresses from symbolized profiles.
 func (p *Profile) clearAddresses() {
 	for _, m := range p.Mapping {
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -526,6 +527,53 @@ func TestRemoveDuplicateSampleStacktraces(t *testing.T) {
 	require.Equal(t, total-duplicate, len(p.Sample), "unexpected total samples")
 }
 
+func TestTruncateStacktraces(t *testing.T) {
+	p := &Profile{Profile: &profilev1.Profile{
+		SampleType: []*profilev1.ValueType{{Type: 1, Unit: 2}},
+		PeriodType: &profilev1.ValueType{Type: 1, Unit: 2},
+		Sample: []*profilev1.Sample{
+			{LocationId: []uint64{1, 2, 3, 4}, Value: []int64{1}},
+			{LocationId: []uint64{2, 3, 4}, Value: []int64{2}},
+			{LocationId: []uint64{1, 1, 3, 4}, Value: []int64{3}},
+		},
+		Mapping: []*profilev1.Mapping{{Id: 1, HasFunctions: true}},
+		Location: []*profilev1.Location{
+			{Id: 1, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 1}}},
+			{Id: 2, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 2}}},
+			{Id: 3, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 3}}},
+			{Id: 4, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 4}}},
+		},
+		Function: []*profilev1.Function{
+			{Id: 1, Name: 3},
+			{Id: 2, Name: 4},
+			{Id: 3, Name: 5},
+			{Id: 4, Name: 6},
+		},
+		StringTable: []string{"", "cpu", "nanoseconds", "a", "b", "c", "d"},
+	}}
+
+	p.TruncateStacktraces(3)
+	p.Normalize()
+
+	stacks := make(map[string]int64)
+	for _, s := range p.Sample {
+		var names []string
+		for _, id := range s.LocationId {
+			fn := p.Function[p.Location[id-1].Line[0].FunctionId-1]
+			names = append(names, p.StringTable[fn.Name])
+		}
+		stacks[strings.Join(names, ";")] += s.Value[0]
+	}
+	assert.Equal(t, map[string]int64{
+		TruncatedFrameName + ";c;d": 4,
+		"b;c;d":                     2,
+	}, stacks)
+	// The truncated frames are removed from the symbols.
+	assert.Len(t, p.Location, 4)
+	assert.Len(t, p.Function, 4)
+	assert.NotContains(t, p.StringTable, "a")
+}
+
 func TestEmptyMappingJava(t *testing.T) {
 	p, err := OpenFile("testdata/profile_java")
 	require.NoError(t, err)
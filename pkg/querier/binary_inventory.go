@@ -0,0 +1,289 @@
+package querier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/errgroup"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/og/util/attime"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const (
+	defaultBinaryInventoryRange = 24 * time.Hour
+	defaultBinaryInventoryStep  = time.Hour
+	maxBinaryInventoryBuckets   = 168
+	binaryInventoryConcurrency  = 16
+)
+
+// BinaryInventoryResponse lists the binaries observed in the profiles.
+type BinaryInventoryResponse struct {
+	Binaries []*InventoryBinary `json:"binaries"`
+}
+
+// InventoryBinary is a binary or a shared library, as identified by the
+// profile mappings, and the services it was observed in.
+type InventoryBinary struct {
+	Name     string              `json:"name"`
+	Path     string              `json:"path"`
+	BuildID  string              `json:"build_id,omitempty"`
+	Version  string              `json:"version,omitempty"`
+	Services []*InventoryService `json:"services"`
+}
+
+// InventoryService is a service the binary was observed in. The first
+// and the last time the binary was seen are accurate within the step
+// of the query.
+type InventoryService struct {
+	ServiceName string `json:"service_name"`
+	FirstSeen   int64  `json:"first_seen"`
+	LastSeen    int64  `json:"last_seen"`
+}
+
+// BinaryInventory lists the binaries and the shared libraries executed by
+// the services, as observed in the mappings of the profiles, so that one
+// can tell which services are running a specific version of a library.
+//
+// The query parameters are:
+//   - query: the profile type and the label selector of the profiles,
+//     the CPU profiles of all services by default.
+//   - from, until: the time range, the last 24 hours by default.
+//   - step: the resolution of the first and last seen times, 1h by default.
+//   - binary: only the binaries with the file name containing the value.
+//   - build_id: only the binaries with the build ID.
+func (q *QueryHandlers) BinaryInventory(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	v := req.Form
+	selector, profileTypeID := "{}", costAttributionProfileType
+	if v.Get("query") != "" {
+		var err error
+		var ptype *typesv1.ProfileType
+		if selector, ptype, err = ParseQuery("query", req); err != nil {
+			httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+			return
+		}
+		profileTypeID = ptype.ID
+	}
+	end := time.Now()
+	if s := v.Get("until"); s != "" {
+		end = attime.Parse(s)
+	}
+	start := end.Add(-defaultBinaryInventoryRange)
+	if s := v.Get("from"); s != "" {
+		start = attime.Parse(s)
+	}
+	step := defaultBinaryInventoryStep
+	if s := v.Get("step"); s != "" {
+		d, err := model.ParseDuration(s)
+		if err != nil {
+			httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid step: %w", err)))
+			return
+		}
+		step = time.Duration(d)
+	}
+	switch {
+	case !end.After(start):
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, errors.New("until must be after from")))
+		return
+	case step <= 0 || end.Sub(start)/step >= maxBinaryInventoryBuckets:
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument,
+			fmt.Errorf("step must be positive, and the time range must not exceed %d steps", maxBinaryInventoryBuckets)))
+		return
+	}
+
+	inv := binaryInventory{
+		filter: binaryFilter{
+			name:    v.Get("binary"),
+			buildID: strings.ToLower(v.Get("build_id")),
+		},
+		binaries: make(map[binaryKey]*InventoryBinary),
+	}
+	// The steps at which the services have profiles are looked up
+	// first, so that the profiles are only fetched where they exist.
+	series, err := q.client.SelectSeries(req.Context(), connect.NewRequest(&querierv1.SelectSeriesRequest{
+		ProfileTypeID: profileTypeID,
+		LabelSelector: selector,
+		Start:         start.UnixMilli(),
+		End:           end.UnixMilli(),
+		GroupBy:       []string{"service_name"},
+		Step:          step.Seconds(),
+		Aggregation:   typesv1.TimeSeriesAggregationType_TIME_SERIES_AGGREGATION_TYPE_SUM.Enum(),
+	}))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	g, ctx := errgroup.WithContext(req.Context())
+	g.SetLimit(binaryInventoryConcurrency)
+	for _, s := range series.Msg.Series {
+		var service string
+		for _, l := range s.Labels {
+			if l.Name == "service_name" {
+				service = l.Value
+			}
+		}
+		if service == "" {
+			continue
+		}
+		for _, p := range s.Points {
+			if p.Value == 0 {
+				continue
+			}
+			// A point aggregates the profiles of the step preceding it.
+			from := max(p.Timestamp-step.Milliseconds(), start.UnixMilli())
+			to := min(p.Timestamp, end.UnixMilli())
+			g.Go(func() error {
+				resp, err := q.client.SelectMergeProfile(ctx, connect.NewRequest(&querierv1.SelectMergeProfileRequest{
+					ProfileTypeID: profileTypeID,
+					LabelSelector: withServiceName(selector, service),
+					Start:         from,
+					End:           to,
+				}))
+				if err != nil {
+					return err
+				}
+				p := resp.Msg
+				for _, m := range p.Mapping {
+					inv.add(service, p.StringTable[m.Filename], p.StringTable[m.BuildId], from, to)
+				}
+				return nil
+			})
+		}
+	}
+	if err = g.Wait(); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(&BinaryInventoryResponse{Binaries: inv.list()}); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+}
+
+// withServiceName adds the service name matcher to the label selector.
+func withServiceName(selector, service string) string {
+	matcher := fmt.Sprintf("service_name=%q", service)
+	selector = strings.TrimSpace(selector)
+	if inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(selector, "{"), "}")); inner != "" {
+		return "{" + inner + "," + matcher + "}"
+	}
+	return "{" + matcher + "}"
+}
+
+type binaryKey struct {
+	path    string
+	buildID string
+}
+
+type binaryFilter struct {
+	name    string
+	buildID string
+}
+
+func (f binaryFilter) matches(name, buildID string) bool {
+	return strings.Contains(name, f.name) && (f.buildID == "" || f.buildID == strings.ToLower(buildID))
+}
+
+type binaryInventory struct {
+	filter   binaryFilter
+	mu       sync.Mutex
+	binaries map[binaryKey]*InventoryBinary
+}
+
+func (inv *binaryInventory) add(service, filename, buildID string, from, to int64) {
+	// Mappings of anonymous memory and special regions, such
+	// as [vdso], do not refer to a binary.
+	if filename == "" || strings.HasPrefix(filename, "[") {
+		return
+	}
+	name := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if !inv.filter.matches(name, buildID) {
+		return
+	}
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	k := binaryKey{path: filename, buildID: buildID}
+	b, ok := inv.binaries[k]
+	if !ok {
+		b = &InventoryBinary{
+			Name:    name,
+			Path:    filename,
+			BuildID: buildID,
+			Version: binaryVersion(name),
+		}
+		inv.binaries[k] = b
+	}
+	for _, s := range b.Services {
+		if s.ServiceName == service {
+			s.FirstSeen = min(s.FirstSeen, from)
+			s.LastSeen = max(s.LastSeen, to)
+			return
+		}
+	}
+	b.Services = append(b.Services, &InventoryService{
+		ServiceName: service,
+		FirstSeen:   from,
+		LastSeen:    to,
+	})
+}
+
+func (inv *binaryInventory) list() []*InventoryBinary {
+	binaries := make([]*InventoryBinary, 0, len(inv.binaries))
+	for _, b := range inv.binaries {
+		sort.Slice(b.Services, func(i, j int) bool {
+			return b.Services[i].ServiceName < b.Services[j].ServiceName
+		})
+		binaries = append(binaries, b)
+	}
+	sort.Slice(binaries, func(i, j int) bool {
+		a, b := binaries[i], binaries[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.BuildID < b.BuildID
+	})
+	return binaries
+}
+
+var (
+	// libssl.so.3, libc.so.6, libstdc++.so.6.0.30
+	sonameVersion = regexp.MustCompile(`\.so\.(\d+(?:\.\d+)*)$`)
+	// libfoo-1.2.3.so, log4j-core-2.14.1.jar, python3.11
+	fileNameVersion = regexp.MustCompile(`[-_]?(\d+(?:\.\d+)+)(?:\.(?:so|jar|dll|dylib))?$`)
+)
+
+// binaryVersion returns the version of the binary, as indicated by the
+// file name, if any. The file name is the only source of the version in
+// the profile mappings: the build ID identifies the binary, but it's not
+// a version.
+func binaryVersion(name string) string {
+	if m := sonameVersion.FindStringSubmatch(name); m != nil {
+		return m[1]
+	}
+	if m := fileNameVersion.FindStringSubmatch(name); m != nil {
+		return m[1]
+	}
+	return ""
+}
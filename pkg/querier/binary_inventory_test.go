@@ -0,0 +1,117 @@
+package querier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockquerierv1connect"
+)
+
+func Test_BinaryInventory(t *testing.T) {
+	const (
+		start = int64(0)
+		step  = int64(time.Hour / time.Millisecond)
+	)
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	client.On("SelectSeries", mock.Anything, mock.Anything).
+		Once().
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(*connect.Request[querierv1.SelectSeriesRequest]).Msg
+			assert.Equal(t, []string{"service_name"}, req.GroupBy)
+			assert.Equal(t, `{namespace="prod"}`, req.LabelSelector)
+			assert.Equal(t, 3*step, req.End-req.Start)
+		}).
+		Return(connect.NewResponse(&querierv1.SelectSeriesResponse{Series: []*typesv1.Series{
+			{
+				Labels: []*typesv1.LabelPair{{Name: "service_name", Value: "api"}},
+				Points: []*typesv1.Point{{Timestamp: start + step, Value: 1}, {Timestamp: start + 2*step}, {Timestamp: start + 3*step, Value: 1}},
+			},
+			{
+				Labels: []*typesv1.LabelPair{{Name: "service_name", Value: "worker"}},
+				Points: []*typesv1.Point{{Timestamp: start + 2*step, Value: 1}},
+			},
+		}}), nil)
+
+	profiles := map[string]*profilev1.Profile{
+		`{namespace="prod",service_name="api"}`: {
+			StringTable: []string{"", "/usr/lib/libssl.so.3", "abc", "/app/api", "def", "[vdso]"},
+			Mapping:     []*profilev1.Mapping{{Filename: 3, BuildId: 4}, {Filename: 1, BuildId: 2}, {Filename: 5}},
+		},
+		`{namespace="prod",service_name="worker"}`: {
+			StringTable: []string{"", "/usr/lib/libssl.so.1.1", "123"},
+			Mapping:     []*profilev1.Mapping{{Filename: 1, BuildId: 2}},
+		},
+	}
+	client.On("SelectMergeProfile", mock.Anything, mock.Anything).
+		Times(3).
+		Return(func(_ context.Context, req *connect.Request[querierv1.SelectMergeProfileRequest]) (*connect.Response[profilev1.Profile], error) {
+			assert.Equal(t, step, req.Msg.End-req.Msg.Start)
+			return connect.NewResponse(profiles[req.Msg.LabelSelector]), nil
+		})
+
+	rec := httptest.NewRecorder()
+	NewHTTPHandlers(client).BinaryInventory(rec, httptest.NewRequest(http.MethodGet,
+		`/pyroscope/binaries?query=process_cpu:cpu:nanoseconds:cpu:nanoseconds{namespace="prod"}&from=0&until=10800&binary=libssl`, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp BinaryInventoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []*InventoryBinary{
+		{
+			Name:     "libssl.so.1.1",
+			Path:     "/usr/lib/libssl.so.1.1",
+			BuildID:  "123",
+			Version:  "1.1",
+			Services: []*InventoryService{{ServiceName: "worker", FirstSeen: start + step, LastSeen: start + 2*step}},
+		},
+		{
+			Name:     "libssl.so.3",
+			Path:     "/usr/lib/libssl.so.3",
+			BuildID:  "abc",
+			Version:  "3",
+			Services: []*InventoryService{{ServiceName: "api", FirstSeen: start, LastSeen: start + 3*step}},
+		},
+	}, resp.Binaries)
+}
+
+func Test_BinaryInventory_InvalidRequest(t *testing.T) {
+	handlers := NewHTTPHandlers(mockquerierv1connect.NewMockQuerierServiceClient(t))
+	for _, params := range []string{
+		"step=foo",
+		"step=0s",
+		"from=now-30d&step=1h",
+		"from=now&until=now-1h",
+		"query=foo",
+	} {
+		rec := httptest.NewRecorder()
+		handlers.BinaryInventory(rec, httptest.NewRequest(http.MethodGet, "/pyroscope/binaries?"+params, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, params)
+	}
+}
+
+func Test_binaryVersion(t *testing.T) {
+	for name, version := range map[string]string{
+		"libssl.so.3":                "3",
+		"libstdc++.so.6.0.30":        "6.0.30",
+		"log4j-core-2.14.1.jar":      "2.14.1",
+		"libfoo-1.2.so":              "1.2",
+		"python3.11":                 "3.11",
+		"libc.so":                    "",
+		"api":                        "",
+		"System.Private.CoreLib.dll": "",
+	} {
+		assert.Equal(t, version, binaryVersion(name), name)
+	}
+}
@@ -0,0 +1,92 @@
+package querier
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/model"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const (
+	costAttributionProfileType   = "process_cpu:cpu:nanoseconds:cpu:nanoseconds"
+	defaultCostAttributionWindow = 5 * time.Minute
+	maxCostAttributionWindow     = time.Hour
+)
+
+// The labels OpenCost uses to allocate the costs of the containers.
+var costAttributionLabels = []string{"namespace", "pod", "container"}
+
+// CostAttribution exposes the CPU usage observed by the profilers, per
+// Kubernetes namespace, pod, and container, in the Prometheus text format.
+// The endpoint is meant to be scraped by the Prometheus instance OpenCost
+// (or Kubecost) queries, so that the CPU costs of the workloads can be
+// allocated by their actual usage, rather than by the resource requests.
+//
+// The value is the average number of CPU cores used over the window
+// preceding the scrape (query parameter "window", 5m by default). Only
+// the profiles labelled with the Kubernetes namespace are accounted.
+func (q *QueryHandlers) CostAttribution(w http.ResponseWriter, req *http.Request) {
+	window := defaultCostAttributionWindow
+	if s := req.URL.Query().Get("window"); s != "" {
+		d, err := model.ParseDuration(s)
+		if err != nil {
+			httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid window: %w", err)))
+			return
+		}
+		window = time.Duration(d)
+	}
+	if window <= 0 || window > maxCostAttributionWindow {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument,
+			fmt.Errorf("window must be positive and not greater than %s", maxCostAttributionWindow)))
+		return
+	}
+
+	end := time.Now()
+	resp, err := q.client.SelectSeries(req.Context(), connect.NewRequest(&querierv1.SelectSeriesRequest{
+		ProfileTypeID: costAttributionProfileType,
+		LabelSelector: `{namespace!=""}`,
+		Start:         end.Add(-window).UnixMilli(),
+		End:           end.UnixMilli(),
+		GroupBy:       costAttributionLabels,
+		Step:          window.Seconds(),
+		Aggregation:   typesv1.TimeSeriesAggregationType_TIME_SERIES_AGGREGATION_TYPE_SUM.Enum(),
+	}))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	cores := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pyroscope_cost_attribution_cpu_usage_cores",
+		Help: "Average number of CPU cores used, as observed by the profilers, over the window preceding the scrape.",
+	}, costAttributionLabels)
+	for _, s := range resp.Msg.Series {
+		var nanoseconds float64
+		for _, p := range s.Points {
+			nanoseconds += p.Value
+		}
+		values := make([]string, len(costAttributionLabels))
+		for i, name := range costAttributionLabels {
+			for _, l := range s.Labels {
+				if l.Name == name {
+					values[i] = l.Value
+					break
+				}
+			}
+		}
+		cores.WithLabelValues(values...).Add(nanoseconds / float64(window.Nanoseconds()))
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(cores)
+	// The response is compressed by the HTTP server.
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{DisableCompression: true}).ServeHTTP(w, req)
+}
@@ -0,0 +1,47 @@
+package querier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockquerierv1connect"
+)
+
+func Test_CostAttribution(t *testing.T) {
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	client.On("SelectSeries", mock.Anything, mock.Anything).
+		Once().
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(*connect.Request[querierv1.SelectSeriesRequest]).Msg
+			assert.Equal(t, costAttributionProfileType, req.ProfileTypeID)
+			assert.Equal(t, []string{"namespace", "pod", "container"}, req.GroupBy)
+			assert.Equal(t, time.Minute.Milliseconds(), req.End-req.Start)
+		}).
+		Return(connect.NewResponse(&querierv1.SelectSeriesResponse{Series: []*typesv1.Series{{
+			Labels: []*typesv1.LabelPair{{Name: "namespace", Value: "ns"}, {Name: "pod", Value: "pod-1"}, {Name: "container", Value: "app"}},
+			Points: []*typesv1.Point{{Value: 30e9}, {Value: 60e9}},
+		}}}), nil)
+
+	rec := httptest.NewRecorder()
+	NewHTTPHandlers(client).CostAttribution(rec, httptest.NewRequest(http.MethodGet, "/pyroscope/cost-attribution/metrics?window=1m", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `pyroscope_cost_attribution_cpu_usage_cores{container="app",namespace="ns",pod="pod-1"} 1.5`)
+}
+
+func Test_CostAttribution_InvalidWindow(t *testing.T) {
+	handlers := NewHTTPHandlers(mockquerierv1connect.NewMockQuerierServiceClient(t))
+	for _, window := range []string{"foo", "0s", "2h"} {
+		rec := httptest.NewRecorder()
+		handlers.CostAttribution(rec, httptest.NewRequest(http.MethodGet, "/pyroscope/cost-attribution/metrics?window="+window, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, window)
+	}
+}
@@ -0,0 +1,241 @@
+package querier
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"connectrpc.com/connect"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/querier/timeline"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const (
+	exportTableTop    = "top"
+	exportTableSeries = "series"
+
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+
+	defaultExportTopLimit = 1000
+)
+
+// Export streams table-style query results in the CSV or JSON lines
+// format, to be loaded into spreadsheets and notebooks. The query is
+// specified as for Render. The table is selected with the "table"
+// parameter:
+//   - top: the functions, with their self and total values, sorted by
+//     the self value. At most "limit" functions are returned (1000 by
+//     default, 0 for all the functions).
+//   - series: the time series of the query, grouped by the "groupBy"
+//     labels, with the given "step" (in seconds) and "aggregation".
+func (q *QueryHandlers) Export(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	selectParams, _, err := parseSelectProfilesRequest(renderRequestFieldNames{}, req)
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+
+	params := req.URL.Query()
+	format := params.Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatCSV && format != exportFormatJSONL {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unsupported format %q, expected csv or jsonl", format)))
+		return
+	}
+	table := params.Get("table")
+	if table == "" {
+		table = exportTableTop
+	}
+
+	var export func(io.Writer, string) error
+	switch table {
+	case exportTableTop:
+		limit := defaultExportTopLimit
+		if s := params.Get("limit"); s != "" {
+			if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+				httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid limit %q", s)))
+				return
+			}
+		}
+		resp, err := q.client.SelectMergeStacktraces(req.Context(), connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+			Start:         selectParams.Start,
+			End:           selectParams.End,
+			ProfileTypeID: selectParams.ProfileTypeID,
+			LabelSelector: selectParams.LabelSelector,
+			MaxNodes:      selectParams.MaxNodes,
+			Format:        querierv1.ProfileFormat_PROFILE_FORMAT_TREE,
+		}))
+		if err != nil {
+			httputil.Error(w, err)
+			return
+		}
+		tree, err := phlaremodel.UnmarshalTree(resp.Msg.Tree)
+		if err != nil {
+			httputil.Error(w, err)
+			return
+		}
+		export = func(dst io.Writer, format string) error {
+			return exportTopFunctions(dst, format, tree, limit)
+		}
+
+	case exportTableSeries:
+		step := timeline.CalcPointInterval(selectParams.Start, selectParams.End)
+		if s := params.Get("step"); s != "" {
+			if step, err = strconv.ParseFloat(s, 64); err != nil || step <= 0 {
+				httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid step %q", s)))
+				return
+			}
+		}
+		aggregation := typesv1.TimeSeriesAggregationType_TIME_SERIES_AGGREGATION_TYPE_SUM
+		if params.Get("aggregation") == "avg" {
+			aggregation = typesv1.TimeSeriesAggregationType_TIME_SERIES_AGGREGATION_TYPE_AVERAGE
+		}
+		groupBy := params["groupBy"]
+		resp, err := q.client.SelectSeries(req.Context(), connect.NewRequest(&querierv1.SelectSeriesRequest{
+			ProfileTypeID: selectParams.ProfileTypeID,
+			LabelSelector: selectParams.LabelSelector,
+			Start:         selectParams.Start,
+			End:           selectParams.End,
+			Step:          step,
+			GroupBy:       groupBy,
+			Aggregation:   &aggregation,
+		}))
+		if err != nil {
+			httputil.Error(w, err)
+			return
+		}
+		export = func(dst io.Writer, format string) error {
+			return exportSeries(dst, format, resp.Msg.Series, groupBy)
+		}
+
+	default:
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unsupported table %q, expected top or series", table)))
+		return
+	}
+
+	if format == exportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+	} else {
+		w.Header().Set("Content-Type", "application/jsonl")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table+"."+format))
+	// The response is streamed: once the status is sent,
+	// errors can only be reported by truncating it.
+	_ = export(w, format)
+}
+
+func exportTopFunctions(dst io.Writer, format string, tree *phlaremodel.Tree, limit int) error {
+	functions := tree.FunctionTotals()
+	if limit > 0 && len(functions) > limit {
+		functions = functions[:limit]
+	}
+
+	rw := newRowWriter(dst, format, []string{"function", "self", "total"})
+	for _, fn := range functions {
+		if err := rw.write(fn.Name, fn.Self, fn.Total); err != nil {
+			return err
+		}
+	}
+	return rw.flush()
+}
+
+func exportSeries(dst io.Writer, format string, series []*typesv1.Series, groupBy []string) error {
+	columns := make([]string, 0, len(groupBy)+2)
+	columns = append(columns, "timestamp")
+	columns = append(columns, groupBy...)
+	columns = append(columns, "value")
+	rw := newRowWriter(dst, format, columns)
+	row := make([]any, len(columns))
+	for _, s := range series {
+		for i, name := range groupBy {
+			row[i+1] = phlaremodel.Labels(s.Labels).Get(name)
+		}
+		for _, p := range s.Points {
+			row[0] = p.Timestamp
+			row[len(row)-1] = p.Value
+			if err := rw.write(row...); err != nil {
+				return err
+			}
+		}
+	}
+	return rw.flush()
+}
+
+// rowWriter writes the rows of a table with the given columns in the
+// CSV (with the header) or JSON lines (an object per row) format.
+type rowWriter struct {
+	columns []string
+	csv     *csv.Writer
+	jsonl   *bufio.Writer
+	record  []string
+}
+
+func newRowWriter(dst io.Writer, format string, columns []string) *rowWriter {
+	rw := &rowWriter{columns: columns}
+	if format == exportFormatJSONL {
+		rw.jsonl = bufio.NewWriter(dst)
+		return rw
+	}
+	rw.csv = csv.NewWriter(dst)
+	rw.record = make([]string, len(columns))
+	// The error, if any, is returned on flush.
+	_ = rw.csv.Write(columns)
+	return rw
+}
+
+func (rw *rowWriter) write(values ...any) error {
+	if rw.csv != nil {
+		for i, v := range values {
+			switch v := v.(type) {
+			case string:
+				rw.record[i] = v
+			case float64:
+				rw.record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				rw.record[i] = fmt.Sprint(v)
+			}
+		}
+		return rw.csv.Write(rw.record)
+	}
+	// The columns are written in order, therefore the
+	// object is not marshalled from a map.
+	_ = rw.jsonl.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			_ = rw.jsonl.WriteByte(',')
+		}
+		name, _ := json.Marshal(rw.columns[i])
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, _ = rw.jsonl.Write(name)
+		_ = rw.jsonl.WriteByte(':')
+		_, _ = rw.jsonl.Write(value)
+	}
+	_, err := rw.jsonl.WriteString("}\n")
+	return err
+}
+
+func (rw *rowWriter) flush() error {
+	if rw.csv != nil {
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+	return rw.jsonl.Flush()
+}
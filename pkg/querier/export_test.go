@@ -0,0 +1,85 @@
+package querier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockquerierv1connect"
+)
+
+const exportQuery = `/pyroscope/export?query=process_cpu:cpu:nanoseconds:cpu:nanoseconds{service_name="svc"}&from=1000&until=2000`
+
+func Test_Export_TopFunctions(t *testing.T) {
+	tree := new(phlaremodel.Tree)
+	tree.InsertStack(3, "main", "a", "b")
+	tree.InsertStack(2, "main", "b")
+	tree.InsertStack(1, "main", "a", "a")
+
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	client.On("SelectMergeStacktraces", mock.Anything, mock.Anything).
+		Return(connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{Tree: tree.Bytes(-1)}), nil)
+	handlers := NewHTTPHandlers(client)
+
+	rec := httptest.NewRecorder()
+	handlers.Export(rec, httptest.NewRequest(http.MethodGet, exportQuery, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "function,self,total\nb,5,5\na,1,4\nmain,0,6\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handlers.Export(rec, httptest.NewRequest(http.MethodGet, exportQuery+"&format=jsonl&limit=1", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `{"function":"b","self":5,"total":5}`+"\n", rec.Body.String())
+}
+
+func Test_Export_Series(t *testing.T) {
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	client.On("SelectSeries", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(*connect.Request[querierv1.SelectSeriesRequest]).Msg
+			assert.Equal(t, []string{"pod"}, req.GroupBy)
+			assert.Equal(t, float64(10), req.Step)
+		}).
+		Return(connect.NewResponse(&querierv1.SelectSeriesResponse{Series: []*typesv1.Series{
+			{
+				Labels: []*typesv1.LabelPair{{Name: "pod", Value: "a,1"}},
+				Points: []*typesv1.Point{{Timestamp: 1000, Value: 1.5}, {Timestamp: 2000, Value: 2}},
+			},
+			{
+				Labels: []*typesv1.LabelPair{{Name: "pod", Value: "b"}},
+				Points: []*typesv1.Point{{Timestamp: 1000, Value: 3}},
+			},
+		}}), nil)
+	handlers := NewHTTPHandlers(client)
+
+	rec := httptest.NewRecorder()
+	handlers.Export(rec, httptest.NewRequest(http.MethodGet, exportQuery+"&table=series&groupBy=pod&step=10", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "timestamp,pod,value\n1000,\"a,1\",1.5\n2000,\"a,1\",2\n1000,b,3\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handlers.Export(rec, httptest.NewRequest(http.MethodGet, exportQuery+"&table=series&groupBy=pod&step=10&format=jsonl", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `{"timestamp":1000,"pod":"a,1","value":1.5}
+{"timestamp":2000,"pod":"a,1","value":2}
+{"timestamp":1000,"pod":"b","value":3}
+`, rec.Body.String())
+}
+
+func Test_Export_InvalidRequest(t *testing.T) {
+	handlers := NewHTTPHandlers(mockquerierv1connect.NewMockQuerierServiceClient(t))
+	for _, params := range []string{"&format=xml", "&table=foo", "&limit=-1", "&table=series&step=0"} {
+		rec := httptest.NewRecorder()
+		handlers.Export(rec, httptest.NewRequest(http.MethodGet, exportQuery+params, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, params)
+	}
+}
@@ -135,6 +135,10 @@ func (q *QueryHandlers) Render(w http.ResponseWriter, req *http.Request) {
 	}
 
 	groupBy := req.URL.Query()["groupBy"]
+	var groupByLimit *int64
+	if v, err := strconv.ParseInt(req.URL.Query().Get("groupByLimit"), 10, 64); err == nil && v > 0 {
+		groupByLimit = &v
+	}
 	var aggregation typesv1.TimeSeriesAggregationType
 	if req.URL.Query().Has("aggregation") {
 		aggregationParam := req.URL.Query().Get("aggregation")
@@ -199,6 +203,8 @@ func (q *QueryHandlers) Render(w http.ResponseWriter, req *http.Request) {
 				Step:          timelineStep,
 				GroupBy:       groupBy,
 				Aggregation:   &aggregation,
+				Limit:         groupByLimit,
+				IncludeOther:  groupByLimit != nil,
 			}))
 
 		return err
@@ -270,7 +276,7 @@ func parseSelectProfilesRequest(fieldNames renderRequestFieldNames, req *http.Re
 			until: "until",
 		}
 	}
-	selector, ptype, err := parseQuery(fieldNames.query, req)
+	selector, ptype, err := ParseQuery(fieldNames.query, req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -300,7 +306,9 @@ func parseSelectProfilesRequest(fieldNames renderRequestFieldNames, req *http.Re
 	return p, ptype, nil
 }
 
-func parseQuery(fieldName string, req *http.Request) (string, *typesv1.ProfileType, error) {
+// ParseQuery parses the profile type and the label selector of the
+// query specified in the form field, e.g., "process_cpu:cpu:nanoseconds:cpu:nanoseconds{service_name="foo"}".
+func ParseQuery(fieldName string, req *http.Request) (string, *typesv1.ProfileType, error) {
 	q := req.Form.Get(fieldName)
 	if q == "" {
 		return "", nil, fmt.Errorf("'%s' is required", fieldName)
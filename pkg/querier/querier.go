@@ -469,6 +469,31 @@ func (q *Querier) Series(ctx context.Context, req *connect.Request[querierv1.Ser
 	}), nil
 }
 
+// Explain is served by the query frontend and is never forwarded to queriers.
+func (q *Querier) Explain(context.Context, *connect.Request[querierv1.ExplainRequest]) (*connect.Response[querierv1.ExplainResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("explain is handled by the query frontend"))
+}
+
+// SelectAllocationSizes is only served by the query backend.
+func (q *Querier) SelectAllocationSizes(context.Context, *connect.Request[querierv1.SelectAllocationSizesRequest]) (*connect.Response[querierv1.SelectAllocationSizesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("allocation sizes are only available in the v2 storage"))
+}
+
+// SelectProfileTypesSeries is handled by the query frontend.
+func (q *Querier) SelectProfileTypesSeries(context.Context, *connect.Request[querierv1.SelectProfileTypesSeriesRequest]) (*connect.Response[querierv1.SelectProfileTypesSeriesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("profile types series are handled by the query frontend"))
+}
+
+// ServiceHierarchy is only served by the query frontend, from the metastore index.
+func (q *Querier) ServiceHierarchy(context.Context, *connect.Request[querierv1.ServiceHierarchyRequest]) (*connect.Response[querierv1.ServiceHierarchyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service hierarchy is only available in the v2 storage"))
+}
+
+// SelectServiceGraph is only served by the query backend.
+func (q *Querier) SelectServiceGraph(context.Context, *connect.Request[querierv1.SelectServiceGraphRequest]) (*connect.Response[querierv1.SelectServiceGraphResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service graph is only available in the v2 storage"))
+}
+
 // FIXME(kolesnikovae): The method is never used and should be removed.
 func (q *Querier) Diff(ctx context.Context, req *connect.Request[querierv1.DiffRequest]) (*connect.Response[querierv1.DiffResponse], error) {
 	sp, ctx := opentracing.StartSpanFromContext(ctx, "Diff")
@@ -602,6 +627,9 @@ func (q *Querier) SelectMergeStacktraces(ctx context.Context, req *connect.Reque
 	switch req.Msg.Format {
 	default:
 		resp.Flamegraph = phlaremodel.NewFlameGraph(t, req.Msg.GetMaxNodes())
+		if req.Msg.ClassifyFrames {
+			phlaremodel.ClassifyFlameGraphFrames(resp.Flamegraph)
+		}
 	case querierv1.ProfileFormat_PROFILE_FORMAT_TREE:
 		resp.Tree = t.Bytes(req.Msg.GetMaxNodes())
 	}
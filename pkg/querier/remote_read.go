@@ -0,0 +1,199 @@
+package querier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const (
+	// Profiles are usually collected every 15 seconds:
+	// finer resolution does not make sense.
+	remoteReadMinStep = 15 * time.Second
+	// The step is increased for long time ranges, to bound the
+	// number of points per series, like the Prometheus query API.
+	remoteReadMaxPoints = 11000
+
+	maxRemoteReadRequestSize = 16 << 20
+)
+
+// RemoteRead implements the Prometheus remote read protocol: it allows
+// Prometheus (or Mimir) to query the time series derived from the profiles
+// on demand.
+//
+// The metric name is the profile type ID, e.g.,
+// process_cpu:cpu:nanoseconds:cpu:nanoseconds; the value at a point is
+// the sum of the profile values within the step interval. The series are
+// grouped by the service name, and by the labels the query matchers refer
+// to. Only the SAMPLES response type is supported.
+func (q *QueryHandlers) RemoteRead(w http.ResponseWriter, req *http.Request) {
+	compressed, err := io.ReadAll(io.LimitReader(req.Body, maxRemoteReadRequestSize))
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	body, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	var readRequest prompb.ReadRequest
+	if err = readRequest.Unmarshal(body); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	if len(readRequest.AcceptedResponseTypes) > 0 && !slices.Contains(readRequest.AcceptedResponseTypes, prompb.ReadRequest_SAMPLES) {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument,
+			fmt.Errorf("none of the accepted response types is supported: %v", readRequest.AcceptedResponseTypes)))
+		return
+	}
+
+	resp := &prompb.ReadResponse{Results: make([]*prompb.QueryResult, len(readRequest.Queries))}
+	for i, query := range readRequest.Queries {
+		series, err := q.remoteReadQuery(req.Context(), query)
+		if err != nil {
+			httputil.Error(w, err)
+			return
+		}
+		resp.Results[i] = &prompb.QueryResult{Timeseries: series}
+	}
+
+	data, err := resp.Marshal()
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Encoding", "snappy")
+	_, _ = w.Write(snappy.Encode(nil, data))
+}
+
+func (q *QueryHandlers) remoteReadQuery(ctx context.Context, query *prompb.Query) ([]*prompb.TimeSeries, error) {
+	matchers, err := fromLabelMatchers(query.Matchers)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	var nameMatchers, selector []*labels.Matcher
+	groupBy := []string{phlaremodel.LabelNameServiceName}
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			nameMatchers = append(nameMatchers, m)
+			continue
+		}
+		selector = append(selector, m)
+		if !slices.Contains(groupBy, m.Name) {
+			groupBy = append(groupBy, m.Name)
+		}
+	}
+	if len(nameMatchers) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("query must select the metric name (profile type)"))
+	}
+
+	profileTypes, err := q.client.ProfileTypes(ctx, connect.NewRequest(&querierv1.ProfileTypesRequest{
+		Start: query.StartTimestampMs,
+		End:   query.EndTimestampMs,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	step := remoteReadStep(query)
+	var result []*prompb.TimeSeries
+	for _, t := range profileTypes.Msg.ProfileTypes {
+		if !matchesAll(nameMatchers, t.ID) {
+			continue
+		}
+		resp, err := q.client.SelectSeries(ctx, connect.NewRequest(&querierv1.SelectSeriesRequest{
+			ProfileTypeID: t.ID,
+			LabelSelector: convertMatchersToString(selector),
+			Start:         query.StartTimestampMs,
+			End:           query.EndTimestampMs,
+			GroupBy:       groupBy,
+			Step:          step.Seconds(),
+			Aggregation:   typesv1.TimeSeriesAggregationType_TIME_SERIES_AGGREGATION_TYPE_SUM.Enum(),
+		}))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Msg.Series {
+			result = append(result, toTimeSeries(t.ID, s))
+		}
+	}
+	return result, nil
+}
+
+func remoteReadStep(query *prompb.Query) time.Duration {
+	step := remoteReadMinStep
+	if query.Hints != nil {
+		step = max(step, time.Duration(query.Hints.StepMs)*time.Millisecond)
+	}
+	timeRange := time.Duration(query.EndTimestampMs-query.StartTimestampMs) * time.Millisecond
+	if minStep := timeRange / remoteReadMaxPoints; step < minStep {
+		step = minStep.Truncate(time.Second) + time.Second
+	}
+	return step
+}
+
+func matchesAll(matchers []*labels.Matcher, value string) bool {
+	for _, m := range matchers {
+		if !m.Matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func toTimeSeries(name string, s *typesv1.Series) *prompb.TimeSeries {
+	ts := &prompb.TimeSeries{
+		Labels:  make([]prompb.Label, 0, len(s.Labels)+1),
+		Samples: make([]prompb.Sample, 0, len(s.Points)),
+	}
+	ts.Labels = append(ts.Labels, prompb.Label{Name: labels.MetricName, Value: name})
+	for _, l := range s.Labels {
+		ts.Labels = append(ts.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+	}
+	sort.Slice(ts.Labels, func(i, j int) bool { return ts.Labels[i].Name < ts.Labels[j].Name })
+	for _, p := range s.Points {
+		ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: p.Timestamp, Value: p.Value})
+	}
+	return ts
+}
+
+func fromLabelMatchers(matchers []*prompb.LabelMatcher) ([]*labels.Matcher, error) {
+	result := make([]*labels.Matcher, 0, len(matchers))
+	for _, m := range matchers {
+		var t labels.MatchType
+		switch m.Type {
+		case prompb.LabelMatcher_EQ:
+			t = labels.MatchEqual
+		case prompb.LabelMatcher_NEQ:
+			t = labels.MatchNotEqual
+		case prompb.LabelMatcher_RE:
+			t = labels.MatchRegexp
+		case prompb.LabelMatcher_NRE:
+			t = labels.MatchNotRegexp
+		default:
+			return nil, fmt.Errorf("invalid matcher type: %v", m.Type)
+		}
+		matcher, err := labels.NewMatcher(t, m.Name, m.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
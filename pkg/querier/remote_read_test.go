@@ -0,0 +1,113 @@
+package querier
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockquerierv1connect"
+)
+
+func Test_RemoteRead(t *testing.T) {
+	const (
+		cpu    = "process_cpu:cpu:nanoseconds:cpu:nanoseconds"
+		memory = "memory:alloc_space:bytes:space:bytes"
+	)
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	client.On("ProfileTypes", mock.Anything, mock.Anything).
+		Return(connect.NewResponse(&querierv1.ProfileTypesResponse{ProfileTypes: []*typesv1.ProfileType{{ID: cpu}, {ID: memory}}}), nil)
+	client.On("SelectSeries", mock.Anything, mock.Anything).
+		Once().
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(*connect.Request[querierv1.SelectSeriesRequest]).Msg
+			assert.Equal(t, cpu, req.ProfileTypeID)
+			assert.Equal(t, `{env="prod"}`, req.LabelSelector)
+			assert.Equal(t, []string{"service_name", "env"}, req.GroupBy)
+			assert.Equal(t, int64(1000), req.Start)
+			assert.Equal(t, int64(61000), req.End)
+			assert.Equal(t, float64(30), req.Step)
+		}).
+		Return(connect.NewResponse(&querierv1.SelectSeriesResponse{Series: []*typesv1.Series{{
+			Labels: []*typesv1.LabelPair{{Name: "service_name", Value: "svc"}, {Name: "env", Value: "prod"}},
+			Points: []*typesv1.Point{{Timestamp: 31000, Value: 1}, {Timestamp: 61000, Value: 2}},
+		}}}), nil)
+
+	readRequest := &prompb.ReadRequest{Queries: []*prompb.Query{{
+		StartTimestampMs: 1000,
+		EndTimestampMs:   61000,
+		Matchers: []*prompb.LabelMatcher{
+			{Type: prompb.LabelMatcher_RE, Name: "__name__", Value: "process_cpu:.*"},
+			{Type: prompb.LabelMatcher_EQ, Name: "env", Value: "prod"},
+		},
+		Hints: &prompb.ReadHints{StepMs: 30000},
+	}}}
+	data, err := readRequest.Marshal()
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	NewHTTPHandlers(client).RemoteRead(rec, httptest.NewRequest(http.MethodPost, "/prometheus/api/v1/read", bytes.NewReader(snappy.Encode(nil, data))))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "snappy", rec.Header().Get("Content-Encoding"))
+
+	body, err := snappy.Decode(nil, rec.Body.Bytes())
+	require.NoError(t, err)
+	var resp prompb.ReadResponse
+	require.NoError(t, resp.Unmarshal(body))
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, []*prompb.TimeSeries{{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: cpu},
+			{Name: "env", Value: "prod"},
+			{Name: "service_name", Value: "svc"},
+		},
+		Samples: []prompb.Sample{{Timestamp: 31000, Value: 1}, {Timestamp: 61000, Value: 2}},
+	}}, resp.Results[0].Timeseries)
+}
+
+func Test_RemoteRead_InvalidRequest(t *testing.T) {
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	handlers := NewHTTPHandlers(client)
+	for _, tc := range []struct {
+		name string
+		body []byte
+	}{
+		{name: "not compressed", body: []byte("foo")},
+		{name: "no metric name", body: snappyRequest(t, &prompb.ReadRequest{Queries: []*prompb.Query{{
+			Matchers: []*prompb.LabelMatcher{{Type: prompb.LabelMatcher_EQ, Name: "service_name", Value: "svc"}},
+		}}})},
+		{name: "streamed response", body: snappyRequest(t, &prompb.ReadRequest{
+			AcceptedResponseTypes: []prompb.ReadRequest_ResponseType{prompb.ReadRequest_STREAMED_XOR_CHUNKS},
+		})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handlers.RemoteRead(rec, httptest.NewRequest(http.MethodPost, "/prometheus/api/v1/read", bytes.NewReader(tc.body)))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func Test_remoteReadStep(t *testing.T) {
+	assert.Equal(t, remoteReadMinStep, remoteReadStep(&prompb.Query{EndTimestampMs: time.Hour.Milliseconds()}))
+	assert.Equal(t, time.Minute, remoteReadStep(&prompb.Query{Hints: &prompb.ReadHints{StepMs: time.Minute.Milliseconds()}}))
+	step := remoteReadStep(&prompb.Query{EndTimestampMs: (30 * 24 * time.Hour).Milliseconds()})
+	assert.LessOrEqual(t, int64(30*24*time.Hour/step), int64(remoteReadMaxPoints))
+}
+
+func snappyRequest(t *testing.T, req *prompb.ReadRequest) []byte {
+	t.Helper()
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	return snappy.Encode(nil, data)
+}
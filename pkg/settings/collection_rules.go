@@ -0,0 +1,127 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+)
+
+func NewCollectionRules(store CollectionRulesStore, logger log.Logger) *CollectionRules {
+	return &CollectionRules{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// CollectionRules serves the collection rules agents poll to find out what
+// to collect from their targets.
+type CollectionRules struct {
+	store  CollectionRulesStore
+	logger log.Logger
+}
+
+func (cr *CollectionRules) GetCollectionRules(ctx context.Context, req *connect.Request[settingsv1.GetCollectionRulesRequest]) (*connect.Response[settingsv1.GetCollectionRulesResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	rules, generation, err := cr.store.Get(ctx, tenantID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	if req.Msg.KnownGeneration != 0 && req.Msg.KnownGeneration == generation {
+		return connect.NewResponse(&settingsv1.GetCollectionRulesResponse{
+			Generation:  generation,
+			NotModified: true,
+		}), nil
+	}
+
+	return connect.NewResponse(&settingsv1.GetCollectionRulesResponse{
+		Rules:      rules,
+		Generation: generation,
+	}), nil
+}
+
+func (cr *CollectionRules) UpsertCollectionRule(ctx context.Context, req *connect.Request[settingsv1.UpsertCollectionRuleRequest]) (*connect.Response[settingsv1.UpsertCollectionRuleResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if req.Msg == nil || req.Msg.Rule == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no collection rule provided"))
+	}
+
+	if err = validateCollectionRule(req.Msg.Rule); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if req.Msg.Rule.ModifiedAt <= 0 {
+		req.Msg.Rule.ModifiedAt = time.Now().UnixMilli()
+	}
+
+	rule, err := cr.store.Upsert(ctx, tenantID, req.Msg.Rule)
+	if err != nil {
+		if errors.Is(err, oldSettingErr) {
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&settingsv1.UpsertCollectionRuleResponse{
+		Rule: rule,
+	}), nil
+}
+
+func (cr *CollectionRules) DeleteCollectionRule(ctx context.Context, req *connect.Request[settingsv1.DeleteCollectionRuleRequest]) (*connect.Response[settingsv1.DeleteCollectionRuleResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if req.Msg.Name == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("collection rule name is required"))
+	}
+
+	err = cr.store.Delete(ctx, tenantID, req.Msg.Name)
+	if err != nil {
+		if errors.Is(err, collectionRuleNotFoundErr) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&settingsv1.DeleteCollectionRuleResponse{}), nil
+}
+
+func validateCollectionRule(rule *settingsv1.CollectionRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("collection rule name is required")
+	}
+	for _, selector := range rule.TargetSelectors {
+		if _, err := parser.ParseMetricSelector(selector); err != nil {
+			return fmt.Errorf("invalid target selector %q: %w", selector, err)
+		}
+	}
+	seen := make(map[string]struct{}, len(rule.ProfileTypes))
+	for _, pt := range rule.ProfileTypes {
+		if pt.Name == "" {
+			return fmt.Errorf("profile type name is required")
+		}
+		if _, ok := seen[pt.Name]; ok {
+			return fmt.Errorf("duplicate profile type %q", pt.Name)
+		}
+		seen[pt.Name] = struct{}{}
+	}
+	return nil
+}
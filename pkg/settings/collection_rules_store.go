@@ -0,0 +1,185 @@
+package settings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+)
+
+var (
+	collectionRuleNotFoundErr = errors.New("collection rule not found")
+	collectionRulesFilename   = "tenant_collection_rules.json"
+)
+
+type CollectionRulesStore interface {
+	// Get collection rules for a tenant, and their generation.
+	Get(ctx context.Context, tenantID string) ([]*settingsv1.CollectionRule, int64, error)
+
+	// Upsert a collection rule for a tenant.
+	Upsert(ctx context.Context, tenantID string, rule *settingsv1.CollectionRule) (*settingsv1.CollectionRule, error)
+
+	// Delete a collection rule of a tenant.
+	Delete(ctx context.Context, tenantID string, name string) error
+}
+
+// NewMemoryCollectionRulesStore will create a collection rules store with an
+// in-memory objstore bucket.
+func NewMemoryCollectionRulesStore() (CollectionRulesStore, error) {
+	return NewBucketCollectionRulesStore(objstore.NewInMemBucket())
+}
+
+// NewBucketCollectionRulesStore will create a collection rules store with an
+// objstore bucket.
+func NewBucketCollectionRulesStore(bucket objstore.Bucket) (CollectionRulesStore, error) {
+	store := &bucketCollectionRulesStore{
+		store:  make(map[string]*tenantCollectionRules),
+		bucket: bucket,
+	}
+
+	return store, nil
+}
+
+type tenantCollectionRules struct {
+	Rules      map[string]*settingsv1.CollectionRule `json:"rules"`
+	Generation int64                                 `json:"generation"`
+}
+
+type bucketCollectionRulesStore struct {
+	rw sync.Mutex
+
+	// store is indexed by tenant id.
+	store map[string]*tenantCollectionRules
+
+	// bucket is an object store bucket.
+	bucket objstore.Bucket
+}
+
+func (s *bucketCollectionRulesStore) Get(ctx context.Context, tenantID string) ([]*settingsv1.CollectionRule, int64, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tenantRules, ok := s.store[tenantID]
+	if !ok {
+		return []*settingsv1.CollectionRule{}, 0, nil
+	}
+
+	rules := make([]*settingsv1.CollectionRule, 0, len(tenantRules.Rules))
+	for _, rule := range tenantRules.Rules {
+		rules = append(rules, rule)
+	}
+
+	slices.SortFunc(rules, func(a, b *settingsv1.CollectionRule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return rules, tenantRules.Generation, nil
+}
+
+func (s *bucketCollectionRulesStore) Upsert(ctx context.Context, tenantID string, rule *settingsv1.CollectionRule) (*settingsv1.CollectionRule, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantRules, ok := s.store[tenantID]
+	if !ok {
+		tenantRules = &tenantCollectionRules{
+			Rules: make(map[string]*settingsv1.CollectionRule, 1),
+		}
+		s.store[tenantID] = tenantRules
+	}
+
+	oldRule, ok := tenantRules.Rules[rule.Name]
+	if ok && oldRule.ModifiedAt > rule.ModifiedAt {
+		return nil, errors.Wrapf(oldSettingErr, "failed to update %s", rule.Name)
+	}
+	tenantRules.Rules[rule.Name] = rule
+	tenantRules.nextGeneration(rule.ModifiedAt)
+
+	err = s.unsafeFlush(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *bucketCollectionRulesStore) Delete(ctx context.Context, tenantID string, name string) error {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return err
+	}
+
+	tenantRules, ok := s.store[tenantID]
+	if !ok {
+		return errors.Wrapf(collectionRuleNotFoundErr, "failed to delete %s", name)
+	}
+	if _, ok = tenantRules.Rules[name]; !ok {
+		return errors.Wrapf(collectionRuleNotFoundErr, "failed to delete %s", name)
+	}
+	delete(tenantRules.Rules, name)
+	tenantRules.nextGeneration(time.Now().UnixMilli())
+
+	return s.unsafeFlush(ctx)
+}
+
+// nextGeneration advances the generation of the tenant rules. The generation
+// is a timestamp, which is guaranteed to increase with every update.
+func (r *tenantCollectionRules) nextGeneration(now int64) {
+	r.Generation = max(r.Generation+1, now)
+}
+
+// unsafeFlush will flush the store to object storage. This is not thread-safe,
+// the store's write mutex should be acquired first.
+func (s *bucketCollectionRulesStore) unsafeFlush(ctx context.Context) error {
+	data, err := json.Marshal(s.store)
+	if err != nil {
+		return err
+	}
+
+	return s.bucket.Upload(ctx, collectionRulesFilename, bytes.NewReader(data))
+}
+
+// unsafeLoad will read the store in object storage into memory, if it exists.
+// This is not thread-safe, the store's write mutex should be acquired first.
+func (s *bucketCollectionRulesStore) unsafeLoad(ctx context.Context) error {
+	reader, err := s.bucket.Get(ctx, collectionRulesFilename)
+	if err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			// It is OK if we don't find the file.
+			return nil
+		}
+		return err
+	}
+	defer reader.Close()
+
+	// Rules may have been deleted by other replicas, therefore the
+	// store is replaced rather than merged with the stored object.
+	store := make(map[string]*tenantCollectionRules)
+	err = json.NewDecoder(reader).Decode(&store)
+	if err != nil {
+		return err
+	}
+
+	s.store = store
+	return nil
+}
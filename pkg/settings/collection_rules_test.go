@@ -0,0 +1,128 @@
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func TestCollectionRules(t *testing.T) {
+	const tenantID = "1234"
+	ctx := tenant.InjectTenantID(context.Background(), tenantID)
+
+	newCollectionRules := func(t *testing.T) *CollectionRules {
+		store, err := NewMemoryCollectionRulesStore()
+		require.NoError(t, err)
+		return NewCollectionRules(store, log.NewNopLogger())
+	}
+
+	rule := func(name string, modifiedAt int64) *settingsv1.CollectionRule {
+		return &settingsv1.CollectionRule{
+			Name:            name,
+			TargetSelectors: []string{`{service_name="foo"}`},
+			ProfileTypes: []*settingsv1.CollectionProfileType{
+				{Name: "cpu", Enabled: true, SampleRate: 97},
+				{Name: "memory", Enabled: false},
+			},
+			ModifiedAt: modifiedAt,
+		}
+	}
+
+	upsert := func(cr *CollectionRules, ctx context.Context, r *settingsv1.CollectionRule) error {
+		_, err := cr.UpsertCollectionRule(ctx, connect.NewRequest(&settingsv1.UpsertCollectionRuleRequest{Rule: r}))
+		return err
+	}
+
+	get := func(cr *CollectionRules, ctx context.Context, known int64) *settingsv1.GetCollectionRulesResponse {
+		resp, err := cr.GetCollectionRules(ctx, connect.NewRequest(&settingsv1.GetCollectionRulesRequest{KnownGeneration: known}))
+		require.NoError(t, err)
+		return resp.Msg
+	}
+
+	t.Run("no rules", func(t *testing.T) {
+		cr := newCollectionRules(t)
+		got := get(cr, ctx, 0)
+		require.Empty(t, got.Rules)
+		require.Zero(t, got.Generation)
+		require.False(t, got.NotModified)
+	})
+
+	t.Run("upsert and get rules", func(t *testing.T) {
+		cr := newCollectionRules(t)
+		require.NoError(t, upsert(cr, ctx, rule("b", 100)))
+		require.NoError(t, upsert(cr, ctx, rule("a", 100)))
+
+		got := get(cr, ctx, 0)
+		require.Equal(t, []*settingsv1.CollectionRule{rule("a", 100), rule("b", 100)}, got.Rules)
+		require.NotZero(t, got.Generation)
+
+		// Rules of other tenants are not visible.
+		other := get(cr, tenant.InjectTenantID(context.Background(), "other"), 0)
+		require.Empty(t, other.Rules)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		cr := newCollectionRules(t)
+		require.NoError(t, upsert(cr, ctx, rule("a", 100)))
+		generation := get(cr, ctx, 0).Generation
+
+		got := get(cr, ctx, generation)
+		require.True(t, got.NotModified)
+		require.Empty(t, got.Rules)
+		require.Equal(t, generation, got.Generation)
+
+		// Any update changes the generation.
+		require.NoError(t, upsert(cr, ctx, rule("a", 100)))
+		got = get(cr, ctx, generation)
+		require.False(t, got.NotModified)
+		require.Len(t, got.Rules, 1)
+		require.Greater(t, got.Generation, generation)
+	})
+
+	t.Run("newer update already written", func(t *testing.T) {
+		cr := newCollectionRules(t)
+		require.NoError(t, upsert(cr, ctx, rule("a", 100)))
+		err := upsert(cr, ctx, rule("a", 99))
+		require.EqualError(t, err, "already_exists: failed to update a: newer update already written")
+	})
+
+	t.Run("delete rule", func(t *testing.T) {
+		cr := newCollectionRules(t)
+		require.NoError(t, upsert(cr, ctx, rule("a", 100)))
+		generation := get(cr, ctx, 0).Generation
+
+		_, err := cr.DeleteCollectionRule(ctx, connect.NewRequest(&settingsv1.DeleteCollectionRuleRequest{Name: "a"}))
+		require.NoError(t, err)
+		got := get(cr, ctx, generation)
+		require.Empty(t, got.Rules)
+		require.False(t, got.NotModified)
+
+		_, err = cr.DeleteCollectionRule(ctx, connect.NewRequest(&settingsv1.DeleteCollectionRuleRequest{Name: "a"}))
+		require.EqualError(t, err, "not_found: failed to delete a: collection rule not found")
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		cr := newCollectionRules(t)
+		for _, r := range []*settingsv1.CollectionRule{
+			{},
+			{Name: "a", TargetSelectors: []string{`{service_name=}`}},
+			{Name: "a", ProfileTypes: []*settingsv1.CollectionProfileType{{}}},
+			{Name: "a", ProfileTypes: []*settingsv1.CollectionProfileType{{Name: "cpu"}, {Name: "cpu"}}},
+		} {
+			err := upsert(cr, ctx, r)
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		}
+	})
+
+	t.Run("missing tenant id", func(t *testing.T) {
+		cr := newCollectionRules(t)
+		_, err := cr.GetCollectionRules(context.Background(), connect.NewRequest(&settingsv1.GetCollectionRulesRequest{}))
+		require.EqualError(t, err, "invalid_argument: no org id")
+	})
+}
@@ -0,0 +1,179 @@
+package settings
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/tenant"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+)
+
+func NewSavedViews(store SavedViewsStore, logger log.Logger) *SavedViews {
+	return &SavedViews{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// SavedViews serves the named query views users save to share
+// canonical views of their services.
+type SavedViews struct {
+	store  SavedViewsStore
+	logger log.Logger
+}
+
+func (sv *SavedViews) ListSavedViews(ctx context.Context, req *connect.Request[settingsv1.ListSavedViewsRequest]) (*connect.Response[settingsv1.ListSavedViewsResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	views, err := sv.store.List(ctx, tenantID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	userID := req.Header().Get(UserHeaderName)
+	visible := make([]*settingsv1.SavedView, 0, len(views))
+	for _, view := range views {
+		if isSavedViewVisible(view, userID) {
+			visible = append(visible, view)
+		}
+	}
+
+	return connect.NewResponse(&settingsv1.ListSavedViewsResponse{
+		Views: visible,
+	}), nil
+}
+
+func (sv *SavedViews) GetSavedView(ctx context.Context, req *connect.Request[settingsv1.GetSavedViewRequest]) (*connect.Response[settingsv1.GetSavedViewResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	view, err := sv.find(ctx, tenantID, req.Msg.Id, req.Header().Get(UserHeaderName))
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&settingsv1.GetSavedViewResponse{
+		View: view,
+	}), nil
+}
+
+func (sv *SavedViews) UpsertSavedView(ctx context.Context, req *connect.Request[settingsv1.UpsertSavedViewRequest]) (*connect.Response[settingsv1.UpsertSavedViewResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if req.Msg == nil || req.Msg.View == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no saved view provided"))
+	}
+
+	view := req.Msg.View
+	if err = validateSavedView(view); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	// The owner is never taken from the request message: a private
+	// view belongs to the user the request is authenticated for.
+	userID := req.Header().Get(UserHeaderName)
+	view.Owner = ""
+	if req.Msg.Private {
+		if _, view.Owner, err = tenantAndUserID(ctx, req.Header()); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if view.Id == "" {
+		view.Id = ulid.MustNew(ulid.Timestamp(now), rand.Reader).String()
+		view.CreatedAt = now.UnixMilli()
+	} else if _, err = sv.find(ctx, tenantID, view.Id, userID); err != nil {
+		return nil, err
+	}
+	if view.ModifiedAt <= 0 {
+		view.ModifiedAt = now.UnixMilli()
+	}
+
+	view, err = sv.store.Upsert(ctx, tenantID, view)
+	if err != nil {
+		if errors.Is(err, oldSettingErr) || errors.Is(err, savedViewNameTakenErr) {
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&settingsv1.UpsertSavedViewResponse{
+		View: view,
+	}), nil
+}
+
+func (sv *SavedViews) DeleteSavedView(ctx context.Context, req *connect.Request[settingsv1.DeleteSavedViewRequest]) (*connect.Response[settingsv1.DeleteSavedViewResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if _, err = sv.find(ctx, tenantID, req.Msg.Id, req.Header().Get(UserHeaderName)); err != nil {
+		return nil, err
+	}
+
+	err = sv.store.Delete(ctx, tenantID, req.Msg.Id)
+	if err != nil {
+		if errors.Is(err, savedViewNotFoundErr) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&settingsv1.DeleteSavedViewResponse{}), nil
+}
+
+// find returns the view with the given ID, if it is visible to the user.
+// Private views of other users are reported as not found.
+func (sv *SavedViews) find(ctx context.Context, tenantID, id, userID string) (*settingsv1.SavedView, error) {
+	if id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("saved view id is required"))
+	}
+
+	views, err := sv.store.List(ctx, tenantID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	for _, view := range views {
+		if view.Id == id && isSavedViewVisible(view, userID) {
+			return view, nil
+		}
+	}
+	return nil, connect.NewError(connect.CodeNotFound, errors.Wrapf(savedViewNotFoundErr, "failed to find %s", id))
+}
+
+// isSavedViewVisible reports whether the view is shared with the tenant,
+// or owned by the user. Private views are not visible without the user.
+func isSavedViewVisible(view *settingsv1.SavedView, userID string) bool {
+	return view.Owner == "" || (userID != "" && view.Owner == userID)
+}
+
+func validateSavedView(view *settingsv1.SavedView) error {
+	if view.Name == "" {
+		return fmt.Errorf("saved view name is required")
+	}
+	if view.LabelSelector != "" {
+		if _, err := parser.ParseMetricSelector(view.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label selector %q: %w", view.LabelSelector, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package settings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+)
+
+var (
+	savedViewNotFoundErr  = errors.New("saved view not found")
+	savedViewNameTakenErr = errors.New("saved view name is already taken")
+	savedViewsFilename    = "tenant_saved_views.json"
+)
+
+type SavedViewsStore interface {
+	// List all saved views of a tenant.
+	List(ctx context.Context, tenantID string) ([]*settingsv1.SavedView, error)
+
+	// Upsert a saved view of a tenant.
+	Upsert(ctx context.Context, tenantID string, view *settingsv1.SavedView) (*settingsv1.SavedView, error)
+
+	// Delete a saved view of a tenant.
+	Delete(ctx context.Context, tenantID string, id string) error
+}
+
+// NewMemorySavedViewsStore will create a saved views store with an in-memory
+// objstore bucket.
+func NewMemorySavedViewsStore() (SavedViewsStore, error) {
+	return NewBucketSavedViewsStore(objstore.NewInMemBucket())
+}
+
+// NewBucketSavedViewsStore will create a saved views store with an objstore
+// bucket.
+func NewBucketSavedViewsStore(bucket objstore.Bucket) (SavedViewsStore, error) {
+	store := &bucketSavedViewsStore{
+		store:  make(map[string]map[string]*settingsv1.SavedView),
+		bucket: bucket,
+	}
+
+	return store, nil
+}
+
+type bucketSavedViewsStore struct {
+	rw sync.Mutex
+
+	// store is indexed by tenant id, then by view id.
+	store map[string]map[string]*settingsv1.SavedView
+
+	// bucket is an object store bucket.
+	bucket objstore.Bucket
+}
+
+func (s *bucketSavedViewsStore) List(ctx context.Context, tenantID string) ([]*settingsv1.SavedView, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantViews := s.store[tenantID]
+	views := make([]*settingsv1.SavedView, 0, len(tenantViews))
+	for _, view := range tenantViews {
+		views = append(views, view)
+	}
+
+	slices.SortFunc(views, func(a, b *settingsv1.SavedView) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return views, nil
+}
+
+func (s *bucketSavedViewsStore) Upsert(ctx context.Context, tenantID string, view *settingsv1.SavedView) (*settingsv1.SavedView, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantViews, ok := s.store[tenantID]
+	if !ok {
+		tenantViews = make(map[string]*settingsv1.SavedView, 1)
+		s.store[tenantID] = tenantViews
+	}
+
+	for _, v := range tenantViews {
+		if v.Id != view.Id && v.Owner == view.Owner && v.Name == view.Name {
+			return nil, errors.Wrapf(savedViewNameTakenErr, "failed to update %s", view.Name)
+		}
+	}
+
+	oldView, ok := tenantViews[view.Id]
+	if ok {
+		if oldView.ModifiedAt > view.ModifiedAt {
+			return nil, errors.Wrapf(oldSettingErr, "failed to update %s", view.Name)
+		}
+		// Ownership and creation time can't be changed.
+		view.Owner = oldView.Owner
+		view.CreatedAt = oldView.CreatedAt
+	}
+	tenantViews[view.Id] = view
+
+	err = s.unsafeFlush(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (s *bucketSavedViewsStore) Delete(ctx context.Context, tenantID string, id string) error {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return err
+	}
+
+	tenantViews, ok := s.store[tenantID]
+	if !ok {
+		return errors.Wrapf(savedViewNotFoundErr, "failed to delete %s", id)
+	}
+	if _, ok = tenantViews[id]; !ok {
+		return errors.Wrapf(savedViewNotFoundErr, "failed to delete %s", id)
+	}
+	delete(tenantViews, id)
+
+	return s.unsafeFlush(ctx)
+}
+
+// unsafeFlush will flush the store to object storage. This is not thread-safe,
+// the store's write mutex should be acquired first.
+func (s *bucketSavedViewsStore) unsafeFlush(ctx context.Context) error {
+	data, err := json.Marshal(s.store)
+	if err != nil {
+		return err
+	}
+
+	return s.bucket.Upload(ctx, savedViewsFilename, bytes.NewReader(data))
+}
+
+// unsafeLoad will read the store in object storage into memory, if it exists.
+// This is not thread-safe, the store's write mutex should be acquired first.
+func (s *bucketSavedViewsStore) unsafeLoad(ctx context.Context) error {
+	reader, err := s.bucket.Get(ctx, savedViewsFilename)
+	if err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			// It is OK if we don't find the file.
+			return nil
+		}
+		return err
+	}
+	defer reader.Close()
+
+	// Views may have been deleted by other replicas, therefore the
+	// store is replaced rather than merged with the stored object.
+	store := make(map[string]map[string]*settingsv1.SavedView)
+	err = json.NewDecoder(reader).Decode(&store)
+	if err != nil {
+		return err
+	}
+
+	s.store = store
+	return nil
+}
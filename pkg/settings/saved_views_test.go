@@ -0,0 +1,223 @@
+package settings
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func TestSavedViews(t *testing.T) {
+	const tenantID = "1234"
+	ctx := tenant.InjectTenantID(context.Background(), tenantID)
+
+	newSavedViews := func(t *testing.T) *SavedViews {
+		store, err := NewMemorySavedViewsStore()
+		require.NoError(t, err)
+		return NewSavedViews(store, log.NewNopLogger())
+	}
+
+	view := func(name string) *settingsv1.SavedView {
+		return &settingsv1.SavedView{
+			Name:          name,
+			ProfileTypeId: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+			LabelSelector: `{service_name="checkout", env="prod"}`,
+			GroupBy:       []string{"pod"},
+			From:          "now-1h",
+			Until:         "now",
+		}
+	}
+
+	// withUser sets the user header of the request, if the user is specified.
+	withUser := func(req interface{ Header() http.Header }, user string) {
+		if user != "" {
+			req.Header().Set(UserHeaderName, user)
+		}
+	}
+
+	// upsert creates or updates a view; the view is private to the
+	// user, if the user is specified, or shared with the tenant.
+	upsert := func(sv *SavedViews, ctx context.Context, v *settingsv1.SavedView, user string) (*settingsv1.SavedView, error) {
+		req := connect.NewRequest(&settingsv1.UpsertSavedViewRequest{View: v, Private: user != ""})
+		withUser(req, user)
+		resp, err := sv.UpsertSavedView(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.View, nil
+	}
+
+	get := func(sv *SavedViews, id, user string) (*settingsv1.SavedView, error) {
+		req := connect.NewRequest(&settingsv1.GetSavedViewRequest{Id: id})
+		withUser(req, user)
+		resp, err := sv.GetSavedView(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.View, nil
+	}
+
+	remove := func(sv *SavedViews, id, user string) error {
+		req := connect.NewRequest(&settingsv1.DeleteSavedViewRequest{Id: id})
+		withUser(req, user)
+		_, err := sv.DeleteSavedView(ctx, req)
+		return err
+	}
+
+	list := func(sv *SavedViews, ctx context.Context, user string) []string {
+		req := connect.NewRequest(&settingsv1.ListSavedViewsRequest{})
+		withUser(req, user)
+		resp, err := sv.ListSavedViews(ctx, req)
+		require.NoError(t, err)
+		names := make([]string, 0, len(resp.Msg.Views))
+		for _, v := range resp.Msg.Views {
+			names = append(names, v.Name)
+		}
+		return names
+	}
+
+	t.Run("no views", func(t *testing.T) {
+		sv := newSavedViews(t)
+		require.Empty(t, list(sv, ctx, ""))
+	})
+
+	t.Run("create and get view", func(t *testing.T) {
+		sv := newSavedViews(t)
+		created, err := upsert(sv, ctx, view("checkout CPU"), "")
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Id)
+		require.NotZero(t, created.CreatedAt)
+		require.NotZero(t, created.ModifiedAt)
+
+		got, err := get(sv, created.Id, "")
+		require.NoError(t, err)
+		require.Equal(t, created, got)
+
+		// Views of other tenants are not visible.
+		require.Empty(t, list(sv, tenant.InjectTenantID(context.Background(), "other"), ""))
+	})
+
+	t.Run("private views", func(t *testing.T) {
+		sv := newSavedViews(t)
+		_, err := upsert(sv, ctx, view("shared"), "")
+		require.NoError(t, err)
+		private, err := upsert(sv, ctx, view("mine"), "alice")
+		require.NoError(t, err)
+		require.Equal(t, "alice", private.Owner)
+		_, err = upsert(sv, ctx, view("theirs"), "bob")
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"shared"}, list(sv, ctx, ""))
+		require.Equal(t, []string{"mine", "shared"}, list(sv, ctx, "alice"))
+
+		_, err = get(sv, private.Id, "bob")
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		_, err = get(sv, private.Id, "")
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		err = remove(sv, private.Id, "")
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("owner is set by the server", func(t *testing.T) {
+		sv := newSavedViews(t)
+		v := view("a")
+		v.Owner = "alice"
+		shared, err := upsert(sv, ctx, v, "")
+		require.NoError(t, err)
+		require.Empty(t, shared.Owner)
+
+		v = view("b")
+		v.Owner = "alice"
+		private, err := upsert(sv, ctx, v, "bob")
+		require.NoError(t, err)
+		require.Equal(t, "bob", private.Owner)
+		require.Equal(t, []string{"a"}, list(sv, ctx, "alice"))
+
+		// A private view requires the user.
+		_, err = sv.UpsertSavedView(ctx, connect.NewRequest(&settingsv1.UpsertSavedViewRequest{View: view("c"), Private: true}))
+		require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+
+	t.Run("update view", func(t *testing.T) {
+		sv := newSavedViews(t)
+		created, err := upsert(sv, ctx, view("a"), "alice")
+		require.NoError(t, err)
+
+		update := view("b")
+		update.Id = created.Id
+		update.ModifiedAt = created.ModifiedAt + 1
+		updated, err := upsert(sv, ctx, update, "alice")
+		require.NoError(t, err)
+		require.Equal(t, created.CreatedAt, updated.CreatedAt)
+		require.Equal(t, []string{"b"}, list(sv, ctx, "alice"))
+
+		// Only the owner can update a private view.
+		update = view("c")
+		update.Id = created.Id
+		_, err = upsert(sv, ctx, update, "bob")
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		_, err = upsert(sv, ctx, update, "")
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("newer update already written", func(t *testing.T) {
+		sv := newSavedViews(t)
+		created, err := upsert(sv, ctx, view("a"), "")
+		require.NoError(t, err)
+
+		update := view("a")
+		update.Id = created.Id
+		update.ModifiedAt = created.ModifiedAt - 1
+		_, err = upsert(sv, ctx, update, "")
+		require.EqualError(t, err, "already_exists: failed to update a: newer update already written")
+	})
+
+	t.Run("name is taken", func(t *testing.T) {
+		sv := newSavedViews(t)
+		_, err := upsert(sv, ctx, view("a"), "")
+		require.NoError(t, err)
+		_, err = upsert(sv, ctx, view("a"), "")
+		require.EqualError(t, err, "already_exists: failed to update a: saved view name is already taken")
+
+		// Names are unique within the owner scope.
+		_, err = upsert(sv, ctx, view("a"), "alice")
+		require.NoError(t, err)
+	})
+
+	t.Run("delete view", func(t *testing.T) {
+		sv := newSavedViews(t)
+		created, err := upsert(sv, ctx, view("a"), "alice")
+		require.NoError(t, err)
+
+		require.NoError(t, remove(sv, created.Id, "alice"))
+		require.Empty(t, list(sv, ctx, "alice"))
+
+		err = remove(sv, created.Id, "alice")
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("invalid views", func(t *testing.T) {
+		sv := newSavedViews(t)
+		for _, v := range []*settingsv1.SavedView{
+			{},
+			{Name: "a", LabelSelector: `{service_name=}`},
+		} {
+			_, err := upsert(sv, ctx, v, "")
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		}
+		_, err := get(sv, "", "")
+		require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+
+	t.Run("missing tenant id", func(t *testing.T) {
+		sv := newSavedViews(t)
+		_, err := sv.ListSavedViews(context.Background(), connect.NewRequest(&settingsv1.ListSavedViewsRequest{}))
+		require.EqualError(t, err, "invalid_argument: no org id")
+	})
+}
@@ -15,10 +15,11 @@ import (
 	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
 )
 
-func New(store Store, logger log.Logger) (*TenantSettings, error) {
+func New(store Store, userStore UserSettingsStore, logger log.Logger) (*TenantSettings, error) {
 	ts := &TenantSettings{
-		store:  store,
-		logger: logger,
+		store:     store,
+		userStore: userStore,
+		logger:    logger,
 	}
 
 	ts.Service = services.NewBasicService(ts.starting, ts.running, ts.stopping)
@@ -29,8 +30,9 @@ func New(store Store, logger log.Logger) (*TenantSettings, error) {
 type TenantSettings struct {
 	services.Service
 
-	store  Store
-	logger log.Logger
+	store     Store
+	userStore UserSettingsStore
+	logger    log.Logger
 }
 
 func (ts *TenantSettings) starting(ctx context.Context) error {
@@ -102,6 +104,10 @@ func (ts *TenantSettings) Set(ctx context.Context, req *connect.Request[settings
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no setting values provided"))
 	}
 
+	if err = validateViewSetting(req.Msg.Setting); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
 	if req.Msg.Setting.ModifiedAt <= 0 {
 		req.Msg.Setting.ModifiedAt = time.Now().UnixMilli()
 	}
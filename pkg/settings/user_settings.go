@@ -0,0 +1,102 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+)
+
+const (
+	// UserHeaderName is the header that identifies the user. Grafana sets
+	// it to the login of the signed-in user when proxying data source
+	// requests. Like the tenant header, it is trusted as is.
+	UserHeaderName = "X-Grafana-User"
+
+	maxUserSettings         = 100
+	maxUserSettingNameSize  = 256
+	maxUserSettingValueSize = 16 << 10
+)
+
+var tooManyUserSettingsErr = fmt.Errorf("user may not have more than %d settings", maxUserSettings)
+
+func (ts *TenantSettings) GetUserSettings(ctx context.Context, req *connect.Request[settingsv1.GetUserSettingsRequest]) (*connect.Response[settingsv1.GetUserSettingsResponse], error) {
+	tenantID, userID, err := tenantAndUserID(ctx, req.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := ts.userStore.Get(ctx, tenantID, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&settingsv1.GetUserSettingsResponse{
+		Settings: settings,
+	}), nil
+}
+
+func (ts *TenantSettings) SetUserSettings(ctx context.Context, req *connect.Request[settingsv1.SetUserSettingsRequest]) (*connect.Response[settingsv1.SetUserSettingsResponse], error) {
+	tenantID, userID, err := tenantAndUserID(ctx, req.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Msg == nil || req.Msg.Setting == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no setting values provided"))
+	}
+
+	if err = validateUserSetting(req.Msg.Setting); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if req.Msg.Setting.ModifiedAt <= 0 {
+		req.Msg.Setting.ModifiedAt = time.Now().UnixMilli()
+	}
+
+	setting, err := ts.userStore.Set(ctx, tenantID, userID, req.Msg.Setting)
+	if err != nil {
+		if errors.Is(err, oldSettingErr) {
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		}
+		if errors.Is(err, tooManyUserSettingsErr) {
+			return nil, connect.NewError(connect.CodeResourceExhausted, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&settingsv1.SetUserSettingsResponse{
+		Setting: setting,
+	}), nil
+}
+
+func tenantAndUserID(ctx context.Context, header http.Header) (string, string, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return "", "", connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	userID := header.Get(UserHeaderName)
+	if userID == "" {
+		return "", "", connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("no user id, the %s header is required", UserHeaderName))
+	}
+	return tenantID, userID, nil
+}
+
+func validateUserSetting(setting *settingsv1.Setting) error {
+	if setting.Name == "" {
+		return fmt.Errorf("setting name is required")
+	}
+	if len(setting.Name) > maxUserSettingNameSize {
+		return fmt.Errorf("setting name must not exceed %d bytes", maxUserSettingNameSize)
+	}
+	if len(setting.Value) > maxUserSettingValueSize {
+		return fmt.Errorf("setting %s: value must not exceed %d bytes", setting.Name, maxUserSettingValueSize)
+	}
+	return nil
+}
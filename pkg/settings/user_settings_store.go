@@ -0,0 +1,137 @@
+package settings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+)
+
+var userSettingsFilename = "user_settings.json"
+
+type UserSettingsStore interface {
+	// Get settings of a user of a tenant.
+	Get(ctx context.Context, tenantID, userID string) ([]*settingsv1.Setting, error)
+
+	// Set a setting of a user of a tenant.
+	Set(ctx context.Context, tenantID, userID string, setting *settingsv1.Setting) (*settingsv1.Setting, error)
+}
+
+// NewMemoryUserSettingsStore will create a user settings store with an
+// in-memory objstore bucket.
+func NewMemoryUserSettingsStore() (UserSettingsStore, error) {
+	return NewBucketUserSettingsStore(objstore.NewInMemBucket())
+}
+
+// NewBucketUserSettingsStore will create a user settings store with an
+// objstore bucket.
+func NewBucketUserSettingsStore(bucket objstore.Bucket) (UserSettingsStore, error) {
+	store := &bucketUserSettingsStore{
+		store:  make(map[string]map[string]map[string]*settingsv1.Setting),
+		bucket: bucket,
+	}
+
+	return store, nil
+}
+
+type bucketUserSettingsStore struct {
+	rw sync.Mutex
+
+	// store is indexed by tenant id, then by user id, then by setting name.
+	store map[string]map[string]map[string]*settingsv1.Setting
+
+	// bucket is an object store bucket.
+	bucket objstore.Bucket
+}
+
+func (s *bucketUserSettingsStore) Get(ctx context.Context, tenantID, userID string) ([]*settingsv1.Setting, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userSettings := s.store[tenantID][userID]
+	settings := make([]*settingsv1.Setting, 0, len(userSettings))
+	for _, setting := range userSettings {
+		settings = append(settings, setting)
+	}
+
+	slices.SortFunc(settings, func(a, b *settingsv1.Setting) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return settings, nil
+}
+
+func (s *bucketUserSettingsStore) Set(ctx context.Context, tenantID, userID string, setting *settingsv1.Setting) (*settingsv1.Setting, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	err := s.unsafeLoad(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantSettings, ok := s.store[tenantID]
+	if !ok {
+		tenantSettings = make(map[string]map[string]*settingsv1.Setting, 1)
+		s.store[tenantID] = tenantSettings
+	}
+	userSettings, ok := tenantSettings[userID]
+	if !ok {
+		userSettings = make(map[string]*settingsv1.Setting, 1)
+		tenantSettings[userID] = userSettings
+	}
+
+	oldSetting, ok := userSettings[setting.Name]
+	if ok && oldSetting.ModifiedAt > setting.ModifiedAt {
+		return nil, errors.Wrapf(oldSettingErr, "failed to update %s", setting.Name)
+	}
+	if !ok && len(userSettings) >= maxUserSettings {
+		return nil, errors.Wrapf(tooManyUserSettingsErr, "failed to add %s", setting.Name)
+	}
+	userSettings[setting.Name] = setting
+
+	err = s.unsafeFlush(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// unsafeFlush will flush the store to object storage. This is not thread-safe,
+// the store's write mutex should be acquired first.
+func (s *bucketUserSettingsStore) unsafeFlush(ctx context.Context) error {
+	data, err := json.Marshal(s.store)
+	if err != nil {
+		return err
+	}
+
+	return s.bucket.Upload(ctx, userSettingsFilename, bytes.NewReader(data))
+}
+
+// unsafeLoad will read the store in object storage into memory, if it exists.
+// This is not thread-safe, the store's write mutex should be acquired first.
+func (s *bucketUserSettingsStore) unsafeLoad(ctx context.Context) error {
+	reader, err := s.bucket.Get(ctx, userSettingsFilename)
+	if err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			// It is OK if we don't find the file.
+			return nil
+		}
+		return err
+	}
+	defer reader.Close()
+
+	return json.NewDecoder(reader).Decode(&s.store)
+}
@@ -0,0 +1,114 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func TestTenantSettings_UserSettings(t *testing.T) {
+	ctx := tenant.InjectTenantID(context.Background(), "1234")
+
+	newTenantSettings := func(t *testing.T) *TenantSettings {
+		userStore, err := NewMemoryUserSettingsStore()
+		require.NoError(t, err)
+		return &TenantSettings{
+			userStore: userStore,
+			logger:    log.NewNopLogger(),
+		}
+	}
+
+	set := func(ts *TenantSettings, ctx context.Context, user string, setting *settingsv1.Setting) (*settingsv1.Setting, error) {
+		req := connect.NewRequest(&settingsv1.SetUserSettingsRequest{Setting: setting})
+		if user != "" {
+			req.Header().Set(UserHeaderName, user)
+		}
+		resp, err := ts.SetUserSettings(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Setting, nil
+	}
+
+	get := func(ts *TenantSettings, ctx context.Context, user string) ([]*settingsv1.Setting, error) {
+		req := connect.NewRequest(&settingsv1.GetUserSettingsRequest{})
+		if user != "" {
+			req.Header().Set(UserHeaderName, user)
+		}
+		resp, err := ts.GetUserSettings(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Settings, nil
+	}
+
+	t.Run("settings are per user", func(t *testing.T) {
+		ts := newTenantSettings(t)
+		timeRange := &settingsv1.Setting{Name: "time_range", Value: "now-1h", ModifiedAt: 100}
+		colorMode := &settingsv1.Setting{Name: "color_mode", Value: "dark", ModifiedAt: 100}
+		_, err := set(ts, ctx, "alice", timeRange)
+		require.NoError(t, err)
+		_, err = set(ts, ctx, "alice", colorMode)
+		require.NoError(t, err)
+		_, err = set(ts, ctx, "bob", &settingsv1.Setting{Name: "time_range", Value: "now-6h", ModifiedAt: 100})
+		require.NoError(t, err)
+
+		settings, err := get(ts, ctx, "alice")
+		require.NoError(t, err)
+		require.Equal(t, []*settingsv1.Setting{colorMode, timeRange}, settings)
+
+		// Settings of the user in other tenants are not visible.
+		settings, err = get(ts, tenant.InjectTenantID(context.Background(), "other"), "alice")
+		require.NoError(t, err)
+		require.Empty(t, settings)
+	})
+
+	t.Run("newer update already written", func(t *testing.T) {
+		ts := newTenantSettings(t)
+		_, err := set(ts, ctx, "alice", &settingsv1.Setting{Name: "time_range", Value: "now-1h", ModifiedAt: 100})
+		require.NoError(t, err)
+		_, err = set(ts, ctx, "alice", &settingsv1.Setting{Name: "time_range", Value: "now-6h", ModifiedAt: 99})
+		require.EqualError(t, err, "already_exists: failed to update time_range: newer update already written")
+	})
+
+	t.Run("too many settings", func(t *testing.T) {
+		ts := newTenantSettings(t)
+		for i := 0; i < maxUserSettings; i++ {
+			_, err := set(ts, ctx, "alice", &settingsv1.Setting{Name: fmt.Sprintf("key%d", i)})
+			require.NoError(t, err)
+		}
+		_, err := set(ts, ctx, "alice", &settingsv1.Setting{Name: "one_more"})
+		require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+		// Existing settings can be updated.
+		_, err = set(ts, ctx, "alice", &settingsv1.Setting{Name: "key0", Value: "updated"})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		ts := newTenantSettings(t)
+		_, err := get(ts, ctx, "")
+		require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+		_, err = set(ts, ctx, "", &settingsv1.Setting{Name: "time_range"})
+		require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+		_, err = get(ts, context.Background(), "alice")
+		require.EqualError(t, err, "invalid_argument: no org id")
+
+		for _, setting := range []*settingsv1.Setting{
+			nil,
+			{Name: ""},
+			{Name: strings.Repeat("a", maxUserSettingNameSize+1)},
+			{Name: "pinned_services", Value: strings.Repeat("a", maxUserSettingValueSize+1)},
+		} {
+			_, err = set(ts, ctx, "alice", setting)
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		}
+	})
+}
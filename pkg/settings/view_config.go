@@ -0,0 +1,142 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/dskit/tenant"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+)
+
+const (
+	viewSettingPrefix        = "view."
+	serviceViewSettingPrefix = "view.service."
+
+	viewKeyProfileType = "profile_type"
+	viewKeyAggregation = "aggregation"
+	viewKeyColorScheme = "color_scheme"
+)
+
+var viewAggregations = []string{"sum", "average"}
+
+func (ts *TenantSettings) GetViewConfig(ctx context.Context, req *connect.Request[settingsv1.GetViewConfigRequest]) (*connect.Response[settingsv1.GetViewConfigResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	settings, err := ts.store.Get(ctx, tenantID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &settingsv1.GetViewConfigResponse{Defaults: new(settingsv1.ViewConfig)}
+	services := make(map[string]*settingsv1.ViewConfig)
+	for _, s := range settings {
+		service, key, ok := parseViewSettingName(s.Name)
+		if !ok {
+			continue
+		}
+		config := resp.Defaults
+		if service != "" {
+			if req.Msg.ServiceName != "" && service != req.Msg.ServiceName {
+				continue
+			}
+			if config, ok = services[service]; !ok {
+				config = new(settingsv1.ViewConfig)
+				services[service] = config
+			}
+		}
+		setViewConfigValue(config, key, s.Value)
+	}
+
+	for service, config := range services {
+		resp.Services = append(resp.Services, &settingsv1.ServiceViewConfig{
+			ServiceName: service,
+			Config:      mergeViewConfig(config, resp.Defaults),
+		})
+	}
+	slices.SortFunc(resp.Services, func(a, b *settingsv1.ServiceViewConfig) int {
+		return strings.Compare(a.ServiceName, b.ServiceName)
+	})
+
+	return connect.NewResponse(resp), nil
+}
+
+// parseViewSettingName returns the service and the key of a view setting.
+// Service names may include dots, while keys never do.
+func parseViewSettingName(name string) (service, key string, ok bool) {
+	if !strings.HasPrefix(name, viewSettingPrefix) {
+		return "", "", false
+	}
+	if strings.HasPrefix(name, serviceViewSettingPrefix) {
+		rest := strings.TrimPrefix(name, serviceViewSettingPrefix)
+		i := strings.LastIndexByte(rest, '.')
+		if i <= 0 {
+			return "", "", false
+		}
+		service, key = rest[:i], rest[i+1:]
+	} else {
+		key = strings.TrimPrefix(name, viewSettingPrefix)
+	}
+	switch key {
+	case viewKeyProfileType, viewKeyAggregation, viewKeyColorScheme:
+		return service, key, true
+	}
+	return "", "", false
+}
+
+func validateViewSetting(s *settingsv1.Setting) error {
+	if !strings.HasPrefix(s.Name, viewSettingPrefix) {
+		return nil
+	}
+	_, key, ok := parseViewSettingName(s.Name)
+	if !ok {
+		return fmt.Errorf("invalid view setting %q", s.Name)
+	}
+	if s.Value == "" {
+		// An empty value resets the setting.
+		return nil
+	}
+	switch key {
+	case viewKeyProfileType:
+		if _, err := phlaremodel.ParseProfileTypeSelector(s.Value); err != nil {
+			return fmt.Errorf("invalid profile type %q: %w", s.Value, err)
+		}
+	case viewKeyAggregation:
+		if !slices.Contains(viewAggregations, s.Value) {
+			return fmt.Errorf("invalid aggregation %q: must be one of %v", s.Value, viewAggregations)
+		}
+	}
+	return nil
+}
+
+func setViewConfigValue(config *settingsv1.ViewConfig, key, value string) {
+	switch key {
+	case viewKeyProfileType:
+		config.ProfileType = value
+	case viewKeyAggregation:
+		config.Aggregation = value
+	case viewKeyColorScheme:
+		config.ColorScheme = value
+	}
+}
+
+func mergeViewConfig(config, defaults *settingsv1.ViewConfig) *settingsv1.ViewConfig {
+	merged := config.CloneVT()
+	if merged.ProfileType == "" {
+		merged.ProfileType = defaults.ProfileType
+	}
+	if merged.Aggregation == "" {
+		merged.Aggregation = defaults.Aggregation
+	}
+	if merged.ColorScheme == "" {
+		merged.ColorScheme = defaults.ColorScheme
+	}
+	return merged
+}
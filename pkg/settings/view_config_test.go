@@ -0,0 +1,81 @@
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+
+	settingsv1 "github.com/grafana/pyroscope/api/gen/proto/go/settings/v1"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func TestTenantSettings_GetViewConfig(t *testing.T) {
+	const tenantID = "1234"
+	ctx := tenant.InjectTenantID(context.Background(), tenantID)
+
+	setting := func(name, value string) *settingsv1.Setting {
+		return &settingsv1.Setting{Name: name, Value: value, ModifiedAt: 100}
+	}
+
+	ts, cleanup := newTestTenantSettings(t, map[string][]*settingsv1.Setting{
+		tenantID: {
+			setting("view.profile_type", "process_cpu:cpu:nanoseconds:cpu:nanoseconds"),
+			setting("view.color_scheme", "package_name"),
+			setting("view.service.cart.aggregation", "average"),
+			setting("view.service.checkout.svc.profile_type", "memory:inuse_space:bytes:space:bytes"),
+			setting("view.service.checkout.svc.color_scheme", "value"),
+			setting("view.unknown", "ignored"),
+			setting("other", "ignored"),
+		},
+	})
+	defer cleanup()
+
+	t.Run("all services", func(t *testing.T) {
+		got, err := ts.GetViewConfig(ctx, connect.NewRequest(&settingsv1.GetViewConfigRequest{}))
+		require.NoError(t, err)
+		require.Equal(t, &settingsv1.GetViewConfigResponse{
+			Defaults: &settingsv1.ViewConfig{
+				ProfileType: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+				ColorScheme: "package_name",
+			},
+			Services: []*settingsv1.ServiceViewConfig{
+				{
+					ServiceName: "cart",
+					Config: &settingsv1.ViewConfig{
+						ProfileType: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+						Aggregation: "average",
+						ColorScheme: "package_name",
+					},
+				},
+				{
+					ServiceName: "checkout.svc",
+					Config: &settingsv1.ViewConfig{
+						ProfileType: "memory:inuse_space:bytes:space:bytes",
+						ColorScheme: "value",
+					},
+				},
+			},
+		}, got.Msg)
+	})
+
+	t.Run("single service", func(t *testing.T) {
+		got, err := ts.GetViewConfig(ctx, connect.NewRequest(&settingsv1.GetViewConfigRequest{ServiceName: "cart"}))
+		require.NoError(t, err)
+		require.Len(t, got.Msg.Services, 1)
+		require.Equal(t, "cart", got.Msg.Services[0].ServiceName)
+	})
+
+	t.Run("invalid view settings", func(t *testing.T) {
+		for _, s := range []*settingsv1.Setting{
+			setting("view.unknown", "x"),
+			setting("view.service..aggregation", "sum"),
+			setting("view.aggregation", "max"),
+			setting("view.service.cart.profile_type", "cpu"),
+		} {
+			_, err := ts.Set(ctx, connect.NewRequest(&settingsv1.SetSettingsRequest{Setting: s}))
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), s.Name)
+		}
+	})
+}
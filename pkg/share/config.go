@@ -0,0 +1,121 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/common/model"
+)
+
+type TargetType string
+
+const (
+	// TargetFlamegraphCom uploads the flame graph to flamegraph.com,
+	// or to a self-hosted instance of it.
+	TargetFlamegraphCom TargetType = "flamegraph_com"
+	// TargetPprof posts the profile in the pprof format to an HTTP
+	// endpoint, e.g., an internal pprof viewer.
+	TargetPprof TargetType = "pprof"
+	// TargetS3 uploads the profile in the pprof format to an S3
+	// bucket, and shares a presigned URL of the object.
+	TargetS3 TargetType = "s3"
+)
+
+var TargetTypes = []TargetType{TargetFlamegraphCom, TargetPprof, TargetS3}
+
+const (
+	// FlamegraphComTargetName is the name of the built-in flamegraph.com
+	// target, available unless disabled for the tenant.
+	FlamegraphComTargetName = "flamegraph.com"
+	flamegraphComURL        = "https://flamegraph.com/api/upload/v1"
+
+	defaultPresignExpiry = 24 * time.Hour
+	// Presigned URLs can't be valid for longer than 7 days.
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// Target is a service the profiles of the tenant can be shared with.
+type Target struct {
+	// Name of the target, presented to the users.
+	Name string     `yaml:"name" json:"name"`
+	Type TargetType `yaml:"type" json:"type"`
+	// URL of the flamegraph.com upload API, or of the pprof endpoint.
+	URL string `yaml:"url" json:"url"`
+	// The secrets are masked when the overrides are dumped,
+	// e.g., at the /runtime_config endpoint.
+	BearerToken flagext.Secret `yaml:"bearer_token" json:"bearer_token"`
+	// S3 target options.
+	S3 S3Target `yaml:"s3" json:"s3"`
+}
+
+type S3Target struct {
+	Endpoint        string         `yaml:"endpoint" json:"endpoint"`
+	Region          string         `yaml:"region" json:"region"`
+	BucketName      string         `yaml:"bucket_name" json:"bucket_name"`
+	Prefix          string         `yaml:"prefix" json:"prefix"`
+	AccessKeyID     string         `yaml:"access_key_id" json:"access_key_id"`
+	SecretAccessKey flagext.Secret `yaml:"secret_access_key" json:"secret_access_key"`
+	Insecure        bool           `yaml:"insecure" json:"insecure"`
+	// How long the presigned URL is valid, 24h by default.
+	Expiry model.Duration `yaml:"expiry" json:"expiry"`
+}
+
+type Targets []Target
+
+// ExampleDoc provides an example doc for this config, as the targets
+// are only configured in the overrides.
+func (Targets) ExampleDoc() (comment string, yaml interface{}) {
+	return `This example allows sharing profiles with an internal pprof viewer, and as presigned S3 URLs valid for 3 days.`,
+		[]map[string]interface{}{
+			{"name": "pprof-viewer", "type": "pprof", "url": "https://pprof.example.com/upload"},
+			{
+				"name": "s3",
+				"type": "s3",
+				"s3": map[string]interface{}{
+					"endpoint":    "s3.eu-west-1.amazonaws.com",
+					"region":      "eu-west-1",
+					"bucket_name": "shared-profiles",
+					"expiry":      "3d",
+				},
+			},
+		}
+}
+
+// ValidateTargets validates the share targets of a tenant.
+func ValidateTargets(targets Targets) error {
+	names := map[string]struct{}{FlamegraphComTargetName: {}}
+	for _, t := range targets {
+		if t.Name == "" {
+			return errors.New("share target name is required")
+		}
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("duplicate share target %q", t.Name)
+		}
+		names[t.Name] = struct{}{}
+		if !slices.Contains(TargetTypes, t.Type) {
+			return fmt.Errorf("unsupported type of share target %q: %q, supported: %v", t.Name, t.Type, TargetTypes)
+		}
+		switch t.Type {
+		case TargetS3:
+			if t.S3.Endpoint == "" || t.S3.BucketName == "" {
+				return fmt.Errorf("share target %q: s3 endpoint and bucket name are required", t.Name)
+			}
+			if e := time.Duration(t.S3.Expiry); e < 0 || e > maxPresignExpiry {
+				return fmt.Errorf("share target %q: s3 expiry must be between 0 and %s", t.Name, maxPresignExpiry)
+			}
+		default:
+			u, err := url.Parse(t.URL)
+			if err != nil {
+				return fmt.Errorf("invalid URL of share target %q: %w", t.Name, err)
+			}
+			if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+				return fmt.Errorf("invalid URL of share target %q: absolute http(s) URL expected", t.Name)
+			}
+		}
+	}
+	return nil
+}
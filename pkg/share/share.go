@@ -0,0 +1,193 @@
+// Package share implements sharing of profiles with external services.
+//
+// Besides the public flamegraph.com service, the profiles can be shared
+// with self-hosted targets, which are configured per tenant in the limits
+// overrides: internal pprof viewers, and S3 buckets the profiles are
+// uploaded to and shared as presigned URLs. The flamegraph.com target can
+// be disabled for the tenants that must not share profiles publicly.
+package share
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const maxShareRequestSize = 32 << 20
+
+type Overrides interface {
+	ShareTargets(tenantID string) []Target
+	ShareFlamegraphComEnabled(tenantID string) bool
+}
+
+// Profile is a profile shared, as rendered by the UI.
+type Profile struct {
+	TenantID    string
+	Name        string
+	Flamebearer *flamebearer.FlamebearerProfile
+	// The original flamebearer JSON.
+	JSON []byte
+}
+
+// Sharer shares a profile and returns the URL it is available at.
+type Sharer interface {
+	Share(context.Context, *Profile) (string, error)
+}
+
+type Service struct {
+	logger    log.Logger
+	overrides Overrides
+	client    *http.Client
+	newSharer func(Target) (Sharer, error)
+}
+
+func New(overrides Overrides, logger log.Logger) *Service {
+	s := &Service{
+		logger:    logger,
+		overrides: overrides,
+		client:    http.DefaultClient,
+	}
+	s.newSharer = s.sharer
+	return s
+}
+
+func (s *Service) sharer(t Target) (Sharer, error) {
+	switch t.Type {
+	case TargetFlamegraphCom:
+		return &flamegraphCom{url: t.URL, bearerToken: t.BearerToken.String(), client: s.client}, nil
+	case TargetPprof:
+		return &pprofEndpoint{url: t.URL, bearerToken: t.BearerToken.String(), client: s.client}, nil
+	case TargetS3:
+		return newS3Bucket(t.S3)
+	default:
+		return nil, fmt.Errorf("unsupported share target type %q", t.Type)
+	}
+}
+
+// targets returns the share targets available to the tenant.
+func (s *Service) targets(tenantID string) []Target {
+	var targets []Target
+	if s.overrides.ShareFlamegraphComEnabled(tenantID) {
+		targets = append(targets, Target{
+			Name: FlamegraphComTargetName,
+			Type: TargetFlamegraphCom,
+			URL:  flamegraphComURL,
+		})
+	}
+	return append(targets, s.overrides.ShareTargets(tenantID)...)
+}
+
+type targetInfo struct {
+	Name string     `json:"name"`
+	Type TargetType `json:"type"`
+}
+
+// TargetsHandler lists the share targets available to the tenant.
+func (s *Service) TargetsHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	targets := s.targets(tenantID)
+	infos := make([]targetInfo, 0, len(targets))
+	for _, t := range targets {
+		infos = append(infos, targetInfo{Name: t.Name, Type: t.Type})
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(infos)
+}
+
+type shareRequest struct {
+	// Name of the target. Defaults to flamegraph.com.
+	Target string `json:"target"`
+	Name   string `json:"name"`
+	// Base64-encoded flamebearer profile JSON.
+	Profile string `json:"profile"`
+}
+
+type shareResponse struct {
+	URL string `json:"url"`
+}
+
+// ShareHandler shares the profile with the target requested.
+func (s *Service) ShareHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	var req shareRequest
+	if err = json.NewDecoder(io.LimitReader(r.Body, maxShareRequestSize)).Decode(&req); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	profile, err := decodeProfile(tenantID, &req)
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	if req.Target == "" {
+		req.Target = FlamegraphComTargetName
+	}
+	var target *Target
+	for _, t := range s.targets(tenantID) {
+		if t.Name == req.Target {
+			target = &t
+			break
+		}
+	}
+	if target == nil {
+		httputil.Error(w, connect.NewError(connect.CodeNotFound, fmt.Errorf("share target %q not found", req.Target)))
+		return
+	}
+
+	sharer, err := s.newSharer(*target)
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInternal, err))
+		return
+	}
+	u, err := sharer.Share(r.Context(), profile)
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "failed to share profile", "tenant", tenantID, "target", target.Name, "err", err)
+		httputil.Error(w, connect.NewError(connect.CodeUnavailable, fmt.Errorf("failed to share profile with %s: %w", target.Name, err)))
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(shareResponse{URL: u})
+}
+
+func decodeProfile(tenantID string, req *shareRequest) (*Profile, error) {
+	if req.Profile == "" {
+		return nil, errors.New("profile is required")
+	}
+	b, err := base64.StdEncoding.DecodeString(req.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile encoding: %w", err)
+	}
+	var fb flamebearer.FlamebearerProfile
+	if err = json.Unmarshal(b, &fb); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	if err = fb.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	return &Profile{
+		TenantID:    tenantID,
+		Name:        req.Name,
+		Flamebearer: &fb,
+		JSON:        b,
+	}, nil
+}
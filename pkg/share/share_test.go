@@ -0,0 +1,238 @@
+package share
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/google/pprof/profile"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer"
+)
+
+type overrides struct {
+	targets              []Target
+	flamegraphComEnabled bool
+}
+
+func (o *overrides) ShareTargets(string) []Target          { return o.targets }
+func (o *overrides) ShareFlamegraphComEnabled(string) bool { return o.flamegraphComEnabled }
+
+func testProfile(t *testing.T) string {
+	t.Helper()
+	fb := flamebearer.FlamebearerProfile{
+		Version: 1,
+		FlamebearerProfileV1: flamebearer.FlamebearerProfileV1{
+			Flamebearer: flamebearer.FlamebearerV1{
+				Names:    []string{"total", "main", "work"},
+				Levels:   [][]int{{0, 3, 0, 0}, {0, 3, 1, 1}, {0, 2, 2, 2}},
+				NumTicks: 3,
+				MaxSelf:  2,
+			},
+			Metadata: flamebearer.FlamebearerMetadataV1{
+				Format: "single",
+				Units:  "samples",
+				Name:   "cpu",
+			},
+		},
+	}
+	b, err := json.Marshal(fb)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func share(t *testing.T, s *Service, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/pyroscope/share", strings.NewReader(body))
+	req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+	rec := httptest.NewRecorder()
+	s.ShareHandler(rec, req)
+	return rec
+}
+
+func sharedURLOf(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp shareResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp.URL
+}
+
+func readPprof(t *testing.T, r io.Reader) *profile.Profile {
+	t.Helper()
+	gr, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	p, err := profile.Parse(gr)
+	require.NoError(t, err)
+	return p
+}
+
+func Test_Share_FlamegraphCom(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req flamegraphComRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "my-profile", req.Name)
+		assert.Equal(t, "samples", req.FileTypeData.Units)
+		assert.Equal(t, "json", req.Type)
+		_, _ = w.Write([]byte(`{"key":"abc","url":"https://flamegraph.example.com/share/abc"}`))
+	}))
+	defer srv.Close()
+
+	s := New(&overrides{flamegraphComEnabled: true}, log.NewNopLogger())
+	// The built-in target is replaced with the test server.
+	s.newSharer = func(target Target) (Sharer, error) {
+		target.URL = srv.URL
+		return s.sharer(target)
+	}
+	rec := share(t, s, `{"name":"my-profile","profile":"`+testProfile(t)+`"}`)
+	assert.Equal(t, "https://flamegraph.example.com/share/abc", sharedURLOf(t, rec))
+
+	s = New(&overrides{}, log.NewNopLogger())
+	rec = share(t, s, `{"name":"my-profile","profile":"`+testProfile(t)+`"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func Test_Share_Pprof(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		p := readPprof(t, r.Body)
+		assert.Equal(t, "cpu", p.SampleType[0].Type)
+		var total int64
+		for _, s := range p.Sample {
+			total += s.Value[0]
+		}
+		assert.Equal(t, int64(3), total)
+		w.Header().Set("Location", "https://pprof.example.com/profiles/1")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	s := New(&overrides{targets: []Target{{Name: "viewer", Type: TargetPprof, URL: srv.URL, BearerToken: flagext.SecretWithValue("token")}}}, log.NewNopLogger())
+	rec := share(t, s, `{"target":"viewer","profile":"`+testProfile(t)+`"}`)
+	assert.Equal(t, "https://pprof.example.com/profiles/1", sharedURLOf(t, rec))
+}
+
+func Test_Share_S3(t *testing.T) {
+	objects := make(map[string][]byte)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		objects[r.URL.Path] = readChunked(t, r.Body)
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	s := New(&overrides{targets: []Target{{
+		Name: "s3",
+		Type: TargetS3,
+		S3: S3Target{
+			Endpoint:        strings.TrimPrefix(srv.URL, "http://"),
+			Region:          "eu-west-1",
+			BucketName:      "bucket",
+			Prefix:          "shared/",
+			AccessKeyID:     "key",
+			SecretAccessKey: flagext.SecretWithValue("secret"),
+			Insecure:        true,
+		},
+	}}}, log.NewNopLogger())
+	rec := share(t, s, `{"target":"s3","profile":"`+testProfile(t)+`"}`)
+	u, err := url.Parse(sharedURLOf(t, rec))
+	require.NoError(t, err)
+	assert.Equal(t, "86400", u.Query().Get("X-Amz-Expires"))
+	require.Contains(t, objects, u.Path)
+	assert.True(t, strings.HasPrefix(u.Path, "/bucket/shared/tenant/"))
+	readPprof(t, bytes.NewReader(objects[u.Path]))
+}
+
+// readChunked reads the body of the request signed with the
+// streaming signature, used by the client over plain HTTP.
+func readChunked(t *testing.T, r io.Reader) []byte {
+	t.Helper()
+	var data []byte
+	br := bufio.NewReader(r)
+	for {
+		header, err := br.ReadString('\n')
+		require.NoError(t, err)
+		size, _, _ := strings.Cut(header, ";")
+		n, err := strconv.ParseInt(size, 16, 64)
+		require.NoError(t, err)
+		chunk := make([]byte, n+2)
+		_, err = io.ReadFull(br, chunk)
+		require.NoError(t, err)
+		if n == 0 {
+			return data
+		}
+		data = append(data, chunk[:n]...)
+	}
+}
+
+func Test_TargetsHandler(t *testing.T) {
+	s := New(&overrides{
+		flamegraphComEnabled: true,
+		targets:              []Target{{Name: "viewer", Type: TargetPprof, URL: "http://viewer"}},
+	}, log.NewNopLogger())
+	rec := httptest.NewRecorder()
+	s.TargetsHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(user.InjectOrgID(context.Background(), "tenant")))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"name":"flamegraph.com","type":"flamegraph_com"},{"name":"viewer","type":"pprof"}]`, rec.Body.String())
+}
+
+func Test_Share_InvalidRequest(t *testing.T) {
+	s := New(&overrides{flamegraphComEnabled: true}, log.NewNopLogger())
+	for _, body := range []string{`{`, `{"profile":""}`, `{"profile":"!"}`, `{"profile":"e30="}`} {
+		assert.Equal(t, http.StatusBadRequest, share(t, s, body).Code, body)
+	}
+}
+
+func Test_ValidateTargets(t *testing.T) {
+	assert.NoError(t, ValidateTargets(Targets{
+		{Name: "viewer", Type: TargetPprof, URL: "https://viewer"},
+		{Name: "s3", Type: TargetS3, S3: S3Target{Endpoint: "s3", BucketName: "b"}},
+	}))
+	for _, targets := range []Targets{
+		{{Type: TargetPprof, URL: "https://viewer"}},
+		{{Name: FlamegraphComTargetName, Type: TargetFlamegraphCom, URL: "https://viewer"}},
+		{{Name: "viewer", Type: "ftp", URL: "ftp://viewer"}},
+		{{Name: "viewer", Type: TargetPprof, URL: "viewer"}},
+		{{Name: "s3", Type: TargetS3}},
+	} {
+		assert.Error(t, ValidateTargets(targets))
+	}
+}
+
+func Test_Target_Secrets(t *testing.T) {
+	const config = `
+name: s3
+type: s3
+bearer_token: bearer-token
+s3:
+  access_key_id: key
+  secret_access_key: secret-access-key
+`
+	var target Target
+	require.NoError(t, yaml.Unmarshal([]byte(config), &target))
+	assert.Equal(t, "bearer-token", target.BearerToken.String())
+	assert.Equal(t, "secret-access-key", target.S3.SecretAccessKey.String())
+
+	// The secrets are not revealed when the overrides are dumped.
+	out, err := yaml.Marshal(target)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "bearer-token")
+	assert.NotContains(t, string(out), "secret-access-key")
+	assert.Contains(t, string(out), "access_key_id: key")
+}
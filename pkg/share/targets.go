@@ -0,0 +1,195 @@
+package share
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/pyroscope/pkg/og/storage/tree"
+	"github.com/grafana/pyroscope/pkg/og/structs/flamebearer"
+)
+
+const maxResponseSize = 1 << 20
+
+type flamegraphCom struct {
+	url         string
+	bearerToken string
+	client      *http.Client
+}
+
+type flamegraphComRequest struct {
+	FileTypeData struct {
+		Units   string `json:"units"`
+		SpyName string `json:"spyName"`
+	} `json:"fileTypeData"`
+	Name    string `json:"name"`
+	Profile string `json:"profile"`
+	Type    string `json:"type"`
+}
+
+func (f *flamegraphCom) Share(ctx context.Context, p *Profile) (string, error) {
+	var req flamegraphComRequest
+	req.FileTypeData.Units = string(p.Flamebearer.Metadata.Units)
+	req.FileTypeData.SpyName = p.Flamebearer.Metadata.SpyName
+	req.Name = p.Name
+	req.Profile = base64.StdEncoding.EncodeToString(p.JSON)
+	req.Type = "json"
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	return post(ctx, f.client, f.url, f.bearerToken, "application/json", body)
+}
+
+// pprofEndpoint posts the gzipped pprof profile to the URL. The response
+// is expected to be the URL the profile is available at: either in the
+// Location header, in the "url" field of a JSON object, or as plain text.
+type pprofEndpoint struct {
+	url         string
+	bearerToken string
+	client      *http.Client
+}
+
+func (e *pprofEndpoint) Share(ctx context.Context, p *Profile) (string, error) {
+	body, err := toPprof(p.Flamebearer)
+	if err != nil {
+		return "", err
+	}
+	return post(ctx, e.client, e.url, e.bearerToken, "application/octet-stream", body)
+}
+
+func post(ctx context.Context, client *http.Client, u, bearerToken, contentType string, body []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return sharedURL(resp, b)
+}
+
+func sharedURL(resp *http.Response, body []byte) (string, error) {
+	if l, err := resp.Location(); err == nil {
+		return l.String(), nil
+	}
+	var r shareResponse
+	if err := json.Unmarshal(body, &r); err == nil && r.URL != "" {
+		return r.URL, nil
+	}
+	s := strings.TrimSpace(string(body))
+	if u, err := url.Parse(s); err == nil && u.IsAbs() {
+		return s, nil
+	}
+	return "", fmt.Errorf("response does not contain the URL of the shared profile")
+}
+
+type s3Bucket struct {
+	client *minio.Client
+	config S3Target
+}
+
+func newS3Bucket(cfg S3Target) (*s3Bucket, error) {
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.IAM{Client: &http.Client{Transport: http.DefaultTransport}},
+	})
+	if cfg.AccessKeyID != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey.String(), "")
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: !cfg.Insecure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3Bucket{client: client, config: cfg}, nil
+}
+
+func (b *s3Bucket) Share(ctx context.Context, p *Profile) (string, error) {
+	body, err := toPprof(p.Flamebearer)
+	if err != nil {
+		return "", err
+	}
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return "", err
+	}
+	key := b.config.Prefix + p.TenantID + "/" + hex.EncodeToString(id) + ".pb.gz"
+	_, err = b.client.PutObject(ctx, b.config.BucketName, key, bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	if err != nil {
+		return "", fmt.Errorf("uploading profile: %w", err)
+	}
+	expiry := time.Duration(b.config.Expiry)
+	if expiry == 0 {
+		expiry = defaultPresignExpiry
+	}
+	params := make(url.Values)
+	if p.Name != "" {
+		params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", p.Name+".pb.gz"))
+	}
+	u, err := b.client.PresignedGetObject(ctx, b.config.BucketName, key, expiry, params)
+	if err != nil {
+		return "", fmt.Errorf("presigning profile URL: %w", err)
+	}
+	return u.String(), nil
+}
+
+// toPprof converts the flame graph to a gzipped pprof profile.
+func toPprof(fb *flamebearer.FlamebearerProfile) ([]byte, error) {
+	t, err := flamebearer.ProfileToTree(*fb)
+	if err != nil {
+		return nil, err
+	}
+	md := &tree.PprofMetadata{
+		Type: fb.Metadata.Name,
+		Unit: string(fb.Metadata.Units),
+	}
+	if md.Type == "" {
+		md.Type = "samples"
+	}
+	b, err := proto.Marshal(t.Pprof(md))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err = gw.Write(b); err != nil {
+		return nil, err
+	}
+	if err = gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
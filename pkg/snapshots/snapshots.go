@@ -0,0 +1,285 @@
+package snapshots
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql/parser"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	snapshotsv1 "github.com/grafana/pyroscope/api/gen/proto/go/snapshots/v1"
+	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/objstore"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const (
+	defaultTTL = 24 * time.Hour
+	maxTTL     = 30 * 24 * time.Hour
+
+	secretSize = 16
+)
+
+var (
+	errInvalidToken     = errors.New("invalid snapshot token")
+	errSnapshotNotFound = errors.New("snapshot not found")
+)
+
+// Snapshots freezes query results into immutable objects stored in the
+// bucket. A snapshot is identified by an unguessable token that encodes
+// the tenant, which allows public snapshots to be served without tenant
+// credentials. Expired snapshots are removed when they are accessed, and
+// by the Sweeper otherwise.
+type Snapshots struct {
+	logger  log.Logger
+	bucket  objstore.Bucket
+	querier querierv1connect.QuerierServiceClient
+	now     func() time.Time
+}
+
+func New(bucket objstore.Bucket, querier querierv1connect.QuerierServiceClient, logger log.Logger) *Snapshots {
+	return &Snapshots{
+		logger:  logger,
+		bucket:  bucket,
+		querier: querier,
+		now:     time.Now,
+	}
+}
+
+func (s *Snapshots) Create(ctx context.Context, c *connect.Request[snapshotsv1.CreateSnapshotRequest]) (*connect.Response[snapshotsv1.CreateSnapshotResponse], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	profileType, ttl, err := validateCreateRequest(c.Msg)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	resp, err := s.querier.SelectMergeStacktraces(ctx, connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+		ProfileTypeID: c.Msg.ProfileTypeID,
+		LabelSelector: c.Msg.LabelSelector,
+		Start:         c.Msg.Start,
+		End:           c.Msg.End,
+		MaxNodes:      c.Msg.MaxNodes,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	profile, err := json.Marshal(phlaremodel.ExportToFlamebearer(resp.Msg.Flamegraph, profileType))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	token, secret, err := newToken(tenantID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	now := s.now()
+	snapshot := &snapshotsv1.Snapshot{
+		Token:              token,
+		Name:               c.Msg.Name,
+		ProfileTypeID:      c.Msg.ProfileTypeID,
+		LabelSelector:      c.Msg.LabelSelector,
+		Start:              c.Msg.Start,
+		End:                c.Msg.End,
+		CreatedAt:          now.UnixMilli(),
+		ExpiresAt:          now.Add(ttl).UnixMilli(),
+		Public:             c.Msg.Public,
+		FlamebearerProfile: string(profile),
+	}
+	data, err := snapshot.MarshalVT()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	// The expiry marker is uploaded first: a marker of a snapshot
+	// that failed to upload is harmless, while a snapshot without
+	// a marker would never be removed, unless it is accessed.
+	if err = s.bucket.Upload(ctx, expiryMarkerPath(snapshot.ExpiresAt, token), bytes.NewReader(nil)); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.Wrap(err, "failed to upload snapshot expiry marker"))
+	}
+	if err = s.getBucket(tenantID).Upload(ctx, secret, bytes.NewReader(data)); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.Wrap(err, "failed to upload snapshot"))
+	}
+
+	return connect.NewResponse(&snapshotsv1.CreateSnapshotResponse{Snapshot: snapshot}), nil
+}
+
+func (s *Snapshots) Get(ctx context.Context, c *connect.Request[snapshotsv1.GetSnapshotRequest]) (*connect.Response[snapshotsv1.GetSnapshotResponse], error) {
+	tenantID, secret, err := resolveToken(ctx, c.Msg.Token)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.load(ctx, tenantID, secret)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&snapshotsv1.GetSnapshotResponse{Snapshot: snapshot}), nil
+}
+
+func (s *Snapshots) Delete(ctx context.Context, c *connect.Request[snapshotsv1.DeleteSnapshotRequest]) (*connect.Response[snapshotsv1.DeleteSnapshotResponse], error) {
+	tenantID, secret, err := resolveToken(ctx, c.Msg.Token)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.load(ctx, tenantID, secret); err != nil {
+		return nil, err
+	}
+	if err = s.getBucket(tenantID).Delete(ctx, secret); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.Wrap(err, "failed to delete snapshot"))
+	}
+	return connect.NewResponse(&snapshotsv1.DeleteSnapshotResponse{}), nil
+}
+
+// PublicHandler serves public snapshots without tenant credentials.
+// Private snapshots are reported as not found.
+func (s *Snapshots) PublicHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, secret, err := parseToken(mux.Vars(r)["token"])
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeNotFound, errSnapshotNotFound))
+		return
+	}
+	snapshot, err := s.load(r.Context(), tenantID, secret)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if !snapshot.Public {
+		httputil.Error(w, connect.NewError(connect.CodeNotFound, errSnapshotNotFound))
+		return
+	}
+	data, err := protojson.Marshal(snapshot)
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInternal, err))
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// resolveToken returns the tenant and the secret of the token,
+// if the token belongs to the tenant of the request.
+func resolveToken(ctx context.Context, token string) (tenantID, secret string, err error) {
+	tenantID, err = tenant.TenantID(ctx)
+	if err != nil {
+		return "", "", connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	tokenTenantID, secret, err := parseToken(token)
+	if err != nil {
+		return "", "", connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if tokenTenantID != tenantID {
+		return "", "", connect.NewError(connect.CodeNotFound, errSnapshotNotFound)
+	}
+	return tenantID, secret, nil
+}
+
+func (s *Snapshots) load(ctx context.Context, tenantID, secret string) (*snapshotsv1.Snapshot, error) {
+	bucket := s.getBucket(tenantID)
+	reader, err := bucket.Get(ctx, secret)
+	if err != nil {
+		if bucket.IsObjNotFoundErr(err) {
+			return nil, connect.NewError(connect.CodeNotFound, errSnapshotNotFound)
+		}
+		return nil, connect.NewError(connect.CodeInternal, errors.Wrap(err, "failed to get snapshot"))
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	var snapshot snapshotsv1.Snapshot
+	if err = snapshot.UnmarshalVT(data); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if s.now().UnixMilli() >= snapshot.ExpiresAt {
+		if err = bucket.Delete(ctx, secret); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to delete expired snapshot", "tenant", tenantID, "err", err)
+		}
+		return nil, connect.NewError(connect.CodeNotFound, errSnapshotNotFound)
+	}
+	return &snapshot, nil
+}
+
+func (s *Snapshots) getBucket(tenantID string) objstore.Bucket {
+	return tenantBucket(s.bucket, tenantID)
+}
+
+func tenantBucket(bucket objstore.Bucket, tenantID string) objstore.Bucket {
+	return objstore.NewPrefixedBucket(bucket, tenantID+"/snapshots")
+}
+
+func validateCreateRequest(req *snapshotsv1.CreateSnapshotRequest) (*typesv1.ProfileType, time.Duration, error) {
+	profileType, err := phlaremodel.ParseProfileTypeSelector(req.ProfileTypeID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if req.LabelSelector != "" {
+		if _, err = parser.ParseMetricSelector(req.LabelSelector); err != nil {
+			return nil, 0, fmt.Errorf("invalid label selector %q: %w", req.LabelSelector, err)
+		}
+	}
+	if req.Start >= req.End {
+		return nil, 0, fmt.Errorf("start must be before end")
+	}
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	switch {
+	case ttl < 0:
+		return nil, 0, fmt.Errorf("ttl must not be negative")
+	case ttl == 0:
+		ttl = defaultTTL
+	case ttl > maxTTL:
+		return nil, 0, fmt.Errorf("ttl must not exceed %s", maxTTL)
+	}
+	return profileType, ttl, nil
+}
+
+// newToken generates a token for a snapshot of the tenant. The token is the
+// URL-safe base64 encoded tenant ID, followed by the random secret the
+// snapshot object is named after.
+func newToken(tenantID string) (token, secret string, err error) {
+	b := make([]byte, secretSize)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret = hex.EncodeToString(b)
+	return base64.RawURLEncoding.EncodeToString([]byte(tenantID)) + "." + secret, secret, nil
+}
+
+func parseToken(token string) (tenantID, secret string, err error) {
+	encodedTenantID, secret, ok := strings.Cut(token, ".")
+	if !ok || len(secret) != 2*secretSize {
+		return "", "", errInvalidToken
+	}
+	if _, err = hex.DecodeString(secret); err != nil {
+		return "", "", errInvalidToken
+	}
+	t, err := base64.RawURLEncoding.DecodeString(encodedTenantID)
+	if err != nil {
+		return "", "", errInvalidToken
+	}
+	// The tenant ID is used as the object path prefix.
+	if err = tenant.ValidTenantID(string(t)); err != nil {
+		return "", "", errInvalidToken
+	}
+	return string(t), secret, nil
+}
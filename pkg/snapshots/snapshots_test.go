@@ -0,0 +1,180 @@
+package snapshots
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	thanosobjstore "github.com/thanos-io/objstore"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	snapshotsv1 "github.com/grafana/pyroscope/api/gen/proto/go/snapshots/v1"
+	phlareobjstore "github.com/grafana/pyroscope/pkg/objstore"
+	"github.com/grafana/pyroscope/pkg/tenant"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockquerierv1connect"
+)
+
+func TestSnapshots(t *testing.T) {
+	ctx := tenant.InjectTenantID(context.Background(), "tenant-a")
+	now := time.UnixMilli(1000)
+
+	newSnapshots := func(t *testing.T) *Snapshots {
+		querier := mockquerierv1connect.NewMockQuerierServiceClient(t)
+		querier.On("SelectMergeStacktraces", mock.Anything, mock.Anything).
+			Return(connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{
+				Flamegraph: &querierv1.FlameGraph{
+					Names:   []string{"total", "foo"},
+					Levels:  []*querierv1.Level{{Values: []int64{0, 100, 0, 0}}, {Values: []int64{0, 100, 100, 1}}},
+					Total:   100,
+					MaxSelf: 100,
+				},
+			}), nil).Maybe()
+		s := New(phlareobjstore.NewBucket(thanosobjstore.NewInMemBucket()), querier, log.NewNopLogger())
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	create := func(s *Snapshots, ctx context.Context, public bool) (*snapshotsv1.Snapshot, error) {
+		resp, err := s.Create(ctx, connect.NewRequest(&snapshotsv1.CreateSnapshotRequest{
+			Name:          "incident",
+			ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+			LabelSelector: `{service_name="checkout"}`,
+			Start:         0,
+			End:           1000,
+			TtlSeconds:    60,
+			Public:        public,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Snapshot, nil
+	}
+
+	getPublic := func(s *Snapshots, token string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/pyroscope/snapshots/"+token, nil), map[string]string{"token": token})
+		w := httptest.NewRecorder()
+		s.PublicHandler(w, req)
+		return w
+	}
+
+	t.Run("create and get snapshot", func(t *testing.T) {
+		s := newSnapshots(t)
+		created, err := create(s, ctx, false)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Token)
+		require.Equal(t, int64(61000), created.ExpiresAt)
+		require.Contains(t, created.FlamebearerProfile, `"names":["total","foo"]`)
+
+		resp, err := s.Get(ctx, connect.NewRequest(&snapshotsv1.GetSnapshotRequest{Token: created.Token}))
+		require.NoError(t, err)
+		require.Equal(t, created.String(), resp.Msg.Snapshot.String())
+
+		// Snapshots of other tenants are not visible.
+		_, err = s.Get(tenant.InjectTenantID(context.Background(), "tenant-b"), connect.NewRequest(&snapshotsv1.GetSnapshotRequest{Token: created.Token}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("public snapshot", func(t *testing.T) {
+		s := newSnapshots(t)
+		private, err := create(s, ctx, false)
+		require.NoError(t, err)
+		public, err := create(s, ctx, true)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusNotFound, getPublic(s, private.Token).Code)
+		w := getPublic(s, public.Token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), public.Token)
+	})
+
+	t.Run("expired snapshot", func(t *testing.T) {
+		s := newSnapshots(t)
+		created, err := create(s, ctx, true)
+		require.NoError(t, err)
+
+		s.now = func() time.Time { return now.Add(time.Minute) }
+		_, err = s.Get(ctx, connect.NewRequest(&snapshotsv1.GetSnapshotRequest{Token: created.Token}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		require.Equal(t, http.StatusNotFound, getPublic(s, created.Token).Code)
+	})
+
+	t.Run("expired snapshot is swept", func(t *testing.T) {
+		s := newSnapshots(t)
+		expired, err := create(s, ctx, true)
+		require.NoError(t, err)
+		resp, err := s.Create(ctx, connect.NewRequest(&snapshotsv1.CreateSnapshotRequest{
+			ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds",
+			Start:         0,
+			End:           1000,
+			TtlSeconds:    3600,
+		}))
+		require.NoError(t, err)
+		valid := resp.Msg.Snapshot
+
+		// The expired snapshot has never been read.
+		sweeper := NewSweeper(s.bucket, log.NewNopLogger())
+		sweeper.now = func() time.Time { return now.Add(time.Minute) }
+		require.NoError(t, sweeper.sweep(ctx))
+
+		// The snapshot object is removed from the bucket.
+		_, err = s.Get(ctx, connect.NewRequest(&snapshotsv1.GetSnapshotRequest{Token: expired.Token}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		_, err = s.Get(ctx, connect.NewRequest(&snapshotsv1.GetSnapshotRequest{Token: valid.Token}))
+		require.NoError(t, err)
+
+		var markers []string
+		require.NoError(t, s.bucket.Iter(ctx, expiryMarkersPrefix, func(name string) error {
+			markers = append(markers, name)
+			return nil
+		}))
+		require.Equal(t, []string{expiryMarkerPath(valid.ExpiresAt, valid.Token)}, markers)
+	})
+
+	t.Run("delete snapshot", func(t *testing.T) {
+		s := newSnapshots(t)
+		created, err := create(s, ctx, false)
+		require.NoError(t, err)
+
+		_, err = s.Delete(ctx, connect.NewRequest(&snapshotsv1.DeleteSnapshotRequest{Token: created.Token}))
+		require.NoError(t, err)
+		_, err = s.Get(ctx, connect.NewRequest(&snapshotsv1.GetSnapshotRequest{Token: created.Token}))
+		require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		s := newSnapshots(t)
+		for _, req := range []*snapshotsv1.CreateSnapshotRequest{
+			{ProfileTypeID: "invalid", Start: 0, End: 1000},
+			{ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds", Start: 1000, End: 1000},
+			{ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds", LabelSelector: "{", Start: 0, End: 1000},
+			{ProfileTypeID: "process_cpu:cpu:nanoseconds:cpu:nanoseconds", Start: 0, End: 1000, TtlSeconds: int64(maxTTL/time.Second) + 1},
+		} {
+			_, err := s.Create(ctx, connect.NewRequest(req))
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		}
+		for _, token := range []string{
+			"",
+			"invalid",
+			base64.RawURLEncoding.EncodeToString([]byte("tenant-a")) + ".invalid",
+			base64.RawURLEncoding.EncodeToString([]byte("../tenant-a")) + ".00000000000000000000000000000000",
+		} {
+			_, err := s.Get(ctx, connect.NewRequest(&snapshotsv1.GetSnapshotRequest{Token: token}))
+			require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			require.Equal(t, http.StatusNotFound, getPublic(s, token).Code)
+		}
+	})
+
+	t.Run("missing tenant id", func(t *testing.T) {
+		s := newSnapshots(t)
+		_, err := create(s, context.Background(), false)
+		require.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
@@ -0,0 +1,105 @@
+package snapshots
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+
+	"github.com/grafana/pyroscope/pkg/objstore"
+	"github.com/grafana/pyroscope/pkg/phlaredb/bucket"
+)
+
+const sweepInterval = time.Hour
+
+// expiryMarkersPrefix is the bucket prefix of the snapshot expiry markers:
+// empty objects named after the snapshot expiration time and token. The
+// markers allow expired snapshots to be found without reading them.
+const expiryMarkersPrefix = bucket.PyroscopeInternalsPrefix + "/snapshots/expiry/"
+
+func expiryMarkerPath(expiresAt int64, token string) string {
+	return path.Join(expiryMarkersPrefix, strconv.FormatInt(expiresAt, 10)+"."+token)
+}
+
+func parseExpiryMarker(name string) (expiresAt int64, token string, ok bool) {
+	ts, token, ok := strings.Cut(path.Base(name), ".")
+	if !ok {
+		return 0, "", false
+	}
+	expiresAt, err := strconv.ParseInt(ts, 10, 64)
+	return expiresAt, token, err == nil
+}
+
+// Sweeper periodically removes expired snapshots, including the ones
+// that are never accessed after they expire. The removal is idempotent,
+// therefore sweepers may run concurrently.
+type Sweeper struct {
+	services.Service
+
+	logger log.Logger
+	bucket objstore.Bucket
+	now    func() time.Time
+}
+
+func NewSweeper(bucket objstore.Bucket, logger log.Logger) *Sweeper {
+	s := &Sweeper{
+		logger: logger,
+		bucket: bucket,
+		now:    time.Now,
+	}
+	s.Service = services.NewTimerService(sweepInterval, nil, s.iteration, nil).WithName("snapshots sweeper")
+	return s
+}
+
+func (s *Sweeper) iteration(ctx context.Context) error {
+	if err := s.sweep(ctx); err != nil {
+		level.Warn(s.logger).Log("msg", "failed to remove expired snapshots", "err", err)
+	}
+	return nil
+}
+
+func (s *Sweeper) sweep(ctx context.Context) error {
+	now := s.now().UnixMilli()
+	var expired []string
+	err := s.bucket.Iter(ctx, expiryMarkersPrefix, func(name string) error {
+		if expiresAt, _, ok := parseExpiryMarker(name); ok && now >= expiresAt {
+			expired = append(expired, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var removed int
+	for _, name := range expired {
+		if err = s.remove(ctx, name); err != nil {
+			return err
+		}
+		removed++
+	}
+	if removed > 0 {
+		level.Info(s.logger).Log("msg", "expired snapshots removed", "snapshots", removed)
+	}
+	return nil
+}
+
+// remove deletes the snapshot of the expiry marker, and then the marker.
+// The snapshot may have been deleted already.
+func (s *Sweeper) remove(ctx context.Context, marker string) error {
+	_, token, _ := parseExpiryMarker(marker)
+	if tenantID, secret, err := parseToken(token); err == nil {
+		err = tenantBucket(s.bucket, tenantID).Delete(ctx, secret)
+		if err != nil && !s.bucket.IsObjNotFoundErr(err) {
+			return err
+		}
+	}
+	if err := s.bucket.Delete(ctx, marker); err != nil && !s.bucket.IsObjNotFoundErr(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Backend generates the response of the language model to the prompt.
+type Backend interface {
+	Complete(ctx context.Context, system, user string) (string, error)
+}
+
+const maxCompletionResponseSize = 4 << 20
+
+// openAICompatible uses the chat completions API, implemented by OpenAI,
+// and by most of the local model servers (e.g., Ollama, vLLM).
+type openAICompatible struct {
+	endpoint string
+	apiKey   string
+	model    string
+	client   *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	Temperature    float64        `json:"temperature"`
+	ResponseFormat responseFormat `json:"response_format"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (b *openAICompatible) Complete(ctx context.Context, system, user string) (string, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model: b.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		ResponseFormat: responseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(b.endpoint, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCompletionResponseSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	var completion chatCompletionResponse
+	if err = json.Unmarshal(data, &completion); err != nil {
+		return "", fmt.Errorf("invalid completion response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("completion response has no choices")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
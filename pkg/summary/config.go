@@ -0,0 +1,44 @@
+package summary
+
+import (
+	"errors"
+	"flag"
+	"net/url"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+type Config struct {
+	Endpoint     string         `yaml:"endpoint" category:"experimental"`
+	APIKey       flagext.Secret `yaml:"api_key" category:"experimental"`
+	Model        string         `yaml:"model" category:"experimental"`
+	Timeout      time.Duration  `yaml:"timeout" category:"experimental"`
+	MaxFunctions int            `yaml:"max_functions" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	const prefix = "profile-summary."
+	f.StringVar(&cfg.Endpoint, prefix+"endpoint", "", "Base URL of the OpenAI-compatible API used to summarize profiles, e.g., https://api.openai.com/v1, or the URL of a local model server. Profile summaries are disabled if empty. Summaries must also be enabled per tenant.")
+	f.Var(&cfg.APIKey, prefix+"api-key", "API key sent in the Authorization header to the profile summary API.")
+	f.StringVar(&cfg.Model, prefix+"model", "", "Model used to summarize profiles.")
+	f.DurationVar(&cfg.Timeout, prefix+"timeout", time.Minute, "Timeout of a request to the profile summary API.")
+	f.IntVar(&cfg.MaxFunctions, prefix+"max-functions", 20, "Maximum number of functions, call paths, and changes of the profile sent to the profile summary API.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("profile summary endpoint must be an absolute http(s) URL")
+	}
+	if cfg.Model == "" {
+		return errors.New("profile summary model is required")
+	}
+	if cfg.MaxFunctions < 1 {
+		return errors.New("profile summary max functions must be positive")
+	}
+	return nil
+}
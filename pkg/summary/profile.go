@@ -0,0 +1,133 @@
+package summary
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+)
+
+// Only the frames closest to the leaf of long call paths are kept.
+const maxPathDepth = 16
+
+// prunedProfile is the representation of the profile sent to the model:
+// the values are expressed as percentages of the profile total.
+type prunedProfile struct {
+	ProfileType  string           `json:"profile_type"`
+	Total        int64            `json:"total"`
+	TopFunctions []functionShare  `json:"top_functions"`
+	TopPaths     []pathShare      `json:"top_paths"`
+	Changes      []functionChange `json:"changes_from_baseline,omitempty"`
+}
+
+type functionShare struct {
+	Function     string  `json:"function"`
+	SelfPercent  float64 `json:"self_percent"`
+	TotalPercent float64 `json:"total_percent"`
+}
+
+type pathShare struct {
+	// Frames from the caller to the callee.
+	Path        []string `json:"path"`
+	SelfPercent float64  `json:"self_percent"`
+}
+
+type functionChange struct {
+	Function            string  `json:"function"`
+	BaselineSelfPercent float64 `json:"baseline_self_percent"`
+	SelfPercent         float64 `json:"self_percent"`
+}
+
+// Changes smaller than the threshold, in percentage
+// points of the profile total, are not reported.
+const minChangePercent = 0.1
+
+func pruneProfile(profileType string, tree, baseline *phlaremodel.Tree, limit int) *prunedProfile {
+	total := tree.Total()
+	p := &prunedProfile{
+		ProfileType:  profileType,
+		Total:        total,
+		TopFunctions: make([]functionShare, 0, limit),
+	}
+	functions := tree.FunctionTotals()
+	for _, fn := range functions[:min(limit, len(functions))] {
+		p.TopFunctions = append(p.TopFunctions, functionShare{
+			Function:     fn.Name,
+			SelfPercent:  percent(fn.Self, total),
+			TotalPercent: percent(fn.Total, total),
+		})
+	}
+	p.TopPaths = topPaths(tree, total, limit)
+	if baseline != nil {
+		p.Changes = changes(functions, baseline, total, limit)
+	}
+	return p
+}
+
+func topPaths(tree *phlaremodel.Tree, total int64, limit int) []pathShare {
+	type path struct {
+		frames []string
+		self   int64
+	}
+	var paths []path
+	tree.IterateStacks(func(_ string, self int64, stack []string) {
+		frames := make([]string, 0, min(len(stack), maxPathDepth))
+		// The stack is ordered from the leaf to the root.
+		for i := min(len(stack), maxPathDepth) - 1; i >= 0; i-- {
+			if stack[i] != "" {
+				frames = append(frames, stack[i])
+			}
+		}
+		paths = append(paths, path{frames: frames, self: self})
+	})
+	slices.SortFunc(paths, func(a, b path) int { return cmp.Compare(b.self, a.self) })
+	shares := make([]pathShare, 0, min(limit, len(paths)))
+	for _, p := range paths[:min(limit, len(paths))] {
+		shares = append(shares, pathShare{Path: p.frames, SelfPercent: percent(p.self, total)})
+	}
+	return shares
+}
+
+func changes(functions []phlaremodel.FunctionTotal, baseline *phlaremodel.Tree, total int64, limit int) []functionChange {
+	baselineTotal := baseline.Total()
+	baselineSelf := make(map[string]int64)
+	for _, fn := range baseline.FunctionTotals() {
+		baselineSelf[fn.Name] = fn.Self
+	}
+	var result []functionChange
+	add := func(name string, self int64) {
+		c := functionChange{
+			Function:            name,
+			BaselineSelfPercent: percent(baselineSelf[name], baselineTotal),
+			SelfPercent:         percent(self, total),
+		}
+		if math.Abs(c.SelfPercent-c.BaselineSelfPercent) >= minChangePercent {
+			result = append(result, c)
+		}
+	}
+	for _, fn := range functions {
+		add(fn.Name, fn.Self)
+		delete(baselineSelf, fn.Name)
+	}
+	// Functions missing in the profile.
+	for name := range baselineSelf {
+		add(name, 0)
+	}
+	slices.SortFunc(result, func(a, b functionChange) int {
+		da := math.Abs(a.SelfPercent - a.BaselineSelfPercent)
+		db := math.Abs(b.SelfPercent - b.BaselineSelfPercent)
+		if c := cmp.Compare(db, da); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Function, b.Function)
+	})
+	return result[:min(limit, len(result))]
+}
+
+func percent(v, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(v)/float64(total)*10000) / 100
+}
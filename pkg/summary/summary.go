@@ -0,0 +1,192 @@
+// Package summary implements summarization of profiles with a language
+// model.
+//
+// The merged profile is pruned to the top functions and call paths, and,
+// if a baseline is given, to the largest changes from the baseline. The
+// pruned profile is sent to an OpenAI-compatible chat completions API,
+// which responds with a short summary and the suspected hotspots.
+// Summaries are disabled by default, and must be enabled per tenant.
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/querier/v1/querierv1connect"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const maxSummaryRequestSize = 1 << 20
+
+const systemPrompt = `You are a performance engineer analyzing a continuous profiling profile.
+The user message is a JSON document describing the profile: the profile type, the total value,
+the top functions and call paths with their share of the total in percent, and, if present,
+the largest changes of the self share of functions compared to a baseline profile.
+Call paths are ordered from the caller to the callee.
+Respond with a JSON object with the following fields:
+  "summary": a short summary of where the resources are spent, and of the changes from the baseline if present;
+  "hotspots": an array of the suspected hotspots, each an object with the "function" name and the "reason" it is suspected.
+Only mention functions present in the profile.`
+
+type Overrides interface {
+	ProfileSummaryEnabled(tenantID string) bool
+}
+
+type Service struct {
+	logger       log.Logger
+	overrides    Overrides
+	querier      querierv1connect.QuerierServiceClient
+	backend      Backend
+	model        string
+	maxFunctions int
+}
+
+func New(cfg Config, overrides Overrides, querier querierv1connect.QuerierServiceClient, logger log.Logger) *Service {
+	return &Service{
+		logger:    logger,
+		overrides: overrides,
+		querier:   querier,
+		backend: &openAICompatible{
+			endpoint: cfg.Endpoint,
+			apiKey:   cfg.APIKey.String(),
+			model:    cfg.Model,
+			client:   &http.Client{Timeout: cfg.Timeout},
+		},
+		model:        cfg.Model,
+		maxFunctions: cfg.MaxFunctions,
+	}
+}
+
+type query struct {
+	LabelSelector string `json:"labelSelector"`
+	// Unix timestamps in milliseconds.
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+type summaryRequest struct {
+	ProfileTypeID string `json:"profileTypeID"`
+	query
+	// Optional baseline the profile is compared to.
+	Baseline *query `json:"baseline,omitempty"`
+}
+
+type Hotspot struct {
+	Function string `json:"function"`
+	Reason   string `json:"reason"`
+}
+
+type summaryResponse struct {
+	Summary  string    `json:"summary"`
+	Hotspots []Hotspot `json:"hotspots"`
+	Model    string    `json:"model"`
+}
+
+// Handler summarizes the merged profile of the query.
+func (s *Service) Handler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	if !s.overrides.ProfileSummaryEnabled(tenantID) {
+		httputil.Error(w, connect.NewError(connect.CodePermissionDenied, errors.New("profile summaries are not enabled for the tenant")))
+		return
+	}
+	var req summaryRequest
+	if err = json.NewDecoder(io.LimitReader(r.Body, maxSummaryRequestSize)).Decode(&req); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+	if err = req.validate(); err != nil {
+		httputil.Error(w, connect.NewError(connect.CodeInvalidArgument, err))
+		return
+	}
+
+	tree, err := s.selectTree(r.Context(), req.ProfileTypeID, req.query)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	var baseline *phlaremodel.Tree
+	if req.Baseline != nil {
+		if baseline, err = s.selectTree(r.Context(), req.ProfileTypeID, *req.Baseline); err != nil {
+			httputil.Error(w, err)
+			return
+		}
+	}
+	prompt, err := json.Marshal(pruneProfile(req.ProfileTypeID, tree, baseline, s.maxFunctions))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	completion, err := s.backend.Complete(r.Context(), systemPrompt, string(prompt))
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "failed to summarize profile", "tenant", tenantID, "err", err)
+		httputil.Error(w, connect.NewError(connect.CodeUnavailable, fmt.Errorf("failed to summarize profile: %w", err)))
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.parseCompletion(completion))
+}
+
+func (r *summaryRequest) validate() error {
+	if r.ProfileTypeID == "" {
+		return errors.New("profileTypeID is required")
+	}
+	if _, err := phlaremodel.ParseProfileTypeSelector(r.ProfileTypeID); err != nil {
+		return err
+	}
+	if r.End <= r.Start {
+		return errors.New("end must be after start")
+	}
+	if r.Baseline != nil && r.Baseline.End <= r.Baseline.Start {
+		return errors.New("baseline end must be after start")
+	}
+	return nil
+}
+
+func (s *Service) selectTree(ctx context.Context, profileTypeID string, q query) (*phlaremodel.Tree, error) {
+	labelSelector := q.LabelSelector
+	if labelSelector == "" {
+		labelSelector = "{}"
+	}
+	resp, err := s.querier.SelectMergeStacktraces(ctx, connect.NewRequest(&querierv1.SelectMergeStacktracesRequest{
+		ProfileTypeID: profileTypeID,
+		LabelSelector: labelSelector,
+		Start:         q.Start,
+		End:           q.End,
+		Format:        querierv1.ProfileFormat_PROFILE_FORMAT_TREE,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return phlaremodel.UnmarshalTree(resp.Msg.Tree)
+}
+
+// parseCompletion parses the structured response of the model. Models
+// that do not follow the response format instructions respond with text,
+// which is returned as the summary.
+func (s *Service) parseCompletion(completion string) summaryResponse {
+	resp := summaryResponse{Model: s.model}
+	if err := json.Unmarshal([]byte(completion), &resp); err != nil || resp.Summary == "" {
+		resp = summaryResponse{Summary: strings.TrimSpace(completion)}
+	}
+	resp.Model = s.model
+	if resp.Hotspots == nil {
+		resp.Hotspots = []Hotspot{}
+	}
+	return resp
+}
@@ -0,0 +1,162 @@
+package summary
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	querierv1 "github.com/grafana/pyroscope/api/gen/proto/go/querier/v1"
+	phlaremodel "github.com/grafana/pyroscope/pkg/model"
+	"github.com/grafana/pyroscope/pkg/test/mocks/mockquerierv1connect"
+)
+
+type overrides map[string]bool
+
+func (o overrides) ProfileSummaryEnabled(tenantID string) bool { return o[tenantID] }
+
+const testProfileType = "process_cpu:cpu:nanoseconds:cpu:nanoseconds"
+
+func summarize(t *testing.T, s *Service, tenantID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/pyroscope/summary", strings.NewReader(body))
+	req = req.WithContext(user.InjectOrgID(req.Context(), tenantID))
+	rec := httptest.NewRecorder()
+	s.Handler(rec, req)
+	return rec
+}
+
+func Test_Summary(t *testing.T) {
+	var completionRequest chatCompletionRequest
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&completionRequest))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": `{"summary":"Most of the time is spent in b.","hotspots":[{"function":"b","reason":"Self time grew."}]}`,
+				},
+			}},
+		})
+	}))
+	defer llm.Close()
+
+	current := new(phlaremodel.Tree)
+	current.InsertStack(3, "main", "a", "b")
+	current.InsertStack(1, "main", "c")
+	baseline := new(phlaremodel.Tree)
+	baseline.InsertStack(1, "main", "a", "b")
+	baseline.InsertStack(1, "main", "c")
+
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	client.On("SelectMergeStacktraces", mock.Anything, mock.MatchedBy(func(r *connect.Request[querierv1.SelectMergeStacktracesRequest]) bool {
+		return r.Msg.Start == 1000
+	})).Return(connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{Tree: current.Bytes(-1)}), nil)
+	client.On("SelectMergeStacktraces", mock.Anything, mock.MatchedBy(func(r *connect.Request[querierv1.SelectMergeStacktracesRequest]) bool {
+		return r.Msg.Start == 0
+	})).Return(connect.NewResponse(&querierv1.SelectMergeStacktracesResponse{Tree: baseline.Bytes(-1)}), nil)
+
+	var cfg Config
+	cfg.Endpoint = llm.URL + "/v1"
+	require.NoError(t, cfg.APIKey.Set("secret"))
+	cfg.Model = "test-model"
+	cfg.Timeout = time.Second
+	cfg.MaxFunctions = 2
+	s := New(cfg, overrides{"tenant": true}, client, log.NewNopLogger())
+
+	rec := summarize(t, s, "tenant", `{"profileTypeID":"`+testProfileType+`","labelSelector":"{service_name=\"svc\"}","start":1000,"end":2000,"baseline":{"start":0,"end":1000}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp summaryResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, summaryResponse{
+		Summary:  "Most of the time is spent in b.",
+		Hotspots: []Hotspot{{Function: "b", Reason: "Self time grew."}},
+		Model:    "test-model",
+	}, resp)
+
+	assert.Equal(t, "test-model", completionRequest.Model)
+	require.Len(t, completionRequest.Messages, 2)
+	var prompt prunedProfile
+	require.NoError(t, json.Unmarshal([]byte(completionRequest.Messages[1].Content), &prompt))
+	assert.Equal(t, prunedProfile{
+		ProfileType: testProfileType,
+		Total:       4,
+		TopFunctions: []functionShare{
+			{Function: "b", SelfPercent: 75, TotalPercent: 75},
+			{Function: "c", SelfPercent: 25, TotalPercent: 25},
+		},
+		TopPaths: []pathShare{
+			{Path: []string{"main", "a", "b"}, SelfPercent: 75},
+			{Path: []string{"main", "c"}, SelfPercent: 25},
+		},
+		Changes: []functionChange{
+			{Function: "b", BaselineSelfPercent: 50, SelfPercent: 75},
+			{Function: "c", BaselineSelfPercent: 50, SelfPercent: 25},
+		},
+	}, prompt)
+}
+
+func Test_Summary_TextCompletion(t *testing.T) {
+	s := &Service{
+		overrides: overrides{"tenant": true},
+		model:     "test-model",
+	}
+	assert.Equal(t, summaryResponse{
+		Summary:  "The profile is dominated by b.",
+		Hotspots: []Hotspot{},
+		Model:    "test-model",
+	}, s.parseCompletion(" The profile is dominated by b.\n"))
+}
+
+func Test_Summary_Disabled(t *testing.T) {
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	s := New(Config{Endpoint: "http://localhost", Model: "m", MaxFunctions: 1}, overrides{}, client, log.NewNopLogger())
+	rec := summarize(t, s, "tenant", `{"profileTypeID":"`+testProfileType+`","start":1000,"end":2000}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func Test_Summary_InvalidRequest(t *testing.T) {
+	client := mockquerierv1connect.NewMockQuerierServiceClient(t)
+	s := New(Config{Endpoint: "http://localhost", Model: "m", MaxFunctions: 1}, overrides{"tenant": true}, client, log.NewNopLogger())
+	for _, body := range []string{
+		`{`,
+		`{"start":1000,"end":2000}`,
+		`{"profileTypeID":"invalid","start":1000,"end":2000}`,
+		`{"profileTypeID":"` + testProfileType + `","start":2000,"end":1000}`,
+		`{"profileTypeID":"` + testProfileType + `","start":1000,"end":2000,"baseline":{}}`,
+	} {
+		rec := summarize(t, s, "tenant", body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		cfg   Config
+		valid bool
+	}{
+		{cfg: Config{}, valid: true},
+		{cfg: Config{Endpoint: "http://localhost:11434/v1", Model: "llama3", MaxFunctions: 20}, valid: true},
+		{cfg: Config{Endpoint: "localhost:11434", Model: "llama3", MaxFunctions: 20}},
+		{cfg: Config{Endpoint: "https://api.openai.com/v1", MaxFunctions: 20}},
+		{cfg: Config{Endpoint: "https://api.openai.com/v1", Model: "gpt-4o-mini"}},
+	} {
+		err := tc.cfg.Validate()
+		if tc.valid {
+			assert.NoError(t, err, tc.cfg.Endpoint)
+		} else {
+			assert.Error(t, err, tc.cfg.Endpoint)
+		}
+	}
+}
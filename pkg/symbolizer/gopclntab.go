@@ -0,0 +1,82 @@
+package symbolizer
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errNoPCLNTab = errors.New("no .gopclntab section")
+
+// goTable resolves the addresses of a Go binary to the function names,
+// file names, and line numbers with the symbol table the Go runtime
+// relies on for stack unwinding (.gopclntab). Unlike the ELF symbol
+// table and DWARF, it is not removed when the binary is stripped.
+type goTable struct {
+	table *gosym.Table
+	// The virtual address at which the executable segment
+	// starts, and the offset of the segment in the file.
+	textVaddr  uint64
+	textOffset uint64
+	// Position-independent executables are loaded at
+	// an address that is not known until run time.
+	relocatable bool
+}
+
+func openGoTable(r io.ReaderAt) (*goTable, error) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("opening ELF file: %w", err)
+	}
+	defer f.Close()
+	pclntab := f.Section(".gopclntab")
+	if pclntab == nil {
+		return nil, errNoPCLNTab
+	}
+	text := f.Section(".text")
+	if text == nil {
+		return nil, errors.New("no .text section")
+	}
+	data, err := pclntab.Data()
+	if err != nil {
+		return nil, fmt.Errorf("reading .gopclntab section: %w", err)
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.Addr))
+	if err != nil {
+		return nil, fmt.Errorf("parsing .gopclntab section: %w", err)
+	}
+	t := &goTable{
+		table:       table,
+		relocatable: f.Type == elf.ET_DYN,
+	}
+	for _, p := range f.Progs {
+		if p.Type == elf.PT_LOAD && p.Flags&elf.PF_X != 0 {
+			t.textVaddr = p.Vaddr
+			t.textOffset = p.Off
+			break
+		}
+	}
+	return t, nil
+}
+
+// resolve returns the function the address belongs to. The address is
+// the run time address within the mapping of the executable segment.
+func (t *goTable) resolve(addr, memoryStart, fileOffset uint64) (fn, file string, line int, ok bool) {
+	pc := addr
+	if t.relocatable {
+		// The mapping address is translated to the file offset,
+		// and then to the virtual address of the segment.
+		if addr < memoryStart {
+			return "", "", 0, false
+		}
+		pc = addr - memoryStart + fileOffset - t.textOffset + t.textVaddr
+	}
+	f := t.table.PCToFunc(pc)
+	if f == nil {
+		return "", "", 0, false
+	}
+	file, line, _ = t.table.PCToLine(pc)
+	return f.Name, file, line, true
+}
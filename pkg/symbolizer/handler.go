@@ -0,0 +1,56 @@
+package symbolizer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/pyroscope/pkg/tenant"
+	httputil "github.com/grafana/pyroscope/pkg/util/http"
+)
+
+const maxBuildIDLength = 256
+
+// ServeHTTP handles the upload of a binary, identified by the build ID
+// query parameter. The request body is either a Go ELF binary, which must
+// include the .gopclntab section, the PDB file of a Windows module, or a
+// Wasm module with the name section. A binary uploaded for the build ID replaces
+// the previous one: other instances may use the cached symbol table of
+// the previous binary until the cache TTL expires.
+func (s *Symbolizer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.ExtractTenantIDFromContext(r.Context())
+	if err != nil {
+		httputil.ErrorWithStatus(w, err, http.StatusUnauthorized)
+		return
+	}
+	buildID := r.URL.Query().Get("build_id")
+	if buildID == "" || len(buildID) > maxBuildIDLength {
+		httputil.ErrorWithStatus(w, errors.New("invalid build_id"), http.StatusBadRequest)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.config.MaxBinarySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			httputil.ErrorWithStatus(w, err, http.StatusRequestEntityTooLarge)
+			return
+		}
+		httputil.ErrorWithStatus(w, err, http.StatusBadRequest)
+		return
+	}
+	if _, err = openTable(bytes.NewReader(data), int64(len(data))); err != nil {
+		httputil.ErrorWithStatus(w, fmt.Errorf("invalid binary: %w", err), http.StatusBadRequest)
+		return
+	}
+	if err = s.bucket.Upload(r.Context(), objectPath(tenantID, buildID), bytes.NewReader(data)); err != nil {
+		level.Error(s.logger).Log("msg", "failed to upload binary", "tenant", tenantID, "build_id", buildID, "err", err)
+		httputil.Error(w, err)
+		return
+	}
+	s.tables.Remove(objectPath(tenantID, buildID))
+	w.WriteHeader(http.StatusNoContent)
+}
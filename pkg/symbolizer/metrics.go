@@ -0,0 +1,34 @@
+package symbolizer
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	loadResultLoaded   = "loaded"
+	loadResultNotFound = "not_found"
+	loadResultError    = "error"
+
+	locationResultResolved   = "resolved"
+	locationResultUnresolved = "unresolved"
+)
+
+type metrics struct {
+	binaryLoads *prometheus.CounterVec
+	locations   *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		binaryLoads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_symbolizer_binary_loads_total",
+			Help: "Total number of attempts to load the symbol table of a binary, by result.",
+		}, []string{"result"}),
+		locations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_symbolizer_locations_total",
+			Help: "Total number of profile locations without symbols looked up in the binary symbol tables, by result.",
+		}, []string{"result"}),
+	}
+	if reg != nil {
+		reg.MustRegister(m.binaryLoads, m.locations)
+	}
+	return m
+}
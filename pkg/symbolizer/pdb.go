@@ -0,0 +1,265 @@
+package symbolizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+var (
+	msfMagic = []byte("Microsoft C/C++ MSF 7.00\r\n\x1aDS\x00\x00\x00")
+
+	errInvalidMSF = errors.New("invalid MSF file")
+)
+
+const (
+	dbiStream           = 3
+	dbiHeaderSize       = 64
+	dbgHeaderSectionHdr = 5
+	sectionHeaderSize   = 40
+	noStream            = 0xFFFF
+
+	symPub32           = 0x110E
+	imageScnMemExecute = 0x20000000
+)
+
+// msfFile provides access to the streams of a Multi-Stream File,
+// the container format of the PDB files.
+type msfFile struct {
+	r         io.ReaderAt
+	blockSize uint32
+	numBlocks uint32
+	sizes     []uint32
+	blocks    [][]uint32
+}
+
+func isMSF(r io.ReaderAt) bool {
+	magic := make([]byte, len(msfMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(magic, msfMagic)
+}
+
+// openMSF opens the file of the given size: the blocks
+// and the streams must fit in the file.
+func openMSF(r io.ReaderAt, size int64) (*msfFile, error) {
+	var sb [56]byte
+	if _, err := r.ReadAt(sb[:], 0); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(sb[:len(msfMagic)], msfMagic) {
+		return nil, errInvalidMSF
+	}
+	f := &msfFile{
+		r:         r,
+		blockSize: binary.LittleEndian.Uint32(sb[32:]),
+		numBlocks: binary.LittleEndian.Uint32(sb[40:]),
+	}
+	switch f.blockSize {
+	case 512, 1024, 2048, 4096:
+	default:
+		return nil, fmt.Errorf("%w: block size %d", errInvalidMSF, f.blockSize)
+	}
+	if size < 0 || uint64(f.numBlocks)*uint64(f.blockSize) > uint64(size) {
+		return nil, fmt.Errorf("%w: %d blocks exceed the file size %d", errInvalidMSF, f.numBlocks, size)
+	}
+	dirSize := binary.LittleEndian.Uint32(sb[44:])
+	blockMapAddr := binary.LittleEndian.Uint32(sb[52:])
+	dirBlocks, err := f.readUint32s(blockMapAddr, f.blockCount(dirSize))
+	if err != nil {
+		return nil, err
+	}
+	dir, err := f.readBlocks(dirBlocks, dirSize)
+	if err != nil {
+		return nil, err
+	}
+	return f, f.parseDirectory(dir)
+}
+
+func (f *msfFile) parseDirectory(dir []byte) error {
+	if len(dir) < 4 {
+		return errInvalidMSF
+	}
+	n := binary.LittleEndian.Uint32(dir)
+	dir = dir[4:]
+	if uint64(len(dir)) < uint64(n)*4 {
+		return errInvalidMSF
+	}
+	f.sizes = make([]uint32, n)
+	f.blocks = make([][]uint32, n)
+	for i := range f.sizes {
+		if f.sizes[i] = binary.LittleEndian.Uint32(dir[i*4:]); f.sizes[i] == 0xFFFFFFFF {
+			f.sizes[i] = 0
+		}
+	}
+	dir = dir[n*4:]
+	for i, size := range f.sizes {
+		c := f.blockCount(size)
+		if uint64(len(dir)) < uint64(c)*4 {
+			return errInvalidMSF
+		}
+		f.blocks[i] = make([]uint32, c)
+		for j := range f.blocks[i] {
+			f.blocks[i][j] = binary.LittleEndian.Uint32(dir[j*4:])
+		}
+		dir = dir[c*4:]
+	}
+	return nil
+}
+
+func (f *msfFile) blockCount(size uint32) uint32 {
+	return (size + f.blockSize - 1) / f.blockSize
+}
+
+func (f *msfFile) readUint32s(block, n uint32) ([]uint32, error) {
+	if uint64(n)*4 > uint64(f.blockSize) {
+		return nil, errInvalidMSF
+	}
+	b, err := f.readBlocks([]uint32{block}, n*4)
+	if err != nil {
+		return nil, err
+	}
+	v := make([]uint32, n)
+	for i := range v {
+		v[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	return v, nil
+}
+
+func (f *msfFile) readBlocks(blocks []uint32, size uint32) ([]byte, error) {
+	if uint64(size) > uint64(len(blocks))*uint64(f.blockSize) ||
+		uint64(size) > uint64(f.numBlocks)*uint64(f.blockSize) {
+		return nil, errInvalidMSF
+	}
+	b := make([]byte, size)
+	for i, block := range blocks {
+		if block >= f.numBlocks {
+			return nil, fmt.Errorf("%w: block %d out of range", errInvalidMSF, block)
+		}
+		lo := uint32(i) * f.blockSize
+		hi := min(lo+f.blockSize, size)
+		if _, err := f.r.ReadAt(b[lo:hi], int64(block)*int64(f.blockSize)); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (f *msfFile) stream(i int) ([]byte, error) {
+	if i < 0 || i >= len(f.sizes) {
+		return nil, fmt.Errorf("%w: stream %d not found", errInvalidMSF, i)
+	}
+	return f.readBlocks(f.blocks[i], f.sizes[i])
+}
+
+// pdbTable resolves the addresses of a Windows module to the names of the
+// public functions recorded in the PDB file. The names are decorated, and
+// no file names or line numbers are resolved: these require the module
+// debug information, which is not loaded.
+type pdbTable struct {
+	symbols []pdbSymbol
+}
+
+type pdbSymbol struct {
+	rva  uint32
+	end  uint32
+	name string
+}
+
+type pdbSection struct {
+	rva, size, flags uint32
+}
+
+func openPDBTable(r io.ReaderAt, size int64) (*pdbTable, error) {
+	f, err := openMSF(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening PDB file: %w", err)
+	}
+	dbi, err := f.stream(dbiStream)
+	if err != nil {
+		return nil, fmt.Errorf("reading DBI stream: %w", err)
+	}
+	if len(dbi) < dbiHeaderSize {
+		return nil, errors.New("invalid DBI stream")
+	}
+	symRecords := int(binary.LittleEndian.Uint16(dbi[20:]))
+	offset := dbiHeaderSize
+	for _, o := range []int{24, 28, 32, 36, 40, 52} {
+		offset += int(int32(binary.LittleEndian.Uint32(dbi[o:])))
+	}
+	dbgHeaderSize := int(int32(binary.LittleEndian.Uint32(dbi[48:])))
+	if offset < dbiHeaderSize || offset+dbgHeaderSize > len(dbi) || dbgHeaderSize < (dbgHeaderSectionHdr+1)*2 {
+		return nil, errors.New("no section headers in DBI stream")
+	}
+	sectionHdr := int(binary.LittleEndian.Uint16(dbi[offset+dbgHeaderSectionHdr*2:]))
+	if sectionHdr == noStream {
+		return nil, errors.New("no section headers in DBI stream")
+	}
+	b, err := f.stream(sectionHdr)
+	if err != nil {
+		return nil, fmt.Errorf("reading section headers: %w", err)
+	}
+	sections := make([]pdbSection, len(b)/sectionHeaderSize)
+	for i := range sections {
+		h := b[i*sectionHeaderSize:]
+		sections[i] = pdbSection{
+			size:  binary.LittleEndian.Uint32(h[8:]),
+			rva:   binary.LittleEndian.Uint32(h[12:]),
+			flags: binary.LittleEndian.Uint32(h[36:]),
+		}
+	}
+	if b, err = f.stream(symRecords); err != nil {
+		return nil, fmt.Errorf("reading symbol records: %w", err)
+	}
+	t := new(pdbTable)
+	for len(b) >= 4 {
+		n := int(binary.LittleEndian.Uint16(b)) + 2
+		if n < 4 || n > len(b) {
+			break
+		}
+		if binary.LittleEndian.Uint16(b[2:]) == symPub32 && n >= 15 {
+			offset := binary.LittleEndian.Uint32(b[8:])
+			segment := int(binary.LittleEndian.Uint16(b[12:]))
+			name := b[14:n]
+			if i := bytes.IndexByte(name, 0); i >= 0 {
+				name = name[:i]
+			}
+			// Only the symbols of the code sections are resolved.
+			if segment > 0 && segment <= len(sections) && sections[segment-1].flags&imageScnMemExecute != 0 {
+				s := sections[segment-1]
+				t.symbols = append(t.symbols, pdbSymbol{
+					rva:  s.rva + offset,
+					end:  s.rva + s.size,
+					name: string(name),
+				})
+			}
+		}
+		b = b[n:]
+	}
+	if len(t.symbols) == 0 {
+		return nil, errors.New("no public symbols in PDB file")
+	}
+	sort.Slice(t.symbols, func(i, j int) bool {
+		return t.symbols[i].rva < t.symbols[j].rva
+	})
+	return t, nil
+}
+
+// resolve returns the function the address belongs to: the closest public
+// symbol preceding the address in the same section. The address is the run
+// time address within the mapping of the module loaded at memoryStart.
+func (t *pdbTable) resolve(addr, memoryStart, _ uint64) (fn, file string, line int, ok bool) {
+	if addr < memoryStart || addr-memoryStart > 0xFFFFFFFF {
+		return "", "", 0, false
+	}
+	rva := uint32(addr - memoryStart)
+	i := sort.Search(len(t.symbols), func(i int) bool { return t.symbols[i].rva > rva }) - 1
+	if i < 0 || rva >= t.symbols[i].end {
+		return "", "", 0, false
+	}
+	return t.symbols[i].name, "", 0, true
+}
@@ -0,0 +1,152 @@
+package symbolizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBlockSize = 512
+
+// buildMSF lays out the streams in a Multi-Stream File: the superblock,
+// the stream blocks, the directory blocks, and the directory block map.
+func buildMSF(streams [][]byte) []byte {
+	blocks := [][]byte{nil} // The superblock.
+	add := func(b []byte) []uint32 {
+		var idx []uint32
+		for len(b) > 0 {
+			n := min(len(b), testBlockSize)
+			idx = append(idx, uint32(len(blocks)))
+			blocks = append(blocks, b[:n])
+			b = b[n:]
+		}
+		return idx
+	}
+	dir := binary.LittleEndian.AppendUint32(nil, uint32(len(streams)))
+	for _, s := range streams {
+		dir = binary.LittleEndian.AppendUint32(dir, uint32(len(s)))
+	}
+	for _, s := range streams {
+		for _, b := range add(s) {
+			dir = binary.LittleEndian.AppendUint32(dir, b)
+		}
+	}
+	var blockMap []byte
+	for _, b := range add(dir) {
+		blockMap = binary.LittleEndian.AppendUint32(blockMap, b)
+	}
+	blockMapAddr := add(blockMap)[0]
+
+	sb := append([]byte{}, msfMagic...)
+	for _, v := range []uint32{testBlockSize, 1, uint32(len(blocks)), uint32(len(dir)), 0, blockMapAddr} {
+		sb = binary.LittleEndian.AppendUint32(sb, v)
+	}
+	blocks[0] = sb
+	var file bytes.Buffer
+	for _, b := range blocks {
+		file.Write(b)
+		file.Write(make([]byte, testBlockSize-len(b)))
+	}
+	return file.Bytes()
+}
+
+func buildPDB() []byte {
+	dbi := make([]byte, dbiHeaderSize)
+	binary.LittleEndian.PutUint16(dbi[20:], 4)    // Symbol records stream.
+	binary.LittleEndian.PutUint32(dbi[48:], 11*2) // Optional debug header size.
+	for i := 0; i < 11; i++ {
+		stream := uint16(noStream)
+		if i == dbgHeaderSectionHdr {
+			stream = 5
+		}
+		dbi = binary.LittleEndian.AppendUint16(dbi, stream)
+	}
+
+	var symbols []byte
+	pub := func(segment uint16, offset uint32, name string) {
+		rec := binary.LittleEndian.AppendUint16(nil, symPub32)
+		rec = binary.LittleEndian.AppendUint32(rec, 2)
+		rec = binary.LittleEndian.AppendUint32(rec, offset)
+		rec = binary.LittleEndian.AppendUint16(rec, segment)
+		rec = append(rec, name...)
+		rec = append(rec, 0)
+		symbols = binary.LittleEndian.AppendUint16(symbols, uint16(len(rec)))
+		symbols = append(symbols, rec...)
+	}
+	pub(1, 0x100, "helper")
+	pub(1, 0x10, "main")
+	pub(2, 0x0, "g_data")
+
+	var sections []byte
+	section := func(name string, rva, size, flags uint32) {
+		h := make([]byte, sectionHeaderSize)
+		copy(h, name)
+		binary.LittleEndian.PutUint32(h[8:], size)
+		binary.LittleEndian.PutUint32(h[12:], rva)
+		binary.LittleEndian.PutUint32(h[36:], flags)
+		sections = append(sections, h...)
+	}
+	section(".text", 0x1000, 0x1000, imageScnMemExecute)
+	section(".data", 0x3000, 0x100, 0)
+
+	// A stream larger than a block.
+	info := bytes.Repeat([]byte{1}, 3*testBlockSize/2)
+	return buildMSF([][]byte{nil, info, nil, dbi, symbols, sections})
+}
+
+func Test_PDBTable(t *testing.T) {
+	r := bytes.NewReader(buildPDB())
+	require.True(t, isMSF(r))
+	table, err := openTable(r, r.Size())
+	require.NoError(t, err)
+
+	const base = 0x140000000
+	for _, tc := range []struct {
+		addr uint64
+		want string
+	}{
+		{addr: base + 0x1010, want: "main"},
+		{addr: base + 0x10ff, want: "main"},
+		{addr: base + 0x1150, want: "helper"},
+		{addr: base + 0x1000},
+		{addr: base + 0x2100},
+		{addr: base + 0x3000},
+		{addr: base - 1},
+	} {
+		fn, _, _, ok := table.resolve(tc.addr, base, 0)
+		assert.Equal(t, tc.want != "", ok, "%#x", tc.addr)
+		assert.Equal(t, tc.want, fn, "%#x", tc.addr)
+	}
+}
+
+func Test_PDBTable_Invalid(t *testing.T) {
+	b := buildPDB()
+	// Block size.
+	binary.LittleEndian.PutUint32(b[32:], 100)
+	_, err := openTable(bytes.NewReader(b), int64(len(b)))
+	assert.Error(t, err)
+
+	b = buildMSF([][]byte{nil, nil, nil, make([]byte, dbiHeaderSize)})
+	_, err = openTable(bytes.NewReader(b), int64(len(b)))
+	assert.Error(t, err)
+
+	_, err = openTable(bytes.NewReader(msfMagic), int64(len(msfMagic)))
+	assert.Error(t, err)
+
+	// Blocks beyond the end of the file.
+	b = buildPDB()
+	_, err = openTable(bytes.NewReader(b), int64(len(b)-testBlockSize))
+	assert.Error(t, err)
+	binary.LittleEndian.PutUint32(b[40:], 1<<30)
+	_, err = openTable(bytes.NewReader(b), int64(len(b)))
+	assert.Error(t, err)
+
+	// Streams larger than the file are not read.
+	f, err := openMSF(bytes.NewReader(buildPDB()), int64(len(buildPDB())))
+	require.NoError(t, err)
+	_, err = f.readBlocks(make([]uint32, 1<<20), 1<<20*testBlockSize)
+	assert.ErrorIs(t, err, errInvalidMSF)
+}
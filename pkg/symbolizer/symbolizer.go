@@ -0,0 +1,316 @@
+package symbolizer
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	"github.com/grafana/pyroscope/pkg/model/wasm"
+	"github.com/grafana/pyroscope/pkg/objstore"
+)
+
+// Config configures the symbolization of the profiles of stripped Go
+// binaries and Windows modules. The binaries, or the PDB files of the
+// modules, are uploaded to the object storage by the users.
+type Config struct {
+	Enabled       bool          `yaml:"enabled" category:"experimental"`
+	CacheSize     int           `yaml:"cache_size" category:"experimental"`
+	CacheTTL      time.Duration `yaml:"cache_ttl" category:"experimental"`
+	MaxBinarySize int64         `yaml:"max_binary_size" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Resolve the function names of the profile locations that have no symbols with the binaries uploaded by their build ID: the .gopclntab section of Go binaries, the public symbols of PDB files, or the name section of Wasm modules. Requires the storage bucket.")
+	f.IntVar(&cfg.CacheSize, prefix+"cache-size", 64, "Maximum number of binary symbol tables cached in memory.")
+	f.DurationVar(&cfg.CacheTTL, prefix+"cache-ttl", time.Hour, "Time after which the cached symbol tables, including the ones of the binaries not found, are loaded again.")
+	f.Int64Var(&cfg.MaxBinarySize, prefix+"max-binary-size", 512<<20, "Maximum size of an uploaded binary, in bytes.")
+}
+
+// Symbolizer resolves the profile locations of the mappings that have
+// no symbols, using the binaries stored in the bucket by the build ID.
+type Symbolizer struct {
+	config  Config
+	logger  log.Logger
+	bucket  objstore.Bucket
+	metrics *metrics
+
+	// A nil table indicates that the binary can't be symbolized.
+	tables *expirable.LRU[string, symbolTable]
+	loads  singleflight.Group
+}
+
+func New(config Config, logger log.Logger, reg prometheus.Registerer, bucket objstore.Bucket) *Symbolizer {
+	return &Symbolizer{
+		config:  config,
+		logger:  logger,
+		bucket:  bucket,
+		metrics: newMetrics(reg),
+		tables:  expirable.NewLRU[string, symbolTable](config.CacheSize, nil, config.CacheTTL),
+	}
+}
+
+var errBinaryNotFound = errors.New("binary not found")
+
+// symbolTable resolves the run time addresses within a mapping.
+type symbolTable interface {
+	resolve(addr, memoryStart, fileOffset uint64) (fn, file string, line int, ok bool)
+}
+
+// openTable loads the symbol table of the binary: the .gopclntab section
+// of a Go ELF binary, the public symbols of a PDB file, or the name section
+// of a Wasm module. The size of the binary bounds the data read from it.
+func openTable(r io.ReaderAt, size int64) (symbolTable, error) {
+	switch {
+	case isMSF(r):
+		return openPDBTable(r, size)
+	case isWasm(r):
+		return openWasmTable(r)
+	}
+	return openGoTable(r)
+}
+
+func objectPath(tenantID, buildID string) string {
+	return tenantID + "/debuginfo/" + url.PathEscape(buildID)
+}
+
+func (s *Symbolizer) table(ctx context.Context, tenantID, buildID string) symbolTable {
+	path := objectPath(tenantID, buildID)
+	if t, ok := s.tables.Get(path); ok {
+		return t
+	}
+	v, _, _ := s.loads.Do(path, func() (any, error) {
+		t, err := s.load(ctx, path)
+		switch {
+		case err == nil:
+		case errors.Is(err, errBinaryNotFound):
+			s.metrics.binaryLoads.WithLabelValues(loadResultNotFound).Inc()
+		default:
+			s.metrics.binaryLoads.WithLabelValues(loadResultError).Inc()
+			level.Warn(s.logger).Log("msg", "failed to load binary symbol table", "tenant", tenantID, "build_id", buildID, "err", err)
+			if ctx.Err() != nil {
+				// Not cached: the failure is not related to the binary.
+				return nil, err
+			}
+		}
+		if err != nil {
+			t = nil
+		} else {
+			s.metrics.binaryLoads.WithLabelValues(loadResultLoaded).Inc()
+		}
+		s.tables.Add(path, t)
+		return t, nil
+	})
+	t, _ := v.(symbolTable)
+	return t
+}
+
+func (s *Symbolizer) load(ctx context.Context, path string) (symbolTable, error) {
+	attrs, err := s.bucket.Attributes(ctx, path)
+	if err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			return nil, errBinaryNotFound
+		}
+		return nil, err
+	}
+	r, err := s.bucket.ReaderAt(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return openTable(r, attrs.Size)
+}
+
+// SymbolizeProfile adds the functions to the locations of the mappings
+// that have no symbols, if the binary has been uploaded. Locations that
+// already have functions, or can't be resolved, are left intact. The
+// frames of Wasm guest functions named by their index are renamed, if the
+// module has been uploaded: the module is identified by the build ID of
+// the mapping of the frames.
+func (s *Symbolizer) SymbolizeProfile(ctx context.Context, tenantID string, p *profilev1.Profile) {
+	var b *profileBuilder
+	guest := guestFunctions(p)
+	for _, m := range p.Mapping {
+		if m.BuildId <= 0 || m.BuildId >= int64(len(p.StringTable)) {
+			continue
+		}
+		if m.HasFunctions && !hasGuestFrames(p, m, guest) {
+			continue
+		}
+		buildID := p.StringTable[m.BuildId]
+		if buildID == "" {
+			continue
+		}
+		t := s.table(ctx, tenantID, buildID)
+		if t == nil {
+			continue
+		}
+		if b == nil {
+			b = newProfileBuilder(p)
+		}
+		if n, ok := t.(*wasmTable); ok {
+			resolved, unresolved := b.nameGuestFunctions(n, m, guest)
+			s.metrics.locations.WithLabelValues(locationResultResolved).Add(float64(resolved))
+			s.metrics.locations.WithLabelValues(locationResultUnresolved).Add(float64(unresolved))
+			continue
+		}
+		if m.HasFunctions {
+			continue
+		}
+		resolved, unresolved := b.symbolizeMapping(t, m)
+		s.metrics.locations.WithLabelValues(locationResultResolved).Add(float64(resolved))
+		s.metrics.locations.WithLabelValues(locationResultUnresolved).Add(float64(unresolved))
+		if unresolved == 0 {
+			m.HasFunctions = true
+			m.HasFilenames = true
+			m.HasLineNumbers = true
+		}
+	}
+}
+
+// guestFunctions returns the Wasm guest functions that are only
+// named by their index, by the function identifier.
+func guestFunctions(p *profilev1.Profile) map[uint64]*profilev1.Function {
+	var guest map[uint64]*profilev1.Function
+	for _, fn := range p.Function {
+		if fn.Name < 0 || fn.Name >= int64(len(p.StringTable)) {
+			continue
+		}
+		if f, ok := wasm.ParseGuestFrame(p.StringTable[fn.Name]); ok && f.Name == "" {
+			if guest == nil {
+				guest = make(map[uint64]*profilev1.Function)
+			}
+			guest[fn.Id] = fn
+		}
+	}
+	return guest
+}
+
+func hasGuestFrames(p *profilev1.Profile, m *profilev1.Mapping, guest map[uint64]*profilev1.Function) bool {
+	if len(guest) == 0 {
+		return false
+	}
+	for _, loc := range p.Location {
+		if loc.MappingId != m.Id {
+			continue
+		}
+		for _, line := range loc.Line {
+			if _, ok := guest[line.FunctionId]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// profileBuilder adds the functions and the strings to the profile.
+type profileBuilder struct {
+	profile   *profilev1.Profile
+	strings   map[string]int64
+	functions map[string]uint64
+	maxID     uint64
+}
+
+func newProfileBuilder(p *profilev1.Profile) *profileBuilder {
+	b := &profileBuilder{
+		profile:   p,
+		strings:   make(map[string]int64, len(p.StringTable)),
+		functions: make(map[string]uint64),
+	}
+	for i, str := range p.StringTable {
+		if _, ok := b.strings[str]; !ok {
+			b.strings[str] = int64(i)
+		}
+	}
+	for _, fn := range p.Function {
+		b.maxID = max(b.maxID, fn.Id)
+	}
+	return b
+}
+
+func (b *profileBuilder) symbolizeMapping(t symbolTable, m *profilev1.Mapping) (resolved, unresolved int) {
+	for _, loc := range b.profile.Location {
+		if loc.MappingId != m.Id || len(loc.Line) > 0 {
+			continue
+		}
+		name, file, line, ok := t.resolve(loc.Address, m.MemoryStart, m.FileOffset)
+		if !ok {
+			unresolved++
+			continue
+		}
+		loc.Line = []*profilev1.Line{{
+			FunctionId: b.function(name, file),
+			Line:       int64(line),
+		}}
+		resolved++
+	}
+	return resolved, unresolved
+}
+
+// nameGuestFunctions renames the guest functions of the mapping after the
+// names recorded in the module. The original name is kept as the system
+// name of the function.
+func (b *profileBuilder) nameGuestFunctions(t *wasmTable, m *profilev1.Mapping, guest map[uint64]*profilev1.Function) (resolved, unresolved int) {
+	for _, loc := range b.profile.Location {
+		if loc.MappingId != m.Id {
+			continue
+		}
+		for _, line := range loc.Line {
+			fn, ok := guest[line.FunctionId]
+			if !ok {
+				continue
+			}
+			f, _ := wasm.ParseGuestFrame(b.profile.StringTable[fn.Name])
+			name, ok := t.functionName(f.Function)
+			if !ok {
+				unresolved++
+				continue
+			}
+			fn.SystemName = fn.Name
+			fn.Name = b.string(name)
+			if fn.Filename == 0 && t.module != "" {
+				fn.Filename = b.string(t.module)
+			}
+			// The function may be referenced by other locations.
+			delete(guest, fn.Id)
+			resolved++
+		}
+	}
+	return resolved, unresolved
+}
+
+func (b *profileBuilder) function(name, file string) uint64 {
+	key := name + "\x00" + file
+	if id, ok := b.functions[key]; ok {
+		return id
+	}
+	b.maxID++
+	fn := &profilev1.Function{
+		Id:         b.maxID,
+		Name:       b.string(name),
+		SystemName: b.string(name),
+		Filename:   b.string(file),
+	}
+	b.profile.Function = append(b.profile.Function, fn)
+	b.functions[key] = fn.Id
+	return fn.Id
+}
+
+func (b *profileBuilder) string(s string) int64 {
+	if i, ok := b.strings[s]; ok {
+		return i
+	}
+	i := int64(len(b.profile.StringTable))
+	b.profile.StringTable = append(b.profile.StringTable, s)
+	b.strings[s] = i
+	return i
+}
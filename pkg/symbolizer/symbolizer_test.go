@@ -0,0 +1,138 @@
+package symbolizer
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+	phlareobj "github.com/grafana/pyroscope/pkg/objstore"
+	"github.com/grafana/pyroscope/pkg/tenant"
+)
+
+func symbolizeMe() int { return 42 }
+
+func newTestSymbolizer(t *testing.T) (*Symbolizer, phlareobj.Bucket) {
+	var config Config
+	config.RegisterFlagsWithPrefix("", flag.NewFlagSet("", flag.PanicOnError))
+	config.Enabled = true
+	bucket := phlareobj.NewBucket(objstore.NewInMemBucket())
+	return New(config, log.NewNopLogger(), prometheus.NewRegistry(), bucket), bucket
+}
+
+// testProfile returns a profile with the location of a function of the
+// test binary, and a location that does not belong to any function.
+func testProfile(t *testing.T, buildID string) *profilev1.Profile {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	f, err := os.Open(exe)
+	require.NoError(t, err)
+	defer f.Close()
+	table, err := openGoTable(f)
+	require.NoError(t, err)
+
+	name := runtime.FuncForPC(reflect.ValueOf(symbolizeMe).Pointer()).Name()
+	fn := table.table.LookupFunc(name)
+	require.NotNil(t, fn)
+	// The difference between the run time address and the virtual
+	// address of the function is the load address of the executable.
+	pc := uint64(reflect.ValueOf(symbolizeMe).Pointer())
+	base := pc - fn.Entry
+	return &profilev1.Profile{
+		StringTable: []string{"", buildID, "main"},
+		Mapping: []*profilev1.Mapping{{
+			Id:          1,
+			MemoryStart: base + table.textVaddr,
+			MemoryLimit: base + table.textVaddr + 1<<30,
+			FileOffset:  table.textOffset,
+			Filename:    2,
+			BuildId:     1,
+		}},
+		Location: []*profilev1.Location{
+			{Id: 1, MappingId: 1, Address: pc},
+			{Id: 2, MappingId: 1, Address: base + table.textVaddr - 1},
+		},
+	}
+}
+
+func upload(t *testing.T, s *Symbolizer, buildID string, body []byte) int {
+	req := httptest.NewRequest(http.MethodPost, "/pyroscope/debuginfo/upload?build_id="+buildID, bytes.NewReader(body))
+	req = req.WithContext(tenant.InjectTenantID(req.Context(), "tenant-a"))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	return w.Code
+}
+
+func Test_Symbolizer_SymbolizeProfile(t *testing.T) {
+	s, _ := newTestSymbolizer(t)
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	binary, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, upload(t, s, "test/build-id", binary))
+
+	p := testProfile(t, "test/build-id")
+	s.SymbolizeProfile(context.Background(), "tenant-a", p)
+
+	require.Len(t, p.Location[0].Line, 1)
+	assert.Empty(t, p.Location[1].Line)
+	require.Len(t, p.Function, 1)
+	fn := p.Function[0]
+	assert.Equal(t, p.Location[0].Line[0].FunctionId, fn.Id)
+	assert.Equal(t, runtime.FuncForPC(reflect.ValueOf(symbolizeMe).Pointer()).Name(), p.StringTable[fn.Name])
+	assert.Contains(t, p.StringTable[fn.Filename], "symbolizer_test.go")
+	assert.NotZero(t, p.Location[0].Line[0].Line)
+	// Not all the locations have been resolved.
+	assert.False(t, p.Mapping[0].HasFunctions)
+
+	// The binary is uploaded per tenant.
+	p = testProfile(t, "test/build-id")
+	s.SymbolizeProfile(context.Background(), "tenant-b", p)
+	assert.Empty(t, p.Location[0].Line)
+	assert.Empty(t, p.Function)
+}
+
+func Test_Symbolizer_NotFound(t *testing.T) {
+	s, bucket := newTestSymbolizer(t)
+	p := testProfile(t, "unknown")
+	s.SymbolizeProfile(context.Background(), "tenant-a", p)
+	assert.Empty(t, p.Location[0].Line)
+
+	// The missing binaries are cached.
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	binary, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	require.NoError(t, bucket.Upload(context.Background(), objectPath("tenant-a", "unknown"), bytes.NewReader(binary)))
+	s.SymbolizeProfile(context.Background(), "tenant-a", p)
+	assert.Empty(t, p.Location[0].Line)
+
+	// The upload invalidates the cached entry.
+	require.Equal(t, http.StatusNoContent, upload(t, s, "unknown", binary))
+	s.SymbolizeProfile(context.Background(), "tenant-a", p)
+	assert.Len(t, p.Location[0].Line, 1)
+}
+
+func Test_Symbolizer_Upload_Invalid(t *testing.T) {
+	s, bucket := newTestSymbolizer(t)
+	assert.Equal(t, http.StatusBadRequest, upload(t, s, "", []byte("binary")))
+	assert.Equal(t, http.StatusBadRequest, upload(t, s, "build-id", []byte("not an ELF file")))
+	ok, err := bucket.Exists(context.Background(), objectPath("tenant-a", "build-id"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	s.config.MaxBinarySize = 1 << 10
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload(t, s, "build-id", make([]byte, 2<<10)))
+}
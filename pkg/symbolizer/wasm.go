@@ -0,0 +1,173 @@
+package symbolizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	wasmMagic = []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+
+	errInvalidWasm = errors.New("invalid Wasm module")
+)
+
+const (
+	wasmCustomSection       = 0
+	wasmModuleNameSection   = 0
+	wasmFunctionNameSection = 1
+	// The name section is the only section loaded in memory.
+	maxWasmNameSectionSize = 64 << 20
+)
+
+// wasmTable resolves the indexes of the functions of a Wasm module to the
+// names recorded in the "name" custom section of the module. The guest
+// functions are JIT-compiled: their addresses are not resolved.
+type wasmTable struct {
+	module    string
+	functions map[uint32]string
+}
+
+func isWasm(r io.ReaderAt) bool {
+	magic := make([]byte, len(wasmMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(magic, wasmMagic)
+}
+
+func (*wasmTable) resolve(uint64, uint64, uint64) (string, string, int, bool) {
+	return "", "", 0, false
+}
+
+func (t *wasmTable) functionName(index uint32) (string, bool) {
+	name, ok := t.functions[index]
+	return name, ok
+}
+
+// openWasmTable locates the name section of the module: only the
+// section headers are read, the sections themselves are skipped.
+func openWasmTable(r io.ReaderAt) (*wasmTable, error) {
+	if !isWasm(r) {
+		return nil, errInvalidWasm
+	}
+	offset := int64(len(wasmMagic))
+	var header [1 + binary.MaxVarintLen32]byte
+	for {
+		n, err := r.ReadAt(header[:], offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, errors.New("no name section in Wasm module")
+		}
+		id := header[0]
+		size, k := binary.Uvarint(header[1:n])
+		if k <= 0 || size > 1<<32 {
+			return nil, fmt.Errorf("%w: malformed section header at %d", errInvalidWasm, offset)
+		}
+		offset += int64(1 + k)
+		if id == wasmCustomSection {
+			if t, ok, err := readNameSection(r, offset, size); err != nil || ok {
+				return t, err
+			}
+		}
+		offset += int64(size)
+	}
+}
+
+func readNameSection(r io.ReaderAt, offset int64, size uint64) (*wasmTable, bool, error) {
+	// The custom section starts with its name.
+	var prefix [1 + len("name")]byte
+	if size < uint64(len(prefix)) {
+		return nil, false, nil
+	}
+	if _, err := r.ReadAt(prefix[:], offset); err != nil {
+		return nil, false, err
+	}
+	if prefix[0] != byte(len("name")) || string(prefix[1:]) != "name" {
+		return nil, false, nil
+	}
+	if size > maxWasmNameSectionSize {
+		return nil, false, fmt.Errorf("%w: name section too large", errInvalidWasm)
+	}
+	b := make([]byte, size-uint64(len(prefix)))
+	if _, err := r.ReadAt(b, offset+int64(len(prefix))); err != nil {
+		return nil, false, err
+	}
+	t, err := parseNameSection(b)
+	return t, true, err
+}
+
+func parseNameSection(b []byte) (*wasmTable, error) {
+	d := wasmDecoder{b: b}
+	t := &wasmTable{functions: make(map[uint32]string)}
+	for len(d.b) > 0 && d.err == nil {
+		id := d.byte()
+		sub := wasmDecoder{b: d.bytes(int(d.uint32()))}
+		switch id {
+		case wasmModuleNameSection:
+			t.module = sub.name()
+		case wasmFunctionNameSection:
+			for n := sub.uint32(); n > 0 && sub.err == nil; n-- {
+				index := sub.uint32()
+				t.functions[index] = sub.name()
+			}
+		}
+		if sub.err != nil {
+			return nil, sub.err
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return t, nil
+}
+
+type wasmDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *wasmDecoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: malformed name section", errInvalidWasm)
+	}
+	d.b = nil
+}
+
+func (d *wasmDecoder) byte() byte {
+	if len(d.b) == 0 {
+		d.fail()
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *wasmDecoder) uint32() uint32 {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 || v > 0xFFFFFFFF {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return uint32(v)
+}
+
+func (d *wasmDecoder) bytes(n int) []byte {
+	if n > len(d.b) {
+		d.fail()
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *wasmDecoder) name() string {
+	return string(d.bytes(int(d.uint32())))
+}
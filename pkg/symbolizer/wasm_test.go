@@ -0,0 +1,104 @@
+package symbolizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	profilev1 "github.com/grafana/pyroscope/api/gen/proto/go/google/v1"
+)
+
+func appendWasmName(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendWasmSection(b []byte, id byte, payload []byte) []byte {
+	b = append(b, id)
+	b = binary.AppendUvarint(b, uint64(len(payload)))
+	return append(b, payload...)
+}
+
+// buildWasm returns a module with a type section, and the name section
+// with the module name and the names of the given functions.
+func buildWasm(module string, functions map[uint32]string) []byte {
+	var names []byte
+	names = appendWasmSection(names, wasmModuleNameSection, appendWasmName(nil, module))
+	fns := binary.AppendUvarint(nil, uint64(len(functions)))
+	for index, name := range functions {
+		fns = binary.AppendUvarint(fns, uint64(index))
+		fns = appendWasmName(fns, name)
+	}
+	names = appendWasmSection(names, wasmFunctionNameSection, fns)
+
+	b := append([]byte{}, wasmMagic...)
+	b = appendWasmSection(b, 1, []byte{0x01, 0x60, 0x00, 0x00})
+	b = appendWasmSection(b, wasmCustomSection, appendWasmName(nil, "producers"))
+	return appendWasmSection(b, wasmCustomSection, append(appendWasmName(nil, "name"), names...))
+}
+
+func Test_WasmTable(t *testing.T) {
+	r := bytes.NewReader(buildWasm("guest", map[uint32]string{1: "compute", 7: "main"}))
+	require.True(t, isWasm(r))
+	table, err := openTable(r, r.Size())
+	require.NoError(t, err)
+	w, ok := table.(*wasmTable)
+	require.True(t, ok)
+	assert.Equal(t, "guest", w.module)
+
+	name, ok := w.functionName(7)
+	assert.True(t, ok)
+	assert.Equal(t, "main", name)
+	_, ok = w.functionName(2)
+	assert.False(t, ok)
+	_, _, _, ok = w.resolve(0x1000, 0, 0)
+	assert.False(t, ok)
+}
+
+func Test_WasmTable_Invalid(t *testing.T) {
+	b := buildWasm("guest", map[uint32]string{1: "compute"})
+	for _, x := range [][]byte{
+		// No name section.
+		appendWasmSection(append([]byte{}, wasmMagic...), 1, []byte{0x00}),
+		// Truncated name section.
+		b[:len(b)-3],
+		wasmMagic,
+	} {
+		_, err := openTable(bytes.NewReader(x), int64(len(x)))
+		assert.Error(t, err)
+	}
+}
+
+func Test_Symbolizer_WasmGuestFrames(t *testing.T) {
+	s, _ := newTestSymbolizer(t)
+	require.Equal(t, http.StatusNoContent, upload(t, s, "mod-id", buildWasm("guest", map[uint32]string{1: "compute"})))
+
+	p := &profilev1.Profile{
+		StringTable: []string{"", "mod-id", "wasm[0]::function[1]", "wasm[0]::function[2]", "wasmtime::func::Func::call"},
+		Mapping:     []*profilev1.Mapping{{Id: 1, BuildId: 1, HasFunctions: true}},
+		Function: []*profilev1.Function{
+			{Id: 1, Name: 2},
+			{Id: 2, Name: 3},
+			{Id: 3, Name: 4},
+		},
+		Location: []*profilev1.Location{
+			{Id: 1, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 1}}},
+			{Id: 2, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 1}}},
+			{Id: 3, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 2}}},
+			{Id: 4, MappingId: 1, Line: []*profilev1.Line{{FunctionId: 3}}},
+		},
+	}
+	s.SymbolizeProfile(context.Background(), "tenant-a", p)
+
+	assert.Equal(t, "compute", p.StringTable[p.Function[0].Name])
+	assert.Equal(t, "wasm[0]::function[1]", p.StringTable[p.Function[0].SystemName])
+	assert.Equal(t, "guest", p.StringTable[p.Function[0].Filename])
+	// Unknown function index and host frames are left intact.
+	assert.Equal(t, "wasm[0]::function[2]", p.StringTable[p.Function[1].Name])
+	assert.Equal(t, "wasmtime::func::Func::call", p.StringTable[p.Function[2].Name])
+}
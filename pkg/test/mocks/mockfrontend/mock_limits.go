@@ -3,9 +3,10 @@
 package mockfrontend
 
 import (
-	time "time"
-
+	read_path "github.com/grafana/pyroscope/pkg/frontend/read_path"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockLimits is an autogenerated mock type for the Limits type
@@ -343,6 +344,52 @@ func (_c *MockLimits_QuerySplitDuration_Call) RunAndReturn(run func(string) time
 	return _c
 }
 
+// ReadPathOverrides provides a mock function with given fields: tenantID
+func (_m *MockLimits) ReadPathOverrides(tenantID string) read_path.Config {
+	ret := _m.Called(tenantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadPathOverrides")
+	}
+
+	var r0 read_path.Config
+	if rf, ok := ret.Get(0).(func(string) read_path.Config); ok {
+		r0 = rf(tenantID)
+	} else {
+		r0 = ret.Get(0).(read_path.Config)
+	}
+
+	return r0
+}
+
+// MockLimits_ReadPathOverrides_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadPathOverrides'
+type MockLimits_ReadPathOverrides_Call struct {
+	*mock.Call
+}
+
+// ReadPathOverrides is a helper method to define mock.On call
+//   - tenantID string
+func (_e *MockLimits_Expecter) ReadPathOverrides(tenantID interface{}) *MockLimits_ReadPathOverrides_Call {
+	return &MockLimits_ReadPathOverrides_Call{Call: _e.mock.On("ReadPathOverrides", tenantID)}
+}
+
+func (_c *MockLimits_ReadPathOverrides_Call) Run(run func(tenantID string)) *MockLimits_ReadPathOverrides_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockLimits_ReadPathOverrides_Call) Return(_a0 read_path.Config) *MockLimits_ReadPathOverrides_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLimits_ReadPathOverrides_Call) RunAndReturn(run func(string) read_path.Config) *MockLimits_ReadPathOverrides_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockLimits creates a new instance of MockLimits. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockLimits(t interface {
@@ -5,11 +5,17 @@ package mockindex
 import (
 	bbolt "go.etcd.io/bbolt"
 
+	iter "github.com/grafana/pyroscope/pkg/iter"
+
 	metastorev1 "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1"
 
 	mock "github.com/stretchr/testify/mock"
 
+	raft_log "github.com/grafana/pyroscope/api/gen/proto/go/metastore/v1/raft_log"
+
 	store "github.com/grafana/pyroscope/pkg/experiment/metastore/index/store"
+
+	time "time"
 )
 
 // MockStore is an autogenerated mock type for the Store type
@@ -25,6 +31,148 @@ func (_m *MockStore) EXPECT() *MockStore_Expecter {
 	return &MockStore_Expecter{mock: &_m.Mock}
 }
 
+// AddDictionaryRefs provides a mock function with given fields: tx, paths
+func (_m *MockStore) AddDictionaryRefs(tx *bbolt.Tx, paths []string) error {
+	ret := _m.Called(tx, paths)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDictionaryRefs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, []string) error); ok {
+		r0 = rf(tx, paths)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_AddDictionaryRefs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddDictionaryRefs'
+type MockStore_AddDictionaryRefs_Call struct {
+	*mock.Call
+}
+
+// AddDictionaryRefs is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - paths []string
+func (_e *MockStore_Expecter) AddDictionaryRefs(tx interface{}, paths interface{}) *MockStore_AddDictionaryRefs_Call {
+	return &MockStore_AddDictionaryRefs_Call{Call: _e.mock.On("AddDictionaryRefs", tx, paths)}
+}
+
+func (_c *MockStore_AddDictionaryRefs_Call) Run(run func(tx *bbolt.Tx, paths []string)) *MockStore_AddDictionaryRefs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockStore_AddDictionaryRefs_Call) Return(_a0 error) *MockStore_AddDictionaryRefs_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_AddDictionaryRefs_Call) RunAndReturn(run func(*bbolt.Tx, []string) error) *MockStore_AddDictionaryRefs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ArchivePartition provides a mock function with given fields: _a0, _a1
+func (_m *MockStore) ArchivePartition(_a0 *bbolt.Tx, _a1 *raft_log.ArchivedPartition) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchivePartition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, *raft_log.ArchivedPartition) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_ArchivePartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ArchivePartition'
+type MockStore_ArchivePartition_Call struct {
+	*mock.Call
+}
+
+// ArchivePartition is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 *raft_log.ArchivedPartition
+func (_e *MockStore_Expecter) ArchivePartition(_a0 interface{}, _a1 interface{}) *MockStore_ArchivePartition_Call {
+	return &MockStore_ArchivePartition_Call{Call: _e.mock.On("ArchivePartition", _a0, _a1)}
+}
+
+func (_c *MockStore_ArchivePartition_Call) Run(run func(_a0 *bbolt.Tx, _a1 *raft_log.ArchivedPartition)) *MockStore_ArchivePartition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(*raft_log.ArchivedPartition))
+	})
+	return _c
+}
+
+func (_c *MockStore_ArchivePartition_Call) Return(_a0 error) *MockStore_ArchivePartition_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_ArchivePartition_Call) RunAndReturn(run func(*bbolt.Tx, *raft_log.ArchivedPartition) error) *MockStore_ArchivePartition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckPartitions provides a mock function with given fields: _a0
+func (_m *MockStore) CheckPartitions(_a0 *bbolt.Tx) []store.PartitionIssue {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPartitions")
+	}
+
+	var r0 []store.PartitionIssue
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) []store.PartitionIssue); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.PartitionIssue)
+		}
+	}
+
+	return r0
+}
+
+// MockStore_CheckPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckPartitions'
+type MockStore_CheckPartitions_Call struct {
+	*mock.Call
+}
+
+// CheckPartitions is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+func (_e *MockStore_Expecter) CheckPartitions(_a0 interface{}) *MockStore_CheckPartitions_Call {
+	return &MockStore_CheckPartitions_Call{Call: _e.mock.On("CheckPartitions", _a0)}
+}
+
+func (_c *MockStore_CheckPartitions_Call) Run(run func(_a0 *bbolt.Tx)) *MockStore_CheckPartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx))
+	})
+	return _c
+}
+
+func (_c *MockStore_CheckPartitions_Call) Return(_a0 []store.PartitionIssue) *MockStore_CheckPartitions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_CheckPartitions_Call) RunAndReturn(run func(*bbolt.Tx) []store.PartitionIssue) *MockStore_CheckPartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateBuckets provides a mock function with given fields: _a0
 func (_m *MockStore) CreateBuckets(_a0 *bbolt.Tx) error {
 	ret := _m.Called(_a0)
@@ -71,6 +219,53 @@ func (_c *MockStore_CreateBuckets_Call) RunAndReturn(run func(*bbolt.Tx) error)
 	return _c
 }
 
+// DeleteArchivedPartition provides a mock function with given fields: _a0, _a1
+func (_m *MockStore) DeleteArchivedPartition(_a0 *bbolt.Tx, _a1 store.PartitionKey) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteArchivedPartition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_DeleteArchivedPartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteArchivedPartition'
+type MockStore_DeleteArchivedPartition_Call struct {
+	*mock.Call
+}
+
+// DeleteArchivedPartition is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 store.PartitionKey
+func (_e *MockStore_Expecter) DeleteArchivedPartition(_a0 interface{}, _a1 interface{}) *MockStore_DeleteArchivedPartition_Call {
+	return &MockStore_DeleteArchivedPartition_Call{Call: _e.mock.On("DeleteArchivedPartition", _a0, _a1)}
+}
+
+func (_c *MockStore_DeleteArchivedPartition_Call) Run(run func(_a0 *bbolt.Tx, _a1 store.PartitionKey)) *MockStore_DeleteArchivedPartition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey))
+	})
+	return _c
+}
+
+func (_c *MockStore_DeleteArchivedPartition_Call) Return(_a0 error) *MockStore_DeleteArchivedPartition_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_DeleteArchivedPartition_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey) error) *MockStore_DeleteArchivedPartition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteBlockList provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockStore) DeleteBlockList(_a0 *bbolt.Tx, _a1 store.PartitionKey, _a2 *metastorev1.BlockList) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -119,220 +314,979 @@ func (_c *MockStore_DeleteBlockList_Call) RunAndReturn(run func(*bbolt.Tx, store
 	return _c
 }
 
-// ListBlocks provides a mock function with given fields: tx, p, shard, tenant
-func (_m *MockStore) ListBlocks(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string) []*metastorev1.BlockMeta {
-	ret := _m.Called(tx, p, shard, tenant)
+// DeleteDeletedBlocks provides a mock function with given fields: _a0, _a1
+func (_m *MockStore) DeleteDeletedBlocks(_a0 *bbolt.Tx, _a1 []store.DeletedBlock) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListBlocks")
+		panic("no return value specified for DeleteDeletedBlocks")
 	}
 
-	var r0 []*metastorev1.BlockMeta
-	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, uint32, string) []*metastorev1.BlockMeta); ok {
-		r0 = rf(tx, p, shard, tenant)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, []store.DeletedBlock) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*metastorev1.BlockMeta)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// MockStore_ListBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBlocks'
-type MockStore_ListBlocks_Call struct {
+// MockStore_DeleteDeletedBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeletedBlocks'
+type MockStore_DeleteDeletedBlocks_Call struct {
 	*mock.Call
 }
 
-// ListBlocks is a helper method to define mock.On call
-//   - tx *bbolt.Tx
-//   - p store.PartitionKey
-//   - shard uint32
-//   - tenant string
-func (_e *MockStore_Expecter) ListBlocks(tx interface{}, p interface{}, shard interface{}, tenant interface{}) *MockStore_ListBlocks_Call {
-	return &MockStore_ListBlocks_Call{Call: _e.mock.On("ListBlocks", tx, p, shard, tenant)}
+// DeleteDeletedBlocks is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 []store.DeletedBlock
+func (_e *MockStore_Expecter) DeleteDeletedBlocks(_a0 interface{}, _a1 interface{}) *MockStore_DeleteDeletedBlocks_Call {
+	return &MockStore_DeleteDeletedBlocks_Call{Call: _e.mock.On("DeleteDeletedBlocks", _a0, _a1)}
 }
 
-func (_c *MockStore_ListBlocks_Call) Run(run func(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string)) *MockStore_ListBlocks_Call {
+func (_c *MockStore_DeleteDeletedBlocks_Call) Run(run func(_a0 *bbolt.Tx, _a1 []store.DeletedBlock)) *MockStore_DeleteDeletedBlocks_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(uint32), args[3].(string))
+		run(args[0].(*bbolt.Tx), args[1].([]store.DeletedBlock))
 	})
 	return _c
 }
 
-func (_c *MockStore_ListBlocks_Call) Return(_a0 []*metastorev1.BlockMeta) *MockStore_ListBlocks_Call {
+func (_c *MockStore_DeleteDeletedBlocks_Call) Return(_a0 error) *MockStore_DeleteDeletedBlocks_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_ListBlocks_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, uint32, string) []*metastorev1.BlockMeta) *MockStore_ListBlocks_Call {
+func (_c *MockStore_DeleteDeletedBlocks_Call) RunAndReturn(run func(*bbolt.Tx, []store.DeletedBlock) error) *MockStore_DeleteDeletedBlocks_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListPartitions provides a mock function with given fields: _a0
-func (_m *MockStore) ListPartitions(_a0 *bbolt.Tx) []store.PartitionKey {
-	ret := _m.Called(_a0)
+// DeleteReleasedDictionaries provides a mock function with given fields: tx, before
+func (_m *MockStore) DeleteReleasedDictionaries(tx *bbolt.Tx, before int64) error {
+	ret := _m.Called(tx, before)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListPartitions")
+		panic("no return value specified for DeleteReleasedDictionaries")
 	}
 
-	var r0 []store.PartitionKey
-	if rf, ok := ret.Get(0).(func(*bbolt.Tx) []store.PartitionKey); ok {
-		r0 = rf(_a0)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, int64) error); ok {
+		r0 = rf(tx, before)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]store.PartitionKey)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// MockStore_ListPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPartitions'
-type MockStore_ListPartitions_Call struct {
+// MockStore_DeleteReleasedDictionaries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteReleasedDictionaries'
+type MockStore_DeleteReleasedDictionaries_Call struct {
 	*mock.Call
 }
 
-// ListPartitions is a helper method to define mock.On call
-//   - _a0 *bbolt.Tx
-func (_e *MockStore_Expecter) ListPartitions(_a0 interface{}) *MockStore_ListPartitions_Call {
-	return &MockStore_ListPartitions_Call{Call: _e.mock.On("ListPartitions", _a0)}
+// DeleteReleasedDictionaries is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - before int64
+func (_e *MockStore_Expecter) DeleteReleasedDictionaries(tx interface{}, before interface{}) *MockStore_DeleteReleasedDictionaries_Call {
+	return &MockStore_DeleteReleasedDictionaries_Call{Call: _e.mock.On("DeleteReleasedDictionaries", tx, before)}
 }
 
-func (_c *MockStore_ListPartitions_Call) Run(run func(_a0 *bbolt.Tx)) *MockStore_ListPartitions_Call {
+func (_c *MockStore_DeleteReleasedDictionaries_Call) Run(run func(tx *bbolt.Tx, before int64)) *MockStore_DeleteReleasedDictionaries_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*bbolt.Tx))
+		run(args[0].(*bbolt.Tx), args[1].(int64))
 	})
 	return _c
 }
 
-func (_c *MockStore_ListPartitions_Call) Return(_a0 []store.PartitionKey) *MockStore_ListPartitions_Call {
+func (_c *MockStore_DeleteReleasedDictionaries_Call) Return(_a0 error) *MockStore_DeleteReleasedDictionaries_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_ListPartitions_Call) RunAndReturn(run func(*bbolt.Tx) []store.PartitionKey) *MockStore_ListPartitions_Call {
+func (_c *MockStore_DeleteReleasedDictionaries_Call) RunAndReturn(run func(*bbolt.Tx, int64) error) *MockStore_DeleteReleasedDictionaries_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListShards provides a mock function with given fields: _a0, _a1
-func (_m *MockStore) ListShards(_a0 *bbolt.Tx, _a1 store.PartitionKey) []uint32 {
-	ret := _m.Called(_a0, _a1)
+// DeleteTenantBlocks provides a mock function with given fields: tx, p, tenant
+func (_m *MockStore) DeleteTenantBlocks(tx *bbolt.Tx, p store.PartitionKey, tenant string) ([]*metastorev1.BlockMeta, bool, error) {
+	ret := _m.Called(tx, p, tenant)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListShards")
+		panic("no return value specified for DeleteTenantBlocks")
 	}
 
-	var r0 []uint32
-	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey) []uint32); ok {
-		r0 = rf(_a0, _a1)
+	var r0 []*metastorev1.BlockMeta
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, string) ([]*metastorev1.BlockMeta, bool, error)); ok {
+		return rf(tx, p, tenant)
+	}
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, string) []*metastorev1.BlockMeta); ok {
+		r0 = rf(tx, p, tenant)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]uint32)
+			r0 = ret.Get(0).([]*metastorev1.BlockMeta)
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(*bbolt.Tx, store.PartitionKey, string) bool); ok {
+		r1 = rf(tx, p, tenant)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(*bbolt.Tx, store.PartitionKey, string) error); ok {
+		r2 = rf(tx, p, tenant)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// MockStore_ListShards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListShards'
-type MockStore_ListShards_Call struct {
+// MockStore_DeleteTenantBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTenantBlocks'
+type MockStore_DeleteTenantBlocks_Call struct {
 	*mock.Call
 }
 
-// ListShards is a helper method to define mock.On call
-//   - _a0 *bbolt.Tx
-//   - _a1 store.PartitionKey
-func (_e *MockStore_Expecter) ListShards(_a0 interface{}, _a1 interface{}) *MockStore_ListShards_Call {
-	return &MockStore_ListShards_Call{Call: _e.mock.On("ListShards", _a0, _a1)}
+// DeleteTenantBlocks is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - p store.PartitionKey
+//   - tenant string
+func (_e *MockStore_Expecter) DeleteTenantBlocks(tx interface{}, p interface{}, tenant interface{}) *MockStore_DeleteTenantBlocks_Call {
+	return &MockStore_DeleteTenantBlocks_Call{Call: _e.mock.On("DeleteTenantBlocks", tx, p, tenant)}
 }
 
-func (_c *MockStore_ListShards_Call) Run(run func(_a0 *bbolt.Tx, _a1 store.PartitionKey)) *MockStore_ListShards_Call {
+func (_c *MockStore_DeleteTenantBlocks_Call) Run(run func(tx *bbolt.Tx, p store.PartitionKey, tenant string)) *MockStore_DeleteTenantBlocks_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey))
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_ListShards_Call) Return(_a0 []uint32) *MockStore_ListShards_Call {
-	_c.Call.Return(_a0)
+func (_c *MockStore_DeleteTenantBlocks_Call) Return(_a0 []*metastorev1.BlockMeta, _a1 bool, _a2 error) *MockStore_DeleteTenantBlocks_Call {
+	_c.Call.Return(_a0, _a1, _a2)
 	return _c
 }
 
-func (_c *MockStore_ListShards_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey) []uint32) *MockStore_ListShards_Call {
+func (_c *MockStore_DeleteTenantBlocks_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, string) ([]*metastorev1.BlockMeta, bool, error)) *MockStore_DeleteTenantBlocks_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListTenants provides a mock function with given fields: tx, p, shard
-func (_m *MockStore) ListTenants(tx *bbolt.Tx, p store.PartitionKey, shard uint32) []string {
-	ret := _m.Called(tx, p, shard)
+// DictionaryReleased provides a mock function with given fields: tx, path
+func (_m *MockStore) DictionaryReleased(tx *bbolt.Tx, path string) bool {
+	ret := _m.Called(tx, path)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListTenants")
+		panic("no return value specified for DictionaryReleased")
 	}
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, uint32) []string); ok {
-		r0 = rf(tx, p, shard)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string) bool); ok {
+		r0 = rf(tx, path)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
 	return r0
 }
 
-// MockStore_ListTenants_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTenants'
-type MockStore_ListTenants_Call struct {
+// MockStore_DictionaryReleased_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DictionaryReleased'
+type MockStore_DictionaryReleased_Call struct {
 	*mock.Call
 }
 
-// ListTenants is a helper method to define mock.On call
+// DictionaryReleased is a helper method to define mock.On call
 //   - tx *bbolt.Tx
-//   - p store.PartitionKey
-//   - shard uint32
-func (_e *MockStore_Expecter) ListTenants(tx interface{}, p interface{}, shard interface{}) *MockStore_ListTenants_Call {
-	return &MockStore_ListTenants_Call{Call: _e.mock.On("ListTenants", tx, p, shard)}
+//   - path string
+func (_e *MockStore_Expecter) DictionaryReleased(tx interface{}, path interface{}) *MockStore_DictionaryReleased_Call {
+	return &MockStore_DictionaryReleased_Call{Call: _e.mock.On("DictionaryReleased", tx, path)}
 }
 
-func (_c *MockStore_ListTenants_Call) Run(run func(tx *bbolt.Tx, p store.PartitionKey, shard uint32)) *MockStore_ListTenants_Call {
+func (_c *MockStore_DictionaryReleased_Call) Run(run func(tx *bbolt.Tx, path string)) *MockStore_DictionaryReleased_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(uint32))
+		run(args[0].(*bbolt.Tx), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_ListTenants_Call) Return(_a0 []string) *MockStore_ListTenants_Call {
+func (_c *MockStore_DictionaryReleased_Call) Return(_a0 bool) *MockStore_DictionaryReleased_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_ListTenants_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, uint32) []string) *MockStore_ListTenants_Call {
+func (_c *MockStore_DictionaryReleased_Call) RunAndReturn(run func(*bbolt.Tx, string) bool) *MockStore_DictionaryReleased_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// StoreBlock provides a mock function with given fields: _a0, _a1, _a2
-func (_m *MockStore) StoreBlock(_a0 *bbolt.Tx, _a1 store.PartitionKey, _a2 *metastorev1.BlockMeta) error {
-	ret := _m.Called(_a0, _a1, _a2)
+// FlushPendingBlocks provides a mock function with given fields: _a0
+func (_m *MockStore) FlushPendingBlocks(_a0 *bbolt.Tx) (int, error) {
+	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
-		panic("no return value specified for StoreBlock")
+		panic("no return value specified for FlushPendingBlocks")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, *metastorev1.BlockMeta) error); ok {
-		r0 = rf(_a0, _a1, _a2)
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) (int, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) int); ok {
+		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(*bbolt.Tx) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_FlushPendingBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FlushPendingBlocks'
+type MockStore_FlushPendingBlocks_Call struct {
+	*mock.Call
+}
+
+// FlushPendingBlocks is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+func (_e *MockStore_Expecter) FlushPendingBlocks(_a0 interface{}) *MockStore_FlushPendingBlocks_Call {
+	return &MockStore_FlushPendingBlocks_Call{Call: _e.mock.On("FlushPendingBlocks", _a0)}
+}
+
+func (_c *MockStore_FlushPendingBlocks_Call) Run(run func(_a0 *bbolt.Tx)) *MockStore_FlushPendingBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx))
+	})
+	return _c
+}
+
+func (_c *MockStore_FlushPendingBlocks_Call) Return(_a0 int, _a1 error) *MockStore_FlushPendingBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_FlushPendingBlocks_Call) RunAndReturn(run func(*bbolt.Tx) (int, error)) *MockStore_FlushPendingBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IterateBlocks provides a mock function with given fields: tx, p, shard, tenant, after
+func (_m *MockStore) IterateBlocks(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string, after string) iter.Iterator[*metastorev1.BlockMeta] {
+	ret := _m.Called(tx, p, shard, tenant, after)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterateBlocks")
+	}
+
+	var r0 iter.Iterator[*metastorev1.BlockMeta]
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, uint32, string, string) iter.Iterator[*metastorev1.BlockMeta]); ok {
+		r0 = rf(tx, p, shard, tenant, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Iterator[*metastorev1.BlockMeta])
+		}
+	}
+
+	return r0
+}
+
+// MockStore_IterateBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IterateBlocks'
+type MockStore_IterateBlocks_Call struct {
+	*mock.Call
+}
+
+// IterateBlocks is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - p store.PartitionKey
+//   - shard uint32
+//   - tenant string
+//   - after string
+func (_e *MockStore_Expecter) IterateBlocks(tx interface{}, p interface{}, shard interface{}, tenant interface{}, after interface{}) *MockStore_IterateBlocks_Call {
+	return &MockStore_IterateBlocks_Call{Call: _e.mock.On("IterateBlocks", tx, p, shard, tenant, after)}
+}
+
+func (_c *MockStore_IterateBlocks_Call) Run(run func(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string, after string)) *MockStore_IterateBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(uint32), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_IterateBlocks_Call) Return(_a0 iter.Iterator[*metastorev1.BlockMeta]) *MockStore_IterateBlocks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_IterateBlocks_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, uint32, string, string) iter.Iterator[*metastorev1.BlockMeta]) *MockStore_IterateBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListArchivedPartitions provides a mock function with given fields: _a0
+func (_m *MockStore) ListArchivedPartitions(_a0 *bbolt.Tx) []*raft_log.ArchivedPartition {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListArchivedPartitions")
+	}
+
+	var r0 []*raft_log.ArchivedPartition
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) []*raft_log.ArchivedPartition); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*raft_log.ArchivedPartition)
+		}
+	}
+
+	return r0
+}
+
+// MockStore_ListArchivedPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListArchivedPartitions'
+type MockStore_ListArchivedPartitions_Call struct {
+	*mock.Call
+}
+
+// ListArchivedPartitions is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+func (_e *MockStore_Expecter) ListArchivedPartitions(_a0 interface{}) *MockStore_ListArchivedPartitions_Call {
+	return &MockStore_ListArchivedPartitions_Call{Call: _e.mock.On("ListArchivedPartitions", _a0)}
+}
+
+func (_c *MockStore_ListArchivedPartitions_Call) Run(run func(_a0 *bbolt.Tx)) *MockStore_ListArchivedPartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListArchivedPartitions_Call) Return(_a0 []*raft_log.ArchivedPartition) *MockStore_ListArchivedPartitions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_ListArchivedPartitions_Call) RunAndReturn(run func(*bbolt.Tx) []*raft_log.ArchivedPartition) *MockStore_ListArchivedPartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListBlocks provides a mock function with given fields: tx, p, shard, tenant
+func (_m *MockStore) ListBlocks(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string) []*metastorev1.BlockMeta {
+	ret := _m.Called(tx, p, shard, tenant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBlocks")
+	}
+
+	var r0 []*metastorev1.BlockMeta
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, uint32, string) []*metastorev1.BlockMeta); ok {
+		r0 = rf(tx, p, shard, tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*metastorev1.BlockMeta)
+		}
+	}
+
+	return r0
+}
+
+// MockStore_ListBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBlocks'
+type MockStore_ListBlocks_Call struct {
+	*mock.Call
+}
+
+// ListBlocks is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - p store.PartitionKey
+//   - shard uint32
+//   - tenant string
+func (_e *MockStore_Expecter) ListBlocks(tx interface{}, p interface{}, shard interface{}, tenant interface{}) *MockStore_ListBlocks_Call {
+	return &MockStore_ListBlocks_Call{Call: _e.mock.On("ListBlocks", tx, p, shard, tenant)}
+}
+
+func (_c *MockStore_ListBlocks_Call) Run(run func(tx *bbolt.Tx, p store.PartitionKey, shard uint32, tenant string)) *MockStore_ListBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(uint32), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListBlocks_Call) Return(_a0 []*metastorev1.BlockMeta) *MockStore_ListBlocks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_ListBlocks_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, uint32, string) []*metastorev1.BlockMeta) *MockStore_ListBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeletedBlocks provides a mock function with given fields: tx, before, limit
+func (_m *MockStore) ListDeletedBlocks(tx *bbolt.Tx, before int64, limit int) ([]store.DeletedBlock, error) {
+	ret := _m.Called(tx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeletedBlocks")
+	}
+
+	var r0 []store.DeletedBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, int64, int) ([]store.DeletedBlock, error)); ok {
+		return rf(tx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, int64, int) []store.DeletedBlock); ok {
+		r0 = rf(tx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.DeletedBlock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*bbolt.Tx, int64, int) error); ok {
+		r1 = rf(tx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_ListDeletedBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeletedBlocks'
+type MockStore_ListDeletedBlocks_Call struct {
+	*mock.Call
+}
+
+// ListDeletedBlocks is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - before int64
+//   - limit int
+func (_e *MockStore_Expecter) ListDeletedBlocks(tx interface{}, before interface{}, limit interface{}) *MockStore_ListDeletedBlocks_Call {
+	return &MockStore_ListDeletedBlocks_Call{Call: _e.mock.On("ListDeletedBlocks", tx, before, limit)}
+}
+
+func (_c *MockStore_ListDeletedBlocks_Call) Run(run func(tx *bbolt.Tx, before int64, limit int)) *MockStore_ListDeletedBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListDeletedBlocks_Call) Return(_a0 []store.DeletedBlock, _a1 error) *MockStore_ListDeletedBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_ListDeletedBlocks_Call) RunAndReturn(run func(*bbolt.Tx, int64, int) ([]store.DeletedBlock, error)) *MockStore_ListDeletedBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPartitions provides a mock function with given fields: _a0
+func (_m *MockStore) ListPartitions(_a0 *bbolt.Tx) []store.PartitionKey {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPartitions")
+	}
+
+	var r0 []store.PartitionKey
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) []store.PartitionKey); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.PartitionKey)
+		}
+	}
+
+	return r0
+}
+
+// MockStore_ListPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPartitions'
+type MockStore_ListPartitions_Call struct {
+	*mock.Call
+}
+
+// ListPartitions is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+func (_e *MockStore_Expecter) ListPartitions(_a0 interface{}) *MockStore_ListPartitions_Call {
+	return &MockStore_ListPartitions_Call{Call: _e.mock.On("ListPartitions", _a0)}
+}
+
+func (_c *MockStore_ListPartitions_Call) Run(run func(_a0 *bbolt.Tx)) *MockStore_ListPartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListPartitions_Call) Return(_a0 []store.PartitionKey) *MockStore_ListPartitions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_ListPartitions_Call) RunAndReturn(run func(*bbolt.Tx) []store.PartitionKey) *MockStore_ListPartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListShards provides a mock function with given fields: _a0, _a1
+func (_m *MockStore) ListShards(_a0 *bbolt.Tx, _a1 store.PartitionKey) []uint32 {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListShards")
+	}
+
+	var r0 []uint32
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey) []uint32); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint32)
+		}
+	}
+
+	return r0
+}
+
+// MockStore_ListShards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListShards'
+type MockStore_ListShards_Call struct {
+	*mock.Call
+}
+
+// ListShards is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 store.PartitionKey
+func (_e *MockStore_Expecter) ListShards(_a0 interface{}, _a1 interface{}) *MockStore_ListShards_Call {
+	return &MockStore_ListShards_Call{Call: _e.mock.On("ListShards", _a0, _a1)}
+}
+
+func (_c *MockStore_ListShards_Call) Run(run func(_a0 *bbolt.Tx, _a1 store.PartitionKey)) *MockStore_ListShards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListShards_Call) Return(_a0 []uint32) *MockStore_ListShards_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_ListShards_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey) []uint32) *MockStore_ListShards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTenants provides a mock function with given fields: tx, p, shard
+func (_m *MockStore) ListTenants(tx *bbolt.Tx, p store.PartitionKey, shard uint32) []string {
+	ret := _m.Called(tx, p, shard)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTenants")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, uint32) []string); ok {
+		r0 = rf(tx, p, shard)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// MockStore_ListTenants_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTenants'
+type MockStore_ListTenants_Call struct {
+	*mock.Call
+}
+
+// ListTenants is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - p store.PartitionKey
+//   - shard uint32
+func (_e *MockStore_Expecter) ListTenants(tx interface{}, p interface{}, shard interface{}) *MockStore_ListTenants_Call {
+	return &MockStore_ListTenants_Call{Call: _e.mock.On("ListTenants", tx, p, shard)}
+}
+
+func (_c *MockStore_ListTenants_Call) Run(run func(tx *bbolt.Tx, p store.PartitionKey, shard uint32)) *MockStore_ListTenants_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(uint32))
+	})
+	return _c
+}
+
+func (_c *MockStore_ListTenants_Call) Return(_a0 []string) *MockStore_ListTenants_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_ListTenants_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, uint32) []string) *MockStore_ListTenants_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MovePartition provides a mock function with given fields: tx, source, target
+func (_m *MockStore) MovePartition(tx *bbolt.Tx, source store.PartitionKey, target store.PartitionKey) (int, error) {
+	ret := _m.Called(tx, source, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MovePartition")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, store.PartitionKey) (int, error)); ok {
+		return rf(tx, source, target)
+	}
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, store.PartitionKey) int); ok {
+		r0 = rf(tx, source, target)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(*bbolt.Tx, store.PartitionKey, store.PartitionKey) error); ok {
+		r1 = rf(tx, source, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_MovePartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MovePartition'
+type MockStore_MovePartition_Call struct {
+	*mock.Call
+}
+
+// MovePartition is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - source store.PartitionKey
+//   - target store.PartitionKey
+func (_e *MockStore_Expecter) MovePartition(tx interface{}, source interface{}, target interface{}) *MockStore_MovePartition_Call {
+	return &MockStore_MovePartition_Call{Call: _e.mock.On("MovePartition", tx, source, target)}
+}
+
+func (_c *MockStore_MovePartition_Call) Run(run func(tx *bbolt.Tx, source store.PartitionKey, target store.PartitionKey)) *MockStore_MovePartition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(store.PartitionKey))
+	})
+	return _c
+}
+
+func (_c *MockStore_MovePartition_Call) Return(_a0 int, _a1 error) *MockStore_MovePartition_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_MovePartition_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, store.PartitionKey) (int, error)) *MockStore_MovePartition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PartitionScheme provides a mock function with given fields: _a0
+func (_m *MockStore) PartitionScheme(_a0 *bbolt.Tx) string {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PartitionScheme")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockStore_PartitionScheme_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PartitionScheme'
+type MockStore_PartitionScheme_Call struct {
+	*mock.Call
+}
+
+// PartitionScheme is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+func (_e *MockStore_Expecter) PartitionScheme(_a0 interface{}) *MockStore_PartitionScheme_Call {
+	return &MockStore_PartitionScheme_Call{Call: _e.mock.On("PartitionScheme", _a0)}
+}
+
+func (_c *MockStore_PartitionScheme_Call) Run(run func(_a0 *bbolt.Tx)) *MockStore_PartitionScheme_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx))
+	})
+	return _c
+}
+
+func (_c *MockStore_PartitionScheme_Call) Return(_a0 string) *MockStore_PartitionScheme_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_PartitionScheme_Call) RunAndReturn(run func(*bbolt.Tx) string) *MockStore_PartitionScheme_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecompressBlocks provides a mock function with given fields: _a0
+func (_m *MockStore) RecompressBlocks(_a0 *bbolt.Tx) (int, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecompressBlocks")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) (int, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx) int); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(*bbolt.Tx) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_RecompressBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecompressBlocks'
+type MockStore_RecompressBlocks_Call struct {
+	*mock.Call
+}
+
+// RecompressBlocks is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+func (_e *MockStore_Expecter) RecompressBlocks(_a0 interface{}) *MockStore_RecompressBlocks_Call {
+	return &MockStore_RecompressBlocks_Call{Call: _e.mock.On("RecompressBlocks", _a0)}
+}
+
+func (_c *MockStore_RecompressBlocks_Call) Run(run func(_a0 *bbolt.Tx)) *MockStore_RecompressBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx))
+	})
+	return _c
+}
+
+func (_c *MockStore_RecompressBlocks_Call) Return(_a0 int, _a1 error) *MockStore_RecompressBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_RecompressBlocks_Call) RunAndReturn(run func(*bbolt.Tx) (int, error)) *MockStore_RecompressBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseDictionaryRefs provides a mock function with given fields: tx, paths, releasedAt
+func (_m *MockStore) ReleaseDictionaryRefs(tx *bbolt.Tx, paths []string, releasedAt int64) ([]string, error) {
+	ret := _m.Called(tx, paths, releasedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseDictionaryRefs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, []string, int64) ([]string, error)); ok {
+		return rf(tx, paths, releasedAt)
+	}
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, []string, int64) []string); ok {
+		r0 = rf(tx, paths, releasedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*bbolt.Tx, []string, int64) error); ok {
+		r1 = rf(tx, paths, releasedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_ReleaseDictionaryRefs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseDictionaryRefs'
+type MockStore_ReleaseDictionaryRefs_Call struct {
+	*mock.Call
+}
+
+// ReleaseDictionaryRefs is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - paths []string
+//   - releasedAt int64
+func (_e *MockStore_Expecter) ReleaseDictionaryRefs(tx interface{}, paths interface{}, releasedAt interface{}) *MockStore_ReleaseDictionaryRefs_Call {
+	return &MockStore_ReleaseDictionaryRefs_Call{Call: _e.mock.On("ReleaseDictionaryRefs", tx, paths, releasedAt)}
+}
+
+func (_c *MockStore_ReleaseDictionaryRefs_Call) Run(run func(tx *bbolt.Tx, paths []string, releasedAt int64)) *MockStore_ReleaseDictionaryRefs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].([]string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockStore_ReleaseDictionaryRefs_Call) Return(_a0 []string, _a1 error) *MockStore_ReleaseDictionaryRefs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_ReleaseDictionaryRefs_Call) RunAndReturn(run func(*bbolt.Tx, []string, int64) ([]string, error)) *MockStore_ReleaseDictionaryRefs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepairPartitions provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockStore) RepairPartitions(_a0 *bbolt.Tx, _a1 []store.PartitionIssue, _a2 func(string) time.Duration) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepairPartitions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, []store.PartitionIssue, func(string) time.Duration) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_RepairPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepairPartitions'
+type MockStore_RepairPartitions_Call struct {
+	*mock.Call
+}
+
+// RepairPartitions is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 []store.PartitionIssue
+//   - _a2 func(string) time.Duration
+func (_e *MockStore_Expecter) RepairPartitions(_a0 interface{}, _a1 interface{}, _a2 interface{}) *MockStore_RepairPartitions_Call {
+	return &MockStore_RepairPartitions_Call{Call: _e.mock.On("RepairPartitions", _a0, _a1, _a2)}
+}
+
+func (_c *MockStore_RepairPartitions_Call) Run(run func(_a0 *bbolt.Tx, _a1 []store.PartitionIssue, _a2 func(string) time.Duration)) *MockStore_RepairPartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].([]store.PartitionIssue), args[2].(func(string) time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStore_RepairPartitions_Call) Return(_a0 error) *MockStore_RepairPartitions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_RepairPartitions_Call) RunAndReturn(run func(*bbolt.Tx, []store.PartitionIssue, func(string) time.Duration) error) *MockStore_RepairPartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SplitPartition provides a mock function with given fields: tx, source, d
+func (_m *MockStore) SplitPartition(tx *bbolt.Tx, source store.PartitionKey, d time.Duration) ([]store.PartitionKey, int, error) {
+	ret := _m.Called(tx, source, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitPartition")
+	}
+
+	var r0 []store.PartitionKey
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, time.Duration) ([]store.PartitionKey, int, error)); ok {
+		return rf(tx, source, d)
+	}
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, time.Duration) []store.PartitionKey); ok {
+		r0 = rf(tx, source, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]store.PartitionKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*bbolt.Tx, store.PartitionKey, time.Duration) int); ok {
+		r1 = rf(tx, source, d)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(*bbolt.Tx, store.PartitionKey, time.Duration) error); ok {
+		r2 = rf(tx, source, d)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockStore_SplitPartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SplitPartition'
+type MockStore_SplitPartition_Call struct {
+	*mock.Call
+}
+
+// SplitPartition is a helper method to define mock.On call
+//   - tx *bbolt.Tx
+//   - source store.PartitionKey
+//   - d time.Duration
+func (_e *MockStore_Expecter) SplitPartition(tx interface{}, source interface{}, d interface{}) *MockStore_SplitPartition_Call {
+	return &MockStore_SplitPartition_Call{Call: _e.mock.On("SplitPartition", tx, source, d)}
+}
+
+func (_c *MockStore_SplitPartition_Call) Run(run func(tx *bbolt.Tx, source store.PartitionKey, d time.Duration)) *MockStore_SplitPartition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStore_SplitPartition_Call) Return(_a0 []store.PartitionKey, _a1 int, _a2 error) *MockStore_SplitPartition_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockStore_SplitPartition_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, time.Duration) ([]store.PartitionKey, int, error)) *MockStore_SplitPartition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreBlock provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockStore) StoreBlock(_a0 *bbolt.Tx, _a1 store.PartitionKey, _a2 *metastorev1.BlockMeta) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreBlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, *metastorev1.BlockMeta) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // MockStore_StoreBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreBlock'
@@ -365,6 +1319,148 @@ func (_c *MockStore_StoreBlock_Call) RunAndReturn(run func(*bbolt.Tx, store.Part
 	return _c
 }
 
+// StoreDeletedBlocks provides a mock function with given fields: _a0, _a1
+func (_m *MockStore) StoreDeletedBlocks(_a0 *bbolt.Tx, _a1 []store.DeletedBlock) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreDeletedBlocks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, []store.DeletedBlock) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_StoreDeletedBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreDeletedBlocks'
+type MockStore_StoreDeletedBlocks_Call struct {
+	*mock.Call
+}
+
+// StoreDeletedBlocks is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 []store.DeletedBlock
+func (_e *MockStore_Expecter) StoreDeletedBlocks(_a0 interface{}, _a1 interface{}) *MockStore_StoreDeletedBlocks_Call {
+	return &MockStore_StoreDeletedBlocks_Call{Call: _e.mock.On("StoreDeletedBlocks", _a0, _a1)}
+}
+
+func (_c *MockStore_StoreDeletedBlocks_Call) Run(run func(_a0 *bbolt.Tx, _a1 []store.DeletedBlock)) *MockStore_StoreDeletedBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].([]store.DeletedBlock))
+	})
+	return _c
+}
+
+func (_c *MockStore_StoreDeletedBlocks_Call) Return(_a0 error) *MockStore_StoreDeletedBlocks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_StoreDeletedBlocks_Call) RunAndReturn(run func(*bbolt.Tx, []store.DeletedBlock) error) *MockStore_StoreDeletedBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StorePartitionScheme provides a mock function with given fields: _a0, _a1
+func (_m *MockStore) StorePartitionScheme(_a0 *bbolt.Tx, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StorePartitionScheme")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_StorePartitionScheme_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StorePartitionScheme'
+type MockStore_StorePartitionScheme_Call struct {
+	*mock.Call
+}
+
+// StorePartitionScheme is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 string
+func (_e *MockStore_Expecter) StorePartitionScheme(_a0 interface{}, _a1 interface{}) *MockStore_StorePartitionScheme_Call {
+	return &MockStore_StorePartitionScheme_Call{Call: _e.mock.On("StorePartitionScheme", _a0, _a1)}
+}
+
+func (_c *MockStore_StorePartitionScheme_Call) Run(run func(_a0 *bbolt.Tx, _a1 string)) *MockStore_StorePartitionScheme_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_StorePartitionScheme_Call) Return(_a0 error) *MockStore_StorePartitionScheme_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_StorePartitionScheme_Call) RunAndReturn(run func(*bbolt.Tx, string) error) *MockStore_StorePartitionScheme_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StorePendingBlock provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockStore) StorePendingBlock(_a0 *bbolt.Tx, _a1 store.PartitionKey, _a2 *metastorev1.BlockMeta) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StorePendingBlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*bbolt.Tx, store.PartitionKey, *metastorev1.BlockMeta) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_StorePendingBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StorePendingBlock'
+type MockStore_StorePendingBlock_Call struct {
+	*mock.Call
+}
+
+// StorePendingBlock is a helper method to define mock.On call
+//   - _a0 *bbolt.Tx
+//   - _a1 store.PartitionKey
+//   - _a2 *metastorev1.BlockMeta
+func (_e *MockStore_Expecter) StorePendingBlock(_a0 interface{}, _a1 interface{}, _a2 interface{}) *MockStore_StorePendingBlock_Call {
+	return &MockStore_StorePendingBlock_Call{Call: _e.mock.On("StorePendingBlock", _a0, _a1, _a2)}
+}
+
+func (_c *MockStore_StorePendingBlock_Call) Run(run func(_a0 *bbolt.Tx, _a1 store.PartitionKey, _a2 *metastorev1.BlockMeta)) *MockStore_StorePendingBlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*bbolt.Tx), args[1].(store.PartitionKey), args[2].(*metastorev1.BlockMeta))
+	})
+	return _c
+}
+
+func (_c *MockStore_StorePendingBlock_Call) Return(_a0 error) *MockStore_StorePendingBlock_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_StorePendingBlock_Call) RunAndReturn(run func(*bbolt.Tx, store.PartitionKey, *metastorev1.BlockMeta) error) *MockStore_StorePendingBlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockStore creates a new instance of MockStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockStore(t interface {
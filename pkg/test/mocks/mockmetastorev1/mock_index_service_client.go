@@ -99,6 +99,228 @@ func (_c *MockIndexServiceClient_AddBlock_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// AddBlocks provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) AddBlocks(ctx context.Context, in *metastorev1.AddBlocksRequest, opts ...grpc.CallOption) (*metastorev1.AddBlocksResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddBlocks")
+	}
+
+	var r0 *metastorev1.AddBlocksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.AddBlocksRequest, ...grpc.CallOption) (*metastorev1.AddBlocksResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.AddBlocksRequest, ...grpc.CallOption) *metastorev1.AddBlocksResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.AddBlocksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.AddBlocksRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_AddBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddBlocks'
+type MockIndexServiceClient_AddBlocks_Call struct {
+	*mock.Call
+}
+
+// AddBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.AddBlocksRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) AddBlocks(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_AddBlocks_Call {
+	return &MockIndexServiceClient_AddBlocks_Call{Call: _e.mock.On("AddBlocks",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_AddBlocks_Call) Run(run func(ctx context.Context, in *metastorev1.AddBlocksRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_AddBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.AddBlocksRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_AddBlocks_Call) Return(_a0 *metastorev1.AddBlocksResponse, _a1 error) *MockIndexServiceClient_AddBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_AddBlocks_Call) RunAndReturn(run func(context.Context, *metastorev1.AddBlocksRequest, ...grpc.CallOption) (*metastorev1.AddBlocksResponse, error)) *MockIndexServiceClient_AddBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckIndex provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) CheckIndex(ctx context.Context, in *metastorev1.CheckIndexRequest, opts ...grpc.CallOption) (*metastorev1.CheckIndexResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckIndex")
+	}
+
+	var r0 *metastorev1.CheckIndexResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.CheckIndexRequest, ...grpc.CallOption) (*metastorev1.CheckIndexResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.CheckIndexRequest, ...grpc.CallOption) *metastorev1.CheckIndexResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.CheckIndexResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.CheckIndexRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_CheckIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckIndex'
+type MockIndexServiceClient_CheckIndex_Call struct {
+	*mock.Call
+}
+
+// CheckIndex is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.CheckIndexRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) CheckIndex(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_CheckIndex_Call {
+	return &MockIndexServiceClient_CheckIndex_Call{Call: _e.mock.On("CheckIndex",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_CheckIndex_Call) Run(run func(ctx context.Context, in *metastorev1.CheckIndexRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_CheckIndex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.CheckIndexRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_CheckIndex_Call) Return(_a0 *metastorev1.CheckIndexResponse, _a1 error) *MockIndexServiceClient_CheckIndex_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_CheckIndex_Call) RunAndReturn(run func(context.Context, *metastorev1.CheckIndexRequest, ...grpc.CallOption) (*metastorev1.CheckIndexResponse, error)) *MockIndexServiceClient_CheckIndex_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeBlock provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) DescribeBlock(ctx context.Context, in *metastorev1.DescribeBlockRequest, opts ...grpc.CallOption) (*metastorev1.DescribeBlockResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeBlock")
+	}
+
+	var r0 *metastorev1.DescribeBlockResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.DescribeBlockRequest, ...grpc.CallOption) (*metastorev1.DescribeBlockResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.DescribeBlockRequest, ...grpc.CallOption) *metastorev1.DescribeBlockResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.DescribeBlockResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.DescribeBlockRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_DescribeBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeBlock'
+type MockIndexServiceClient_DescribeBlock_Call struct {
+	*mock.Call
+}
+
+// DescribeBlock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.DescribeBlockRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) DescribeBlock(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_DescribeBlock_Call {
+	return &MockIndexServiceClient_DescribeBlock_Call{Call: _e.mock.On("DescribeBlock",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_DescribeBlock_Call) Run(run func(ctx context.Context, in *metastorev1.DescribeBlockRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_DescribeBlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.DescribeBlockRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_DescribeBlock_Call) Return(_a0 *metastorev1.DescribeBlockResponse, _a1 error) *MockIndexServiceClient_DescribeBlock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_DescribeBlock_Call) RunAndReturn(run func(context.Context, *metastorev1.DescribeBlockRequest, ...grpc.CallOption) (*metastorev1.DescribeBlockResponse, error)) *MockIndexServiceClient_DescribeBlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetBlockMetadata provides a mock function with given fields: ctx, in, opts
 func (_m *MockIndexServiceClient) GetBlockMetadata(ctx context.Context, in *metastorev1.GetBlockMetadataRequest, opts ...grpc.CallOption) (*metastorev1.GetBlockMetadataResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -173,16 +395,756 @@ func (_c *MockIndexServiceClient_GetBlockMetadata_Call) RunAndReturn(run func(co
 	return _c
 }
 
+// WatchBlocks provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) WatchBlocks(ctx context.Context, in *metastorev1.WatchBlocksRequest, opts ...grpc.CallOption) (metastorev1.IndexService_WatchBlocksClient, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchBlocks")
+	}
+
+	var r0 metastorev1.IndexService_WatchBlocksClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.WatchBlocksRequest, ...grpc.CallOption) (metastorev1.IndexService_WatchBlocksClient, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.WatchBlocksRequest, ...grpc.CallOption) metastorev1.IndexService_WatchBlocksClient); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(metastorev1.IndexService_WatchBlocksClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.WatchBlocksRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_WatchBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchBlocks'
+type MockIndexServiceClient_WatchBlocks_Call struct {
+	*mock.Call
+}
+
+// WatchBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.WatchBlocksRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) WatchBlocks(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_WatchBlocks_Call {
+	return &MockIndexServiceClient_WatchBlocks_Call{Call: _e.mock.On("WatchBlocks",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_WatchBlocks_Call) Run(run func(ctx context.Context, in *metastorev1.WatchBlocksRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_WatchBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.WatchBlocksRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_WatchBlocks_Call) Return(_a0 metastorev1.IndexService_WatchBlocksClient, _a1 error) *MockIndexServiceClient_WatchBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_WatchBlocks_Call) RunAndReturn(run func(context.Context, *metastorev1.WatchBlocksRequest, ...grpc.CallOption) (metastorev1.IndexService_WatchBlocksClient, error)) *MockIndexServiceClient_WatchBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockIndexServiceClient creates a new instance of MockIndexServiceClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockIndexServiceClient(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockIndexServiceClient {
-	mock := &MockIndexServiceClient{}
-	mock.Mock.Test(t)
+// GetIndexRebuildStatus provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) GetIndexRebuildStatus(ctx context.Context, in *metastorev1.GetIndexRebuildStatusRequest, opts ...grpc.CallOption) (*metastorev1.GetIndexRebuildStatusResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+	if len(ret) == 0 {
+		panic("no return value specified for GetIndexRebuildStatus")
+	}
 
-	return mock
+	var r0 *metastorev1.GetIndexRebuildStatusResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetIndexRebuildStatusRequest, ...grpc.CallOption) (*metastorev1.GetIndexRebuildStatusResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetIndexRebuildStatusRequest, ...grpc.CallOption) *metastorev1.GetIndexRebuildStatusResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.GetIndexRebuildStatusResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.GetIndexRebuildStatusRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_GetIndexRebuildStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIndexRebuildStatus'
+type MockIndexServiceClient_GetIndexRebuildStatus_Call struct {
+	*mock.Call
+}
+
+// GetIndexRebuildStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.GetIndexRebuildStatusRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) GetIndexRebuildStatus(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_GetIndexRebuildStatus_Call {
+	return &MockIndexServiceClient_GetIndexRebuildStatus_Call{Call: _e.mock.On("GetIndexRebuildStatus",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_GetIndexRebuildStatus_Call) Run(run func(ctx context.Context, in *metastorev1.GetIndexRebuildStatusRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_GetIndexRebuildStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.GetIndexRebuildStatusRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_GetIndexRebuildStatus_Call) Return(_a0 *metastorev1.GetIndexRebuildStatusResponse, _a1 error) *MockIndexServiceClient_GetIndexRebuildStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_GetIndexRebuildStatus_Call) RunAndReturn(run func(context.Context, *metastorev1.GetIndexRebuildStatusRequest, ...grpc.CallOption) (*metastorev1.GetIndexRebuildStatusResponse, error)) *MockIndexServiceClient_GetIndexRebuildStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListBlocks provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) ListBlocks(ctx context.Context, in *metastorev1.ListBlocksRequest, opts ...grpc.CallOption) (*metastorev1.ListBlocksResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBlocks")
+	}
+
+	var r0 *metastorev1.ListBlocksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListBlocksRequest, ...grpc.CallOption) (*metastorev1.ListBlocksResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListBlocksRequest, ...grpc.CallOption) *metastorev1.ListBlocksResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListBlocksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListBlocksRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_ListBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBlocks'
+type MockIndexServiceClient_ListBlocks_Call struct {
+	*mock.Call
+}
+
+// ListBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.ListBlocksRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) ListBlocks(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_ListBlocks_Call {
+	return &MockIndexServiceClient_ListBlocks_Call{Call: _e.mock.On("ListBlocks",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_ListBlocks_Call) Run(run func(ctx context.Context, in *metastorev1.ListBlocksRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_ListBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.ListBlocksRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListBlocks_Call) Return(_a0 *metastorev1.ListBlocksResponse, _a1 error) *MockIndexServiceClient_ListBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListBlocks_Call) RunAndReturn(run func(context.Context, *metastorev1.ListBlocksRequest, ...grpc.CallOption) (*metastorev1.ListBlocksResponse, error)) *MockIndexServiceClient_ListBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPartitions provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) ListPartitions(ctx context.Context, in *metastorev1.ListPartitionsRequest, opts ...grpc.CallOption) (*metastorev1.ListPartitionsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPartitions")
+	}
+
+	var r0 *metastorev1.ListPartitionsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListPartitionsRequest, ...grpc.CallOption) (*metastorev1.ListPartitionsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListPartitionsRequest, ...grpc.CallOption) *metastorev1.ListPartitionsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListPartitionsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListPartitionsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_ListPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPartitions'
+type MockIndexServiceClient_ListPartitions_Call struct {
+	*mock.Call
+}
+
+// ListPartitions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.ListPartitionsRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) ListPartitions(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_ListPartitions_Call {
+	return &MockIndexServiceClient_ListPartitions_Call{Call: _e.mock.On("ListPartitions",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_ListPartitions_Call) Run(run func(ctx context.Context, in *metastorev1.ListPartitionsRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_ListPartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.ListPartitionsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListPartitions_Call) Return(_a0 *metastorev1.ListPartitionsResponse, _a1 error) *MockIndexServiceClient_ListPartitions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListPartitions_Call) RunAndReturn(run func(context.Context, *metastorev1.ListPartitionsRequest, ...grpc.CallOption) (*metastorev1.ListPartitionsResponse, error)) *MockIndexServiceClient_ListPartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQuarantinedBlocks provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) ListQuarantinedBlocks(ctx context.Context, in *metastorev1.ListQuarantinedBlocksRequest, opts ...grpc.CallOption) (*metastorev1.ListQuarantinedBlocksResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQuarantinedBlocks")
+	}
+
+	var r0 *metastorev1.ListQuarantinedBlocksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedBlocksRequest, ...grpc.CallOption) (*metastorev1.ListQuarantinedBlocksResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedBlocksRequest, ...grpc.CallOption) *metastorev1.ListQuarantinedBlocksResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListQuarantinedBlocksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListQuarantinedBlocksRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_ListQuarantinedBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQuarantinedBlocks'
+type MockIndexServiceClient_ListQuarantinedBlocks_Call struct {
+	*mock.Call
+}
+
+// ListQuarantinedBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.ListQuarantinedBlocksRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) ListQuarantinedBlocks(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_ListQuarantinedBlocks_Call {
+	return &MockIndexServiceClient_ListQuarantinedBlocks_Call{Call: _e.mock.On("ListQuarantinedBlocks",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_ListQuarantinedBlocks_Call) Run(run func(ctx context.Context, in *metastorev1.ListQuarantinedBlocksRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_ListQuarantinedBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.ListQuarantinedBlocksRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListQuarantinedBlocks_Call) Return(_a0 *metastorev1.ListQuarantinedBlocksResponse, _a1 error) *MockIndexServiceClient_ListQuarantinedBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListQuarantinedBlocks_Call) RunAndReturn(run func(context.Context, *metastorev1.ListQuarantinedBlocksRequest, ...grpc.CallOption) (*metastorev1.ListQuarantinedBlocksResponse, error)) *MockIndexServiceClient_ListQuarantinedBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQuarantinedWriters provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) ListQuarantinedWriters(ctx context.Context, in *metastorev1.ListQuarantinedWritersRequest, opts ...grpc.CallOption) (*metastorev1.ListQuarantinedWritersResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQuarantinedWriters")
+	}
+
+	var r0 *metastorev1.ListQuarantinedWritersResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedWritersRequest, ...grpc.CallOption) (*metastorev1.ListQuarantinedWritersResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedWritersRequest, ...grpc.CallOption) *metastorev1.ListQuarantinedWritersResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListQuarantinedWritersResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListQuarantinedWritersRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_ListQuarantinedWriters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQuarantinedWriters'
+type MockIndexServiceClient_ListQuarantinedWriters_Call struct {
+	*mock.Call
+}
+
+// ListQuarantinedWriters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.ListQuarantinedWritersRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) ListQuarantinedWriters(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_ListQuarantinedWriters_Call {
+	return &MockIndexServiceClient_ListQuarantinedWriters_Call{Call: _e.mock.On("ListQuarantinedWriters",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_ListQuarantinedWriters_Call) Run(run func(ctx context.Context, in *metastorev1.ListQuarantinedWritersRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_ListQuarantinedWriters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.ListQuarantinedWritersRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListQuarantinedWriters_Call) Return(_a0 *metastorev1.ListQuarantinedWritersResponse, _a1 error) *MockIndexServiceClient_ListQuarantinedWriters_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ListQuarantinedWriters_Call) RunAndReturn(run func(context.Context, *metastorev1.ListQuarantinedWritersRequest, ...grpc.CallOption) (*metastorev1.ListQuarantinedWritersResponse, error)) *MockIndexServiceClient_ListQuarantinedWriters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QuarantineBlock provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) QuarantineBlock(ctx context.Context, in *metastorev1.QuarantineBlockRequest, opts ...grpc.CallOption) (*metastorev1.QuarantineBlockResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuarantineBlock")
+	}
+
+	var r0 *metastorev1.QuarantineBlockResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineBlockRequest, ...grpc.CallOption) (*metastorev1.QuarantineBlockResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineBlockRequest, ...grpc.CallOption) *metastorev1.QuarantineBlockResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.QuarantineBlockResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.QuarantineBlockRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_QuarantineBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QuarantineBlock'
+type MockIndexServiceClient_QuarantineBlock_Call struct {
+	*mock.Call
+}
+
+// QuarantineBlock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.QuarantineBlockRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) QuarantineBlock(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_QuarantineBlock_Call {
+	return &MockIndexServiceClient_QuarantineBlock_Call{Call: _e.mock.On("QuarantineBlock",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_QuarantineBlock_Call) Run(run func(ctx context.Context, in *metastorev1.QuarantineBlockRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_QuarantineBlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.QuarantineBlockRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_QuarantineBlock_Call) Return(_a0 *metastorev1.QuarantineBlockResponse, _a1 error) *MockIndexServiceClient_QuarantineBlock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_QuarantineBlock_Call) RunAndReturn(run func(context.Context, *metastorev1.QuarantineBlockRequest, ...grpc.CallOption) (*metastorev1.QuarantineBlockResponse, error)) *MockIndexServiceClient_QuarantineBlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+func NewMockIndexServiceClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIndexServiceClient {
+	mock := &MockIndexServiceClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// QuarantineWriter provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) QuarantineWriter(ctx context.Context, in *metastorev1.QuarantineWriterRequest, opts ...grpc.CallOption) (*metastorev1.QuarantineWriterResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuarantineWriter")
+	}
+
+	var r0 *metastorev1.QuarantineWriterResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineWriterRequest, ...grpc.CallOption) (*metastorev1.QuarantineWriterResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineWriterRequest, ...grpc.CallOption) *metastorev1.QuarantineWriterResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.QuarantineWriterResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.QuarantineWriterRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_QuarantineWriter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QuarantineWriter'
+type MockIndexServiceClient_QuarantineWriter_Call struct {
+	*mock.Call
+}
+
+// QuarantineWriter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.QuarantineWriterRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) QuarantineWriter(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_QuarantineWriter_Call {
+	return &MockIndexServiceClient_QuarantineWriter_Call{Call: _e.mock.On("QuarantineWriter",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_QuarantineWriter_Call) Run(run func(ctx context.Context, in *metastorev1.QuarantineWriterRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_QuarantineWriter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.QuarantineWriterRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_QuarantineWriter_Call) Return(_a0 *metastorev1.QuarantineWriterResponse, _a1 error) *MockIndexServiceClient_QuarantineWriter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_QuarantineWriter_Call) RunAndReturn(run func(context.Context, *metastorev1.QuarantineWriterRequest, ...grpc.CallOption) (*metastorev1.QuarantineWriterResponse, error)) *MockIndexServiceClient_QuarantineWriter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebuildIndex provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) RebuildIndex(ctx context.Context, in *metastorev1.RebuildIndexRequest, opts ...grpc.CallOption) (*metastorev1.RebuildIndexResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildIndex")
+	}
+
+	var r0 *metastorev1.RebuildIndexResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.RebuildIndexRequest, ...grpc.CallOption) (*metastorev1.RebuildIndexResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.RebuildIndexRequest, ...grpc.CallOption) *metastorev1.RebuildIndexResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.RebuildIndexResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.RebuildIndexRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_RebuildIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebuildIndex'
+type MockIndexServiceClient_RebuildIndex_Call struct {
+	*mock.Call
+}
+
+// RebuildIndex is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.RebuildIndexRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) RebuildIndex(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_RebuildIndex_Call {
+	return &MockIndexServiceClient_RebuildIndex_Call{Call: _e.mock.On("RebuildIndex",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_RebuildIndex_Call) Run(run func(ctx context.Context, in *metastorev1.RebuildIndexRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_RebuildIndex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.RebuildIndexRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_RebuildIndex_Call) Return(_a0 *metastorev1.RebuildIndexResponse, _a1 error) *MockIndexServiceClient_RebuildIndex_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_RebuildIndex_Call) RunAndReturn(run func(context.Context, *metastorev1.RebuildIndexRequest, ...grpc.CallOption) (*metastorev1.RebuildIndexResponse, error)) *MockIndexServiceClient_RebuildIndex_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseWriter provides a mock function with given fields: ctx, in, opts
+func (_m *MockIndexServiceClient) ReleaseWriter(ctx context.Context, in *metastorev1.ReleaseWriterRequest, opts ...grpc.CallOption) (*metastorev1.ReleaseWriterResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseWriter")
+	}
+
+	var r0 *metastorev1.ReleaseWriterResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ReleaseWriterRequest, ...grpc.CallOption) (*metastorev1.ReleaseWriterResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ReleaseWriterRequest, ...grpc.CallOption) *metastorev1.ReleaseWriterResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ReleaseWriterResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ReleaseWriterRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceClient_ReleaseWriter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseWriter'
+type MockIndexServiceClient_ReleaseWriter_Call struct {
+	*mock.Call
+}
+
+// ReleaseWriter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.ReleaseWriterRequest
+//   - opts ...grpc.CallOption
+func (_e *MockIndexServiceClient_Expecter) ReleaseWriter(ctx interface{}, in interface{}, opts ...interface{}) *MockIndexServiceClient_ReleaseWriter_Call {
+	return &MockIndexServiceClient_ReleaseWriter_Call{Call: _e.mock.On("ReleaseWriter",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockIndexServiceClient_ReleaseWriter_Call) Run(run func(ctx context.Context, in *metastorev1.ReleaseWriterRequest, opts ...grpc.CallOption)) *MockIndexServiceClient_ReleaseWriter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.ReleaseWriterRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ReleaseWriter_Call) Return(_a0 *metastorev1.ReleaseWriterResponse, _a1 error) *MockIndexServiceClient_ReleaseWriter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceClient_ReleaseWriter_Call) RunAndReturn(run func(context.Context, *metastorev1.ReleaseWriterRequest, ...grpc.CallOption) (*metastorev1.ReleaseWriterResponse, error)) *MockIndexServiceClient_ReleaseWriter_Call {
+	_c.Call.Return(run)
+	return _c
 }
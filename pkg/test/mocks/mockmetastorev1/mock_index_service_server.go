@@ -81,6 +81,183 @@ func (_c *MockIndexServiceServer_AddBlock_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// AddBlocks provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) AddBlocks(_a0 context.Context, _a1 *metastorev1.AddBlocksRequest) (*metastorev1.AddBlocksResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddBlocks")
+	}
+
+	var r0 *metastorev1.AddBlocksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.AddBlocksRequest) (*metastorev1.AddBlocksResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.AddBlocksRequest) *metastorev1.AddBlocksResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.AddBlocksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.AddBlocksRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_AddBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddBlocks'
+type MockIndexServiceServer_AddBlocks_Call struct {
+	*mock.Call
+}
+
+// AddBlocks is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.AddBlocksRequest
+func (_e *MockIndexServiceServer_Expecter) AddBlocks(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_AddBlocks_Call {
+	return &MockIndexServiceServer_AddBlocks_Call{Call: _e.mock.On("AddBlocks", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_AddBlocks_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.AddBlocksRequest)) *MockIndexServiceServer_AddBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.AddBlocksRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_AddBlocks_Call) Return(_a0 *metastorev1.AddBlocksResponse, _a1 error) *MockIndexServiceServer_AddBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_AddBlocks_Call) RunAndReturn(run func(context.Context, *metastorev1.AddBlocksRequest) (*metastorev1.AddBlocksResponse, error)) *MockIndexServiceServer_AddBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckIndex provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) CheckIndex(_a0 context.Context, _a1 *metastorev1.CheckIndexRequest) (*metastorev1.CheckIndexResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckIndex")
+	}
+
+	var r0 *metastorev1.CheckIndexResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.CheckIndexRequest) (*metastorev1.CheckIndexResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.CheckIndexRequest) *metastorev1.CheckIndexResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.CheckIndexResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.CheckIndexRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_CheckIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckIndex'
+type MockIndexServiceServer_CheckIndex_Call struct {
+	*mock.Call
+}
+
+// CheckIndex is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.CheckIndexRequest
+func (_e *MockIndexServiceServer_Expecter) CheckIndex(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_CheckIndex_Call {
+	return &MockIndexServiceServer_CheckIndex_Call{Call: _e.mock.On("CheckIndex", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_CheckIndex_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.CheckIndexRequest)) *MockIndexServiceServer_CheckIndex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.CheckIndexRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_CheckIndex_Call) Return(_a0 *metastorev1.CheckIndexResponse, _a1 error) *MockIndexServiceServer_CheckIndex_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_CheckIndex_Call) RunAndReturn(run func(context.Context, *metastorev1.CheckIndexRequest) (*metastorev1.CheckIndexResponse, error)) *MockIndexServiceServer_CheckIndex_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeBlock provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) DescribeBlock(_a0 context.Context, _a1 *metastorev1.DescribeBlockRequest) (*metastorev1.DescribeBlockResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeBlock")
+	}
+
+	var r0 *metastorev1.DescribeBlockResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.DescribeBlockRequest) (*metastorev1.DescribeBlockResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.DescribeBlockRequest) *metastorev1.DescribeBlockResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.DescribeBlockResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.DescribeBlockRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_DescribeBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeBlock'
+type MockIndexServiceServer_DescribeBlock_Call struct {
+	*mock.Call
+}
+
+// DescribeBlock is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.DescribeBlockRequest
+func (_e *MockIndexServiceServer_Expecter) DescribeBlock(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_DescribeBlock_Call {
+	return &MockIndexServiceServer_DescribeBlock_Call{Call: _e.mock.On("DescribeBlock", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_DescribeBlock_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.DescribeBlockRequest)) *MockIndexServiceServer_DescribeBlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.DescribeBlockRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_DescribeBlock_Call) Return(_a0 *metastorev1.DescribeBlockResponse, _a1 error) *MockIndexServiceServer_DescribeBlock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_DescribeBlock_Call) RunAndReturn(run func(context.Context, *metastorev1.DescribeBlockRequest) (*metastorev1.DescribeBlockResponse, error)) *MockIndexServiceServer_DescribeBlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetBlockMetadata provides a mock function with given fields: _a0, _a1
 func (_m *MockIndexServiceServer) GetBlockMetadata(_a0 context.Context, _a1 *metastorev1.GetBlockMetadataRequest) (*metastorev1.GetBlockMetadataResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -140,6 +317,584 @@ func (_c *MockIndexServiceServer_GetBlockMetadata_Call) RunAndReturn(run func(co
 	return _c
 }
 
+// GetIndexRebuildStatus provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) GetIndexRebuildStatus(_a0 context.Context, _a1 *metastorev1.GetIndexRebuildStatusRequest) (*metastorev1.GetIndexRebuildStatusResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIndexRebuildStatus")
+	}
+
+	var r0 *metastorev1.GetIndexRebuildStatusResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetIndexRebuildStatusRequest) (*metastorev1.GetIndexRebuildStatusResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetIndexRebuildStatusRequest) *metastorev1.GetIndexRebuildStatusResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.GetIndexRebuildStatusResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.GetIndexRebuildStatusRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_GetIndexRebuildStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIndexRebuildStatus'
+type MockIndexServiceServer_GetIndexRebuildStatus_Call struct {
+	*mock.Call
+}
+
+// GetIndexRebuildStatus is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.GetIndexRebuildStatusRequest
+func (_e *MockIndexServiceServer_Expecter) GetIndexRebuildStatus(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_GetIndexRebuildStatus_Call {
+	return &MockIndexServiceServer_GetIndexRebuildStatus_Call{Call: _e.mock.On("GetIndexRebuildStatus", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_GetIndexRebuildStatus_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.GetIndexRebuildStatusRequest)) *MockIndexServiceServer_GetIndexRebuildStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.GetIndexRebuildStatusRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_GetIndexRebuildStatus_Call) Return(_a0 *metastorev1.GetIndexRebuildStatusResponse, _a1 error) *MockIndexServiceServer_GetIndexRebuildStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_GetIndexRebuildStatus_Call) RunAndReturn(run func(context.Context, *metastorev1.GetIndexRebuildStatusRequest) (*metastorev1.GetIndexRebuildStatusResponse, error)) *MockIndexServiceServer_GetIndexRebuildStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListBlocks provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) ListBlocks(_a0 context.Context, _a1 *metastorev1.ListBlocksRequest) (*metastorev1.ListBlocksResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBlocks")
+	}
+
+	var r0 *metastorev1.ListBlocksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListBlocksRequest) (*metastorev1.ListBlocksResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListBlocksRequest) *metastorev1.ListBlocksResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListBlocksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListBlocksRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_ListBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBlocks'
+type MockIndexServiceServer_ListBlocks_Call struct {
+	*mock.Call
+}
+
+// ListBlocks is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.ListBlocksRequest
+func (_e *MockIndexServiceServer_Expecter) ListBlocks(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_ListBlocks_Call {
+	return &MockIndexServiceServer_ListBlocks_Call{Call: _e.mock.On("ListBlocks", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_ListBlocks_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.ListBlocksRequest)) *MockIndexServiceServer_ListBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.ListBlocksRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListBlocks_Call) Return(_a0 *metastorev1.ListBlocksResponse, _a1 error) *MockIndexServiceServer_ListBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListBlocks_Call) RunAndReturn(run func(context.Context, *metastorev1.ListBlocksRequest) (*metastorev1.ListBlocksResponse, error)) *MockIndexServiceServer_ListBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPartitions provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) ListPartitions(_a0 context.Context, _a1 *metastorev1.ListPartitionsRequest) (*metastorev1.ListPartitionsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPartitions")
+	}
+
+	var r0 *metastorev1.ListPartitionsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListPartitionsRequest) (*metastorev1.ListPartitionsResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListPartitionsRequest) *metastorev1.ListPartitionsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListPartitionsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListPartitionsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_ListPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPartitions'
+type MockIndexServiceServer_ListPartitions_Call struct {
+	*mock.Call
+}
+
+// ListPartitions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.ListPartitionsRequest
+func (_e *MockIndexServiceServer_Expecter) ListPartitions(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_ListPartitions_Call {
+	return &MockIndexServiceServer_ListPartitions_Call{Call: _e.mock.On("ListPartitions", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_ListPartitions_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.ListPartitionsRequest)) *MockIndexServiceServer_ListPartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.ListPartitionsRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListPartitions_Call) Return(_a0 *metastorev1.ListPartitionsResponse, _a1 error) *MockIndexServiceServer_ListPartitions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListPartitions_Call) RunAndReturn(run func(context.Context, *metastorev1.ListPartitionsRequest) (*metastorev1.ListPartitionsResponse, error)) *MockIndexServiceServer_ListPartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQuarantinedBlocks provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) ListQuarantinedBlocks(_a0 context.Context, _a1 *metastorev1.ListQuarantinedBlocksRequest) (*metastorev1.ListQuarantinedBlocksResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQuarantinedBlocks")
+	}
+
+	var r0 *metastorev1.ListQuarantinedBlocksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedBlocksRequest) (*metastorev1.ListQuarantinedBlocksResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedBlocksRequest) *metastorev1.ListQuarantinedBlocksResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListQuarantinedBlocksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListQuarantinedBlocksRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_ListQuarantinedBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQuarantinedBlocks'
+type MockIndexServiceServer_ListQuarantinedBlocks_Call struct {
+	*mock.Call
+}
+
+// ListQuarantinedBlocks is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.ListQuarantinedBlocksRequest
+func (_e *MockIndexServiceServer_Expecter) ListQuarantinedBlocks(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_ListQuarantinedBlocks_Call {
+	return &MockIndexServiceServer_ListQuarantinedBlocks_Call{Call: _e.mock.On("ListQuarantinedBlocks", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_ListQuarantinedBlocks_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.ListQuarantinedBlocksRequest)) *MockIndexServiceServer_ListQuarantinedBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.ListQuarantinedBlocksRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListQuarantinedBlocks_Call) Return(_a0 *metastorev1.ListQuarantinedBlocksResponse, _a1 error) *MockIndexServiceServer_ListQuarantinedBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListQuarantinedBlocks_Call) RunAndReturn(run func(context.Context, *metastorev1.ListQuarantinedBlocksRequest) (*metastorev1.ListQuarantinedBlocksResponse, error)) *MockIndexServiceServer_ListQuarantinedBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListQuarantinedWriters provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) ListQuarantinedWriters(_a0 context.Context, _a1 *metastorev1.ListQuarantinedWritersRequest) (*metastorev1.ListQuarantinedWritersResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListQuarantinedWriters")
+	}
+
+	var r0 *metastorev1.ListQuarantinedWritersResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedWritersRequest) (*metastorev1.ListQuarantinedWritersResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ListQuarantinedWritersRequest) *metastorev1.ListQuarantinedWritersResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ListQuarantinedWritersResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ListQuarantinedWritersRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_ListQuarantinedWriters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListQuarantinedWriters'
+type MockIndexServiceServer_ListQuarantinedWriters_Call struct {
+	*mock.Call
+}
+
+// ListQuarantinedWriters is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.ListQuarantinedWritersRequest
+func (_e *MockIndexServiceServer_Expecter) ListQuarantinedWriters(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_ListQuarantinedWriters_Call {
+	return &MockIndexServiceServer_ListQuarantinedWriters_Call{Call: _e.mock.On("ListQuarantinedWriters", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_ListQuarantinedWriters_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.ListQuarantinedWritersRequest)) *MockIndexServiceServer_ListQuarantinedWriters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.ListQuarantinedWritersRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListQuarantinedWriters_Call) Return(_a0 *metastorev1.ListQuarantinedWritersResponse, _a1 error) *MockIndexServiceServer_ListQuarantinedWriters_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ListQuarantinedWriters_Call) RunAndReturn(run func(context.Context, *metastorev1.ListQuarantinedWritersRequest) (*metastorev1.ListQuarantinedWritersResponse, error)) *MockIndexServiceServer_ListQuarantinedWriters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QuarantineBlock provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) QuarantineBlock(_a0 context.Context, _a1 *metastorev1.QuarantineBlockRequest) (*metastorev1.QuarantineBlockResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuarantineBlock")
+	}
+
+	var r0 *metastorev1.QuarantineBlockResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineBlockRequest) (*metastorev1.QuarantineBlockResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineBlockRequest) *metastorev1.QuarantineBlockResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.QuarantineBlockResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.QuarantineBlockRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_QuarantineBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QuarantineBlock'
+type MockIndexServiceServer_QuarantineBlock_Call struct {
+	*mock.Call
+}
+
+// QuarantineBlock is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.QuarantineBlockRequest
+func (_e *MockIndexServiceServer_Expecter) QuarantineBlock(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_QuarantineBlock_Call {
+	return &MockIndexServiceServer_QuarantineBlock_Call{Call: _e.mock.On("QuarantineBlock", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_QuarantineBlock_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.QuarantineBlockRequest)) *MockIndexServiceServer_QuarantineBlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.QuarantineBlockRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_QuarantineBlock_Call) Return(_a0 *metastorev1.QuarantineBlockResponse, _a1 error) *MockIndexServiceServer_QuarantineBlock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_QuarantineBlock_Call) RunAndReturn(run func(context.Context, *metastorev1.QuarantineBlockRequest) (*metastorev1.QuarantineBlockResponse, error)) *MockIndexServiceServer_QuarantineBlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QuarantineWriter provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) QuarantineWriter(_a0 context.Context, _a1 *metastorev1.QuarantineWriterRequest) (*metastorev1.QuarantineWriterResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuarantineWriter")
+	}
+
+	var r0 *metastorev1.QuarantineWriterResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineWriterRequest) (*metastorev1.QuarantineWriterResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.QuarantineWriterRequest) *metastorev1.QuarantineWriterResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.QuarantineWriterResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.QuarantineWriterRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_QuarantineWriter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QuarantineWriter'
+type MockIndexServiceServer_QuarantineWriter_Call struct {
+	*mock.Call
+}
+
+// QuarantineWriter is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.QuarantineWriterRequest
+func (_e *MockIndexServiceServer_Expecter) QuarantineWriter(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_QuarantineWriter_Call {
+	return &MockIndexServiceServer_QuarantineWriter_Call{Call: _e.mock.On("QuarantineWriter", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_QuarantineWriter_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.QuarantineWriterRequest)) *MockIndexServiceServer_QuarantineWriter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.QuarantineWriterRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_QuarantineWriter_Call) Return(_a0 *metastorev1.QuarantineWriterResponse, _a1 error) *MockIndexServiceServer_QuarantineWriter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_QuarantineWriter_Call) RunAndReturn(run func(context.Context, *metastorev1.QuarantineWriterRequest) (*metastorev1.QuarantineWriterResponse, error)) *MockIndexServiceServer_QuarantineWriter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebuildIndex provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) RebuildIndex(_a0 context.Context, _a1 *metastorev1.RebuildIndexRequest) (*metastorev1.RebuildIndexResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildIndex")
+	}
+
+	var r0 *metastorev1.RebuildIndexResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.RebuildIndexRequest) (*metastorev1.RebuildIndexResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.RebuildIndexRequest) *metastorev1.RebuildIndexResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.RebuildIndexResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.RebuildIndexRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_RebuildIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebuildIndex'
+type MockIndexServiceServer_RebuildIndex_Call struct {
+	*mock.Call
+}
+
+// RebuildIndex is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.RebuildIndexRequest
+func (_e *MockIndexServiceServer_Expecter) RebuildIndex(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_RebuildIndex_Call {
+	return &MockIndexServiceServer_RebuildIndex_Call{Call: _e.mock.On("RebuildIndex", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_RebuildIndex_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.RebuildIndexRequest)) *MockIndexServiceServer_RebuildIndex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.RebuildIndexRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_RebuildIndex_Call) Return(_a0 *metastorev1.RebuildIndexResponse, _a1 error) *MockIndexServiceServer_RebuildIndex_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_RebuildIndex_Call) RunAndReturn(run func(context.Context, *metastorev1.RebuildIndexRequest) (*metastorev1.RebuildIndexResponse, error)) *MockIndexServiceServer_RebuildIndex_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseWriter provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) ReleaseWriter(_a0 context.Context, _a1 *metastorev1.ReleaseWriterRequest) (*metastorev1.ReleaseWriterResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseWriter")
+	}
+
+	var r0 *metastorev1.ReleaseWriterResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ReleaseWriterRequest) (*metastorev1.ReleaseWriterResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.ReleaseWriterRequest) *metastorev1.ReleaseWriterResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.ReleaseWriterResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.ReleaseWriterRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIndexServiceServer_ReleaseWriter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseWriter'
+type MockIndexServiceServer_ReleaseWriter_Call struct {
+	*mock.Call
+}
+
+// ReleaseWriter is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.ReleaseWriterRequest
+func (_e *MockIndexServiceServer_Expecter) ReleaseWriter(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_ReleaseWriter_Call {
+	return &MockIndexServiceServer_ReleaseWriter_Call{Call: _e.mock.On("ReleaseWriter", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_ReleaseWriter_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.ReleaseWriterRequest)) *MockIndexServiceServer_ReleaseWriter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.ReleaseWriterRequest))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ReleaseWriter_Call) Return(_a0 *metastorev1.ReleaseWriterResponse, _a1 error) *MockIndexServiceServer_ReleaseWriter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_ReleaseWriter_Call) RunAndReturn(run func(context.Context, *metastorev1.ReleaseWriterRequest) (*metastorev1.ReleaseWriterResponse, error)) *MockIndexServiceServer_ReleaseWriter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WatchBlocks provides a mock function with given fields: _a0, _a1
+func (_m *MockIndexServiceServer) WatchBlocks(_a0 *metastorev1.WatchBlocksRequest, _a1 metastorev1.IndexService_WatchBlocksServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchBlocks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*metastorev1.WatchBlocksRequest, metastorev1.IndexService_WatchBlocksServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIndexServiceServer_WatchBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchBlocks'
+type MockIndexServiceServer_WatchBlocks_Call struct {
+	*mock.Call
+}
+
+// WatchBlocks is a helper method to define mock.On call
+//   - _a0 *metastorev1.WatchBlocksRequest
+//   - _a1 metastorev1.IndexService_WatchBlocksServer
+func (_e *MockIndexServiceServer_Expecter) WatchBlocks(_a0 interface{}, _a1 interface{}) *MockIndexServiceServer_WatchBlocks_Call {
+	return &MockIndexServiceServer_WatchBlocks_Call{Call: _e.mock.On("WatchBlocks", _a0, _a1)}
+}
+
+func (_c *MockIndexServiceServer_WatchBlocks_Call) Run(run func(_a0 *metastorev1.WatchBlocksRequest, _a1 metastorev1.IndexService_WatchBlocksServer)) *MockIndexServiceServer_WatchBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*metastorev1.WatchBlocksRequest), args[1].(metastorev1.IndexService_WatchBlocksServer))
+	})
+	return _c
+}
+
+func (_c *MockIndexServiceServer_WatchBlocks_Call) Return(_a0 error) *MockIndexServiceServer_WatchBlocks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIndexServiceServer_WatchBlocks_Call) RunAndReturn(run func(*metastorev1.WatchBlocksRequest, metastorev1.IndexService_WatchBlocksServer) error) *MockIndexServiceServer_WatchBlocks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // mustEmbedUnimplementedIndexServiceServer provides a mock function with given fields:
 func (_m *MockIndexServiceServer) mustEmbedUnimplementedIndexServiceServer() {
 	_m.Called()
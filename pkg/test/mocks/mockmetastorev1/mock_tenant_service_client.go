@@ -173,6 +173,80 @@ func (_c *MockTenantServiceClient_GetTenant_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetTenantBlockStats provides a mock function with given fields: ctx, in, opts
+func (_m *MockTenantServiceClient) GetTenantBlockStats(ctx context.Context, in *metastorev1.GetTenantBlockStatsRequest, opts ...grpc.CallOption) (*metastorev1.GetTenantBlockStatsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTenantBlockStats")
+	}
+
+	var r0 *metastorev1.GetTenantBlockStatsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetTenantBlockStatsRequest, ...grpc.CallOption) (*metastorev1.GetTenantBlockStatsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetTenantBlockStatsRequest, ...grpc.CallOption) *metastorev1.GetTenantBlockStatsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.GetTenantBlockStatsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.GetTenantBlockStatsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTenantServiceClient_GetTenantBlockStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTenantBlockStats'
+type MockTenantServiceClient_GetTenantBlockStats_Call struct {
+	*mock.Call
+}
+
+// GetTenantBlockStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *metastorev1.GetTenantBlockStatsRequest
+//   - opts ...grpc.CallOption
+func (_e *MockTenantServiceClient_Expecter) GetTenantBlockStats(ctx interface{}, in interface{}, opts ...interface{}) *MockTenantServiceClient_GetTenantBlockStats_Call {
+	return &MockTenantServiceClient_GetTenantBlockStats_Call{Call: _e.mock.On("GetTenantBlockStats",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockTenantServiceClient_GetTenantBlockStats_Call) Run(run func(ctx context.Context, in *metastorev1.GetTenantBlockStatsRequest, opts ...grpc.CallOption)) *MockTenantServiceClient_GetTenantBlockStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*metastorev1.GetTenantBlockStatsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockTenantServiceClient_GetTenantBlockStats_Call) Return(_a0 *metastorev1.GetTenantBlockStatsResponse, _a1 error) *MockTenantServiceClient_GetTenantBlockStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTenantServiceClient_GetTenantBlockStats_Call) RunAndReturn(run func(context.Context, *metastorev1.GetTenantBlockStatsRequest, ...grpc.CallOption) (*metastorev1.GetTenantBlockStatsResponse, error)) *MockTenantServiceClient_GetTenantBlockStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockTenantServiceClient creates a new instance of MockTenantServiceClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockTenantServiceClient(t interface {
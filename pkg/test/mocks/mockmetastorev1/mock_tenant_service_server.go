@@ -140,6 +140,65 @@ func (_c *MockTenantServiceServer_GetTenant_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetTenantBlockStats provides a mock function with given fields: _a0, _a1
+func (_m *MockTenantServiceServer) GetTenantBlockStats(_a0 context.Context, _a1 *metastorev1.GetTenantBlockStatsRequest) (*metastorev1.GetTenantBlockStatsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTenantBlockStats")
+	}
+
+	var r0 *metastorev1.GetTenantBlockStatsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetTenantBlockStatsRequest) (*metastorev1.GetTenantBlockStatsResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metastorev1.GetTenantBlockStatsRequest) *metastorev1.GetTenantBlockStatsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metastorev1.GetTenantBlockStatsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metastorev1.GetTenantBlockStatsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTenantServiceServer_GetTenantBlockStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTenantBlockStats'
+type MockTenantServiceServer_GetTenantBlockStats_Call struct {
+	*mock.Call
+}
+
+// GetTenantBlockStats is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *metastorev1.GetTenantBlockStatsRequest
+func (_e *MockTenantServiceServer_Expecter) GetTenantBlockStats(_a0 interface{}, _a1 interface{}) *MockTenantServiceServer_GetTenantBlockStats_Call {
+	return &MockTenantServiceServer_GetTenantBlockStats_Call{Call: _e.mock.On("GetTenantBlockStats", _a0, _a1)}
+}
+
+func (_c *MockTenantServiceServer_GetTenantBlockStats_Call) Run(run func(_a0 context.Context, _a1 *metastorev1.GetTenantBlockStatsRequest)) *MockTenantServiceServer_GetTenantBlockStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*metastorev1.GetTenantBlockStatsRequest))
+	})
+	return _c
+}
+
+func (_c *MockTenantServiceServer_GetTenantBlockStats_Call) Return(_a0 *metastorev1.GetTenantBlockStatsResponse, _a1 error) *MockTenantServiceServer_GetTenantBlockStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTenantServiceServer_GetTenantBlockStats_Call) RunAndReturn(run func(context.Context, *metastorev1.GetTenantBlockStatsRequest) (*metastorev1.GetTenantBlockStatsResponse, error)) *MockTenantServiceServer_GetTenantBlockStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // mustEmbedUnimplementedTenantServiceServer provides a mock function with given fields:
 func (_m *MockTenantServiceServer) mustEmbedUnimplementedTenantServiceServer() {
 	_m.Called()
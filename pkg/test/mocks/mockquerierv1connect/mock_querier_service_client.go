@@ -147,6 +147,65 @@ func (_c *MockQuerierServiceClient_Diff_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// Explain provides a mock function with given fields: _a0, _a1
+func (_m *MockQuerierServiceClient) Explain(_a0 context.Context, _a1 *connect.Request[querierv1.ExplainRequest]) (*connect.Response[querierv1.ExplainResponse], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Explain")
+	}
+
+	var r0 *connect.Response[querierv1.ExplainResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.ExplainRequest]) (*connect.Response[querierv1.ExplainResponse], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.ExplainRequest]) *connect.Response[querierv1.ExplainResponse]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*connect.Response[querierv1.ExplainResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *connect.Request[querierv1.ExplainRequest]) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerierServiceClient_Explain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Explain'
+type MockQuerierServiceClient_Explain_Call struct {
+	*mock.Call
+}
+
+// Explain is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *connect.Request[querierv1.ExplainRequest]
+func (_e *MockQuerierServiceClient_Expecter) Explain(_a0 interface{}, _a1 interface{}) *MockQuerierServiceClient_Explain_Call {
+	return &MockQuerierServiceClient_Explain_Call{Call: _e.mock.On("Explain", _a0, _a1)}
+}
+
+func (_c *MockQuerierServiceClient_Explain_Call) Run(run func(_a0 context.Context, _a1 *connect.Request[querierv1.ExplainRequest])) *MockQuerierServiceClient_Explain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*connect.Request[querierv1.ExplainRequest]))
+	})
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_Explain_Call) Return(_a0 *connect.Response[querierv1.ExplainResponse], _a1 error) *MockQuerierServiceClient_Explain_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_Explain_Call) RunAndReturn(run func(context.Context, *connect.Request[querierv1.ExplainRequest]) (*connect.Response[querierv1.ExplainResponse], error)) *MockQuerierServiceClient_Explain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetProfileStats provides a mock function with given fields: _a0, _a1
 func (_m *MockQuerierServiceClient) GetProfileStats(_a0 context.Context, _a1 *connect.Request[typesv1.GetProfileStatsRequest]) (*connect.Response[typesv1.GetProfileStatsResponse], error) {
 	ret := _m.Called(_a0, _a1)
@@ -383,6 +442,65 @@ func (_c *MockQuerierServiceClient_ProfileTypes_Call) RunAndReturn(run func(cont
 	return _c
 }
 
+// SelectAllocationSizes provides a mock function with given fields: _a0, _a1
+func (_m *MockQuerierServiceClient) SelectAllocationSizes(_a0 context.Context, _a1 *connect.Request[querierv1.SelectAllocationSizesRequest]) (*connect.Response[querierv1.SelectAllocationSizesResponse], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SelectAllocationSizes")
+	}
+
+	var r0 *connect.Response[querierv1.SelectAllocationSizesResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.SelectAllocationSizesRequest]) (*connect.Response[querierv1.SelectAllocationSizesResponse], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.SelectAllocationSizesRequest]) *connect.Response[querierv1.SelectAllocationSizesResponse]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*connect.Response[querierv1.SelectAllocationSizesResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *connect.Request[querierv1.SelectAllocationSizesRequest]) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerierServiceClient_SelectAllocationSizes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectAllocationSizes'
+type MockQuerierServiceClient_SelectAllocationSizes_Call struct {
+	*mock.Call
+}
+
+// SelectAllocationSizes is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *connect.Request[querierv1.SelectAllocationSizesRequest]
+func (_e *MockQuerierServiceClient_Expecter) SelectAllocationSizes(_a0 interface{}, _a1 interface{}) *MockQuerierServiceClient_SelectAllocationSizes_Call {
+	return &MockQuerierServiceClient_SelectAllocationSizes_Call{Call: _e.mock.On("SelectAllocationSizes", _a0, _a1)}
+}
+
+func (_c *MockQuerierServiceClient_SelectAllocationSizes_Call) Run(run func(_a0 context.Context, _a1 *connect.Request[querierv1.SelectAllocationSizesRequest])) *MockQuerierServiceClient_SelectAllocationSizes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*connect.Request[querierv1.SelectAllocationSizesRequest]))
+	})
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_SelectAllocationSizes_Call) Return(_a0 *connect.Response[querierv1.SelectAllocationSizesResponse], _a1 error) *MockQuerierServiceClient_SelectAllocationSizes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_SelectAllocationSizes_Call) RunAndReturn(run func(context.Context, *connect.Request[querierv1.SelectAllocationSizesRequest]) (*connect.Response[querierv1.SelectAllocationSizesResponse], error)) *MockQuerierServiceClient_SelectAllocationSizes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SelectMergeProfile provides a mock function with given fields: _a0, _a1
 func (_m *MockQuerierServiceClient) SelectMergeProfile(_a0 context.Context, _a1 *connect.Request[querierv1.SelectMergeProfileRequest]) (*connect.Response[googlev1.Profile], error) {
 	ret := _m.Called(_a0, _a1)
@@ -560,6 +678,65 @@ func (_c *MockQuerierServiceClient_SelectMergeStacktraces_Call) RunAndReturn(run
 	return _c
 }
 
+// SelectProfileTypesSeries provides a mock function with given fields: _a0, _a1
+func (_m *MockQuerierServiceClient) SelectProfileTypesSeries(_a0 context.Context, _a1 *connect.Request[querierv1.SelectProfileTypesSeriesRequest]) (*connect.Response[querierv1.SelectProfileTypesSeriesResponse], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SelectProfileTypesSeries")
+	}
+
+	var r0 *connect.Response[querierv1.SelectProfileTypesSeriesResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.SelectProfileTypesSeriesRequest]) (*connect.Response[querierv1.SelectProfileTypesSeriesResponse], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.SelectProfileTypesSeriesRequest]) *connect.Response[querierv1.SelectProfileTypesSeriesResponse]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*connect.Response[querierv1.SelectProfileTypesSeriesResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *connect.Request[querierv1.SelectProfileTypesSeriesRequest]) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerierServiceClient_SelectProfileTypesSeries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectProfileTypesSeries'
+type MockQuerierServiceClient_SelectProfileTypesSeries_Call struct {
+	*mock.Call
+}
+
+// SelectProfileTypesSeries is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *connect.Request[querierv1.SelectProfileTypesSeriesRequest]
+func (_e *MockQuerierServiceClient_Expecter) SelectProfileTypesSeries(_a0 interface{}, _a1 interface{}) *MockQuerierServiceClient_SelectProfileTypesSeries_Call {
+	return &MockQuerierServiceClient_SelectProfileTypesSeries_Call{Call: _e.mock.On("SelectProfileTypesSeries", _a0, _a1)}
+}
+
+func (_c *MockQuerierServiceClient_SelectProfileTypesSeries_Call) Run(run func(_a0 context.Context, _a1 *connect.Request[querierv1.SelectProfileTypesSeriesRequest])) *MockQuerierServiceClient_SelectProfileTypesSeries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*connect.Request[querierv1.SelectProfileTypesSeriesRequest]))
+	})
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_SelectProfileTypesSeries_Call) Return(_a0 *connect.Response[querierv1.SelectProfileTypesSeriesResponse], _a1 error) *MockQuerierServiceClient_SelectProfileTypesSeries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_SelectProfileTypesSeries_Call) RunAndReturn(run func(context.Context, *connect.Request[querierv1.SelectProfileTypesSeriesRequest]) (*connect.Response[querierv1.SelectProfileTypesSeriesResponse], error)) *MockQuerierServiceClient_SelectProfileTypesSeries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SelectSeries provides a mock function with given fields: _a0, _a1
 func (_m *MockQuerierServiceClient) SelectSeries(_a0 context.Context, _a1 *connect.Request[querierv1.SelectSeriesRequest]) (*connect.Response[querierv1.SelectSeriesResponse], error) {
 	ret := _m.Called(_a0, _a1)
@@ -619,6 +796,65 @@ func (_c *MockQuerierServiceClient_SelectSeries_Call) RunAndReturn(run func(cont
 	return _c
 }
 
+// SelectServiceGraph provides a mock function with given fields: _a0, _a1
+func (_m *MockQuerierServiceClient) SelectServiceGraph(_a0 context.Context, _a1 *connect.Request[querierv1.SelectServiceGraphRequest]) (*connect.Response[querierv1.SelectServiceGraphResponse], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SelectServiceGraph")
+	}
+
+	var r0 *connect.Response[querierv1.SelectServiceGraphResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.SelectServiceGraphRequest]) (*connect.Response[querierv1.SelectServiceGraphResponse], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.SelectServiceGraphRequest]) *connect.Response[querierv1.SelectServiceGraphResponse]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*connect.Response[querierv1.SelectServiceGraphResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *connect.Request[querierv1.SelectServiceGraphRequest]) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerierServiceClient_SelectServiceGraph_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectServiceGraph'
+type MockQuerierServiceClient_SelectServiceGraph_Call struct {
+	*mock.Call
+}
+
+// SelectServiceGraph is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *connect.Request[querierv1.SelectServiceGraphRequest]
+func (_e *MockQuerierServiceClient_Expecter) SelectServiceGraph(_a0 interface{}, _a1 interface{}) *MockQuerierServiceClient_SelectServiceGraph_Call {
+	return &MockQuerierServiceClient_SelectServiceGraph_Call{Call: _e.mock.On("SelectServiceGraph", _a0, _a1)}
+}
+
+func (_c *MockQuerierServiceClient_SelectServiceGraph_Call) Run(run func(_a0 context.Context, _a1 *connect.Request[querierv1.SelectServiceGraphRequest])) *MockQuerierServiceClient_SelectServiceGraph_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*connect.Request[querierv1.SelectServiceGraphRequest]))
+	})
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_SelectServiceGraph_Call) Return(_a0 *connect.Response[querierv1.SelectServiceGraphResponse], _a1 error) *MockQuerierServiceClient_SelectServiceGraph_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_SelectServiceGraph_Call) RunAndReturn(run func(context.Context, *connect.Request[querierv1.SelectServiceGraphRequest]) (*connect.Response[querierv1.SelectServiceGraphResponse], error)) *MockQuerierServiceClient_SelectServiceGraph_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Series provides a mock function with given fields: _a0, _a1
 func (_m *MockQuerierServiceClient) Series(_a0 context.Context, _a1 *connect.Request[querierv1.SeriesRequest]) (*connect.Response[querierv1.SeriesResponse], error) {
 	ret := _m.Called(_a0, _a1)
@@ -678,6 +914,65 @@ func (_c *MockQuerierServiceClient_Series_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// ServiceHierarchy provides a mock function with given fields: _a0, _a1
+func (_m *MockQuerierServiceClient) ServiceHierarchy(_a0 context.Context, _a1 *connect.Request[querierv1.ServiceHierarchyRequest]) (*connect.Response[querierv1.ServiceHierarchyResponse], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServiceHierarchy")
+	}
+
+	var r0 *connect.Response[querierv1.ServiceHierarchyResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.ServiceHierarchyRequest]) (*connect.Response[querierv1.ServiceHierarchyResponse], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *connect.Request[querierv1.ServiceHierarchyRequest]) *connect.Response[querierv1.ServiceHierarchyResponse]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*connect.Response[querierv1.ServiceHierarchyResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *connect.Request[querierv1.ServiceHierarchyRequest]) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerierServiceClient_ServiceHierarchy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServiceHierarchy'
+type MockQuerierServiceClient_ServiceHierarchy_Call struct {
+	*mock.Call
+}
+
+// ServiceHierarchy is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *connect.Request[querierv1.ServiceHierarchyRequest]
+func (_e *MockQuerierServiceClient_Expecter) ServiceHierarchy(_a0 interface{}, _a1 interface{}) *MockQuerierServiceClient_ServiceHierarchy_Call {
+	return &MockQuerierServiceClient_ServiceHierarchy_Call{Call: _e.mock.On("ServiceHierarchy", _a0, _a1)}
+}
+
+func (_c *MockQuerierServiceClient_ServiceHierarchy_Call) Run(run func(_a0 context.Context, _a1 *connect.Request[querierv1.ServiceHierarchyRequest])) *MockQuerierServiceClient_ServiceHierarchy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*connect.Request[querierv1.ServiceHierarchyRequest]))
+	})
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_ServiceHierarchy_Call) Return(_a0 *connect.Response[querierv1.ServiceHierarchyResponse], _a1 error) *MockQuerierServiceClient_ServiceHierarchy_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerierServiceClient_ServiceHierarchy_Call) RunAndReturn(run func(context.Context, *connect.Request[querierv1.ServiceHierarchyRequest]) (*connect.Response[querierv1.ServiceHierarchyResponse], error)) *MockQuerierServiceClient_ServiceHierarchy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockQuerierServiceClient creates a new instance of MockQuerierServiceClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockQuerierServiceClient(t interface {
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/middleware"
+)
+
+const serverTimingHeader = "Server-Timing"
+
+type serverTimingContextKey struct{}
+
+// ServerTiming collects the durations of the request processing stages.
+// The durations are reported to the client in the Server-Timing response
+// header, and can be inspected in the browser developer tools. All the
+// methods are safe to call on a nil ServerTiming.
+type ServerTiming struct {
+	mu      sync.Mutex
+	entries []serverTimingEntry
+}
+
+type serverTimingEntry struct {
+	name     string
+	duration time.Duration
+	// Non-zero for the stages that are not finished yet.
+	started time.Time
+}
+
+// ServerTimingFromContext returns the ServerTiming of the request,
+// or nil, if the request is not handled by the ServerTimingMiddleware.
+func ServerTimingFromContext(ctx context.Context) *ServerTiming {
+	t, _ := ctx.Value(serverTimingContextKey{}).(*ServerTiming)
+	return t
+}
+
+// Add adds the duration to the stage.
+func (t *ServerTiming) Add(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(name)
+	e.duration += d
+}
+
+// Start starts the stage that lasts until the response headers are
+// written. If the stage has been already started, it is restarted.
+func (t *ServerTiming) Start(name string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(name)
+	e.started = time.Now()
+}
+
+func (t *ServerTiming) entry(name string) *serverTimingEntry {
+	for i := range t.entries {
+		if t.entries[i].name == name {
+			return &t.entries[i]
+		}
+	}
+	t.entries = append(t.entries, serverTimingEntry{name: name})
+	return &t.entries[len(t.entries)-1]
+}
+
+func (t *ServerTiming) header(now time.Time) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var b strings.Builder
+	for _, e := range t.entries {
+		d := e.duration
+		if !e.started.IsZero() {
+			d += now.Sub(e.started)
+		}
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(e.name)
+		b.WriteString(";dur=")
+		b.WriteString(strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64))
+	}
+	return b.String()
+}
+
+// ServerTimingMiddleware provides the request handlers with ServerTiming,
+// and adds the Server-Timing header to the response, if any durations have
+// been recorded by the time the response headers are written.
+func ServerTimingMiddleware() middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t := new(ServerTiming)
+			ctx := context.WithValue(r.Context(), serverTimingContextKey{}, t)
+			next.ServeHTTP(&serverTimingWriter{ResponseWriter: w, timing: t}, r.WithContext(ctx))
+		})
+	})
+}
+
+type serverTimingWriter struct {
+	http.ResponseWriter
+	timing      *ServerTiming
+	wroteHeader bool
+}
+
+func (w *serverTimingWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if h := w.timing.header(time.Now()); h != "" {
+			w.Header().Set(serverTimingHeader, h)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *serverTimingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *serverTimingWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *serverTimingWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTimingMiddleware(t *testing.T) {
+	handler := ServerTimingMiddleware().Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timing := ServerTimingFromContext(r.Context())
+		require.NotNil(t, timing)
+		timing.Add("metastore-resolve", 10*time.Millisecond)
+		timing.Add("bucket-fetch", time.Millisecond)
+		timing.Add("metastore-resolve", 5*time.Millisecond)
+		timing.Start("render")
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Regexp(t,
+		regexp.MustCompile(`^metastore-resolve;dur=15\.000, bucket-fetch;dur=1\.000, render;dur=\d+\.\d{3}$`),
+		rec.Header().Get("Server-Timing"))
+}
+
+func TestServerTimingMiddleware_NoTimings(t *testing.T) {
+	handler := ServerTimingMiddleware().Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Values("Server-Timing"))
+}
+
+func TestServerTiming_Nil(t *testing.T) {
+	var timing *ServerTiming
+	timing.Add("merge", time.Second)
+	timing.Start("render")
+	assert.Nil(t, ServerTimingFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
@@ -0,0 +1,119 @@
+// Package hyperloglog implements the HyperLogLog cardinality estimator.
+//
+// The sketch is built from 64-bit hashes of the items, therefore the
+// large range correction of the original algorithm is not needed.
+// See "HyperLogLog: the analysis of a near-optimal cardinality
+// estimation algorithm" by Flajolet et al.
+package hyperloglog
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	MinPrecision = 4
+	MaxPrecision = 16
+)
+
+// Sketch is a HyperLogLog sketch. The memory footprint of the sketch is
+// 2^precision bytes, and the relative error of the estimate is about
+// 1.04/sqrt(2^precision). The sketch is not safe for concurrent use.
+type Sketch struct {
+	p         uint8
+	registers []uint8
+}
+
+// New creates a new sketch with the given precision, which
+// is clamped to the [MinPrecision, MaxPrecision] range.
+func New(precision uint8) *Sketch {
+	precision = min(max(precision, MinPrecision), MaxPrecision)
+	return &Sketch{
+		p:         precision,
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+func (s *Sketch) position(h uint64) (int, uint8) {
+	i := h >> (64 - s.p)
+	// The lowest bit is set to bound the rank by 64-p+1.
+	w := h<<s.p | 1<<(s.p-1)
+	return int(i), uint8(bits.LeadingZeros64(w)) + 1
+}
+
+// Insert adds the item hash to the sketch. It returns true if the sketch
+// has been changed, which means that the item has not been added before.
+// The opposite is not true: the item may be new even if the sketch does
+// not change.
+func (s *Sketch) Insert(h uint64) bool {
+	i, r := s.position(h)
+	if s.registers[i] >= r {
+		return false
+	}
+	s.registers[i] = r
+	return true
+}
+
+// Covers reports whether adding the item hash would not change the sketch,
+// and therefore, the item may have already been added.
+func (s *Sketch) Covers(h uint64) bool {
+	i, r := s.position(h)
+	return s.registers[i] >= r
+}
+
+// Reset removes all the items from the sketch.
+func (s *Sketch) Reset() { clear(s.registers) }
+
+// Empty reports whether no items have been added to the sketch.
+func (s *Sketch) Empty() bool {
+	for _, r := range s.registers {
+		if r != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Estimate returns the estimated number of distinct items in the sketch.
+func (s *Sketch) Estimate() uint64 { return Estimate(s) }
+
+// Estimate returns the estimated number of distinct items in the union
+// of the sketches, which must have the same precision.
+func Estimate(sketches ...*Sketch) uint64 {
+	if len(sketches) == 0 {
+		return 0
+	}
+	m := len(sketches[0].registers)
+	var sum float64
+	var zeros int
+	for i := 0; i < m; i++ {
+		var r uint8
+		for _, s := range sketches {
+			r = max(r, s.registers[i])
+		}
+		if r == 0 {
+			zeros++
+		}
+		sum += 1 / float64(uint64(1)<<r)
+	}
+	fm := float64(m)
+	e := alpha(m) * fm * fm / sum
+	if e <= 2.5*fm && zeros > 0 {
+		// Small range correction: linear counting.
+		e = fm * math.Log(fm/float64(zeros))
+	}
+	return uint64(e + 0.5)
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
@@ -0,0 +1,47 @@
+package hyperloglog
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func hash(i int) uint64 { return xxhash.Sum64String(strconv.Itoa(i)) }
+
+func Test_Estimate(t *testing.T) {
+	for _, n := range []int{0, 10, 100, 1000, 10000, 100000} {
+		s := New(11)
+		for i := 0; i < n; i++ {
+			s.Insert(hash(i))
+			// Duplicates do not affect the estimate.
+			s.Insert(hash(i))
+		}
+		assert.InDelta(t, n, s.Estimate(), float64(n)*0.05+1, "n=%d", n)
+	}
+}
+
+func Test_Estimate_Union(t *testing.T) {
+	a, b := New(11), New(11)
+	for i := 0; i < 2000; i++ {
+		a.Insert(hash(i))
+	}
+	for i := 1000; i < 3000; i++ {
+		b.Insert(hash(i))
+	}
+	assert.InDelta(t, 3000, Estimate(a, b), 3000*0.05)
+}
+
+func Test_Insert_Covers(t *testing.T) {
+	s := New(8)
+	assert.True(t, s.Empty())
+	assert.False(t, s.Covers(hash(1)))
+	assert.True(t, s.Insert(hash(1)))
+	assert.True(t, s.Covers(hash(1)))
+	assert.False(t, s.Insert(hash(1)))
+	assert.False(t, s.Empty())
+	s.Reset()
+	assert.True(t, s.Empty())
+	assert.Equal(t, uint64(0), s.Estimate())
+}
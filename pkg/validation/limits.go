@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/grafana/dskit/flagext"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/pyroscope/pkg/distributor/forwarder"
 	writepath "github.com/grafana/pyroscope/pkg/distributor/write_path"
 	"github.com/grafana/pyroscope/pkg/experiment/distributor/placement/adaptive_placement"
 	readpath "github.com/grafana/pyroscope/pkg/frontend/read_path"
 	"github.com/grafana/pyroscope/pkg/phlaredb/block"
+	"github.com/grafana/pyroscope/pkg/share"
 )
 
 const (
@@ -30,16 +33,25 @@ const (
 // to support tenant-friendly duration format (e.g: "1h30m45s") in JSON value.
 type Limits struct {
 	// Distributor enforced limits.
-	IngestionRateMB        float64 `yaml:"ingestion_rate_mb" json:"ingestion_rate_mb"`
-	IngestionBurstSizeMB   float64 `yaml:"ingestion_burst_size_mb" json:"ingestion_burst_size_mb"`
-	MaxLabelNameLength     int     `yaml:"max_label_name_length" json:"max_label_name_length"`
-	MaxLabelValueLength    int     `yaml:"max_label_value_length" json:"max_label_value_length"`
-	MaxLabelNamesPerSeries int     `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
-	MaxSessionsPerSeries   int     `yaml:"max_sessions_per_series" json:"max_sessions_per_series"`
-	EnforceLabelsOrder     bool    `yaml:"enforce_labels_order" json:"enforce_labels_order"`
+	IngestionRateMB      float64 `yaml:"ingestion_rate_mb" json:"ingestion_rate_mb"`
+	IngestionBurstSizeMB float64 `yaml:"ingestion_burst_size_mb" json:"ingestion_burst_size_mb"`
+	// Per profile type ingestion rate limits, keyed by the profile name.
+	IngestionProfileTypeRateLimits ProfileTypeRateLimits `yaml:"ingestion_profile_type_rate_limits" json:"ingestion_profile_type_rate_limits" category:"advanced" doc:"nocli|description=Per-tenant ingestion rate limits of individual profile types, keyed by the profile name (e.g., memory or process_cpu). The limits apply in addition to the ingestion rate limit, and are shared across distributors the same way."`
+	MaxLabelNameLength             int                   `yaml:"max_label_name_length" json:"max_label_name_length"`
+	MaxLabelValueLength            int                   `yaml:"max_label_value_length" json:"max_label_value_length"`
+	MaxLabelNamesPerSeries         int                   `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
+	MaxSessionsPerSeries           int                   `yaml:"max_sessions_per_series" json:"max_sessions_per_series"`
+	MaxLabelValueCardinality       int                   `yaml:"max_label_value_cardinality" json:"max_label_value_cardinality"`
+	LabelCardinalityPlaceholder    string                `yaml:"label_cardinality_placeholder" json:"label_cardinality_placeholder" category:"advanced"`
+	MaxActiveSeriesPerTenant       int                   `yaml:"max_active_series_per_tenant" json:"max_active_series_per_tenant"`
+	EnforceLabelsOrder             bool                  `yaml:"enforce_labels_order" json:"enforce_labels_order"`
+
+	ReservedLabelNames flagext.StringSliceCSV `yaml:"reserved_label_names" json:"reserved_label_names" category:"advanced"`
 
 	MaxProfileSizeBytes              int `yaml:"max_profile_size_bytes" json:"max_profile_size_bytes"`
 	MaxProfileStacktraceSamples      int `yaml:"max_profile_stacktrace_samples" json:"max_profile_stacktrace_samples"`
+	MaxProfileLocations              int `yaml:"max_profile_locations" json:"max_profile_locations"`
+	MaxProfileStringTableBytes       int `yaml:"max_profile_string_table_bytes" json:"max_profile_string_table_bytes"`
 	MaxProfileStacktraceSampleLabels int `yaml:"max_profile_stacktrace_sample_labels" json:"max_profile_stacktrace_sample_labels"`
 	MaxProfileStacktraceDepth        int `yaml:"max_profile_stacktrace_depth" json:"max_profile_stacktrace_depth"`
 	MaxProfileSymbolValueLength      int `yaml:"max_profile_symbol_value_length" json:"max_profile_symbol_value_length"`
@@ -55,6 +67,14 @@ type Limits struct {
 	IngestionRelabelingRules                RelabelRules         `yaml:"ingestion_relabeling_rules" json:"ingestion_relabeling_rules" category:"advanced"`
 	IngestionRelabelingDefaultRulesPosition RelabelRulesPosition `yaml:"ingestion_relabeling_default_rules_position" json:"ingestion_relabeling_default_rules_position" category:"advanced"`
 
+	// Forwarding of the profiles accepted by the distributor.
+	ForwardingTargets forwarder.Targets `yaml:"forwarding_targets" json:"forwarding_targets" category:"experimental" doc:"nocli|description=Pyroscope instances the profiles of the tenant are forwarded to, once they have been accepted by the distributor. The profiles are forwarded asynchronously, on a best-effort basis. The targets may be configured with tenant_id, basic_auth_username and basic_auth_password, or bearer_token, and relabel_configs applied to the series forwarded."`
+
+	// Sharing of the profiles with external services.
+	ShareFlamegraphComEnabled bool          `yaml:"share_flamegraph_com_enabled" json:"share_flamegraph_com_enabled" category:"experimental"`
+	ShareTargets              share.Targets `yaml:"share_targets" json:"share_targets" category:"experimental" doc:"nocli|description=Self-hosted services the profiles of the tenant can be shared with, in addition to flamegraph.com. Targets of the pprof type are posted the profile in the pprof format, and respond with the URL of the profile. Targets of the s3 type upload the profile to the bucket, and share a presigned URL."`
+	ProfileSummaryEnabled     bool          `yaml:"profile_summary_enabled" json:"profile_summary_enabled" category:"experimental"`
+
 	// The tenant shard size determines the how many ingesters a particular
 	// tenant will be sharded to. Needs to be specified on distributors for
 	// correct distribution and on ingesters so that the local ingestion limit
@@ -111,8 +131,30 @@ type Limits struct {
 	// Distributors use these limits to determine how many shards to allocate
 	// to a tenant dataset by default, if no placement rules defined.
 	AdaptivePlacementLimits adaptive_placement.PlacementLimits `yaml:",inline" json:",inline"`
+
+	// Duration of the metastore index partitions of the tenant blocks.
+	// If not set, the metastore partition duration applies.
+	MetastoreIndexPartitionDuration model.Duration `yaml:"metastore_index_partition_duration" json:"metastore_index_partition_duration" doc:"hidden"`
+	// How long the metastore keeps the index partitions of the tenant blocks.
+	// Expired partitions are deleted along with the block objects.
+	// If not set, the partitions are kept indefinitely.
+	MetastoreIndexRetentionPeriod model.Duration `yaml:"metastore_index_retention_period" json:"metastore_index_retention_period" doc:"hidden"`
 }
 
+// ProfileTypeRateLimit is the ingestion rate limit of a profile type.
+type ProfileTypeRateLimit struct {
+	IngestionRateMB      float64 `yaml:"ingestion_rate_mb" json:"ingestion_rate_mb"`
+	IngestionBurstSizeMB float64 `yaml:"ingestion_burst_size_mb" json:"ingestion_burst_size_mb"`
+}
+
+func (l ProfileTypeRateLimit) IngestionRateBytes() float64 { return l.IngestionRateMB * bytesInMB }
+
+func (l ProfileTypeRateLimit) IngestionBurstSizeBytes() int {
+	return int(l.IngestionBurstSizeMB * bytesInMB)
+}
+
+type ProfileTypeRateLimits map[string]ProfileTypeRateLimit
+
 // LimitError are errors that do not comply with the limits specified.
 type LimitError string
 
@@ -131,7 +173,12 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxLabelValueLength, "validation.max-length-label-value", 2048, "Maximum length accepted for label value. This setting also applies to the metric name.")
 	f.IntVar(&l.MaxLabelNamesPerSeries, "validation.max-label-names-per-series", 30, "Maximum number of label names per series.")
 	f.IntVar(&l.MaxSessionsPerSeries, "validation.max-sessions-per-series", 0, "Maximum number of sessions per series. 0 to disable.")
+	f.IntVar(&l.MaxLabelValueCardinality, "validation.max-label-value-cardinality", 0, "Maximum number of distinct values of a label per tenant. The number is estimated by each distributor independently, over the values seen in the last 5 to 10 minutes. Series with new values of a label that reached the limit are discarded. Labels with names starting with '__' are not limited. 0 to disable.")
+	f.StringVar(&l.LabelCardinalityPlaceholder, "validation.label-cardinality-placeholder", "", "If set, new values of a label that reached the max label value cardinality limit are replaced with the placeholder, instead of discarding the series.")
+	f.IntVar(&l.MaxActiveSeriesPerTenant, "validation.max-active-series-per-tenant", 0, "Maximum number of active series of profiles per tenant on the segment-writer write path, where the ingester series limits do not apply. The number is estimated by each distributor independently, over the series seen in the last 5 to 10 minutes. Profiles of new series that exceed the limit are discarded. 0 to disable.")
 	f.BoolVar(&l.EnforceLabelsOrder, "validation.enforce-labels-order", false, "Enforce labels order optimization.")
+	l.ReservedLabelNames = defaultReservedLabelNames()
+	f.Var(&l.ReservedLabelNames, "validation.reserved-label-names", "Comma-separated list of label names reserved for the labels set by Pyroscope. Profiles with any of these labels are rejected.")
 
 	f.IntVar(&l.MaxLocalSeriesPerTenant, "ingester.max-local-series-per-tenant", 0, "Maximum number of active series of profiles per tenant, per ingester. 0 to disable.")
 	f.IntVar(&l.MaxGlobalSeriesPerTenant, "ingester.max-global-series-per-tenant", 5000, "Maximum number of active series of profiles per tenant, across the cluster. 0 to disable. When the global limit is enabled, each ingester is configured with a dynamic local limit based on the replication factor and the current number of healthy ingesters, and is kept updated whenever the number of ingesters change.")
@@ -150,12 +197,17 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxQueryParallelism, "querier.max-query-parallelism", 0, "Maximum number of queries that will be scheduled in parallel by the frontend.")
 
 	f.BoolVar(&l.QueryAnalysisEnabled, "querier.query-analysis-enabled", true, "Whether query analysis is enabled in the query frontend. If disabled, the /AnalyzeQuery endpoint will return an empty response.")
+	f.BoolVar(&l.ShareFlamegraphComEnabled, "share.flamegraph-com-enabled", true, "Whether the profiles can be shared publicly with flamegraph.com.")
+	f.BoolVar(&l.ProfileSummaryEnabled, "querier.profile-summary-enabled", false, "Whether the profiles of the tenant can be summarized with the language model configured in the profile summary settings. The profile summary sends the top functions and call paths of the profiles to the configured API.")
+
 	f.BoolVar(&l.QueryAnalysisSeriesEnabled, "querier.query-analysis-series-enabled", false, "Whether the series portion of query analysis is enabled. If disabled, no series data (e.g., series count) will be calculated by the /AnalyzeQuery endpoint.")
 
 	f.IntVar(&l.MaxProfileSizeBytes, "validation.max-profile-size-bytes", 4*1024*1024, "Maximum size of a profile in bytes. This is based off the uncompressed size. 0 to disable.")
 	f.IntVar(&l.MaxProfileStacktraceSamples, "validation.max-profile-stacktrace-samples", 16000, "Maximum number of samples in a profile. 0 to disable.")
+	f.IntVar(&l.MaxProfileLocations, "validation.max-profile-locations", 0, "Maximum number of locations in a profile. 0 to disable.")
+	f.IntVar(&l.MaxProfileStringTableBytes, "validation.max-profile-string-table-bytes", 0, "Maximum total size of the profile string table in bytes, before symbol values are truncated. 0 to disable.")
 	f.IntVar(&l.MaxProfileStacktraceSampleLabels, "validation.max-profile-stacktrace-sample-labels", 100, "Maximum number of labels in a profile sample. 0 to disable.")
-	f.IntVar(&l.MaxProfileStacktraceDepth, "validation.max-profile-stacktrace-depth", 1000, "Maximum depth of a profile stacktrace. Profiles are not rejected instead stacktraces are truncated. 0 to disable.")
+	f.IntVar(&l.MaxProfileStacktraceDepth, "validation.max-profile-stacktrace-depth", 1000, "Maximum depth of a profile stacktrace. Profiles are not rejected instead stacktraces are truncated: the frames beyond the limit are collapsed into a synthetic '[truncated]' frame. 0 to disable.")
 	f.IntVar(&l.MaxProfileSymbolValueLength, "validation.max-profile-symbol-value-length", 65535, "Maximum length of a profile symbol value (labels, function names and filenames, etc...). Profiles are not rejected instead symbol values are truncated. 0 to disable.")
 
 	f.IntVar(&l.MaxFlameGraphNodesDefault, "querier.max-flamegraph-nodes-default", 8<<10, "Maximum number of flame graph nodes by default. 0 to disable.")
@@ -208,12 +260,37 @@ func (l *Limits) UnmarshalYAML(unmarshal func(interface{}) error) error {
 // Validate validates that this limits config is valid.
 func (l *Limits) Validate() error {
 
+	for profileType, limit := range l.IngestionProfileTypeRateLimits {
+		if limit.IngestionRateMB < 0 || limit.IngestionBurstSizeMB < 0 {
+			return fmt.Errorf("invalid ingestion rate limit of profile type %q: rate and burst size must not be negative", profileType)
+		}
+	}
+
+	if err := forwarder.ValidateTargets(l.ForwardingTargets); err != nil {
+		return err
+	}
+
+	if err := share.ValidateTargets(l.ShareTargets); err != nil {
+		return err
+	}
+
 	if l.IngestionRelabelingDefaultRulesPosition != "" {
 		if err := l.IngestionRelabelingDefaultRulesPosition.Set(string(l.IngestionRelabelingDefaultRulesPosition)); err != nil {
 			return err
 		}
 	}
 
+	// Partitions are aligned to the day: the duration must be a whole
+	// number of hours that a day can be divided into.
+	if d := time.Duration(l.MetastoreIndexPartitionDuration); d != 0 {
+		if d < 0 || d%time.Hour != 0 || (24*time.Hour)%d != 0 {
+			return fmt.Errorf("invalid metastore index partition duration %s: must be a whole number of hours that divides a day", d)
+		}
+	}
+	if l.MetastoreIndexRetentionPeriod < 0 {
+		return fmt.Errorf("invalid metastore index retention period %s: must not be negative", l.MetastoreIndexRetentionPeriod)
+	}
+
 	return nil
 }
 
@@ -270,6 +347,11 @@ func (o *Overrides) IngestionBurstSizeBytes(tenantID string) int {
 	return int(o.getOverridesForTenant(tenantID).IngestionBurstSizeMB * bytesInMB)
 }
 
+// IngestionProfileTypeRateLimits returns the ingestion rate limits of profile types.
+func (o *Overrides) IngestionProfileTypeRateLimits(tenantID string) ProfileTypeRateLimits {
+	return o.getOverridesForTenant(tenantID).IngestionProfileTypeRateLimits
+}
+
 // IngestionTenantShardSize returns the ingesters shard size for a given user.
 func (o *Overrides) IngestionTenantShardSize(tenantID string) int {
 	return o.getOverridesForTenant(tenantID).IngestionTenantShardSize
@@ -301,6 +383,16 @@ func (o *Overrides) MaxProfileStacktraceSamples(tenantID string) int {
 	return o.getOverridesForTenant(tenantID).MaxProfileStacktraceSamples
 }
 
+// MaxProfileLocations returns the maximum number of locations in a profile.
+func (o *Overrides) MaxProfileLocations(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxProfileLocations
+}
+
+// MaxProfileStringTableBytes returns the maximum size of the profile string table in bytes.
+func (o *Overrides) MaxProfileStringTableBytes(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxProfileStringTableBytes
+}
+
 // MaxProfileStacktraceSampleLabels returns the maximum number of labels in a profile sample.
 func (o *Overrides) MaxProfileStacktraceSampleLabels(tenantID string) int {
 	return o.getOverridesForTenant(tenantID).MaxProfileStacktraceSampleLabels
@@ -321,6 +413,22 @@ func (o *Overrides) MaxSessionsPerSeries(tenantID string) int {
 	return o.getOverridesForTenant(tenantID).MaxSessionsPerSeries
 }
 
+// MaxLabelValueCardinality returns the maximum number of distinct values of a label.
+func (o *Overrides) MaxLabelValueCardinality(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxLabelValueCardinality
+}
+
+// LabelCardinalityPlaceholder returns the value that replaces the values
+// of labels that reached the cardinality limit.
+func (o *Overrides) LabelCardinalityPlaceholder(tenantID string) string {
+	return o.getOverridesForTenant(tenantID).LabelCardinalityPlaceholder
+}
+
+// ReservedLabelNames returns the label names that must not be set by clients.
+func (o *Overrides) ReservedLabelNames(tenantID string) []string {
+	return o.getOverridesForTenant(tenantID).ReservedLabelNames
+}
+
 func (o *Overrides) EnforceLabelsOrder(tenantID string) bool {
 	return o.getOverridesForTenant(tenantID).EnforceLabelsOrder
 }
@@ -333,6 +441,12 @@ func (o *Overrides) DistributorAggregationPeriod(tenantID string) model.Duration
 	return o.getOverridesForTenant(tenantID).DistributorAggregationPeriod
 }
 
+// MaxActiveSeriesPerTenant returns the maximum number of active series
+// of a tenant on the segment-writer write path.
+func (o *Overrides) MaxActiveSeriesPerTenant(tenantID string) int {
+	return o.getOverridesForTenant(tenantID).MaxActiveSeriesPerTenant
+}
+
 // MaxLocalSeriesPerTenant returns the maximum number of series a tenant is allowed to store
 // in a single ingester.
 func (o *Overrides) MaxLocalSeriesPerTenant(tenantID string) int {
@@ -466,6 +580,22 @@ func (o *Overrides) QueryAnalysisSeriesEnabled(tenantID string) bool {
 	return o.getOverridesForTenant(tenantID).QueryAnalysisSeriesEnabled
 }
 
+func (o *Overrides) ForwardingTargets(tenantID string) []forwarder.Target {
+	return o.getOverridesForTenant(tenantID).ForwardingTargets
+}
+
+func (o *Overrides) ShareFlamegraphComEnabled(tenantID string) bool {
+	return o.getOverridesForTenant(tenantID).ShareFlamegraphComEnabled
+}
+
+func (o *Overrides) ShareTargets(tenantID string) []share.Target {
+	return o.getOverridesForTenant(tenantID).ShareTargets
+}
+
+func (o *Overrides) ProfileSummaryEnabled(tenantID string) bool {
+	return o.getOverridesForTenant(tenantID).ProfileSummaryEnabled
+}
+
 func (o *Overrides) WritePathOverrides(tenantID string) writepath.Config {
 	return o.getOverridesForTenant(tenantID).WritePathOverrides
 }
@@ -478,6 +608,14 @@ func (o *Overrides) PlacementLimits(tenantID string) adaptive_placement.Placemen
 	return o.getOverridesForTenant(tenantID).AdaptivePlacementLimits
 }
 
+func (o *Overrides) MetastoreIndexPartitionDuration(tenantID string) time.Duration {
+	return time.Duration(o.getOverridesForTenant(tenantID).MetastoreIndexPartitionDuration)
+}
+
+func (o *Overrides) MetastoreIndexRetentionPeriod(tenantID string) time.Duration {
+	return time.Duration(o.getOverridesForTenant(tenantID).MetastoreIndexRetentionPeriod)
+}
+
 func (o *Overrides) DefaultLimits() *Limits {
 	return o.defaultLimits
 }
@@ -7,14 +7,22 @@ import (
 	"github.com/go-kit/log/level"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/pyroscope/pkg/experiment/metastore/index"
 	"github.com/grafana/pyroscope/pkg/util"
 )
 
 type RuntimeConfigValues struct {
 	TenantLimits map[string]*Limits `yaml:"overrides"`
+
+	// Metastore index settings applied without a restart.
+	MetastoreIndex index.RuntimeConfig `yaml:"metastore_index"`
 }
 
 func (r RuntimeConfigValues) validate() error {
+	if err := r.MetastoreIndex.Validate(); err != nil {
+		return fmt.Errorf("invalid metastore index config: %w", err)
+	}
+
 	for t, c := range r.TenantLimits {
 		if c == nil {
 			level.Warn(util.Logger).Log("msg", "skipping empty tenant limit definition", "tenant", t)
@@ -2,6 +2,8 @@ package validation
 
 import (
 	"time"
+
+	readpath "github.com/grafana/pyroscope/pkg/frontend/read_path"
 )
 
 type MockLimits struct {
@@ -26,11 +28,15 @@ type MockLimits struct {
 
 	MaxProfileSizeBytesValue              int
 	MaxProfileStacktraceSamplesValue      int
+	MaxProfileLocationsValue              int
+	MaxProfileStringTableBytesValue       int
 	MaxProfileStacktraceDepthValue        int
 	MaxProfileStacktraceSampleLabelsValue int
 	MaxProfileSymbolValueLengthValue      int
 
 	MaxQueriersPerTenantValue int
+
+	ReadPathOverridesValue readpath.Config
 }
 
 func (m MockLimits) QuerySplitDuration(string) time.Duration        { return m.QuerySplitDurationValue }
@@ -42,6 +48,10 @@ func (m MockLimits) QueryAnalysisSeriesEnabled(tenantID string) bool {
 	return m.QueryAnalysisSeriesEnabledValue
 }
 
+func (m MockLimits) ReadPathOverrides(string) readpath.Config {
+	return m.ReadPathOverridesValue
+}
+
 func (m MockLimits) MaxFlameGraphNodesDefault(string) int { return m.MaxFlameGraphNodesDefaultValue }
 func (m MockLimits) MaxFlameGraphNodesMax(string) int     { return m.MaxFlameGraphNodesMaxValue }
 
@@ -53,6 +63,14 @@ func (m MockLimits) MaxProfileStacktraceSamples(userID string) int {
 	return m.MaxProfileStacktraceSamplesValue
 }
 
+func (m MockLimits) MaxProfileLocations(userID string) int {
+	return m.MaxProfileLocationsValue
+}
+
+func (m MockLimits) MaxProfileStringTableBytes(userID string) int {
+	return m.MaxProfileStringTableBytesValue
+}
+
 func (m MockLimits) DistributorAggregationWindow(userID string) time.Duration {
 	return m.DistributorAggregationWindowValue
 }
@@ -3,6 +3,7 @@ package validation
 import (
 	"encoding/hex"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -32,6 +33,9 @@ const (
 	MissingLabels Reason = "missing_labels"
 	// RateLimited is one of the values for the reason to discard samples.
 	RateLimited Reason = "rate_limited"
+	// ProfileTypeRateLimited is a reason for discarding profiles of a profile
+	// type which ingestion rate limit has been exceeded.
+	ProfileTypeRateLimited Reason = "profile_type_rate_limited"
 
 	// NotInIngestionWindow is a reason for discarding profiles when Pyroscope doesn't accept profiles
 	// that are outside of the ingestion window.
@@ -45,11 +49,19 @@ const (
 	LabelValueTooLong Reason = "label_value_too_long"
 	// DuplicateLabelNames is a reason for discarding a request which has duplicate label names
 	DuplicateLabelNames Reason = "duplicate_label_names"
+	// ReservedLabelName is a reason for discarding a request which has a label
+	// name reserved for the labels set by Pyroscope.
+	ReservedLabelName Reason = "reserved_label_name"
+	// LabelCardinalityLimit is a reason for discarding a series which has a
+	// new value of a label that reached the label value cardinality limit.
+	LabelCardinalityLimit Reason = "label_cardinality_limit"
 	// SeriesLimit is a reason for discarding lines when we can't create a new stream
 	// because the limit of active streams has been reached.
 	SeriesLimit           Reason = "series_limit"
 	QueryLimit            Reason = "query_limit"
 	SamplesLimit          Reason = "samples_limit"
+	LocationsLimit        Reason = "locations_limit"
+	StringTableSizeLimit  Reason = "string_table_size_limit"
 	ProfileSizeLimit      Reason = "profile_size_limit"
 	SampleLabelsLimit     Reason = "sample_labels_limit"
 	MalformedProfile      Reason = "malformed_profile"
@@ -59,16 +71,21 @@ const (
 	// Those profiles were dropped because of relabeling rules
 	DroppedByRelabelRules Reason = "dropped_by_relabel_rules"
 
+	ProfileTypeRateLimitedErrorMsg      = "push rate limit (%s) of profile type '%s' exceeded while adding %s"
 	SeriesLimitErrorMsg                 = "Maximum active series limit exceeded (%d/%d), reduce the number of active streams (reduce labels or reduce label values), or contact your administrator to see if the limit can be increased"
+	LabelCardinalityLimitErrorMsg       = "Maximum number of distinct values of label '%s' exceeded (limit: %d), reduce the number of label values, or contact your administrator to see if the limit can be increased"
 	MissingLabelsErrorMsg               = "error at least one label pair is required per profile"
 	InvalidLabelsErrorMsg               = "invalid labels '%s' with error: %s"
 	MaxLabelNamesPerSeriesErrorMsg      = "profile series '%s' has %d label names; limit %d"
 	LabelNameTooLongErrorMsg            = "profile with labels '%s' has label name too long: '%s'"
-	LabelValueTooLongErrorMsg           = "profile with labels '%s' has label value too long: '%s'"
+	LabelValueTooLongErrorMsg           = "profile with labels '%s' has label value too long: '%s' (actual: %d, limit: %d)"
 	DuplicateLabelNamesErrorMsg         = "profile with labels '%s' has duplicate label name: '%s'"
+	ReservedLabelNameErrorMsg           = "profile with labels '%s' has reserved label name: '%s'"
 	QueryTooLongErrorMsg                = "the query time range exceeds the limit (max_query_length, actual: %s, limit: %s)"
-	ProfileTooBigErrorMsg               = "the profile with labels '%s' exceeds the size limit (max_profile_size_byte, actual: %d, limit: %d)"
+	ProfileTooBigErrorMsg               = "the profile with labels '%s' exceeds the size limit (max_profile_size_bytes, actual: %d, limit: %d)"
 	ProfileTooManySamplesErrorMsg       = "the profile with labels '%s' exceeds the samples count limit (max_profile_stacktrace_samples, actual: %d, limit: %d)"
+	ProfileTooManyLocationsErrorMsg     = "the profile with labels '%s' exceeds the locations count limit (max_profile_locations, actual: %d, limit: %d)"
+	ProfileStringTableTooBigErrorMsg    = "the profile with labels '%s' exceeds the string table size limit (max_profile_string_table_bytes, actual: %d, limit: %d)"
 	ProfileTooManySampleLabelsErrorMsg  = "the profile with labels '%s' exceeds the sample labels limit (max_profile_stacktrace_sample_labels, actual: %d, limit: %d)"
 	NotInIngestionWindowErrorMsg        = "profile with labels '%s' is outside of ingestion window (profile timestamp: %s, %s)"
 	MaxFlameGraphNodesErrorMsg          = "max flamegraph nodes limit %d is greater than allowed %d"
@@ -129,8 +146,8 @@ func ValidateLabels(limits LabelValidationLimits, tenantID string, ls []*typesv1
 		if len(l.Name) > limits.MaxLabelNameLength(tenantID) {
 			return NewErrorf(LabelNameTooLong, LabelNameTooLongErrorMsg, phlaremodel.LabelPairsString(ls), l.Name)
 		}
-		if len(l.Value) > limits.MaxLabelValueLength(tenantID) {
-			return NewErrorf(LabelValueTooLong, LabelValueTooLongErrorMsg, phlaremodel.LabelPairsString(ls), l.Value)
+		if limit := limits.MaxLabelValueLength(tenantID); len(l.Value) > limit {
+			return NewErrorf(LabelValueTooLong, LabelValueTooLongErrorMsg, phlaremodel.LabelPairsString(ls), l.Name, len(l.Value), limit)
 		}
 		var origName string
 		var ok bool
@@ -138,7 +155,7 @@ func ValidateLabels(limits LabelValidationLimits, tenantID string, ls []*typesv1
 			return NewErrorf(InvalidLabels, InvalidLabelsErrorMsg, phlaremodel.LabelPairsString(ls), "invalid label name '"+origName+"'")
 		}
 		if !model.LabelValue(l.Value).IsValid() {
-			return NewErrorf(InvalidLabels, InvalidLabelsErrorMsg, phlaremodel.LabelPairsString(ls), "invalid label value '"+l.Value+"'")
+			return NewErrorf(InvalidLabels, InvalidLabelsErrorMsg, phlaremodel.LabelPairsString(ls), "invalid value of label '"+l.Name+"'")
 		}
 		if cmp := strings.Compare(lastLabelName, l.Name); cmp == 0 {
 			return NewErrorf(DuplicateLabelNames, DuplicateLabelNamesErrorMsg, phlaremodel.LabelPairsString(ls), origName)
@@ -149,6 +166,31 @@ func ValidateLabels(limits LabelValidationLimits, tenantID string, ls []*typesv1
 	return nil
 }
 
+// defaultReservedLabelNames returns the names of the labels that are
+// derived from the profile and its series on ingestion, and may affect
+// the way the series are stored and queried.
+func defaultReservedLabelNames() []string {
+	return []string{
+		phlaremodel.LabelNameProfileType,
+		phlaremodel.LabelNameServiceNamePrivate,
+		phlaremodel.LabelNamePeriodType,
+		phlaremodel.LabelNamePeriodUnit,
+		phlaremodel.LabelNameOrder,
+	}
+}
+
+// ValidateReservedLabelNames verifies that the labels do not include any
+// of the reserved names: such labels are set by Pyroscope, and must not be
+// provided by clients.
+func ValidateReservedLabelNames(reserved []string, ls []*typesv1.LabelPair) error {
+	for _, l := range ls {
+		if slices.Contains(reserved, l.Name) {
+			return NewErrorf(ReservedLabelName, ReservedLabelNameErrorMsg, phlaremodel.LabelPairsString(ls), l.Name)
+		}
+	}
+	return nil
+}
+
 // SanitizeLabelName reports whether the label name is valid,
 // and returns the sanitized value.
 //
@@ -182,8 +224,9 @@ func SanitizeLabelName(ln string) (old, sanitized string, ok bool) {
 type ProfileValidationLimits interface {
 	MaxProfileSizeBytes(tenantID string) int
 	MaxProfileStacktraceSamples(tenantID string) int
+	MaxProfileLocations(tenantID string) int
+	MaxProfileStringTableBytes(tenantID string) int
 	MaxProfileStacktraceSampleLabels(tenantID string) int
-	MaxProfileStacktraceDepth(tenantID string) int
 	MaxProfileSymbolValueLength(tenantID string) int
 	RejectNewerThan(tenantID string) time.Duration
 	RejectOlderThan(tenantID string) time.Duration
@@ -243,16 +286,23 @@ func ValidateProfile(limits ProfileValidationLimits, tenantID string, prof *goog
 	if limit, size := limits.MaxProfileStacktraceSamples(tenantID), len(prof.Sample); limit != 0 && size > limit {
 		return NewErrorf(SamplesLimit, ProfileTooManySamplesErrorMsg, phlaremodel.LabelPairsString(ls), size, limit)
 	}
+	if limit, size := limits.MaxProfileLocations(tenantID), len(prof.Location); limit != 0 && size > limit {
+		return NewErrorf(LocationsLimit, ProfileTooManyLocationsErrorMsg, phlaremodel.LabelPairsString(ls), size, limit)
+	}
+	if limit := limits.MaxProfileStringTableBytes(tenantID); limit != 0 {
+		var size int
+		for _, str := range prof.StringTable {
+			size += len(str)
+		}
+		if size > limit {
+			return NewErrorf(StringTableSizeLimit, ProfileStringTableTooBigErrorMsg, phlaremodel.LabelPairsString(ls), size, limit)
+		}
+	}
 	var (
-		depthLimit        = limits.MaxProfileStacktraceDepth(tenantID)
 		labelsLimit       = limits.MaxProfileStacktraceSampleLabels(tenantID)
 		symbolLengthLimit = limits.MaxProfileSymbolValueLength(tenantID)
 	)
 	for _, s := range prof.Sample {
-		if depthLimit != 0 && len(s.LocationId) > depthLimit {
-			// Truncate the deepest frames: s.LocationId[0] is the leaf.
-			s.LocationId = s.LocationId[len(s.LocationId)-depthLimit:]
-		}
 		if labelsLimit != 0 && len(s.Label) > labelsLimit {
 			return NewErrorf(SampleLabelsLimit, ProfileTooManySampleLabelsErrorMsg, phlaremodel.LabelPairsString(ls), len(s.Label), labelsLimit)
 		}
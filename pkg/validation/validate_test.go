@@ -69,7 +69,7 @@ func TestValidateLabels(t *testing.T) {
 				{Name: model.MetricNameLabel, Value: "qux"},
 				{Name: "foo", Value: "\xc5"},
 			},
-			expectedErr:    "invalid labels '{__name__=\"qux\", foo=\"\\xc5\", service_name=\"svc\"}' with error: invalid label value '\xc5'",
+			expectedErr:    "invalid labels '{__name__=\"qux\", foo=\"\\xc5\", service_name=\"svc\"}' with error: invalid value of label 'foo'",
 			expectedReason: InvalidLabels,
 		},
 		{
@@ -100,7 +100,7 @@ func TestValidateLabels(t *testing.T) {
 				{Name: model.MetricNameLabel, Value: "qux"},
 			},
 			expectedReason: LabelValueTooLong,
-			expectedErr:    `profile with labels '{__name__="qux", foo="barrrrrrrrrrrrrrr", service_name="svc"}' has label value too long: 'barrrrrrrrrrrrrrr'`,
+			expectedErr:    `profile with labels '{__name__="qux", foo="barrrrrrrrrrrrrrr", service_name="svc"}' has label value too long: 'foo' (actual: 17, limit: 10)`,
 		},
 
 		{
@@ -143,6 +143,24 @@ func TestValidateLabels(t *testing.T) {
 	}
 }
 
+func TestValidateReservedLabelNames(t *testing.T) {
+	reserved := defaultReservedLabelNames()
+	require.NoError(t, ValidateReservedLabelNames(reserved, []*typesv1.LabelPair{
+		{Name: model.MetricNameLabel, Value: "qux"},
+		{Name: phlaremodel.LabelNameServiceName, Value: "svc"},
+	}))
+	err := ValidateReservedLabelNames(reserved, []*typesv1.LabelPair{
+		{Name: model.MetricNameLabel, Value: "qux"},
+		{Name: phlaremodel.LabelNameServiceNamePrivate, Value: "svc"},
+	})
+	require.Error(t, err)
+	require.Equal(t, `profile with labels '{__name__="qux", __service_name__="svc"}' has reserved label name: '__service_name__'`, err.Error())
+	require.Equal(t, ReservedLabelName, ReasonOf(err))
+	require.NoError(t, ValidateReservedLabelNames(nil, []*typesv1.LabelPair{
+		{Name: phlaremodel.LabelNameServiceNamePrivate, Value: "svc"},
+	}))
+}
+
 func Test_ValidateRangeRequest(t *testing.T) {
 	now := model.Now()
 	for _, tt := range []struct {
@@ -278,6 +296,30 @@ func TestValidateProfile(t *testing.T) {
 			NewErrorf(SamplesLimit, ProfileTooManySamplesErrorMsg, `{foo="bar"}`, 3, 2),
 			nil,
 		},
+		{
+			"too many locations",
+			&googlev1.Profile{
+				Location: make([]*googlev1.Location, 3),
+			},
+			0,
+			MockLimits{
+				MaxProfileLocationsValue: 2,
+			},
+			NewErrorf(LocationsLimit, ProfileTooManyLocationsErrorMsg, `{foo="bar"}`, 3, 2),
+			nil,
+		},
+		{
+			"string table too big",
+			&googlev1.Profile{
+				StringTable: []string{"", "foo", "bar"},
+			},
+			0,
+			MockLimits{
+				MaxProfileStringTableBytesValue: 5,
+			},
+			NewErrorf(StringTableSizeLimit, ProfileStringTableTooBigErrorMsg, `{foo="bar"}`, 6, 5),
+			nil,
+		},
 		{
 			"too many labels",
 			&googlev1.Profile{
@@ -295,25 +337,18 @@ func TestValidateProfile(t *testing.T) {
 			nil,
 		},
 		{
-			"truncate labels and stacktrace",
+			"truncate symbols",
 			&googlev1.Profile{
 				StringTable: []string{"foo", "/foo/bar"},
-				Sample: []*googlev1.Sample{
-					{
-						LocationId: []uint64{0, 1, 2, 3, 4, 5},
-					},
-				},
 			},
 			0,
 			MockLimits{
-				MaxProfileStacktraceDepthValue:   2,
 				MaxProfileSymbolValueLengthValue: 3,
 			},
 			nil,
 			func(t *testing.T, profile *googlev1.Profile) {
 				t.Helper()
 				require.Equal(t, []string{"foo", "bar"}, profile.StringTable)
-				require.Equal(t, []uint64{4, 5}, profile.Sample[0].LocationId)
 			},
 		},
 		{
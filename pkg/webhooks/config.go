@@ -0,0 +1,40 @@
+package webhooks
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+type Config struct {
+	Endpoints        flagext.StringSliceCSV `yaml:"endpoints" category:"experimental"`
+	Secret           flagext.Secret         `yaml:"secret" category:"experimental"`
+	Events           flagext.StringSliceCSV `yaml:"events" category:"experimental"`
+	Timeout          time.Duration          `yaml:"timeout" category:"experimental"`
+	MaxRetries       int                    `yaml:"max_retries" category:"experimental"`
+	QueueSize        int                    `yaml:"queue_size" category:"experimental"`
+	ThrottleInterval time.Duration          `yaml:"throttle_interval" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	const prefix = "webhooks."
+	f.Var(&cfg.Endpoints, prefix+"endpoints", "Comma-separated list of URLs the event notifications are posted to. Notifications are disabled if empty.")
+	f.Var(&cfg.Secret, prefix+"secret", "Secret used to sign notifications. The HMAC-SHA256 signature of the request body is sent in the X-Pyroscope-Signature header.")
+	f.Var(&cfg.Events, prefix+"events", fmt.Sprintf("Comma-separated list of event types to notify about. All events are sent if empty. Supported events: %v.", EventTypes))
+	f.DurationVar(&cfg.Timeout, prefix+"timeout", 10*time.Second, "Timeout of a single notification request.")
+	f.IntVar(&cfg.MaxRetries, prefix+"max-retries", 5, "Maximum number of attempts to deliver a notification to an endpoint.")
+	f.IntVar(&cfg.QueueSize, prefix+"queue-size", 1000, "Maximum number of notifications waiting to be delivered. Notifications are dropped if the queue is full.")
+	f.DurationVar(&cfg.ThrottleInterval, prefix+"throttle-interval", 5*time.Minute, "Minimum interval between notifications of the same event type for a tenant, for events that may occur repeatedly, such as quota violations.")
+}
+
+func (cfg *Config) Validate() error {
+	for _, e := range cfg.Events {
+		if !slices.Contains(EventTypes, EventType(e)) {
+			return fmt.Errorf("unsupported webhook event type %q", e)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type EventType string
+
+const (
+	// EventBlockCompacted is emitted when a block reaches
+	// the final compaction level.
+	EventBlockCompacted EventType = "block_compacted"
+	// EventRetentionApplied is emitted when blocks of a tenant
+	// are marked for deletion due to the retention period.
+	EventRetentionApplied EventType = "retention_applied"
+	// EventRegressionDetected is emitted when a performance
+	// regression is detected.
+	EventRegressionDetected EventType = "regression_detected"
+	// EventQuotaExceeded is emitted when a tenant exceeds
+	// its ingestion limits.
+	EventQuotaExceeded EventType = "quota_exceeded"
+)
+
+var EventTypes = []EventType{
+	EventBlockCompacted,
+	EventRetentionApplied,
+	EventRegressionDetected,
+	EventQuotaExceeded,
+}
+
+// throttledEvents may occur at a high rate, e.g., on every request,
+// therefore only one notification per tenant is sent within the
+// throttle interval.
+var throttledEvents = []EventType{
+	EventQuotaExceeded,
+}
+
+const (
+	signatureHeader = "X-Pyroscope-Signature"
+	eventTypeHeader = "X-Pyroscope-Event"
+)
+
+type Event struct {
+	Type       EventType         `json:"type"`
+	Tenant     string            `json:"tenant,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Notifier delivers event notifications to the configured endpoints
+// asynchronously. A nil Notifier is valid and discards all events.
+type Notifier struct {
+	services.Service
+
+	cfg    Config
+	logger log.Logger
+	client *http.Client
+	queue  chan Event
+
+	mu        sync.Mutex
+	throttled map[string]time.Time
+
+	notifications *prometheus.CounterVec
+	dropped       *prometheus.CounterVec
+}
+
+func New(cfg Config, logger log.Logger, reg prometheus.Registerer) *Notifier {
+	if len(cfg.Endpoints) == 0 {
+		return nil
+	}
+	n := &Notifier{
+		cfg:       cfg,
+		logger:    logger,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		queue:     make(chan Event, cfg.QueueSize),
+		throttled: make(map[string]time.Time),
+
+		notifications: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_webhook_notifications_total",
+			Help: "Total number of webhook notification attempts, by event type and result.",
+		}, []string{"event", "result"}),
+		dropped: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_webhook_notifications_dropped_total",
+			Help: "Total number of webhook notifications dropped because the queue is full.",
+		}, []string{"event"}),
+	}
+	n.Service = services.NewBasicService(nil, n.running, nil)
+	return n
+}
+
+// Notify enqueues the event for delivery. The call never blocks:
+// if the queue is full, the event is dropped.
+func (n *Notifier) Notify(e Event) {
+	if n == nil {
+		return
+	}
+	if len(n.cfg.Events) > 0 && !slices.Contains(n.cfg.Events, string(e.Type)) {
+		return
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	if n.throttle(e) {
+		return
+	}
+	select {
+	case n.queue <- e:
+	default:
+		n.dropped.WithLabelValues(string(e.Type)).Inc()
+	}
+}
+
+func (n *Notifier) throttle(e Event) bool {
+	if n.cfg.ThrottleInterval <= 0 || !slices.Contains(throttledEvents, e.Type) {
+		return false
+	}
+	k := string(e.Type) + "/" + e.Tenant
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if last, ok := n.throttled[k]; ok && e.Timestamp.Sub(last) < n.cfg.ThrottleInterval {
+		return true
+	}
+	n.throttled[k] = e.Timestamp
+	return false
+}
+
+func (n *Notifier) running(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-n.queue:
+			n.send(ctx, e)
+		}
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, e Event) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		level.Error(n.logger).Log("msg", "failed to marshal webhook event", "event", e.Type, "err", err)
+		return
+	}
+	for _, endpoint := range n.cfg.Endpoints {
+		if err = n.deliver(ctx, endpoint, e.Type, body); err != nil {
+			n.notifications.WithLabelValues(string(e.Type), "failure").Inc()
+			level.Warn(n.logger).Log("msg", "failed to deliver webhook notification", "event", e.Type, "tenant", e.Tenant, "endpoint", endpoint, "err", err)
+			continue
+		}
+		n.notifications.WithLabelValues(string(e.Type), "success").Inc()
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, endpoint string, eventType EventType, body []byte) error {
+	b := backoff.New(ctx, backoff.Config{
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+		MaxRetries: n.cfg.MaxRetries,
+	})
+	var err error
+	for b.Ongoing() {
+		var retryable bool
+		if retryable, err = n.post(ctx, endpoint, eventType, body); err == nil || !retryable {
+			return err
+		}
+		b.Wait()
+	}
+	if err == nil {
+		err = b.Err()
+	}
+	return err
+}
+
+// post sends the request and reports whether the request may be retried,
+// if it has failed.
+func (n *Notifier) post(ctx context.Context, endpoint string, eventType EventType, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventTypeHeader, string(eventType))
+	if secret := n.cfg.Secret.String(); secret != "" {
+		req.Header.Set(signatureHeader, "sha256="+Sign([]byte(secret), body))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retryable := resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("unexpected response status: %s", resp.Status)
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 signature of the body.
+// Receivers should compute the signature of the request body and
+// compare it with the value of the X-Pyroscope-Signature header.
+func Sign(secret, body []byte) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifier_NilIsNoop(t *testing.T) {
+	n := New(Config{}, log.NewNopLogger(), prometheus.NewRegistry())
+	require.Nil(t, n)
+	n.Notify(Event{Type: EventBlockCompacted})
+}
+
+func TestNotifier_Delivery(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		received []Event
+	)
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "sha256="+Sign([]byte("secret"), body), r.Header.Get(signatureHeader))
+
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			// The first attempt fails and is retried.
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var e Event
+		require.NoError(t, json.Unmarshal(body, &e))
+		require.Equal(t, string(e.Type), r.Header.Get(eventTypeHeader))
+		received = append(received, e)
+		if len(received) == 2 {
+			close(done)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		Endpoints:        flagext.StringSliceCSV{srv.URL},
+		Secret:           flagext.SecretWithValue("secret"),
+		Events:           flagext.StringSliceCSV{string(EventQuotaExceeded), string(EventRetentionApplied)},
+		Timeout:          time.Second,
+		MaxRetries:       3,
+		QueueSize:        10,
+		ThrottleInterval: time.Hour,
+	}
+	n := New(cfg, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), n))
+	defer func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), n))
+	}()
+
+	// Not subscribed.
+	n.Notify(Event{Type: EventBlockCompacted, Tenant: "tenant-a"})
+	// The second quota notification is throttled.
+	n.Notify(Event{Type: EventQuotaExceeded, Tenant: "tenant-a"})
+	n.Notify(Event{Type: EventQuotaExceeded, Tenant: "tenant-a"})
+	n.Notify(Event{Type: EventRetentionApplied, Tenant: "tenant-a", Attributes: map[string]string{"blocks": "2"}})
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for notifications")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 3, attempts)
+	require.Len(t, received, 2)
+	require.Equal(t, EventQuotaExceeded, received[0].Type)
+	require.Equal(t, EventRetentionApplied, received[1].Type)
+	require.Equal(t, "2", received[1].Attributes["blocks"])
+}
+
+func TestNotifier_Throttle(t *testing.T) {
+	n := &Notifier{
+		cfg:       Config{ThrottleInterval: time.Minute},
+		throttled: make(map[string]time.Time),
+	}
+	now := time.Now()
+	require.False(t, n.throttle(Event{Type: EventQuotaExceeded, Tenant: "a", Timestamp: now}))
+	require.True(t, n.throttle(Event{Type: EventQuotaExceeded, Tenant: "a", Timestamp: now.Add(time.Second)}))
+	require.False(t, n.throttle(Event{Type: EventQuotaExceeded, Tenant: "b", Timestamp: now.Add(time.Second)}))
+	require.False(t, n.throttle(Event{Type: EventQuotaExceeded, Tenant: "a", Timestamp: now.Add(2 * time.Minute)}))
+	require.False(t, n.throttle(Event{Type: EventBlockCompacted, Tenant: "a", Timestamp: now}))
+	require.False(t, n.throttle(Event{Type: EventBlockCompacted, Tenant: "a", Timestamp: now}))
+}
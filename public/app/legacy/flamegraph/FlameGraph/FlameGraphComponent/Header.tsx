@@ -23,6 +23,7 @@ const unitsToFlamegraphTitle = new Map(
     lock_samples: 'number of contended locks per function',
     trace_samples: 'aggregated span duration',
     exceptions: 'number of exceptions thrown',
+    nanoseconds: 'time spent per function',
     unknown: '',
   })
 );
@@ -31,6 +31,7 @@ export function getFormatter(max: number, sampleRate: number, unit: Units) {
     case 'bytes':
       return new BytesFormatter(max);
     case 'lock_nanoseconds':
+    case 'nanoseconds':
       return new NanosecondsFormatter(max);
     case 'lock_samples':
       return new ObjectsFormatter(max);
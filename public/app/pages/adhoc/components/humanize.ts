@@ -43,6 +43,8 @@ export const humanizeUnits = (u: UnitsType) => {
       return 'Trace Samples';
     case 'exceptions':
       return 'Exceptions';
+    case 'nanoseconds':
+      return 'Nanoseconds';
   }
 };
 
@@ -11,6 +11,9 @@ import { request, parseResponse } from './base';
 
 interface ShareWithFlamegraphDotcomProps {
   flamebearer: Profile;
+  // Name of the share target configured on the server.
+  // Defaults to flamegraph.com.
+  target?: string;
   name?: string;
   groupByTag?: string;
   groupByTagValue?: string;
@@ -18,15 +21,17 @@ interface ShareWithFlamegraphDotcomProps {
 
 export async function shareWithFlamegraphDotcom({
   flamebearer,
+  target,
   name,
   groupByTag,
   groupByTagValue,
 }: ShareWithFlamegraphDotcomProps): Promise<
   Result<FlamegraphDotComResponse, RequestError | ZodError>
 > {
-  const response = await request('/export', {
+  const response = await request('/pyroscope/share', {
     method: 'POST',
     body: JSON.stringify({
+      target,
       name,
       groupByTag,
       groupByTagValue,